@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/autoapproval"
+)
+
+// SetThresholdRequest sets the auto-approval threshold of a label
+type SetThresholdRequest struct {
+	Threshold float64 `json:"threshold" binding:"required" example:"0.97"` // Over 0 and at most 1
+}
+
+// ThresholdResponse wraps the threshold in effect for a label
+type ThresholdResponse struct {
+	types.BaseResponse
+	Threshold autoapproval.Threshold `json:"threshold"`
+}
+
+// ThresholdListResponse lists the labels that have an auto-approval threshold
+type ThresholdListResponse struct {
+	types.BaseResponse
+	Thresholds []autoapproval.Threshold `json:"thresholds"`
+	Count      int                      `json:"count"`
+}
+
+// ListAutoApprovalThresholds returns the thresholds in effect
+// @Summary      List auto-approval thresholds
+// @Description  The confidence at or over which episode analysis approves the clips it creates, for each label that
+// @Description  has one: from clips.auto_approve_thresholds in config, or set through this API (source "override"),
+// @Description  which wins. Clips with other labels, or without a score, always wait for review. Requires the admin
+// @Description  permission.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} ThresholdListResponse
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/auto-approval/thresholds [get]
+func ListAutoApprovalThresholds(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.AutoApprovalService == nil {
+			types.SendInternalError(c, "Auto-approval service not available")
+			return
+		}
+
+		list, err := deps.AutoApprovalService.List(c.Request.Context())
+		if err != nil {
+			types.SendInternalError(c, "Failed to list auto-approval thresholds")
+			return
+		}
+
+		c.JSON(http.StatusOK, ThresholdListResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Thresholds:   list,
+			Count:        len(list),
+		})
+	}
+}
+
+// SetAutoApprovalThreshold sets a label's threshold
+// @Summary      Set an auto-approval threshold
+// @Description  Auto-approve clips analysis creates with this label when the model's confidence is at or over
+// @Description  threshold, overriding config. Auto-approved clips count against label quotas and stay flagged
+// @Description  (auto_approved) until a reviewer approves or rejects them; the annotation history records them as
+// @Description  "auto_approve". Applies to analysis run from now on. Requires the admin permission.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        label path string true "Clip label" example(advertisement)
+// @Param        request body SetThresholdRequest true "Threshold"
+// @Success      200 {object} ThresholdResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid threshold"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/auto-approval/thresholds/{label} [put]
+func SetAutoApprovalThreshold(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req SetThresholdRequest
+		if !types.BindJSONOrError(c, &req) {
+			return
+		}
+		if deps.AutoApprovalService == nil {
+			types.SendInternalError(c, "Auto-approval service not available")
+			return
+		}
+
+		threshold, err := deps.AutoApprovalService.Set(c.Request.Context(), c.Param("label"), req.Threshold, c.GetString("user_id"))
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to set auto-approval threshold")
+			return
+		}
+
+		c.JSON(http.StatusOK, ThresholdResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Threshold set"},
+			Threshold:    *threshold,
+		})
+	}
+}
+
+// DeleteAutoApprovalThreshold removes a label's override
+// @Summary      Delete an auto-approval threshold
+// @Description  Remove the threshold set through the API for a label, so the one from config, if any, applies
+// @Description  again. Clips already auto-approved are not changed. Requires the admin permission.
+// @Tags         admin
+// @Produce      json
+// @Param        label path string true "Clip label" example(advertisement)
+// @Success      200 {object} types.BaseResponse
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      404 {object} types.ErrorResponse "No threshold set for the label"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/auto-approval/thresholds/{label} [delete]
+func DeleteAutoApprovalThreshold(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.AutoApprovalService == nil {
+			types.SendInternalError(c, "Auto-approval service not available")
+			return
+		}
+
+		if err := deps.AutoApprovalService.Delete(c.Request.Context(), c.Param("label")); err != nil {
+			types.SendServiceError(c, err, "Failed to delete auto-approval threshold")
+			return
+		}
+
+		c.JSON(http.StatusOK, types.BaseResponse{Status: types.StatusOK, Message: "Threshold removed"})
+	}
+}
@@ -0,0 +1,194 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/blocklist"
+)
+
+// BlocklistEntryRequest adds a blocklist entry
+type BlocklistEntryRequest struct {
+	Kind   string `json:"kind" binding:"required" example:"domain"` // feed, domain or category
+	Value  string `json:"value" binding:"required" example:"spam.example.com"`
+	Reason string `json:"reason,omitempty" example:"Spam network"`
+}
+
+// BlocklistResponse lists blocklist entries
+type BlocklistResponse struct {
+	types.BaseResponse
+	Entries []models.BlocklistEntry `json:"entries"`
+	Count   int                     `json:"count"`
+}
+
+// BlocklistEntryResponse wraps a single entry
+type BlocklistEntryResponse struct {
+	types.BaseResponse
+	Entry models.BlocklistEntry `json:"entry"`
+}
+
+// BlocklistAuditResponse lists blocklist audit rows
+type BlocklistAuditResponse struct {
+	types.BaseResponse
+	Audit []models.BlocklistAudit `json:"audit"`
+	Count int                     `json:"count"`
+}
+
+// ListBlocklist returns every blocklist entry
+// @Summary      List blocklist entries
+// @Description  List the feed IDs, domains and categories withheld from search, trending, random and episode
+// @Description  endpoints. Requires the podcasts:admin permission.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} BlocklistResponse
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/blocklist [get]
+func ListBlocklist(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.BlocklistService == nil {
+			types.SendInternalError(c, "Blocklist service not available")
+			return
+		}
+
+		entries, err := deps.BlocklistService.List(c.Request.Context())
+		if err != nil {
+			types.SendInternalError(c, "Failed to list blocklist")
+			return
+		}
+
+		c.JSON(http.StatusOK, BlocklistResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Entries:      entries,
+			Count:        len(entries),
+		})
+	}
+}
+
+// AddBlocklistEntry blocks a feed, domain or category
+// @Summary      Add blocklist entry
+// @Description  Block a Podcast Index feed ID, a domain (matching feed, link and enclosure URLs on it and its
+// @Description  subdomains) or a category (name or ID). Takes effect immediately and clears cached responses.
+// @Description  Requires the podcasts:admin permission; the change is recorded in the audit log.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body BlocklistEntryRequest true "Entry to add"
+// @Success      201 {object} BlocklistEntryResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid kind or value"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      409 {object} types.ErrorResponse "Already blocked"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/blocklist [post]
+func AddBlocklistEntry(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req BlocklistEntryRequest
+		if !types.BindJSONOrError(c, &req) {
+			return
+		}
+		if deps.BlocklistService == nil {
+			types.SendInternalError(c, "Blocklist service not available")
+			return
+		}
+
+		entry, err := deps.BlocklistService.Add(c.Request.Context(), req.Kind, req.Value, req.Reason, c.GetString("user_id"))
+		switch {
+		case errors.Is(err, blocklist.ErrInvalidEntry):
+			types.SendBadRequest(c, err.Error())
+			return
+		case errors.Is(err, blocklist.ErrDuplicateEntry):
+			types.SendError(c, http.StatusConflict, types.CodeBlocklistDuplicate, "Already blocked")
+			return
+		case err != nil:
+			types.SendInternalError(c, "Failed to add blocklist entry")
+			return
+		}
+
+		c.JSON(http.StatusCreated, BlocklistEntryResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Entry blocked"},
+			Entry:        *entry,
+		})
+	}
+}
+
+// RemoveBlocklistEntry unblocks an entry
+// @Summary      Remove blocklist entry
+// @Description  Lift a block. Requires the podcasts:admin permission; the change is recorded in the audit log.
+// @Tags         admin
+// @Produce      json
+// @Param        id path int true "Entry ID" minimum(1)
+// @Success      200 {object} types.BaseResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid entry ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      404 {object} types.ErrorResponse "Entry not found"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/blocklist/{id} [delete]
+func RemoveBlocklistEntry(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := types.ParseUintParam(c, "id")
+		if !ok {
+			return
+		}
+		if deps.BlocklistService == nil {
+			types.SendInternalError(c, "Blocklist service not available")
+			return
+		}
+
+		err := deps.BlocklistService.Remove(c.Request.Context(), id, c.GetString("user_id"))
+		if errors.Is(err, blocklist.ErrEntryNotFound) {
+			types.SendError(c, http.StatusNotFound, types.CodeBlocklistNotFound, "Blocklist entry not found")
+			return
+		}
+		if err != nil {
+			types.SendInternalError(c, "Failed to remove blocklist entry")
+			return
+		}
+
+		c.JSON(http.StatusOK, types.BaseResponse{Status: types.StatusOK, Message: "Entry removed"})
+	}
+}
+
+// GetBlocklistAudit returns recent blocklist changes and overrides
+// @Summary      Blocklist audit log
+// @Description  Most recent blocklist additions, removals and admin overrides (include_blocked=true requests),
+// @Description  newest first. Requires the podcasts:admin permission.
+// @Tags         admin
+// @Produce      json
+// @Param        limit query int false "Rows to return (1-500)" default(100)
+// @Success      200 {object} BlocklistAuditResponse
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/blocklist/audit [get]
+func GetBlocklistAudit(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.BlocklistService == nil {
+			types.SendInternalError(c, "Blocklist service not available")
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit < 1 {
+			limit = 100
+		}
+
+		audit, err := deps.BlocklistService.AuditLog(c.Request.Context(), limit)
+		if err != nil {
+			types.SendInternalError(c, "Failed to load blocklist audit log")
+			return
+		}
+
+		c.JSON(http.StatusOK, BlocklistAuditResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Audit:        audit,
+			Count:        len(audit),
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/cleanup"
+)
+
+// CleanupStatsResponse reports the temp file cleanup's work since startup
+type CleanupStatsResponse struct {
+	types.BaseResponse
+	Stats cleanup.Stats `json:"stats"`
+}
+
+// GetCleanupStats returns what the temp file cleanup has removed
+// @Summary      Temp file cleanup stats
+// @Description  Files and bytes reclaimed by the background cleanup of temp files left behind by crashes, since the
+// @Description  server started and in the latest run. Temp files and export directories following the app's
+// @Description  naming conventions are removed from temp_dir once nothing in them
+// @Description  has changed for cleanup.max_age. Requires the podcasts:admin permission.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} CleanupStatsResponse
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      503 {object} types.ErrorResponse "Cleanup not running"
+// @Router       /api/v1/admin/cleanup/stats [get]
+func GetCleanupStats(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.CleanupService == nil {
+			types.SendError(c, http.StatusServiceUnavailable, types.CodeServiceUnavailable, "Cleanup not running")
+			return
+		}
+
+		c.JSON(http.StatusOK, CleanupStatsResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Stats:        deps.CleanupService.Stats(),
+		})
+	}
+}
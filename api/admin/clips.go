@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// VerifyClipsRequest selects what happens to clips whose file is broken
+type VerifyClipsRequest struct {
+	Repair string `json:"repair" example:"reextract" enums:"reextract,invalidate"` // Empty only reports
+}
+
+// QueuedVerificationResponse is returned when a clip verification is queued
+type QueuedVerificationResponse struct {
+	types.BaseResponse
+	Job types.PublicJob `json:"job"`
+}
+
+// VerifyClips queues a check of stored clip files against their records
+// @Summary      Verify clip storage
+// @Description  Queues a check that every ready clip's file exists in storage with the size and SHA-256 recorded when
+// @Description  it was extracted. The job result reports the clips whose file is missing, unreadable or changed. With
+// @Description  repair "reextract" broken clips are extracted again from the episode audio; with "invalidate" they are
+// @Description  marked failed so exports skip them. Clips extracted before hashes were kept get their hash recorded.
+// @Description  The check reads every stored clip, so it runs as a job; poll the Location header for the result.
+// @Description  The same check runs every clips.integrity_check_interval. Requires the podcasts:admin permission.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body VerifyClipsRequest false "Repair mode"
+// @Success      202 {object} QueuedVerificationResponse
+// @Header       202 {string} Location "Job status URL"
+// @Failure      400 {object} types.ErrorResponse "Invalid repair mode"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Failure      503 {object} types.ErrorResponse "Job queue is full, see Retry-After"
+// @Router       /api/v1/admin/clips/verify [post]
+func VerifyClips(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.JobService == nil {
+			types.SendInternalError(c, "Job service not available")
+			return
+		}
+
+		var req VerifyClipsRequest
+		if c.Request.ContentLength > 0 && !types.BindJSONOrError(c, &req) {
+			return
+		}
+		if !clips.IsValidRepairMode(req.Repair) {
+			types.SendBadRequest(c, "repair must be reextract or invalidate")
+			return
+		}
+
+		job, err := deps.JobService.EnqueueJob(c.Request.Context(), models.JobTypeClipVerification,
+			models.JobPayload{"repair": req.Repair}, jobs.WithCreatedBy(c.GetString("user_id")))
+		if err != nil {
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to queue clip verification: %v", err))
+			return
+		}
+
+		types.SetJobLocation(c, job.ID)
+		c.JSON(http.StatusAccepted, QueuedVerificationResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Clip verification queued"},
+			Job:          types.NewPublicJob(job),
+		})
+	}
+}
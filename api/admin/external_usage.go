@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/externalusage"
+)
+
+// ExternalUsageResponse is Podcast Index usage by day with today's standing
+// against the budget
+type ExternalUsageResponse struct {
+	types.BaseResponse
+	Budget externalusage.Status     `json:"budget"`
+	Days   []externalusage.DayUsage `json:"days"`
+}
+
+// GetExternalUsage returns daily aggregates of Podcast Index calls
+// @Summary      Get external API usage
+// @Description  Podcast Index calls per UTC day, newest first, with error and refusal counts and latencies in
+// @Description  total and per endpoint. When podcast_index.daily_budget is set and today's calls reach it, the
+// @Description  service is degraded: further calls are refused and only stored podcasts and episodes are served
+// @Description  until the next UTC day. Requires the podcasts:admin permission.
+// @Tags         admin
+// @Produce      json
+// @Param        days query int false "Days to cover, today included (1-90)" default(7)
+// @Success      200 {object} ExternalUsageResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid days"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/external-usage [get]
+func GetExternalUsage(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.ExternalUsageService == nil {
+			types.SendInternalError(c, "External usage tracking not enabled")
+			return
+		}
+
+		days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+		if err != nil || days < 1 || days > 90 {
+			types.SendBadRequest(c, "days must be between 1 and 90")
+			return
+		}
+
+		ctx := c.Request.Context()
+		usage, err := deps.ExternalUsageService.Daily(ctx, externalusage.PodcastIndex, days)
+		if err != nil {
+			log.Printf("[ERROR] Failed to get external API usage: %v", err)
+			types.SendInternalError(c, "Failed to get external API usage")
+			return
+		}
+		status, err := deps.ExternalUsageService.Status(ctx, externalusage.PodcastIndex)
+		if err != nil {
+			log.Printf("[ERROR] Failed to get external API budget status: %v", err)
+			types.SendInternalError(c, "Failed to get external API usage")
+			return
+		}
+
+		c.JSON(http.StatusOK, ExternalUsageResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Budget:       *status,
+			Days:         usage,
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/feedhealth"
+)
+
+// UnhealthyPodcastsResponse lists podcasts whose feed or audio keeps failing
+type UnhealthyPodcastsResponse struct {
+	types.BaseResponse
+	Podcasts []feedhealth.UnhealthyFeed `json:"podcasts"`
+	Count    int                        `json:"count"`
+	Total    int64                      `json:"total"`
+}
+
+// ListUnhealthyPodcasts returns degraded and dead podcasts
+// @Summary      List unhealthy podcasts
+// @Description  Podcasts whose episode audio keeps failing to download, or whose feed Podcast Index has marked
+// @Description  dead or dropped, dead first and then by the length of the failure streak. A podcast is degraded
+// @Description  after feed_health.degraded_after consecutive failures and dead after feed_health.dead_after
+// @Description  failures spanning at least feed_health.dead_min_age. A successful download makes it healthy again.
+// @Description  Requires the podcasts:admin permission.
+// @Tags         admin
+// @Produce      json
+// @Param        status query string false "Only this status" Enums(degraded, dead)
+// @Param        limit query int false "Rows to return (1-500)" default(100)
+// @Param        offset query int false "Rows to skip" default(0)
+// @Success      200 {object} UnhealthyPodcastsResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid status, limit or offset"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/podcasts/unhealthy [get]
+func ListUnhealthyPodcasts(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.FeedHealthService == nil {
+			types.SendInternalError(c, "Feed health tracking not enabled")
+			return
+		}
+
+		status := models.FeedHealthStatus(c.Query("status"))
+		if status != "" && status != models.FeedDegraded && status != models.FeedDead {
+			types.SendBadRequest(c, "status must be degraded or dead")
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit < 1 || limit > 500 {
+			types.SendBadRequest(c, "limit must be between 1 and 500")
+			return
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			types.SendBadRequest(c, "offset must be zero or more")
+			return
+		}
+
+		feeds, total, err := deps.FeedHealthService.ListUnhealthy(c.Request.Context(), status, limit, offset)
+		if err != nil {
+			log.Printf("[ERROR] Failed to list unhealthy podcasts: %v", err)
+			types.SendInternalError(c, "Failed to list unhealthy podcasts")
+			return
+		}
+		if feeds == nil {
+			feeds = []feedhealth.UnhealthyFeed{}
+		}
+
+		c.JSON(http.StatusOK, UnhealthyPodcastsResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Podcasts:     feeds,
+			Count:        len(feeds),
+			Total:        total,
+		})
+	}
+}
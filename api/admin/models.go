@@ -0,0 +1,203 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// RegisterModelRequest adds a detector model version to the registry
+type RegisterModelRequest struct {
+	Name      string   `json:"name" binding:"required" example:"ad-detector"`
+	Version   string   `json:"version" binding:"required" example:"2024-06"`
+	Backend   string   `json:"backend" binding:"required" enums:"builtin,local,http" example:"local"`
+	Endpoint  string   `json:"endpoint,omitempty" example:"https://inference.example.com/ads"` // For the http backend
+	LocalPath string   `json:"local_path,omitempty" example:"/opt/models/ad-detector/detect"`  // Executable, for the local backend
+	Labels    []string `json:"labels" binding:"required,min=1" example:"advertisement,music"`
+	Default   bool     `json:"default,omitempty" example:"false"` // Make it the model analysis runs when none is selected
+
+	// Settings of http models
+	Input          string  `json:"input,omitempty" enums:"audio,features" example:"audio"` // What each request carries (default audio)
+	ChunkSeconds   float64 `json:"chunk_seconds,omitempty" example:"30"`                   // Audio per request, 1-600 (default 30)
+	TimeoutSeconds int     `json:"timeout_seconds,omitempty" example:"60"`                 // Per request, up to 600 (default 60)
+	TokenEnv       string  `json:"token_env,omitempty" example:"AD_DETECTOR_TOKEN"`        // Server environment variable holding a bearer token
+}
+
+// DetectorModelResponse wraps a registered model
+type DetectorModelResponse struct {
+	types.BaseResponse
+	Model models.DetectorModel `json:"model"`
+}
+
+// DetectorModelListResponse lists the registered models
+type DetectorModelListResponse struct {
+	types.BaseResponse
+	Models []models.DetectorModel `json:"models"`
+	Count  int                    `json:"count"`
+}
+
+// ListDetectorModels returns the model registry
+// @Summary      List detector models
+// @Description  The detector models episode analysis can run, by name and newest version first, including the
+// @Description  built-in ones. Requires the admin permission.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} DetectorModelListResponse
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/models [get]
+func ListDetectorModels(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.ModelRegistry == nil {
+			types.SendInternalError(c, "Model registry not available")
+			return
+		}
+
+		list, err := deps.ModelRegistry.List(c.Request.Context())
+		if err != nil {
+			types.SendInternalError(c, "Failed to list detector models")
+			return
+		}
+
+		c.JSON(http.StatusOK, DetectorModelListResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Models:       list,
+			Count:        len(list),
+		})
+	}
+}
+
+// RegisterDetectorModel adds a model version to the registry
+// @Summary      Register a detector model
+// @Description  Add a version of a detector model: a built-in detector by name, an executable on the server
+// @Description  (local_path) that is given the episode's audio file and prints {"segments": [{"start", "end",
+// @Description  "label", "score"}]}, or an inference service (endpoint). An inference service is POSTed the
+// @Description  episode chunk by chunk as JSON {model, version, episode_id, labels, chunk: {index, offset, duration,
+// @Description  sample_rate, channels}} plus either audio (base64 16kHz mono WAV) or, with input "features",
+// @Description  features: {frame_rate, levels_db} (RMS level per 10ms frame); it answers with the same segments
+// @Description  as a local model, timed from the start of the chunk. Detections with labels the model doesn't
+// @Description  list are dropped. Select it with POST /episodes/{id}/analyze?model=name&version=version. Requires
+// @Description  the admin permission.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body RegisterModelRequest true "Model to register"
+// @Success      201 {object} DetectorModelResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid model"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      409 {object} types.ErrorResponse "Version already registered"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/models [post]
+func RegisterDetectorModel(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RegisterModelRequest
+		if !types.BindJSONOrError(c, &req) {
+			return
+		}
+		if deps.ModelRegistry == nil {
+			types.SendInternalError(c, "Model registry not available")
+			return
+		}
+
+		model, err := deps.ModelRegistry.Register(c.Request.Context(), &models.DetectorModel{
+			Name:      req.Name,
+			Version:   req.Version,
+			Backend:   req.Backend,
+			Endpoint:  req.Endpoint,
+			LocalPath: req.LocalPath,
+			Labels:    req.Labels,
+			IsDefault: req.Default,
+
+			Input:          req.Input,
+			ChunkSeconds:   req.ChunkSeconds,
+			TimeoutSeconds: req.TimeoutSeconds,
+			TokenEnv:       req.TokenEnv,
+		}, c.GetString("user_id"))
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to register detector model")
+			return
+		}
+
+		c.JSON(http.StatusCreated, DetectorModelResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Model registered"},
+			Model:        *model,
+		})
+	}
+}
+
+// SetDefaultDetectorModel makes a model the default
+// @Summary      Set the default detector model
+// @Description  Make a model the one episode analysis runs when none is selected, including analysis queued by
+// @Description  the post-cache pipeline. Requires the admin permission.
+// @Tags         admin
+// @Produce      json
+// @Param        id path int true "Model ID" minimum(1)
+// @Success      200 {object} DetectorModelResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid model ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      404 {object} types.ErrorResponse "Model not found"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/models/{id}/default [put]
+func SetDefaultDetectorModel(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := types.ParseUintParam(c, "id")
+		if !ok {
+			return
+		}
+		if deps.ModelRegistry == nil {
+			types.SendInternalError(c, "Model registry not available")
+			return
+		}
+
+		model, err := deps.ModelRegistry.SetDefault(c.Request.Context(), id)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to set the default detector model")
+			return
+		}
+
+		c.JSON(http.StatusOK, DetectorModelResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Default model set"},
+			Model:        *model,
+		})
+	}
+}
+
+// DeleteDetectorModel removes a model from the registry
+// @Summary      Delete a detector model
+// @Description  Remove a model version from the registry. Clips it created keep its name and version. The default
+// @Description  model can't be removed. Requires the admin permission.
+// @Tags         admin
+// @Produce      json
+// @Param        id path int true "Model ID" minimum(1)
+// @Success      200 {object} types.BaseResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid model ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      404 {object} types.ErrorResponse "Model not found"
+// @Failure      409 {object} types.ErrorResponse "Model is the default"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/models/{id} [delete]
+func DeleteDetectorModel(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := types.ParseUintParam(c, "id")
+		if !ok {
+			return
+		}
+		if deps.ModelRegistry == nil {
+			types.SendInternalError(c, "Model registry not available")
+			return
+		}
+
+		if err := deps.ModelRegistry.Delete(c.Request.Context(), id); err != nil {
+			types.SendServiceError(c, err, "Failed to delete detector model")
+			return
+		}
+
+		c.JSON(http.StatusOK, types.BaseResponse{Status: types.StatusOK, Message: "Model removed"})
+	}
+}
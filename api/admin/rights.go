@@ -0,0 +1,188 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// PodcastRightsRequest sets a podcast's rights. Every allowed-use flag must be
+// given, so a forgotten field can't grant a use.
+type PodcastRightsRequest struct {
+	License             string `json:"license,omitempty" example:"CC-BY-ND-4.0"` // SPDX identifier or URL
+	FairUseNote         string `json:"fair_use_note,omitempty" example:"Short excerpts for ad detection research"`
+	AllowTraining       *bool  `json:"allow_training" binding:"required" example:"true"`
+	AllowDerivatives    *bool  `json:"allow_derivatives" binding:"required" example:"false"`
+	AllowRedistribution *bool  `json:"allow_redistribution" binding:"required" example:"false"`
+}
+
+// PodcastRightsResponse wraps a podcast's rights
+type PodcastRightsResponse struct {
+	types.BaseResponse
+	Rights models.PodcastRights `json:"rights"`
+}
+
+// PodcastRightsListResponse lists the podcasts with recorded rights
+type PodcastRightsListResponse struct {
+	types.BaseResponse
+	Rights []models.PodcastRights `json:"rights"`
+	Count  int                    `json:"count"`
+}
+
+// ListPodcastRights returns every podcast's recorded rights
+// @Summary      List podcast rights
+// @Description  The license, fair use note and allowed uses recorded per podcast, by feed ID. Podcasts without
+// @Description  an entry have no recorded restrictions. Requires the podcasts:admin permission.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} PodcastRightsListResponse
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/podcasts/rights [get]
+func ListPodcastRights(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.RightsService == nil {
+			types.SendInternalError(c, "Rights service not available")
+			return
+		}
+
+		rights, err := deps.RightsService.List(c.Request.Context())
+		if err != nil {
+			types.SendInternalError(c, "Failed to list podcast rights")
+			return
+		}
+
+		c.JSON(http.StatusOK, PodcastRightsListResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Rights:       rights,
+			Count:        len(rights),
+		})
+	}
+}
+
+// GetPodcastRights returns a podcast's recorded rights
+// @Summary      Get podcast rights
+// @Description  The license, fair use note and allowed uses recorded for a podcast. Requires the podcasts:admin
+// @Description  permission.
+// @Tags         admin
+// @Produce      json
+// @Param        id path int true "Podcast Index feed ID" minimum(1)
+// @Success      200 {object} PodcastRightsResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid feed ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      404 {object} types.ErrorResponse "No rights recorded"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/podcasts/{id}/rights [get]
+func GetPodcastRights(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		feedID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+		if deps.RightsService == nil {
+			types.SendInternalError(c, "Rights service not available")
+			return
+		}
+
+		rights, err := deps.RightsService.Get(c.Request.Context(), feedID)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to get podcast rights")
+			return
+		}
+
+		c.JSON(http.StatusOK, PodcastRightsResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Rights:       *rights,
+		})
+	}
+}
+
+// SetPodcastRights records a podcast's rights
+// @Summary      Set podcast rights
+// @Description  Record the license, fair use note and allowed uses of a podcast's audio, replacing any recorded
+// @Description  before. Clips and datasets inherit them: manifests carry the license and allowed uses, and
+// @Description  dataset exports leave out clips of podcasts that don't allow derivatives or training, reporting
+// @Description  them as rights_restricted. Requires the podcasts:admin permission.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Podcast Index feed ID" minimum(1)
+// @Param        request body PodcastRightsRequest true "Rights to record"
+// @Success      200 {object} PodcastRightsResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid feed ID or rights"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/podcasts/{id}/rights [put]
+func SetPodcastRights(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		feedID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+		var req PodcastRightsRequest
+		if !types.BindJSONOrError(c, &req) {
+			return
+		}
+		if deps.RightsService == nil {
+			types.SendInternalError(c, "Rights service not available")
+			return
+		}
+
+		rights, err := deps.RightsService.Set(c.Request.Context(), &models.PodcastRights{
+			PodcastIndexFeedID:  feedID,
+			License:             req.License,
+			FairUseNote:         req.FairUseNote,
+			AllowTraining:       *req.AllowTraining,
+			AllowDerivatives:    *req.AllowDerivatives,
+			AllowRedistribution: *req.AllowRedistribution,
+		}, c.GetString("user_id"))
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to set podcast rights")
+			return
+		}
+
+		c.JSON(http.StatusOK, PodcastRightsResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Rights recorded"},
+			Rights:       *rights,
+		})
+	}
+}
+
+// DeletePodcastRights removes a podcast's recorded rights
+// @Summary      Delete podcast rights
+// @Description  Forget a podcast's recorded rights, lifting its export restrictions. Requires the podcasts:admin
+// @Description  permission.
+// @Tags         admin
+// @Produce      json
+// @Param        id path int true "Podcast Index feed ID" minimum(1)
+// @Success      200 {object} types.BaseResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid feed ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      404 {object} types.ErrorResponse "No rights recorded"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/admin/podcasts/{id}/rights [delete]
+func DeletePodcastRights(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		feedID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+		if deps.RightsService == nil {
+			types.SendInternalError(c, "Rights service not available")
+			return
+		}
+
+		if err := deps.RightsService.Delete(c.Request.Context(), feedID); err != nil {
+			types.SendServiceError(c, err, "Failed to delete podcast rights")
+			return
+		}
+
+		c.JSON(http.StatusOK, types.BaseResponse{Status: types.StatusOK, Message: "Rights removed"})
+	}
+}
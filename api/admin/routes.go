@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// RegisterRoutes registers admin routes; every route requires the admin permission
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	router.Use(requireAdmin())
+
+	// Blocklist management
+	router.GET("/blocklist", ListBlocklist(deps))
+	router.POST("/blocklist", AddBlocklistEntry(deps))
+	router.DELETE("/blocklist/:id", RemoveBlocklistEntry(deps))
+	router.GET("/blocklist/audit", GetBlocklistAudit(deps))
+
+	// Podcasts whose feed or audio keeps failing, for cleanup
+	router.GET("/podcasts/unhealthy", ListUnhealthyPodcasts(deps))
+
+	// License and allowed uses per podcast, inherited by clips and datasets
+	router.GET("/podcasts/rights", ListPodcastRights(deps))
+	router.GET("/podcasts/:id/rights", GetPodcastRights(deps))
+	router.PUT("/podcasts/:id/rights", SetPodcastRights(deps))
+	router.DELETE("/podcasts/:id/rights", DeletePodcastRights(deps))
+
+	// Detector models episode analysis can run
+	router.GET("/models", ListDetectorModels(deps))
+	router.POST("/models", RegisterDetectorModel(deps))
+	router.PUT("/models/:id/default", SetDefaultDetectorModel(deps))
+	router.DELETE("/models/:id", DeleteDetectorModel(deps))
+
+	// Confidence per label at which analysis approves the clips it creates
+	router.GET("/auto-approval/thresholds", ListAutoApprovalThresholds(deps))
+	router.PUT("/auto-approval/thresholds/:label", SetAutoApprovalThreshold(deps))
+	router.DELETE("/auto-approval/thresholds/:label", DeleteAutoApprovalThreshold(deps))
+
+	// Clip files that drifted from their records, checked in a job
+	router.POST("/clips/verify", VerifyClips(deps))
+
+	// Podcast Index calls per day against the daily budget
+	router.GET("/external-usage", GetExternalUsage(deps))
+
+	// What the orphaned temp file cleanup has reclaimed
+	router.GET("/cleanup/stats", GetCleanupStats(deps))
+}
+
+// requireAdmin rejects callers without the admin permission
+func requireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("user_id") == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeUnauthorized,
+				Message: "Authentication required",
+			})
+			return
+		}
+		if !types.HasPermission(c, types.AdminPermission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeForbidden,
+				Message: "Admin permission required",
+			})
+			return
+		}
+		c.Next()
+	}
+}
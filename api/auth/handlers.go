@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
 	"github.com/killallgit/player-api/internal/services/auth"
 )
 
@@ -36,13 +37,13 @@ func (h *Handler) SetDevAuth(enabled bool, token string) {
 // @Security BearerAuth
 // @Produce json
 // @Success 200 {object} auth.UserInfo
-// @Failure 401 {object} map[string]string
+// @Failure 401 {object} types.ErrorResponse
 // @Router /api/v1/me [get]
 func (h *Handler) Me(c *gin.Context) {
 	// Get claims from context (set by auth middleware)
 	claims, exists := c.Get("claims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		types.SendError(c, http.StatusUnauthorized, types.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -76,7 +77,7 @@ func (h *Handler) AuthMiddleware() gin.HandlerFunc {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			types.SendError(c, http.StatusUnauthorized, types.CodeUnauthorized, "Authorization header required")
 			c.Abort()
 			return
 		}
@@ -84,7 +85,7 @@ func (h *Handler) AuthMiddleware() gin.HandlerFunc {
 		// Check Bearer prefix
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			types.SendError(c, http.StatusUnauthorized, types.CodeUnauthorized, "Invalid authorization header format")
 			c.Abort()
 			return
 		}
@@ -93,9 +94,9 @@ func (h *Handler) AuthMiddleware() gin.HandlerFunc {
 		claims, err := h.authService.ValidateToken(parts[1])
 		if err != nil {
 			if err == auth.ErrUnauthorized {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Access denied - insufficient permissions"})
+				types.SendError(c, http.StatusForbidden, types.CodeForbidden, "Access denied - insufficient permissions")
 			} else {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+				types.SendError(c, http.StatusUnauthorized, types.CodeUnauthorized, "Invalid or expired token")
 			}
 			c.Abort()
 			return
@@ -145,7 +146,7 @@ func (h *Handler) RequirePermission(permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		claims, exists := c.Get("claims")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			types.SendError(c, http.StatusUnauthorized, types.CodeUnauthorized, "Authentication required")
 			c.Abort()
 			return
 		}
@@ -153,6 +154,9 @@ func (h *Handler) RequirePermission(permissions ...string) gin.HandlerFunc {
 		authClaims := claims.(*auth.Claims)
 		if !authClaims.HasAnyPermission(permissions...) {
 			c.JSON(http.StatusForbidden, gin.H{
+				"status":               types.StatusError,
+				"code":                 types.CodeForbidden,
+				"message":              "Insufficient permissions",
 				"error":                "Insufficient permissions",
 				"required_permissions": permissions,
 				"user_permissions":     authClaims.AppMetadata.Permissions,
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/spf13/viper"
+)
+
+// DefaultMaxBodyBytes is the request body limit when none is configured
+const DefaultMaxBodyBytes int64 = 1024 * 1024
+
+// BodyLimitRule overrides the body limit for routes under Prefix. Multipart
+// bodies are rejected unless a rule opts the route in.
+type BodyLimitRule struct {
+	Prefix    string `mapstructure:"prefix"`
+	MaxBodyKB int64  `mapstructure:"max_body_kb"` // 0 inherits the default limit
+	Multipart bool   `mapstructure:"multipart"`   // Accept multipart/form-data uploads
+}
+
+// BodyLimitConfig sets the global body limit and per-route-group overrides.
+// The rule with the longest matching prefix wins.
+type BodyLimitConfig struct {
+	MaxBytes int64 // <= 0 disables the limit
+	Rules    []BodyLimitRule
+}
+
+// BodyLimitConfigFromViper builds the body limit config from request_limits.*
+func BodyLimitConfigFromViper() BodyLimitConfig {
+	var rules []BodyLimitRule
+	if err := viper.UnmarshalKey("request_limits.routes", &rules); err != nil {
+		log.Printf("[WARN] Ignoring invalid request_limits.routes config: %v", err)
+		rules = nil
+	}
+	return BodyLimitConfig{
+		MaxBytes: viper.GetInt64("request_limits.max_body_kb") * 1024,
+		Rules:    rules,
+	}
+}
+
+// resolve returns the body limit and multipart policy for a request path
+func (cfg BodyLimitConfig) resolve(path string) (int64, bool) {
+	limit, multipart := cfg.MaxBytes, false
+	matched := -1
+	for _, rule := range cfg.Rules {
+		if !strings.HasPrefix(path, rule.Prefix) || len(rule.Prefix) <= matched {
+			continue
+		}
+		matched = len(rule.Prefix)
+		limit, multipart = cfg.MaxBytes, rule.Multipart
+		if rule.MaxBodyKB > 0 {
+			limit = rule.MaxBodyKB * 1024
+		}
+	}
+	return limit, multipart
+}
+
+func RequestSizeLimit() gin.HandlerFunc {
+	return RequestSizeLimitWithSize(DefaultMaxBodyBytes)
+}
+
+func RequestSizeLimitWithSize(maxBytes int64) gin.HandlerFunc {
+	return RequestSizeLimits(BodyLimitConfig{MaxBytes: maxBytes})
+}
+
+// RequestSizeLimits enforces per-route body limits. Bodies with a declared
+// Content-Length over the limit get 413 before any of the body is read; chunked
+// bodies are cut off by http.MaxBytesReader once they pass it, which surfaces as
+// a read error in the handler.
+func RequestSizeLimits(cfg BodyLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.Request
+		if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		limit, allowMultipart := cfg.resolve(req.URL.Path)
+
+		if mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err == nil && strings.HasPrefix(mediaType, "multipart/") {
+			if !allowMultipart {
+				abortBody(c, http.StatusUnsupportedMediaType, "Multipart bodies are not accepted on this route")
+				return
+			}
+			if params["boundary"] == "" {
+				abortBody(c, http.StatusBadRequest, "Multipart body is missing its boundary")
+				return
+			}
+		}
+
+		if limit > 0 {
+			if req.ContentLength > limit {
+				// Don't let the server try to drain a body we already refused
+				c.Header("Connection", "close")
+				abortBody(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds the %d KB limit", limit/1024))
+				return
+			}
+			req.Body = http.MaxBytesReader(c.Writer, req.Body, limit)
+		}
+
+		c.Next()
+	}
+}
+
+func abortBody(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, types.ErrorResponse{
+		Status:  types.StatusError,
+		Code:    types.CodeForStatus(status),
+		Message: message,
+	})
+}
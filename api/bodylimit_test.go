@@ -0,0 +1,110 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bodyLimitRouter(cfg BodyLimitConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestSizeLimits(cfg))
+	handler := func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+	router.POST("/api/v1/clips", handler)
+	router.POST("/api/v1/clips/import", handler)
+	return router
+}
+
+func postBody(router *gin.Engine, path, contentType string, body io.Reader, length int64) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", contentType)
+	req.ContentLength = length
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestSizeLimits_PerRouteLimits(t *testing.T) {
+	router := bodyLimitRouter(BodyLimitConfig{
+		MaxBytes: 1024,
+		Rules: []BodyLimitRule{
+			{Prefix: "/api/v1/clips/import", MaxBodyKB: 4, Multipart: true},
+		},
+	})
+
+	small := strings.Repeat("a", 512)
+	large := strings.Repeat("a", 2048)
+
+	w := postBody(router, "/api/v1/clips", "application/json", strings.NewReader(small), int64(len(small)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = postBody(router, "/api/v1/clips", "application/json", strings.NewReader(large), int64(len(large)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "1 KB limit")
+
+	w = postBody(router, "/api/v1/clips/import", "application/json", strings.NewReader(large), int64(len(large)))
+	assert.Equal(t, http.StatusOK, w.Code, "longer prefix rule should raise the limit")
+}
+
+func TestRequestSizeLimits_ChunkedBodyIsCutOff(t *testing.T) {
+	router := bodyLimitRouter(BodyLimitConfig{MaxBytes: 1024})
+
+	large := strings.Repeat("a", 2048)
+	// Unknown length: the limit is enforced while the handler reads
+	w := postBody(router, "/api/v1/clips", "application/json", strings.NewReader(large), -1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestRequestSizeLimits_Multipart(t *testing.T) {
+	router := bodyLimitRouter(BodyLimitConfig{
+		MaxBytes: 1024,
+		Rules:    []BodyLimitRule{{Prefix: "/api/v1/clips/import", Multipart: true}},
+	})
+
+	body := "--x\r\nContent-Disposition: form-data; name=\"f\"\r\n\r\nv\r\n--x--\r\n"
+
+	w := postBody(router, "/api/v1/clips", "multipart/form-data; boundary=x", strings.NewReader(body), int64(len(body)))
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = postBody(router, "/api/v1/clips/import", "multipart/form-data", strings.NewReader(body), int64(len(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postBody(router, "/api/v1/clips/import", "multipart/form-data; boundary=x", strings.NewReader(body), int64(len(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestBodyLimitConfigFromViper(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set("request_limits.max_body_kb", 2)
+	viper.Set("request_limits.routes", []map[string]interface{}{
+		{"prefix": "/api/v1/uploads", "max_body_kb": 10240, "multipart": true},
+	})
+
+	cfg := BodyLimitConfigFromViper()
+	assert.Equal(t, int64(2048), cfg.MaxBytes)
+	require.Len(t, cfg.Rules, 1)
+
+	limit, multipart := cfg.resolve("/api/v1/uploads/audio")
+	assert.Equal(t, int64(10240*1024), limit)
+	assert.True(t, multipart)
+
+	limit, multipart = cfg.resolve("/api/v1/episodes")
+	assert.Equal(t, int64(2048), limit)
+	assert.False(t, multipart)
+}
@@ -1,6 +1,8 @@
 package categories
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"time"
 
@@ -11,7 +13,7 @@ import (
 
 // CategoriesProvider defines the interface for getting categories
 type CategoriesProvider interface {
-	GetCategories() (*podcastindex.CategoriesResponse, error)
+	GetCategories(ctx context.Context) (*podcastindex.CategoriesResponse, error)
 }
 
 // Get returns all available podcast categories
@@ -23,6 +25,7 @@ type CategoriesProvider interface {
 // @Produce      json
 // @Success      200 {object} podcastindex.CategoriesResponse "Categories response with ID and name for each category"
 // @Failure      500 {object} types.ErrorResponse "Service unavailable or API communication failure"
+// @Failure      503 {object} types.ErrorResponse "Daily Podcast Index budget spent"
 // @Router       /api/v1/categories [get]
 func Get(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -31,22 +34,26 @@ func Get(deps *types.Dependencies) gin.HandlerFunc {
 		if !ok {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Categories service not available",
 			})
 			return
 		}
 
 		// Get categories
-		categories, err := podcastClient.GetCategories()
+		categories, err := podcastClient.GetCategories(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-				Status:  types.StatusError,
-				Message: "Failed to fetch categories",
-				Details: err.Error(),
-			})
+			types.SendServiceError(c, err, "Failed to fetch categories")
 			return
 		}
 
+		// Persist the category tree so local browse endpoints can resolve parents
+		if deps.CategoryService != nil {
+			if err := deps.CategoryService.SyncCategories(c.Request.Context(), categories.Feeds); err != nil {
+				log.Printf("[WARN] Failed to persist categories: %v", err)
+			}
+		}
+
 		// Add cache headers (categories rarely change)
 		c.Header("Cache-Control", "public, max-age=86400") // Cache for 24 hours
 		c.Header("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
@@ -0,0 +1,187 @@
+package categories
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	categoriesService "github.com/killallgit/player-api/internal/services/categories"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+)
+
+// CategoryTrending defines the interface for the remote category fallback
+type CategoryTrending interface {
+	GetTrending(ctx context.Context, max, since int, categories, notCategories []string, lang string, fullText bool) (*podcastindex.SearchResponse, error)
+}
+
+// GetPodcasts returns podcasts in a category
+// @Summary      Browse podcasts in a category
+// @Description  List locally known podcasts in a category, including its subcategories. Podcasts become locally
+// @Description  known once they have been fetched through the API. If no local podcasts match, trending podcasts
+// @Description  for the category are fetched from Podcast Index instead; the 'source' field indicates which was used.
+// @Description  Blocklisted podcasts are left out.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Podcast Index category ID"
+// @Param        limit query int false "Maximum results (1-100)" default(20)
+// @Param        offset query int false "Offset for local results" default(0)
+// @Success      200 {object} types.CategoryPodcastsResponse "Podcasts in the category"
+// @Failure      400 {object} types.ErrorResponse "Invalid category ID or pagination parameters"
+// @Failure      404 {object} types.ErrorResponse "Category not found"
+// @Failure      500 {object} types.ErrorResponse "Failed to fetch podcasts"
+// @Router       /api/v1/categories/{id}/podcasts [get]
+func GetPodcasts(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || categoryID == 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid category ID",
+			})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		if err != nil || limit < 1 || limit > 100 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Limit must be between 1 and 100",
+			})
+			return
+		}
+
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Offset must be a non-negative integer",
+			})
+			return
+		}
+
+		if deps.CategoryService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Category service not available",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+		defer cancel()
+
+		category, err := lookupCategory(ctx, deps, uint(categoryID))
+		if err != nil {
+			if errors.Is(err, categoriesService.ErrCategoryNotFound) {
+				c.JSON(http.StatusNotFound, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeCategoryNotFound,
+					Message: "Category not found",
+				})
+				return
+			}
+			types.SendServiceError(c, err, "Failed to load category")
+			return
+		}
+
+		categoryData := types.Category{ID: int(category.ID), Name: category.Name}
+		if category.ParentID != nil {
+			categoryData.ParentID = int(*category.ParentID)
+		}
+
+		block := types.Blocklist(c, deps)
+
+		localPodcasts, total, err := deps.CategoryService.ListPodcastsInCategory(ctx, category.ID, limit, offset)
+		if err != nil {
+			log.Printf("[WARN] Failed to list local podcasts for category %d: %v", category.ID, err)
+		}
+
+		if total > 0 {
+			kept := localPodcasts[:0]
+			for i := range localPodcasts {
+				if !block.Blocks(types.PodcastSubject(&localPodcasts[i])) {
+					kept = append(kept, localPodcasts[i])
+				}
+			}
+			podcasts := types.FromModelPodcastList(kept)
+			c.JSON(http.StatusOK, types.CategoryPodcastsResponse{
+				BaseResponse: types.BaseResponse{
+					Status:  types.StatusOK,
+					Message: "Fetched podcasts in category",
+				},
+				Category: categoryData,
+				Podcasts: podcasts,
+				Count:    len(podcasts),
+				Total:    int(total),
+				Offset:   offset,
+				Source:   "local",
+			})
+			return
+		}
+
+		trendingClient, ok := deps.PodcastClient.(CategoryTrending)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Podcast Index client not available",
+			})
+			return
+		}
+
+		results, err := trendingClient.GetTrending(ctx, limit, 0, []string{category.Name}, block.Categories(nil), "", false)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to fetch podcasts for category")
+			return
+		}
+
+		podcasts := types.FromPodcastIndexList(block.FilterPodcasts(results.Feeds))
+		c.JSON(http.StatusOK, types.CategoryPodcastsResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Fetched podcasts in category from Podcast Index",
+			},
+			Category: categoryData,
+			Podcasts: podcasts,
+			Count:    len(podcasts),
+			Source:   "remote",
+		})
+	}
+}
+
+// lookupCategory loads a category from the local tree, syncing the tree from
+// Podcast Index once if the category is not known yet
+func lookupCategory(ctx context.Context, deps *types.Dependencies, id uint) (*models.Category, error) {
+	category, err := deps.CategoryService.GetCategory(ctx, id)
+	if err == nil || !errors.Is(err, categoriesService.ErrCategoryNotFound) {
+		return category, err
+	}
+
+	provider, ok := deps.PodcastClient.(CategoriesProvider)
+	if !ok {
+		return nil, err
+	}
+
+	remote, fetchErr := provider.GetCategories(ctx)
+	if fetchErr != nil {
+		log.Printf("[WARN] Failed to fetch categories for sync: %v", fetchErr)
+		return nil, err
+	}
+
+	if syncErr := deps.CategoryService.SyncCategories(ctx, remote.Feeds); syncErr != nil {
+		return nil, syncErr
+	}
+
+	return deps.CategoryService.GetCategory(ctx, id)
+}
@@ -9,4 +9,7 @@ import (
 func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
 	// GET /api/v1/categories
 	router.GET("", Get(deps))
+
+	// GET /api/v1/categories/:id/podcasts
+	router.GET("/:id/podcasts", GetPodcasts(deps))
 }
@@ -12,7 +12,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/spf13/viper"
 )
 
 // CreateClipRequest represents the request to create a clip
@@ -23,28 +25,87 @@ type CreateClipRequest struct {
 	OriginalStartTime     float64 `json:"start_time" binding:"min=0" example:"30" description:"Start time in seconds (can be 0)"`
 	OriginalEndTime       float64 `json:"end_time" binding:"required,gt=0" example:"45" description:"End time in seconds (must be > start_time)"`
 	Label                 string  `json:"label" binding:"required,min=1" example:"advertisement" description:"Classification label for ML training"`
+	CallbackURL           string  `json:"callback_url,omitempty" example:"https://pipeline.example.com/hooks/clips" description:"Queue extraction now and POST a signed notification here when it completes or fails"`
 }
 
 // ClipResponse represents a clip in API responses
 // @Description Complete information about an audio clip
 type ClipResponse struct {
-	UUID                  string   `json:"uuid" example:"052f3b9b-cc02-418c-a9ab-8f49534c01c8" description:"Unique identifier for the clip"`
-	PodcastIndexEpisodeID int64    `json:"podcast_index_episode_id" example:"12345" description:"Podcast Index Episode ID for clip organization"`
-	Label                 string   `json:"label" example:"advertisement" description:"ML training label"`
-	Status                string   `json:"status" example:"ready" enums:"queued,processing,ready,failed" description:"Processing status: queued, processing, ready, or failed"`
-	Extracted             bool     `json:"extracted" example:"true" description:"Whether audio file has been extracted to storage"`
-	ClipFilename          *string  `json:"filename,omitempty" example:"clip_052f3b9b-cc02-418c-a9ab-8f49534c01c8.wav" description:"Generated filename (null if not extracted)"`
-	ClipDuration          *float64 `json:"duration,omitempty" example:"15" description:"Duration in seconds (null if not extracted)"`
-	ClipSizeBytes         *int64   `json:"size_bytes,omitempty" example:"480078" description:"File size in bytes (null if not extracted)"`
-	SourceEpisodeURL      string   `json:"source_episode_url" example:"https://example.com/episode.mp3" description:"Original audio source"`
-	OriginalStartTime     float64  `json:"original_start_time" example:"30" description:"Original start time in source"`
-	OriginalEndTime       float64  `json:"original_end_time" example:"45" description:"Original end time in source"`
-	AutoLabeled           bool     `json:"auto_labeled" example:"false" description:"Whether this clip was automatically labeled"`
-	LabelConfidence       *float64 `json:"label_confidence,omitempty" example:"0.85" description:"Confidence score (0.0-1.0) if auto-labeled"`
-	LabelMethod           string   `json:"label_method" example:"manual" description:"How it was labeled: manual, peak_detection, whisper, etc."`
-	ErrorMessage          string   `json:"error_message,omitempty" example:"failed to download source audio: HTTP 403" description:"Error details if status is failed"`
-	CreatedAt             string   `json:"created_at" example:"2025-09-25T16:36:45Z" description:"Creation timestamp"`
-	UpdatedAt             string   `json:"updated_at" example:"2025-09-25T16:36:47Z" description:"Last update timestamp"`
+	UUID                  string          `json:"uuid" example:"052f3b9b-cc02-418c-a9ab-8f49534c01c8" description:"Unique identifier for the clip"`
+	PodcastIndexEpisodeID int64           `json:"podcast_index_episode_id" example:"12345" description:"Podcast Index Episode ID for clip organization"`
+	Label                 string          `json:"label" example:"advertisement" description:"ML training label"`
+	Status                string          `json:"status" example:"ready" enums:"queued,processing,ready,failed" description:"Processing status: queued, processing, ready, or failed"`
+	Extracted             bool            `json:"extracted" example:"true" description:"Whether audio file has been extracted to storage"`
+	ClipFilename          *string         `json:"filename,omitempty" example:"clip_052f3b9b-cc02-418c-a9ab-8f49534c01c8.wav" description:"Generated filename (null if not extracted)"`
+	ClipDuration          *float64        `json:"duration,omitempty" example:"15" description:"Duration in seconds (null if not extracted)"`
+	ClipSizeBytes         *int64          `json:"size_bytes,omitempty" example:"480078" description:"File size in bytes (null if not extracted)"`
+	SourceEpisodeURL      string          `json:"source_episode_url" example:"https://example.com/episode.mp3" description:"Original audio source"`
+	OriginalStartTime     float64         `json:"original_start_time" example:"30" description:"Original start time in source"`
+	OriginalEndTime       float64         `json:"original_end_time" example:"45" description:"Original end time in source"`
+	RangeClamped          bool            `json:"range_clamped,omitempty" example:"false" description:"The requested end ran past the episode and was moved to its end"`
+	AutoLabeled           bool            `json:"auto_labeled" example:"false" description:"Whether this clip was automatically labeled"`
+	LabelConfidence       *float64        `json:"label_confidence,omitempty" example:"0.85" description:"Confidence score (0.0-1.0) if auto-labeled"`
+	LabelMethod           string          `json:"label_method" example:"manual" description:"How it was labeled: manual, peak_detection, model, etc."`
+	ModelName             string          `json:"model_name,omitempty" example:"volume-spike" description:"Registry model whose detection created the clip"`
+	ModelVersion          string          `json:"model_version,omitempty" example:"1" description:"Version of model_name"`
+	Approved              bool            `json:"approved" example:"false" description:"Whether the clip is approved for dataset export"`
+	AutoApproved          bool            `json:"auto_approved" example:"false" description:"Approved by analysis for a score over its label's threshold, awaiting a reviewer's confirmation"`
+	ApprovalThreshold     *float64        `json:"approval_threshold,omitempty" example:"0.97" description:"Threshold the score met when auto-approved"`
+	Rejected              bool            `json:"rejected" example:"false" description:"Whether a reviewer rejected the clip"`
+	RejectionReason       string          `json:"rejection_reason,omitempty" example:"false_positive" enums:"false_positive,bad_boundaries,poor_audio,duplicate,other" description:"Why the clip was rejected"`
+	RejectedAt            string          `json:"rejected_at,omitempty" example:"2025-09-25T17:00:00Z" description:"When the clip was rejected"`
+	ErrorMessage          string          `json:"error_message,omitempty" example:"failed to download source audio: HTTP 403" description:"Error details if status is failed"`
+	JobError              *types.JobError `json:"job_error,omitempty" description:"Why extraction failed: category, code, whether it will be retried, and a message for users"`
+	TranscriptText        *string         `json:"transcript_text,omitempty" example:"This episode is brought to you by" description:"Spoken text of the time range"`
+	CreatedAt             string          `json:"created_at" example:"2025-09-25T16:36:45Z" description:"Creation timestamp"`
+	UpdatedAt             string          `json:"updated_at" example:"2025-09-25T16:36:47Z" description:"Last update timestamp"`
+	JobID                 uint            `json:"job_id,omitempty" example:"42" description:"Extraction job queued for callback_url"`
+	SampleRate            *int            `json:"sample_rate,omitempty" example:"44100" description:"Sample rate of the episode's cached original audio (omitted until it is cached)"`
+	StartSample           *int64          `json:"start_sample,omitempty" example:"1323000" description:"original_start_time in sample frames at sample_rate"`
+	EndSample             *int64          `json:"end_sample,omitempty" example:"1984500" description:"original_end_time in sample frames at sample_rate (exclusive)"`
+}
+
+// newClipResponse converts a clip model to its API representation, with
+// sample coordinates when its episode's sample rate is in sampleRates
+func newClipResponse(clip *models.Clip, sampleRates map[int64]int) ClipResponse {
+	response := ClipResponse{
+		UUID:                  clip.UUID,
+		PodcastIndexEpisodeID: clip.PodcastIndexEpisodeID,
+		Label:                 clip.Label,
+		Status:                clip.Status,
+		Extracted:             clip.Extracted,
+		ClipFilename:          clip.ClipFilename,
+		ClipDuration:          clip.ClipDuration,
+		ClipSizeBytes:         clip.ClipSizeBytes,
+		SourceEpisodeURL:      clip.SourceEpisodeURL,
+		OriginalStartTime:     clip.OriginalStartTime,
+		OriginalEndTime:       clip.OriginalEndTime,
+		RangeClamped:          clip.RangeClamped,
+		AutoLabeled:           clip.AutoLabeled,
+		LabelConfidence:       clip.LabelConfidence,
+		LabelMethod:           clip.LabelMethod,
+		ModelName:             clip.ModelName,
+		ModelVersion:          clip.ModelVersion,
+		Approved:              clip.Approved,
+		AutoApproved:          clip.AutoApproved,
+		ApprovalThreshold:     clip.ApprovalThreshold,
+		Rejected:              clip.Rejected,
+		RejectionReason:       clip.RejectionReason,
+		ErrorMessage:          clip.ErrorMessage,
+		JobError:              types.NewClipError(clip),
+		TranscriptText:        clip.TranscriptText,
+		CreatedAt:             clip.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:             clip.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if clip.RejectedAt != nil {
+		response.RejectedAt = clip.RejectedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if samples := clip.Samples(sampleRates[clip.PodcastIndexEpisodeID]); samples != nil {
+		response.SampleRate = &samples.SampleRate
+		response.StartSample = &samples.StartSample
+		response.EndSample = &samples.EndSample
+	}
+	return response
 }
 
 // UpdateLabelRequest represents the request to update a clip's label
@@ -58,12 +119,13 @@ type UpdateLabelRequest struct {
 // @Description The clip is stored as metadata (time range + label) and will be extracted during dataset export.
 // @Description No audio processing occurs immediately - clips are materialized only when exporting the dataset.
 // @Description The exact time range specified is preserved (no padding or cropping to fixed duration).
+// @Description With callback_url, extraction is queued immediately and the URL receives a signed POST when it finishes.
 // @Tags clips
 // @Accept json
 // @Produce json
 // @Param request body CreateClipRequest true "Audio clip parameters with episode ID and time range in seconds"
 // @Success 202 {object} ClipResponse "Clip created successfully (status=pending, awaiting export)"
-// @Failure 400 {object} types.ErrorResponse "Invalid request parameters (e.g., end_time <= start_time)"
+// @Failure 400 {object} types.ErrorResponse "Invalid request parameters (e.g., end_time <= start_time, or start_time past the end of the episode)"
 // @Failure 500 {object} types.ErrorResponse "Internal server error during clip creation"
 // @Router /api/v1/clips [post]
 func CreateClip(deps *types.Dependencies) gin.HandlerFunc {
@@ -76,7 +138,10 @@ func CreateClip(deps *types.Dependencies) gin.HandlerFunc {
 
 		// Validate time range
 		if req.OriginalEndTime <= req.OriginalStartTime {
-			types.SendBadRequest(c, "end_time must be greater than start_time")
+			types.SendError(c, http.StatusBadRequest, types.CodeClipInvalidRange, "end_time must be greater than start_time")
+			return
+		}
+		if !types.ValidateCallbackURL(c, deps, req.CallbackURL) {
 			return
 		}
 
@@ -86,33 +151,20 @@ func CreateClip(deps *types.Dependencies) gin.HandlerFunc {
 			OriginalStartTime:     req.OriginalStartTime,
 			OriginalEndTime:       req.OriginalEndTime,
 			Label:                 req.Label,
+			CreatedBy:             c.GetString("user_id"),
 		})
 
 		if err != nil {
-			types.SendInternalError(c, fmt.Sprintf("Failed to create clip: %v", err))
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to create clip: %v", err))
 			return
 		}
 
+		types.ClipTranscripts(c, deps, clip)
+		response := newClipResponse(clip, types.ClipSampleRates(c, deps, clip))
+		response.JobID = types.QueueClipExtraction(c, deps, clip.UUID, req.CallbackURL)
+
 		// Return accepted status since processing is async
-		c.JSON(http.StatusAccepted, ClipResponse{
-			UUID:                  clip.UUID,
-			PodcastIndexEpisodeID: clip.PodcastIndexEpisodeID,
-			Label:                 clip.Label,
-			Status:                clip.Status,
-			Extracted:             clip.Extracted,
-			ClipFilename:          clip.ClipFilename,
-			ClipDuration:          clip.ClipDuration,
-			ClipSizeBytes:         clip.ClipSizeBytes,
-			SourceEpisodeURL:      clip.SourceEpisodeURL,
-			OriginalStartTime:     clip.OriginalStartTime,
-			OriginalEndTime:       clip.OriginalEndTime,
-			AutoLabeled:           clip.AutoLabeled,
-			LabelConfidence:       clip.LabelConfidence,
-			LabelMethod:           clip.LabelMethod,
-			ErrorMessage:          clip.ErrorMessage,
-			CreatedAt:             clip.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			UpdatedAt:             clip.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-		})
+		c.JSON(http.StatusAccepted, response)
 	}
 }
 
@@ -136,33 +188,12 @@ func GetClip(deps *types.Dependencies) gin.HandlerFunc {
 
 		clip, err := deps.ClipService.GetClip(c.Request.Context(), uuid)
 		if err != nil {
-			if err.Error() == "clip not found" {
-				types.SendNotFound(c, "Clip not found")
-			} else {
-				types.SendInternalError(c, fmt.Sprintf("Failed to get clip: %v", err))
-			}
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to get clip: %v", err))
 			return
 		}
 
-		c.JSON(http.StatusOK, ClipResponse{
-			UUID:                  clip.UUID,
-			PodcastIndexEpisodeID: clip.PodcastIndexEpisodeID,
-			Label:                 clip.Label,
-			Status:                clip.Status,
-			Extracted:             clip.Extracted,
-			ClipFilename:          clip.ClipFilename,
-			ClipDuration:          clip.ClipDuration,
-			ClipSizeBytes:         clip.ClipSizeBytes,
-			SourceEpisodeURL:      clip.SourceEpisodeURL,
-			OriginalStartTime:     clip.OriginalStartTime,
-			OriginalEndTime:       clip.OriginalEndTime,
-			AutoLabeled:           clip.AutoLabeled,
-			LabelConfidence:       clip.LabelConfidence,
-			LabelMethod:           clip.LabelMethod,
-			ErrorMessage:          clip.ErrorMessage,
-			CreatedAt:             clip.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			UpdatedAt:             clip.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-		})
+		types.ClipTranscripts(c, deps, clip)
+		c.JSON(http.StatusOK, newClipResponse(clip, types.ClipSampleRates(c, deps, clip)))
 	}
 }
 
@@ -178,6 +209,7 @@ func GetClip(deps *types.Dependencies) gin.HandlerFunc {
 // @Success 200 {object} ClipResponse "Label updated successfully"
 // @Failure 400 {object} types.ErrorResponse "Invalid request (empty label or malformed JSON)"
 // @Failure 404 {object} types.ErrorResponse "Clip with specified UUID not found"
+// @Failure 409 {object} types.ErrorResponse "Approved clip would exceed the new label's quota"
 // @Failure 500 {object} types.ErrorResponse "Internal server error or storage operation failed"
 // @Router /api/v1/clips/{uuid}/label [put]
 func UpdateClipLabel(deps *types.Dependencies) gin.HandlerFunc {
@@ -194,35 +226,15 @@ func UpdateClipLabel(deps *types.Dependencies) gin.HandlerFunc {
 			return
 		}
 
-		clip, err := deps.ClipService.UpdateClipLabel(c.Request.Context(), uuid, req.Label)
+		ctx := clips.WithActor(c.Request.Context(), c.GetString("user_id"))
+		clip, err := deps.ClipService.UpdateClipLabel(ctx, uuid, req.Label)
 		if err != nil {
-			if err.Error() == "clip not found" {
-				types.SendNotFound(c, "Clip not found")
-			} else {
-				types.SendInternalError(c, fmt.Sprintf("Failed to update label: %v", err))
-			}
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to update label: %v", err))
 			return
 		}
 
-		c.JSON(http.StatusOK, ClipResponse{
-			UUID:                  clip.UUID,
-			PodcastIndexEpisodeID: clip.PodcastIndexEpisodeID,
-			Label:                 clip.Label,
-			Status:                clip.Status,
-			Extracted:             clip.Extracted,
-			ClipFilename:          clip.ClipFilename,
-			ClipDuration:          clip.ClipDuration,
-			ClipSizeBytes:         clip.ClipSizeBytes,
-			SourceEpisodeURL:      clip.SourceEpisodeURL,
-			OriginalStartTime:     clip.OriginalStartTime,
-			OriginalEndTime:       clip.OriginalEndTime,
-			AutoLabeled:           clip.AutoLabeled,
-			LabelConfidence:       clip.LabelConfidence,
-			LabelMethod:           clip.LabelMethod,
-			ErrorMessage:          clip.ErrorMessage,
-			CreatedAt:             clip.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			UpdatedAt:             clip.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-		})
+		types.ClipTranscripts(c, deps, clip)
+		c.JSON(http.StatusOK, newClipResponse(clip, types.ClipSampleRates(c, deps, clip)))
 	}
 }
 
@@ -243,7 +255,8 @@ func DeleteClip(deps *types.Dependencies) gin.HandlerFunc {
 			return
 		}
 
-		if err := deps.ClipService.DeleteClip(c.Request.Context(), uuid); err != nil {
+		ctx := clips.WithActor(c.Request.Context(), c.GetString("user_id"))
+		if err := deps.ClipService.DeleteClip(ctx, uuid); err != nil {
 			types.SendInternalError(c, fmt.Sprintf("Failed to delete clip: %v", err))
 			return
 		}
@@ -252,63 +265,118 @@ func DeleteClip(deps *types.Dependencies) gin.HandlerFunc {
 	}
 }
 
+// ListClipsResponse is a page of clips
+// @Description Clips matching the filters, with the total for paging
+type ListClipsResponse struct {
+	Clips  []ClipResponse `json:"clips"`
+	Total  int64          `json:"total" example:"240" description:"Number of clips matching the filters"`
+	Limit  int            `json:"limit" example:"100"`
+	Offset int            `json:"offset" example:"0"`
+}
+
 // @Summary List clips with optional filtering
-// @Description Retrieve a paginated list of clips with optional filtering by label and processing status.
-// @Description Results are ordered by creation time (newest first). Use this endpoint to monitor clip processing
-// @Description or to browse available training data by label.
+// @Description Retrieve a paginated list of clips with optional filtering by label, processing status, episode
+// @Description and podcast. Results are ordered by creation time (newest first) unless sort picks another key;
+// @Description clips without a confidence score sort last when sorting by confidence. Use this endpoint to
+// @Description monitor clip processing or to browse available training data by label.
 // @Tags clips
 // @Produce json
 // @Param label query string false "Filter clips by exact label match (e.g., 'advertisement')"
 // @Param status query string false "Filter by processing status" Enums(queued, processing, ready, failed)
+// @Param rejected query boolean false "Filter by rejection status"
+// @Param auto_approved query boolean false "Filter by whether analysis approved the clip and it awaits a reviewer's confirmation"
+// @Param rejection_reason query string false "Filter rejected clips by reason" Enums(false_positive, bad_boundaries, poor_audio, duplicate, other)
+// @Param episode_id query int false "Filter by Podcast Index episode ID"
+// @Param podcast_id query int false "Filter by Podcast Index feed ID"
+// @Param model query string false "Filter by the name of the registry model that created the clip"
+// @Param sort query string false "Sort key" Enums(created_at, duration, confidence) default(created_at)
+// @Param order query string false "Sort direction" Enums(desc, asc) default(desc)
 // @Param limit query int false "Maximum number of clips to return (1-1000)" default(100) minimum(1) maximum(1000)
 // @Param offset query int false "Number of clips to skip for pagination" default(0) minimum(0)
-// @Success 200 {array} ClipResponse "List of clips matching the filters"
+// @Success 200 {object} ListClipsResponse "Clips matching the filters"
+// @Failure 400 {object} types.ErrorResponse "Invalid query parameters"
 // @Failure 500 {object} types.ErrorResponse "Internal server error"
 // @Router /api/v1/clips [get]
 func ListClips(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Parse query parameters
-		label := c.Query("label")
-		status := c.Query("status")
+		filters := clips.ListClipsFilters{
+			Label:  c.Query("label"),
+			Status: c.Query("status"),
+			Reason: c.Query("rejection_reason"),
+			Model:  c.Query("model"),
+			Sort:   c.DefaultQuery("sort", clips.SortByCreatedAt),
+		}
 
-		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
-		offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if rejectedStr := c.Query("rejected"); rejectedStr != "" {
+			rejected := rejectedStr == "true"
+			filters.Rejected = &rejected
+		}
+		if autoApprovedStr := c.Query("auto_approved"); autoApprovedStr != "" {
+			autoApproved := autoApprovedStr == "true"
+			filters.AutoApproved = &autoApproved
+		}
 
-		// List clips
-		clipsList, err := deps.ClipService.ListClips(c.Request.Context(), clips.ListClipsFilters{
-			Label:  label,
-			Status: status,
-			Limit:  limit,
-			Offset: offset,
-		})
+		if !clips.IsValidClipSort(filters.Sort) {
+			types.SendBadRequest(c, "sort must be one of created_at, duration, confidence")
+			return
+		}
+		switch c.DefaultQuery("order", "desc") {
+		case "desc":
+		case "asc":
+			filters.Ascending = true
+		default:
+			types.SendBadRequest(c, "order must be 'asc' or 'desc'")
+			return
+		}
+
+		for param, target := range map[string]**int64{"episode_id": &filters.EpisodeID, "podcast_id": &filters.PodcastID} {
+			raw := c.Query(param)
+			if raw == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || id <= 0 {
+				types.SendBadRequest(c, "Invalid "+param)
+				return
+			}
+			*target = &id
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit < 1 || limit > 1000 {
+			types.SendBadRequest(c, "limit must be between 1 and 1000")
+			return
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			types.SendBadRequest(c, "offset must be a non-negative integer")
+			return
+		}
+		filters.Limit = limit
+		filters.Offset = offset
 
+		clipsList, err := deps.ClipService.ListClips(c.Request.Context(), filters)
 		if err != nil {
 			types.SendInternalError(c, fmt.Sprintf("Failed to list clips: %v", err))
 			return
 		}
+		total, err := deps.ClipService.CountClips(c.Request.Context(), filters)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to count clips: %v", err))
+			return
+		}
 
 		// Convert to response format
-		response := make([]ClipResponse, len(clipsList))
+		response := ListClipsResponse{
+			Clips:  make([]ClipResponse, len(clipsList)),
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		}
+		types.ClipTranscripts(c, deps, clipsList...)
+		rates := types.ClipSampleRates(c, deps, clipsList...)
 		for i, clip := range clipsList {
-			response[i] = ClipResponse{
-				UUID:                  clip.UUID,
-				PodcastIndexEpisodeID: clip.PodcastIndexEpisodeID,
-				Label:                 clip.Label,
-				Status:                clip.Status,
-				Extracted:             clip.Extracted,
-				ClipFilename:          clip.ClipFilename,
-				ClipDuration:          clip.ClipDuration,
-				ClipSizeBytes:         clip.ClipSizeBytes,
-				SourceEpisodeURL:      clip.SourceEpisodeURL,
-				OriginalStartTime:     clip.OriginalStartTime,
-				OriginalEndTime:       clip.OriginalEndTime,
-				AutoLabeled:           clip.AutoLabeled,
-				LabelConfidence:       clip.LabelConfidence,
-				LabelMethod:           clip.LabelMethod,
-				ErrorMessage:          clip.ErrorMessage,
-				CreatedAt:             clip.CreatedAt.Format("2006-01-02T15:04:05Z"),
-				UpdatedAt:             clip.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-			}
+			response.Clips[i] = newClipResponse(clip, rates)
 		}
 
 		c.JSON(http.StatusOK, response)
@@ -322,15 +390,19 @@ func ListClips(deps *types.Dependencies) gin.HandlerFunc {
 // @Description with metadata for each clip. Audio files are in 16kHz mono WAV format with exact durations preserved.
 // @Description The manifest includes clip UUID, label, duration, source URL, and original time range for full traceability.
 // @Description Previously extracted clips are reused to avoid redundant processing.
+// @Description With include_negatives=true, clips rejected as false positives are added under hard_negatives/{label}/
+// @Description and marked with hard_negative=true in the manifest.
 // @Tags clips
 // @Produce application/zip
+// @Param include_negatives query boolean false "Include rejected false positives as hard negatives" default(false)
 // @Success 200 {file} binary "ZIP archive containing labeled audio clips and manifest.jsonl"
 // @Failure 500 {object} types.ErrorResponse "Internal server error during export"
 // @Router /api/v1/clips/export [get]
 func ExportDataset(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Create temporary directory for export
-		tempDir, err := os.MkdirTemp("", "dataset_export_*")
+		// Create temporary directory for export in temp_dir, where the
+		// cleanup service removes it if the export is interrupted
+		tempDir, err := os.MkdirTemp(viper.GetString("temp_dir"), "dataset_export_*")
 		if err != nil {
 			types.SendInternalError(c, fmt.Sprintf("Failed to create temp directory: %v", err))
 			return
@@ -338,7 +410,8 @@ func ExportDataset(deps *types.Dependencies) gin.HandlerFunc {
 		defer os.RemoveAll(tempDir) // Clean up
 
 		// Export dataset to temp directory
-		if err := deps.ClipService.ExportDataset(c.Request.Context(), tempDir); err != nil {
+		opts := clips.ExportOptions{IncludeHardNegatives: c.Query("include_negatives") == "true"}
+		if _, err := deps.ClipService.ExportDataset(c.Request.Context(), tempDir, opts); err != nil {
 			types.SendInternalError(c, fmt.Sprintf("Failed to export dataset: %v", err))
 			return
 		}
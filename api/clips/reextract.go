@@ -0,0 +1,121 @@
+package clips
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// ReextractRequest selects extracted clips to re-extract; omitted fields don't filter
+type ReextractRequest struct {
+	Labels        []string   `json:"labels" example:"advertisement"`
+	MinDuration   float64    `json:"min_duration" example:"2"`                      // Seconds
+	MaxDuration   float64    `json:"max_duration" example:"60"`                     // Seconds
+	CreatedAfter  *time.Time `json:"created_after" example:"2025-01-01T00:00:00Z"`  // Inclusive
+	CreatedBefore *time.Time `json:"created_before" example:"2025-10-01T00:00:00Z"` // Exclusive
+	PodcastIDs    []int64    `json:"podcast_ids" example:"920666"`                  // Podcast Index feed IDs
+	EpisodeIDs    []int64    `json:"episode_ids" example:"16795089"`                // Podcast Index episode IDs
+
+	// POST a signed notification here when the re-extraction job finishes
+	CallbackURL string `json:"callback_url" example:"https://pipeline.example.com/hooks/clips"`
+}
+
+// validate checks the selection ranges
+func (r *ReextractRequest) validate() string {
+	if r.MinDuration < 0 || r.MaxDuration < 0 {
+		return "min_duration and max_duration must not be negative"
+	}
+	if r.MaxDuration > 0 && r.MinDuration > r.MaxDuration {
+		return "min_duration must not exceed max_duration"
+	}
+	if r.CreatedAfter != nil && r.CreatedBefore != nil && !r.CreatedAfter.Before(*r.CreatedBefore) {
+		return "created_after must be before created_before"
+	}
+	return ""
+}
+
+// QueuedReextractionResponse is returned when re-extraction has been queued
+type QueuedReextractionResponse struct {
+	types.BaseResponse
+	Job types.PublicJob `json:"job"`
+}
+
+// @Summary Re-extract clips
+// @Description Queues a job that re-extracts already-extracted clips after the extractor settings (target
+// @Description duration, normalization) change. For each matching clip the stored file is deleted, the clip is
+// @Description reset to pending (extracted=false) and then extracted again from the episode audio with the
+// @Description current settings. The job's progress follows the clips done; its result counts the clips
+// @Description matched, re-extracted and failed. Clips that fail are marked failed with an error_message.
+// @Description Without filters every extracted clip is re-extracted.
+// @Tags clips
+// @Accept json
+// @Produce json
+// @Param request body ReextractRequest false "Clip selection"
+// @Success 202 {object} QueuedReextractionResponse
+// @Header 202 {string} Location "Job status URL"
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Failure 503 {object} types.ErrorResponse "Job queue is full, see Retry-After"
+// @Router /api/v1/clips/reextract [post]
+func ReextractClips(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ReextractRequest
+		if c.Request.ContentLength != 0 {
+			if !types.BindJSONOrError(c, &req) {
+				return
+			}
+		}
+		if msg := req.validate(); msg != "" {
+			types.SendBadRequest(c, msg)
+			return
+		}
+		if req.CallbackURL != "" && !types.ValidateCallbackURL(c, deps, req.CallbackURL) {
+			return
+		}
+
+		if deps.JobService == nil {
+			types.SendInternalError(c, "Job service not available")
+			return
+		}
+
+		var payload models.JobPayload
+		raw, err := json.Marshal(clips.ExportFilters{
+			Labels:        req.Labels,
+			MinDuration:   req.MinDuration,
+			MaxDuration:   req.MaxDuration,
+			CreatedAfter:  req.CreatedAfter,
+			CreatedBefore: req.CreatedBefore,
+			PodcastIDs:    req.PodcastIDs,
+			EpisodeIDs:    req.EpisodeIDs,
+		})
+		if err == nil {
+			err = json.Unmarshal(raw, &payload)
+		}
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to encode clip filters: %v", err))
+			return
+		}
+
+		ctx := c.Request.Context()
+		job, err := deps.JobService.EnqueueJob(ctx, models.JobTypeClipReextraction, payload,
+			jobs.WithCreatedBy(c.GetString("user_id")))
+		if err != nil {
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to queue clip re-extraction: %v", err))
+			return
+		}
+		types.RegisterCallback(ctx, deps, job.ID, req.CallbackURL)
+
+		types.SetJobLocation(c, job.ID)
+		c.JSON(http.StatusAccepted, QueuedReextractionResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Clip re-extraction queued"},
+			Job:          types.NewPublicJob(job),
+		})
+	}
+}
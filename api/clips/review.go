@@ -0,0 +1,169 @@
+package clips
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/clips"
+)
+
+// RejectClipRequest is the optional body for rejecting a clip
+// @Description Reason for rejecting a clip; defaults to false_positive when omitted
+type RejectClipRequest struct {
+	Reason string `json:"reason" example:"false_positive" enums:"false_positive,bad_boundaries,poor_audio,duplicate,other"`
+}
+
+// ReviewQueueResponse is a page of clips awaiting review
+// @Description Unreviewed clips ordered by label confidence
+type ReviewQueueResponse struct {
+	Clips  []ClipResponse `json:"clips"`
+	Total  int64          `json:"total" example:"42" description:"Number of clips awaiting review that match the filters"`
+	Limit  int            `json:"limit" example:"50"`
+	Offset int            `json:"offset" example:"0"`
+}
+
+// @Summary List clips awaiting review
+// @Description Lists clips across all episodes that are neither approved nor rejected, sorted by
+// @Description label confidence. Use order=asc to work through the least certain detections first.
+// @Description Clips without a confidence score are listed last in either order.
+// @Tags clips
+// @Produce json
+// @Param order query string false "Sort by confidence" Enums(desc, asc) default(desc)
+// @Param label query string false "Filter by exact label"
+// @Param episode_id query int false "Filter by Podcast Index episode ID"
+// @Param limit query int false "Maximum number of clips to return (1-200)" default(50) minimum(1) maximum(200)
+// @Param offset query int false "Number of clips to skip" default(0) minimum(0)
+// @Success 200 {object} ReviewQueueResponse "Clips awaiting review"
+// @Failure 400 {object} types.ErrorResponse "Invalid query parameters"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /api/v1/clips/review-queue [get]
+func ReviewQueue(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filters := clips.ReviewQueueFilters{Label: c.Query("label")}
+
+		switch c.DefaultQuery("order", "desc") {
+		case "desc":
+		case "asc":
+			filters.Ascending = true
+		default:
+			types.SendBadRequest(c, "order must be 'asc' or 'desc'")
+			return
+		}
+
+		if episodeIDStr := c.Query("episode_id"); episodeIDStr != "" {
+			episodeID, err := strconv.ParseInt(episodeIDStr, 10, 64)
+			if err != nil || episodeID <= 0 {
+				types.SendBadRequest(c, "Invalid episode_id")
+				return
+			}
+			filters.EpisodeID = &episodeID
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 || limit > 200 {
+			types.SendBadRequest(c, "limit must be between 1 and 200")
+			return
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			types.SendBadRequest(c, "offset must be a non-negative integer")
+			return
+		}
+		filters.Limit = limit
+		filters.Offset = offset
+
+		queue, total, err := deps.ClipService.ReviewQueue(c.Request.Context(), filters)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to load review queue: %v", err))
+			return
+		}
+
+		response := ReviewQueueResponse{
+			Clips:  make([]ClipResponse, len(queue)),
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		}
+		types.ClipTranscripts(c, deps, queue...)
+		rates := types.ClipSampleRates(c, deps, queue...)
+		for i, clip := range queue {
+			response.Clips[i] = newClipResponse(clip, rates)
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// @Summary Approve a clip from the review queue
+// @Description Marks a clip as approved for dataset export and removes it from the review queue.
+// @Description Approving an already approved clip is a no-op; approving a rejected clip clears the rejection.
+// @Description Approving a clip analysis auto-approved confirms it, clearing auto_approved; the audit trail
+// @Description records the confirmation as a human approval.
+// @Tags clips
+// @Produce json
+// @Param uuid path string true "Unique clip identifier (UUID format)"
+// @Success 200 {object} ClipResponse "Clip approved"
+// @Failure 404 {object} types.ErrorResponse "Clip not found"
+// @Failure 409 {object} types.ErrorResponse "The label has reached its approved-clip quota"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /api/v1/clips/{uuid}/approve [post]
+func ApproveClip(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := clips.WithActor(c.Request.Context(), c.GetString("user_id"))
+		clip, err := deps.ClipService.ApproveClip(ctx, c.Param("uuid"))
+		if err != nil {
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to approve clip: %v", err))
+			return
+		}
+
+		types.ClipTranscripts(c, deps, clip)
+		c.JSON(http.StatusOK, newClipResponse(clip, types.ClipSampleRates(c, deps, clip)))
+	}
+}
+
+// @Summary Reject a clip from the review queue
+// @Description Marks a clip as rejected with a reason code. Unlike deleting, the clip is kept so the
+// @Description decision is not lost: it leaves the review queue, stays listable via GET /clips?rejected=true,
+// @Description and clips rejected as false_positive can be exported as hard negatives.
+// @Description The body is optional; the reason defaults to false_positive. Rejecting again with a
+// @Description different reason updates it.
+// @Tags clips
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Unique clip identifier (UUID format)"
+// @Param request body RejectClipRequest false "Rejection reason"
+// @Success 200 {object} ClipResponse "Clip rejected"
+// @Failure 400 {object} types.ErrorResponse "Unknown rejection reason"
+// @Failure 404 {object} types.ErrorResponse "Clip not found"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /api/v1/clips/{uuid}/reject [post]
+func RejectClip(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RejectClipRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			types.SendBadRequest(c, err.Error())
+			return
+		}
+
+		ctx := clips.WithActor(c.Request.Context(), c.GetString("user_id"))
+		clip, err := deps.ClipService.RejectClip(ctx, c.Param("uuid"), req.Reason)
+		if err != nil {
+			if errors.Is(err, clips.ErrInvalidRejectionReason) {
+				types.SendBadRequest(c, err.Error())
+			} else if errors.Is(err, clips.ErrClipNotFound) {
+				types.SendError(c, http.StatusNotFound, types.CodeClipNotFound, "Clip not found")
+			} else {
+				types.SendInternalError(c, fmt.Sprintf("Failed to reject clip: %v", err))
+			}
+			return
+		}
+
+		types.ClipTranscripts(c, deps, clip)
+		c.JSON(http.StatusOK, newClipResponse(clip, types.ClipSampleRates(c, deps, clip)))
+	}
+}
@@ -2,7 +2,9 @@ package clips
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/middleware"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/spf13/viper"
 )
 
 // RegisterRoutes registers clip-related routes
@@ -10,10 +12,17 @@ func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
 	// Clip management endpoints
 	router.POST("", CreateClip(deps))                 // Create new clip
 	router.GET("", ListClips(deps))                   // List all clips
+	router.GET("/stats", GetClipStats(deps))          // Per-label counts, duration, bytes and quotas
+	router.POST("/reextract", ReextractClips(deps))   // Re-extract clips after extractor settings change
 	router.GET("/:uuid", GetClip(deps))               // Get specific clip
 	router.PUT("/:uuid/label", UpdateClipLabel(deps)) // Update clip label
 	router.DELETE("/:uuid", DeleteClip(deps))         // Delete clip
 
+	// Review workflow
+	router.GET("/review-queue", ReviewQueue(deps))   // Unreviewed clips sorted by confidence
+	router.POST("/:uuid/approve", ApproveClip(deps)) // Quick-approve from the queue
+	router.POST("/:uuid/reject", RejectClip(deps))   // Quick-reject from the queue
+
 	// Export endpoint
-	router.GET("/export", ExportDataset(deps)) // Export dataset as ZIP
+	router.GET("/export", middleware.StreamDeadline(viper.GetDuration("server.stream_write_timeout")), ExportDataset(deps)) // Export dataset as ZIP
 }
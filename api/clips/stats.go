@@ -0,0 +1,53 @@
+package clips
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/clips"
+)
+
+// ClipStatsResponse summarizes the clip corpus per label
+// @Description Clip counts, durations and storage per label, with quotas where configured
+type ClipStatsResponse struct {
+	Labels []clips.LabelStats `json:"labels"`
+	Total  clips.LabelStats   `json:"total"` // Sums over all labels; label, quota and remaining are empty
+}
+
+// @Summary Clip statistics per label
+// @Description Returns how many clips each label holds (approved, rejected, awaiting review, extracted),
+// @Description their total annotated duration and extracted size in bytes, and the label's approved-clip
+// @Description quota with the approvals remaining under it. Labels with a quota but no clips are included.
+// @Tags clips
+// @Produce json
+// @Success 200 {object} ClipStatsResponse
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /api/v1/clips/stats [get]
+func GetClipStats(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		labels, err := deps.ClipService.LabelStats(c.Request.Context())
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to get clip stats: %v", err))
+			return
+		}
+
+		response := ClipStatsResponse{Labels: labels}
+		if response.Labels == nil {
+			response.Labels = []clips.LabelStats{}
+		}
+		for _, label := range labels {
+			response.Total.Clips += label.Clips
+			response.Total.Approved += label.Approved
+			response.Total.Rejected += label.Rejected
+			response.Total.Pending += label.Pending
+			response.Total.Extracted += label.Extracted
+			response.Total.DurationSeconds += label.DurationSeconds
+			response.Total.ApprovedDuration += label.ApprovedDuration
+			response.Total.Bytes += label.Bytes
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
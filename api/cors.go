@@ -0,0 +1,201 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// CORSRule is the policy applied to requests from a set of origins. Origins may be
+// exact ("https://app.example.com"), a subdomain wildcard ("https://*.example.com")
+// or "*" for any origin.
+type CORSRule struct {
+	Origins          []string      `mapstructure:"origins"`
+	Methods          []string      `mapstructure:"methods"`
+	Headers          []string      `mapstructure:"headers"`
+	AllowCredentials *bool         `mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `mapstructure:"max_age"`
+}
+
+// CORSConfig configures the CORS middleware. Rules are checked in order and the
+// first one matching the request origin wins.
+type CORSConfig struct {
+	Enabled       bool
+	ExposeHeaders []string
+	Rules         []CORSRule
+}
+
+// CORSConfigFromViper builds the CORS config from security.cors_*. Entries in
+// security.cors_rules take precedence; fields they leave unset fall back to the
+// top-level values, which also form the final catch-all rule.
+func CORSConfigFromViper() CORSConfig {
+	credentials := viper.GetBool("security.cors_allow_credentials")
+	base := CORSRule{
+		Origins:          configList("security.cors_origins"),
+		Methods:          configList("security.cors_methods"),
+		Headers:          configList("security.cors_headers"),
+		AllowCredentials: &credentials,
+		MaxAge:           viper.GetDuration("security.cors_max_age"),
+	}
+
+	var rules []CORSRule
+	if err := viper.UnmarshalKey("security.cors_rules", &rules); err != nil {
+		log.Printf("[WARN] Ignoring invalid security.cors_rules config: %v", err)
+		rules = nil
+	}
+	for i := range rules {
+		rules[i] = rules[i].withDefaults(base)
+	}
+
+	return CORSConfig{
+		Enabled:       viper.GetBool("security.cors_enabled"),
+		ExposeHeaders: configList("security.cors_expose_headers"),
+		Rules:         append(rules, base),
+	}
+}
+
+// configList reads a list setting written either as a YAML list or a comma-separated string
+func configList(key string) []string {
+	var values []string
+	if err := viper.UnmarshalKey(key, &values); err != nil {
+		log.Printf("[WARN] Ignoring invalid %s config: %v", key, err)
+		return nil
+	}
+	return trimList(values)
+}
+
+func trimList(values []string) []string {
+	trimmed := make([]string, 0, len(values))
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				trimmed = append(trimmed, part)
+			}
+		}
+	}
+	return trimmed
+}
+
+func (r CORSRule) withDefaults(base CORSRule) CORSRule {
+	r.Origins = trimList(r.Origins)
+	if r.Methods = trimList(r.Methods); len(r.Methods) == 0 {
+		r.Methods = base.Methods
+	}
+	if r.Headers = trimList(r.Headers); len(r.Headers) == 0 {
+		r.Headers = base.Headers
+	}
+	if r.AllowCredentials == nil {
+		r.AllowCredentials = base.AllowCredentials
+	}
+	if r.MaxAge == 0 {
+		r.MaxAge = base.MaxAge
+	}
+	return r
+}
+
+func (r CORSRule) credentials() bool {
+	return r.AllowCredentials != nil && *r.AllowCredentials
+}
+
+// matches reports whether origin is allowed by the rule. An empty origin only
+// matches "*", so same-origin and non-browser requests still get wildcard headers.
+func (r CORSRule) matches(origin string) bool {
+	for _, allowed := range r.Origins {
+		if allowed == "*" {
+			return true
+		}
+		if origin == "" {
+			continue
+		}
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if scheme, host, ok := strings.Cut(allowed, "://*."); ok {
+			prefix := strings.ToLower(scheme + "://")
+			lower := strings.ToLower(origin)
+			if strings.HasPrefix(lower, prefix) && strings.HasSuffix(lower, "."+strings.ToLower(host)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r CORSRule) wildcard() bool {
+	for _, allowed := range r.Origins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS applies the configured cross-origin policy and answers preflight requests.
+// Range and If-Range should be in the allowed headers (and Content-Range in the
+// exposed ones) so browsers can seek in the audio stream.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	for _, rule := range cfg.Rules {
+		if rule.credentials() && rule.wildcard() {
+			log.Printf("[WARN] CORS rule allows credentials for any origin; every site can make authenticated requests")
+		}
+	}
+
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions
+
+		var rule *CORSRule
+		for i := range cfg.Rules {
+			if cfg.Rules[i].matches(origin) {
+				rule = &cfg.Rules[i]
+				break
+			}
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		if rule == nil {
+			if preflight && origin != "" {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// Credentials require echoing the origin; "*" is rejected by browsers
+		if origin == "" || (rule.wildcard() && !rule.credentials()) {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if rule.credentials() && origin != "" {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		if exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", exposeHeaders)
+		}
+		c.Header("Access-Control-Allow-Methods", strings.Join(rule.Methods, ", "))
+		c.Header("Access-Control-Allow-Headers", strings.Join(rule.Headers, ", "))
+
+		if preflight {
+			if rule.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(int(rule.MaxAge.Seconds())))
+			}
+			c.AbortWithStatus(http.StatusOK)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func corsRouter(cfg CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(cfg))
+	router.GET("/api/v1/episodes/:id/stream", func(c *gin.Context) {
+		c.Status(http.StatusPartialContent)
+	})
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/episodes/1/stream", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_PerOriginRules(t *testing.T) {
+	yes := true
+	no := false
+	router := corsRouter(CORSConfig{
+		Enabled:       true,
+		ExposeHeaders: []string{"Content-Range", "Accept-Ranges"},
+		Rules: []CORSRule{
+			{
+				Origins:          []string{"https://app.example.com", "https://*.preview.example.com"},
+				Methods:          []string{"GET", "POST"},
+				Headers:          []string{"Authorization", "Range"},
+				AllowCredentials: &yes,
+				MaxAge:           time.Hour,
+			},
+			{
+				Origins:          []string{"https://partner.example.org"},
+				Methods:          []string{"GET"},
+				Headers:          []string{"Range"},
+				AllowCredentials: &no,
+			},
+		},
+	})
+
+	t.Run("credentialed origin is echoed", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "https://app.example.com", nil)
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "Content-Range, Accept-Ranges", w.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("subdomain wildcard", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "https://pr-42.preview.example.com", nil)
+		assert.Equal(t, "https://pr-42.preview.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+		w = corsRequest(router, http.MethodGet, "https://preview.example.com.evil.test", nil)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("preflight allows Range for streaming", func(t *testing.T) {
+		w := corsRequest(router, http.MethodOptions, "https://app.example.com", map[string]string{
+			"Access-Control-Request-Method":  "GET",
+			"Access-Control-Request-Headers": "range",
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Range")
+		assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("second rule without credentials", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "https://partner.example.org", nil)
+		assert.Equal(t, "https://partner.example.org", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("unknown origin", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "https://evil.test", nil)
+		assert.Equal(t, http.StatusPartialContent, w.Code, "the browser enforces CORS; the request itself still runs")
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+		w = corsRequest(router, http.MethodOptions, "https://evil.test", map[string]string{
+			"Access-Control-Request-Method": "GET",
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestCORS_Disabled(t *testing.T) {
+	router := corsRouter(CORSConfig{Enabled: false, Rules: []CORSRule{{Origins: []string{"*"}}}})
+
+	w := corsRequest(router, http.MethodGet, "https://app.example.com", nil)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSConfigFromViper(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set("security.cors_enabled", true)
+	viper.Set("security.cors_origins", "https://a.example.com, https://b.example.com")
+	viper.Set("security.cors_methods", []string{"GET", "HEAD"})
+	viper.Set("security.cors_headers", "Range,If-Range")
+	viper.Set("security.cors_expose_headers", "Content-Range")
+	viper.Set("security.cors_max_age", "10m")
+	viper.Set("security.cors_rules", []map[string]interface{}{
+		{"origins": "https://app.example.com", "allow_credentials": true},
+	})
+
+	cfg := CORSConfigFromViper()
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, []string{"Content-Range"}, cfg.ExposeHeaders)
+	require.Len(t, cfg.Rules, 2)
+
+	rule := cfg.Rules[0]
+	assert.Equal(t, []string{"https://app.example.com"}, rule.Origins)
+	assert.Equal(t, []string{"GET", "HEAD"}, rule.Methods, "unset fields inherit the defaults")
+	assert.Equal(t, []string{"Range", "If-Range"}, rule.Headers)
+	assert.True(t, rule.credentials())
+	assert.Equal(t, 10*time.Minute, rule.MaxAge)
+
+	base := cfg.Rules[1]
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, base.Origins)
+	assert.False(t, base.credentials())
+}
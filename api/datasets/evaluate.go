@@ -0,0 +1,116 @@
+package datasets
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/datasets"
+)
+
+// EvaluateRequest is a model's predictions for a dataset's clips
+type EvaluateRequest struct {
+	Name        string                `json:"name" example:"ads-detector v7"`                  // Model or run name, for telling runs apart
+	MinScore    float64               `json:"min_score" example:"0.5" minimum:"0" maximum:"1"` // Predictions scoring lower count as "none"
+	Predictions []datasets.Prediction `json:"predictions" binding:"required,min=1"`
+}
+
+// @Summary Evaluate predictions against a dataset
+// @Description Scores a model's predictions (clip UUID, predicted label and optional score) against the labels in
+// @Description the dataset's archive and records the run. UUIDs are the ones in the archive's manifest, so
+// @Description pseudonymous IDs for anonymized datasets. Hard negatives are truly "none", as are predictions with
+// @Description an empty label or a score under min_score. The response carries the confusion matrix, precision,
+// @Description recall and F1 per label, accuracy, macro F1, and the most frequent confused pairs with the most
+// @Description confident mistakes of each as examples. Dataset clips without a prediction are counted as missing
+// @Description and predictions for clips not in the dataset as unknown; neither enters the matrix.
+// @Tags datasets
+// @Accept json
+// @Produce json
+// @Param id path string true "Dataset ID"
+// @Param request body EvaluateRequest true "Predictions"
+// @Success 201 {object} datasets.Evaluation
+// @Failure 400 {object} types.ErrorResponse "Invalid predictions, or none for clips in the dataset"
+// @Failure 404 {object} types.ErrorResponse "Dataset or its archive not found"
+// @Failure 413 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/datasets/{id}/evaluate [post]
+func EvaluateDataset(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req EvaluateRequest
+		if !types.BindJSONOrError(c, &req) {
+			return
+		}
+		if deps.DatasetService == nil {
+			types.SendInternalError(c, "Dataset service not available")
+			return
+		}
+
+		run, err := deps.DatasetService.Evaluate(c.Request.Context(), c.Param("id"), datasets.EvaluateParams{
+			Name:        req.Name,
+			MinScore:    req.MinScore,
+			Predictions: req.Predictions,
+			CreatedBy:   c.GetString("user_id"),
+		})
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to evaluate predictions")
+			return
+		}
+		c.JSON(http.StatusCreated, run)
+	}
+}
+
+// @Summary List a dataset's evaluations
+// @Description The recorded evaluation runs of a dataset, newest first, with their summary metrics for comparing
+// @Description models over time. Fetch a run for its confusion matrix.
+// @Tags datasets
+// @Produce json
+// @Param id path string true "Dataset ID"
+// @Success 200 {array} models.DatasetEvaluation
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/datasets/{id}/evaluations [get]
+func ListEvaluations(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.DatasetService == nil {
+			types.SendInternalError(c, "Dataset service not available")
+			return
+		}
+
+		runs, err := deps.DatasetService.ListEvaluations(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to list evaluations")
+			return
+		}
+		c.JSON(http.StatusOK, runs)
+	}
+}
+
+// @Summary Get an evaluation
+// @Tags datasets
+// @Produce json
+// @Param id path string true "Dataset ID"
+// @Param evaluation_id path int true "Evaluation ID"
+// @Success 200 {object} datasets.Evaluation
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/datasets/{id}/evaluations/{evaluation_id} [get]
+func GetEvaluation(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := types.ParseUintParam(c, "evaluation_id")
+		if !ok {
+			return
+		}
+		if deps.DatasetService == nil {
+			types.SendInternalError(c, "Dataset service not available")
+			return
+		}
+
+		run, err := deps.DatasetService.GetEvaluation(c.Request.Context(), c.Param("id"), id)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to get evaluation")
+			return
+		}
+		c.JSON(http.StatusOK, run)
+	}
+}
@@ -0,0 +1,472 @@
+package datasets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/datasets"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/spf13/viper"
+)
+
+// DatasetResponse describes a generated dataset
+type DatasetResponse struct {
+	ID               string  `json:"id" example:"ds-20251002-130000-1a2b3c4d"`
+	Name             string  `json:"name" example:"ads-v3"`
+	Description      string  `json:"description,omitempty"`
+	Format           string  `json:"format" example:"jsonl"`
+	AudioFormat      string  `json:"audio_format" example:"processed"`
+	TotalSamples     int     `json:"total_samples" example:"1240"`
+	TotalDuration    float64 `json:"total_duration_seconds" example:"18600.5"`
+	AverageDuration  float64 `json:"average_duration_seconds" example:"15"`
+	TotalSize        int64   `json:"total_size_bytes" example:"595200000"`
+	GenerationTimeMs int64   `json:"generation_time_ms" example:"84000"`
+	CreatedAt        string  `json:"created_at" example:"2025-10-02T13:00:00Z"`
+	DownloadURL      string  `json:"download_url" example:"/api/v1/datasets/ds-20251002-130000-1a2b3c4d/download"`
+
+	// Selected clips left out for lack of audio, and the regeneration queued
+	// behind caching their episodes (cache_missing_audio)
+	SkippedSamples int                     `json:"skipped_samples" example:"3"`
+	Skipped        []datasets.MissingAudio `json:"skipped,omitempty"`
+	RerunJobID     *uint                   `json:"rerun_job_id,omitempty" example:"812"`
+
+	Anonymized bool `json:"anonymized" example:"false"` // Identifiers in the archive are pseudonymous
+}
+
+// CreateDatasetRequest names a dataset to generate
+type CreateDatasetRequest struct {
+	Name             string `json:"name" example:"ads-v3"`
+	Description      string `json:"description" example:"Approved ads through October"`
+	IncludeNegatives bool   `json:"include_negatives" example:"false"`                                   // Add rejected false positives as hard negatives
+	Format           string `json:"format" example:"audiofolder" enums:"jsonl,audiofolder,kaldi,espnet"` // Metadata layout; defaults to jsonl
+
+	// Clip selection; omitted fields don't filter
+	Labels        []string   `json:"labels" example:"advertisement,music"`
+	MinDuration   float64    `json:"min_duration" example:"2"`                      // Seconds
+	MaxDuration   float64    `json:"max_duration" example:"60"`                     // Seconds
+	CreatedAfter  *time.Time `json:"created_after" example:"2025-01-01T00:00:00Z"`  // Inclusive
+	CreatedBefore *time.Time `json:"created_before" example:"2025-10-01T00:00:00Z"` // Exclusive
+	PodcastIDs    []int64    `json:"podcast_ids" example:"920666"`                  // Podcast Index feed IDs
+	EpisodeIDs    []int64    `json:"episode_ids" example:"16795089"`                // Podcast Index episode IDs
+
+	// Generate in the background and POST a signed notification here when done
+	CallbackURL string `json:"callback_url" example:"https://pipeline.example.com/hooks/datasets"`
+
+	// Queue caching for the episodes of clips skipped for lack of audio, and a
+	// regeneration once it completes
+	CacheMissingAudio bool `json:"cache_missing_audio" example:"false"`
+
+	// Replace identifiers with pseudonymous IDs and leave out source URLs and
+	// podcast names, for sharing outside
+	Anonymize bool `json:"anonymize" example:"false"`
+}
+
+// QueuedDatasetResponse is returned when generation runs as a background job
+type QueuedDatasetResponse struct {
+	types.BaseResponse
+	Job types.PublicJob `json:"job"`
+}
+
+// validate checks the clip selection ranges
+func (r *CreateDatasetRequest) validate() string {
+	if r.Format != "" && !models.IsValidDatasetFormat(r.Format) {
+		return "format must be " + models.DatasetFormats
+	}
+	if r.MinDuration < 0 || r.MaxDuration < 0 {
+		return "min_duration and max_duration must not be negative"
+	}
+	if r.MaxDuration > 0 && r.MinDuration > r.MaxDuration {
+		return "min_duration must not exceed max_duration"
+	}
+	if r.CreatedAfter != nil && r.CreatedBefore != nil && !r.CreatedAfter.Before(*r.CreatedBefore) {
+		return "created_after must be before created_before"
+	}
+	return ""
+}
+
+// SignedURLResponse is an expiring, unauthenticated download link
+type SignedURLResponse struct {
+	URL       string    `json:"url" example:"/api/v1/datasets/ds-20251002-130000-1a2b3c4d/download?expires=1759413600&signature=9f2c..."`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-10-03T13:00:00Z"`
+}
+
+func newDatasetResponse(dataset *models.Dataset) DatasetResponse {
+	var skipped []datasets.MissingAudio
+	if dataset.SkippedJSON != "" {
+		if err := json.Unmarshal([]byte(dataset.SkippedJSON), &skipped); err != nil {
+			log.Printf("[WARN] Failed to decode skip report of dataset %s: %v", dataset.ID, err)
+		}
+	}
+	return DatasetResponse{
+		ID:               dataset.ID,
+		Name:             dataset.Name,
+		Description:      dataset.Description,
+		Format:           dataset.Format,
+		AudioFormat:      dataset.AudioFormat,
+		TotalSamples:     dataset.TotalSamples,
+		TotalDuration:    dataset.TotalDuration,
+		AverageDuration:  dataset.AverageDuration,
+		TotalSize:        dataset.TotalSize,
+		GenerationTimeMs: dataset.GenerationTimeMs,
+		CreatedAt:        dataset.CreatedAt.Format(time.RFC3339),
+		DownloadURL:      downloadPath(dataset.ID),
+		SkippedSamples:   dataset.SkippedSamples,
+		Skipped:          skipped,
+		RerunJobID:       dataset.RerunJobID,
+		Anonymized:       dataset.Anonymized,
+	}
+}
+
+func downloadPath(id string) string {
+	return "/api/v1/datasets/" + url.PathEscape(id) + "/download"
+}
+
+// @Summary Generate a dataset
+// @Description Exports all approved clips (plus rejected false positives with include_negatives) into a zip
+// @Description archive of label directories and a manifest.jsonl, and records it as a dataset. Clips that
+// @Description have not been extracted yet are extracted first, so this can take a while. With format
+// @Description "audiofolder" the archive carries a Hugging Face metadata.jsonl instead of the manifest and
+// @Description loads with datasets.load_dataset("audiofolder", data_dir=...) once unzipped. Format "kaldi" adds a
+// @Description kaldi/ data directory (wav.scp, segments, utt2spk, spk2utt, utt2dur, utt2label, text) and "espnet"
+// @Description an ESPnet data.json next to the manifest, both with paths relative to the archive root.
+// @Description labels, min/max_duration, created_after/before, podcast_ids and episode_ids narrow the clips;
+// @Description without them the whole approved corpus is exported. With callback_url the dataset is built by a
+// @Description background job instead: the response is 202 with the job, and the URL receives a signed POST
+// @Description carrying dataset_id when it completes (or job.failed when it does not).
+// @Description With dry_run=true nothing is extracted, written or recorded: the response reports the sample counts
+// @Description per label, the clips whose audio is missing and the estimated archive size for the same options.
+// @Description Clips skipped for lack of audio are listed in skipped. With cache_missing_audio the episodes of clips
+// @Description whose source audio was missing are queued for caching, followed by a regeneration job (rerun_job_id)
+// @Description that runs once they have all completed.
+// @Description With anonymize the archive is fit to share outside: clip UUIDs and audio file names become
+// @Description pseudonymous IDs, source_url is dropped in favor of pseudonymous episode_id and podcast_id
+// @Description columns, and info.json lists sources by pseudonymous ID and language only. The IDs differ
+// @Description per dataset, and the mapping back to the real clips, episodes and podcasts stays on the server.
+// @Tags datasets
+// @Accept json
+// @Produce json
+// @Param request body CreateDatasetRequest false "Dataset name and options"
+// @Param dry_run query bool false "Report what would be generated without generating it"
+// @Success 200 {object} datasets.DryRunReport "Dry run"
+// @Success 201 {object} DatasetResponse
+// @Success 202 {object} QueuedDatasetResponse "Generation queued (callback_url set)"
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse "No approved clips to export"
+// @Failure 500 {object} types.ErrorResponse
+// @Failure 503 {object} types.ErrorResponse "Job queue is full, see Retry-After"
+// @Router /api/v1/datasets [post]
+func CreateDataset(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateDatasetRequest
+		if c.Request.ContentLength != 0 {
+			if !types.BindJSONOrError(c, &req) {
+				return
+			}
+		}
+		if msg := req.validate(); msg != "" {
+			types.SendBadRequest(c, msg)
+			return
+		}
+
+		if deps.DatasetService == nil {
+			types.SendInternalError(c, "Dataset service not available")
+			return
+		}
+
+		params := datasets.GenerateParams{
+			Name:                 req.Name,
+			Description:          req.Description,
+			IncludeHardNegatives: req.IncludeNegatives,
+			Format:               req.Format,
+			CacheMissingAudio:    req.CacheMissingAudio,
+			Anonymize:            req.Anonymize,
+			Filters: clips.ExportFilters{
+				Labels:        req.Labels,
+				MinDuration:   req.MinDuration,
+				MaxDuration:   req.MaxDuration,
+				CreatedAfter:  req.CreatedAfter,
+				CreatedBefore: req.CreatedBefore,
+				PodcastIDs:    req.PodcastIDs,
+				EpisodeIDs:    req.EpisodeIDs,
+			},
+		}
+
+		if c.Query("dry_run") == "true" {
+			report, err := deps.DatasetService.DryRun(c.Request.Context(), params)
+			if errors.Is(err, datasets.ErrUnsupportedFormat) {
+				types.SendBadRequest(c, "format must be "+models.DatasetFormats)
+				return
+			}
+			if err != nil {
+				types.SendInternalError(c, fmt.Sprintf("Failed to plan dataset: %v", err))
+				return
+			}
+			c.JSON(http.StatusOK, report)
+			return
+		}
+
+		if req.CallbackURL != "" {
+			queueDataset(c, deps, params, req.CallbackURL)
+			return
+		}
+
+		dataset, err := deps.DatasetService.Generate(c.Request.Context(), params)
+		if errors.Is(err, datasets.ErrUnsupportedFormat) {
+			types.SendBadRequest(c, "format must be "+models.DatasetFormats)
+			return
+		}
+		if errors.Is(err, datasets.ErrEmptyDataset) {
+			response := types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeDatasetEmpty,
+				Message: "No approved clips to export",
+			}
+			if err != datasets.ErrEmptyDataset {
+				response.Details = err.Error() // Says which re-run was queued
+			}
+			c.JSON(http.StatusUnprocessableEntity, response)
+			return
+		}
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to generate dataset: %v", err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, newDatasetResponse(dataset))
+	}
+}
+
+// queueDataset hands generation to a dataset_generation job whose payload is
+// the GenerateParams, and attaches callbackURL to it
+func queueDataset(c *gin.Context, deps *types.Dependencies, params datasets.GenerateParams, callbackURL string) {
+	if !types.ValidateCallbackURL(c, deps, callbackURL) {
+		return
+	}
+	if deps.JobService == nil {
+		types.SendInternalError(c, "Job service not available")
+		return
+	}
+
+	payload, err := params.Payload()
+	if err != nil {
+		types.SendInternalError(c, fmt.Sprintf("Failed to encode dataset parameters: %v", err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	job, err := deps.JobService.EnqueueJob(ctx, models.JobTypeDatasetGeneration, payload,
+		jobs.WithCreatedBy(c.GetString("user_id")))
+	if err != nil {
+		types.SendServiceError(c, err, fmt.Sprintf("Failed to queue dataset generation: %v", err))
+		return
+	}
+	types.RegisterCallback(ctx, deps, job.ID, callbackURL)
+
+	types.SetJobLocation(c, job.ID)
+	c.JSON(http.StatusAccepted, QueuedDatasetResponse{
+		BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Dataset generation queued"},
+		Job:          types.NewPublicJob(job),
+	})
+}
+
+// @Summary List datasets
+// @Description Lists generated datasets, newest first
+// @Tags datasets
+// @Produce json
+// @Param limit query int false "Maximum datasets to return" default(50) minimum(1) maximum(200)
+// @Success 200 {array} DatasetResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/datasets [get]
+func ListDatasets(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		if deps.DatasetService == nil {
+			types.SendInternalError(c, "Dataset service not available")
+			return
+		}
+
+		list, err := deps.DatasetService.List(c.Request.Context(), limit)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to list datasets: %v", err))
+			return
+		}
+
+		response := make([]DatasetResponse, len(list))
+		for i := range list {
+			response[i] = newDatasetResponse(&list[i])
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// @Summary Get a dataset
+// @Tags datasets
+// @Produce json
+// @Param id path string true "Dataset ID"
+// @Success 200 {object} DatasetResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/datasets/{id} [get]
+func GetDataset(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dataset, ok := loadDataset(c, deps)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, newDatasetResponse(dataset))
+	}
+}
+
+// @Summary Create a signed download URL
+// @Description Issues a download link for the dataset that works without authentication until it expires,
+// @Description for handing to training infrastructure. expires_in defaults to datasets.url_ttl and is capped
+// @Description at datasets.max_url_ttl.
+// @Tags datasets
+// @Produce json
+// @Param id path string true "Dataset ID"
+// @Param expires_in query string false "Link lifetime as a Go duration, e.g. 6h"
+// @Success 200 {object} SignedURLResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Router /api/v1/datasets/{id}/signed-url [post]
+func CreateSignedURL(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ttl := viper.GetDuration("datasets.url_ttl")
+		if raw := c.Query("expires_in"); raw != "" {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil || parsed <= 0 {
+				types.SendBadRequest(c, "expires_in must be a positive duration such as 30m or 6h")
+				return
+			}
+			ttl = parsed
+		}
+		if maxTTL := viper.GetDuration("datasets.max_url_ttl"); maxTTL > 0 && ttl > maxTTL {
+			types.SendBadRequest(c, fmt.Sprintf("expires_in may not exceed %s", maxTTL))
+			return
+		}
+
+		dataset, ok := loadDataset(c, deps)
+		if !ok {
+			return
+		}
+
+		expires, signature := deps.DatasetService.SignDownload(dataset.ID, ttl)
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, SignedURLResponse{
+			URL:       fmt.Sprintf("%s?expires=%d&signature=%s", downloadPath(dataset.ID), expires.Unix(), signature),
+			ExpiresAt: expires,
+		})
+	}
+}
+
+// SignedOrAuthenticated authorizes a download by its link signature when one is
+// present, and otherwise defers to auth. With no auth configured, unsigned
+// requests are let through like the rest of the API.
+func SignedOrAuthenticated(deps *types.Dependencies, auth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		signature := c.Query("signature")
+		if signature == "" {
+			if auth != nil {
+				auth(c)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+		if err == nil && deps.DatasetService != nil {
+			err = deps.DatasetService.VerifyDownload(c.Param("id"), expires, signature)
+		} else if err == nil {
+			err = datasets.ErrInvalidSignature
+		}
+		if err != nil {
+			message, code := "Invalid download link", types.CodeInvalidLinkSignature
+			if errors.Is(err, datasets.ErrLinkExpired) {
+				message, code = "Download link has expired", types.CodeLinkExpired
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    code,
+				Message: message,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// @Summary Download a dataset
+// @Description Streams the dataset zip archive. Supports Range and If-Range requests so interrupted downloads
+// @Description can resume. Authorize with a bearer token, or with the expires and signature parameters of a
+// @Description link from POST /api/v1/datasets/{id}/signed-url.
+// @Tags datasets
+// @Produce application/zip
+// @Param id path string true "Dataset ID"
+// @Param expires query int false "Signed link expiry (Unix seconds)"
+// @Param signature query string false "Signed link signature"
+// @Param Range header string false "Byte range, e.g. bytes=1048576-"
+// @Success 200 {file} binary "Dataset archive"
+// @Success 206 {file} binary "Requested byte range"
+// @Failure 403 {object} types.ErrorResponse "Invalid or expired link"
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 416 {string} string "Range not satisfiable"
+// @Router /api/v1/datasets/{id}/download [get]
+func DownloadDataset(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dataset, ok := loadDataset(c, deps)
+		if !ok {
+			return
+		}
+
+		file, err := os.Open(dataset.DatasetPath)
+		if err != nil {
+			types.SendError(c, http.StatusNotFound, types.CodeDatasetNotFound, "Dataset archive is no longer available")
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			types.SendInternalError(c, "Failed to read dataset archive")
+			return
+		}
+
+		// Archives never change once written, so the ID and size identify the bytes
+		c.Header("ETag", fmt.Sprintf(`"%s-%d"`, dataset.ID, info.Size()))
+		c.Header("Cache-Control", "private, max-age=0")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, dataset.ID))
+		c.Header("Content-Type", "application/zip")
+		http.ServeContent(c.Writer, c.Request, dataset.ID+".zip", info.ModTime(), file)
+	}
+}
+
+func loadDataset(c *gin.Context, deps *types.Dependencies) (*models.Dataset, bool) {
+	if deps.DatasetService == nil {
+		types.SendInternalError(c, "Dataset service not available")
+		return nil, false
+	}
+
+	dataset, err := deps.DatasetService.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, datasets.ErrDatasetNotFound) {
+		types.SendError(c, http.StatusNotFound, types.CodeDatasetNotFound, "Dataset not found")
+		return nil, false
+	}
+	if err != nil {
+		types.SendInternalError(c, fmt.Sprintf("Failed to load dataset: %v", err))
+		return nil, false
+	}
+	return dataset, true
+}
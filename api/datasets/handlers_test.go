@@ -0,0 +1,96 @@
+package datasets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/datasets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupDownloadRouter(t *testing.T) (*gin.Engine, datasets.Service, string) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Dataset{}))
+
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "ds-1.zip")
+	require.NoError(t, os.WriteFile(archive, []byte("0123456789"), 0o644))
+	require.NoError(t, db.Create(&models.Dataset{ID: "ds-1", Name: "ads", DatasetPath: archive, TotalSize: 10}).Error)
+
+	svc := datasets.NewService(datasets.NewRepository(db), nil, dir, []byte("secret"), datasets.CardOptions{})
+	deps := &types.Dependencies{DatasetService: svc}
+
+	denyAll := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+	router := gin.New()
+	RegisterDownloadRoutes(router.Group("/api/v1/datasets"), deps, denyAll)
+	return router, svc, "/api/v1/datasets/ds-1/download"
+}
+
+func TestDownloadDataset_SignedRange(t *testing.T) {
+	router, svc, path := setupDownloadRouter(t)
+	expires, sig := svc.SignDownload("ds-1", time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s?expires=%d&signature=%s", path, expires.Unix(), sig), nil)
+	req.Header.Set("Range", "bytes=4-")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "456789", w.Body.String())
+	assert.Equal(t, "bytes 4-9/10", w.Header().Get("Content-Range"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="ds-1.zip"`)
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+}
+
+func TestDownloadDataset_Authorization(t *testing.T) {
+	router, svc, path := setupDownloadRouter(t)
+	past, stale := svc.SignDownload("ds-1", -time.Minute)
+	expires, sig := svc.SignDownload("ds-1", time.Hour)
+
+	tests := []struct {
+		name  string
+		query string
+		code  int
+	}{
+		{name: "unsigned falls back to auth", query: "", code: http.StatusUnauthorized},
+		{name: "tampered signature", query: fmt.Sprintf("?expires=%d&signature=%s", expires.Unix(), stale), code: http.StatusForbidden},
+		{name: "expired link", query: fmt.Sprintf("?expires=%d&signature=%s", past.Unix(), stale), code: http.StatusForbidden},
+		{name: "missing expiry", query: "?signature=" + sig, code: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+tt.query, nil))
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestDownloadDataset_NotFound(t *testing.T) {
+	router, svc, _ := setupDownloadRouter(t)
+	expires, sig := svc.SignDownload("ds-missing", time.Hour)
+
+	w := httptest.NewRecorder()
+	url := fmt.Sprintf("/api/v1/datasets/ds-missing/download?expires=%d&signature=%s", expires.Unix(), sig)
+	router.ServeHTTP(w, httptest.NewRequestWithContext(context.Background(), http.MethodGet, url, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,39 @@
+package datasets
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/middleware"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/spf13/viper"
+)
+
+// RegisterRoutes registers dataset management routes
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// POST /api/v1/datasets - Build a dataset archive from approved clips
+	// GET /api/v1/datasets - List generated datasets
+	// GET /api/v1/datasets/:id - Dataset details
+	// Generation runs before the response is written, so it is exempt from the write timeout
+	router.POST("", middleware.NoWriteDeadline(), CreateDataset(deps))
+	router.GET("", ListDatasets(deps))
+	router.GET("/:id", GetDataset(deps))
+
+	// POST /api/v1/datasets/:id/signed-url - Expiring download link for sharing
+	router.POST("/:id/signed-url", CreateSignedURL(deps))
+
+	// POST /api/v1/datasets/:id/evaluate - Score model predictions against the labels
+	// GET /api/v1/datasets/:id/evaluations - Earlier runs, for comparison
+	// GET /api/v1/datasets/:id/evaluations/:evaluation_id - One run's confusion matrix
+	router.POST("/:id/evaluate", EvaluateDataset(deps))
+	router.GET("/:id/evaluations", ListEvaluations(deps))
+	router.GET("/:id/evaluations/:evaluation_id", GetEvaluation(deps))
+}
+
+// RegisterDownloadRoutes registers the dataset download. The router must not
+// require authentication itself: requests carrying a link signature are
+// authorized by it, and the rest are passed through auth (when configured).
+func RegisterDownloadRoutes(router *gin.RouterGroup, deps *types.Dependencies, auth gin.HandlerFunc) {
+	// GET/HEAD /api/v1/datasets/:id/download - Range-capable archive download
+	streamDeadline := middleware.StreamDeadline(viper.GetDuration("server.stream_write_timeout"))
+	router.GET("/:id/download", streamDeadline, SignedOrAuthenticated(deps, auth), DownloadDataset(deps))
+	router.HEAD("/:id/download", SignedOrAuthenticated(deps, auth), DownloadDataset(deps))
+}
@@ -0,0 +1,193 @@
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/cache"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+	"github.com/spf13/viper"
+)
+
+// Section names used in the response and the errors map
+const (
+	SectionTrending = "trending"
+	SectionRecent   = "recent"
+	SectionRandom   = "random"
+)
+
+// sectionTimeout bounds each upstream call so one slow section cannot hold up the others
+const sectionTimeout = 8 * time.Second
+
+// Get returns trending podcasts, recent episodes and random episodes in one response
+// @Summary      Discover home screen digest
+// @Description  Compose trending podcasts, recent episodes and random episodes in a single call. Each section is
+// @Description  fetched concurrently and cached independently. If a section fails, the others are still returned
+// @Description  and the failure is reported in the 'errors' map keyed by section name. Returns 502 only when every
+// @Description  section fails. Blocklisted podcasts and their episodes are left out of every section.
+// @Tags         discover
+// @Produce      json
+// @Param        limit query int false "Number of items per section (1-50)" default(10) minimum(1) maximum(50)
+// @Param        lang query string false "Language code for trending and random sections" default(en)
+// @Success      200 {object} types.DiscoverResponse "Sections that were fetched successfully"
+// @Failure      502 {object} types.DiscoverResponse "All sections failed"
+// @Router       /api/v1/discover [get]
+func Get(deps *types.Dependencies, sectionCache cache.Cache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+		if limit > 50 {
+			limit = 50
+		}
+		lang := c.DefaultQuery("lang", "en")
+
+		ctx := c.Request.Context()
+
+		// Sections are cached as Podcast Index returned them and filtered per
+		// request, so admins overriding the blocklist share the cache. Blocked
+		// categories still go upstream, since episodes carry none to match on.
+		block := types.Blocklist(c, deps)
+		notCategories := block.Categories(nil)
+		notCategoriesKey := strings.Join(notCategories, ",")
+
+		var (
+			trending []podcastindex.Podcast
+			recent   []podcastindex.Episode
+			random   []podcastindex.Episode
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+		)
+		sectionErrors := make(map[string]string)
+		var lastErr error
+
+		fail := func(section string, err error) {
+			log.Printf("[WARN] Discover section %s failed: %v", section, err)
+			mu.Lock()
+			sectionErrors[section] = err.Error()
+			lastErr = err
+			mu.Unlock()
+		}
+
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("discover:%s:%s:%d:%s", SectionTrending, lang, limit, notCategoriesKey)
+			ttl := time.Duration(viper.GetInt("cache.ttl_trending")) * time.Minute
+			if err := loadSection(ctx, sectionCache, key, ttl, &trending, func(ctx context.Context) (interface{}, error) {
+				resp, err := deps.PodcastClient.GetTrending(ctx, limit, 24, nil, notCategories, lang, false)
+				if err != nil {
+					return nil, err
+				}
+				return resp.Feeds, nil
+			}); err != nil {
+				fail(SectionTrending, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("discover:%s:%d", SectionRecent, limit)
+			ttl := time.Duration(viper.GetInt("cache.ttl_recent")) * time.Minute
+			if err := loadSection(ctx, sectionCache, key, ttl, &recent, func(ctx context.Context) (interface{}, error) {
+				resp, err := deps.PodcastClient.GetRecentEpisodes(ctx, limit)
+				if err != nil {
+					return nil, err
+				}
+				return resp.Items, nil
+			}); err != nil {
+				fail(SectionRecent, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("discover:%s:%s:%d:%s", SectionRandom, lang, limit, notCategoriesKey)
+			ttl := time.Duration(viper.GetInt("cache.ttl_random")) * time.Minute
+			if err := loadSection(ctx, sectionCache, key, ttl, &random, func(ctx context.Context) (interface{}, error) {
+				resp, err := deps.PodcastClient.GetRandomEpisodes(ctx, limit, lang, nil, notCategories)
+				if err != nil {
+					return nil, err
+				}
+				return resp.Items, nil
+			}); err != nil {
+				fail(SectionRandom, err)
+			}
+		}()
+		wg.Wait()
+
+		response := types.DiscoverResponse{
+			Trending: types.FromPodcastIndexList(block.FilterPodcasts(trending)),
+			Recent:   types.FromPodcastIndexEpisodeList(block.FilterEpisodes(recent)),
+			Random:   types.FromPodcastIndexEpisodeList(block.FilterEpisodes(random)),
+		}
+		if len(sectionErrors) > 0 {
+			response.Errors = sectionErrors
+		}
+
+		switch len(sectionErrors) {
+		case 0:
+			response.Status = types.StatusOK
+			response.Message = "Fetched discover sections"
+		case 3:
+			response.Status = types.StatusError
+			response.Message = "Failed to fetch any discover section"
+			// A spent Podcast Index budget is a 503 like on the other routes
+			status := http.StatusBadGateway
+			if known, _, _, ok := types.LookupServiceError(lastErr); ok {
+				status = known
+			}
+			c.JSON(status, response)
+			return
+		default:
+			response.Status = types.StatusOK
+			response.Message = "Some discover sections are unavailable"
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// loadSection decodes a cached section into dest, or calls fetch with its own
+// timeout and caches the result. A nil cache or non-positive TTL disables caching.
+func loadSection(ctx context.Context, sectionCache cache.Cache, key string, ttl time.Duration, dest interface{}, fetch func(context.Context) (interface{}, error)) error {
+	if sectionCache != nil {
+		if data, ok := sectionCache.Get(ctx, key); ok {
+			if err := json.Unmarshal(data, dest); err == nil {
+				return nil
+			}
+		}
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, sectionTimeout)
+	defer cancel()
+
+	value, err := fetch(fetchCtx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding section: %w", err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("decoding section: %w", err)
+	}
+
+	if sectionCache != nil && ttl > 0 {
+		if err := sectionCache.Set(ctx, key, data, ttl); err != nil {
+			log.Printf("[WARN] Failed to cache discover section %s: %v", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/blocklist"
+	"github.com/killallgit/player-api/internal/services/cache"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDiscoverClient struct {
+	trendingErr   error
+	recentErr     error
+	randomErr     error
+	trendingHits  int
+	notCategories []string
+}
+
+func (m *mockDiscoverClient) Search(ctx context.Context, query string, limit int, fullText bool, val string, apOnly bool, clean bool) (*podcastindex.SearchResponse, error) {
+	return &podcastindex.SearchResponse{}, nil
+}
+
+func (m *mockDiscoverClient) GetTrending(ctx context.Context, max, since int, categories, notCategories []string, lang string, fullText bool) (*podcastindex.SearchResponse, error) {
+	m.trendingHits++
+	m.notCategories = notCategories
+	if m.trendingErr != nil {
+		return nil, m.trendingErr
+	}
+	return &podcastindex.SearchResponse{Feeds: []podcastindex.Podcast{{ID: 1, Title: "Trending Show"}}}, nil
+}
+
+func (m *mockDiscoverClient) GetCategories(ctx context.Context) (*podcastindex.CategoriesResponse, error) {
+	return &podcastindex.CategoriesResponse{}, nil
+}
+
+func (m *mockDiscoverClient) GetEpisodesByPodcastID(ctx context.Context, podcastID int64, limit int) (*podcastindex.EpisodesResponse, error) {
+	return &podcastindex.EpisodesResponse{}, nil
+}
+
+func (m *mockDiscoverClient) GetEpisodesByFeedURL(ctx context.Context, feedURL string, limit int) (*podcastindex.EpisodesResponse, error) {
+	return &podcastindex.EpisodesResponse{}, nil
+}
+
+func (m *mockDiscoverClient) GetEpisodesByiTunesID(ctx context.Context, itunesID int64, limit int) (*podcastindex.EpisodesResponse, error) {
+	return &podcastindex.EpisodesResponse{}, nil
+}
+
+func (m *mockDiscoverClient) GetRecentEpisodes(ctx context.Context, limit int) (*podcastindex.EpisodesResponse, error) {
+	if m.recentErr != nil {
+		return nil, m.recentErr
+	}
+	return &podcastindex.EpisodesResponse{Items: []podcastindex.Episode{{ID: 10, Title: "Recent Episode"}, {ID: 11, FeedId: 1, Title: "Blocked Episode"}}}, nil
+}
+
+func (m *mockDiscoverClient) GetRandomEpisodes(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+	if m.randomErr != nil {
+		return nil, m.randomErr
+	}
+	return &podcastindex.EpisodesResponse{Items: []podcastindex.Episode{{ID: 20, Title: "Random Episode"}}}, nil
+}
+
+func (m *mockDiscoverClient) GetRecentFeeds(ctx context.Context, limit int) (*podcastindex.RecentFeedsResponse, error) {
+	return &podcastindex.RecentFeedsResponse{}, nil
+}
+
+// stubBlocklist blocks one feed and one category
+type stubBlocklist struct {
+	blocklist.Service
+	feedID int64
+}
+
+func (s *stubBlocklist) Match(subject blocklist.Subject) *models.BlocklistEntry {
+	if subject.FeedID == s.feedID {
+		return &models.BlocklistEntry{Kind: "feed"}
+	}
+	return nil
+}
+
+func (s *stubBlocklist) BlockedCategories() []string {
+	return []string{"Politics"}
+}
+
+func performDiscover(t *testing.T, client *mockDiscoverClient, sectionCache cache.Cache) (int, types.DiscoverResponse) {
+	return performDiscoverWith(t, &types.Dependencies{PodcastClient: client}, sectionCache)
+}
+
+func performDiscoverWith(t *testing.T, deps *types.Dependencies, sectionCache cache.Cache) (int, types.DiscoverResponse) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router.Group("/api/v1/discover"), deps, sectionCache)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/discover", nil)
+	router.ServeHTTP(w, req)
+
+	var resp types.DiscoverResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp
+}
+
+func TestGet_AllSections(t *testing.T) {
+	code, resp := performDiscover(t, &mockDiscoverClient{}, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Errors)
+	require.Len(t, resp.Trending, 1)
+	assert.Equal(t, "Trending Show", resp.Trending[0].Title)
+	require.Len(t, resp.Recent, 2)
+	require.Len(t, resp.Random, 1)
+}
+
+func TestGet_PartialFailure(t *testing.T) {
+	code, resp := performDiscover(t, &mockDiscoverClient{recentErr: errors.New("upstream down")}, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, resp.Trending, 1)
+	assert.Empty(t, resp.Recent)
+	assert.Len(t, resp.Random, 1)
+	assert.Equal(t, "upstream down", resp.Errors[SectionRecent])
+}
+
+func TestGet_AllSectionsFail(t *testing.T) {
+	boom := errors.New("boom")
+	code, resp := performDiscover(t, &mockDiscoverClient{trendingErr: boom, recentErr: boom, randomErr: boom}, nil)
+
+	assert.Equal(t, http.StatusBadGateway, code)
+	assert.Equal(t, types.StatusError, resp.Status)
+	assert.Len(t, resp.Errors, 3)
+}
+
+func TestGet_SectionsCachedIndependently(t *testing.T) {
+	viper.Set("cache.ttl_trending", 60)
+	defer viper.Set("cache.ttl_trending", nil)
+
+	memCache := cache.NewMemoryCache(1)
+	defer memCache.Stop()
+
+	client := &mockDiscoverClient{}
+	performDiscover(t, client, memCache)
+	assert.Equal(t, 1, client.trendingHits)
+
+	// A later failure in trending is masked by the cached section
+	client.trendingErr = errors.New("rate limited")
+	code, resp := performDiscover(t, client, memCache)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, client.trendingHits)
+	assert.Len(t, resp.Trending, 1)
+	assert.Empty(t, resp.Errors)
+}
+
+func TestGet_AppliesBlocklist(t *testing.T) {
+	viper.Set("cache.ttl_trending", 60)
+	defer viper.Set("cache.ttl_trending", nil)
+
+	memCache := cache.NewMemoryCache(1)
+	defer memCache.Stop()
+
+	// Prime the cache before anything is blocked
+	client := &mockDiscoverClient{}
+	performDiscover(t, client, memCache)
+
+	deps := &types.Dependencies{PodcastClient: client, BlocklistService: &stubBlocklist{feedID: 1}}
+	code, resp := performDiscoverWith(t, deps, memCache)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Trending)
+	require.Len(t, resp.Recent, 1)
+	assert.Equal(t, "Recent Episode", resp.Recent[0].Title)
+	assert.Len(t, resp.Random, 1)
+	// Blocked categories go upstream, under their own cache key
+	assert.Equal(t, 2, client.trendingHits)
+	assert.Equal(t, []string{"Politics"}, client.notCategories)
+}
@@ -0,0 +1,14 @@
+package discover
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/cache"
+)
+
+// RegisterRoutes registers the discover route. sectionCache may be nil, in
+// which case every section is fetched on each request.
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies, sectionCache cache.Cache) {
+	// GET /api/v1/discover
+	router.GET("", Get(deps, sectionCache))
+}
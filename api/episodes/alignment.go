@@ -0,0 +1,173 @@
+package episodes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/waveforms"
+)
+
+// AlignedSegment is a transcript segment with the waveform peaks it spans
+type AlignedSegment struct {
+	Start     float64 `json:"start" example:"12.5"`
+	End       float64 `json:"end" example:"15.2"`
+	Text      string  `json:"text" example:"Welcome back to the show"`
+	StartPeak int     `json:"start_peak" example:"125"` // First peak index covered (inclusive)
+	EndPeak   int     `json:"end_peak" example:"152"`   // One past the last peak covered
+}
+
+// AlignmentResponse pairs transcript segments with waveform peak indices for a time range
+type AlignmentResponse struct {
+	EpisodeID  int64            `json:"episode_id" example:"12345"`
+	Start      float64          `json:"start" example:"0"`
+	End        float64          `json:"end" example:"60"`
+	Duration   float64          `json:"duration" example:"1800.5"`
+	Resolution int              `json:"resolution" example:"1000"`
+	StartPeak  int              `json:"start_peak" example:"0"`
+	EndPeak    int              `json:"end_peak" example:"34"`
+	Timed      bool             `json:"timed" example:"true"` // False when the transcript has no timing and is returned as one segment
+	Segments   []AlignedSegment `json:"segments"`
+}
+
+// @Summary Align transcript with waveform
+// @Description Returns the transcript segments overlapping a time range together with the waveform peak
+// @Description indices each one covers, so a player can highlight text as the playhead crosses the waveform.
+// @Description Peak ranges are half-open: start_peak is inclusive and end_peak exclusive. Transcripts without
+// @Description timing (e.g. plain text) come back as a single segment spanning the episode with timed=false.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Param start query number false "Range start in seconds" default(0) minimum(0)
+// @Param end query number false "Range end in seconds (defaults to the episode duration)"
+// @Success 200 {object} AlignmentResponse
+// @Failure 400 {object} types.ErrorResponse "Invalid episode ID or time range"
+// @Failure 404 {object} types.ErrorResponse "Transcript or waveform not available"
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/episodes/{id}/alignment [get]
+func GetAlignment(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID <= 0 {
+			types.SendBadRequest(c, "Invalid episode ID")
+			return
+		}
+
+		start, err := strconv.ParseFloat(c.DefaultQuery("start", "0"), 64)
+		if err != nil || start < 0 {
+			types.SendBadRequest(c, "start must be a non-negative number of seconds")
+			return
+		}
+		end := -1.0
+		if endStr := c.Query("end"); endStr != "" {
+			end, err = strconv.ParseFloat(endStr, 64)
+			if err != nil || end <= start {
+				types.SendBadRequest(c, "end must be a number of seconds greater than start")
+				return
+			}
+		}
+
+		if deps.TranscriptionService == nil || deps.WaveformService == nil {
+			types.SendInternalError(c, "Transcription or waveform service not available")
+			return
+		}
+
+		ctx := c.Request.Context()
+		transcript, err := deps.TranscriptionService.GetTranscription(ctx, episodeID)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to get transcription: %v", err))
+			return
+		}
+		if transcript == nil {
+			types.SendError(c, http.StatusNotFound, types.CodeTranscriptNotFound, "Transcription not found for this episode")
+			return
+		}
+
+		waveform, err := deps.WaveformService.GetWaveform(ctx, episodeID)
+		if err != nil {
+			if errors.Is(err, waveforms.ErrWaveformNotFound) {
+				types.SendError(c, http.StatusNotFound, types.CodeWaveformNotFound, "Waveform not found for this episode")
+				return
+			}
+			types.SendInternalError(c, fmt.Sprintf("Failed to get waveform: %v", err))
+			return
+		}
+
+		segments, err := transcript.Segments()
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to decode transcript segments: %v", err))
+			return
+		}
+
+		duration := waveform.Duration
+		if duration <= 0 {
+			duration = transcript.Duration
+		}
+		if end < 0 {
+			end = duration
+		}
+
+		timed := len(segments) > 0
+		if !timed {
+			segments = []models.TranscriptSegment{{Start: 0, End: duration, Text: transcript.Text}}
+		}
+
+		c.JSON(http.StatusOK, alignSegments(episodeID, segments, duration, waveform.Resolution, start, end, timed))
+	}
+}
+
+// alignSegments keeps the segments overlapping [start, end) and maps each onto
+// the waveform's peak indices
+func alignSegments(episodeID int64, segments []models.TranscriptSegment, duration float64, resolution int, start, end float64, timed bool) AlignmentResponse {
+	response := AlignmentResponse{
+		EpisodeID:  episodeID,
+		Start:      start,
+		End:        end,
+		Duration:   duration,
+		Resolution: resolution,
+		Timed:      timed,
+		Segments:   []AlignedSegment{},
+	}
+	response.StartPeak, response.EndPeak = peakRange(start, end, duration, resolution)
+
+	for _, seg := range segments {
+		if seg.End <= start || seg.Start >= end {
+			continue
+		}
+		aligned := AlignedSegment{Start: seg.Start, End: seg.End, Text: seg.Text}
+		aligned.StartPeak, aligned.EndPeak = peakRange(seg.Start, seg.End, duration, resolution)
+		response.Segments = append(response.Segments, aligned)
+	}
+
+	return response
+}
+
+// peakRange converts a time range to a half-open range of peak indices. Every
+// non-empty range covers at least one peak so short words stay highlightable.
+func peakRange(start, end, duration float64, resolution int) (int, int) {
+	if duration <= 0 || resolution <= 0 {
+		return 0, 0
+	}
+	scale := float64(resolution) / duration
+	first := clampPeak(int(math.Floor(start*scale)), resolution)
+	last := clampPeak(int(math.Ceil(end*scale)), resolution)
+	if last <= first && first < resolution {
+		last = first + 1
+	}
+	return first, last
+}
+
+func clampPeak(index, resolution int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > resolution {
+		return resolution
+	}
+	return index
+}
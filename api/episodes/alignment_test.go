@@ -0,0 +1,148 @@
+package episodes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/transcription"
+	"github.com/killallgit/player-api/internal/services/waveforms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestPeakRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end float64
+		wantFirst  int
+		wantLast   int
+	}{
+		{"whole episode", 0, 100, 0, 1000},
+		{"partial peaks round outward", 1.25, 2.01, 12, 21},
+		{"tiny span still covers a peak", 5.0, 5.0001, 50, 51},
+		{"clamped past the end", 99.95, 120, 999, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := peakRange(tt.start, tt.end, 100, 1000)
+			assert.Equal(t, tt.wantFirst, first)
+			assert.Equal(t, tt.wantLast, last)
+		})
+	}
+
+	first, last := peakRange(0, 10, 0, 1000)
+	assert.Zero(t, first, "unknown duration maps to no peaks")
+	assert.Zero(t, last)
+}
+
+func setupAlignmentDeps(t *testing.T, segments []models.TranscriptSegment) *types.Dependencies {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Transcription{}, &models.Waveform{}, &models.WaveformCheckpoint{}))
+
+	ctx := context.Background()
+	transcriptionService := transcription.NewService(transcription.NewRepository(db))
+	waveformService := waveforms.NewService(waveforms.NewRepository(db))
+
+	transcript := &models.Transcription{PodcastIndexEpisodeID: 42, Text: "hello world again", Duration: 30}
+	if segments != nil {
+		require.NoError(t, transcript.SetSegments(segments))
+	}
+	require.NoError(t, transcriptionService.SaveTranscription(ctx, transcript))
+
+	waveform := &models.Waveform{PodcastIndexEpisodeID: 42, Duration: 30}
+	require.NoError(t, waveform.SetPeaks(make([]float32, 300)))
+	require.NoError(t, waveformService.SaveWaveform(ctx, waveform))
+
+	return &types.Dependencies{
+		TranscriptionService: transcriptionService,
+		WaveformService:      waveformService,
+	}
+}
+
+func getAlignment(t *testing.T, deps *types.Dependencies, url string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.GET("/api/v1/episodes/:id/alignment", GetAlignment(deps))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", url, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAlignment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deps := setupAlignmentDeps(t, []models.TranscriptSegment{
+		{Start: 0, End: 4.5, Text: "hello"},
+		{Start: 4.5, End: 10, Text: "world"},
+		{Start: 10, End: 30, Text: "again"},
+	})
+
+	t.Run("returns overlapping segments with peak indices", func(t *testing.T) {
+		w := getAlignment(t, deps, "/api/v1/episodes/42/alignment?start=5&end=12")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response AlignmentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Timed)
+		assert.Equal(t, 300, response.Resolution)
+		assert.Equal(t, 50, response.StartPeak)
+		assert.Equal(t, 120, response.EndPeak)
+
+		require.Len(t, response.Segments, 2)
+		assert.Equal(t, "world", response.Segments[0].Text)
+		assert.Equal(t, 45, response.Segments[0].StartPeak)
+		assert.Equal(t, 100, response.Segments[0].EndPeak)
+		assert.Equal(t, "again", response.Segments[1].Text)
+		assert.Equal(t, 300, response.Segments[1].EndPeak)
+	})
+
+	t.Run("range defaults to the whole episode", func(t *testing.T) {
+		w := getAlignment(t, deps, "/api/v1/episodes/42/alignment")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response AlignmentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 30.0, response.End)
+		assert.Len(t, response.Segments, 3)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		w := getAlignment(t, deps, "/api/v1/episodes/42/alignment?start=10&end=5")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing transcript", func(t *testing.T) {
+		w := getAlignment(t, deps, "/api/v1/episodes/7/alignment")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetAlignment_UntimedTranscript(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deps := setupAlignmentDeps(t, nil)
+	w := getAlignment(t, deps, "/api/v1/episodes/42/alignment?start=3&end=6")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response AlignmentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Timed)
+	require.Len(t, response.Segments, 1)
+	assert.Equal(t, "hello world again", response.Segments[0].Text)
+	assert.Equal(t, 0, response.Segments[0].StartPeak)
+	assert.Equal(t, 300, response.Segments[0].EndPeak)
+}
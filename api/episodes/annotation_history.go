@@ -0,0 +1,110 @@
+package episodes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// AnnotationHistoryEntry is one recorded change to an annotation
+type AnnotationHistoryEntry struct {
+	Action    string                     `json:"action" enums:"create,update,approve,reject,delete,bulk_delete,auto_label,auto_approve" example:"update"`
+	UserID    string                     `json:"user_id,omitempty" example:"6f1c2a7e-0b5d-4e8a-9a51-3d2f0c9b7e14"`
+	Before    *models.AnnotationSnapshot `json:"before,omitempty"`
+	After     *models.AnnotationSnapshot `json:"after,omitempty"`
+	CreatedAt string                     `json:"created_at" example:"2025-10-02T13:00:00Z"`
+}
+
+// AnnotationHistoryResponse lists the changes to an annotation, oldest first
+type AnnotationHistoryResponse struct {
+	UUID      string                   `json:"uuid" example:"a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"`
+	EpisodeID int64                    `json:"episode_id" example:"12345"`
+	History   []AnnotationHistoryEntry `json:"history"`
+}
+
+// @Summary Get annotation history
+// @Description Returns every recorded create, update, approve, reject, delete and auto-label change to
+// @Description an annotation (clip), oldest first, with the acting user and the label state before and
+// @Description after each change. History remains available after the annotation is deleted.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Param uuid path string true "Annotation (clip) UUID"
+// @Success 200 {object} AnnotationHistoryResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse "No history for this annotation on this episode"
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/episodes/{id}/annotations/{uuid}/history [get]
+func GetAnnotationHistory(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			types.SendBadRequest(c, "Invalid episode ID")
+			return
+		}
+
+		uuid := c.Param("uuid")
+		if uuid == "" {
+			types.SendBadRequest(c, "UUID is required")
+			return
+		}
+
+		if deps.ClipService == nil {
+			types.SendInternalError(c, "Clip service not available")
+			return
+		}
+
+		history, err := deps.ClipService.GetAnnotationHistory(c.Request.Context(), uuid)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to get annotation history: %v", err))
+			return
+		}
+
+		// The clip may be gone, so ownership is checked against the audit rows themselves
+		if len(history) == 0 || history[0].PodcastIndexEpisodeID != episodeID {
+			types.SendNotFound(c, "Annotation not found for this episode")
+			return
+		}
+
+		response := AnnotationHistoryResponse{
+			UUID:      uuid,
+			EpisodeID: episodeID,
+			History:   make([]AnnotationHistoryEntry, len(history)),
+		}
+		for i, audit := range history {
+			entry := AnnotationHistoryEntry{
+				Action:    audit.Action,
+				UserID:    audit.UserID,
+				CreatedAt: audit.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			}
+			if entry.Before, err = decodeSnapshot(audit.Before); err != nil {
+				types.SendInternalError(c, fmt.Sprintf("Failed to decode annotation history: %v", err))
+				return
+			}
+			if entry.After, err = decodeSnapshot(audit.After); err != nil {
+				types.SendInternalError(c, fmt.Sprintf("Failed to decode annotation history: %v", err))
+				return
+			}
+			response.History[i] = entry
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// decodeSnapshot parses a stored snapshot; empty data means there was no state
+func decodeSnapshot(data []byte) (*models.AnnotationSnapshot, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var snapshot models.AnnotationSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,71 @@
+package episodes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAnnotationHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupTestDB(t)
+	deps := &types.Dependencies{ClipService: &testClipService{db: db}}
+
+	clip := &models.Clip{UUID: "clip-uuid-1", PodcastIndexEpisodeID: 12345, Label: "advertisement"}
+	created, err := models.NewAnnotationAudit(clip, "user-1", models.AnnotationActionCreate, nil, clip.Snapshot())
+	require.NoError(t, err)
+	require.NoError(t, db.Create(created).Error)
+
+	before := clip.Snapshot()
+	clip.Label = "music"
+	updated, err := models.NewAnnotationAudit(clip, "user-2", models.AnnotationActionUpdate, before, clip.Snapshot())
+	require.NoError(t, err)
+	require.NoError(t, db.Create(updated).Error)
+
+	router := gin.New()
+	router.GET("/api/v1/episodes/:id/annotations/:uuid/history", GetAnnotationHistory(deps))
+
+	t.Run("returns changes oldest first", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/episodes/12345/annotations/clip-uuid-1/history", nil)
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response AnnotationHistoryResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.History, 2)
+
+		assert.Equal(t, models.AnnotationActionCreate, response.History[0].Action)
+		assert.Nil(t, response.History[0].Before)
+		assert.Equal(t, "advertisement", response.History[0].After.Label)
+
+		assert.Equal(t, "user-2", response.History[1].UserID)
+		assert.Equal(t, "advertisement", response.History[1].Before.Label)
+		assert.Equal(t, "music", response.History[1].After.Label)
+	})
+
+	t.Run("other episode is not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/episodes/999/annotations/clip-uuid-1/history", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unknown annotation is not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/episodes/12345/annotations/missing/history", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,195 @@
+package episodes
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+)
+
+// CreateClipFromTranscriptRequest selects transcript segments to clip, either
+// by index or by a span of their text
+type CreateClipFromTranscriptRequest struct {
+	Segments    []int  `json:"segments,omitempty" example:"3,4,5"`                                        // Consecutive 0-based segment indices in transcript order
+	Text        string `json:"text,omitempty" example:"brought to you by"`                                // Spoken text to find; the clip covers the segments it spans
+	Label       string `json:"label" binding:"required,min=1" example:"advertisement"`                    // ML training label
+	CallbackURL string `json:"callback_url,omitempty" example:"https://pipeline.example.com/hooks/clips"` // Queue extraction now and notify this URL when done
+}
+
+// @Summary Create clip from transcript selection
+// @Description Creates a clip covering whole transcript segments, selected either by their indices (consecutive,
+// @Description 0-based, in the order GET /episodes/{id}/alignment lists them for the whole episode) or by a span of
+// @Description spoken text. Text is matched word by word, ignoring case and punctuation, and must occur exactly once.
+// @Description The clip's time range runs from the first selected segment's start to the last one's end, and their
+// @Description text is stored on the clip as transcript_text for text-audio paired training data. Like other manual
+// @Description clips it is approved for export.
+// @Tags episodes
+// @Accept json
+// @Produce json
+// @Param id path int true "Episode ID"
+// @Param request body CreateClipFromTranscriptRequest true "Segment indices or text span, and the label"
+// @Success 202 {object} EpisodeClipResponse "Clip created (approved=true, status=pending)"
+// @Failure 400 {object} types.ErrorResponse "Invalid selection, or text not found or ambiguous"
+// @Failure 404 {object} types.ErrorResponse "Transcription not found for this episode"
+// @Failure 409 {object} types.ErrorResponse "Transcript has no timing, or label quota reached"
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/episodes/{id}/clips/from-transcript [post]
+func CreateClipFromTranscript(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID <= 0 {
+			types.SendBadRequest(c, "Invalid episode ID")
+			return
+		}
+
+		var req CreateClipFromTranscriptRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			types.SendBadRequest(c, err.Error())
+			return
+		}
+		if (len(req.Segments) == 0) == (strings.TrimSpace(req.Text) == "") {
+			types.SendBadRequest(c, "Provide either segments or text")
+			return
+		}
+		if !types.ValidateCallbackURL(c, deps, req.CallbackURL) {
+			return
+		}
+
+		if deps.TranscriptionService == nil {
+			types.SendInternalError(c, "Transcription service not available")
+			return
+		}
+		transcript, err := deps.TranscriptionService.GetTranscription(c.Request.Context(), episodeID)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to get transcription: %v", err))
+			return
+		}
+		if transcript == nil {
+			types.SendError(c, http.StatusNotFound, types.CodeTranscriptNotFound, "Transcription not found for this episode")
+			return
+		}
+		segments, err := transcript.Segments()
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to decode transcript segments: %v", err))
+			return
+		}
+		if len(segments) == 0 {
+			types.SendConflict(c, "Transcript has no timing, so segments can't be resolved to a time range")
+			return
+		}
+
+		var selected []models.TranscriptSegment
+		if len(req.Segments) > 0 {
+			selected, err = segmentsByIndex(segments, req.Segments)
+		} else {
+			selected, err = segmentsByText(segments, req.Text)
+		}
+		if err != nil {
+			types.SendBadRequest(c, err.Error())
+			return
+		}
+
+		clip, err := deps.ClipService.CreateClip(c.Request.Context(), clips.CreateClipParams{
+			PodcastIndexEpisodeID: episodeID,
+			OriginalStartTime:     selected[0].Start,
+			OriginalEndTime:       selected[len(selected)-1].End,
+			Label:                 req.Label,
+			Approved:              true, // Manual clips are pre-approved
+			CreatedBy:             c.GetString("user_id"),
+			TranscriptText:        joinSegmentText(selected),
+		})
+		if err != nil {
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to create clip: %v", err))
+			return
+		}
+
+		types.ClipTranscripts(c, deps, clip)
+		response := toClipResponse(clip, types.ClipSampleRates(c, deps, clip))
+		response.JobID = types.QueueClipExtraction(c, deps, clip.UUID, req.CallbackURL)
+		c.JSON(http.StatusAccepted, response)
+	}
+}
+
+// segmentsByIndex returns the segments at indices, which must be consecutive
+// and ascending, and must span a non-empty time range
+func segmentsByIndex(segments []models.TranscriptSegment, indices []int) ([]models.TranscriptSegment, error) {
+	for i, index := range indices {
+		if index < 0 || index >= len(segments) {
+			return nil, fmt.Errorf("segment %d out of range (transcript has %d segments)", index, len(segments))
+		}
+		if i > 0 && index != indices[i-1]+1 {
+			return nil, fmt.Errorf("segments must be consecutive and ascending")
+		}
+	}
+	selected := segments[indices[0] : indices[len(indices)-1]+1]
+	if selected[len(selected)-1].End <= selected[0].Start {
+		return nil, fmt.Errorf("selected segments have no duration")
+	}
+	return selected, nil
+}
+
+// segmentsByText returns the segments spanned by the only occurrence of text.
+// Words are compared case-insensitively with punctuation ignored, and may run
+// across segment boundaries.
+func segmentsByText(segments []models.TranscriptSegment, text string) ([]models.TranscriptSegment, error) {
+	query := transcriptWords(text)
+	if len(query) == 0 {
+		return nil, fmt.Errorf("text contains no words")
+	}
+
+	var words []string
+	var owners []int // Segment index of each word
+	for i, segment := range segments {
+		for _, word := range transcriptWords(segment.Text) {
+			words = append(words, word)
+			owners = append(owners, i)
+		}
+	}
+
+	match, matches := -1, 0
+	for start := 0; start+len(query) <= len(words); start++ {
+		if slices.Equal(words[start:start+len(query)], query) {
+			if matches == 0 {
+				match = start
+			}
+			matches++
+		}
+	}
+	switch {
+	case matches == 0:
+		return nil, fmt.Errorf("text not found in transcript")
+	case matches > 1:
+		return nil, fmt.Errorf("text occurs %d times in transcript; select segments by index instead", matches)
+	}
+
+	first, last := owners[match], owners[match+len(query)-1]
+	if segments[last].End <= segments[first].Start {
+		return nil, fmt.Errorf("matched segments have no duration")
+	}
+	return segments[first : last+1], nil
+}
+
+// transcriptWords lowercases text and splits it into words, dropping punctuation
+func transcriptWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+// joinSegmentText joins the trimmed text of segments with single spaces
+func joinSegmentText(segments []models.TranscriptSegment) string {
+	parts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if text := strings.TrimSpace(segment.Text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
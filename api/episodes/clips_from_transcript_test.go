@@ -0,0 +1,45 @@
+package episodes
+
+import (
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscriptSelection(t *testing.T) {
+	segments := []models.TranscriptSegment{
+		{Start: 0, End: 4, Text: " Welcome back to the show."},
+		{Start: 4, End: 9, Text: " This episode is brought"},
+		{Start: 9, End: 12, Text: " to you by Acme, the show's sponsor."},
+		{Start: 12, End: 15, Text: " Back to the show."},
+	}
+
+	t.Run("by index", func(t *testing.T) {
+		selected, err := segmentsByIndex(segments, []int{1, 2})
+		require.NoError(t, err)
+		assert.Equal(t, segments[1:3], selected)
+		assert.Equal(t, "This episode is brought to you by Acme, the show's sponsor.", joinSegmentText(selected))
+
+		_, err = segmentsByIndex(segments, []int{1, 3})
+		assert.EqualError(t, err, "segments must be consecutive and ascending")
+		_, err = segmentsByIndex(segments, []int{4})
+		assert.EqualError(t, err, "segment 4 out of range (transcript has 4 segments)")
+	})
+
+	t.Run("by text across segments", func(t *testing.T) {
+		selected, err := segmentsByText(segments, "Brought to YOU by acme")
+		require.NoError(t, err)
+		assert.Equal(t, segments[1:3], selected)
+	})
+
+	t.Run("text errors", func(t *testing.T) {
+		_, err := segmentsByText(segments, "the show")
+		assert.EqualError(t, err, "text occurs 2 times in transcript; select segments by index instead")
+		_, err = segmentsByText(segments, "not said")
+		assert.EqualError(t, err, "text not found in transcript")
+		_, err = segmentsByText(segments, "...")
+		assert.EqualError(t, err, "text contains no words")
+	})
+}
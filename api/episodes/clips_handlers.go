@@ -1,6 +1,7 @@
 package episodes
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -13,22 +14,36 @@ import (
 
 // EpisodeClipResponse represents a clip in API responses
 type EpisodeClipResponse struct {
-	UUID              string   `json:"uuid" example:"a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"`
-	Label             string   `json:"label" example:"advertisement"`
-	Status            string   `json:"status" enums:"detected,queued,processing,ready,failed" example:"queued"`
-	Approved          bool     `json:"approved" example:"true"`
-	Extracted         bool     `json:"extracted" example:"false"`
-	ClipFilename      *string  `json:"filename,omitempty" example:"clip_a1b2c3d4.wav"`
-	ClipDuration      *float64 `json:"duration,omitempty" example:"15.0"`
-	ClipSizeBytes     *int64   `json:"size_bytes,omitempty" example:"480332"`
-	OriginalStartTime float64  `json:"original_start_time" example:"30.0"`
-	OriginalEndTime   float64  `json:"original_end_time" example:"45.0"`
-	AutoLabeled       bool     `json:"auto_labeled" example:"false"`
-	LabelConfidence   *float64 `json:"label_confidence,omitempty" example:"0.95"`
-	LabelMethod       string   `json:"label_method" enums:"manual,peak_detection" example:"manual"`
-	ErrorMessage      string   `json:"error_message,omitempty" example:""`
-	CreatedAt         string   `json:"created_at" example:"2025-10-02T13:00:00Z"`
-	UpdatedAt         string   `json:"updated_at" example:"2025-10-02T13:00:00Z"`
+	UUID              string          `json:"uuid" example:"a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"`
+	Label             string          `json:"label" example:"advertisement"`
+	Status            string          `json:"status" enums:"detected,queued,processing,ready,failed" example:"queued"`
+	Approved          bool            `json:"approved" example:"true"`
+	AutoApproved      bool            `json:"auto_approved" example:"false"`               // Approved by analysis, awaiting a reviewer's confirmation
+	ApprovalThreshold *float64        `json:"approval_threshold,omitempty" example:"0.97"` // Threshold the score met when auto-approved
+	Rejected          bool            `json:"rejected" example:"false"`
+	RejectionReason   string          `json:"rejection_reason,omitempty" enums:"false_positive,bad_boundaries,poor_audio,duplicate,other" example:""`
+	Extracted         bool            `json:"extracted" example:"false"`
+	ClipFilename      *string         `json:"filename,omitempty" example:"clip_a1b2c3d4.wav"`
+	ClipDuration      *float64        `json:"duration,omitempty" example:"15.0"`
+	ClipSizeBytes     *int64          `json:"size_bytes,omitempty" example:"480332"`
+	OriginalStartTime float64         `json:"original_start_time" example:"30.0"`
+	OriginalEndTime   float64         `json:"original_end_time" example:"45.0"`
+	RangeClamped      bool            `json:"range_clamped,omitempty" example:"false"` // The requested end ran past the episode and was moved to its end
+	AutoLabeled       bool            `json:"auto_labeled" example:"false"`
+	LabelConfidence   *float64        `json:"label_confidence,omitempty" example:"0.95"`
+	LabelMethod       string          `json:"label_method" enums:"manual,peak_detection,model" example:"manual"`
+	ModelName         string          `json:"model_name,omitempty" example:"volume-spike"` // Registry model whose detection created the clip
+	ModelVersion      string          `json:"model_version,omitempty" example:"1"`
+	ErrorMessage      string          `json:"error_message,omitempty" example:""`
+	JobError          *types.JobError `json:"job_error,omitempty"` // Why extraction failed, when it did
+	CreatedAt         string          `json:"created_at" example:"2025-10-02T13:00:00Z"`
+	UpdatedAt         string          `json:"updated_at" example:"2025-10-02T13:00:00Z"`
+	TranscriptText    *string         `json:"transcript_text,omitempty" example:"This episode is brought to you by"`
+	JobID             uint            `json:"job_id,omitempty" example:"42"` // Extraction job queued for callback_url
+	// Time range in sample frames of the cached original audio; omitted until the episode is cached
+	SampleRate  *int   `json:"sample_rate,omitempty" example:"44100"`
+	StartSample *int64 `json:"start_sample,omitempty" example:"1323000"`
+	EndSample   *int64 `json:"end_sample,omitempty" example:"1984500"` // Exclusive
 }
 
 // CreateClipRequest represents the request to create a clip for an episode
@@ -36,6 +51,7 @@ type CreateClipRequest struct {
 	OriginalStartTime float64 `json:"start_time" binding:"min=0" example:"30"`
 	OriginalEndTime   float64 `json:"end_time" binding:"required,gt=0" example:"45"`
 	Label             string  `json:"label" binding:"required,min=1" example:"advertisement"`
+	CallbackURL       string  `json:"callback_url,omitempty" example:"https://pipeline.example.com/hooks/clips"` // Queue extraction now and notify this URL when done
 }
 
 // UpdateLabelRequest represents the request to update a clip's label
@@ -50,8 +66,9 @@ type UpdateLabelRequest struct {
 // @Produce json
 // @Param id path int true "Episode ID"
 // @Param request body CreateClipRequest true "Clip creation parameters"
-// @Success 202 {object} EpisodeClipResponse "Clip created successfully (approved=true, status=pending)"
-// @Failure 400 {object} types.ErrorResponse
+// @Success 202 {object} EpisodeClipResponse "Clip created successfully (approved=true, status=pending); range_clamped is set when end_time ran past the episode"
+// @Failure 400 {object} types.ErrorResponse "Invalid time range, or start_time past the end of the episode (CLIP_INVALID_RANGE)"
+// @Failure 409 {object} types.ErrorResponse "Label quota reached"
 // @Failure 500 {object} types.ErrorResponse
 // @Router /api/v1/episodes/{id}/clips [post]
 func CreateClipForEpisode(deps *types.Dependencies) gin.HandlerFunc {
@@ -72,7 +89,10 @@ func CreateClipForEpisode(deps *types.Dependencies) gin.HandlerFunc {
 
 		// Validate time range
 		if req.OriginalEndTime <= req.OriginalStartTime {
-			types.SendBadRequest(c, "end_time must be greater than start_time")
+			types.SendError(c, http.StatusBadRequest, types.CodeClipInvalidRange, "end_time must be greater than start_time")
+			return
+		}
+		if !types.ValidateCallbackURL(c, deps, req.CallbackURL) {
 			return
 		}
 
@@ -83,14 +103,18 @@ func CreateClipForEpisode(deps *types.Dependencies) gin.HandlerFunc {
 			OriginalEndTime:       req.OriginalEndTime,
 			Label:                 req.Label,
 			Approved:              true, // Manual clips are pre-approved
+			CreatedBy:             c.GetString("user_id"),
 		})
 
 		if err != nil {
-			types.SendInternalError(c, fmt.Sprintf("Failed to create clip: %v", err))
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to create clip: %v", err))
 			return
 		}
 
-		c.JSON(http.StatusAccepted, toClipResponse(clip))
+		types.ClipTranscripts(c, deps, clip)
+		response := toClipResponse(clip, types.ClipSampleRates(c, deps, clip))
+		response.JobID = types.QueueClipExtraction(c, deps, clip.UUID, req.CallbackURL)
+		c.JSON(http.StatusAccepted, response)
 	}
 }
 
@@ -101,6 +125,7 @@ func CreateClipForEpisode(deps *types.Dependencies) gin.HandlerFunc {
 // @Param id path int true "Episode ID"
 // @Param status query string false "Filter by status" Enums(queued, processing, ready, failed, detected)
 // @Param approved query boolean false "Filter by approval status (true/false)"
+// @Param rejected query boolean false "Filter by rejection status (true/false)"
 // @Success 200 {array} EpisodeClipResponse
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
@@ -125,11 +150,19 @@ func ListClipsForEpisode(deps *types.Dependencies) gin.HandlerFunc {
 			approvedFilter = &approved
 		}
 
+		// Optional rejected filter
+		var rejectedFilter *bool
+		if rejectedStr := c.Query("rejected"); rejectedStr != "" {
+			rejected := rejectedStr == "true"
+			rejectedFilter = &rejected
+		}
+
 		// List clips for this episode
 		clipsList, err := deps.ClipService.ListClips(c.Request.Context(), clips.ListClipsFilters{
 			EpisodeID: &episodeID, // Filter by episode
 			Status:    status,
 			Approved:  approvedFilter,
+			Rejected:  rejectedFilter,
 			Limit:     1000, // Return all clips for episode
 			Offset:    0,
 		})
@@ -140,9 +173,11 @@ func ListClipsForEpisode(deps *types.Dependencies) gin.HandlerFunc {
 		}
 
 		// Convert to response format
+		types.ClipTranscripts(c, deps, clipsList...)
+		rates := types.ClipSampleRates(c, deps, clipsList...)
 		response := make([]EpisodeClipResponse, len(clipsList))
 		for i, clip := range clipsList {
-			response[i] = toClipResponse(clip)
+			response[i] = toClipResponse(clip, rates)
 		}
 
 		c.JSON(http.StatusOK, response)
@@ -178,8 +213,8 @@ func GetClipForEpisode(deps *types.Dependencies) gin.HandlerFunc {
 
 		clip, err := deps.ClipService.GetClip(c.Request.Context(), uuid)
 		if err != nil {
-			if err.Error() == "clip not found" {
-				types.SendNotFound(c, "Clip not found")
+			if errors.Is(err, clips.ErrClipNotFound) {
+				types.SendError(c, http.StatusNotFound, types.CodeClipNotFound, "Clip not found")
 			} else {
 				types.SendInternalError(c, fmt.Sprintf("Failed to get clip: %v", err))
 			}
@@ -188,11 +223,12 @@ func GetClipForEpisode(deps *types.Dependencies) gin.HandlerFunc {
 
 		// Verify clip belongs to this episode
 		if clip.PodcastIndexEpisodeID != episodeID {
-			types.SendNotFound(c, "Clip not found for this episode")
+			types.SendError(c, http.StatusNotFound, types.CodeClipNotFound, "Clip not found for this episode")
 			return
 		}
 
-		c.JSON(http.StatusOK, toClipResponse(clip))
+		types.ClipTranscripts(c, deps, clip)
+		c.JSON(http.StatusOK, toClipResponse(clip, types.ClipSampleRates(c, deps, clip)))
 	}
 }
 
@@ -207,6 +243,7 @@ func GetClipForEpisode(deps *types.Dependencies) gin.HandlerFunc {
 // @Success 200 {object} EpisodeClipResponse
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse "Label quota reached"
 // @Failure 500 {object} types.ErrorResponse
 // @Router /api/v1/episodes/{id}/clips/{uuid}/label [put]
 func UpdateClipLabel(deps *types.Dependencies) gin.HandlerFunc {
@@ -234,8 +271,8 @@ func UpdateClipLabel(deps *types.Dependencies) gin.HandlerFunc {
 		// Get clip first to verify it belongs to episode
 		clip, err := deps.ClipService.GetClip(c.Request.Context(), uuid)
 		if err != nil {
-			if err.Error() == "clip not found" {
-				types.SendNotFound(c, "Clip not found")
+			if errors.Is(err, clips.ErrClipNotFound) {
+				types.SendError(c, http.StatusNotFound, types.CodeClipNotFound, "Clip not found")
 			} else {
 				types.SendInternalError(c, fmt.Sprintf("Failed to get clip: %v", err))
 			}
@@ -244,18 +281,24 @@ func UpdateClipLabel(deps *types.Dependencies) gin.HandlerFunc {
 
 		// Verify clip belongs to this episode
 		if clip.PodcastIndexEpisodeID != episodeID {
-			types.SendNotFound(c, "Clip not found for this episode")
+			types.SendError(c, http.StatusNotFound, types.CodeClipNotFound, "Clip not found for this episode")
 			return
 		}
 
 		// Update label
-		clip, err = deps.ClipService.UpdateClipLabel(c.Request.Context(), uuid, req.Label)
+		ctx := clips.WithActor(c.Request.Context(), c.GetString("user_id"))
+		clip, err = deps.ClipService.UpdateClipLabel(ctx, uuid, req.Label)
+		if errors.Is(err, clips.ErrLabelQuotaExceeded) {
+			types.SendError(c, http.StatusConflict, types.CodeLabelQuotaExceeded, err.Error())
+			return
+		}
 		if err != nil {
 			types.SendInternalError(c, fmt.Sprintf("Failed to update label: %v", err))
 			return
 		}
 
-		c.JSON(http.StatusOK, toClipResponse(clip))
+		types.ClipTranscripts(c, deps, clip)
+		c.JSON(http.StatusOK, toClipResponse(clip, types.ClipSampleRates(c, deps, clip)))
 	}
 }
 
@@ -288,7 +331,7 @@ func DeleteClipFromEpisode(deps *types.Dependencies) gin.HandlerFunc {
 		// Get clip first to verify it belongs to episode
 		clip, err := deps.ClipService.GetClip(c.Request.Context(), uuid)
 		if err != nil {
-			if err.Error() == "clip not found" {
+			if errors.Is(err, clips.ErrClipNotFound) {
 				// Idempotent - already deleted
 				c.Status(http.StatusNoContent)
 				return
@@ -299,12 +342,13 @@ func DeleteClipFromEpisode(deps *types.Dependencies) gin.HandlerFunc {
 
 		// Verify clip belongs to this episode
 		if clip.PodcastIndexEpisodeID != episodeID {
-			types.SendNotFound(c, "Clip not found for this episode")
+			types.SendError(c, http.StatusNotFound, types.CodeClipNotFound, "Clip not found for this episode")
 			return
 		}
 
 		// Delete clip
-		if err := deps.ClipService.DeleteClip(c.Request.Context(), uuid); err != nil {
+		ctx := clips.WithActor(c.Request.Context(), c.GetString("user_id"))
+		if err := deps.ClipService.DeleteClip(ctx, uuid); err != nil {
 			types.SendInternalError(c, fmt.Sprintf("Failed to delete clip: %v", err))
 			return
 		}
@@ -314,7 +358,7 @@ func DeleteClipFromEpisode(deps *types.Dependencies) gin.HandlerFunc {
 }
 
 // @Summary Approve clip for extraction
-// @Description Mark a clip as approved for extraction. This is used for clips created by analysis (status=detected, approved=false) to trigger audio extraction. Sets approved=true and queues the clip for processing.
+// @Description Mark a clip as approved for extraction. This is used for clips created by analysis (status=detected, approved=false) to trigger audio extraction. Sets approved=true and queues the clip for processing. Approving an auto-approved clip confirms it, clearing auto_approved.
 // @Tags episodes
 // @Produce json
 // @Param id path int true "Episode ID"
@@ -322,6 +366,7 @@ func DeleteClipFromEpisode(deps *types.Dependencies) gin.HandlerFunc {
 // @Success 200 {object} EpisodeClipResponse "Clip approved and queued for extraction"
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse "Label quota reached"
 // @Failure 500 {object} types.ErrorResponse
 // @Router /api/v1/episodes/{id}/clips/{uuid}/approve [put]
 func ApproveClip(deps *types.Dependencies) gin.HandlerFunc {
@@ -343,8 +388,8 @@ func ApproveClip(deps *types.Dependencies) gin.HandlerFunc {
 		// Get clip first to verify it belongs to episode
 		clip, err := deps.ClipService.GetClip(c.Request.Context(), uuid)
 		if err != nil {
-			if err.Error() == "clip not found" {
-				types.SendNotFound(c, "Clip not found")
+			if errors.Is(err, clips.ErrClipNotFound) {
+				types.SendError(c, http.StatusNotFound, types.CodeClipNotFound, "Clip not found")
 				return
 			}
 			types.SendInternalError(c, fmt.Sprintf("Failed to get clip: %v", err))
@@ -353,38 +398,60 @@ func ApproveClip(deps *types.Dependencies) gin.HandlerFunc {
 
 		// Verify clip belongs to this episode
 		if clip.PodcastIndexEpisodeID != episodeID {
-			types.SendNotFound(c, "Clip not found for this episode")
+			types.SendError(c, http.StatusNotFound, types.CodeClipNotFound, "Clip not found for this episode")
 			return
 		}
 
 		// Approve the clip via service
-		clip, err = deps.ClipService.ApproveClip(c.Request.Context(), uuid)
+		ctx := clips.WithActor(c.Request.Context(), c.GetString("user_id"))
+		clip, err = deps.ClipService.ApproveClip(ctx, uuid)
+		if errors.Is(err, clips.ErrLabelQuotaExceeded) {
+			types.SendError(c, http.StatusConflict, types.CodeLabelQuotaExceeded, err.Error())
+			return
+		}
 		if err != nil {
 			types.SendInternalError(c, fmt.Sprintf("Failed to approve clip: %v", err))
 			return
 		}
-		c.JSON(http.StatusOK, toClipResponse(clip))
+		types.ClipTranscripts(c, deps, clip)
+		c.JSON(http.StatusOK, toClipResponse(clip, types.ClipSampleRates(c, deps, clip)))
 	}
 }
 
-// Helper function to convert clip model to response
-func toClipResponse(clip *models.Clip) EpisodeClipResponse {
-	return EpisodeClipResponse{
+// Helper function to convert clip model to response, with sample coordinates
+// when its episode's sample rate is in sampleRates
+func toClipResponse(clip *models.Clip, sampleRates map[int64]int) EpisodeClipResponse {
+	response := EpisodeClipResponse{
 		UUID:              clip.UUID,
 		Label:             clip.Label,
 		Status:            clip.Status,
 		Approved:          clip.Approved,
+		AutoApproved:      clip.AutoApproved,
+		ApprovalThreshold: clip.ApprovalThreshold,
+		Rejected:          clip.Rejected,
+		RejectionReason:   clip.RejectionReason,
 		Extracted:         clip.Extracted,
 		ClipFilename:      clip.ClipFilename,
 		ClipDuration:      clip.ClipDuration,
 		ClipSizeBytes:     clip.ClipSizeBytes,
 		OriginalStartTime: clip.OriginalStartTime,
 		OriginalEndTime:   clip.OriginalEndTime,
+		RangeClamped:      clip.RangeClamped,
 		AutoLabeled:       clip.AutoLabeled,
 		LabelConfidence:   clip.LabelConfidence,
 		LabelMethod:       clip.LabelMethod,
+		ModelName:         clip.ModelName,
+		ModelVersion:      clip.ModelVersion,
 		ErrorMessage:      clip.ErrorMessage,
+		JobError:          types.NewClipError(clip),
+		TranscriptText:    clip.TranscriptText,
 		CreatedAt:         clip.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:         clip.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
+	if samples := clip.Samples(sampleRates[clip.PodcastIndexEpisodeID]); samples != nil {
+		response.SampleRate = &samples.SampleRate
+		response.StartSample = &samples.StartSample
+		response.EndSample = &samples.EndSample
+	}
+	return response
 }
@@ -0,0 +1,95 @@
+package episodes
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/clips"
+)
+
+// DeleteAnnotationsResponse reports the annotations a bulk deletion matched
+type DeleteAnnotationsResponse struct {
+	EpisodeID int64    `json:"episode_id" example:"12345"`
+	DryRun    bool     `json:"dry_run"`
+	Count     int64    `json:"count" example:"42"` // Annotations deleted, or that would be on a dry run
+	Deleted   []string `json:"deleted,omitempty"`  // UUIDs of the deleted annotations; absent on a dry run
+}
+
+// @Summary Delete annotations by filter
+// @Description Deletes every annotation (clip) on the episode matching the filters, to clean up a bad labeling
+// @Description session. At least one of label, created_after and created_by is required. The deletion runs in
+// @Description one transaction and records a bulk_delete entry in each annotation's history. With dry_run=true
+// @Description nothing is deleted and only the number of matching annotations is returned.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Param label query string false "Only annotations with this label"
+// @Param created_after query string false "Only annotations created at or after this RFC 3339 time"
+// @Param created_by query string false "Only annotations created by this user ID"
+// @Param dry_run query bool false "Count the matching annotations without deleting them"
+// @Success 200 {object} DeleteAnnotationsResponse
+// @Failure 400 {object} types.ErrorResponse "Invalid episode ID or filters"
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/episodes/{id}/annotations [delete]
+func DeleteAnnotations(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+
+		filters := clips.ListClipsFilters{
+			EpisodeID: &episodeID,
+			Label:     c.Query("label"),
+			CreatedBy: c.Query("created_by"),
+		}
+		if raw := c.Query("created_after"); raw != "" {
+			createdAfter, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				types.SendBadRequest(c, "created_after must be an RFC 3339 time")
+				return
+			}
+			filters.CreatedAfter = &createdAfter
+		}
+		// Without a filter this would wipe the episode's labels, which is never a cleanup
+		if filters.Label == "" && filters.CreatedBy == "" && filters.CreatedAfter == nil {
+			types.SendBadRequest(c, "At least one of label, created_after or created_by is required")
+			return
+		}
+
+		if deps.ClipService == nil {
+			types.SendInternalError(c, "Clip service not available")
+			return
+		}
+
+		response := DeleteAnnotationsResponse{
+			EpisodeID: episodeID,
+			DryRun:    c.Query("dry_run") == "true",
+		}
+
+		if response.DryRun {
+			count, err := deps.ClipService.CountClips(c.Request.Context(), filters)
+			if err != nil {
+				types.SendInternalError(c, fmt.Sprintf("Failed to count annotations: %v", err))
+				return
+			}
+			response.Count = count
+			c.JSON(http.StatusOK, response)
+			return
+		}
+
+		ctx := clips.WithActor(c.Request.Context(), c.GetString("user_id"))
+		deleted, err := deps.ClipService.DeleteClips(ctx, filters)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to delete annotations: %v", err))
+			return
+		}
+		response.Count = int64(len(deleted))
+		response.Deleted = deleted
+
+		c.JSON(http.StatusOK, response)
+	}
+}
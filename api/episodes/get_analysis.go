@@ -0,0 +1,96 @@
+package episodes
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/contentsafety"
+)
+
+// ContentAnalysisResponse represents explicit-language analysis results for an episode
+type ContentAnalysisResponse struct {
+	EpisodeID  int64                `json:"episode_id" example:"12345"`
+	Explicit   bool                 `json:"explicit" example:"true"`
+	FlagCount  int                  `json:"flag_count" example:"2"`
+	Timing     string               `json:"timing" example:"segments"` // "segments" (from transcript timestamps) or "estimated"
+	Flags      []models.ContentFlag `json:"flags"`
+	AnalyzedAt time.Time            `json:"analyzed_at"`
+}
+
+// @Summary Get episode content analysis
+// @Description Returns explicit-language regions detected in the episode transcription, with start/end times in seconds so family-mode clients can skip or mute them. Analysis runs automatically after transcription; if a transcription exists but has not been analyzed yet, it is analyzed on demand. Timing is 'segments' when derived from transcript timestamps, or 'estimated' when interpolated across the episode duration.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Success 200 {object} ContentAnalysisResponse "Flagged regions"
+// @Failure 400 {object} types.ErrorResponse "Invalid episode ID"
+// @Failure 404 {object} types.ErrorResponse "No transcription available to analyze"
+// @Failure 500 {object} types.ErrorResponse "Analysis unavailable"
+// @Router /api/v1/episodes/{id}/analyze [get]
+func GetContentAnalysis(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			types.SendBadRequest(c, "Invalid episode ID")
+			return
+		}
+
+		if deps.ContentSafetyService == nil {
+			types.SendInternalError(c, "Content analysis service not available")
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		analysis, err := deps.ContentSafetyService.GetAnalysis(ctx, episodeID)
+		if err != nil && !errors.Is(err, contentsafety.ErrAnalysisNotFound) {
+			types.SendInternalError(c, err.Error())
+			return
+		}
+
+		if analysis == nil {
+			if deps.TranscriptionService == nil {
+				types.SendError(c, http.StatusNotFound, types.CodeAnalysisNotFound, "Episode has not been analyzed")
+				return
+			}
+
+			transcriptionModel, err := deps.TranscriptionService.GetTranscription(ctx, episodeID)
+			if err != nil {
+				types.SendInternalError(c, err.Error())
+				return
+			}
+			if transcriptionModel == nil {
+				types.SendError(c, http.StatusNotFound, types.CodeTranscriptNotFound, "No transcription available for episode")
+				return
+			}
+
+			log.Printf("[DEBUG] Analyzing existing transcription for episode %d on demand", episodeID)
+			analysis, err = deps.ContentSafetyService.AnalyzeText(ctx, episodeID, transcriptionModel.Text, transcriptionModel.Duration)
+			if err != nil {
+				types.SendInternalError(c, err.Error())
+				return
+			}
+		}
+
+		flags, err := analysis.Flags()
+		if err != nil {
+			types.SendInternalError(c, "Failed to decode content flags")
+			return
+		}
+
+		c.JSON(http.StatusOK, ContentAnalysisResponse{
+			EpisodeID:  episodeID,
+			Explicit:   analysis.Explicit,
+			FlagCount:  analysis.FlagCount,
+			Timing:     analysis.Timing,
+			Flags:      flags,
+			AnalyzedAt: analysis.UpdatedAt,
+		})
+	}
+}
@@ -14,7 +14,8 @@ import (
 // @Description  Retrieve comprehensive episode information including title, description, audio URL, duration,
 // @Description  and links to additional resources like transcripts and chapters. The episode data is fetched
 // @Description  from the local database cache or Podcast Index API if not cached. Audio URLs are direct links
-// @Description  suitable for streaming or download.
+// @Description  suitable for streaming or download. 'artwork_palette' lists the artwork's dominant colors,
+// @Description  extracted on first request.
 // @Tags         episodes
 // @Accept       json
 // @Produce      json
@@ -23,6 +24,7 @@ import (
 // @Failure      400 {object} types.ErrorResponse "Invalid ID format (must be positive integer)"
 // @Failure      404 {object} types.ErrorResponse "Episode not found in database or Podcast Index API"
 // @Failure      500 {object} types.ErrorResponse "Internal server error or API communication failure"
+// @Failure      503 {object} types.ErrorResponse "Daily Podcast Index budget spent"
 // @Router       /api/v1/episodes/{id} [get]
 func GetByID(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -39,22 +41,28 @@ func GetByID(deps *types.Dependencies) gin.HandlerFunc {
 				log.Printf("[WARN] Episode not found - Podcast Index ID: %d, Error: %v", podcastIndexID, err)
 				c.JSON(http.StatusNotFound, types.ErrorResponse{
 					Status:  types.StatusError,
+					Code:    types.CodeEpisodeNotFound,
 					Message: "Episode not found",
 				})
 			} else {
 				log.Printf("[ERROR] Failed to fetch episode with Podcast Index ID %d: %v", podcastIndexID, err)
-				c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-					Status:  types.StatusError,
-					Message: "Failed to fetch episode",
-					Details: err.Error(),
-				})
+				types.SendServiceError(c, err, "Failed to fetch episode")
 			}
 			return
 		}
+		if types.Blocklist(c, deps).Blocks(types.EpisodeSubject(episode)) {
+			c.JSON(http.StatusNotFound, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeEpisodeNotFound,
+				Message: "Episode not found",
+			})
+			return
+		}
 
 		// Convert to unified Episode format
 		pieFormat := deps.EpisodeTransformer.ModelToPodcastIndex(episode)
 		unifiedEpisode := types.FromServiceEpisode(&pieFormat)
+		unifiedEpisode.ArtworkPalette = types.ArtworkPalette(c, deps, unifiedEpisode.Image)
 
 		// Return unified response
 		c.JSON(http.StatusOK, types.SingleEpisodeResponse{
@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
 	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
 	"gorm.io/gorm"
 )
 
@@ -36,6 +37,7 @@ type ClipsResponse struct {
 	EpisodeID int64          `json:"episode_id" example:"123" description:"Podcast Index Episode ID"`
 	Clips     []ClipResponse `json:"clips" description:"Array of clips (empty if analysis pending)"`
 	Progress  *int           `json:"progress,omitempty" example:"45" description:"Analysis progress 0-100 (only when processing)"`
+	JobID     uint           `json:"job_id,omitempty" example:"1234" description:"Analysis job to poll at /api/v1/jobs/{id} (only when queued)"`
 }
 
 // GetClips returns clips for an episode, triggering analysis if needed
@@ -49,6 +51,7 @@ func GetClips(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil || podcastIndexID <= 0 {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Invalid Podcast Index Episode ID",
 			})
 			return
@@ -58,6 +61,7 @@ func GetClips(deps *types.Dependencies) gin.HandlerFunc {
 		if deps.ClipService == nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Clip service not available",
 			})
 			return
@@ -74,6 +78,7 @@ func GetClips(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: fmt.Sprintf("Failed to retrieve clips: %v", err),
 			})
 			return
@@ -108,11 +113,13 @@ func GetClips(deps *types.Dependencies) gin.HandlerFunc {
 		}
 
 		// No clips exist - enqueue analysis job if job service is available
+		var jobID uint
 		if deps.JobService != nil {
 			// Get episode details to get audio URL
 			if deps.EpisodeService == nil {
 				c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 					Status:  types.StatusError,
+					Code:    types.CodeInternal,
 					Message: "Episode service not available",
 				})
 				return
@@ -122,6 +129,7 @@ func GetClips(deps *types.Dependencies) gin.HandlerFunc {
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 					Status:  types.StatusError,
+					Code:    types.CodeInternal,
 					Message: fmt.Sprintf("Failed to get episode details: %v", err),
 				})
 				return
@@ -134,17 +142,21 @@ func GetClips(deps *types.Dependencies) gin.HandlerFunc {
 			}
 
 			// Use EnqueueUniqueJob to prevent duplicate jobs for same episode
-			uniqueKey := fmt.Sprintf("episode_analysis:%d", podcastIndexID)
-			job, jobErr := deps.JobService.EnqueueUniqueJob(ctx, "episode_analysis", payload, uniqueKey)
-			if jobErr != nil {
+			job, jobErr := deps.JobService.EnqueueUniqueJob(ctx, models.JobTypeEpisodeAnalysis, payload, "episode_id")
+			if errors.Is(jobErr, jobs.ErrQueueFull) {
+				types.SendServiceError(c, jobErr, "Failed to queue episode analysis")
+				return
+			} else if jobErr != nil {
 				log.Printf("[WARN] Failed to enqueue episode analysis job for episode %d: %v", podcastIndexID, jobErr)
 				// Continue anyway - return queued status
 			} else {
 				log.Printf("[INFO] Enqueued episode analysis job %d for episode %d", job.ID, podcastIndexID)
+				jobID = job.ID
 			}
 		}
 
 		// Return 202 Accepted with empty clips
+		types.SetJobLocation(c, jobID)
 		c.JSON(http.StatusAccepted, ClipsResponse{
 			BaseResponse: types.BaseResponse{
 				Status:  types.StatusQueued,
@@ -153,6 +165,7 @@ func GetClips(deps *types.Dependencies) gin.HandlerFunc {
 			EpisodeID: podcastIndexID,
 			Clips:     []ClipResponse{},
 			Progress:  nil,
+			JobID:     jobID,
 		})
 	}
 }
@@ -47,6 +47,14 @@ func (s *testClipService) GetClipsByEpisodeID(ctx context.Context, episodeID int
 	return clips, nil
 }
 
+func (s *testClipService) SampleRates(ctx context.Context, episodeIDs []int64) (map[int64]int, error) {
+	return map[int64]int{}, nil
+}
+
+func (s *testClipService) AttachTranscriptText(ctx context.Context, clips []*models.Clip) error {
+	return nil
+}
+
 func (s *testClipService) UpdateClipLabel(ctx context.Context, uuid, newLabel string) (*models.Clip, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -55,16 +63,73 @@ func (s *testClipService) ApproveClip(ctx context.Context, uuid string) (*models
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (s *testClipService) AutoApproveClip(ctx context.Context, uuid string, threshold float64) (*models.Clip, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *testClipService) RejectClip(ctx context.Context, uuid, reason string) (*models.Clip, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *testClipService) GetAnnotationHistory(ctx context.Context, uuid string) ([]models.AnnotationAudit, error) {
+	var history []models.AnnotationAudit
+	if err := s.db.Where("clip_uuid = ?", uuid).Order("id ASC").Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (s *testClipService) ReviewQueue(ctx context.Context, filters clips.ReviewQueueFilters) ([]*models.Clip, int64, error) {
+	return nil, 0, fmt.Errorf("not implemented")
+}
+
+func (s *testClipService) LabelStats(ctx context.Context) ([]clips.LabelStats, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (s *testClipService) DeleteClip(ctx context.Context, uuid string) error {
 	return fmt.Errorf("not implemented")
 }
 
+func (s *testClipService) DeleteClips(ctx context.Context, filters clips.ListClipsFilters) ([]string, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (s *testClipService) ListClips(ctx context.Context, filters clips.ListClipsFilters) ([]*models.Clip, error) {
+	query := s.db.Model(&models.Clip{})
+	if filters.EpisodeID != nil {
+		query = query.Where("podcast_index_episode_id = ?", *filters.EpisodeID)
+	}
+	if filters.Approved != nil {
+		query = query.Where("approved = ?", *filters.Approved)
+	}
+	if filters.Rejected != nil {
+		query = query.Where("rejected = ?", *filters.Rejected)
+	}
+	var result []*models.Clip
+	err := query.Find(&result).Error
+	return result, err
+}
+
+func (s *testClipService) CountClips(ctx context.Context, filters clips.ListClipsFilters) (int64, error) {
+	list, err := s.ListClips(ctx, filters)
+	return int64(len(list)), err
+}
+
+func (s *testClipService) ExportDataset(ctx context.Context, exportPath string, opts clips.ExportOptions) ([]clips.SkippedClip, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (s *testClipService) ExportDataset(ctx context.Context, exportPath string) error {
-	return fmt.Errorf("not implemented")
+func (s *testClipService) ReextractClips(ctx context.Context, filters clips.ExportFilters, progress clips.ReextractProgress) (*clips.ReextractResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *testClipService) VerifyClips(ctx context.Context, opts clips.VerifyOptions) (*clips.VerifyResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *testClipService) PlanExport(ctx context.Context, opts clips.ExportOptions) ([]clips.PlannedClip, error) {
+	return nil, fmt.Errorf("not implemented")
 }
 
 func setupTestDB(t *testing.T) *gorm.DB {
@@ -74,7 +139,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 
 	// Migrate
-	err = db.AutoMigrate(&models.Clip{})
+	err = db.AutoMigrate(&models.Clip{}, &models.AnnotationAudit{})
 	require.NoError(t, err)
 
 	return db
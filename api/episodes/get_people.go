@@ -0,0 +1,66 @@
+package episodes
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// GetPeople returns the people credited on an episode
+// @Summary      Get episode people
+// @Description  List hosts, guests and other contributors credited on an episode via podcast:person tags.
+// @Description  Credits are stored when the episode is synced from Podcast Index; an episode not yet in the
+// @Description  database is fetched first. Use /people/{id}/episodes to browse other episodes for a person.
+// @Tags         episodes
+// @Produce      json
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Success      200 {object} types.EpisodePeopleResponse "Credited people (may be empty)"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID"
+// @Failure      500 {object} types.ErrorResponse "Failed to load people"
+// @Router       /api/v1/episodes/{id}/people [get]
+func GetPeople(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID <= 0 {
+			types.SendBadRequest(c, "Invalid episode ID")
+			return
+		}
+
+		if deps.PeopleService == nil {
+			types.SendInternalError(c, "People service not available")
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		credits, err := deps.PeopleService.GetEpisodePeople(ctx, episodeID)
+		if err != nil {
+			types.SendInternalError(c, err.Error())
+			return
+		}
+
+		// An episode that has never been synced has no credits yet; syncing it records them
+		if len(credits) == 0 && deps.EpisodeService != nil {
+			if _, err := deps.EpisodeService.GetEpisodeByPodcastIndexID(ctx, episodeID); err != nil {
+				log.Printf("[DEBUG] Could not sync episode %d for people lookup: %v", episodeID, err)
+			} else if credits, err = deps.PeopleService.GetEpisodePeople(ctx, episodeID); err != nil {
+				types.SendInternalError(c, err.Error())
+				return
+			}
+		}
+
+		people := types.FromModelEpisodeCredits(credits)
+		c.JSON(http.StatusOK, types.EpisodePeopleResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Fetched episode people",
+			},
+			EpisodeID: episodeID,
+			People:    people,
+			Count:     len(people),
+		})
+	}
+}
@@ -42,10 +42,10 @@ func GetReviews(deps *types.Dependencies) gin.HandlerFunc {
 		episode, err := deps.EpisodeService.GetEpisodeByPodcastIndexID(c.Request.Context(), episodeID)
 		if err != nil {
 			if episodeService.IsNotFound(err) {
-				types.SendNotFound(c, "Episode not found")
+				types.SendError(c, http.StatusNotFound, types.CodeEpisodeNotFound, "Episode not found")
 			} else {
 				log.Printf("[ERROR] Failed to fetch episode %d: %v", episodeID, err)
-				types.SendInternalError(c, "Failed to fetch episode")
+				types.SendServiceError(c, err, "Failed to fetch episode")
 			}
 			return
 		}
@@ -0,0 +1,92 @@
+package episodes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/loudness"
+	"github.com/spf13/viper"
+)
+
+// Targets a player may ask gain for; below the EBU R128 absolute gate is meaningless
+const (
+	minTargetLUFS = -70.0
+	maxTargetLUFS = 0.0
+)
+
+// LoudnessResponse is the EBU R128 measurement of an episode, with the gain
+// that levels it to a target loudness
+type LoudnessResponse struct {
+	EpisodeID      int64     `json:"episode_id" example:"12345"`
+	IntegratedLUFS float64   `json:"integrated_lufs" example:"-18.42"` // Integrated loudness
+	TruePeakDBTP   float64   `json:"true_peak_dbtp" example:"-0.31"`   // Maximum true peak
+	RangeLU        float64   `json:"range_lu" example:"6.7"`           // Loudness range (LRA)
+	ThresholdLUFS  float64   `json:"threshold_lufs" example:"-28.61"`  // Relative gate of the integrated measurement
+	TargetLUFS     float64   `json:"target_lufs" example:"-16"`
+	GainDB         float64   `json:"gain_db" example:"0.31"` // Gain reaching target_lufs, limited to keep the true peak at max_true_peak_dbtp
+	MaxTruePeak    float64   `json:"max_true_peak_dbtp" example:"-1"`
+	MeasuredAt     time.Time `json:"measured_at"`
+}
+
+// @Summary Get episode loudness
+// @Description EBU R128 integrated loudness, true peak and loudness range (LRA) of the episode's original audio,
+// @Description measured with ffmpeg's loudnorm filter, so players can level volume across shows. gain_db is the
+// @Description gain that brings the episode to target_lufs (loudness.target_lufs unless the target parameter is
+// @Description given), reduced so the true peak stays at or under loudness.max_true_peak. The audio must be cached.
+// @Description The first request measures it before responding: loudnorm decodes the whole episode, which takes
+// @Description time in proportion to its length (tens of seconds for an hour-long episode, more on a busy server),
+// @Description so clients should allow for a slow first response. Later requests read the stored result.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Param target query number false "Target integrated loudness in LUFS (-70 to 0)" example(-16)
+// @Success 200 {object} LoudnessResponse "Loudness measurement"
+// @Failure 400 {object} types.ErrorResponse "Invalid episode ID or target"
+// @Failure 404 {object} types.ErrorResponse "Episode audio not cached"
+// @Failure 500 {object} types.ErrorResponse "Measurement failed"
+// @Router /api/v1/episodes/{id}/loudness [get]
+func GetLoudness(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+
+		target := viper.GetFloat64("loudness.target_lufs")
+		if targetStr := c.Query("target"); targetStr != "" {
+			var err error
+			target, err = strconv.ParseFloat(targetStr, 64)
+			if err != nil || target < minTargetLUFS || target > maxTargetLUFS {
+				types.SendBadRequest(c, "target must be a loudness from -70 to 0 LUFS")
+				return
+			}
+		}
+
+		if deps.LoudnessService == nil {
+			types.SendInternalError(c, "Loudness service not available")
+			return
+		}
+
+		measured, err := deps.LoudnessService.GetLoudness(c.Request.Context(), episodeID)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to measure episode loudness")
+			return
+		}
+
+		maxTruePeak := viper.GetFloat64("loudness.max_true_peak")
+		c.JSON(http.StatusOK, LoudnessResponse{
+			EpisodeID:      episodeID,
+			IntegratedLUFS: measured.IntegratedLUFS,
+			TruePeakDBTP:   measured.TruePeakDBTP,
+			RangeLU:        measured.RangeLU,
+			ThresholdLUFS:  measured.ThresholdLUFS,
+			TargetLUFS:     target,
+			GainDB:         loudness.Gain(measured, target, maxTruePeak),
+			MaxTruePeak:    maxTruePeak,
+			MeasuredAt:     measured.UpdatedAt,
+		})
+	}
+}
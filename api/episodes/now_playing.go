@@ -0,0 +1,207 @@
+package episodes
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	episodeService "github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/transcription"
+	"github.com/killallgit/player-api/pkg/chapters"
+	"github.com/spf13/viper"
+)
+
+// Where a now playing chapter comes from
+const (
+	ChapterSourceFeed    = "feed"    // The feed's podcast:chapters file
+	ChapterSourceOutline = "outline" // A transcript outline section, for feeds without chapters
+)
+
+// NowPlayingArtwork is one size of the episode's artwork, shaped like a
+// MediaSession MediaImage
+type NowPlayingArtwork struct {
+	Src   string `json:"src" example:"https://example.com/artwork.jpg"`
+	Sizes string `json:"sizes" example:"512x512"`
+}
+
+// NowPlayingChapter is the chapter playing at the requested position
+type NowPlayingChapter struct {
+	Title  string  `json:"title" example:"Interview"`
+	Start  float64 `json:"start" example:"300"`
+	End    float64 `json:"end,omitempty" example:"1800"` // Absent for a last chapter the feed gives no end
+	Image  string  `json:"image,omitempty"`
+	URL    string  `json:"url,omitempty"`
+	Source string  `json:"source" enums:"feed,outline"`
+}
+
+// NowPlayingResponse holds what a lock-screen or MediaSession integration shows
+type NowPlayingResponse struct {
+	EpisodeID   int64               `json:"episode_id" example:"12345"`
+	Title       string              `json:"title"`
+	Show        string              `json:"show"`
+	Artwork     []NowPlayingArtwork `json:"artwork"`            // Smallest first
+	Duration    *int                `json:"duration,omitempty"` // Seconds
+	Position    float64             `json:"position" example:"312.5"`
+	Chapter     *NowPlayingChapter  `json:"chapter,omitempty"`
+	SkipMarkers []SkipMarker        `json:"skip_markers"`
+}
+
+// GetNowPlaying returns media session metadata for an episode
+// @Summary Get now playing metadata
+// @Description Returns, in one compact payload, what lock-screen players and the MediaSession API need: title,
+// @Description show, artwork in each of now_playing.artwork_sizes, duration, the chapter playing at t and the
+// @Description episode's skip markers. Artwork URLs go through now_playing.artwork_url when set; otherwise every
+// @Description size points at the original image. Chapters come from the feed's chapters file, or from the
+// @Description transcript outline when the feed has none. Missing chapters or skip markers leave those fields
+// @Description empty rather than failing the request.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Param t query number false "Playback position in seconds" minimum(0) default(0)
+// @Success 200 {object} NowPlayingResponse
+// @Failure 400 {object} types.ErrorResponse "Invalid episode ID or position"
+// @Failure 404 {object} types.ErrorResponse "Episode not found"
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/episodes/{id}/now-playing [get]
+func GetNowPlaying(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+
+		position := 0.0
+		if raw := c.Query("t"); raw != "" {
+			var err error
+			position, err = strconv.ParseFloat(raw, 64)
+			if err != nil || position < 0 {
+				types.SendBadRequest(c, "t must be a non-negative number of seconds")
+				return
+			}
+		}
+
+		episode, err := deps.EpisodeService.GetEpisodeByPodcastIndexID(c.Request.Context(), episodeID)
+		if err != nil {
+			if episodeService.IsNotFound(err) {
+				types.SendError(c, http.StatusNotFound, types.CodeEpisodeNotFound, "Episode not found")
+				return
+			}
+			types.SendServiceError(c, err, fmt.Sprintf("Failed to fetch episode: %v", err))
+			return
+		}
+		if types.Blocklist(c, deps).Blocks(types.EpisodeSubject(episode)) {
+			types.SendError(c, http.StatusNotFound, types.CodeEpisodeNotFound, "Episode not found")
+			return
+		}
+
+		show := episode.FeedTitle
+		if show == "" && episode.Podcast != nil {
+			show = episode.Podcast.Title
+		}
+
+		response := NowPlayingResponse{
+			EpisodeID:   episodeID,
+			Title:       episode.Title,
+			Show:        show,
+			Artwork:     nowPlayingArtwork(episode),
+			Duration:    episode.Duration,
+			Position:    position,
+			Chapter:     nowPlayingChapter(c, deps, episode, position),
+			SkipMarkers: []SkipMarker{},
+		}
+
+		if deps.ClipService != nil {
+			markers, err := skipMarkers(c.Request.Context(), deps.ClipService, episodeID, viper.GetFloat64("clips.skip_min_confidence"))
+			if err != nil {
+				log.Printf("[WARN] Returning now playing for episode %d without skip markers: %v", episodeID, err)
+			} else {
+				response.SkipMarkers = markers
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// nowPlayingArtwork lists the episode's artwork, or its podcast's, in each
+// configured size
+func nowPlayingArtwork(episode *models.Episode) []NowPlayingArtwork {
+	image := episode.Image
+	if image == "" {
+		image = episode.FeedImage
+	}
+	if image == "" {
+		return []NowPlayingArtwork{}
+	}
+
+	template := viper.GetString("now_playing.artwork_url")
+	sizes := viper.GetIntSlice("now_playing.artwork_sizes")
+	artwork := make([]NowPlayingArtwork, 0, len(sizes))
+	for _, size := range sizes {
+		if size <= 0 {
+			continue
+		}
+		src := image
+		if template != "" {
+			src = strings.NewReplacer("{size}", strconv.Itoa(size), "{url}", url.QueryEscape(image)).Replace(template)
+		}
+		artwork = append(artwork, NowPlayingArtwork{Src: src, Sizes: fmt.Sprintf("%dx%d", size, size)})
+	}
+	return artwork
+}
+
+// nowPlayingChapter finds the chapter at position in the feed's chapters,
+// falling back to the transcript outline when the feed has none or they
+// can't be fetched
+func nowPlayingChapter(c *gin.Context, deps *types.Dependencies, episode *models.Episode, position float64) *NowPlayingChapter {
+	if episode.ChaptersURL != "" && deps.Chapters != nil {
+		feedChapters, err := deps.Chapters.Fetch(c.Request.Context(), episode.ChaptersURL)
+		if err == nil {
+			chapter := chapters.At(feedChapters, position)
+			if chapter == nil {
+				return nil
+			}
+			return &NowPlayingChapter{
+				Title:  chapter.Title,
+				Start:  chapter.StartTime,
+				End:    chapter.EndTime,
+				Image:  chapter.Img,
+				URL:    chapter.URL,
+				Source: ChapterSourceFeed,
+			}
+		}
+		log.Printf("[WARN] Failed to fetch chapters for episode %d: %v", episode.PodcastIndexID, err)
+	}
+
+	if deps.TranscriptionService == nil {
+		return nil
+	}
+	outline, err := deps.TranscriptionService.Outline(c.Request.Context(), episode.PodcastIndexID)
+	if err != nil {
+		if !errors.Is(err, transcription.ErrNoTranscript) {
+			log.Printf("[WARN] Failed to load outline for episode %d: %v", episode.PodcastIndexID, err)
+		}
+		return nil
+	}
+	if !outline.Timed {
+		return nil
+	}
+	for _, section := range outline.Sections {
+		if position >= section.Start && position < section.End {
+			return &NowPlayingChapter{
+				Title:  section.Title,
+				Start:  section.Start,
+				End:    section.End,
+				Source: ChapterSourceOutline,
+			}
+		}
+	}
+	return nil
+}
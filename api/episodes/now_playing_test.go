@@ -0,0 +1,103 @@
+package episodes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	episodeService "github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/pkg/chapters"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nowPlayingEpisodes serves one episode; other methods are unused
+type nowPlayingEpisodes struct {
+	episodeService.EpisodeService
+	episode *models.Episode
+}
+
+func (s *nowPlayingEpisodes) GetEpisodeByPodcastIndexID(ctx context.Context, podcastIndexID int64) (*models.Episode, error) {
+	if s.episode == nil || s.episode.PodcastIndexID != podcastIndexID {
+		return nil, episodeService.ErrEpisodeNotFound
+	}
+	return s.episode, nil
+}
+
+func TestGetNowPlaying(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	viper.Set("clips.skip_labels", []string{"advertisement"})
+	viper.Set("now_playing.artwork_sizes", []int{96, 512})
+	viper.Set("now_playing.artwork_url", "https://img.example.com/{size}?src={url}")
+	defer viper.Reset()
+
+	chapterServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.2.0","chapters":[{"startTime":0,"title":"Intro"},{"startTime":120,"title":"Main","img":"https://example.com/main.jpg"}]}`))
+	}))
+	defer chapterServer.Close()
+
+	duration := 3600
+	db := setupTestDB(t)
+	require.NoError(t, db.Create(skipClip("ad", 60, 90, "advertisement", nil)).Error)
+	deps := &types.Dependencies{
+		EpisodeService: &nowPlayingEpisodes{episode: &models.Episode{
+			PodcastIndexID: 12345,
+			Title:          "Episode 42",
+			FeedTitle:      "The Show",
+			FeedImage:      "https://example.com/show.jpg",
+			Duration:       &duration,
+			ChaptersURL:    chapterServer.URL + "/chapters.json",
+		}},
+		ClipService: &testClipService{db: db},
+		Chapters:    chapters.NewFetcher(chapters.DefaultFetchOptions()),
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/episodes/:id/now-playing", GetNowPlaying(deps))
+
+	get := func(path string) (*httptest.ResponseRecorder, NowPlayingResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		var response NowPlayingResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response
+	}
+
+	w, response := get("/api/v1/episodes/12345/now-playing?t=150.5")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Episode 42", response.Title)
+	assert.Equal(t, "The Show", response.Show)
+	assert.Equal(t, []NowPlayingArtwork{
+		{Src: "https://img.example.com/96?src=https%3A%2F%2Fexample.com%2Fshow.jpg", Sizes: "96x96"},
+		{Src: "https://img.example.com/512?src=https%3A%2F%2Fexample.com%2Fshow.jpg", Sizes: "512x512"},
+	}, response.Artwork, "episodes without their own image get the podcast's")
+	require.NotNil(t, response.Duration)
+	assert.Equal(t, 3600, *response.Duration)
+	assert.Equal(t, 150.5, response.Position)
+	require.NotNil(t, response.Chapter)
+	assert.Equal(t, "Main", response.Chapter.Title)
+	assert.Equal(t, "https://example.com/main.jpg", response.Chapter.Image)
+	assert.Equal(t, ChapterSourceFeed, response.Chapter.Source)
+	require.Len(t, response.SkipMarkers, 1)
+	assert.Equal(t, 60.0, response.SkipMarkers[0].Start)
+
+	_, response = get("/api/v1/episodes/12345/now-playing")
+	require.NotNil(t, response.Chapter)
+	assert.Equal(t, "Intro", response.Chapter.Title)
+	assert.Equal(t, 120.0, response.Chapter.End)
+
+	w, _ = get("/api/v1/episodes/12345/now-playing?t=-1")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = get("/api/v1/episodes/999/now-playing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
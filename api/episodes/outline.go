@@ -0,0 +1,67 @@
+package episodes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/transcription"
+)
+
+// OutlineResponse is an episode's transcript split into topical sections
+type OutlineResponse struct {
+	types.BaseResponse
+	EpisodeID int64 `json:"episode_id" example:"16797088990"`
+	models.TranscriptOutline
+}
+
+// GetOutline returns the transcript outline of an episode
+// @Summary Get transcript outline
+// @Description Splits the episode's transcript into paragraphs, breaking at pauses of two seconds or more and
+// @Description at sentence ends once a paragraph runs long, and groups the paragraphs into topical sections
+// @Description where the vocabulary shifts (TextTiling). Each section is titled after the words most
+// @Description particular to it, giving players chapter-like navigation for feeds without chapters. Untimed
+// @Description transcripts are split the same way, with timed false and every time zero. The outline is
+// @Description built when the transcript is stored and kept with it.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Success 200 {object} OutlineResponse "Sections in playback order"
+// @Failure 400 {object} types.ErrorResponse "Invalid episode ID"
+// @Failure 404 {object} types.ErrorResponse "Episode not transcribed"
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/episodes/{id}/outline [get]
+func GetOutline(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID <= 0 {
+			types.SendBadRequest(c, "Invalid episode ID")
+			return
+		}
+
+		if deps.TranscriptionService == nil {
+			types.SendInternalError(c, "Transcription service not available")
+			return
+		}
+
+		outline, err := deps.TranscriptionService.Outline(c.Request.Context(), episodeID)
+		switch {
+		case errors.Is(err, transcription.ErrNoTranscript):
+			types.SendError(c, http.StatusNotFound, types.CodeTranscriptNotFound, "Episode has no transcript")
+			return
+		case err != nil:
+			types.SendInternalError(c, fmt.Sprintf("Failed to build outline: %v", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, OutlineResponse{
+			BaseResponse:      types.BaseResponse{Status: types.StatusOK, Message: fmt.Sprintf("Found %d sections", len(outline.Sections))},
+			EpisodeID:         episodeID,
+			TranscriptOutline: *outline,
+		})
+	}
+}
@@ -6,24 +6,33 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
 )
 
-// AnalysisResponse represents the response from volume analysis
+// AnalysisResponse represents the response from episode analysis
 type AnalysisResponse struct {
 	EpisodeID    int64    `json:"episode_id" example:"12345"`
+	Model        string   `json:"model" example:"volume-spike@1"`
 	ClipsCreated int      `json:"clips_created" example:"3"`
 	ClipUUIDs    []string `json:"clip_uuids" example:"052f3b9b-cc02-418c-a9ab-8f49534c01c8,123e4567-e89b-12d3-a456-426614174000"`
-	Message      string   `json:"message" example:"Successfully analyzed episode and created 3 clips from volume spikes"`
+	Message      string   `json:"message" example:"Successfully analyzed episode and created 3 clips from detected segments"`
 }
 
-// @Summary Analyze episode for volume spikes
-// @Description Scans the entire episode audio for volume anomalies (loud sections that may be ads or music) and automatically creates clips from detected spikes. Uses cached audio if available to avoid re-downloading. Created clips are labeled as 'volume_spike' for review.
+// @Summary Analyze episode with a detector model
+// @Description Runs a detector model from the model registry over the entire episode audio and automatically creates
+// @Description clips from the segments it detects, labeled with what the model predicts and recording the model's
+// @Description name and version. Without a model the registry's default runs, initially the built-in volume spike
+// @Description detector, which finds loud sections that may be ads or music and labels them 'volume_spike'. Uses
+// @Description cached audio if available to avoid re-downloading. Created clips await review.
 // @Tags episodes
 // @Produce json
 // @Param id path int true "Podcast Index Episode ID"
+// @Param model query string false "Registry model name (default: the default model)"
+// @Param version query string false "Model version (default: the newest registered)"
 // @Success 200 {object} AnalysisResponse "Analysis completed successfully with list of created clip UUIDs"
-// @Failure 400 {object} types.ErrorResponse "Invalid episode ID"
-// @Failure 404 {object} types.ErrorResponse "Episode not found"
+// @Failure 400 {object} types.ErrorResponse "Invalid episode ID or model selection"
+// @Failure 404 {object} types.ErrorResponse "Episode or model not found"
+// @Failure 422 {object} types.ErrorResponse "The model's backend can't run on this server"
 // @Failure 500 {object} types.ErrorResponse "Analysis failed"
 // @Router /api/v1/episodes/{id}/analyze [post]
 func AnalyzeVolumeSpikes(deps *types.Dependencies) gin.HandlerFunc {
@@ -35,22 +44,38 @@ func AnalyzeVolumeSpikes(deps *types.Dependencies) gin.HandlerFunc {
 			return
 		}
 
-		clipUUIDs, err := deps.EpisodeAnalysisService.AnalyzeAndCreateClips(c.Request.Context(), episodeID)
+		var model *models.DetectorModel
+		if deps.ModelRegistry != nil {
+			model, err = deps.ModelRegistry.Resolve(c.Request.Context(), c.Query("model"), c.Query("version"))
+			if err != nil {
+				types.SendServiceError(c, err, "Failed to resolve model")
+				return
+			}
+		} else if c.Query("model") != "" {
+			types.SendInternalError(c, "Model registry not available")
+			return
+		}
+
+		clipUUIDs, err := deps.EpisodeAnalysisService.AnalyzeAndCreateClips(c.Request.Context(), episodeID, model)
 		if err != nil {
-			types.SendInternalError(c, err.Error())
+			types.SendServiceError(c, err, err.Error())
 			return
 		}
 
-		message := "No volume spikes detected"
+		message := "Nothing detected"
 		if len(clipUUIDs) > 0 {
-			message = "Successfully analyzed episode and created clips from volume spikes"
+			message = "Successfully analyzed episode and created clips from detected segments"
 		}
 
-		c.JSON(http.StatusOK, AnalysisResponse{
+		response := AnalysisResponse{
 			EpisodeID:    episodeID,
 			ClipsCreated: len(clipUUIDs),
 			ClipUUIDs:    clipUUIDs,
 			Message:      message,
-		})
+		}
+		if model != nil {
+			response.Model = model.Identity()
+		}
+		c.JSON(http.StatusOK, response)
 	}
 }
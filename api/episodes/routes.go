@@ -2,7 +2,9 @@ package episodes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/middleware"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/spf13/viper"
 )
 
 // RegisterRoutes registers episode routes
@@ -13,14 +15,56 @@ func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
 	// GET /api/v1/episodes/:id/reviews - Get iTunes reviews for the podcast
 	router.GET("/:id/reviews", GetReviews(deps))
 
+	// GET /api/v1/episodes/:id/people - Get hosts and guests credited on the episode
+	router.GET("/:id/people", GetPeople(deps))
+
 	// POST /api/v1/episodes/:id/analyze - Analyze episode for volume spikes
-	router.POST("/:id/analyze", AnalyzeVolumeSpikes(deps))
+	// Download and analysis run before the response is written, so it is exempt from the write timeout
+	router.POST("/:id/analyze", middleware.NoWriteDeadline(), AnalyzeVolumeSpikes(deps))
+
+	// GET /api/v1/episodes/:id/analyze - Get explicit-language regions from the transcription
+	router.GET("/:id/analyze", GetContentAnalysis(deps))
+
+	// GET /api/v1/episodes/:id/alignment - Transcript segments mapped onto waveform peaks
+	router.GET("/:id/alignment", GetAlignment(deps))
+
+	// GET /api/v1/episodes/:id/outline - Transcript paragraphs grouped into topical sections
+	router.GET("/:id/outline", GetOutline(deps))
+
+	// GET /api/v1/episodes/:id/loudness - EBU R128 loudness of the cached audio, for volume leveling
+	// The first request decodes the whole episode before responding, so it is exempt from the write timeout
+	router.GET("/:id/loudness", middleware.NoWriteDeadline(), GetLoudness(deps))
+
+	// GET /api/v1/episodes/:id/similar - Re-runs and compilations found by transcript overlap
+	router.GET("/:id/similar", GetSimilar(deps))
 
 	// Clip management endpoints (scoped to episode)
-	router.POST("/:id/clips", CreateClipForEpisode(deps))          // Create clip for this episode
-	router.GET("/:id/clips", ListClipsForEpisode(deps))            // List all clips for this episode
-	router.GET("/:id/clips/:uuid", GetClipForEpisode(deps))        // Get specific clip
-	router.PUT("/:id/clips/:uuid/label", UpdateClipLabel(deps))    // Update clip label
-	router.PUT("/:id/clips/:uuid/approve", ApproveClip(deps))      // Approve clip for extraction
-	router.DELETE("/:id/clips/:uuid", DeleteClipFromEpisode(deps)) // Delete clip
+	router.POST("/:id/clips", CreateClipForEpisode(deps))                     // Create clip for this episode
+	router.POST("/:id/clips/from-transcript", CreateClipFromTranscript(deps)) // Create clip from transcript segments
+	router.GET("/:id/clips", ListClipsForEpisode(deps))                       // List all clips for this episode
+	router.GET("/:id/clips/:uuid", GetClipForEpisode(deps))                   // Get specific clip
+	router.PUT("/:id/clips/:uuid/label", UpdateClipLabel(deps))               // Update clip label
+	router.PUT("/:id/clips/:uuid/approve", ApproveClip(deps))                 // Approve clip for extraction
+	router.DELETE("/:id/clips/:uuid", DeleteClipFromEpisode(deps))            // Delete clip
+
+	// GET /api/v1/episodes/:id/skip-markers - Merged ad ranges from approved clips for auto-skip
+	router.GET("/:id/skip-markers", GetSkipMarkers(deps))
+
+	// GET /api/v1/episodes/:id/now-playing - Lock-screen metadata: artwork sizes, chapter at ?t=, skip markers
+	router.GET("/:id/now-playing", GetNowPlaying(deps))
+
+	// DELETE /api/v1/episodes/:id/annotations - Bulk delete annotations (clips) by label, creation time or creator
+	router.DELETE("/:id/annotations", DeleteAnnotations(deps))
+
+	// GET /api/v1/episodes/:id/annotations/:uuid/history - Audit trail of an annotation (clip)
+	router.GET("/:id/annotations/:uuid/history", GetAnnotationHistory(deps))
+}
+
+// RegisterStreamRoutes registers the audio proxy. It must not sit behind the response
+// cache, which would buffer whole audio files in memory; segments are cached on disk.
+func RegisterStreamRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// GET/HEAD /api/v1/episodes/:id/stream - Range-capable audio proxy
+	streamDeadline := middleware.StreamDeadline(viper.GetDuration("server.stream_write_timeout"))
+	router.GET("/:id/stream", streamDeadline, Stream(deps))
+	router.HEAD("/:id/stream", Stream(deps))
 }
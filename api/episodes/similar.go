@@ -0,0 +1,126 @@
+package episodes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/transcription"
+)
+
+const (
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 50
+)
+
+// SimilarEpisode is an episode of the same podcast whose transcript overlaps the requested one
+type SimilarEpisode struct {
+	EpisodeID   int64   `json:"episode_id" example:"16797088990"`
+	Title       string  `json:"title,omitempty" example:"Best of 2023"`
+	PublishedAt int64   `json:"published_at,omitempty" example:"1704067200"` // Unix seconds
+	Kind        string  `json:"kind" example:"compilation"`                  // rerun, compilation, excerpt or overlap
+	Similarity  float64 `json:"similarity" example:"0.31"`                   // Jaccard similarity of the transcripts' word shingles
+	ContainedIn float64 `json:"contained_in" example:"0.33"`                 // Share of the requested episode found in this one
+	Contains    float64 `json:"contains" example:"0.92"`                     // Share of this episode found in the requested one
+}
+
+// SimilarEpisodesResponse lists likely re-runs and compilations of an episode
+type SimilarEpisodesResponse struct {
+	types.BaseResponse
+	EpisodeID int64            `json:"episode_id" example:"16797088990"`
+	Compared  int              `json:"compared" example:"120"` // Transcribed episodes of the podcast compared against
+	Similar   []SimilarEpisode `json:"similar"`
+}
+
+// GetSimilar finds re-runs and compilations of an episode
+// @Summary Find duplicated episodes
+// @Description Compares the episode's transcript with the transcripts of the podcast's other episodes, most
+// @Description recent first, using hashed five-word shingles, and returns those sharing at least min_score of
+// @Description either transcript. kind is "rerun" when the transcripts are nearly identical, "compilation"
+// @Description when the requested episode contains most of the other, "excerpt" when most of the requested
+// @Description episode appears in the other, and "overlap" otherwise. Only episodes with a stored transcript
+// @Description take part, so dataset builders should transcribe a podcast before deduplicating it.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Param limit query int false "Maximum results" default(10) minimum(1) maximum(50)
+// @Param min_score query number false "Minimum share of either transcript in common" default(0.2) minimum(0) maximum(1)
+// @Success 200 {object} SimilarEpisodesResponse "Closest matches first"
+// @Failure 400 {object} types.ErrorResponse "Invalid episode ID, limit or min_score"
+// @Failure 404 {object} types.ErrorResponse "Episode not stored or not transcribed"
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/episodes/{id}/similar [get]
+func GetSimilar(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID <= 0 {
+			types.SendBadRequest(c, "Invalid episode ID")
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSimilarLimit)))
+		if err != nil || limit < 1 || limit > maxSimilarLimit {
+			types.SendBadRequest(c, "limit must be between 1 and 50")
+			return
+		}
+		minScore := transcription.DefaultSimilarMinScore
+		if raw := c.Query("min_score"); raw != "" {
+			minScore, err = strconv.ParseFloat(raw, 64)
+			if err != nil || minScore < 0 || minScore > 1 {
+				types.SendBadRequest(c, "min_score must be between 0 and 1")
+				return
+			}
+		}
+
+		if deps.TranscriptionService == nil {
+			types.SendInternalError(c, "Transcription service not available")
+			return
+		}
+
+		result, err := deps.TranscriptionService.SimilarEpisodes(c.Request.Context(), episodeID, transcription.SimilarOptions{
+			Limit:    limit,
+			MinScore: minScore,
+		})
+		switch {
+		case errors.Is(err, transcription.ErrEpisodeNotStored):
+			types.SendError(c, http.StatusNotFound, types.CodeEpisodeNotFound, "Episode not found")
+			return
+		case errors.Is(err, transcription.ErrNoTranscript):
+			types.SendError(c, http.StatusNotFound, types.CodeTranscriptNotFound, "Episode has no transcript to compare")
+			return
+		case err != nil:
+			types.SendInternalError(c, fmt.Sprintf("Failed to compare episodes: %v", err))
+			return
+		}
+
+		similar := make([]SimilarEpisode, len(result.Similar))
+		for i, match := range result.Similar {
+			similar[i] = SimilarEpisode{
+				EpisodeID:   match.EpisodeID,
+				Kind:        match.Kind,
+				Similarity:  match.Jaccard,
+				ContainedIn: match.ContainedIn,
+				Contains:    match.Contains,
+			}
+			if match.Episode != nil {
+				similar[i].Title = match.Episode.Title
+				if !match.Episode.PublishedAt.IsZero() {
+					similar[i].PublishedAt = match.Episode.PublishedAt.Unix()
+				}
+			}
+		}
+
+		c.JSON(http.StatusOK, SimilarEpisodesResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: fmt.Sprintf("Found %d similar episodes among %d compared", len(similar), result.Compared),
+			},
+			EpisodeID: episodeID,
+			Compared:  result.Compared,
+			Similar:   similar,
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package episodes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/spf13/viper"
+)
+
+// SkipMarker is a time range the player can jump over
+type SkipMarker struct {
+	Start      float64  `json:"start" example:"312.4"`
+	End        float64  `json:"end" example:"371.9"`
+	Labels     []string `json:"labels" example:"advertisement"`
+	Confidence *float64 `json:"confidence,omitempty" example:"0.82"` // Lowest auto-label confidence among merged clips; absent when all were manual
+	ClipCount  int      `json:"clip_count" example:"2"`
+}
+
+// SkipMarkersResponse lists the skippable ranges of an episode in playback order
+type SkipMarkersResponse struct {
+	EpisodeID     int64        `json:"episode_id" example:"12345"`
+	MinConfidence float64      `json:"min_confidence" example:"0.5"`
+	Markers       []SkipMarker `json:"markers"`
+}
+
+// @Summary Get skip markers
+// @Description Returns approved ad clips for an episode as merged, non-overlapping time ranges for
+// @Description auto-skip. Clips whose ranges overlap, or sit closer than clips.skip_merge_gap seconds
+// @Description apart, become one marker. Auto-labeled clips below min_confidence are left out;
+// @Description manually created clips carry no confidence and are always included.
+// @Tags episodes
+// @Produce json
+// @Param id path int true "Podcast Index Episode ID"
+// @Param min_confidence query number false "Minimum auto-label confidence (0-1)" minimum(0) maximum(1)
+// @Success 200 {object} SkipMarkersResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /api/v1/episodes/{id}/skip-markers [get]
+func GetSkipMarkers(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID <= 0 {
+			types.SendBadRequest(c, "Invalid episode ID")
+			return
+		}
+
+		minConfidence := viper.GetFloat64("clips.skip_min_confidence")
+		if raw := c.Query("min_confidence"); raw != "" {
+			minConfidence, err = strconv.ParseFloat(raw, 64)
+			if err != nil || minConfidence < 0 || minConfidence > 1 {
+				types.SendBadRequest(c, "min_confidence must be a number between 0 and 1")
+				return
+			}
+		}
+
+		if deps.ClipService == nil {
+			types.SendInternalError(c, "Clip service not available")
+			return
+		}
+
+		markers, err := skipMarkers(c.Request.Context(), deps.ClipService, episodeID, minConfidence)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to list clips: %v", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, SkipMarkersResponse{
+			EpisodeID:     episodeID,
+			MinConfidence: minConfidence,
+			Markers:       markers,
+		})
+	}
+}
+
+// skipMarkers merges the episode's approved clips carrying one of
+// clips.skip_labels into markers, leaving out auto-labeled clips below
+// minConfidence
+func skipMarkers(ctx context.Context, clipService clips.Service, episodeID int64, minConfidence float64) ([]SkipMarker, error) {
+	approved, rejected := true, false
+	approvedClips, err := clipService.ListClips(ctx, clips.ListClipsFilters{
+		EpisodeID: &episodeID,
+		Approved:  &approved,
+		Rejected:  &rejected,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	skipLabels := make(map[string]bool)
+	for _, label := range viper.GetStringSlice("clips.skip_labels") {
+		skipLabels[label] = true
+	}
+
+	candidates := make([]*models.Clip, 0, len(approvedClips))
+	for _, clip := range approvedClips {
+		if !skipLabels[clip.Label] {
+			continue
+		}
+		if clip.LabelConfidence != nil && *clip.LabelConfidence < minConfidence {
+			continue
+		}
+		candidates = append(candidates, clip)
+	}
+	return mergeSkipMarkers(candidates, viper.GetFloat64("clips.skip_merge_gap")), nil
+}
+
+// mergeSkipMarkers sorts clips by start time and folds together any whose
+// ranges overlap or are separated by no more than gap seconds
+func mergeSkipMarkers(clipList []*models.Clip, gap float64) []SkipMarker {
+	sorted := make([]*models.Clip, 0, len(clipList))
+	for _, clip := range clipList {
+		if clip.OriginalEndTime > clip.OriginalStartTime {
+			sorted = append(sorted, clip)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OriginalStartTime < sorted[j].OriginalStartTime
+	})
+
+	markers := []SkipMarker{}
+	for _, clip := range sorted {
+		if n := len(markers); n > 0 && clip.OriginalStartTime <= markers[n-1].End+gap {
+			last := &markers[n-1]
+			if clip.OriginalEndTime > last.End {
+				last.End = clip.OriginalEndTime
+			}
+			last.ClipCount++
+			last.Labels = appendLabel(last.Labels, clip.Label)
+			if clip.LabelConfidence != nil && (last.Confidence == nil || *clip.LabelConfidence < *last.Confidence) {
+				confidence := *clip.LabelConfidence
+				last.Confidence = &confidence
+			}
+			continue
+		}
+
+		marker := SkipMarker{
+			Start:     clip.OriginalStartTime,
+			End:       clip.OriginalEndTime,
+			Labels:    []string{clip.Label},
+			ClipCount: 1,
+		}
+		if clip.LabelConfidence != nil {
+			confidence := *clip.LabelConfidence
+			marker.Confidence = &confidence
+		}
+		markers = append(markers, marker)
+	}
+	return markers
+}
+
+func appendLabel(labels []string, label string) []string {
+	for _, existing := range labels {
+		if existing == label {
+			return labels
+		}
+	}
+	return append(labels, label)
+}
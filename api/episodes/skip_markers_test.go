@@ -0,0 +1,121 @@
+package episodes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func skipClip(uuid string, start, end float64, label string, confidence *float64) *models.Clip {
+	return &models.Clip{
+		UUID:                  uuid,
+		PodcastIndexEpisodeID: 12345,
+		SourceEpisodeURL:      "https://example.com/ep.mp3",
+		OriginalStartTime:     start,
+		OriginalEndTime:       end,
+		Label:                 label,
+		LabelConfidence:       confidence,
+		Approved:              true,
+	}
+}
+
+func TestMergeSkipMarkers(t *testing.T) {
+	high, low := 0.9, 0.6
+
+	markers := mergeSkipMarkers([]*models.Clip{
+		skipClip("c", 100, 130, "advertisement", &high),
+		skipClip("a", 10, 40, "advertisement", &high),
+		skipClip("b", 35, 60, "sponsor", &low),
+		skipClip("d", 130.5, 150, "advertisement", nil),
+		skipClip("e", 200, 200, "advertisement", nil), // empty range is dropped
+	}, 1.0)
+
+	require.Len(t, markers, 2)
+
+	assert.Equal(t, 10.0, markers[0].Start)
+	assert.Equal(t, 60.0, markers[0].End)
+	assert.Equal(t, 2, markers[0].ClipCount)
+	assert.Equal(t, []string{"advertisement", "sponsor"}, markers[0].Labels)
+	require.NotNil(t, markers[0].Confidence)
+	assert.Equal(t, low, *markers[0].Confidence)
+
+	// Within the merge gap of the previous marker
+	assert.Equal(t, 100.0, markers[1].Start)
+	assert.Equal(t, 150.0, markers[1].End)
+	assert.Equal(t, 2, markers[1].ClipCount)
+
+	assert.Empty(t, mergeSkipMarkers(nil, 1.0))
+}
+
+func TestGetSkipMarkers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	viper.Set("clips.skip_labels", []string{"advertisement"})
+	viper.Set("clips.skip_merge_gap", 0.0)
+	defer viper.Reset()
+
+	db := setupTestDB(t)
+	deps := &types.Dependencies{ClipService: &testClipService{db: db}}
+
+	high, low := 0.95, 0.4
+	pending := skipClip("pending", 300, 330, "advertisement", &high)
+	pending.Approved = false
+	rejected := skipClip("rejected", 400, 430, "advertisement", &high)
+	rejected.Rejected = true
+	other := skipClip("other-episode", 10, 20, "advertisement", nil)
+	other.PodcastIndexEpisodeID = 999
+
+	for _, clip := range []*models.Clip{
+		skipClip("manual", 10, 40, "advertisement", nil),
+		skipClip("auto-high", 100, 130, "advertisement", &high),
+		skipClip("auto-low", 200, 230, "advertisement", &low),
+		skipClip("music", 500, 530, "music", &high),
+		pending, rejected, other,
+	} {
+		require.NoError(t, db.Create(clip).Error)
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/episodes/:id/skip-markers", GetSkipMarkers(deps))
+
+	get := func(query string) (*httptest.ResponseRecorder, SkipMarkersResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/episodes/12345/skip-markers"+query, nil)
+		router.ServeHTTP(w, req)
+		var response SkipMarkersResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response
+	}
+
+	t.Run("includes approved ad clips only", func(t *testing.T) {
+		w, response := get("")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, response.Markers, 3)
+		assert.Equal(t, 10.0, response.Markers[0].Start)
+		assert.Equal(t, 100.0, response.Markers[1].Start)
+		assert.Equal(t, 200.0, response.Markers[2].Start)
+	})
+
+	t.Run("confidence threshold keeps manual clips", func(t *testing.T) {
+		w, response := get("?min_confidence=0.5")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 0.5, response.MinConfidence)
+		require.Len(t, response.Markers, 2)
+		assert.Nil(t, response.Markers[0].Confidence)
+		assert.Equal(t, 100.0, response.Markers[1].Start)
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		w, _ := get("?min_confidence=1.5")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
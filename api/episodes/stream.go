@@ -0,0 +1,187 @@
+package episodes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	episodeService "github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/streamcache"
+)
+
+// Stream proxies an episode's audio or video, serving byte ranges from the segment cache
+// @Summary      Stream episode audio
+// @Description  Proxy the episode's audio with HTTP Range support. Fetched ranges are stored on disk in aligned chunks,
+// @Description  so seeking back over audio already played is served locally and concurrent requests for the same
+// @Description  chunk share one origin fetch. Only single ranges are honored; multi-range requests receive the full body.
+// @Description  If the origin does not support range requests, the client is redirected to the original audio URL.
+// @Description  With quality=low or quality=high, the lowest or highest bitrate among the enclosure and its alternate
+// @Description  enclosures is streamed; the enclosure counts as the highest when its bitrate can't be estimated.
+// @Tags         episodes
+// @Produce      audio/mpeg
+// @Produce      video/mp4
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        Range header string false "Byte range, e.g. bytes=0-1023"
+// @Param        quality query string false "Rendition to stream; defaults to the enclosure" Enums(low, high)
+// @Success      200 {file} binary "Full audio"
+// @Success      206 {file} binary "Requested byte range"
+// @Success      302 "Origin does not support ranges; redirect to the audio URL"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID or quality"
+// @Failure      404 {object} types.ErrorResponse "Episode not found"
+// @Failure      416 {object} types.ErrorResponse "Range not satisfiable"
+// @Failure      502 {object} types.ErrorResponse "Failed to fetch audio from origin"
+// @Router       /api/v1/episodes/{id}/stream [get]
+func Stream(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		podcastIndexID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+		quality := c.Query("quality")
+		if quality != "" && quality != models.QualityLow && quality != models.QualityHigh {
+			types.SendBadRequest(c, "quality must be low or high")
+			return
+		}
+
+		if deps.StreamCacheService == nil {
+			types.SendInternalError(c, "Audio streaming not available")
+			return
+		}
+
+		episode, err := deps.EpisodeService.GetEpisodeByPodcastIndexID(c.Request.Context(), podcastIndexID)
+		if err != nil {
+			if episodeService.IsNotFound(err) {
+				types.SendError(c, http.StatusNotFound, types.CodeEpisodeNotFound, "Episode not found")
+				return
+			}
+			types.SendServiceError(c, err, "Failed to fetch episode")
+			return
+		}
+		if episode.AudioURL == "" {
+			types.SendError(c, http.StatusNotFound, types.CodeEpisodeNoAudio, "Episode has no audio")
+			return
+		}
+
+		rendition := episode.RenditionFor(quality)
+		variant := ""
+		if rendition.Alternate {
+			variant = renditionVariant(rendition.URL)
+		} else {
+			// The enclosure may have been refused before; proxy what served it then
+			rendition.URL = episode.DownloadURL()
+		}
+
+		source, err := deps.StreamCacheService.Stat(c.Request.Context(), podcastIndexID, variant, rendition.URL)
+		if err != nil {
+			if errors.Is(err, streamcache.ErrRangeNotSupported) {
+				c.Redirect(http.StatusFound, rendition.URL)
+				return
+			}
+			log.Printf("[ERROR] Failed to stat stream for episode %d: %v", podcastIndexID, err)
+			c.JSON(http.StatusBadGateway, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeUpstreamError,
+				Message: "Failed to fetch audio from origin",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		start, end, partial, err := parseRange(c.GetHeader("Range"), source.Size)
+		if err != nil {
+			c.Header("Content-Range", fmt.Sprintf("bytes */%d", source.Size))
+			c.JSON(http.StatusRequestedRangeNotSatisfiable, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeRangeNotSatisfiable,
+				Message: "Range not satisfiable",
+			})
+			return
+		}
+
+		contentType := source.ContentType
+		if contentType == "" || contentType == "application/octet-stream" {
+			contentType = rendition.Type
+		}
+		if contentType == "" {
+			contentType = "audio/mpeg"
+		}
+
+		c.Header("Accept-Ranges", "bytes")
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Length", strconv.FormatInt(end-start+1, 10))
+		status := http.StatusOK
+		if partial {
+			status = http.StatusPartialContent
+			c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, source.Size))
+		}
+		c.Status(status)
+
+		if c.Request.Method == http.MethodHead {
+			return
+		}
+
+		if err := deps.StreamCacheService.WriteRange(c.Request.Context(), c.Writer, podcastIndexID, variant, rendition.URL, start, end); err != nil {
+			// Headers are already sent; the client sees a truncated body
+			log.Printf("[WARN] Stream for episode %d ended early: %v", podcastIndexID, err)
+		}
+	}
+}
+
+// renditionVariant names an alternate enclosure's stream cache entry after
+// its URL, so a feed replacing the file starts a fresh entry
+func renditionVariant(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:4])
+}
+
+// parseRange interprets a single-range Range header against the audio size. It
+// returns partial=false for a missing or multi-range header, which is served whole.
+func parseRange(header string, size int64) (start, end int64, partial bool, err error) {
+	if header == "" || !strings.HasPrefix(header, "bytes=") || strings.Contains(header, ",") {
+		return 0, size - 1, false, nil
+	}
+
+	spec := strings.TrimSpace(strings.TrimPrefix(header, "bytes="))
+	dash := strings.Index(spec, "-")
+	if dash < 0 {
+		return 0, 0, false, streamcache.ErrInvalidRange
+	}
+	first, last := spec[:dash], spec[dash+1:]
+
+	switch {
+	case first == "":
+		// Suffix range: the last N bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false, streamcache.ErrInvalidRange
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, size - 1, true, nil
+	default:
+		start, err = strconv.ParseInt(first, 10, 64)
+		if err != nil || start < 0 || start >= size {
+			return 0, 0, false, streamcache.ErrInvalidRange
+		}
+		end = size - 1
+		if last != "" {
+			end, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || end < start {
+				return 0, 0, false, streamcache.ErrInvalidRange
+			}
+			if end >= size {
+				end = size - 1
+			}
+		}
+		return start, end, true, nil
+	}
+}
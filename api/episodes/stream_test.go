@@ -0,0 +1,43 @@
+package episodes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		start   int64
+		end     int64
+		partial bool
+		wantErr bool
+	}{
+		{name: "no header", header: "", start: 0, end: 999},
+		{name: "closed range", header: "bytes=100-199", start: 100, end: 199, partial: true},
+		{name: "open range", header: "bytes=900-", start: 900, end: 999, partial: true},
+		{name: "suffix", header: "bytes=-100", start: 900, end: 999, partial: true},
+		{name: "suffix larger than file", header: "bytes=-5000", start: 0, end: 999, partial: true},
+		{name: "end clamped", header: "bytes=500-5000", start: 500, end: 999, partial: true},
+		{name: "multi-range served whole", header: "bytes=0-1,5-6", start: 0, end: 999},
+		{name: "start past end of file", header: "bytes=1000-", wantErr: true},
+		{name: "reversed", header: "bytes=200-100", wantErr: true},
+		{name: "garbage", header: "bytes=abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, partial, err := parseRange(tt.header, 1000)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+			assert.Equal(t, tt.partial, partial)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	eventsService "github.com/killallgit/player-api/internal/services/events"
+)
+
+// PostEventsRequest is a batch of playback events
+type PostEventsRequest struct {
+	Events []eventsService.Event `json:"events" binding:"required"`
+}
+
+// PostEventsResponse reports what happened to a batch
+type PostEventsResponse struct {
+	types.BaseResponse
+	Result *eventsService.IngestResult `json:"result"`
+}
+
+// PostEvents ingests a batch of client playback analytics
+// @Summary      Report playback events
+// @Description  Accepts a batch of playback events (play, pause, seek, complete, skip_ad) for podcast analytics
+// @Description  and listening stats. Events are appended as they arrive and deleted after events.retention;
+// @Description  events already older than that are dropped. Anonymous listeners are sampled per session_id
+// @Description  at events.sample_rate, and stored events are weighted so totals stay estimates of the full
+// @Description  traffic. Signed-in listeners are never sampled. A batch with any invalid event is refused
+// @Description  as a whole.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        request body PostEventsRequest true "Events in the order they happened"
+// @Success      200 {object} PostEventsResponse "Batch stored"
+// @Failure      400 {object} types.ErrorResponse "Invalid event or batch too large"
+// @Failure      500 {object} types.ErrorResponse "Failed to store events"
+// @Router       /api/v1/events [post]
+func PostEvents(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.EventsService == nil {
+			types.SendInternalError(c, "Events service not available")
+			return
+		}
+
+		var req PostEventsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid request format",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		result, err := deps.EventsService.Ingest(c.Request.Context(), c.GetString("user_id"), req.Events)
+		if errors.Is(err, eventsService.ErrInvalidEvent) || errors.Is(err, eventsService.ErrBatchTooLarge) {
+			types.SendBadRequest(c, err.Error())
+			return
+		}
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to store events: %v", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, PostEventsResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: fmt.Sprintf("Stored %d of %d events", result.Stored, result.Received),
+			},
+			Result: result,
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package events
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// RegisterRoutes registers client analytics routes
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// POST /api/v1/events - Batched playback analytics
+	router.POST("", PostEvents(deps))
+}
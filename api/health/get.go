@@ -6,15 +6,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/cache"
 )
 
 // Get handles health check requests
 // @Summary      Health check
-// @Description  Get the health status of the API server and database connection
+// @Description  Get the health status of the API server and database connection, and the response cache's
+// @Description  hit, miss and eviction counts since startup
 // @Tags         health
 // @Accept       json
 // @Produce      json
-// @Success      200 {object} object{status=string,timestamp=string,database=object} "Server health status"
+// @Success      200 {object} object{status=string,timestamp=string,database=object,cache=object} "Server health status"
 // @Router       /health [get]
 func Get(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -30,10 +32,32 @@ func Get(deps *types.Dependencies) gin.HandlerFunc {
 			response["database"] = gin.H{"status": "not configured"}
 		}
 
+		if deps != nil {
+			response["cache"] = getCacheStatus(deps)
+		}
+
 		c.JSON(http.StatusOK, response)
 	}
 }
 
+// getCacheStatus returns the response cache's statistics
+func getCacheStatus(deps *types.Dependencies) gin.H {
+	if deps.ResponseCache == nil {
+		return gin.H{"status": "disabled"}
+	}
+	provider, ok := deps.ResponseCache.(cache.StatsProvider)
+	if !ok {
+		return gin.H{"status": "enabled"}
+	}
+
+	stats := provider.Stats()
+	return gin.H{
+		"status":    "enabled",
+		"stats":     stats,
+		"hit_ratio": stats.HitRatio(),
+	}
+}
+
 // getDatabaseStatus returns the database connection status
 func getDatabaseStatus(deps *types.Dependencies) gin.H {
 	if deps.DB == nil || deps.DB.DB == nil {
@@ -0,0 +1,131 @@
+package jobs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	jobsService "github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// JobResponse wraps a job's public status
+type JobResponse struct {
+	types.BaseResponse
+	Job types.PublicJob `json:"job"`
+}
+
+// GetJob reports the status of a background job
+// @Summary      Get job status
+// @Description  Poll the status of a background job, such as the one behind a 202 response from waveform,
+// @Description  transcription or summary generation (its URL is in the Location header). Only non-sensitive
+// @Description  fields are returned. Jobs started on behalf of a user are visible to that user only.
+// @Tags         jobs
+// @Produce      json
+// @Param        id path int true "Job ID" minimum(1)
+// @Success      200 {object} JobResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid job ID"
+// @Failure      404 {object} types.ErrorResponse "Job not found"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/jobs/{id} [get]
+func GetJob(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobID, ok := types.ParseUintParam(c, "id")
+		if !ok {
+			return
+		}
+
+		if deps.JobService == nil {
+			types.SendInternalError(c, "Job service not available")
+			return
+		}
+
+		job, err := deps.JobService.GetJob(c.Request.Context(), jobID)
+		if errors.Is(err, jobsService.ErrJobNotFound) || (err == nil && !visibleTo(job, c.GetString("user_id"))) {
+			types.SendError(c, http.StatusNotFound, types.CodeJobNotFound, "Job not found")
+			return
+		}
+		if err != nil {
+			types.SendInternalError(c, "Failed to get job")
+			return
+		}
+
+		// Pollers must see progress, never a cached snapshot
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, JobResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Job:          types.NewPublicJob(job),
+		})
+	}
+}
+
+// CancelJob stops a background job
+// @Summary      Cancel a job
+// @Description  Stop a queued or running job. A queued job never starts; a running one has its download or ffmpeg
+// @Description  process stopped and ends in the cancelled state, with partial output discarded. Jobs waiting on it
+// @Description  fail. Users can cancel jobs started on their behalf; other jobs need the admin permission.
+// @Description  Cancelling a cancelled job is a no-op.
+// @Tags         jobs
+// @Produce      json
+// @Param        id path int true "Job ID" minimum(1)
+// @Success      200 {object} JobResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid job ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      403 {object} types.ErrorResponse "Admin permission required"
+// @Failure      404 {object} types.ErrorResponse "Job not found"
+// @Failure      409 {object} types.ErrorResponse "Job already finished"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/jobs/{id}/cancel [post]
+func CancelJob(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobID, ok := types.ParseUintParam(c, "id")
+		if !ok {
+			return
+		}
+
+		userID := c.GetString("user_id")
+		if userID == "" {
+			types.SendError(c, http.StatusUnauthorized, types.CodeUnauthorized, "Authentication required")
+			return
+		}
+
+		if deps.JobService == nil {
+			types.SendInternalError(c, "Job service not available")
+			return
+		}
+
+		ctx := c.Request.Context()
+		job, err := deps.JobService.GetJob(ctx, jobID)
+		if errors.Is(err, jobsService.ErrJobNotFound) || (err == nil && !visibleTo(job, userID)) {
+			types.SendError(c, http.StatusNotFound, types.CodeJobNotFound, "Job not found")
+			return
+		}
+		if err != nil {
+			types.SendInternalError(c, "Failed to get job")
+			return
+		}
+		if job.CreatedBy != userID && !types.HasPermission(c, types.AdminPermission) {
+			types.SendError(c, http.StatusForbidden, types.CodeForbidden, "Admin permission required to cancel system jobs")
+			return
+		}
+
+		job, err = deps.JobService.CancelJob(ctx, jobID)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to cancel job")
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, JobResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Job cancelled"},
+			Job:          types.NewPublicJob(job),
+		})
+	}
+}
+
+// visibleTo hides jobs run for another user (exports, account deletion) so
+// their existence isn't disclosed; system jobs are visible to everyone
+func visibleTo(job *models.Job, userID string) bool {
+	return job.CreatedBy == "" || job.CreatedBy == userID
+}
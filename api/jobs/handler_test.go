@@ -0,0 +1,124 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	jobsService "github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRouter(t *testing.T, userID string) (*gin.Engine, jobsService.Service) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Job{}, &models.JobDependency{}))
+	svc := jobsService.NewService(jobsService.NewRepository(db))
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+	})
+	RegisterRoutes(router.Group("/api/v1/jobs"), &types.Dependencies{JobService: svc})
+	return router, svc
+}
+
+func TestGetJob_PublicFieldsOnly(t *testing.T) {
+	router, svc := setupRouter(t, "")
+	job, err := svc.EnqueueJob(context.Background(), models.JobTypeWaveformGeneration,
+		models.JobPayload{"episode_id": 42, "audio_url": "https://cdn.example.com/private.mp3"})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, types.JobLocation(job.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.NotContains(t, w.Body.String(), "private.mp3")
+
+	var resp JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, job.ID, resp.Job.ID)
+	assert.Equal(t, "waveform_generation", resp.Job.Type)
+	assert.Equal(t, "pending", resp.Job.Status)
+}
+
+func TestGetJob_Visibility(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		viewer string
+		code   int
+	}{
+		{name: "owner", viewer: "user-1", code: http.StatusOK},
+		{name: "other user", viewer: "user-2", code: http.StatusNotFound},
+		{name: "anonymous", viewer: "", code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, svc := setupRouter(t, tt.viewer)
+			job, err := svc.EnqueueJob(ctx, models.JobTypeUserExport, models.JobPayload{"user_id": "user-1"}, jobsService.WithCreatedBy("user-1"))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, types.JobLocation(job.ID), nil))
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestGetJob_NotFoundAndInvalid(t *testing.T) {
+	router, _ := setupRouter(t, "")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/999", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCancelJob(t *testing.T) {
+	ctx := context.Background()
+
+	router, svc := setupRouter(t, "user-1")
+	export, err := svc.EnqueueJob(ctx, models.JobTypeUserExport, models.JobPayload{"user_id": "user-1"}, jobsService.WithCreatedBy("user-1"))
+	require.NoError(t, err)
+	system, err := svc.EnqueueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 42})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, types.JobLocation(export.ID)+"/cancel", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "cancelled", resp.Job.Status)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, types.JobLocation(system.ID)+"/cancel", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code, "system jobs need the admin permission")
+
+	// Someone else's job stays hidden
+	other, _ := setupRouter(t, "user-2")
+	w = httptest.NewRecorder()
+	other.ServeHTTP(w, httptest.NewRequest(http.MethodPost, types.JobLocation(export.ID)+"/cancel", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	anonymous, _ := setupRouter(t, "")
+	w = httptest.NewRecorder()
+	anonymous.ServeHTTP(w, httptest.NewRequest(http.MethodPost, types.JobLocation(system.ID)+"/cancel", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,15 @@
+package jobs
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// RegisterRoutes registers job status and cancellation routes
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// GET /api/v1/jobs/:id - Status of a background job named in a 202 Location header
+	router.GET("/:id", GetJob(deps))
+
+	// POST /api/v1/jobs/:id/cancel - Stop a queued or running job
+	router.POST("/:id/cancel", CancelJob(deps))
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"time"
+
+	"github.com/killallgit/player-api/api/middleware"
+	"github.com/spf13/viper"
+)
+
+// LiveSettings are the operational settings the middleware reads on every
+// request, so a config change can be applied without a restart
+type LiveSettings struct {
+	RateLimits       *RateLimits // Most routes
+	SearchRateLimits *RateLimits // Search, which calls Podcast Index on every cache miss
+	CacheTTLs        *middleware.CacheTTLs
+}
+
+// LiveSettingsFromViper loads the settings from the security.*rate_limit*
+// and cache.ttl_* keys
+func LiveSettingsFromViper() *LiveSettings {
+	live := &LiveSettings{
+		RateLimits:       &RateLimits{},
+		SearchRateLimits: &RateLimits{},
+		CacheTTLs:        &middleware.CacheTTLs{},
+	}
+	live.reloadRateLimits(viper.GetViper())
+	live.reloadCacheTTLs(viper.GetViper())
+	return live
+}
+
+// reloadRateLimits re-reads the rate limits from settings. Signed-in callers
+// are limited per user, scaled by their tier.
+func (l *LiveSettings) reloadRateLimits(settings *viper.Viper) {
+	tiers := RateLimitTiersFromViper(settings)
+	l.RateLimits.Set(settings.GetInt("security.rate_limit_rps"), settings.GetInt("security.rate_limit_burst"), tiers)
+	l.SearchRateLimits.Set(settings.GetInt("security.search_rate_limit_rps"), settings.GetInt("security.search_rate_limit_burst"), tiers)
+}
+
+// reloadCacheTTLs re-reads the response cache TTLs from settings, given in
+// minutes per path
+func (l *LiveSettings) reloadCacheTTLs(settings *viper.Viper) {
+	minutes := func(key string) time.Duration {
+		return time.Duration(settings.GetInt(key)) * time.Minute
+	}
+	l.CacheTTLs.Set(settings.GetDuration("cache.default_ttl"), map[string]time.Duration{
+		"/api/v1/search":     minutes("cache.ttl_search"),
+		"/api/v1/trending":   minutes("cache.ttl_trending"),
+		"/api/v1/podcasts":   minutes("cache.ttl_podcast"),
+		"/api/v1/episodes":   minutes("cache.ttl_episode"),
+		"/api/v1/categories": minutes("cache.ttl_categories"),
+	})
+}
@@ -0,0 +1,175 @@
+package me
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/userdata"
+	"github.com/spf13/viper"
+)
+
+// DeleteAccount schedules deletion of the authenticated user's data
+// @Summary      Delete account data
+// @Description  Schedule deletion of everything stored for the user: subscriptions, playback progress, listening
+// @Description  history and preferences are deleted; clips the user created are anonymized or deleted depending on
+// @Description  account_deletion.clip_policy. Deletion runs as a background job after a grace period
+// @Description  (account_deletion.grace_period) and can be cancelled until then with DELETE /api/v1/me/deletion.
+// @Description  Repeating the request while one is pending returns the existing schedule. The identity provider
+// @Description  account itself is not removed.
+// @Tags         me
+// @Produce      json
+// @Success      202 {object} types.AccountDeletionResponse "Deletion scheduled"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to schedule deletion"
+// @Router       /api/v1/me [delete]
+func DeleteAccount(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		if deps.UserDataService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Account deletion not available",
+			})
+			return
+		}
+
+		deletion, err := deps.UserDataService.ScheduleDeletion(c.Request.Context(), userID, viper.GetDuration("account_deletion.grace_period"))
+		if err != nil {
+			log.Printf("[ERROR] Failed to schedule account deletion for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to schedule deletion",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, accountDeletionResponse(deletion, "Account deletion scheduled"))
+	}
+}
+
+// GetAccountDeletion returns the user's pending or most recent deletion request
+// @Summary      Get account deletion status
+// @Description  Return the status of the user's account deletion request and when it will run.
+// @Tags         me
+// @Produce      json
+// @Success      200 {object} types.AccountDeletionResponse "Deletion status"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "No deletion requested"
+// @Router       /api/v1/me/deletion [get]
+func GetAccountDeletion(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		if deps.UserDataService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Account deletion not available",
+			})
+			return
+		}
+
+		deletion, err := deps.UserDataService.GetDeletion(c.Request.Context(), userID)
+		if err != nil {
+			if errors.Is(err, userdata.ErrDeletionNotFound) {
+				c.JSON(http.StatusNotFound, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeDeletionNotFound,
+					Message: "No deletion requested",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load deletion status",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, accountDeletionResponse(deletion, "Account deletion status"))
+	}
+}
+
+// CancelAccountDeletion cancels a deletion still within its grace period
+// @Summary      Cancel account deletion
+// @Description  Cancel a scheduled account deletion. Fails with 409 once the deletion job has been queued.
+// @Tags         me
+// @Produce      json
+// @Success      200 {object} types.AccountDeletionResponse "Deletion cancelled"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "No pending deletion"
+// @Failure      409 {object} types.ErrorResponse "Deletion already in progress"
+// @Router       /api/v1/me/deletion [delete]
+func CancelAccountDeletion(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		if deps.UserDataService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Account deletion not available",
+			})
+			return
+		}
+
+		deletion, err := deps.UserDataService.CancelDeletion(c.Request.Context(), userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, userdata.ErrDeletionNotFound):
+				c.JSON(http.StatusNotFound, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeDeletionNotFound,
+					Message: "No pending deletion",
+				})
+			case errors.Is(err, userdata.ErrDeletionInProgress):
+				c.JSON(http.StatusConflict, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeConflict,
+					Message: "Deletion already in progress and can no longer be cancelled",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInternal,
+					Message: "Failed to cancel deletion",
+					Details: err.Error(),
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, accountDeletionResponse(deletion, "Account deletion cancelled"))
+	}
+}
+
+func accountDeletionResponse(deletion *models.AccountDeletion, message string) types.AccountDeletionResponse {
+	return types.AccountDeletionResponse{
+		BaseResponse:   types.BaseResponse{Status: types.StatusOK, Message: message},
+		DeletionStatus: string(deletion.Status),
+		RequestedAt:    deletion.RequestedAt,
+		ScheduledFor:   deletion.ScheduledFor,
+		CompletedAt:    deletion.CompletedAt,
+		CancelledAt:    deletion.CancelledAt,
+	}
+}
@@ -0,0 +1,138 @@
+package me
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+)
+
+// defaultCalendarRange is how far a calendar reaches when 'to' is omitted
+const defaultCalendarRange = 30 * 24 * time.Hour
+
+// GetCalendar returns released and expected episodes of the user's subscriptions
+// @Summary      Release calendar
+// @Description  Return episodes of the user's subscriptions released in [from, to), and future releases expected
+// @Description  from each show's cadence (the median gap between its recent episodes). Shows with too few or too
+// @Description  irregular releases, or that have gone several of their intervals without one, predict nothing.
+// @Description  from and to take an RFC 3339 time or a YYYY-MM-DD date (UTC; a 'to' date is included). By default
+// @Description  the calendar starts today and covers 30 days; it may cover at most 92 days.
+// @Tags         me
+// @Produce      json
+// @Param        from query string false "Start of the range (RFC 3339 or YYYY-MM-DD)"
+// @Param        to query string false "End of the range (RFC 3339, exclusive, or YYYY-MM-DD, inclusive)"
+// @Param        folder query string false "Only subscriptions in this folder"
+// @Param        tag query string false "Only subscriptions with this tag (case-insensitive)"
+// @Success      200 {object} types.CalendarResponse "Releases by date and show cadences"
+// @Failure      400 {object} types.ErrorResponse "Invalid from or to, or range too long"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to build calendar"
+// @Router       /api/v1/me/calendar [get]
+func GetCalendar(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSubscriptions(c, deps)
+		if !ok {
+			return
+		}
+
+		now := time.Now().UTC()
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if raw := c.Query("from"); raw != "" {
+			parsed, err := parseCalendarTime(raw, false)
+			if err != nil {
+				sendCalendarRangeError(c, "from must be an RFC 3339 time or a YYYY-MM-DD date")
+				return
+			}
+			from = parsed
+		}
+		to := from.Add(defaultCalendarRange)
+		if raw := c.Query("to"); raw != "" {
+			parsed, err := parseCalendarTime(raw, true)
+			if err != nil {
+				sendCalendarRangeError(c, "to must be an RFC 3339 time or a YYYY-MM-DD date")
+				return
+			}
+			to = parsed
+		}
+
+		calendar, err := deps.SubscriptionService.Calendar(c.Request.Context(), userID, subscriptionFilter(c), from, to)
+		if errors.Is(err, subscriptions.ErrInvalidCalendarRange) {
+			sendCalendarRangeError(c, "to must be after from, at most 92 days later")
+			return
+		}
+		if err != nil {
+			log.Printf("[ERROR] Failed to build calendar for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to build calendar",
+			})
+			return
+		}
+
+		block := types.Blocklist(c, deps)
+		response := types.CalendarResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			From:         from,
+			To:           to,
+			Entries:      make([]types.CalendarEntry, 0, len(calendar.Entries)),
+			Cadences:     make([]types.PodcastCadence, 0, len(calendar.Cadences)),
+		}
+		for _, entry := range calendar.Entries {
+			if entry.Episode != nil && block.Blocks(types.EpisodeSubject(entry.Episode)) {
+				continue
+			}
+			item := types.CalendarEntry{Date: entry.At, Expected: entry.Episode == nil}
+			if entry.Podcast != nil {
+				item.PodcastID = entry.Podcast.PodcastIndexID
+				item.PodcastTitle = entry.Podcast.Title
+				item.Image = entry.Podcast.Image
+			}
+			if entry.Episode != nil {
+				item.Episode = types.FromModelEpisode(entry.Episode)
+			}
+			response.Entries = append(response.Entries, item)
+		}
+		response.Message = fmt.Sprintf("Calendar has %d releases", len(response.Entries))
+		for _, show := range calendar.Cadences {
+			response.Cadences = append(response.Cadences, types.PodcastCadence{
+				PodcastID:       show.Podcast.PodcastIndexID,
+				PodcastTitle:    show.Podcast.Title,
+				IntervalSeconds: int64(show.Interval / time.Second),
+				LastRelease:     show.LastRelease,
+			})
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// parseCalendarTime parses an RFC 3339 time or a UTC date. A date that ends
+// the range includes the whole day, so it resolves to the following midnight.
+func parseCalendarTime(raw string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func sendCalendarRangeError(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, types.ErrorResponse{
+		Status:  types.StatusError,
+		Code:    types.CodeInvalidRequest,
+		Message: message,
+	})
+}
@@ -0,0 +1,216 @@
+package me
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/userdata"
+	"github.com/spf13/viper"
+)
+
+// PostExport starts a personal data export for the authenticated user
+// @Summary      Request a data export
+// @Description  Queue a background job that assembles the user's subscriptions, playback progress (resume positions),
+// @Description  listening history, preferences and created clips into a zip of JSON files. Poll the returned job with
+// @Description  GET /api/v1/me/export/{jobId}; once completed it includes a signed download URL that expires after
+// @Description  export.url_ttl.
+// @Tags         me
+// @Produce      json
+// @Success      202 {object} types.DataExportResponse "Export queued"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to queue export"
+// @Failure      503 {object} types.ErrorResponse "Job queue is full, see Retry-After"
+// @Router       /api/v1/me/export [post]
+func PostExport(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		if deps.JobService == nil || deps.UserDataService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Data export not available",
+			})
+			return
+		}
+
+		job, err := deps.JobService.EnqueueJob(
+			c.Request.Context(),
+			models.JobTypeUserExport,
+			models.JobPayload{"user_id": userID},
+			jobs.WithCreatedBy(userID),
+		)
+		if err != nil {
+			log.Printf("[ERROR] Failed to enqueue export for user %s: %v", userID, err)
+			types.SendServiceError(c, err, "Failed to queue export")
+			return
+		}
+
+		types.SetJobLocation(c, job.ID)
+		c.JSON(http.StatusAccepted, types.DataExportResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Export queued"},
+			JobID:        job.ID,
+			JobStatus:    string(job.Status),
+		})
+	}
+}
+
+// GetExport reports the status of one of the user's data exports
+// @Summary      Get data export status
+// @Description  Return the status of a data export job. When completed, the response carries a freshly signed
+// @Description  download URL; calling this endpoint again issues a new link.
+// @Tags         me
+// @Produce      json
+// @Param        jobId path int true "Export job ID"
+// @Success      200 {object} types.DataExportResponse "Export status"
+// @Failure      400 {object} types.ErrorResponse "Invalid job ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "Export not found"
+// @Router       /api/v1/me/export/{jobId} [get]
+func GetExport(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid job ID",
+			})
+			return
+		}
+
+		if deps.JobService == nil || deps.UserDataService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Data export not available",
+			})
+			return
+		}
+
+		job, err := deps.JobService.GetJob(c.Request.Context(), uint(jobID))
+		if err != nil && !errors.Is(err, jobs.ErrJobNotFound) {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load export",
+				Details: err.Error(),
+			})
+			return
+		}
+		// Other users' exports are reported as missing rather than forbidden
+		if job == nil || job.Type != models.JobTypeUserExport || job.CreatedBy != userID {
+			c.JSON(http.StatusNotFound, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeExportNotFound,
+				Message: "Export not found",
+			})
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+
+		response := types.DataExportResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			JobID:        job.ID,
+			JobStatus:    string(job.Status),
+			Progress:     job.Progress,
+			Error:        job.Error,
+		}
+
+		if job.Status != models.JobStatusCompleted {
+			response.Message = "Export is not ready yet"
+			c.JSON(http.StatusOK, response)
+			return
+		}
+
+		expires, signature := deps.UserDataService.SignDownload(job.ID, viper.GetDuration("export.url_ttl"))
+		response.Message = "Export is ready"
+		response.DownloadURL = fmt.Sprintf("/api/v1/exports/%d/download?expires=%d&signature=%s", job.ID, expires.Unix(), signature)
+		response.ExpiresAt = &expires
+		if size, ok := job.Result["size_bytes"].(float64); ok {
+			response.SizeBytes = int64(size)
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// DownloadExport serves a finished export archive to the holder of a signed link.
+// It is registered outside the authenticated group so the link works in a browser.
+// @Summary      Download a data export
+// @Description  Stream the zip archive for a completed export. Requires the expires and signature parameters from
+// @Description  the download URL returned by GET /api/v1/me/export/{jobId}.
+// @Tags         me
+// @Produce      application/zip
+// @Param        jobId path int true "Export job ID"
+// @Param        expires query int true "Link expiry (Unix seconds)"
+// @Param        signature query string true "Link signature"
+// @Success      200 {file} binary "Export archive"
+// @Failure      403 {object} types.ErrorResponse "Invalid or expired link"
+// @Failure      404 {object} types.ErrorResponse "Export not found"
+// @Router       /api/v1/exports/{jobId}/download [get]
+func DownloadExport(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusNotFound, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeExportNotFound,
+				Message: "Export not found",
+			})
+			return
+		}
+		expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+		if err != nil || deps.UserDataService == nil {
+			c.JSON(http.StatusForbidden, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeForbidden,
+				Message: "Invalid download link",
+			})
+			return
+		}
+
+		if err := deps.UserDataService.VerifyDownload(uint(jobID), expires, c.Query("signature")); err != nil {
+			message, code := "Invalid download link", types.CodeInvalidLinkSignature
+			if errors.Is(err, userdata.ErrLinkExpired) {
+				message, code = "Download link has expired", types.CodeLinkExpired
+			}
+			c.JSON(http.StatusForbidden, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    code,
+				Message: message,
+			})
+			return
+		}
+
+		path := deps.UserDataService.ArchivePath(uint(jobID))
+		if _, err := os.Stat(path); err != nil {
+			c.JSON(http.StatusNotFound, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeExportNotFound,
+				Message: "Export not found",
+			})
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.FileAttachment(path, fmt.Sprintf("killall-export-%d.zip", jobID))
+	}
+}
@@ -0,0 +1,22 @@
+package me
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// requireUser returns the authenticated user's ID, writing a 401 if there is none
+func requireUser(c *gin.Context) (string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeUnauthorized,
+			Message: "Authentication required",
+		})
+		return "", false
+	}
+	return userID, true
+}
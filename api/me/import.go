@@ -0,0 +1,202 @@
+package me
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/imports"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// PostImport starts importing another app's export for the authenticated user
+// @Summary      Import subscriptions and listening history
+// @Description  Upload an export from another podcast app, either as the raw request body or as the "file" field of
+// @Description  a multipart form. Accepted formats are OPML (Apple Podcasts, Pocket Casts, Overcast's extended OPML
+// @Description  with episode progress), CSV with a header row, and JSON ({"subscriptions": [...], "history": [...]}).
+// @Description  The format is detected from the file name, content type or content unless given with ?format=.
+// @Description  The file is checked right away; podcasts are then matched to Podcast Index by feed URL or iTunes ID
+// @Description  in a background job, which subscribes the user and records playback positions. Progress the user
+// @Description  already has that is newer than the file's is kept. Poll GET /api/v1/me/import/{jobId} for the result.
+// @Tags         me
+// @Accept       xml
+// @Accept       plain
+// @Accept       json
+// @Accept       mpfd
+// @Produce      json
+// @Param        format query string false "File format" Enums(opml, csv, json)
+// @Param        file formData file false "Export file, for multipart uploads"
+// @Success      202 {object} types.LibraryImportResponse "Import queued"
+// @Failure      400 {object} types.ErrorResponse "Unreadable file or unsupported format"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      413 {object} types.ErrorResponse "File too large"
+// @Failure      422 {object} types.ErrorResponse "No podcasts in the file"
+// @Failure      500 {object} types.ErrorResponse "Failed to queue import"
+// @Failure      503 {object} types.ErrorResponse "Job queue is full, see Retry-After"
+// @Router       /api/v1/me/import [post]
+func PostImport(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		if deps.JobService == nil || deps.ImportService == nil {
+			types.SendError(c, http.StatusInternalServerError, types.CodeInternal, "Import not available")
+			return
+		}
+
+		data, filename, contentType, err := readImportFile(c)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				types.SendError(c, http.StatusRequestEntityTooLarge, types.CodePayloadTooLarge, "Import file is too large")
+				return
+			}
+			types.SendError(c, http.StatusBadRequest, types.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		format := imports.Format(strings.ToLower(c.Query("format")))
+		if format == "" {
+			format = imports.DetectFormat(filename, contentType, data)
+		}
+		library, err := imports.Parse(format, bytes.NewReader(data))
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to read import file")
+			return
+		}
+
+		job, err := deps.JobService.EnqueueJob(
+			c.Request.Context(),
+			models.JobTypeLibraryImport,
+			models.JobPayload{"user_id": userID, "format": string(format), "library": library},
+			jobs.WithCreatedBy(userID),
+		)
+		if err != nil {
+			log.Printf("[ERROR] Failed to enqueue library import for user %s: %v", userID, err)
+			types.SendServiceError(c, err, "Failed to queue import")
+			return
+		}
+
+		types.SetJobLocation(c, job.ID)
+		c.JSON(http.StatusAccepted, types.LibraryImportResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Import queued"},
+			JobID:        job.ID,
+			JobStatus:    string(job.Status),
+			Format:       string(format),
+			Feeds:        len(library.Feeds),
+			Plays:        len(library.Plays),
+		})
+	}
+}
+
+// GetImport reports the status of one of the user's imports
+// @Summary      Get import status
+// @Description  Return the status of a library import job. Once completed, the result counts the new subscriptions
+// @Description  and imported playback positions and lists entries that couldn't be matched to Podcast Index.
+// @Tags         me
+// @Produce      json
+// @Param        jobId path int true "Import job ID"
+// @Success      200 {object} types.LibraryImportResponse "Import status"
+// @Failure      400 {object} types.ErrorResponse "Invalid job ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "Import not found"
+// @Router       /api/v1/me/import/{jobId} [get]
+func GetImport(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+		if err != nil {
+			types.SendError(c, http.StatusBadRequest, types.CodeInvalidRequest, "Invalid job ID")
+			return
+		}
+
+		if deps.JobService == nil {
+			types.SendError(c, http.StatusInternalServerError, types.CodeInternal, "Import not available")
+			return
+		}
+
+		job, err := deps.JobService.GetJob(c.Request.Context(), uint(jobID))
+		if err != nil && !errors.Is(err, jobs.ErrJobNotFound) {
+			types.SendError(c, http.StatusInternalServerError, types.CodeInternal, "Failed to load import")
+			return
+		}
+		// Other users' imports are reported as missing rather than forbidden
+		if job == nil || job.Type != models.JobTypeLibraryImport || job.CreatedBy != userID {
+			types.SendError(c, http.StatusNotFound, types.CodeImportNotFound, "Import not found")
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+
+		response := types.LibraryImportResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Import is not finished yet"},
+			JobID:        job.ID,
+			JobStatus:    string(job.Status),
+			Progress:     job.Progress,
+			Error:        job.Error,
+		}
+		response.Format, _ = job.Payload["format"].(string)
+		if library, ok := job.Payload["library"].(map[string]interface{}); ok {
+			feeds, _ := library["feeds"].([]interface{})
+			plays, _ := library["plays"].([]interface{})
+			response.Feeds, response.Plays = len(feeds), len(plays)
+		}
+
+		if job.Status == models.JobStatusCompleted {
+			var result imports.Result
+			if encoded, err := json.Marshal(job.Result); err == nil && json.Unmarshal(encoded, &result) == nil {
+				response.Result = &result
+				response.Feeds, response.Plays = result.Feeds, result.Plays
+			}
+			response.Message = "Import finished"
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// readImportFile returns the uploaded file with its name and content type,
+// from the "file" field of a multipart form or else the raw body
+func readImportFile(c *gin.Context) ([]byte, string, string, error) {
+	if mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type")); mediaType == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, "", "", err
+			}
+			return nil, "", "", errors.New("multipart upload must include a \"file\" field")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", "", err
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		return data, header.Filename, header.Header.Get("Content-Type"), err
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if len(data) == 0 {
+		return nil, "", "", errors.New("request body must contain the export file")
+	}
+	return data, c.Query("filename"), c.GetHeader("Content-Type"), nil
+}
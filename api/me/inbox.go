@@ -0,0 +1,196 @@
+package me
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+)
+
+// GetInbox returns the user's home feed of new episodes
+// @Summary      Inbox
+// @Description  Return recent episodes from all of the user's subscriptions merged newest first, each with whether
+// @Description  the user has read it. Episodes published longer ago than inbox.max_age (30 days by default) drop
+// @Description  out. Pass 'unread=true' for unread episodes only, and 'folder' and/or 'tag' to build the inbox from
+// @Description  only some subscriptions. unread_count always covers the whole inbox.
+// @Tags         me
+// @Produce      json
+// @Param        unread query bool false "Only unread episodes" default(false)
+// @Param        folder query string false "Only subscriptions in this folder"
+// @Param        tag query string false "Only subscriptions with this tag (case-insensitive)"
+// @Param        limit query int false "Maximum episodes (1-200)" default(50)
+// @Param        offset query int false "Episodes to skip" default(0)
+// @Success      200 {object} types.InboxResponse "Inbox page and unread count"
+// @Failure      400 {object} types.ErrorResponse "Invalid limit or offset"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to load inbox"
+// @Router       /api/v1/me/inbox [get]
+func GetInbox(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSubscriptions(c, deps)
+		if !ok {
+			return
+		}
+		limit, offset, ok := episodePage(c)
+		if !ok {
+			return
+		}
+
+		inbox, err := deps.SubscriptionService.Inbox(c.Request.Context(), userID, subscriptions.InboxQuery{
+			Filter:     subscriptionFilter(c),
+			UnreadOnly: c.Query("unread") == "true",
+			Limit:      limit,
+			Offset:     offset,
+		})
+		if err != nil {
+			log.Printf("[ERROR] Failed to load inbox for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load inbox",
+			})
+			return
+		}
+
+		// Enclosures can be hosted on a blocked domain
+		block := types.Blocklist(c, deps)
+		items := inbox.Items[:0]
+		for _, item := range inbox.Items {
+			if !block.Blocks(types.EpisodeSubject(&item.Episode)) {
+				items = append(items, item)
+			}
+		}
+		episodes := make([]models.Episode, len(items))
+		for i := range items {
+			episodes[i] = items[i].Episode
+		}
+
+		response := types.InboxResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: fmt.Sprintf("Fetched %d inbox episodes", len(items)),
+			},
+			Episodes:    make([]types.InboxEpisode, 0, len(items)),
+			Count:       len(items),
+			Total:       inbox.Total,
+			Offset:      offset,
+			UnreadCount: inbox.Unread,
+		}
+		for i, episode := range toFeedEpisodes(c, deps, episodes) {
+			response.Episodes = append(response.Episodes, types.InboxEpisode{
+				Episode: episode,
+				Read:    items[i].ReadAt != nil,
+				ReadAt:  items[i].ReadAt,
+			})
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// MarkInboxRead marks inbox episodes read
+// @Summary      Mark inbox episodes read
+// @Description  Mark the given episodes read, or every unread episode in the inbox when 'episode_ids' is empty or
+// @Description  omitted. IDs of episodes that aren't from the user's subscriptions or are already read are ignored.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Param        request body types.InboxReadRequest false "Podcast Index episode IDs to mark read"
+// @Success      200 {object} types.InboxReadResponse "Number marked read and remaining unread count"
+// @Failure      400 {object} types.ErrorResponse "Invalid request body"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to update inbox"
+// @Router       /api/v1/me/inbox/read [post]
+func MarkInboxRead(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSubscriptions(c, deps)
+		if !ok {
+			return
+		}
+
+		var req types.InboxReadRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: "Invalid request body",
+					Details: err.Error(),
+				})
+				return
+			}
+		}
+
+		updated, err := deps.SubscriptionService.MarkRead(c.Request.Context(), userID, req.EpisodeIDs)
+		sendInboxUpdate(c, deps, userID, updated, err, "Episodes marked read")
+	}
+}
+
+// MarkInboxUnread marks inbox episodes unread again
+// @Summary      Mark inbox episodes unread
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Param        request body types.InboxReadRequest true "Podcast Index episode IDs to mark unread"
+// @Success      200 {object} types.InboxReadResponse "Number marked unread and unread count"
+// @Failure      400 {object} types.ErrorResponse "Invalid request body or no episode IDs"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to update inbox"
+// @Router       /api/v1/me/inbox/unread [post]
+func MarkInboxUnread(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSubscriptions(c, deps)
+		if !ok {
+			return
+		}
+
+		var req types.InboxReadRequest
+		if err := c.ShouldBindJSON(&req); err != nil || len(req.EpisodeIDs) == 0 {
+			response := types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "episode_ids is required",
+			}
+			if err != nil {
+				response.Message = "Invalid request body"
+				response.Details = err.Error()
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		updated, err := deps.SubscriptionService.MarkUnread(c.Request.Context(), userID, req.EpisodeIDs)
+		sendInboxUpdate(c, deps, userID, updated, err, "Episodes marked unread")
+	}
+}
+
+// sendInboxUpdate writes the result of a read state change with the new unread count
+func sendInboxUpdate(c *gin.Context, deps *types.Dependencies, userID string, updated int64, err error, message string) {
+	if err != nil {
+		log.Printf("[ERROR] Failed to update inbox for user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInternal,
+			Message: "Failed to update inbox",
+		})
+		return
+	}
+	unread, err := deps.SubscriptionService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("[WARN] Failed to count unread inbox episodes for user %s: %v", userID, err)
+	}
+
+	c.JSON(http.StatusOK, types.InboxReadResponse{
+		BaseResponse: types.BaseResponse{
+			Status:  types.StatusOK,
+			Message: message,
+		},
+		Updated:     updated,
+		UnreadCount: unread,
+	})
+}
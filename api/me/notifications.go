@@ -0,0 +1,226 @@
+package me
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/notifications"
+)
+
+// GetNotifications returns the user's notification feed
+// @Summary      List notifications
+// @Description  Return the authenticated user's notifications, newest first: new episodes of subscribed podcasts,
+// @Description  completed jobs they started and finished data exports. Page with 'before' using 'next_before' from
+// @Description  the previous response. Notifications are deleted after notifications.retention.
+// @Tags         me
+// @Produce      json
+// @Param        unread query bool false "Only unread notifications" default(false)
+// @Param        limit query int false "Page size (1-200)" default(50)
+// @Param        before query int false "Only notifications with an ID lower than this"
+// @Success      200 {object} types.NotificationsResponse "Notifications and unread count"
+// @Failure      400 {object} types.ErrorResponse "Invalid limit or cursor"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to load notifications"
+// @Router       /api/v1/me/notifications [get]
+func GetNotifications(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		if deps.NotificationService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Notifications not available",
+			})
+			return
+		}
+
+		opts := notifications.ListOptions{UnreadOnly: c.Query("unread") == "true"}
+		if raw := c.Query("limit"); raw != "" {
+			limit, err := strconv.Atoi(raw)
+			if err != nil || limit < 1 || limit > notifications.MaxListLimit {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: "limit must be between 1 and 200",
+				})
+				return
+			}
+			opts.Limit = limit
+		}
+		if raw := c.Query("before"); raw != "" {
+			before, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: "before must be a notification ID",
+				})
+				return
+			}
+			opts.BeforeID = uint(before)
+		}
+
+		ctx := c.Request.Context()
+		items, err := deps.NotificationService.List(ctx, userID, opts)
+		if err != nil {
+			log.Printf("[ERROR] Failed to list notifications for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load notifications",
+			})
+			return
+		}
+		unread, err := deps.NotificationService.UnreadCount(ctx, userID)
+		if err != nil {
+			log.Printf("[ERROR] Failed to count unread notifications for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load notifications",
+			})
+			return
+		}
+
+		limit := opts.Limit
+		if limit == 0 {
+			limit = notifications.DefaultListLimit
+		}
+
+		response := types.NotificationsResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Notifications retrieved",
+			},
+			Notifications: make([]types.Notification, 0, len(items)),
+			UnreadCount:   unread,
+		}
+		for i := range items {
+			response.Notifications = append(response.Notifications, toNotification(&items[i]))
+		}
+		if len(items) == limit {
+			response.NextBefore = items[len(items)-1].ID
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// MarkNotificationsRead marks notifications as read
+// @Summary      Mark notifications read
+// @Description  Mark the given notifications read, or every unread notification when 'ids' is empty or omitted.
+// @Description  IDs that belong to other users or are already read are ignored.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Param        request body types.NotificationsReadRequest false "Notifications to mark read"
+// @Success      200 {object} types.NotificationsReadResponse "Number marked read and remaining unread count"
+// @Failure      400 {object} types.ErrorResponse "Invalid request body"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to update notifications"
+// @Router       /api/v1/me/notifications/read [post]
+func MarkNotificationsRead(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req types.NotificationsReadRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: "Invalid request body",
+					Details: err.Error(),
+				})
+				return
+			}
+		}
+		markRead(c, deps, req.IDs)
+	}
+}
+
+// MarkNotificationRead marks a single notification as read
+// @Summary      Mark a notification read
+// @Tags         me
+// @Produce      json
+// @Param        id path int true "Notification ID"
+// @Success      200 {object} types.NotificationsReadResponse "Number marked read (0 if already read) and unread count"
+// @Failure      400 {object} types.ErrorResponse "Invalid notification ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to update notifications"
+// @Router       /api/v1/me/notifications/{id}/read [post]
+func MarkNotificationRead(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid notification ID",
+			})
+			return
+		}
+		markRead(c, deps, []uint{uint(id)})
+	}
+}
+
+// markRead applies a mark-read request for the authenticated user and writes the response
+func markRead(c *gin.Context, deps *types.Dependencies, ids []uint) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return
+	}
+
+	if deps.NotificationService == nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInternal,
+			Message: "Notifications not available",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	updated, err := deps.NotificationService.MarkRead(ctx, userID, ids)
+	if err != nil {
+		log.Printf("[ERROR] Failed to mark notifications read for user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInternal,
+			Message: "Failed to update notifications",
+		})
+		return
+	}
+	unread, err := deps.NotificationService.UnreadCount(ctx, userID)
+	if err != nil {
+		log.Printf("[WARN] Failed to count unread notifications for user %s: %v", userID, err)
+	}
+
+	c.JSON(http.StatusOK, types.NotificationsReadResponse{
+		BaseResponse: types.BaseResponse{
+			Status:  types.StatusOK,
+			Message: "Notifications marked read",
+		},
+		Updated:     updated,
+		UnreadCount: unread,
+	})
+}
+
+func toNotification(n *models.Notification) types.Notification {
+	return types.Notification{
+		ID:        n.ID,
+		Type:      string(n.Type),
+		Title:     n.Title,
+		Body:      n.Body,
+		Data:      n.Data,
+		CreatedAt: n.CreatedAt,
+		ReadAt:    n.ReadAt,
+	}
+}
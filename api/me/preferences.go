@@ -0,0 +1,174 @@
+package me
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	preferencesService "github.com/killallgit/player-api/internal/services/preferences"
+)
+
+// GetPreferences returns the authenticated user's preferences
+// @Summary      Get user preferences
+// @Description  Return the authenticated user's playback and display settings. Users without stored settings
+// @Description  receive the defaults (1.0x speed, 30s forward, 15s back, system theme).
+// @Tags         me
+// @Produce      json
+// @Success      200 {object} types.PreferencesResponse "Current preferences"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to load preferences"
+// @Router       /api/v1/me/preferences [get]
+func GetPreferences(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		if deps.PreferencesService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Preferences service not available",
+			})
+			return
+		}
+
+		prefs, err := deps.PreferencesService.Get(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load preferences",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		sendPreferences(c, prefs, "Fetched preferences")
+	}
+}
+
+// PutPreferences updates the authenticated user's preferences
+// @Summary      Update user preferences
+// @Description  Apply a partial update to the authenticated user's settings. Known keys are validated:
+// @Description  playback_speed 0.5-3.0, skip_forward_seconds and skip_back_seconds 1-300, theme one of
+// @Description  system/light/dark. languages (up to 10 codes such as "en" or "pt-br") and excluded_categories
+// @Description  (up to 25) replace the stored lists and filter search and trending results. Anything else belongs
+// @Description  in 'extras', which is merged key by key (null removes a key) and limited to 16KB. Unknown top-level
+// @Description  keys are rejected.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Param        request body types.PreferencesRequest true "Fields to change"
+// @Success      200 {object} types.PreferencesResponse "Updated preferences"
+// @Failure      400 {object} types.ErrorResponse "Invalid preference value or unknown key"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to save preferences"
+// @Router       /api/v1/me/preferences [put]
+func PutPreferences(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Failed to read request body",
+			})
+			return
+		}
+
+		var req types.PreferencesRequest
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid request format",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		if deps.PreferencesService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Preferences service not available",
+			})
+			return
+		}
+
+		prefs, err := deps.PreferencesService.Update(c.Request.Context(), userID, preferencesService.Update{
+			PlaybackSpeed:      req.PlaybackSpeed,
+			SkipForwardSeconds: req.SkipForwardSeconds,
+			SkipBackSeconds:    req.SkipBackSeconds,
+			Theme:              req.Theme,
+			Languages:          req.Languages,
+			ExcludedCategories: req.ExcludedCategories,
+			Extras:             req.Extras,
+		})
+		if err != nil {
+			if errors.Is(err, preferencesService.ErrInvalidPreference) {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to save preferences",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		sendPreferences(c, prefs, "Updated preferences")
+	}
+}
+
+func sendPreferences(c *gin.Context, prefs *models.UserPreferences, message string) {
+	extras, err := prefs.Extras()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInternal,
+			Message: "Failed to decode stored extras",
+		})
+		return
+	}
+
+	response := types.PreferencesResponse{
+		BaseResponse: types.BaseResponse{
+			Status:  types.StatusOK,
+			Message: message,
+		},
+		PlaybackSpeed:      prefs.PlaybackSpeed,
+		SkipForwardSeconds: prefs.SkipForwardSeconds,
+		SkipBackSeconds:    prefs.SkipBackSeconds,
+		Theme:              prefs.Theme,
+		Languages:          prefs.LanguageList(),
+		ExcludedCategories: prefs.ExcludedCategoryList(),
+		Extras:             extras,
+	}
+	if !prefs.UpdatedAt.IsZero() {
+		response.UpdatedAt = &prefs.UpdatedAt
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,193 @@
+package me
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/autoplay"
+)
+
+// GetQueueNext returns the episode to play after the given one
+// @Summary      Next episode to autoplay
+// @Description  Evaluate the user's autoplay rules after an episode and return the episode to play next, so every
+// @Description  client continues playback the same way. Rules are tried in order; the first that finds an unplayed
+// @Description  episode decides. Pass the Podcast Index ID of the episode that just played as 'episode_id', and
+// @Description  'completed' to say whether it was played to the end (otherwise the stored progress decides). The
+// @Description  episode is null when no rule finds one or a stop rule applies.
+// @Tags         me
+// @Produce      json
+// @Param        episode_id query int false "Podcast Index ID of the episode that just played"
+// @Param        completed query bool false "Whether that episode was played to the end"
+// @Success      200 {object} types.QueueNextResponse "Next episode, or null"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to evaluate autoplay rules"
+// @Router       /api/v1/me/queue/next [get]
+func GetQueueNext(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireAutoplay(c, deps)
+		if !ok {
+			return
+		}
+
+		query := autoplay.NextQuery{}
+		if raw := c.Query("episode_id"); raw != "" {
+			episodeID, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || episodeID <= 0 {
+				types.SendError(c, http.StatusBadRequest, types.CodeInvalidRequest, "Invalid episode ID")
+				return
+			}
+			query.EpisodeID = episodeID
+		}
+		if raw := c.Query("completed"); raw != "" {
+			completed, err := strconv.ParseBool(raw)
+			if err != nil {
+				types.SendError(c, http.StatusBadRequest, types.CodeInvalidRequest, "completed must be true or false")
+				return
+			}
+			query.Finished = &completed
+		}
+		// Enclosures can be hosted on a blocked domain
+		block := types.Blocklist(c, deps)
+		query.Skip = func(episode *models.Episode) bool { return block.Blocks(types.EpisodeSubject(episode)) }
+
+		decision, err := deps.AutoplayService.Next(c.Request.Context(), userID, query)
+		if err != nil {
+			log.Printf("[ERROR] Failed to evaluate autoplay rules for user %s: %v", userID, err)
+			types.SendError(c, http.StatusInternalServerError, types.CodeInternal, "Failed to evaluate autoplay rules")
+			return
+		}
+
+		response := types.QueueNextResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Nothing to play next"},
+			Rule:         decision.Rule,
+		}
+		if decision.Rule != nil {
+			response.RuleIndex = &decision.RuleIndex
+		}
+		if decision.Episode != nil {
+			episodes := toFeedEpisodes(c, deps, []models.Episode{*decision.Episode})
+			response.Episode = &episodes[0]
+			response.Message = "Found the next episode"
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetQueueRules returns the user's autoplay rules
+// @Summary      Get autoplay rules
+// @Description  Return the user's autoplay rules in evaluation order. Users who haven't set any get the defaults:
+// @Description  the next unplayed episode of the same podcast, else the newest unplayed episode in the inbox.
+// @Tags         me
+// @Produce      json
+// @Success      200 {object} types.AutoplayRulesResponse "Autoplay rules"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to load autoplay rules"
+// @Router       /api/v1/me/queue/rules [get]
+func GetQueueRules(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireAutoplay(c, deps)
+		if !ok {
+			return
+		}
+
+		rules, custom, err := deps.AutoplayService.Rules(c.Request.Context(), userID)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to load autoplay rules")
+			return
+		}
+		sendAutoplayRules(c, rules, !custom, "Fetched autoplay rules")
+	}
+}
+
+// PutQueueRules replaces the user's autoplay rules
+// @Summary      Set autoplay rules
+// @Description  Replace the user's autoplay rules, evaluated in order by GET /api/v1/me/queue/next. Each rule has a
+// @Description  source: same_podcast (order next, oldest or newest), inbox (optionally limited to a folder),
+// @Description  in_progress (resume the most recently played unfinished episode) or stop. 'when' limits a rule to
+// @Description  after a finished or unfinished episode. At most 10 rules; an empty list turns autoplay off.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Param        request body types.AutoplayRulesRequest true "Rules in evaluation order"
+// @Success      200 {object} types.AutoplayRulesResponse "Saved rules"
+// @Failure      400 {object} types.ErrorResponse "Invalid rules"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to save autoplay rules"
+// @Router       /api/v1/me/queue/rules [put]
+func PutQueueRules(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireAutoplay(c, deps)
+		if !ok {
+			return
+		}
+
+		var req types.AutoplayRulesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			types.SendError(c, http.StatusBadRequest, types.CodeInvalidRequest, "Invalid request body")
+			return
+		}
+
+		rules, err := deps.AutoplayService.SetRules(c.Request.Context(), userID, req.Rules)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to save autoplay rules")
+			return
+		}
+		sendAutoplayRules(c, rules, false, "Updated autoplay rules")
+	}
+}
+
+// DeleteQueueRules restores the default autoplay rules
+// @Summary      Reset autoplay rules
+// @Tags         me
+// @Produce      json
+// @Success      200 {object} types.AutoplayRulesResponse "Default rules"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to reset autoplay rules"
+// @Router       /api/v1/me/queue/rules [delete]
+func DeleteQueueRules(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireAutoplay(c, deps)
+		if !ok {
+			return
+		}
+
+		if err := deps.AutoplayService.ResetRules(c.Request.Context(), userID); err != nil {
+			types.SendServiceError(c, err, "Failed to reset autoplay rules")
+			return
+		}
+		sendAutoplayRules(c, autoplay.DefaultRules, true, "Restored default autoplay rules")
+	}
+}
+
+func sendAutoplayRules(c *gin.Context, rules []autoplay.Rule, isDefault bool, message string) {
+	if rules == nil {
+		rules = []autoplay.Rule{}
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, types.AutoplayRulesResponse{
+		BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: message},
+		Rules:        rules,
+		Default:      isDefault,
+	})
+}
+
+// requireAutoplay returns the authenticated user's ID, writing an error if
+// there is none or autoplay is unavailable
+func requireAutoplay(c *gin.Context, deps *types.Dependencies) (string, bool) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return "", false
+	}
+	if deps.AutoplayService == nil {
+		types.SendError(c, http.StatusInternalServerError, types.CodeInternal, "Autoplay not available")
+		return "", false
+	}
+	return userID, true
+}
@@ -0,0 +1,86 @@
+package me
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/middleware"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/spf13/viper"
+)
+
+// RegisterRoutes registers routes scoped to the authenticated user
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// GET/PUT /api/v1/me/preferences - Settings shared across the user's clients
+	router.GET("/preferences", GetPreferences(deps))
+	router.PUT("/preferences", PutPreferences(deps))
+
+	// GET /api/v1/me/stats - Listening totals and streaks
+	router.GET("/stats", GetStats(deps))
+
+	// POST /api/v1/me/export - Queue a personal data export
+	// GET /api/v1/me/export/:jobId - Export status and signed download URL
+	router.POST("/export", PostExport(deps))
+	router.GET("/export/:jobId", GetExport(deps))
+
+	// POST /api/v1/me/import - Import another app's subscriptions and listening history
+	// GET /api/v1/me/import/:jobId - Import status and result
+	router.POST("/import", PostImport(deps))
+	router.GET("/import/:jobId", GetImport(deps))
+
+	// GET /api/v1/me/notifications - Notification feed
+	// POST /api/v1/me/notifications/read - Mark some or all read
+	// POST /api/v1/me/notifications/:id/read - Mark one read
+	router.GET("/notifications", GetNotifications(deps))
+	router.POST("/notifications/read", MarkNotificationsRead(deps))
+	router.POST("/notifications/:id/read", MarkNotificationRead(deps))
+
+	// GET/POST /api/v1/me/saved-searches - Searches re-run on a schedule
+	// GET/DELETE /api/v1/me/saved-searches/:id
+	// GET /api/v1/me/saved-searches/:id/results - Podcasts found, optionally since a time
+	router.GET("/saved-searches", GetSavedSearches(deps))
+	router.POST("/saved-searches", PostSavedSearch(deps))
+	router.GET("/saved-searches/:id", GetSavedSearch(deps))
+	router.DELETE("/saved-searches/:id", DeleteSavedSearch(deps))
+	router.GET("/saved-searches/:id/results", GetSavedSearchResults(deps))
+
+	// GET/POST /api/v1/me/subscriptions - Subscriptions, filterable by folder or tag
+	// GET /api/v1/me/subscriptions/folders - Folders with subscription counts
+	// GET /api/v1/me/subscriptions/episodes - Episode feed, filterable by folder or tag
+	// GET/PATCH/DELETE /api/v1/me/subscriptions/:podcastId
+	router.GET("/subscriptions", GetSubscriptions(deps))
+	router.POST("/subscriptions", PostSubscription(deps))
+	router.GET("/subscriptions/folders", GetSubscriptionFolders(deps))
+	router.GET("/subscriptions/episodes", GetSubscriptionEpisodes(deps))
+	router.GET("/subscriptions/:podcastId", GetSubscription(deps))
+	router.PATCH("/subscriptions/:podcastId", PatchSubscription(deps))
+	router.DELETE("/subscriptions/:podcastId", DeleteSubscription(deps))
+
+	// GET /api/v1/me/inbox - Recent episodes across subscriptions with read state
+	// POST /api/v1/me/inbox/read - Mark some or all read
+	// POST /api/v1/me/inbox/unread - Mark some unread again
+	router.GET("/inbox", GetInbox(deps))
+	router.POST("/inbox/read", MarkInboxRead(deps))
+	router.POST("/inbox/unread", MarkInboxUnread(deps))
+
+	// GET /api/v1/me/queue/next - Episode the user's autoplay rules pick after one
+	// GET/PUT/DELETE /api/v1/me/queue/rules - The rules; DELETE restores the defaults
+	router.GET("/queue/next", GetQueueNext(deps))
+	router.GET("/queue/rules", GetQueueRules(deps))
+	router.PUT("/queue/rules", PutQueueRules(deps))
+	router.DELETE("/queue/rules", DeleteQueueRules(deps))
+
+	// GET /api/v1/me/calendar - Released and expected episodes in a date range
+	router.GET("/calendar", GetCalendar(deps))
+
+	// DELETE /api/v1/me - Schedule deletion of the user's data
+	// GET/DELETE /api/v1/me/deletion - Deletion status and cancellation
+	router.DELETE("", DeleteAccount(deps))
+	router.GET("/deletion", GetAccountDeletion(deps))
+	router.DELETE("/deletion", CancelAccountDeletion(deps))
+}
+
+// RegisterDownloadRoutes registers the signed export download route. The router
+// must not require authentication; the link signature authorizes the download.
+func RegisterDownloadRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// GET /api/v1/exports/:jobId/download
+	router.GET("/:jobId/download", middleware.StreamDeadline(viper.GetDuration("server.stream_write_timeout")), DownloadExport(deps))
+}
@@ -0,0 +1,382 @@
+package me
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/savedsearches"
+)
+
+const (
+	defaultSavedSearchResults = 50
+	maxSavedSearchResults     = 200
+)
+
+// GetSavedSearches lists the user's saved searches
+// @Summary      List saved searches
+// @Description  Return the authenticated user's saved searches, oldest first, with when each last ran and runs next.
+// @Tags         me
+// @Produce      json
+// @Success      200 {object} types.SavedSearchesResponse "Saved searches"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to load saved searches"
+// @Router       /api/v1/me/saved-searches [get]
+func GetSavedSearches(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSavedSearches(c, deps)
+		if !ok {
+			return
+		}
+
+		searches, err := deps.SavedSearchService.List(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("[ERROR] Failed to list saved searches for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load saved searches",
+			})
+			return
+		}
+
+		response := types.SavedSearchesResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Saved searches retrieved",
+			},
+			SavedSearches: make([]types.SavedSearch, 0, len(searches)),
+		}
+		for i := range searches {
+			response.SavedSearches = append(response.SavedSearches, toSavedSearch(&searches[i]))
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// PostSavedSearch saves a search to be re-run on a schedule
+// @Summary      Save a search
+// @Description  Save a Podcast Index search, with the same parameters as POST /api/v1/search, to be re-run every
+// @Description  interval_minutes. The first run happens shortly after saving and records the podcasts already
+// @Description  matching; later runs record podcasts not seen before. New podcasts are listed by
+// @Description  GET /api/v1/me/saved-searches/{id}/results and, when requested, announced with a notification
+// @Description  and a signed saved_search.results webhook.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Param        request body types.SavedSearchRequest true "Search to save"
+// @Success      201 {object} types.SavedSearchResponse "Saved search"
+// @Failure      400 {object} types.ErrorResponse "Invalid search, interval, limit or webhook URL"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      409 {object} types.ErrorResponse "Saved search limit reached"
+// @Failure      500 {object} types.ErrorResponse "Failed to save search"
+// @Router       /api/v1/me/saved-searches [post]
+func PostSavedSearch(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSavedSearches(c, deps)
+		if !ok {
+			return
+		}
+
+		var req types.SavedSearchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		search := &models.SavedSearch{
+			UserID:          userID,
+			Name:            req.Name,
+			Query:           req.Query,
+			Limit:           req.Limit,
+			FullText:        req.FullText,
+			Val:             req.Val,
+			ApOnly:          req.ApOnly,
+			Clean:           req.Clean,
+			Languages:       joinList(strings.Split(req.Lang, ",")),
+			NotCategories:   joinList(req.NotCategories),
+			IntervalMinutes: req.IntervalMinutes,
+			Notify:          req.Notify,
+			WebhookURL:      strings.TrimSpace(req.WebhookURL),
+		}
+		if err := deps.SavedSearchService.Create(c.Request.Context(), search); err != nil {
+			switch {
+			case errors.Is(err, savedsearches.ErrInvalidSavedSearch):
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: "Invalid saved search",
+					Details: err.Error(),
+				})
+			case errors.Is(err, savedsearches.ErrTooManySavedSearches):
+				c.JSON(http.StatusConflict, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeSavedSearchLimit,
+					Message: "Saved search limit reached",
+					Details: err.Error(),
+				})
+			default:
+				log.Printf("[ERROR] Failed to save search for user %s: %v", userID, err)
+				c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInternal,
+					Message: "Failed to save search",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, types.SavedSearchResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Search saved",
+			},
+			SavedSearch: toSavedSearch(search),
+		})
+	}
+}
+
+// GetSavedSearch returns one of the user's saved searches
+// @Summary      Get a saved search
+// @Tags         me
+// @Produce      json
+// @Param        id path int true "Saved search ID"
+// @Success      200 {object} types.SavedSearchResponse "Saved search"
+// @Failure      400 {object} types.ErrorResponse "Invalid saved search ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "Saved search not found"
+// @Failure      500 {object} types.ErrorResponse "Failed to load saved search"
+// @Router       /api/v1/me/saved-searches/{id} [get]
+func GetSavedSearch(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, id, ok := savedSearchTarget(c, deps)
+		if !ok {
+			return
+		}
+
+		search, err := deps.SavedSearchService.Get(c.Request.Context(), userID, id)
+		if err != nil {
+			sendSavedSearchError(c, userID, err)
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, types.SavedSearchResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Saved search retrieved",
+			},
+			SavedSearch: toSavedSearch(search),
+		})
+	}
+}
+
+// DeleteSavedSearch removes one of the user's saved searches
+// @Summary      Delete a saved search
+// @Description  Stop re-running the search and delete the results it recorded.
+// @Tags         me
+// @Produce      json
+// @Param        id path int true "Saved search ID"
+// @Success      200 {object} types.BaseResponse "Saved search deleted"
+// @Failure      400 {object} types.ErrorResponse "Invalid saved search ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "Saved search not found"
+// @Failure      500 {object} types.ErrorResponse "Failed to load saved search"
+// @Router       /api/v1/me/saved-searches/{id} [delete]
+func DeleteSavedSearch(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, id, ok := savedSearchTarget(c, deps)
+		if !ok {
+			return
+		}
+
+		if err := deps.SavedSearchService.Delete(c.Request.Context(), userID, id); err != nil {
+			sendSavedSearchError(c, userID, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, types.BaseResponse{
+			Status:  types.StatusOK,
+			Message: "Saved search deleted",
+		})
+	}
+}
+
+// GetSavedSearchResults lists podcasts a saved search has found
+// @Summary      List saved search results
+// @Description  Return podcasts the saved search has found, newest first. Pass 'since' (typically the time of the
+// @Description  previous call) to get only podcasts found after it. Results with initial=true were already
+// @Description  matching when the search was saved.
+// @Tags         me
+// @Produce      json
+// @Param        id path int true "Saved search ID"
+// @Param        since query string false "Only podcasts found after this time (RFC 3339)"
+// @Param        limit query int false "Maximum results (1-200)" default(50)
+// @Success      200 {object} types.SavedSearchResultsResponse "Podcasts found"
+// @Failure      400 {object} types.ErrorResponse "Invalid saved search ID, since or limit"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "Saved search not found"
+// @Failure      500 {object} types.ErrorResponse "Failed to load saved search"
+// @Router       /api/v1/me/saved-searches/{id}/results [get]
+func GetSavedSearchResults(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, id, ok := savedSearchTarget(c, deps)
+		if !ok {
+			return
+		}
+
+		var since time.Time
+		if raw := c.Query("since"); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: "since must be an RFC 3339 time",
+				})
+				return
+			}
+			since = parsed
+		}
+		limit := defaultSavedSearchResults
+		if raw := c.Query("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 || parsed > maxSavedSearchResults {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: "limit must be between 1 and 200",
+				})
+				return
+			}
+			limit = parsed
+		}
+
+		results, err := deps.SavedSearchService.Results(c.Request.Context(), userID, id, since, limit)
+		if err != nil {
+			sendSavedSearchError(c, userID, err)
+			return
+		}
+
+		response := types.SavedSearchResultsResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Saved search results retrieved",
+			},
+			Results: make([]types.SavedSearchResult, 0, len(results)),
+		}
+		for _, result := range results {
+			response.Results = append(response.Results, types.SavedSearchResult{
+				PodcastID: result.PodcastIndexFeedID,
+				Title:     result.Title,
+				Author:    result.Author,
+				Image:     result.Image,
+				URL:       result.URL,
+				FoundAt:   result.FoundAt,
+				Initial:   result.Initial,
+			})
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// requireSavedSearches returns the authenticated user's ID, writing an error
+// if there is none or saved searches are disabled
+func requireSavedSearches(c *gin.Context, deps *types.Dependencies) (string, bool) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return "", false
+	}
+	if deps.SavedSearchService == nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInternal,
+			Message: "Saved searches not available",
+		})
+		return "", false
+	}
+	return userID, true
+}
+
+// savedSearchTarget resolves the user and the saved search ID in the path
+func savedSearchTarget(c *gin.Context, deps *types.Dependencies) (string, uint, bool) {
+	userID, ok := requireSavedSearches(c, deps)
+	if !ok {
+		return "", 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInvalidRequest,
+			Message: "Invalid saved search ID",
+		})
+		return "", 0, false
+	}
+	return userID, uint(id), true
+}
+
+func sendSavedSearchError(c *gin.Context, userID string, err error) {
+	if errors.Is(err, savedsearches.ErrSavedSearchNotFound) {
+		c.JSON(http.StatusNotFound, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeSavedSearchNotFound,
+			Message: "Saved search not found",
+		})
+		return
+	}
+	log.Printf("[ERROR] Failed to load saved search for user %s: %v", userID, err)
+	c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+		Status:  types.StatusError,
+		Code:    types.CodeInternal,
+		Message: "Failed to load saved search",
+	})
+}
+
+// joinList trims and joins values into the comma-separated form stored on models
+func joinList(values []string) string {
+	kept := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			kept = append(kept, v)
+		}
+	}
+	return strings.Join(kept, ",")
+}
+
+func toSavedSearch(s *models.SavedSearch) types.SavedSearch {
+	return types.SavedSearch{
+		ID:              s.ID,
+		Name:            s.Name,
+		Query:           s.Query,
+		Limit:           s.Limit,
+		FullText:        s.FullText,
+		Val:             s.Val,
+		ApOnly:          s.ApOnly,
+		Clean:           s.Clean,
+		Lang:            s.Languages,
+		NotCategories:   s.NotCategoryList(),
+		IntervalMinutes: s.IntervalMinutes,
+		Notify:          s.Notify,
+		WebhookURL:      s.WebhookURL,
+		CreatedAt:       s.CreatedAt,
+		LastRunAt:       s.LastRunAt,
+		NextRunAt:       s.NextRunAt,
+		LastError:       s.LastError,
+	}
+}
@@ -0,0 +1,66 @@
+package me
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/playback"
+)
+
+// ListeningStatsResponse wraps the authenticated user's listening stats
+type ListeningStatsResponse struct {
+	types.BaseResponse
+	Stats *playback.Stats `json:"stats"`
+}
+
+// GetStats returns the authenticated user's listening stats
+// @Summary      Get listening stats
+// @Description  Return totals computed from playback progress reports: hours listened, episodes completed,
+// @Description  the podcasts with the most completed episodes, and current/longest daily listening streaks.
+// @Description  Days are UTC. Listening time only counts forward progress that is plausible for the wall time
+// @Description  between reports, so seeking ahead does not inflate totals. Ad skips come from skip_ad
+// @Description  playback events (POST /api/v1/events) still within events.retention.
+// @Tags         me
+// @Produce      json
+// @Success      200 {object} ListeningStatsResponse "Listening stats"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to compute stats"
+// @Router       /api/v1/me/stats [get]
+func GetStats(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireUser(c)
+		if !ok {
+			return
+		}
+
+		if deps.PlaybackService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Playback service not available",
+			})
+			return
+		}
+
+		stats, err := deps.PlaybackService.GetStats(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to compute stats",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, ListeningStatsResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Fetched listening stats",
+			},
+			Stats: stats,
+		})
+	}
+}
@@ -0,0 +1,489 @@
+package me
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+)
+
+const (
+	defaultSubscriptionEpisodes = 50
+	maxSubscriptionEpisodes     = 200
+)
+
+// GetSubscriptions lists the user's subscriptions
+// @Summary      List subscriptions
+// @Description  Return the authenticated user's subscriptions, oldest first, with their podcasts. Pass 'folder'
+// @Description  and/or 'tag' to list only subscriptions in that folder or carrying that tag.
+// @Tags         me
+// @Produce      json
+// @Param        folder query string false "Only subscriptions in this folder"
+// @Param        tag query string false "Only subscriptions with this tag (case-insensitive)"
+// @Success      200 {object} types.SubscriptionsResponse "Subscriptions"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to load subscriptions"
+// @Router       /api/v1/me/subscriptions [get]
+func GetSubscriptions(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSubscriptions(c, deps)
+		if !ok {
+			return
+		}
+
+		subs, err := deps.SubscriptionService.List(c.Request.Context(), userID, subscriptionFilter(c))
+		if err != nil {
+			log.Printf("[ERROR] Failed to list subscriptions for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load subscriptions",
+			})
+			return
+		}
+
+		response := types.SubscriptionsResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Subscriptions retrieved",
+			},
+			Subscriptions: make([]types.Subscription, 0, len(subs)),
+		}
+		for i := range subs {
+			response.Subscriptions = append(response.Subscriptions, toSubscription(&subs[i]))
+		}
+		podcasts := make([]*types.Podcast, len(response.Subscriptions))
+		for i := range response.Subscriptions {
+			podcasts[i] = response.Subscriptions[i].Podcast
+		}
+		types.WithPodcastPalettes(c, deps, podcasts...)
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// PostSubscription subscribes the user to a podcast
+// @Summary      Subscribe to a podcast
+// @Description  Subscribe to a podcast by Podcast Index feed ID, optionally placing it in a folder and tagging it.
+// @Description  The podcast is fetched from Podcast Index if it isn't stored yet. Tags are stored lowercase.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Param        request body types.SubscriptionRequest true "Podcast to subscribe to"
+// @Success      201 {object} types.SubscriptionResponse "Subscription"
+// @Failure      400 {object} types.ErrorResponse "Invalid folder or tags, or podcast not found"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      409 {object} types.ErrorResponse "Already subscribed"
+// @Failure      500 {object} types.ErrorResponse "Failed to subscribe"
+// @Router       /api/v1/me/subscriptions [post]
+func PostSubscription(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSubscriptions(c, deps)
+		if !ok {
+			return
+		}
+
+		var req types.SubscriptionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		sub, err := deps.SubscriptionService.Subscribe(c.Request.Context(), userID, req.PodcastID, req.Folder, req.Tags)
+		if err != nil {
+			sendSubscriptionError(c, userID, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, types.SubscriptionResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Subscribed",
+			},
+			Subscription: toSubscription(sub),
+		})
+	}
+}
+
+// GetSubscription returns the user's subscription to a podcast
+// @Summary      Get a subscription
+// @Tags         me
+// @Produce      json
+// @Param        podcastId path int true "Podcast Index feed ID"
+// @Success      200 {object} types.SubscriptionResponse "Subscription"
+// @Failure      400 {object} types.ErrorResponse "Invalid podcast ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "Not subscribed"
+// @Failure      500 {object} types.ErrorResponse "Failed to load subscription"
+// @Router       /api/v1/me/subscriptions/{podcastId} [get]
+func GetSubscription(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, podcastID, ok := subscriptionTarget(c, deps)
+		if !ok {
+			return
+		}
+
+		sub, err := deps.SubscriptionService.Get(c.Request.Context(), userID, podcastID)
+		if err != nil {
+			sendSubscriptionError(c, userID, err)
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, types.SubscriptionResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Subscription retrieved",
+			},
+			Subscription: toSubscription(sub),
+		})
+	}
+}
+
+// PatchSubscription moves a subscription to another folder or retags it
+// @Summary      Update a subscription
+// @Description  Change the subscription's folder and/or tags. Omitted fields are left alone; an empty folder
+// @Description  takes the subscription out of its folder and an empty tag list clears its tags.
+// @Tags         me
+// @Accept       json
+// @Produce      json
+// @Param        podcastId path int true "Podcast Index feed ID"
+// @Param        request body types.SubscriptionUpdateRequest true "Folder and tags"
+// @Success      200 {object} types.SubscriptionResponse "Updated subscription"
+// @Failure      400 {object} types.ErrorResponse "Invalid podcast ID, folder or tags"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "Not subscribed"
+// @Failure      500 {object} types.ErrorResponse "Failed to load subscription"
+// @Router       /api/v1/me/subscriptions/{podcastId} [patch]
+func PatchSubscription(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, podcastID, ok := subscriptionTarget(c, deps)
+		if !ok {
+			return
+		}
+
+		var req types.SubscriptionUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid request body",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		sub, err := deps.SubscriptionService.Update(c.Request.Context(), userID, podcastID, subscriptions.Changes{
+			Folder: req.Folder,
+			Tags:   req.Tags,
+		})
+		if err != nil {
+			sendSubscriptionError(c, userID, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, types.SubscriptionResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Subscription updated",
+			},
+			Subscription: toSubscription(sub),
+		})
+	}
+}
+
+// DeleteSubscription unsubscribes the user from a podcast
+// @Summary      Unsubscribe from a podcast
+// @Tags         me
+// @Produce      json
+// @Param        podcastId path int true "Podcast Index feed ID"
+// @Success      200 {object} types.BaseResponse "Unsubscribed"
+// @Failure      400 {object} types.ErrorResponse "Invalid podcast ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "Not subscribed"
+// @Failure      500 {object} types.ErrorResponse "Failed to load subscription"
+// @Router       /api/v1/me/subscriptions/{podcastId} [delete]
+func DeleteSubscription(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, podcastID, ok := subscriptionTarget(c, deps)
+		if !ok {
+			return
+		}
+
+		if err := deps.SubscriptionService.Unsubscribe(c.Request.Context(), userID, podcastID); err != nil {
+			sendSubscriptionError(c, userID, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, types.BaseResponse{
+			Status:  types.StatusOK,
+			Message: "Unsubscribed",
+		})
+	}
+}
+
+// GetSubscriptionFolders lists the user's subscription folders
+// @Summary      List subscription folders
+// @Description  Return the folders the user has placed subscriptions in, by name, with how many subscriptions
+// @Description  each holds. Subscriptions outside any folder are counted under the empty folder name.
+// @Tags         me
+// @Produce      json
+// @Success      200 {object} types.SubscriptionFoldersResponse "Folders"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to load folders"
+// @Router       /api/v1/me/subscriptions/folders [get]
+func GetSubscriptionFolders(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSubscriptions(c, deps)
+		if !ok {
+			return
+		}
+
+		folders, err := deps.SubscriptionService.Folders(c.Request.Context(), userID)
+		if err != nil {
+			log.Printf("[ERROR] Failed to list subscription folders for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load folders",
+			})
+			return
+		}
+
+		response := types.SubscriptionFoldersResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Folders retrieved",
+			},
+			Folders: make([]types.SubscriptionFolder, 0, len(folders)),
+		}
+		for _, folder := range folders {
+			response.Folders = append(response.Folders, types.SubscriptionFolder{
+				Folder: folder.Folder,
+				Count:  folder.Count,
+			})
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetSubscriptionEpisodes returns the episode feed of the user's subscriptions
+// @Summary      Subscription episode feed
+// @Description  Return stored episodes of the user's subscriptions, newest first. Pass 'folder' and/or 'tag' to
+// @Description  build the feed from only the subscriptions in that folder or carrying that tag. Episodes on
+// @Description  blocked feeds or domains are left out of the page.
+// @Tags         me
+// @Produce      json
+// @Param        folder query string false "Only subscriptions in this folder"
+// @Param        tag query string false "Only subscriptions with this tag (case-insensitive)"
+// @Param        limit query int false "Maximum episodes (1-200)" default(50)
+// @Param        offset query int false "Episodes to skip" default(0)
+// @Success      200 {object} types.EpisodesResponse "Episodes"
+// @Failure      400 {object} types.ErrorResponse "Invalid limit or offset"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to load episodes"
+// @Router       /api/v1/me/subscriptions/episodes [get]
+func GetSubscriptionEpisodes(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := requireSubscriptions(c, deps)
+		if !ok {
+			return
+		}
+
+		limit, offset, ok := episodePage(c)
+		if !ok {
+			return
+		}
+		query := subscriptions.EpisodeQuery{Filter: subscriptionFilter(c), Limit: limit, Offset: offset}
+
+		episodes, total, err := deps.SubscriptionService.Episodes(c.Request.Context(), userID, query)
+		if err != nil {
+			log.Printf("[ERROR] Failed to load subscription episodes for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load episodes",
+			})
+			return
+		}
+
+		block := types.Blocklist(c, deps)
+		kept := episodes[:0]
+		for i := range episodes {
+			if !block.Blocks(types.EpisodeSubject(&episodes[i])) {
+				kept = append(kept, episodes[i])
+			}
+		}
+		responseEpisodes := toFeedEpisodes(c, deps, kept)
+
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, types.EpisodesResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: fmt.Sprintf("Fetched %d episodes from subscriptions", len(responseEpisodes)),
+			},
+			Episodes: responseEpisodes,
+			Count:    len(responseEpisodes),
+			Total:    int(total),
+			Offset:   query.Offset,
+		})
+	}
+}
+
+// episodePage reads the limit and offset query parameters of an episode feed,
+// writing a 400 if either is invalid
+func episodePage(c *gin.Context) (limit, offset int, ok bool) {
+	limit = defaultSubscriptionEpisodes
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxSubscriptionEpisodes {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "limit must be between 1 and 200",
+			})
+			return 0, 0, false
+		}
+		limit = parsed
+	}
+	if raw := c.Query("offset"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "offset must be a non-negative integer",
+			})
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+	return limit, offset, true
+}
+
+// toFeedEpisodes converts feed episodes to their API form with processing
+// flags, which come from one aggregate query for the whole page
+func toFeedEpisodes(c *gin.Context, deps *types.Dependencies, episodes []models.Episode) []types.Episode {
+	responseEpisodes := types.WithEpisodePalettes(c, deps, types.FromModelEpisodeList(episodes))
+	if deps.EpisodeService == nil {
+		return responseEpisodes
+	}
+	ids := make([]int64, len(episodes))
+	for i := range episodes {
+		ids[i] = episodes[i].PodcastIndexID
+	}
+	statuses, err := deps.EpisodeService.GetEpisodeStatuses(c.Request.Context(), ids)
+	if err != nil {
+		log.Printf("[WARN] Failed to get episode statuses for feed: %v", err)
+		return responseEpisodes
+	}
+	return types.WithEpisodeStatuses(responseEpisodes, statuses)
+}
+
+// requireSubscriptions returns the authenticated user's ID, writing an error
+// if there is none or subscriptions are unavailable
+func requireSubscriptions(c *gin.Context, deps *types.Dependencies) (string, bool) {
+	userID, ok := requireUser(c)
+	if !ok {
+		return "", false
+	}
+	if deps.SubscriptionService == nil {
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInternal,
+			Message: "Subscriptions not available",
+		})
+		return "", false
+	}
+	return userID, true
+}
+
+// subscriptionTarget resolves the user and the Podcast Index feed ID in the path
+func subscriptionTarget(c *gin.Context, deps *types.Dependencies) (string, int64, bool) {
+	userID, ok := requireSubscriptions(c, deps)
+	if !ok {
+		return "", 0, false
+	}
+	podcastID, err := strconv.ParseInt(c.Param("podcastId"), 10, 64)
+	if err != nil || podcastID <= 0 {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInvalidRequest,
+			Message: "Invalid podcast ID",
+		})
+		return "", 0, false
+	}
+	return userID, podcastID, true
+}
+
+// subscriptionFilter reads the folder and tag query parameters
+func subscriptionFilter(c *gin.Context) subscriptions.Filter {
+	return subscriptions.Filter{
+		Folder: strings.TrimSpace(c.Query("folder")),
+		Tag:    c.Query("tag"),
+	}
+}
+
+func sendSubscriptionError(c *gin.Context, userID string, err error) {
+	switch {
+	case errors.Is(err, subscriptions.ErrSubscriptionNotFound):
+		c.JSON(http.StatusNotFound, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeSubscriptionNotFound,
+			Message: "Not subscribed to this podcast",
+		})
+	case errors.Is(err, subscriptions.ErrAlreadySubscribed):
+		c.JSON(http.StatusConflict, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeAlreadySubscribed,
+			Message: "Already subscribed to this podcast",
+		})
+	case errors.Is(err, subscriptions.ErrInvalidSubscription):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInvalidRequest,
+			Message: "Invalid folder or tags",
+			Details: err.Error(),
+		})
+	case errors.Is(err, subscriptions.ErrPodcastNotFound):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodePodcastNotFound,
+			Message: "Podcast not found",
+			Details: err.Error(),
+		})
+	default:
+		log.Printf("[ERROR] Failed to load subscription for user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeInternal,
+			Message: "Failed to load subscription",
+		})
+	}
+}
+
+// toSubscription converts a subscription with its podcast loaded to its API form
+func toSubscription(sub *models.Subscription) types.Subscription {
+	return types.Subscription{
+		PodcastID:    sub.Podcast.PodcastIndexID,
+		Podcast:      types.FromModelPodcast(&sub.Podcast),
+		Folder:       sub.Folder,
+		Tags:         sub.TagList(),
+		SubscribedAt: sub.CreatedAt,
+	}
+}
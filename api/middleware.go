@@ -1,11 +1,16 @@
 package api
 
 import (
+	"log"
+	"math"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/spf13/viper"
 	"golang.org/x/time/rate"
 )
 
@@ -15,47 +20,91 @@ type clientLimiter struct {
 	lastSeen time.Time
 }
 
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		c.Header("Access-Control-Max-Age", "86400")
+// RateLimitTier raises, lowers or lifts the rate limits for some signed-in
+// callers: those listed by user ID or holding a permission
+type RateLimitTier struct {
+	Name       string   `mapstructure:"name"`
+	Permission string   `mapstructure:"permission"` // JWT permission that puts a caller in the tier
+	Users      []string `mapstructure:"users"`      // User IDs in the tier whatever their permissions, e.g. service accounts
+	Multiplier float64  `mapstructure:"multiplier"` // Scales each route's rate and burst; 0 leaves them as they are
+	Exempt     bool     `mapstructure:"exempt"`     // Not rate limited at all
+}
 
-		if c.Request.Method == http.MethodOptions {
-			c.AbortWithStatus(http.StatusOK)
-			return
-		}
+// RateLimitTiersFromViper loads the tiers from security.rate_limit_tiers
+func RateLimitTiersFromViper(settings *viper.Viper) []RateLimitTier {
+	var tiers []RateLimitTier
+	if err := settings.UnmarshalKey("security.rate_limit_tiers", &tiers); err != nil {
+		log.Printf("[WARN] Ignoring invalid security.rate_limit_tiers config: %v", err)
+		return nil
+	}
+	return tiers
+}
 
-		c.Next()
+// rateLimitTierFor returns the first tier the signed-in caller belongs to
+func rateLimitTierFor(c *gin.Context, tiers []RateLimitTier, userID string) *RateLimitTier {
+	for i := range tiers {
+		tier := &tiers[i]
+		if slices.Contains(tier.Users, userID) || (tier.Permission != "" && types.HasPermission(c, tier.Permission)) {
+			return tier
+		}
 	}
+	return nil
 }
 
-func RequestSizeLimit() gin.HandlerFunc {
-	return RequestSizeLimitWithSize(1024 * 1024)
+// RateLimits is a per-client request rate with its burst, and the tiers
+// scaling them. Set replaces them while requests are served.
+type RateLimits struct {
+	mu    sync.RWMutex
+	rps   int
+	burst int
+	tiers []RateLimitTier
 }
 
-func RequestSizeLimitWithSize(maxBytes int64) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		if c.Request.Method == http.MethodPost ||
-			c.Request.Method == http.MethodPut ||
-			c.Request.Method == http.MethodPatch {
-			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
-		}
-		c.Next()
-	}
+// Set replaces the limits. Clients' existing limiters keep the old ones
+// until they are dropped.
+func (l *RateLimits) Set(rps, burst int, tiers []RateLimitTier) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rps, l.burst, l.tiers = rps, burst, tiers
+}
+
+func (l *RateLimits) get() (int, int, []RateLimitTier) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.rps, l.burst, l.tiers
 }
 
-func PerClientRateLimit(rateLimiters *sync.Map, cleanupStop chan struct{}, cleanupInitialized *sync.Once, rps int, burst int) gin.HandlerFunc {
+// PerClientRateLimit limits each client to the limits' requests a second and
+// bursts. Signed-in callers are limited per user, wherever they connect
+// from, and scaled by their tier; anonymous ones per IP.
+func PerClientRateLimit(rateLimiters *sync.Map, cleanupStop chan struct{}, cleanupInitialized *sync.Once, limits *RateLimits) gin.HandlerFunc {
 	cleanupInitialized.Do(func() {
 		go cleanupOldRateLimiters(rateLimiters, cleanupStop)
 	})
 
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
+		rps, burst, tiers := limits.get()
+		key := "ip:" + c.ClientIP()
+		limit, limitBurst := rate.Limit(rps), burst
+		if userID := c.GetString("user_id"); userID != "" {
+			key = "user:" + userID
+			if tier := rateLimitTierFor(c, tiers, userID); tier != nil {
+				if tier.Exempt {
+					c.Next()
+					return
+				}
+				// Keyed by tier too, so a caller moved to another tier
+				// starts on its limits rather than the old ones
+				key += "|" + tier.Name
+				if tier.Multiplier > 0 {
+					limit = rate.Limit(float64(rps) * tier.Multiplier)
+					limitBurst = max(1, int(math.Ceil(float64(burst)*tier.Multiplier)))
+				}
+			}
+		}
 
-		limiterInterface, _ := rateLimiters.LoadOrStore(clientIP, &clientLimiter{
-			limiter:  rate.NewLimiter(rate.Every(time.Second/time.Duration(rps)), burst),
+		limiterInterface, _ := rateLimiters.LoadOrStore(key, &clientLimiter{
+			limiter:  rate.NewLimiter(limit, limitBurst),
 			lastSeen: time.Now(),
 		})
 
@@ -63,9 +112,7 @@ func PerClientRateLimit(rateLimiters *sync.Map, cleanupStop chan struct{}, clean
 		cl.lastSeen = time.Now()
 
 		if !cl.limiter.Allow() {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded. Please slow down your requests.",
-			})
+			types.SendError(c, http.StatusTooManyRequests, types.CodeRateLimited, "Rate limit exceeded. Please slow down your requests.")
 			c.Abort()
 			return
 		}
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,10 +19,41 @@ import (
 
 // CacheConfig holds configuration for cache middleware
 type CacheConfig struct {
-	Cache      cache.Cache
-	DefaultTTL time.Duration
-	TTLByPath  map[string]time.Duration // Path-specific TTLs
-	Enabled    bool
+	Cache   cache.Cache
+	TTLs    *CacheTTLs
+	Enabled bool
+}
+
+// CacheTTLs holds how long responses are cached, by path. Set replaces the
+// TTLs while requests are served; entries already cached keep theirs.
+type CacheTTLs struct {
+	mu         sync.RWMutex
+	defaultTTL time.Duration
+	byPath     map[string]time.Duration // Exact paths or prefixes
+}
+
+// Set replaces the TTLs
+func (t *CacheTTLs) Set(defaultTTL time.Duration, byPath map[string]time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.defaultTTL, t.byPath = defaultTTL, byPath
+}
+
+// For returns the TTL for a request path: its own, that of a path it starts
+// with, or the default
+func (t *CacheTTLs) For(path string) time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if pathTTL, exists := t.byPath[path]; exists {
+		return pathTTL
+	}
+	for prefix, pathTTL := range t.byPath {
+		if strings.HasPrefix(path, prefix) {
+			return pathTTL
+		}
+	}
+	return t.defaultTTL
 }
 
 // responseWriter captures response for caching
@@ -101,26 +133,14 @@ func CacheMiddleware(config CacheConfig) gin.HandlerFunc {
 		// Process request
 		c.Next()
 
-		// Only cache successful responses
-		if w.status == http.StatusOK && w.body.Len() > 0 {
-			// Determine TTL
-			ttl := config.DefaultTTL
-			if pathTTL, exists := config.TTLByPath[c.Request.URL.Path]; exists {
-				ttl = pathTTL
-			} else {
-				// Check for path prefix match
-				for path, pathTTL := range config.TTLByPath {
-					if strings.HasPrefix(c.Request.URL.Path, path) {
-						ttl = pathTTL
-						break
-					}
-				}
-			}
+		// Only cache successful responses that the handler did not mark as per-user
+		if w.status == http.StatusOK && w.body.Len() > 0 && isStorable(c.Writer.Header()) {
+			ttl := config.TTLs.For(c.Request.URL.Path)
 
 			// Create cached response
 			cachedResponse := CachedResponse{
 				Status:      w.status,
-				Headers:     c.Writer.Header(),
+				Headers:     cacheableHeaders(c.Writer.Header()),
 				Body:        w.body.Bytes(),
 				ContentType: c.ContentType(),
 				CachedAt:    time.Now(),
@@ -181,6 +201,31 @@ func shouldBypassCache(req *http.Request) bool {
 	return false
 }
 
+// cacheableHeaders drops per-request CORS headers, which depend on the caller's
+// Origin and are set again by the CORS middleware on every hit
+func cacheableHeaders(header http.Header) http.Header {
+	stored := make(http.Header, len(header))
+	for key, values := range header {
+		if strings.HasPrefix(key, "Access-Control-") {
+			continue
+		}
+		stored[key] = values
+	}
+	return stored
+}
+
+// isStorable reports whether a response may be stored in the shared cache.
+// Handlers serving user-specific data set Cache-Control: private or no-store.
+func isStorable(header http.Header) bool {
+	for _, directive := range strings.Split(strings.ToLower(header.Get("Cache-Control")), ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "private" || directive == "no-store" {
+			return false
+		}
+	}
+	return true
+}
+
 // generateCacheKey creates a unique key for the request
 func generateCacheKey(req *http.Request) string {
 	// Start with path
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deadlineWriter pushes the connection's write deadline forward on every write,
+// so a stream only times out when the client stops reading, not when it runs long
+type deadlineWriter struct {
+	gin.ResponseWriter
+	controller *http.ResponseController
+	timeout    time.Duration
+}
+
+func (w *deadlineWriter) Write(data []byte) (int, error) {
+	w.extend()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *deadlineWriter) WriteString(s string) (int, error) {
+	w.extend()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *deadlineWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *deadlineWriter) extend() {
+	// Not every writer supports deadlines (e.g. httptest recorders); ignore those
+	_ = w.controller.SetWriteDeadline(time.Now().Add(w.timeout))
+}
+
+// StreamDeadline replaces the server's blanket write timeout for long-running
+// responses with a per-write deadline. The handler may take as long as it needs
+// before its first write (building an archive, say); after that each write must
+// complete within timeout of the previous one, so long downloads survive while
+// stalled clients are reaped. A non-positive timeout only lifts the deadline.
+func StreamDeadline(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		controller := clearWriteDeadline(c)
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		c.Writer = &deadlineWriter{
+			ResponseWriter: c.Writer,
+			controller:     controller,
+			timeout:        timeout,
+		}
+		c.Next()
+	}
+}
+
+// NoWriteDeadline lifts the server's write timeout for handlers that do their
+// work synchronously before writing a small response
+func NoWriteDeadline() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clearWriteDeadline(c)
+		c.Next()
+	}
+}
+
+// clearWriteDeadline removes the connection's write deadline, returning the
+// controller used so callers can set a new one
+func clearWriteDeadline(c *gin.Context) *http.ResponseController {
+	controller := http.NewResponseController(c.Writer)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("[WARN] Failed to clear write deadline: %v", err)
+	}
+	return controller
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveWithWriteTimeout runs router behind a real server, since httptest
+// recorders don't support write deadlines
+func serveWithWriteTimeout(t *testing.T, router *gin.Engine, timeout time.Duration) string {
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = timeout
+	server.Start()
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// fetch returns the body, or an error when the server cut the response off
+func fetch(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func TestStreamDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	slowStart := func(c *gin.Context) {
+		time.Sleep(300 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	}
+	router.GET("/plain", slowStart)
+	router.GET("/stream", StreamDeadline(200*time.Millisecond), slowStart)
+	router.GET("/sync", NoWriteDeadline(), slowStart)
+	router.GET("/long", StreamDeadline(200*time.Millisecond), func(c *gin.Context) {
+		// Runs well past the server timeout, but never stalls between writes
+		for i := 0; i < 6; i++ {
+			c.String(http.StatusOK, "chunk")
+			c.Writer.Flush()
+			time.Sleep(80 * time.Millisecond)
+		}
+	})
+	url := serveWithWriteTimeout(t, router, 150*time.Millisecond)
+
+	_, err := fetch(url + "/plain")
+	assert.Error(t, err, "the server timeout still applies to other routes")
+
+	body, err := fetch(url + "/stream")
+	require.NoError(t, err, "work before the first write is not cut off")
+	assert.Equal(t, "done", body)
+
+	body, err = fetch(url + "/sync")
+	require.NoError(t, err)
+	assert.Equal(t, "done", body)
+
+	body, err = fetch(url + "/long")
+	require.NoError(t, err, "each write extends the deadline")
+	assert.Len(t, body, 6*len("chunk"))
+
+}
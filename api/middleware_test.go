@@ -6,8 +6,10 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,7 +43,14 @@ func TestCORS(t *testing.T) {
 			_, router := gin.CreateTestContext(w)
 
 			// Apply CORS middleware
-			router.Use(CORS())
+			router.Use(CORS(CORSConfig{
+				Enabled: true,
+				Rules: []CORSRule{{
+					Origins: []string{"*"},
+					Methods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+					Headers: []string{"Content-Type", "Authorization"},
+				}},
+			}))
 			router.Any("/test", func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{"message": "success"})
 			})
@@ -98,7 +107,7 @@ func TestPerClientRateLimit_Basic(t *testing.T) {
 	cleanupInitialized := &sync.Once{}
 
 	router := gin.New()
-	middleware := PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, 10, 5) // Generous limits
+	middleware := PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, &RateLimits{rps: 10, burst: 5}) // Generous limits
 	router.Use(middleware)
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
@@ -117,6 +126,56 @@ func TestPerClientRateLimit_Basic(t *testing.T) {
 	close(cleanupStop)
 }
 
+func TestPerClientRateLimit_Tiers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cleanupStop := make(chan struct{})
+	defer close(cleanupStop)
+	tiers := []RateLimitTier{
+		{Name: "service", Users: []string{"svc-1"}, Exempt: true},
+		{Name: "admin", Permission: "podcasts:admin", Multiplier: 3},
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set("user_id", user)
+		}
+		if c.GetHeader("X-Test-Admin") != "" {
+			c.Set("permissions", []string{"podcasts:admin"})
+		}
+	})
+	router.Use(PerClientRateLimit(&sync.Map{}, cleanupStop, &sync.Once{}, &RateLimits{rps: 1, burst: 2, tiers: tiers}))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// allowed counts the requests that get through out of n in a burst
+	allowed := func(remoteAddr, user string, admin bool, n int) int {
+		ok := 0
+		for i := 0; i < n; i++ {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = remoteAddr
+			req.Header.Set("X-Test-User", user)
+			if admin {
+				req.Header.Set("X-Test-Admin", "1")
+			}
+			router.ServeHTTP(w, req)
+			if w.Code == http.StatusOK {
+				ok++
+			}
+		}
+		return ok
+	}
+
+	assert.Equal(t, 2, allowed("10.0.0.1:1000", "", false, 5), "anonymous callers get the route's burst per IP")
+	assert.Equal(t, 2, allowed("10.0.0.1:1000", "user-1", false, 5), "signed-in callers aren't limited by their IP's use")
+	assert.Equal(t, 0, allowed("10.0.0.2:1000", "user-1", false, 1), "a user's limit follows them across IPs")
+	assert.Equal(t, 6, allowed("10.0.0.3:1000", "admin-1", true, 10), "tier multiplier scales the burst")
+	assert.Equal(t, 20, allowed("10.0.0.4:1000", "svc-1", false, 20), "exempt tier isn't limited")
+}
+
 func TestCleanupOldRateLimiters(t *testing.T) {
 	// Just test that the function exists and doesn't panic
 	rateLimiters := &sync.Map{}
@@ -129,3 +188,48 @@ func TestCleanupOldRateLimiters(t *testing.T) {
 	// Test passes if no panic occurred
 	assert.True(t, true)
 }
+
+func TestApplyConfigChanges_RateLimitsAndCacheTTLs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(viper.Reset)
+	viper.Set("security.rate_limit_rps", 1)
+	viper.Set("security.rate_limit_burst", 1)
+	viper.Set("cache.default_ttl", "30m")
+	viper.Set("cache.ttl_search", 15)
+
+	server := &Server{rateLimiters: &sync.Map{}, live: LiveSettingsFromViper()}
+	cleanupStop := make(chan struct{})
+	defer close(cleanupStop)
+	router := gin.New()
+	router.Use(PerClientRateLimit(server.rateLimiters, cleanupStop, &sync.Once{}, server.live.RateLimits))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	allowed := func(n int) int {
+		ok := 0
+		for i := 0; i < n; i++ {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+			if w.Code == http.StatusOK {
+				ok++
+			}
+		}
+		return ok
+	}
+
+	assert.Equal(t, 1, allowed(3))
+	assert.Equal(t, 15*time.Minute, server.live.CacheTTLs.For("/api/v1/search/byterm"))
+
+	// Reloads arrive in their own instance; the global settings stay untouched
+	updated := viper.New()
+	updated.Set("security.rate_limit_rps", 1)
+	updated.Set("security.rate_limit_burst", 3)
+	updated.Set("cache.default_ttl", "30m")
+	updated.Set("cache.ttl_search", 5)
+	server.ApplyConfigChanges([]string{"security.rate_limit_burst", "cache.ttl_search"}, updated)
+
+	assert.Equal(t, 3, allowed(5), "the exhausted client starts over on the new limits")
+	assert.Equal(t, 5*time.Minute, server.live.CacheTTLs.For("/api/v1/search/byterm"))
+	assert.Equal(t, 30*time.Minute, server.live.CacheTTLs.For("/api/v1/stats"))
+	assert.Equal(t, 1, viper.GetInt("security.rate_limit_burst"))
+}
@@ -0,0 +1,121 @@
+package people
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	peopleService "github.com/killallgit/player-api/internal/services/people"
+)
+
+// GetEpisodes returns episodes a person is credited on
+// @Summary      Browse episodes by person
+// @Description  List locally known episodes on which a person is credited as host, guest or other role,
+// @Description  newest first. Person IDs come from GET /episodes/{id}/people. Episodes of blocklisted podcasts are left out.
+// @Tags         people
+// @Produce      json
+// @Param        id path int true "Person ID"
+// @Param        limit query int false "Maximum results (1-100)" default(20)
+// @Param        offset query int false "Offset for pagination" default(0)
+// @Success      200 {object} types.PersonEpisodesResponse "Episodes for the person"
+// @Failure      400 {object} types.ErrorResponse "Invalid person ID or pagination parameters"
+// @Failure      404 {object} types.ErrorResponse "Person not found"
+// @Failure      500 {object} types.ErrorResponse "Failed to fetch episodes"
+// @Router       /api/v1/people/{id}/episodes [get]
+func GetEpisodes(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		personID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || personID == 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid person ID",
+			})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		if err != nil || limit < 1 || limit > 100 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Limit must be between 1 and 100",
+			})
+			return
+		}
+
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Offset must be a non-negative integer",
+			})
+			return
+		}
+
+		if deps.PeopleService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "People service not available",
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		person, err := deps.PeopleService.GetPerson(ctx, uint(personID))
+		if err != nil {
+			if errors.Is(err, peopleService.ErrPersonNotFound) {
+				c.JSON(http.StatusNotFound, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodePersonNotFound,
+					Message: "Person not found",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load person",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		episodes, total, err := deps.PeopleService.ListPersonEpisodes(ctx, person.ID, limit, offset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to fetch episodes for person",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		block := types.Blocklist(c, deps)
+		kept := episodes[:0]
+		for i := range episodes {
+			if !block.Blocks(types.EpisodeSubject(&episodes[i])) {
+				kept = append(kept, episodes[i])
+			}
+		}
+
+		responseEpisodes := types.WithEpisodePalettes(c, deps, types.FromModelEpisodeList(kept))
+		c.JSON(http.StatusOK, types.PersonEpisodesResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Fetched episodes for person",
+			},
+			Person:   types.FromModelPerson(person),
+			Episodes: responseEpisodes,
+			Count:    len(responseEpisodes),
+			Total:    int(total),
+			Offset:   offset,
+		})
+	}
+}
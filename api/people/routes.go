@@ -0,0 +1,12 @@
+package people
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// RegisterRoutes registers people routes
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// GET /api/v1/people/:id/episodes - Episodes a person is credited on
+	router.GET("/:id/episodes", GetEpisodes(deps))
+}
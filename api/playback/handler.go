@@ -0,0 +1,159 @@
+package playback
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	playbackService "github.com/killallgit/player-api/internal/services/playback"
+)
+
+// UpdateProgress records the authenticated user's position in an episode
+// @Summary      Report playback progress
+// @Description  Record the authenticated user's position in an episode. The episode is marked completed when
+// @Description  'completed' is true or the position reaches 95% of the duration; completed episodes are excluded
+// @Description  from /random results for the user.
+// @Tags         playback
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        request body types.PlaybackProgressRequest true "Current position"
+// @Success      200 {object} models.PlaybackProgress "Updated progress"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID or request body"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      500 {object} types.ErrorResponse "Failed to save progress"
+// @Router       /api/v1/episodes/{id}/progress [put]
+func UpdateProgress(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID <= 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid Podcast Index Episode ID",
+			})
+			return
+		}
+
+		userID := c.GetString("user_id")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeUnauthorized,
+				Message: "Authentication required",
+			})
+			return
+		}
+
+		var req types.PlaybackProgressRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid request format",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		if deps.PlaybackService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Playback service not available",
+			})
+			return
+		}
+
+		progress, err := deps.PlaybackService.UpdateProgress(c.Request.Context(), userID, episodeID, req.Position, req.Duration, req.Completed)
+		if err != nil {
+			if errors.Is(err, playbackService.ErrInvalidPosition) {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to save progress",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, progress)
+	}
+}
+
+// GetProgress returns the authenticated user's position in an episode
+// @Summary      Get playback progress
+// @Description  Return the authenticated user's last reported position in an episode.
+// @Tags         playback
+// @Produce      json
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Success      200 {object} models.PlaybackProgress "Current progress"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID"
+// @Failure      401 {object} types.ErrorResponse "Authentication required"
+// @Failure      404 {object} types.ErrorResponse "No progress recorded"
+// @Failure      500 {object} types.ErrorResponse "Failed to load progress"
+// @Router       /api/v1/episodes/{id}/progress [get]
+func GetProgress(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID <= 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid Podcast Index Episode ID",
+			})
+			return
+		}
+
+		userID := c.GetString("user_id")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeUnauthorized,
+				Message: "Authentication required",
+			})
+			return
+		}
+
+		if deps.PlaybackService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Playback service not available",
+			})
+			return
+		}
+
+		c.Header("Cache-Control", "private, no-store")
+
+		progress, err := deps.PlaybackService.GetProgress(c.Request.Context(), userID, episodeID)
+		if err != nil {
+			if errors.Is(err, playbackService.ErrProgressNotFound) {
+				c.JSON(http.StatusNotFound, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeProgressNotFound,
+					Message: "No progress recorded for episode",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to load progress",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, progress)
+	}
+}
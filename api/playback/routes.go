@@ -0,0 +1,12 @@
+package playback
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// RegisterRoutes registers playback progress routes on the episodes group
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	router.PUT("/:id/progress", UpdateProgress(deps))
+	router.GET("/:id/progress", GetProgress(deps))
+}
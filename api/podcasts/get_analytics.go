@@ -0,0 +1,65 @@
+package podcasts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/analytics"
+)
+
+// AnalyticsResponse wraps podcast-level analytics
+type AnalyticsResponse struct {
+	types.BaseResponse
+	Analytics *analytics.PodcastAnalytics `json:"analytics"`
+}
+
+// GetAnalytics returns ad density and clip label distribution for a podcast
+// @Summary      Get podcast analytics
+// @Description  Summarizes a podcast's analyzed episodes: average ad time per hour of audio, clip label
+// @Description  distribution, average episode duration and transcript availability. Figures come from
+// @Description  per-episode stats refreshed as transcription, waveform, clip extraction and auto-label
+// @Description  jobs complete, so episodes count once any such job has finished for them. Ad time is the
+// @Description  union of approved clips carrying one of clips.skip_labels; rejected clips are ignored.
+// @Description  'listening' sums client playback events (POST /api/v1/events) still within events.retention.
+// @Tags         podcasts
+// @Produce      json
+// @Param        id path int64 true "Podcast's Podcast Index ID" minimum(1) example(6780065)
+// @Success      200 {object} AnalyticsResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid podcast ID format"
+// @Failure      404 {object} types.ErrorResponse "No analyzed episodes or playback events for this podcast"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/podcasts/{id}/analytics [get]
+func GetAnalytics(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		podcastID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+
+		if deps.AnalyticsService == nil {
+			types.SendInternalError(c, "Analytics service not available")
+			return
+		}
+
+		result, err := deps.AnalyticsService.PodcastAnalytics(c.Request.Context(), podcastID)
+		if errors.Is(err, analytics.ErrNoAnalytics) {
+			types.SendNotFound(c, "No analytics for this podcast yet")
+			return
+		}
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to compute analytics: %v", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, AnalyticsResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: fmt.Sprintf("Analytics across %d analyzed episodes", result.AnalyzedEpisodes),
+			},
+			Analytics: result,
+		})
+	}
+}
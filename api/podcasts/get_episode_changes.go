@@ -0,0 +1,91 @@
+package podcasts
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/blocklist"
+	"github.com/killallgit/player-api/internal/services/episodes"
+)
+
+// GetEpisodeChanges returns a podcast's episodes changed since a cursor
+// @Summary      Get episode changes for a podcast
+// @Description  For clients keeping their own copy of a podcast's episodes: returns the stored episodes created,
+// @Description  updated or deleted since 'since', oldest change first. Start without 'since' to get every stored
+// @Description  episode, then pass 'nextCursor' from each response as 'since' on the next call; while 'hasMore'
+// @Description  is true there are more changes to fetch straight away. Cursors are opaque. Only stored episodes
+// @Description  are considered; use /podcasts/{id}/episodes to sync the podcast from Podcast Index first.
+// @Tags         podcasts
+// @Produce      json
+// @Param        id path int64 true "Podcast's Podcast Index ID (feedId)" minimum(1)
+// @Param        since query string false "Cursor from a previous response's nextCursor"
+// @Param        limit query int false "Maximum changes to return (1-500)" default(100)
+// @Success      200 {object} types.EpisodeChangesResponse "Changed episodes and the cursor to continue from"
+// @Failure      400 {object} types.ErrorResponse "Invalid podcast ID, cursor or limit"
+// @Failure      404 {object} types.ErrorResponse "Podcast not found"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/podcasts/{id}/episodes/changes [get]
+func GetEpisodeChanges(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Pollers repeat the same cursor until something changes, so a cached
+		// "no changes" would hide new episodes for the whole podcast cache TTL
+		c.Header("Cache-Control", "private, no-store")
+
+		podcastID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit < 1 || limit > episodes.MaxChangesLimit {
+			types.SendBadRequest(c, fmt.Sprintf("limit must be between 1 and %d", episodes.MaxChangesLimit))
+			return
+		}
+
+		block := types.Blocklist(c, deps)
+		if block.Blocks(blocklist.Subject{FeedID: podcastID}) {
+			types.SendError(c, http.StatusNotFound, types.CodePodcastNotFound, "Podcast not found")
+			return
+		}
+
+		changes, err := deps.EpisodeService.GetEpisodeChanges(c.Request.Context(), podcastID, c.Query("since"), limit)
+		if err != nil {
+			types.SendServiceError(c, err, "Failed to get episode changes")
+			return
+		}
+
+		// Episodes on a blocked domain are gone as far as the client is concerned
+		deleted := make([]int64, 0, len(changes.Deleted))
+		for _, episode := range changes.Deleted {
+			deleted = append(deleted, episode.PodcastIndexID)
+		}
+		keep := func(list []models.Episode) []models.Episode {
+			kept := make([]models.Episode, 0, len(list))
+			for i := range list {
+				if block.Blocks(types.EpisodeSubject(&list[i])) {
+					deleted = append(deleted, list[i].PodcastIndexID)
+					continue
+				}
+				kept = append(kept, list[i])
+			}
+			return kept
+		}
+		created := types.FromModelEpisodeList(keep(changes.Created))
+		updated := types.FromModelEpisodeList(keep(changes.Updated))
+
+		c.JSON(http.StatusOK, types.EpisodeChangesResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: fmt.Sprintf("%d episode changes", len(created)+len(updated)+len(deleted)),
+			},
+			Created:    created,
+			Updated:    updated,
+			Deleted:    deleted,
+			NextCursor: changes.Next.String(),
+			HasMore:    changes.HasMore,
+		})
+	}
+}
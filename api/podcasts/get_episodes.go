@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/blocklist"
 )
 
 // GetEpisodesForPodcast returns episodes for a specific podcast
@@ -24,7 +25,7 @@ import (
 // @Success      200 {object} types.EpisodesResponse "List of episodes with full metadata including audio URLs"
 // @Failure      400 {object} types.ErrorResponse "Invalid podcast ID format or out of range"
 // @Failure      500 {object} types.ErrorResponse "Failed to fetch episodes from Podcast Index API"
-// @Failure      503 {object} types.ErrorResponse "Podcast Index API credentials not configured"
+// @Failure      503 {object} types.ErrorResponse "Podcast Index API credentials not configured, or the daily Podcast Index budget is spent"
 // @Router       /api/v1/podcasts/{id}/episodes [get]
 func GetEpisodesForPodcast(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -40,6 +41,13 @@ func GetEpisodesForPodcast(deps *types.Dependencies) gin.HandlerFunc {
 			max = 20
 		}
 
+		// Blocked feeds are refused before anything is synced from Podcast Index
+		block := types.Blocklist(c, deps)
+		if block.Blocks(blocklist.Subject{FeedID: podcastID}) {
+			types.SendError(c, http.StatusNotFound, types.CodePodcastNotFound, "Podcast not found")
+			return
+		}
+
 		// Get episodes using DB-first approach with automatic API fallback
 		// The service will check DB first, and fetch from API if needed
 		episodes, _, err := deps.EpisodeService.GetEpisodesByPodcastIndexFeedID(c.Request.Context(), podcastID, 1, max)
@@ -50,23 +58,52 @@ func GetEpisodesForPodcast(deps *types.Dependencies) gin.HandlerFunc {
 			if err.Error() == "podcast API client not available - check Podcast Index API credentials" {
 				c.JSON(http.StatusServiceUnavailable, types.ErrorResponse{
 					Status:  types.StatusError,
+					Code:    types.CodeServiceUnavailable,
 					Message: "Podcast API service is not configured",
 					Details: "The server is not properly configured to fetch podcast data. Please contact the administrator.",
 				})
 				return
 			}
 
+			// A spent Podcast Index budget and other known errors keep their status
+			if _, _, _, known := types.LookupServiceError(err); known {
+				types.SendServiceError(c, err, "Failed to fetch episodes")
+				return
+			}
+
 			// Return error to client
 			c.JSON(http.StatusNotFound, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeNotFound,
 				Message: "Failed to fetch episodes",
 				Details: err.Error(),
 			})
 			return
 		}
 
+		// Enclosures can still be hosted on a blocked domain
+		kept := episodes[:0]
+		for i := range episodes {
+			if !block.Blocks(types.EpisodeSubject(&episodes[i])) {
+				kept = append(kept, episodes[i])
+			}
+		}
+		episodes = kept
+
 		// Transform database episodes to API response type
 		responseEpisodes := types.FromModelEpisodeList(episodes)
+
+		// Processing flags for the whole page come from one aggregate query
+		ids := make([]int64, len(episodes))
+		for i := range episodes {
+			ids[i] = episodes[i].PodcastIndexID
+		}
+		if statuses, err := deps.EpisodeService.GetEpisodeStatuses(c.Request.Context(), ids); err != nil {
+			log.Printf("[WARN] Failed to get episode statuses for podcast %d: %v", podcastID, err)
+		} else {
+			responseEpisodes = types.WithEpisodeStatuses(responseEpisodes, statuses)
+		}
+		responseEpisodes = types.WithEpisodePalettes(c, deps, responseEpisodes)
 		c.JSON(http.StatusOK, types.EpisodesResponse{
 			BaseResponse: types.BaseResponse{
 				Status:  types.StatusOK,
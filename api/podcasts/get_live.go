@@ -0,0 +1,94 @@
+package podcasts
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/blocklist"
+)
+
+// GetLiveItems returns a podcast's live and upcoming streams
+// @Summary      Get live and upcoming streams
+// @Description  Lists the streams a podcast announces with podcast:liveItem: those broadcasting now and those
+// @Description  scheduled to start, soonest first. Ended streams are left out, as are scheduled ones that are
+// @Description  live_items.start_grace past their start without going live. Items are fetched from Podcast Index
+// @Description  when older than live_items.stale_after, and their statuses are rechecked in the background
+// @Description  from live_items.lead_time before each scheduled start until the stream ends.
+// @Tags         podcasts
+// @Produce      json
+// @Param        id path int64 true "Podcast's Podcast Index ID" minimum(1) example(6780065)
+// @Success      200 {object} types.LiveItemsResponse
+// @Failure      400 {object} types.ErrorResponse "Invalid podcast ID format"
+// @Failure      404 {object} types.ErrorResponse "Podcast not found"
+// @Failure      502 {object} types.ErrorResponse "Podcast Index could not be reached"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/podcasts/{id}/live [get]
+func GetLiveItems(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Whether a stream is live changes by the minute; the podcast cache TTL is hours
+		c.Header("Cache-Control", "private, no-store")
+
+		podcastID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+
+		if types.Blocklist(c, deps).Blocks(blocklist.Subject{FeedID: podcastID}) {
+			types.SendError(c, http.StatusNotFound, types.CodePodcastNotFound, "Podcast not found")
+			return
+		}
+
+		if deps.LiveItemService == nil {
+			types.SendInternalError(c, "Live item service not available")
+			return
+		}
+
+		items, err := deps.LiveItemService.List(c.Request.Context(), podcastID)
+		if err != nil {
+			log.Printf("[ERROR] Failed to list live items for podcast %d: %v", podcastID, err)
+			c.JSON(http.StatusBadGateway, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeUpstreamError,
+				Message: "Failed to fetch live items",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		response := types.LiveItemsResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK},
+			Live:         []types.LiveItem{},
+			Upcoming:     []types.LiveItem{},
+		}
+		for i := range items {
+			if items[i].IsLive() {
+				response.Live = append(response.Live, toLiveItem(&items[i]))
+			} else {
+				response.Upcoming = append(response.Upcoming, toLiveItem(&items[i]))
+			}
+		}
+		response.Message = fmt.Sprintf("%d live, %d upcoming", len(response.Live), len(response.Upcoming))
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func toLiveItem(item *models.LiveItem) types.LiveItem {
+	return types.LiveItem{
+		ID:            item.PodcastIndexID,
+		GUID:          item.GUID,
+		Title:         item.Title,
+		Description:   item.Description,
+		Status:        item.Status,
+		StartTime:     item.StartTime,
+		EndTime:       item.EndTime,
+		EnclosureURL:  item.EnclosureURL,
+		EnclosureType: item.EnclosureType,
+		ContentLink:   item.ContentLink,
+		Image:         item.Image,
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
 )
 
 // GetPodcast returns podcast details by Podcast Index ID
@@ -13,6 +14,8 @@ import (
 // @Description  Retrieve detailed information about a specific podcast using its Podcast Index ID.
 // @Description  Data is fetched from the database if available, otherwise retrieved from Podcast Index API.
 // @Description  Podcast metadata is automatically cached and refreshed if older than 24 hours.
+// @Description  'health' is set to degraded or dead when the feed or its episode audio keeps failing to fetch.
+// @Description  'artwork_palette' lists the artwork's dominant colors, extracted on first request.
 // @Tags         podcasts
 // @Accept       json
 // @Produce      json
@@ -21,6 +24,7 @@ import (
 // @Failure      400 {object} types.ErrorResponse "Invalid podcast ID format"
 // @Failure      404 {object} types.ErrorResponse "Podcast not found"
 // @Failure      500 {object} types.ErrorResponse "Failed to fetch podcast"
+// @Failure      503 {object} types.ErrorResponse "Daily Podcast Index budget spent"
 // @Router       /api/v1/podcasts/{id} [get]
 func GetPodcast(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -32,20 +36,41 @@ func GetPodcast(deps *types.Dependencies) gin.HandlerFunc {
 		podcast, err := deps.PodcastService.GetPodcastByPodcastIndexID(c.Request.Context(), podcastID)
 		if err != nil {
 			log.Printf("[ERROR] Failed to get podcast %d: %v", podcastID, err)
+			// A spent Podcast Index budget and other known errors keep their status
+			if _, _, _, known := types.LookupServiceError(err); known {
+				types.SendServiceError(c, err, "Failed to fetch podcast")
+				return
+			}
 			c.JSON(http.StatusNotFound, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodePodcastNotFound,
 				Message: "Podcast not found",
 				Details: err.Error(),
 			})
 			return
 		}
+		if types.Blocklist(c, deps).Blocks(types.PodcastSubject(podcast)) {
+			types.SendError(c, http.StatusNotFound, types.CodePodcastNotFound, "Podcast not found")
+			return
+		}
 
-		c.JSON(http.StatusOK, types.SinglePodcastResponse{
+		response := types.SinglePodcastResponse{
 			BaseResponse: types.BaseResponse{
 				Status:  types.StatusOK,
 				Message: "Podcast retrieved successfully",
 			},
 			Podcast: types.FromModelPodcast(podcast),
-		})
+		}
+		if deps.FeedHealthService != nil {
+			status, err := deps.FeedHealthService.Status(c.Request.Context(), podcastID)
+			if err != nil {
+				log.Printf("[WARN] Failed to load feed health for podcast %d: %v", podcastID, err)
+			} else if status != models.FeedHealthy {
+				response.Podcast.Health = string(status)
+			}
+		}
+		response.Podcast.ArtworkPalette = types.ArtworkPalette(c, deps, response.Podcast.Image)
+
+		c.JSON(http.StatusOK, response)
 	}
 }
@@ -13,4 +13,13 @@ func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies, podcastMi
 
 	// GET /api/v1/podcasts/:id/episodes - Get episodes for a podcast by feedId
 	router.GET("/:id/episodes", episodesMiddleware, GetEpisodesForPodcast(deps))
+
+	// GET /api/v1/podcasts/:id/episodes/changes - Episodes created, updated or deleted since a cursor
+	router.GET("/:id/episodes/changes", episodesMiddleware, GetEpisodeChanges(deps))
+
+	// GET /api/v1/podcasts/:id/analytics - Ad density and clip label distribution across analyzed episodes
+	router.GET("/:id/analytics", podcastMiddleware, GetAnalytics(deps))
+
+	// GET /api/v1/podcasts/:id/live - Streams the podcast is broadcasting or has scheduled
+	router.GET("/:id/live", podcastMiddleware, GetLiveItems(deps))
 }
@@ -1,6 +1,7 @@
 package random
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -8,19 +9,32 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
 	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
 )
 
+// oversampleFactor is how many extra episodes to request from Podcast Index when
+// results are filtered locally, so the response can still be filled after filtering
+const oversampleFactor = 3
+
 // Get returns random podcast episodes
 // @Summary Get random podcast episodes
-// @Description Returns random podcast episodes from Podcast Index with optional language and category filtering.
+// @Description Returns random podcast episodes from Podcast Index with optional language, category and duration filtering.
 // @Description Useful for discovering new content. Episodes are randomly selected from recent additions to the index.
+// @Description For authenticated users, episodes they have already finished are excluded unless exclude_played=false.
+// @Description Duration and played filters are applied after fetching, so fewer than 'limit' episodes may be returned.
 // @Tags random
 // @Produce json
 // @Param limit query int false "Number of episodes to return (1-100)" default(10) minimum(1) maximum(100)
 // @Param lang query string false "Language code filter (e.g., 'en', 'es', 'fr')" default(en)
+// @Param cat query string false "Comma-separated categories to include (e.g., 'Technology,Science')"
 // @Param notcat query string false "Comma-separated categories to exclude (e.g., 'News,Politics')"
+// @Param min_duration query int false "Minimum episode duration in seconds"
+// @Param max_duration query int false "Maximum episode duration in seconds"
+// @Param exclude_played query bool false "Exclude episodes the authenticated user has finished" default(true)
 // @Success 200 {object} models.EpisodeResponse "Random episodes with metadata"
+// @Failure 400 {object} types.ErrorResponse "Invalid duration range"
 // @Failure 500 {object} types.ErrorResponse "Podcast Index API unavailable or communication failure"
+// @Failure 503 {object} types.ErrorResponse "Daily Podcast Index budget spent"
 // @Router /api/v1/random [get]
 func Get(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -37,47 +51,141 @@ func Get(deps *types.Dependencies) gin.HandlerFunc {
 		// Parse language parameter
 		lang := c.DefaultQuery("lang", "en")
 
-		// Parse notcat parameter
-		var notCategories []string
-		if notcat := c.Query("notcat"); notcat != "" {
-			// Split by comma and trim spaces
-			categories := strings.Split(notcat, ",")
-			for _, cat := range categories {
-				trimmed := strings.TrimSpace(cat)
-				if trimmed != "" {
-					notCategories = append(notCategories, trimmed)
-				}
+		// Parse category include/exclude lists
+		categories := parseList(c.Query("cat"))
+		notCategories := parseList(c.Query("notcat"))
+
+		// Parse duration bounds (seconds)
+		minDuration, err := parseDuration(c.Query("min_duration"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "min_duration must be a non-negative integer",
+			})
+			return
+		}
+		maxDuration, err := parseDuration(c.Query("max_duration"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "max_duration must be a non-negative integer",
+			})
+			return
+		}
+		if maxDuration > 0 && minDuration > maxDuration {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "min_duration cannot be greater than max_duration",
+			})
+			return
+		}
+
+		// Look up the authenticated user's finished episodes
+		var played map[int64]struct{}
+		userID := c.GetString("user_id")
+		if userID != "" && c.Query("exclude_played") != "false" && deps.PlaybackService != nil {
+			played, err = deps.PlaybackService.PlayedEpisodeIDs(c.Request.Context(), userID)
+			if err != nil {
+				log.Printf("[WARN] Failed to load played episodes for user %s: %v", userID, err)
 			}
 		}
 
-		// Call Podcast Index API
+		// Over-fetch when results are filtered locally
+		fetchLimit := limit
+		if minDuration > 0 || maxDuration > 0 || len(played) > 0 {
+			fetchLimit = limit * oversampleFactor
+			if fetchLimit > 100 {
+				fetchLimit = 100
+			}
+		}
+
+		// Call Podcast Index API; blocked categories are excluded upstream but
+		// not echoed back in notcat
+		block := types.Blocklist(c, deps)
 		episodes, err := deps.PodcastClient.GetRandomEpisodes(
 			c.Request.Context(),
-			limit,
+			fetchLimit,
 			lang,
-			notCategories,
+			categories,
+			block.Categories(notCategories),
 		)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-				Status:  types.StatusError,
-				Message: "Failed to fetch random episodes",
-				Details: err.Error(),
-			})
+			types.SendServiceError(c, err, "Failed to fetch random episodes")
 			return
 		}
 
+		results := filterEpisodes(block.FilterEpisodes(episodes.Items), minDuration, maxDuration, played, limit)
+
 		// Build episode response with consistent format
 		response := models.EpisodeResponse{
 			Status:      episodes.Status,
-			Results:     episodes.Items,
-			TotalCount:  episodes.Count,
+			Results:     results,
+			TotalCount:  len(results),
 			Max:         strconv.Itoa(limit), // Convert to string to match PodcastIndex API format
 			Lang:        lang,
+			Cat:         categories,
 			NotCat:      notCategories,
 			Description: episodes.Description,
 		}
 
+		// Results depend on the caller's history when played episodes were excluded
+		if played != nil {
+			c.Header("Cache-Control", "private, no-store")
+		}
+
 		// Return the episode response
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+// parseList splits a comma-separated query value, trimming spaces and dropping empties
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		trimmed := strings.TrimSpace(item)
+		if trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// parseDuration parses an optional non-negative number of seconds; empty means no bound
+func parseDuration(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return seconds, nil
+}
+
+// filterEpisodes applies duration bounds and played exclusion, keeping at most limit episodes.
+// Episodes with unknown duration (0) are dropped when any duration bound is set.
+func filterEpisodes(items []podcastindex.Episode, minDuration, maxDuration int, played map[int64]struct{}, limit int) []podcastindex.Episode {
+	results := make([]podcastindex.Episode, 0, limit)
+	for _, episode := range items {
+		if len(results) >= limit {
+			break
+		}
+		if (minDuration > 0 || maxDuration > 0) && episode.Duration <= 0 {
+			continue
+		}
+		if minDuration > 0 && episode.Duration < minDuration {
+			continue
+		}
+		if maxDuration > 0 && episode.Duration > maxDuration {
+			continue
+		}
+		if _, ok := played[episode.ID]; ok {
+			continue
+		}
+		results = append(results, episode)
+	}
+	return results
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/internal/services/podcastindex"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,12 +17,12 @@ import (
 
 // Mock client for testing
 type mockRandomClient struct {
-	getRandomFunc func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error)
+	getRandomFunc func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error)
 }
 
-func (m *mockRandomClient) GetRandomEpisodes(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+func (m *mockRandomClient) GetRandomEpisodes(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 	if m.getRandomFunc != nil {
-		return m.getRandomFunc(ctx, max, lang, notCategories)
+		return m.getRandomFunc(ctx, max, lang, categories, notCategories)
 	}
 	return &podcastindex.EpisodesResponse{
 		Status:      "true",
@@ -36,11 +37,11 @@ func (m *mockRandomClient) Search(ctx context.Context, query string, limit int,
 	return &podcastindex.SearchResponse{}, nil
 }
 
-func (m *mockRandomClient) GetTrending(ctx context.Context, max, since int, categories []string, lang string, fullText bool) (*podcastindex.SearchResponse, error) {
+func (m *mockRandomClient) GetTrending(ctx context.Context, max, since int, categories, notCategories []string, lang string, fullText bool) (*podcastindex.SearchResponse, error) {
 	return &podcastindex.SearchResponse{}, nil
 }
 
-func (m *mockRandomClient) GetCategories() (*podcastindex.CategoriesResponse, error) {
+func (m *mockRandomClient) GetCategories(ctx context.Context) (*podcastindex.CategoriesResponse, error) {
 	return &podcastindex.CategoriesResponse{}, nil
 }
 
@@ -70,16 +71,17 @@ func TestGet(t *testing.T) {
 	tests := []struct {
 		name           string
 		queryParams    map[string]string
-		mockFunc       func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error)
+		mockFunc       func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error)
 		expectedStatus int
 		expectedMax    int
 		expectedLang   string
 		expectedNotCat []string
+		expectedIDs    []int64
 	}{
 		{
 			name:        "Default parameters",
 			queryParams: map[string]string{},
-			mockFunc: func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 				assert.Equal(t, 10, max)
 				assert.Equal(t, "en", lang)
 				assert.Empty(t, notCategories)
@@ -99,7 +101,7 @@ func TestGet(t *testing.T) {
 			queryParams: map[string]string{
 				"limit": "5",
 			},
-			mockFunc: func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 				assert.Equal(t, 5, max)
 				assert.Equal(t, "en", lang)
 				return &podcastindex.EpisodesResponse{
@@ -115,7 +117,7 @@ func TestGet(t *testing.T) {
 			queryParams: map[string]string{
 				"limit": "200",
 			},
-			mockFunc: func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 				assert.Equal(t, 100, max) // Should be capped at 100
 				return &podcastindex.EpisodesResponse{
 					Status: "true",
@@ -130,7 +132,7 @@ func TestGet(t *testing.T) {
 			queryParams: map[string]string{
 				"limit": "invalid",
 			},
-			mockFunc: func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 				assert.Equal(t, 10, max) // Should default to 10
 				return &podcastindex.EpisodesResponse{
 					Status: "true",
@@ -145,7 +147,7 @@ func TestGet(t *testing.T) {
 			queryParams: map[string]string{
 				"lang": "es",
 			},
-			mockFunc: func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 				assert.Equal(t, "es", lang)
 				return &podcastindex.EpisodesResponse{
 					Status: "true",
@@ -160,7 +162,7 @@ func TestGet(t *testing.T) {
 			queryParams: map[string]string{
 				"notcat": "News,Politics,Religion",
 			},
-			mockFunc: func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 				assert.Equal(t, []string{"News", "Politics", "Religion"}, notCategories)
 				return &podcastindex.EpisodesResponse{
 					Status: "true",
@@ -175,7 +177,7 @@ func TestGet(t *testing.T) {
 			queryParams: map[string]string{
 				"notcat": "News, Politics , Religion",
 			},
-			mockFunc: func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 				assert.Equal(t, []string{"News", "Politics", "Religion"}, notCategories)
 				return &podcastindex.EpisodesResponse{
 					Status: "true",
@@ -185,10 +187,64 @@ func TestGet(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "Include categories",
+			queryParams: map[string]string{
+				"cat": "Technology,Science",
+			},
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+				assert.Equal(t, []string{"Technology", "Science"}, categories)
+				assert.Empty(t, notCategories)
+				return &podcastindex.EpisodesResponse{
+					Status: "true",
+					Items:  []podcastindex.Episode{},
+					Count:  0,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Duration filter oversamples",
+			queryParams: map[string]string{
+				"limit":        "5",
+				"min_duration": "600",
+				"max_duration": "1800",
+			},
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+				assert.Equal(t, 15, max)
+				return &podcastindex.EpisodesResponse{
+					Status: "true",
+					Items: []podcastindex.Episode{
+						{ID: 1, Duration: 300},
+						{ID: 2, Duration: 900},
+						{ID: 3, Duration: 0},
+						{ID: 4, Duration: 3600},
+					},
+					Count: 4,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []int64{2},
+		},
+		{
+			name: "Inverted duration range",
+			queryParams: map[string]string{
+				"min_duration": "1800",
+				"max_duration": "600",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid duration",
+			queryParams: map[string]string{
+				"min_duration": "-5",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:        "API error",
 			queryParams: map[string]string{},
-			mockFunc: func(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+			mockFunc: func(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 				return nil, assert.AnError
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -244,6 +300,16 @@ func TestGet(t *testing.T) {
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				require.NoError(t, err)
 				assert.Equal(t, "true", response.Status)
+
+				if tt.expectedIDs != nil {
+					var filtered models.EpisodeResponse
+					require.NoError(t, json.Unmarshal(w.Body.Bytes(), &filtered))
+					ids := make([]int64, 0, len(filtered.Results))
+					for _, item := range filtered.Results {
+						ids = append(ids, item.ID)
+					}
+					assert.Equal(t, tt.expectedIDs, ids)
+				}
 			}
 		})
 	}
@@ -1,8 +1,12 @@
 package api
 
 import (
+	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
+	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
@@ -10,48 +14,78 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	adminAPI "github.com/killallgit/player-api/api/admin"
 	authAPI "github.com/killallgit/player-api/api/auth"
 	"github.com/killallgit/player-api/api/categories"
+	datasetsAPI "github.com/killallgit/player-api/api/datasets"
+	"github.com/killallgit/player-api/api/discover"
 	"github.com/killallgit/player-api/api/episodes"
+	eventsAPI "github.com/killallgit/player-api/api/events"
 	"github.com/killallgit/player-api/api/health"
+	jobsAPI "github.com/killallgit/player-api/api/jobs"
+	meAPI "github.com/killallgit/player-api/api/me"
 	"github.com/killallgit/player-api/api/middleware"
+	peopleAPI "github.com/killallgit/player-api/api/people"
+	playbackAPI "github.com/killallgit/player-api/api/playback"
 	"github.com/killallgit/player-api/api/podcasts"
 	"github.com/killallgit/player-api/api/random"
 	"github.com/killallgit/player-api/api/search"
+	"github.com/killallgit/player-api/api/share"
+	summaryAPI "github.com/killallgit/player-api/api/summary"
 	transcriptionAPI "github.com/killallgit/player-api/api/transcription"
 	"github.com/killallgit/player-api/api/trending"
 	"github.com/killallgit/player-api/api/types"
 	"github.com/killallgit/player-api/api/version"
 	"github.com/killallgit/player-api/api/waveform"
 	_ "github.com/killallgit/player-api/docs"
+	"github.com/killallgit/player-api/internal/models"
+	analyticsService "github.com/killallgit/player-api/internal/services/analytics"
+	artworkService "github.com/killallgit/player-api/internal/services/artwork"
 	"github.com/killallgit/player-api/internal/services/audiocache"
 	authService "github.com/killallgit/player-api/internal/services/auth"
+	"github.com/killallgit/player-api/internal/services/autoapproval"
+	autoplayService "github.com/killallgit/player-api/internal/services/autoplay"
+	blocklistService "github.com/killallgit/player-api/internal/services/blocklist"
 	"github.com/killallgit/player-api/internal/services/cache"
+	categoriesService "github.com/killallgit/player-api/internal/services/categories"
 	clipsService "github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/contentsafety"
+	datasetsService "github.com/killallgit/player-api/internal/services/datasets"
 	episodeanalysis "github.com/killallgit/player-api/internal/services/episode_analysis"
 	episodesService "github.com/killallgit/player-api/internal/services/episodes"
+	eventsService "github.com/killallgit/player-api/internal/services/events"
+	"github.com/killallgit/player-api/internal/services/externalusage"
+	feedhealthService "github.com/killallgit/player-api/internal/services/feedhealth"
+	importsService "github.com/killallgit/player-api/internal/services/imports"
 	"github.com/killallgit/player-api/internal/services/itunes"
 	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/liveitems"
+	"github.com/killallgit/player-api/internal/services/loudness"
+	"github.com/killallgit/player-api/internal/services/modelregistry"
+	notificationsService "github.com/killallgit/player-api/internal/services/notifications"
+	peopleService "github.com/killallgit/player-api/internal/services/people"
+	"github.com/killallgit/player-api/internal/services/pipeline"
+	playbackService "github.com/killallgit/player-api/internal/services/playback"
 	"github.com/killallgit/player-api/internal/services/podcastindex"
 	podcastsService "github.com/killallgit/player-api/internal/services/podcasts"
+	preferencesService "github.com/killallgit/player-api/internal/services/preferences"
+	rightsService "github.com/killallgit/player-api/internal/services/rights"
+	savedSearchesService "github.com/killallgit/player-api/internal/services/savedsearches"
+	"github.com/killallgit/player-api/internal/services/streamcache"
+	subscriptionsService "github.com/killallgit/player-api/internal/services/subscriptions"
+	summaryService "github.com/killallgit/player-api/internal/services/summary"
 	"github.com/killallgit/player-api/internal/services/transcription"
+	userdataService "github.com/killallgit/player-api/internal/services/userdata"
 	"github.com/killallgit/player-api/internal/services/waveforms"
+	webhooksService "github.com/killallgit/player-api/internal/services/webhooks"
+	"github.com/killallgit/player-api/pkg/chapters"
 	"github.com/killallgit/player-api/pkg/config"
+	"github.com/killallgit/player-api/pkg/download"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 	"github.com/spf13/viper"
 )
 
-// Rate limiting constants to avoid duplication
-const (
-	// Search endpoints have stricter limits due to external API calls
-	SearchRateLimit      = 5
-	SearchRateLimitBurst = 10
-
-	// General API endpoints have moderate limits
-	GeneralRateLimit      = 10
-	GeneralRateLimitBurst = 20
-)
-
-func RegisterRoutes(engine *gin.Engine, deps *types.Dependencies, rateLimiters *sync.Map, cleanupStop chan struct{}, cleanupInitialized *sync.Once) error {
+func RegisterRoutes(engine *gin.Engine, deps *types.Dependencies, live *LiveSettings, rateLimiters *sync.Map, cleanupStop chan struct{}, cleanupInitialized *sync.Once) error {
 	health.RegisterRoutes(engine, deps)
 	version.RegisterRoutes(engine, deps)
 
@@ -126,85 +160,167 @@ func RegisterRoutes(engine *gin.Engine, deps *types.Dependencies, rateLimiters *
 			baseURL = cfg.PodcastIndex.BaseURL
 		}
 
+		if deps.ExternalUsageService == nil && deps.DB != nil && deps.DB.DB != nil {
+			initializeExternalUsageService(deps)
+		}
+		var usage podcastindex.UsageTracker
+		if deps.ExternalUsageService != nil {
+			usage = deps.ExternalUsageService.Tracker(externalusage.PodcastIndex)
+		}
+
 		deps.PodcastClient = podcastindex.NewClient(podcastindex.Config{
 			APIKey:    apiKey,
 			APISecret: apiSecret,
 			BaseURL:   baseURL,
+			Usage:     usage,
 		})
 	}
 
 	var cacheMiddleware gin.HandlerFunc
+	var memCache cache.Cache
 	if viper.GetBool("cache.enabled") {
 		maxSizeMB := viper.GetInt64("cache.max_size_mb")
-		memCache := cache.NewMemoryCache(maxSizeMB)
-
-		ttlByPath := make(map[string]time.Duration)
-		ttlByPath["/api/v1/search"] = time.Duration(viper.GetInt("cache.ttl_search")) * time.Minute
-		ttlByPath["/api/v1/trending"] = time.Duration(viper.GetInt("cache.ttl_trending")) * time.Minute
-		ttlByPath["/api/v1/podcasts"] = time.Duration(viper.GetInt("cache.ttl_podcast")) * time.Minute
-		ttlByPath["/api/v1/episodes"] = time.Duration(viper.GetInt("cache.ttl_episode")) * time.Minute
-		ttlByPath["/api/v1/categories"] = time.Duration(viper.GetInt("cache.ttl_categories")) * time.Minute
+		memCache = cache.NewMemoryCache(maxSizeMB)
+		deps.ResponseCache = memCache
 
 		cacheConfig := middleware.CacheConfig{
-			Cache:      memCache,
-			DefaultTTL: viper.GetDuration("cache.default_ttl"),
-			TTLByPath:  ttlByPath,
-			Enabled:    true,
+			Cache:   memCache,
+			TTLs:    live.CacheTTLs,
+			Enabled: true,
 		}
 
 		cacheMiddleware = middleware.CacheMiddleware(cacheConfig)
 	}
 
+	// The blocklist filters the discovery routes below, so it is set up before them
+	if deps.BlocklistService == nil && deps.DB != nil && deps.DB.DB != nil {
+		initializeBlocklistService(deps, memCache)
+	}
+
 	searchGroup := v1.Group("/search")
-	searchGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, SearchRateLimit, SearchRateLimitBurst))
+	searchGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.SearchRateLimits))
 	if cacheMiddleware != nil {
 		searchGroup.Use(cacheMiddleware)
 	}
 	search.RegisterRoutes(searchGroup, deps)
 
 	trendingGroup := v1.Group("/trending")
-	trendingGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, GeneralRateLimit, GeneralRateLimitBurst))
+	trendingGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
 	if cacheMiddleware != nil {
 		trendingGroup.Use(cacheMiddleware)
 	}
 	trending.RegisterRoutes(trendingGroup, deps)
 
 	categoriesGroup := v1.Group("/categories")
-	categoriesGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, GeneralRateLimit, GeneralRateLimitBurst))
+	categoriesGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
 	if cacheMiddleware != nil {
 		categoriesGroup.Use(cacheMiddleware)
 	}
 	categories.RegisterRoutes(categoriesGroup, deps)
 
 	randomGroup := v1.Group("/random")
-	randomGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, GeneralRateLimit, GeneralRateLimitBurst))
+	randomGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
 	random.RegisterRoutes(randomGroup, deps)
 
+	// Discover caches each section itself so partial failures are not cached as a whole response
+	discoverGroup := v1.Group("/discover")
+	discoverGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+	discover.RegisterRoutes(discoverGroup, deps, memCache)
+
 	if deps.DB != nil && deps.DB.DB != nil {
 		initializeAllServices(deps, cfg)
 
 		episodeGroup := v1.Group("/episodes")
-		episodeGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, GeneralRateLimit, GeneralRateLimitBurst))
+		episodeGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
 		if cacheMiddleware != nil {
 			episodeGroup.Use(cacheMiddleware)
 		}
 
 		episodes.RegisterRoutes(episodeGroup, deps)
 		waveform.RegisterRoutes(episodeGroup, deps)
+		playbackAPI.RegisterRoutes(episodeGroup, deps)
+		share.RegisterEpisodeRoutes(episodeGroup, deps)
+
+		// Audio streaming bypasses the response cache; segments are cached on disk instead
+		streamGroup := v1.Group("/episodes")
+		streamGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		episodes.RegisterStreamRoutes(streamGroup, deps)
 
 		if viper.GetBool("transcription.enabled") {
 			transcriptionAPI.RegisterRoutes(episodeGroup, deps)
+			transcriptionAPI.RegisterLiveRoutes(streamGroup, deps)
+
+			// Not cached: installed models change when one is downloaded
+			transcriptionGroup := v1.Group("/transcription")
+			transcriptionGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+			transcriptionAPI.RegisterModelRoutes(transcriptionGroup, deps)
 			log.Println("[INFO] Transcription routes enabled")
 		}
 
+		if viper.GetBool("summary.enabled") {
+			summaryAPI.RegisterRoutes(episodeGroup, deps)
+			log.Println("[INFO] Summary routes enabled")
+		}
+
 		podcastGroup := v1.Group("/podcasts")
-		podcastMiddleware := PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, GeneralRateLimit, GeneralRateLimitBurst)
-		episodesMiddleware := PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, GeneralRateLimit, GeneralRateLimitBurst)
+		podcastMiddleware := PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits)
+		episodesMiddleware := PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits)
 		if cacheMiddleware != nil {
 			podcastGroup.Use(cacheMiddleware)
 		}
 		podcasts.RegisterRoutes(podcastGroup, deps, podcastMiddleware, episodesMiddleware)
 
+		if deps.EventsService != nil {
+			// Write-only, so never behind the response cache
+			eventsGroup := v1.Group("/events")
+			eventsGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+			eventsAPI.RegisterRoutes(eventsGroup, deps)
+		}
+
+		meGroup := v1.Group("/me")
+		meGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		meAPI.RegisterRoutes(meGroup, deps)
+
+		datasetsGroup := v1.Group("/datasets")
+		datasetsGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		datasetsAPI.RegisterRoutes(datasetsGroup, deps)
+
+		// Dataset downloads accept either a bearer token or a signed URL, so they
+		// sit outside v1's auth middleware and apply it themselves
+		var datasetAuth gin.HandlerFunc
+		if authHandler != nil {
+			datasetAuth = authHandler.AuthMiddleware()
+		}
+		datasetDownloads := engine.Group("/api/v1/datasets")
+		datasetDownloads.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		datasetsAPI.RegisterDownloadRoutes(datasetDownloads, deps, datasetAuth)
+
+		// Share links are opened by whoever they are sent to, without a token
+		shareGroup := engine.Group("/share")
+		shareGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		share.RegisterRoutes(shareGroup, deps)
+
+		// Export downloads are authorized by a signed URL rather than a bearer token
+		exportsGroup := engine.Group("/api/v1/exports")
+		exportsGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		meAPI.RegisterDownloadRoutes(exportsGroup, deps)
+
+		jobsGroup := v1.Group("/jobs")
+		jobsGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		jobsAPI.RegisterRoutes(jobsGroup, deps)
+
+		peopleGroup := v1.Group("/people")
+		peopleGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		peopleAPI.RegisterRoutes(peopleGroup, deps)
+
+		if deps.RightsService == nil && deps.DB != nil && deps.DB.DB != nil {
+			deps.RightsService = rightsService.NewService(rightsService.NewRepository(deps.DB.DB))
+		}
+
+		adminGroup := v1.Group("/admin")
+		adminGroup.Use(PerClientRateLimit(rateLimiters, cleanupStop, cleanupInitialized, live.RateLimits))
+		adminAPI.RegisterRoutes(adminGroup, deps)
+
 		// Clips are now handled under /episodes/:id/clips (see episodes routes)
 	}
 
@@ -217,38 +333,165 @@ func initializeAllServices(deps *types.Dependencies, cfg *config.Config) {
 		initializeJobService(deps)
 	}
 
+	// Feed health before the podcast and audio cache services, which report to it
+	if deps.FeedHealthService == nil && viper.GetBool("feed_health.enabled") {
+		initializeFeedHealthService(deps)
+	}
+
 	// Initialize podcast service before episode service (episode service depends on it)
 	if deps.PodcastService == nil {
 		initializePodcastService(deps)
 	}
 
+	if deps.CategoryService == nil {
+		initializeCategoryService(deps)
+	}
+
+	if deps.ArtworkService == nil && viper.GetBool("artwork.palette_enabled") {
+		initializeArtworkService(deps)
+	}
+
+	if deps.PlaybackService == nil {
+		initializePlaybackService(deps)
+	}
+
+	if deps.PreferencesService == nil {
+		initializePreferencesService(deps)
+	}
+
+	if deps.SubscriptionService == nil && deps.PodcastService != nil {
+		initializeSubscriptionService(deps)
+	}
+
+	// People and notification services before episode service so synced episodes
+	// record their credits and reach subscribers
+	if deps.PeopleService == nil {
+		initializePeopleService(deps)
+	}
+
+	if deps.NotificationService == nil {
+		initializeNotificationService(deps)
+	}
+
 	if deps.EpisodeService == nil || deps.EpisodeTransformer == nil {
 		initializeEpisodeService(deps, cfg)
 	}
 
+	if deps.AutoplayService == nil {
+		initializeAutoplayService(deps)
+	}
+
+	if deps.ImportService == nil && deps.SubscriptionService != nil && deps.EpisodeService != nil {
+		initializeImportService(deps)
+	}
+
+	if deps.AnalyticsService == nil {
+		initializeAnalyticsService(deps)
+	}
+
+	if deps.EventsService == nil && viper.GetBool("events.enabled") {
+		initializeEventsService(deps)
+	}
+
+	if deps.FFmpeg == nil {
+		deps.FFmpeg = newFFmpeg()
+	}
+
+	if deps.Chapters == nil {
+		fetchOpts := chapters.DefaultFetchOptions()
+		if timeout := viper.GetDuration("chapters.fetch_timeout"); timeout > 0 {
+			fetchOpts.Timeout = timeout
+		}
+		fetchOpts.CacheTTL = viper.GetDuration("chapters.cache_ttl")
+		deps.Chapters = chapters.NewFetcher(fetchOpts)
+	}
+
 	// Initialize audio cache service (required by waveform, transcription, episode analysis)
 	if deps.AudioCacheService == nil {
 		initializeAudioCacheService(deps)
 	}
 
+	if deps.DownloadPolicies == nil {
+		initializeDownloadPolicies(deps)
+	}
+
+	if deps.StreamCacheService == nil {
+		initializeStreamCacheService(deps)
+	}
+
 	if deps.WaveformService == nil {
 		initializeWaveformService(deps)
 	}
 
+	if deps.LoudnessService == nil && deps.AudioCacheService != nil {
+		initializeLoudnessService(deps)
+	}
+
 	if deps.TranscriptionService == nil {
 		initializeTranscriptionService(deps)
 	}
 
+	if deps.WhisperModels == nil {
+		initializeWhisperModels(deps)
+	}
+
+	if deps.LiveTranscriber == nil && viper.GetBool("transcription.enabled") {
+		initializeLiveTranscriber(deps)
+	}
+
+	if deps.ContentSafetyService == nil && viper.GetBool("content_safety.enabled") {
+		initializeContentSafetyService(deps)
+	}
+
+	if deps.SummaryService == nil && viper.GetBool("summary.enabled") {
+		initializeSummaryService(deps)
+	}
+
 	// Initialize clip service if not set (depends on JobService)
 	if deps.ClipService == nil {
 		initializeClipService(deps)
 	}
 
+	// Export and account deletion (depends on ClipService for the delete clip policy)
+	if deps.UserDataService == nil {
+		initializeUserDataService(deps)
+	}
+
+	if deps.DatasetService == nil && deps.ClipService != nil {
+		initializeDatasetService(deps)
+	}
+
+	if deps.WebhookService == nil {
+		initializeWebhookService(deps)
+	}
+
+	// Saved searches notify through the notification and webhook services
+	if deps.SavedSearchService == nil && deps.PodcastClient != nil && viper.GetBool("saved_searches.enabled") {
+		initializeSavedSearchService(deps)
+	}
+
+	if deps.LiveItemService == nil && deps.PodcastClient != nil && viper.GetBool("live_items.enabled") {
+		initializeLiveItemService(deps)
+	}
+
+	if deps.ModelRegistry == nil && deps.DB != nil && deps.DB.DB != nil {
+		initializeModelRegistry(deps)
+	}
+
+	if deps.AutoApprovalService == nil && deps.DB != nil && deps.DB.DB != nil {
+		initializeAutoApprovalService(deps)
+	}
+
 	// Initialize episode analysis service if not set (depends on AudioCacheService, ClipService, EpisodeService)
 	if deps.EpisodeAnalysisService == nil {
 		initializeEpisodeAnalysisService(deps)
 	}
 
+	// Post-cache pipeline (needs every service whose jobs it queues)
+	if deps.AudioCacheService != nil && deps.JobService != nil {
+		initializePipeline(deps)
+	}
+
 	if deps.ITunesClient == nil {
 		initializeITunesClient(deps)
 	}
@@ -291,6 +534,10 @@ func initializeEpisodeService(deps *types.Dependencies, _ *config.Config) {
 		deps.PodcastService,
 		episodesService.WithMaxConcurrentSync(maxConcurrentSync),
 		episodesService.WithSyncTimeout(syncTimeout),
+		episodesService.WithFetchRetry(config.GetInt("episodes.fetch_attempts"), config.GetDuration("episodes.fetch_backoff")),
+		episodesService.WithMissingEpisodeTTL(config.GetDuration("episodes.missing_ttl")),
+		episodesService.WithPeopleIngester(deps.PeopleService),
+		episodesService.WithNewEpisodeNotifier(deps.NotificationService),
 	)
 
 	deps.EpisodeTransformer = episodesService.NewTransformer()
@@ -309,32 +556,421 @@ func initializePodcastService(deps *types.Dependencies) {
 	}
 
 	podcastRepo := podcastsService.NewRepository(deps.DB.DB)
-	deps.PodcastService = podcastsService.NewService(podcastRepo, podcastClient)
+	var opts []podcastsService.Option
+	if deps.FeedHealthService != nil {
+		opts = append(opts, podcastsService.WithFeedHealth(deps.FeedHealthService))
+	}
+	deps.PodcastService = podcastsService.NewService(podcastRepo, podcastClient, opts...)
 	log.Printf("[INFO] Podcast service initialized successfully")
 }
 
+func initializeFeedHealthService(deps *types.Dependencies) {
+	deps.FeedHealthService = feedhealthService.NewService(
+		feedhealthService.NewRepository(deps.DB.DB),
+		feedhealthService.Config{
+			DegradedAfter: viper.GetInt("feed_health.degraded_after"),
+			DeadAfter:     viper.GetInt("feed_health.dead_after"),
+			DeadMinAge:    viper.GetDuration("feed_health.dead_min_age"),
+		},
+	)
+}
+
+func initializeArtworkService(deps *types.Dependencies) {
+	deps.ArtworkService = artworkService.NewService(
+		artworkService.NewRepository(deps.DB.DB),
+		artworkService.WithPaletteSize(viper.GetInt("artwork.palette_size")),
+		artworkService.WithFetchTimeout(viper.GetDuration("artwork.fetch_timeout")),
+		artworkService.WithRetryAfter(viper.GetDuration("artwork.retry_after")),
+	)
+}
+
+func initializeCategoryService(deps *types.Dependencies) {
+	categoryRepo := categoriesService.NewRepository(deps.DB.DB)
+	deps.CategoryService = categoriesService.NewService(categoryRepo)
+}
+
+func initializePeopleService(deps *types.Dependencies) {
+	deps.PeopleService = peopleService.NewService(peopleService.NewRepository(deps.DB.DB))
+}
+
+func initializeNotificationService(deps *types.Dependencies) {
+	deps.NotificationService = notificationsService.NewService(notificationsService.NewRepository(deps.DB.DB))
+}
+
+func initializeAnalyticsService(deps *types.Dependencies) {
+	deps.AnalyticsService = analyticsService.NewService(
+		analyticsService.NewRepository(deps.DB.DB),
+		viper.GetStringSlice("clips.skip_labels"),
+	)
+}
+
+func initializeEventsService(deps *types.Dependencies) {
+	deps.EventsService = eventsService.NewService(eventsService.NewRepository(deps.DB.DB), eventsService.Config{
+		MaxBatch:   viper.GetInt("events.max_batch"),
+		SampleRate: viper.GetFloat64("events.sample_rate"),
+		Retention:  viper.GetDuration("events.retention"),
+	})
+}
+
+func initializePlaybackService(deps *types.Dependencies) {
+	deps.PlaybackService = playbackService.NewService(playbackService.NewRepository(deps.DB.DB))
+}
+
+func initializeBlocklistService(deps *types.Dependencies, responseCache cache.Cache) {
+	var opts []blocklistService.Option
+	if responseCache != nil {
+		// Cached listings may include content that was just blocked
+		opts = append(opts, blocklistService.WithChangeHook(func(ctx context.Context) {
+			if err := responseCache.Clear(ctx); err != nil {
+				log.Printf("[WARN] Failed to clear response cache after blocklist change: %v", err)
+			}
+		}))
+	}
+
+	svc := blocklistService.NewService(blocklistService.NewRepository(deps.DB.DB), opts...)
+	if err := svc.Reload(context.Background()); err != nil {
+		log.Printf("[ERROR] Failed to load blocklist: %v", err)
+	}
+	deps.BlocklistService = svc
+}
+
+func initializePreferencesService(deps *types.Dependencies) {
+	deps.PreferencesService = preferencesService.NewService(preferencesService.NewRepository(deps.DB.DB))
+}
+
+func initializeSubscriptionService(deps *types.Dependencies) {
+	deps.SubscriptionService = subscriptionsService.NewService(
+		subscriptionsService.NewRepository(deps.DB.DB),
+		deps.PodcastService,
+		subscriptionsService.WithInboxMaxAge(viper.GetDuration("inbox.max_age")),
+	)
+}
+
+func initializeAutoplayService(deps *types.Dependencies) {
+	var inbox autoplayService.InboxSource
+	if deps.SubscriptionService != nil {
+		inbox = deps.SubscriptionService
+	}
+	deps.AutoplayService = autoplayService.NewService(autoplayService.NewRepository(deps.DB.DB), inbox)
+}
+
+func initializeImportService(deps *types.Dependencies) {
+	deps.ImportService = importsService.NewService(
+		importsService.NewRepository(deps.DB.DB),
+		deps.PodcastService,
+		deps.EpisodeService,
+		deps.SubscriptionService,
+		deps.PlaybackService,
+	)
+}
+
+func initializeUserDataService(deps *types.Dependencies) {
+	secret := []byte(viper.GetString("export.signing_secret"))
+	if len(secret) == 0 {
+		// Links signed with a random key stop working when the process restarts
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Printf("[ERROR] Failed to generate export signing key: %v", err)
+			return
+		}
+		log.Printf("[WARN] export.signing_secret not set; export download links will not survive a restart")
+	}
+	opts := []userdataService.Option{
+		userdataService.WithClipPolicy(userdataService.ClipPolicy(viper.GetString("account_deletion.clip_policy"))),
+	}
+	if deps.ClipService != nil {
+		opts = append(opts, userdataService.WithClipRemover(deps.ClipService))
+	}
+	deps.UserDataService = userdataService.NewService(
+		userdataService.NewRepository(deps.DB.DB),
+		viper.GetString("export.directory"),
+		secret,
+		opts...,
+	)
+}
+
+func initializeDatasetService(deps *types.Dependencies) {
+	secret := []byte(viper.GetString("datasets.signing_secret"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Printf("[ERROR] Failed to generate dataset signing key: %v", err)
+			return
+		}
+		log.Printf("[WARN] datasets.signing_secret not set; signed dataset URLs will not survive a restart")
+	}
+	var opts []datasetsService.Option
+	if deps.JobService != nil {
+		opts = append(opts, datasetsService.WithJobQueue(deps.JobService))
+	}
+	deps.DatasetService = datasetsService.NewService(
+		datasetsService.NewRepository(deps.DB.DB),
+		deps.ClipService,
+		viper.GetString("datasets.directory"),
+		secret,
+		datasetsService.CardOptions{
+			License:        viper.GetString("datasets.license"),
+			TargetDuration: viper.GetFloat64("clips.target_duration"),
+		},
+		opts...,
+	)
+}
+
+func initializePipeline(deps *types.Dependencies) {
+	var profiles map[string][]pipeline.Step
+	if err := viper.UnmarshalKey("pipeline.profiles", &profiles); err != nil {
+		log.Printf("[WARN] Ignoring invalid pipeline.profiles config: %v", err)
+		return
+	}
+	podcastProfiles := make(map[int64]string)
+	for feedID, profile := range viper.GetStringMapString("pipeline.podcast_profiles") {
+		id, err := strconv.ParseInt(feedID, 10, 64)
+		if err != nil {
+			log.Printf("[WARN] Ignoring pipeline profile for invalid feed ID %q", feedID)
+			continue
+		}
+		podcastProfiles[id] = profile
+	}
+
+	var checkers pipeline.Checkers
+	if deps.WaveformService != nil {
+		checkers.Waveforms = deps.WaveformService
+	}
+	if deps.TranscriptionService != nil {
+		checkers.Transcriptions = deps.TranscriptionService
+	}
+	if deps.EpisodeAnalysisService != nil && deps.ClipService != nil {
+		checkers.Clips = deps.ClipService
+	}
+
+	var episodes pipeline.EpisodeLookup
+	if deps.EpisodeService != nil {
+		episodes = deps.EpisodeService
+	}
+
+	p := pipeline.New(deps.JobService, episodes, checkers, pipeline.Config{
+		Profiles:        profiles,
+		DefaultProfile:  viper.GetString("pipeline.default_profile"),
+		PodcastProfiles: podcastProfiles,
+	})
+	deps.AudioCacheService.OnCached(p.AudioCached)
+}
+
+func initializeWebhookService(deps *types.Dependencies) {
+	secret := []byte(viper.GetString("webhooks.signing_secret"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Printf("[ERROR] Failed to generate webhook signing key: %v", err)
+			return
+		}
+		log.Printf("[WARN] webhooks.signing_secret not set; callback signatures cannot be verified by receivers")
+	}
+	deps.WebhookService = webhooksService.NewService(
+		webhooksService.NewRepository(deps.DB.DB),
+		webhooksService.Config{
+			Secret:       secret,
+			Timeout:      viper.GetDuration("webhooks.timeout"),
+			MaxAttempts:  viper.GetInt("webhooks.max_attempts"),
+			RetryBackoff: viper.GetDuration("webhooks.retry_backoff"),
+			AllowPrivate: viper.GetBool("webhooks.allow_private"),
+		},
+	)
+}
+
+func initializeSavedSearchService(deps *types.Dependencies) {
+	// Podcast Index search can't apply language, category or blocklist filters,
+	// so each run filters the feeds it gets back
+	filter := func(search *models.SavedSearch, feed podcastindex.Podcast) bool {
+		filters := types.ContentFilters{Languages: search.LanguageList(), ExcludedCategories: search.NotCategoryList()}
+		if !filters.Allows(feed) {
+			return false
+		}
+		return deps.BlocklistService == nil || deps.BlocklistService.Match(types.PodcastIndexSubject(feed)) == nil
+	}
+
+	opts := []savedSearchesService.Option{savedSearchesService.WithFilter(filter)}
+	if deps.NotificationService != nil {
+		opts = append(opts, savedSearchesService.WithNotifier(deps.NotificationService))
+	}
+	if deps.WebhookService != nil {
+		opts = append(opts, savedSearchesService.WithWebhooks(deps.WebhookService))
+	}
+
+	deps.SavedSearchService = savedSearchesService.NewService(
+		savedSearchesService.NewRepository(deps.DB.DB),
+		deps.PodcastClient,
+		savedSearchesService.Config{
+			MaxPerUser:      viper.GetInt("saved_searches.max_per_user"),
+			DefaultInterval: viper.GetDuration("saved_searches.default_interval"),
+			MinInterval:     viper.GetDuration("saved_searches.min_interval"),
+			DefaultLimit:    viper.GetInt("saved_searches.default_limit"),
+			MaxLimit:        viper.GetInt("saved_searches.max_limit"),
+			BatchSize:       viper.GetInt("saved_searches.batch_size"),
+		},
+		opts...,
+	)
+}
+
+func initializeLiveItemService(deps *types.Dependencies) {
+	deps.LiveItemService = liveitems.NewService(
+		liveitems.NewRepository(deps.DB.DB),
+		deps.PodcastClient,
+		liveitems.Config{
+			StaleAfter:    viper.GetDuration("live_items.stale_after"),
+			CheckInterval: viper.GetDuration("live_items.check_interval"),
+			LeadTime:      viper.GetDuration("live_items.lead_time"),
+			StartGrace:    viper.GetDuration("live_items.start_grace"),
+		},
+	)
+}
+
+func initializeDownloadPolicies(deps *types.Dependencies) {
+	base := download.DefaultBasePolicy()
+	if ua := viper.GetString("download.user_agent"); ua != "" {
+		base.UserAgent = ua
+	}
+	if referer := viper.GetString("download.referer"); referer != "" {
+		base.Referer = referer
+	}
+	if viper.IsSet("download.max_retries") {
+		retries := viper.GetInt("download.max_retries")
+		base.MaxRetries = &retries
+	}
+	if backoff := viper.GetDuration("download.retry_backoff"); backoff > 0 {
+		base.RetryBackoff = backoff
+	}
+	if statuses := viper.GetIntSlice("download.retry_statuses"); len(statuses) > 0 {
+		base.RetryStatuses = statuses
+	}
+
+	var hosts []download.HostPolicy
+	if err := viper.UnmarshalKey("download.hosts", &hosts); err != nil {
+		log.Printf("[WARN] Ignoring invalid download.hosts config: %v", err)
+		hosts = nil
+	}
+
+	deps.DownloadPolicies = download.NewPolicies(base, download.DefaultHostPolicies(), hosts)
+}
+
+func initializeStreamCacheService(deps *types.Dependencies) {
+	deps.StreamCacheService = streamcache.NewService(
+		viper.GetString("stream_cache.directory"),
+		viper.GetInt64("stream_cache.chunk_size"),
+		viper.GetDuration("stream_cache.fetch_timeout"),
+		deps.DownloadPolicies,
+		streamcache.WithMaxSize(viper.GetInt64("stream_cache.max_size_mb")*1024*1024),
+	)
+}
+
 func initializeWaveformService(deps *types.Dependencies) {
 	waveformRepo := waveforms.NewRepository(deps.DB.DB)
 	deps.WaveformService = waveforms.NewService(waveformRepo)
 }
 
+// newFFmpeg builds the ffmpeg wrapper with the configured per-process limits
+func newFFmpeg() *ffmpeg.FFmpeg {
+	return ffmpeg.New(
+		viper.GetString("ffmpeg.path"),
+		viper.GetString("ffmpeg.ffprobe_path"),
+		viper.GetDuration("ffmpeg.timeout"),
+		ffmpeg.WithLimits(ffmpeg.ResourceLimits{
+			CPUTime:        viper.GetDuration("ffmpeg.cpu_time_limit"),
+			Nice:           viper.GetInt("ffmpeg.nice"),
+			MaxOutputBytes: viper.GetInt64("ffmpeg.max_output_mb") * 1024 * 1024,
+			MaxConcurrent:  viper.GetInt("ffmpeg.max_concurrent"),
+		}),
+	)
+}
+
+func initializeWhisperModels(deps *types.Dependencies) {
+	// transcription.model_path predates the registry; it still names the
+	// default model and where models live unless those are set explicitly
+	modelPath := transcription.WhisperFromConfig().ModelPath
+	dir := viper.GetString("transcription.models_dir")
+	if dir == "" {
+		dir = filepath.Dir(modelPath)
+	}
+	defaultModel := viper.GetString("transcription.default_model")
+	if defaultModel == "" {
+		defaultModel = transcription.ModelNameFromPath(modelPath)
+	}
+
+	podcastModels := make(map[int64]string)
+	for feedID, model := range viper.GetStringMapString("transcription.podcast_models") {
+		id, err := strconv.ParseInt(feedID, 10, 64)
+		if err != nil {
+			log.Printf("[WARN] Ignoring whisper model for invalid feed ID %q", feedID)
+			continue
+		}
+		podcastModels[id] = model
+	}
+
+	deps.WhisperModels = transcription.NewModelRegistry(transcription.ModelConfig{
+		Dir:           dir,
+		Default:       defaultModel,
+		PodcastModels: podcastModels,
+		AutoDownload:  viper.GetBool("transcription.auto_download_models"),
+		BaseURL:       viper.GetString("transcription.model_base_url"),
+	})
+}
+
+func initializeLiveTranscriber(deps *types.Dependencies) {
+	whisper := transcription.WhisperFromConfig()
+	whisper.Models = deps.WhisperModels
+	deps.LiveTranscriber = transcription.NewLive(deps.StreamCacheService, deps.FFmpeg, whisper, transcription.LiveConfig{
+		ChunkDuration: viper.GetDuration("transcription.live.chunk_duration"),
+		PollInterval:  viper.GetDuration("transcription.live.poll_interval"),
+		IdleTimeout:   viper.GetDuration("transcription.live.idle_timeout"),
+		MaxSessions:   viper.GetInt("transcription.live.max_sessions"),
+		TempDir:       viper.GetString("temp_dir"),
+	})
+}
+
 func initializeTranscriptionService(deps *types.Dependencies) {
 	transcriptionRepo := transcription.NewRepository(deps.DB.DB)
 	deps.TranscriptionService = transcription.NewService(transcriptionRepo)
 }
 
+func initializeContentSafetyService(deps *types.Dependencies) {
+	detector := contentsafety.NewDetector(
+		viper.GetStringSlice("content_safety.extra_terms"),
+		viper.GetFloat64("content_safety.padding_seconds"),
+	)
+	deps.ContentSafetyService = contentsafety.NewService(contentsafety.NewRepository(deps.DB.DB), detector)
+}
+
+func initializeSummaryService(deps *types.Dependencies) {
+	client := summaryService.NewOpenAIClient(summaryService.OpenAIConfig{
+		APIURL:          viper.GetString("summary.api_url"),
+		APIKey:          viper.GetString("summary.api_key"),
+		Model:           viper.GetString("summary.model"),
+		MaxOutputTokens: viper.GetInt("summary.max_output_tokens"),
+		Timeout:         viper.GetDuration("summary.timeout"),
+	})
+
+	summaryRepo := summaryService.NewRepository(deps.DB.DB)
+	deps.SummaryService = summaryService.NewService(
+		summaryRepo,
+		client,
+		summaryService.WithMaxInputTokens(viper.GetInt("summary.max_input_tokens")),
+		summaryService.WithDailyTokenBudget(viper.GetInt64("summary.daily_token_budget")),
+	)
+	log.Printf("[INFO] Summary service initialized with model %s", client.Model())
+}
+
 func initializeClipService(deps *types.Dependencies) {
 	clipsBasePath := viper.GetString("clips.storage_path")
 	tempDir := viper.GetString("temp_dir")
 	targetDuration := viper.GetFloat64("clips.target_duration")
 
-	extractor, err := clipsService.NewFFmpegExtractor(tempDir, targetDuration)
+	extractor, err := clipsService.NewFFmpegExtractor(deps.FFmpeg, tempDir, targetDuration)
 	if err != nil {
 		log.Printf("[ERROR] Failed to create FFmpeg extractor: %v", err)
 		return
 	}
 
-	storage, err := clipsService.NewLocalClipStorage(clipsBasePath)
+	storage, err := clipsService.NewClipStorage(clipsBasePath, viper.GetBool("clips.deduplicate"))
 	if err != nil {
 		log.Printf("[ERROR] Failed to create clip storage: %v", err)
 		return
@@ -351,6 +987,20 @@ func initializeClipService(deps *types.Dependencies) {
 		return
 	}
 
+	var quotas map[string]int
+	if err := viper.UnmarshalKey("clips.label_quotas", &quotas); err != nil {
+		log.Printf("[WARN] Ignoring invalid clips.label_quotas config: %v", err)
+		quotas = nil
+	}
+
+	opts := []clipsService.Option{
+		clipsService.WithLabelQuotas(quotas),
+		clipsService.WithTempDir(viper.GetString("temp_dir")),
+	}
+	if deps.AnalyticsService != nil {
+		opts = append(opts, clipsService.WithStatsRefresher(deps.AnalyticsService))
+	}
+
 	deps.ClipService = clipsService.NewService(
 		deps.DB.DB,
 		storage,
@@ -358,6 +1008,7 @@ func initializeClipService(deps *types.Dependencies) {
 		deps.JobService,
 		deps.EpisodeService,
 		deps.AudioCacheService,
+		opts...,
 	)
 	log.Printf("[INFO] Clip service initialized with storage at %s", clipsBasePath)
 }
@@ -371,10 +1022,71 @@ func initializeAudioCacheService(deps *types.Dependencies) {
 		return
 	}
 
-	deps.AudioCacheService = audiocache.NewService(audioCacheRepo, storage)
+	opts := []audiocache.Option{
+		audiocache.WithProber(deps.FFmpeg),
+		audiocache.WithTranscoder(deps.FFmpeg),
+		audiocache.WithTempDir(viper.GetString("temp_dir")),
+	}
+	if deps.FeedHealthService != nil {
+		opts = append(opts, audiocache.WithFetchRecorder(deps.FeedHealthService))
+	}
+	var mirrors []download.MirrorRule
+	if err := viper.UnmarshalKey("download.mirrors", &mirrors); err != nil {
+		log.Printf("[WARN] Ignoring invalid download.mirrors config: %v", err)
+	} else if len(mirrors) > 0 {
+		opts = append(opts, audiocache.WithMirrors(mirrors))
+	}
+	if deps.EpisodeService != nil {
+		opts = append(opts, audiocache.WithAlternateSource(audiocache.AlternateSourceFunc(
+			func(ctx context.Context, podcastIndexEpisodeID int64) ([]string, error) {
+				episode, err := deps.EpisodeService.GetEpisodeByPodcastIndexID(ctx, podcastIndexEpisodeID)
+				if err != nil {
+					return nil, err
+				}
+				var urls []string
+				for _, rendition := range episode.Renditions()[1:] {
+					urls = append(urls, rendition.URL)
+				}
+				return urls, nil
+			})))
+	}
+	deps.AudioCacheService = audiocache.NewService(audioCacheRepo, storage, opts...)
 	log.Printf("[INFO] Audio cache service initialized with storage at %s", cacheDir)
 }
 
+func initializeExternalUsageService(deps *types.Dependencies) {
+	budgets := map[string]int64{
+		externalusage.PodcastIndex: viper.GetInt64("podcast_index.daily_budget"),
+	}
+	deps.ExternalUsageService = externalusage.NewService(externalusage.NewRepository(deps.DB.DB), budgets)
+	log.Printf("[INFO] External usage service initialized")
+}
+
+func initializeLoudnessService(deps *types.Dependencies) {
+	deps.LoudnessService = loudness.NewService(loudness.NewRepository(deps.DB.DB), deps.AudioCacheService, deps.FFmpeg)
+	log.Printf("[INFO] Loudness service initialized")
+}
+
+func initializeModelRegistry(deps *types.Dependencies) {
+	registry := modelregistry.NewService(modelregistry.NewRepository(deps.DB.DB), episodeanalysis.BuiltinModels()...)
+	if err := registry.EnsureBuiltins(context.Background()); err != nil {
+		log.Printf("[ERROR] Failed to register built-in detector models: %v", err)
+		return
+	}
+	deps.ModelRegistry = registry
+	log.Printf("[INFO] Model registry initialized")
+}
+
+func initializeAutoApprovalService(deps *types.Dependencies) {
+	var thresholds map[string]float64
+	if err := viper.UnmarshalKey("clips.auto_approve_thresholds", &thresholds); err != nil {
+		log.Printf("[WARN] Ignoring invalid clips.auto_approve_thresholds config: %v", err)
+		thresholds = nil
+	}
+	deps.AutoApprovalService = autoapproval.NewService(autoapproval.NewRepository(deps.DB.DB), thresholds)
+	log.Printf("[INFO] Auto-approval service initialized")
+}
+
 func initializeEpisodeAnalysisService(deps *types.Dependencies) {
 	if deps.AudioCacheService == nil {
 		log.Printf("[ERROR] AudioCacheService not initialized, episode analysis service requires it")
@@ -393,13 +1105,16 @@ func initializeEpisodeAnalysisService(deps *types.Dependencies) {
 		deps.AudioCacheService,
 		deps.ClipService,
 		deps.EpisodeService,
+		deps.ModelRegistry,
+		deps.AutoApprovalService,
+		deps.FFmpeg,
 	)
 	log.Printf("[INFO] Episode analysis service initialized")
 }
 
 func initializeJobService(deps *types.Dependencies) {
 	jobRepo := jobs.NewRepository(deps.DB.DB)
-	deps.JobService = jobs.NewService(jobRepo)
+	deps.JobService = jobs.NewService(jobRepo, jobs.WithQueueLimits(jobQueueLimitsFromConfig()))
 }
 
 func initializeITunesClient(deps *types.Dependencies) {
@@ -420,6 +1135,7 @@ func NotFoundHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.JSON(404, gin.H{
 			"status":  "error",
+			"code":    types.CodeNotFound,
 			"message": "The requested endpoint was not found",
 			"path":    c.Request.URL.Path,
 		})
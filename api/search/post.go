@@ -20,7 +20,9 @@ type PodcastSearcher interface {
 // @Description  Search the Podcast Index for podcasts matching the query string. Returns podcast metadata
 // @Description  including titles, descriptions, feed URLs, and iTunes IDs. Results can be filtered by various
 // @Description  criteria such as value4value support, iTunes availability, and explicit content. Search uses
-// @Description  the Podcast Index API which indexes millions of podcasts from RSS feeds worldwide.
+// @Description  the Podcast Index API which indexes millions of podcasts from RSS feeds worldwide. For signed-in
+// @Description  users, results are limited to the languages in their preferences and exclude their hidden
+// @Description  categories; lang and notCategories in the request take precedence.
 // @Tags         search
 // @Accept       json
 // @Produce      json
@@ -28,6 +30,7 @@ type PodcastSearcher interface {
 // @Success      200 {object} types.PodcastSearchResponse "Matching podcasts with metadata (feedId can be used with /podcasts/{id}/episodes)"
 // @Failure      400 {object} types.ErrorResponse "Invalid request format or missing required query field"
 // @Failure      500 {object} types.ErrorResponse "Search service error or API communication failure"
+// @Failure      503 {object} types.ErrorResponse "Daily Podcast Index budget spent"
 // @Failure      504 {object} types.ErrorResponse "Request timeout (search limited to 10 seconds)"
 // @Router       /api/v1/search [post]
 func Post(deps *types.Dependencies) gin.HandlerFunc {
@@ -37,6 +40,7 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Invalid request format",
 				Details: err.Error(),
 			})
@@ -47,6 +51,7 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 		if req.Query == "" {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Search query is required",
 			})
 			return
@@ -61,6 +66,7 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 		if req.Limit < 1 || req.Limit > 100 {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Limit must be between 1 and 100",
 			})
 			return
@@ -71,6 +77,7 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 		if !ok {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Search service not available",
 			})
 			return
@@ -87,21 +94,33 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 			if ctx.Err() == context.DeadlineExceeded {
 				c.JSON(http.StatusGatewayTimeout, types.ErrorResponse{
 					Status:  types.StatusError,
+					Code:    types.CodeUpstreamTimeout,
 					Message: "Search request timed out",
 				})
 				return
 			}
 
-			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-				Status:  types.StatusError,
-				Message: "Failed to search podcasts",
-				Details: err.Error(),
-			})
+			types.SendServiceError(c, err, "Failed to search podcasts")
 			return
 		}
 
+		// Podcast Index search has no language or category parameters, so the
+		// user's filters are applied to the results here
+		filters := types.UserContentFilters(c, deps).Override(req.Lang, req.NotCategories)
+		feeds := types.Blocklist(c, deps).FilterPodcasts(results.Feeds)
+		if !filters.IsEmpty() {
+			c.Header("Cache-Control", "private")
+			unfiltered := feeds
+			feeds = make([]podcastindex.Podcast, 0, len(unfiltered))
+			for _, feed := range unfiltered {
+				if filters.Allows(feed) {
+					feeds = append(feeds, feed)
+				}
+			}
+		}
+
 		// Transform Podcast Index results to our simplified format
-		podcasts := types.FromPodcastIndexList(results.Feeds)
+		podcasts := types.FromPodcastIndexList(feeds)
 
 		// Return the search response
 		c.JSON(http.StatusOK, types.PodcastSearchResponse{
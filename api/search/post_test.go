@@ -11,7 +11,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/internal/services/podcastindex"
+	"github.com/killallgit/player-api/internal/services/preferences"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,12 +30,12 @@ func (m *mockSearcher) Search(ctx context.Context, query string, limit int, full
 	return &podcastindex.SearchResponse{}, nil
 }
 
-func (m *mockSearcher) GetTrending(ctx context.Context, max, since int, categories []string, lang string, fullText bool) (*podcastindex.SearchResponse, error) {
+func (m *mockSearcher) GetTrending(ctx context.Context, max, since int, categories, notCategories []string, lang string, fullText bool) (*podcastindex.SearchResponse, error) {
 	// Return empty response for tests
 	return &podcastindex.SearchResponse{}, nil
 }
 
-func (m *mockSearcher) GetCategories() (*podcastindex.CategoriesResponse, error) {
+func (m *mockSearcher) GetCategories(ctx context.Context) (*podcastindex.CategoriesResponse, error) {
 	// Return empty response for tests
 	return &podcastindex.CategoriesResponse{}, nil
 }
@@ -56,7 +58,7 @@ func (m *mockSearcher) GetRecentEpisodes(ctx context.Context, limit int) (*podca
 	return &podcastindex.EpisodesResponse{}, nil
 }
 
-func (m *mockSearcher) GetRandomEpisodes(ctx context.Context, max int, lang string, notCategories []string) (*podcastindex.EpisodesResponse, error) {
+func (m *mockSearcher) GetRandomEpisodes(ctx context.Context, max int, lang string, categories, notCategories []string) (*podcastindex.EpisodesResponse, error) {
 	return &podcastindex.EpisodesResponse{}, nil
 }
 
@@ -328,3 +330,73 @@ func TestPost(t *testing.T) {
 		})
 	}
 }
+
+// stubPreferences serves fixed preferences for every user
+type stubPreferences struct {
+	prefs models.UserPreferences
+}
+
+func (s *stubPreferences) Get(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	prefs := s.prefs
+	prefs.UserID = userID
+	return &prefs, nil
+}
+
+func (s *stubPreferences) Update(ctx context.Context, userID string, update preferences.Update) (*models.UserPreferences, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestPost_AppliesUserContentFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	feeds := []podcastindex.Podcast{
+		{ID: 1, Title: "English Tech", Language: "en-US", Categories: map[string]string{"102": "Technology"}},
+		{ID: 2, Title: "Spanish Tech", Language: "es", Categories: map[string]string{"102": "Technology"}},
+		{ID: 3, Title: "English News", Language: "en", Categories: map[string]string{"55": "News"}},
+	}
+	deps := &types.Dependencies{
+		PodcastClient: &mockSearcher{
+			searchFunc: func(ctx context.Context, query string, limit int, fullText bool, val string, apOnly bool, clean bool) (*podcastindex.SearchResponse, error) {
+				return &podcastindex.SearchResponse{Feeds: feeds, Count: len(feeds)}, nil
+			},
+		},
+		PreferencesService: &stubPreferences{prefs: models.UserPreferences{Languages: "en", ExcludedCategories: "news"}},
+	}
+
+	search := func(userID string, req interface{}) ([]float64, http.Header) {
+		router := gin.New()
+		router.POST("/search", func(c *gin.Context) {
+			if userID != "" {
+				c.Set("user_id", userID)
+			}
+		}, Post(deps))
+
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/search", bytes.NewBuffer(body)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp types.PodcastSearchResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		ids := make([]float64, 0, len(resp.Podcasts))
+		for _, p := range resp.Podcasts {
+			ids = append(ids, float64(p.ID))
+		}
+		return ids, w.Header()
+	}
+
+	// Anonymous requests are not filtered
+	ids, header := search("", types.SearchRequest{Query: "tech"})
+	assert.Equal(t, []float64{1, 2, 3}, ids)
+	assert.Empty(t, header.Get("Cache-Control"))
+
+	// Signed-in users get their stored languages and excluded categories
+	ids, header = search("user-1", types.SearchRequest{Query: "tech"})
+	assert.Equal(t, []float64{1}, ids)
+	assert.Equal(t, "private", header.Get("Cache-Control"))
+
+	// Explicit request filters take precedence; an empty list clears the exclusions
+	ids, _ = search("user-1", map[string]interface{}{"query": "tech", "lang": "es,en", "notCategories": []string{}})
+	assert.Equal(t, []float64{1, 2, 3}, ids)
+}
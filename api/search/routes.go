@@ -9,4 +9,5 @@ import (
 func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
 	// POST /api/v1/search (router already includes /search prefix)
 	router.POST("", Post(deps))
+	router.POST("/transcripts", PostTranscripts(deps))
 }
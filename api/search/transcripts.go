@@ -0,0 +1,128 @@
+package search
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/services/transcription"
+)
+
+const (
+	defaultTranscriptLimit = 10
+	maxTranscriptLimit     = 50
+	maxTranscriptSegments  = 10
+)
+
+// PostTranscripts handles searches inside stored transcripts
+// @Summary      Search inside episode audio
+// @Description  Search every stored transcript for episodes that mention all words of the query. Episodes are
+// @Description  ranked by how many transcript segments match, and each result carries its best matching
+// @Description  segments with timestamps (when the transcript is timed) and a snippet with the matched words
+// @Description  wrapped in <mark></mark>. Only episodes that have been transcribed or had a transcript fetched
+// @Description  are searchable.
+// @Tags         search
+// @Accept       json
+// @Produce      json
+// @Param        request body types.TranscriptSearchRequest true "Search query with optional paging"
+// @Success      200 {object} types.TranscriptSearchResponse "Matching episodes, most matches first"
+// @Failure      400 {object} types.ErrorResponse "Missing query, no searchable words, or limits out of range"
+// @Failure      500 {object} types.ErrorResponse "Search failed"
+// @Failure      503 {object} types.ErrorResponse "Transcript search not available"
+// @Router       /api/v1/search/transcripts [post]
+func PostTranscripts(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req types.TranscriptSearchRequest
+		if !types.BindJSONOrError(c, &req) {
+			return
+		}
+
+		if req.Limit == 0 {
+			req.Limit = defaultTranscriptLimit
+		}
+		if req.Limit < 1 || req.Limit > maxTranscriptLimit {
+			types.SendBadRequest(c, "Limit must be between 1 and 50")
+			return
+		}
+		if req.Offset < 0 {
+			types.SendBadRequest(c, "Offset cannot be negative")
+			return
+		}
+		if req.Segments < 0 || req.Segments > maxTranscriptSegments {
+			types.SendBadRequest(c, "Segments must be between 1 and 10")
+			return
+		}
+
+		if deps.TranscriptionService == nil {
+			c.JSON(http.StatusServiceUnavailable, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeServiceUnavailable,
+				Message: "Transcript search not available",
+			})
+			return
+		}
+
+		matches, err := deps.TranscriptionService.SearchTranscripts(c.Request.Context(), req.Query, transcription.SearchOptions{
+			Limit:    req.Limit,
+			Offset:   req.Offset,
+			Segments: req.Segments,
+		})
+		if errors.Is(err, transcription.ErrEmptyQuery) {
+			types.SendBadRequest(c, "Search query has no searchable words")
+			return
+		}
+		if errors.Is(err, transcription.ErrSearchIndexBuilding) {
+			types.SendError(c, http.StatusServiceUnavailable, types.CodeServiceUnavailable, "Transcript search index is being built, try again shortly")
+			return
+		}
+		if err != nil {
+			log.Printf("[ERROR] Transcript search for %q failed: %v", req.Query, err)
+			types.SendInternalError(c, "Failed to search transcripts")
+			return
+		}
+
+		block := types.Blocklist(c, deps)
+		results := make([]types.TranscriptSearchResult, 0, len(matches))
+		for _, match := range matches {
+			if match.Episode != nil && block.Blocks(types.EpisodeSubject(match.Episode)) {
+				continue
+			}
+			results = append(results, transcriptSearchResult(match))
+		}
+
+		c.JSON(http.StatusOK, types.TranscriptSearchResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Transcript search results retrieved successfully",
+			},
+			Results: results,
+			Query:   req.Query,
+			Count:   len(results),
+			Offset:  req.Offset,
+		})
+	}
+}
+
+func transcriptSearchResult(match transcription.TranscriptMatch) types.TranscriptSearchResult {
+	result := types.TranscriptSearchResult{
+		EpisodeID: match.EpisodeID,
+		Matches:   match.Matches,
+		Segments:  make([]types.TranscriptSearchSegment, len(match.Segments)),
+	}
+	if match.Episode != nil {
+		result.Title = match.Episode.Title
+		result.FeedID = match.Episode.PodcastIndexFeedID
+		result.FeedTitle = match.Episode.FeedTitle
+	}
+	for i, segment := range match.Segments {
+		result.Segments[i] = types.TranscriptSearchSegment{
+			Start:   segment.Start,
+			End:     segment.End,
+			Text:    segment.Text,
+			Snippet: segment.Snippet,
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/transcription"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTranscripts struct {
+	transcription.TranscriptionService
+	matches []transcription.TranscriptMatch
+	opts    transcription.SearchOptions
+}
+
+func (f *fakeTranscripts) SearchTranscripts(ctx context.Context, query string, opts transcription.SearchOptions) ([]transcription.TranscriptMatch, error) {
+	f.opts = opts
+	if query == "!!" {
+		return nil, transcription.ErrEmptyQuery
+	}
+	return f.matches, nil
+}
+
+func postTranscriptSearch(t *testing.T, deps *types.Dependencies, body any) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/search/transcripts", PostTranscripts(deps))
+
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/search/transcripts", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPostTranscripts(t *testing.T) {
+	start, end := 42.0, 47.5
+	fake := &fakeTranscripts{matches: []transcription.TranscriptMatch{{
+		EpisodeID: 11,
+		Episode:   &models.Episode{PodcastIndexID: 11, PodcastIndexFeedID: 3, Title: "Solar Power", FeedTitle: "Energy Hour"},
+		Matches:   4,
+		Segments: []transcription.SegmentMatch{{
+			Start: &start, End: &end,
+			Text:    "Solar is cheap now.",
+			Snippet: "<mark>Solar</mark> is cheap now.",
+		}},
+	}}}
+	deps := &types.Dependencies{TranscriptionService: fake}
+
+	w := postTranscriptSearch(t, deps, types.TranscriptSearchRequest{Query: "solar", Segments: 2})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, transcription.SearchOptions{Limit: defaultTranscriptLimit, Segments: 2}, fake.opts)
+
+	var resp types.TranscriptSearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Results, 1)
+	result := resp.Results[0]
+	assert.Equal(t, int64(11), result.EpisodeID)
+	assert.Equal(t, "Solar Power", result.Title)
+	assert.Equal(t, int64(3), result.FeedID)
+	assert.Equal(t, 4, result.Matches)
+	require.Len(t, result.Segments, 1)
+	assert.Equal(t, 42.0, *result.Segments[0].Start)
+	assert.Equal(t, "<mark>Solar</mark> is cheap now.", result.Segments[0].Snippet)
+}
+
+func TestPostTranscripts_Validation(t *testing.T) {
+	deps := &types.Dependencies{TranscriptionService: &fakeTranscripts{}}
+
+	tests := []struct {
+		name string
+		body types.TranscriptSearchRequest
+	}{
+		{"missing query", types.TranscriptSearchRequest{}},
+		{"limit too high", types.TranscriptSearchRequest{Query: "solar", Limit: 51}},
+		{"negative offset", types.TranscriptSearchRequest{Query: "solar", Offset: -1}},
+		{"too many segments", types.TranscriptSearchRequest{Query: "solar", Segments: 11}},
+		{"no searchable words", types.TranscriptSearchRequest{Query: "!!"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postTranscriptSearch(t, deps, tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	w := postTranscriptSearch(t, &types.Dependencies{}, types.TranscriptSearchRequest{Query: "solar"})
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -2,21 +2,34 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
 	"github.com/killallgit/player-api/internal/database"
+	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/internal/services/autolabel"
 	"github.com/killallgit/player-api/internal/services/cleanup"
 	"github.com/killallgit/player-api/internal/services/clips"
 	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/events"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/liveitems"
+	"github.com/killallgit/player-api/internal/services/notifications"
+	"github.com/killallgit/player-api/internal/services/savedsearches"
+	"github.com/killallgit/player-api/internal/services/transcription"
+	"github.com/killallgit/player-api/internal/services/userdata"
 	"github.com/killallgit/player-api/internal/services/workers"
 	"github.com/killallgit/player-api/pkg/ffmpeg"
+	"github.com/killallgit/player-api/pkg/logging"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // Server represents the HTTP server
@@ -26,37 +39,65 @@ type Server struct {
 	db                 *database.DB
 	episodeCache       episodes.EpisodeCache
 	rateLimiters       *sync.Map
+	live               *LiveSettings
 	cleanupInitialized sync.Once
 	cleanupStop        chan struct{}
 	workerPool         *workers.WorkerPool
 	workerCancel       context.CancelFunc
 	cleanupService     *cleanup.Service
+	deletionSweeper    *userdata.Sweeper
+	notificationPruner *notifications.Pruner
+	eventPruner        *events.Pruner
+	savedSearches      *savedsearches.Scheduler
+	liveItems          *liveitems.Scheduler
+	clipIntegrity      *clips.IntegrityChecker
 
 	// Dependencies for handlers
 	dependencies *types.Dependencies
 }
 
+// NewServer creates the HTTP server. Timeouts come from the server.* config keys:
+// read_header_timeout bounds slow header delivery, read_timeout the whole request,
+// write_timeout the response for ordinary handlers (streaming and long synchronous
+// routes lift it, see middleware.StreamDeadline and middleware.NoWriteDeadline), and
+// idle_timeout keep-alive connections.
 func NewServer(address string) *Server {
 	engine := gin.New()
 	engine.Use(gin.Recovery())
 
+	// Cleartext HTTP/2 for deployments behind an h2c-capable proxy; TLS listeners
+	// negotiate HTTP/2 on their own
+	engine.UseH2C = viper.GetBool("server.http2")
+
+	// Multipart parts beyond this are spooled to temp files instead of memory
+	engine.MaxMultipartMemory = viper.GetInt64("request_limits.multipart_memory_mb") * 1024 * 1024
+
 	server := &Server{
 		engine:       engine,
 		rateLimiters: &sync.Map{},
 		cleanupStop:  make(chan struct{}),
 		httpServer: &http.Server{
-			Addr:           address,
-			Handler:        engine,
-			ReadTimeout:    30 * time.Second,
-			WriteTimeout:   0,
-			IdleTimeout:    30 * time.Second,
-			MaxHeaderBytes: 1 << 20,
+			Addr:              address,
+			Handler:           engine.Handler(),
+			ReadHeaderTimeout: durationOr("server.read_header_timeout", 10*time.Second),
+			ReadTimeout:       durationOr("server.read_timeout", 30*time.Second),
+			WriteTimeout:      durationOr("server.write_timeout", 60*time.Second),
+			IdleTimeout:       durationOr("server.idle_timeout", 120*time.Second),
+			MaxHeaderBytes:    1 << 20,
 		},
 	}
 
 	return server
 }
 
+// durationOr reads a duration setting, falling back when it is unset or invalid
+func durationOr(key string, fallback time.Duration) time.Duration {
+	if d := viper.GetDuration(key); d > 0 {
+		return d
+	}
+	return fallback
+}
+
 func (s *Server) SetDatabase(db *database.DB) {
 	s.db = db
 	if s.dependencies == nil {
@@ -76,6 +117,9 @@ func (s *Server) Engine() *gin.Engine {
 func (s *Server) Initialize() error {
 	s.setupMiddleware()
 
+	// Before the routes, so the admin stats endpoint can reach it
+	s.initializeCleanupService()
+
 	if err := s.setupRoutes(); err != nil {
 		return err
 	}
@@ -84,19 +128,59 @@ func (s *Server) Initialize() error {
 		return err
 	}
 
-	s.initializeCleanupService()
-
 	return nil
 }
 
 func (s *Server) setupMiddleware() {
+	if viper.GetBool("tracing.enabled") {
+		// First, so the server span covers the rest of the middleware too
+		s.engine.Use(otelgin.Middleware(viper.GetString("tracing.service_name")))
+	}
 	s.engine.Use(gin.Logger())
-	s.engine.Use(CORS())
-	s.engine.Use(RequestSizeLimit())
+	s.engine.Use(CORS(CORSConfigFromViper()))
+	s.engine.Use(RequestSizeLimits(BodyLimitConfigFromViper()))
 }
 
 func (s *Server) setupRoutes() error {
-	return RegisterRoutes(s.engine, s.dependencies, s.rateLimiters, s.cleanupStop, &s.cleanupInitialized)
+	s.live = LiveSettingsFromViper()
+	return RegisterRoutes(s.engine, s.dependencies, s.live, s.rateLimiters, s.cleanupStop, &s.cleanupInitialized)
+}
+
+// ApplyConfigChanges applies changed reloadable settings (see config.Watch)
+// to the running server, reading the new values from settings
+func (s *Server) ApplyConfigChanges(changed []string, settings *viper.Viper) {
+	var rateLimits, cacheTTLs bool
+	for _, key := range changed {
+		switch {
+		case strings.HasPrefix(key, "security."):
+			rateLimits = true
+		case strings.HasPrefix(key, "cache."):
+			cacheTTLs = true
+		case key == "processing.workers":
+			if s.workerPool != nil {
+				s.workerPool.Resize(settings.GetInt("processing.workers"))
+			}
+		case key == "logging.level":
+			level, err := logging.ParseLevel(settings.GetString("logging.level"))
+			if err != nil {
+				log.Printf("[WARN] Keeping the current log level: %v", err)
+				continue
+			}
+			logging.SetLevel(level)
+		}
+	}
+
+	if s.live == nil {
+		return
+	}
+	if rateLimits {
+		s.live.reloadRateLimits(settings)
+		// Clients get limiters with the new limits on their next request
+		s.rateLimiters.Clear()
+	}
+	if cacheTTLs {
+		s.live.reloadCacheTTLs(settings)
+	}
 }
 
 func (s *Server) initializeWorkerPool() error {
@@ -111,11 +195,11 @@ func (s *Server) initializeWorkerPool() error {
 	}
 
 	numWorkers := viper.GetInt("processing.workers")
-	ffmpegPath := viper.GetString("ffmpeg.path")
-	ffprobePath := viper.GetString("ffmpeg.ffprobe_path")
-	ffmpegTimeout := viper.GetDuration("ffmpeg.timeout")
-
-	ffmpegInstance := ffmpeg.New(ffmpegPath, ffprobePath, ffmpegTimeout)
+	// Shared with the preview handler so the process cap covers both
+	if s.dependencies.FFmpeg == nil {
+		s.dependencies.FFmpeg = newFFmpeg()
+	}
+	ffmpegInstance := s.dependencies.FFmpeg
 
 	if err := ffmpegInstance.ValidateBinaries(); err != nil {
 		log.Printf("[WARN] FFmpeg binaries not available: %v", err)
@@ -130,35 +214,62 @@ func (s *Server) initializeWorkerPool() error {
 		s.dependencies.AudioCacheService,
 		ffmpegInstance,
 		ffmpeg.DefaultProcessingOptions(),
+		s.dependencies.DownloadPolicies,
 	)
 
 	var transcriptionProcessor *workers.TranscriptionProcessor
 	if s.dependencies.TranscriptionService != nil {
+		backend, err := transcription.NewBackendFromConfig(s.dependencies.WhisperModels, ffmpegInstance)
+		if err != nil {
+			return fmt.Errorf("configuring transcription backend: %w", err)
+		}
+		log.Printf("[INFO] Transcription backend: %s", backend.Name())
 		transcriptionProcessor = workers.NewTranscriptionProcessor(
 			s.dependencies.JobService,
 			s.dependencies.TranscriptionService,
 			s.dependencies.EpisodeService,
 			s.dependencies.AudioCacheService,
+			s.dependencies.ContentSafetyService,
+			s.dependencies.DownloadPolicies,
+			s.dependencies.WhisperModels,
+			backend,
 		)
 	}
 
 	pollInterval := 5 * time.Second
 	s.workerPool = workers.NewWorkerPool(s.dependencies.JobService, numWorkers, pollInterval)
+	timeouts := jobTimeoutsFromConfig()
+	s.workerPool.SetTimeouts(timeouts, viper.GetDuration("jobs.heartbeat_interval"))
+	s.workerPool.EnableReaper(workers.ReaperConfig{
+		Interval:         viper.GetDuration("jobs.reaper_interval"),
+		HeartbeatTimeout: viper.GetDuration("jobs.heartbeat_timeout"),
+		Timeouts:         timeouts,
+	})
 	s.workerPool.RegisterProcessor(waveformProcessor)
 
 	if transcriptionProcessor != nil {
 		s.workerPool.RegisterProcessor(transcriptionProcessor)
 	}
 
+	if s.dependencies.SummaryService != nil && s.dependencies.TranscriptionService != nil {
+		summaryProcessor := workers.NewSummaryProcessor(
+			s.dependencies.JobService,
+			s.dependencies.SummaryService,
+			s.dependencies.TranscriptionService,
+		)
+		s.workerPool.RegisterProcessor(summaryProcessor)
+		log.Printf("[INFO] Registered summary processor")
+	}
+
 	var clipProcessor *workers.ClipExtractionProcessor
 	if s.dependencies.ClipService != nil {
 		clipsBasePath := viper.GetString("clips.storage_path")
 		tempDir := viper.GetString("temp_dir")
 		targetDuration := viper.GetFloat64("clips.target_duration")
 
-		extractor, err := clips.NewFFmpegExtractor(tempDir, targetDuration)
+		extractor, err := clips.NewFFmpegExtractor(s.dependencies.FFmpeg, tempDir, targetDuration)
 		if err == nil {
-			storage, err := clips.NewLocalClipStorage(clipsBasePath)
+			storage, err := clips.NewClipStorage(clipsBasePath, viper.GetBool("clips.deduplicate"))
 			if err == nil {
 				clipProcessor = workers.NewClipExtractionProcessor(
 					s.dependencies.JobService,
@@ -176,10 +287,24 @@ func (s *Server) initializeWorkerPool() error {
 		}
 	}
 
+	if s.dependencies.ClipService != nil {
+		s.workerPool.RegisterProcessor(workers.NewClipReextractionProcessor(
+			s.dependencies.JobService,
+			s.dependencies.ClipService,
+		))
+		log.Printf("[INFO] Registered clip re-extraction processor")
+
+		s.workerPool.RegisterProcessor(workers.NewClipVerificationProcessor(
+			s.dependencies.JobService,
+			s.dependencies.ClipService,
+		))
+		log.Printf("[INFO] Registered clip verification processor")
+	}
+
 	if s.dependencies.ClipService != nil {
 		clipsBasePath := viper.GetString("clips.storage_path")
 
-		peakDetector := autolabel.NewFFmpegPeakDetector("")
+		peakDetector := autolabel.NewFFmpegPeakDetector(s.dependencies.FFmpeg)
 		autolabelSvc := autolabel.NewService(s.dependencies.DB.DB, peakDetector)
 
 		autolabelProcessor := workers.NewAutoLabelProcessor(
@@ -192,6 +317,117 @@ func (s *Server) initializeWorkerPool() error {
 		log.Printf("[INFO] Registered autolabel processor")
 	}
 
+	if s.dependencies.UserDataService != nil {
+		s.workerPool.RegisterProcessor(workers.NewUserExportProcessor(
+			s.dependencies.JobService,
+			s.dependencies.UserDataService,
+		))
+		log.Printf("[INFO] Registered user export processor")
+
+		s.workerPool.RegisterProcessor(workers.NewAccountDeletionProcessor(
+			s.dependencies.JobService,
+			s.dependencies.UserDataService,
+		))
+		s.deletionSweeper = userdata.NewSweeper(
+			s.dependencies.UserDataService,
+			s.dependencies.JobService,
+			viper.GetDuration("account_deletion.sweep_interval"),
+		)
+		log.Printf("[INFO] Registered account deletion processor")
+	}
+
+	if s.dependencies.ImportService != nil {
+		s.workerPool.RegisterProcessor(workers.NewLibraryImportProcessor(
+			s.dependencies.JobService,
+			s.dependencies.ImportService,
+		))
+		log.Printf("[INFO] Registered library import processor")
+	}
+
+	if s.dependencies.EpisodeAnalysisService != nil {
+		s.workerPool.RegisterProcessor(workers.NewEpisodeAnalysisProcessor(
+			s.dependencies.JobService,
+			s.dependencies.EpisodeAnalysisService,
+			s.dependencies.ModelRegistry,
+		))
+		log.Printf("[INFO] Registered episode analysis processor")
+	}
+
+	if s.dependencies.DatasetService != nil {
+		s.workerPool.RegisterProcessor(workers.NewDatasetGenerationProcessor(
+			s.dependencies.JobService,
+			s.dependencies.DatasetService,
+		))
+		log.Printf("[INFO] Registered dataset generation processor")
+	}
+
+	if s.dependencies.AudioCacheService != nil {
+		s.workerPool.RegisterProcessor(workers.NewAudioCacheProcessor(
+			s.dependencies.JobService,
+			s.dependencies.EpisodeService,
+			s.dependencies.AudioCacheService,
+		))
+		log.Printf("[INFO] Registered audio cache processor")
+	}
+
+	if s.dependencies.NotificationService != nil {
+		s.workerPool.AddNotifier(s.dependencies.NotificationService)
+		s.notificationPruner = notifications.NewPruner(
+			s.dependencies.NotificationService,
+			viper.GetDuration("notifications.retention"),
+			viper.GetDuration("notifications.prune_interval"),
+		)
+	}
+
+	if s.dependencies.AnalyticsService != nil {
+		s.workerPool.AddNotifier(s.dependencies.AnalyticsService)
+	}
+
+	if s.dependencies.EventsService != nil {
+		s.eventPruner = events.NewPruner(
+			s.dependencies.EventsService,
+			viper.GetDuration("events.retention"),
+			viper.GetDuration("events.prune_interval"),
+		)
+	}
+
+	if s.dependencies.WebhookService != nil {
+		s.workerPool.AddNotifier(s.dependencies.WebhookService)
+	}
+
+	if s.dependencies.SavedSearchService != nil {
+		s.savedSearches = savedsearches.NewScheduler(
+			s.dependencies.SavedSearchService,
+			viper.GetDuration("saved_searches.check_interval"),
+		)
+	}
+
+	if s.dependencies.LiveItemService != nil {
+		s.workerPool.RegisterProcessor(workers.NewLiveItemRefreshProcessor(
+			s.dependencies.JobService,
+			s.dependencies.LiveItemService,
+		))
+		s.liveItems = liveitems.NewScheduler(
+			s.dependencies.LiveItemService,
+			s.dependencies.JobService,
+			viper.GetDuration("live_items.schedule_interval"),
+			viper.GetInt("live_items.batch_size"),
+		)
+		log.Printf("[INFO] Registered live item refresh processor")
+	}
+
+	if s.dependencies.ClipService != nil {
+		interval := viper.GetDuration("clips.integrity_check_interval")
+		repair := viper.GetString("clips.integrity_repair")
+		if !clips.IsValidRepairMode(repair) {
+			log.Printf("[WARN] Ignoring invalid clips.integrity_repair %q; broken clips will only be reported", repair)
+			repair = clips.RepairNone
+		}
+		if interval > 0 {
+			s.clipIntegrity = clips.NewIntegrityChecker(s.dependencies.ClipService, interval, repair)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	s.workerCancel = cancel
 
@@ -202,18 +438,90 @@ func (s *Server) initializeWorkerPool() error {
 		}
 	}()
 
+	if s.deletionSweeper != nil {
+		s.deletionSweeper.Start(ctx)
+	}
+
+	if s.notificationPruner != nil {
+		s.notificationPruner.Start(ctx)
+	}
+
+	if s.eventPruner != nil {
+		s.eventPruner.Start(ctx)
+	}
+
+	if s.savedSearches != nil {
+		s.savedSearches.Start(ctx)
+	}
+
+	if s.liveItems != nil {
+		s.liveItems.Start(ctx)
+	}
+
+	if s.clipIntegrity != nil {
+		s.clipIntegrity.Start(ctx)
+	}
+
+	if s.dependencies.TranscriptionService != nil {
+		go func() {
+			if err := s.dependencies.TranscriptionService.BuildSearchIndex(ctx); err != nil {
+				log.Printf("[WARN] Transcript search index build failed, retrying on the next search: %v", err)
+			}
+		}()
+	}
+
 	s.dependencies.WorkerPool = s.workerPool
 
 	return nil
 }
 
+// jobTimeoutsFromConfig reads jobs.timeout and the per-type jobs.timeouts
+func jobTimeoutsFromConfig() jobs.Timeouts {
+	timeouts := jobs.Timeouts{
+		Default: viper.GetDuration("jobs.timeout"),
+		ByType:  make(map[models.JobType]time.Duration),
+	}
+	for jobType, value := range viper.GetStringMapString("jobs.timeouts") {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			log.Printf("[WARN] Ignoring invalid timeout %q for %s jobs", value, jobType)
+			continue
+		}
+		timeouts.ByType[models.JobType(jobType)] = timeout
+	}
+	return timeouts
+}
+
+// jobQueueLimitsFromConfig reads jobs.queue_limit, the per-type
+// jobs.queue_limits and the low priority share
+func jobQueueLimitsFromConfig() jobs.QueueLimits {
+	limits := jobs.QueueLimits{
+		Default:          viper.GetInt("jobs.queue_limit"),
+		ByType:           make(map[models.JobType]int),
+		LowPriorityShare: viper.GetFloat64("jobs.low_priority_share"),
+		RetryAfter:       viper.GetDuration("jobs.queue_retry_after"),
+	}
+	for jobType := range viper.GetStringMap("jobs.queue_limits") {
+		limits.ByType[models.JobType(jobType)] = viper.GetInt("jobs.queue_limits." + jobType)
+	}
+	return limits
+}
+
 func (s *Server) initializeCleanupService() {
 	tempDir := viper.GetString("temp_dir")
 	cleanupInterval := viper.GetDuration("cleanup.interval")
 	maxTempAge := viper.GetDuration("cleanup.max_age")
 
+	// Temp files go in temp_dir rather than the system temp directory
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		log.Printf("[WARN] Failed to create temp directory %s: %v", tempDir, err)
+	}
+
 	s.cleanupService = cleanup.NewService(tempDir, maxTempAge, cleanupInterval)
 	s.cleanupService.Start(context.Background())
+	if s.dependencies != nil {
+		s.dependencies.CleanupService = s.cleanupService
+	}
 
 	log.Printf("[INFO] Cleanup service started for %s (interval: %v, max age: %v)", tempDir, cleanupInterval, maxTempAge)
 }
@@ -229,6 +537,30 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		s.workerPool.Stop()
 	}
 
+	if s.deletionSweeper != nil {
+		s.deletionSweeper.Stop()
+	}
+
+	if s.notificationPruner != nil {
+		s.notificationPruner.Stop()
+	}
+
+	if s.eventPruner != nil {
+		s.eventPruner.Stop()
+	}
+
+	if s.savedSearches != nil {
+		s.savedSearches.Stop()
+	}
+
+	if s.liveItems != nil {
+		s.liveItems.Stop()
+	}
+
+	if s.clipIntegrity != nil {
+		s.clipIntegrity.Stop()
+	}
+
 	if s.cleanupService != nil {
 		log.Println("[INFO] Stopping cleanup service...")
 		s.cleanupService.Stop()
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServer_Timeouts(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	viper.Set("server.read_timeout", "15s")
+	viper.Set("server.write_timeout", "30s")
+	viper.Set("server.idle_timeout", "invalid")
+
+	server := NewServer(":0")
+	assert.Equal(t, 15*time.Second, server.httpServer.ReadTimeout)
+	assert.Equal(t, 30*time.Second, server.httpServer.WriteTimeout)
+	assert.Equal(t, 120*time.Second, server.httpServer.IdleTimeout, "invalid values fall back")
+	assert.Equal(t, 10*time.Second, server.httpServer.ReadHeaderTimeout, "unset values fall back")
+}
@@ -0,0 +1,174 @@
+package share
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/spf13/viper"
+)
+
+// CreateShareRequest is the optional body of a share request
+type CreateShareRequest struct {
+	StartTime float64 `json:"start_time" example:"1425"` // Seconds into the episode; fractions are dropped
+}
+
+// ShareLinkResponse is a share link for an episode
+type ShareLinkResponse struct {
+	types.BaseResponse
+	Token     string `json:"token" example:"7_DdvZwBkQs"`
+	URL       string `json:"url" example:"/share/7_DdvZwBkQs"`
+	EpisodeID int64  `json:"episode_id" example:"41951637359"`
+	StartTime int    `json:"start_time,omitempty" example:"1425"`
+}
+
+// ResolvedShareResponse is what a share link points at
+type ResolvedShareResponse struct {
+	types.BaseResponse
+	Episode   *types.Episode `json:"episode"`
+	StartTime int            `json:"start_time,omitempty" example:"1425"`
+	TargetURL string         `json:"target_url" example:"https://example.com/episode.mp3#t=1425"`
+}
+
+// CreateEpisodeShare generates a share link for an episode
+// @Summary      Create an episode share link
+// @Description  Generate a short link to an episode, optionally starting at a moment in it ("listen from 23:45").
+// @Description  The token encodes the episode and start time, so links never expire. The URL is relative unless
+// @Description  share.base_url is configured.
+// @Tags         episodes
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1) example(41951637359)
+// @Param        request body CreateShareRequest false "Where playback starts"
+// @Success      201 {object} ShareLinkResponse "Share link"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID or start time"
+// @Failure      404 {object} types.ErrorResponse "Episode not found"
+// @Failure      500 {object} types.ErrorResponse "Failed to fetch episode"
+// @Router       /api/v1/episodes/{id}/share [post]
+func CreateEpisodeShare(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+		var req CreateShareRequest
+		if c.Request.ContentLength != 0 && !types.BindJSONOrError(c, &req) {
+			return
+		}
+
+		episode, ok := loadEpisode(c, deps, episodeID)
+		if !ok {
+			return
+		}
+
+		if req.StartTime < 0 || math.IsNaN(req.StartTime) || math.IsInf(req.StartTime, 0) {
+			types.SendError(c, http.StatusBadRequest, types.CodeInvalidRequest, "start_time must be a non-negative number of seconds")
+			return
+		}
+		start := int(req.StartTime)
+		if episode.Duration != nil && *episode.Duration > 0 && start >= *episode.Duration {
+			types.SendError(c, http.StatusBadRequest, types.CodeInvalidRequest, "start_time is past the end of the episode")
+			return
+		}
+
+		token := EncodeToken(episodeID, start)
+		c.JSON(http.StatusCreated, ShareLinkResponse{
+			BaseResponse: types.BaseResponse{
+				Status:  types.StatusOK,
+				Message: "Share link created",
+			},
+			Token:     token,
+			URL:       strings.TrimSuffix(viper.GetString("share.base_url"), "/") + "/share/" + token,
+			EpisodeID: episodeID,
+			StartTime: start,
+		})
+	}
+}
+
+// Resolve follows a share link
+// @Summary      Resolve a share link
+// @Description  Browsers are redirected to the episode at the shared moment: to share.redirect_url when configured,
+// @Description  otherwise to the episode audio with a #t= media fragment. Clients sending Accept: application/json
+// @Description  (or ?format=json) get the episode and start time instead.
+// @Tags         episodes
+// @Produce      json,html
+// @Param        token path string true "Share token" example(7_DdvZwBkQs)
+// @Param        format query string false "json to get the episode instead of a redirect"
+// @Success      200 {object} ResolvedShareResponse "Shared episode and start time"
+// @Success      302 "Redirect to the episode"
+// @Failure      404 {object} types.ErrorResponse "Unknown share link, or the episode is not stored"
+// @Failure      500 {object} types.ErrorResponse "Failed to fetch episode"
+// @Router       /share/{token} [get]
+func Resolve(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, start, err := DecodeToken(c.Param("token"))
+		if err != nil {
+			types.SendError(c, http.StatusNotFound, types.CodeShareLinkNotFound, "Share link not found")
+			return
+		}
+
+		episode, ok := loadEpisode(c, deps, episodeID)
+		if !ok {
+			return
+		}
+		target := targetURL(episode, start)
+
+		if c.Query("format") == "json" || c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON {
+			c.JSON(http.StatusOK, ResolvedShareResponse{
+				BaseResponse: types.BaseResponse{
+					Status:  types.StatusOK,
+					Message: "Share link resolved",
+				},
+				Episode:   types.FromModelEpisode(episode),
+				StartTime: start,
+				TargetURL: target,
+			})
+			return
+		}
+
+		if target == "" {
+			types.SendError(c, http.StatusNotFound, types.CodeEpisodeNoAudio, "Episode has no audio")
+			return
+		}
+		c.Redirect(http.StatusFound, target)
+	}
+}
+
+// loadEpisode loads the shared episode from the database, writing an error if
+// it is missing or blocked. Tokens are unauthenticated and decode to any ID, so
+// episodes that were never stored are not fetched from Podcast Index.
+func loadEpisode(c *gin.Context, deps *types.Dependencies, episodeID int64) (*models.Episode, bool) {
+	stored, err := deps.EpisodeService.GetEpisodesByPodcastIndexIDs(c.Request.Context(), []int64{episodeID})
+	if err != nil {
+		log.Printf("[ERROR] Failed to fetch shared episode %d: %v", episodeID, err)
+		types.SendError(c, http.StatusInternalServerError, types.CodeInternal, "Failed to fetch episode")
+		return nil, false
+	}
+	episode, ok := stored[episodeID]
+	if !ok || types.Blocklist(c, deps).Blocks(types.EpisodeSubject(episode)) {
+		types.SendError(c, http.StatusNotFound, types.CodeEpisodeNotFound, "Episode not found")
+		return nil, false
+	}
+	return episode, true
+}
+
+// targetURL is where a share link sends browsers. share.redirect_url may
+// contain {episode_id} and {start}; without it the audio itself is opened at
+// the start time with a media fragment.
+func targetURL(episode *models.Episode, start int) string {
+	if template := viper.GetString("share.redirect_url"); template != "" {
+		return strings.NewReplacer(
+			"{episode_id}", strconv.FormatInt(episode.PodcastIndexID, 10),
+			"{start}", strconv.Itoa(start),
+		).Replace(template)
+	}
+	if episode.AudioURL == "" || start == 0 {
+		return episode.AudioURL
+	}
+	return episode.AudioURL + "#t=" + strconv.Itoa(start)
+}
@@ -0,0 +1,61 @@
+package share
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// storedEpisodes serves episodes from the database only; any call that could
+// reach Podcast Index panics on the nil embedded service
+type storedEpisodes struct {
+	episodes.EpisodeService
+	stored map[int64]*models.Episode
+}
+
+func (s *storedEpisodes) GetEpisodesByPodcastIndexIDs(ctx context.Context, ids []int64) (map[int64]*models.Episode, error) {
+	found := make(map[int64]*models.Episode)
+	for _, id := range ids {
+		if episode, ok := s.stored[id]; ok {
+			found[id] = episode
+		}
+	}
+	return found, nil
+}
+
+func TestResolve_OnlyStoredEpisodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := &types.Dependencies{EpisodeService: &storedEpisodes{stored: map[int64]*models.Episode{
+		7: {PodcastIndexID: 7, AudioURL: "https://cdn.example.com/ep.mp3"},
+	}}}
+	router := gin.New()
+	router.GET("/share/:token", Resolve(deps))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/share/"+EncodeToken(7, 30), nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://cdn.example.com/ep.mp3#t=30", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/share/"+EncodeToken(41951637359, 0), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTargetURL(t *testing.T) {
+	defer viper.Reset()
+	episode := &models.Episode{PodcastIndexID: 41951637359, AudioURL: "https://cdn.example.com/ep.mp3"}
+
+	assert.Equal(t, "https://cdn.example.com/ep.mp3#t=1425", targetURL(episode, 1425))
+	assert.Equal(t, "https://cdn.example.com/ep.mp3", targetURL(episode, 0))
+
+	viper.Set("share.redirect_url", "https://player.example.com/e/{episode_id}?t={start}")
+	assert.Equal(t, "https://player.example.com/e/41951637359?t=1425", targetURL(episode, 1425))
+}
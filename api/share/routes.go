@@ -0,0 +1,19 @@
+package share
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// RegisterEpisodeRoutes registers share link creation under /api/v1/episodes
+func RegisterEpisodeRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// POST /api/v1/episodes/:id/share - Short link to an episode, optionally at a start time
+	router.POST("/:id/share", CreateEpisodeShare(deps))
+}
+
+// RegisterRoutes registers share link resolution. Links are opened by anyone
+// they are sent to, so these routes sit outside the API's auth.
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	// GET /share/:token - Redirect to (or describe) the shared episode
+	router.GET("/:token", Resolve(deps))
+}
@@ -0,0 +1,42 @@
+package share
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidToken is returned for tokens EncodeToken could not have produced
+var ErrInvalidToken = errors.New("invalid share token")
+
+// EncodeToken packs an episode's Podcast Index ID and a start time in whole
+// seconds into a short URL-safe token. The values are varints, so a typical
+// link is about a dozen characters. Nothing is stored: the token is the link.
+func EncodeToken(episodeID int64, start int) string {
+	buf := binary.AppendUvarint(nil, uint64(episodeID))
+	if start > 0 {
+		buf = binary.AppendUvarint(buf, uint64(start))
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// DecodeToken unpacks a token made by EncodeToken
+func DecodeToken(token string) (int64, int, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, 0, ErrInvalidToken
+	}
+	episodeID, n := binary.Uvarint(buf)
+	if n <= 0 || episodeID == 0 || episodeID > 1<<62 {
+		return 0, 0, ErrInvalidToken
+	}
+	buf = buf[n:]
+	if len(buf) == 0 {
+		return int64(episodeID), 0, nil
+	}
+	start, n := binary.Uvarint(buf)
+	if n != len(buf) || start == 0 || start > 1<<31 {
+		return 0, 0, ErrInvalidToken // Trailing bytes, or a zero start EncodeToken would have left out
+	}
+	return int64(episodeID), int(start), nil
+}
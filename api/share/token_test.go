@@ -0,0 +1,44 @@
+package share
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken_RoundTrip(t *testing.T) {
+	tests := []struct {
+		episodeID int64
+		start     int
+	}{
+		{41951637359, 0},
+		{41951637359, 1425},
+		{1, 1},
+		{16797088990, 36000},
+	}
+	for _, tt := range tests {
+		token := EncodeToken(tt.episodeID, tt.start)
+		assert.LessOrEqual(t, len(token), 12, "token %q is short", token)
+
+		episodeID, start, err := DecodeToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, tt.episodeID, episodeID)
+		assert.Equal(t, tt.start, start)
+	}
+}
+
+func TestDecodeToken_Invalid(t *testing.T) {
+	valid := EncodeToken(41951637359, 1425)
+	for _, token := range []string{
+		"",
+		"!!!",
+		"AA",                               // Episode ID 0
+		valid + "AA",                       // Trailing bytes
+		EncodeToken(41951637359, 0) + "AA", // Zero start
+		"_____________w",                   // Overlong varint
+	} {
+		_, _, err := DecodeToken(token)
+		assert.ErrorIs(t, err, ErrInvalidToken, "token %q", token)
+	}
+}
@@ -0,0 +1,226 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	summaryService "github.com/killallgit/player-api/internal/services/summary"
+	"gorm.io/gorm"
+)
+
+// TriggerSummary queues summary generation for an episode with a completed transcription
+// @Summary      Generate episode summary
+// @Description  Queue an LLM summary of the episode's transcription. A transcription is required; use
+// @Description  POST /episodes/{id}/transcribe first if one does not exist. While a transcription job is still
+// @Description  queued or running, the summary job waits for it and fails if it fails. The result contains a short
+// @Description  summary and a chapter-style topic list. Pass regenerate=true to replace an existing summary.
+// @Tags         summary
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        regenerate query bool false "Replace an existing summary"
+// @Success      200 {object} types.JobStatusResponse "Summary already exists"
+// @Success      202 {object} types.JobStatusResponse "Summary job queued (use job_id to track)"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID format"
+// @Failure      404 {object} types.ErrorResponse "No transcription available or in progress for this episode"
+// @Failure      500 {object} types.ErrorResponse "Service unavailable"
+// @Failure      503 {object} types.ErrorResponse "Job queue is full, see Retry-After"
+// @Router       /api/v1/episodes/{id}/summary [post]
+func TriggerSummary(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID < 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid Podcast Index Episode ID",
+			})
+			return
+		}
+
+		if deps.SummaryService == nil || deps.TranscriptionService == nil || deps.JobService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Summary service not available",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		regenerate := c.Query("regenerate") == "true"
+		if !regenerate {
+			if existing, err := deps.SummaryService.GetSummary(ctx, episodeID); err == nil && existing != nil {
+				c.JSON(http.StatusOK, types.JobStatusResponse{
+					EpisodeID: episodeID,
+					Status:    "completed",
+					Progress:  100,
+					Message:   "Summary already exists",
+				})
+				return
+			}
+		}
+
+		// Without a transcription the summary can still be queued behind a
+		// transcription job that is on its way; otherwise there is nothing to wait for
+		var jobOpts []jobs.JobOption
+		transcriptionModel, err := deps.TranscriptionService.GetTranscription(ctx, episodeID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to check transcription",
+			})
+			return
+		}
+		if transcriptionModel == nil {
+			transcriptionJob, jobErr := deps.JobService.GetJobForTranscription(ctx, episodeID)
+			if jobErr != nil || transcriptionJob.IsTerminal() {
+				c.JSON(http.StatusNotFound, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeTranscriptNotFound,
+					Message: "Transcription not found for episode",
+					Details: "Use POST /api/v1/episodes/{id}/transcribe to generate a transcription first",
+				})
+				return
+			}
+			jobOpts = append(jobOpts, jobs.WithDependsOn(transcriptionJob.ID))
+		}
+
+		existingJob, jobErr := deps.JobService.GetJobForSummary(ctx, episodeID)
+		if jobErr == nil && existingJob != nil {
+			switch existingJob.Status {
+			case models.JobStatusPending, models.JobStatusProcessing:
+				types.SetJobLocation(c, existingJob.ID)
+				c.JSON(http.StatusAccepted, types.JobStatusResponse{
+					EpisodeID: episodeID,
+					JobID:     existingJob.ID,
+					Status:    string(existingJob.Status),
+					Progress:  existingJob.Progress,
+					Message:   "Summary generation already in progress",
+				})
+				return
+			}
+		}
+
+		job, err := deps.JobService.EnqueueUniqueJob(ctx, models.JobTypeSummaryGeneration, models.JobPayload{
+			"episode_id": episodeID,
+		}, "episode_id", jobOpts...)
+		if err != nil {
+			log.Printf("Failed to enqueue summary job for episode %d: %v", episodeID, err)
+			types.SendServiceError(c, err, "Failed to trigger summary generation")
+			return
+		}
+
+		message := "Summary generation triggered"
+		if job.Status != models.JobStatusPending {
+			message = "Summary generation already in progress"
+		}
+		log.Printf("Summary generation job %d (%s) for episode %d", job.ID, job.Status, episodeID)
+		types.SetJobLocation(c, job.ID)
+		c.JSON(http.StatusAccepted, types.JobStatusResponse{
+			EpisodeID: episodeID,
+			JobID:     job.ID,
+			Status:    string(job.Status),
+			Progress:  job.Progress,
+			Message:   message,
+		})
+	}
+}
+
+// GetSummary returns the stored summary for an episode
+// @Summary      Get episode summary
+// @Description  Retrieve the LLM-generated summary and topic list for an episode. Use POST /episodes/{id}/summary
+// @Description  to generate one if it does not exist yet.
+// @Tags         summary
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Success      200 {object} types.SummaryData "Summary with topics"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID format"
+// @Failure      404 {object} types.ErrorResponse "No summary available for this episode"
+// @Failure      500 {object} types.ErrorResponse "Database or service error"
+// @Router       /api/v1/episodes/{id}/summary [get]
+func GetSummary(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || episodeID < 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid Podcast Index Episode ID",
+			})
+			return
+		}
+
+		if deps.SummaryService == nil {
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Summary service not available",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		summaryModel, err := deps.SummaryService.GetSummary(ctx, episodeID)
+		if err != nil {
+			if errors.Is(err, summaryService.ErrSummaryNotFound) {
+				c.JSON(http.StatusNotFound, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeSummaryNotFound,
+					Message: "Summary not found for episode",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInternal,
+				Message: "Failed to retrieve summary",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		// A regenerate job replaces the summary in the background, so the
+		// response cache must not keep serving the old one
+		c.Header("Cache-Control", "no-store")
+
+		topics, err := summaryModel.Topics()
+		if err != nil {
+			log.Printf("[WARN] Failed to decode summary topics for episode %d: %v", episodeID, err)
+			topics = []models.SummaryTopic{}
+		}
+
+		topicData := make([]types.SummaryTopicData, len(topics))
+		for i, topic := range topics {
+			topicData[i] = types.SummaryTopicData{
+				Title:       topic.Title,
+				Description: topic.Description,
+			}
+		}
+
+		c.JSON(http.StatusOK, types.SummaryData{
+			EpisodeID:        episodeID,
+			Summary:          summaryModel.Summary,
+			Topics:           topicData,
+			Model:            summaryModel.Model,
+			PromptTokens:     summaryModel.PromptTokens,
+			CompletionTokens: summaryModel.CompletionTokens,
+			Truncated:        summaryModel.Truncated,
+			GeneratedAt:      summaryModel.UpdatedAt,
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package summary
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// RegisterRoutes registers all summary-related routes
+func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	router.POST("/:id/summary", TriggerSummary(deps))
+	router.GET("/:id/summary", GetSummary(deps))
+}
@@ -0,0 +1,93 @@
+package transcription
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/pkg/transcript"
+)
+
+// downloadContentTypes maps the download formats to their content types
+var downloadContentTypes = map[string]string{
+	"srt": "application/x-subrip; charset=utf-8",
+	"vtt": "text/vtt; charset=utf-8",
+	"txt": "text/plain; charset=utf-8",
+}
+
+// DownloadTranscription returns the stored transcription as a subtitle or text file
+// @Summary      Download episode transcription as a file
+// @Description  Render the stored transcription as SubRip (srt), WebVTT (vtt) or plain text (txt) and return it
+// @Description  as an attachment named after the episode. Subtitle cues come from the stored timed segments, so
+// @Description  srt and vtt need a timed transcript; untimed transcripts can only be downloaded as txt.
+// @Tags         transcription
+// @Produce      plain
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        format query string false "File format" Enums(srt, vtt, txt) default(srt)
+// @Success      200 {file} file "Transcription file"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID or format"
+// @Failure      404 {object} types.ErrorResponse "No transcription available for this episode"
+// @Failure      409 {object} types.ErrorResponse "Transcript has no timing for subtitle cues"
+// @Failure      500 {object} types.ErrorResponse
+// @Router       /api/v1/episodes/{id}/transcription/download [get]
+func DownloadTranscription(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+		format := c.DefaultQuery("format", "srt")
+		contentType, ok := downloadContentTypes[format]
+		if !ok {
+			types.SendBadRequest(c, "format must be srt, vtt or txt")
+			return
+		}
+
+		if deps.TranscriptionService == nil {
+			types.SendInternalError(c, "Transcription service not available")
+			return
+		}
+		stored, err := deps.TranscriptionService.GetTranscription(c.Request.Context(), episodeID)
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to get transcription: %v", err))
+			return
+		}
+		if stored == nil {
+			types.SendError(c, http.StatusNotFound, types.CodeTranscriptNotFound, "Transcription not found for this episode")
+			return
+		}
+		segments, err := stored.Segments()
+		if err != nil {
+			types.SendInternalError(c, fmt.Sprintf("Failed to decode transcript segments: %v", err))
+			return
+		}
+
+		doc := &transcript.Transcript{FullText: stored.Text}
+		for _, segment := range segments {
+			doc.Segments = append(doc.Segments, transcript.Segment{
+				Start: time.Duration(segment.Start * float64(time.Second)),
+				End:   time.Duration(segment.End * float64(time.Second)),
+				Text:  segment.Text,
+			})
+		}
+
+		if format != "txt" && len(segments) == 0 {
+			types.SendConflict(c, "Transcript has no timing, so it can only be downloaded as txt")
+			return
+		}
+		var body string
+		switch format {
+		case "srt":
+			body = doc.ToSRT()
+		case "vtt":
+			body = doc.ToVTT()
+		default:
+			body = doc.ToPlainText() + "\n"
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="episode-%d.%s"`, episodeID, format))
+		c.Data(http.StatusOK, contentType, []byte(body))
+	}
+}
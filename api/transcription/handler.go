@@ -2,6 +2,7 @@ package transcription
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -22,10 +23,13 @@ import (
 // @Accept       json
 // @Produce      json
 // @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        callback_url query string false "URL that receives a signed POST when the transcription job completes or fails"
+// @Param        model query string false "Whisper model (tiny, base, small, medium, or a .en variant); defaults to the podcast's configured model, else the server default. See GET /transcription/models"
 // @Success      200 {object} types.JobStatusResponse "Transcription already exists and is ready"
 // @Success      202 {object} types.JobStatusResponse "Transcription job queued successfully (use job_id to track)"
-// @Failure      400 {object} types.ErrorResponse "Invalid episode ID format"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID format, callback_url or model"
 // @Failure      500 {object} types.ErrorResponse "Service unavailable or configuration error"
+// @Failure      503 {object} types.ErrorResponse "Job queue is full, see Retry-After"
 // @Router       /api/v1/episodes/{id}/transcribe [post]
 func TriggerTranscription(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -36,6 +40,7 @@ func TriggerTranscription(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil || episodeID < 0 {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Invalid Podcast Index Episode ID",
 			})
 			return
@@ -45,6 +50,7 @@ func TriggerTranscription(deps *types.Dependencies) gin.HandlerFunc {
 		if deps.TranscriptionService == nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Transcription service not available",
 			})
 			return
@@ -54,13 +60,27 @@ func TriggerTranscription(deps *types.Dependencies) gin.HandlerFunc {
 		if deps.JobService == nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Job service not available",
 			})
 			return
 		}
 
-		// Create context with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		callbackURL := c.Query("callback_url")
+		if !types.ValidateCallbackURL(c, deps, callbackURL) {
+			return
+		}
+
+		model := c.Query("model")
+		if model != "" && deps.WhisperModels != nil {
+			if err := deps.WhisperModels.Validate(model); err != nil {
+				types.SendBadRequest(c, fmt.Sprintf("Unknown whisper model %q", model))
+				return
+			}
+		}
+
+		// Derived from the request so a queued job joins its trace
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 		defer cancel()
 
 		// Check if transcription already exists
@@ -81,6 +101,8 @@ func TriggerTranscription(deps *types.Dependencies) gin.HandlerFunc {
 			// Job already exists, return status based on job state
 			switch existingJob.Status {
 			case models.JobStatusPending, models.JobStatusProcessing:
+				types.RegisterCallback(ctx, deps, existingJob.ID, callbackURL)
+				types.SetJobLocation(c, existingJob.ID)
 				c.JSON(http.StatusAccepted, types.JobStatusResponse{
 					EpisodeID: episodeID,
 					JobID:     existingJob.ID,
@@ -99,35 +121,39 @@ func TriggerTranscription(deps *types.Dependencies) gin.HandlerFunc {
 					Message:   "Transcription generation completed",
 				})
 				return
-			case models.JobStatusFailed:
-				// Job failed, allow creating a new one
-				log.Printf("Previous transcription job %d failed, creating new job", existingJob.ID)
 			}
 		}
 
-		// Create a new transcription generation job
+		// Create a transcription generation job. Concurrent requests for the same
+		// episode, and a failed job still awaiting retry, resolve to one live job.
 		payload := models.JobPayload{
 			"episode_id": episodeID,
 		}
+		if model != "" {
+			payload["model"] = model
+		}
 
-		job, err := deps.JobService.EnqueueJob(ctx, models.JobTypeTranscriptionGeneration, payload)
+		job, err := deps.JobService.EnqueueUniqueJob(ctx, models.JobTypeTranscriptionGeneration, payload, "episode_id")
 		if err != nil {
 			log.Printf("Failed to enqueue transcription job for episode %d: %v", episodeID, err)
-			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-				Status:  types.StatusError,
-				Message: "Failed to trigger transcription generation",
-				Details: err.Error(),
-			})
+			types.SendServiceError(c, err, "Failed to trigger transcription generation")
 			return
 		}
 
-		log.Printf("Enqueued transcription generation job %d for episode %d", job.ID, episodeID)
+		types.RegisterCallback(ctx, deps, job.ID, callbackURL)
+
+		message := "Transcription generation triggered"
+		if job.Status != models.JobStatusPending {
+			message = "Transcription generation already in progress"
+		}
+		log.Printf("Transcription generation job %d (%s) for episode %d", job.ID, job.Status, episodeID)
+		types.SetJobLocation(c, job.ID)
 		c.JSON(http.StatusAccepted, types.JobStatusResponse{
 			EpisodeID: episodeID,
 			JobID:     job.ID,
 			Status:    string(job.Status),
 			Progress:  job.Progress,
-			Message:   "Transcription generation triggered",
+			Message:   message,
 		})
 	}
 }
@@ -138,12 +164,16 @@ func TriggerTranscription(deps *types.Dependencies) gin.HandlerFunc {
 // @Description  from two sources: 'fetched' (downloaded from podcast RSS feed transcriptURL) or 'generated' (created
 // @Description  using Whisper speech-to-text). The response includes the full text, source type, language, and timestamps.
 // @Description  Use POST /episodes/{id}/transcribe first to trigger generation if transcription doesn't exist.
+// @Description  Pass wait (e.g. 30s, at most 45s) to hold the request open while a queued transcription finishes;
+// @Description  if the job is still running when the wait elapses, 202 with its progress is returned.
 // @Tags         transcription
 // @Accept       json
 // @Produce      json
 // @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        wait query string false "Wait up to this long for a running transcription job, e.g. 30s (max 45s)"
 // @Success      200 {object} types.TranscriptionData "Full transcription text with metadata"
-// @Failure      400 {object} types.ErrorResponse "Invalid episode ID format"
+// @Success      202 {object} types.JobStatusResponse "Transcription still in progress after waiting"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID format or wait"
 // @Failure      404 {object} types.ErrorResponse "No transcription available for this episode"
 // @Failure      500 {object} types.ErrorResponse "Database or service error"
 // @Router       /api/v1/episodes/{id}/transcribe [get]
@@ -156,6 +186,7 @@ func GetTranscription(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil || episodeID < 0 {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Invalid Podcast Index Episode ID",
 			})
 			return
@@ -165,11 +196,20 @@ func GetTranscription(deps *types.Dependencies) gin.HandlerFunc {
 		if deps.TranscriptionService == nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Transcription service not available",
 			})
 			return
 		}
 
+		wait, ok := types.ParseWait(c)
+		if !ok {
+			return
+		}
+		if wait > 0 {
+			awaitTranscription(c.Request.Context(), deps, episodeID, wait)
+		}
+
 		// Create context with timeout
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -179,8 +219,25 @@ func GetTranscription(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil {
 			// Check if it's a not found error
 			if err.Error() == "transcription not found" {
+				// A client that asked to wait gets the job's progress rather than a 404
+				if wait > 0 && deps.JobService != nil {
+					job, jobErr := deps.JobService.GetJobForTranscription(ctx, episodeID)
+					if jobErr == nil && job != nil &&
+						(job.Status == models.JobStatusPending || job.Status == models.JobStatusProcessing) {
+						types.SetJobLocation(c, job.ID)
+						c.JSON(http.StatusAccepted, types.JobStatusResponse{
+							EpisodeID: episodeID,
+							JobID:     job.ID,
+							Status:    string(job.Status),
+							Progress:  job.Progress,
+							Message:   "Transcription generation in progress",
+						})
+						return
+					}
+				}
 				c.JSON(http.StatusNotFound, types.ErrorResponse{
 					Status:  types.StatusError,
+					Code:    types.CodeTranscriptNotFound,
 					Message: "Transcription not found for episode",
 				})
 				return
@@ -188,6 +245,7 @@ func GetTranscription(deps *types.Dependencies) gin.HandlerFunc {
 
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Failed to retrieve transcription",
 				Details: err.Error(),
 			})
@@ -210,13 +268,15 @@ func GetTranscription(deps *types.Dependencies) gin.HandlerFunc {
 
 // GetTranscriptionStatus returns the processing status of a transcription
 // @Summary      Get transcription generation status
-// @Description Check the status of transcription generation for an episode
+// @Description Check the status of transcription generation for an episode. With wait (e.g. 30s, at most 45s)
+// @Description the request is held open until a running job completes or fails, or the wait elapses.
 // @Tags         transcription
 // @Accept       json
 // @Produce      json
 // @Param        id path int64 true "Episode ID (Podcast Index ID)"
+// @Param        wait query string false "Wait up to this long for a running transcription job, e.g. 30s (max 45s)"
 // @Success      200 {object} types.JobStatusResponse "Transcription status"
-// @Failure      400 {object} types.ErrorResponse "Invalid Podcast Index Episode ID"
+// @Failure      400 {object} types.ErrorResponse "Invalid Podcast Index Episode ID or wait"
 // @Failure      404 {object} types.JobStatusResponse "Transcription not available"
 // @Failure      500 {object} types.ErrorResponse "Internal server error"
 // @Router       /api/v1/episodes/{id}/transcribe/status [get]
@@ -229,6 +289,7 @@ func GetTranscriptionStatus(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil || episodeID < 0 {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Invalid Podcast Index Episode ID",
 			})
 			return
@@ -238,11 +299,20 @@ func GetTranscriptionStatus(deps *types.Dependencies) gin.HandlerFunc {
 		if deps.TranscriptionService == nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Transcription service not available",
 			})
 			return
 		}
 
+		wait, ok := types.ParseWait(c)
+		if !ok {
+			return
+		}
+		if wait > 0 {
+			awaitTranscription(c.Request.Context(), deps, episodeID, wait)
+		}
+
 		// Create context with timeout
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -271,9 +341,14 @@ func GetTranscriptionStatus(deps *types.Dependencies) gin.HandlerFunc {
 					Message:   "Transcription generation in progress",
 				}
 
-				if job.Status == models.JobStatusFailed {
+				if job.Status == models.JobStatusFailed || job.Status == models.JobStatusPermanentlyFailed {
 					response.Message = "Transcription generation failed"
 					response.Error = job.Error
+					response.ErrorType = job.ErrorType
+					response.ErrorCode = job.ErrorCode
+					response.RetryCount = job.RetryCount
+					response.MaxRetries = job.MaxRetries
+					response.JobError = types.NewJobError(job)
 				}
 
 				c.JSON(http.StatusOK, response)
@@ -289,3 +364,20 @@ func GetTranscriptionStatus(deps *types.Dependencies) gin.HandlerFunc {
 		})
 	}
 }
+
+// awaitTranscription holds a request with ?wait until the episode's running
+// transcription job finishes. Unlike waveforms, transcriptions are never
+// queued by a GET, so there is nothing to wait for without a job.
+func awaitTranscription(ctx context.Context, deps *types.Dependencies, episodeID int64, wait time.Duration) {
+	if deps.JobService == nil {
+		return
+	}
+	if _, err := deps.TranscriptionService.GetTranscription(ctx, episodeID); err == nil {
+		return
+	}
+	job, err := deps.JobService.GetJobForTranscription(ctx, episodeID)
+	if err != nil || job == nil {
+		return
+	}
+	types.WaitForJob(ctx, deps, job.ID, wait)
+}
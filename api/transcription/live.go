@@ -0,0 +1,202 @@
+package transcription
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	episodeService "github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/transcription"
+)
+
+// liveHeartbeat is how often a comment is sent while whisper works on a chunk,
+// keeping proxies and the per-write stream deadline from closing the connection
+const liveHeartbeat = 15 * time.Second
+
+// LiveSegment is a caption sent as an SSE "segment" event
+type LiveSegment struct {
+	Start float64 `json:"start"` // Seconds into the episode
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// LiveDone is sent as the final SSE "done" event
+type LiveDone struct {
+	Source string `json:"source"`           // "stored" when an existing transcript was replayed, otherwise "live"
+	Reason string `json:"reason,omitempty"` // Why a live stream stopped before the end of the audio
+}
+
+// StreamLiveTranscript streams captions for an episode as it is first played
+// @Summary      Stream live captions during playback
+// @Description  Server-sent events with captions for an episode that has no transcript yet. The audio already
+// @Description  fetched through /episodes/{id}/stream is transcribed with whisper in chunks, and each chunk's
+// @Description  segments are sent as "segment" events (start, end, text) as soon as they are ready. The stream
+// @Description  follows playback: when it catches up with the cached audio it waits for more. It ends with a
+// @Description  "done" event at the end of the audio, or with reason "idle" when nothing new is cached for a while.
+// @Description  Captions trail the cached audio by about one chunk. If the episode already has a timed transcript,
+// @Description  its segments are replayed instead with source "stored". This does not store a transcript; use
+// @Description  POST /episodes/{id}/transcribe for that.
+// @Tags         transcription
+// @Produce      text/event-stream
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        from query number false "Start captions this many seconds into the episode" default(0)
+// @Success      200 {object} LiveSegment "Event stream of segment events followed by a done event"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID or from"
+// @Failure      404 {object} types.ErrorResponse "Episode not found or has no audio"
+// @Failure      503 {object} types.ErrorResponse "Live transcription unavailable or at capacity"
+// @Router       /api/v1/episodes/{id}/transcribe/live [get]
+func StreamLiveTranscript(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		episodeID, ok := types.ParseInt64Param(c, "id")
+		if !ok {
+			return
+		}
+
+		from := 0.0
+		if raw := c.Query("from"); raw != "" {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil || parsed < 0 {
+				types.SendBadRequest(c, "from must be a non-negative number of seconds")
+				return
+			}
+			from = parsed
+		}
+
+		if deps.TranscriptionService == nil || deps.EpisodeService == nil {
+			c.JSON(http.StatusServiceUnavailable, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeServiceUnavailable,
+				Message: "Live transcription not available",
+			})
+			return
+		}
+		ctx := c.Request.Context()
+
+		if stored, err := deps.TranscriptionService.GetTranscription(ctx, episodeID); err == nil && stored != nil {
+			if segments, err := stored.Segments(); err == nil && len(segments) > 0 {
+				startEventStream(c)
+				sendSegments(c, segments, from)
+				c.SSEvent("done", LiveDone{Source: "stored"})
+				c.Writer.Flush()
+				return
+			}
+		}
+
+		if deps.LiveTranscriber == nil {
+			c.JSON(http.StatusServiceUnavailable, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeServiceUnavailable,
+				Message: "Live transcription not available",
+			})
+			return
+		}
+
+		episode, err := deps.EpisodeService.GetEpisodeByPodcastIndexID(ctx, episodeID)
+		if err != nil {
+			if episodeService.IsNotFound(err) {
+				types.SendError(c, http.StatusNotFound, types.CodeEpisodeNotFound, "Episode not found")
+				return
+			}
+			types.SendServiceError(c, err, "Failed to fetch episode")
+			return
+		}
+		if episode.AudioURL == "" {
+			types.SendError(c, http.StatusNotFound, types.CodeEpisodeNoAudio, "Episode has no audio")
+			return
+		}
+
+		chunks := make(chan []models.TranscriptSegment)
+		result := make(chan error, 1)
+		go func() {
+			result <- deps.LiveTranscriber.Stream(ctx, episodeID, episode.AudioURL, from, func(segments []models.TranscriptSegment) error {
+				select {
+				case chunks <- segments:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		}()
+
+		heartbeat := time.NewTicker(liveHeartbeat)
+		defer heartbeat.Stop()
+		started := false
+		for {
+			select {
+			case segments := <-chunks:
+				if !started {
+					startEventStream(c)
+					started = true
+				}
+				sendSegments(c, segments, 0)
+			case <-heartbeat.C:
+				if !started {
+					startEventStream(c)
+					started = true
+				}
+				_, _ = io.WriteString(c.Writer, ": keepalive\n\n")
+				c.Writer.Flush()
+			case err := <-result:
+				finishLiveStream(c, episodeID, err, started)
+				return
+			}
+		}
+	}
+}
+
+// finishLiveStream reports how a live stream ended. Errors before the first
+// event still get a JSON response; after that they become SSE events.
+func finishLiveStream(c *gin.Context, episodeID int64, err error, started bool) {
+	if errors.Is(err, transcription.ErrLiveBusy) && !started {
+		c.JSON(http.StatusServiceUnavailable, types.ErrorResponse{
+			Status:  types.StatusError,
+			Code:    types.CodeServiceUnavailable,
+			Message: "Live transcription at capacity, try again shortly",
+		})
+		return
+	}
+	if c.Request.Context().Err() != nil {
+		return // client went away
+	}
+
+	if !started {
+		startEventStream(c)
+	}
+	switch {
+	case err == nil:
+		c.SSEvent("done", LiveDone{Source: "live"})
+	case errors.Is(err, transcription.ErrLiveIdle):
+		c.SSEvent("done", LiveDone{Source: "live", Reason: "idle"})
+	default:
+		log.Printf("[ERROR] Live transcription for episode %d failed: %v", episodeID, err)
+		c.SSEvent("error", gin.H{"message": fmt.Sprintf("Live transcription failed: %v", err)})
+	}
+	c.Writer.Flush()
+}
+
+func startEventStream(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // stop nginx from buffering events
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+}
+
+// sendSegments writes the segments ending after from as SSE events
+func sendSegments(c *gin.Context, segments []models.TranscriptSegment, from float64) {
+	for _, segment := range segments {
+		if segment.End <= from {
+			continue
+		}
+		c.SSEvent("segment", LiveSegment{Start: segment.Start, End: segment.End, Text: segment.Text})
+	}
+	c.Writer.Flush()
+}
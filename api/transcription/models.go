@@ -0,0 +1,49 @@
+package transcription
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+)
+
+// ListModels returns the whisper models available for transcription
+// @Summary      List whisper models
+// @Description  List the whisper models that can be passed as the model parameter of POST /episodes/{id}/transcribe,
+// @Description  whether each is installed, and the server default. Known models that aren't installed are downloaded
+// @Description  on first use when auto_download is true; otherwise transcription with them fails.
+// @Tags         transcription
+// @Produce      json
+// @Success      200 {object} types.WhisperModelsResponse "Known and installed models"
+// @Failure      503 {object} types.ErrorResponse "Model registry not configured"
+// @Router       /api/v1/transcription/models [get]
+func ListModels(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deps.WhisperModels == nil {
+			c.JSON(http.StatusServiceUnavailable, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeServiceUnavailable,
+				Message: "Whisper models not configured",
+			})
+			return
+		}
+
+		infos := deps.WhisperModels.List()
+		models := make([]types.WhisperModel, 0, len(infos))
+		for _, info := range infos {
+			models = append(models, types.WhisperModel{
+				Name:      info.Name,
+				Installed: info.Installed,
+				Default:   info.Default,
+				SizeBytes: info.SizeBytes,
+			})
+		}
+
+		c.JSON(http.StatusOK, types.WhisperModelsResponse{
+			BaseResponse: types.BaseResponse{Status: types.StatusOK, Message: "Whisper models retrieved"},
+			Models:       models,
+			Default:      deps.WhisperModels.Default(),
+			AutoDownload: deps.WhisperModels.AutoDownload(),
+		})
+	}
+}
@@ -2,7 +2,9 @@ package transcription
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/middleware"
 	"github.com/killallgit/player-api/api/types"
+	"github.com/spf13/viper"
 )
 
 // RegisterRoutes registers all transcription-related routes
@@ -11,4 +13,18 @@ func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
 	router.POST("/:id/transcribe", TriggerTranscription(deps))
 	router.GET("/:id/transcribe", GetTranscription(deps))
 	router.GET("/:id/transcribe/status", GetTranscriptionStatus(deps))
+	router.GET("/:id/transcription/download", DownloadTranscription(deps))
+}
+
+// RegisterLiveRoutes registers the live caption stream. Like the audio proxy it
+// must not sit behind the response cache, which would hold the event stream
+// until it ends.
+func RegisterLiveRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	streamDeadline := middleware.StreamDeadline(viper.GetDuration("server.stream_write_timeout"))
+	router.GET("/:id/transcribe/live", streamDeadline, StreamLiveTranscript(deps))
+}
+
+// RegisterModelRoutes registers the whisper model registry routes
+func RegisterModelRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
+	router.GET("/models", ListModels(deps))
 }
@@ -12,7 +12,7 @@ import (
 
 // PodcastTrending defines the interface for getting trending podcasts
 type PodcastTrending interface {
-	GetTrending(ctx context.Context, max, since int, categories []string, lang string, fullText bool) (*podcastindex.SearchResponse, error)
+	GetTrending(ctx context.Context, max, since int, categories, notCategories []string, lang string, fullText bool) (*podcastindex.SearchResponse, error)
 }
 
 // Post handles trending podcasts requests with filters
@@ -21,6 +21,8 @@ type PodcastTrending interface {
 // @Description  Results can be filtered by time period, categories, and language. Trending podcasts are determined
 // @Description  by Podcast Index's algorithm which considers factors like new episodes, subscriber growth, and
 // @Description  social media mentions. Use the returned podcast IDs (feedId) with /podcasts/{id}/episodes to get episodes.
+// @Description  For signed-in users, the languages and excluded categories from their preferences apply unless the
+// @Description  request sets lang or notCategories.
 // @Tags         trending
 // @Accept       json
 // @Produce      json
@@ -28,6 +30,7 @@ type PodcastTrending interface {
 // @Success      200 {object} types.TrendingPodcastsResponse "List of trending podcasts with metadata"
 // @Failure      400 {object} types.ErrorResponse "Invalid request format or parameter values"
 // @Failure      500 {object} types.ErrorResponse "Failed to fetch trending data from Podcast Index API"
+// @Failure      503 {object} types.ErrorResponse "Daily Podcast Index budget spent"
 // @Failure      504 {object} types.ErrorResponse "Request timeout (limited to 10 seconds)"
 // @Router       /api/v1/trending [post]
 func Post(deps *types.Dependencies) gin.HandlerFunc {
@@ -37,6 +40,7 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Invalid request format",
 				Details: err.Error(),
 			})
@@ -55,6 +59,7 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 		if req.Max < 1 || req.Max > 100 {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Max must be between 1 and 100",
 			})
 			return
@@ -62,6 +67,7 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 		if req.Since < 1 || req.Since > 720 {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Since must be between 1 and 720 hours (30 days)",
 			})
 			return
@@ -72,37 +78,43 @@ func Post(deps *types.Dependencies) gin.HandlerFunc {
 		if !ok {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Trending service not available",
 			})
 			return
 		}
 
+		// Signed-in users get their language and category preferences unless
+		// the request sets its own
+		filters := types.UserContentFilters(c, deps).Override(req.Lang, req.NotCategories)
+		if !filters.IsEmpty() {
+			c.Header("Cache-Control", "private")
+		}
+
 		// Create context with timeout
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 		defer cancel()
 
 		// Get trending podcasts
-		results, err := podcastClient.GetTrending(ctx, req.Max, req.Since, req.Categories, req.Lang, req.FullText)
+		block := types.Blocklist(c, deps)
+		results, err := podcastClient.GetTrending(ctx, req.Max, req.Since, req.Categories, block.Categories(filters.ExcludedCategories), filters.Lang(), req.FullText)
 		if err != nil {
 			// Check if it's a context timeout
 			if ctx.Err() == context.DeadlineExceeded {
 				c.JSON(http.StatusGatewayTimeout, types.ErrorResponse{
 					Status:  types.StatusError,
+					Code:    types.CodeUpstreamTimeout,
 					Message: "Trending request timed out",
 				})
 				return
 			}
 
-			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-				Status:  types.StatusError,
-				Message: "Failed to fetch trending podcasts",
-				Details: err.Error(),
-			})
+			types.SendServiceError(c, err, "Failed to fetch trending podcasts")
 			return
 		}
 
 		// Transform Podcast Index results to our simplified format
-		podcasts := types.FromPodcastIndexList(results.Feeds)
+		podcasts := types.FromPodcastIndexList(block.FilterPodcasts(results.Feeds))
 
 		// Return the TrendingPodcastsResponse
 		c.JSON(http.StatusOK, types.TrendingPodcastsResponse{
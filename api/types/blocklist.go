@@ -0,0 +1,154 @@
+package types
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/blocklist"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+)
+
+// AdminPermission is the JWT permission that grants access to admin endpoints
+// and to blocked content
+const AdminPermission = "podcasts:admin"
+
+// HasPermission reports whether the authenticated caller holds permission
+func HasPermission(c *gin.Context, permission string) bool {
+	permissions, _ := c.Get("permissions")
+	granted, _ := permissions.([]string)
+	for _, p := range granted {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentBlock applies the blocklist to one request. Its zero value blocks nothing.
+type ContentBlock struct {
+	svc blocklist.Service
+}
+
+// Blocklist returns the blocklist for this request. Admins can pass
+// include_blocked=true to see blocked content; each such request is audited
+// and kept out of shared caches.
+func Blocklist(c *gin.Context, deps *Dependencies) ContentBlock {
+	if deps.BlocklistService == nil {
+		return ContentBlock{}
+	}
+	if c.Query("include_blocked") == "true" && HasPermission(c, AdminPermission) {
+		c.Header("Cache-Control", "private, no-store")
+		if err := deps.BlocklistService.RecordOverride(c.Request.Context(), c.GetString("user_id"), c.Request.URL.Path); err != nil {
+			log.Printf("[WARN] Failed to audit blocklist override: %v", err)
+		}
+		return ContentBlock{}
+	}
+	return ContentBlock{svc: deps.BlocklistService}
+}
+
+// Blocks reports whether subject must be withheld
+func (b ContentBlock) Blocks(subject blocklist.Subject) bool {
+	if b.svc == nil {
+		return false
+	}
+	entry := b.svc.Match(subject)
+	if entry != nil {
+		log.Printf("[DEBUG] Blocklist withheld feed %d (%s %q)", subject.FeedID, entry.Kind, entry.Value)
+	}
+	return entry != nil
+}
+
+// Categories returns the blocked categories to send upstream as notcat,
+// merged with the ones the caller asked to exclude
+func (b ContentBlock) Categories(notCategories []string) []string {
+	if b.svc == nil {
+		return notCategories
+	}
+	blocked := b.svc.BlockedCategories()
+	if len(blocked) == 0 {
+		return notCategories
+	}
+	return append(append([]string{}, notCategories...), blocked...)
+}
+
+// FilterPodcasts drops blocked podcasts
+func (b ContentBlock) FilterPodcasts(podcasts []podcastindex.Podcast) []podcastindex.Podcast {
+	if b.svc == nil {
+		return podcasts
+	}
+	kept := make([]podcastindex.Podcast, 0, len(podcasts))
+	for _, podcast := range podcasts {
+		if !b.Blocks(PodcastIndexSubject(podcast)) {
+			kept = append(kept, podcast)
+		}
+	}
+	return kept
+}
+
+// FilterEpisodes drops episodes of blocked podcasts
+func (b ContentBlock) FilterEpisodes(episodes []podcastindex.Episode) []podcastindex.Episode {
+	if b.svc == nil {
+		return episodes
+	}
+	kept := make([]podcastindex.Episode, 0, len(episodes))
+	for _, episode := range episodes {
+		if !b.Blocks(PodcastIndexEpisodeSubject(episode)) {
+			kept = append(kept, episode)
+		}
+	}
+	return kept
+}
+
+// PodcastIndexSubject describes a Podcast Index feed for blocklist matching
+func PodcastIndexSubject(p podcastindex.Podcast) blocklist.Subject {
+	subject := blocklist.Subject{
+		FeedID: int64(p.ID),
+		URLs:   []string{p.URL, p.OriginalURL, p.Link},
+	}
+	for id, name := range p.Categories {
+		subject.Categories = append(subject.Categories, id, name)
+	}
+	return subject
+}
+
+// PodcastIndexEpisodeSubject describes a Podcast Index episode for blocklist
+// matching. Episodes carry no categories; endpoints that return them pass
+// blocked categories upstream instead.
+func PodcastIndexEpisodeSubject(e podcastindex.Episode) blocklist.Subject {
+	return blocklist.Subject{
+		FeedID: int64(e.FeedId),
+		URLs:   []string{e.EnclosureURL, e.Link},
+	}
+}
+
+// PodcastSubject describes a stored podcast for blocklist matching
+func PodcastSubject(p *models.Podcast) blocklist.Subject {
+	subject := blocklist.Subject{
+		FeedID: p.PodcastIndexID,
+		URLs:   []string{p.FeedURL, p.OriginalURL, p.Link},
+	}
+	var categories map[string]string
+	if len(p.Categories) > 0 && json.Unmarshal(p.Categories, &categories) == nil {
+		for id, name := range categories {
+			subject.Categories = append(subject.Categories, id, name)
+		}
+	}
+	return subject
+}
+
+// EpisodeSubject describes a stored episode for blocklist matching, including
+// its podcast's categories when the podcast is loaded
+func EpisodeSubject(e *models.Episode) blocklist.Subject {
+	subject := blocklist.Subject{
+		FeedID: e.PodcastIndexFeedID,
+		URLs:   []string{e.AudioURL, e.Link},
+	}
+	if e.Podcast != nil {
+		podcast := PodcastSubject(e.Podcast)
+		subject.URLs = append(subject.URLs, podcast.URLs...)
+		subject.Categories = podcast.Categories
+	}
+	return subject
+}
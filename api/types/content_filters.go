@@ -0,0 +1,91 @@
+package types
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+)
+
+// ContentFilters narrows discovery results to the languages a listener reads
+// and away from categories they've hidden
+type ContentFilters struct {
+	Languages          []string
+	ExcludedCategories []string
+}
+
+// UserContentFilters loads the authenticated user's discovery filters from
+// their preferences. Anonymous requests, or a failed lookup, get no filters.
+func UserContentFilters(c *gin.Context, deps *Dependencies) ContentFilters {
+	userID := c.GetString("user_id")
+	if userID == "" || deps.PreferencesService == nil {
+		return ContentFilters{}
+	}
+
+	prefs, err := deps.PreferencesService.Get(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("[WARN] Failed to load discovery preferences for %s: %v", userID, err)
+		return ContentFilters{}
+	}
+	return ContentFilters{
+		Languages:          prefs.LanguageList(),
+		ExcludedCategories: prefs.ExcludedCategoryList(),
+	}
+}
+
+// Override replaces the stored filters with ones given explicitly on the
+// request: a non-empty lang, and notCategories whenever it was sent at all
+func (f ContentFilters) Override(lang string, notCategories []string) ContentFilters {
+	if lang = strings.TrimSpace(lang); lang != "" {
+		f.Languages = strings.Split(lang, ",")
+	}
+	if notCategories != nil {
+		f.ExcludedCategories = notCategories
+	}
+	return f
+}
+
+// IsEmpty reports whether the filters leave results untouched
+func (f ContentFilters) IsEmpty() bool {
+	return len(f.Languages) == 0 && len(f.ExcludedCategories) == 0
+}
+
+// Lang returns the languages in Podcast Index's comma-separated lang form
+func (f ContentFilters) Lang() string {
+	return strings.Join(f.Languages, ",")
+}
+
+// Allows reports whether a podcast passes the filters, for endpoints where
+// Podcast Index can't apply them itself. Languages match on their primary
+// subtag, so "en" admits feeds tagged "en-US"; categories match by name,
+// case-insensitively, or by ID.
+func (f ContentFilters) Allows(podcast podcastindex.Podcast) bool {
+	if len(f.Languages) > 0 && !matchesLanguage(podcast.Language, f.Languages) {
+		return false
+	}
+	for id, name := range podcast.Categories {
+		for _, excluded := range f.ExcludedCategories {
+			excluded = strings.TrimSpace(excluded)
+			if strings.EqualFold(excluded, name) || excluded == id {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchesLanguage(feedLanguage string, languages []string) bool {
+	feedLanguage = strings.ToLower(strings.TrimSpace(feedLanguage))
+	for _, lang := range languages {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if feedLanguage == lang || strings.HasPrefix(feedLanguage, lang+"-") {
+			return true
+		}
+		// A regional preference still accepts feeds tagged with the bare language
+		if primary, _, ok := strings.Cut(lang, "-"); ok && feedLanguage == primary {
+			return true
+		}
+	}
+	return false
+}
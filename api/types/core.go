@@ -18,6 +18,9 @@ type Podcast struct {
 	Categories   []string `json:"categories,omitempty"`
 	EpisodeCount int      `json:"episodeCount,omitempty"`
 	LastUpdated  int64    `json:"lastUpdated,omitempty"` // Unix timestamp
+	Health       string   `json:"health,omitempty"`      // "degraded" or "dead" when fetches keep failing; omitted while healthy
+
+	ArtworkPalette []string `json:"artwork_palette,omitempty"` // Dominant colors of Image as #rrggbb, most common first
 }
 
 // Episode represents a simplified episode with essential fields
@@ -35,16 +38,54 @@ type Episode struct {
 	ChaptersURL   string `json:"chaptersUrl,omitempty"`
 	Episode       int    `json:"episode,omitempty"` // Episode number
 	Season        int    `json:"season,omitempty"`  // Season number
+
+	MediaType           string               `json:"media_type" enums:"audio,video" example:"audio"` // Kind of enclosure; video episodes are processed from their audio track
+	AlternateEnclosures []AlternateEnclosure `json:"alternateEnclosures,omitempty"`                  // Other renditions, e.g. a lower bitrate; pick one when streaming with ?quality
+
+	Status         *EpisodeStatus `json:"status,omitempty"`          // Processing state; set by list endpoints
+	ArtworkPalette []string       `json:"artwork_palette,omitempty"` // Dominant colors of Image as #rrggbb, most common first
+}
+
+// AlternateEnclosure is another rendition of an episode's media
+type AlternateEnclosure struct {
+	URL     string `json:"url" example:"https://cdn.example.com/ep42-64k.mp3"`
+	Type    string `json:"type" example:"audio/mpeg"`
+	Length  int64  `json:"length,omitempty" example:"28800000"` // Bytes
+	Bitrate int    `json:"bitrate,omitempty" example:"64000"`   // Bits per second
+	Height  int    `json:"height,omitempty"`                    // Pixels, for video
+	Lang    string `json:"lang,omitempty" example:"en"`
+	Title   string `json:"title,omitempty" example:"Low bandwidth"`
+	Codecs  string `json:"codecs,omitempty" example:"mp3"`
+	Default bool   `json:"default,omitempty"` // The feed's preferred rendition
+}
+
+// EpisodeStatus summarizes what has been processed for an episode
+type EpisodeStatus struct {
+	HasWaveform       bool `json:"hasWaveform"`
+	HasTranscript     bool `json:"hasTranscript"`
+	ClipCount         int  `json:"clipCount"`
+	ApprovedClipCount int  `json:"approvedClipCount"`
 }
 
 // Waveform represents audio waveform data
 type Waveform struct {
-	ID         string    `json:"id"`
-	EpisodeID  int64     `json:"episodeId"`
-	Data       []float32 `json:"data"`
-	Duration   float64   `json:"duration"` // Total duration in seconds
-	SampleRate int       `json:"sampleRate"`
-	Status     string    `json:"status"`
+	ID         string         `json:"id"`
+	EpisodeID  int64          `json:"episodeId"`
+	Data       []float32      `json:"data"`
+	Duration   float64        `json:"duration"` // Total duration in seconds
+	SampleRate int            `json:"sampleRate"`
+	Status     string         `json:"status"`
+	Progress   int            `json:"progress,omitempty"` // Percent decoded; set with partial data while processing
+	Stats      *WaveformStats `json:"stats,omitempty"`    // Rendering hints for the whole episode; absent until generation finishes
+}
+
+// WaveformStats summarizes a waveform so clients can auto-scale rendering.
+// Peak values are on the same [0,1] scale as Data.
+type WaveformStats struct {
+	P95Peak        float32 `json:"p95Peak"`        // Scale so this maps near full height; the loudest 5% may clip
+	MedianPeak     float32 `json:"medianPeak"`     // Typical peak height
+	DynamicRangeDB float64 `json:"dynamicRangeDb"` // Spread between loud (p95) and quiet (p10) peaks
+	LoudnessDB     float64 `json:"loudnessDb"`     // RMS level of the audio in dBFS
 }
 
 // Transcription represents episode transcription data
@@ -59,8 +100,24 @@ type Transcription struct {
 
 // Category represents a podcast category
 type Category struct {
-	ID   int    `json:"id"`
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ParentID int    `json:"parentId,omitempty"` // Parent category ID for subcategories
+}
+
+// Person represents a host, guest or other credited contributor
+type Person struct {
+	ID   uint   `json:"id"`
 	Name string `json:"name"`
+	Href string `json:"href,omitempty"` // Profile or homepage URL
+	Img  string `json:"img,omitempty"`  // Headshot URL
+}
+
+// EpisodeCredit is a person's role on a specific episode
+type EpisodeCredit struct {
+	Person Person `json:"person"`
+	Role   string `json:"role"`            // e.g. "host", "guest"
+	Group  string `json:"group,omitempty"` // e.g. "cast", "writing"
 }
 
 // ReviewData contains aggregated review information
@@ -2,17 +2,48 @@ package types
 
 import (
 	"github.com/killallgit/player-api/internal/database"
+	"github.com/killallgit/player-api/internal/services/analytics"
+	"github.com/killallgit/player-api/internal/services/artwork"
 	"github.com/killallgit/player-api/internal/services/audiocache"
 	"github.com/killallgit/player-api/internal/services/auth"
+	"github.com/killallgit/player-api/internal/services/autoapproval"
+	"github.com/killallgit/player-api/internal/services/autoplay"
+	"github.com/killallgit/player-api/internal/services/blocklist"
+	"github.com/killallgit/player-api/internal/services/cache"
+	"github.com/killallgit/player-api/internal/services/categories"
+	"github.com/killallgit/player-api/internal/services/cleanup"
 	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/contentsafety"
+	"github.com/killallgit/player-api/internal/services/datasets"
 	episodeanalysis "github.com/killallgit/player-api/internal/services/episode_analysis"
 	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/events"
+	"github.com/killallgit/player-api/internal/services/externalusage"
+	"github.com/killallgit/player-api/internal/services/feedhealth"
+	"github.com/killallgit/player-api/internal/services/imports"
 	"github.com/killallgit/player-api/internal/services/itunes"
 	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/liveitems"
+	"github.com/killallgit/player-api/internal/services/loudness"
+	"github.com/killallgit/player-api/internal/services/modelregistry"
+	"github.com/killallgit/player-api/internal/services/notifications"
+	"github.com/killallgit/player-api/internal/services/people"
+	"github.com/killallgit/player-api/internal/services/playback"
 	"github.com/killallgit/player-api/internal/services/podcasts"
+	"github.com/killallgit/player-api/internal/services/preferences"
+	"github.com/killallgit/player-api/internal/services/rights"
+	"github.com/killallgit/player-api/internal/services/savedsearches"
+	"github.com/killallgit/player-api/internal/services/streamcache"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+	"github.com/killallgit/player-api/internal/services/summary"
 	"github.com/killallgit/player-api/internal/services/transcription"
+	"github.com/killallgit/player-api/internal/services/userdata"
 	"github.com/killallgit/player-api/internal/services/waveforms"
+	"github.com/killallgit/player-api/internal/services/webhooks"
 	"github.com/killallgit/player-api/internal/services/workers"
+	"github.com/killallgit/player-api/pkg/chapters"
+	"github.com/killallgit/player-api/pkg/download"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 )
 
 // Dependencies holds all the dependencies needed by handlers
@@ -21,12 +52,45 @@ type Dependencies struct {
 	PodcastService         podcasts.PodcastService
 	EpisodeService         episodes.EpisodeService
 	EpisodeTransformer     episodes.EpisodeTransformer
+	CategoryService        categories.Service
 	WaveformService        waveforms.WaveformService
 	TranscriptionService   transcription.TranscriptionService
+	LiveTranscriber        *transcription.Live          // Captions from the stream cache during first playback
+	WhisperModels          *transcription.ModelRegistry // Installed whisper models; shared so downloads happen once
+	SummaryService         summary.Service
+	ContentSafetyService   contentsafety.Service
+	LoudnessService        loudness.Service      // Loudness of cached episodes
+	ExternalUsageService   externalusage.Service // Podcast Index call accounting and daily budget
 	AudioCacheService      audiocache.Service
-	ClipService            clips.Service // New clip service for ML training data
+	StreamCacheService     streamcache.Service
+	DownloadPolicies       *download.Policies // Shared so per-host concurrency limits hold process-wide
+	FFmpeg                 *ffmpeg.FFmpeg     // Shared so the process cap covers workers and handlers
+	Chapters               *chapters.Fetcher  // Feeds' JSON chapter files, cached
+	ClipService            clips.Service      // New clip service for ML training data
 	EpisodeAnalysisService episodeanalysis.Service
+	ModelRegistry          modelregistry.Service // Detector models episode analysis can run
+	AutoApprovalService    autoapproval.Service  // Per-label confidence that approves analysis clips
+	PlaybackService        playback.Service
+	PeopleService          people.Service
+	PreferencesService     preferences.Service
+	AutoplayService        autoplay.Service // Users' rules for what plays after an episode
+	UserDataService        userdata.Service
+	NotificationService    notifications.Service
+	SavedSearchService     savedsearches.Service // Re-runs users' saved Podcast Index searches on a schedule
+	LiveItemService        liveitems.Service     // Podcasts' podcast:liveItem streams and their statuses
+	SubscriptionService    subscriptions.Service // Users' subscriptions with their folders and tags
+	ImportService          imports.Service       // Brings subscriptions and progress over from other apps
+	AnalyticsService       analytics.Service
+	EventsService          events.Service // Client playback analytics feeding AnalyticsService and listening stats
+	DatasetService         datasets.Service
+	WebhookService         webhooks.Service   // Delivers signed callback_url notifications
+	BlocklistService       blocklist.Service  // Withholds admin-flagged podcasts from every listing
+	FeedHealthService      feedhealth.Service // Flags podcasts whose feed or audio keeps failing
+	RightsService          rights.Service     // License and allowed uses per podcast, enforced at export
 	JobService             jobs.Service
+	CleanupService         *cleanup.Service // Removes temp files orphaned by crashes
+	ResponseCache          cache.Cache      // Cached API responses; nil when cache.enabled is off
+	ArtworkService         artwork.Service  // Dominant colors of podcast and episode artwork
 	WorkerPool             *workers.WorkerPool
 	PodcastClient          PodcastClient
 	ITunesClient           *itunes.Client
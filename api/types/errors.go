@@ -0,0 +1,214 @@
+package types
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/internal/services/autoapproval"
+	"github.com/killallgit/player-api/internal/services/autoplay"
+	"github.com/killallgit/player-api/internal/services/blocklist"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/datasets"
+	episodeanalysis "github.com/killallgit/player-api/internal/services/episode_analysis"
+	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/externalusage"
+	"github.com/killallgit/player-api/internal/services/imports"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/loudness"
+	"github.com/killallgit/player-api/internal/services/modelregistry"
+	"github.com/killallgit/player-api/internal/services/rights"
+	"github.com/killallgit/player-api/internal/services/savedsearches"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+	"github.com/killallgit/player-api/internal/services/summary"
+	"github.com/killallgit/player-api/internal/services/userdata"
+	"github.com/killallgit/player-api/internal/services/waveforms"
+)
+
+// ErrorCode is a stable, machine-readable error identifier. Clients should
+// branch on the code rather than the message, which may be reworded.
+type ErrorCode string
+
+// General error codes, one per HTTP status the API returns
+const (
+	CodeInvalidRequest      ErrorCode = "INVALID_REQUEST"
+	CodeUnauthorized        ErrorCode = "UNAUTHORIZED"
+	CodeForbidden           ErrorCode = "FORBIDDEN"
+	CodeNotFound            ErrorCode = "NOT_FOUND"
+	CodeConflict            ErrorCode = "CONFLICT"
+	CodePayloadTooLarge     ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeRangeNotSatisfiable ErrorCode = "RANGE_NOT_SATISFIABLE"
+	CodeUnprocessable       ErrorCode = "UNPROCESSABLE"
+	CodeRateLimited         ErrorCode = "RATE_LIMITED"
+	CodeInternal            ErrorCode = "INTERNAL_ERROR"
+	CodeUpstreamError       ErrorCode = "UPSTREAM_ERROR"
+	CodeServiceUnavailable  ErrorCode = "SERVICE_UNAVAILABLE"
+	CodeUpstreamTimeout     ErrorCode = "UPSTREAM_TIMEOUT"
+)
+
+// Resource-specific error codes
+const (
+	CodePodcastNotFound      ErrorCode = "PODCAST_NOT_FOUND"
+	CodeEpisodeNotFound      ErrorCode = "EPISODE_NOT_FOUND"
+	CodeEpisodeNoAudio       ErrorCode = "EPISODE_NO_AUDIO"
+	CodeClipNotFound         ErrorCode = "CLIP_NOT_FOUND"
+	CodeClipInvalidRange     ErrorCode = "CLIP_INVALID_RANGE"
+	CodeLabelQuotaExceeded   ErrorCode = "LABEL_QUOTA_EXCEEDED"
+	CodeTranscriptNotFound   ErrorCode = "TRANSCRIPT_NOT_FOUND"
+	CodeWaveformNotFound     ErrorCode = "WAVEFORM_NOT_FOUND"
+	CodeAudioNotCached       ErrorCode = "AUDIO_NOT_CACHED"
+	CodeSummaryNotFound      ErrorCode = "SUMMARY_NOT_FOUND"
+	CodeAnalysisNotFound     ErrorCode = "ANALYSIS_NOT_FOUND"
+	CodeJobNotFound          ErrorCode = "JOB_NOT_FOUND"
+	CodeDatasetNotFound      ErrorCode = "DATASET_NOT_FOUND"
+	CodeDatasetEmpty         ErrorCode = "DATASET_EMPTY"
+	CodeEvaluationNotFound   ErrorCode = "EVALUATION_NOT_FOUND"
+	CodeCategoryNotFound     ErrorCode = "CATEGORY_NOT_FOUND"
+	CodePersonNotFound       ErrorCode = "PERSON_NOT_FOUND"
+	CodeSubscriptionNotFound ErrorCode = "SUBSCRIPTION_NOT_FOUND"
+	CodeAlreadySubscribed    ErrorCode = "ALREADY_SUBSCRIBED"
+	CodeSavedSearchNotFound  ErrorCode = "SAVED_SEARCH_NOT_FOUND"
+	CodeSavedSearchLimit     ErrorCode = "SAVED_SEARCH_LIMIT"
+	CodeBlocklistNotFound    ErrorCode = "BLOCKLIST_ENTRY_NOT_FOUND"
+	CodeBlocklistDuplicate   ErrorCode = "BLOCKLIST_ENTRY_EXISTS"
+	CodeRightsNotFound       ErrorCode = "PODCAST_RIGHTS_NOT_FOUND"
+	CodeExportNotFound       ErrorCode = "EXPORT_NOT_FOUND"
+	CodeDeletionNotFound     ErrorCode = "DELETION_NOT_FOUND"
+	CodeProgressNotFound     ErrorCode = "PROGRESS_NOT_FOUND"
+	CodeInvalidCallbackURL   ErrorCode = "INVALID_CALLBACK_URL"
+	CodeLinkExpired          ErrorCode = "LINK_EXPIRED"
+	CodeInvalidLinkSignature ErrorCode = "INVALID_LINK_SIGNATURE"
+	CodeShareLinkNotFound    ErrorCode = "SHARE_LINK_NOT_FOUND"
+	CodeImportNotFound       ErrorCode = "IMPORT_NOT_FOUND"
+	CodeUnsupportedImport    ErrorCode = "UNSUPPORTED_IMPORT_FORMAT"
+	CodeModelNotFound        ErrorCode = "DETECTOR_MODEL_NOT_FOUND"
+	CodeModelExists          ErrorCode = "DETECTOR_MODEL_EXISTS"
+	CodeJobQueueFull         ErrorCode = "JOB_QUEUE_FULL"
+)
+
+// CodeForStatus returns the general error code for an HTTP status
+func CodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound, http.StatusGone:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusRequestedRangeNotSatisfiable:
+		return CodeRangeNotSatisfiable
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusBadGateway:
+		return CodeUpstreamError
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeUpstreamTimeout
+	default:
+		return CodeInternal
+	}
+}
+
+// serviceError is how a service error is reported to clients
+type serviceError struct {
+	err     error
+	status  int
+	code    ErrorCode
+	message string
+}
+
+// serviceErrors maps service sentinel errors to responses. Matching uses
+// errors.Is, so wrapped errors are found too; the first match wins.
+var serviceErrors = []serviceError{
+	{episodes.ErrEpisodeNotFound, http.StatusNotFound, CodeEpisodeNotFound, "Episode not found"},
+	{episodes.ErrInvalidCursor, http.StatusBadRequest, CodeInvalidRequest, "Invalid sync cursor"},
+	{clips.ErrClipNotFound, http.StatusNotFound, CodeClipNotFound, "Clip not found"},
+	{clips.ErrRangeOutOfBounds, http.StatusBadRequest, CodeClipInvalidRange, "Time range is outside the episode"},
+	{clips.ErrLabelQuotaExceeded, http.StatusConflict, CodeLabelQuotaExceeded, "Label quota reached"},
+	{waveforms.ErrWaveformNotFound, http.StatusNotFound, CodeWaveformNotFound, "Waveform not found"},
+	{loudness.ErrAudioNotCached, http.StatusNotFound, CodeAudioNotCached, "Episode audio has not been cached yet"},
+	{summary.ErrSummaryNotFound, http.StatusNotFound, CodeSummaryNotFound, "Summary not found"},
+	{jobs.ErrJobNotFound, http.StatusNotFound, CodeJobNotFound, "Job not found"},
+	{jobs.ErrJobFinished, http.StatusConflict, CodeConflict, "Job already finished"},
+	{jobs.ErrQueueFull, http.StatusServiceUnavailable, CodeJobQueueFull, "Too much work is queued, try again later"},
+	{datasets.ErrDatasetNotFound, http.StatusNotFound, CodeDatasetNotFound, "Dataset not found"},
+	{datasets.ErrEmptyDataset, http.StatusUnprocessableEntity, CodeDatasetEmpty, "No approved clips to export"},
+	{datasets.ErrArchiveMissing, http.StatusNotFound, CodeDatasetNotFound, "Dataset archive is no longer available"},
+	{datasets.ErrInvalidPredictions, http.StatusBadRequest, CodeInvalidRequest, "Invalid predictions"},
+	{datasets.ErrEvaluationNotFound, http.StatusNotFound, CodeEvaluationNotFound, "Evaluation not found"},
+	{datasets.ErrLinkExpired, http.StatusForbidden, CodeLinkExpired, "Download link has expired"},
+	{datasets.ErrInvalidSignature, http.StatusForbidden, CodeInvalidLinkSignature, "Invalid download link"},
+	{subscriptions.ErrSubscriptionNotFound, http.StatusNotFound, CodeSubscriptionNotFound, "Not subscribed to this podcast"},
+	{subscriptions.ErrAlreadySubscribed, http.StatusConflict, CodeAlreadySubscribed, "Already subscribed to this podcast"},
+	{subscriptions.ErrPodcastNotFound, http.StatusBadRequest, CodePodcastNotFound, "Podcast not found"},
+	{savedsearches.ErrSavedSearchNotFound, http.StatusNotFound, CodeSavedSearchNotFound, "Saved search not found"},
+	{savedsearches.ErrTooManySavedSearches, http.StatusConflict, CodeSavedSearchLimit, "Saved search limit reached"},
+	{blocklist.ErrEntryNotFound, http.StatusNotFound, CodeBlocklistNotFound, "Blocklist entry not found"},
+	{blocklist.ErrDuplicateEntry, http.StatusConflict, CodeBlocklistDuplicate, "Blocklist entry already exists"},
+	{rights.ErrRightsNotFound, http.StatusNotFound, CodeRightsNotFound, "No rights recorded for this podcast"},
+	{rights.ErrInvalidRights, http.StatusBadRequest, CodeInvalidRequest, "Invalid podcast rights"},
+	{modelregistry.ErrModelNotFound, http.StatusNotFound, CodeModelNotFound, "Detector model not found"},
+	{modelregistry.ErrInvalidModel, http.StatusBadRequest, CodeInvalidRequest, "Invalid detector model"},
+	{modelregistry.ErrModelExists, http.StatusConflict, CodeModelExists, "Detector model version already registered"},
+	{modelregistry.ErrDefaultModel, http.StatusConflict, CodeConflict, "Make another model the default first"},
+	{autoapproval.ErrThresholdNotFound, http.StatusNotFound, CodeNotFound, "No auto-approval threshold set for this label"},
+	{autoapproval.ErrInvalidThreshold, http.StatusBadRequest, CodeInvalidRequest, "Invalid auto-approval threshold"},
+	{episodeanalysis.ErrBackendUnavailable, http.StatusUnprocessableEntity, CodeUnprocessable, "This server can't run the model's backend"},
+	{userdata.ErrDeletionNotFound, http.StatusNotFound, CodeDeletionNotFound, "No deletion requested"},
+	{autoplay.ErrInvalidRules, http.StatusBadRequest, CodeInvalidRequest, "Invalid autoplay rules"},
+	{imports.ErrUnsupportedFormat, http.StatusBadRequest, CodeUnsupportedImport, "Unsupported import format"},
+	{imports.ErrInvalidFile, http.StatusBadRequest, CodeInvalidRequest, "Import file could not be read"},
+	{imports.ErrEmptyImport, http.StatusUnprocessableEntity, CodeUnprocessable, "Import file has no podcasts or episodes"},
+	{imports.ErrTooLarge, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "Import file has too many entries"},
+	{externalusage.ErrBudgetExceeded, http.StatusServiceUnavailable, CodeServiceUnavailable, "Today's Podcast Index budget is spent; only stored podcasts are available"},
+}
+
+// LookupServiceError returns the status, code and message for a known
+// service error, or false for errors that should be reported as internal
+func LookupServiceError(err error) (int, ErrorCode, string, bool) {
+	for _, known := range serviceErrors {
+		if errors.Is(err, known.err) {
+			return known.status, known.code, known.message, true
+		}
+	}
+	return 0, "", "", false
+}
+
+// NewErrorResponse builds the standard error envelope
+func NewErrorResponse(code ErrorCode, message string) ErrorResponse {
+	return ErrorResponse{Status: StatusError, Code: code, Message: message}
+}
+
+// SendError sends the standard error envelope with a specific code. Like the
+// other Send helpers, it repeats the message in 'error' for older clients.
+func SendError(c *gin.Context, status int, code ErrorCode, message string) {
+	c.JSON(status, legacyErrorResponse(code, message))
+}
+
+// SendServiceError reports a service error: known errors get their own
+// status and code with the error text as details, anything else is a 500
+// with the given message. A full job queue also says when to retry.
+func SendServiceError(c *gin.Context, err error, message string) {
+	var queueFull *jobs.QueueFullError
+	if errors.As(err, &queueFull) && queueFull.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(queueFull.RetryAfter.Seconds()))))
+	}
+	if status, code, known, ok := LookupServiceError(err); ok {
+		response := legacyErrorResponse(code, known)
+		response.Details = err.Error()
+		c.JSON(status, response)
+		return
+	}
+	SendError(c, http.StatusInternalServerError, CodeInternal, message)
+}
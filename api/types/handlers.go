@@ -1,10 +1,16 @@
 package types
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/internal/models"
 )
 
 // Handler utility functions to reduce duplication across handlers
@@ -15,9 +21,7 @@ func ParseUintParam(c *gin.Context, paramName string) (uint, bool) {
 	paramStr := c.Param(paramName)
 	value, err := strconv.ParseUint(paramStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid " + paramName,
-		})
+		SendBadRequest(c, "Invalid "+paramName)
 		return 0, false
 	}
 	return uint(value), true
@@ -29,9 +33,7 @@ func ParseInt64Param(c *gin.Context, paramName string) (int64, bool) {
 	paramStr := c.Param(paramName)
 	value, err := strconv.ParseInt(paramStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid " + paramName,
-		})
+		SendBadRequest(c, "Invalid "+paramName)
 		return 0, false
 	}
 	return value, true
@@ -41,10 +43,9 @@ func ParseInt64Param(c *gin.Context, paramName string) (int64, bool) {
 // Returns false and sends error response if binding fails
 func BindJSONOrError(c *gin.Context, target interface{}) bool {
 	if err := c.ShouldBindJSON(target); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Details: err.Error(),
-		})
+		response := legacyErrorResponse(CodeInvalidRequest, "Invalid request body")
+		response.Details = err.Error()
+		c.JSON(http.StatusBadRequest, response)
 		return false
 	}
 	return true
@@ -52,17 +53,31 @@ func BindJSONOrError(c *gin.Context, target interface{}) bool {
 
 // SendBadRequest sends a standardized bad request response
 func SendBadRequest(c *gin.Context, message string) {
-	c.JSON(http.StatusBadRequest, ErrorResponse{Error: message})
+	c.JSON(http.StatusBadRequest, legacyErrorResponse(CodeInvalidRequest, message))
 }
 
-// SendNotFound sends a standardized not found response
+// SendNotFound sends a standardized not found response. Use SendError with a
+// resource-specific code when the missing resource is known.
 func SendNotFound(c *gin.Context, message string) {
-	c.JSON(http.StatusNotFound, ErrorResponse{Error: message})
+	c.JSON(http.StatusNotFound, legacyErrorResponse(CodeNotFound, message))
+}
+
+// SendConflict sends a standardized conflict response
+func SendConflict(c *gin.Context, message string) {
+	c.JSON(http.StatusConflict, legacyErrorResponse(CodeConflict, message))
 }
 
 // SendInternalError sends a standardized internal server error response
 func SendInternalError(c *gin.Context, message string) {
-	c.JSON(http.StatusInternalServerError, ErrorResponse{Error: message})
+	c.JSON(http.StatusInternalServerError, legacyErrorResponse(CodeInternal, message))
+}
+
+// legacyErrorResponse builds the error envelope for the helpers above, which
+// always returned the message in 'error'; it stays there for older clients
+func legacyErrorResponse(code ErrorCode, message string) ErrorResponse {
+	response := NewErrorResponse(code, message)
+	response.Error = message
+	return response
 }
 
 // SendSuccess sends a standardized success response with data
@@ -74,3 +89,236 @@ func SendSuccess(c *gin.Context, data interface{}) {
 func SendCreated(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusCreated, data)
 }
+
+// JobLocation returns the public status URL of a background job
+func JobLocation(jobID uint) string {
+	return fmt.Sprintf("/api/v1/jobs/%d", jobID)
+}
+
+// SetJobLocation points a 202 response at the status endpoint of the job doing the work
+func SetJobLocation(c *gin.Context, jobID uint) {
+	if jobID > 0 {
+		c.Header("Location", JobLocation(jobID))
+	}
+}
+
+// MaxWait caps the wait query parameter of long-polling GETs
+const MaxWait = 45 * time.Second
+
+// waitWriteMargin is how long a long-polling request has to write its
+// response once the wait is over
+const waitWriteMargin = 15 * time.Second
+
+// waitPollInterval is how often a long-polling request rechecks its job
+const waitPollInterval = 500 * time.Millisecond
+
+// ParseWait reads the optional wait query parameter of a long-polling GET, a
+// duration such as 30s or a number of seconds, answering 400 itself when it
+// is invalid. Longer waits are capped at MaxWait; no parameter means 0. The
+// connection's write deadline is moved past the wait, so a server.write_timeout
+// shorter than the wait does not cut the response off.
+func ParseWait(c *gin.Context) (time.Duration, bool) {
+	raw := c.Query("wait")
+	if raw == "" {
+		return 0, true
+	}
+	wait, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			wait = -1
+		} else {
+			wait = time.Duration(seconds) * time.Second
+		}
+	}
+	if wait < 0 {
+		SendBadRequest(c, "wait must be a duration such as 30s")
+		return 0, false
+	}
+	wait = min(wait, MaxWait)
+	if wait > 0 {
+		deadline := time.Now().Add(wait + waitWriteMargin)
+		// Not every writer supports deadlines (e.g. httptest recorders); ignore those
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			log.Printf("[WARN] Failed to extend write deadline for long poll: %v", err)
+		}
+	}
+	return wait, true
+}
+
+// WaitForJob blocks until the job leaves the pending and processing states,
+// wait elapses or the client goes away. A failed job awaiting its retry
+// counts as finished, so the caller can report the failure.
+func WaitForJob(ctx context.Context, deps *Dependencies, jobID uint, wait time.Duration) {
+	if deps.JobService == nil || jobID == 0 || wait <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+	for {
+		job, err := deps.JobService.GetJob(ctx, jobID)
+		if err != nil || (job.Status != models.JobStatusPending && job.Status != models.JobStatusProcessing) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ValidateCallbackURL checks an optional callback_url before any work is
+// queued, answering 400 itself when the URL is unusable
+func ValidateCallbackURL(c *gin.Context, deps *Dependencies, callbackURL string) bool {
+	if callbackURL == "" {
+		return true
+	}
+	if deps.WebhookService == nil {
+		SendBadRequest(c, "callback_url is not supported by this server")
+		return false
+	}
+	if err := deps.WebhookService.ValidateURL(callbackURL); err != nil {
+		SendBadRequest(c, err.Error())
+		return false
+	}
+	return true
+}
+
+// RegisterCallback attaches a validated callback_url to a queued job. The job
+// is already running by now, so a failure is logged rather than returned. A
+// job that finished before the callback was registered is reported to it
+// right away.
+func RegisterCallback(ctx context.Context, deps *Dependencies, jobID uint, callbackURL string) {
+	if callbackURL == "" || deps.WebhookService == nil {
+		return
+	}
+	if err := deps.WebhookService.Register(ctx, jobID, callbackURL); err != nil {
+		log.Printf("[WARN] Failed to register callback for job %d: %v", jobID, err)
+		return
+	}
+	if deps.JobService == nil {
+		return
+	}
+
+	job, err := deps.JobService.GetJob(ctx, jobID)
+	if err != nil {
+		log.Printf("[WARN] Failed to check job %d after registering its callback: %v", jobID, err)
+		return
+	}
+	if err := deps.WebhookService.CatchUp(ctx, job); err != nil {
+		log.Printf("[WARN] Failed to deliver callback for finished job %d: %v", jobID, err)
+	}
+}
+
+// QueueClipExtraction enqueues extraction of a newly created clip so a
+// callback_url has a job to report on. It returns 0 when nothing was queued.
+func QueueClipExtraction(c *gin.Context, deps *Dependencies, clipUUID, callbackURL string) uint {
+	if callbackURL == "" || deps.JobService == nil {
+		return 0
+	}
+	ctx := c.Request.Context()
+	job, err := deps.JobService.EnqueueUniqueJob(ctx, models.JobTypeClipExtraction,
+		models.JobPayload{"clip_uuid": clipUUID}, "clip_uuid")
+	if err != nil {
+		log.Printf("[WARN] Failed to enqueue extraction for clip %s: %v", clipUUID, err)
+		return 0
+	}
+	RegisterCallback(ctx, deps, job.ID, callbackURL)
+	SetJobLocation(c, job.ID)
+	return job.ID
+}
+
+// ClipSampleRates loads the original sample rates of the clips' episodes for
+// their sample coordinates. The coordinates are optional, so a failed lookup
+// is logged and the clips are returned without them.
+func ClipSampleRates(c *gin.Context, deps *Dependencies, clips ...*models.Clip) map[int64]int {
+	if deps.ClipService == nil || len(clips) == 0 {
+		return nil
+	}
+	seen := make(map[int64]bool, len(clips))
+	var episodeIDs []int64
+	for _, clip := range clips {
+		if !seen[clip.PodcastIndexEpisodeID] {
+			seen[clip.PodcastIndexEpisodeID] = true
+			episodeIDs = append(episodeIDs, clip.PodcastIndexEpisodeID)
+		}
+	}
+	rates, err := deps.ClipService.SampleRates(c.Request.Context(), episodeIDs)
+	if err != nil {
+		log.Printf("[WARN] Returning clips without sample coordinates: %v", err)
+	}
+	return rates
+}
+
+// ClipTranscripts fills in the clips' transcript_text from their episodes'
+// timed transcripts. Clips are still returned if the transcripts can't be read.
+func ClipTranscripts(c *gin.Context, deps *Dependencies, clips ...*models.Clip) {
+	if deps.ClipService == nil || len(clips) == 0 {
+		return
+	}
+	if err := deps.ClipService.AttachTranscriptText(c.Request.Context(), clips); err != nil {
+		log.Printf("[WARN] Returning clips without transcript text: %v", err)
+	}
+}
+
+// ArtworkPalette returns the dominant colors of an image, extracting them
+// first if they haven't been. The palette is optional, so a failure is logged
+// and nil returned.
+func ArtworkPalette(c *gin.Context, deps *Dependencies, imageURL string) []string {
+	if deps.ArtworkService == nil || imageURL == "" {
+		return nil
+	}
+	palette, err := deps.ArtworkService.Palette(c.Request.Context(), imageURL)
+	if err != nil {
+		log.Printf("[WARN] Returning %s without artwork palette: %v", imageURL, err)
+	}
+	return palette
+}
+
+// WithEpisodePalettes sets the episodes' artwork palettes from those already
+// extracted. Images without one are queued, so they appear on a later request.
+func WithEpisodePalettes(c *gin.Context, deps *Dependencies, episodes []Episode) []Episode {
+	if deps.ArtworkService == nil || len(episodes) == 0 {
+		return episodes
+	}
+	imageURLs := make([]string, len(episodes))
+	for i := range episodes {
+		imageURLs[i] = episodes[i].Image
+	}
+	palettes, err := deps.ArtworkService.Palettes(c.Request.Context(), imageURLs)
+	if err != nil {
+		log.Printf("[WARN] Returning episodes without artwork palettes: %v", err)
+		return episodes
+	}
+	for i := range episodes {
+		episodes[i].ArtworkPalette = palettes[episodes[i].Image]
+	}
+	return episodes
+}
+
+// WithPodcastPalettes is WithEpisodePalettes for podcasts
+func WithPodcastPalettes(c *gin.Context, deps *Dependencies, podcasts ...*Podcast) {
+	if deps.ArtworkService == nil || len(podcasts) == 0 {
+		return
+	}
+	imageURLs := make([]string, 0, len(podcasts))
+	for _, podcast := range podcasts {
+		if podcast != nil {
+			imageURLs = append(imageURLs, podcast.Image)
+		}
+	}
+	palettes, err := deps.ArtworkService.Palettes(c.Request.Context(), imageURLs)
+	if err != nil {
+		log.Printf("[WARN] Returning podcasts without artwork palettes: %v", err)
+		return
+	}
+	for _, podcast := range podcasts {
+		if podcast != nil {
+			podcast.ArtworkPalette = palettes[podcast.Image]
+		}
+	}
+}
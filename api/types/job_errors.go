@@ -0,0 +1,83 @@
+package types
+
+import (
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// JobError explains why a job, or the clip it extracted, failed. Clients
+// branch on category and code; message is fit to show users as is.
+type JobError struct {
+	Category  string `json:"category" example:"download" enums:"download,processing,system,not_found,resource_limit,dependency,configuration,unknown"`
+	Code      string `json:"code,omitempty" example:"403"`                               // Finer cause within the category, e.g. "403", "timeout", "corrupt_file"
+	Retryable bool   `json:"retryable" example:"true"`                                   // The work will be retried automatically
+	Message   string `json:"message" example:"The podcast's host is blocking downloads"` // User-facing explanation
+}
+
+// jobErrorMessages are the user-facing messages by category and code. The
+// empty code holds the category's fallback.
+var jobErrorMessages = map[models.JobErrorType]map[string]string{
+	models.ErrorTypeDownload: {
+		"":                 "The episode's audio could not be downloaded",
+		"403":              "The podcast's host is blocking downloads",
+		"404":              "The episode's audio file is no longer on the podcast's host",
+		"timeout":          "The podcast's host took too long to send the audio",
+		"dns_error":        "The podcast's host could not be reached",
+		"connection_error": "The podcast's host could not be reached",
+	},
+	models.ErrorTypeProcessing: {
+		"":                   "The episode's audio could not be processed",
+		"corrupt_file":       "The episode's audio file is damaged",
+		"unsupported_format": "The episode's audio is in a format that isn't supported",
+		"no_audio_stream":    "The episode's file has no audio",
+		"duration_exceeded":  "The episode is too long to process",
+	},
+	models.ErrorTypeSystem: {
+		"":            "Something went wrong on our side",
+		"job_timeout": "Processing took longer than allowed",
+	},
+	models.ErrorTypeNotFound: {
+		"":        "Something this needs no longer exists",
+		"episode": "The episode no longer exists",
+	},
+	models.ErrorTypeResourceLimit: {
+		"": "The episode is too large to process",
+	},
+	models.ErrorTypeDependency: {
+		"": "A step this depends on failed",
+	},
+	models.ErrorTypeConfiguration: {
+		"": "This feature isn't set up correctly on our side",
+	},
+}
+
+// NewJobError describes a failed job, or returns nil for a job that hasn't
+// failed. A failed job awaiting its next attempt is retryable.
+func NewJobError(job *models.Job) *JobError {
+	if job == nil || (job.Status != models.JobStatusFailed && job.Status != models.JobStatusPermanentlyFailed) {
+		return nil
+	}
+	return newJobError(models.JobErrorType(job.ErrorType), job.ErrorCode, job.Status == models.JobStatusFailed)
+}
+
+// NewClipError describes why a clip failed to extract, or returns nil for a
+// clip that hasn't failed. Failures recorded outside the extraction job are
+// unclassified.
+func NewClipError(clip *models.Clip) *JobError {
+	if clip == nil || !clip.IsFailed() {
+		return nil
+	}
+	errorType := models.JobErrorType(clip.ErrorType)
+	return newJobError(errorType, clip.ErrorCode, errorType != "" && errorType.Retryable())
+}
+
+func newJobError(errorType models.JobErrorType, code string, retryable bool) *JobError {
+	messages, ok := jobErrorMessages[errorType]
+	if !ok {
+		return &JobError{Category: "unknown", Code: code, Retryable: retryable, Message: "The work could not be completed"}
+	}
+	message, ok := messages[code]
+	if !ok {
+		message = messages[""]
+	}
+	return &JobError{Category: string(errorType), Code: code, Retryable: retryable, Message: message}
+}
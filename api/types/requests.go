@@ -1,5 +1,7 @@
 package types
 
+import "github.com/killallgit/player-api/internal/services/autoplay"
+
 // SearchRequest represents a podcast search request
 type SearchRequest struct {
 	Query    string `json:"query" binding:"required" example:"technology"`
@@ -8,6 +10,17 @@ type SearchRequest struct {
 	Val      string `json:"val,omitempty" example:"any"`      // Filter by value block type (e.g., "any", "lightning")
 	ApOnly   bool   `json:"apOnly,omitempty" example:"false"` // Only return podcasts with iTunes ID
 	Clean    bool   `json:"clean,omitempty" example:"false"`  // Only return non-explicit content
+	// Override the user's language and category preferences for this search
+	Lang          string   `json:"lang,omitempty" example:"en,es"`                  // Comma-separated language codes
+	NotCategories []string `json:"notCategories,omitempty" example:"News,Politics"` // Category names/IDs to leave out; [] disables the preference
+}
+
+// TranscriptSearchRequest represents a search across stored transcripts
+type TranscriptSearchRequest struct {
+	Query    string `json:"query" binding:"required" example:"solar power"`
+	Limit    int    `json:"limit,omitempty" example:"10"`   // Episodes to return, 1-50
+	Offset   int    `json:"offset,omitempty" example:"0"`   // Episodes to skip
+	Segments int    `json:"segments,omitempty" example:"3"` // Matching segments per episode, 1-10
 }
 
 // TrendingRequest represents a trending podcasts request
@@ -17,4 +30,71 @@ type TrendingRequest struct {
 	Categories []string `json:"categories,omitempty" example:"News,Technology"`        // Category names/IDs to filter
 	Lang       string   `json:"lang,omitempty" validate:"max=10" example:"en"`         // Language code
 	FullText   bool     `json:"fullText,omitempty" example:"false"`                    // Return full descriptions
+	// Category names/IDs to leave out; [] disables the user's excluded categories
+	NotCategories []string `json:"notCategories,omitempty" example:"News,Politics"`
+}
+
+// PlaybackProgressRequest reports the listener's position in an episode
+type PlaybackProgressRequest struct {
+	Position  float64 `json:"position" example:"1234.5"`         // Current position in seconds
+	Duration  float64 `json:"duration,omitempty" example:"3600"` // Episode duration in seconds, if known
+	Completed bool    `json:"completed,omitempty" example:"false"`
+}
+
+// PreferencesRequest is a partial preferences update; omitted fields are unchanged.
+// Extras are merged key by key; set a key to null to remove it.
+type PreferencesRequest struct {
+	PlaybackSpeed      *float64               `json:"playback_speed,omitempty" example:"1.25"`
+	SkipForwardSeconds *int                   `json:"skip_forward_seconds,omitempty" example:"30"`
+	SkipBackSeconds    *int                   `json:"skip_back_seconds,omitempty" example:"15"`
+	Theme              *string                `json:"theme,omitempty" example:"dark"` // "system", "light" or "dark"
+	Languages          *[]string              `json:"languages,omitempty" example:"en,es"`
+	ExcludedCategories *[]string              `json:"excluded_categories,omitempty" example:"News,Politics"`
+	Extras             map[string]interface{} `json:"extras,omitempty"`
+}
+
+// NotificationsReadRequest selects notifications to mark read; empty IDs marks all
+type NotificationsReadRequest struct {
+	IDs []uint `json:"ids,omitempty" example:"12,13"`
+}
+
+// SavedSearchRequest creates a saved search. The search fields match SearchRequest.
+type SavedSearchRequest struct {
+	Name          string   `json:"name,omitempty" example:"New Rust shows"` // Defaults to the query
+	Query         string   `json:"query" binding:"required" example:"rust programming"`
+	Limit         int      `json:"limit,omitempty" example:"20"` // Podcast Index results checked per run
+	FullText      bool     `json:"fullText,omitempty" example:"false"`
+	Val           string   `json:"val,omitempty" example:"any"`
+	ApOnly        bool     `json:"apOnly,omitempty" example:"false"`
+	Clean         bool     `json:"clean,omitempty" example:"false"`
+	Lang          string   `json:"lang,omitempty" example:"en,es"`                  // Comma-separated language codes
+	NotCategories []string `json:"notCategories,omitempty" example:"News,Politics"` // Category names/IDs to leave out
+	// Minutes between runs; defaults to saved_searches.default_interval
+	IntervalMinutes int    `json:"interval_minutes,omitempty" example:"1440"`
+	Notify          bool   `json:"notify,omitempty" example:"true"`                                // Post a notification when a run finds new podcasts
+	WebhookURL      string `json:"webhook_url,omitempty" example:"https://example.com/hooks/rust"` // Also POST a signed saved_search.results event here
+}
+
+// SubscriptionRequest subscribes the user to a podcast
+type SubscriptionRequest struct {
+	PodcastID int64    `json:"podcast_id" binding:"required,min=1" example:"920666"` // Podcast Index feed ID
+	Folder    string   `json:"folder,omitempty" example:"news"`
+	Tags      []string `json:"tags,omitempty" example:"daily,politics"` // Stored lowercase
+}
+
+// SubscriptionUpdateRequest changes a subscription's folder or tags; omitted fields are left alone
+type SubscriptionUpdateRequest struct {
+	Folder *string   `json:"folder,omitempty" example:"comedy"` // Empty string removes it from its folder
+	Tags   *[]string `json:"tags,omitempty" example:"weekly"`   // Empty list clears the tags
+}
+
+// InboxReadRequest selects inbox episodes by Podcast Index episode ID
+type InboxReadRequest struct {
+	EpisodeIDs []int64 `json:"episode_ids,omitempty" example:"1001,1002"`
+}
+
+// AutoplayRulesRequest replaces the user's autoplay rules; an empty list turns
+// autoplay off
+type AutoplayRulesRequest struct {
+	Rules []autoplay.Rule `json:"rules" binding:"required"`
 }
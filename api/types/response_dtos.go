@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // WaveformData represents waveform data in API responses
 // This is the consolidated version replacing duplicates in api/episodes and api/waveform packages
 type WaveformData struct {
@@ -19,25 +21,61 @@ type TranscriptionData struct {
 	Text      string  `json:"text" example:"This is the transcription..."` // Full transcription text
 	Language  string  `json:"language" example:"en"`                       // Detected or specified language
 	Duration  float64 `json:"duration" example:"300.5"`                    // Duration in seconds
-	Model     string  `json:"model" example:"ggml-base.en.bin"`            // Model used for transcription
+	Model     string  `json:"model" example:"base.en"`                     // Whisper model used for transcription
 	Source    string  `json:"source,omitempty"`                            // "fetched" or "generated" - optional for some responses
 	Cached    bool    `json:"cached,omitempty"`                            // Whether data is cached - optional for some responses
 }
 
+// SummaryTopicData represents a chapter-style topic in a summary response
+type SummaryTopicData struct {
+	Title       string `json:"title" example:"Introduction"` // Short topic title
+	Description string `json:"description,omitempty"`        // One-sentence description
+}
+
+// SummaryData represents an episode summary in API responses
+type SummaryData struct {
+	EpisodeID        int64              `json:"episode_id"`                             // Podcast Index Episode ID
+	Summary          string             `json:"summary" example:"The hosts discuss..."` // Short prose summary
+	Topics           []SummaryTopicData `json:"topics"`                                 // Topics in the order discussed
+	Model            string             `json:"model" example:"gpt-4o-mini"`            // Model used for summarization
+	PromptTokens     int                `json:"prompt_tokens"`                          // Tokens sent to the model
+	CompletionTokens int                `json:"completion_tokens"`                      // Tokens generated by the model
+	Truncated        bool               `json:"truncated"`                              // True if the transcription was cut to fit the input budget
+	GeneratedAt      time.Time          `json:"generated_at"`                           // When the summary was generated
+}
+
 // JobStatusResponse represents job status information
 type JobStatusResponse struct {
-	EpisodeID    int64   `json:"episode_id"`              // Episode ID
-	JobID        uint    `json:"job_id,omitempty"`        // Job ID (optional)
-	Status       string  `json:"status"`                  // Status: pending, processing, completed, failed, permanently_failed, not_found
-	Progress     int     `json:"progress"`                // Progress 0-100
-	Message      string  `json:"message"`                 // Human-readable message
-	Error        string  `json:"error,omitempty"`         // Error message (only for failed status)
-	ErrorType    string  `json:"error_type,omitempty"`    // Error type: "download", "processing", "system" (only for failed jobs)
-	ErrorCode    string  `json:"error_code,omitempty"`    // Specific error code like "403", "timeout", "corrupt_file" (only for failed jobs)
-	ErrorDetails string  `json:"error_details,omitempty"` // Technical error details for debugging (only for failed jobs)
-	RetryCount   int     `json:"retry_count,omitempty"`   // Number of retries attempted (only for failed jobs)
-	MaxRetries   int     `json:"max_retries,omitempty"`   // Maximum retry attempts (only for failed jobs)
-	RetryAfter   float64 `json:"retry_after,omitempty"`   // Seconds until retry (only for failed jobs)
-	Retried      bool    `json:"retried,omitempty"`       // True if this was a manual retry (only when applicable)
-	Hint         string  `json:"hint,omitempty"`          // Helpful hint for the client (e.g., "Use retry=true parameter")
+	EpisodeID    int64     `json:"episode_id"`              // Episode ID
+	JobID        uint      `json:"job_id,omitempty"`        // Job ID (optional)
+	Status       string    `json:"status"`                  // Status: pending, processing, completed, failed, permanently_failed, not_found
+	Progress     int       `json:"progress"`                // Progress 0-100
+	Message      string    `json:"message"`                 // Human-readable message
+	Error        string    `json:"error,omitempty"`         // Error message (only for failed status)
+	ErrorType    string    `json:"error_type,omitempty"`    // Error type: "download", "processing", "system", "not_found", "resource_limit", "dependency", "configuration" (only for failed jobs)
+	ErrorCode    string    `json:"error_code,omitempty"`    // Specific error code like "403", "timeout", "corrupt_file" (only for failed jobs)
+	ErrorDetails string    `json:"error_details,omitempty"` // Technical error details for debugging (only for failed jobs)
+	RetryCount   int       `json:"retry_count,omitempty"`   // Number of retries attempted (only for failed jobs)
+	MaxRetries   int       `json:"max_retries,omitempty"`   // Maximum retry attempts (only for failed jobs)
+	RetryAfter   float64   `json:"retry_after,omitempty"`   // Seconds until retry (only for failed jobs)
+	Retried      bool      `json:"retried,omitempty"`       // True if this was a manual retry (only when applicable)
+	Hint         string    `json:"hint,omitempty"`          // Helpful hint for the client (e.g., "Use retry=true parameter")
+	JobError     *JobError `json:"job_error,omitempty"`     // Why the job failed (only for failed jobs)
+}
+
+// PublicJob is the client-facing view of a background job. It leaves out the
+// payload, result, worker and error text, which can carry URLs and internals.
+type PublicJob struct {
+	ID          uint       `json:"id" example:"1234"`
+	Type        string     `json:"type" example:"waveform_generation"`
+	Status      string     `json:"status" example:"processing"` // pending, processing, completed, failed, permanently_failed, cancelled
+	Progress    int        `json:"progress" example:"45"`       // 0-100
+	RetryCount  int        `json:"retry_count"`
+	MaxRetries  int        `json:"max_retries" example:"3"`
+	ErrorType   string     `json:"error_type,omitempty" example:"download"` // Failure category, only for failed jobs
+	JobError    *JobError  `json:"job_error,omitempty"`                     // Why the job failed, only for failed jobs
+	DependsOn   []uint     `json:"depends_on,omitempty"`                    // Jobs that must complete before this one starts
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
 }
@@ -1,5 +1,12 @@
 package types
 
+import (
+	"time"
+
+	"github.com/killallgit/player-api/internal/services/autoplay"
+	"github.com/killallgit/player-api/internal/services/imports"
+)
+
 // Status constants for API responses
 const (
 	StatusOK         = "ok"
@@ -7,6 +14,7 @@ const (
 	StatusProcessing = "processing"
 	StatusFailed     = "failed"
 	StatusQueued     = "queued"
+	StatusPreview    = "preview"
 )
 
 // BaseResponse contains fields common to all API responses
@@ -48,6 +56,27 @@ type PodcastsResponse struct {
 	Offset   int       `json:"offset,omitempty"`
 }
 
+// CategoryPodcastsResponse for browsing podcasts within a category
+type CategoryPodcastsResponse struct {
+	BaseResponse
+	Category Category  `json:"category"`
+	Podcasts []Podcast `json:"podcasts"`
+	Count    int       `json:"count"`           // Number of results in this response
+	Total    int       `json:"total,omitempty"` // Total locally known podcasts in the category
+	Offset   int       `json:"offset,omitempty"`
+	Source   string    `json:"source"` // "local" or "remote"
+}
+
+// DiscoverResponse for the combined home screen digest. Sections that failed
+// are omitted and listed in Errors keyed by section name.
+type DiscoverResponse struct {
+	BaseResponse
+	Trending []Podcast         `json:"trending,omitempty"`
+	Recent   []Episode         `json:"recent,omitempty"`
+	Random   []Episode         `json:"random,omitempty"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
 // EpisodesResponse for episode lists
 type EpisodesResponse struct {
 	BaseResponse
@@ -57,6 +86,77 @@ type EpisodesResponse struct {
 	Offset   int       `json:"offset,omitempty"`
 }
 
+// EpisodeChangesResponse for incremental episode sync
+type EpisodeChangesResponse struct {
+	BaseResponse
+	Created    []Episode `json:"created"`
+	Updated    []Episode `json:"updated"`
+	Deleted    []int64   `json:"deleted"`    // Podcast Index IDs of episodes to drop
+	NextCursor string    `json:"nextCursor"` // Pass as 'since' on the next call
+	HasMore    bool      `json:"hasMore"`    // Call again straight away for the rest
+}
+
+// EpisodePeopleResponse for people credited on an episode
+type EpisodePeopleResponse struct {
+	BaseResponse
+	EpisodeID int64           `json:"episodeId"`
+	People    []EpisodeCredit `json:"people"`
+	Count     int             `json:"count"`
+}
+
+// PersonEpisodesResponse for episodes a person is credited on
+type PersonEpisodesResponse struct {
+	BaseResponse
+	Person   Person    `json:"person"`
+	Episodes []Episode `json:"episodes"`
+	Count    int       `json:"count"`           // Number of results in this response
+	Total    int       `json:"total,omitempty"` // Total locally known episodes for the person
+	Offset   int       `json:"offset,omitempty"`
+}
+
+// WhisperModelsResponse lists the whisper models transcription can use
+type WhisperModelsResponse struct {
+	BaseResponse
+	Models       []WhisperModel `json:"models"`
+	Default      string         `json:"default" example:"base.en"` // Used when neither the request nor the podcast picks a model
+	AutoDownload bool           `json:"auto_download"`             // Whether models that aren't installed are fetched on first use
+}
+
+// WhisperModel is one whisper model and whether it is installed
+type WhisperModel struct {
+	Name      string `json:"name" example:"small.en"`
+	Installed bool   `json:"installed"`
+	Default   bool   `json:"default"`
+	SizeBytes int64  `json:"size_bytes,omitempty"` // Size on disk, when installed
+}
+
+// TranscriptSearchResponse for searches across stored transcripts
+type TranscriptSearchResponse struct {
+	BaseResponse
+	Results []TranscriptSearchResult `json:"results"`
+	Query   string                   `json:"query"`
+	Count   int                      `json:"count"` // Number of episodes in this response
+	Offset  int                      `json:"offset,omitempty"`
+}
+
+// TranscriptSearchResult is an episode whose transcript matched, with its best matching segments
+type TranscriptSearchResult struct {
+	EpisodeID int64                     `json:"episodeId"`
+	Title     string                    `json:"title,omitempty"`
+	FeedID    int64                     `json:"feedId,omitempty"`
+	FeedTitle string                    `json:"feedTitle,omitempty"`
+	Matches   int                       `json:"matches"` // Number of matching transcript segments
+	Segments  []TranscriptSearchSegment `json:"segments"`
+}
+
+// TranscriptSearchSegment is a matching span of a transcript
+type TranscriptSearchSegment struct {
+	Start   *float64 `json:"start,omitempty"` // Seconds into the episode; absent for untimed transcripts
+	End     *float64 `json:"end,omitempty"`
+	Text    string   `json:"text"`
+	Snippet string   `json:"snippet"` // Excerpt with matched words wrapped in <mark></mark>
+}
+
 // SingleEpisodeResponse for getting a single episode
 type SingleEpisodeResponse struct {
 	BaseResponse
@@ -67,6 +167,8 @@ type SingleEpisodeResponse struct {
 type WaveformResponse struct {
 	BaseResponse
 	Waveform *Waveform `json:"waveform"`
+	JobID    uint      `json:"job_id,omitempty"`    // Generation job to poll at /api/v1/jobs/{id} while queued or processing
+	JobError *JobError `json:"job_error,omitempty"` // Why generation failed, when it did
 }
 
 // TranscriptionResponse for transcription data
@@ -89,11 +191,260 @@ type CategoriesResponse struct {
 	Count      int        `json:"count"`
 }
 
+// DataExportResponse describes a personal data export job. DownloadURL is only
+// set once the archive is ready and expires at ExpiresAt.
+type DataExportResponse struct {
+	BaseResponse
+	JobID       uint       `json:"job_id"`
+	JobStatus   string     `json:"job_status"` // pending, processing, completed, failed, permanently_failed
+	Progress    int        `json:"progress"`
+	SizeBytes   int64      `json:"size_bytes,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// LibraryImportResponse describes an import of another app's subscriptions and
+// listening history. Result is set once the job has completed.
+type LibraryImportResponse struct {
+	BaseResponse
+	JobID     uint            `json:"job_id"`
+	JobStatus string          `json:"job_status"` // pending, processing, completed, failed, permanently_failed
+	Progress  int             `json:"progress"`
+	Format    string          `json:"format,omitempty"` // opml, csv or json
+	Feeds     int             `json:"feeds"`            // Podcasts found in the file
+	Plays     int             `json:"plays"`            // Episodes with progress found in the file
+	Result    *imports.Result `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// AccountDeletionResponse describes a user's account deletion request
+type AccountDeletionResponse struct {
+	BaseResponse
+	DeletionStatus string     `json:"deletion_status"` // scheduled, queued, completed, cancelled
+	RequestedAt    time.Time  `json:"requested_at"`
+	ScheduledFor   time.Time  `json:"scheduled_for"` // Deletion runs after this time unless cancelled
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// PreferencesResponse for the authenticated user's settings
+type PreferencesResponse struct {
+	BaseResponse
+	PlaybackSpeed      float64                `json:"playback_speed"`
+	SkipForwardSeconds int                    `json:"skip_forward_seconds"`
+	SkipBackSeconds    int                    `json:"skip_back_seconds"`
+	Theme              string                 `json:"theme"`
+	Languages          []string               `json:"languages"`           // Applied to search and trending
+	ExcludedCategories []string               `json:"excluded_categories"` // Hidden from search and trending
+	Extras             map[string]interface{} `json:"extras"`
+	UpdatedAt          *time.Time             `json:"updated_at,omitempty"` // Unset until first saved
+}
+
+// Notification is an entry in the user's notification feed
+type Notification struct {
+	ID        uint                   `json:"id"`
+	Type      string                 `json:"type"` // new_episodes, job_completed, export_ready, saved_search
+	Title     string                 `json:"title"`
+	Body      string                 `json:"body,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	ReadAt    *time.Time             `json:"read_at,omitempty"`
+}
+
+// NotificationsResponse is a page of the user's notification feed, newest first
+type NotificationsResponse struct {
+	BaseResponse
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int64          `json:"unread_count"`
+	NextBefore    uint           `json:"next_before,omitempty"` // Pass as 'before' to fetch the next page
+}
+
+// NotificationsReadResponse reports the result of marking notifications read
+type NotificationsReadResponse struct {
+	BaseResponse
+	Updated     int64 `json:"updated"`
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// SavedSearch is a Podcast Index search re-run on a schedule
+type SavedSearch struct {
+	ID              uint       `json:"id"`
+	Name            string     `json:"name"`
+	Query           string     `json:"query"`
+	Limit           int        `json:"limit"`
+	FullText        bool       `json:"fullText"`
+	Val             string     `json:"val,omitempty"`
+	ApOnly          bool       `json:"apOnly"`
+	Clean           bool       `json:"clean"`
+	Lang            string     `json:"lang,omitempty"`
+	NotCategories   []string   `json:"notCategories"`
+	IntervalMinutes int        `json:"interval_minutes"`
+	Notify          bool       `json:"notify"`
+	WebhookURL      string     `json:"webhook_url,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt       time.Time  `json:"next_run_at"`
+	LastError       string     `json:"last_error,omitempty"` // Why the last run failed; cleared by the next success
+}
+
+// SavedSearchResponse is a single saved search
+type SavedSearchResponse struct {
+	BaseResponse
+	SavedSearch SavedSearch `json:"saved_search"`
+}
+
+// SavedSearchesResponse lists the user's saved searches, oldest first
+type SavedSearchesResponse struct {
+	BaseResponse
+	SavedSearches []SavedSearch `json:"saved_searches"`
+}
+
+// SavedSearchResult is a podcast a saved search found
+type SavedSearchResult struct {
+	PodcastID int64     `json:"podcast_id"` // Podcast Index feed ID
+	Title     string    `json:"title"`
+	Author    string    `json:"author,omitempty"`
+	Image     string    `json:"image,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	FoundAt   time.Time `json:"found_at"`
+	Initial   bool      `json:"initial"` // Found by the first run, when the search was saved
+}
+
+// SavedSearchResultsResponse lists podcasts a saved search has found, newest first
+type SavedSearchResultsResponse struct {
+	BaseResponse
+	Results []SavedSearchResult `json:"results"`
+}
+
+// LiveItem is a live stream a podcast has announced in its feed
+type LiveItem struct {
+	ID            int64      `json:"id" example:"4481290"` // Podcast Index live item ID
+	GUID          string     `json:"guid,omitempty"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description,omitempty"`
+	Status        string     `json:"status" enums:"pending,live"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       *time.Time `json:"end_time,omitempty"`
+	EnclosureURL  string     `json:"enclosure_url,omitempty"` // The stream, e.g. an HLS playlist or Icecast mount
+	EnclosureType string     `json:"enclosure_type,omitempty"`
+	ContentLink   string     `json:"content_link,omitempty"` // Page the stream can also be watched on
+	Image         string     `json:"image,omitempty"`
+}
+
+// LiveItemsResponse lists a podcast's live and upcoming streams
+type LiveItemsResponse struct {
+	BaseResponse
+	Live     []LiveItem `json:"live"`     // Broadcasting now
+	Upcoming []LiveItem `json:"upcoming"` // Scheduled, soonest first
+}
+
+// Subscription is a podcast the user subscribes to, with how they organized it
+type Subscription struct {
+	PodcastID    int64     `json:"podcast_id"` // Podcast Index feed ID
+	Podcast      *Podcast  `json:"podcast"`
+	Folder       string    `json:"folder"` // Empty when not in a folder
+	Tags         []string  `json:"tags"`
+	SubscribedAt time.Time `json:"subscribed_at"`
+}
+
+// SubscriptionResponse is a single subscription
+type SubscriptionResponse struct {
+	BaseResponse
+	Subscription Subscription `json:"subscription"`
+}
+
+// SubscriptionsResponse lists the user's subscriptions, oldest first
+type SubscriptionsResponse struct {
+	BaseResponse
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// SubscriptionFolder is a folder and how many subscriptions are in it
+type SubscriptionFolder struct {
+	Folder string `json:"folder"` // Empty for subscriptions not in a folder
+	Count  int64  `json:"count"`
+}
+
+// SubscriptionFoldersResponse lists the user's subscription folders by name
+type SubscriptionFoldersResponse struct {
+	BaseResponse
+	Folders []SubscriptionFolder `json:"folders"`
+}
+
+// InboxEpisode is an episode in the user's inbox with its read state
+type InboxEpisode struct {
+	Episode
+	Read   bool       `json:"read"`
+	ReadAt *time.Time `json:"readAt,omitempty"`
+}
+
+// InboxResponse is a page of the user's inbox, newest first
+type InboxResponse struct {
+	BaseResponse
+	Episodes    []InboxEpisode `json:"episodes"`
+	Count       int            `json:"count"` // Number of results in this response
+	Total       int64          `json:"total"` // Episodes matching the query
+	Offset      int            `json:"offset"`
+	UnreadCount int64          `json:"unread_count"` // Unread episodes in the whole inbox
+}
+
+// AutoplayRulesResponse lists the user's autoplay rules in evaluation order
+type AutoplayRulesResponse struct {
+	BaseResponse
+	Rules   []autoplay.Rule `json:"rules"`
+	Default bool            `json:"default"` // The user hasn't set rules of their own
+}
+
+// QueueNextResponse is the episode the user's autoplay rules pick. Episode is
+// null when no rule found one or a stop rule applied.
+type QueueNextResponse struct {
+	BaseResponse
+	Episode   *Episode       `json:"episode"`
+	Rule      *autoplay.Rule `json:"rule,omitempty"`       // The rule that decided
+	RuleIndex *int           `json:"rule_index,omitempty"` // Its position in the rules
+}
+
+// InboxReadResponse reports how many inbox episodes changed read state
+type InboxReadResponse struct {
+	BaseResponse
+	Updated     int64 `json:"updated"`
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// CalendarEntry is a release on the user's calendar
+type CalendarEntry struct {
+	Date         time.Time `json:"date"`
+	PodcastID    int64     `json:"podcast_id"` // Podcast Index feed ID
+	PodcastTitle string    `json:"podcast_title"`
+	Image        string    `json:"image,omitempty"`
+	Expected     bool      `json:"expected"`          // Predicted from the show's cadence rather than released
+	Episode      *Episode  `json:"episode,omitempty"` // The released episode
+}
+
+// PodcastCadence is how often a subscribed podcast releases episodes
+type PodcastCadence struct {
+	PodcastID       int64     `json:"podcast_id"` // Podcast Index feed ID
+	PodcastTitle    string    `json:"podcast_title"`
+	IntervalSeconds int64     `json:"interval_seconds"` // Median time between releases
+	LastRelease     time.Time `json:"last_release"`
+}
+
+// CalendarResponse lists released and expected episodes in a range, by date
+type CalendarResponse struct {
+	BaseResponse
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"` // Exclusive
+	Entries  []CalendarEntry  `json:"entries"`
+	Cadences []PodcastCadence `json:"cadences"` // Podcasts regular enough to predict
+}
+
 // ErrorResponse for detailed error information
 type ErrorResponse struct {
 	Status  string      `json:"status"`
+	Code    ErrorCode   `json:"code" example:"EPISODE_NOT_FOUND"` // Stable machine-readable code; branch on this, not the message
 	Message string      `json:"message"`
-	Error   string      `json:"error,omitempty"`   // Error code/type
+	Error   string      `json:"error,omitempty"`   // Deprecated: repeats the message for older clients
 	Details interface{} `json:"details,omitempty"` // Additional error details
 }
 
@@ -97,6 +97,7 @@ func FromPodcastIndexEpisode(e *podcastindex.Episode) *Episode {
 		Description:   e.Description,
 		Link:          e.Link,
 		AudioURL:      e.EnclosureURL,
+		MediaType:     models.MediaTypeOf(e.EnclosureType),
 		Duration:      e.Duration,
 		PublishedAt:   e.DatePublished,
 		Image:         image,
@@ -144,6 +145,7 @@ func FromServiceEpisode(e *episodes.PodcastIndexEpisode) *Episode {
 		Description:   e.Description,
 		Link:          e.Link,
 		AudioURL:      e.EnclosureURL,
+		MediaType:     models.MediaTypeOf(e.EnclosureType),
 		Duration:      duration,
 		PublishedAt:   e.DatePublished,
 		Image:         e.Image,
@@ -151,9 +153,34 @@ func FromServiceEpisode(e *episodes.PodcastIndexEpisode) *Episode {
 		ChaptersURL:   e.ChaptersURL,
 		Episode:       episode,
 		Season:        season,
+
+		AlternateEnclosures: fromServiceAlternates(e.AlternateEnclosures),
 	}
 }
 
+// fromServiceAlternates transforms alternate enclosures in Podcast Index
+// format, keeping each one's first source
+func fromServiceAlternates(alternates []episodes.AlternateEnclosure) []AlternateEnclosure {
+	var result []AlternateEnclosure
+	for _, alt := range alternates {
+		if len(alt.Sources) == 0 {
+			continue
+		}
+		result = append(result, AlternateEnclosure{
+			URL:     alt.Sources[0].URI,
+			Type:    alt.Type,
+			Length:  alt.Length,
+			Bitrate: int(alt.Bitrate),
+			Height:  alt.Height,
+			Lang:    alt.Lang,
+			Title:   alt.Title,
+			Codecs:  alt.Codecs,
+			Default: alt.Default,
+		})
+	}
+	return result
+}
+
 // FromServiceEpisodeList transforms a list of internal service episodes
 func FromServiceEpisodeList(episodes []episodes.PodcastIndexEpisode) []Episode {
 	result := make([]Episode, 0, len(episodes))
@@ -199,6 +226,7 @@ func FromModelEpisode(e *models.Episode) *Episode {
 		Description:   e.Description,
 		Link:          e.Link,
 		AudioURL:      e.AudioURL,
+		MediaType:     e.MediaType(),
 		Duration:      duration,
 		PublishedAt:   e.PublishedAt.Unix(),
 		Image:         image,
@@ -206,9 +234,20 @@ func FromModelEpisode(e *models.Episode) *Episode {
 		ChaptersURL:   "", // Not stored in models.Episode yet
 		Episode:       episode,
 		Season:        season,
+
+		AlternateEnclosures: fromModelAlternates(e.AlternateEnclosures),
 	}
 }
 
+// fromModelAlternates transforms stored alternate enclosures
+func fromModelAlternates(alternates []models.AlternateEnclosure) []AlternateEnclosure {
+	var result []AlternateEnclosure
+	for _, alt := range alternates {
+		result = append(result, AlternateEnclosure(alt))
+	}
+	return result
+}
+
 // FromModelEpisodeList transforms a list of database model episodes
 func FromModelEpisodeList(episodes []models.Episode) []Episode {
 	result := make([]Episode, 0, len(episodes))
@@ -220,6 +259,21 @@ func FromModelEpisodeList(episodes []models.Episode) []Episode {
 	return result
 }
 
+// WithEpisodeStatuses sets Status on each episode that has an entry in statuses
+func WithEpisodeStatuses(episodes []Episode, statuses map[int64]models.EpisodeStatus) []Episode {
+	for i := range episodes {
+		if status, ok := statuses[episodes[i].ID]; ok {
+			episodes[i].Status = &EpisodeStatus{
+				HasWaveform:       status.HasWaveform,
+				HasTranscript:     status.HasTranscript,
+				ClipCount:         status.ClipCount,
+				ApprovedClipCount: status.ApprovedClipCount,
+			}
+		}
+	}
+	return episodes
+}
+
 // FromModelPodcast transforms a database model podcast to our simplified Podcast type
 func FromModelPodcast(p *models.Podcast) *Podcast {
 	if p == nil {
@@ -276,3 +330,47 @@ func FromModelPodcastList(podcasts []models.Podcast) []Podcast {
 	}
 	return result
 }
+
+// FromModelPerson transforms a database model person to our simplified Person type
+func FromModelPerson(p *models.Person) Person {
+	return Person{
+		ID:   p.ID,
+		Name: p.Name,
+		Href: p.Href,
+		Img:  p.Img,
+	}
+}
+
+// FromModelEpisodeCredits transforms episode-person links to API credits
+func FromModelEpisodeCredits(credits []models.EpisodePerson) []EpisodeCredit {
+	result := make([]EpisodeCredit, 0, len(credits))
+	for i := range credits {
+		result = append(result, EpisodeCredit{
+			Person: FromModelPerson(&credits[i].Person),
+			Role:   credits[i].Role,
+			Group:  credits[i].Group,
+		})
+	}
+	return result
+}
+
+// NewPublicJob converts a job to its client-facing view
+func NewPublicJob(job *models.Job) PublicJob {
+	public := PublicJob{
+		ID:          job.ID,
+		Type:        string(job.Type),
+		Status:      string(job.Status),
+		Progress:    job.Progress,
+		RetryCount:  job.RetryCount,
+		MaxRetries:  job.MaxRetries,
+		DependsOn:   job.DependsOn,
+		CreatedAt:   job.CreatedAt,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+	}
+	if job.Status == models.JobStatusFailed || job.Status == models.JobStatusPermanentlyFailed {
+		public.ErrorType = job.ErrorType
+		public.JobError = NewJobError(job)
+	}
+	return public
+}
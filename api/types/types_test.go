@@ -1,9 +1,21 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/jobs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDependencies(t *testing.T) {
@@ -17,5 +29,148 @@ func TestDependencies(t *testing.T) {
 	assert.Nil(t, deps.EpisodeTransformer)
 }
 
+func TestParseWait(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, true},
+		{"?wait=30s", 30 * time.Second, true},
+		{"?wait=10", 10 * time.Second, true},
+		{"?wait=5m", MaxWait, true},
+		{"?wait=soon", 0, false},
+		{"?wait=-1s", 0, false},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+
+		got, ok := ParseWait(c)
+		assert.Equal(t, tt.wantOK, ok, tt.query)
+		assert.Equal(t, tt.want, got, tt.query)
+		if !ok {
+			assert.Equal(t, http.StatusBadRequest, w.Code, tt.query)
+		}
+	}
+}
+
+func TestParseWait_OutlastsWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		wait, ok := ParseWait(c)
+		if !ok {
+			return
+		}
+		time.Sleep(wait)
+		c.String(http.StatusOK, "done")
+	})
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	// The wait runs past the server's write timeout
+	resp, err := http.Get(server.URL + "/?wait=300ms")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+}
+
+func TestCodeForStatus(t *testing.T) {
+	assert.Equal(t, CodeInvalidRequest, CodeForStatus(http.StatusBadRequest))
+	assert.Equal(t, CodeRateLimited, CodeForStatus(http.StatusTooManyRequests))
+	assert.Equal(t, CodeUpstreamTimeout, CodeForStatus(http.StatusGatewayTimeout))
+	assert.Equal(t, CodeInternal, CodeForStatus(http.StatusTeapot))
+}
+
+func TestSendServiceError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		err    error
+		status int
+		code   ErrorCode
+	}{
+		{fmt.Errorf("loading episode 42: %w", episodes.ErrEpisodeNotFound), http.StatusNotFound, CodeEpisodeNotFound},
+		{clips.ErrClipNotFound, http.StatusNotFound, CodeClipNotFound},
+		{fmt.Errorf("%w: label music allows 10", clips.ErrLabelQuotaExceeded), http.StatusConflict, CodeLabelQuotaExceeded},
+		{fmt.Errorf("disk full"), http.StatusInternalServerError, CodeInternal},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		SendServiceError(c, tt.err, "Something failed")
+		assert.Equal(t, tt.status, w.Code, tt.err.Error())
+
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, StatusError, response.Status)
+		assert.Equal(t, tt.code, response.Code, tt.err.Error())
+		assert.Equal(t, response.Message, response.Error)
+	}
+}
+
+func TestSendServiceError_QueueFullSetsRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	err := fmt.Errorf("enqueueing: %w", &jobs.QueueFullError{
+		Type: models.JobTypeWaveformGeneration, Depth: 10, Limit: 10, RetryAfter: 1500 * time.Millisecond,
+	})
+	SendServiceError(c, err, "Something failed")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, CodeJobQueueFull, response.Code)
+}
+
 // NotFoundHandler is in api package, not types package
 // So we'll just test what we have in this package
+
+func TestNewJobError(t *testing.T) {
+	job := &models.Job{Status: models.JobStatusProcessing, ErrorType: "download", ErrorCode: "403"}
+	assert.Nil(t, NewJobError(job), "only failed jobs carry an error")
+
+	job.Status = models.JobStatusFailed
+	assert.Equal(t, &JobError{
+		Category:  "download",
+		Code:      "403",
+		Retryable: true,
+		Message:   "The podcast's host is blocking downloads",
+	}, NewJobError(job))
+
+	job.Status = models.JobStatusPermanentlyFailed
+	job.ErrorCode = "http_500"
+	jobErr := NewJobError(job)
+	assert.False(t, jobErr.Retryable)
+	assert.Equal(t, "The episode's audio could not be downloaded", jobErr.Message, "unknown codes fall back to the category's message")
+
+	assert.Equal(t, "unknown", NewJobError(&models.Job{Status: models.JobStatusFailed}).Category)
+}
+
+func TestNewClipError(t *testing.T) {
+	assert.Nil(t, NewClipError(&models.Clip{Status: "ready"}))
+
+	jobErr := NewClipError(&models.Clip{Status: "failed", ErrorType: "processing", ErrorCode: "corrupt_file"})
+	assert.Equal(t, "processing", jobErr.Category)
+	assert.True(t, jobErr.Retryable)
+
+	jobErr = NewClipError(&models.Clip{Status: "failed", ErrorType: "not_found"})
+	assert.False(t, jobErr.Retryable)
+
+	jobErr = NewClipError(&models.Clip{Status: "failed"})
+	assert.Equal(t, "unknown", jobErr.Category)
+	assert.False(t, jobErr.Retryable, "unclassified failures are not retried")
+}
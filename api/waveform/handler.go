@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/killallgit/player-api/api/types"
 	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
 	"github.com/killallgit/player-api/internal/services/waveforms"
 )
 
@@ -22,15 +23,20 @@ import (
 // @Description  visualizations. If waveform doesn't exist, it will be automatically queued for generation and the
 // @Description  response will include status:"pending" or "processing". Generation typically takes 10-60 seconds
 // @Description  depending on episode duration. Poll this endpoint until status:"ready" to get the final data.
+// @Description  While processing, 'data' holds the peaks computed so far (left to right) and 'progress' the
+// @Description  percent of audio decoded, so the waveform can be drawn as it grows.
+// @Description  Instead of polling, pass wait (e.g. 30s, at most 45s) to hold the request open until generation
+// @Description  finishes; if it is still running when the wait elapses the usual 202 with progress is returned.
 // @Tags         waveform
 // @Accept       json
 // @Produce      json
 // @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        wait query string false "Wait up to this long for generation to finish, e.g. 30s (max 45s)"
 // @Success      200 {object} types.WaveformResponse "Waveform ready with amplitude data array (status:ready)"
 // @Success      202 {object} types.WaveformResponse "Generation in progress (status:processing or pending)"
-// @Failure      400 {object} types.ErrorResponse "Invalid episode ID format"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID format or wait"
 // @Failure      500 {object} types.ErrorResponse "Waveform service error or database failure"
-// @Failure      503 {object} types.WaveformResponse "Generation failed, automatic retry scheduled (status:failed)"
+// @Failure      503 {object} types.WaveformResponse "Generation failed, automatic retry scheduled (status:failed), or the job queue is full (JOB_QUEUE_FULL, see Retry-After)"
 // @Router       /api/v1/episodes/{id}/waveform [get]
 func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -41,6 +47,7 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil || podcastIndexID < 0 {
 			c.JSON(http.StatusBadRequest, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
 				Message: "Invalid Podcast Index Episode ID",
 			})
 			return
@@ -50,6 +57,7 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 		if deps.WaveformService == nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Waveform service not available",
 			})
 			return
@@ -59,13 +67,22 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 		if deps.EpisodeService == nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Episode service not available",
 			})
 			return
 		}
 
-		// Create context with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		wait, ok := types.ParseWait(c)
+		if !ok {
+			return
+		}
+		if wait > 0 {
+			awaitWaveform(c.Request.Context(), deps, podcastIndexID, wait)
+		}
+
+		// Derived from the request so a queued job joins its trace
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 		defer cancel()
 
 		// Debug logging
@@ -76,27 +93,28 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil {
 			if errors.Is(err, waveforms.ErrWaveformNotFound) {
 				// Check if there's already a job for this episode (using Podcast Index ID)
+				var queuedJobID uint
+				var previousError *types.JobError // Why the last attempt failed, when a new job replaces it
 				if deps.JobService != nil {
 					existingJob, jobErr := deps.JobService.GetJobForWaveform(ctx, podcastIndexID)
 					if jobErr == nil && existingJob != nil {
 						// Job already exists, return status based on job state
 						switch existingJob.Status {
 						case models.JobStatusPending, models.JobStatusProcessing:
+							types.SetJobLocation(c, existingJob.ID)
 							c.JSON(http.StatusAccepted, types.WaveformResponse{
 								BaseResponse: types.BaseResponse{
 									Status:  types.StatusProcessing,
 									Message: "Waveform generation in progress",
 								},
-								Waveform: &types.Waveform{
-									ID:        strconv.FormatInt(podcastIndexID, 10),
-									EpisodeID: podcastIndexID,
-									Status:    types.StatusProcessing,
-								},
+								Waveform: partialWaveform(ctx, deps, podcastIndexID),
+								JobID:    existingJob.ID,
 							})
 							return
 						case models.JobStatusFailed:
 							// Failed job exists - worker will retry it automatically
 							// Don't create a new job, just report the current status
+							types.SetJobLocation(c, existingJob.ID)
 							c.JSON(http.StatusAccepted, types.WaveformResponse{
 								BaseResponse: types.BaseResponse{
 									Status: types.StatusProcessing,
@@ -108,6 +126,8 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 									EpisodeID: podcastIndexID,
 									Status:    types.StatusProcessing,
 								},
+								JobID:    existingJob.ID,
+								JobError: types.NewJobError(existingJob),
 							})
 							return
 						case models.JobStatusCompleted:
@@ -120,6 +140,7 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 							}
 							c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 								Status:  types.StatusError,
+								Code:    types.CodeInternal,
 								Message: "Waveform processing completed but data not found",
 							})
 							return
@@ -127,6 +148,7 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 							// Job permanently failed - allow creating a new job after cleanup
 							log.Printf("Previous waveform job %d permanently failed for episode %d, will clean up and retry",
 								existingJob.ID, podcastIndexID)
+							previousError = types.NewJobError(existingJob)
 
 							// Clean up the permanently failed job by deleting it
 							if deps.JobService != nil {
@@ -143,6 +165,7 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 							log.Printf("Unknown job status %s for job %d", existingJob.Status, existingJob.ID)
 							c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 								Status:  types.StatusError,
+								Code:    types.CodeInternal,
 								Message: "Unknown job status",
 							})
 							return
@@ -156,9 +179,13 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 					}
 
 					job, jobErr := deps.JobService.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, payload, "episode_id")
-					if jobErr != nil {
+					if errors.Is(jobErr, jobs.ErrQueueFull) {
+						types.SendServiceError(c, jobErr, "Failed to queue waveform generation")
+						return
+					} else if jobErr != nil {
 						log.Printf("Failed to enqueue waveform job for episode %d: %v", podcastIndexID, jobErr)
 					} else {
+						queuedJobID = job.ID
 						if job.ID > 0 {
 							// Check if this is a new job or existing job returned by EnqueueUniqueJob
 							if job.Status == models.JobStatusPending {
@@ -170,6 +197,7 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 					}
 				}
 
+				types.SetJobLocation(c, queuedJobID)
 				c.JSON(http.StatusAccepted, types.WaveformResponse{
 					BaseResponse: types.BaseResponse{
 						Status:  types.StatusQueued,
@@ -180,12 +208,15 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 						EpisodeID: podcastIndexID,
 						Status:    types.StatusQueued,
 					},
+					JobID:    queuedJobID,
+					JobError: previousError,
 				})
 				return
 			}
 
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Failed to retrieve waveform",
 			})
 			return
@@ -197,6 +228,7 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, types.ErrorResponse{
 				Status:  types.StatusError,
+				Code:    types.CodeInternal,
 				Message: "Failed to decode waveform data",
 			})
 			return
@@ -215,7 +247,73 @@ func GetWaveform(deps *types.Dependencies) gin.HandlerFunc {
 				Duration:   waveformModel.Duration,
 				SampleRate: waveformModel.SampleRate,
 				Status:     types.StatusOK,
+				Stats:      waveformStats(waveformModel),
 			},
 		})
 	}
 }
+
+// awaitWaveform holds a request with ?wait until the episode's waveform job
+// finishes, queueing the job first if there is none. The handler then answers
+// from whatever state generation is in.
+func awaitWaveform(ctx context.Context, deps *types.Dependencies, podcastIndexID int64, wait time.Duration) {
+	if deps.JobService == nil {
+		return
+	}
+	if _, err := deps.WaveformService.GetWaveform(ctx, podcastIndexID); err == nil {
+		return
+	}
+
+	job, err := deps.JobService.GetJobForWaveform(ctx, podcastIndexID)
+	if err != nil || job == nil {
+		payload := models.JobPayload{"episode_id": podcastIndexID}
+		job, err = deps.JobService.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, payload, "episode_id")
+		if err != nil {
+			log.Printf("Failed to enqueue waveform job for episode %d: %v", podcastIndexID, err)
+			return
+		}
+	}
+	types.WaitForJob(ctx, deps, job.ID, wait)
+}
+
+// waveformStats returns the stored rendering hints, or nil for waveforms
+// generated before they were recorded
+func waveformStats(w *models.Waveform) *types.WaveformStats {
+	if !w.HasStats() {
+		return nil
+	}
+	return &types.WaveformStats{
+		P95Peak:        *w.P95Peak,
+		MedianPeak:     *w.MedianPeak,
+		DynamicRangeDB: *w.DynamicRangeDB,
+		LoudnessDB:     *w.LoudnessDB,
+	}
+}
+
+// partialWaveform returns the in-progress waveform, including checkpointed
+// peaks when the processor has recorded any
+func partialWaveform(ctx context.Context, deps *types.Dependencies, podcastIndexID int64) *types.Waveform {
+	waveform := &types.Waveform{
+		ID:        strconv.FormatInt(podcastIndexID, 10),
+		EpisodeID: podcastIndexID,
+		Status:    types.StatusProcessing,
+	}
+
+	checkpoint, err := deps.WaveformService.GetCheckpoint(ctx, podcastIndexID)
+	if err != nil {
+		if !errors.Is(err, waveforms.ErrCheckpointNotFound) {
+			log.Printf("[WARN] Failed to load waveform checkpoint for episode %d: %v", podcastIndexID, err)
+		}
+		return waveform
+	}
+
+	peaks, err := checkpoint.Peaks()
+	if err != nil {
+		log.Printf("[WARN] Failed to decode waveform checkpoint for episode %d: %v", podcastIndexID, err)
+		return waveform
+	}
+	waveform.Data = peaks
+	waveform.Duration = checkpoint.Duration
+	waveform.Progress = checkpoint.Progress
+	return waveform
+}
@@ -0,0 +1,239 @@
+package waveform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/killallgit/player-api/api/types"
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/download"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
+	"github.com/spf13/viper"
+)
+
+// Bounds for the points query parameter
+const (
+	minPreviewPoints = 10
+	maxPreviewPoints = 1000
+)
+
+// defaultPreviewTimeout bounds a preview when ffmpeg.preview_timeout is unset
+const defaultPreviewTimeout = 60 * time.Second
+
+// GetWaveformPreview returns a coarse waveform without waiting for the full job
+// @Summary      Get a low-resolution waveform preview
+// @Description  Returns a coarse waveform for quick display. When the full waveform is ready it is downsampled.
+// @Description  Otherwise the audio is decoded as it streams in (from the audio cache when available, else from
+// @Description  the episode's audio URL) without being stored, and full-resolution generation is queued separately.
+// @Tags         waveform
+// @Produce      json
+// @Param        id path int64 true "Episode's Podcast Index ID" minimum(1)
+// @Param        points query int false "Number of peaks (10-1000)" default(200)
+// @Success      200 {object} types.WaveformResponse "Preview waveform (status:preview) or downsampled full waveform (status:ok)"
+// @Failure      400 {object} types.ErrorResponse "Invalid episode ID or points"
+// @Failure      404 {object} types.ErrorResponse "Episode not found or has no audio URL"
+// @Failure      502 {object} types.ErrorResponse "Audio could not be fetched or decoded"
+// @Failure      503 {object} types.ErrorResponse "Waveform preview not available"
+// @Router       /api/v1/episodes/{id}/waveform/preview [get]
+func GetWaveformPreview(deps *types.Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		podcastIndexID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || podcastIndexID <= 0 {
+			c.JSON(http.StatusBadRequest, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeInvalidRequest,
+				Message: "Invalid Podcast Index Episode ID",
+			})
+			return
+		}
+
+		points := ffmpeg.DefaultPreviewResolution
+		if raw := c.Query("points"); raw != "" {
+			points, err = strconv.Atoi(raw)
+			if err != nil || points < minPreviewPoints || points > maxPreviewPoints {
+				c.JSON(http.StatusBadRequest, types.ErrorResponse{
+					Status:  types.StatusError,
+					Code:    types.CodeInvalidRequest,
+					Message: fmt.Sprintf("points must be between %d and %d", minPreviewPoints, maxPreviewPoints),
+				})
+				return
+			}
+		}
+
+		if deps.WaveformService == nil || deps.EpisodeService == nil || deps.FFmpeg == nil {
+			c.JSON(http.StatusServiceUnavailable, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeServiceUnavailable,
+				Message: "Waveform preview not available",
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		// A finished waveform is cheaper and more accurate than decoding again
+		if existing, err := deps.WaveformService.GetWaveform(ctx, podcastIndexID); err == nil && existing != nil {
+			if peaks, err := existing.Peaks(); err == nil {
+				response := previewResponse(podcastIndexID, &ffmpeg.WaveformData{
+					Peaks:      ffmpeg.DownsamplePeaks(peaks, points),
+					Duration:   existing.Duration,
+					SampleRate: existing.SampleRate,
+				}, types.StatusOK)
+				response.Waveform.Stats = waveformStats(existing)
+				c.JSON(http.StatusOK, response)
+				return
+			}
+		}
+
+		episode, err := deps.EpisodeService.GetEpisodeByPodcastIndexID(ctx, podcastIndexID)
+		if _, _, _, known := types.LookupServiceError(err); known {
+			types.SendServiceError(c, err, "Failed to fetch episode")
+			return
+		}
+		if err != nil || episode.AudioURL == "" {
+			c.JSON(http.StatusNotFound, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeEpisodeNotFound,
+				Message: "Episode not found or has no audio",
+			})
+			return
+		}
+
+		// The full-resolution job proceeds independently of the preview
+		if deps.JobService != nil {
+			payload := models.JobPayload{"episode_id": podcastIndexID}
+			if _, err := deps.JobService.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, payload, "episode_id"); err != nil {
+				log.Printf("[WARN] Failed to enqueue waveform job for episode %d: %v", podcastIndexID, err)
+			}
+		}
+
+		timeout := viper.GetDuration("ffmpeg.preview_timeout")
+		if timeout <= 0 {
+			timeout = defaultPreviewTimeout
+		}
+		previewCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		source, err := openPreviewSource(previewCtx, deps, podcastIndexID, episode.AudioURL)
+		if err != nil {
+			log.Printf("[WARN] Waveform preview fetch failed for episode %d: %v", podcastIndexID, err)
+			c.JSON(http.StatusBadGateway, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeUpstreamError,
+				Message: "Failed to fetch episode audio",
+				Details: err.Error(),
+			})
+			return
+		}
+		defer source.Close()
+
+		data, err := deps.FFmpeg.GeneratePreviewWaveform(previewCtx, source, points)
+		if err != nil {
+			log.Printf("[WARN] Waveform preview decode failed for episode %d: %v", podcastIndexID, err)
+			c.JSON(http.StatusBadGateway, types.ErrorResponse{
+				Status:  types.StatusError,
+				Code:    types.CodeUpstreamError,
+				Message: "Failed to decode episode audio",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, previewResponse(podcastIndexID, data, types.StatusPreview))
+	}
+}
+
+// openPreviewSource prefers locally cached audio and otherwise streams the
+// origin response body, applying the host's download policy
+func openPreviewSource(ctx context.Context, deps *types.Dependencies, podcastIndexID int64, audioURL string) (io.ReadCloser, error) {
+	if deps.AudioCacheService != nil {
+		if cached, err := deps.AudioCacheService.GetCachedAudio(ctx, podcastIndexID); err == nil && cached != nil && cached.OriginalPath != "" {
+			if file, err := os.Open(cached.OriginalPath); err == nil {
+				return file, nil
+			}
+		}
+	}
+
+	policies := deps.DownloadPolicies
+	if policies == nil {
+		policies = download.NewPolicies(download.DefaultBasePolicy(), download.DefaultHostPolicies())
+	}
+	policy := policies.For(audioURL)
+	release, err := policies.Acquire(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, audioURL, nil)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	policy.Apply(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		release()
+		return nil, &download.StatusError{StatusCode: resp.StatusCode, Message: resp.Status}
+	}
+
+	return &releasingBody{ReadCloser: resp.Body, release: release}, nil
+}
+
+// releasingBody frees the host concurrency slot when the body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+func previewResponse(podcastIndexID int64, data *ffmpeg.WaveformData, status string) types.WaveformResponse {
+	message := "Waveform preview generated"
+	if status == types.StatusOK {
+		message = "Waveform preview downsampled from full waveform"
+	}
+	return types.WaveformResponse{
+		BaseResponse: types.BaseResponse{
+			Status:  types.StatusOK,
+			Message: message,
+		},
+		Waveform: &types.Waveform{
+			ID:         strconv.FormatInt(podcastIndexID, 10),
+			EpisodeID:  podcastIndexID,
+			Data:       data.Peaks,
+			Duration:   data.Duration,
+			SampleRate: data.SampleRate,
+			Status:     status,
+			Stats:      previewStats(data.Stats),
+		},
+	}
+}
+
+// previewStats converts the decoder's stats for a preview response
+func previewStats(stats *ffmpeg.WaveformStats) *types.WaveformStats {
+	if stats == nil {
+		return nil
+	}
+	return &types.WaveformStats{
+		P95Peak:        stats.P95Peak,
+		MedianPeak:     stats.MedianPeak,
+		DynamicRangeDB: stats.DynamicRangeDB,
+		LoudnessDB:     stats.LoudnessDB,
+	}
+}
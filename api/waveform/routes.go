@@ -9,4 +9,7 @@ import (
 func RegisterRoutes(router *gin.RouterGroup, deps *types.Dependencies) {
 	// GET /api/v1/episodes/:id/waveform - Get waveform data with status
 	router.GET("/:id/waveform", GetWaveform(deps))
+
+	// GET /api/v1/episodes/:id/waveform/preview - Coarse waveform decoded from a stream
+	router.GET("/:id/waveform/preview", GetWaveformPreview(deps))
 }
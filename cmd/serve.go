@@ -7,10 +7,13 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/killallgit/player-api/api"
 	"github.com/killallgit/player-api/internal/database"
 	"github.com/killallgit/player-api/pkg/config"
+	"github.com/killallgit/player-api/pkg/logging"
+	"github.com/killallgit/player-api/pkg/tracing"
 	"github.com/spf13/cobra"
 )
 
@@ -51,6 +54,34 @@ func runServer(cmd *cobra.Command, args []string) error {
 		serverPort = config.GetInt("server.port")
 	}
 
+	// Log lines below logging.level are dropped
+	level, err := logging.ParseLevel(config.GetString("logging.level"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v, logging at info\n", err)
+	}
+	logging.Install(level)
+
+	// Tracing comes first so database and server setup use the real provider
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Enabled:     config.GetBool("tracing.enabled"),
+		ServiceName: config.GetString("tracing.service_name"),
+		Endpoint:    config.GetString("tracing.endpoint"),
+		URLPath:     config.GetString("tracing.url_path"),
+		Insecure:    config.GetBool("tracing.insecure"),
+		Headers:     config.GetStringMapString("tracing.headers"),
+		SampleRatio: config.GetFloat64("tracing.sample_ratio"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to flush traces: %v\n", err)
+		}
+	}()
+
 	// Initialize database with migrations
 	db, err := database.InitializeWithMigrations()
 	if err != nil {
@@ -70,6 +101,9 @@ func runServer(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize server: %w", err)
 	}
 
+	// Rate limits, cache TTLs, worker count and log level follow the config file
+	config.Watch(apiServer.ApplyConfigChanges)
+
 	// Channel to listen for interrupt signals
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -15,6 +15,7 @@ import (
 	"github.com/killallgit/player-api/internal/services/clips"
 	"github.com/killallgit/player-api/internal/services/jobs"
 	"github.com/killallgit/player-api/internal/services/workers"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
@@ -34,6 +35,14 @@ func (m *mockEpisodeService) GetEpisodeByPodcastIndexID(ctx context.Context, pod
 	}, nil
 }
 
+func (m *mockEpisodeService) GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) (map[int64]*models.Episode, error) {
+	episodes := make(map[int64]*models.Episode, len(podcastIndexIDs))
+	for _, id := range podcastIndexIDs {
+		episodes[id] = &models.Episode{PodcastIndexID: id, AudioURL: m.audioURL}
+	}
+	return episodes, nil
+}
+
 // Mock audio cache service for tests (returns nil - no cache)
 type mockAudioCacheService struct{}
 
@@ -72,7 +81,7 @@ func setupClipTestSuite(t *testing.T) *ClipTestSuite {
 	require.NoError(t, err, "Failed to connect to test database")
 
 	// Run migrations
-	err = db.AutoMigrate(&models.Job{}, &models.Clip{})
+	err = db.AutoMigrate(&models.Job{}, &models.JobDependency{}, &models.Clip{}, &models.AnnotationAudit{})
 	require.NoError(t, err, "Failed to migrate test database")
 
 	// Create database wrapper (for potential future use)
@@ -83,7 +92,7 @@ func setupClipTestSuite(t *testing.T) *ClipTestSuite {
 	jobService := jobs.NewService(jobRepo)
 
 	// Initialize clip components (targetDuration=0 means no normalization - preserve exact durations)
-	extractor, err := clips.NewFFmpegExtractor(tempDir, 0.0)
+	extractor, err := clips.NewFFmpegExtractor(ffmpeg.New("ffmpeg", "ffprobe", 0), tempDir, 0.0)
 	require.NoError(t, err, "Failed to create FFmpeg extractor")
 
 	storage, err := clips.NewLocalClipStorage(clipsDir)
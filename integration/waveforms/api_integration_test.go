@@ -71,6 +71,18 @@ func (m *mockEpisodeService) GetRecentEpisodes(ctx context.Context, limit int) (
 	return nil, nil
 }
 
+func (m *mockEpisodeService) GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) (map[int64]*models.Episode, error) {
+	return nil, nil
+}
+
+func (m *mockEpisodeService) GetEpisodeStatuses(ctx context.Context, podcastIndexIDs []int64) (map[int64]models.EpisodeStatus, error) {
+	return nil, nil
+}
+
+func (m *mockEpisodeService) GetEpisodeChanges(ctx context.Context, feedID int64, cursor string, limit int) (*episodes.EpisodeChanges, error) {
+	return nil, nil
+}
+
 func (m *mockEpisodeService) GetEpisodesByFeedID(ctx context.Context, feedID int64, limit int) ([]*episodes.PodcastIndexEpisode, error) {
 	return nil, nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/pkg/config"
+	"github.com/killallgit/player-api/pkg/tracing"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -43,6 +44,10 @@ func Initialize(dbPath string, verbose bool) (*DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get underlying SQL database: %w", err)
@@ -103,6 +108,15 @@ func InitializeWithMigrations() (*DB, error) {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	if err := migrateLegacyWaveforms(db.DB); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	if err := dedupeSubscriptions(db.DB); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
 	if err := db.AutoMigrate(
 		&models.Podcast{},
 		&models.Episode{},
@@ -113,6 +127,34 @@ func InitializeWithMigrations() (*DB, error) {
 		&models.AudioCache{},
 		&models.Dataset{},
 		&models.Clip{},
+		&models.EpisodeSummary{},
+		&models.SummaryUsage{},
+		&models.ContentAnalysis{},
+		&models.Category{},
+		&models.PodcastCategory{},
+		&models.PlaybackProgress{},
+		&models.ListeningHistory{},
+		&models.DailyListening{},
+		&models.Person{},
+		&models.EpisodePerson{},
+		&models.UserPreferences{}, &models.AccountDeletion{}, &models.WaveformCheckpoint{},
+		&models.Notification{}, &models.AnnotationAudit{}, &models.EpisodeStats{},
+		&models.JobCallback{}, &models.JobDependency{},
+		&models.BlocklistEntry{}, &models.BlocklistAudit{},
+		&models.PlaybackEvent{},
+		&models.SavedSearch{},
+		&models.EpisodeRead{},
+		&models.SavedSearchResult{},
+		&models.FeedHealth{},
+		&models.ArtworkPalette{},
+		&models.AutoplayRules{},
+		&models.PodcastRights{},
+		&models.DatasetEvaluation{},
+		&models.DetectorModel{},
+		&models.AutoApprovalThreshold{},
+		&models.EpisodeLoudness{},
+		&models.ExternalAPIUsage{},
+		&models.LiveItem{}, &models.LiveItemSchedule{},
 	); err != nil {
 		_ = db.Close()
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// dedupeSubscriptions soft-deletes all but the oldest active subscription of
+// each user to each podcast. It runs before AutoMigrate, which can't add the
+// (user_id, podcast_id) unique index while duplicates from concurrent
+// subscribes remain.
+func dedupeSubscriptions(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Subscription{}) ||
+		db.Migrator().HasIndex(&models.Subscription{}, "idx_subscription_user_podcast") {
+		return nil
+	}
+
+	result := db.Exec(`UPDATE subscriptions SET deleted_at = CURRENT_TIMESTAMP
+		WHERE deleted_at IS NULL AND id NOT IN
+		(SELECT MIN(id) FROM subscriptions WHERE deleted_at IS NULL GROUP BY user_id, podcast_id)`)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove duplicate subscriptions: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[INFO] Removed %d duplicate subscriptions", result.RowsAffected)
+	}
+	return nil
+}
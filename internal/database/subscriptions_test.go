@@ -0,0 +1,38 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupeSubscriptions(t *testing.T) {
+	db, err := Initialize(":memory:", false)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Before the unique index, concurrent subscribes could store duplicates
+	require.NoError(t, db.Exec(`CREATE TABLE subscriptions (
+		id integer PRIMARY KEY AUTOINCREMENT, created_at datetime, updated_at datetime, deleted_at datetime,
+		user_id text NOT NULL, podcast_id integer NOT NULL, folder text, tags text)`).Error)
+	for _, row := range []struct {
+		userID    string
+		podcastID uint
+	}{{"user-1", 1}, {"user-1", 1}, {"user-1", 2}, {"user-2", 1}} {
+		require.NoError(t, db.Exec("INSERT INTO subscriptions (user_id, podcast_id) VALUES (?, ?)", row.userID, row.podcastID).Error)
+	}
+
+	require.NoError(t, dedupeSubscriptions(db.DB))
+	require.NoError(t, db.AutoMigrate(&models.Subscription{}))
+
+	var ids []uint
+	require.NoError(t, db.Model(&models.Subscription{}).Order("id").Pluck("id", &ids).Error)
+	assert.Equal(t, []uint{1, 3, 4}, ids, "the oldest of the duplicates is kept")
+
+	// The index now refuses a second active subscription, but not a resubscribe
+	assert.Error(t, db.Create(&models.Subscription{UserID: "user-1", PodcastID: 2}).Error)
+	require.NoError(t, db.Where("id = ?", 3).Delete(&models.Subscription{}).Error)
+	assert.NoError(t, db.Create(&models.Subscription{UserID: "user-1", PodcastID: 2}).Error)
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// migrateLegacyWaveforms moves waveforms written under the episode's database
+// ID (the old episode_id column) onto podcast_index_episode_id, the only key
+// the API reads them by, and drops the old column. It runs before
+// AutoMigrate, which can't add a NOT NULL column to a table that has rows.
+func migrateLegacyWaveforms(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Waveform{}) || !hasColumn(db, "waveforms", "episode_id") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+		if !hasColumn(tx, "waveforms", "podcast_index_episode_id") {
+			if err := tx.Exec("ALTER TABLE waveforms ADD COLUMN podcast_index_episode_id integer").Error; err != nil {
+				return fmt.Errorf("failed to add podcast_index_episode_id to waveforms: %w", err)
+			}
+		}
+
+		var backfilled int64
+		if migrator.HasTable(&models.Episode{}) {
+			result := tx.Exec(`UPDATE waveforms
+				SET podcast_index_episode_id = (SELECT podcast_index_id FROM episodes WHERE episodes.id = waveforms.episode_id)
+				WHERE (podcast_index_episode_id IS NULL OR podcast_index_episode_id = 0) AND episode_id IS NOT NULL`)
+			if result.Error != nil {
+				return fmt.Errorf("failed to backfill waveform episode IDs: %w", result.Error)
+			}
+			backfilled = result.RowsAffected
+		}
+
+		// Rows whose episode is gone can never be read, and where both
+		// processors wrote a waveform for the same episode the newest wins
+		orphaned := tx.Exec("DELETE FROM waveforms WHERE podcast_index_episode_id IS NULL OR podcast_index_episode_id = 0")
+		if orphaned.Error != nil {
+			return fmt.Errorf("failed to remove unresolvable waveforms: %w", orphaned.Error)
+		}
+		duplicates := tx.Exec(`DELETE FROM waveforms WHERE id NOT IN
+			(SELECT MAX(id) FROM waveforms GROUP BY podcast_index_episode_id)`)
+		if duplicates.Error != nil {
+			return fmt.Errorf("failed to remove duplicate waveforms: %w", duplicates.Error)
+		}
+
+		if migrator.HasIndex(&models.Waveform{}, "idx_waveforms_episode_id") {
+			if err := migrator.DropIndex(&models.Waveform{}, "idx_waveforms_episode_id"); err != nil {
+				return fmt.Errorf("failed to drop waveforms episode_id index: %w", err)
+			}
+		}
+		if err := tx.Exec("ALTER TABLE waveforms DROP COLUMN episode_id").Error; err != nil {
+			return fmt.Errorf("failed to drop waveforms episode_id column: %w", err)
+		}
+
+		log.Printf("[INFO] Migrated legacy waveforms: %d backfilled, %d unresolvable and %d duplicates removed",
+			backfilled-orphaned.RowsAffected, orphaned.RowsAffected, duplicates.RowsAffected)
+		return nil
+	})
+}
+
+// hasColumn reports whether table has the column. The SQLite migrator's
+// HasColumn matches the table's DDL, so it also finds episode_id inside
+// podcast_index_episode_id.
+func hasColumn(db *gorm.DB, table, column string) bool {
+	var count int64
+	db.Raw("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	return count > 0
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateLegacyWaveforms(t *testing.T) {
+	db, err := Initialize(":memory:", false)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Episode{}))
+	for _, piID := range []int64{41951637359, 41951637360} {
+		require.NoError(t, db.Create(&models.Episode{
+			PodcastIndexID: piID, GUID: fmt.Sprint(piID), Title: "Episode",
+		}).Error)
+	}
+
+	// The old schema keyed waveforms by the episode's database ID
+	require.NoError(t, db.Exec(`CREATE TABLE waveforms (
+		id integer PRIMARY KEY AUTOINCREMENT, created_at datetime, updated_at datetime, deleted_at datetime,
+		episode_id integer NOT NULL, peaks_data blob NOT NULL, duration real NOT NULL, resolution integer NOT NULL,
+		sample_rate integer DEFAULT 44100)`).Error)
+	require.NoError(t, db.Exec("CREATE INDEX idx_waveforms_episode_id ON waveforms(episode_id)").Error)
+	for _, episodeID := range []int{1, 2, 99} {
+		require.NoError(t, db.Exec("INSERT INTO waveforms (episode_id, peaks_data, duration, resolution) VALUES (?, '[0.5]', 60, 1)", episodeID).Error)
+	}
+
+	require.NoError(t, migrateLegacyWaveforms(db.DB))
+	require.NoError(t, db.AutoMigrate(&models.Waveform{}))
+	assert.False(t, hasColumn(db.DB, "waveforms", "episode_id"))
+
+	var waveforms []models.Waveform
+	require.NoError(t, db.Order("podcast_index_episode_id").Find(&waveforms).Error)
+	require.Len(t, waveforms, 2, "the waveform of the missing episode is dropped")
+	assert.Equal(t, int64(41951637359), waveforms[0].PodcastIndexEpisodeID)
+	assert.Equal(t, int64(41951637360), waveforms[1].PodcastIndexEpisodeID)
+
+	// Running again on the current schema does nothing
+	require.NoError(t, migrateLegacyWaveforms(db.DB))
+}
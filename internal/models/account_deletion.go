@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// AccountDeletionStatus represents the state of a scheduled account deletion
+type AccountDeletionStatus string
+
+const (
+	AccountDeletionScheduled AccountDeletionStatus = "scheduled" // Waiting out the grace period; can be cancelled
+	AccountDeletionQueued    AccountDeletionStatus = "queued"    // Deletion job enqueued; can no longer be cancelled
+	AccountDeletionCompleted AccountDeletionStatus = "completed"
+	AccountDeletionCancelled AccountDeletionStatus = "cancelled"
+)
+
+// AccountDeletion records a user's request to delete their data
+type AccountDeletion struct {
+	ID uint `gorm:"primarykey" json:"-"`
+
+	// Owner (Supabase user UUID); one row per user, reused if they request deletion again
+	UserID string `gorm:"uniqueIndex;not null;size:36" json:"-"`
+
+	Status       AccountDeletionStatus `gorm:"not null;index:idx_account_deletion_due" json:"status"`
+	RequestedAt  time.Time             `json:"requested_at"`
+	ScheduledFor time.Time             `gorm:"index:idx_account_deletion_due" json:"scheduled_for"` // End of the grace period
+	JobID        *uint                 `json:"job_id,omitempty"`
+	CompletedAt  *time.Time            `json:"completed_at,omitempty"`
+	CancelledAt  *time.Time            `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time             `json:"-"`
+	UpdatedAt    time.Time             `json:"-"`
+}
+
+// TableName specifies the table name for AccountDeletion
+func (AccountDeletion) TableName() string {
+	return "account_deletions"
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Annotation audit actions
+const (
+	AnnotationActionCreate    = "create"
+	AnnotationActionUpdate    = "update"
+	AnnotationActionApprove   = "approve"
+	AnnotationActionReject    = "reject"
+	AnnotationActionDelete    = "delete"
+	AnnotationActionAutoLabel = "auto_label"
+
+	// AnnotationActionAutoApprove is analysis approving a clip whose score met
+	// its label's threshold; reviewers approving clips record AnnotationActionApprove
+	AnnotationActionAutoApprove = "auto_approve"
+
+	// AnnotationActionBulkDelete is a clip removed by a filtered bulk deletion
+	// rather than on its own, so cleanups can be told apart from single deletes
+	AnnotationActionBulkDelete = "bulk_delete"
+)
+
+// AnnotationSnapshot is the labeling-relevant state of a clip at one point in time.
+// Processing fields (extraction status, file sizes) are left out on purpose: the
+// audit trail answers "who said this segment is an ad", not "when was it extracted".
+type AnnotationSnapshot struct {
+	Label             string   `json:"label"`
+	OriginalStartTime float64  `json:"original_start_time"`
+	OriginalEndTime   float64  `json:"original_end_time"`
+	AutoLabeled       bool     `json:"auto_labeled"`
+	LabelConfidence   *float64 `json:"label_confidence,omitempty"`
+	LabelMethod       string   `json:"label_method"`
+	Approved          bool     `json:"approved"`
+	AutoApproved      bool     `json:"auto_approved,omitempty"`
+	Rejected          bool     `json:"rejected"`
+	RejectionReason   string   `json:"rejection_reason,omitempty"`
+}
+
+// AnnotationAudit records one change to a clip's annotation. Rows outlive the
+// clip they describe so deleted training labels remain traceable.
+type AnnotationAudit struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+
+	ClipUUID              string `json:"clip_uuid" gorm:"size:36;not null;index"`
+	PodcastIndexEpisodeID int64  `json:"podcast_index_episode_id" gorm:"not null;index"`
+
+	// UserID is the Supabase user that made the change; empty for system changes
+	UserID string `json:"user_id,omitempty" gorm:"size:36;index"`
+	Action string `json:"action" gorm:"size:20;not null"`
+
+	Before datatypes.JSON `json:"before,omitempty"` // Snapshot before the change; null on create
+	After  datatypes.JSON `json:"after,omitempty"`  // Snapshot after the change; null on delete
+}
+
+// TableName returns the table name for the AnnotationAudit model
+func (AnnotationAudit) TableName() string {
+	return "annotation_audits"
+}
+
+// Snapshot captures the clip's current annotation state
+func (c *Clip) Snapshot() *AnnotationSnapshot {
+	return &AnnotationSnapshot{
+		Label:             c.Label,
+		OriginalStartTime: c.OriginalStartTime,
+		OriginalEndTime:   c.OriginalEndTime,
+		AutoLabeled:       c.AutoLabeled,
+		LabelConfidence:   c.LabelConfidence,
+		LabelMethod:       c.LabelMethod,
+		Approved:          c.Approved,
+		AutoApproved:      c.AutoApproved,
+		Rejected:          c.Rejected,
+		RejectionReason:   c.RejectionReason,
+	}
+}
+
+// NewAnnotationAudit builds an audit row for a change from before to after.
+// Either snapshot may be nil (create has no before, delete has no after).
+func NewAnnotationAudit(clip *Clip, userID, action string, before, after *AnnotationSnapshot) (*AnnotationAudit, error) {
+	audit := &AnnotationAudit{
+		ClipUUID:              clip.UUID,
+		PodcastIndexEpisodeID: clip.PodcastIndexEpisodeID,
+		UserID:                userID,
+		Action:                action,
+	}
+	if before != nil {
+		data, err := json.Marshal(before)
+		if err != nil {
+			return nil, err
+		}
+		audit.Before = data
+	}
+	if after != nil {
+		data, err := json.Marshal(after)
+		if err != nil {
+			return nil, err
+		}
+		audit.After = data
+	}
+	return audit, nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ArtworkPalette holds the dominant colors of an artwork image. Podcasts and
+// episodes sharing an image share its row.
+type ArtworkPalette struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	ImageURL  string    `gorm:"not null;uniqueIndex;size:1000" json:"image_url"`
+	Colors    string    `gorm:"size:200" json:"-"`               // Comma-separated #rrggbb, most dominant first; empty if extraction failed
+	Error     string    `gorm:"size:500" json:"error,omitempty"` // Why extraction failed
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for ArtworkPalette
+func (ArtworkPalette) TableName() string {
+	return "artwork_palettes"
+}
+
+// ColorList returns the palette's colors, most dominant first
+func (p *ArtworkPalette) ColorList() []string {
+	if p.Colors == "" {
+		return nil
+	}
+	return strings.Split(p.Colors, ",")
+}
@@ -6,6 +6,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// Format of the processed rendition. Transcription reads it as is and clips
+// are cut from it, so an episode is resampled once rather than per pipeline.
+const (
+	ProcessedAudioSampleRate = 16000
+	ProcessedAudioChannels   = 1
+	ProcessedAudioCodec      = "pcm_s16le" // In a WAV container
+)
+
 // AudioCache represents cached audio files for episodes
 type AudioCache struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
@@ -17,24 +25,42 @@ type AudioCache struct {
 
 	// Original audio info
 	OriginalURL    string `gorm:"not null" json:"original_url"`
+	SourceURL      string `json:"source_url"` // Where the audio was downloaded from; a fallback when the enclosure URL was refused
 	OriginalSHA256 string `gorm:"size:64" json:"original_sha256"`
 	OriginalPath   string `json:"original_path"`
 	OriginalSize   int64  `json:"original_size"`
 
-	// Processed audio (16kHz mono for ML)
-	ProcessedPath   string `json:"processed_path"`
-	ProcessedSHA256 string `gorm:"size:64" json:"processed_sha256"`
-	ProcessedSize   int64  `json:"processed_size"`
+	// Processed audio (16kHz mono for ML); the sample rate is SampleRate
+	ProcessedPath     string `json:"processed_path"`
+	ProcessedSHA256   string `gorm:"size:64" json:"processed_sha256"`
+	ProcessedSize     int64  `json:"processed_size"`
+	ProcessedCodec    string `gorm:"size:32" json:"processed_codec"` // Empty for renditions made before the format was recorded
+	ProcessedChannels int    `json:"processed_channels"`
 
 	// Metadata
-	DurationSeconds float64   `json:"duration_seconds"`
-	SampleRate      int       `json:"sample_rate"`
+	DurationSeconds float64   `json:"duration_seconds"` // Measured with ffprobe, not taken from the feed
+	SampleRate      int       `json:"sample_rate"`      // Of the processed file
 	CachedAt        time.Time `json:"cached_at"`
-	LastUsedAt      time.Time `json:"last_used_at"`
+
+	// Original audio stream as reported by ffprobe
+	Codec              string    `gorm:"size:32" json:"codec"`
+	Bitrate            int       `json:"bitrate"` // Bits per second
+	Channels           int       `json:"channels"`
+	OriginalSampleRate int       `json:"original_sample_rate"`
+	LastUsedAt         time.Time `json:"last_used_at"`
 
 	// No direct relationship - we use Podcast Index ID for lookups
 }
 
+// HasCurrentProcessed reports whether the processed rendition is in the
+// current format. Older renditions are processed again when next needed.
+func (a *AudioCache) HasCurrentProcessed() bool {
+	return a.ProcessedPath != "" &&
+		a.ProcessedCodec == ProcessedAudioCodec &&
+		a.SampleRate == ProcessedAudioSampleRate &&
+		a.ProcessedChannels == ProcessedAudioChannels
+}
+
 // TableName returns the table name for the AudioCache model
 func (AudioCache) TableName() string {
 	return "audio_cache"
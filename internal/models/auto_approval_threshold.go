@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// AutoApprovalThreshold is the confidence at or over which episode analysis
+// approves the clips it creates with Label, overriding the threshold from
+// config for that label
+type AutoApprovalThreshold struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Label     string  `json:"label" gorm:"not null;size:100;uniqueIndex"`
+	Threshold float64 `json:"threshold" gorm:"not null"` // Confidence 0.0-1.0
+	UpdatedBy string  `json:"updated_by,omitempty" gorm:"size:36"`
+}
+
+// TableName returns the table name for the AutoApprovalThreshold model
+func (AutoApprovalThreshold) TableName() string {
+	return "auto_approval_thresholds"
+}
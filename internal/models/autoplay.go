@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// AutoplayRules stores a user's rules for what plays after an episode. Users
+// without a row get the default rules.
+type AutoplayRules struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	UserID    string    `gorm:"uniqueIndex;not null;size:36" json:"-"` // Supabase UUID
+	RulesData string    `gorm:"type:text;not null" json:"-"`           // JSON-encoded rules, in evaluation order
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for AutoplayRules
+func (AutoplayRules) TableName() string {
+	return "autoplay_rules"
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Blocklist entry kinds
+const (
+	BlockKindFeed     = "feed"     // Podcast Index feed ID
+	BlockKindDomain   = "domain"   // Host of a feed, link or enclosure URL, including subdomains
+	BlockKindCategory = "category" // Podcast Index category name or ID
+)
+
+// Blocklist audit actions
+const (
+	BlocklistActionAdd      = "add"
+	BlocklistActionRemove   = "remove"
+	BlocklistActionOverride = "override" // An admin asked to see blocked content
+)
+
+// BlocklistEntry keeps matching podcasts and episodes from reaching clients
+type BlocklistEntry struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Kind   string `gorm:"size:20;not null;uniqueIndex:idx_blocklist_kind_value" json:"kind"`
+	Value  string `gorm:"size:255;not null;uniqueIndex:idx_blocklist_kind_value" json:"value"` // Normalized: lowercase domains and categories
+	Reason string `gorm:"size:500" json:"reason,omitempty"`
+
+	// CreatedBy is the Supabase user that added the entry
+	CreatedBy string `gorm:"size:36" json:"created_by,omitempty"`
+}
+
+// TableName returns the table name for the BlocklistEntry model
+func (BlocklistEntry) TableName() string {
+	return "blocklist_entries"
+}
+
+// BlocklistAudit records a change to the blocklist or an admin viewing blocked
+// content. Rows keep the entry's kind and value so they outlive its removal.
+type BlocklistAudit struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	UserID  string `gorm:"size:36;index" json:"user_id,omitempty"`
+	Action  string `gorm:"size:20;not null" json:"action"`
+	EntryID uint   `json:"entry_id,omitempty"`
+	Kind    string `gorm:"size:20" json:"kind,omitempty"`
+	Value   string `gorm:"size:255" json:"value,omitempty"`
+	Reason  string `gorm:"size:500" json:"reason,omitempty"`
+	Path    string `gorm:"size:255" json:"path,omitempty"` // Request path, for overrides
+}
+
+// TableName returns the table name for the BlocklistAudit model
+func (BlocklistAudit) TableName() string {
+	return "blocklist_audits"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Category represents a Podcast Index category, arranged as a tree via ParentID
+type Category struct {
+	// ID is the Podcast Index category ID (not auto-incremented)
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `json:"name" gorm:"not null;index"`
+	ParentID  *uint     `json:"parent_id,omitempty" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PodcastCategory associates a locally stored podcast with a category
+type PodcastCategory struct {
+	PodcastID  uint `json:"podcast_id" gorm:"primaryKey"`
+	CategoryID uint `json:"category_id" gorm:"primaryKey;index"`
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +17,29 @@ const (
 	ClipStatusFailed  = "failed"  // Extraction failed
 )
 
+// Clip rejection reason codes recorded when a reviewer rejects a clip
+const (
+	ClipRejectionFalsePositive = "false_positive" // Segment does not contain the labeled content
+	ClipRejectionBadBoundaries = "bad_boundaries" // Right content, but start/end times are off
+	ClipRejectionPoorAudio     = "poor_audio"     // Audio too noisy or degraded to train on
+	ClipRejectionDuplicate     = "duplicate"      // Overlaps a clip that is already kept
+	ClipRejectionOther         = "other"
+)
+
+// IsValidClipRejectionReason reports whether reason is a known rejection code
+func IsValidClipRejectionReason(reason string) bool {
+	switch reason {
+	case ClipRejectionFalsePositive, ClipRejectionBadBoundaries, ClipRejectionPoorAudio,
+		ClipRejectionDuplicate, ClipRejectionOther:
+		return true
+	}
+	return false
+}
+
+// HardNegativesDir is the dataset directory holding rejected false positives,
+// grouped by the label they were wrongly given
+const HardNegativesDir = "hard_negatives"
+
 // Clip represents an extracted audio segment with ML label for training
 type Clip struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
@@ -30,6 +55,9 @@ type Clip struct {
 	SourceEpisodeURL  string  `json:"source_episode_url" gorm:"not null;size:500"`
 	OriginalStartTime float64 `json:"original_start_time" gorm:"not null"` // Time in seconds
 	OriginalEndTime   float64 `json:"original_end_time" gorm:"not null"`   // Time in seconds
+	// Set on the clip CreateClip returns when the requested end ran past the
+	// episode and was moved to its end; not stored
+	RangeClamped bool `json:"range_clamped,omitempty" gorm:"-"`
 
 	// Flexible label - any string allowed for future extensibility
 	Label string `json:"label" gorm:"not null;size:100;index"` // Index for fast filtering by label
@@ -39,8 +67,28 @@ type Clip struct {
 	LabelConfidence *float64 `json:"label_confidence,omitempty" gorm:"type:decimal(5,4)"` // Confidence score 0.0-1.0 (nullable)
 	LabelMethod     string   `json:"label_method" gorm:"size:50;default:manual"`          // How it was labeled: "manual", "peak_detection", etc.
 
+	// Registry model that created the clip during episode analysis; empty for
+	// clips created by hand
+	ModelName    string `json:"model_name,omitempty" gorm:"size:100;index"`
+	ModelVersion string `json:"model_version,omitempty" gorm:"size:50"`
+
+	// Spoken text chosen when the clip was created from a transcript selection.
+	// Other clips get theirs from the episode's transcript as they're read
+	// (see TranscriptExcerpt), so it isn't stored for them.
+	TranscriptText *string `json:"transcript_text,omitempty" gorm:"type:text"`
+
+	// Owner (Supabase user UUID) for manually created clips; empty for automatic clips
+	CreatedBy string `json:"created_by,omitempty" gorm:"size:36;index"`
+
 	// Approval workflow (for review before extraction)
 	Approved bool `json:"approved" gorm:"default:false;index"` // Whether clip is approved for extraction/dataset
+	// Approved by analysis for a score at or over its label's threshold, and
+	// not yet confirmed by a reviewer; ApprovalThreshold is the threshold
+	AutoApproved      bool       `json:"auto_approved" gorm:"default:false;index"`
+	ApprovalThreshold *float64   `json:"approval_threshold,omitempty"`
+	Rejected          bool       `json:"rejected" gorm:"default:false;index"`             // Whether a reviewer dismissed the clip (kept out of the review queue)
+	RejectionReason   string     `json:"rejection_reason,omitempty" gorm:"size:50;index"` // One of the ClipRejection* codes
+	RejectedAt        *time.Time `json:"rejected_at,omitempty"`
 
 	// Extracted clip information (optional - NULL for auto-detected clips without extraction)
 	// ClipFilename is just the filename (e.g., "clip_abc123.wav")
@@ -48,6 +96,7 @@ type Clip struct {
 	ClipFilename  *string  `json:"clip_filename,omitempty" gorm:"size:255;uniqueIndex"` // NULL if not extracted
 	ClipDuration  *float64 `json:"clip_duration,omitempty"`                             // NULL if not extracted
 	ClipSizeBytes *int64   `json:"clip_size_bytes,omitempty"`                           // NULL if not extracted
+	ClipSHA256    *string  `json:"clip_sha256,omitempty" gorm:"size:64"`                // Hex digest of the stored file; NULL if not extracted
 	Extracted     bool     `json:"extracted" gorm:"default:false;index"`                // Whether audio has been extracted to file
 
 	// Processing status
@@ -55,6 +104,9 @@ type Clip struct {
 
 	// Optional error message if processing failed
 	ErrorMessage string `json:"error_message,omitempty" gorm:"size:500"`
+	// How the extraction job classified the failure; empty when it failed elsewhere
+	ErrorType string `json:"error_type,omitempty" gorm:"size:50"`
+	ErrorCode string `json:"error_code,omitempty" gorm:"size:100"`
 }
 
 // BeforeCreate generates a UUID before creating a new clip
@@ -78,6 +130,51 @@ func (c *Clip) GetOriginalDuration() float64 {
 	return c.OriginalEndTime - c.OriginalStartTime
 }
 
+// ClipSamples locates a clip in sample frames of the episode's original audio,
+// so ML pipelines don't each round seconds their own way
+type ClipSamples struct {
+	SampleRate  int   `json:"sample_rate"`  // Of the cached original audio, in Hz
+	StartSample int64 `json:"start_sample"` // First frame of the clip
+	EndSample   int64 `json:"end_sample"`   // Frame just past the clip
+}
+
+// Samples converts the clip's time range to frames at sampleRate, rounding
+// to the nearest frame. It returns nil when the sample rate isn't known.
+func (c *Clip) Samples(sampleRate int) *ClipSamples {
+	if sampleRate <= 0 {
+		return nil
+	}
+	rate := float64(sampleRate)
+	return &ClipSamples{
+		SampleRate:  sampleRate,
+		StartSample: int64(math.Round(c.OriginalStartTime * rate)),
+		EndSample:   int64(math.Round(c.OriginalEndTime * rate)),
+	}
+}
+
+// TranscriptExcerpt joins the text of the timed segments the clip covers. A
+// segment counts when at least half of it, or half of the clip, lies inside
+// the overlap, so boundaries that cut a segment short don't drag in its text
+// while a clip within one long segment still gets it. It returns "" when no
+// segment qualifies.
+func (c *Clip) TranscriptExcerpt(segments []TranscriptSegment) string {
+	clipLength := c.OriginalEndTime - c.OriginalStartTime
+	var parts []string
+	for _, segment := range segments {
+		overlap := math.Min(segment.End, c.OriginalEndTime) - math.Max(segment.Start, c.OriginalStartTime)
+		if overlap <= 0 {
+			continue
+		}
+		if overlap*2 < segment.End-segment.Start && overlap*2 < clipLength {
+			continue
+		}
+		if text := strings.TrimSpace(segment.Text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 // IsReady returns true if the clip is ready for use
 func (c *Clip) IsReady() bool {
 	return c.Status == "ready"
@@ -98,6 +195,22 @@ func (c *Clip) GetRelativePath() string {
 	return fmt.Sprintf("%s/%s", c.Label, *c.ClipFilename)
 }
 
+// IsHardNegative returns true if the clip was rejected as a false positive,
+// making it a useful negative example for its label
+func (c *Clip) IsHardNegative() bool {
+	return c.Rejected && c.RejectionReason == ClipRejectionFalsePositive
+}
+
+// GetExportPath returns the clip's relative path inside an exported dataset.
+// Hard negatives live under HardNegativesDir so they never mix with positives.
+func (c *Clip) GetExportPath() string {
+	path := c.GetRelativePath()
+	if path == "" || !c.IsHardNegative() {
+		return path
+	}
+	return HardNegativesDir + "/" + path
+}
+
 // IsExtracted returns true if the clip has been extracted to an audio file
 func (c *Clip) IsExtracted() bool {
 	return c.Extracted && c.ClipFilename != nil
@@ -116,6 +229,9 @@ type ClipExport struct {
 	OriginalEndTime   float64  `json:"original_end_time"`          // End time in original
 	UUID              string   `json:"uuid"`                       // Stable identifier
 	CreatedAt         string   `json:"created_at"`                 // ISO 8601 timestamp
+	HardNegative      bool     `json:"hard_negative,omitempty"`    // Rejected false positive for Label
+	RejectionReason   string   `json:"rejection_reason,omitempty"` // Reason code for rejected clips
+	TranscriptText    *string  `json:"transcript_text,omitempty"`  // Spoken text of the time range, if known
 }
 
 // ToExport converts a Clip to its export representation
@@ -126,7 +242,7 @@ func (c *Clip) ToExport() ClipExport {
 		duration = *c.ClipDuration
 	}
 	return ClipExport{
-		FilePath:          c.GetRelativePath(),
+		FilePath:          c.GetExportPath(),
 		Label:             c.Label,
 		AutoLabeled:       c.AutoLabeled,
 		LabelConfidence:   c.LabelConfidence,
@@ -137,5 +253,8 @@ func (c *Clip) ToExport() ClipExport {
 		OriginalEndTime:   c.OriginalEndTime,
 		UUID:              c.UUID,
 		CreatedAt:         c.CreatedAt.Format(time.RFC3339),
+		HardNegative:      c.IsHardNegative(),
+		RejectionReason:   c.RejectionReason,
+		TranscriptText:    c.TranscriptText,
 	}
 }
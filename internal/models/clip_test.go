@@ -69,6 +69,43 @@ func TestClip_GetOriginalDuration(t *testing.T) {
 	assert.InDelta(t, 15.2, duration, 0.001, "Duration should be approximately 15.2")
 }
 
+func TestClip_Samples(t *testing.T) {
+	clip := Clip{
+		OriginalStartTime: 30.5,
+		OriginalEndTime:   45.70001,
+	}
+
+	assert.Nil(t, clip.Samples(0), "unknown sample rate")
+	assert.Equal(t, &ClipSamples{SampleRate: 44100, StartSample: 1345050, EndSample: 2015370}, clip.Samples(44100))
+	assert.Equal(t, &ClipSamples{SampleRate: 16000, StartSample: 488000, EndSample: 731200}, clip.Samples(16000))
+}
+
+func TestClip_TranscriptExcerpt(t *testing.T) {
+	segments := []TranscriptSegment{
+		{Start: 0, End: 10, Text: " Welcome back."},
+		{Start: 10, End: 14, Text: " This episode is sponsored"},
+		{Start: 14, End: 20, Text: " by Acme. "},
+		{Start: 20, End: 40, Text: " Now, the news."},
+	}
+
+	tests := []struct {
+		name       string
+		start, end float64
+		want       string
+	}{
+		{"whole segments", 10, 20, "This episode is sponsored by Acme."},
+		{"segments cut short are left out", 9, 21, "This episode is sponsored by Acme."},
+		{"clip inside one long segment", 25, 30, "Now, the news."},
+		{"no transcript there", 50, 60, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip := Clip{OriginalStartTime: tt.start, OriginalEndTime: tt.end}
+			assert.Equal(t, tt.want, clip.TranscriptExcerpt(segments))
+		})
+	}
+}
+
 func TestClip_IsReady(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -140,6 +177,59 @@ func TestClip_GetRelativePath(t *testing.T) {
 	}
 }
 
+func TestClip_GetExportPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		clip     Clip
+		wantPath string
+	}{
+		{
+			name: "approved clip keeps its label directory",
+			clip: Clip{
+				Label:        "advertisement",
+				Approved:     true,
+				ClipFilename: stringPtr("clip_abc123.wav"),
+			},
+			wantPath: "advertisement/clip_abc123.wav",
+		},
+		{
+			name: "false positive goes under hard negatives",
+			clip: Clip{
+				Label:           "advertisement",
+				Rejected:        true,
+				RejectionReason: ClipRejectionFalsePositive,
+				ClipFilename:    stringPtr("clip_abc123.wav"),
+			},
+			wantPath: "hard_negatives/advertisement/clip_abc123.wav",
+		},
+		{
+			name: "rejected for other reasons is not a hard negative",
+			clip: Clip{
+				Label:           "advertisement",
+				Rejected:        true,
+				RejectionReason: ClipRejectionBadBoundaries,
+				ClipFilename:    stringPtr("clip_abc123.wav"),
+			},
+			wantPath: "advertisement/clip_abc123.wav",
+		},
+		{
+			name: "not extracted",
+			clip: Clip{
+				Label:           "advertisement",
+				Rejected:        true,
+				RejectionReason: ClipRejectionFalsePositive,
+			},
+			wantPath: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPath, tt.clip.GetExportPath())
+		})
+	}
+}
+
 func TestClip_IsExtracted(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Content flag severities
+const (
+	ContentSeverityMild   = "mild"
+	ContentSeverityStrong = "strong"
+)
+
+// Content analysis timing sources
+const (
+	ContentTimingSegments  = "segments"  // Flag times come from transcript segment timestamps
+	ContentTimingEstimated = "estimated" // Flag times are interpolated from text position over duration
+)
+
+// ContentFlag marks a region of an episode containing explicit language
+type ContentFlag struct {
+	StartTime float64  `json:"start_time"` // Start of region in seconds
+	EndTime   float64  `json:"end_time"`   // End of region in seconds
+	Severity  string   `json:"severity"`   // "mild" or "strong"
+	Terms     []string `json:"terms"`      // Matched terms within the region
+}
+
+// ContentAnalysis stores explicit-language detection results for an episode transcription
+type ContentAnalysis struct {
+	ID uint `gorm:"primarykey" json:"id"`
+
+	// Episode reference (using Podcast Index ID for consistency)
+	PodcastIndexEpisodeID int64 `gorm:"uniqueIndex;not null" json:"podcast_index_episode_id"`
+
+	Explicit  bool           `json:"explicit"`           // True if any strong-severity region was found
+	FlagCount int            `json:"flag_count"`         // Number of flagged regions
+	FlagsData []byte         `gorm:"type:blob" json:"-"` // JSON-encoded []ContentFlag
+	Timing    string         `json:"timing"`             // "segments" or "estimated"
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for ContentAnalysis
+func (ContentAnalysis) TableName() string {
+	return "content_analyses"
+}
+
+// Flags returns the decoded flagged regions
+func (a *ContentAnalysis) Flags() ([]ContentFlag, error) {
+	if len(a.FlagsData) == 0 {
+		return []ContentFlag{}, nil
+	}
+	var flags []ContentFlag
+	if err := json.Unmarshal(a.FlagsData, &flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// SetFlags encodes the flagged regions and updates the summary fields
+func (a *ContentAnalysis) SetFlags(flags []ContentFlag) error {
+	data, err := json.Marshal(flags)
+	if err != nil {
+		return err
+	}
+	a.FlagsData = data
+	a.FlagCount = len(flags)
+	a.Explicit = false
+	for _, flag := range flags {
+		if flag.Severity == ContentSeverityStrong {
+			a.Explicit = true
+			break
+		}
+	}
+	return nil
+}
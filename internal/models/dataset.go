@@ -3,9 +3,46 @@ package models
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Dataset archive layouts. All hold the clip audio in label directories; they
+// differ in the metadata files written beside it. The speech toolkit layouts
+// add their files to the JSONL manifest rather than replacing it.
+const (
+	DatasetFormatJSONL       = "jsonl"       // manifest.jsonl keyed by file_path
+	DatasetFormatAudioFolder = "audiofolder" // Hugging Face audiofolder: metadata.jsonl keyed by file_name
+	DatasetFormatKaldi       = "kaldi"       // manifest.jsonl plus a Kaldi data directory (wav.scp, segments, utt2spk, text)
+	DatasetFormatESPnet      = "espnet"      // manifest.jsonl plus an ESPnet data.json
+)
+
+// DatasetFormats lists the dataset formats, for error messages
+const DatasetFormats = "jsonl, audiofolder, kaldi or espnet"
+
+// IsValidDatasetFormat reports whether format is one of the DatasetFormat* layouts
+func IsValidDatasetFormat(format string) bool {
+	switch format {
+	case DatasetFormatJSONL, DatasetFormatAudioFolder, DatasetFormatKaldi, DatasetFormatESPnet:
+		return true
+	}
+	return false
+}
+
+// Metadata file names for each dataset format
+const (
+	DatasetManifestFile     = "manifest.jsonl"
+	AudioFolderMetadataFile = "metadata.jsonl"
+)
+
+// DatasetMetadataFile returns the metadata file written for a dataset format
+func DatasetMetadataFile(format string) string {
+	if format == DatasetFormatAudioFolder {
+		return AudioFolderMetadataFile
+	}
+	return DatasetManifestFile
+}
+
 // Dataset represents a generated ML dataset
 type Dataset struct {
 	ID        string    `gorm:"primaryKey;size:36" json:"id"`
@@ -18,7 +55,7 @@ type Dataset struct {
 	Label       string `gorm:"not null;size:100" json:"label"` // e.g., "advertisement"
 
 	// Format info
-	Format      string `gorm:"not null;size:50" json:"format"`       // "jsonl" or "audiofolder"
+	Format      string `gorm:"not null;size:50" json:"format"`       // One of the DatasetFormat* layouts
 	AudioFormat string `gorm:"not null;size:50" json:"audio_format"` // "original" or "processed"
 
 	// Statistics
@@ -35,6 +72,16 @@ type Dataset struct {
 	GenerationTimeMs int64  `json:"generation_time_ms"`                       // Generation time in milliseconds
 	FiltersJSON      string `gorm:"type:text" json:"filters_json,omitempty"`  // JSON-encoded filters
 	MetadataJSON     string `gorm:"type:text" json:"metadata_json,omitempty"` // JSON-encoded metadata
+
+	// Selected clips left out for lack of audio
+	SkippedSamples int    `json:"skipped_samples"`
+	SkippedJSON    string `gorm:"type:text" json:"skipped_json,omitempty"` // JSON-encoded skip report
+	RerunJobID     *uint  `json:"rerun_job_id,omitempty"`                  // Regeneration queued behind audio caching
+
+	// Anonymized archives carry pseudonymous IDs; the mapping back to clips,
+	// episodes and podcasts stays on the server
+	Anonymized  bool   `json:"anonymized"`
+	MappingPath string `gorm:"size:500" json:"-"`
 }
 
 // TableName returns the table name for the Dataset model
@@ -50,7 +97,7 @@ func (d *Dataset) BeforeCreate(tx *gorm.DB) error {
 
 	// Generate ID if not set
 	if d.ID == "" {
-		d.ID = generateDatasetID()
+		d.ID = NewDatasetID()
 	}
 
 	return nil
@@ -62,9 +109,35 @@ func (d *Dataset) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
-// generateDatasetID generates a unique dataset ID
-func generateDatasetID() string {
+// NewDatasetID generates a unique dataset ID
+func NewDatasetID() string {
 	// Use timestamp + random suffix for unique ID
 	timestamp := time.Now().Format("20060102-150405")
-	return "ds-" + timestamp
+	return "ds-" + timestamp + "-" + uuid.New().String()[:8]
+}
+
+// DatasetEvaluation is one run of a model's predictions scored against a
+// dataset's labels. The summary metrics are columns so runs can be compared
+// over time; the confusion matrix and per-label metrics are kept as JSON.
+type DatasetEvaluation struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	DatasetID string `gorm:"size:36;not null;index" json:"dataset_id"`
+	Name      string `gorm:"size:255" json:"name,omitempty"`      // Model or run name, e.g. "ads-detector v7"
+	CreatedBy string `gorm:"size:36" json:"created_by,omitempty"` // Supabase user that uploaded the predictions
+
+	Samples  int     `json:"samples"`   // Dataset clips with a prediction
+	Missing  int     `json:"missing"`   // Dataset clips without one
+	Unknown  int     `json:"unknown"`   // Predictions for clips not in the dataset
+	MinScore float64 `json:"min_score"` // Predictions scoring lower counted as no label
+	Accuracy float64 `json:"accuracy"`
+	MacroF1  float64 `json:"macro_f1"`
+
+	ResultJSON string `gorm:"type:text" json:"-"`
+}
+
+// TableName returns the table name for the DatasetEvaluation model
+func (DatasetEvaluation) TableName() string {
+	return "dataset_evaluations"
 }
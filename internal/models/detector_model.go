@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Detector backends, which say how a registered model is run
+const (
+	DetectorBackendBuiltin = "builtin" // Compiled into the server and picked by Name
+	DetectorBackendLocal   = "local"   // An executable on the server at LocalPath
+	DetectorBackendHTTP    = "http"    // An inference service at Endpoint
+)
+
+// What an http model is sent for each chunk of an episode
+const (
+	DetectorInputAudio    = "audio"    // The chunk as 16kHz mono WAV
+	DetectorInputFeatures = "features" // Frame levels of the chunk in dBFS
+)
+
+// DetectorModel is an entry of the model registry: a version of a detector
+// that episode analysis can run to find labeled segments. Clips it creates
+// record its name and version, so they stay attributable after the entry is
+// removed.
+type DetectorModel struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name    string `gorm:"not null;size:100;uniqueIndex:idx_detector_model_version" json:"name"`
+	Version string `gorm:"not null;size:50;uniqueIndex:idx_detector_model_version" json:"version"`
+
+	Backend   string `gorm:"not null;size:20" json:"backend"`
+	Endpoint  string `gorm:"size:500" json:"endpoint,omitempty"`   // URL, for DetectorBackendHTTP
+	LocalPath string `gorm:"size:500" json:"local_path,omitempty"` // Executable, for DetectorBackendLocal
+
+	// Settings of http models; zero values take the defaults of the
+	// analysis service
+	Input          string  `gorm:"size:20" json:"input,omitempty"`      // DetectorInputAudio (the default) or DetectorInputFeatures
+	ChunkSeconds   float64 `json:"chunk_seconds,omitempty"`             // Length of the audio sent per request
+	TimeoutSeconds int     `json:"timeout_seconds,omitempty"`           // Per request
+	TokenEnv       string  `gorm:"size:100" json:"token_env,omitempty"` // Environment variable holding a bearer token
+
+	// Labels the model predicts; detections with any other label are dropped
+	Labels []string `gorm:"serializer:json;type:text" json:"labels"`
+
+	// IsDefault marks the model analysis runs when none is selected; at most one
+	// entry has it
+	IsDefault bool `gorm:"not null;default:false;index" json:"default"`
+
+	// CreatedBy is the Supabase user that registered the model; empty for built-ins
+	CreatedBy string `gorm:"size:36" json:"created_by,omitempty"`
+}
+
+// TableName returns the table name for the DetectorModel model
+func (DetectorModel) TableName() string {
+	return "detector_models"
+}
+
+// Identity returns the model's "name@version"
+func (m *DetectorModel) Identity() string {
+	return m.Name + "@" + m.Version
+}
+
+// PredictsLabel reports whether label is one of the model's labels
+func (m *DetectorModel) PredictsLabel(label string) bool {
+	for _, l := range m.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
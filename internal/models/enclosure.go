@@ -0,0 +1,123 @@
+package models
+
+import (
+	"strings"
+)
+
+// Stream qualities a client can ask for
+const (
+	QualityLow  = "low"
+	QualityHigh = "high"
+)
+
+// Kinds of media an episode's enclosure can be
+const (
+	MediaTypeAudio = "audio"
+	MediaTypeVideo = "video"
+)
+
+// MediaTypeOf returns the kind of media an enclosure MIME type describes.
+// Anything but video counts as audio, including a missing type.
+func MediaTypeOf(enclosureType string) string {
+	if mediaKind(enclosureType) == MediaTypeVideo {
+		return MediaTypeVideo
+	}
+	return MediaTypeAudio
+}
+
+// MediaType returns whether the episode's enclosure is audio or video. Video
+// episodes are processed from their audio track.
+func (e *Episode) MediaType() string {
+	return MediaTypeOf(e.EnclosureType)
+}
+
+// DownloadURL returns where to fetch the episode's audio from: the source that
+// served it after the enclosure URL was last refused, else the enclosure URL
+func (e *Episode) DownloadURL() string {
+	if e.AudioSourceURL != "" {
+		return e.AudioSourceURL
+	}
+	return e.AudioURL
+}
+
+// AlternateEnclosure is another rendition of an episode's media, from the
+// feed's podcast:alternateEnclosure tags
+type AlternateEnclosure struct {
+	URL     string `json:"url"`
+	Type    string `json:"type"`              // MIME type
+	Length  int64  `json:"length,omitempty"`  // Bytes
+	Bitrate int    `json:"bitrate,omitempty"` // Bits per second
+	Height  int    `json:"height,omitempty"`  // Pixels, for video
+	Lang    string `json:"lang,omitempty"`    // Language, when it differs from the feed's
+	Title   string `json:"title,omitempty"`   // e.g. "Low bandwidth"
+	Codecs  string `json:"codecs,omitempty"`  // RFC 6381 codecs string
+	Default bool   `json:"default,omitempty"` // The feed's preferred rendition
+}
+
+// Rendition is one URL an episode's media can be played from
+type Rendition struct {
+	URL       string
+	Type      string
+	Bitrate   int  // Bits per second; 0 when unknown
+	Alternate bool // From an alternate enclosure rather than the enclosure
+}
+
+// Renditions returns the enclosure followed by the alternate enclosures of
+// the same kind of media. The enclosure's bitrate is estimated from its
+// length and the feed's duration.
+func (e *Episode) Renditions() []Rendition {
+	enclosure := Rendition{URL: e.AudioURL, Type: e.EnclosureType}
+	if e.EnclosureLength > 0 {
+		duration := e.Duration
+		if e.DurationProbed && e.FeedDuration != nil {
+			duration = e.FeedDuration
+		}
+		if duration != nil && *duration > 0 {
+			enclosure.Bitrate = int(e.EnclosureLength * 8 / int64(*duration))
+		}
+	}
+
+	renditions := []Rendition{enclosure}
+	kind := mediaKind(e.EnclosureType)
+	if kind == "" {
+		kind = "audio"
+	}
+	for _, alt := range e.AlternateEnclosures {
+		if alt.URL == "" || alt.URL == e.AudioURL || mediaKind(alt.Type) != kind {
+			continue
+		}
+		renditions = append(renditions, Rendition{URL: alt.URL, Type: alt.Type, Bitrate: alt.Bitrate, Alternate: true})
+	}
+	return renditions
+}
+
+// RenditionFor picks the rendition for a stream quality: the lowest or the
+// highest bitrate. An enclosure of unknown bitrate counts as the highest,
+// since feeds publish their full-quality file as the enclosure. Any other
+// quality, and ties, get the enclosure.
+func (e *Episode) RenditionFor(quality string) Rendition {
+	renditions := e.Renditions()
+	best := renditions[0]
+	for _, r := range renditions[1:] {
+		if r.Bitrate <= 0 {
+			continue
+		}
+		switch quality {
+		case QualityLow:
+			if best.Bitrate <= 0 || r.Bitrate < best.Bitrate {
+				best = r
+			}
+		case QualityHigh:
+			if best.Bitrate > 0 && r.Bitrate > best.Bitrate {
+				best = r
+			}
+		}
+	}
+	return best
+}
+
+// mediaKind returns the top-level MIME type, e.g. "audio" for "audio/mpeg"
+func mediaKind(mimeType string) string {
+	kind, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(mimeType)), "/")
+	return kind
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpisode_RenditionFor(t *testing.T) {
+	duration := 1000
+	episode := &Episode{
+		AudioURL:        "https://cdn.example.com/ep.mp3",
+		EnclosureType:   "audio/mpeg",
+		EnclosureLength: 16_000_000, // 128 kbps over 1000s
+		Duration:        &duration,
+		AlternateEnclosures: []AlternateEnclosure{
+			{URL: "https://cdn.example.com/ep-64k.mp3", Type: "audio/mpeg", Bitrate: 64000},
+			{URL: "https://cdn.example.com/ep-256k.m4a", Type: "audio/mp4", Bitrate: 256000},
+			{URL: "https://cdn.example.com/ep.mp4", Type: "video/mp4", Bitrate: 32000},
+			{URL: "https://cdn.example.com/ep-unknown.opus", Type: "audio/opus"},
+		},
+	}
+
+	assert.Equal(t, "https://cdn.example.com/ep.mp3", episode.RenditionFor("").URL)
+	assert.False(t, episode.RenditionFor("").Alternate)
+	assert.Equal(t, "https://cdn.example.com/ep-64k.mp3", episode.RenditionFor(QualityLow).URL, "video and unknown bitrates are skipped")
+	assert.Equal(t, "https://cdn.example.com/ep-256k.m4a", episode.RenditionFor(QualityHigh).URL)
+	assert.True(t, episode.RenditionFor(QualityHigh).Alternate)
+
+	// Without a length the enclosure's bitrate is unknown and counts as the highest
+	episode.EnclosureLength = 0
+	assert.Equal(t, "https://cdn.example.com/ep.mp3", episode.RenditionFor(QualityHigh).URL)
+	assert.Equal(t, "https://cdn.example.com/ep-64k.mp3", episode.RenditionFor(QualityLow).URL)
+
+	assert.Len(t, (&Episode{AudioURL: "https://cdn.example.com/ep.mp3"}).Renditions(), 1)
+}
+
+func TestMediaTypeOf(t *testing.T) {
+	assert.Equal(t, MediaTypeVideo, MediaTypeOf("video/mp4"))
+	assert.Equal(t, MediaTypeVideo, MediaTypeOf("Video/Quicktime"))
+	assert.Equal(t, MediaTypeAudio, MediaTypeOf("audio/mpeg"))
+	assert.Equal(t, MediaTypeAudio, MediaTypeOf(""))
+}
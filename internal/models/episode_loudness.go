@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// EpisodeLoudness is the EBU R128 measurement of an episode's cached
+// original audio, for volume leveling across shows
+type EpisodeLoudness struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"measured_at"`
+
+	PodcastIndexEpisodeID int64  `json:"podcast_index_episode_id" gorm:"not null;uniqueIndex"`
+	SourceSHA256          string `json:"-" gorm:"size:64"` // Of the audio measured; a new download is measured again
+
+	IntegratedLUFS float64 `json:"integrated_lufs"` // Integrated loudness
+	TruePeakDBTP   float64 `json:"true_peak_dbtp"`  // Maximum true peak
+	RangeLU        float64 `json:"range_lu"`        // Loudness range (LRA)
+	ThresholdLUFS  float64 `json:"threshold_lufs"`  // Relative gate of the integrated measurement
+}
+
+// TableName returns the table name for the EpisodeLoudness model
+func (EpisodeLoudness) TableName() string {
+	return "episode_loudness"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// EpisodeRead records that a user has read (seen or dismissed) an episode in
+// their inbox. Episodes without a row are unread.
+type EpisodeRead struct {
+	ID                    uint      `gorm:"primarykey" json:"-"`
+	UserID                string    `gorm:"not null;size:36;uniqueIndex:idx_episode_read_user_episode" json:"-"` // Supabase UUID
+	PodcastIndexEpisodeID int64     `gorm:"not null;uniqueIndex:idx_episode_read_user_episode" json:"episode_id"`
+	ReadAt                time.Time `gorm:"not null" json:"read_at"`
+}
+
+// TableName specifies the table name for EpisodeRead
+func (EpisodeRead) TableName() string {
+	return "episode_reads"
+}
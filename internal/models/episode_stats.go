@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EpisodeStats holds analysis figures for one episode. Rows are refreshed as
+// jobs for the episode complete, and podcast analytics are summed from them.
+type EpisodeStats struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	PodcastIndexEpisodeID int64 `gorm:"uniqueIndex;not null" json:"podcast_index_episode_id"`
+	PodcastIndexFeedID    int64 `gorm:"not null;index" json:"podcast_index_feed_id"`
+
+	DurationSeconds float64 `json:"duration_seconds"` // 0 when the duration is unknown
+	AdSeconds       float64 `json:"ad_seconds"`       // Union of approved ad clip ranges
+	HasTranscript   bool    `json:"has_transcript"`
+	LabelCountsData []byte  `gorm:"type:blob" json:"-"` // JSON-encoded map of clip label to count
+	ClipCount       int     `json:"clip_count"`         // Clips that were not rejected
+}
+
+// TableName specifies the table name for EpisodeStats
+func (EpisodeStats) TableName() string {
+	return "episode_stats"
+}
+
+// LabelCounts returns the decoded clip label counts
+func (s *EpisodeStats) LabelCounts() (map[string]int, error) {
+	counts := map[string]int{}
+	if len(s.LabelCountsData) == 0 {
+		return counts, nil
+	}
+	if err := json.Unmarshal(s.LabelCountsData, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// SetLabelCounts encodes and sets the clip label counts
+func (s *EpisodeStats) SetLabelCounts(counts map[string]int) error {
+	data, err := json.Marshal(counts)
+	if err != nil {
+		return err
+	}
+	s.LabelCountsData = data
+	return nil
+}
@@ -0,0 +1,11 @@
+package models
+
+// EpisodeStatus is the processing state shown next to an episode in lists. It is
+// aggregated from waveforms, transcriptions and clips on read, not stored.
+type EpisodeStatus struct {
+	PodcastIndexEpisodeID int64
+	HasWaveform           bool
+	HasTranscript         bool
+	ClipCount             int // Clips that were not rejected
+	ApprovedClipCount     int
+}
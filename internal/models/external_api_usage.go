@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ExternalAPIUsage counts one day's calls to one endpoint of an external API,
+// such as Podcast Index, for watching quota use
+type ExternalAPIUsage struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"last_call_at"`
+
+	Day      string `json:"day" gorm:"size:10;not null;uniqueIndex:idx_external_usage_day_endpoint"` // UTC date, YYYY-MM-DD
+	Service  string `json:"service" gorm:"size:50;not null;uniqueIndex:idx_external_usage_day_endpoint"`
+	Endpoint string `json:"endpoint" gorm:"size:100;not null;uniqueIndex:idx_external_usage_day_endpoint"`
+
+	Calls          int64 `json:"calls"`            // Calls made, failed ones included
+	Errors         int64 `json:"errors"`           // Calls that failed
+	Rejected       int64 `json:"rejected"`         // Calls not made because the daily budget was spent
+	TotalLatencyMs int64 `json:"total_latency_ms"` // Summed over Calls
+	MaxLatencyMs   int64 `json:"max_latency_ms"`
+}
+
+// TableName returns the table name for the ExternalAPIUsage model
+func (ExternalAPIUsage) TableName() string {
+	return "external_api_usage"
+}
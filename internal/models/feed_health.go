@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// FeedHealthStatus summarizes whether a podcast's feed and audio can still be fetched
+type FeedHealthStatus string
+
+const (
+	FeedHealthy  FeedHealthStatus = "healthy"
+	FeedDegraded FeedHealthStatus = "degraded" // Recent fetches keep failing
+	FeedDead     FeedHealthStatus = "dead"     // Failing for long enough, or gone from Podcast Index
+)
+
+// FeedFailureKind classifies a failed fetch
+type FeedFailureKind string
+
+const (
+	FeedFailureAudioGone  FeedFailureKind = "audio_gone"  // Enclosure answered 404 or 410
+	FeedFailureAudioError FeedFailureKind = "audio_error" // Any other failed enclosure download
+	FeedFailureFeedGone   FeedFailureKind = "feed_gone"   // Podcast Index reports the feed dead or no longer knows it
+)
+
+// FeedHealth tracks fetch failures for one podcast. Rows are created on the
+// first failure; podcasts without one are healthy.
+type FeedHealth struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	PodcastIndexFeedID int64            `gorm:"uniqueIndex;not null" json:"podcast_index_feed_id"`
+	Status             FeedHealthStatus `gorm:"not null;size:16;index;default:healthy" json:"status"`
+
+	ConsecutiveFailures int             `json:"consecutive_failures"` // Reset by a successful fetch
+	TotalFailures       int             `json:"total_failures"`
+	FailingSince        *time.Time      `json:"failing_since,omitempty"` // First failure of the current streak
+	LastFailureAt       *time.Time      `json:"last_failure_at,omitempty"`
+	LastFailureKind     FeedFailureKind `gorm:"size:16" json:"last_failure_kind,omitempty"`
+	LastStatusCode      int             `json:"last_status_code,omitempty"` // HTTP status of the last failure, if any
+	LastError           string          `gorm:"size:500" json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time      `json:"last_success_at,omitempty"`
+}
+
+// TableName specifies the table name for FeedHealth
+func (FeedHealth) TableName() string {
+	return "feed_health"
+}
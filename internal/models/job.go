@@ -31,6 +31,16 @@ const (
 	JobTypePodcastSync             JobType = "podcast_sync"
 	JobTypeClipExtraction          JobType = "clip_extraction"
 	JobTypeAutoLabel               JobType = "autolabel"
+	JobTypeSummaryGeneration       JobType = "summary_generation"
+	JobTypeUserExport              JobType = "user_export"
+	JobTypeAccountDeletion         JobType = "account_deletion"
+	JobTypeDatasetGeneration       JobType = "dataset_generation"
+	JobTypeEpisodeAnalysis         JobType = "episode_analysis"
+	JobTypeAudioCache              JobType = "audio_cache"
+	JobTypeClipReextraction        JobType = "clip_reextraction"
+	JobTypeLibraryImport           JobType = "library_import"
+	JobTypeLiveItemRefresh         JobType = "live_item_refresh"
+	JobTypeClipVerification        JobType = "clip_verification"
 )
 
 // JobErrorType represents the category of error that occurred
@@ -41,8 +51,24 @@ const (
 	ErrorTypeProcessing JobErrorType = "processing" // FFmpeg/audio processing failed
 	ErrorTypeSystem     JobErrorType = "system"     // Database, worker, or other system error
 	ErrorTypeNotFound   JobErrorType = "not_found"  // Resource permanently not found
+	// Process exceeded a CPU, output size or similar limit
+	ErrorTypeResourceLimit JobErrorType = "resource_limit"
+	// A job this one depends on failed or was cancelled, so it can never start
+	ErrorTypeDependency JobErrorType = "dependency"
+	// The server is misconfigured, e.g. rejected credentials; only an operator can fix it
+	ErrorTypeConfiguration JobErrorType = "configuration"
 )
 
+// Retryable reports whether a job failing with this type of error is retried.
+// Retrying these would fail the same way.
+func (t JobErrorType) Retryable() bool {
+	switch t {
+	case ErrorTypeNotFound, ErrorTypeResourceLimit, ErrorTypeDependency, ErrorTypeConfiguration:
+		return false
+	}
+	return true
+}
+
 // StructuredJobError represents a structured error with classification information
 type StructuredJobError struct {
 	Type     JobErrorType
@@ -100,6 +126,31 @@ func NewNotFoundError(code, message, details string, originalErr error) *Structu
 	}
 }
 
+// NewResourceLimitError creates an error for a process killed by a resource limit.
+// Retrying would hit the same limit, so these fail permanently.
+func NewResourceLimitError(code, message, details string, originalErr error) *StructuredJobError {
+	return &StructuredJobError{
+		Type:     ErrorTypeResourceLimit,
+		Code:     code,
+		Message:  message,
+		Details:  details,
+		Original: originalErr,
+	}
+}
+
+// NewConfigurationError creates an error for a job the server can't run as
+// configured. Retrying fails the same way until an operator steps in, so these
+// fail permanently.
+func NewConfigurationError(code, message, details string, originalErr error) *StructuredJobError {
+	return &StructuredJobError{
+		Type:     ErrorTypeConfiguration,
+		Code:     code,
+		Message:  message,
+		Details:  details,
+		Original: originalErr,
+	}
+}
+
 // Job represents a background job in the queue
 type Job struct {
 	gorm.Model
@@ -116,6 +167,9 @@ type Job struct {
 	Error        string     `json:"error,omitempty"`
 	Result       JobResult  `json:"result,omitempty" gorm:"type:json"`
 	WorkerID     string     `json:"worker_id,omitempty"` // ID of the worker processing this job
+	// HeartbeatAt is refreshed by the worker while it processes the job; a
+	// processing job whose heartbeat stops is presumed abandoned
+	HeartbeatAt *time.Time `json:"heartbeat_at,omitempty"`
 
 	// Error classification fields
 	ErrorType    string `json:"error_type,omitempty"`    // "download", "processing", "system"
@@ -124,6 +178,33 @@ type Job struct {
 
 	// Metadata
 	CreatedBy string `json:"created_by,omitempty"` // Optional user/system identifier
+
+	// TraceContext carries the enqueuing request's trace (W3C trace context as
+	// JSON) so the job's spans join it
+	TraceContext string `json:"-" gorm:"type:text"`
+
+	// DedupKey names the work a unique enqueue asked for ("type:key=value"). The
+	// partial index makes it unique among live jobs only, so concurrent enqueues
+	// for the same episode collapse into one job while finished ones stay as history.
+	DedupKey *string `json:"-" gorm:"size:255;uniqueIndex:idx_jobs_live_dedup,where:dedup_key IS NOT NULL AND deleted_at IS NULL AND status <> 'completed' AND status <> 'cancelled' AND status <> 'permanently_failed'"`
+
+	// DependsOn lists the jobs that must complete before this one is claimed.
+	// It is stored as JobDependency rows and loaded with the job.
+	DependsOn []uint `json:"depends_on,omitempty" gorm:"-"`
+}
+
+// JobDependency is an edge in the job graph: JobID stays pending until
+// DependsOnID has completed, and fails with it if it fails for good
+type JobDependency struct {
+	ID          uint      `gorm:"primarykey"`
+	JobID       uint      `gorm:"not null;uniqueIndex:idx_job_dependencies_edge"`
+	DependsOnID uint      `gorm:"not null;uniqueIndex:idx_job_dependencies_edge;index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (JobDependency) TableName() string {
+	return "job_dependencies"
 }
 
 // JobPayload represents the input data for a job
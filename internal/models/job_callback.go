@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// JobCallback is a URL to call when a job finishes. Several requests can share
+// one deduplicated job, so each registers its own callback row.
+type JobCallback struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	JobID uint   `gorm:"not null;index" json:"job_id"`
+	URL   string `gorm:"not null;size:2048" json:"url"`
+
+	// Delivery state; NotifiedAt is set when delivery starts, so a callback
+	// registered as its job finishes is sent once rather than twice, and
+	// DeliveredAt stays nil until the receiver answers 2xx
+	NotifiedAt  *time.Time `json:"notified_at,omitempty"`
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	LastError   string     `gorm:"size:500" json:"last_error,omitempty"`
+}
+
+// TableName returns the table name for the JobCallback model
+func (JobCallback) TableName() string {
+	return "job_callbacks"
+}
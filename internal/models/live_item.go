@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Live item statuses, from the feed's podcast:liveItem status attribute
+const (
+	LiveItemStatusPending = "pending"
+	LiveItemStatusLive    = "live"
+	LiveItemStatusEnded   = "ended"
+)
+
+// LiveItem is a live stream a podcast's feed announces with podcast:liveItem.
+// A podcast's items are replaced wholesale each time Podcast Index is asked.
+type LiveItem struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	PodcastIndexID     int64 `gorm:"index" json:"podcast_index_id"`
+	PodcastIndexFeedID int64 `gorm:"not null;index:idx_live_item_feed_start,priority:1" json:"podcast_index_feed_id"`
+
+	GUID        string `gorm:"size:512" json:"guid,omitempty"`
+	Title       string `gorm:"size:512" json:"title"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+	Status      string `gorm:"size:16;not null" json:"status"`
+
+	StartTime time.Time  `gorm:"index:idx_live_item_feed_start,priority:2" json:"start_time"`
+	EndTime   *time.Time `json:"end_time,omitempty"` // Unset when the feed leaves it open-ended
+
+	EnclosureURL  string `gorm:"size:2048" json:"enclosure_url,omitempty"` // The stream itself
+	EnclosureType string `gorm:"size:100" json:"enclosure_type,omitempty"`
+	ContentLink   string `gorm:"size:2048" json:"content_link,omitempty"` // Page the stream can be watched on, e.g. a YouTube live
+	Image         string `gorm:"size:2048" json:"image,omitempty"`
+}
+
+// TableName specifies the table name for LiveItem
+func (LiveItem) TableName() string {
+	return "live_items"
+}
+
+// IsLive reports whether the stream is broadcasting
+func (l *LiveItem) IsLive() bool {
+	return l.Status == LiveItemStatusLive
+}
+
+// LiveItemSchedule tracks when a podcast's live items were last fetched and
+// when their statuses are next due a check
+type LiveItemSchedule struct {
+	PodcastIndexFeedID int64      `gorm:"primaryKey;autoIncrement:false"`
+	RefreshedAt        time.Time  `gorm:"not null"`
+	NextRefreshAt      *time.Time `gorm:"index"`              // Unset when nothing is live or about to start
+	Failures           int        `gorm:"not null;default:0"` // Status checks failed in a row; each backs the next one off further
+}
+
+// TableName specifies the table name for LiveItemSchedule
+func (LiveItemSchedule) TableName() string {
+	return "live_item_schedules"
+}
@@ -80,7 +80,14 @@ type Episode struct {
 	AudioURL        string `json:"audio_url" gorm:"not null"`
 	EnclosureType   string `json:"enclosure_type"`
 	EnclosureLength int64  `json:"enclosure_length"`
-	Duration        *int   `json:"duration"` // Duration in seconds, nullable
+	Duration        *int   `json:"duration"`                // Duration in seconds, nullable; measured once the audio is cached
+	FeedDuration    *int   `json:"feed_duration,omitempty"` // Duration the feed claims, kept once Duration is measured
+	DurationProbed  bool   `json:"duration_probed"`         // Duration comes from ffprobe rather than the feed
+	// Where the audio was last downloaded from when the enclosure URL was
+	// refused; kept here so it outlives the audio cache entry
+	AudioSourceURL string `json:"audio_source_url,omitempty"`
+
+	AlternateEnclosures []AlternateEnclosure `json:"alternate_enclosures,omitempty" gorm:"serializer:json;type:text"`
 
 	// Timestamps
 	PublishedAt time.Time `json:"published_at" gorm:"index"`
@@ -114,7 +121,16 @@ type Episode struct {
 // Note: UserID now references Supabase user UUID
 type Subscription struct {
 	gorm.Model
-	UserID    string  `json:"user_id" gorm:"not null;size:36;index"` // Supabase UUID
-	PodcastID uint    `json:"podcast_id" gorm:"not null"`
+	UserID    string  `json:"user_id" gorm:"not null;size:36;index;uniqueIndex:idx_subscription_user_podcast,where:deleted_at IS NULL"` // Supabase UUID
+	PodcastID uint    `json:"podcast_id" gorm:"not null;uniqueIndex:idx_subscription_user_podcast,where:deleted_at IS NULL"`
 	Podcast   Podcast `json:"podcast,omitempty" gorm:"foreignKey:PodcastID"`
+
+	// Organization chosen by the user: one folder, any number of tags
+	Folder string `json:"folder" gorm:"size:50;index"`
+	Tags   string `json:"tags" gorm:"size:500"` // Comma-separated, lowercase
+}
+
+// TagList returns the subscription's tags
+func (s *Subscription) TagList() []string {
+	return splitList(s.Tags)
 }
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// NotificationType identifies the event a notification reports
+type NotificationType string
+
+const (
+	NotificationNewEpisodes  NotificationType = "new_episodes"  // New episodes of a subscribed podcast
+	NotificationJobCompleted NotificationType = "job_completed" // A job the user started has finished
+	NotificationExportReady  NotificationType = "export_ready"  // A personal data export can be downloaded
+	NotificationSavedSearch  NotificationType = "saved_search"  // A saved search found new podcasts
+)
+
+// Notification is an entry in a user's in-app notification feed
+type Notification struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"` // Indexed for retention purges
+
+	// Owner (Supabase user UUID)
+	UserID string `gorm:"not null;size:36;index:idx_notifications_user_read" json:"-"`
+
+	Type   NotificationType  `gorm:"not null;size:32" json:"type"`
+	Title  string            `gorm:"not null;size:255" json:"title"`
+	Body   string            `gorm:"type:text" json:"body,omitempty"`
+	Data   datatypes.JSONMap `gorm:"type:json" json:"data,omitempty"` // Type-specific identifiers, e.g. job_id or episode IDs
+	ReadAt *time.Time        `gorm:"index:idx_notifications_user_read" json:"read_at,omitempty"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Person is a host, guest or other contributor credited via podcast:person tags
+type Person struct {
+	ID uint `gorm:"primarykey" json:"id"`
+
+	// Key deduplicates people across feeds: lowercased name plus profile link
+	Key       string    `gorm:"uniqueIndex;not null;size:512" json:"-"`
+	Name      string    `gorm:"not null" json:"name"`
+	Href      string    `json:"href,omitempty"` // Profile or homepage URL
+	Img       string    `json:"img,omitempty"`  // Headshot URL
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Person
+func (Person) TableName() string {
+	return "people"
+}
+
+// PersonKey builds the deduplication key for a person
+func PersonKey(name, href string) string {
+	return strings.ToLower(strings.TrimSpace(name)) + "|" + strings.ToLower(strings.TrimSpace(href))
+}
+
+// EpisodePerson links a person to an episode with their role in it
+type EpisodePerson struct {
+	PodcastIndexEpisodeID int64  `gorm:"primaryKey;autoIncrement:false" json:"podcast_index_episode_id"`
+	PersonID              uint   `gorm:"primaryKey;autoIncrement:false;index" json:"person_id"`
+	Role                  string `gorm:"primaryKey" json:"role"` // e.g. "host", "guest" (lowercased)
+	Group                 string `json:"group"`                  // e.g. "cast", "writing"
+	Person                Person `gorm:"foreignKey:PersonID" json:"person"`
+}
+
+// TableName specifies the table name for EpisodePerson
+func (EpisodePerson) TableName() string {
+	return "episode_people"
+}
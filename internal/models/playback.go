@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// PlaybackProgress tracks how far a user has listened into an episode
+type PlaybackProgress struct {
+	ID uint `gorm:"primarykey" json:"id"`
+
+	// Owner (Supabase user UUID) and episode (Podcast Index ID); one row per pair
+	UserID                string `gorm:"not null;size:36;uniqueIndex:idx_playback_user_episode" json:"user_id"`
+	PodcastIndexEpisodeID int64  `gorm:"not null;uniqueIndex:idx_playback_user_episode" json:"podcast_index_episode_id"`
+
+	Position    float64    `json:"position"`               // Last reported position in seconds
+	Duration    float64    `json:"duration"`               // Episode duration in seconds as reported by the client
+	Completed   bool       `gorm:"index" json:"completed"` // Set once the episode has been played through
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ReportedAt  time.Time  `json:"reported_at"` // When the client last reported progress
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for PlaybackProgress
+func (PlaybackProgress) TableName() string {
+	return "playback_progress"
+}
+
+// ListeningHistory records each time a user finishes an episode
+type ListeningHistory struct {
+	ID                    uint      `gorm:"primarykey" json:"id"`
+	UserID                string    `gorm:"not null;size:36;index:idx_history_user_completed" json:"user_id"`
+	PodcastIndexEpisodeID int64     `gorm:"not null;index" json:"podcast_index_episode_id"`
+	PodcastIndexFeedID    int64     `gorm:"index" json:"podcast_index_feed_id"` // 0 if the episode was not stored locally
+	Duration              float64   `json:"duration"`                           // Episode duration in seconds
+	CompletedAt           time.Time `gorm:"index:idx_history_user_completed" json:"completed_at"`
+}
+
+// TableName specifies the table name for ListeningHistory
+func (ListeningHistory) TableName() string {
+	return "listening_history"
+}
+
+// DailyListening aggregates a user's listening per UTC day so stats stay cheap to compute
+type DailyListening struct {
+	UserID            string  `gorm:"primaryKey;size:36" json:"-"`
+	Day               string  `gorm:"primaryKey;size:10" json:"day"` // YYYY-MM-DD (UTC)
+	SecondsListened   float64 `json:"seconds_listened"`
+	EpisodesCompleted int     `json:"episodes_completed"`
+}
+
+// TableName specifies the table name for DailyListening
+func (DailyListening) TableName() string {
+	return "daily_listening"
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Playback event types reported by clients
+const (
+	PlaybackEventPlay     = "play"
+	PlaybackEventPause    = "pause"
+	PlaybackEventSeek     = "seek"
+	PlaybackEventComplete = "complete"
+	PlaybackEventSkipAd   = "skip_ad"
+)
+
+// PlaybackEvent is one client analytics event. Rows are only ever inserted,
+// and deleted once they pass the retention period.
+type PlaybackEvent struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	ReceivedAt time.Time `gorm:"not null" json:"received_at"`
+	OccurredAt time.Time `gorm:"not null;index;index:idx_playback_events_feed_occurred,priority:2" json:"occurred_at"` // Client time, clamped to ReceivedAt
+
+	UserID                string `gorm:"size:36;index" json:"user_id,omitempty"` // Empty for anonymous listeners
+	SessionID             string `gorm:"size:64" json:"session_id,omitempty"`    // Client-generated playback session
+	PodcastIndexEpisodeID int64  `gorm:"not null;index" json:"podcast_index_episode_id"`
+	PodcastIndexFeedID    int64  `gorm:"index:idx_playback_events_feed_occurred,priority:1" json:"podcast_index_feed_id"` // 0 when the episode isn't stored locally
+
+	Type         string  `gorm:"size:16;not null" json:"type"`
+	Position     float64 `json:"position"`                // Seconds into the episode when the event happened; the target of a seek or skip
+	FromPosition float64 `json:"from_position,omitempty"` // Where a seek or skip started
+
+	// Weight is the number of events this row stands for, 1/sample rate at ingestion,
+	// so totals stay unbiased when the sample rate changes
+	Weight float64 `gorm:"not null;default:1" json:"weight"`
+}
+
+// TableName specifies the table name for PlaybackEvent
+func (PlaybackEvent) TableName() string {
+	return "playback_events"
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Uses of a podcast's audio that PodcastRights can allow
+const (
+	RightsUseTraining       = "training"       // Training and evaluating models
+	RightsUseDerivatives    = "derivatives"    // Cutting clips and building datasets from the audio
+	RightsUseRedistribution = "redistribution" // Sharing exported clips outside the server
+)
+
+// PodcastRights records the terms a podcast's audio may be used under. Clips
+// and datasets inherit them from the podcast their episode belongs to; a
+// podcast without a row has no recorded restrictions.
+type PodcastRights struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	PodcastIndexFeedID int64  `gorm:"not null;uniqueIndex" json:"podcast_index_feed_id"`
+	License            string `gorm:"size:255" json:"license,omitempty"`        // SPDX identifier or URL
+	FairUseNote        string `gorm:"size:1000" json:"fair_use_note,omitempty"` // Why use without a license is fair, if it is
+
+	// Allowed uses; stored without defaults so a false always sticks
+	AllowTraining       bool `json:"allow_training"`
+	AllowDerivatives    bool `json:"allow_derivatives"`
+	AllowRedistribution bool `json:"allow_redistribution"`
+
+	// UpdatedBy is the Supabase user that last changed the rights
+	UpdatedBy string `gorm:"size:36" json:"updated_by,omitempty"`
+}
+
+// TableName returns the table name for the PodcastRights model
+func (PodcastRights) TableName() string {
+	return "podcast_rights"
+}
+
+// AllowedUses lists the RightsUse* values the rights allow
+func (r *PodcastRights) AllowedUses() []string {
+	uses := []string{}
+	if r.AllowTraining {
+		uses = append(uses, RightsUseTraining)
+	}
+	if r.AllowDerivatives {
+		uses = append(uses, RightsUseDerivatives)
+	}
+	if r.AllowRedistribution {
+		uses = append(uses, RightsUseRedistribution)
+	}
+	return uses
+}
+
+// ExportRestriction explains why clips of the podcast can't be exported into a
+// dataset, or returns "" when they can. Clips are derivatives of the audio and
+// datasets exist for training, so both uses must be allowed.
+func (r *PodcastRights) ExportRestriction() string {
+	switch {
+	case !r.AllowDerivatives:
+		return "podcast is marked no-derivatives"
+	case !r.AllowTraining:
+		return "podcast does not allow training use"
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// UserPreferences stores client settings shared across a user's devices
+type UserPreferences struct {
+	ID uint `gorm:"primarykey" json:"-"`
+
+	// Owner (Supabase user UUID); one row per user
+	UserID string `gorm:"uniqueIndex;not null;size:36" json:"-"`
+
+	PlaybackSpeed      float64   `json:"playback_speed"`       // Multiplier, e.g. 1.25
+	SkipForwardSeconds int       `json:"skip_forward_seconds"` // Seconds jumped by the forward button
+	SkipBackSeconds    int       `json:"skip_back_seconds"`    // Seconds jumped by the back button
+	Theme              string    `json:"theme"`                // "system", "light" or "dark"
+	ExtrasData         []byte    `gorm:"type:blob" json:"-"`   // JSON-encoded free-form client settings
+	UpdatedAt          time.Time `json:"updated_at"`
+	CreatedAt          time.Time `json:"-"`
+
+	// Discovery filters applied to search and trending; comma-separated
+	Languages          string `gorm:"size:255" json:"languages,omitempty"`            // Language codes, e.g. "en,es"
+	ExcludedCategories string `gorm:"size:1024" json:"excluded_categories,omitempty"` // Category names or IDs to hide
+}
+
+// TableName specifies the table name for UserPreferences
+func (UserPreferences) TableName() string {
+	return "user_preferences"
+}
+
+// Extras returns the decoded free-form settings
+func (p *UserPreferences) Extras() (map[string]interface{}, error) {
+	extras := map[string]interface{}{}
+	if len(p.ExtrasData) == 0 {
+		return extras, nil
+	}
+	if err := json.Unmarshal(p.ExtrasData, &extras); err != nil {
+		return nil, err
+	}
+	return extras, nil
+}
+
+// SetExtras encodes the free-form settings
+func (p *UserPreferences) SetExtras(extras map[string]interface{}) error {
+	data, err := json.Marshal(extras)
+	if err != nil {
+		return err
+	}
+	p.ExtrasData = data
+	return nil
+}
+
+// LanguageList returns the preferred language codes, empty when unset
+func (p *UserPreferences) LanguageList() []string {
+	return splitList(p.Languages)
+}
+
+// ExcludedCategoryList returns the categories hidden from discovery, empty when unset
+func (p *UserPreferences) ExcludedCategoryList() []string {
+	return splitList(p.ExcludedCategories)
+}
+
+func splitList(joined string) []string {
+	if joined == "" {
+		return []string{}
+	}
+	return strings.Split(joined, ",")
+}
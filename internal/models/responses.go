@@ -10,6 +10,7 @@ type EpisodeResponse struct {
 	TotalCount  int                    `json:"totalCount"`
 	Max         string                 `json:"max,omitempty"`    // Max results parameter used (string to match PodcastIndex API)
 	Lang        string                 `json:"lang,omitempty"`   // Language parameter used
+	Cat         []string               `json:"cat,omitempty"`    // Included categories
 	NotCat      []string               `json:"notcat,omitempty"` // Excluded categories
 	Description string                 `json:"description,omitempty"`
 }
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// SavedSearch is a Podcast Index search a user asked to have re-run on a
+// schedule. Each run records podcasts it hasn't returned before.
+type SavedSearch struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Owner (Supabase user UUID)
+	UserID string `gorm:"not null;size:36;index" json:"-"`
+
+	Name string `gorm:"size:100" json:"name"`
+
+	// Search parameters, as in POST /api/v1/search
+	Query         string `gorm:"not null;size:255" json:"query"`
+	Limit         int    `json:"limit"`
+	FullText      bool   `json:"full_text"`
+	Val           string `gorm:"size:32" json:"val,omitempty"`
+	ApOnly        bool   `json:"ap_only"`
+	Clean         bool   `json:"clean"`
+	Languages     string `gorm:"size:255" json:"languages,omitempty"`       // Comma-separated language codes
+	NotCategories string `gorm:"size:1024" json:"not_categories,omitempty"` // Comma-separated category names or IDs
+
+	IntervalMinutes int    `json:"interval_minutes"`                       // Time between runs
+	Notify          bool   `json:"notify"`                                 // Post an in-app notification when a run finds podcasts
+	WebhookURL      string `gorm:"size:2048" json:"webhook_url,omitempty"` // Also POST new results here
+
+	NextRunAt time.Time  `gorm:"index" json:"next_run_at"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastError string     `gorm:"size:500" json:"last_error,omitempty"` // Cleared by the next successful run
+}
+
+// TableName specifies the table name for SavedSearch
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// LanguageList returns the language filter, empty when unset
+func (s *SavedSearch) LanguageList() []string {
+	return splitList(s.Languages)
+}
+
+// NotCategoryList returns the excluded categories, empty when unset
+func (s *SavedSearch) NotCategoryList() []string {
+	return splitList(s.NotCategories)
+}
+
+// SavedSearchResult is a podcast a saved search returned, recorded the first
+// time it appeared
+type SavedSearchResult struct {
+	ID                 uint      `gorm:"primarykey" json:"id"`
+	SavedSearchID      uint      `gorm:"not null;uniqueIndex:idx_saved_search_feed;index:idx_saved_search_found,priority:1" json:"-"`
+	PodcastIndexFeedID int64     `gorm:"not null;uniqueIndex:idx_saved_search_feed" json:"podcast_index_feed_id"`
+	FoundAt            time.Time `gorm:"not null;index:idx_saved_search_found,priority:2" json:"found_at"`
+	Initial            bool      `json:"initial"` // Returned by the first run, so not new to the user
+
+	Title  string `gorm:"size:512" json:"title"`
+	Author string `gorm:"size:255" json:"author,omitempty"`
+	Image  string `gorm:"size:2048" json:"image,omitempty"`
+	URL    string `gorm:"size:2048" json:"url,omitempty"` // Feed URL
+}
+
+// TableName specifies the table name for SavedSearchResult
+func (SavedSearchResult) TableName() string {
+	return "saved_search_results"
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SummaryTopic is a chapter-style topic extracted from a transcription
+type SummaryTopic struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// EpisodeSummary represents an LLM-generated summary of an episode transcription
+type EpisodeSummary struct {
+	ID uint `gorm:"primarykey" json:"id"`
+
+	// Episode reference (using Podcast Index ID for consistency)
+	PodcastIndexEpisodeID int64 `gorm:"uniqueIndex;not null" json:"podcast_index_episode_id"`
+
+	Summary          string         `gorm:"type:text" json:"summary"`
+	TopicsData       []byte         `gorm:"type:blob" json:"-"` // JSON-encoded []SummaryTopic
+	Model            string         `json:"model"`
+	PromptTokens     int            `json:"prompt_tokens"`
+	CompletionTokens int            `json:"completion_tokens"`
+	Truncated        bool           `json:"truncated"` // True if the transcription was cut to fit the input budget
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for EpisodeSummary
+func (EpisodeSummary) TableName() string {
+	return "episode_summaries"
+}
+
+// Topics returns the decoded topic list
+func (s *EpisodeSummary) Topics() ([]SummaryTopic, error) {
+	if len(s.TopicsData) == 0 {
+		return []SummaryTopic{}, nil
+	}
+	var topics []SummaryTopic
+	if err := json.Unmarshal(s.TopicsData, &topics); err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
+
+// SetTopics encodes and sets the topic list
+func (s *EpisodeSummary) SetTopics(topics []SummaryTopic) error {
+	data, err := json.Marshal(topics)
+	if err != nil {
+		return err
+	}
+	s.TopicsData = data
+	return nil
+}
+
+// SummaryUsage records the tokens spent by one call to the summary model,
+// successful or not. Rows are only ever appended, so regenerating a summary
+// or failing to produce one still counts against the daily token budget.
+type SummaryUsage struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	PodcastIndexEpisodeID int64  `gorm:"index;not null" json:"podcast_index_episode_id"`
+	Model                 string `json:"model"`
+	PromptTokens          int    `json:"prompt_tokens"`
+	CompletionTokens      int    `json:"completion_tokens"`
+	Estimated             bool   `json:"estimated"` // True when the API reported no usage and the prompt size was estimated
+	Failed                bool   `json:"failed"`    // True when the call produced no summary
+}
+
+// TableName specifies the table name for SummaryUsage
+func (SummaryUsage) TableName() string {
+	return "summary_usages"
+}
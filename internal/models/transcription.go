@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -24,9 +25,87 @@ type Transcription struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	SegmentsData []byte `gorm:"type:blob" json:"-"` // JSON-encoded []TranscriptSegment; empty for untimed transcripts
+	OutlineData  []byte `gorm:"type:blob" json:"-"` // JSON-encoded TranscriptOutline; empty until first built
 }
 
 // TableName specifies the table name for Transcription
 func (Transcription) TableName() string {
 	return "transcriptions"
 }
+
+// TranscriptSegment is a span of transcript text with timing in seconds
+type TranscriptSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// Segments returns the decoded timed segments, or nil if the transcript has no timing
+func (t *Transcription) Segments() ([]TranscriptSegment, error) {
+	if len(t.SegmentsData) == 0 {
+		return nil, nil
+	}
+	var segments []TranscriptSegment
+	if err := json.Unmarshal(t.SegmentsData, &segments); err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// SetSegments encodes and sets the timed segments
+func (t *Transcription) SetSegments(segments []TranscriptSegment) error {
+	data, err := json.Marshal(segments)
+	if err != nil {
+		return err
+	}
+	t.SegmentsData = data
+	return nil
+}
+
+// TranscriptOutline splits a transcript into topical sections of paragraphs,
+// for chapter-like navigation of episodes whose feeds have no chapters
+type TranscriptOutline struct {
+	Version  int              `json:"version"` // Segmentation version the outline was built with
+	Timed    bool             `json:"timed"`   // False for untimed transcripts, whose times are all zero
+	Sections []OutlineSection `json:"sections"`
+}
+
+// OutlineSection is a run of paragraphs about one topic
+type OutlineSection struct {
+	Start      float64            `json:"start"`
+	End        float64            `json:"end"`
+	Title      string             `json:"title"`    // Built from the keywords
+	Keywords   []string           `json:"keywords"` // Words most particular to the section, most telling first
+	Paragraphs []OutlineParagraph `json:"paragraphs"`
+}
+
+// OutlineParagraph is a run of transcript text between pauses or at a length limit
+type OutlineParagraph struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// Outline returns the decoded outline, or nil if none has been built
+func (t *Transcription) Outline() (*TranscriptOutline, error) {
+	if len(t.OutlineData) == 0 {
+		return nil, nil
+	}
+	var outline TranscriptOutline
+	if err := json.Unmarshal(t.OutlineData, &outline); err != nil {
+		return nil, err
+	}
+	return &outline, nil
+}
+
+// SetOutline encodes and sets the outline
+func (t *Transcription) SetOutline(outline *TranscriptOutline) error {
+	data, err := json.Marshal(outline)
+	if err != nil {
+		return err
+	}
+	t.OutlineData = data
+	return nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,6 +15,18 @@ type Waveform struct {
 	Duration              float64 `json:"duration" gorm:"not null"`                   // Duration in seconds
 	Resolution            int     `json:"resolution" gorm:"not null"`                 // Number of peaks
 	SampleRate            int     `json:"sample_rate,omitempty" gorm:"default:44100"` // Sample rate of original audio
+
+	// Rendering hints computed alongside the peaks; nil on waveforms generated
+	// before they were recorded
+	P95Peak        *float32 `json:"p95_peak,omitempty"`         // 95th percentile of the normalized peaks
+	MedianPeak     *float32 `json:"median_peak,omitempty"`      // Median of the normalized peaks
+	DynamicRangeDB *float64 `json:"dynamic_range_db,omitempty"` // p95 vs p10 peak level in dB
+	LoudnessDB     *float64 `json:"loudness_db,omitempty"`      // RMS level of the audio in dBFS
+}
+
+// HasStats reports whether the rendering hints were recorded
+func (w *Waveform) HasStats() bool {
+	return w.P95Peak != nil && w.MedianPeak != nil && w.DynamicRangeDB != nil && w.LoudnessDB != nil
 }
 
 // Peaks returns the decoded peaks data
@@ -35,3 +48,32 @@ func (w *Waveform) SetPeaks(peaks []float32) error {
 	w.Resolution = len(peaks)
 	return nil
 }
+
+// WaveformCheckpoint holds the peaks computed so far by a running waveform job.
+// It is removed once the full waveform is saved.
+type WaveformCheckpoint struct {
+	PodcastIndexEpisodeID int64     `json:"podcast_index_episode_id" gorm:"primaryKey;autoIncrement:false"`
+	PeaksData             []byte    `json:"-" gorm:"type:blob;not null"` // JSON-encoded []float32
+	Duration              float64   `json:"duration"`                    // Expected total duration in seconds
+	Progress              int       `json:"progress"`                    // Percent of the audio decoded
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// Peaks returns the decoded partial peaks
+func (c *WaveformCheckpoint) Peaks() ([]float32, error) {
+	var peaks []float32
+	if err := json.Unmarshal(c.PeaksData, &peaks); err != nil {
+		return nil, err
+	}
+	return peaks, nil
+}
+
+// SetPeaks encodes and sets the partial peaks
+func (c *WaveformCheckpoint) SetPeaks(peaks []float32) error {
+	data, err := json.Marshal(peaks)
+	if err != nil {
+		return err
+	}
+	c.PeaksData = data
+	return nil
+}
@@ -0,0 +1,9 @@
+package analytics
+
+import "errors"
+
+var (
+	// ErrNoAnalytics is returned when none of a podcast's episodes have been analyzed
+	// and no playback events have been reported for it
+	ErrNoAnalytics = errors.New("no analytics for podcast")
+)
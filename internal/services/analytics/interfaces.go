@@ -0,0 +1,83 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// LabelShare is how often a clip label occurs across a podcast's analyzed episodes
+type LabelShare struct {
+	Count int     `json:"count" example:"42"`
+	Share float64 `json:"share" example:"0.35"` // Fraction of all counted clips
+}
+
+// PodcastAnalytics summarizes the analyzed episodes of one podcast
+type PodcastAnalytics struct {
+	PodcastIndexFeedID     int64                 `json:"podcast_id" example:"6780065"`
+	AnalyzedEpisodes       int                   `json:"analyzed_episodes" example:"25"`
+	AvgEpisodeDuration     float64               `json:"avg_episode_duration_seconds" example:"3120.5"` // Over episodes with a known duration
+	AdSecondsPerHour       float64               `json:"ad_seconds_per_hour" example:"142.7"`
+	TotalAdSeconds         float64               `json:"total_ad_seconds" example:"3100"`
+	LabelDistribution      map[string]LabelShare `json:"label_distribution"`
+	TranscribedEpisodes    int                   `json:"transcribed_episodes" example:"20"`
+	TranscriptAvailability float64               `json:"transcript_availability" example:"0.8"` // Fraction of analyzed episodes with a transcript
+	UpdatedAt              time.Time             `json:"updated_at"`                            // Most recent episode refresh
+	Listening              *ListenerEngagement   `json:"listening,omitempty"`                   // Omitted until clients report playback events
+}
+
+// ListenerEngagement summarizes client playback events for a podcast over the
+// event retention period. Counts are estimates when anonymous events are sampled.
+type ListenerEngagement struct {
+	Plays            float64 `json:"plays" example:"1250"`
+	Completions      float64 `json:"completions" example:"610"`
+	CompletionRate   float64 `json:"completion_rate" example:"0.488"` // Completions per play
+	Seeks            float64 `json:"seeks" example:"3400"`
+	AdSkips          float64 `json:"ad_skips" example:"880"`
+	AdSecondsSkipped float64 `json:"ad_seconds_skipped" example:"52800"`
+}
+
+// EventTotal sums one type of playback event
+type EventTotal struct {
+	Type    string
+	Events  float64 // Sum of event weights
+	Seconds float64 // Sum of weighted forward jumps, for seeks and skips
+}
+
+// Service maintains per-episode stats and summarizes them per podcast
+type Service interface {
+	// RefreshEpisode recomputes the stored stats of one episode
+	RefreshEpisode(ctx context.Context, podcastIndexEpisodeID int64) error
+
+	// NotifyJobCompleted refreshes the stats of the episode a finished job worked on
+	NotifyJobCompleted(ctx context.Context, job *models.Job) error
+
+	// PodcastAnalytics sums the stored stats of a podcast's episodes and its
+	// listeners' playback events
+	PodcastAnalytics(ctx context.Context, podcastIndexFeedID int64) (*PodcastAnalytics, error)
+}
+
+// Repository defines the interface for stats persistence and the source data they are built from
+type Repository interface {
+	// GetEpisode returns an episode by Podcast Index ID
+	GetEpisode(ctx context.Context, podcastIndexEpisodeID int64) (*models.Episode, error)
+
+	// EpisodeIDForClip returns the episode a clip belongs to
+	EpisodeIDForClip(ctx context.Context, clipUUID string) (int64, error)
+
+	// ListClips returns the clips of an episode that were not rejected
+	ListClips(ctx context.Context, podcastIndexEpisodeID int64) ([]models.Clip, error)
+
+	// GetTranscription returns the episode transcription, or nil if there is none
+	GetTranscription(ctx context.Context, podcastIndexEpisodeID int64) (*models.Transcription, error)
+
+	// SaveEpisodeStats inserts or replaces the stats of an episode
+	SaveEpisodeStats(ctx context.Context, stats *models.EpisodeStats) error
+
+	// ListEpisodeStats returns the stats of every analyzed episode of a podcast
+	ListEpisodeStats(ctx context.Context, podcastIndexFeedID int64) ([]models.EpisodeStats, error)
+
+	// SumPlaybackEvents totals a podcast's playback events by type
+	SumPlaybackEvents(ctx context.Context, podcastIndexFeedID int64) ([]EventTotal, error)
+}
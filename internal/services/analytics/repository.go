@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new analytics repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetEpisode returns an episode by Podcast Index ID
+func (r *repository) GetEpisode(ctx context.Context, podcastIndexEpisodeID int64) (*models.Episode, error) {
+	var episode models.Episode
+	err := r.db.WithContext(ctx).Where("podcast_index_id = ?", podcastIndexEpisodeID).First(&episode).Error
+	if err != nil {
+		return nil, err
+	}
+	return &episode, nil
+}
+
+// EpisodeIDForClip returns the episode a clip belongs to
+func (r *repository) EpisodeIDForClip(ctx context.Context, clipUUID string) (int64, error) {
+	var clip models.Clip
+	err := r.db.WithContext(ctx).Select("podcast_index_episode_id").Where("uuid = ?", clipUUID).First(&clip).Error
+	return clip.PodcastIndexEpisodeID, err
+}
+
+// ListClips returns the clips of an episode that were not rejected
+func (r *repository) ListClips(ctx context.Context, podcastIndexEpisodeID int64) ([]models.Clip, error) {
+	var clips []models.Clip
+	err := r.db.WithContext(ctx).
+		Where("podcast_index_episode_id = ? AND rejected = ?", podcastIndexEpisodeID, false).
+		Find(&clips).Error
+	return clips, err
+}
+
+// GetTranscription returns the episode transcription, or nil if there is none
+func (r *repository) GetTranscription(ctx context.Context, podcastIndexEpisodeID int64) (*models.Transcription, error) {
+	var transcription models.Transcription
+	err := r.db.WithContext(ctx).Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).First(&transcription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transcription, nil
+}
+
+// SaveEpisodeStats inserts or replaces the stats of an episode
+func (r *repository) SaveEpisodeStats(ctx context.Context, stats *models.EpisodeStats) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "podcast_index_episode_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at", "podcast_index_feed_id", "duration_seconds", "ad_seconds",
+			"has_transcript", "label_counts_data", "clip_count",
+		}),
+	}).Create(stats).Error
+}
+
+// ListEpisodeStats returns the stats of every analyzed episode of a podcast
+func (r *repository) ListEpisodeStats(ctx context.Context, podcastIndexFeedID int64) ([]models.EpisodeStats, error) {
+	var stats []models.EpisodeStats
+	err := r.db.WithContext(ctx).Where("podcast_index_feed_id = ?", podcastIndexFeedID).Find(&stats).Error
+	return stats, err
+}
+
+// SumPlaybackEvents totals a podcast's playback events by type
+func (r *repository) SumPlaybackEvents(ctx context.Context, podcastIndexFeedID int64) ([]EventTotal, error) {
+	var totals []EventTotal
+	err := r.db.WithContext(ctx).Model(&models.PlaybackEvent{}).
+		Select("type, SUM(weight) AS events, "+
+			"SUM(CASE WHEN position > from_position THEN (position - from_position) * weight ELSE 0 END) AS seconds").
+		Where("podcast_index_feed_id = ?", podcastIndexFeedID).
+		Group("type").
+		Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,237 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// statsJobTypes are the jobs whose results feed episode stats
+var statsJobTypes = map[models.JobType]bool{
+	models.JobTypeTranscription:           true,
+	models.JobTypeTranscriptionGeneration: true,
+	models.JobTypeClipExtraction:          true,
+	models.JobTypeAutoLabel:               true,
+	models.JobTypeWaveformGeneration:      true,
+}
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	adLabels map[string]bool
+}
+
+// NewService creates a new analytics service. Approved clips with one of
+// adLabels count towards ad time.
+func NewService(repo Repository, adLabels []string) Service {
+	labels := make(map[string]bool, len(adLabels))
+	for _, label := range adLabels {
+		labels[label] = true
+	}
+	return &service{repo: repo, adLabels: labels}
+}
+
+// NotifyJobCompleted refreshes the stats of the episode a finished job worked on.
+// Jobs that don't touch episode analysis are ignored.
+func (s *service) NotifyJobCompleted(ctx context.Context, job *models.Job) error {
+	if job == nil || !statsJobTypes[job.Type] {
+		return nil
+	}
+
+	var episodeID int64
+	if id, ok := job.GetPayloadInt("episode_id"); ok {
+		episodeID = int64(id)
+	} else if clipUUID, ok := job.GetPayloadString("clip_uuid"); ok {
+		id, err := s.repo.EpisodeIDForClip(ctx, clipUUID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("resolving episode of clip %s: %w", clipUUID, err)
+		}
+		episodeID = id
+	}
+	if episodeID <= 0 {
+		return nil
+	}
+
+	return s.RefreshEpisode(ctx, episodeID)
+}
+
+// RefreshEpisode recomputes the stored stats of one episode. Episodes that are
+// not in the database yet are skipped, since they can't be tied to a podcast.
+func (s *service) RefreshEpisode(ctx context.Context, podcastIndexEpisodeID int64) error {
+	episode, err := s.repo.GetEpisode(ctx, podcastIndexEpisodeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("loading episode %d: %w", podcastIndexEpisodeID, err)
+	}
+
+	clips, err := s.repo.ListClips(ctx, podcastIndexEpisodeID)
+	if err != nil {
+		return fmt.Errorf("listing clips of episode %d: %w", podcastIndexEpisodeID, err)
+	}
+
+	transcription, err := s.repo.GetTranscription(ctx, podcastIndexEpisodeID)
+	if err != nil {
+		return fmt.Errorf("loading transcription of episode %d: %w", podcastIndexEpisodeID, err)
+	}
+
+	stats := &models.EpisodeStats{
+		PodcastIndexEpisodeID: podcastIndexEpisodeID,
+		PodcastIndexFeedID:    episode.PodcastIndexFeedID,
+		HasTranscript:         transcription != nil && transcription.Text != "",
+		ClipCount:             len(clips),
+	}
+	if episode.Duration != nil && *episode.Duration > 0 {
+		stats.DurationSeconds = float64(*episode.Duration)
+	} else if transcription != nil {
+		stats.DurationSeconds = transcription.Duration
+	}
+
+	counts := make(map[string]int)
+	var adRanges [][2]float64
+	for _, clip := range clips {
+		counts[clip.Label]++
+		if clip.Approved && s.adLabels[clip.Label] {
+			adRanges = append(adRanges, [2]float64{clip.OriginalStartTime, clip.OriginalEndTime})
+		}
+	}
+	stats.AdSeconds = unionSeconds(adRanges)
+	if err := stats.SetLabelCounts(counts); err != nil {
+		return fmt.Errorf("encoding label counts: %w", err)
+	}
+
+	if err := s.repo.SaveEpisodeStats(ctx, stats); err != nil {
+		return fmt.Errorf("saving stats of episode %d: %w", podcastIndexEpisodeID, err)
+	}
+	return nil
+}
+
+// PodcastAnalytics sums the stored stats of a podcast's episodes and its
+// listeners' playback events
+func (s *service) PodcastAnalytics(ctx context.Context, podcastIndexFeedID int64) (*PodcastAnalytics, error) {
+	rows, err := s.repo.ListEpisodeStats(ctx, podcastIndexFeedID)
+	if err != nil {
+		return nil, fmt.Errorf("listing episode stats: %w", err)
+	}
+	totals, err := s.repo.SumPlaybackEvents(ctx, podcastIndexFeedID)
+	if err != nil {
+		return nil, fmt.Errorf("summing playback events: %w", err)
+	}
+	if len(rows) == 0 && len(totals) == 0 {
+		return nil, ErrNoAnalytics
+	}
+
+	result := &PodcastAnalytics{
+		PodcastIndexFeedID: podcastIndexFeedID,
+		AnalyzedEpisodes:   len(rows),
+		LabelDistribution:  make(map[string]LabelShare),
+		Listening:          engagement(totals),
+	}
+
+	var timedEpisodes int
+	var timedSeconds, timedAdSeconds float64
+	var totalClips int
+	for i := range rows {
+		row := &rows[i]
+		if row.UpdatedAt.After(result.UpdatedAt) {
+			result.UpdatedAt = row.UpdatedAt
+		}
+		if row.HasTranscript {
+			result.TranscribedEpisodes++
+		}
+		result.TotalAdSeconds += row.AdSeconds
+		if row.DurationSeconds > 0 {
+			timedEpisodes++
+			timedSeconds += row.DurationSeconds
+			timedAdSeconds += row.AdSeconds
+		}
+
+		counts, err := row.LabelCounts()
+		if err != nil {
+			return nil, fmt.Errorf("decoding label counts of episode %d: %w", row.PodcastIndexEpisodeID, err)
+		}
+		for label, count := range counts {
+			share := result.LabelDistribution[label]
+			share.Count += count
+			result.LabelDistribution[label] = share
+			totalClips += count
+		}
+	}
+
+	if timedEpisodes > 0 {
+		result.AvgEpisodeDuration = timedSeconds / float64(timedEpisodes)
+		// Ad time on episodes without a duration can't be put against any hours
+		result.AdSecondsPerHour = timedAdSeconds / (timedSeconds / 3600)
+	}
+	for label, share := range result.LabelDistribution {
+		share.Share = float64(share.Count) / float64(totalClips)
+		result.LabelDistribution[label] = share
+	}
+	if len(rows) > 0 {
+		result.TranscriptAvailability = float64(result.TranscribedEpisodes) / float64(len(rows))
+	}
+
+	return result, nil
+}
+
+// engagement folds per-type event totals into listener figures
+func engagement(totals []EventTotal) *ListenerEngagement {
+	if len(totals) == 0 {
+		return nil
+	}
+	result := &ListenerEngagement{}
+	for _, total := range totals {
+		switch total.Type {
+		case models.PlaybackEventPlay:
+			result.Plays = total.Events
+		case models.PlaybackEventComplete:
+			result.Completions = total.Events
+		case models.PlaybackEventSeek:
+			result.Seeks = total.Events
+		case models.PlaybackEventSkipAd:
+			result.AdSkips = total.Events
+			result.AdSecondsSkipped = total.Seconds
+		}
+	}
+	if result.Plays > 0 {
+		result.CompletionRate = math.Min(result.Completions/result.Plays, 1)
+	}
+	return result
+}
+
+// unionSeconds returns the total length covered by ranges, counting overlaps once
+func unionSeconds(ranges [][2]float64) float64 {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	var total float64
+	var current [2]float64
+	open := false
+	for _, r := range ranges {
+		if r[1] <= r[0] {
+			continue
+		}
+		if open && r[0] <= current[1] {
+			if r[1] > current[1] {
+				current[1] = r[1]
+			}
+			continue
+		}
+		if open {
+			total += current[1] - current[0]
+		}
+		current, open = r, true
+	}
+	if open {
+		total += current[1] - current[0]
+	}
+	return total
+}
@@ -0,0 +1,160 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Episode{}, &models.Clip{}, &models.Transcription{}, &models.EpisodeStats{}, &models.PlaybackEvent{}))
+	return db
+}
+
+func seedEpisode(t *testing.T, db *gorm.DB, id, feedID int64, duration int) {
+	episode := models.Episode{
+		PodcastID:          1,
+		PodcastIndexID:     id,
+		PodcastIndexFeedID: feedID,
+		Title:              "Episode",
+		GUID:               fmt.Sprintf("guid-%d", id),
+		AudioURL:           "https://example.com/ep.mp3",
+	}
+	if duration > 0 {
+		episode.Duration = &duration
+	}
+	require.NoError(t, db.Create(&episode).Error)
+}
+
+func seedClip(t *testing.T, db *gorm.DB, uuid string, episodeID int64, start, end float64, label string, approved, rejected bool) {
+	require.NoError(t, db.Create(&models.Clip{
+		UUID:                  uuid,
+		PodcastIndexEpisodeID: episodeID,
+		SourceEpisodeURL:      "https://example.com/ep.mp3",
+		OriginalStartTime:     start,
+		OriginalEndTime:       end,
+		Label:                 label,
+		Approved:              approved,
+		Rejected:              rejected,
+	}).Error)
+}
+
+func TestPodcastAnalytics(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db), []string{"advertisement"})
+	ctx := context.Background()
+
+	seedEpisode(t, db, 1, 100, 3600)
+	seedEpisode(t, db, 2, 100, 1800)
+	seedEpisode(t, db, 3, 200, 3600)
+
+	// Episode 1: two overlapping ads (90s of ad time), one pending ad, one rejected ad
+	seedClip(t, db, "a", 1, 0, 60, "advertisement", true, false)
+	seedClip(t, db, "b", 1, 30, 90, "advertisement", true, false)
+	seedClip(t, db, "c", 1, 500, 530, "advertisement", false, false)
+	seedClip(t, db, "d", 1, 600, 630, "advertisement", false, true)
+	seedClip(t, db, "e", 1, 700, 730, "music", true, false)
+	// Episode 2: one ad of 45s
+	seedClip(t, db, "f", 2, 100, 145, "advertisement", true, false)
+	require.NoError(t, db.Create(&models.Transcription{PodcastIndexEpisodeID: 2, Text: "hello"}).Error)
+
+	_, err := svc.PodcastAnalytics(ctx, 100)
+	assert.ErrorIs(t, err, ErrNoAnalytics)
+
+	for _, id := range []int64{1, 2, 3} {
+		require.NoError(t, svc.RefreshEpisode(ctx, id))
+	}
+	// Refreshing again updates the row in place
+	require.NoError(t, svc.RefreshEpisode(ctx, 1))
+
+	result, err := svc.PodcastAnalytics(ctx, 100)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.AnalyzedEpisodes)
+	assert.Equal(t, 2700.0, result.AvgEpisodeDuration)
+	assert.Equal(t, 135.0, result.TotalAdSeconds)
+	assert.InDelta(t, 90.0, result.AdSecondsPerHour, 0.001) // 135s over 1.5h
+	assert.Equal(t, 1, result.TranscribedEpisodes)
+	assert.Equal(t, 0.5, result.TranscriptAvailability)
+
+	require.Contains(t, result.LabelDistribution, "advertisement")
+	assert.Equal(t, 4, result.LabelDistribution["advertisement"].Count)
+	assert.Equal(t, 1, result.LabelDistribution["music"].Count)
+	assert.InDelta(t, 0.8, result.LabelDistribution["advertisement"].Share, 0.001)
+}
+
+func TestNotifyJobCompleted_RefreshesEpisode(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db), []string{"advertisement"})
+	ctx := context.Background()
+
+	seedEpisode(t, db, 1, 100, 3600)
+	seedClip(t, db, "clip-1", 1, 0, 30, "advertisement", true, false)
+
+	// Unrelated job types leave stats alone
+	require.NoError(t, svc.NotifyJobCompleted(ctx, &models.Job{
+		Type:    models.JobTypeUserExport,
+		Payload: models.JobPayload{"user_id": "user-1"},
+	}))
+	_, err := svc.PodcastAnalytics(ctx, 100)
+	assert.ErrorIs(t, err, ErrNoAnalytics)
+
+	// Clip jobs resolve their episode through the clip
+	require.NoError(t, svc.NotifyJobCompleted(ctx, &models.Job{
+		Type:    models.JobTypeAutoLabel,
+		Payload: models.JobPayload{"clip_uuid": "clip-1"},
+	}))
+	result, err := svc.PodcastAnalytics(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, result.TotalAdSeconds)
+
+	// Episodes that aren't stored yet are skipped without error
+	require.NoError(t, svc.NotifyJobCompleted(ctx, &models.Job{
+		Type:    models.JobTypeTranscription,
+		Payload: models.JobPayload{"episode_id": float64(999)},
+	}))
+}
+
+func TestPodcastAnalytics_ListenerEngagement(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db), []string{"advertisement"})
+	ctx := context.Background()
+
+	// Anonymous events sampled at 50% weigh 2 each
+	require.NoError(t, db.Create(&[]models.PlaybackEvent{
+		{PodcastIndexEpisodeID: 1, PodcastIndexFeedID: 100, Type: models.PlaybackEventPlay, Weight: 2},
+		{PodcastIndexEpisodeID: 1, PodcastIndexFeedID: 100, Type: models.PlaybackEventSkipAd, FromPosition: 60, Position: 120, Weight: 2},
+		{PodcastIndexEpisodeID: 2, PodcastIndexFeedID: 100, Type: models.PlaybackEventPlay, Weight: 1},
+		{PodcastIndexEpisodeID: 2, PodcastIndexFeedID: 100, Type: models.PlaybackEventComplete, Weight: 1},
+		{PodcastIndexEpisodeID: 3, PodcastIndexFeedID: 200, Type: models.PlaybackEventPlay, Weight: 1},
+	}).Error)
+
+	// Events alone are enough to report on a podcast
+	result, err := svc.PodcastAnalytics(ctx, 100)
+	require.NoError(t, err)
+	assert.Zero(t, result.AnalyzedEpisodes)
+	require.NotNil(t, result.Listening)
+	assert.Equal(t, &ListenerEngagement{
+		Plays:            3,
+		Completions:      1,
+		CompletionRate:   1.0 / 3,
+		AdSkips:          2,
+		AdSecondsSkipped: 120,
+	}, result.Listening)
+}
+
+func TestUnionSeconds(t *testing.T) {
+	assert.Equal(t, 0.0, unionSeconds(nil))
+	assert.Equal(t, 40.0, unionSeconds([][2]float64{{20, 40}, {0, 10}, {35, 50}, {45, 45}}))
+}
@@ -0,0 +1,29 @@
+package artwork
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Service extracts and stores the dominant colors of podcast and episode artwork
+type Service interface {
+	// Palette returns the image's colors, most dominant first, extracting and
+	// storing them first if the image hasn't been analyzed. An image that
+	// can't be fetched or decoded has an empty palette.
+	Palette(ctx context.Context, imageURL string) ([]string, error)
+
+	// Palettes returns the stored palettes of the images by URL. Images not
+	// analyzed yet are left out and extracted in the background, so a later
+	// request has them.
+	Palettes(ctx context.Context, imageURLs []string) (map[string][]string, error)
+}
+
+// Repository defines the interface for palette persistence
+type Repository interface {
+	// Find returns the stored rows for the image URLs, in no particular order
+	Find(ctx context.Context, imageURLs []string) ([]models.ArtworkPalette, error)
+
+	// Save inserts the palette, or replaces the stored one for its image URL
+	Save(ctx context.Context, palette *models.ArtworkPalette) error
+}
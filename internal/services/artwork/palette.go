@@ -0,0 +1,131 @@
+package artwork
+
+import (
+	"fmt"
+	"image"
+	"slices"
+)
+
+// maxSamples bounds the pixels considered, so large artwork costs no more
+// than a thumbnail
+const maxSamples = 128 * 128
+
+// rgb is an opaque color sample
+type rgb [3]uint8
+
+// box is a group of samples in median cut
+type box []rgb
+
+// ExtractPalette returns up to size dominant colors of img as #rrggbb, most
+// common first. It uses median cut: the samples are repeatedly split at the
+// median of the color channel with the widest range, and each final group
+// contributes its average color. Transparent pixels are ignored.
+func ExtractPalette(img image.Image, size int) []string {
+	samples := sample(img)
+	if len(samples) == 0 || size <= 0 {
+		return nil
+	}
+
+	boxes := []box{samples}
+	for len(boxes) < size {
+		// Split the box with the widest channel range; ties go to the larger box
+		index, widest := -1, 0
+		for i, b := range boxes {
+			if len(b) < 2 {
+				continue
+			}
+			if _, spread := b.widestChannel(); spread > widest || (spread == widest && index >= 0 && len(b) > len(boxes[index])) {
+				index, widest = i, spread
+			}
+		}
+		if index < 0 || widest == 0 {
+			break // Every box is a single color
+		}
+		low, high := boxes[index].split()
+		boxes[index] = low
+		boxes = append(boxes, high)
+	}
+
+	slices.SortStableFunc(boxes, func(a, b box) int { return len(b) - len(a) })
+
+	palette := make([]string, 0, len(boxes))
+	seen := make(map[string]bool, len(boxes))
+	for _, b := range boxes {
+		color := b.average().hex()
+		if !seen[color] {
+			seen[color] = true
+			palette = append(palette, color)
+		}
+	}
+	return palette
+}
+
+// sample returns the opaque pixels of img on a grid of at most maxSamples points
+func sample(img image.Image) box {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	step := 1
+	for (width/step)*(height/step) > maxSamples {
+		step++
+	}
+
+	samples := make(box, 0, (width/step+1)*(height/step+1))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Undo alpha premultiplication for semi-transparent pixels
+			samples = append(samples, rgb{uint8(r * 0xff / a), uint8(g * 0xff / a), uint8(b * 0xff / a)})
+		}
+	}
+	return samples
+}
+
+// widestChannel returns the channel with the largest range in the box and that range
+func (b box) widestChannel() (int, int) {
+	channel, widest := 0, -1
+	for c := 0; c < 3; c++ {
+		low, high := uint8(255), uint8(0)
+		for _, s := range b {
+			low = min(low, s[c])
+			high = max(high, s[c])
+		}
+		if spread := int(high) - int(low); spread > widest {
+			channel, widest = c, spread
+		}
+	}
+	return channel, widest
+}
+
+// split sorts the box along its widest channel and cuts it at the median
+func (b box) split() (box, box) {
+	channel, _ := b.widestChannel()
+	slices.SortFunc(b, func(x, y rgb) int { return int(x[channel]) - int(y[channel]) })
+	median := len(b) / 2
+	return b[:median], b[median:]
+}
+
+// average returns the mean color of the box
+func (b box) average() rgb {
+	var sum [3]int
+	for _, s := range b {
+		for c := range sum {
+			sum[c] += int(s[c])
+		}
+	}
+	var mean rgb
+	for c := range sum {
+		mean[c] = uint8((sum[c] + len(b)/2) / len(b))
+	}
+	return mean
+}
+
+func (c rgb) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2])
+}
@@ -0,0 +1,37 @@
+package artwork
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new palette repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Find returns the stored rows for the image URLs
+func (r *repository) Find(ctx context.Context, imageURLs []string) ([]models.ArtworkPalette, error) {
+	var palettes []models.ArtworkPalette
+	if len(imageURLs) == 0 {
+		return palettes, nil
+	}
+	err := r.db.WithContext(ctx).Where("image_url IN ?", imageURLs).Find(&palettes).Error
+	return palettes, err
+}
+
+// Save upserts the palette on its image URL
+func (r *repository) Save(ctx context.Context, palette *models.ArtworkPalette) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "image_url"}},
+		DoUpdates: clause.AssignmentColumns([]string{"colors", "error", "updated_at"}),
+	}).Create(palette).Error
+}
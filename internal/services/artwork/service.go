@@ -0,0 +1,264 @@
+package artwork
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // Decoders for the formats artwork comes in
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Defaults for the Option settings
+const (
+	DefaultPaletteSize  = 5
+	DefaultFetchTimeout = 10 * time.Second
+	DefaultRetryAfter   = 24 * time.Hour
+)
+
+const (
+	maxImageBytes        = 20 << 20 // Larger downloads are cut off and fail to decode
+	maxImagePixels       = 25 << 20 // A small file can declare huge dimensions; decoding allocates for all of them
+	maxErrorLength       = 500
+	backgroundExtractors = 2 // Concurrent extractions queued by Palettes
+)
+
+// service implements the Service interface
+type service struct {
+	repo        Repository
+	client      *http.Client
+	paletteSize int
+	retryAfter  time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]chan struct{} // Closed when the image's extraction finishes
+	slots    chan struct{}            // Bounds background extractions
+	now      func() time.Time
+}
+
+// Option configures the service
+type Option func(*service)
+
+// WithPaletteSize sets how many colors a palette has at most
+func WithPaletteSize(size int) Option {
+	return func(s *service) {
+		if size > 0 {
+			s.paletteSize = size
+		}
+	}
+}
+
+// WithFetchTimeout bounds how long downloading an image may take
+func WithFetchTimeout(timeout time.Duration) Option {
+	return func(s *service) {
+		if timeout > 0 {
+			s.client = &http.Client{Timeout: timeout}
+		}
+	}
+}
+
+// WithHTTPClient sets the client images are downloaded with
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *service) {
+		if client != nil {
+			s.client = client
+		}
+	}
+}
+
+// WithRetryAfter sets how long an image that failed to download or decode is
+// left before it is tried again
+func WithRetryAfter(retryAfter time.Duration) Option {
+	return func(s *service) {
+		if retryAfter > 0 {
+			s.retryAfter = retryAfter
+		}
+	}
+}
+
+// NewService creates a new artwork palette service
+func NewService(repo Repository, opts ...Option) Service {
+	s := &service{
+		repo:        repo,
+		client:      &http.Client{Timeout: DefaultFetchTimeout},
+		paletteSize: DefaultPaletteSize,
+		retryAfter:  DefaultRetryAfter,
+		inflight:    make(map[string]chan struct{}),
+		slots:       make(chan struct{}, backgroundExtractors),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Palette returns the image's colors, extracting them if needed
+func (s *service) Palette(ctx context.Context, imageURL string) ([]string, error) {
+	if imageURL == "" {
+		return nil, nil
+	}
+	stored, err := s.repo.Find(ctx, []string{imageURL})
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) > 0 && !s.stale(&stored[0]) {
+		return stored[0].ColorList(), nil
+	}
+
+	palette, err := s.extract(ctx, imageURL)
+	if err != nil {
+		return nil, err
+	}
+	return palette.ColorList(), nil
+}
+
+// Palettes returns the stored palettes and queues the rest
+func (s *service) Palettes(ctx context.Context, imageURLs []string) (map[string][]string, error) {
+	wanted := make(map[string]bool, len(imageURLs))
+	var unique []string
+	for _, imageURL := range imageURLs {
+		if imageURL != "" && !wanted[imageURL] {
+			wanted[imageURL] = true
+			unique = append(unique, imageURL)
+		}
+	}
+
+	stored, err := s.repo.Find(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	palettes := make(map[string][]string, len(stored))
+	for i := range stored {
+		if colors := stored[i].ColorList(); colors != nil {
+			palettes[stored[i].ImageURL] = colors
+		}
+		if !s.stale(&stored[i]) {
+			delete(wanted, stored[i].ImageURL)
+		}
+	}
+
+	for imageURL := range wanted {
+		s.extractInBackground(imageURL)
+	}
+	return palettes, nil
+}
+
+// stale reports whether a stored failure is old enough to try again
+func (s *service) stale(palette *models.ArtworkPalette) bool {
+	return palette.Colors == "" && s.now().Sub(palette.UpdatedAt) > s.retryAfter
+}
+
+// extractInBackground extracts the image's palette unless it is already being
+// extracted or the background extractors are all busy; the image is queued
+// again by the next request that wants it
+func (s *service) extractInBackground(imageURL string) {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		return
+	}
+	go func() {
+		defer func() { <-s.slots }()
+		if _, err := s.extract(context.Background(), imageURL); err != nil {
+			log.Printf("[WARN] Failed to store artwork palette for %s: %v", imageURL, err)
+		}
+	}()
+}
+
+// extract downloads the image, computes its palette and stores it. Failures
+// to fetch or decode the image are stored too, so the image isn't downloaded
+// on every request. Concurrent calls for one image share an extraction.
+func (s *service) extract(ctx context.Context, imageURL string) (*models.ArtworkPalette, error) {
+	s.mu.Lock()
+	if done, ok := s.inflight[imageURL]; ok {
+		s.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		stored, err := s.repo.Find(ctx, []string{imageURL})
+		if err != nil || len(stored) == 0 {
+			return &models.ArtworkPalette{ImageURL: imageURL}, err
+		}
+		return &stored[0], nil
+	}
+	done := make(chan struct{})
+	s.inflight[imageURL] = done
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.inflight, imageURL)
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	palette := &models.ArtworkPalette{ImageURL: imageURL}
+	colors, err := s.fetchPalette(ctx, imageURL)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err() // Not the image's fault
+		}
+		palette.Error = err.Error()
+		if len(palette.Error) > maxErrorLength {
+			palette.Error = palette.Error[:maxErrorLength]
+		}
+	} else {
+		palette.Colors = strings.Join(colors, ",")
+	}
+
+	if err := s.repo.Save(ctx, palette); err != nil {
+		return nil, fmt.Errorf("failed to save palette: %w", err)
+	}
+	return palette, nil
+}
+
+// fetchPalette downloads and decodes the image and extracts its palette.
+// Dimensions are checked before decoding, so a decompression bomb is refused
+// rather than allocated.
+func (s *service) fetchPalette(ctx context.Context, imageURL string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image URL: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if pixels := int64(config.Width) * int64(config.Height); pixels > maxImagePixels {
+		return nil, fmt.Errorf("image is too large: %dx%d", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	colors := ExtractPalette(img, s.paletteSize)
+	if len(colors) == 0 {
+		return nil, fmt.Errorf("image has no opaque pixels")
+	}
+	return colors, nil
+}
@@ -0,0 +1,158 @@
+package artwork
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.ArtworkPalette{}))
+	return db
+}
+
+// stripes returns an image with horizontal bands of the colors, each band
+// rows tall
+func stripes(rows []int, colors ...color.RGBA) *image.RGBA {
+	height := 0
+	for _, r := range rows {
+		height += r
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 10, height))
+	y := 0
+	for i, c := range colors {
+		for end := y + rows[i]; y < end; y++ {
+			for x := 0; x < 10; x++ {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+var (
+	red  = color.RGBA{R: 200, G: 20, B: 30, A: 255}
+	navy = color.RGBA{R: 10, G: 20, B: 90, A: 255}
+)
+
+func TestExtractPalette(t *testing.T) {
+	img := stripes([]int{30, 10}, red, navy)
+	assert.Equal(t, []string{"#c8141e", "#0a145a"}, ExtractPalette(img, 5), "most common first; no more colors than the image has")
+	assert.Equal(t, []string{"#99142d"}, ExtractPalette(img, 1), "a single color is the weighted average")
+
+	assert.Empty(t, ExtractPalette(image.NewRGBA(image.Rect(0, 0, 4, 4)), 5), "transparent pixels are ignored")
+}
+
+func TestPalette_ExtractsOnceAndStores(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, stripes([]int{30, 10}, red, navy)))
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/art.png" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db), WithHTTPClient(server.Client())).(*service)
+	ctx := context.Background()
+
+	colors, err := svc.Palette(ctx, server.URL+"/art.png")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"#c8141e", "#0a145a"}, colors)
+
+	colors, err = svc.Palette(ctx, server.URL+"/art.png")
+	require.NoError(t, err)
+	assert.Len(t, colors, 2)
+	assert.Equal(t, int32(1), requests.Load(), "the stored palette is reused")
+
+	// Failures are stored and only retried once they are old
+	colors, err = svc.Palette(ctx, server.URL+"/missing.png")
+	require.NoError(t, err)
+	assert.Empty(t, colors)
+	_, err = svc.Palette(ctx, server.URL+"/missing.png")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	svc.now = func() time.Time { return time.Now().Add(DefaultRetryAfter + time.Hour) }
+	_, err = svc.Palette(ctx, server.URL+"/missing.png")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+// pngHeader returns the start of a PNG declaring width x height, which is
+// all DecodeConfig reads
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	ihdr[12], ihdr[13] = 8, 2 // 8-bit RGB
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)-4))
+	buf.Write(ihdr)
+	_ = binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+	return buf.Bytes()
+}
+
+func TestPalette_RefusesHugeDimensions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(pngHeader(50000, 50000))
+	}))
+	defer server.Close()
+
+	svc := NewService(NewRepository(setupTestDB(t)), WithHTTPClient(server.Client())).(*service)
+	_, err := svc.fetchPalette(context.Background(), server.URL+"/bomb.png")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too large")
+}
+
+func TestPalettes_QueuesMissing(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, stripes([]int{4}, navy)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db), WithHTTPClient(server.Client()))
+	ctx := context.Background()
+	require.NoError(t, db.Create(&models.ArtworkPalette{ImageURL: "https://cdn.example.com/a.jpg", Colors: "#112233,#445566"}).Error)
+
+	urls := []string{"https://cdn.example.com/a.jpg", server.URL + "/b.png", ""}
+	palettes, err := svc.Palettes(ctx, urls)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"https://cdn.example.com/a.jpg": {"#112233", "#445566"}}, palettes)
+
+	assert.Eventually(t, func() bool {
+		palettes, err := svc.Palettes(ctx, urls)
+		return err == nil && len(palettes[server.URL+"/b.png"]) == 1
+	}, 5*time.Second, 10*time.Millisecond, "the missing image is extracted in the background")
+}
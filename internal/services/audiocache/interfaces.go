@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 )
 
 // Service defines the interface for audio caching operations
@@ -16,7 +17,12 @@ type Service interface {
 	// GetCachedAudio retrieves cached audio without downloading
 	GetCachedAudio(ctx context.Context, podcastIndexEpisodeID int64) (*models.AudioCache, error)
 
-	// ProcessAudioForML converts audio to 16kHz mono for ML training
+	// GetProcessedAudio is GetOrDownloadAudio for callers that read the
+	// processed rendition, making sure it exists in the current format
+	GetProcessedAudio(ctx context.Context, podcastIndexEpisodeID int64, audioURL string) (*models.AudioCache, error)
+
+	// ProcessAudioForML converts audio to the processed rendition's format:
+	// 16kHz mono 16-bit PCM WAV
 	ProcessAudioForML(ctx context.Context, originalPath string, outputPath string) error
 
 	// UpdateLastUsed updates the last used timestamp for cache entry
@@ -27,8 +33,15 @@ type Service interface {
 
 	// GetCacheStats returns statistics about the cache
 	GetCacheStats(ctx context.Context) (*CacheStats, error)
+
+	// OnCached registers a hook run each time an episode's audio is newly cached
+	OnCached(hook CachedHook)
 }
 
+// CachedHook is called with a cache entry right after it is created. It runs
+// on the caller's goroutine and should only queue work, not do it.
+type CachedHook func(ctx context.Context, cache *models.AudioCache)
+
 // Repository defines the interface for audio cache data persistence
 type Repository interface {
 	// Create creates a new audio cache entry
@@ -40,9 +53,12 @@ type Repository interface {
 	// GetBySHA256 retrieves cache entry by SHA256 hash
 	GetBySHA256(ctx context.Context, sha256 string) (*models.AudioCache, error)
 
-	// Update updates an existing cache entry
+	// Update writes the non-zero fields of an existing cache entry
 	Update(ctx context.Context, cache *models.AudioCache) error
 
+	// CountByProcessedPath counts the cache entries using a processed file
+	CountByProcessedPath(ctx context.Context, path string) (int64, error)
+
 	// Delete deletes a cache entry
 	Delete(ctx context.Context, id uint) error
 
@@ -51,6 +67,48 @@ type Repository interface {
 
 	// GetStats retrieves cache statistics
 	GetStats(ctx context.Context) (*CacheStats, error)
+
+	// RecordEpisodeDuration stores a measured duration on the episode, keeping
+	// the feed's value as its feed duration. It returns that feed duration.
+	RecordEpisodeDuration(ctx context.Context, podcastIndexEpisodeID int64, seconds int) (feedDuration *int, err error)
+
+	// EpisodeSourceURL returns where the episode's audio was last downloaded
+	// from when its enclosure was refused; "" when it wasn't or the episode isn't stored
+	EpisodeSourceURL(ctx context.Context, podcastIndexEpisodeID int64) (string, error)
+
+	// RecordSourceURL stores where the episode's audio was downloaded from; "" clears it
+	RecordSourceURL(ctx context.Context, podcastIndexEpisodeID int64, sourceURL string) error
+}
+
+// FetchRecorder is told how each download of episode audio went; a nil err is a success
+type FetchRecorder interface {
+	RecordAudioFetch(ctx context.Context, podcastIndexEpisodeID int64, err error)
+}
+
+// AlternateSource lists other URLs an episode's audio is published at, tried
+// when the enclosure URL is refused
+type AlternateSource interface {
+	AlternateAudioURLs(ctx context.Context, podcastIndexEpisodeID int64) ([]string, error)
+}
+
+// AlternateSourceFunc adapts a function to an AlternateSource
+type AlternateSourceFunc func(ctx context.Context, podcastIndexEpisodeID int64) ([]string, error)
+
+// AlternateAudioURLs calls f
+func (f AlternateSourceFunc) AlternateAudioURLs(ctx context.Context, podcastIndexEpisodeID int64) ([]string, error) {
+	return f(ctx, podcastIndexEpisodeID)
+}
+
+// Prober reads stream metadata from an audio file; *ffmpeg.FFmpeg satisfies it
+type Prober interface {
+	GetMetadata(ctx context.Context, filePath string) (*ffmpeg.AudioMetadata, error)
+}
+
+// Transcoder runs the ffmpeg and ffprobe processes behind the processed
+// rendition under the configured resource limits; *ffmpeg.FFmpeg satisfies it
+type Transcoder interface {
+	Run(ctx context.Context, args ...string) (string, error)
+	Duration(ctx context.Context, filePath string) (float64, error)
 }
 
 // StorageBackend defines the interface for file storage operations
@@ -43,9 +43,18 @@ func (r *RepositoryImpl) GetBySHA256(ctx context.Context, sha256 string) (*model
 	return &cache, nil
 }
 
-// Update updates an existing cache entry
+// Update writes the non-zero fields of cache to its entry, so a partial
+// struct such as UpdateLastUsed's leaves the other columns alone
 func (r *RepositoryImpl) Update(ctx context.Context, cache *models.AudioCache) error {
-	return r.db.WithContext(ctx).Save(cache).Error
+	return r.db.WithContext(ctx).Model(cache).Updates(cache).Error
+}
+
+// CountByProcessedPath counts the entries using a processed file; entries
+// for the same audio share files
+func (r *RepositoryImpl) CountByProcessedPath(ctx context.Context, path string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.AudioCache{}).Where("processed_path = ?", path).Count(&count).Error
+	return count, err
 }
 
 // Delete deletes a cache entry
@@ -100,3 +109,49 @@ func (r *RepositoryImpl) GetStats(ctx context.Context) (*CacheStats, error) {
 
 	return stats, nil
 }
+
+// RecordEpisodeDuration replaces the episode's duration with a measured one
+func (r *RepositoryImpl) RecordEpisodeDuration(ctx context.Context, podcastIndexEpisodeID int64, seconds int) (*int, error) {
+	var feedDuration *int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var episode models.Episode
+		if err := tx.Select("id", "duration", "feed_duration", "duration_probed").
+			Where("podcast_index_id = ?", podcastIndexEpisodeID).
+			First(&episode).Error; err != nil {
+			return err
+		}
+
+		feedDuration = episode.Duration
+		if episode.DurationProbed {
+			feedDuration = episode.FeedDuration
+		}
+		return tx.Model(&models.Episode{}).Where("id = ?", episode.ID).Updates(map[string]interface{}{
+			"duration":        seconds,
+			"feed_duration":   feedDuration,
+			"duration_probed": true,
+		}).Error
+	})
+	return feedDuration, err
+}
+
+// EpisodeSourceURL returns where the episode's audio was last downloaded from
+// when its enclosure was refused
+func (r *RepositoryImpl) EpisodeSourceURL(ctx context.Context, podcastIndexEpisodeID int64) (string, error) {
+	var urls []string
+	if err := r.db.WithContext(ctx).Model(&models.Episode{}).
+		Where("podcast_index_id = ?", podcastIndexEpisodeID).
+		Pluck("audio_source_url", &urls).Error; err != nil {
+		return "", err
+	}
+	if len(urls) == 0 {
+		return "", nil
+	}
+	return urls[0], nil
+}
+
+// RecordSourceURL stores where the episode's audio was downloaded from
+func (r *RepositoryImpl) RecordSourceURL(ctx context.Context, podcastIndexEpisodeID int64, sourceURL string) error {
+	return r.db.WithContext(ctx).Model(&models.Episode{}).
+		Where("podcast_index_id = ?", podcastIndexEpisodeID).
+		Update("audio_source_url", sourceURL).Error
+}
@@ -7,29 +7,101 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
 	"os"
-	"os/exec"
+	"slices"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/download"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 	"gorm.io/gorm"
 )
 
+// DurationTolerance is how far, as a fraction of the measured duration, a
+// feed's duration may be off before caching logs a warning
+const DurationTolerance = 0.05
+
 // ServiceImpl implements the Service interface
 type ServiceImpl struct {
 	repository Repository
 	storage    StorageBackend
+	prober     Prober
+	transcoder Transcoder
+	recorder   FetchRecorder
+	alternates AlternateSource
+	mirrors    []download.MirrorRule
+	hooks      []CachedHook
+	tempDir    string // Downloads and conversions in progress; the system temp directory when empty
+
+	// process makes the processed rendition; ProcessAudioForML unless a test replaces it
+	process func(ctx context.Context, originalPath, outputPath string) error
+}
+
+// Option configures optional ServiceImpl behaviour
+type Option func(*ServiceImpl)
+
+// WithProber reads codec, bitrate, channels, sample rate and duration of newly
+// cached audio with ffprobe. Without it only the duration is measured.
+func WithProber(prober Prober) Option {
+	return func(s *ServiceImpl) {
+		s.prober = prober
+	}
+}
+
+// WithTranscoder runs ffmpeg and ffprobe through transcoder. Without it the
+// binaries on PATH are run without resource limits.
+func WithTranscoder(transcoder Transcoder) Option {
+	return func(s *ServiceImpl) {
+		s.transcoder = transcoder
+	}
+}
+
+// WithFetchRecorder reports download outcomes, so feeds whose audio keeps
+// failing can be flagged
+func WithFetchRecorder(recorder FetchRecorder) Option {
+	return func(s *ServiceImpl) {
+		s.recorder = recorder
+	}
+}
+
+// WithMirrors adds mirror hosts to try when an enclosure host refuses a download
+func WithMirrors(mirrors []download.MirrorRule) Option {
+	return func(s *ServiceImpl) {
+		s.mirrors = mirrors
+	}
+}
+
+// WithAlternateSource tries the episode's alternate URLs when its enclosure
+// URL and every fallback derived from it are refused
+func WithAlternateSource(source AlternateSource) Option {
+	return func(s *ServiceImpl) {
+		s.alternates = source
+	}
+}
+
+// WithTempDir puts downloads and conversions in progress in dir, which the
+// cleanup service sweeps after a crash
+func WithTempDir(dir string) Option {
+	return func(s *ServiceImpl) {
+		s.tempDir = dir
+	}
 }
 
 // NewService creates a new audio cache service
-func NewService(repository Repository, storage StorageBackend) Service {
-	return &ServiceImpl{
+func NewService(repository Repository, storage StorageBackend, opts ...Option) Service {
+	s := &ServiceImpl{
 		repository: repository,
 		storage:    storage,
+		transcoder: ffmpeg.New("ffmpeg", "ffprobe", 0),
+	}
+	s.process = s.ProcessAudioForML
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // GetOrDownloadAudio retrieves cached audio or downloads if not present
@@ -48,11 +120,15 @@ func (s *ServiceImpl) GetOrDownloadAudio(ctx context.Context, podcastIndexEpisod
 	log.Printf("[INFO] Downloading audio for Podcast Index episode %d from %s", podcastIndexEpisodeID, audioURL)
 
 	// Download audio to temp file
-	tempFile, err := s.downloadAudio(ctx, audioURL)
+	tempFile, sourceURL, err := s.fetchAudio(ctx, podcastIndexEpisodeID, audioURL)
+	if s.recorder != nil {
+		s.recorder.RecordAudioFetch(ctx, podcastIndexEpisodeID, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to download audio: %w", err)
 	}
 	defer os.Remove(tempFile)
+	s.recordSourceURL(ctx, podcastIndexEpisodeID, audioURL, sourceURL)
 
 	// Calculate SHA256 of original file
 	sha256Hash, err := s.calculateSHA256(tempFile)
@@ -69,20 +145,29 @@ func (s *ServiceImpl) GetOrDownloadAudio(ctx context.Context, podcastIndexEpisod
 		newCache := &models.AudioCache{
 			PodcastIndexEpisodeID: podcastIndexEpisodeID,
 			OriginalURL:           audioURL,
+			SourceURL:             sourceURL,
 			OriginalSHA256:        existingCache.OriginalSHA256,
 			OriginalPath:          existingCache.OriginalPath,
 			OriginalSize:          existingCache.OriginalSize,
 			ProcessedPath:         existingCache.ProcessedPath,
 			ProcessedSHA256:       existingCache.ProcessedSHA256,
 			ProcessedSize:         existingCache.ProcessedSize,
+			ProcessedCodec:        existingCache.ProcessedCodec,
+			ProcessedChannels:     existingCache.ProcessedChannels,
 			DurationSeconds:       existingCache.DurationSeconds,
 			SampleRate:            existingCache.SampleRate,
+			Codec:                 existingCache.Codec,
+			Bitrate:               existingCache.Bitrate,
+			Channels:              existingCache.Channels,
+			OriginalSampleRate:    existingCache.OriginalSampleRate,
 		}
 
 		if err := s.repository.Create(ctx, newCache); err != nil {
 			return nil, fmt.Errorf("failed to create cache entry: %w", err)
 		}
 
+		s.recordEpisodeDuration(ctx, podcastIndexEpisodeID, newCache.DurationSeconds)
+		s.runHooks(ctx, newCache)
 		return newCache, nil
 	}
 
@@ -106,10 +191,10 @@ func (s *ServiceImpl) GetOrDownloadAudio(ctx context.Context, podcastIndexEpisod
 	}
 
 	// Process audio for ML (16kHz mono)
-	processedFilename := fmt.Sprintf("processed/%d_%s_16khz.mp3", podcastIndexEpisodeID, sha256Hash[:8])
-	processedTempFile := tempFile + "_processed.mp3"
+	processedFilename := processedFilename(podcastIndexEpisodeID, sha256Hash)
+	processedTempFile := tempFile + "_processed.wav"
 
-	if err := s.ProcessAudioForML(ctx, tempFile, processedTempFile); err != nil {
+	if err := s.process(ctx, tempFile, processedTempFile); err != nil {
 		// Clean up original file on error
 		if delErr := s.storage.Delete(ctx, originalPath); delErr != nil {
 			log.Printf("[WARN] Failed to cleanup original file after processing error: %v", delErr)
@@ -157,25 +242,36 @@ func (s *ServiceImpl) GetOrDownloadAudio(ctx context.Context, podcastIndexEpisod
 		return nil, fmt.Errorf("failed to stat processed file: %w", err)
 	}
 
-	// Get audio duration
-	duration, err := s.getAudioDuration(processedTempFile)
-	if err != nil {
-		log.Printf("[WARN] Failed to get audio duration: %v", err)
-		duration = 0
+	// Measure the original rather than trusting the feed's duration
+	metadata := s.probe(ctx, tempFile)
+	duration := metadata.Duration
+	if duration <= 0 {
+		duration, err = s.getAudioDuration(ctx, processedTempFile)
+		if err != nil {
+			log.Printf("[WARN] Failed to get audio duration: %v", err)
+			duration = 0
+		}
 	}
 
 	// Create cache entry
 	cache = &models.AudioCache{
 		PodcastIndexEpisodeID: podcastIndexEpisodeID,
 		OriginalURL:           audioURL,
+		SourceURL:             sourceURL,
 		OriginalSHA256:        sha256Hash,
 		OriginalPath:          originalPath,
 		OriginalSize:          fileInfo.Size(),
 		ProcessedPath:         processedPath,
 		ProcessedSHA256:       processedSHA256,
 		ProcessedSize:         processedInfo.Size(),
+		ProcessedCodec:        models.ProcessedAudioCodec,
+		ProcessedChannels:     models.ProcessedAudioChannels,
 		DurationSeconds:       duration,
-		SampleRate:            16000,
+		SampleRate:            models.ProcessedAudioSampleRate,
+		Codec:                 metadata.Codec,
+		Bitrate:               metadata.Bitrate,
+		Channels:              metadata.Channels,
+		OriginalSampleRate:    metadata.SampleRate,
 	}
 
 	if err := s.repository.Create(ctx, cache); err != nil {
@@ -189,10 +285,153 @@ func (s *ServiceImpl) GetOrDownloadAudio(ctx context.Context, podcastIndexEpisod
 		return nil, fmt.Errorf("failed to create cache entry: %w", err)
 	}
 
+	s.recordEpisodeDuration(ctx, podcastIndexEpisodeID, duration)
+
 	log.Printf("[INFO] Successfully cached audio for Podcast Index episode %d", podcastIndexEpisodeID)
+	s.runHooks(ctx, cache)
 	return cache, nil
 }
 
+// GetProcessedAudio returns the episode's cache entry, downloading it if
+// needed, with a processed rendition in the current format. Entries processed
+// before the format changed, or whose processed file is gone, are processed
+// again from the original, so transcription and clip extraction can always
+// read ProcessedPath instead of resampling the episode themselves.
+func (s *ServiceImpl) GetProcessedAudio(ctx context.Context, podcastIndexEpisodeID int64, audioURL string) (*models.AudioCache, error) {
+	cache, err := s.GetOrDownloadAudio(ctx, podcastIndexEpisodeID, audioURL)
+	if err != nil {
+		return nil, err
+	}
+	if cache.HasCurrentProcessed() {
+		if exists, err := s.storage.Exists(ctx, cache.ProcessedPath); err == nil && exists {
+			return cache, nil
+		}
+	}
+	if err := s.reprocess(ctx, cache); err != nil {
+		return nil, fmt.Errorf("failed to process cached audio: %w", err)
+	}
+	return cache, nil
+}
+
+// reprocess makes a current processed rendition from the cached original and
+// records it, removing the old rendition unless another entry still uses it
+func (s *ServiceImpl) reprocess(ctx context.Context, cache *models.AudioCache) error {
+	if cache.OriginalPath == "" {
+		return fmt.Errorf("no original audio cached for Podcast Index episode %d", cache.PodcastIndexEpisodeID)
+	}
+	log.Printf("[INFO] Processing cached audio of Podcast Index episode %d to %s at %d Hz",
+		cache.PodcastIndexEpisodeID, models.ProcessedAudioCodec, models.ProcessedAudioSampleRate)
+
+	temp, err := os.CreateTemp(s.tempDir, "audio_processed_*.wav")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	temp.Close()
+	defer os.Remove(temp.Name())
+
+	if err := s.process(ctx, cache.OriginalPath, temp.Name()); err != nil {
+		return err
+	}
+	digest, err := s.calculateSHA256(temp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to calculate processed SHA256: %w", err)
+	}
+	info, err := os.Stat(temp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to stat processed file: %w", err)
+	}
+	file, err := os.Open(temp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to open processed file: %w", err)
+	}
+	defer file.Close()
+
+	path, err := s.storage.Save(ctx, file, processedFilename(cache.PodcastIndexEpisodeID, cache.OriginalSHA256))
+	if err != nil {
+		return fmt.Errorf("failed to save processed audio: %w", err)
+	}
+
+	oldPath := cache.ProcessedPath
+	cache.ProcessedPath = path
+	cache.ProcessedSHA256 = digest
+	cache.ProcessedSize = info.Size()
+	cache.ProcessedCodec = models.ProcessedAudioCodec
+	cache.ProcessedChannels = models.ProcessedAudioChannels
+	cache.SampleRate = models.ProcessedAudioSampleRate
+	if err := s.repository.Update(ctx, cache); err != nil {
+		return fmt.Errorf("failed to record processed audio: %w", err)
+	}
+
+	if oldPath != "" && oldPath != path {
+		if users, err := s.repository.CountByProcessedPath(ctx, oldPath); err != nil || users > 0 {
+			return nil
+		}
+		if err := s.storage.Delete(ctx, oldPath); err != nil {
+			log.Printf("[WARN] Failed to delete outdated processed file %s: %v", oldPath, err)
+		}
+	}
+	return nil
+}
+
+// processedFilename names an episode's processed rendition in storage
+func processedFilename(podcastIndexEpisodeID int64, originalSHA256 string) string {
+	return fmt.Sprintf("processed/%d_%s_16khz.wav", podcastIndexEpisodeID, originalSHA256[:min(8, len(originalSHA256))])
+}
+
+// OnCached registers a hook run after each newly cached episode. Hooks are
+// registered during startup, before any audio is cached.
+func (s *ServiceImpl) OnCached(hook CachedHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+func (s *ServiceImpl) runHooks(ctx context.Context, cache *models.AudioCache) {
+	for _, hook := range s.hooks {
+		hook(ctx, cache)
+	}
+}
+
+// probe reads the stream metadata of path, returning empty metadata when no
+// prober is configured or ffprobe fails
+func (s *ServiceImpl) probe(ctx context.Context, path string) ffmpeg.AudioMetadata {
+	if s.prober == nil {
+		return ffmpeg.AudioMetadata{}
+	}
+	metadata, err := s.prober.GetMetadata(ctx, path)
+	if err != nil {
+		log.Printf("[WARN] Failed to probe cached audio: %v", err)
+		return ffmpeg.AudioMetadata{}
+	}
+	return *metadata
+}
+
+// recordEpisodeDuration makes the measured duration the episode's duration and
+// warns when the feed's figure was off by more than DurationTolerance
+func (s *ServiceImpl) recordEpisodeDuration(ctx context.Context, podcastIndexEpisodeID int64, duration float64) {
+	if duration <= 0 {
+		return
+	}
+
+	feedDuration, err := s.repository.RecordEpisodeDuration(ctx, podcastIndexEpisodeID, int(math.Round(duration)))
+	if err != nil {
+		log.Printf("[WARN] Failed to record measured duration for episode %d: %v", podcastIndexEpisodeID, err)
+		return
+	}
+	if feedDuration != nil && durationMismatch(*feedDuration, duration) {
+		log.Printf("[WARN] Episode %d: feed duration %ds differs from measured %.0fs by more than %.0f%%",
+			podcastIndexEpisodeID, *feedDuration, duration, DurationTolerance*100)
+	}
+}
+
+// durationMismatch reports whether a feed duration is more than
+// DurationTolerance away from the measured one. A missing (zero) feed
+// duration is not a mismatch.
+func durationMismatch(feedSeconds int, measured float64) bool {
+	if feedSeconds <= 0 || measured <= 0 {
+		return false
+	}
+	return math.Abs(float64(feedSeconds)-measured)/measured > DurationTolerance
+}
+
 // GetCachedAudio retrieves cached audio without downloading
 func (s *ServiceImpl) GetCachedAudio(ctx context.Context, podcastIndexEpisodeID int64) (*models.AudioCache, error) {
 	cache, err := s.repository.GetByPodcastIndexEpisodeID(ctx, podcastIndexEpisodeID)
@@ -211,22 +450,23 @@ func (s *ServiceImpl) GetCachedAudio(ctx context.Context, podcastIndexEpisodeID
 	return cache, nil
 }
 
-// ProcessAudioForML converts audio to 16kHz mono for ML training
+// ProcessAudioForML converts audio to the processed rendition's format,
+// 16kHz mono 16-bit PCM WAV, for transcription and ML training
 func (s *ServiceImpl) ProcessAudioForML(ctx context.Context, originalPath string, outputPath string) error {
-	// Use ffmpeg to convert to 16kHz mono
-	cmd := exec.CommandContext(ctx, "ffmpeg",
+	// Use ffmpeg to convert to 16kHz mono. Lossless PCM, so clips cut from
+	// it aren't encoded twice and seeking is sample accurate.
+	output, err := s.transcoder.Run(ctx,
 		"-i", originalPath,
-		"-ar", "16000", // Sample rate 16kHz
-		"-ac", "1", // Mono
-		"-b:a", "64k", // Bitrate
-		"-f", "mp3", // Output format
+		"-vn", // Drop cover art
+		"-ar", strconv.Itoa(models.ProcessedAudioSampleRate),
+		"-ac", strconv.Itoa(models.ProcessedAudioChannels),
+		"-c:a", models.ProcessedAudioCodec,
+		"-f", "wav", // Output format
 		"-y", // Overwrite output
 		outputPath,
 	)
-
-	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("ffmpeg failed: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("ffmpeg failed: %w\nOutput: %s", err, output)
 	}
 
 	return nil
@@ -276,10 +516,85 @@ func (s *ServiceImpl) GetCacheStats(ctx context.Context) (*CacheStats, error) {
 	return s.repository.GetStats(ctx)
 }
 
+// fetchAudio downloads an episode's audio to a temp file and returns the URL
+// that served it. When the host refuses the enclosure URL, the fallbacks
+// derived from it and then the episode's alternate URLs are tried in turn; if
+// none works the enclosure's error is returned.
+func (s *ServiceImpl) fetchAudio(ctx context.Context, podcastIndexEpisodeID int64, audioURL string) (string, string, error) {
+	// A source that served the audio after the enclosure was refused is tried
+	// first, so the refusing host isn't asked again on every download
+	if stored := s.storedSourceURL(ctx, podcastIndexEpisodeID, audioURL); stored != "" {
+		tempFile, err := s.downloadAudio(ctx, stored)
+		if err == nil {
+			return tempFile, stored, nil
+		}
+		if ctx.Err() != nil {
+			return "", "", err
+		}
+		log.Printf("[DEBUG] Stored source %s of Podcast Index episode %d failed: %v", stored, podcastIndexEpisodeID, err)
+	}
+
+	tempFile, err := s.downloadAudio(ctx, audioURL)
+	if err == nil || !download.IsForbidden(err) {
+		return tempFile, audioURL, err
+	}
+
+	candidates := download.FallbackURLs(audioURL, s.mirrors)
+	if s.alternates != nil {
+		alternates, altErr := s.alternates.AlternateAudioURLs(ctx, podcastIndexEpisodeID)
+		if altErr != nil {
+			log.Printf("[WARN] Failed to load alternate URLs for Podcast Index episode %d: %v", podcastIndexEpisodeID, altErr)
+		}
+		for _, alternate := range alternates {
+			if alternate != audioURL && !slices.Contains(candidates, alternate) {
+				candidates = append(candidates, alternate)
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		log.Printf("[INFO] Enclosure of Podcast Index episode %d was refused, trying %s", podcastIndexEpisodeID, candidate)
+		tempFile, fallbackErr := s.downloadAudio(ctx, candidate)
+		if fallbackErr == nil {
+			return tempFile, candidate, nil
+		}
+		if ctx.Err() != nil {
+			return "", "", fallbackErr
+		}
+		log.Printf("[DEBUG] Fallback %s failed: %v", candidate, fallbackErr)
+	}
+	return "", "", err
+}
+
+// storedSourceURL returns the episode's recorded source when it differs from
+// the enclosure URL
+func (s *ServiceImpl) storedSourceURL(ctx context.Context, podcastIndexEpisodeID int64, audioURL string) string {
+	stored, err := s.repository.EpisodeSourceURL(ctx, podcastIndexEpisodeID)
+	if err != nil {
+		log.Printf("[WARN] Failed to load stored audio source of Podcast Index episode %d: %v", podcastIndexEpisodeID, err)
+		return ""
+	}
+	if stored == audioURL {
+		return ""
+	}
+	return stored
+}
+
+// recordSourceURL keeps the URL that served the audio on the episode when it
+// isn't the enclosure, and clears it once the enclosure serves it again
+func (s *ServiceImpl) recordSourceURL(ctx context.Context, podcastIndexEpisodeID int64, audioURL, sourceURL string) {
+	if sourceURL == audioURL {
+		sourceURL = ""
+	}
+	if err := s.repository.RecordSourceURL(ctx, podcastIndexEpisodeID, sourceURL); err != nil {
+		log.Printf("[WARN] Failed to record audio source of Podcast Index episode %d: %v", podcastIndexEpisodeID, err)
+	}
+}
+
 // downloadAudio downloads audio from URL to temp file
 func (s *ServiceImpl) downloadAudio(ctx context.Context, url string) (string, error) {
 	// Create temp file
-	tempFile, err := os.CreateTemp("", "audio_download_*.mp3")
+	tempFile, err := os.CreateTemp(s.tempDir, "audio_download_*.mp3")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
@@ -306,7 +621,7 @@ func (s *ServiceImpl) downloadAudio(ctx context.Context, url string) (string, er
 
 	if resp.StatusCode != http.StatusOK {
 		os.Remove(tempFile.Name())
-		return "", fmt.Errorf("download failed with status %d", resp.StatusCode)
+		return "", &download.StatusError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("download failed with status %d", resp.StatusCode)}
 	}
 
 	// Copy to temp file
@@ -336,24 +651,6 @@ func (s *ServiceImpl) calculateSHA256(filepath string) (string, error) {
 }
 
 // getAudioDuration gets duration of audio file in seconds
-func (s *ServiceImpl) getAudioDuration(filepath string) (float64, error) {
-	cmd := exec.Command("ffprobe",
-		"-v", "error",
-		"-show_entries", "format=duration",
-		"-of", "default=noprint_wrappers=1:nokey=1",
-		filepath,
-	)
-
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, fmt.Errorf("ffprobe failed: %w", err)
-	}
-
-	durationStr := strings.TrimSpace(string(output))
-	duration, err := strconv.ParseFloat(durationStr, 64)
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse duration: %w", err)
-	}
-
-	return duration, nil
+func (s *ServiceImpl) getAudioDuration(ctx context.Context, filepath string) (float64, error) {
+	return s.transcoder.Duration(ctx, filepath)
 }
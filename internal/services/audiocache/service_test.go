@@ -3,12 +3,16 @@ package audiocache
 import (
 	"context"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/killallgit/player-api/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -64,6 +68,29 @@ func (m *MockRepository) GetStats(ctx context.Context) (*CacheStats, error) {
 	return args.Get(0).(*CacheStats), args.Error(1)
 }
 
+func (m *MockRepository) RecordEpisodeDuration(ctx context.Context, podcastIndexEpisodeID int64, seconds int) (*int, error) {
+	args := m.Called(ctx, podcastIndexEpisodeID, seconds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*int), args.Error(1)
+}
+
+func (m *MockRepository) EpisodeSourceURL(ctx context.Context, podcastIndexEpisodeID int64) (string, error) {
+	args := m.Called(ctx, podcastIndexEpisodeID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) RecordSourceURL(ctx context.Context, podcastIndexEpisodeID int64, sourceURL string) error {
+	args := m.Called(ctx, podcastIndexEpisodeID, sourceURL)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CountByProcessedPath(ctx context.Context, path string) (int64, error) {
+	args := m.Called(ctx, path)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockStorageBackend is a mock implementation of StorageBackend
 type MockStorageBackend struct {
 	mock.Mock
@@ -253,3 +280,193 @@ func TestGetCacheStats(t *testing.T) {
 	// Verify mock expectations
 	mockRepo.AssertExpectations(t)
 }
+
+func TestDurationMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     int
+		measured float64
+		want     bool
+	}{
+		{"exact", 3600, 3600, false},
+		{"within tolerance", 3700, 3600, false},
+		{"over tolerance", 3000, 3600, true},
+		{"feed overstates", 4000, 3600, true},
+		{"feed missing", 0, 3600, false},
+		{"nothing measured", 3600, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, durationMismatch(tt.feed, tt.measured))
+		})
+	}
+}
+
+func TestGetOrDownloadAudio_ReusesProbedMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not really audio"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, new(MockStorageBackend))
+
+	existing := &models.AudioCache{
+		ID:                 1,
+		OriginalSHA256:     "abc",
+		OriginalPath:       "/cache/original/1_abc.mp3",
+		ProcessedPath:      "/cache/processed/1_abc_16khz.mp3",
+		DurationSeconds:    1799.6,
+		SampleRate:         16000,
+		Codec:              "mp3",
+		Bitrate:            128000,
+		Channels:           2,
+		OriginalSampleRate: 44100,
+	}
+	feedDuration := 1500
+
+	mockRepo.On("GetByPodcastIndexEpisodeID", ctx, int64(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetBySHA256", ctx, mock.AnythingOfType("string")).Return(existing, nil)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*models.AudioCache")).Return(nil)
+	mockRepo.On("RecordEpisodeDuration", ctx, int64(2), 1800).Return(&feedDuration, nil)
+	mockRepo.On("EpisodeSourceURL", ctx, int64(2)).Return("", nil)
+	mockRepo.On("RecordSourceURL", ctx, int64(2), "").Return(nil)
+
+	cache, err := service.GetOrDownloadAudio(ctx, 2, server.URL+"/episode.mp3")
+	require.NoError(t, err)
+	assert.Equal(t, "mp3", cache.Codec)
+	assert.Equal(t, 128000, cache.Bitrate)
+	assert.Equal(t, 2, cache.Channels)
+	assert.Equal(t, 44100, cache.OriginalSampleRate)
+	assert.Equal(t, 1799.6, cache.DurationSeconds)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// alternateURLs is an AlternateSource listing the same URLs for every episode
+type alternateURLs []string
+
+func (a alternateURLs) AlternateAudioURLs(ctx context.Context, podcastIndexEpisodeID int64) ([]string, error) {
+	return a, nil
+}
+
+func TestGetOrDownloadAudio_FallsBackWhenRefused(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path != "/alternate.mp3" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("not really audio"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, new(MockStorageBackend), WithAlternateSource(alternateURLs{server.URL + "/alternate.mp3"}))
+
+	existing := &models.AudioCache{ID: 1, OriginalSHA256: "abc", OriginalPath: "/cache/original/1_abc.mp3"}
+	mockRepo.On("GetByPodcastIndexEpisodeID", ctx, int64(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetBySHA256", ctx, mock.AnythingOfType("string")).Return(existing, nil)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*models.AudioCache")).Return(nil)
+	mockRepo.On("EpisodeSourceURL", ctx, int64(2)).Return("", nil)
+	mockRepo.On("RecordSourceURL", ctx, int64(2), server.URL+"/alternate.mp3").Return(nil)
+
+	// The https form of the enclosure is tried first but fails its TLS handshake here, so the alternate serves it
+	enclosure := server.URL + "/episode.mp3"
+	cache, err := service.GetOrDownloadAudio(ctx, 2, enclosure)
+	require.NoError(t, err)
+	assert.Equal(t, enclosure, cache.OriginalURL)
+	assert.Equal(t, server.URL+"/alternate.mp3", cache.SourceURL)
+	assert.Equal(t, []string{"/episode.mp3", "/alternate.mp3"}, requested)
+	mockRepo.AssertCalled(t, "RecordSourceURL", ctx, int64(2), server.URL+"/alternate.mp3")
+
+	// Once recorded, the source that worked is tried before the refused enclosure
+	requested = nil
+	storedRepo := new(MockRepository)
+	storedRepo.On("GetByPodcastIndexEpisodeID", ctx, int64(2)).Return(nil, gorm.ErrRecordNotFound)
+	storedRepo.On("GetBySHA256", ctx, mock.AnythingOfType("string")).Return(existing, nil)
+	storedRepo.On("Create", ctx, mock.AnythingOfType("*models.AudioCache")).Return(nil)
+	storedRepo.On("EpisodeSourceURL", ctx, int64(2)).Return(server.URL+"/alternate.mp3", nil)
+	storedRepo.On("RecordSourceURL", ctx, int64(2), server.URL+"/alternate.mp3").Return(nil)
+	cache, err = NewService(storedRepo, new(MockStorageBackend)).GetOrDownloadAudio(ctx, 2, enclosure)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/alternate.mp3", cache.SourceURL)
+	assert.Equal(t, []string{"/alternate.mp3"}, requested)
+
+	// Other failures don't fall back
+	requested = nil
+	_, err = NewService(mockRepo, new(MockStorageBackend), WithAlternateSource(alternateURLs{server.URL + "/alternate.mp3"})).
+		GetOrDownloadAudio(ctx, 2, "http://127.0.0.1:1/closed.mp3")
+	assert.Error(t, err)
+	assert.Empty(t, requested)
+}
+
+func TestGetProcessedAudio(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reprocesses a rendition in an older format", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockStorage := new(MockStorageBackend)
+		service := NewService(mockRepo, mockStorage).(*ServiceImpl)
+
+		var processed []string
+		service.process = func(ctx context.Context, originalPath, outputPath string) error {
+			processed = append(processed, originalPath)
+			return os.WriteFile(outputPath, []byte("RIFF-pcm"), 0o644)
+		}
+
+		cache := &models.AudioCache{
+			ID:                    1,
+			PodcastIndexEpisodeID: 7,
+			OriginalSHA256:        "abcdef123456",
+			OriginalPath:          "/cache/original/7_abcdef12.mp3",
+			ProcessedPath:         "/cache/processed/7_abcdef12_16khz.mp3",
+			SampleRate:            16000,
+		}
+		mockRepo.On("GetByPodcastIndexEpisodeID", ctx, int64(7)).Return(cache, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*models.AudioCache")).Return(nil)
+		mockStorage.On("Save", ctx, mock.Anything, "processed/7_abcdef12_16khz.wav").Return("/cache/processed/7_abcdef12_16khz.wav", nil)
+		mockRepo.On("CountByProcessedPath", ctx, "/cache/processed/7_abcdef12_16khz.mp3").Return(int64(0), nil)
+		mockStorage.On("Delete", ctx, "/cache/processed/7_abcdef12_16khz.mp3").Return(nil)
+
+		result, err := service.GetProcessedAudio(ctx, 7, "https://example.com/7.mp3")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/cache/original/7_abcdef12.mp3"}, processed)
+		assert.True(t, result.HasCurrentProcessed())
+		assert.Equal(t, "/cache/processed/7_abcdef12_16khz.wav", result.ProcessedPath)
+		assert.Equal(t, int64(8), result.ProcessedSize)
+		assert.Len(t, result.ProcessedSHA256, 64)
+
+		mockRepo.AssertExpectations(t)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("reuses a current rendition", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockStorage := new(MockStorageBackend)
+		service := NewService(mockRepo, mockStorage).(*ServiceImpl)
+		service.process = func(ctx context.Context, originalPath, outputPath string) error {
+			t.Fatal("current rendition was processed again")
+			return nil
+		}
+
+		cache := &models.AudioCache{
+			ID:                1,
+			OriginalPath:      "/cache/original/7_abcdef12.mp3",
+			ProcessedPath:     "/cache/processed/7_abcdef12_16khz.wav",
+			ProcessedCodec:    models.ProcessedAudioCodec,
+			ProcessedChannels: models.ProcessedAudioChannels,
+			SampleRate:        models.ProcessedAudioSampleRate,
+		}
+		mockRepo.On("GetByPodcastIndexEpisodeID", ctx, int64(7)).Return(cache, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*models.AudioCache")).Return(nil)
+		mockStorage.On("Exists", ctx, cache.ProcessedPath).Return(true, nil)
+
+		result, err := service.GetProcessedAudio(ctx, 7, "https://example.com/7.mp3")
+		require.NoError(t, err)
+		assert.Equal(t, cache.ProcessedPath, result.ProcessedPath)
+	})
+}
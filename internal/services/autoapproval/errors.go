@@ -0,0 +1,11 @@
+package autoapproval
+
+import "errors"
+
+var (
+	// ErrThresholdNotFound is returned when a label has no stored threshold
+	ErrThresholdNotFound = errors.New("auto-approval threshold not found")
+
+	// ErrInvalidThreshold is returned for a label or threshold out of range
+	ErrInvalidThreshold = errors.New("invalid auto-approval threshold")
+)
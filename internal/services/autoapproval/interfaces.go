@@ -0,0 +1,49 @@
+package autoapproval
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Threshold sources
+const (
+	SourceConfig   = "config"   // clips.auto_approve_thresholds
+	SourceOverride = "override" // Set through the API
+)
+
+// Threshold is the confidence that auto-approves clips with a label, and
+// where it comes from
+type Threshold struct {
+	Label     string  `json:"label"`
+	Threshold float64 `json:"threshold"`
+	Source    string  `json:"source" enums:"config,override"`
+	UpdatedBy string  `json:"updated_by,omitempty"`
+}
+
+// Service manages the per-label confidence thresholds at or over which
+// episode analysis approves the clips it creates
+type Service interface {
+	// List returns the threshold in effect for every label that has one,
+	// by label
+	List(ctx context.Context) ([]Threshold, error)
+
+	// Set stores a threshold for label on behalf of userID, overriding config
+	Set(ctx context.Context, label string, threshold float64, userID string) (*Threshold, error)
+
+	// Delete removes the stored threshold for label, so config applies again
+	Delete(ctx context.Context, label string) error
+
+	// ThresholdFor returns the threshold in effect for label, and false when
+	// clips with it are never auto-approved
+	ThresholdFor(ctx context.Context, label string) (float64, bool, error)
+}
+
+// Repository defines threshold persistence
+type Repository interface {
+	List(ctx context.Context) ([]models.AutoApprovalThreshold, error)
+	Get(ctx context.Context, label string) (*models.AutoApprovalThreshold, error)
+	// Save creates or updates the threshold for its label
+	Save(ctx context.Context, threshold *models.AutoApprovalThreshold) error
+	Delete(ctx context.Context, label string) error
+}
@@ -0,0 +1,73 @@
+package autoapproval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new threshold repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// List returns all stored thresholds ordered by label
+func (r *repository) List(ctx context.Context) ([]models.AutoApprovalThreshold, error) {
+	var list []models.AutoApprovalThreshold
+	if err := r.db.WithContext(ctx).Order("label").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("listing auto-approval thresholds: %w", err)
+	}
+	return list, nil
+}
+
+// Get returns the stored threshold for a label
+func (r *repository) Get(ctx context.Context, label string) (*models.AutoApprovalThreshold, error) {
+	var threshold models.AutoApprovalThreshold
+	err := r.db.WithContext(ctx).Where("label = ?", label).First(&threshold).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrThresholdNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting auto-approval threshold: %w", err)
+	}
+	return &threshold, nil
+}
+
+// Save updates the label's row when there is one, else creates it
+func (r *repository) Save(ctx context.Context, threshold *models.AutoApprovalThreshold) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.AutoApprovalThreshold
+		err := tx.Where("label = ?", threshold.Label).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+		case err != nil:
+			return fmt.Errorf("getting auto-approval threshold: %w", err)
+		default:
+			threshold.ID = existing.ID
+			threshold.CreatedAt = existing.CreatedAt
+		}
+		if err := tx.Save(threshold).Error; err != nil {
+			return fmt.Errorf("saving auto-approval threshold: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete removes the stored threshold for a label
+func (r *repository) Delete(ctx context.Context, label string) error {
+	res := r.db.WithContext(ctx).Where("label = ?", label).Delete(&models.AutoApprovalThreshold{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting auto-approval threshold: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrThresholdNotFound
+	}
+	return nil
+}
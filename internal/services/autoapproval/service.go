@@ -0,0 +1,102 @@
+package autoapproval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// maxLabelLength matches the clips.label column
+const maxLabelLength = 100
+
+type service struct {
+	repo     Repository
+	defaults map[string]float64
+}
+
+// NewService creates a threshold service. defaults are the thresholds from
+// config, by label; entries out of range are ignored.
+func NewService(repo Repository, defaults map[string]float64) Service {
+	s := &service{repo: repo, defaults: make(map[string]float64, len(defaults))}
+	for label, threshold := range defaults {
+		label = strings.TrimSpace(label)
+		if validate(label, threshold) == nil {
+			s.defaults[label] = threshold
+		}
+	}
+	return s
+}
+
+// List merges the config thresholds with the stored ones, which win
+func (s *service) List(ctx context.Context) ([]Threshold, error) {
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byLabel := make(map[string]Threshold, len(s.defaults)+len(stored))
+	for label, threshold := range s.defaults {
+		byLabel[label] = Threshold{Label: label, Threshold: threshold, Source: SourceConfig}
+	}
+	for _, t := range stored {
+		byLabel[t.Label] = override(&t)
+	}
+
+	list := make([]Threshold, 0, len(byLabel))
+	for _, t := range byLabel {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Label < list[j].Label })
+	return list, nil
+}
+
+// Set validates and stores a label's threshold
+func (s *service) Set(ctx context.Context, label string, threshold float64, userID string) (*Threshold, error) {
+	label = strings.TrimSpace(label)
+	if err := validate(label, threshold); err != nil {
+		return nil, err
+	}
+	stored := &models.AutoApprovalThreshold{Label: label, Threshold: threshold, UpdatedBy: userID}
+	if err := s.repo.Save(ctx, stored); err != nil {
+		return nil, err
+	}
+	t := override(stored)
+	return &t, nil
+}
+
+// Delete removes a label's stored threshold
+func (s *service) Delete(ctx context.Context, label string) error {
+	return s.repo.Delete(ctx, strings.TrimSpace(label))
+}
+
+// ThresholdFor looks up the stored threshold for label, then the config one
+func (s *service) ThresholdFor(ctx context.Context, label string) (float64, bool, error) {
+	stored, err := s.repo.Get(ctx, label)
+	if err == nil {
+		return stored.Threshold, true, nil
+	}
+	if !errors.Is(err, ErrThresholdNotFound) {
+		return 0, false, err
+	}
+	threshold, ok := s.defaults[label]
+	return threshold, ok, nil
+}
+
+func override(t *models.AutoApprovalThreshold) Threshold {
+	return Threshold{Label: t.Label, Threshold: t.Threshold, Source: SourceOverride, UpdatedBy: t.UpdatedBy}
+}
+
+// validate checks a label fits the clips table and a threshold is a
+// confidence above zero; 0 would approve every scored clip
+func validate(label string, threshold float64) error {
+	if label == "" || len(label) > maxLabelLength {
+		return fmt.Errorf("%w: label must be 1-%d characters", ErrInvalidThreshold, maxLabelLength)
+	}
+	if !(threshold > 0 && threshold <= 1) {
+		return fmt.Errorf("%w: threshold must be over 0 and at most 1", ErrInvalidThreshold)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package autoapproval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestService(t *testing.T, defaults map[string]float64) Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.AutoApprovalThreshold{}))
+	return NewService(NewRepository(db), defaults)
+}
+
+func TestThresholdFor_OverrideWinsOverConfig(t *testing.T) {
+	svc := setupTestService(t, map[string]float64{"advertisement": 0.97, "music": 0.9, "broken": 1.5})
+	ctx := context.Background()
+
+	threshold, ok, err := svc.ThresholdFor(ctx, "advertisement")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 0.97, threshold)
+
+	_, ok, err = svc.ThresholdFor(ctx, "broken")
+	require.NoError(t, err)
+	assert.False(t, ok, "out of range config is ignored")
+
+	_, err = svc.Set(ctx, "advertisement", 0.99, "admin-1")
+	require.NoError(t, err)
+	_, err = svc.Set(ctx, " advertisement ", 0.95, "admin-2")
+	require.NoError(t, err)
+	threshold, ok, err = svc.ThresholdFor(ctx, "advertisement")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 0.95, threshold)
+
+	list, err := svc.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Threshold{
+		{Label: "advertisement", Threshold: 0.95, Source: SourceOverride, UpdatedBy: "admin-2"},
+		{Label: "music", Threshold: 0.9, Source: SourceConfig},
+	}, list)
+
+	// Deleting the override falls back to config
+	require.NoError(t, svc.Delete(ctx, "advertisement"))
+	threshold, _, err = svc.ThresholdFor(ctx, "advertisement")
+	require.NoError(t, err)
+	assert.Equal(t, 0.97, threshold)
+	assert.ErrorIs(t, svc.Delete(ctx, "advertisement"), ErrThresholdNotFound)
+}
+
+func TestSet_RejectsOutOfRange(t *testing.T) {
+	svc := setupTestService(t, nil)
+	ctx := context.Background()
+
+	for _, threshold := range []float64{0, -0.5, 1.01} {
+		_, err := svc.Set(ctx, "music", threshold, "admin-1")
+		assert.ErrorIs(t, err, ErrInvalidThreshold, "threshold %v", threshold)
+	}
+	_, err := svc.Set(ctx, "  ", 0.9, "admin-1")
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+
+	_, ok, err := svc.ThresholdFor(ctx, "music")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
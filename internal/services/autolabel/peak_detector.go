@@ -23,18 +23,21 @@ type VolumeStats struct {
 	SilenceDuration float64 // Duration of silence in seconds
 }
 
+// FFmpegRunner runs ffmpeg under the configured resource limits and returns
+// its stderr; *ffmpeg.FFmpeg satisfies it
+type FFmpegRunner interface {
+	Run(ctx context.Context, args ...string) (string, error)
+}
+
 // FFmpegPeakDetector implements PeakDetector using FFmpeg
 type FFmpegPeakDetector struct {
-	ffmpegPath string
+	ffmpeg FFmpegRunner
 }
 
 // NewFFmpegPeakDetector creates a new FFmpeg-based peak detector
-func NewFFmpegPeakDetector(ffmpegPath string) PeakDetector {
-	if ffmpegPath == "" {
-		ffmpegPath = "ffmpeg" // Use system PATH
-	}
+func NewFFmpegPeakDetector(runner FFmpegRunner) PeakDetector {
 	return &FFmpegPeakDetector{
-		ffmpegPath: ffmpegPath,
+		ffmpeg: runner,
 	}
 }
 
@@ -42,15 +45,13 @@ func NewFFmpegPeakDetector(ffmpegPath string) PeakDetector {
 func (d *FFmpegPeakDetector) DetectPeaks(ctx context.Context, audioPath string) (*VolumeStats, error) {
 	// Run FFmpeg with volumedetect filter
 	// Command: ffmpeg -i input.wav -af volumedetect -f null -
-	cmd := exec.CommandContext(ctx, d.ffmpegPath,
+	// FFmpeg writes volumedetect output to stderr, which Run returns
+	output, err := d.ffmpeg.Run(ctx,
 		"-i", audioPath,
 		"-af", "volumedetect,silencedetect=n=-50dB:d=0.5",
 		"-f", "null",
 		"-",
 	)
-
-	// FFmpeg writes volumedetect output to stderr
-	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("ffmpeg volumedetect failed: %w, output: %s", err, string(output))
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
 	"gorm.io/gorm"
 )
 
@@ -70,18 +71,21 @@ func (s *ServiceImpl) UpdateClipWithAutoLabel(ctx context.Context, clipUUID stri
 		return fmt.Errorf("failed to find clip: %w", err)
 	}
 
+	before := clip.Snapshot()
+
 	// Update autolabel fields
 	clip.AutoLabeled = true
 	clip.LabelConfidence = &result.Confidence
 	clip.LabelMethod = result.Method
 	clip.Label = result.Label
 
-	// Save to database
-	if err := s.db.Save(&clip).Error; err != nil {
-		return fmt.Errorf("failed to update clip: %w", err)
-	}
-
-	return nil
+	// Save to database along with the audit entry (system change, no user)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&clip).Error; err != nil {
+			return fmt.Errorf("failed to update clip: %w", err)
+		}
+		return clips.RecordAnnotationChange(tx, &clip, "", models.AnnotationActionAutoLabel, before, clip.Snapshot())
+	})
 }
 
 // classifyAudio applies heuristics to classify audio based on volume statistics
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
@@ -27,7 +28,7 @@ func TestPeakDetectorWithRealAudio(t *testing.T) {
 	t.Logf("Testing with audio file: %s", audioPath)
 
 	// Create peak detector
-	detector := NewFFmpegPeakDetector("")
+	detector := NewFFmpegPeakDetector(ffmpeg.New("ffmpeg", "ffprobe", 0))
 
 	// Detect peaks
 	ctx := context.Background()
@@ -65,11 +66,11 @@ func TestAutoLabelService(t *testing.T) {
 	require.NoError(t, err)
 
 	// Auto-migrate
-	err = db.AutoMigrate(&models.Clip{})
+	err = db.AutoMigrate(&models.Clip{}, &models.AnnotationAudit{})
 	require.NoError(t, err)
 
 	// Create autolabel service
-	detector := NewFFmpegPeakDetector("")
+	detector := NewFFmpegPeakDetector(ffmpeg.New("ffmpeg", "ffprobe", 0))
 	service := NewService(db, detector)
 
 	// Test AutoLabelClip
@@ -0,0 +1,11 @@
+package autoplay
+
+import "errors"
+
+var (
+	// ErrRulesNotFound is returned when a user has no stored rules
+	ErrRulesNotFound = errors.New("autoplay rules not found")
+
+	// ErrInvalidRules is returned when rules fail validation
+	ErrInvalidRules = errors.New("invalid autoplay rules")
+)
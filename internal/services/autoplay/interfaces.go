@@ -0,0 +1,127 @@
+package autoplay
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+)
+
+// Rule sources
+const (
+	// SourceSamePodcast picks an unplayed episode of the podcast just played
+	SourceSamePodcast = "same_podcast"
+
+	// SourceInbox picks the newest unplayed episode in the user's inbox
+	SourceInbox = "inbox"
+
+	// SourceInProgress resumes the episode the user most recently left unfinished
+	SourceInProgress = "in_progress"
+
+	// SourceStop ends autoplay; later rules are not evaluated
+	SourceStop = "stop"
+)
+
+// Orders for SourceSamePodcast
+const (
+	OrderNext   = "next"   // The episode published after the one just played
+	OrderOldest = "oldest" // The oldest unplayed episode
+	OrderNewest = "newest" // The newest unplayed episode
+)
+
+// Conditions on the episode just played
+const (
+	WhenAlways     = ""           // Whether or not it was finished
+	WhenFinished   = "finished"   // It was played to the end
+	WhenUnfinished = "unfinished" // It was skipped or stopped early
+)
+
+// Rule is one step of a user's autoplay rules. Rules are evaluated in order
+// and the first that finds an episode decides what plays next.
+type Rule struct {
+	Source string `json:"source"`           // same_podcast, inbox, in_progress or stop
+	When   string `json:"when,omitempty"`   // Only apply after a finished or unfinished episode
+	Order  string `json:"order,omitempty"`  // same_podcast only: next (default), oldest or newest
+	Folder string `json:"folder,omitempty"` // inbox only: limit to subscriptions in this folder
+}
+
+// DefaultRules apply to users who haven't set their own: continue the podcast
+// in release order, else the newest episode in the inbox
+var DefaultRules = []Rule{
+	{Source: SourceSamePodcast, Order: OrderNext},
+	{Source: SourceInbox},
+}
+
+// NextQuery describes the episode that just played
+type NextQuery struct {
+	// EpisodeID is the Podcast Index ID of the episode that just played; 0
+	// when nothing has played yet, which skips same_podcast rules
+	EpisodeID int64
+
+	// Finished overrides whether the episode was played to the end; when nil
+	// the user's stored progress decides
+	Finished *bool
+
+	// Skip, when set, rules out episodes the caller won't play (for example,
+	// blocked ones)
+	Skip func(episode *models.Episode) bool
+}
+
+// Decision is what plays next. Episode is nil when no rule found one.
+type Decision struct {
+	Episode   *models.Episode
+	Rule      *Rule // The rule that decided, nil when none did
+	RuleIndex int   // Position of Rule in the user's rules; -1 when none did
+}
+
+// InboxSource lists the user's inbox
+type InboxSource interface {
+	Inbox(ctx context.Context, userID string, query subscriptions.InboxQuery) (*subscriptions.Inbox, error)
+}
+
+// Service manages users' autoplay rules and evaluates them
+type Service interface {
+	// Rules returns the user's rules, or DefaultRules with custom false when
+	// the user hasn't set any
+	Rules(ctx context.Context, userID string) (rules []Rule, custom bool, err error)
+
+	// SetRules validates and stores the user's rules. An empty list turns
+	// autoplay off.
+	SetRules(ctx context.Context, userID string, rules []Rule) ([]Rule, error)
+
+	// ResetRules removes the user's rules so the defaults apply again
+	ResetRules(ctx context.Context, userID string) error
+
+	// Next evaluates the user's rules after the queried episode
+	Next(ctx context.Context, userID string, query NextQuery) (*Decision, error)
+}
+
+// Repository persists rules and reads the episodes they pick from
+type Repository interface {
+	// GetRules returns the user's stored rules
+	GetRules(ctx context.Context, userID string) (*models.AutoplayRules, error)
+
+	// SaveRules creates or replaces the user's rules
+	SaveRules(ctx context.Context, rules *models.AutoplayRules) error
+
+	// DeleteRules removes the user's rules
+	DeleteRules(ctx context.Context, userID string) error
+
+	// Episode returns a stored episode by Podcast Index ID, or nil
+	Episode(ctx context.Context, podcastIndexID int64) (*models.Episode, error)
+
+	// Finished reports whether the user has played the episode to the end
+	Finished(ctx context.Context, userID string, podcastIndexID int64) (bool, error)
+
+	// FinishedIDs returns which of the episodes the user has played to the end
+	FinishedIDs(ctx context.Context, userID string, podcastIndexIDs []int64) (map[int64]bool, error)
+
+	// UnplayedInPodcast returns stored episodes of a feed the user hasn't
+	// finished, oldest first when ascending and newest first otherwise.
+	// after, when not nil, keeps only episodes published after it.
+	UnplayedInPodcast(ctx context.Context, userID string, podcastIndexFeedID int64, after *models.Episode, ascending bool, limit int) ([]models.Episode, error)
+
+	// InProgress returns episodes the user started and didn't finish, most
+	// recently played first
+	InProgress(ctx context.Context, userID string, limit int) ([]models.Episode, error)
+}
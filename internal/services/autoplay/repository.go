@@ -0,0 +1,120 @@
+package autoplay
+
+import (
+	"context"
+	"errors"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new autoplay repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetRules returns the user's stored rules
+func (r *repository) GetRules(ctx context.Context, userID string) (*models.AutoplayRules, error) {
+	var rules models.AutoplayRules
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&rules).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRulesNotFound
+		}
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// SaveRules creates or replaces the user's rules
+func (r *repository) SaveRules(ctx context.Context, rules *models.AutoplayRules) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rules_data", "updated_at"}),
+	}).Create(rules).Error
+}
+
+// DeleteRules removes the user's rules
+func (r *repository) DeleteRules(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.AutoplayRules{}).Error
+}
+
+// Episode returns a stored episode by Podcast Index ID, or nil
+func (r *repository) Episode(ctx context.Context, podcastIndexID int64) (*models.Episode, error) {
+	var episode models.Episode
+	err := r.db.WithContext(ctx).Where("podcast_index_id = ?", podcastIndexID).First(&episode).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &episode, nil
+}
+
+// Finished reports whether the user has played the episode to the end
+func (r *repository) Finished(ctx context.Context, userID string, podcastIndexID int64) (bool, error) {
+	finished, err := r.FinishedIDs(ctx, userID, []int64{podcastIndexID})
+	return finished[podcastIndexID], err
+}
+
+// FinishedIDs returns which of the episodes the user has played to the end
+func (r *repository) FinishedIDs(ctx context.Context, userID string, podcastIndexIDs []int64) (map[int64]bool, error) {
+	finished := make(map[int64]bool)
+	if len(podcastIndexIDs) == 0 {
+		return finished, nil
+	}
+	var ids []int64
+	err := r.db.WithContext(ctx).Model(&models.PlaybackProgress{}).
+		Where("user_id = ? AND completed = ? AND podcast_index_episode_id IN ?", userID, true, podcastIndexIDs).
+		Pluck("podcast_index_episode_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		finished[id] = true
+	}
+	return finished, nil
+}
+
+// UnplayedInPodcast returns stored episodes of a feed the user hasn't finished
+func (r *repository) UnplayedInPodcast(ctx context.Context, userID string, podcastIndexFeedID int64, after *models.Episode, ascending bool, limit int) ([]models.Episode, error) {
+	finished := r.db.Model(&models.PlaybackProgress{}).
+		Select("podcast_index_episode_id").
+		Where("user_id = ? AND completed = ?", userID, true)
+
+	query := r.db.WithContext(ctx).
+		Where("podcast_index_feed_id = ? AND podcast_index_id NOT IN (?)", podcastIndexFeedID, finished)
+	if after != nil {
+		// Episodes released together are ordered by ID, so "next" still moves on
+		query = query.Where("published_at > ? OR (published_at = ? AND podcast_index_id > ?)",
+			after.PublishedAt, after.PublishedAt, after.PodcastIndexID)
+	}
+
+	order := "published_at DESC, podcast_index_id DESC"
+	if ascending {
+		order = "published_at ASC, podcast_index_id ASC"
+	}
+
+	var episodes []models.Episode
+	err := query.Order(order).Limit(limit).Find(&episodes).Error
+	return episodes, err
+}
+
+// InProgress returns episodes the user started and didn't finish, most
+// recently played first
+func (r *repository) InProgress(ctx context.Context, userID string, limit int) ([]models.Episode, error) {
+	var episodes []models.Episode
+	err := r.db.WithContext(ctx).
+		Joins("JOIN playback_progress ON playback_progress.podcast_index_episode_id = episodes.podcast_index_id").
+		Where("playback_progress.user_id = ? AND playback_progress.completed = ? AND playback_progress.position > 0", userID, false).
+		Order("playback_progress.reported_at DESC").
+		Limit(limit).
+		Find(&episodes).Error
+	return episodes, err
+}
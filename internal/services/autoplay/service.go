@@ -0,0 +1,230 @@
+package autoplay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+)
+
+const (
+	// MaxRules caps how many rules a user can set
+	MaxRules = 10
+
+	// MaxFolderLength matches the longest subscription folder name
+	MaxFolderLength = 50
+
+	// candidateLimit is how many episodes a rule considers, so skipped
+	// episodes don't exhaust it
+	candidateLimit = 50
+)
+
+// service implements the Service interface
+type service struct {
+	repo  Repository
+	inbox InboxSource
+}
+
+// NewService creates a new autoplay service. inbox may be nil, in which case
+// inbox rules never find an episode.
+func NewService(repo Repository, inbox InboxSource) Service {
+	return &service{repo: repo, inbox: inbox}
+}
+
+// Rules returns the user's rules, or the defaults
+func (s *service) Rules(ctx context.Context, userID string) ([]Rule, bool, error) {
+	stored, err := s.repo.GetRules(ctx, userID)
+	if errors.Is(err, ErrRulesNotFound) {
+		return defaultRules(), false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("loading autoplay rules: %w", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal([]byte(stored.RulesData), &rules); err != nil {
+		return nil, false, fmt.Errorf("decoding autoplay rules: %w", err)
+	}
+	return rules, true, nil
+}
+
+// SetRules validates and stores the user's rules
+func (s *service) SetRules(ctx context.Context, userID string, rules []Rule) ([]Rule, error) {
+	normalized, err := normalize(rules)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("encoding autoplay rules: %w", err)
+	}
+	if err := s.repo.SaveRules(ctx, &models.AutoplayRules{UserID: userID, RulesData: string(data)}); err != nil {
+		return nil, fmt.Errorf("saving autoplay rules: %w", err)
+	}
+	return normalized, nil
+}
+
+// ResetRules removes the user's rules so the defaults apply again
+func (s *service) ResetRules(ctx context.Context, userID string) error {
+	return s.repo.DeleteRules(ctx, userID)
+}
+
+// Next evaluates the user's rules after the queried episode
+func (s *service) Next(ctx context.Context, userID string, query NextQuery) (*Decision, error) {
+	rules, _, err := s.Rules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *models.Episode
+	if query.EpisodeID != 0 {
+		if current, err = s.repo.Episode(ctx, query.EpisodeID); err != nil {
+			return nil, fmt.Errorf("loading episode %d: %w", query.EpisodeID, err)
+		}
+	}
+	// Without an episode just played, rules conditioned on it never apply
+	played, finished := query.EpisodeID != 0 || query.Finished != nil, false
+	if query.Finished != nil {
+		finished = *query.Finished
+	} else if query.EpisodeID != 0 {
+		if finished, err = s.repo.Finished(ctx, userID, query.EpisodeID); err != nil {
+			return nil, fmt.Errorf("loading progress: %w", err)
+		}
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.When != WhenAlways && (!played || (rule.When == WhenFinished) != finished) {
+			continue
+		}
+		if rule.Source == SourceStop {
+			return &Decision{Rule: rule, RuleIndex: i}, nil
+		}
+
+		candidates, err := s.candidates(ctx, userID, rule, current)
+		if err != nil {
+			return nil, err
+		}
+		for j := range candidates {
+			episode := &candidates[j]
+			if episode.PodcastIndexID == query.EpisodeID || (query.Skip != nil && query.Skip(episode)) {
+				continue
+			}
+			return &Decision{Episode: episode, Rule: rule, RuleIndex: i}, nil
+		}
+	}
+	return &Decision{RuleIndex: -1}, nil
+}
+
+// candidates returns the episodes a rule would play, best first
+func (s *service) candidates(ctx context.Context, userID string, rule *Rule, current *models.Episode) ([]models.Episode, error) {
+	switch rule.Source {
+	case SourceSamePodcast:
+		if current == nil {
+			return nil, nil
+		}
+		var after *models.Episode
+		if rule.Order == OrderNext {
+			after = current
+		}
+		episodes, err := s.repo.UnplayedInPodcast(ctx, userID, current.PodcastIndexFeedID, after, rule.Order != OrderNewest, candidateLimit)
+		if err != nil {
+			return nil, fmt.Errorf("loading podcast episodes: %w", err)
+		}
+		return episodes, nil
+
+	case SourceInbox:
+		if s.inbox == nil {
+			return nil, nil
+		}
+		inbox, err := s.inbox.Inbox(ctx, userID, subscriptions.InboxQuery{
+			Filter: subscriptions.Filter{Folder: rule.Folder},
+			Limit:  candidateLimit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("loading inbox: %w", err)
+		}
+		episodes := make([]models.Episode, 0, len(inbox.Items))
+		ids := make([]int64, 0, len(inbox.Items))
+		for _, item := range inbox.Items {
+			episodes = append(episodes, item.Episode)
+			ids = append(ids, item.Episode.PodcastIndexID)
+		}
+		played, err := s.repo.FinishedIDs(ctx, userID, ids)
+		if err != nil {
+			return nil, fmt.Errorf("loading progress: %w", err)
+		}
+		unplayed := episodes[:0]
+		for _, episode := range episodes {
+			if !played[episode.PodcastIndexID] {
+				unplayed = append(unplayed, episode)
+			}
+		}
+		return unplayed, nil
+
+	case SourceInProgress:
+		episodes, err := s.repo.InProgress(ctx, userID, candidateLimit)
+		if err != nil {
+			return nil, fmt.Errorf("loading episodes in progress: %w", err)
+		}
+		return episodes, nil
+	}
+	return nil, nil
+}
+
+// normalize validates rules and fills in defaults, so stored rules read the
+// same way they are evaluated
+func normalize(rules []Rule) ([]Rule, error) {
+	if len(rules) > MaxRules {
+		return nil, fmt.Errorf("%w: at most %d rules", ErrInvalidRules, MaxRules)
+	}
+
+	normalized := make([]Rule, 0, len(rules))
+	for i, rule := range rules {
+		rule.Source = strings.ToLower(strings.TrimSpace(rule.Source))
+		rule.When = strings.ToLower(strings.TrimSpace(rule.When))
+		rule.Order = strings.ToLower(strings.TrimSpace(rule.Order))
+		rule.Folder = strings.TrimSpace(rule.Folder)
+
+		switch rule.When {
+		case WhenAlways, WhenFinished, WhenUnfinished:
+		default:
+			return nil, fmt.Errorf("%w: rule %d: when must be finished or unfinished", ErrInvalidRules, i+1)
+		}
+
+		switch rule.Source {
+		case SourceSamePodcast:
+			switch rule.Order {
+			case "":
+				rule.Order = OrderNext
+			case OrderNext, OrderOldest, OrderNewest:
+			default:
+				return nil, fmt.Errorf("%w: rule %d: order must be next, oldest or newest", ErrInvalidRules, i+1)
+			}
+		case SourceInbox:
+			if len(rule.Folder) > MaxFolderLength {
+				return nil, fmt.Errorf("%w: rule %d: folder is longer than %d characters", ErrInvalidRules, i+1, MaxFolderLength)
+			}
+		case SourceInProgress, SourceStop:
+		default:
+			return nil, fmt.Errorf("%w: rule %d: unknown source %q", ErrInvalidRules, i+1, rule.Source)
+		}
+		if rule.Order != "" && rule.Source != SourceSamePodcast {
+			return nil, fmt.Errorf("%w: rule %d: order only applies to same_podcast", ErrInvalidRules, i+1)
+		}
+		if rule.Folder != "" && rule.Source != SourceInbox {
+			return nil, fmt.Errorf("%w: rule %d: folder only applies to inbox", ErrInvalidRules, i+1)
+		}
+		normalized = append(normalized, rule)
+	}
+	return normalized, nil
+}
+
+// defaultRules returns a copy of DefaultRules that callers may modify
+func defaultRules() []Rule {
+	return append([]Rule(nil), DefaultRules...)
+}
@@ -0,0 +1,178 @@
+package autoplay
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeInbox returns the stored episodes of feed 200, newest first
+type fakeInbox struct {
+	db     *gorm.DB
+	folder string // Folder of the last query
+}
+
+func (f *fakeInbox) Inbox(ctx context.Context, userID string, query subscriptions.InboxQuery) (*subscriptions.Inbox, error) {
+	f.folder = query.Folder
+	var episodes []models.Episode
+	if err := f.db.Where("podcast_index_feed_id = ?", 200).Order("published_at DESC").Find(&episodes).Error; err != nil {
+		return nil, err
+	}
+	inbox := &subscriptions.Inbox{}
+	for _, episode := range episodes {
+		inbox.Items = append(inbox.Items, subscriptions.InboxItem{Episode: episode})
+	}
+	return inbox, nil
+}
+
+func setupService(t *testing.T) (Service, *gorm.DB, *fakeInbox) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Episode{}, &models.PlaybackProgress{}, &models.AutoplayRules{}))
+
+	// Feed 100 has episodes 1-4 released a day apart; feed 200 has 11 and 12
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for feed, ids := range map[int64][]int64{100: {1, 2, 3, 4}, 200: {11, 12}} {
+		podcast := models.Podcast{PodcastIndexID: feed, Title: "Show", FeedURL: fmt.Sprintf("https://example.com/%d.xml", feed)}
+		require.NoError(t, db.Create(&podcast).Error)
+		for i, id := range ids {
+			require.NoError(t, db.Create(&models.Episode{
+				PodcastID: podcast.ID, PodcastIndexID: id, PodcastIndexFeedID: feed,
+				Title: "Episode", GUID: fmt.Sprintf("guid-%d", id), AudioURL: fmt.Sprintf("https://example.com/%d.mp3", id),
+				PublishedAt: base.AddDate(0, 0, i),
+			}).Error)
+		}
+	}
+
+	inbox := &fakeInbox{db: db}
+	return NewService(NewRepository(db), inbox), db, inbox
+}
+
+func finish(t *testing.T, db *gorm.DB, userID string, episodeIDs ...int64) {
+	for _, id := range episodeIDs {
+		require.NoError(t, db.Create(&models.PlaybackProgress{UserID: userID, PodcastIndexEpisodeID: id, Completed: true, ReportedAt: time.Now()}).Error)
+	}
+}
+
+func TestNext_DefaultRules(t *testing.T) {
+	svc, db, _ := setupService(t)
+	ctx := context.Background()
+	finish(t, db, "user-1", 2, 3)
+
+	decision, err := svc.Next(ctx, "user-1", NextQuery{EpisodeID: 1})
+	require.NoError(t, err)
+	require.NotNil(t, decision.Episode)
+	assert.Equal(t, int64(4), decision.Episode.PodcastIndexID, "finished episodes are skipped")
+	assert.Equal(t, 0, decision.RuleIndex)
+
+	// Nothing newer in the podcast, so the inbox decides
+	decision, err = svc.Next(ctx, "user-1", NextQuery{EpisodeID: 4})
+	require.NoError(t, err)
+	require.NotNil(t, decision.Episode)
+	assert.Equal(t, int64(12), decision.Episode.PodcastIndexID)
+	assert.Equal(t, SourceInbox, decision.Rule.Source)
+
+	finish(t, db, "user-1", 12)
+	decision, err = svc.Next(ctx, "user-1", NextQuery{
+		EpisodeID: 4,
+		Skip:      func(episode *models.Episode) bool { return episode.PodcastIndexID == 11 },
+	})
+	require.NoError(t, err)
+	assert.Nil(t, decision.Episode)
+	assert.Equal(t, -1, decision.RuleIndex)
+}
+
+func TestNext_Conditions(t *testing.T) {
+	svc, db, inbox := setupService(t)
+	ctx := context.Background()
+
+	_, err := svc.SetRules(ctx, "user-1", []Rule{
+		{Source: "same_podcast", When: "finished", Order: "oldest"},
+		{Source: "stop", When: "unfinished"},
+		{Source: "inbox", Folder: "News"},
+	})
+	require.NoError(t, err)
+
+	decision, err := svc.Next(ctx, "user-1", NextQuery{EpisodeID: 3})
+	require.NoError(t, err)
+	assert.Nil(t, decision.Episode, "an unfinished episode stops autoplay")
+	assert.Equal(t, 1, decision.RuleIndex)
+
+	finish(t, db, "user-1", 3)
+	decision, err = svc.Next(ctx, "user-1", NextQuery{EpisodeID: 3})
+	require.NoError(t, err)
+	require.NotNil(t, decision.Episode)
+	assert.Equal(t, int64(1), decision.Episode.PodcastIndexID)
+
+	unfinished := false
+	decision, err = svc.Next(ctx, "user-1", NextQuery{EpisodeID: 3, Finished: &unfinished})
+	require.NoError(t, err)
+	assert.Equal(t, SourceStop, decision.Rule.Source, "the client's word overrides stored progress")
+
+	decision, err = svc.Next(ctx, "user-1", NextQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, SourceInbox, decision.Rule.Source, "same_podcast needs an episode")
+	assert.Equal(t, "News", inbox.folder)
+}
+
+func TestNext_InProgress(t *testing.T) {
+	svc, db, _ := setupService(t)
+	ctx := context.Background()
+	require.NoError(t, db.Create(&models.PlaybackProgress{UserID: "user-1", PodcastIndexEpisodeID: 2, Position: 60, ReportedAt: time.Now().Add(-time.Hour)}).Error)
+	require.NoError(t, db.Create(&models.PlaybackProgress{UserID: "user-1", PodcastIndexEpisodeID: 11, Position: 30, ReportedAt: time.Now()}).Error)
+
+	_, err := svc.SetRules(ctx, "user-1", []Rule{{Source: "in_progress"}})
+	require.NoError(t, err)
+
+	decision, err := svc.Next(ctx, "user-1", NextQuery{EpisodeID: 11})
+	require.NoError(t, err)
+	require.NotNil(t, decision.Episode)
+	assert.Equal(t, int64(2), decision.Episode.PodcastIndexID, "the episode just played isn't resumed")
+}
+
+func TestRules(t *testing.T) {
+	svc, _, _ := setupService(t)
+	ctx := context.Background()
+
+	rules, custom, err := svc.Rules(ctx, "user-1")
+	require.NoError(t, err)
+	assert.False(t, custom)
+	assert.Equal(t, DefaultRules, rules)
+
+	saved, err := svc.SetRules(ctx, "user-1", []Rule{{Source: " Same_Podcast "}})
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{{Source: SourceSamePodcast, Order: OrderNext}}, saved)
+
+	_, err = svc.SetRules(ctx, "user-1", []Rule{})
+	require.NoError(t, err)
+	rules, custom, err = svc.Rules(ctx, "user-1")
+	require.NoError(t, err)
+	assert.True(t, custom)
+	assert.Empty(t, rules, "an empty list turns autoplay off")
+
+	require.NoError(t, svc.ResetRules(ctx, "user-1"))
+	_, custom, err = svc.Rules(ctx, "user-1")
+	require.NoError(t, err)
+	assert.False(t, custom)
+
+	for _, invalid := range [][]Rule{
+		{{Source: "random"}},
+		{{Source: "inbox", Order: "oldest"}},
+		{{Source: "same_podcast", Folder: "News"}},
+		{{Source: "same_podcast", Order: "shuffle"}},
+		{{Source: "inbox", When: "sometimes"}},
+		make([]Rule, MaxRules+1),
+	} {
+		_, err := svc.SetRules(ctx, "user-1", invalid)
+		assert.ErrorIs(t, err, ErrInvalidRules, "%v", invalid)
+	}
+}
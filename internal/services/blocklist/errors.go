@@ -0,0 +1,14 @@
+package blocklist
+
+import "errors"
+
+var (
+	// ErrEntryNotFound is returned when removing an entry that doesn't exist
+	ErrEntryNotFound = errors.New("blocklist entry not found")
+
+	// ErrInvalidEntry is returned for an unknown kind or a malformed value
+	ErrInvalidEntry = errors.New("invalid blocklist entry")
+
+	// ErrDuplicateEntry is returned when the kind and value are already blocked
+	ErrDuplicateEntry = errors.New("blocklist entry already exists")
+)
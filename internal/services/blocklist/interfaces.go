@@ -0,0 +1,54 @@
+package blocklist
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Subject is a podcast or episode as the blocklist sees it
+type Subject struct {
+	FeedID     int64    // Podcast Index feed ID; 0 if unknown
+	URLs       []string // Feed, link and enclosure URLs, matched against blocked domains
+	Categories []string // Category names and IDs
+}
+
+// Service manages the blocklist and checks content against it. Entries are
+// held in memory, so Match is cheap enough to call for every search result.
+type Service interface {
+	// List returns all entries, newest first
+	List(ctx context.Context) ([]models.BlocklistEntry, error)
+
+	// Add blocks a feed ID, domain or category on behalf of userID
+	Add(ctx context.Context, kind, value, reason, userID string) (*models.BlocklistEntry, error)
+
+	// Remove unblocks an entry on behalf of userID
+	Remove(ctx context.Context, id uint, userID string) error
+
+	// Match returns the entry that blocks subject, or nil if it may be shown
+	Match(subject Subject) *models.BlocklistEntry
+
+	// BlockedCategories lists blocked categories, for Podcast Index endpoints
+	// that can exclude them upstream (notcat)
+	BlockedCategories() []string
+
+	// RecordOverride audits an admin request that was shown blocked content
+	RecordOverride(ctx context.Context, userID, path string) error
+
+	// AuditLog returns the most recent audit rows, newest first
+	AuditLog(ctx context.Context, limit int) ([]models.BlocklistAudit, error)
+
+	// Reload refreshes the in-memory entries from the database
+	Reload(ctx context.Context) error
+}
+
+// Repository defines blocklist persistence
+type Repository interface {
+	ListEntries(ctx context.Context) ([]models.BlocklistEntry, error)
+	// CreateEntry stores entry and its audit row together
+	CreateEntry(ctx context.Context, entry *models.BlocklistEntry, audit *models.BlocklistAudit) error
+	// DeleteEntry removes an entry and stores its audit row, returning the removed entry
+	DeleteEntry(ctx context.Context, id uint, audit *models.BlocklistAudit) (*models.BlocklistEntry, error)
+	CreateAudit(ctx context.Context, audit *models.BlocklistAudit) error
+	ListAudits(ctx context.Context, limit int) ([]models.BlocklistAudit, error)
+}
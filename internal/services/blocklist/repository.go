@@ -0,0 +1,91 @@
+package blocklist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new blocklist repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ListEntries returns all entries, newest first
+func (r *repository) ListEntries(ctx context.Context) ([]models.BlocklistEntry, error) {
+	var entries []models.BlocklistEntry
+	if err := r.db.WithContext(ctx).Order("id DESC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("listing blocklist entries: %w", err)
+	}
+	return entries, nil
+}
+
+// CreateEntry stores entry and its audit row in one transaction
+func (r *repository) CreateEntry(ctx context.Context, entry *models.BlocklistEntry, audit *models.BlocklistAudit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+		if res.Error != nil {
+			return fmt.Errorf("creating blocklist entry: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrDuplicateEntry
+		}
+		audit.EntryID = entry.ID
+		if err := tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("creating blocklist audit: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteEntry removes an entry and stores its audit row in one transaction
+func (r *repository) DeleteEntry(ctx context.Context, id uint, audit *models.BlocklistAudit) (*models.BlocklistEntry, error) {
+	var entry models.BlocklistEntry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&entry, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEntryNotFound
+			}
+			return fmt.Errorf("finding blocklist entry: %w", err)
+		}
+		if err := tx.Delete(&entry).Error; err != nil {
+			return fmt.Errorf("deleting blocklist entry: %w", err)
+		}
+		audit.EntryID = entry.ID
+		audit.Kind = entry.Kind
+		audit.Value = entry.Value
+		if err := tx.Create(audit).Error; err != nil {
+			return fmt.Errorf("creating blocklist audit: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateAudit stores an audit row
+func (r *repository) CreateAudit(ctx context.Context, audit *models.BlocklistAudit) error {
+	if err := r.db.WithContext(ctx).Create(audit).Error; err != nil {
+		return fmt.Errorf("creating blocklist audit: %w", err)
+	}
+	return nil
+}
+
+// ListAudits returns up to limit audit rows, newest first
+func (r *repository) ListAudits(ctx context.Context, limit int) ([]models.BlocklistAudit, error) {
+	var audits []models.BlocklistAudit
+	if err := r.db.WithContext(ctx).Order("id DESC").Limit(limit).Find(&audits).Error; err != nil {
+		return nil, fmt.Errorf("listing blocklist audits: %w", err)
+	}
+	return audits, nil
+}
@@ -0,0 +1,263 @@
+package blocklist
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// MaxAuditLimit caps how many audit rows one request returns
+const MaxAuditLimit = 500
+
+// Option configures optional service behaviour
+type Option func(*service)
+
+// WithChangeHook calls hook after every change to the blocklist, e.g. to drop
+// cached responses that may contain content that is now blocked
+func WithChangeHook(hook func(ctx context.Context)) Option {
+	return func(s *service) {
+		s.onChange = hook
+	}
+}
+
+type service struct {
+	repo     Repository
+	onChange func(ctx context.Context)
+
+	mu         sync.RWMutex
+	feeds      map[int64]*models.BlocklistEntry
+	domains    map[string]*models.BlocklistEntry
+	categories map[string]*models.BlocklistEntry
+}
+
+// NewService creates a blocklist service. Call Reload before relying on Match.
+func NewService(repo Repository, opts ...Option) Service {
+	s := &service{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.index(nil)
+	return s
+}
+
+// List returns all entries, newest first
+func (s *service) List(ctx context.Context) ([]models.BlocklistEntry, error) {
+	return s.repo.ListEntries(ctx)
+}
+
+// Add validates and stores an entry, then makes it take effect immediately
+func (s *service) Add(ctx context.Context, kind, value, reason, userID string) (*models.BlocklistEntry, error) {
+	normalized, err := Normalize(kind, value)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.BlocklistEntry{
+		Kind:      kind,
+		Value:     normalized,
+		Reason:    strings.TrimSpace(reason),
+		CreatedBy: userID,
+	}
+	audit := &models.BlocklistAudit{
+		UserID: userID,
+		Action: models.BlocklistActionAdd,
+		Kind:   kind,
+		Value:  normalized,
+		Reason: entry.Reason,
+	}
+	if err := s.repo.CreateEntry(ctx, entry, audit); err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] Blocklist: %s blocked %s %q", userID, kind, normalized)
+
+	if err := s.changed(ctx); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Remove deletes an entry, then lifts it immediately
+func (s *service) Remove(ctx context.Context, id uint, userID string) error {
+	entry, err := s.repo.DeleteEntry(ctx, id, &models.BlocklistAudit{
+		UserID: userID,
+		Action: models.BlocklistActionRemove,
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Blocklist: %s unblocked %s %q", userID, entry.Kind, entry.Value)
+
+	return s.changed(ctx)
+}
+
+// changed reloads the entries and runs the change hook
+func (s *service) changed(ctx context.Context) error {
+	if err := s.Reload(ctx); err != nil {
+		return err
+	}
+	if s.onChange != nil {
+		s.onChange(ctx)
+	}
+	return nil
+}
+
+// Match returns the entry that blocks subject, checking feed ID, then URL
+// hosts and their parent domains, then categories
+func (s *service) Match(subject Subject) *models.BlocklistEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if entry, ok := s.feeds[subject.FeedID]; ok && subject.FeedID != 0 {
+		return entry
+	}
+	if len(s.domains) > 0 {
+		for _, raw := range subject.URLs {
+			if entry := s.matchHost(raw); entry != nil {
+				return entry
+			}
+		}
+	}
+	if len(s.categories) > 0 {
+		for _, category := range subject.Categories {
+			if entry, ok := s.categories[strings.ToLower(strings.TrimSpace(category))]; ok {
+				return entry
+			}
+		}
+	}
+	return nil
+}
+
+// matchHost checks a URL's host and each parent domain; callers hold mu
+func (s *service) matchHost(raw string) *models.BlocklistEntry {
+	if raw == "" {
+		return nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	for host != "" {
+		if entry, ok := s.domains[host]; ok {
+			return entry
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return nil
+}
+
+// BlockedCategories returns the blocked categories, sorted
+func (s *service) BlockedCategories() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	categories := make([]string, 0, len(s.categories))
+	for category := range s.categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// RecordOverride audits an admin request that was shown blocked content
+func (s *service) RecordOverride(ctx context.Context, userID, path string) error {
+	log.Printf("[INFO] Blocklist: %s overrode the blocklist on %s", userID, path)
+	return s.repo.CreateAudit(ctx, &models.BlocklistAudit{
+		UserID: userID,
+		Action: models.BlocklistActionOverride,
+		Path:   path,
+	})
+}
+
+// AuditLog returns the most recent audit rows, newest first
+func (s *service) AuditLog(ctx context.Context, limit int) ([]models.BlocklistAudit, error) {
+	if limit <= 0 || limit > MaxAuditLimit {
+		limit = MaxAuditLimit
+	}
+	return s.repo.ListAudits(ctx, limit)
+}
+
+// Reload refreshes the in-memory entries from the database
+func (s *service) Reload(ctx context.Context) error {
+	entries, err := s.repo.ListEntries(ctx)
+	if err != nil {
+		return err
+	}
+	s.index(entries)
+	return nil
+}
+
+// index swaps in lookup maps built from entries
+func (s *service) index(entries []models.BlocklistEntry) {
+	feeds := make(map[int64]*models.BlocklistEntry)
+	domains := make(map[string]*models.BlocklistEntry)
+	categories := make(map[string]*models.BlocklistEntry)
+	for i := range entries {
+		entry := &entries[i]
+		switch entry.Kind {
+		case models.BlockKindFeed:
+			if id, err := strconv.ParseInt(entry.Value, 10, 64); err == nil {
+				feeds[id] = entry
+			}
+		case models.BlockKindDomain:
+			domains[entry.Value] = entry
+		case models.BlockKindCategory:
+			categories[entry.Value] = entry
+		}
+	}
+
+	s.mu.Lock()
+	s.feeds, s.domains, s.categories = feeds, domains, categories
+	s.mu.Unlock()
+}
+
+// Normalize validates value for kind and returns the form it is stored and
+// matched in. Domains may be given as URLs; their host is kept.
+func Normalize(kind, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("%w: value is required", ErrInvalidEntry)
+	}
+
+	switch kind {
+	case models.BlockKindFeed:
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return "", fmt.Errorf("%w: feed must be a Podcast Index feed ID", ErrInvalidEntry)
+		}
+		return strconv.FormatInt(id, 10), nil
+
+	case models.BlockKindDomain:
+		host := strings.ToLower(value)
+		if strings.Contains(host, "://") {
+			parsed, err := url.Parse(host)
+			if err != nil {
+				return "", fmt.Errorf("%w: invalid URL", ErrInvalidEntry)
+			}
+			host = parsed.Hostname()
+		}
+		host = strings.TrimSuffix(host, ".")
+		if !strings.Contains(host, ".") || strings.ContainsAny(host, " /:@") || len(host) > 253 {
+			return "", fmt.Errorf("%w: %q is not a domain", ErrInvalidEntry, value)
+		}
+		return host, nil
+
+	case models.BlockKindCategory:
+		if len(value) > 64 {
+			return "", fmt.Errorf("%w: category is too long", ErrInvalidEntry)
+		}
+		return strings.ToLower(value), nil
+	}
+	return "", fmt.Errorf("%w: kind must be feed, domain or category", ErrInvalidEntry)
+}
@@ -0,0 +1,109 @@
+package blocklist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.BlocklistEntry{}, &models.BlocklistAudit{}))
+	return db
+}
+
+func TestNormalize(t *testing.T) {
+	valid := []struct{ kind, value, want string }{
+		{models.BlockKindFeed, " 0042 ", "42"},
+		{models.BlockKindDomain, "Feeds.Example.com.", "feeds.example.com"},
+		{models.BlockKindDomain, "https://cdn.example.com/rss.xml", "cdn.example.com"},
+		{models.BlockKindCategory, " Religion ", "religion"},
+	}
+	for _, tc := range valid {
+		got, err := Normalize(tc.kind, tc.value)
+		require.NoError(t, err, tc.value)
+		assert.Equal(t, tc.want, got)
+	}
+
+	invalid := []struct{ kind, value string }{
+		{models.BlockKindFeed, "abc"},
+		{models.BlockKindFeed, "-1"},
+		{models.BlockKindDomain, "localhost"},
+		{models.BlockKindDomain, "bad domain.com"},
+		{models.BlockKindCategory, ""},
+		{"author", "someone"},
+	}
+	for _, tc := range invalid {
+		_, err := Normalize(tc.kind, tc.value)
+		assert.ErrorIs(t, err, ErrInvalidEntry, tc.value)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	_, err := svc.Add(ctx, models.BlockKindFeed, "100", "spam", "admin")
+	require.NoError(t, err)
+	_, err = svc.Add(ctx, models.BlockKindDomain, "example.com", "", "admin")
+	require.NoError(t, err)
+	_, err = svc.Add(ctx, models.BlockKindCategory, "Religion", "", "admin")
+	require.NoError(t, err)
+
+	assert.NotNil(t, svc.Match(Subject{FeedID: 100}))
+	assert.NotNil(t, svc.Match(Subject{URLs: []string{"https://media.cdn.example.com/ep.mp3"}}))
+	assert.NotNil(t, svc.Match(Subject{Categories: []string{"News", "RELIGION"}}))
+
+	assert.Nil(t, svc.Match(Subject{FeedID: 101}))
+	assert.Nil(t, svc.Match(Subject{URLs: []string{"https://notexample.com/ep.mp3"}}))
+	assert.Nil(t, svc.Match(Subject{Categories: []string{"News"}}))
+
+	assert.Equal(t, []string{"religion"}, svc.BlockedCategories())
+}
+
+func TestAdd_Duplicate(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	_, err := svc.Add(ctx, models.BlockKindDomain, "example.com", "", "admin")
+	require.NoError(t, err)
+	_, err = svc.Add(ctx, models.BlockKindDomain, "EXAMPLE.com", "", "admin")
+	assert.ErrorIs(t, err, ErrDuplicateEntry)
+}
+
+func TestRemove_AuditsAndRunsHook(t *testing.T) {
+	var changes int
+	svc := NewService(NewRepository(setupTestDB(t)), WithChangeHook(func(ctx context.Context) {
+		changes++
+	}))
+	ctx := context.Background()
+
+	entry, err := svc.Add(ctx, models.BlockKindFeed, "100", "spam", "alice")
+	require.NoError(t, err)
+	require.NoError(t, svc.Remove(ctx, entry.ID, "bob"))
+	assert.Nil(t, svc.Match(Subject{FeedID: 100}))
+	assert.Equal(t, 2, changes)
+
+	assert.ErrorIs(t, svc.Remove(ctx, entry.ID, "bob"), ErrEntryNotFound)
+
+	require.NoError(t, svc.RecordOverride(ctx, "alice", "/api/v1/search"))
+
+	audits, err := svc.AuditLog(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, audits, 3)
+	assert.Equal(t, models.BlocklistActionOverride, audits[0].Action)
+	assert.Equal(t, models.BlocklistActionRemove, audits[1].Action)
+	assert.Equal(t, "bob", audits[1].UserID)
+	assert.Equal(t, "100", audits[1].Value)
+	assert.Equal(t, models.BlocklistActionAdd, audits[2].Action)
+	assert.Equal(t, "spam", audits[2].Reason)
+}
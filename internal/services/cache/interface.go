@@ -25,13 +25,23 @@ type Cache interface {
 
 // CacheStats provides statistics about cache usage
 type CacheStats struct {
-	Hits      int64
-	Misses    int64
-	Sets      int64
-	Deletes   int64
-	Evictions int64
-	Size      int64
-	MaxSize   int64
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`
+	Sets        int64 `json:"sets"`
+	Deletes     int64 `json:"deletes"`
+	Evictions   int64 `json:"evictions"`   // Entries dropped to make room
+	Expirations int64 `json:"expirations"` // Entries dropped once their TTL passed
+	Entries     int64 `json:"entries"`
+	Size        int64 `json:"size_bytes"`
+	MaxSize     int64 `json:"max_size_bytes"` // 0 when unbounded
+}
+
+// HitRatio returns the share of lookups that were hits, or 0 before any lookup
+func (s CacheStats) HitRatio() float64 {
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		return float64(s.Hits) / float64(lookups)
+	}
+	return 0
 }
 
 // StatsProvider interface for caches that provide statistics
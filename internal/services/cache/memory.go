@@ -1,17 +1,29 @@
 package cache
 
 import (
+	"container/list"
 	"context"
+	"errors"
 	"sync"
-	"sync/atomic"
 	"time"
+	"unsafe"
 )
 
-// MemoryCache implements an in-memory cache
+// ErrEntryTooLarge is returned by Set for a value that could never fit in the cache
+var ErrEntryTooLarge = errors.New("cache entry larger than the cache")
+
+// entryOverhead is the memory an entry takes beyond its key and value: its
+// item, its list element and its map slot (a string header plus a pointer)
+const entryOverhead = int64(unsafe.Sizeof(cacheItem{}) + unsafe.Sizeof(list.Element{}) + unsafe.Sizeof("") + unsafe.Sizeof(uintptr(0)))
+
+// MemoryCache implements an in-memory LRU cache bounded by the total size of
+// its entries. Expired entries are removed by a background sweep or when read;
+// when a new entry doesn't fit, the least recently used entries are evicted.
 type MemoryCache struct {
-	mu          sync.RWMutex
-	items       map[string]*cacheItem
-	maxSizeMB   int64
+	mu          sync.Mutex
+	items       map[string]*list.Element // Values are *cacheItem
+	lru         *list.List               // Most recently used at the front
+	maxSize     int64                    // Bytes; 0 means unbounded
 	currentSize int64
 	stats       CacheStats
 	stopCh      chan struct{}
@@ -19,17 +31,20 @@ type MemoryCache struct {
 }
 
 type cacheItem struct {
+	key    string
 	value  []byte
 	expiry time.Time
 	size   int64
 }
 
-// NewMemoryCache creates a new in-memory cache
+// NewMemoryCache creates a new in-memory cache holding at most maxSizeMB
+// megabytes of entries
 func NewMemoryCache(maxSizeMB int64) *MemoryCache {
 	mc := &MemoryCache{
-		items:     make(map[string]*cacheItem),
-		maxSizeMB: maxSizeMB * 1024 * 1024, // Convert MB to bytes
-		stopCh:    make(chan struct{}),
+		items:   make(map[string]*list.Element),
+		lru:     list.New(),
+		maxSize: maxSizeMB * 1024 * 1024, // Convert MB to bytes
+		stopCh:  make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -39,25 +54,33 @@ func NewMemoryCache(maxSizeMB int64) *MemoryCache {
 	return mc
 }
 
+// entrySize is the memory an entry holds on to. The value's capacity counts,
+// not its length, since the whole backing array stays alive.
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key)+cap(value)) + entryOverhead
+}
+
 // Get retrieves a value from the cache
 func (mc *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool) {
-	mc.mu.RLock()
-	item, exists := mc.items[key]
-	mc.mu.RUnlock()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 
+	elem, exists := mc.items[key]
 	if !exists {
-		atomic.AddInt64(&mc.stats.Misses, 1)
+		mc.stats.Misses++
 		return nil, false
 	}
 
-	// Check if expired
+	item := elem.Value.(*cacheItem)
 	if time.Now().After(item.expiry) {
-		_ = mc.Delete(ctx, key)
-		atomic.AddInt64(&mc.stats.Misses, 1)
+		mc.remove(elem)
+		mc.stats.Expirations++
+		mc.stats.Misses++
 		return nil, false
 	}
 
-	atomic.AddInt64(&mc.stats.Hits, 1)
+	mc.lru.MoveToFront(elem)
+	mc.stats.Hits++
 	return item.value, true
 }
 
@@ -67,37 +90,36 @@ func (mc *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl ti
 		ttl = 30 * time.Minute // Default TTL
 	}
 
-	size := int64(len(key) + len(value))
+	size := entrySize(key, value)
+	if mc.maxSize > 0 && size > mc.maxSize {
+		return ErrEntryTooLarge
+	}
 
-	// Check if we need to make room
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if elem, exists := mc.items[key]; exists {
+		mc.remove(elem)
+	}
 	mc.makeRoom(size)
 
-	item := &cacheItem{
+	mc.items[key] = mc.lru.PushFront(&cacheItem{
+		key:    key,
 		value:  value,
 		expiry: time.Now().Add(ttl),
 		size:   size,
-	}
-
-	mc.mu.Lock()
-	// If replacing, adjust size
-	if oldItem, exists := mc.items[key]; exists {
-		atomic.AddInt64(&mc.currentSize, -(oldItem.size))
-	}
-	mc.items[key] = item
-	atomic.AddInt64(&mc.currentSize, size)
-	mc.mu.Unlock()
-
-	atomic.AddInt64(&mc.stats.Sets, 1)
+	})
+	mc.currentSize += size
+	mc.stats.Sets++
 	return nil
 }
 
 // Delete removes a value from the cache
 func (mc *MemoryCache) Delete(ctx context.Context, key string) error {
 	mc.mu.Lock()
-	if item, exists := mc.items[key]; exists {
-		delete(mc.items, key)
-		atomic.AddInt64(&mc.currentSize, -(item.size))
-		atomic.AddInt64(&mc.stats.Deletes, 1)
+	if elem, exists := mc.items[key]; exists {
+		mc.remove(elem)
+		mc.stats.Deletes++
 	}
 	mc.mu.Unlock()
 	return nil
@@ -106,29 +128,31 @@ func (mc *MemoryCache) Delete(ctx context.Context, key string) error {
 // Clear removes all values from the cache
 func (mc *MemoryCache) Clear(ctx context.Context) error {
 	mc.mu.Lock()
-	mc.items = make(map[string]*cacheItem)
-	atomic.StoreInt64(&mc.currentSize, 0)
+	mc.items = make(map[string]*list.Element)
+	mc.lru.Init()
+	mc.currentSize = 0
 	mc.mu.Unlock()
 	return nil
 }
 
-// Has checks if a key exists in the cache
+// Has checks if a key exists in the cache. It doesn't count as a use.
 func (mc *MemoryCache) Has(ctx context.Context, key string) bool {
-	mc.mu.RLock()
-	item, exists := mc.items[key]
-	mc.mu.RUnlock()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 
-	if exists && time.Now().Before(item.expiry) {
-		return true
-	}
-	return false
+	elem, exists := mc.items[key]
+	return exists && time.Now().Before(elem.Value.(*cacheItem).expiry)
 }
 
 // Stats returns cache statistics
 func (mc *MemoryCache) Stats() CacheStats {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	stats := mc.stats
-	stats.Size = atomic.LoadInt64(&mc.currentSize)
-	stats.MaxSize = mc.maxSizeMB
+	stats.Entries = int64(len(mc.items))
+	stats.Size = mc.currentSize
+	stats.MaxSize = mc.maxSize
 	return stats
 }
 
@@ -147,50 +171,49 @@ func (mc *MemoryCache) cleanupExpired() {
 	for {
 		select {
 		case <-ticker.C:
+			mc.mu.Lock()
 			mc.removeExpired()
+			mc.mu.Unlock()
 		case <-mc.stopCh:
 			return
 		}
 	}
 }
 
-// removeExpired removes all expired items
+// removeExpired removes all expired items. The caller holds mu.
 func (mc *MemoryCache) removeExpired() {
 	now := time.Now()
-	mc.mu.Lock()
-	for key, item := range mc.items {
-		if now.After(item.expiry) {
-			delete(mc.items, key)
-			atomic.AddInt64(&mc.currentSize, -(item.size))
-			atomic.AddInt64(&mc.stats.Evictions, 1)
+	for elem := mc.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if now.After(elem.Value.(*cacheItem).expiry) {
+			mc.remove(elem)
+			mc.stats.Expirations++
 		}
+		elem = prev
 	}
-	mc.mu.Unlock()
 }
 
-// makeRoom ensures there's room for new data by evicting old items if necessary
+// makeRoom evicts entries until sizeNeeded more bytes fit: expired entries
+// first, then the least recently used. The caller holds mu.
 func (mc *MemoryCache) makeRoom(sizeNeeded int64) {
-	currentSize := atomic.LoadInt64(&mc.currentSize)
-	if mc.maxSizeMB <= 0 || currentSize+sizeNeeded <= mc.maxSizeMB {
+	if mc.maxSize <= 0 || mc.currentSize+sizeNeeded <= mc.maxSize {
 		return
 	}
 
-	// Simple eviction: remove expired items first, then oldest items
 	mc.removeExpired()
-
-	// If still not enough room, remove items until we have space
-	currentSize = atomic.LoadInt64(&mc.currentSize)
-	if currentSize+sizeNeeded > mc.maxSizeMB {
-		mc.mu.Lock()
-		targetSize := mc.maxSizeMB - sizeNeeded
-		for key, item := range mc.items {
-			if atomic.LoadInt64(&mc.currentSize) <= targetSize {
-				break
-			}
-			delete(mc.items, key)
-			atomic.AddInt64(&mc.currentSize, -(item.size))
-			atomic.AddInt64(&mc.stats.Evictions, 1)
+	for mc.currentSize+sizeNeeded > mc.maxSize {
+		oldest := mc.lru.Back()
+		if oldest == nil {
+			return
 		}
-		mc.mu.Unlock()
+		mc.remove(oldest)
+		mc.stats.Evictions++
 	}
 }
+
+// remove drops an entry. The caller holds mu.
+func (mc *MemoryCache) remove(elem *list.Element) {
+	item := mc.lru.Remove(elem).(*cacheItem)
+	delete(mc.items, item.key)
+	mc.currentSize -= item.size
+}
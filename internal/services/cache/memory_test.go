@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	mc := NewMemoryCache(1)
+	defer mc.Stop()
+	ctx := context.Background()
+
+	// Three 300KB entries fit in the 1MB cache; a fourth does not
+	value := func() []byte { return make([]byte, 300*1024) }
+	require.NoError(t, mc.Set(ctx, "a", value(), time.Hour))
+	require.NoError(t, mc.Set(ctx, "b", value(), time.Hour))
+	require.NoError(t, mc.Set(ctx, "c", value(), time.Hour))
+
+	_, found := mc.Get(ctx, "a")
+	require.True(t, found)
+
+	require.NoError(t, mc.Set(ctx, "d", value(), time.Hour))
+
+	assert.True(t, mc.Has(ctx, "a"), "a was used more recently than b")
+	assert.False(t, mc.Has(ctx, "b"))
+	assert.True(t, mc.Has(ctx, "c"))
+	assert.True(t, mc.Has(ctx, "d"))
+
+	stats := mc.Stats()
+	assert.Equal(t, int64(1), stats.Evictions)
+	assert.Equal(t, int64(3), stats.Entries)
+	assert.Equal(t, int64(4), stats.Sets)
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.LessOrEqual(t, stats.Size, stats.MaxSize)
+}
+
+func TestMemoryCache_SizeAccounting(t *testing.T) {
+	mc := NewMemoryCache(1)
+	defer mc.Stop()
+	ctx := context.Background()
+
+	// A slice keeps its whole backing array alive
+	value := make([]byte, 10, 1000)
+	require.NoError(t, mc.Set(ctx, "key", value, time.Hour))
+	assert.Equal(t, entrySize("key", value), mc.Stats().Size)
+	assert.Greater(t, mc.Stats().Size, int64(1003))
+
+	// Replacing an entry swaps its size rather than adding to it
+	require.NoError(t, mc.Set(ctx, "key", make([]byte, 10), time.Hour))
+	assert.Equal(t, int64(13)+entryOverhead, mc.Stats().Size)
+
+	require.NoError(t, mc.Delete(ctx, "key"))
+	assert.Zero(t, mc.Stats().Size)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, mc.Set(ctx, fmt.Sprintf("key-%d", i), []byte("value"), time.Hour))
+	}
+	require.NoError(t, mc.Clear(ctx))
+	assert.Zero(t, mc.Stats().Size)
+	assert.Zero(t, mc.Stats().Entries)
+
+	assert.ErrorIs(t, mc.Set(ctx, "huge", make([]byte, 2*1024*1024), time.Hour), ErrEntryTooLarge)
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	mc := NewMemoryCache(1)
+	defer mc.Stop()
+	ctx := context.Background()
+
+	require.NoError(t, mc.Set(ctx, "short", []byte("value"), time.Millisecond))
+	require.NoError(t, mc.Set(ctx, "long", []byte("value"), time.Hour))
+	time.Sleep(5 * time.Millisecond)
+
+	_, found := mc.Get(ctx, "short")
+	assert.False(t, found)
+	_, found = mc.Get(ctx, "missing")
+	assert.False(t, found)
+	_, found = mc.Get(ctx, "long")
+	assert.True(t, found)
+
+	stats := mc.Stats()
+	assert.Equal(t, int64(1), stats.Expirations)
+	assert.Zero(t, stats.Evictions, "expiry isn't an eviction")
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.InDelta(t, 1.0/3, stats.HitRatio(), 0.001)
+}
+
+func TestMemoryCache_Unbounded(t *testing.T) {
+	mc := NewMemoryCache(0)
+	defer mc.Stop()
+	ctx := context.Background()
+
+	require.NoError(t, mc.Set(ctx, "big", make([]byte, 2*1024*1024), time.Hour))
+	assert.True(t, mc.Has(ctx, "big"))
+	assert.Zero(t, mc.Stats().MaxSize)
+}
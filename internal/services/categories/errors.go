@@ -0,0 +1,8 @@
+package categories
+
+import "errors"
+
+var (
+	// ErrCategoryNotFound is returned when a category is not stored locally
+	ErrCategoryNotFound = errors.New("category not found")
+)
@@ -0,0 +1,41 @@
+package categories
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+)
+
+// Service defines the interface for locally persisted category operations
+type Service interface {
+	// SyncCategories stores the Podcast Index category list and resolves the parent tree
+	SyncCategories(ctx context.Context, categories []podcastindex.Category) error
+
+	// GetCategory retrieves a category by Podcast Index category ID
+	GetCategory(ctx context.Context, id uint) (*models.Category, error)
+
+	// ListCategories returns every stored category ordered by name
+	ListCategories(ctx context.Context) ([]models.Category, error)
+
+	// ListPodcastsInCategory returns locally known podcasts in a category or any of its subcategories
+	ListPodcastsInCategory(ctx context.Context, categoryID uint, limit, offset int) ([]models.Podcast, int64, error)
+}
+
+// Repository defines the interface for category persistence
+type Repository interface {
+	// UpsertCategories creates or renames categories and sets their parents
+	UpsertCategories(ctx context.Context, categories []models.Category) error
+
+	// GetByID retrieves a category by ID
+	GetByID(ctx context.Context, id uint) (*models.Category, error)
+
+	// List returns all categories ordered by name
+	List(ctx context.Context) ([]models.Category, error)
+
+	// GetChildIDs returns the IDs of direct subcategories
+	GetChildIDs(ctx context.Context, parentID uint) ([]uint, error)
+
+	// ListPodcasts returns podcasts linked to any of the given categories
+	ListPodcasts(ctx context.Context, categoryIDs []uint, limit, offset int) ([]models.Podcast, int64, error)
+}
@@ -0,0 +1,84 @@
+package categories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new category repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// UpsertCategories creates or renames categories and sets their parents
+func (r *repository) UpsertCategories(ctx context.Context, categories []models.Category) error {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "parent_id", "updated_at"}),
+	}).Create(&categories).Error
+}
+
+// GetByID retrieves a category by ID
+func (r *repository) GetByID(ctx context.Context, id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.WithContext(ctx).First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("getting category: %w", err)
+	}
+	return &category, nil
+}
+
+// List returns all categories ordered by name
+func (r *repository) List(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("listing categories: %w", err)
+	}
+	return categories, nil
+}
+
+// GetChildIDs returns the IDs of direct subcategories
+func (r *repository) GetChildIDs(ctx context.Context, parentID uint) ([]uint, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).Model(&models.Category{}).Where("parent_id = ?", parentID).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("getting child categories: %w", err)
+	}
+	return ids, nil
+}
+
+// ListPodcasts returns podcasts linked to any of the given categories
+func (r *repository) ListPodcasts(ctx context.Context, categoryIDs []uint, limit, offset int) ([]models.Podcast, int64, error) {
+	subQuery := r.db.Model(&models.PodcastCategory{}).
+		Select("DISTINCT podcast_id").
+		Where("category_id IN ?", categoryIDs)
+
+	query := r.db.WithContext(ctx).Model(&models.Podcast{}).Where("id IN (?)", subQuery)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting podcasts in category: %w", err)
+	}
+
+	var podcasts []models.Podcast
+	if err := query.Order("episode_count DESC, title ASC").Limit(limit).Offset(offset).Find(&podcasts).Error; err != nil {
+		return nil, 0, fmt.Errorf("listing podcasts in category: %w", err)
+	}
+
+	return podcasts, total, nil
+}
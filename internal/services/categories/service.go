@@ -0,0 +1,69 @@
+package categories
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+)
+
+// service implements the Service interface
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new category service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// SyncCategories stores the Podcast Index category list and resolves the parent tree
+func (s *service) SyncCategories(ctx context.Context, categories []podcastindex.Category) error {
+	idsByName := make(map[string]uint, len(categories))
+	for _, c := range categories {
+		idsByName[c.Name] = uint(c.ID)
+	}
+
+	rows := make([]models.Category, 0, len(categories))
+	for _, c := range categories {
+		row := models.Category{ID: uint(c.ID), Name: c.Name}
+		if parentName, ok := parentNames[c.Name]; ok {
+			if parentID, ok := idsByName[parentName]; ok {
+				row.ParentID = &parentID
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if err := s.repo.UpsertCategories(ctx, rows); err != nil {
+		return fmt.Errorf("storing categories: %w", err)
+	}
+
+	log.Printf("[INFO] Synced %d categories", len(rows))
+	return nil
+}
+
+// GetCategory retrieves a category by Podcast Index category ID
+func (s *service) GetCategory(ctx context.Context, id uint) (*models.Category, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListCategories returns every stored category ordered by name
+func (s *service) ListCategories(ctx context.Context) ([]models.Category, error) {
+	return s.repo.List(ctx)
+}
+
+// ListPodcastsInCategory returns locally known podcasts in a category or any of its subcategories
+func (s *service) ListPodcastsInCategory(ctx context.Context, categoryID uint, limit, offset int) ([]models.Podcast, int64, error) {
+	ids := []uint{categoryID}
+
+	children, err := s.repo.GetChildIDs(ctx, categoryID)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids = append(ids, children...)
+
+	return s.repo.ListPodcasts(ctx, ids, limit, offset)
+}
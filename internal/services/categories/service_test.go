@@ -0,0 +1,113 @@
+package categories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+	"github.com/killallgit/player-api/internal/services/podcasts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Category{}, &models.PodcastCategory{}))
+	return db
+}
+
+func TestSyncCategories_ResolvesParents(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	err := svc.SyncCategories(ctx, []podcastindex.Category{
+		{ID: 1, Name: "Arts"},
+		{ID: 2, Name: "Books"},
+		{ID: 102, Name: "Technology"},
+	})
+	require.NoError(t, err)
+
+	books, err := svc.GetCategory(ctx, 2)
+	require.NoError(t, err)
+	require.NotNil(t, books.ParentID)
+	assert.Equal(t, uint(1), *books.ParentID)
+
+	tech, err := svc.GetCategory(ctx, 102)
+	require.NoError(t, err)
+	assert.Nil(t, tech.ParentID)
+
+	_, err = svc.GetCategory(ctx, 999)
+	assert.True(t, errors.Is(err, ErrCategoryNotFound))
+}
+
+func TestListPodcastsInCategory_IncludesSubcategories(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	podcastRepo := podcasts.NewRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, svc.SyncCategories(ctx, []podcastindex.Category{
+		{ID: 1, Name: "Arts"},
+		{ID: 2, Name: "Books"},
+		{ID: 102, Name: "Technology"},
+	}))
+
+	require.NoError(t, podcastRepo.CreatePodcast(ctx, &models.Podcast{
+		PodcastIndexID: 10, Title: "Book Club", FeedURL: "https://example.com/books.xml",
+		Categories: datatypes.JSON(`{"2":"Books"}`),
+	}))
+	require.NoError(t, podcastRepo.CreatePodcast(ctx, &models.Podcast{
+		PodcastIndexID: 11, Title: "Tech Talk", FeedURL: "https://example.com/tech.xml",
+		Categories: datatypes.JSON(`{"102":"Technology"}`),
+	}))
+
+	arts, total, err := svc.ListPodcastsInCategory(ctx, 1, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, arts, 1)
+	assert.Equal(t, "Book Club", arts[0].Title)
+
+	tech, total, err := svc.ListPodcastsInCategory(ctx, 102, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "Tech Talk", tech[0].Title)
+}
+
+func TestPodcastUpdate_ReplacesCategoryLinks(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	podcastRepo := podcasts.NewRepository(db)
+	ctx := context.Background()
+
+	podcast := &models.Podcast{
+		PodcastIndexID: 20, Title: "Shifting Show", FeedURL: "https://example.com/shift.xml",
+		Categories: datatypes.JSON(`{"55":"News"}`),
+	}
+	require.NoError(t, podcastRepo.CreatePodcast(ctx, podcast))
+
+	// Categories unknown to the tree are created from the podcast's own data
+	news, err := svc.GetCategory(ctx, 55)
+	require.NoError(t, err)
+	assert.Equal(t, "News", news.Name)
+
+	podcast.Categories = datatypes.JSON(`{"102":"Technology"}`)
+	require.NoError(t, podcastRepo.UpdatePodcast(ctx, podcast))
+
+	_, total, err := svc.ListPodcastsInCategory(ctx, 55, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	_, total, err = svc.ListPodcastsInCategory(ctx, 102, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
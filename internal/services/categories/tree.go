@@ -0,0 +1,106 @@
+package categories
+
+// parentNames maps Podcast Index subcategory names to their top-level category.
+// Podcast Index returns a flat list; the hierarchy follows the Apple Podcasts
+// taxonomy that the flat names are derived from. Names missing here are roots.
+var parentNames = map[string]string{
+	"Books":      "Arts",
+	"Design":     "Arts",
+	"Fashion":    "Arts",
+	"Beauty":     "Arts",
+	"Food":       "Arts",
+	"Performing": "Arts",
+	"Visual":     "Arts",
+
+	"Careers":          "Business",
+	"Entrepreneurship": "Business",
+	"Investing":        "Business",
+	"Management":       "Business",
+	"Marketing":        "Business",
+	"Non-Profit":       "Business",
+
+	"Interviews": "Comedy",
+	"Improv":     "Comedy",
+	"Stand-Up":   "Comedy",
+
+	"Courses":          "Education",
+	"How-To":           "Education",
+	"Language":         "Education",
+	"Learning":         "Education",
+	"Self-Improvement": "Education",
+
+	"Drama": "Fiction",
+
+	"Fitness":     "Health",
+	"Alternative": "Health",
+	"Medicine":    "Health",
+	"Mental":      "Health",
+	"Nutrition":   "Health",
+	"Sexuality":   "Health",
+
+	"Family":    "Kids",
+	"Parenting": "Kids",
+	"Pets":      "Kids",
+	"Animals":   "Kids",
+	"Stories":   "Kids",
+
+	"Animation":   "Leisure",
+	"Manga":       "Leisure",
+	"Automotive":  "Leisure",
+	"Aviation":    "Leisure",
+	"Crafts":      "Leisure",
+	"Games":       "Leisure",
+	"Hobbies":     "Leisure",
+	"Home":        "Leisure",
+	"Garden":      "Leisure",
+	"Video-Games": "Leisure",
+
+	"Daily":         "News",
+	"Entertainment": "News",
+	"Politics":      "News",
+
+	"Buddhism":     "Religion",
+	"Christianity": "Religion",
+	"Hinduism":     "Religion",
+	"Islam":        "Religion",
+	"Judaism":      "Religion",
+
+	"Astronomy":   "Science",
+	"Chemistry":   "Science",
+	"Earth":       "Science",
+	"Life":        "Science",
+	"Mathematics": "Science",
+	"Natural":     "Science",
+	"Nature":      "Science",
+	"Physics":     "Science",
+	"Social":      "Science",
+
+	"Culture":       "Society",
+	"Documentary":   "Society",
+	"Personal":      "Society",
+	"Journals":      "Society",
+	"Philosophy":    "Society",
+	"Places":        "Society",
+	"Travel":        "Society",
+	"Relationships": "Society",
+
+	"Baseball":   "Sports",
+	"Basketball": "Sports",
+	"Cricket":    "Sports",
+	"Fantasy":    "Sports",
+	"Football":   "Sports",
+	"Golf":       "Sports",
+	"Hockey":     "Sports",
+	"Rugby":      "Sports",
+	"Running":    "Sports",
+	"Soccer":     "Sports",
+	"Swimming":   "Sports",
+	"Tennis":     "Sports",
+	"Volleyball": "Sports",
+	"Wilderness": "Sports",
+	"Wrestling":  "Sports",
+
+	"After-Shows": "TV",
+	"Film":        "TV",
+	"Reviews":     "TV",
+}
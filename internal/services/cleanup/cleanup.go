@@ -2,43 +2,69 @@ package cleanup
 
 import (
 	"context"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
+// orphanPatterns match the temp files and directories the app creates in
+// temp_dir. They are normally removed by whoever created them, but a crash
+// leaves them behind. The patterns are broad, so only temp_dir is scanned and
+// never a temp directory other programs share.
+var orphanPatterns = []string{
+	"episode_*",             // Episode downloads (pkg/download)
+	"audio_download_*",      // Audio cache and ffmpeg downloads
+	"audio_processed_*.wav", // Audio cache conversions
+	"clip_*.wav",            // Clip extraction before the file is stored
+	"dataset_export_*",      // Clip dataset export directories
+	"live-transcript-*",     // Live transcription working directories
+}
+
+// Stats describes the work the cleanup service has done since it started
+type Stats struct {
+	Runs               int64     `json:"runs"`
+	FilesRemoved       int64     `json:"files_removed"` // Directories count once
+	BytesReclaimed     int64     `json:"bytes_reclaimed"`
+	LastRun            time.Time `json:"last_run,omitempty"`
+	LastFilesRemoved   int64     `json:"last_files_removed"`
+	LastBytesReclaimed int64     `json:"last_bytes_reclaimed"`
+}
+
 // Service handles cleanup of temporary files
 type Service struct {
-	tempDir         string
+	dir             string
 	maxAge          time.Duration
 	cleanupInterval time.Duration
 	cancel          context.CancelFunc
+
+	mu    sync.Mutex
+	stats Stats
 }
 
-// NewService creates a new cleanup service
+// NewService creates a new cleanup service for the app's own tempDir
 func NewService(tempDir string, maxAge, cleanupInterval time.Duration) *Service {
 	return &Service{
-		tempDir:         tempDir,
+		dir:             filepath.Clean(tempDir),
 		maxAge:          maxAge,
 		cleanupInterval: cleanupInterval,
 	}
 }
 
-// Start begins the cleanup service
+// Start runs a cleanup straight away, for anything left by a previous crash,
+// and then every cleanup interval until ctx is done or Stop is called
 func (s *Service) Start(ctx context.Context) {
 	ctx, cancel := context.WithCancel(ctx)
 	s.cancel = cancel
 
-	ticker := time.NewTicker(s.cleanupInterval)
-	defer ticker.Stop()
-
-	// Run initial cleanup
-	s.cleanup()
-
-	// Run periodic cleanup
 	go func() {
+		ticker := time.NewTicker(s.cleanupInterval)
+		defer ticker.Stop()
+
+		s.cleanup()
 		for {
 			select {
 			case <-ticker.C:
@@ -60,41 +86,91 @@ func (s *Service) Stop() {
 	}
 }
 
-// cleanup removes old temporary files
+// Stats returns what the cleanup service has removed so far
+func (s *Service) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// cleanup removes orphaned temp files older than the maximum age
 func (s *Service) cleanup() {
-	// Check if temp directory exists
-	if _, err := os.Stat(s.tempDir); os.IsNotExist(err) {
-		return
+	var removed, reclaimed int64
+	cutoff := time.Now().Add(-s.maxAge)
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("[WARN] Failed to read temp directory %s: %v", s.dir, err)
 	}
 
-	// Walk through temp directory
-	err := filepath.Walk(s.tempDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip files with errors
+	for _, entry := range entries {
+		if !isOrphanCandidate(entry.Name()) {
+			continue
 		}
+		path := filepath.Join(s.dir, entry.Name())
 
-		// Skip directories
-		if info.IsDir() {
-			return nil
+		// A directory still being written to has recent entries even when
+		// the directory itself is old
+		size, modified := usage(path)
+		if modified.IsZero() || modified.After(cutoff) {
+			continue
 		}
 
-		// Check if file is an episode temp file (pattern: episode_*_*.mp3)
-		if strings.HasPrefix(info.Name(), "episode_") && strings.HasSuffix(info.Name(), ".mp3") {
-			// Check age
-			if time.Since(info.ModTime()) > s.maxAge {
-				log.Printf("[DEBUG] Removing old temp file: %s", path)
-				if err := os.Remove(path); err != nil {
-					log.Printf("[WARN] Failed to remove temp file %s: %v", path, err)
-				}
-			}
+		log.Printf("[DEBUG] Removing orphaned temp file: %s", path)
+		if err := os.RemoveAll(path); err != nil {
+			log.Printf("[WARN] Failed to remove temp file %s: %v", path, err)
+			continue
+		}
+		removed++
+		reclaimed += size
+	}
+
+	s.mu.Lock()
+	s.stats.Runs++
+	s.stats.FilesRemoved += removed
+	s.stats.BytesReclaimed += reclaimed
+	s.stats.LastRun = time.Now()
+	s.stats.LastFilesRemoved = removed
+	s.stats.LastBytesReclaimed = reclaimed
+	s.mu.Unlock()
+
+	if removed > 0 {
+		log.Printf("[INFO] Cleanup removed %d orphaned temp files, reclaiming %d bytes", removed, reclaimed)
+	}
+}
+
+// isOrphanCandidate reports whether name follows one of the app's temp naming conventions
+func isOrphanCandidate(name string) bool {
+	for _, pattern := range orphanPatterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
 		}
+	}
+	return false
+}
 
+// usage returns the total size of path and the latest modification time of
+// anything in it. The time is zero when path can't be read.
+func usage(path string) (int64, time.Time) {
+	var size int64
+	var modified time.Time
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip entries with errors
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			size += info.Size()
+		}
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
 		return nil
 	})
-
-	if err != nil {
-		log.Printf("[ERROR] Cleanup walk error: %v", err)
-	}
+	return size, modified
 }
 
 // CleanupSingleFile removes a specific temp file
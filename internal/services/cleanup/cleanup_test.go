@@ -0,0 +1,60 @@
+package cleanup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAged(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+	modified := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modified, modified))
+}
+
+func TestCleanup_RemovesOrphans(t *testing.T) {
+	tempDir := t.TempDir()
+	system := t.TempDir()
+	t.Setenv("TMPDIR", system)
+
+	writeAged(t, filepath.Join(tempDir, "episode_42_123.mp3"), 100, 2*time.Hour)
+	writeAged(t, filepath.Join(tempDir, "audio_processed_9.wav"), 50, 2*time.Hour)
+	writeAged(t, filepath.Join(tempDir, "dataset_export_1", "manifest.jsonl"), 30, 2*time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(tempDir, "dataset_export_1"), time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)))
+
+	// Kept: too recent, still being written to, not ours, or outside temp_dir
+	writeAged(t, filepath.Join(tempDir, "audio_download_7.mp3"), 10, time.Minute)
+	writeAged(t, filepath.Join(tempDir, "dataset_export_2", "old.wav"), 10, 2*time.Hour)
+	writeAged(t, filepath.Join(tempDir, "dataset_export_2", "new.wav"), 10, time.Minute)
+	writeAged(t, filepath.Join(tempDir, "notes.txt"), 10, 48*time.Hour)
+	writeAged(t, filepath.Join(system, "episode_1_1.mp3"), 10, 48*time.Hour)
+
+	service := NewService(tempDir, time.Hour, time.Hour)
+	service.cleanup()
+
+	assert.NoFileExists(t, filepath.Join(tempDir, "episode_42_123.mp3"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "audio_processed_9.wav"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "dataset_export_1"))
+	assert.FileExists(t, filepath.Join(tempDir, "audio_download_7.mp3"))
+	assert.FileExists(t, filepath.Join(tempDir, "dataset_export_2", "old.wav"))
+	assert.FileExists(t, filepath.Join(tempDir, "notes.txt"))
+	assert.FileExists(t, filepath.Join(system, "episode_1_1.mp3"), "the shared system temp directory is left alone")
+
+	stats := service.Stats()
+	assert.Equal(t, int64(1), stats.Runs)
+	assert.Equal(t, int64(3), stats.FilesRemoved)
+	assert.Equal(t, int64(180), stats.BytesReclaimed)
+	assert.Equal(t, int64(180), stats.LastBytesReclaimed)
+
+	service.cleanup()
+	stats = service.Stats()
+	assert.Equal(t, int64(2), stats.Runs)
+	assert.Equal(t, int64(180), stats.BytesReclaimed)
+	assert.Equal(t, int64(0), stats.LastBytesReclaimed)
+}
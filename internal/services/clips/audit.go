@@ -0,0 +1,52 @@
+package clips
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes clip changes made with it to userID.
+// Changes made without an actor are recorded as system changes.
+func WithActor(ctx context.Context, userID string) context.Context {
+	if userID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// actorFrom returns the user set by WithActor, or "" for system changes
+func actorFrom(ctx context.Context) string {
+	userID, _ := ctx.Value(actorKey{}).(string)
+	return userID
+}
+
+// RecordAnnotationChange appends an audit row for a clip change. Pass the
+// transaction that performed the change so the two commit together.
+func RecordAnnotationChange(tx *gorm.DB, clip *models.Clip, userID, action string, before, after *models.AnnotationSnapshot) error {
+	audit, err := models.NewAnnotationAudit(clip, userID, action, before, after)
+	if err != nil {
+		return fmt.Errorf("failed to encode annotation snapshot: %w", err)
+	}
+	if err := tx.Create(audit).Error; err != nil {
+		return fmt.Errorf("failed to record annotation change: %w", err)
+	}
+	return nil
+}
+
+// GetAnnotationHistory returns every recorded change to a clip, oldest first.
+// History is kept after the clip itself is deleted.
+func (s *ServiceImpl) GetAnnotationHistory(ctx context.Context, uuid string) ([]models.AnnotationAudit, error) {
+	var history []models.AnnotationAudit
+	if err := s.db.WithContext(ctx).
+		Where("clip_uuid = ?", uuid).
+		Order("id ASC").
+		Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to get annotation history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package clips
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// linkCount returns the number of hard links to path
+func linkCount(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("link count unavailable for %s", path)
+	}
+	return uint64(stat.Nlink), nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package clips
+
+import (
+	"errors"
+	"os"
+)
+
+// linkCount is unavailable here, so unreferenced clip objects are kept rather
+// than risk removing one that is still linked
+func linkCount(path string) (uint64, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, err
+	}
+	return 0, errors.ErrUnsupported
+}
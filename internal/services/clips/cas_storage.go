@@ -0,0 +1,164 @@
+package clips
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// objectsDir holds the shared clip audio, named by PCMDigest. Sanitized labels
+// never start with a dot, so it can't collide with a label directory.
+const objectsDir = ".objects"
+
+// ContentAddressedClipStorage stores each distinct clip audio once. The file
+// is kept under objectsDir by the hash of its PCM data and every clip is a
+// hard link to it in the usual {label}/{filename} layout, so relabels and
+// duplicate extractions share one file while readers and exports see plain
+// files. The object's link count is its reference count: when the last clip
+// linking to it is deleted, the object is removed.
+type ContentAddressedClipStorage struct {
+	*LocalClipStorage
+}
+
+// NewContentAddressedClipStorage creates a deduplicating storage under
+// basePath. Clips already stored there by LocalClipStorage stay readable.
+func NewContentAddressedClipStorage(basePath string) (*ContentAddressedClipStorage, error) {
+	local, err := NewLocalClipStorage(basePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(local.basePath, objectsDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create clip object directory: %w", err)
+	}
+	return &ContentAddressedClipStorage{LocalClipStorage: local}, nil
+}
+
+// NewClipStorage creates the configured clip storage: content addressed when
+// deduplicate is set, one file per clip otherwise
+func NewClipStorage(basePath string, deduplicate bool) (ClipStorage, error) {
+	if deduplicate {
+		return NewContentAddressedClipStorage(basePath)
+	}
+	return NewLocalClipStorage(basePath)
+}
+
+// SaveClip stores the clip's audio, reusing the object of identical audio
+// saved before, and links it at {label}/{filename}
+func (s *ContentAddressedClipStorage) SaveClip(ctx context.Context, label, filename string, data io.Reader) error {
+	incoming, err := os.CreateTemp(filepath.Join(s.basePath, objectsDir), "incoming-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(incoming.Name())
+
+	if _, err := io.Copy(incoming, data); err != nil {
+		incoming.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if _, err := incoming.Seek(0, io.SeekStart); err != nil {
+		incoming.Close()
+		return fmt.Errorf("failed to rewind file: %w", err)
+	}
+	digest, err := PCMDigest(incoming)
+	incoming.Close()
+	if err != nil {
+		return fmt.Errorf("failed to hash clip audio: %w", err)
+	}
+
+	// Release whatever the clip pointed to before, e.g. when re-extracting
+	if err := s.DeleteClip(ctx, label, filename); err != nil {
+		return err
+	}
+
+	object := s.objectPath(digest)
+	path := s.GetClipPath(label, filename)
+	// A concurrent delete may remove the object between the attempts, so
+	// recreate it from the incoming file and try again
+	for attempt := 0; ; attempt++ {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create label directory: %w", err)
+		}
+		err := os.Link(object, path)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, os.ErrNotExist) || attempt == 2 {
+			return fmt.Errorf("failed to link clip: %w", err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(object), 0755); err != nil {
+			return fmt.Errorf("failed to create clip object directory: %w", err)
+		}
+		if err := os.Link(incoming.Name(), object); err != nil && !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to store clip object: %w", err)
+		}
+	}
+}
+
+// DeleteClip unlinks a clip and removes its object once no clip links to it
+func (s *ContentAddressedClipStorage) DeleteClip(ctx context.Context, label, filename string) error {
+	path := s.GetClipPath(label, filename)
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // Already deleted, not an error
+		}
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	digest, err := PCMDigest(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to hash clip audio: %w", err)
+	}
+
+	if err := s.LocalClipStorage.DeleteClip(ctx, label, filename); err != nil {
+		return err
+	}
+	s.releaseObject(digest)
+	return nil
+}
+
+// MoveClip renames the link; unlike LocalClipStorage it never falls back to
+// copying, which would detach the clip from its object
+func (s *ContentAddressedClipStorage) MoveClip(ctx context.Context, oldLabel, newLabel, filename string) error {
+	newPath := s.GetClipPath(newLabel, filename)
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create new label directory: %w", err)
+	}
+
+	oldPath := s.GetClipPath(oldLabel, filename)
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to move file: %w", err)
+	}
+
+	_ = os.Remove(filepath.Dir(oldPath)) // Ignore error if directory is not empty
+	return nil
+}
+
+// releaseObject removes the object for digest when only the object itself
+// still links to it. Clips stored before deduplication have no object.
+func (s *ContentAddressedClipStorage) releaseObject(digest string) {
+	object := s.objectPath(digest)
+	links, err := linkCount(object)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[WARN] Keeping clip object %s: %v", digest, err)
+		}
+		return
+	}
+	if links <= 1 {
+		if err := os.Remove(object); err != nil && !os.IsNotExist(err) {
+			log.Printf("[WARN] Failed to remove unreferenced clip object %s: %v", digest, err)
+		}
+	}
+}
+
+// objectPath fans objects out over subdirectories named by the first two hex digits
+func (s *ContentAddressedClipStorage) objectPath(digest string) string {
+	return filepath.Join(s.basePath, objectsDir, digest[:2], digest+".wav")
+}
@@ -0,0 +1,109 @@
+package clips
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWAV builds a minimal WAV file with an optional LIST chunk before the samples
+func testWAV(samples []byte, list string) []byte {
+	var chunks bytes.Buffer
+	writeChunk := func(id string, body []byte) {
+		chunks.WriteString(id)
+		binary.Write(&chunks, binary.LittleEndian, uint32(len(body)))
+		chunks.Write(body)
+		if len(body)%2 == 1 {
+			chunks.WriteByte(0)
+		}
+	}
+	writeChunk("fmt ", []byte{1, 0, 1, 0, 0x80, 0x3e, 0, 0, 0, 0x7d, 0, 0, 2, 0, 16, 0})
+	if list != "" {
+		writeChunk("LIST", []byte(list))
+	}
+	writeChunk("data", samples)
+
+	var wav bytes.Buffer
+	wav.WriteString("RIFF")
+	binary.Write(&wav, binary.LittleEndian, uint32(4+chunks.Len()))
+	wav.WriteString("WAVE")
+	wav.Write(chunks.Bytes())
+	return wav.Bytes()
+}
+
+func TestPCMDigest(t *testing.T) {
+	digest := func(data []byte) string {
+		d, err := PCMDigest(bytes.NewReader(data))
+		require.NoError(t, err)
+		return d
+	}
+
+	samples := []byte{1, 2, 3, 4}
+	plain := digest(testWAV(samples, ""))
+	assert.Len(t, plain, 64)
+	assert.Equal(t, plain, digest(testWAV(samples, "INFOISFTLavf60.3")), "metadata chunks are ignored")
+	assert.NotEqual(t, plain, digest(testWAV([]byte{1, 2, 3, 5}, "")))
+	assert.NotEqual(t, digest([]byte("not a wav")), digest([]byte("not a wav either")))
+}
+
+func TestContentAddressedClipStorage(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	storage, err := NewContentAddressedClipStorage(base)
+	require.NoError(t, err)
+
+	audio := testWAV([]byte{1, 2, 3, 4}, "")
+	objects := func() []string {
+		matches, err := filepath.Glob(filepath.Join(base, objectsDir, "*", "*.wav"))
+		require.NoError(t, err)
+		return matches
+	}
+
+	// The same audio extracted twice, once with different metadata, is stored once
+	require.NoError(t, storage.SaveClip(ctx, "music", "clip_a.wav", bytes.NewReader(audio)))
+	require.NoError(t, storage.SaveClip(ctx, "music", "clip_b.wav", bytes.NewReader(testWAV([]byte{1, 2, 3, 4}, "INFO"))))
+	require.Len(t, objects(), 1)
+	a, err := os.Stat(storage.GetClipPath("music", "clip_a.wav"))
+	require.NoError(t, err)
+	b, err := os.Stat(storage.GetClipPath("music", "clip_b.wav"))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(a, b))
+
+	// Clips read back as the first stored copy
+	data, err := os.ReadFile(storage.GetClipPath("music", "clip_b.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, audio, data)
+
+	// Relabeling keeps sharing the object
+	require.NoError(t, storage.MoveClip(ctx, "music", "speech", "clip_b.wav"))
+	moved, err := os.Stat(storage.GetClipPath("speech", "clip_b.wav"))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(a, moved))
+
+	// The object goes with its last reference
+	require.NoError(t, storage.DeleteClip(ctx, "music", "clip_a.wav"))
+	assert.Len(t, objects(), 1)
+	require.NoError(t, storage.DeleteClip(ctx, "speech", "clip_b.wav"))
+	assert.Empty(t, objects())
+
+	// Saving over a clip releases its old audio
+	require.NoError(t, storage.SaveClip(ctx, "music", "clip_c.wav", bytes.NewReader(audio)))
+	require.NoError(t, storage.SaveClip(ctx, "music", "clip_c.wav", bytes.NewReader(testWAV([]byte{9, 9}, ""))))
+	assert.Len(t, objects(), 1)
+	data, err = os.ReadFile(storage.GetClipPath("music", "clip_c.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, testWAV([]byte{9, 9}, ""), data)
+
+	// Files stored before deduplication are still deleted cleanly
+	legacy := storage.GetClipPath("music", "clip_old.wav")
+	require.NoError(t, os.WriteFile(legacy, audio, 0o644))
+	require.NoError(t, storage.DeleteClip(ctx, "music", "clip_old.wav"))
+	assert.NoFileExists(t, legacy)
+	assert.Len(t, objects(), 1)
+}
@@ -0,0 +1,85 @@
+package clips
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// PCMDigest returns the hex SHA-256 of a WAV stream's format and sample data,
+// ignoring the other chunks (encoder tags, padding) so that the same audio
+// extracted twice hashes the same. Input that isn't a WAV file is hashed whole.
+func PCMDigest(r io.Reader) (string, error) {
+	hash := sha256.New()
+	br := bufio.NewReader(r)
+
+	header, err := br.Peek(12)
+	if err != nil || string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		if _, err := io.Copy(hash, br); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+	if _, err := br.Discard(12); err != nil {
+		return "", err
+	}
+
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(br, chunk[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", fmt.Errorf("failed to read WAV chunk header: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			hash.Write(chunk[:])
+			if _, err := io.CopyN(hash, br, size); err != nil {
+				return "", fmt.Errorf("failed to read WAV format: %w", err)
+			}
+		case "data":
+			// Streamed WAVs leave the data size unset, so hash to the end
+			hash.Write([]byte(id))
+			if _, err := io.Copy(hash, br); err != nil {
+				return "", fmt.Errorf("failed to read WAV samples: %w", err)
+			}
+			return hex.EncodeToString(hash.Sum(nil)), nil
+		default:
+			if _, err := br.Discard(int(size + size%2)); err != nil {
+				return "", fmt.Errorf("failed to skip WAV %q chunk: %w", id, err)
+			}
+			continue
+		}
+		if size%2 == 1 {
+			if _, err := br.Discard(1); err != nil {
+				return "", fmt.Errorf("failed to read WAV padding: %w", err)
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// StoredDigest returns the hex SHA-256 and size of a clip's stored file, as
+// recorded in clip_sha256 and clip_size_bytes
+func StoredDigest(ctx context.Context, storage ClipStorage, label, filename string) (string, int64, error) {
+	file, err := storage.GetClip(ctx, label, filename)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
@@ -6,10 +6,18 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/killallgit/player-api/pkg/ffmpeg"
+)
+
+// Every extracted clip is normalized to this format, whatever the source
+const (
+	ClipSampleRate = 16000
+	ClipChannels   = 1
+	ClipCodec      = "pcm_s16le"
 )
 
 // AudioExtractor handles the extraction and processing of audio clips
@@ -37,17 +45,16 @@ type ExtractResult struct {
 
 // FFmpegExtractor implements AudioExtractor using FFmpeg
 type FFmpegExtractor struct {
-	ffmpegPath     string
+	ffmpeg         *ffmpeg.FFmpeg // Runs every process under the configured resource limits
 	tempDir        string
 	targetDuration float64 // Target duration in seconds (e.g., 15.0)
 }
 
 // NewFFmpegExtractor creates a new FFmpeg-based extractor
-func NewFFmpegExtractor(tempDir string, targetDuration float64) (*FFmpegExtractor, error) {
-	// Check if ffmpeg is available
-	ffmpegPath, err := exec.LookPath("ffmpeg")
-	if err != nil {
-		return nil, fmt.Errorf("ffmpeg not found in PATH: %w", err)
+func NewFFmpegExtractor(ffmpegInstance *ffmpeg.FFmpeg, tempDir string, targetDuration float64) (*FFmpegExtractor, error) {
+	// Check if ffmpeg and ffprobe are available
+	if err := ffmpegInstance.ValidateBinaries(); err != nil {
+		return nil, err
 	}
 
 	// Ensure temp directory exists
@@ -59,7 +66,7 @@ func NewFFmpegExtractor(tempDir string, targetDuration float64) (*FFmpegExtracto
 	// Any positive value will pad/crop to that duration
 
 	return &FFmpegExtractor{
-		ffmpegPath:     ffmpegPath,
+		ffmpeg:         ffmpegInstance,
 		tempDir:        tempDir,
 		targetDuration: targetDuration,
 	}, nil
@@ -122,8 +129,8 @@ func (e *FFmpegExtractor) ExtractClip(ctx context.Context, params ExtractParams)
 		FilePath:      processedPath,
 		Duration:      actualDuration,
 		SizeBytes:     fileInfo.Size(),
-		SampleRate:    16000, // We always convert to 16kHz
-		Channels:      1,     // We always convert to mono
+		SampleRate:    ClipSampleRate, // We always convert to 16kHz
+		Channels:      ClipChannels,   // We always convert to mono
 		ProcessedPath: processedPath,
 	}, nil
 }
@@ -196,12 +203,10 @@ func (e *FFmpegExtractor) extractAndConvert(ctx context.Context, sourcePath stri
 		params.OutputPath, // Output file
 	}
 
-	cmd := exec.CommandContext(ctx, e.ffmpegPath, args...)
-
 	// Capture stderr for debugging
-	output, err := cmd.CombinedOutput()
+	output, err := e.ffmpeg.Run(ctx, args...)
 	if err != nil {
-		return fmt.Errorf("ffmpeg failed: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("ffmpeg failed: %w\nOutput: %s", err, output)
 	}
 
 	return nil
@@ -246,28 +251,7 @@ func (e *FFmpegExtractor) applyTargetDuration(ctx context.Context, inputPath str
 
 // getAudioDuration gets the duration of an audio file
 func (e *FFmpegExtractor) getAudioDuration(ctx context.Context, filePath string) (float64, error) {
-	args := []string{
-		"-i", filePath,
-		"-show_entries", "format=duration",
-		"-v", "quiet",
-		"-of", "csv=p=0",
-	}
-
-	// Use ffprobe for getting duration
-	ffprobePath := strings.Replace(e.ffmpegPath, "ffmpeg", "ffprobe", 1)
-	cmd := exec.CommandContext(ctx, ffprobePath, args...)
-
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, fmt.Errorf("ffprobe failed: %w", err)
-	}
-
-	var duration float64
-	if _, err := fmt.Sscanf(string(output), "%f", &duration); err != nil {
-		return 0, fmt.Errorf("failed to parse duration: %w", err)
-	}
-
-	return duration, nil
+	return e.ffmpeg.Duration(ctx, filePath)
 }
 
 // padWithSilence pads audio with silence to reach target duration
@@ -284,10 +268,9 @@ func (e *FFmpegExtractor) padWithSilence(ctx context.Context, inputPath, outputP
 		outputPath,
 	}
 
-	cmd := exec.CommandContext(ctx, e.ffmpegPath, args...)
-	output, err := cmd.CombinedOutput()
+	output, err := e.ffmpeg.Run(ctx, args...)
 	if err != nil {
-		return fmt.Errorf("ffmpeg pad failed: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("ffmpeg pad failed: %w\nOutput: %s", err, output)
 	}
 
 	return nil
@@ -307,10 +290,9 @@ func (e *FFmpegExtractor) cropAudio(ctx context.Context, inputPath, outputPath s
 		outputPath,
 	}
 
-	cmd := exec.CommandContext(ctx, e.ffmpegPath, args...)
-	output, err := cmd.CombinedOutput()
+	output, err := e.ffmpeg.Run(ctx, args...)
 	if err != nil {
-		return fmt.Errorf("ffmpeg crop failed: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("ffmpeg crop failed: %w\nOutput: %s", err, output)
 	}
 
 	return nil
@@ -0,0 +1,193 @@
+package clips
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Repair modes for VerifyClips
+const (
+	RepairNone       = ""           // Only report problems
+	RepairReextract  = "reextract"  // Extract broken clips again from the episode audio
+	RepairInvalidate = "invalidate" // Mark broken clips failed so exports skip them
+)
+
+// IsValidRepairMode reports whether mode is one of the Repair* modes
+func IsValidRepairMode(mode string) bool {
+	switch mode {
+	case RepairNone, RepairReextract, RepairInvalidate:
+		return true
+	}
+	return false
+}
+
+// Problems VerifyClips finds with a clip's stored file
+const (
+	ClipFileMissing      = "missing"       // No file in storage
+	ClipFileUnreadable   = "unreadable"    // The file exists but can't be read
+	ClipFileSizeMismatch = "size_mismatch" // Size differs from clip_size_bytes
+	ClipFileHashMismatch = "hash_mismatch" // Contents differ from clip_sha256
+)
+
+// VerifyOptions controls what VerifyClips does about broken clips
+type VerifyOptions struct {
+	Repair string // One of the Repair* modes
+}
+
+// ClipIssue describes a ready clip whose stored file doesn't match its record
+type ClipIssue struct {
+	UUID     string `json:"uuid"`
+	Label    string `json:"label"`
+	Filename string `json:"filename"`
+	Problem  string `json:"problem"`          // One of the ClipFile* problems
+	Detail   string `json:"detail,omitempty"` // Expected and actual values, or the read error
+	Repair   string `json:"repair,omitempty"` // Repair mode applied, if any
+	Repaired bool   `json:"repaired"`         // Whether the repair succeeded
+}
+
+// VerifyResult counts what VerifyClips checked and found
+type VerifyResult struct {
+	Checked      int         `json:"checked"`       // Ready clips checked
+	OK           int         `json:"ok"`            // Files matching their record
+	HashRecorded int         `json:"hash_recorded"` // Clips extracted before hashes were kept, now hashed
+	Broken       int         `json:"broken"`        // Clips with a missing or mismatched file
+	Repaired     int         `json:"repaired"`      // Broken clips re-extracted or invalidated
+	Issues       []ClipIssue `json:"issues"`
+}
+
+// VerifyClips checks that every ready clip's file exists in storage with the
+// recorded size and SHA-256. Files drift from their records through manual
+// deletions or relabel moves that failed halfway. Broken clips are reported
+// and, depending on opts.Repair, extracted again or marked failed; clips
+// extracted before hashes were kept get their current hash recorded.
+func (s *ServiceImpl) VerifyClips(ctx context.Context, opts VerifyOptions) (*VerifyResult, error) {
+	if !IsValidRepairMode(opts.Repair) {
+		return nil, fmt.Errorf("unknown repair mode %q", opts.Repair)
+	}
+
+	var ids []uint
+	if err := s.db.WithContext(ctx).Model(&models.Clip{}).
+		Where("status = ? AND extracted = ? AND clip_filename IS NOT NULL", models.ClipStatusReady, true).
+		Order("id").Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to select clips to verify: %w", err)
+	}
+
+	result := &VerifyResult{Issues: []ClipIssue{}}
+	for start := 0; start < len(ids); start += exportBatchSize {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		var clips []*models.Clip
+		batch := ids[start:min(start+exportBatchSize, len(ids))]
+		if err := s.db.WithContext(ctx).Where("id IN ?", batch).Order("id").Find(&clips).Error; err != nil {
+			return result, fmt.Errorf("failed to load clips to verify: %w", err)
+		}
+
+		var broken []*models.Clip
+		for _, clip := range clips {
+			result.Checked++
+			issue, digest := s.verifyClipFile(ctx, clip)
+			if issue == nil {
+				result.OK++
+				if clip.ClipSHA256 == nil {
+					if err := s.db.WithContext(ctx).Model(clip).Update("clip_sha256", digest).Error; err != nil {
+						log.Printf("[WARN] Failed to record hash of clip %s: %v", clip.UUID, err)
+					} else {
+						result.HashRecorded++
+					}
+				}
+				continue
+			}
+			log.Printf("[WARN] Clip %s failed integrity check: %s (%s)", clip.UUID, issue.Problem, issue.Detail)
+			result.Broken++
+			result.Issues = append(result.Issues, *issue)
+			broken = append(broken, clip)
+		}
+
+		if opts.Repair == RepairNone || len(broken) == 0 {
+			continue
+		}
+		issues := result.Issues[len(result.Issues)-len(broken):]
+		if err := s.repairClips(ctx, broken, issues, opts.Repair); err != nil {
+			return result, err
+		}
+		for _, issue := range issues {
+			if issue.Repaired {
+				result.Repaired++
+			}
+		}
+	}
+
+	log.Printf("[INFO] Verified %d clips: %d broken, %d repaired", result.Checked, result.Broken, result.Repaired)
+	return result, nil
+}
+
+// verifyClipFile reads a clip's stored file, returning the problem found or,
+// when the file matches its record, its hex SHA-256
+func (s *ServiceImpl) verifyClipFile(ctx context.Context, clip *models.Clip) (*ClipIssue, string) {
+	issue := &ClipIssue{UUID: clip.UUID, Label: clip.Label, Filename: *clip.ClipFilename}
+
+	digest, size, err := StoredDigest(ctx, s.storage, clip.Label, *clip.ClipFilename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			issue.Problem = ClipFileMissing
+		} else {
+			issue.Problem = ClipFileUnreadable
+		}
+		issue.Detail = err.Error()
+		return issue, ""
+	}
+
+	if clip.ClipSizeBytes != nil && *clip.ClipSizeBytes != size {
+		issue.Problem = ClipFileSizeMismatch
+		issue.Detail = fmt.Sprintf("expected %d bytes, found %d", *clip.ClipSizeBytes, size)
+		return issue, ""
+	}
+	if clip.ClipSHA256 != nil && *clip.ClipSHA256 != digest {
+		issue.Problem = ClipFileHashMismatch
+		issue.Detail = fmt.Sprintf("expected sha256 %s, found %s", *clip.ClipSHA256, digest)
+		return issue, ""
+	}
+	return nil, digest
+}
+
+// repairClips re-extracts or invalidates broken clips, recording the outcome
+// in the matching issues
+func (s *ServiceImpl) repairClips(ctx context.Context, clips []*models.Clip, issues []ClipIssue, mode string) error {
+	for _, clip := range clips {
+		s.resetExtraction(ctx, clip)
+	}
+
+	var episodes map[int64]*models.Episode
+	if mode == RepairReextract {
+		episodes = s.pendingEpisodes(ctx, clips)
+	}
+
+	for i, clip := range clips {
+		issues[i].Repair = mode
+		switch mode {
+		case RepairReextract:
+			if err := s.reextractClip(ctx, clip, episodes[clip.PodcastIndexEpisodeID]); err != nil {
+				log.Printf("[WARN] Failed to re-extract broken clip %s: %v", clip.UUID, err)
+				continue
+			}
+		case RepairInvalidate:
+			if err := s.db.WithContext(ctx).Model(clip).Updates(map[string]interface{}{
+				"status":        models.ClipStatusFailed,
+				"error_message": "integrity check: " + issues[i].Problem,
+				"error_type":    nil,
+				"error_code":    nil,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to invalidate clip %s: %w", clip.UUID, err)
+			}
+		}
+		issues[i].Repaired = true
+	}
+	return nil
+}
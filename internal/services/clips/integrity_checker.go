@@ -0,0 +1,66 @@
+package clips
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// IntegrityChecker periodically verifies stored clip files against their records
+type IntegrityChecker struct {
+	service  Service
+	interval time.Duration
+	repair   string
+	cancel   context.CancelFunc
+}
+
+// NewIntegrityChecker creates a checker that runs VerifyClips every interval,
+// applying the repair mode to broken clips
+func NewIntegrityChecker(service Service, interval time.Duration, repair string) *IntegrityChecker {
+	return &IntegrityChecker{
+		service:  service,
+		interval: interval,
+		repair:   repair,
+	}
+}
+
+// Start checks every interval until Stop is called. Unlike the pruners it
+// waits for the first interval, since a check reads every stored clip and
+// shouldn't repeat on each restart.
+func (c *IntegrityChecker) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.Check(ctx)
+			case <-ctx.Done():
+				log.Println("[INFO] Clip integrity checker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the checker
+func (c *IntegrityChecker) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// Check verifies the stored clips once
+func (c *IntegrityChecker) Check(ctx context.Context) {
+	result, err := c.service.VerifyClips(ctx, VerifyOptions{Repair: c.repair})
+	if err != nil {
+		log.Printf("[ERROR] Clip integrity check failed: %v", err)
+		return
+	}
+	if result.Broken > 0 {
+		log.Printf("[WARN] Clip integrity check found %d broken clips (%d repaired)", result.Broken, result.Repaired)
+	}
+}
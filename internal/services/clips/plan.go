@@ -0,0 +1,141 @@
+package clips
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Reasons a selected clip's audio can't be exported
+const (
+	MissingNoFilename = "no_filename"       // The clip was never assigned an output file
+	MissingClipFile   = "clip_missing"      // Marked extracted, but the file is gone from storage
+	MissingSource     = "source_missing"    // Not extracted and its cached source audio is gone
+	ExtractionFailed  = "extraction_failed" // Extracting from the source failed; only known after trying
+	RightsRestricted  = "rights_restricted" // The podcast's rights don't allow exporting its clips
+)
+
+// SkippedClip is a selected clip that ExportDataset left out
+type SkippedClip struct {
+	UUID      string
+	Label     string
+	EpisodeID int64  // Podcast Index episode ID
+	Extracted bool   // Whether the clip had been extracted before the export
+	Reason    string // One of the Missing* reasons, ExtractionFailed or RightsRestricted
+	Error     string // The underlying error, if there was one
+}
+
+func newSkippedClip(clip *models.Clip, reason string, err error) SkippedClip {
+	skipped := SkippedClip{
+		UUID:      clip.UUID,
+		Label:     clip.Label,
+		EpisodeID: clip.PodcastIndexEpisodeID,
+		Extracted: clip.Extracted,
+		Reason:    reason,
+	}
+	if err != nil {
+		skipped.Error = err.Error()
+	}
+	return skipped
+}
+
+// PlannedClip is one clip an export with the same options would cover
+type PlannedClip struct {
+	UUID         string
+	Label        string
+	HardNegative bool
+	EpisodeID    int64   // Podcast Index episode ID
+	Duration     float64 // Extracted length, or the annotated length when not extracted yet
+	SizeBytes    int64   // Size of the extracted file; 0 until extracted
+	Extracted    bool
+	Missing      string // One of the Missing* reasons or RightsRestricted; empty when the clip can be exported
+}
+
+// PlanExport lists the clips ExportDataset would export for opts and checks
+// that their audio can be had, without extracting, downloading or copying
+// anything. Extracted clips must still be in storage; pending clips need a
+// source, and a cached local source must still exist. Remote sources are
+// assumed reachable. Clips whose podcast's rights forbid exporting them are
+// reported as RightsRestricted without checking their audio.
+func (s *ServiceImpl) PlanExport(ctx context.Context, opts ExportOptions) ([]PlannedClip, error) {
+	var planned []PlannedClip
+
+	var lastID uint
+	for {
+		var clips []*models.Clip
+		err := s.exportQuery(ctx, opts).
+			Where("clips.id > ?", lastID).
+			Order("clips.id").
+			Limit(exportBatchSize).
+			Find(&clips).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get clips for export: %w", err)
+		}
+		if len(clips) == 0 {
+			break
+		}
+		lastID = clips[len(clips)-1].ID
+
+		rights, err := s.clipRights(ctx, clips)
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, s.planClips(ctx, clips, rights)...)
+
+		if len(clips) < exportBatchSize {
+			break
+		}
+	}
+	return planned, nil
+}
+
+// planClips checks the audio of one page of clips
+func (s *ServiceImpl) planClips(ctx context.Context, clips []*models.Clip, rights map[int64]*models.PodcastRights) []PlannedClip {
+	episodes := s.pendingEpisodes(ctx, clips)
+
+	planned := make([]PlannedClip, 0, len(clips))
+	for _, clip := range clips {
+		p := PlannedClip{
+			UUID:         clip.UUID,
+			Label:        clip.Label,
+			HardNegative: clip.IsHardNegative(),
+			EpisodeID:    clip.PodcastIndexEpisodeID,
+			Duration:     clip.GetOriginalDuration(),
+			Extracted:    clip.Extracted,
+		}
+
+		switch {
+		case exportRestriction(clip, rights) != "":
+			p.Missing = RightsRestricted
+		case clip.ClipFilename == nil:
+			p.Missing = MissingNoFilename
+		case clip.Extracted:
+			if clip.ClipDuration != nil {
+				p.Duration = *clip.ClipDuration
+			}
+			if clip.ClipSizeBytes != nil {
+				p.SizeBytes = *clip.ClipSizeBytes
+			}
+			if !s.clipStored(ctx, clip) {
+				p.Missing = MissingClipFile
+			}
+		default:
+			if !sourceAvailable(s.exportSource(ctx, clip, episodes[clip.PodcastIndexEpisodeID])) {
+				p.Missing = MissingSource
+			}
+		}
+		planned = append(planned, p)
+	}
+	return planned
+}
+
+// clipStored reports whether an extracted clip's file can be read from storage
+func (s *ServiceImpl) clipStored(ctx context.Context, clip *models.Clip) bool {
+	reader, err := s.storage.GetClip(ctx, clip.Label, *clip.ClipFilename)
+	if err != nil {
+		return false
+	}
+	reader.Close()
+	return true
+}
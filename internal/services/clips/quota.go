@@ -0,0 +1,115 @@
+package clips
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrLabelQuotaExceeded is returned when approving a clip would take its label
+// past the configured cap
+var ErrLabelQuotaExceeded = errors.New("label quota exceeded")
+
+// Option configures optional ServiceImpl behaviour
+type Option func(*ServiceImpl)
+
+// WithLabelQuotas caps the number of approved clips per label. Approved clips
+// are what datasets are built from, so the caps keep the corpus balanced;
+// unapproved suggestions and rejected clips don't count. Labels without an
+// entry, or with a cap of 0 or less, are unlimited.
+func WithLabelQuotas(quotas map[string]int) Option {
+	return func(s *ServiceImpl) {
+		s.labelQuotas = make(map[string]int, len(quotas))
+		for label, quota := range quotas {
+			if quota > 0 {
+				s.labelQuotas[label] = quota
+			}
+		}
+	}
+}
+
+// LabelStats summarizes the clips stored under one label
+type LabelStats struct {
+	Label            string  `json:"label"`
+	Clips            int64   `json:"clips"`                     // All clips, whatever their review state
+	Approved         int64   `json:"approved"`                  // Clips that go into datasets
+	Rejected         int64   `json:"rejected"`                  // Dismissed by a reviewer
+	Pending          int64   `json:"pending"`                   // Neither approved nor rejected
+	Extracted        int64   `json:"extracted"`                 // Clips with audio on disk
+	DurationSeconds  float64 `json:"duration_seconds"`          // Annotated length of all clips
+	ApprovedDuration float64 `json:"approved_duration_seconds"` // Annotated length of approved clips
+	Bytes            int64   `json:"bytes"`                     // Size of extracted audio
+	Quota            int     `json:"quota,omitempty"`           // Cap on approved clips; 0 = unlimited
+	Remaining        *int    `json:"remaining,omitempty"`       // Approvals left under the quota
+}
+
+// quotaFor returns the approved-clip cap for label, 0 when unlimited
+func (s *ServiceImpl) quotaFor(label string) int {
+	return s.labelQuotas[label]
+}
+
+// checkLabelQuota fails with ErrLabelQuotaExceeded when label already holds its
+// quota of approved clips. It runs inside the transaction that approves the
+// clip so concurrent approvals can't both squeeze under the cap.
+func (s *ServiceImpl) checkLabelQuota(tx *gorm.DB, label string) error {
+	quota := s.quotaFor(label)
+	if quota == 0 {
+		return nil
+	}
+
+	var approved int64
+	if err := tx.Model(&models.Clip{}).
+		Where("label = ? AND approved = ?", label, true).
+		Count(&approved).Error; err != nil {
+		return fmt.Errorf("failed to count %q clips: %w", label, err)
+	}
+	if approved >= int64(quota) {
+		return fmt.Errorf("%w: %q already has %d of %d approved clips", ErrLabelQuotaExceeded, label, approved, quota)
+	}
+	return nil
+}
+
+// LabelStats returns per-label clip counts, durations and sizes, including
+// labels that have a quota but no clips yet
+func (s *ServiceImpl) LabelStats(ctx context.Context) ([]LabelStats, error) {
+	var rows []LabelStats
+	err := s.db.WithContext(ctx).Model(&models.Clip{}).
+		Select(`label,
+			COUNT(*) AS clips,
+			SUM(CASE WHEN approved THEN 1 ELSE 0 END) AS approved,
+			SUM(CASE WHEN rejected THEN 1 ELSE 0 END) AS rejected,
+			SUM(CASE WHEN NOT approved AND NOT rejected THEN 1 ELSE 0 END) AS pending,
+			SUM(CASE WHEN extracted THEN 1 ELSE 0 END) AS extracted,
+			COALESCE(SUM(original_end_time - original_start_time), 0) AS duration_seconds,
+			COALESCE(SUM(CASE WHEN approved THEN original_end_time - original_start_time ELSE 0 END), 0) AS approved_duration,
+			COALESCE(SUM(CASE WHEN extracted THEN clip_size_bytes ELSE 0 END), 0) AS bytes`).
+		Group("label").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate clip stats: %w", err)
+	}
+
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		seen[row.Label] = true
+	}
+	for label := range s.labelQuotas {
+		if !seen[label] {
+			rows = append(rows, LabelStats{Label: label})
+		}
+	}
+
+	for i := range rows {
+		if quota := s.quotaFor(rows[i].Label); quota > 0 {
+			remaining := max(quota-int(rows[i].Approved), 0)
+			rows[i].Quota = quota
+			rows[i].Remaining = &remaining
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Label < rows[j].Label })
+	return rows, nil
+}
@@ -0,0 +1,116 @@
+package clips
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// ReextractResult counts what ReextractClips did
+type ReextractResult struct {
+	Matched     int `json:"matched"`     // Extracted clips the filters selected
+	Reextracted int `json:"reextracted"` // Extracted again with the current settings
+	Failed      int `json:"failed"`      // Reset but not extracted again; see each clip's error_message
+}
+
+// ReextractProgress is told after each clip how many of total are done
+type ReextractProgress func(done, total int)
+
+// ReextractClips re-extracts the already-extracted clips matching filters, so
+// that changes to the extractor settings (target duration, normalization)
+// apply to them. Each clip's stored file is deleted and the clip reset to
+// pending before it is extracted again from the episode's audio. Clips that
+// can't be extracted again are left pending or failed with the reason, never
+// with the stale file. Only the clips matching when the call starts are
+// processed; progress may be nil.
+func (s *ServiceImpl) ReextractClips(ctx context.Context, filters ExportFilters, progress ReextractProgress) (*ReextractResult, error) {
+	var ids []uint
+	query := s.db.WithContext(ctx).Model(&models.Clip{}).Where("clips.extracted = ?", true)
+	if err := s.applyExportFilters(query, filters).Order("clips.id").Pluck("clips.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to select clips to re-extract: %w", err)
+	}
+
+	result := &ReextractResult{Matched: len(ids)}
+	for start := 0; start < len(ids); start += exportBatchSize {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		var clips []*models.Clip
+		batch := ids[start:min(start+exportBatchSize, len(ids))]
+		if err := s.db.WithContext(ctx).Where("id IN ?", batch).Order("id").Find(&clips).Error; err != nil {
+			return result, fmt.Errorf("failed to load clips to re-extract: %w", err)
+		}
+
+		for _, clip := range clips {
+			s.resetExtraction(ctx, clip)
+		}
+		episodes := s.pendingEpisodes(ctx, clips)
+
+		for i, clip := range clips {
+			if err := s.reextractClip(ctx, clip, episodes[clip.PodcastIndexEpisodeID]); err != nil {
+				log.Printf("[WARN] Failed to re-extract clip %s: %v", clip.UUID, err)
+				result.Failed++
+			} else {
+				result.Reextracted++
+			}
+			if progress != nil {
+				progress(start+i+1, len(ids))
+			}
+		}
+	}
+
+	log.Printf("[INFO] Re-extracted %d/%d clips (%d failed)", result.Reextracted, result.Matched, result.Failed)
+	return result, nil
+}
+
+// resetExtraction deletes a clip's stored file and marks it pending
+func (s *ServiceImpl) resetExtraction(ctx context.Context, clip *models.Clip) {
+	if clip.ClipFilename != nil {
+		if err := s.storage.DeleteClip(ctx, clip.Label, *clip.ClipFilename); err != nil {
+			log.Printf("[WARN] Failed to delete stale file of clip %s: %v", clip.UUID, err)
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Model(clip).Updates(map[string]interface{}{
+		"extracted":       false,
+		"status":          models.ClipStatusPending,
+		"clip_duration":   nil,
+		"clip_size_bytes": nil,
+		"clip_sha256":     nil,
+		"error_message":   nil,
+		"error_type":      nil,
+		"error_code":      nil,
+		"updated_at":      time.Now(),
+	}).Error; err != nil {
+		log.Printf("[WARN] Failed to reset clip %s: %v", clip.UUID, err)
+	}
+	clip.Extracted = false
+	clip.ClipDuration = nil
+	clip.ClipSizeBytes = nil
+	clip.ClipSHA256 = nil
+}
+
+// reextractClip extracts a reset clip into storage, marking it failed when
+// that isn't possible
+func (s *ServiceImpl) reextractClip(ctx context.Context, clip *models.Clip, episode *models.Episode) error {
+	var err error
+	if source := s.exportSource(ctx, clip, episode); !sourceAvailable(source) {
+		err = errors.New("source audio is not available")
+	} else {
+		err = s.extractToStorage(ctx, clip, source)
+	}
+	if err != nil {
+		s.db.Model(clip).Updates(map[string]interface{}{
+			"status":        models.ClipStatusFailed,
+			"error_message": err.Error(),
+			"error_type":    nil,
+			"error_code":    nil,
+		})
+	}
+	return err
+}
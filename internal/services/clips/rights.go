@@ -0,0 +1,89 @@
+package clips
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// episodeRights is a podcast's rights joined to one of its episodes
+type episodeRights struct {
+	EpisodeID            int64
+	models.PodcastRights `gorm:"embedded"`
+}
+
+// clipRights loads the rights clips inherit from their podcasts, by Podcast
+// Index episode ID. Clips of podcasts without recorded rights aren't in the map.
+func (s *ServiceImpl) clipRights(ctx context.Context, clips []*models.Clip) (map[int64]*models.PodcastRights, error) {
+	episodeIDs := make([]int64, 0, len(clips))
+	seen := make(map[int64]bool, len(clips))
+	for _, clip := range clips {
+		if !seen[clip.PodcastIndexEpisodeID] {
+			seen[clip.PodcastIndexEpisodeID] = true
+			episodeIDs = append(episodeIDs, clip.PodcastIndexEpisodeID)
+		}
+	}
+	if len(episodeIDs) == 0 {
+		return nil, nil
+	}
+
+	var rows []episodeRights
+	err := s.db.WithContext(ctx).
+		Model(&models.Episode{}).
+		Select("episodes.podcast_index_id AS episode_id, podcast_rights.*").
+		Joins("JOIN podcast_rights ON podcast_rights.podcast_index_feed_id = episodes.podcast_index_feed_id").
+		Where("episodes.podcast_index_id IN ?", episodeIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load podcast rights for export: %w", err)
+	}
+
+	rights := make(map[int64]*models.PodcastRights, len(rows))
+	for i := range rows {
+		rights[rows[i].EpisodeID] = &rows[i].PodcastRights
+	}
+	return rights, nil
+}
+
+// withoutRestricted drops the clips whose podcast's rights forbid exporting
+// them, returning them as skipped
+func withoutRestricted(clips []*models.Clip, rights map[int64]*models.PodcastRights) ([]*models.Clip, []SkippedClip) {
+	if len(rights) == 0 {
+		return clips, nil
+	}
+	allowed := make([]*models.Clip, 0, len(clips))
+	var skipped []SkippedClip
+	for _, clip := range clips {
+		if restriction := exportRestriction(clip, rights); restriction != "" {
+			skipped = append(skipped, newSkippedClip(clip, RightsRestricted, errors.New(restriction)))
+			continue
+		}
+		allowed = append(allowed, clip)
+	}
+	return allowed, skipped
+}
+
+// exportRestriction is why clip can't be exported, or "" when it can
+func exportRestriction(clip *models.Clip, rights map[int64]*models.PodcastRights) string {
+	if r := rights[clip.PodcastIndexEpisodeID]; r != nil {
+		return r.ExportRestriction()
+	}
+	return ""
+}
+
+// rightsColumns are the manifest columns a clip inherits from its podcast's
+// rights; all nil when the podcast has none recorded
+type rightsColumns struct {
+	License     *string  `json:"license"`
+	FairUseNote *string  `json:"fair_use_note"`
+	AllowedUses []string `json:"allowed_uses"`
+}
+
+func newRightsColumns(r *models.PodcastRights) rightsColumns {
+	if r == nil {
+		return rightsColumns{}
+	}
+	return rightsColumns{License: &r.License, FairUseNote: &r.FairUseNote, AllowedUses: r.AllowedUses()}
+}
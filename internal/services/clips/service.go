@@ -2,11 +2,14 @@ package clips
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,20 +29,68 @@ type Service interface {
 	// GetClipsByEpisodeID retrieves all clips for an episode
 	GetClipsByEpisodeID(ctx context.Context, episodeID int64) ([]*models.Clip, error)
 
+	// SampleRates returns the original sample rate of each episode's cached
+	// audio; episodes without cached audio or a probed rate are left out
+	SampleRates(ctx context.Context, episodeIDs []int64) (map[int64]int, error)
+
+	// AttachTranscriptText sets TranscriptText on clips without stored text to
+	// the excerpt of their episode's timed transcript. The excerpt isn't saved,
+	// so it follows the transcript when an episode is transcribed again.
+	AttachTranscriptText(ctx context.Context, clips []*models.Clip) error
+
 	// UpdateClipLabel updates the label of a clip
 	UpdateClipLabel(ctx context.Context, uuid, newLabel string) (*models.Clip, error)
 
-	// ApproveClip marks a clip as approved for extraction/export
+	// ApproveClip marks a clip as approved for extraction/export. Approving
+	// an auto-approved clip confirms it.
 	ApproveClip(ctx context.Context, uuid string) (*models.Clip, error)
 
+	// AutoApproveClip approves a pending clip on behalf of analysis because
+	// its score met threshold, leaving it auto-approved until a reviewer
+	// confirms or rejects it
+	AutoApproveClip(ctx context.Context, uuid string, threshold float64) (*models.Clip, error)
+
+	// RejectClip marks a clip as rejected with a reason code; the clip is kept
+	// so the negative signal survives
+	RejectClip(ctx context.Context, uuid, reason string) (*models.Clip, error)
+
 	// DeleteClip deletes a clip and its file
 	DeleteClip(ctx context.Context, uuid string) error
 
+	// DeleteClips deletes every clip matching filters in one transaction,
+	// recording an audit row for each, and returns the deleted UUIDs
+	DeleteClips(ctx context.Context, filters ListClipsFilters) ([]string, error)
+
 	// ListClips lists clips with optional filters
 	ListClips(ctx context.Context, filters ListClipsFilters) ([]*models.Clip, error)
 
-	// ExportDataset exports clips for ML training
-	ExportDataset(ctx context.Context, exportPath string) error
+	// CountClips counts the clips ListClips would return without Limit and Offset
+	CountClips(ctx context.Context, filters ListClipsFilters) (int64, error)
+
+	// GetAnnotationHistory returns the audit trail of a clip, oldest change first
+	GetAnnotationHistory(ctx context.Context, uuid string) ([]models.AnnotationAudit, error)
+
+	// ReviewQueue lists clips awaiting review ordered by label confidence
+	ReviewQueue(ctx context.Context, filters ReviewQueueFilters) ([]*models.Clip, int64, error)
+
+	// LabelStats returns clip counts, durations and sizes per label with any quota
+	LabelStats(ctx context.Context) ([]LabelStats, error)
+
+	// ExportDataset exports clips for ML training and returns the selected
+	// clips it had to leave out
+	ExportDataset(ctx context.Context, exportPath string, opts ExportOptions) ([]SkippedClip, error)
+
+	// PlanExport lists the clips ExportDataset would export and whether their
+	// audio is available, without writing anything
+	PlanExport(ctx context.Context, opts ExportOptions) ([]PlannedClip, error)
+
+	// ReextractClips discards the audio of extracted clips matching filters and
+	// extracts them again with the current extractor settings
+	ReextractClips(ctx context.Context, filters ExportFilters, progress ReextractProgress) (*ReextractResult, error)
+
+	// VerifyClips checks ready clips' stored files against their recorded size
+	// and hash, optionally repairing the ones that don't match
+	VerifyClips(ctx context.Context, opts VerifyOptions) (*VerifyResult, error)
 }
 
 // CreateClipParams contains parameters for creating a clip
@@ -48,15 +99,94 @@ type CreateClipParams struct {
 	OriginalStartTime     float64
 	OriginalEndTime       float64
 	Label                 string
-	Approved              bool // Whether clip is approved for extraction (false for analysis results)
+	Approved              bool   // Whether clip is approved for extraction (false for analysis results)
+	CreatedBy             string // Authenticated user creating the clip, if any
+	TranscriptText        string // Spoken text of the range, when created from a transcript selection
+
+	// Registry model whose detection the clip comes from, with its score for
+	// the label; empty for clips created by hand
+	ModelName    string
+	ModelVersion string
+	Confidence   *float64
+}
+
+// LabelMethodModel is the LabelMethod of clips created from the detections of
+// a registry model
+const LabelMethodModel = "model"
+
+// Sort keys for ListClipsFilters
+const (
+	SortByCreatedAt  = "created_at" // Creation time (the default)
+	SortByDuration   = "duration"   // Length of the original time range
+	SortByConfidence = "confidence" // Label confidence; clips without one sort last
+)
+
+// IsValidClipSort reports whether sort is one of the SortBy* keys
+func IsValidClipSort(sort string) bool {
+	switch sort {
+	case SortByCreatedAt, SortByDuration, SortByConfidence:
+		return true
+	}
+	return false
 }
 
 // ListClipsFilters contains filters for listing clips
 type ListClipsFilters struct {
-	EpisodeID *int64 // Optional: filter by episode ID
+	EpisodeID    *int64 // Optional: filter by episode ID
+	PodcastID    *int64 // Optional: filter by the episode's Podcast Index feed ID
+	Label        string
+	Status       string
+	Approved     *bool      // Optional: filter by approval status
+	Rejected     *bool      // Optional: filter by rejection status
+	AutoApproved *bool      // Optional: filter by whether the approval came from analysis and awaits confirmation
+	Reason       string     // Optional: filter rejected clips by reason code
+	Model        string     // Optional: filter by the name of the registry model that created the clip
+	CreatedAfter *time.Time // Optional: clips created at or after this time
+	CreatedBy    string     // Optional: filter by the user that created the clip
+	Sort         string     // One of the SortBy* keys; defaults to SortByCreatedAt
+	Ascending    bool       // Sort ascending instead of descending
+	Limit        int
+	Offset       int
+}
+
+// ExportOptions controls which clips ExportDataset writes
+type ExportOptions struct {
+	// IncludeHardNegatives also exports clips rejected as false positives,
+	// under models.HardNegativesDir
+	IncludeHardNegatives bool
+
+	// Format selects the metadata layout: models.DatasetFormatJSONL (the
+	// default) or models.DatasetFormatAudioFolder, which datasets.load_dataset
+	// ("audiofolder") reads directly
+	Format string
+
+	// Filters narrows the export; the zero value exports every eligible clip
+	Filters ExportFilters
+}
+
+// ExportFilters restricts which clips an export covers. They are applied in
+// SQL, so filtering the whole corpus costs no more than exporting a slice of it.
+type ExportFilters struct {
+	Labels        []string   `json:"labels,omitempty"`         // Only these labels
+	MinDuration   float64    `json:"min_duration,omitempty"`   // Shortest annotated length in seconds (0 = no minimum)
+	MaxDuration   float64    `json:"max_duration,omitempty"`   // Longest annotated length in seconds (0 = no maximum)
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`  // Clips created at or after this time
+	CreatedBefore *time.Time `json:"created_before,omitempty"` // Clips created before this time
+	PodcastIDs    []int64    `json:"podcast_ids,omitempty"`    // Podcast Index feed IDs
+	EpisodeIDs    []int64    `json:"episode_ids,omitempty"`    // Podcast Index episode IDs; empty means all episodes
+}
+
+// ErrClipNotFound is returned when no clip has the given UUID
+var ErrClipNotFound = errors.New("clip not found")
+
+// ErrInvalidRejectionReason is returned when RejectClip gets an unknown reason code
+var ErrInvalidRejectionReason = errors.New("invalid rejection reason")
+
+// ReviewQueueFilters contains filters for the clip review queue
+type ReviewQueueFilters struct {
+	EpisodeID *int64 // Optional: restrict the queue to one episode
 	Label     string
-	Status    string
-	Approved  *bool // Optional: filter by approval status
+	Ascending bool // Lowest confidence first, for working through hard cases
 	Limit     int
 	Offset    int
 }
@@ -69,10 +199,23 @@ type ServiceImpl struct {
 	jobService     jobs.Service
 	episodeService interface {
 		GetEpisodeByPodcastIndexID(ctx context.Context, podcastIndexID int64) (*models.Episode, error)
+		GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) (map[int64]*models.Episode, error)
 	}
 	audioCacheService interface {
 		GetCachedAudio(ctx context.Context, podcastIndexEpisodeID int64) (*models.AudioCache, error)
 	}
+	labelQuotas    map[string]int // Approved-clip cap per label; see WithLabelQuotas
+	statsRefresher StatsRefresher // Optional; see WithStatsRefresher
+	tempDir        string         // Extractions in progress; see WithTempDir
+}
+
+// WithTempDir extracts clips into dir before they are stored, so the cleanup
+// service sweeps what a crash leaves behind. The default is the system temp
+// directory.
+func WithTempDir(dir string) Option {
+	return func(s *ServiceImpl) {
+		s.tempDir = dir
+	}
 }
 
 func NewService(
@@ -82,19 +225,26 @@ func NewService(
 	jobService jobs.Service,
 	episodeService interface {
 		GetEpisodeByPodcastIndexID(ctx context.Context, podcastIndexID int64) (*models.Episode, error)
+		GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) (map[int64]*models.Episode, error)
 	},
 	audioCacheService interface {
 		GetCachedAudio(ctx context.Context, podcastIndexEpisodeID int64) (*models.AudioCache, error)
 	},
+	opts ...Option,
 ) Service {
-	return &ServiceImpl{
+	s := &ServiceImpl{
 		db:                db,
 		storage:           storage,
 		extractor:         extractor,
 		jobService:        jobService,
 		episodeService:    episodeService,
 		audioCacheService: audioCacheService,
+		tempDir:           os.TempDir(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *ServiceImpl) CreateClip(ctx context.Context, params CreateClipParams) (*models.Clip, error) {
@@ -119,18 +269,28 @@ func (s *ServiceImpl) CreateClip(ctx context.Context, params CreateClipParams) (
 		return nil, fmt.Errorf("episode %d has no audio URL", params.PodcastIndexEpisodeID)
 	}
 
+	timeRange, err := CheckTimeRange(params.OriginalStartTime, params.OriginalEndTime, episode)
+	if err != nil {
+		return nil, err
+	}
+	if timeRange.Clamped {
+		log.Printf("[WARN] Clamped clip end for episode %d from %.2fs to %.2fs", params.PodcastIndexEpisodeID, params.OriginalEndTime, timeRange.End)
+	}
+
 	var sourceURL string
 	if s.audioCacheService != nil {
 		cache, err := s.audioCacheService.GetCachedAudio(ctx, params.PodcastIndexEpisodeID)
-		if err == nil && cache != nil && cache.OriginalPath != "" {
+		if err == nil && cache != nil {
 			// Use cached local file (MUCH faster - no download needed!)
-			sourceURL = cache.OriginalPath
-			log.Printf("[DEBUG] Using cached audio for episode %d: %s", params.PodcastIndexEpisodeID, sourceURL)
+			sourceURL = cachedSource(cache)
+			if sourceURL != "" {
+				log.Printf("[DEBUG] Using cached audio for episode %d: %s", params.PodcastIndexEpisodeID, sourceURL)
+			}
 		}
 	}
 
 	if sourceURL == "" {
-		sourceURL = episode.AudioURL
+		sourceURL = episode.DownloadURL()
 		log.Printf("[DEBUG] Using remote audio URL for episode %d: %s", params.PodcastIndexEpisodeID, sourceURL)
 	}
 
@@ -144,23 +304,47 @@ func (s *ServiceImpl) CreateClip(ctx context.Context, params CreateClipParams) (
 		UUID:                  clipID,
 		PodcastIndexEpisodeID: params.PodcastIndexEpisodeID,
 		SourceEpisodeURL:      sourceURL,
-		OriginalStartTime:     params.OriginalStartTime,
-		OriginalEndTime:       params.OriginalEndTime,
+		OriginalStartTime:     timeRange.Start,
+		OriginalEndTime:       timeRange.End,
+		RangeClamped:          timeRange.Clamped,
 		Label:                 params.Label,
 		ClipFilename:          &filename,
 		Status:                initialStatus,
 		Extracted:             false,
 		Approved:              params.Approved,
+		CreatedBy:             params.CreatedBy,
 		LabelMethod:           "manual",
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
 
-	if err := s.db.Create(clip).Error; err != nil {
-		return nil, fmt.Errorf("failed to create clip record: %w", err)
+	if params.TranscriptText != "" {
+		clip.TranscriptText = &params.TranscriptText
+	}
+	if params.ModelName != "" {
+		clip.ModelName = params.ModelName
+		clip.ModelVersion = params.ModelVersion
+		clip.LabelConfidence = params.Confidence
+		clip.LabelMethod = LabelMethodModel
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if clip.Approved {
+			if err := s.checkLabelQuota(tx, clip.Label); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(clip).Error; err != nil {
+			return fmt.Errorf("failed to create clip record: %w", err)
+		}
+		return RecordAnnotationChange(tx, clip, params.CreatedBy, models.AnnotationActionCreate, nil, clip.Snapshot())
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	log.Printf("[DEBUG] Created clip %s (approved=%v, status=pending)", clipID, params.Approved)
+	s.refreshStats(ctx, clip.PodcastIndexEpisodeID)
 	return clip, nil
 }
 
@@ -168,7 +352,7 @@ func (s *ServiceImpl) GetClip(ctx context.Context, uuid string) (*models.Clip, e
 	var clip models.Clip
 	if err := s.db.Where("uuid = ?", uuid).First(&clip).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("clip not found")
+			return nil, ErrClipNotFound
 		}
 		return nil, fmt.Errorf("failed to get clip: %w", err)
 	}
@@ -185,6 +369,90 @@ func (s *ServiceImpl) GetClipsByEpisodeID(ctx context.Context, episodeID int64)
 	return clips, nil
 }
 
+// SampleRates returns the original sample rate of each episode's cached audio
+func (s *ServiceImpl) SampleRates(ctx context.Context, episodeIDs []int64) (map[int64]int, error) {
+	rates := make(map[int64]int, len(episodeIDs))
+	if len(episodeIDs) == 0 {
+		return rates, nil
+	}
+	var rows []struct {
+		PodcastIndexEpisodeID int64
+		OriginalSampleRate    int
+	}
+	err := s.db.WithContext(ctx).Model(&models.AudioCache{}).
+		Select("podcast_index_episode_id, MAX(original_sample_rate) AS original_sample_rate").
+		Where("podcast_index_episode_id IN ? AND original_sample_rate > 0", episodeIDs).
+		Group("podcast_index_episode_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sample rates: %w", err)
+	}
+	for _, row := range rows {
+		rates[row.PodcastIndexEpisodeID] = row.OriginalSampleRate
+	}
+	return rates, nil
+}
+
+// AttachTranscriptText fills in TranscriptText from the clips' episode transcripts
+func (s *ServiceImpl) AttachTranscriptText(ctx context.Context, clips []*models.Clip) error {
+	seen := make(map[int64]bool, len(clips))
+	var episodeIDs []int64
+	for _, clip := range clips {
+		if clip.TranscriptText == nil && !seen[clip.PodcastIndexEpisodeID] {
+			seen[clip.PodcastIndexEpisodeID] = true
+			episodeIDs = append(episodeIDs, clip.PodcastIndexEpisodeID)
+		}
+	}
+	if len(episodeIDs) == 0 {
+		return nil
+	}
+
+	var transcripts []models.Transcription
+	if err := s.db.WithContext(ctx).
+		Select("podcast_index_episode_id", "segments_data").
+		Where("podcast_index_episode_id IN ?", episodeIDs).
+		Find(&transcripts).Error; err != nil {
+		return fmt.Errorf("failed to load transcripts: %w", err)
+	}
+	segments := make(map[int64][]models.TranscriptSegment, len(transcripts))
+	for i := range transcripts {
+		decoded, err := transcripts[i].Segments()
+		if err != nil {
+			log.Printf("[WARN] Skipping undecodable transcript of episode %d: %v", transcripts[i].PodcastIndexEpisodeID, err)
+			continue
+		}
+		segments[transcripts[i].PodcastIndexEpisodeID] = decoded
+	}
+
+	for _, clip := range clips {
+		if clip.TranscriptText != nil {
+			continue
+		}
+		if text := clip.TranscriptExcerpt(segments[clip.PodcastIndexEpisodeID]); text != "" {
+			clip.TranscriptText = &text
+		}
+	}
+	return nil
+}
+
+// clipSampleRates loads the sample rates of the clips' episodes. Sample
+// coordinates are optional, so a failed lookup only drops them.
+func (s *ServiceImpl) clipSampleRates(ctx context.Context, clips []*models.Clip) map[int64]int {
+	seen := make(map[int64]bool, len(clips))
+	var episodeIDs []int64
+	for _, clip := range clips {
+		if !seen[clip.PodcastIndexEpisodeID] {
+			seen[clip.PodcastIndexEpisodeID] = true
+			episodeIDs = append(episodeIDs, clip.PodcastIndexEpisodeID)
+		}
+	}
+	rates, err := s.SampleRates(ctx, episodeIDs)
+	if err != nil {
+		log.Printf("[WARN] Exporting clips without sample coordinates: %v", err)
+	}
+	return rates
+}
+
 func (s *ServiceImpl) UpdateClipLabel(ctx context.Context, uuid, newLabel string) (*models.Clip, error) {
 	if newLabel == "" {
 		return nil, fmt.Errorf("label cannot be empty")
@@ -193,7 +461,7 @@ func (s *ServiceImpl) UpdateClipLabel(ctx context.Context, uuid, newLabel string
 	var clip models.Clip
 	if err := s.db.Where("uuid = ?", uuid).First(&clip).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("clip not found")
+			return nil, ErrClipNotFound
 		}
 		return nil, fmt.Errorf("failed to get clip: %w", err)
 	}
@@ -203,6 +471,7 @@ func (s *ServiceImpl) UpdateClipLabel(ctx context.Context, uuid, newLabel string
 	}
 
 	oldLabel := clip.Label
+	before := clip.Snapshot()
 
 	if clip.Status == "ready" && clip.ClipFilename != nil {
 		if err := s.storage.MoveClip(ctx, oldLabel, newLabel, *clip.ClipFilename); err != nil {
@@ -213,13 +482,25 @@ func (s *ServiceImpl) UpdateClipLabel(ctx context.Context, uuid, newLabel string
 	clip.Label = newLabel
 	clip.UpdatedAt = time.Now()
 
-	if err := s.db.Save(&clip).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if clip.Approved {
+			if err := s.checkLabelQuota(tx, newLabel); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&clip).Error; err != nil {
+			return fmt.Errorf("failed to update clip: %w", err)
+		}
+		return RecordAnnotationChange(tx, &clip, actorFrom(ctx), models.AnnotationActionUpdate, before, clip.Snapshot())
+	})
+	if err != nil {
 		if clip.Status == "ready" && clip.ClipFilename != nil {
 			_ = s.storage.MoveClip(ctx, newLabel, oldLabel, *clip.ClipFilename)
 		}
-		return nil, fmt.Errorf("failed to update clip: %w", err)
+		return nil, err
 	}
 
+	s.refreshStats(ctx, clip.PodcastIndexEpisodeID)
 	return &clip, nil
 }
 
@@ -227,22 +508,123 @@ func (s *ServiceImpl) ApproveClip(ctx context.Context, uuid string) (*models.Cli
 	var clip models.Clip
 	if err := s.db.Where("uuid = ?", uuid).First(&clip).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("clip not found")
+			return nil, ErrClipNotFound
 		}
 		return nil, fmt.Errorf("failed to get clip: %w", err)
 	}
 
-	if clip.Approved {
+	if clip.Approved && !clip.AutoApproved {
 		return &clip, nil // Already approved - idempotent operation
 	}
 
+	// A confirmed auto-approval already counts against the quota
+	checkQuota := !clip.Approved
+	before := clip.Snapshot()
 	clip.Approved = true
+	clip.AutoApproved = false
+	clip.ApprovalThreshold = nil
+	clip.Rejected = false
+	clip.RejectionReason = ""
+	clip.RejectedAt = nil
 	clip.UpdatedAt = time.Now()
 
-	if err := s.db.Save(&clip).Error; err != nil {
-		return nil, fmt.Errorf("failed to approve clip: %w", err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if checkQuota {
+			if err := s.checkLabelQuota(tx, clip.Label); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&clip).Error; err != nil {
+			return fmt.Errorf("failed to approve clip: %w", err)
+		}
+		return RecordAnnotationChange(tx, &clip, actorFrom(ctx), models.AnnotationActionApprove, before, clip.Snapshot())
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	s.refreshStats(ctx, clip.PodcastIndexEpisodeID)
+	return &clip, nil
+}
+
+func (s *ServiceImpl) AutoApproveClip(ctx context.Context, uuid string, threshold float64) (*models.Clip, error) {
+	var clip models.Clip
+	if err := s.db.WithContext(ctx).Where("uuid = ?", uuid).First(&clip).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrClipNotFound
+		}
+		return nil, fmt.Errorf("failed to get clip: %w", err)
+	}
+
+	// Reviewers' decisions stand
+	if clip.Approved || clip.Rejected {
+		return &clip, nil
+	}
+
+	before := clip.Snapshot()
+	clip.Approved = true
+	clip.AutoApproved = true
+	clip.ApprovalThreshold = &threshold
+	clip.UpdatedAt = time.Now()
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkLabelQuota(tx, clip.Label); err != nil {
+			return err
+		}
+		if err := tx.Save(&clip).Error; err != nil {
+			return fmt.Errorf("failed to auto-approve clip: %w", err)
+		}
+		return RecordAnnotationChange(tx, &clip, "", models.AnnotationActionAutoApprove, before, clip.Snapshot())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshStats(ctx, clip.PodcastIndexEpisodeID)
+	return &clip, nil
+}
+
+func (s *ServiceImpl) RejectClip(ctx context.Context, uuid, reason string) (*models.Clip, error) {
+	if reason == "" {
+		reason = models.ClipRejectionFalsePositive
+	}
+	if !models.IsValidClipRejectionReason(reason) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRejectionReason, reason)
+	}
+
+	var clip models.Clip
+	if err := s.db.Where("uuid = ?", uuid).First(&clip).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrClipNotFound
+		}
+		return nil, fmt.Errorf("failed to get clip: %w", err)
+	}
+
+	if clip.Rejected && clip.RejectionReason == reason {
+		return &clip, nil // Already rejected for this reason - idempotent operation
+	}
+
+	before := clip.Snapshot()
+	now := time.Now()
+	clip.Rejected = true
+	clip.RejectionReason = reason
+	clip.RejectedAt = &now
+	clip.Approved = false
+	clip.AutoApproved = false
+	clip.ApprovalThreshold = nil
+	clip.UpdatedAt = now
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&clip).Error; err != nil {
+			return fmt.Errorf("failed to reject clip: %w", err)
+		}
+		return RecordAnnotationChange(tx, &clip, actorFrom(ctx), models.AnnotationActionReject, before, clip.Snapshot())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshStats(ctx, clip.PodcastIndexEpisodeID)
 	return &clip, nil
 }
 
@@ -261,19 +643,119 @@ func (s *ServiceImpl) DeleteClip(ctx context.Context, uuid string) error {
 		}
 	}
 
-	if err := s.db.Delete(&clip).Error; err != nil {
-		return fmt.Errorf("failed to delete clip record: %w", err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&clip).Error; err != nil {
+			return fmt.Errorf("failed to delete clip record: %w", err)
+		}
+		return RecordAnnotationChange(tx, &clip, actorFrom(ctx), models.AnnotationActionDelete, clip.Snapshot(), nil)
+	})
+	if err != nil {
+		return err
 	}
 
+	s.refreshStats(ctx, clip.PodcastIndexEpisodeID)
 	return nil
 }
 
+// DeleteClips deletes the clips matching filters, ignoring Sort, Limit and
+// Offset. Records and their audit rows commit together, so a failure leaves
+// every clip in place; stored files are removed only once the deletion has
+// committed.
+func (s *ServiceImpl) DeleteClips(ctx context.Context, filters ListClipsFilters) ([]string, error) {
+	filters.Limit, filters.Offset = 0, 0
+
+	var deleted []*models.Clip
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.filterClips(tx, filters).Order("id ASC").Find(&deleted).Error; err != nil {
+			return fmt.Errorf("failed to list clips: %w", err)
+		}
+		userID := actorFrom(ctx)
+		for _, clip := range deleted {
+			if err := tx.Delete(clip).Error; err != nil {
+				return fmt.Errorf("failed to delete clip record: %w", err)
+			}
+			if err := RecordAnnotationChange(tx, clip, userID, models.AnnotationActionBulkDelete, clip.Snapshot(), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uuids := make([]string, len(deleted))
+	episodeIDs := make([]int64, len(deleted))
+	for i, clip := range deleted {
+		uuids[i] = clip.UUID
+		episodeIDs[i] = clip.PodcastIndexEpisodeID
+		if clip.Status == models.ClipStatusReady && clip.ClipFilename != nil && s.storage != nil {
+			if err := s.storage.DeleteClip(ctx, clip.Label, *clip.ClipFilename); err != nil {
+				log.Printf("[WARN] Failed to delete file of clip %s: %v", clip.UUID, err)
+			}
+		}
+	}
+	s.refreshStats(ctx, episodeIDs...)
+	return uuids, nil
+}
+
+// ListClips returns clips matching filters, newest first unless filters.Sort
+// picks another key
 func (s *ServiceImpl) ListClips(ctx context.Context, filters ListClipsFilters) ([]*models.Clip, error) {
-	query := s.db.Model(&models.Clip{})
+	query := s.filterClips(s.db.WithContext(ctx), filters)
+
+	direction := "DESC"
+	if filters.Ascending {
+		direction = "ASC"
+	}
+	switch filters.Sort {
+	case SortByDuration:
+		query = query.Order("original_end_time - original_start_time " + direction)
+	case SortByConfidence:
+		query = query.
+			Order("CASE WHEN label_confidence IS NULL THEN 1 ELSE 0 END").
+			Order("label_confidence " + direction)
+	}
+	// Ties keep the default order, with id making pages stable
+	query = query.Order("created_at " + direction).Order("id " + direction)
+
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	var clips []*models.Clip
+	if err := query.Find(&clips).Error; err != nil {
+		return nil, fmt.Errorf("failed to list clips: %w", err)
+	}
+
+	return clips, nil
+}
+
+// CountClips counts the clips matching filters, ignoring Limit and Offset
+func (s *ServiceImpl) CountClips(ctx context.Context, filters ListClipsFilters) (int64, error) {
+	var total int64
+	if err := s.filterClips(s.db.WithContext(ctx), filters).Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to count clips: %w", err)
+	}
+	return total, nil
+}
+
+// filterClips builds the query shared by ListClips, CountClips and
+// DeleteClips on db, which may be a transaction
+func (s *ServiceImpl) filterClips(db *gorm.DB, filters ListClipsFilters) *gorm.DB {
+	query := db.Model(&models.Clip{})
 
 	if filters.EpisodeID != nil {
 		query = query.Where("podcast_index_episode_id = ?", *filters.EpisodeID)
 	}
+	if filters.PodcastID != nil {
+		query = query.Where("podcast_index_episode_id IN (?)",
+			s.db.Model(&models.Episode{}).Select("podcast_index_id").
+				Where("podcast_index_feed_id = ?", *filters.PodcastID))
+	}
 	if filters.Label != "" {
 		query = query.Where("label = ?", filters.Label)
 	}
@@ -283,6 +765,55 @@ func (s *ServiceImpl) ListClips(ctx context.Context, filters ListClipsFilters) (
 	if filters.Approved != nil {
 		query = query.Where("approved = ?", *filters.Approved)
 	}
+	if filters.Rejected != nil {
+		query = query.Where("rejected = ?", *filters.Rejected)
+	}
+	if filters.AutoApproved != nil {
+		query = query.Where("auto_approved = ?", *filters.AutoApproved)
+	}
+	if filters.Reason != "" {
+		query = query.Where("rejection_reason = ?", filters.Reason)
+	}
+	if filters.Model != "" {
+		query = query.Where("model_name = ?", filters.Model)
+	}
+	if filters.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filters.CreatedAfter)
+	}
+	if filters.CreatedBy != "" {
+		query = query.Where("created_by = ?", filters.CreatedBy)
+	}
+	return query
+}
+
+// ReviewQueue returns clips that have been neither approved nor rejected,
+// highest confidence first (or lowest when filters.Ascending is set). Clips
+// without a confidence score always sort last, with older clips ahead of
+// newer ones on ties. The total count ignores Limit and Offset.
+func (s *ServiceImpl) ReviewQueue(ctx context.Context, filters ReviewQueueFilters) ([]*models.Clip, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.Clip{}).
+		Where("approved = ? AND rejected = ?", false, false)
+
+	if filters.EpisodeID != nil {
+		query = query.Where("podcast_index_episode_id = ?", *filters.EpisodeID)
+	}
+	if filters.Label != "" {
+		query = query.Where("label = ?", filters.Label)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count review queue: %w", err)
+	}
+
+	direction := "DESC"
+	if filters.Ascending {
+		direction = "ASC"
+	}
+	query = query.
+		Order("CASE WHEN label_confidence IS NULL THEN 1 ELSE 0 END").
+		Order("label_confidence " + direction).
+		Order("created_at ASC")
 
 	if filters.Limit > 0 {
 		query = query.Limit(filters.Limit)
@@ -291,20 +822,18 @@ func (s *ServiceImpl) ListClips(ctx context.Context, filters ListClipsFilters) (
 		query = query.Offset(filters.Offset)
 	}
 
-	query = query.Order("created_at DESC")
-
 	var clips []*models.Clip
 	if err := query.Find(&clips).Error; err != nil {
-		return nil, fmt.Errorf("failed to list clips: %w", err)
+		return nil, 0, fmt.Errorf("failed to list review queue: %w", err)
 	}
 
-	return clips, nil
+	return clips, total, nil
 }
 
 // ExportDataset exports all approved clips to a directory for ML training.
 //
 // Extraction workflow (lazy evaluation with caching):
-// 1. Query all approved clips (may be pending or already extracted)
+// 1. Page through approved clips matching opts.Filters (may be pending or already extracted)
 // 2. For extracted clips: copy from storage cache to export dir (fast - no download)
 // 3. For pending clips: extract to temp → save to storage → copy to export dir
 // 4. Generate manifest.jsonl with metadata for all successfully exported clips
@@ -314,71 +843,289 @@ func (s *ServiceImpl) ListClips(ctx context.Context, filters ListClipsFilters) (
 // - Subsequent exports: Reuses cached clips from storage (much faster)
 // - Atomic operations: DB updates wrapped in transactions with storage cleanup on failure
 // - Exact time ranges: No padding or cropping (unless targetDuration configured)
-func (s *ServiceImpl) ExportDataset(ctx context.Context, exportPath string) error {
-	// Query ALL approved clips (not just already-extracted ones)
-	var clips []*models.Clip
-	if err := s.db.Where("approved = ?", true).Find(&clips).Error; err != nil {
-		return fmt.Errorf("failed to get approved clips: %w", err)
+//
+// With opts.IncludeHardNegatives, clips rejected as false positives are exported
+// alongside them under hard_negatives/{label}/ and flagged in the manifest.
+// With the audiofolder format the manifest is replaced by metadata.jsonl, whose
+// file_name column Hugging Face resolves against the export directory.
+// Clips inherit the rights of their podcast: the manifest carries its license
+// and allowed uses, and clips of podcasts that don't allow derivatives or
+// training are left out as RightsRestricted.
+func (s *ServiceImpl) ExportDataset(ctx context.Context, exportPath string, opts ExportOptions) ([]SkippedClip, error) {
+	// Track successfully exported clips for manifest, and the ones left out
+	var exportedClips []*models.Clip
+	var skipped []SkippedClip
+	var total int
+	rights := make(map[int64]*models.PodcastRights) // Inherited by the manifest rows
+
+	// Walk the matching clips in primary-key pages so the whole corpus can be
+	// exported without holding every row (and its episode) at once
+	var lastID uint
+	for {
+		var clips []*models.Clip
+		err := s.exportQuery(ctx, opts).
+			Where("clips.id > ?", lastID).
+			Order("clips.id").
+			Limit(exportBatchSize).
+			Find(&clips).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get clips for export: %w", err)
+		}
+		if len(clips) == 0 {
+			break
+		}
+		lastID = clips[len(clips)-1].ID
+		total += len(clips)
+
+		pageRights, err := s.clipRights(ctx, clips)
+		if err != nil {
+			return nil, err
+		}
+		for episodeID, r := range pageRights {
+			rights[episodeID] = r
+		}
+		exportable, restricted := withoutRestricted(clips, pageRights)
+		skipped = append(skipped, restricted...)
+
+		exported, left := s.exportClips(ctx, exportable, exportPath)
+		exportedClips = append(exportedClips, exported...)
+		skipped = append(skipped, left...)
+
+		if len(clips) < exportBatchSize {
+			break
+		}
 	}
 
-	if len(clips) == 0 {
+	if total == 0 {
 		log.Printf("[INFO] No approved clips to export")
-		return nil
+		return nil, nil
 	}
 
-	log.Printf("[INFO] Exporting %d approved clips", len(clips))
+	log.Printf("[INFO] Successfully exported %d/%d clips", len(exportedClips), total)
+	if len(skipped) > 0 {
+		log.Printf("[WARN] Export skipped %d clips without audio or export rights", len(skipped))
+	}
 
-	// Track successfully exported clips for manifest
-	var exportedClips []*models.Clip
+	// Create manifest from successfully exported clips
+	manifestPath := filepath.Join(exportPath, models.DatasetMetadataFile(opts.Format))
+	rates := s.clipSampleRates(ctx, exportedClips)
+	if err := s.AttachTranscriptText(ctx, exportedClips); err != nil {
+		log.Printf("[WARN] Exporting clips without transcript text: %v", err)
+	}
+	if opts.Format == models.DatasetFormatAudioFolder {
+		if err := s.createAudioFolderMetadata(manifestPath, exportedClips, rates, rights); err != nil {
+			return nil, fmt.Errorf("failed to create audiofolder metadata: %w", err)
+		}
+		return skipped, nil
+	}
+	if err := s.createManifestForClips(ctx, manifestPath, exportedClips, rates, rights); err != nil {
+		return nil, fmt.Errorf("failed to create manifest: %w", err)
+	}
+
+	return skipped, nil
+}
+
+// exportBatchSize is how many clips ExportDataset loads and processes per page
+const exportBatchSize = 500
+
+// exportQuery selects the clips an export covers: approved clips (plus hard
+// negatives when requested) narrowed by opts.Filters
+func (s *ServiceImpl) exportQuery(ctx context.Context, opts ExportOptions) *gorm.DB {
+	eligible := s.db.Where("clips.approved = ?", true)
+	if opts.IncludeHardNegatives {
+		eligible = eligible.Or("clips.rejected = ? AND clips.rejection_reason = ?", true, models.ClipRejectionFalsePositive)
+	}
+	query := s.db.WithContext(ctx).Model(&models.Clip{}).Where(eligible)
+	return s.applyExportFilters(query, opts.Filters)
+}
+
+// applyExportFilters narrows a clips query by f
+func (s *ServiceImpl) applyExportFilters(query *gorm.DB, f ExportFilters) *gorm.DB {
+	if len(f.Labels) > 0 {
+		query = query.Where("clips.label IN ?", f.Labels)
+	}
+	if f.MinDuration > 0 {
+		query = query.Where("clips.original_end_time - clips.original_start_time >= ?", f.MinDuration)
+	}
+	if f.MaxDuration > 0 {
+		query = query.Where("clips.original_end_time - clips.original_start_time <= ?", f.MaxDuration)
+	}
+	if f.CreatedAfter != nil {
+		query = query.Where("clips.created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		query = query.Where("clips.created_at < ?", *f.CreatedBefore)
+	}
+	if len(f.EpisodeIDs) > 0 {
+		query = query.Where("clips.podcast_index_episode_id IN ?", f.EpisodeIDs)
+	}
+	if len(f.PodcastIDs) > 0 {
+		query = query.Where("clips.podcast_index_episode_id IN (?)",
+			s.db.Model(&models.Episode{}).Select("podcast_index_id").Where("podcast_index_feed_id IN ?", f.PodcastIDs))
+	}
+	return query
+}
+
+// exportClips copies or extracts one page of clips into exportPath and returns
+// the ones that made it and the ones that didn't
+func (s *ServiceImpl) exportClips(ctx context.Context, clips []*models.Clip, exportPath string) ([]*models.Clip, []SkippedClip) {
+	episodes := s.pendingEpisodes(ctx, clips)
 
-	// Process each clip
+	var exported []*models.Clip
+	var skipped []SkippedClip
 	for _, clip := range clips {
+		if clip.ClipFilename == nil {
+			skipped = append(skipped, newSkippedClip(clip, MissingNoFilename, nil))
+			continue
+		}
+
 		if clip.Extracted {
 			// Clip already extracted - just copy it
 			log.Printf("[DEBUG] Copying already-extracted clip %s", clip.UUID)
 			if err := s.copyExtractedClip(ctx, clip, exportPath); err != nil {
 				log.Printf("[WARN] Failed to copy clip %s: %v", clip.UUID, err)
+				skipped = append(skipped, newSkippedClip(clip, MissingClipFile, err))
 				continue
 			}
-			exportedClips = append(exportedClips, clip)
+			exported = append(exported, clip)
 		} else {
+			source := s.exportSource(ctx, clip, episodes[clip.PodcastIndexEpisodeID])
+			if !sourceAvailable(source) {
+				log.Printf("[WARN] Skipping clip %s: source audio %q is not available", clip.UUID, source)
+				skipped = append(skipped, newSkippedClip(clip, MissingSource, nil))
+				continue
+			}
+
 			// Extract clip on-demand during export
 			log.Printf("[DEBUG] Extracting clip %s on-demand", clip.UUID)
-			if err := s.extractClipForExport(ctx, clip, exportPath); err != nil {
+			if err := s.extractClipForExport(ctx, clip, source, exportPath); err != nil {
 				log.Printf("[WARN] Failed to extract clip %s: %v", clip.UUID, err)
 				// Update clip status to failed
 				s.db.Model(clip).Updates(map[string]interface{}{
 					"status":        "failed",
 					"error_message": err.Error(),
+					"error_type":    nil,
+					"error_code":    nil,
 				})
+				skipped = append(skipped, newSkippedClip(clip, ExtractionFailed, err))
 				continue
 			}
-			exportedClips = append(exportedClips, clip)
+			exported = append(exported, clip)
 		}
 	}
+	return exported, skipped
+}
 
-	log.Printf("[INFO] Successfully exported %d/%d clips", len(exportedClips), len(clips))
+// pendingEpisodes loads, in one batch, the episodes of clips that still need
+// extraction, so a clip whose cached source audio has been evicted can fall
+// back to the feed URL
+func (s *ServiceImpl) pendingEpisodes(ctx context.Context, clips []*models.Clip) map[int64]*models.Episode {
+	var pendingEpisodeIDs []int64
+	for _, clip := range clips {
+		if !clip.Extracted {
+			pendingEpisodeIDs = append(pendingEpisodeIDs, clip.PodcastIndexEpisodeID)
+		}
+	}
+	if len(pendingEpisodeIDs) == 0 || s.episodeService == nil {
+		return nil
+	}
+	episodes, err := s.episodeService.GetEpisodesByPodcastIndexIDs(ctx, pendingEpisodeIDs)
+	if err != nil {
+		log.Printf("[WARN] Failed to load episodes for export, using stored clip sources: %v", err)
+	}
+	return episodes
+}
 
-	// Create manifest from successfully exported clips
-	manifestPath := filepath.Join(exportPath, "manifest.jsonl")
-	if err := s.createManifestForClips(ctx, manifestPath, exportedClips); err != nil {
-		return fmt.Errorf("failed to create manifest: %w", err)
+// exportSource prefers the episode's currently cached audio, which may have
+// been cached since the clip was created, over the clip's recorded source
+func (s *ServiceImpl) exportSource(ctx context.Context, clip *models.Clip, episode *models.Episode) string {
+	if s.audioCacheService != nil {
+		cache, err := s.audioCacheService.GetCachedAudio(ctx, clip.PodcastIndexEpisodeID)
+		if err == nil && cache != nil {
+			if source := cachedSource(cache); source != "" {
+				return source
+			}
+		}
 	}
+	return exportSourceURL(clip, episode)
+}
 
-	return nil
+// cachedSource picks the cached file to cut clips from: the processed
+// rendition when it is current, as clips are 16kHz mono too and ffmpeg then
+// only has to cut, else the original. It returns "" if neither file exists.
+func cachedSource(cache *models.AudioCache) string {
+	candidates := []string{cache.OriginalPath}
+	if cache.HasCurrentProcessed() {
+		candidates = []string{cache.ProcessedPath, cache.OriginalPath}
+	}
+	for _, path := range candidates {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// sourceAvailable reports whether source can be extracted from: remote URLs are
+// assumed reachable, local files must exist
+func sourceAvailable(source string) bool {
+	if source == "" {
+		return false
+	}
+	if strings.Contains(source, "://") {
+		return true
+	}
+	_, err := os.Stat(source)
+	return err == nil
+}
+
+// exportSourceURL picks the audio to extract a clip from. Clips created while the
+// episode was cached point at a local file; once the cache evicts it, the
+// episode's remote audio is used instead, from the source that last served it
+// if its enclosure URL was refused.
+func exportSourceURL(clip *models.Clip, episode *models.Episode) string {
+	source := clip.SourceEpisodeURL
+	if episode == nil || episode.AudioURL == "" {
+		return source
+	}
+	if strings.Contains(source, "://") {
+		if source == episode.AudioURL {
+			return episode.DownloadURL()
+		}
+		return source
+	}
+	if _, err := os.Stat(source); err != nil {
+		log.Printf("[DEBUG] Cached source %s for clip %s is gone, using episode audio URL", source, clip.UUID)
+		return episode.DownloadURL()
+	}
+	return source
 }
 
 // extractClipForExport extracts a clip on-demand during dataset export
 // This workflow: extract to temp → save to storage (for caching) → copy to export dir
-func (s *ServiceImpl) extractClipForExport(ctx context.Context, clip *models.Clip, exportPath string) error {
+func (s *ServiceImpl) extractClipForExport(ctx context.Context, clip *models.Clip, sourceURL, exportPath string) error {
+	if err := s.extractToStorage(ctx, clip, sourceURL); err != nil {
+		return err
+	}
+
+	// Step 4: Copy from storage to export directory
+	return s.copyFromStorageToExport(clip, exportPath)
+}
+
+// extractToStorage extracts a clip to a temp file, saves it to storage and
+// marks the clip extracted, in the database and in memory
+func (s *ServiceImpl) extractToStorage(ctx context.Context, clip *models.Clip, sourceURL string) error {
 	if clip.ClipFilename == nil {
 		return fmt.Errorf("clip has no filename")
 	}
 
 	// Step 1: Extract to temporary file
-	tempFile := filepath.Join(os.TempDir(), *clip.ClipFilename)
+	tempFile := filepath.Join(s.tempDir, *clip.ClipFilename)
 	result, err := s.extractor.ExtractClip(ctx, ExtractParams{
-		SourceURL:  clip.SourceEpisodeURL,
+		SourceURL:  sourceURL,
 		StartTime:  clip.OriginalStartTime,
 		EndTime:    clip.OriginalEndTime,
 		OutputPath: tempFile,
@@ -402,11 +1149,19 @@ func (s *ServiceImpl) extractClipForExport(ctx context.Context, clip *models.Cli
 		return fmt.Errorf("failed to save clip to storage: %w", err)
 	}
 
+	// Record what was stored, which may be an earlier extraction of the same
+	// audio, so the integrity check can detect a changed file
+	digest, size, err := StoredDigest(ctx, s.storage, clip.Label, *clip.ClipFilename)
+	if err != nil {
+		return fmt.Errorf("failed to read back stored clip: %w", err)
+	}
+
 	// Step 3: Update clip record in transaction (atomic DB operation)
 	updates := map[string]interface{}{
 		"extracted":       true,
 		"clip_duration":   result.Duration,
-		"clip_size_bytes": result.SizeBytes,
+		"clip_size_bytes": size,
+		"clip_sha256":     digest,
 		"status":          "ready",
 		"updated_at":      time.Now(),
 	}
@@ -427,11 +1182,10 @@ func (s *ServiceImpl) extractClipForExport(ctx context.Context, clip *models.Cli
 	// Update in-memory clip for manifest
 	clip.Extracted = true
 	clip.ClipDuration = &result.Duration
-	clip.ClipSizeBytes = &result.SizeBytes
+	clip.ClipSizeBytes = &size
+	clip.ClipSHA256 = &digest
 	clip.Status = "ready"
-
-	// Step 4: Copy from storage to export directory
-	return s.copyFromStorageToExport(clip, exportPath)
+	return nil
 }
 
 // copyExtractedClip copies an already-extracted clip from storage to the export directory
@@ -447,8 +1201,11 @@ func (s *ServiceImpl) copyExtractedClip(ctx context.Context, clip *models.Clip,
 	return s.copyFromStorageToExport(clip, exportPath)
 }
 
-// createManifestForClips creates a manifest file from a list of clips
-func (s *ServiceImpl) createManifestForClips(ctx context.Context, manifestPath string, clips []*models.Clip) error {
+// createManifestForClips creates a manifest file from a list of clips. Clips
+// whose episode has a known sample rate also get sample_rate, start_sample
+// and end_sample, clips with spoken text get transcript_text, and clips of
+// podcasts with recorded rights get license, fair_use_note and allowed_uses.
+func (s *ServiceImpl) createManifestForClips(ctx context.Context, manifestPath string, clips []*models.Clip, sampleRates map[int64]int, rights map[int64]*models.PodcastRights) error {
 	file, err := os.Create(manifestPath)
 	if err != nil {
 		return fmt.Errorf("failed to create manifest file: %w", err)
@@ -457,7 +1214,7 @@ func (s *ServiceImpl) createManifestForClips(ctx context.Context, manifestPath s
 
 	for _, clip := range clips {
 		export := clip.ToExport()
-		line := fmt.Sprintf(`{"file_path":"%s","label":"%s","duration":%.3f,"source_url":"%s","original_start_time":%.3f,"original_end_time":%.3f,"uuid":"%s","created_at":"%s"}`,
+		line := fmt.Sprintf(`{"file_path":"%s","label":"%s","duration":%.3f,"source_url":"%s","original_start_time":%.3f,"original_end_time":%.3f,"uuid":"%s","created_at":"%s"`,
 			export.FilePath,
 			export.Label,
 			export.Duration,
@@ -467,6 +1224,27 @@ func (s *ServiceImpl) createManifestForClips(ctx context.Context, manifestPath s
 			export.UUID,
 			export.CreatedAt,
 		)
+		if samples := clip.Samples(sampleRates[clip.PodcastIndexEpisodeID]); samples != nil {
+			line += fmt.Sprintf(`,"sample_rate":%d,"start_sample":%d,"end_sample":%d`, samples.SampleRate, samples.StartSample, samples.EndSample)
+		}
+		if export.HardNegative {
+			line += fmt.Sprintf(`,"hard_negative":true,"rejection_reason":"%s"`, export.RejectionReason)
+		}
+		if export.TranscriptText != nil {
+			text, err := json.Marshal(*export.TranscriptText)
+			if err != nil {
+				return fmt.Errorf("failed to encode transcript text: %w", err)
+			}
+			line += `,"transcript_text":` + string(text)
+		}
+		if r := rights[clip.PodcastIndexEpisodeID]; r != nil {
+			columns, err := json.Marshal(newRightsColumns(r))
+			if err != nil {
+				return fmt.Errorf("failed to encode rights: %w", err)
+			}
+			line += "," + strings.TrimSuffix(strings.TrimPrefix(string(columns), "{"), "}")
+		}
+		line += "}"
 		if _, err := file.WriteString(line + "\n"); err != nil {
 			return fmt.Errorf("failed to write manifest entry: %w", err)
 		}
@@ -475,6 +1253,70 @@ func (s *ServiceImpl) createManifestForClips(ctx context.Context, manifestPath s
 	return nil
 }
 
+// audioFolderRow is one line of a Hugging Face audiofolder metadata.jsonl. The
+// label is an explicit column because hard negatives sit one directory deeper
+// than positives, so it can't be inferred from the folder name.
+type audioFolderRow struct {
+	FileName          string   `json:"file_name"`
+	Label             string   `json:"label"`
+	HardNegative      bool     `json:"hard_negative"`
+	Duration          float64  `json:"duration"`
+	AutoLabeled       bool     `json:"auto_labeled"`
+	LabelConfidence   *float64 `json:"label_confidence"`
+	LabelMethod       string   `json:"label_method"`
+	SourceURL         string   `json:"source_url"`
+	OriginalStartTime float64  `json:"original_start_time"`
+	OriginalEndTime   float64  `json:"original_end_time"`
+	SampleRate        *int     `json:"sample_rate"` // Null when the episode's sample rate isn't known
+	StartSample       *int64   `json:"start_sample"`
+	EndSample         *int64   `json:"end_sample"`
+	TranscriptText    *string  `json:"transcript_text"` // Null when the episode has no timed transcript
+	UUID              string   `json:"uuid"`
+	CreatedAt         string   `json:"created_at"`
+	rightsColumns              // Null when the podcast has no recorded rights
+}
+
+// createAudioFolderMetadata writes metadata.jsonl for the audiofolder format.
+// Every row carries the same keys so the loader infers a single schema.
+func (s *ServiceImpl) createAudioFolderMetadata(metadataPath string, clips []*models.Clip, sampleRates map[int64]int, rights map[int64]*models.PodcastRights) error {
+	file, err := os.Create(metadataPath)
+	if err != nil {
+		return fmt.Errorf("failed to create metadata file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, clip := range clips {
+		export := clip.ToExport()
+		row := audioFolderRow{
+			FileName:          export.FilePath,
+			Label:             export.Label,
+			HardNegative:      export.HardNegative,
+			Duration:          export.Duration,
+			AutoLabeled:       export.AutoLabeled,
+			LabelConfidence:   export.LabelConfidence,
+			LabelMethod:       export.LabelMethod,
+			SourceURL:         export.SourceURL,
+			OriginalStartTime: export.OriginalStartTime,
+			OriginalEndTime:   export.OriginalEndTime,
+			TranscriptText:    export.TranscriptText,
+			UUID:              export.UUID,
+			CreatedAt:         export.CreatedAt,
+			rightsColumns:     newRightsColumns(rights[clip.PodcastIndexEpisodeID]),
+		}
+		if samples := clip.Samples(sampleRates[clip.PodcastIndexEpisodeID]); samples != nil {
+			row.SampleRate = &samples.SampleRate
+			row.StartSample = &samples.StartSample
+			row.EndSample = &samples.EndSample
+		}
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("failed to write metadata entry: %w", err)
+		}
+	}
+
+	return file.Close()
+}
+
 // copyFromStorageToExport copies a clip from storage to the export directory
 // Uses storage abstraction (GetClip) to work with any storage backend
 func (s *ServiceImpl) copyFromStorageToExport(clip *models.Clip, exportPath string) error {
@@ -489,14 +1331,13 @@ func (s *ServiceImpl) copyFromStorageToExport(clip *models.Clip, exportPath stri
 	}
 	defer reader.Close()
 
-	// Create destination directory
-	dstDir := filepath.Join(exportPath, clip.Label)
-	if err := os.MkdirAll(dstDir, 0755); err != nil {
+	// Create destination directory (hard negatives get their own tree)
+	dstPath := filepath.Join(exportPath, filepath.FromSlash(clip.GetExportPath()))
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
 		return fmt.Errorf("failed to create export label directory: %w", err)
 	}
 
 	// Write to destination file
-	dstPath := filepath.Join(dstDir, *clip.ClipFilename)
 	destFile, err := os.Create(dstPath)
 	if err != nil {
 		return fmt.Errorf("failed to create destination file: %w", err)
@@ -0,0 +1,1075 @@
+package clips
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestService(t *testing.T) (*ServiceImpl, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Clip{}, &models.AnnotationAudit{}, &models.Episode{}, &models.PodcastRights{}))
+
+	return &ServiceImpl{db: db}, db
+}
+
+func seedClip(t *testing.T, db *gorm.DB, episodeID int64, label string, confidence *float64, approved bool) *models.Clip {
+	clip := &models.Clip{
+		PodcastIndexEpisodeID: episodeID,
+		SourceEpisodeURL:      "https://example.com/episode.mp3",
+		OriginalStartTime:     0,
+		OriginalEndTime:       10,
+		Label:                 label,
+		AutoLabeled:           confidence != nil,
+		LabelConfidence:       confidence,
+		Approved:              approved,
+	}
+	require.NoError(t, db.Create(clip).Error)
+	return clip
+}
+
+func confidence(v float64) *float64 { return &v }
+
+func queueUUIDs(clips []*models.Clip) []string {
+	uuids := make([]string, len(clips))
+	for i, clip := range clips {
+		uuids[i] = clip.UUID
+	}
+	return uuids
+}
+
+func TestReviewQueue_OrdersByConfidence(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	low := seedClip(t, db, 1, "advertisement", confidence(0.4), false)
+	high := seedClip(t, db, 2, "advertisement", confidence(0.9), false)
+	unscored := seedClip(t, db, 1, "advertisement", nil, false)
+	mid := seedClip(t, db, 3, "advertisement", confidence(0.7), false)
+	seedClip(t, db, 1, "advertisement", confidence(0.95), true)
+
+	queue, total, err := service.ReviewQueue(ctx, ReviewQueueFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, []string{high.UUID, mid.UUID, low.UUID, unscored.UUID}, queueUUIDs(queue))
+
+	queue, _, err = service.ReviewQueue(ctx, ReviewQueueFilters{Ascending: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{low.UUID, mid.UUID, high.UUID, unscored.UUID}, queueUUIDs(queue))
+
+	queue, total, err = service.ReviewQueue(ctx, ReviewQueueFilters{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, []string{mid.UUID, low.UUID}, queueUUIDs(queue))
+}
+
+func TestReviewQueue_Filters(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	ad := seedClip(t, db, 1, "advertisement", confidence(0.8), false)
+	seedClip(t, db, 1, "music", confidence(0.9), false)
+	seedClip(t, db, 2, "advertisement", confidence(0.7), false)
+
+	episodeID := int64(1)
+	queue, total, err := service.ReviewQueue(ctx, ReviewQueueFilters{EpisodeID: &episodeID, Label: "advertisement"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{ad.UUID}, queueUUIDs(queue))
+}
+
+func TestListClips_SortAndCount(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+	require.NoError(t, db.AutoMigrate(&models.Episode{}))
+	require.NoError(t, db.Create(&models.Episode{PodcastID: 1, PodcastIndexID: 1, PodcastIndexFeedID: 100, GUID: "a", Title: "a", AudioURL: "a"}).Error)
+	require.NoError(t, db.Create(&models.Episode{PodcastID: 2, PodcastIndexID: 2, PodcastIndexFeedID: 200, GUID: "b", Title: "b", AudioURL: "b"}).Error)
+
+	short := seedClip(t, db, 1, "music", confidence(0.9), false)
+	long := seedClip(t, db, 1, "music", nil, false)
+	require.NoError(t, db.Model(long).Update("original_end_time", 60).Error)
+	other := seedClip(t, db, 2, "music", confidence(0.4), false)
+
+	list := func(filters ListClipsFilters) []string {
+		clips, err := service.ListClips(ctx, filters)
+		require.NoError(t, err)
+		return queueUUIDs(clips)
+	}
+
+	assert.Equal(t, []string{other.UUID, long.UUID, short.UUID}, list(ListClipsFilters{}))
+	assert.Equal(t, []string{long.UUID, other.UUID, short.UUID}, list(ListClipsFilters{Sort: SortByDuration}), "ties stay newest first")
+	assert.Equal(t, []string{short.UUID, other.UUID, long.UUID}, list(ListClipsFilters{Sort: SortByConfidence}))
+	assert.Equal(t, []string{other.UUID, short.UUID, long.UUID}, list(ListClipsFilters{Sort: SortByConfidence, Ascending: true}))
+
+	podcast := int64(100)
+	assert.Equal(t, []string{short.UUID}, list(ListClipsFilters{PodcastID: &podcast, Sort: SortByCreatedAt, Ascending: true, Limit: 1}))
+
+	// The total ignores paging
+	total, err := service.CountClips(ctx, ListClipsFilters{PodcastID: &podcast, Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestRejectClip(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	clip := seedClip(t, db, 1, "advertisement", confidence(0.6), false)
+	before := clip.UpdatedAt
+	time.Sleep(5 * time.Millisecond)
+
+	rejected, err := service.RejectClip(ctx, clip.UUID, "")
+	require.NoError(t, err)
+	assert.True(t, rejected.Rejected)
+	assert.False(t, rejected.Approved)
+	assert.Equal(t, models.ClipRejectionFalsePositive, rejected.RejectionReason)
+	require.NotNil(t, rejected.RejectedAt)
+	assert.True(t, rejected.UpdatedAt.After(before))
+
+	// Rejecting again with another reason updates it
+	rejected, err = service.RejectClip(ctx, clip.UUID, models.ClipRejectionBadBoundaries)
+	require.NoError(t, err)
+	assert.Equal(t, models.ClipRejectionBadBoundaries, rejected.RejectionReason)
+
+	_, err = service.RejectClip(ctx, clip.UUID, "boring")
+	assert.ErrorIs(t, err, ErrInvalidRejectionReason)
+
+	// Rejected clips stay in the database but leave the queue
+	queue, total, err := service.ReviewQueue(ctx, ReviewQueueFilters{})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, queue)
+
+	stored, err := service.GetClip(ctx, clip.UUID)
+	require.NoError(t, err)
+	assert.True(t, stored.Rejected)
+
+	rejectedOnly := true
+	listed, err := service.ListClips(ctx, ListClipsFilters{Rejected: &rejectedOnly, Reason: models.ClipRejectionBadBoundaries})
+	require.NoError(t, err)
+	assert.Equal(t, []string{clip.UUID}, queueUUIDs(listed))
+
+	// Approving afterwards overrides the rejection
+	approved, err := service.ApproveClip(ctx, clip.UUID)
+	require.NoError(t, err)
+	assert.True(t, approved.Approved)
+	assert.False(t, approved.Rejected)
+	assert.Empty(t, approved.RejectionReason)
+	assert.Nil(t, approved.RejectedAt)
+
+	_, err = service.RejectClip(ctx, "missing", "")
+	assert.EqualError(t, err, "clip not found")
+}
+
+func TestExportDataset_HardNegatives(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	storage, err := NewLocalClipStorage(t.TempDir())
+	require.NoError(t, err)
+	service.storage = storage
+
+	// seedExtracted stores an already-extracted clip so export only copies files
+	seedExtracted := func(approved bool, reason string) *models.Clip {
+		clip := seedClip(t, db, 1, "advertisement", confidence(0.5), approved)
+		filename := "clip_" + clip.UUID + ".wav"
+		require.NoError(t, storage.SaveClip(ctx, clip.Label, filename, strings.NewReader("RIFF")))
+		updates := map[string]interface{}{"clip_filename": filename, "extracted": true, "status": models.ClipStatusReady}
+		if reason != "" {
+			updates["rejected"] = true
+			updates["rejection_reason"] = reason
+		}
+		require.NoError(t, db.Model(clip).Updates(updates).Error)
+		return clip
+	}
+
+	positive := seedExtracted(true, "")
+	negative := seedExtracted(false, models.ClipRejectionFalsePositive)
+	seedExtracted(false, models.ClipRejectionPoorAudio)
+
+	exportDir := t.TempDir()
+	skipped, err := service.ExportDataset(ctx, exportDir, ExportOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	manifest, err := os.ReadFile(filepath.Join(exportDir, "manifest.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(manifest), "\n"))
+	assert.NotContains(t, string(manifest), "hard_negative")
+
+	exportDir = t.TempDir()
+	skipped, err = service.ExportDataset(ctx, exportDir, ExportOptions{IncludeHardNegatives: true})
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.FileExists(t, filepath.Join(exportDir, "advertisement", "clip_"+positive.UUID+".wav"))
+	assert.FileExists(t, filepath.Join(exportDir, models.HardNegativesDir, "advertisement", "clip_"+negative.UUID+".wav"))
+
+	manifest, err = os.ReadFile(filepath.Join(exportDir, "manifest.jsonl"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(manifest)), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		var entry models.ClipExport
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry.UUID == negative.UUID {
+			assert.True(t, entry.HardNegative)
+			assert.Equal(t, models.ClipRejectionFalsePositive, entry.RejectionReason)
+			assert.Equal(t, "hard_negatives/advertisement/clip_"+negative.UUID+".wav", entry.FilePath)
+		} else {
+			assert.False(t, entry.HardNegative)
+		}
+	}
+}
+
+func TestAttachTranscriptText(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+	require.NoError(t, db.AutoMigrate(&models.Transcription{}))
+
+	transcript := &models.Transcription{PodcastIndexEpisodeID: 1, Text: "Hello there. Buy \"Acme\" now."}
+	require.NoError(t, transcript.SetSegments([]models.TranscriptSegment{
+		{Start: 0, End: 5, Text: " Hello there."},
+		{Start: 5, End: 10, Text: ` Buy "Acme" now.`},
+	}))
+	require.NoError(t, db.Create(transcript).Error)
+
+	computed := seedClip(t, db, 1, "advertisement", nil, true)
+	require.NoError(t, db.Model(computed).Updates(map[string]interface{}{"original_start_time": 5, "original_end_time": 10}).Error)
+	stored := seedClip(t, db, 1, "speech", nil, true)
+	chosen := "Hello there"
+	require.NoError(t, db.Model(stored).Update("transcript_text", chosen).Error)
+	untranscribed := seedClip(t, db, 2, "speech", nil, true)
+
+	var clips []*models.Clip
+	require.NoError(t, db.Order("id").Find(&clips).Error)
+	require.NoError(t, service.AttachTranscriptText(ctx, clips))
+	require.NotNil(t, clips[0].TranscriptText)
+	assert.Equal(t, `Buy "Acme" now.`, *clips[0].TranscriptText)
+	assert.Equal(t, chosen, *clips[1].TranscriptText, "stored text is kept")
+	assert.Nil(t, clips[2].TranscriptText)
+	assert.Equal(t, untranscribed.UUID, clips[2].UUID)
+
+	// The excerpt is not saved
+	var reloaded models.Clip
+	require.NoError(t, db.First(&reloaded, computed.ID).Error)
+	assert.Nil(t, reloaded.TranscriptText)
+
+	// Manifests carry the text, escaped
+	manifestPath := filepath.Join(t.TempDir(), "manifest.jsonl")
+	require.NoError(t, service.createManifestForClips(ctx, manifestPath, clips[:1], nil, nil))
+	manifest, err := os.ReadFile(manifestPath)
+	require.NoError(t, err)
+	var entry models.ClipExport
+	require.NoError(t, json.Unmarshal(manifest, &entry))
+	require.NotNil(t, entry.TranscriptText)
+	assert.Equal(t, `Buy "Acme" now.`, *entry.TranscriptText)
+}
+
+func TestAnnotationHistory(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+	service.storage, _ = NewLocalClipStorage(t.TempDir())
+
+	clip := seedClip(t, db, 7, "advertisement", confidence(0.6), false)
+	reviewer := WithActor(ctx, "user-1")
+
+	_, err := service.UpdateClipLabel(reviewer, clip.UUID, "music")
+	require.NoError(t, err)
+	_, err = service.RejectClip(reviewer, clip.UUID, models.ClipRejectionPoorAudio)
+	require.NoError(t, err)
+	_, err = service.ApproveClip(WithActor(ctx, "user-2"), clip.UUID)
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteClip(ctx, clip.UUID))
+
+	history, err := service.GetAnnotationHistory(ctx, clip.UUID)
+	require.NoError(t, err)
+	require.Len(t, history, 4, "history survives the clip being deleted")
+
+	actions := make([]string, len(history))
+	for i, audit := range history {
+		actions[i] = audit.Action
+		assert.Equal(t, int64(7), audit.PodcastIndexEpisodeID)
+	}
+	assert.Equal(t, []string{
+		models.AnnotationActionUpdate,
+		models.AnnotationActionReject,
+		models.AnnotationActionApprove,
+		models.AnnotationActionDelete,
+	}, actions)
+	assert.Equal(t, "user-1", history[0].UserID)
+	assert.Equal(t, "user-2", history[2].UserID)
+	assert.Empty(t, history[3].UserID, "changes without an actor are system changes")
+
+	var before, after models.AnnotationSnapshot
+	require.NoError(t, json.Unmarshal(history[0].Before, &before))
+	require.NoError(t, json.Unmarshal(history[0].After, &after))
+	assert.Equal(t, "advertisement", before.Label)
+	assert.Equal(t, "music", after.Label)
+
+	require.NoError(t, json.Unmarshal(history[1].After, &after))
+	assert.True(t, after.Rejected)
+	assert.Equal(t, models.ClipRejectionPoorAudio, after.RejectionReason)
+
+	assert.NotEmpty(t, history[3].Before)
+	assert.Empty(t, history[3].After)
+
+	// Idempotent calls record nothing
+	other := seedClip(t, db, 7, "music", nil, true)
+	_, err = service.ApproveClip(reviewer, other.UUID)
+	require.NoError(t, err)
+	history, err = service.GetAnnotationHistory(ctx, other.UUID)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestExportSourceURL(t *testing.T) {
+	episode := &models.Episode{AudioURL: "https://example.com/episode.mp3"}
+
+	remote := &models.Clip{SourceEpisodeURL: "https://cdn.example.com/original.mp3"}
+	assert.Equal(t, remote.SourceEpisodeURL, exportSourceURL(remote, episode))
+
+	cachedFile := filepath.Join(t.TempDir(), "episode.mp3")
+	require.NoError(t, os.WriteFile(cachedFile, []byte("audio"), 0o644))
+	cached := &models.Clip{SourceEpisodeURL: cachedFile}
+	assert.Equal(t, cachedFile, exportSourceURL(cached, episode))
+
+	evicted := &models.Clip{SourceEpisodeURL: filepath.Join(t.TempDir(), "gone.mp3")}
+	assert.Equal(t, episode.AudioURL, exportSourceURL(evicted, episode))
+	assert.Equal(t, evicted.SourceEpisodeURL, exportSourceURL(evicted, nil))
+
+	// A refused enclosure is replaced by the source that last served the audio
+	refused := &models.Episode{AudioURL: episode.AudioURL, AudioSourceURL: "https://mirror.example.com/episode.mp3"}
+	assert.Equal(t, refused.AudioSourceURL, exportSourceURL(evicted, refused))
+	assert.Equal(t, refused.AudioSourceURL, exportSourceURL(&models.Clip{SourceEpisodeURL: episode.AudioURL}, refused))
+}
+
+func TestExportDataset_AudioFolder(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	storage, err := NewLocalClipStorage(t.TempDir())
+	require.NoError(t, err)
+	service.storage = storage
+
+	clip := seedClip(t, db, 1, "advertisement", confidence(0.9), true)
+	filename := "clip_" + clip.UUID + ".wav"
+	require.NoError(t, storage.SaveClip(ctx, clip.Label, filename, strings.NewReader("RIFF")))
+	require.NoError(t, db.Model(clip).Updates(map[string]interface{}{
+		"clip_filename": filename, "extracted": true, "status": models.ClipStatusReady,
+	}).Error)
+
+	exportDir := t.TempDir()
+	skipped, err := service.ExportDataset(ctx, exportDir, ExportOptions{Format: models.DatasetFormatAudioFolder})
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+
+	assert.FileExists(t, filepath.Join(exportDir, "advertisement", filename))
+	assert.NoFileExists(t, filepath.Join(exportDir, models.DatasetManifestFile))
+
+	metadata, err := os.ReadFile(filepath.Join(exportDir, models.AudioFolderMetadataFile))
+	require.NoError(t, err)
+	var row map[string]interface{}
+	require.NoError(t, json.Unmarshal(metadata, &row))
+	assert.Equal(t, "advertisement/"+filename, row["file_name"])
+	assert.Equal(t, "advertisement", row["label"])
+	assert.Equal(t, false, row["hard_negative"])
+	assert.NotContains(t, row, "file_path")
+}
+
+func TestExportQuery_Filters(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+	require.NoError(t, db.AutoMigrate(&models.Episode{}))
+	require.NoError(t, db.Create(&models.Episode{PodcastID: 1, PodcastIndexID: 1, PodcastIndexFeedID: 100, GUID: "a", Title: "a", AudioURL: "a"}).Error)
+	require.NoError(t, db.Create(&models.Episode{PodcastID: 1, PodcastIndexID: 2, PodcastIndexFeedID: 200, GUID: "b", Title: "b", AudioURL: "b"}).Error)
+
+	ad := seedClip(t, db, 1, "advertisement", nil, true)
+	music := seedClip(t, db, 2, "music", nil, true)
+	long := seedClip(t, db, 1, "advertisement", nil, true)
+	require.NoError(t, db.Model(long).Update("original_end_time", 90).Error)
+	old := seedClip(t, db, 2, "advertisement", nil, true)
+	require.NoError(t, db.Model(old).Update("created_at", time.Now().AddDate(-1, 0, 0)).Error)
+	negative := seedClip(t, db, 1, "advertisement", nil, false)
+	require.NoError(t, db.Model(negative).Updates(map[string]interface{}{"rejected": true, "rejection_reason": models.ClipRejectionFalsePositive}).Error)
+	seedClip(t, db, 1, "advertisement", nil, false)
+
+	cutoff := time.Now().AddDate(0, -1, 0)
+	tests := []struct {
+		name string
+		opts ExportOptions
+		want []*models.Clip
+	}{
+		{name: "all approved", want: []*models.Clip{ad, music, long, old}},
+		{name: "with hard negatives", opts: ExportOptions{IncludeHardNegatives: true}, want: []*models.Clip{ad, music, long, old, negative}},
+		{name: "labels", opts: ExportOptions{Filters: ExportFilters{Labels: []string{"music"}}}, want: []*models.Clip{music}},
+		{name: "duration range", opts: ExportOptions{Filters: ExportFilters{MinDuration: 30, MaxDuration: 120}}, want: []*models.Clip{long}},
+		{name: "created after", opts: ExportOptions{Filters: ExportFilters{CreatedAfter: &cutoff, Labels: []string{"advertisement"}}}, want: []*models.Clip{ad, long}},
+		{name: "created before", opts: ExportOptions{Filters: ExportFilters{CreatedBefore: &cutoff}}, want: []*models.Clip{old}},
+		{name: "podcast", opts: ExportOptions{IncludeHardNegatives: true, Filters: ExportFilters{PodcastIDs: []int64{100}}}, want: []*models.Clip{ad, long, negative}},
+		{name: "episode", opts: ExportOptions{Filters: ExportFilters{EpisodeIDs: []int64{2}}}, want: []*models.Clip{music, old}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []*models.Clip
+			require.NoError(t, service.exportQuery(ctx, tt.opts).Order("clips.id").Find(&got).Error)
+			assert.Equal(t, queueUUIDs(tt.want), queueUUIDs(got))
+		})
+	}
+}
+
+func TestApproveClip_LabelQuota(t *testing.T) {
+	service, db := setupTestService(t)
+	WithLabelQuotas(map[string]int{"advertisement": 2, "music": 0})(service)
+	ctx := context.Background()
+
+	seedClip(t, db, 1, "advertisement", nil, true)
+	second := seedClip(t, db, 1, "advertisement", nil, false)
+	third := seedClip(t, db, 2, "advertisement", nil, false)
+	song := seedClip(t, db, 2, "music", nil, false)
+
+	_, err := service.ApproveClip(ctx, second.UUID)
+	require.NoError(t, err)
+
+	_, err = service.ApproveClip(ctx, third.UUID)
+	assert.ErrorIs(t, err, ErrLabelQuotaExceeded)
+	var reloaded models.Clip
+	require.NoError(t, db.Where("uuid = ?", third.UUID).First(&reloaded).Error)
+	assert.False(t, reloaded.Approved)
+
+	// A cap of 0 means unlimited
+	_, err = service.ApproveClip(ctx, song.UUID)
+	require.NoError(t, err)
+
+	// Relabeling an approved clip into a full label is refused too
+	_, err = service.UpdateClipLabel(ctx, song.UUID, "advertisement")
+	assert.ErrorIs(t, err, ErrLabelQuotaExceeded)
+	var relabeled models.Clip
+	require.NoError(t, db.Where("uuid = ?", song.UUID).First(&relabeled).Error)
+	assert.Equal(t, "music", relabeled.Label)
+
+	// Unapproved clips move freely
+	_, err = service.UpdateClipLabel(ctx, third.UUID, "music")
+	require.NoError(t, err)
+}
+
+func TestAutoApproveClip(t *testing.T) {
+	service, db := setupTestService(t)
+	WithLabelQuotas(map[string]int{"advertisement": 2})(service)
+	ctx := context.Background()
+
+	clip := seedClip(t, db, 1, "advertisement", confidence(0.98), false)
+	approved, err := service.AutoApproveClip(ctx, clip.UUID, 0.97)
+	require.NoError(t, err)
+	assert.True(t, approved.Approved)
+	assert.True(t, approved.AutoApproved)
+	require.NotNil(t, approved.ApprovalThreshold)
+	assert.Equal(t, 0.97, *approved.ApprovalThreshold)
+
+	// A reviewer confirming it clears the flag without counting it twice
+	seedClip(t, db, 1, "advertisement", nil, true)
+	confirmed, err := service.ApproveClip(WithActor(ctx, "user-1"), clip.UUID)
+	require.NoError(t, err)
+	assert.True(t, confirmed.Approved)
+	assert.False(t, confirmed.AutoApproved)
+	assert.Nil(t, confirmed.ApprovalThreshold)
+
+	history, err := service.GetAnnotationHistory(ctx, clip.UUID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, models.AnnotationActionAutoApprove, history[0].Action)
+	assert.Empty(t, history[0].UserID)
+	var after models.AnnotationSnapshot
+	require.NoError(t, json.Unmarshal(history[0].After, &after))
+	assert.True(t, after.AutoApproved)
+	assert.Equal(t, models.AnnotationActionApprove, history[1].Action)
+	assert.Equal(t, "user-1", history[1].UserID)
+
+	// Full labels leave the clip for review, and reviewers' decisions stand
+	full := seedClip(t, db, 2, "advertisement", confidence(0.99), false)
+	_, err = service.AutoApproveClip(ctx, full.UUID, 0.97)
+	assert.ErrorIs(t, err, ErrLabelQuotaExceeded)
+
+	rejected := seedClip(t, db, 2, "music", confidence(0.99), false)
+	_, err = service.RejectClip(ctx, rejected.UUID, models.ClipRejectionDuplicate)
+	require.NoError(t, err)
+	unchanged, err := service.AutoApproveClip(ctx, rejected.UUID, 0.9)
+	require.NoError(t, err)
+	assert.False(t, unchanged.Approved)
+	assert.True(t, unchanged.Rejected)
+}
+
+func TestLabelStats(t *testing.T) {
+	service, db := setupTestService(t)
+	WithLabelQuotas(map[string]int{"advertisement": 5, "jingle": 3})(service)
+	ctx := context.Background()
+
+	seedClip(t, db, 1, "advertisement", nil, true)
+	extracted := seedClip(t, db, 1, "advertisement", nil, true)
+	size := int64(320000)
+	require.NoError(t, db.Model(extracted).Updates(map[string]interface{}{"extracted": true, "clip_size_bytes": size}).Error)
+	rejected := seedClip(t, db, 2, "advertisement", confidence(0.3), false)
+	require.NoError(t, db.Model(rejected).Updates(map[string]interface{}{"rejected": true}).Error)
+	seedClip(t, db, 2, "music", confidence(0.8), false)
+
+	stats, err := service.LabelStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+
+	ads := stats[0]
+	assert.Equal(t, "advertisement", ads.Label)
+	assert.Equal(t, int64(3), ads.Clips)
+	assert.Equal(t, int64(2), ads.Approved)
+	assert.Equal(t, int64(1), ads.Rejected)
+	assert.Equal(t, int64(0), ads.Pending)
+	assert.Equal(t, int64(1), ads.Extracted)
+	assert.InDelta(t, 30.0, ads.DurationSeconds, 0.001)
+	assert.InDelta(t, 20.0, ads.ApprovedDuration, 0.001)
+	assert.Equal(t, size, ads.Bytes)
+	assert.Equal(t, 5, ads.Quota)
+	require.NotNil(t, ads.Remaining)
+	assert.Equal(t, 3, *ads.Remaining)
+
+	jingle := stats[1]
+	assert.Equal(t, "jingle", jingle.Label)
+	assert.Zero(t, jingle.Clips)
+	require.NotNil(t, jingle.Remaining)
+	assert.Equal(t, 3, *jingle.Remaining)
+
+	music := stats[2]
+	assert.Equal(t, "music", music.Label)
+	assert.Equal(t, int64(1), music.Pending)
+	assert.Zero(t, music.Quota)
+	assert.Nil(t, music.Remaining)
+}
+
+func TestPlanExport_ChecksAudioWithoutWriting(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	storageDir := t.TempDir()
+	storage, err := NewLocalClipStorage(storageDir)
+	require.NoError(t, err)
+	service.storage = storage
+
+	withFile := func(clip *models.Clip, updates map[string]interface{}) {
+		updates["clip_filename"] = "clip_" + clip.UUID + ".wav"
+		require.NoError(t, db.Model(clip).Updates(updates).Error)
+	}
+
+	stored := seedClip(t, db, 1, "advertisement", nil, true)
+	withFile(stored, map[string]interface{}{"extracted": true, "clip_duration": 9.5, "clip_size_bytes": 304044})
+	require.NoError(t, storage.SaveClip(ctx, "advertisement", "clip_"+stored.UUID+".wav", strings.NewReader("RIFF")))
+
+	evicted := seedClip(t, db, 1, "advertisement", nil, true)
+	withFile(evicted, map[string]interface{}{"extracted": true})
+
+	remote := seedClip(t, db, 2, "music", nil, true)
+	withFile(remote, map[string]interface{}{})
+
+	gone := seedClip(t, db, 3, "music", nil, true)
+	withFile(gone, map[string]interface{}{"source_episode_url": filepath.Join(t.TempDir(), "gone.mp3")})
+
+	unnamed := seedClip(t, db, 3, "music", nil, true)
+	seedClip(t, db, 3, "music", nil, false) // Not approved, not planned
+
+	planned, err := service.PlanExport(ctx, ExportOptions{})
+	require.NoError(t, err)
+	require.Len(t, planned, 5)
+
+	byUUID := make(map[string]PlannedClip, len(planned))
+	for _, p := range planned {
+		byUUID[p.UUID] = p
+	}
+	assert.Equal(t, PlannedClip{UUID: stored.UUID, Label: "advertisement", EpisodeID: 1, Duration: 9.5, SizeBytes: 304044, Extracted: true}, byUUID[stored.UUID])
+	assert.Equal(t, MissingClipFile, byUUID[evicted.UUID].Missing)
+	assert.Empty(t, byUUID[remote.UUID].Missing)
+	assert.Equal(t, 10.0, byUUID[remote.UUID].Duration)
+	assert.Equal(t, MissingSource, byUUID[gone.UUID].Missing)
+	assert.Equal(t, MissingNoFilename, byUUID[unnamed.UUID].Missing)
+
+	// Nothing was extracted or marked failed
+	var failed int64
+	require.NoError(t, db.Model(&models.Clip{}).Where("status = ?", "failed").Count(&failed).Error)
+	assert.Zero(t, failed)
+}
+
+type fakeAudioCache map[int64]string
+
+func (f fakeAudioCache) GetCachedAudio(ctx context.Context, episodeID int64) (*models.AudioCache, error) {
+	path, ok := f[episodeID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.AudioCache{PodcastIndexEpisodeID: episodeID, OriginalPath: path}, nil
+}
+
+func TestExportDataset_ReportsSkippedClips(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	storage, err := NewLocalClipStorage(t.TempDir())
+	require.NoError(t, err)
+	service.storage = storage
+
+	evicted := seedClip(t, db, 1, "advertisement", nil, true)
+	require.NoError(t, db.Model(evicted).Updates(map[string]interface{}{
+		"clip_filename": "clip_" + evicted.UUID + ".wav", "extracted": true,
+	}).Error)
+
+	uncached := seedClip(t, db, 2, "music", nil, true)
+	require.NoError(t, db.Model(uncached).Updates(map[string]interface{}{
+		"clip_filename":      "clip_" + uncached.UUID + ".wav",
+		"source_episode_url": filepath.Join(t.TempDir(), "evicted.mp3"),
+	}).Error)
+
+	skipped, err := service.ExportDataset(ctx, t.TempDir(), ExportOptions{})
+	require.NoError(t, err)
+	require.Len(t, skipped, 2)
+	assert.Equal(t, SkippedClip{UUID: evicted.UUID, Label: "advertisement", EpisodeID: 1, Extracted: true, Reason: MissingClipFile, Error: skipped[0].Error}, skipped[0])
+	assert.NotEmpty(t, skipped[0].Error)
+	assert.Equal(t, SkippedClip{UUID: uncached.UUID, Label: "music", EpisodeID: 2, Reason: MissingSource}, skipped[1])
+
+	// Audio cached since the clip was created is used as its source
+	cached := filepath.Join(t.TempDir(), "cached.mp3")
+	require.NoError(t, os.WriteFile(cached, []byte("ID3"), 0o644))
+	service.audioCacheService = fakeAudioCache{2: cached}
+	assert.Equal(t, cached, service.exportSource(ctx, uncached, nil))
+}
+
+func TestExportDataset_EnforcesRights(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	storage, err := NewLocalClipStorage(t.TempDir())
+	require.NoError(t, err)
+	service.storage = storage
+
+	require.NoError(t, db.Create(&models.Episode{PodcastID: 1, PodcastIndexID: 1, PodcastIndexFeedID: 100, GUID: "a", Title: "a", AudioURL: "a"}).Error)
+	require.NoError(t, db.Create(&models.Episode{PodcastID: 2, PodcastIndexID: 2, PodcastIndexFeedID: 200, GUID: "b", Title: "b", AudioURL: "b"}).Error)
+	require.NoError(t, db.Create(&models.PodcastRights{PodcastIndexFeedID: 100, License: "CC-BY-4.0", AllowTraining: true, AllowDerivatives: true}).Error)
+	require.NoError(t, db.Create(&models.PodcastRights{PodcastIndexFeedID: 200, License: "CC-BY-ND-4.0", AllowTraining: true}).Error)
+
+	extracted := func(episodeID int64) *models.Clip {
+		clip := seedClip(t, db, episodeID, "advertisement", nil, true)
+		filename := "clip_" + clip.UUID + ".wav"
+		require.NoError(t, storage.SaveClip(ctx, clip.Label, filename, strings.NewReader("RIFF")))
+		require.NoError(t, db.Model(clip).Updates(map[string]interface{}{"clip_filename": filename, "extracted": true}).Error)
+		return clip
+	}
+	licensed := extracted(1)
+	noDerivatives := extracted(2)
+	unknown := extracted(3) // Episode isn't stored, so no rights are known
+
+	planned, err := service.PlanExport(ctx, ExportOptions{})
+	require.NoError(t, err)
+	require.Len(t, planned, 3)
+	assert.Empty(t, planned[0].Missing)
+	assert.Equal(t, RightsRestricted, planned[1].Missing)
+	assert.Empty(t, planned[2].Missing)
+
+	exportDir := t.TempDir()
+	skipped, err := service.ExportDataset(ctx, exportDir, ExportOptions{})
+	require.NoError(t, err)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, noDerivatives.UUID, skipped[0].UUID)
+	assert.Equal(t, RightsRestricted, skipped[0].Reason)
+	assert.Equal(t, "podcast is marked no-derivatives", skipped[0].Error)
+
+	manifest, err := os.ReadFile(filepath.Join(exportDir, models.DatasetManifestFile))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(manifest)), "\n")
+	require.Len(t, lines, 2)
+	var row map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	assert.Equal(t, licensed.UUID, row["uuid"])
+	assert.Equal(t, "CC-BY-4.0", row["license"])
+	assert.Equal(t, []interface{}{models.RightsUseTraining, models.RightsUseDerivatives}, row["allowed_uses"])
+	row = nil
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &row))
+	assert.Equal(t, unknown.UUID, row["uuid"])
+	assert.NotContains(t, row, "license")
+
+	// Audiofolder rows keep one schema, with nulls where rights are unknown
+	exportDir = t.TempDir()
+	_, err = service.ExportDataset(ctx, exportDir, ExportOptions{Format: models.DatasetFormatAudioFolder})
+	require.NoError(t, err)
+	metadata, err := os.ReadFile(filepath.Join(exportDir, models.AudioFolderMetadataFile))
+	require.NoError(t, err)
+	lines = strings.Split(strings.TrimSpace(string(metadata)), "\n")
+	require.Len(t, lines, 2)
+	row = nil
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &row))
+	assert.Contains(t, row, "license")
+	assert.Nil(t, row["license"])
+	assert.Nil(t, row["allowed_uses"])
+}
+
+// fakeExtractor writes a fixed-size file and reports a fixed duration
+type fakeExtractor struct {
+	duration float64
+	calls    int
+}
+
+func (f *fakeExtractor) ExtractClip(ctx context.Context, params ExtractParams) (*ExtractResult, error) {
+	f.calls++
+	if err := os.WriteFile(params.OutputPath, []byte("RIFF-new"), 0o644); err != nil {
+		return nil, err
+	}
+	return &ExtractResult{FilePath: params.OutputPath, Duration: f.duration, SizeBytes: 8}, nil
+}
+
+func TestReextractClips(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	storage, err := NewLocalClipStorage(t.TempDir())
+	require.NoError(t, err)
+	extractor := &fakeExtractor{duration: 15}
+	service.storage = storage
+	service.extractor = extractor
+
+	source := filepath.Join(t.TempDir(), "episode.mp3")
+	require.NoError(t, os.WriteFile(source, []byte("ID3"), 0o644))
+
+	seedExtracted := func(label, sourceURL string) *models.Clip {
+		clip := seedClip(t, db, 1, label, nil, true)
+		filename := "clip_" + clip.UUID + ".wav"
+		require.NoError(t, storage.SaveClip(ctx, label, filename, strings.NewReader("RIFF-old")))
+		require.NoError(t, db.Model(clip).Updates(map[string]interface{}{
+			"clip_filename": filename, "extracted": true, "status": models.ClipStatusReady,
+			"clip_duration": 10.0, "clip_size_bytes": 8, "source_episode_url": sourceURL,
+		}).Error)
+		return clip
+	}
+
+	stale := seedExtracted("advertisement", source)
+	orphaned := seedExtracted("advertisement", filepath.Join(t.TempDir(), "evicted.mp3"))
+	other := seedExtracted("music", source)
+
+	var progress []int
+	result, err := service.ReextractClips(ctx, ExportFilters{Labels: []string{"advertisement"}}, func(done, total int) {
+		assert.Equal(t, 2, total)
+		progress = append(progress, done)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &ReextractResult{Matched: 2, Reextracted: 1, Failed: 1}, result)
+	assert.Equal(t, []int{1, 2}, progress)
+	assert.Equal(t, 1, extractor.calls)
+
+	reloaded := func(clip *models.Clip) models.Clip {
+		var fresh models.Clip
+		require.NoError(t, db.First(&fresh, clip.ID).Error)
+		return fresh
+	}
+
+	fresh := reloaded(stale)
+	assert.True(t, fresh.Extracted)
+	assert.Equal(t, models.ClipStatusReady, fresh.Status)
+	require.NotNil(t, fresh.ClipDuration)
+	assert.Equal(t, 15.0, *fresh.ClipDuration)
+	data, err := os.ReadFile(storage.GetClipPath("advertisement", *fresh.ClipFilename))
+	require.NoError(t, err)
+	assert.Equal(t, "RIFF-new", string(data))
+
+	// The stale file is gone even though it couldn't be replaced
+	fresh = reloaded(orphaned)
+	assert.False(t, fresh.Extracted)
+	assert.Equal(t, models.ClipStatusFailed, fresh.Status)
+	assert.Nil(t, fresh.ClipDuration)
+	assert.NoFileExists(t, storage.GetClipPath("advertisement", *fresh.ClipFilename))
+
+	// Clips outside the filters are untouched
+	fresh = reloaded(other)
+	assert.True(t, fresh.Extracted)
+	assert.Equal(t, 10.0, *fresh.ClipDuration)
+}
+
+func TestVerifyClips(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	storage, err := NewLocalClipStorage(t.TempDir())
+	require.NoError(t, err)
+	extractor := &fakeExtractor{duration: 10}
+	service.storage = storage
+	service.extractor = extractor
+
+	source := filepath.Join(t.TempDir(), "episode.mp3")
+	require.NoError(t, os.WriteFile(source, []byte("ID3"), 0o644))
+
+	seedExtracted := func(contents string, hash *string) *models.Clip {
+		clip := seedClip(t, db, 1, "music", nil, true)
+		filename := "clip_" + clip.UUID + ".wav"
+		if contents != "" {
+			require.NoError(t, storage.SaveClip(ctx, "music", filename, strings.NewReader(contents)))
+		}
+		require.NoError(t, db.Model(clip).Updates(map[string]interface{}{
+			"clip_filename": filename, "extracted": true, "status": models.ClipStatusReady,
+			"clip_size_bytes": 8, "clip_sha256": hash, "source_episode_url": source,
+		}).Error)
+		require.NoError(t, db.First(clip, clip.ID).Error)
+		return clip
+	}
+
+	unhashed := seedExtracted("RIFF-old", nil)
+	missing := seedExtracted("", nil)
+	truncated := seedExtracted("RIFF", nil)
+	wrongHash := strings.Repeat("0", 64)
+	changed := seedExtracted("RIFF-xyz", &wrongHash)
+
+	reloaded := func(clip *models.Clip) models.Clip {
+		var fresh models.Clip
+		require.NoError(t, db.First(&fresh, clip.ID).Error)
+		return fresh
+	}
+
+	// Reporting leaves every clip as it was, apart from recording missing hashes
+	result, err := service.VerifyClips(ctx, VerifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Checked)
+	assert.Equal(t, 1, result.OK)
+	assert.Equal(t, 1, result.HashRecorded)
+	assert.Equal(t, 3, result.Broken)
+	assert.Zero(t, result.Repaired)
+	problems := map[string]string{}
+	for _, issue := range result.Issues {
+		problems[issue.UUID] = issue.Problem
+		assert.False(t, issue.Repaired)
+	}
+	assert.Equal(t, map[string]string{
+		missing.UUID:   ClipFileMissing,
+		truncated.UUID: ClipFileSizeMismatch,
+		changed.UUID:   ClipFileHashMismatch,
+	}, problems)
+
+	fresh := reloaded(unhashed)
+	require.NotNil(t, fresh.ClipSHA256)
+	assert.Len(t, *fresh.ClipSHA256, 64)
+	fresh = reloaded(truncated)
+	assert.Equal(t, models.ClipStatusReady, fresh.Status)
+
+	// Invalidating marks the broken clips failed and drops their files
+	result, err = service.VerifyClips(ctx, VerifyOptions{Repair: RepairInvalidate})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Repaired)
+	fresh = reloaded(truncated)
+	assert.Equal(t, models.ClipStatusFailed, fresh.Status)
+	assert.False(t, fresh.Extracted)
+	assert.Equal(t, "integrity check: size_mismatch", fresh.ErrorMessage)
+	assert.NoFileExists(t, storage.GetClipPath("music", *fresh.ClipFilename))
+
+	// Re-extracting replaces the file and records the new hash
+	require.NoError(t, os.Remove(storage.GetClipPath("music", *unhashed.ClipFilename)))
+	result, err = service.VerifyClips(ctx, VerifyOptions{Repair: RepairReextract})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Checked)
+	assert.Equal(t, 1, result.Repaired)
+	assert.Equal(t, 1, extractor.calls)
+	fresh = reloaded(unhashed)
+	assert.Equal(t, models.ClipStatusReady, fresh.Status)
+	require.NotNil(t, fresh.ClipSHA256)
+
+	result, err = service.VerifyClips(ctx, VerifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.OK)
+
+	_, err = service.VerifyClips(ctx, VerifyOptions{Repair: "delete"})
+	assert.Error(t, err)
+}
+
+func TestCheckTimeRange(t *testing.T) {
+	seconds := func(v int) *int { return &v }
+	probed := &models.Episode{Duration: seconds(600), DurationProbed: true}
+	feed := &models.Episode{Duration: seconds(600)}
+
+	tests := []struct {
+		name       string
+		start, end float64
+		episode    *models.Episode
+		want       TimeRange
+		wantErr    error
+	}{
+		{name: "inside", start: 10, end: 20, episode: probed, want: TimeRange{Start: 10, End: 20}},
+		{name: "unknown duration", start: 900, end: 910, episode: &models.Episode{}, want: TimeRange{Start: 900, End: 910}},
+		{name: "clamped end", start: 590, end: 620, episode: probed, want: TimeRange{Start: 590, End: 600, Clamped: true}},
+		{name: "feed slack", start: 590, end: 620, episode: feed, want: TimeRange{Start: 590, End: 620}},
+		{name: "past feed slack", start: 590, end: 700, episode: feed, want: TimeRange{Start: 590, End: 630, Clamped: true}},
+		{name: "starts at end", start: 600, end: 610, episode: probed, wantErr: ErrRangeOutOfBounds},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckTimeRange(tt.start, tt.end, tt.episode)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want.Start, got.Start, 0.001)
+			assert.InDelta(t, tt.want.End, got.End, 0.001)
+			assert.Equal(t, tt.want.Clamped, got.Clamped)
+		})
+	}
+
+	_, err := CheckTimeRange(20, 10, probed)
+	assert.Error(t, err, "end before start")
+}
+
+type fakeEpisodeLookup struct{ episode *models.Episode }
+
+func (f fakeEpisodeLookup) GetEpisodeByPodcastIndexID(ctx context.Context, podcastIndexID int64) (*models.Episode, error) {
+	return f.episode, nil
+}
+
+func (f fakeEpisodeLookup) GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) (map[int64]*models.Episode, error) {
+	return map[int64]*models.Episode{f.episode.PodcastIndexID: f.episode}, nil
+}
+
+func TestCreateClip_ChecksEpisodeDuration(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+	duration := 300
+	service.episodeService = fakeEpisodeLookup{&models.Episode{
+		PodcastIndexID: 9, AudioURL: "https://example.com/9.mp3", Duration: &duration, DurationProbed: true,
+	}}
+
+	clip, err := service.CreateClip(ctx, CreateClipParams{PodcastIndexEpisodeID: 9, OriginalStartTime: 290, OriginalEndTime: 320, Label: "outro"})
+	require.NoError(t, err)
+	assert.True(t, clip.RangeClamped)
+	assert.Equal(t, 300.0, clip.OriginalEndTime)
+
+	stored, err := service.GetClip(ctx, clip.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, 300.0, stored.OriginalEndTime)
+	assert.False(t, stored.RangeClamped, "the flag describes the create call only")
+
+	_, err = service.CreateClip(ctx, CreateClipParams{PodcastIndexEpisodeID: 9, OriginalStartTime: 310, OriginalEndTime: 320, Label: "outro"})
+	assert.ErrorIs(t, err, ErrRangeOutOfBounds)
+}
+
+func TestCreateClip_RecordsModel(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+	service.episodeService = fakeEpisodeLookup{&models.Episode{PodcastIndexID: 9, AudioURL: "https://example.com/9.mp3"}}
+
+	score := 0.91
+	detected, err := service.CreateClip(ctx, CreateClipParams{
+		PodcastIndexEpisodeID: 9, OriginalStartTime: 10, OriginalEndTime: 40, Label: "advertisement",
+		ModelName: "ad-detector", ModelVersion: "2", Confidence: &score,
+	})
+	require.NoError(t, err)
+	_, err = service.CreateClip(ctx, CreateClipParams{PodcastIndexEpisodeID: 9, OriginalStartTime: 50, OriginalEndTime: 60, Label: "advertisement"})
+	require.NoError(t, err)
+
+	stored, err := service.GetClip(ctx, detected.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "ad-detector", stored.ModelName)
+	assert.Equal(t, "2", stored.ModelVersion)
+	assert.Equal(t, LabelMethodModel, stored.LabelMethod)
+	require.NotNil(t, stored.LabelConfidence)
+	assert.InDelta(t, 0.91, *stored.LabelConfidence, 1e-9)
+
+	byModel, err := service.ListClips(ctx, ListClipsFilters{Model: "ad-detector"})
+	require.NoError(t, err)
+	require.Len(t, byModel, 1)
+	assert.Equal(t, detected.UUID, byModel[0].UUID)
+}
+
+func TestDeleteClips(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := WithActor(context.Background(), "reviewer-1")
+
+	storage, err := NewLocalClipStorage(t.TempDir())
+	require.NoError(t, err)
+	service.storage = storage
+
+	badSession := seedClip(t, db, 1, "advertisement", nil, false)
+	extracted := seedClip(t, db, 1, "advertisement", nil, true)
+	filename := "clip_" + extracted.UUID + ".wav"
+	require.NoError(t, storage.SaveClip(ctx, extracted.Label, filename, strings.NewReader("RIFF")))
+	require.NoError(t, db.Model(extracted).Updates(map[string]interface{}{
+		"clip_filename": filename, "extracted": true, "status": models.ClipStatusReady,
+	}).Error)
+	otherLabel := seedClip(t, db, 1, "music", nil, false)
+	otherEpisode := seedClip(t, db, 2, "advertisement", nil, false)
+	otherUser := seedClip(t, db, 1, "advertisement", nil, false)
+	require.NoError(t, db.Model(&models.Clip{}).Where("uuid IN ?", []string{badSession.UUID, extracted.UUID}).
+		Update("created_by", "labeler-1").Error)
+	require.NoError(t, db.Model(otherUser).Update("created_by", "labeler-2").Error)
+
+	episodeID := int64(1)
+	filters := ListClipsFilters{EpisodeID: &episodeID, Label: "advertisement", CreatedBy: "labeler-1", Limit: 1}
+
+	deleted, err := service.DeleteClips(ctx, filters)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{badSession.UUID, extracted.UUID}, deleted, "limit is ignored")
+
+	remaining, err := service.ListClips(ctx, ListClipsFilters{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{otherLabel.UUID, otherEpisode.UUID, otherUser.UUID}, queueUUIDs(remaining))
+
+	_, err = os.Stat(filepath.Join(storage.basePath, extracted.Label, filename))
+	assert.True(t, os.IsNotExist(err), "stored file is removed")
+
+	history, err := service.GetAnnotationHistory(ctx, badSession.UUID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, models.AnnotationActionBulkDelete, history[0].Action)
+	assert.Equal(t, "reviewer-1", history[0].UserID)
+	assert.NotEmpty(t, history[0].Before)
+
+	future := time.Now().Add(time.Hour)
+	deleted, err = service.DeleteClips(ctx, ListClipsFilters{EpisodeID: &episodeID, CreatedAfter: &future})
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+}
+
+// fakeStatsRefresher records the episodes whose stats were refreshed
+type fakeStatsRefresher struct {
+	episodes []int64
+}
+
+func (f *fakeStatsRefresher) RefreshEpisode(ctx context.Context, podcastIndexEpisodeID int64) error {
+	f.episodes = append(f.episodes, podcastIndexEpisodeID)
+	return nil
+}
+
+func TestClipChangesRefreshStats(t *testing.T) {
+	service, db := setupTestService(t)
+	refresher := &fakeStatsRefresher{}
+	WithStatsRefresher(refresher)(service)
+	ctx := context.Background()
+
+	clip := seedClip(t, db, 1, "advertisement", confidence(0.9), false)
+	_, err := service.UpdateClipLabel(ctx, clip.UUID, "music")
+	require.NoError(t, err)
+	_, err = service.AutoApproveClip(ctx, clip.UUID, 0.8)
+	require.NoError(t, err)
+	_, err = service.ApproveClip(ctx, clip.UUID)
+	require.NoError(t, err)
+	_, err = service.RejectClip(ctx, clip.UUID, "")
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteClip(ctx, clip.UUID))
+	assert.Equal(t, []int64{1, 1, 1, 1, 1}, refresher.episodes)
+
+	refresher.episodes = nil
+	seedClip(t, db, 2, "advertisement", nil, false)
+	seedClip(t, db, 2, "advertisement", nil, false)
+	seedClip(t, db, 3, "advertisement", nil, false)
+	_, err = service.DeleteClips(ctx, ListClipsFilters{Label: "advertisement"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, refresher.episodes, "once per episode")
+}
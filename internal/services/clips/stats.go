@@ -0,0 +1,39 @@
+package clips
+
+import (
+	"context"
+	"log"
+)
+
+// StatsRefresher recomputes derived per-episode stats after its clips change.
+// The analytics service satisfies it.
+type StatsRefresher interface {
+	RefreshEpisode(ctx context.Context, podcastIndexEpisodeID int64) error
+}
+
+// WithStatsRefresher refreshes an episode's stats whenever one of its clips is
+// created, relabelled, approved, rejected or deleted, so podcast analytics
+// don't wait for the next analysis job to pick the change up
+func WithStatsRefresher(refresher StatsRefresher) Option {
+	return func(s *ServiceImpl) {
+		s.statsRefresher = refresher
+	}
+}
+
+// refreshStats refreshes the stats of each episode once. Stats are derived
+// data, so a failure is logged rather than failing the clip change.
+func (s *ServiceImpl) refreshStats(ctx context.Context, episodeIDs ...int64) {
+	if s.statsRefresher == nil {
+		return
+	}
+	seen := make(map[int64]bool, len(episodeIDs))
+	for _, id := range episodeIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if err := s.statsRefresher.RefreshEpisode(context.WithoutCancel(ctx), id); err != nil {
+			log.Printf("[WARN] Failed to refresh stats of episode %d: %v", id, err)
+		}
+	}
+}
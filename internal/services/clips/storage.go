@@ -89,7 +89,7 @@ func (s *LocalClipStorage) GetClip(ctx context.Context, label, filename string)
 	file, err := os.Open(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("clip not found: %s/%s", label, filename)
+			return nil, fmt.Errorf("clip not found: %s/%s: %w", label, filename, os.ErrNotExist)
 		}
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
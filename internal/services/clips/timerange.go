@@ -0,0 +1,60 @@
+package clips
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// feedDurationSlack is how far past a feed-reported duration a range may run
+// before it is clamped. Feeds round and are sometimes simply wrong; durations
+// measured with ffprobe are trusted exactly.
+const feedDurationSlack = 0.05
+
+// ErrRangeOutOfBounds is returned when a time range starts at or after the end
+// of its episode
+var ErrRangeOutOfBounds = errors.New("time range is outside the episode")
+
+// TimeRange is a time range in seconds checked against an episode's duration
+type TimeRange struct {
+	Start   float64
+	End     float64
+	Clamped bool // End was past the episode's end and has been moved to it
+}
+
+// CheckTimeRange validates [start, end) against the episode's known duration.
+// A range ending past the episode is clamped to its end; one starting there
+// is rejected with ErrRangeOutOfBounds. Episodes without a duration only get
+// the basic checks.
+func CheckTimeRange(start, end float64, episode *models.Episode) (TimeRange, error) {
+	if start < 0 || end <= start {
+		return TimeRange{}, fmt.Errorf("invalid time range: start=%f, end=%f", start, end)
+	}
+	checked := TimeRange{Start: start, End: end}
+
+	limit, ok := durationLimit(episode)
+	if !ok {
+		return checked, nil
+	}
+	if start >= limit {
+		return TimeRange{}, fmt.Errorf("%w: starts at %.2fs but the episode is %.2fs long", ErrRangeOutOfBounds, start, limit)
+	}
+	if end > limit {
+		checked.End = limit
+		checked.Clamped = true
+	}
+	return checked, nil
+}
+
+// durationLimit returns the latest time a range may reach in the episode
+func durationLimit(episode *models.Episode) (float64, bool) {
+	if episode == nil || episode.Duration == nil || *episode.Duration <= 0 {
+		return 0, false
+	}
+	limit := float64(*episode.Duration)
+	if !episode.DurationProbed {
+		limit *= 1 + feedDurationSlack
+	}
+	return limit, true
+}
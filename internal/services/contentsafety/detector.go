@@ -0,0 +1,172 @@
+package contentsafety
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// DefaultPadding is the number of seconds added either side of a flagged word
+const DefaultPadding = 0.75
+
+// defaultTerms maps regex word patterns to severities. Patterns are matched
+// case-insensitively on word boundaries, so short words are listed with their
+// inflections explicitly rather than as open-ended stems.
+var defaultTerms = map[string]string{
+	`fuck\w*`:         models.ContentSeverityStrong,
+	`motherfuck\w*`:   models.ContentSeverityStrong,
+	`shit\w*`:         models.ContentSeverityStrong,
+	`bullshit\w*`:     models.ContentSeverityStrong,
+	`cunts?`:          models.ContentSeverityStrong,
+	`cocksucker\w*`:   models.ContentSeverityStrong,
+	`assholes?`:       models.ContentSeverityStrong,
+	`bitch(es|y)?`:    models.ContentSeverityStrong,
+	`dicks?`:          models.ContentSeverityMild,
+	`damn(ed|it)?`:    models.ContentSeverityMild,
+	`goddamn\w*`:      models.ContentSeverityMild,
+	`hell`:            models.ContentSeverityMild,
+	`crap(py)?`:       models.ContentSeverityMild,
+	`bastards?`:       models.ContentSeverityMild,
+	`piss(ed|ing)?`:   models.ContentSeverityMild,
+	`ass`:             models.ContentSeverityMild,
+	`wank(er|ing)?s?`: models.ContentSeverityMild,
+}
+
+// Segment is a span of transcript text with timing in seconds
+type Segment struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+// Detector finds explicit language in transcript segments
+type Detector struct {
+	patterns []termPattern
+	padding  float64
+}
+
+type termPattern struct {
+	re       *regexp.Regexp
+	severity string
+}
+
+// NewDetector creates a detector using the built-in term list plus any extra
+// terms, which are treated as strong severity and matched literally.
+func NewDetector(extraTerms []string, padding float64) *Detector {
+	if padding <= 0 {
+		padding = DefaultPadding
+	}
+
+	d := &Detector{padding: padding}
+
+	// Sort for deterministic pattern order
+	keys := make([]string, 0, len(defaultTerms))
+	for k := range defaultTerms {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, pattern := range keys {
+		d.patterns = append(d.patterns, termPattern{
+			re:       regexp.MustCompile(`(?i)\b` + pattern + `\b`),
+			severity: defaultTerms[pattern],
+		})
+	}
+
+	for _, term := range extraTerms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		d.patterns = append(d.patterns, termPattern{
+			re:       regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(term) + `\b`),
+			severity: models.ContentSeverityStrong,
+		})
+	}
+
+	return d
+}
+
+// Detect scans segments and returns merged, time-ordered flagged regions.
+// Word positions inside a segment are interpolated from their character offset.
+func (d *Detector) Detect(segments []Segment) []models.ContentFlag {
+	var flags []models.ContentFlag
+
+	for _, seg := range segments {
+		textLen := len(seg.Text)
+		if textLen == 0 {
+			continue
+		}
+		span := seg.End - seg.Start
+		if span < 0 {
+			span = 0
+		}
+
+		for _, p := range d.patterns {
+			for _, loc := range p.re.FindAllStringIndex(seg.Text, -1) {
+				mid := float64(loc[0]+loc[1]) / 2
+				at := seg.Start + span*mid/float64(textLen)
+
+				start := at - d.padding
+				if start < 0 {
+					start = 0
+				}
+
+				flags = append(flags, models.ContentFlag{
+					StartTime: start,
+					EndTime:   at + d.padding,
+					Severity:  p.severity,
+					Terms:     []string{strings.ToLower(seg.Text[loc[0]:loc[1]])},
+				})
+			}
+		}
+	}
+
+	return mergeFlags(flags)
+}
+
+// mergeFlags sorts flags by start time and combines overlapping regions,
+// keeping the highest severity and the union of matched terms.
+func mergeFlags(flags []models.ContentFlag) []models.ContentFlag {
+	if len(flags) == 0 {
+		return []models.ContentFlag{}
+	}
+
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].StartTime < flags[j].StartTime
+	})
+
+	merged := []models.ContentFlag{flags[0]}
+	for _, flag := range flags[1:] {
+		last := &merged[len(merged)-1]
+		if flag.StartTime > last.EndTime {
+			merged = append(merged, flag)
+			continue
+		}
+
+		if flag.EndTime > last.EndTime {
+			last.EndTime = flag.EndTime
+		}
+		if flag.Severity == models.ContentSeverityStrong {
+			last.Severity = models.ContentSeverityStrong
+		}
+		for _, term := range flag.Terms {
+			if !containsString(last.Terms, term) {
+				last.Terms = append(last.Terms, term)
+			}
+		}
+	}
+
+	return merged
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
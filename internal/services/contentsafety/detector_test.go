@@ -0,0 +1,69 @@
+package contentsafety
+
+import (
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetector_FlagsTermsWithinSegments(t *testing.T) {
+	d := NewDetector(nil, 0.5)
+
+	flags := d.Detect([]Segment{
+		{Start: 0, End: 5, Text: "Welcome back to the show everyone."},
+		{Start: 10, End: 12, Text: "Well damn, that was a shitty result."},
+		{Start: 30, End: 31, Text: "Hello, I assume you assessed the class."},
+	})
+
+	require.Len(t, flags, 1)
+	assert.Equal(t, models.ContentSeverityStrong, flags[0].Severity)
+	assert.ElementsMatch(t, []string{"damn", "shitty"}, flags[0].Terms)
+	assert.GreaterOrEqual(t, flags[0].StartTime, 9.5)
+	assert.LessOrEqual(t, flags[0].EndTime, 12.5)
+}
+
+func TestDetector_MildOnly(t *testing.T) {
+	d := NewDetector(nil, 0.5)
+
+	flags := d.Detect([]Segment{{Start: 0, End: 2, Text: "What the hell"}})
+
+	require.Len(t, flags, 1)
+	assert.Equal(t, models.ContentSeverityMild, flags[0].Severity)
+}
+
+func TestDetector_SeparateRegionsAreNotMerged(t *testing.T) {
+	d := NewDetector(nil, 0.5)
+
+	flags := d.Detect([]Segment{
+		{Start: 0, End: 100, Text: "crap and then a long stretch of perfectly clean words until crap"},
+	})
+
+	require.Len(t, flags, 2)
+	assert.Less(t, flags[0].EndTime, flags[1].StartTime)
+}
+
+func TestDetector_ExtraTerms(t *testing.T) {
+	d := NewDetector([]string{"frak"}, 0)
+
+	flags := d.Detect([]Segment{{Start: 0, End: 1, Text: "Oh FRAK."}})
+
+	require.Len(t, flags, 1)
+	assert.Equal(t, []string{"frak"}, flags[0].Terms)
+	assert.Equal(t, models.ContentSeverityStrong, flags[0].Severity)
+}
+
+func TestContentAnalysis_SetFlags(t *testing.T) {
+	analysis := &models.ContentAnalysis{}
+	require.NoError(t, analysis.SetFlags([]models.ContentFlag{
+		{StartTime: 1, EndTime: 2, Severity: models.ContentSeverityMild, Terms: []string{"hell"}},
+	}))
+
+	assert.False(t, analysis.Explicit)
+	assert.Equal(t, 1, analysis.FlagCount)
+
+	flags, err := analysis.Flags()
+	require.NoError(t, err)
+	assert.Equal(t, "hell", flags[0].Terms[0])
+}
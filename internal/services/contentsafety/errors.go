@@ -0,0 +1,8 @@
+package contentsafety
+
+import "errors"
+
+var (
+	// ErrAnalysisNotFound is returned when an episode has not been analyzed
+	ErrAnalysisNotFound = errors.New("content analysis not found")
+)
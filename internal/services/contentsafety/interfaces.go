@@ -0,0 +1,28 @@
+package contentsafety
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Service defines the interface for explicit-language analysis of transcriptions
+type Service interface {
+	// AnalyzeSegments scans timed transcript segments and stores the flagged regions
+	AnalyzeSegments(ctx context.Context, podcastIndexEpisodeID int64, segments []Segment) (*models.ContentAnalysis, error)
+
+	// AnalyzeText scans untimed transcript text, estimating flag times from the episode duration
+	AnalyzeText(ctx context.Context, podcastIndexEpisodeID int64, text string, duration float64) (*models.ContentAnalysis, error)
+
+	// GetAnalysis retrieves stored analysis results for an episode
+	GetAnalysis(ctx context.Context, podcastIndexEpisodeID int64) (*models.ContentAnalysis, error)
+}
+
+// Repository defines the interface for content analysis persistence
+type Repository interface {
+	// GetByEpisodeID retrieves analysis results by Podcast Index episode ID
+	GetByEpisodeID(ctx context.Context, podcastIndexEpisodeID int64) (*models.ContentAnalysis, error)
+
+	// Save creates or replaces analysis results for an episode
+	Save(ctx context.Context, analysis *models.ContentAnalysis) error
+}
@@ -0,0 +1,47 @@
+package contentsafety
+
+import (
+	"context"
+	"errors"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new content analysis repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetByEpisodeID retrieves analysis results by Podcast Index episode ID
+func (r *repository) GetByEpisodeID(ctx context.Context, podcastIndexEpisodeID int64) (*models.ContentAnalysis, error) {
+	var analysis models.ContentAnalysis
+
+	result := r.db.WithContext(ctx).Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).First(&analysis)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrAnalysisNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &analysis, nil
+}
+
+// Save creates or replaces analysis results for an episode
+func (r *repository) Save(ctx context.Context, analysis *models.ContentAnalysis) error {
+	if analysis == nil {
+		return errors.New("analysis cannot be nil")
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "podcast_index_episode_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"explicit", "flag_count", "flags_data", "timing", "updated_at", "deleted_at"}),
+	}).Create(analysis).Error
+}
@@ -0,0 +1,63 @@
+package contentsafety
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	detector *Detector
+}
+
+// NewService creates a new content safety service
+func NewService(repo Repository, detector *Detector) Service {
+	if detector == nil {
+		detector = NewDetector(nil, DefaultPadding)
+	}
+	return &service{
+		repo:     repo,
+		detector: detector,
+	}
+}
+
+// AnalyzeSegments scans timed transcript segments and stores the flagged regions
+func (s *service) AnalyzeSegments(ctx context.Context, podcastIndexEpisodeID int64, segments []Segment) (*models.ContentAnalysis, error) {
+	return s.analyze(ctx, podcastIndexEpisodeID, segments, models.ContentTimingSegments)
+}
+
+// AnalyzeText scans untimed transcript text, estimating flag times from the episode duration
+func (s *service) AnalyzeText(ctx context.Context, podcastIndexEpisodeID int64, text string, duration float64) (*models.ContentAnalysis, error) {
+	segments := []Segment{{Start: 0, End: duration, Text: text}}
+	return s.analyze(ctx, podcastIndexEpisodeID, segments, models.ContentTimingEstimated)
+}
+
+// GetAnalysis retrieves stored analysis results for an episode
+func (s *service) GetAnalysis(ctx context.Context, podcastIndexEpisodeID int64) (*models.ContentAnalysis, error) {
+	return s.repo.GetByEpisodeID(ctx, podcastIndexEpisodeID)
+}
+
+func (s *service) analyze(ctx context.Context, podcastIndexEpisodeID int64, segments []Segment, timing string) (*models.ContentAnalysis, error) {
+	flags := s.detector.Detect(segments)
+
+	analysis := &models.ContentAnalysis{
+		PodcastIndexEpisodeID: podcastIndexEpisodeID,
+		Timing:                timing,
+	}
+	if err := analysis.SetFlags(flags); err != nil {
+		return nil, fmt.Errorf("encoding content flags: %w", err)
+	}
+
+	if err := s.repo.Save(ctx, analysis); err != nil {
+		return nil, fmt.Errorf("saving content analysis: %w", err)
+	}
+
+	log.Printf("[DEBUG] Content analysis for episode %d: %d flagged regions (explicit: %v, timing: %s)",
+		podcastIndexEpisodeID, analysis.FlagCount, analysis.Explicit, timing)
+
+	return analysis, nil
+}
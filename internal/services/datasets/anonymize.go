@@ -0,0 +1,210 @@
+package datasets
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// ClipOrigin is the episode and podcast a clip was cut from
+type ClipOrigin struct {
+	UUID      string `json:"uuid"`
+	EpisodeID int64  `json:"podcast_index_episode_id"`
+	FeedID    int64  `json:"podcast_index_feed_id"`
+	SourceURL string `json:"source_url,omitempty"`
+}
+
+// Mapping ties the pseudonymous IDs of an anonymized dataset back to the
+// clips, episodes and podcasts they stand for. It is written next to the
+// archive, never into it, so only the server can undo the anonymization.
+type Mapping struct {
+	DatasetID string                `json:"dataset_id"`
+	Clips     map[string]ClipOrigin `json:"clips"`    // By pseudonymous clip ID
+	Episodes  map[string]int64      `json:"episodes"` // Podcast Index episode IDs by pseudonymous ID
+	Podcasts  map[string]Source     `json:"podcasts"` // By pseudonymous podcast ID
+}
+
+// anonymizer derives the pseudonymous IDs of one dataset. IDs are keyed by
+// the dataset, so the same clip or podcast can't be linked across datasets.
+type anonymizer struct {
+	key     []byte
+	mapping *Mapping
+}
+
+func newAnonymizer(secret []byte, datasetID string) *anonymizer {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("anonymize:" + datasetID))
+	return &anonymizer{
+		key: mac.Sum(nil),
+		mapping: &Mapping{
+			DatasetID: datasetID,
+			Clips:     make(map[string]ClipOrigin),
+			Episodes:  make(map[string]int64),
+			Podcasts:  make(map[string]Source),
+		},
+	}
+}
+
+// id returns the pseudonymous ID of value within kind ("clip", "episode" or "podcast")
+func (a *anonymizer) id(kind, value string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(kind + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+func (a *anonymizer) clipID(origin ClipOrigin) string {
+	id := a.id("clip", origin.UUID)
+	a.mapping.Clips[id] = origin
+	return id
+}
+
+func (a *anonymizer) episodeID(episodeID int64) string {
+	id := a.id("episode", fmt.Sprint(episodeID))
+	a.mapping.Episodes[id] = episodeID
+	return id
+}
+
+func (a *anonymizer) podcastID(feedID int64) string {
+	return a.id("podcast", fmt.Sprint(feedID))
+}
+
+// anonymizeMetadata rewrites the exported metadata file in dir: clip UUIDs and
+// audio file names become pseudonymous IDs, and pseudonymous episode_id and
+// podcast_id columns are added so samples can still be grouped by source, e.g.
+// to keep a podcast out of both splits. Source URLs are dropped, as are the
+// rights columns: fair use notes and custom licenses may name the podcast, so
+// they stay in the mapping and the card lists each source's license under its
+// pseudonymous ID. Every other column is kept as exported.
+func (a *anonymizer) anonymizeMetadata(dir, format string, origins map[string]ClipOrigin) error {
+	metadataPath := filepath.Join(dir, models.DatasetMetadataFile(format))
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+
+	pathColumn := "file_path"
+	if format == models.DatasetFormatAudioFolder {
+		pathColumn = "file_name"
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Transcript text can make long lines
+	for line := 1; scanner.Scan(); line++ {
+		var row map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			return fmt.Errorf("parsing manifest line %d: %w", line, err)
+		}
+
+		uuid, _ := row["uuid"].(string)
+		origin, ok := origins[uuid]
+		if !ok {
+			origin = ClipOrigin{UUID: uuid}
+		}
+		if url, _ := row["source_url"].(string); url != "" {
+			origin.SourceURL = url
+		}
+		id := a.clipID(origin)
+
+		row["uuid"] = id
+		delete(row, "source_url")
+		delete(row, "license")
+		delete(row, "fair_use_note")
+		row["episode_id"], row["podcast_id"] = nil, nil
+		if ok {
+			row["episode_id"] = a.episodeID(origin.EpisodeID)
+			row["podcast_id"] = a.podcastID(origin.FeedID)
+		}
+
+		if oldPath, _ := row[pathColumn].(string); oldPath != "" {
+			newPath := path.Join(path.Dir(oldPath), "clip_"+id+path.Ext(oldPath))
+			if err := os.Rename(filepath.Join(dir, filepath.FromSlash(oldPath)), filepath.Join(dir, filepath.FromSlash(newPath))); err != nil {
+				return fmt.Errorf("renaming clip %s: %w", id, err)
+			}
+			row[pathColumn] = newPath
+		}
+
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("encoding manifest line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+	return os.WriteFile(metadataPath, out.Bytes(), 0o644)
+}
+
+// anonymizeSources replaces the podcasts in the card with their pseudonymous
+// IDs, keeping only the language, clip count, license and allowed uses, and
+// records them in the mapping. Fair use notes may name the podcast, so they
+// stay in the mapping.
+func (a *anonymizer) anonymizeSources(sources []Source) []Source {
+	anonymized := make([]Source, len(sources))
+	for i, source := range sources {
+		id := a.podcastID(source.FeedID)
+		a.mapping.Podcasts[id] = source
+		anonymized[i] = Source{
+			ID:          id,
+			Language:    source.Language,
+			Clips:       source.Clips,
+			License:     source.License,
+			AllowedUses: source.AllowedUses,
+		}
+	}
+	return anonymized
+}
+
+// anonymizeSkipped strips the skip report written into the card of clip UUIDs,
+// episode IDs and errors, which may quote source URLs. The dataset record
+// keeps the full report for remediation.
+func (a *anonymizer) anonymizeSkipped(skipped []MissingAudio, origins map[string]ClipOrigin) []MissingAudio {
+	if len(skipped) == 0 {
+		return nil
+	}
+	anonymized := make([]MissingAudio, len(skipped))
+	for i, clip := range skipped {
+		origin, ok := origins[clip.UUID]
+		if !ok {
+			origin = ClipOrigin{UUID: clip.UUID, EpisodeID: clip.EpisodeID}
+		}
+		anonymized[i] = MissingAudio{UUID: a.clipID(origin), Label: clip.Label, Reason: clip.Reason}
+	}
+	return anonymized
+}
+
+// writeMapping writes the mapping next to the dataset's archive, readable
+// only by the server
+func (a *anonymizer) writeMapping(path string) error {
+	data, err := json.MarshalIndent(a.mapping, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding anonymization mapping: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing anonymization mapping: %w", err)
+	}
+	return nil
+}
+
+// clipOrigins loads the origins of the exported and skipped clips
+func (s *service) clipOrigins(ctx context.Context, uuids []string, skipped []MissingAudio) (map[string]ClipOrigin, error) {
+	all := append([]string(nil), uuids...)
+	for _, clip := range skipped {
+		all = append(all, clip.UUID)
+	}
+	origins, err := s.repo.ClipOrigins(ctx, all)
+	if err != nil {
+		return nil, fmt.Errorf("loading clip origins: %w", err)
+	}
+	return origins, nil
+}
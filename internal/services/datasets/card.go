@@ -0,0 +1,275 @@
+package datasets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+)
+
+// Files written at the root of every dataset archive
+const (
+	infoFile      = "info.json"
+	croissantFile = "croissant.json"
+)
+
+// croissantContext is the JSON-LD context from the Croissant 1.0 specification
+var croissantContext = map[string]interface{}{
+	"@language":  "en",
+	"@vocab":     "https://schema.org/",
+	"citeAs":     "cr:citeAs",
+	"column":     "cr:column",
+	"conformsTo": "dct:conformsTo",
+	"cr":         "http://mlcommons.org/croissant/",
+	"data": map[string]string{
+		"@id":   "cr:data",
+		"@type": "@json",
+	},
+	"dataType": map[string]string{
+		"@id":   "cr:dataType",
+		"@type": "@vocab",
+	},
+	"dct":           "http://purl.org/dc/terms/",
+	"extract":       "cr:extract",
+	"field":         "cr:field",
+	"fileObject":    "cr:fileObject",
+	"fileProperty":  "cr:fileProperty",
+	"fileSet":       "cr:fileSet",
+	"format":        "cr:format",
+	"includes":      "cr:includes",
+	"isLiveDataset": "cr:isLiveDataset",
+	"jsonPath":      "cr:jsonPath",
+	"key":           "cr:key",
+	"md5":           "cr:md5",
+	"parentField":   "cr:parentField",
+	"path":          "cr:path",
+	"recordSet":     "cr:recordSet",
+	"references":    "cr:references",
+	"regex":         "cr:regex",
+	"repeated":      "cr:repeated",
+	"replace":       "cr:replace",
+	"sc":            "https://schema.org/",
+	"separator":     "cr:separator",
+	"source":        "cr:source",
+	"subField":      "cr:subField",
+	"transform":     "cr:transform",
+}
+
+// manifestEntry is the subset of a manifest or metadata.jsonl line the card needs
+type manifestEntry struct {
+	UUID           string  `json:"uuid"`
+	FilePath       string  `json:"file_path"`
+	Label          string  `json:"label"`
+	Duration       float64 `json:"duration"`
+	HardNegative   bool    `json:"hard_negative"`
+	TranscriptText *string `json:"transcript_text"`
+}
+
+// LabelSummary counts the samples of one label in a dataset
+type LabelSummary struct {
+	Samples         int     `json:"samples"`
+	HardNegatives   int     `json:"hard_negatives"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// Processing records how the clip audio was produced
+type Processing struct {
+	SampleRate     int     `json:"sample_rate"`
+	Channels       int     `json:"channels"`
+	Codec          string  `json:"codec"`
+	TargetDuration float64 `json:"target_duration_seconds"` // 0 = clips keep their annotated length
+}
+
+// Selection records the filters that chose the dataset's clips
+type Selection struct {
+	IncludeHardNegatives bool `json:"include_hard_negatives"`
+	clips.ExportFilters
+}
+
+// Info is the info.json summary written into every dataset archive
+type Info struct {
+	ID            string                  `json:"id"`
+	Name          string                  `json:"name"`
+	Description   string                  `json:"description,omitempty"`
+	Format        string                  `json:"format"`
+	MetadataFile  string                  `json:"metadata_file"`
+	CreatedAt     time.Time               `json:"created_at"`
+	License       string                  `json:"license,omitempty"`
+	TotalSamples  int                     `json:"total_samples"`
+	TotalDuration float64                 `json:"total_duration_seconds"`
+	Labels        map[string]LabelSummary `json:"labels"`
+	Sources       []Source                `json:"sources"`
+	Processing    Processing              `json:"processing"`
+	Selection     Selection               `json:"selection"`
+	Skipped       []MissingAudio          `json:"skipped,omitempty"` // Selected clips left out for lack of audio
+	Anonymized    bool                    `json:"anonymized,omitempty"`
+}
+
+// newInfo summarizes the exported manifest entries
+func newInfo(dataset *models.Dataset, entries []manifestEntry, sources []Source, opts CardOptions, params GenerateParams) *Info {
+	info := &Info{
+		ID:           dataset.ID,
+		Name:         dataset.Name,
+		Description:  dataset.Description,
+		Format:       dataset.Format,
+		MetadataFile: models.DatasetMetadataFile(dataset.Format),
+		CreatedAt:    dataset.CreatedAt,
+		License:      opts.License,
+		Labels:       make(map[string]LabelSummary),
+		Sources:      sources,
+		Processing: Processing{
+			SampleRate:     clips.ClipSampleRate,
+			Channels:       clips.ClipChannels,
+			Codec:          clips.ClipCodec,
+			TargetDuration: opts.TargetDuration,
+		},
+		Selection: Selection{
+			IncludeHardNegatives: params.IncludeHardNegatives,
+			ExportFilters:        params.Filters,
+		},
+	}
+	if info.Sources == nil {
+		info.Sources = []Source{}
+	}
+	for _, entry := range entries {
+		summary := info.Labels[entry.Label]
+		summary.Samples++
+		summary.DurationSeconds += entry.Duration
+		if entry.HardNegative {
+			summary.HardNegatives++
+		}
+		info.Labels[entry.Label] = summary
+		info.TotalSamples++
+		info.TotalDuration += entry.Duration
+	}
+	return info
+}
+
+// croissant renders the dataset as Croissant 1.0 JSON-LD: the metadata file and
+// the audio files as distribution, and one record per clip joining the two
+func (info *Info) croissant() map[string]interface{} {
+	labels := make([]string, 0, len(info.Labels))
+	for label := range info.Labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	basedOn := make([]string, 0, len(info.Sources))
+	for _, source := range info.Sources {
+		if source.FeedURL != "" {
+			basedOn = append(basedOn, source.FeedURL)
+		}
+	}
+
+	description := info.Description
+	if description == "" {
+		description = fmt.Sprintf("%d labeled podcast audio clips (%s)", info.TotalSamples, strings.Join(labels, ", "))
+	}
+
+	pathColumn := "file_path"
+	if info.Format == models.DatasetFormatAudioFolder {
+		pathColumn = "file_name"
+	}
+	column := func(name, dataType, desc string) map[string]interface{} {
+		return map[string]interface{}{
+			"@type":       "cr:Field",
+			"@id":         "clips/" + name,
+			"name":        name,
+			"description": desc,
+			"dataType":    dataType,
+			"source": map[string]interface{}{
+				"fileObject": map[string]string{"@id": "metadata"},
+				"extract":    map[string]string{"column": name},
+			},
+		}
+	}
+
+	sourceColumns := []map[string]interface{}{
+		column("source_url", "sc:URL", "Episode audio the clip was cut from"),
+	}
+	if info.Anonymized {
+		sourceColumns = []map[string]interface{}{
+			column("episode_id", "sc:Text", "Pseudonymous ID of the episode the clip was cut from"),
+			column("podcast_id", "sc:Text", "Pseudonymous ID of the podcast the clip was cut from"),
+		}
+	}
+	fields := append([]map[string]interface{}{
+		column("uuid", "sc:Text", "Stable clip identifier"),
+		column(pathColumn, "sc:Text", "Audio file path relative to the dataset root"),
+		column("label", "sc:Text", "Annotation label"),
+		column("duration", "sc:Float", "Clip duration in seconds"),
+	}, sourceColumns...)
+	fields = append(fields,
+		column("original_start_time", "sc:Float", "Clip start within the episode, in seconds"),
+		column("original_end_time", "sc:Float", "Clip end within the episode, in seconds"),
+	)
+
+	doc := map[string]interface{}{
+		"@context":      croissantContext,
+		"@type":         "sc:Dataset",
+		"conformsTo":    "http://mlcommons.org/croissant/1.0",
+		"name":          info.Name,
+		"description":   description,
+		"identifier":    info.ID,
+		"datePublished": info.CreatedAt.Format(time.RFC3339),
+		"keywords":      labels,
+		"distribution": []map[string]interface{}{
+			{
+				"@type":          "cr:FileObject",
+				"@id":            "metadata",
+				"name":           "metadata",
+				"contentUrl":     info.MetadataFile,
+				"encodingFormat": "application/jsonlines",
+			},
+			{
+				"@type":          "cr:FileSet",
+				"@id":            "audio",
+				"name":           "audio",
+				"description":    fmt.Sprintf("%d Hz %d-channel %s WAV clips in per-label directories", info.Processing.SampleRate, info.Processing.Channels, info.Processing.Codec),
+				"encodingFormat": "audio/wav",
+				"includes":       "**/*.wav",
+			},
+		},
+		"recordSet": []map[string]interface{}{
+			{
+				"@type": "cr:RecordSet",
+				"@id":   "clips",
+				"name":  "clips",
+				"key":   map[string]string{"@id": "clips/uuid"},
+				"field": fields,
+			},
+		},
+	}
+	if info.License != "" {
+		doc["license"] = info.License
+	}
+	if len(basedOn) > 0 {
+		doc["isBasedOn"] = basedOn
+	}
+	return doc
+}
+
+// writeCard writes info.json and croissant.json into dir
+func writeCard(dir string, info *Info) error {
+	if err := writeJSON(filepath.Join(dir, infoFile), info); err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(dir, croissantFile), info.croissant())
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
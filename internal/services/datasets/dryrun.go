@@ -0,0 +1,106 @@
+package datasets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+)
+
+// wavHeaderBytes is the size of the RIFF header on every extracted clip
+const wavHeaderBytes = 44
+
+// DryRunReport is what Generate would produce for the same params. Clips whose
+// audio is missing are listed but left out of the counts, as Generate skips them.
+type DryRunReport struct {
+	Format        string                  `json:"format"`
+	TotalSamples  int                     `json:"total_samples"`
+	TotalDuration float64                 `json:"total_duration_seconds"`
+	EstimatedSize int64                   `json:"estimated_size_bytes"` // Audio the archive would hold
+	ToExtract     int                     `json:"to_extract"`           // Samples that would be extracted during generation
+	Labels        map[string]LabelSummary `json:"labels"`
+	MissingAudio  []MissingAudio          `json:"missing_audio"`
+	Selection     Selection               `json:"selection"`
+}
+
+// MissingAudio is a selected clip that Generate skips, or would have to,
+// because its audio is missing or its podcast's rights forbid exporting it
+type MissingAudio struct {
+	UUID      string `json:"uuid"`
+	Label     string `json:"label"`
+	EpisodeID int64  `json:"podcast_index_episode_id,omitempty"` // Left out of anonymized archives
+	Reason    string `json:"reason" enums:"no_filename,clip_missing,source_missing,extraction_failed,rights_restricted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DryRun plans the export for params and summarizes it. Durations and sizes of
+// clips that are not extracted yet are estimated from their annotated length,
+// or the configured target duration, at the fixed clip format.
+func (s *service) DryRun(ctx context.Context, params GenerateParams) (*DryRunReport, error) {
+	format := params.Format
+	switch format {
+	case "":
+		format = models.DatasetFormatJSONL
+	default:
+		if !models.IsValidDatasetFormat(format) {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+		}
+	}
+
+	planned, err := s.exporter.PlanExport(ctx, clips.ExportOptions{
+		IncludeHardNegatives: params.IncludeHardNegatives,
+		Format:               format,
+		Filters:              params.Filters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("planning export: %w", err)
+	}
+
+	report := &DryRunReport{
+		Format:       format,
+		Labels:       make(map[string]LabelSummary),
+		MissingAudio: []MissingAudio{},
+		Selection:    Selection{params.IncludeHardNegatives, params.Filters},
+	}
+	for _, clip := range planned {
+		if clip.Missing != "" {
+			report.MissingAudio = append(report.MissingAudio, MissingAudio{
+				UUID:      clip.UUID,
+				Label:     clip.Label,
+				EpisodeID: clip.EpisodeID,
+				Reason:    clip.Missing,
+			})
+			continue
+		}
+
+		duration, size := clip.Duration, clip.SizeBytes
+		if !clip.Extracted {
+			if s.card.TargetDuration > 0 {
+				duration = s.card.TargetDuration
+			}
+			size = estimateClipSize(duration)
+			report.ToExtract++
+		}
+
+		summary := report.Labels[clip.Label]
+		summary.Samples++
+		if clip.HardNegative {
+			summary.HardNegatives++
+		}
+		summary.DurationSeconds += duration
+		report.Labels[clip.Label] = summary
+
+		report.TotalSamples++
+		report.TotalDuration += duration
+		report.EstimatedSize += size
+	}
+	return report, nil
+}
+
+// estimateClipSize is the size of a WAV clip of duration seconds in the format
+// the extractor writes
+func estimateClipSize(duration float64) int64 {
+	bytesPerSecond := clips.ClipSampleRate * clips.ClipChannels * 2 // 16-bit PCM
+	return wavHeaderBytes + int64(duration*float64(bytesPerSecond))
+}
@@ -0,0 +1,29 @@
+package datasets
+
+import "errors"
+
+var (
+	// ErrDatasetNotFound is returned when no dataset has the requested ID
+	ErrDatasetNotFound = errors.New("dataset not found")
+
+	// ErrEmptyDataset is returned when there are no clips to put in a dataset
+	ErrEmptyDataset = errors.New("no clips to export")
+
+	// ErrUnsupportedFormat is returned when a dataset format is not one of the models.DatasetFormat* values
+	ErrUnsupportedFormat = errors.New("unsupported dataset format")
+
+	// ErrInvalidSignature is returned when a download link's signature does not match
+	ErrInvalidSignature = errors.New("invalid download signature")
+
+	// ErrLinkExpired is returned when a download link is past its expiry
+	ErrLinkExpired = errors.New("download link expired")
+
+	// ErrArchiveMissing is returned when a dataset's archive is no longer on disk
+	ErrArchiveMissing = errors.New("dataset archive is no longer available")
+
+	// ErrInvalidPredictions is returned for predictions that can't be evaluated
+	ErrInvalidPredictions = errors.New("invalid predictions")
+
+	// ErrEvaluationNotFound is returned when a dataset has no evaluation with the requested ID
+	ErrEvaluationNotFound = errors.New("evaluation not found")
+)
@@ -0,0 +1,349 @@
+package datasets
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// NoneLabel is the class of a clip that has none of the labels: the truth for
+// hard negatives, and the prediction for an empty label or a score under
+// EvaluateParams.MinScore
+const NoneLabel = "none"
+
+// Evaluation limits
+const (
+	MaxConfusedPairs    = 10  // Confused pairs reported per evaluation
+	maxConfusedExamples = 5   // Clip IDs listed per confused pair
+	MaxEvaluations      = 100 // Runs ListEvaluations returns
+)
+
+// Prediction is a model's output for one clip of a dataset
+type Prediction struct {
+	UUID  string   `json:"uuid"`            // The clip's ID in the archive; pseudonymous for anonymized datasets
+	Label string   `json:"label"`           // Predicted label; empty or "none" for no label
+	Score *float64 `json:"score,omitempty"` // Model confidence in Label
+}
+
+// EvaluateParams is a set of predictions to score against a dataset
+type EvaluateParams struct {
+	Name        string
+	MinScore    float64 // Predictions scoring lower count as NoneLabel
+	Predictions []Prediction
+	CreatedBy   string
+}
+
+// LabelMetrics scores the predictions for one class
+type LabelMetrics struct {
+	Support   int     `json:"support"`   // Clips whose true class it is
+	Predicted int     `json:"predicted"` // Clips predicted as it
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+// ConfusedPair is clips of one class predicted as another
+type ConfusedPair struct {
+	Actual    string   `json:"actual"`
+	Predicted string   `json:"predicted"`
+	Count     int      `json:"count"`
+	Share     float64  `json:"share"`                // Of the actual class's clips
+	MeanScore *float64 `json:"mean_score,omitempty"` // Over the confused predictions that had a score
+	Examples  []string `json:"examples"`             // Most confident mistakes first
+}
+
+// EvaluationResult is the confusion matrix of a run with the metrics derived from it
+type EvaluationResult struct {
+	Labels        []string                `json:"labels"` // Row and column order of Matrix
+	Matrix        [][]int                 `json:"matrix"` // Matrix[i][j] counts clips of Labels[i] predicted as Labels[j]
+	PerLabel      map[string]LabelMetrics `json:"per_label"`
+	TopConfusions []ConfusedPair          `json:"top_confusions"`
+}
+
+// Evaluation is a stored run with its result
+type Evaluation struct {
+	models.DatasetEvaluation
+	Result *EvaluationResult `json:"result,omitempty"`
+}
+
+// Evaluate scores predictions against the labels in a dataset's archive and
+// records the run. Hard negatives are truly NoneLabel. Dataset clips without a
+// prediction are counted as missing and left out of the matrix, as are
+// predictions for clips the dataset doesn't have.
+func (s *service) Evaluate(ctx context.Context, datasetID string, params EvaluateParams) (*Evaluation, error) {
+	if params.MinScore < 0 || params.MinScore > 1 {
+		return nil, fmt.Errorf("%w: min_score must be between 0 and 1", ErrInvalidPredictions)
+	}
+	predictions := make(map[string]Prediction, len(params.Predictions))
+	for _, p := range params.Predictions {
+		if p.UUID == "" {
+			return nil, fmt.Errorf("%w: every prediction needs a uuid", ErrInvalidPredictions)
+		}
+		if _, dup := predictions[p.UUID]; dup {
+			return nil, fmt.Errorf("%w: %s is predicted more than once", ErrInvalidPredictions, p.UUID)
+		}
+		predictions[p.UUID] = p
+	}
+
+	dataset, err := s.repo.Get(ctx, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	truth, err := readArchiveLabels(dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &Evaluation{DatasetEvaluation: models.DatasetEvaluation{
+		DatasetID: dataset.ID,
+		Name:      strings.TrimSpace(params.Name),
+		CreatedBy: params.CreatedBy,
+		MinScore:  params.MinScore,
+	}}
+	var pairs []scoredPair
+	for uuid, actual := range truth {
+		p, ok := predictions[uuid]
+		if !ok {
+			run.Missing++
+			continue
+		}
+		pairs = append(pairs, scoredPair{uuid: uuid, actual: actual, predicted: predictedLabel(p, params.MinScore), score: p.Score})
+	}
+	for uuid := range predictions {
+		if _, ok := truth[uuid]; !ok {
+			run.Unknown++
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("%w: none of the %d predictions are for clips in the dataset", ErrInvalidPredictions, len(predictions))
+	}
+
+	run.Result = confusion(pairs)
+	run.Samples = len(pairs)
+	run.Accuracy, run.MacroF1 = run.Result.summary(run.Samples)
+
+	result, err := json.Marshal(run.Result)
+	if err != nil {
+		return nil, fmt.Errorf("encoding evaluation: %w", err)
+	}
+	run.ResultJSON = string(result)
+	if err := s.repo.CreateEvaluation(ctx, &run.DatasetEvaluation); err != nil {
+		return nil, fmt.Errorf("recording evaluation: %w", err)
+	}
+	return run, nil
+}
+
+// ListEvaluations returns a dataset's runs, newest first, without their results
+func (s *service) ListEvaluations(ctx context.Context, datasetID string) ([]models.DatasetEvaluation, error) {
+	if _, err := s.repo.Get(ctx, datasetID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListEvaluations(ctx, datasetID, MaxEvaluations)
+}
+
+// GetEvaluation returns one of a dataset's runs with its result
+func (s *service) GetEvaluation(ctx context.Context, datasetID string, id uint) (*Evaluation, error) {
+	stored, err := s.repo.GetEvaluation(ctx, datasetID, id)
+	if err != nil {
+		return nil, err
+	}
+	run := &Evaluation{DatasetEvaluation: *stored}
+	if stored.ResultJSON != "" {
+		if err := json.Unmarshal([]byte(stored.ResultJSON), &run.Result); err != nil {
+			return nil, fmt.Errorf("decoding evaluation %d: %w", id, err)
+		}
+	}
+	return run, nil
+}
+
+// predictedLabel is the class a prediction counts as
+func predictedLabel(p Prediction, minScore float64) string {
+	label := strings.TrimSpace(p.Label)
+	if label == "" || (p.Score != nil && *p.Score < minScore) {
+		return NoneLabel
+	}
+	return label
+}
+
+// readArchiveLabels returns the true class of each clip in a dataset's archive
+// by its ID there, from the manifest written when the dataset was generated
+func readArchiveLabels(dataset *models.Dataset) (map[string]string, error) {
+	archive, err := zip.OpenReader(dataset.DatasetPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrArchiveMissing
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening dataset archive: %w", err)
+	}
+	defer archive.Close()
+
+	manifest, err := archive.Open(models.DatasetMetadataFile(dataset.Format))
+	if err != nil {
+		return nil, fmt.Errorf("opening dataset manifest: %w", err)
+	}
+	defer manifest.Close()
+
+	truth := make(map[string]string)
+	scanner := bufio.NewScanner(manifest)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Transcript text can make long lines
+	for line := 1; scanner.Scan(); line++ {
+		var entry manifestEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parsing manifest line %d: %w", line, err)
+		}
+		truth[entry.UUID] = entry.Label
+		if entry.HardNegative {
+			truth[entry.UUID] = NoneLabel
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading dataset manifest: %w", err)
+	}
+	return truth, nil
+}
+
+// scoredPair is one clip's true and predicted class
+type scoredPair struct {
+	uuid              string
+	actual, predicted string
+	score             *float64
+}
+
+// confusion builds the confusion matrix of pairs with per-label metrics and
+// the most frequent confusions. Labels are sorted with NoneLabel last.
+func confusion(pairs []scoredPair) *EvaluationResult {
+	seen := make(map[string]bool)
+	for _, p := range pairs {
+		seen[p.actual], seen[p.predicted] = true, true
+	}
+	labels := make([]string, 0, len(seen))
+	for label := range seen {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if (labels[i] == NoneLabel) != (labels[j] == NoneLabel) {
+			return labels[j] == NoneLabel
+		}
+		return labels[i] < labels[j]
+	})
+	index := make(map[string]int, len(labels))
+	for i, label := range labels {
+		index[label] = i
+	}
+
+	result := &EvaluationResult{
+		Labels:   labels,
+		Matrix:   make([][]int, len(labels)),
+		PerLabel: make(map[string]LabelMetrics, len(labels)),
+	}
+	for i := range result.Matrix {
+		result.Matrix[i] = make([]int, len(labels))
+	}
+	confused := make(map[[2]string][]scoredPair)
+	for _, p := range pairs {
+		result.Matrix[index[p.actual]][index[p.predicted]]++
+		if p.actual != p.predicted {
+			key := [2]string{p.actual, p.predicted}
+			confused[key] = append(confused[key], p)
+		}
+	}
+
+	for i, label := range labels {
+		var m LabelMetrics
+		for j := range labels {
+			m.Support += result.Matrix[i][j]
+			m.Predicted += result.Matrix[j][i]
+		}
+		hits := float64(result.Matrix[i][i])
+		if m.Predicted > 0 {
+			m.Precision = hits / float64(m.Predicted)
+		}
+		if m.Support > 0 {
+			m.Recall = hits / float64(m.Support)
+		}
+		if m.Precision+m.Recall > 0 {
+			m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+		}
+		result.PerLabel[label] = m
+	}
+
+	result.TopConfusions = make([]ConfusedPair, 0, len(confused))
+	for key, clips := range confused {
+		result.TopConfusions = append(result.TopConfusions, confusedPair(key[0], key[1], clips, result.PerLabel[key[0]].Support))
+	}
+	sort.Slice(result.TopConfusions, func(i, j int) bool {
+		a, b := result.TopConfusions[i], result.TopConfusions[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Actual != b.Actual {
+			return a.Actual < b.Actual
+		}
+		return a.Predicted < b.Predicted
+	})
+	if len(result.TopConfusions) > MaxConfusedPairs {
+		result.TopConfusions = result.TopConfusions[:MaxConfusedPairs]
+	}
+	return result
+}
+
+// confusedPair summarizes the clips of actual predicted as predicted, listing
+// the highest-scoring mistakes first as they are the most telling
+func confusedPair(actual, predicted string, clips []scoredPair, support int) ConfusedPair {
+	sort.Slice(clips, func(i, j int) bool {
+		a, b := clips[i].score, clips[j].score
+		if (a == nil) != (b == nil) {
+			return b == nil
+		}
+		if a != nil && *a != *b {
+			return *a > *b
+		}
+		return clips[i].uuid < clips[j].uuid
+	})
+
+	pair := ConfusedPair{
+		Actual:    actual,
+		Predicted: predicted,
+		Count:     len(clips),
+		Share:     float64(len(clips)) / float64(support),
+		Examples:  make([]string, 0, min(len(clips), maxConfusedExamples)),
+	}
+	var total float64
+	var scored int
+	for i, c := range clips {
+		if i < maxConfusedExamples {
+			pair.Examples = append(pair.Examples, c.uuid)
+		}
+		if c.score != nil {
+			total += *c.score
+			scored++
+		}
+	}
+	if scored > 0 {
+		mean := total / float64(scored)
+		pair.MeanScore = &mean
+	}
+	return pair
+}
+
+// summary returns the accuracy and the F1 averaged over the true classes of
+// the evaluated clips
+func (r *EvaluationResult) summary(samples int) (accuracy, macroF1 float64) {
+	hits, classes := 0, 0
+	for i, label := range r.Labels {
+		hits += r.Matrix[i][i]
+		if m := r.PerLabel[label]; m.Support > 0 {
+			macroF1 += m.F1
+			classes++
+		}
+	}
+	return float64(hits) / float64(samples), macroF1 / float64(classes)
+}
@@ -0,0 +1,114 @@
+package datasets
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func score(v float64) *float64 { return &v }
+
+func TestEvaluate(t *testing.T) {
+	exporter := &fakeExporter{manifest: `{"uuid":"ad1","label":"advertisement","duration":10}
+{"uuid":"ad2","label":"advertisement","duration":10}
+{"uuid":"ad3","label":"advertisement","duration":10}
+{"uuid":"m1","label":"music","duration":10}
+{"uuid":"m2","label":"music","duration":10}
+{"uuid":"neg","label":"advertisement","duration":10,"hard_negative":true}
+`}
+	svc := NewService(NewRepository(setupTestDB(t)), exporter, t.TempDir(), []byte("secret"), CardOptions{})
+	ctx := context.Background()
+	dataset, err := svc.Generate(ctx, GenerateParams{IncludeHardNegatives: true})
+	require.NoError(t, err)
+
+	run, err := svc.Evaluate(ctx, dataset.ID, EvaluateParams{
+		Name:     "detector v1",
+		MinScore: 0.5,
+		Predictions: []Prediction{
+			{UUID: "ad1", Label: "advertisement", Score: score(0.9)},
+			{UUID: "ad2", Label: "music", Score: score(0.6)},
+			{UUID: "ad3", Label: "advertisement", Score: score(0.3)}, // Under min_score
+			{UUID: "m1", Label: "music", Score: score(0.8)},
+			{UUID: "m2", Label: "advertisement", Score: score(0.95)},
+			{UUID: "neg", Label: "advertisement", Score: score(0.7)},
+			{UUID: "other", Label: "music"},
+		},
+		CreatedBy: "user-1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, run.Samples)
+	assert.Equal(t, 1, run.Unknown)
+	assert.Zero(t, run.Missing)
+	assert.Equal(t, []string{"advertisement", "music", NoneLabel}, run.Result.Labels, "none sorts last")
+	assert.Equal(t, [][]int{
+		{1, 1, 1}, // advertisement: right, as music, under min_score
+		{1, 1, 0}, // music
+		{1, 0, 0}, // the hard negative, predicted as an ad
+	}, run.Result.Matrix)
+	assert.InDelta(t, 2.0/6, run.Accuracy, 1e-9)
+
+	ads := run.Result.PerLabel["advertisement"]
+	assert.Equal(t, 3, ads.Support)
+	assert.Equal(t, 3, ads.Predicted)
+	assert.InDelta(t, 1.0/3, ads.Precision, 1e-9)
+	assert.InDelta(t, (1.0/3+0.5+0)/3, run.MacroF1, 1e-9, "advertisement, music and none F1 averaged")
+
+	// Every confusion here happens once; ties go by class name
+	require.Len(t, run.Result.TopConfusions, 4)
+	first := run.Result.TopConfusions[0]
+	assert.Equal(t, ConfusedPair{Actual: "advertisement", Predicted: "music", Count: 1, Share: 1.0 / 3, MeanScore: score(0.6), Examples: []string{"ad2"}}, first)
+
+	// Runs are kept for comparison
+	_, err = svc.Evaluate(ctx, dataset.ID, EvaluateParams{Name: "detector v2", Predictions: []Prediction{{UUID: "ad1", Label: "advertisement"}}})
+	require.NoError(t, err)
+	runs, err := svc.ListEvaluations(ctx, dataset.ID)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "detector v2", runs[0].Name)
+	assert.Equal(t, 5, runs[0].Missing)
+	assert.Empty(t, runs[1].ResultJSON, "lists leave the matrix out")
+
+	stored, err := svc.GetEvaluation(ctx, dataset.ID, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, run.Result.Matrix, stored.Result.Matrix)
+	assert.Equal(t, "user-1", stored.CreatedBy)
+
+	_, err = svc.GetEvaluation(ctx, "other", run.ID)
+	assert.ErrorIs(t, err, ErrEvaluationNotFound)
+}
+
+func TestEvaluate_Rejects(t *testing.T) {
+	exporter := &fakeExporter{manifest: `{"uuid":"ad1","label":"advertisement","duration":10}
+`}
+	svc := NewService(NewRepository(setupTestDB(t)), exporter, t.TempDir(), []byte("secret"), CardOptions{})
+	ctx := context.Background()
+	dataset, err := svc.Generate(ctx, GenerateParams{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		params EvaluateParams
+	}{
+		{"no uuid", EvaluateParams{Predictions: []Prediction{{Label: "music"}}}},
+		{"duplicate", EvaluateParams{Predictions: []Prediction{{UUID: "ad1"}, {UUID: "ad1"}}}},
+		{"min score", EvaluateParams{MinScore: 2, Predictions: []Prediction{{UUID: "ad1"}}}},
+		{"no matching clips", EvaluateParams{Predictions: []Prediction{{UUID: "other", Label: "music"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Evaluate(ctx, dataset.ID, tt.params)
+			assert.ErrorIs(t, err, ErrInvalidPredictions)
+		})
+	}
+
+	_, err = svc.Evaluate(ctx, "missing", EvaluateParams{Predictions: []Prediction{{UUID: "ad1"}}})
+	assert.ErrorIs(t, err, ErrDatasetNotFound)
+
+	require.NoError(t, os.Remove(dataset.DatasetPath))
+	_, err = svc.Evaluate(ctx, dataset.ID, EvaluateParams{Predictions: []Prediction{{UUID: "ad1"}}})
+	assert.ErrorIs(t, err, ErrArchiveMissing)
+}
@@ -0,0 +1,115 @@
+package datasets
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// GenerateParams describes a dataset to build from the current clips. It is
+// also the payload of a dataset_generation job, hence the JSON tags.
+type GenerateParams struct {
+	Name                 string              `json:"name,omitempty"`
+	Description          string              `json:"description,omitempty"`
+	IncludeHardNegatives bool                `json:"include_hard_negatives,omitempty"`
+	Format               string              `json:"format,omitempty"` // One of the models.DatasetFormat* layouts; defaults to jsonl
+	Filters              clips.ExportFilters `json:"filters"`
+
+	// CacheMissingAudio queues audio caching for the episodes of clips skipped
+	// for lack of source audio, and a re-run of the same generation once it's done
+	CacheMissingAudio bool `json:"cache_missing_audio,omitempty"`
+
+	// Anonymize replaces clip, episode and podcast identifiers in the archive
+	// with pseudonymous IDs and leaves out source URLs and podcast names, for
+	// datasets shared outside. The mapping back is kept on the server.
+	Anonymize bool `json:"anonymize,omitempty"`
+}
+
+// Source is a podcast that contributed clips to a dataset
+type Source struct {
+	ID       string `json:"id,omitempty"` // Pseudonymous podcast ID in anonymized datasets, which omit the rest
+	FeedID   int64  `json:"podcast_index_feed_id,omitempty"`
+	Title    string `json:"title,omitempty"`
+	FeedURL  string `json:"feed_url,omitempty"`
+	Language string `json:"language,omitempty"`
+	Clips    int    `json:"clips"`
+
+	// The podcast's recorded rights, which its clips inherit; empty when none are recorded
+	License     string   `json:"license,omitempty"`
+	FairUseNote string   `json:"fair_use_note,omitempty"`
+	AllowedUses []string `json:"allowed_uses,omitempty" gorm:"-"`
+}
+
+// CardOptions are the dataset-wide facts written into info.json and the
+// Croissant description that can't be derived from the clips themselves
+type CardOptions struct {
+	License        string  // SPDX identifier or URL for the annotations; empty if unspecified
+	TargetDuration float64 // clips.target_duration the audio was padded/cropped to (0 = exact)
+}
+
+// Exporter writes clips and their metadata file into a directory
+type Exporter interface {
+	ExportDataset(ctx context.Context, exportPath string, opts clips.ExportOptions) ([]clips.SkippedClip, error)
+
+	// PlanExport lists what ExportDataset would write without writing it
+	PlanExport(ctx context.Context, opts clips.ExportOptions) ([]clips.PlannedClip, error)
+}
+
+// JobQueue is the part of jobs.Service used to queue audio caching and re-runs
+type JobQueue interface {
+	EnqueueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, opts ...jobs.JobOption) (*models.Job, error)
+	EnqueueUniqueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, uniqueKey string, opts ...jobs.JobOption) (*models.Job, error)
+}
+
+// Service builds dataset archives and authorizes their download
+type Service interface {
+	// Generate exports the approved clips into a new zip archive and records it
+	Generate(ctx context.Context, params GenerateParams) (*models.Dataset, error)
+
+	// DryRun runs the clip selection and audio checks of Generate and reports
+	// what the dataset would contain, without writing or recording anything
+	DryRun(ctx context.Context, params GenerateParams) (*DryRunReport, error)
+
+	// Get returns a dataset by ID
+	Get(ctx context.Context, id string) (*models.Dataset, error)
+
+	// List returns the most recent datasets first
+	List(ctx context.Context, limit int) ([]models.Dataset, error)
+
+	// SignDownload returns an expiry and signature authorizing download of a dataset
+	SignDownload(id string, ttl time.Duration) (time.Time, string)
+
+	// VerifyDownload checks a signature produced by SignDownload
+	VerifyDownload(id string, expires int64, signature string) error
+
+	// Evaluate scores a model's predictions against a dataset's labels and
+	// records the run
+	Evaluate(ctx context.Context, datasetID string, params EvaluateParams) (*Evaluation, error)
+
+	// ListEvaluations returns a dataset's evaluation runs, newest first
+	ListEvaluations(ctx context.Context, datasetID string) ([]models.DatasetEvaluation, error)
+
+	// GetEvaluation returns one of a dataset's runs with its confusion matrix
+	GetEvaluation(ctx context.Context, datasetID string, id uint) (*Evaluation, error)
+}
+
+// Repository defines the interface for dataset persistence
+type Repository interface {
+	Create(ctx context.Context, dataset *models.Dataset) error
+	Get(ctx context.Context, id string) (*models.Dataset, error)
+	List(ctx context.Context, limit int) ([]models.Dataset, error)
+
+	// ListSources returns the podcasts the given clips were cut from, with
+	// how many of the clips came from each and the podcasts' recorded rights
+	ListSources(ctx context.Context, clipUUIDs []string) ([]Source, error)
+
+	// ClipOrigins returns the episode and podcast of each of the given clips, by UUID
+	ClipOrigins(ctx context.Context, clipUUIDs []string) (map[string]ClipOrigin, error)
+
+	CreateEvaluation(ctx context.Context, evaluation *models.DatasetEvaluation) error
+	ListEvaluations(ctx context.Context, datasetID string, limit int) ([]models.DatasetEvaluation, error)
+	GetEvaluation(ctx context.Context, datasetID string, id uint) (*models.DatasetEvaluation, error)
+}
@@ -0,0 +1,108 @@
+package datasets
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// Option configures optional service behaviour
+type Option func(*service)
+
+// WithJobQueue lets Generate act on GenerateParams.CacheMissingAudio; without
+// a queue the option is ignored and skipped clips are only reported
+func WithJobQueue(queue JobQueue) Option {
+	return func(s *service) {
+		s.queue = queue
+	}
+}
+
+// Payload encodes params as the payload of a dataset_generation job
+func (p GenerateParams) Payload() (models.JobPayload, error) {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var payload models.JobPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// missingAudio converts the exporter's skipped clips for the report
+func missingAudio(skipped []clips.SkippedClip) []MissingAudio {
+	if len(skipped) == 0 {
+		return nil
+	}
+	missing := make([]MissingAudio, len(skipped))
+	for i, clip := range skipped {
+		missing[i] = MissingAudio{
+			UUID:      clip.UUID,
+			Label:     clip.Label,
+			EpisodeID: clip.EpisodeID,
+			Reason:    clip.Reason,
+			Error:     clip.Error,
+		}
+	}
+	return missing
+}
+
+// cacheable reports whether caching the episode's audio could bring a skipped
+// clip back. Extracted clips whose file is gone and clips without a filename
+// need more than source audio.
+func cacheable(clip clips.SkippedClip) bool {
+	if clip.Extracted || clip.EpisodeID == 0 {
+		return false
+	}
+	return clip.Reason == clips.MissingSource || clip.Reason == clips.ExtractionFailed
+}
+
+// queueRemediation queues an audio_cache job for each episode with cacheable
+// skipped clips, then a re-run of params that waits for all of them. The re-run
+// doesn't remediate again, so a second shortfall is reported rather than
+// chased. It returns the re-run job, or nil when nothing was queued.
+func (s *service) queueRemediation(ctx context.Context, params GenerateParams, skipped []clips.SkippedClip) *models.Job {
+	if !params.CacheMissingAudio || s.queue == nil {
+		return nil
+	}
+
+	seen := make(map[int64]bool)
+	var cacheJobs []uint
+	for _, clip := range skipped {
+		if !cacheable(clip) || seen[clip.EpisodeID] {
+			continue
+		}
+		seen[clip.EpisodeID] = true
+
+		job, err := s.queue.EnqueueUniqueJob(ctx, models.JobTypeAudioCache,
+			models.JobPayload{"episode_id": clip.EpisodeID}, "episode_id")
+		if err != nil {
+			log.Printf("[WARN] Failed to queue audio caching for episode %d: %v", clip.EpisodeID, err)
+			continue
+		}
+		cacheJobs = append(cacheJobs, job.ID)
+	}
+	if len(cacheJobs) == 0 {
+		return nil
+	}
+
+	rerun := params
+	rerun.CacheMissingAudio = false
+	payload, err := rerun.Payload()
+	if err != nil {
+		log.Printf("[WARN] Failed to encode dataset re-run: %v", err)
+		return nil
+	}
+	job, err := s.queue.EnqueueJob(ctx, models.JobTypeDatasetGeneration, payload, jobs.WithDependsOn(cacheJobs...))
+	if err != nil {
+		log.Printf("[WARN] Failed to queue dataset re-run: %v", err)
+		return nil
+	}
+	log.Printf("[INFO] Queued audio caching for %d episodes and dataset re-run job %d", len(cacheJobs), job.ID)
+	return job
+}
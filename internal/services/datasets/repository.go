@@ -0,0 +1,160 @@
+package datasets
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new datasets repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, dataset *models.Dataset) error {
+	return r.db.WithContext(ctx).Create(dataset).Error
+}
+
+func (r *repository) Get(ctx context.Context, id string) (*models.Dataset, error) {
+	var dataset models.Dataset
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&dataset).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDatasetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+func (r *repository) List(ctx context.Context, limit int) ([]models.Dataset, error) {
+	var datasets []models.Dataset
+	err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&datasets).Error
+	return datasets, err
+}
+
+func (r *repository) CreateEvaluation(ctx context.Context, evaluation *models.DatasetEvaluation) error {
+	return r.db.WithContext(ctx).Create(evaluation).Error
+}
+
+func (r *repository) ListEvaluations(ctx context.Context, datasetID string, limit int) ([]models.DatasetEvaluation, error) {
+	var evaluations []models.DatasetEvaluation
+	err := r.db.WithContext(ctx).
+		Omit("result_json").
+		Where("dataset_id = ?", datasetID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&evaluations).Error
+	return evaluations, err
+}
+
+func (r *repository) GetEvaluation(ctx context.Context, datasetID string, id uint) (*models.DatasetEvaluation, error) {
+	var evaluation models.DatasetEvaluation
+	err := r.db.WithContext(ctx).Where("dataset_id = ? AND id = ?", datasetID, id).First(&evaluation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEvaluationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &evaluation, nil
+}
+
+// sourcesBatchSize keeps the IN list under SQLite's bound-parameter limit
+const sourcesBatchSize = 500
+
+func (r *repository) ListSources(ctx context.Context, clipUUIDs []string) ([]Source, error) {
+	counts := make(map[int64]*Source)
+	var order []int64
+	for start := 0; start < len(clipUUIDs); start += sourcesBatchSize {
+		batch := clipUUIDs[start:min(start+sourcesBatchSize, len(clipUUIDs))]
+
+		// Rights are unique per feed, so grouping by them too keeps one row per podcast
+		var rows []sourceRow
+		err := r.db.WithContext(ctx).
+			Table("clips").
+			Select("episodes.podcast_index_feed_id AS feed_id, MAX(episodes.feed_title) AS title, "+
+				"MAX(podcasts.feed_url) AS feed_url, MAX(episodes.feed_language) AS language, COUNT(*) AS clips, "+
+				"podcast_rights.license, podcast_rights.fair_use_note, podcast_rights.allow_training, "+
+				"podcast_rights.allow_derivatives, podcast_rights.allow_redistribution").
+			Joins("JOIN episodes ON episodes.podcast_index_id = clips.podcast_index_episode_id").
+			Joins("LEFT JOIN podcasts ON podcasts.id = episodes.podcast_id").
+			Joins("LEFT JOIN podcast_rights ON podcast_rights.podcast_index_feed_id = episodes.podcast_index_feed_id").
+			Where("clips.uuid IN ?", batch).
+			Group("episodes.podcast_index_feed_id, podcast_rights.id").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			if existing, ok := counts[row.FeedID]; ok {
+				existing.Clips += row.Clips
+				continue
+			}
+			source := row.source()
+			counts[row.FeedID] = &source
+			order = append(order, row.FeedID)
+		}
+	}
+
+	sources := make([]Source, 0, len(order))
+	for _, id := range order {
+		sources = append(sources, *counts[id])
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Clips > sources[j].Clips })
+	return sources, nil
+}
+
+// sourceRow is a Source as scanned, with the podcast's allowed-use flags, which
+// are null when it has no recorded rights
+type sourceRow struct {
+	Source              `gorm:"embedded"`
+	AllowTraining       *bool
+	AllowDerivatives    *bool
+	AllowRedistribution *bool
+}
+
+func (row sourceRow) source() Source {
+	source := row.Source
+	if row.AllowTraining != nil {
+		rights := models.PodcastRights{
+			AllowTraining:       *row.AllowTraining,
+			AllowDerivatives:    row.AllowDerivatives != nil && *row.AllowDerivatives,
+			AllowRedistribution: row.AllowRedistribution != nil && *row.AllowRedistribution,
+		}
+		source.AllowedUses = rights.AllowedUses()
+	}
+	return source
+}
+
+func (r *repository) ClipOrigins(ctx context.Context, clipUUIDs []string) (map[string]ClipOrigin, error) {
+	origins := make(map[string]ClipOrigin, len(clipUUIDs))
+	for start := 0; start < len(clipUUIDs); start += sourcesBatchSize {
+		batch := clipUUIDs[start:min(start+sourcesBatchSize, len(clipUUIDs))]
+
+		var rows []ClipOrigin
+		err := r.db.WithContext(ctx).
+			Table("clips").
+			Select("clips.uuid, clips.podcast_index_episode_id AS episode_id, "+
+				"episodes.podcast_index_feed_id AS feed_id, clips.source_episode_url AS source_url").
+			Joins("LEFT JOIN episodes ON episodes.podcast_index_id = clips.podcast_index_episode_id").
+			Where("clips.uuid IN ?", batch).
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			origins[row.UUID] = row
+		}
+	}
+	return origins, nil
+}
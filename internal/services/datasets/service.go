@@ -0,0 +1,325 @@
+package datasets
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+)
+
+// Page size bounds for List
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// service implements the Service interface
+type service struct {
+	repo      Repository
+	exporter  Exporter
+	directory string
+	secret    []byte
+	card      CardOptions
+	queue     JobQueue // Optional; see WithJobQueue
+}
+
+// NewService creates a new datasets service. Archives are written under
+// directory and download links are signed with secret; card fills in the
+// info.json and croissant.json written into each archive.
+func NewService(repo Repository, exporter Exporter, directory string, secret []byte, card CardOptions, opts ...Option) Service {
+	s := &service{
+		repo:      repo,
+		exporter:  exporter,
+		directory: directory,
+		secret:    secret,
+		card:      card,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Generate exports clips into a staging directory, zips it next to the other
+// archives and records the dataset. The staging directory is removed either way.
+// Clips the export had to skip are recorded on the dataset and in info.json;
+// with params.CacheMissingAudio their audio is queued for caching ahead of a
+// re-run, whose job ID is recorded too.
+func (s *service) Generate(ctx context.Context, params GenerateParams) (*models.Dataset, error) {
+	started := time.Now()
+
+	format := params.Format
+	switch format {
+	case "":
+		format = models.DatasetFormatJSONL
+	default:
+		if !models.IsValidDatasetFormat(format) {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+		}
+	}
+
+	if err := os.MkdirAll(s.directory, 0o755); err != nil {
+		return nil, fmt.Errorf("creating dataset directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(s.directory, "staging_*")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	// The speech toolkit formats are written from the JSONL manifest
+	exportFormat := format
+	if format == models.DatasetFormatKaldi || format == models.DatasetFormatESPnet {
+		exportFormat = models.DatasetFormatJSONL
+	}
+	skippedClips, err := s.exporter.ExportDataset(ctx, staging, clips.ExportOptions{
+		IncludeHardNegatives: params.IncludeHardNegatives,
+		Format:               exportFormat,
+		Filters:              params.Filters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("exporting clips: %w", err)
+	}
+
+	entries, err := readManifest(filepath.Join(staging, models.DatasetMetadataFile(format)))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		if rerun := s.queueRemediation(ctx, params, skippedClips); rerun != nil {
+			return nil, fmt.Errorf("%w: none of the %d selected clips had audio; regeneration queued as job %d",
+				ErrEmptyDataset, len(skippedClips), rerun.ID)
+		}
+		return nil, ErrEmptyDataset
+	}
+	skipped := missingAudio(skippedClips)
+
+	dataset := &models.Dataset{
+		ID:          models.NewDatasetID(),
+		Name:        params.Name,
+		Description: params.Description,
+		Label:       "all",
+		Format:      format,
+		AudioFormat: "processed",
+		CreatedAt:   time.Now(),
+	}
+	if dataset.Name == "" {
+		dataset.Name = dataset.ID
+	}
+
+	uuids := make([]string, len(entries))
+	for i, entry := range entries {
+		uuids[i] = entry.UUID
+	}
+	sources, err := s.repo.ListSources(ctx, uuids)
+	if err != nil {
+		log.Printf("[WARN] Failed to load source podcasts for dataset %s: %v", dataset.ID, err)
+	}
+	info := newInfo(dataset, entries, sources, s.card, params)
+	info.Skipped = skipped
+	var anon *anonymizer
+	if params.Anonymize {
+		anon = newAnonymizer(s.secret, dataset.ID)
+		origins, err := s.clipOrigins(ctx, uuids, skipped)
+		if err != nil {
+			return nil, err
+		}
+		if err := anon.anonymizeMetadata(staging, format, origins); err != nil {
+			return nil, err
+		}
+		info.Sources = anon.anonymizeSources(info.Sources)
+		info.Skipped = anon.anonymizeSkipped(skipped, origins)
+		info.Anonymized = true
+	}
+	if err := writeToolkitFiles(staging, format); err != nil {
+		return nil, err
+	}
+	if err := writeCard(staging, info); err != nil {
+		return nil, err
+	}
+	dataset.TotalSamples = info.TotalSamples
+	dataset.TotalDuration = info.TotalDuration
+	dataset.AverageDuration = info.TotalDuration / float64(info.TotalSamples)
+	dataset.MetadataPath = croissantFile
+	if filters := recordedFilters(params); filters != "" {
+		dataset.FiltersJSON = filters
+	}
+	if len(skipped) > 0 {
+		report, _ := json.Marshal(skipped)
+		dataset.SkippedSamples = len(skipped)
+		dataset.SkippedJSON = string(report)
+	}
+
+	archive := filepath.Join(s.directory, dataset.ID+".zip")
+	if err := zipDirectory(staging, archive); err != nil {
+		os.Remove(archive)
+		return nil, fmt.Errorf("creating archive: %w", err)
+	}
+	stat, err := os.Stat(archive)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	dataset.DatasetPath = archive
+	dataset.TotalSize = stat.Size()
+	if anon != nil {
+		mapping := filepath.Join(s.directory, dataset.ID+".mapping.json")
+		if err := anon.writeMapping(mapping); err != nil {
+			os.Remove(archive)
+			return nil, err
+		}
+		dataset.Anonymized = true
+		dataset.MappingPath = mapping
+	}
+	dataset.GenerationTimeMs = time.Since(started).Milliseconds()
+	if rerun := s.queueRemediation(ctx, params, skippedClips); rerun != nil {
+		dataset.RerunJobID = &rerun.ID
+	}
+
+	if err := s.repo.Create(ctx, dataset); err != nil {
+		os.Remove(archive)
+		if dataset.MappingPath != "" {
+			os.Remove(dataset.MappingPath)
+		}
+		return nil, fmt.Errorf("recording dataset: %w", err)
+	}
+	return dataset, nil
+}
+
+// Get returns a dataset by ID
+func (s *service) Get(ctx context.Context, id string) (*models.Dataset, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns the most recent datasets first
+func (s *service) List(ctx context.Context, limit int) ([]models.Dataset, error) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+	return s.repo.List(ctx, limit)
+}
+
+// SignDownload returns an expiry and signature authorizing download of a dataset
+func (s *service) SignDownload(id string, ttl time.Duration) (time.Time, string) {
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+	return expires, s.sign(id, expires.Unix())
+}
+
+// VerifyDownload checks a signature produced by SignDownload
+func (s *service) VerifyDownload(id string, expires int64, signature string) error {
+	expected := s.sign(id, expires)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expires {
+		return ErrLinkExpired
+	}
+	return nil
+}
+
+func (s *service) sign(id string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("dataset:" + id + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// recordedFilters encodes the clip selection for Dataset.FiltersJSON, or returns
+// "" when every approved clip was exported
+func recordedFilters(params GenerateParams) string {
+	if !params.IncludeHardNegatives && reflect.ValueOf(params.Filters).IsZero() {
+		return ""
+	}
+	filters, _ := json.Marshal(Selection{params.IncludeHardNegatives, params.Filters})
+	return string(filters)
+}
+
+// readManifest parses the exporter's manifest; a missing manifest means no clips
+func readManifest(path string) ([]manifestEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening manifest: %w", err)
+	}
+	defer file.Close()
+
+	var entries []manifestEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry manifestEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parsing manifest line %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	return entries, nil
+}
+
+// zipDirectory writes every file under dir into a zip archive at target,
+// keeping paths relative to dir
+func zipDirectory(dir, target string) error {
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		header.Method = zip.Store // WAV audio barely compresses
+
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	})
+	if err != nil {
+		zw.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
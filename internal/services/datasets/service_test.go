@@ -0,0 +1,421 @@
+package datasets
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Dataset{}, &models.DatasetEvaluation{}, &models.Podcast{}, &models.Episode{}, &models.Clip{}, &models.PodcastRights{}))
+	return db
+}
+
+// fakeExporter writes a fixed manifest and one audio file per line
+type fakeExporter struct {
+	manifest string
+	skipped  []clips.SkippedClip
+	planned  []clips.PlannedClip
+	opts     clips.ExportOptions
+}
+
+func (f *fakeExporter) PlanExport(ctx context.Context, opts clips.ExportOptions) ([]clips.PlannedClip, error) {
+	f.opts = opts
+	return f.planned, nil
+}
+
+func (f *fakeExporter) ExportDataset(ctx context.Context, path string, opts clips.ExportOptions) ([]clips.SkippedClip, error) {
+	f.opts = opts
+	if f.manifest == "" {
+		return f.skipped, nil
+	}
+	if err := os.MkdirAll(filepath.Join(path, "advertisement"), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(path, "advertisement", "a.wav"), []byte("RIFF"), 0o644); err != nil {
+		return nil, err
+	}
+	return f.skipped, os.WriteFile(filepath.Join(path, models.DatasetMetadataFile(opts.Format)), []byte(f.manifest), 0o644)
+}
+
+func TestGenerate_RecordsStatsAndArchive(t *testing.T) {
+	dir := t.TempDir()
+	db := setupTestDB(t)
+	podcast := models.Podcast{PodcastIndexID: 42, Title: "Show", FeedURL: "https://example.com/feed.xml"}
+	require.NoError(t, db.Create(&podcast).Error)
+	require.NoError(t, db.Create(&models.Episode{PodcastID: podcast.ID, PodcastIndexID: 7, PodcastIndexFeedID: 42,
+		Title: "Ep", AudioURL: "https://example.com/a.mp3", FeedTitle: "Show", FeedLanguage: "en"}).Error)
+	require.NoError(t, db.Create(&models.PodcastRights{PodcastIndexFeedID: 42, License: "CC-BY-4.0", AllowTraining: true, AllowDerivatives: true}).Error)
+	for _, id := range []string{"c1", "c2"} {
+		require.NoError(t, db.Create(&models.Clip{UUID: id, PodcastIndexEpisodeID: 7, SourceEpisodeURL: "https://example.com/a.mp3", Label: "advertisement"}).Error)
+	}
+
+	exporter := &fakeExporter{manifest: `{"uuid":"c1","label":"advertisement","duration":10}
+{"uuid":"c2","label":"advertisement","duration":20,"hard_negative":true}
+`}
+	svc := NewService(NewRepository(db), exporter, dir, []byte("secret"), CardOptions{License: "CC-BY-4.0", TargetDuration: 15})
+
+	dataset, err := svc.Generate(context.Background(), GenerateParams{Name: "ads", IncludeHardNegatives: true})
+	require.NoError(t, err)
+
+	assert.True(t, exporter.opts.IncludeHardNegatives)
+	assert.Equal(t, models.DatasetFormatJSONL, dataset.Format)
+	assert.Equal(t, "ads", dataset.Name)
+	assert.Equal(t, 2, dataset.TotalSamples)
+	assert.Equal(t, 30.0, dataset.TotalDuration)
+	assert.Equal(t, 15.0, dataset.AverageDuration)
+	assert.Contains(t, dataset.FiltersJSON, "include_hard_negatives")
+
+	reader, err := zip.OpenReader(dataset.DatasetPath)
+	require.NoError(t, err)
+	defer reader.Close()
+	var names []string
+	files := make(map[string]*zip.File)
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+		files[f.Name] = f
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"advertisement/a.wav", "croissant.json", "info.json", "manifest.jsonl"}, names)
+
+	var info Info
+	readZipJSON(t, files["info.json"], &info)
+	assert.Equal(t, "CC-BY-4.0", info.License)
+	assert.Equal(t, LabelSummary{Samples: 2, HardNegatives: 1, DurationSeconds: 30}, info.Labels["advertisement"])
+	assert.Equal(t, []Source{{FeedID: 42, Title: "Show", FeedURL: "https://example.com/feed.xml", Language: "en", Clips: 2,
+		License: "CC-BY-4.0", AllowedUses: []string{models.RightsUseTraining, models.RightsUseDerivatives}}}, info.Sources)
+	assert.Equal(t, 16000, info.Processing.SampleRate)
+	assert.Equal(t, 15.0, info.Processing.TargetDuration)
+
+	var croissant map[string]interface{}
+	readZipJSON(t, files["croissant.json"], &croissant)
+	assert.Equal(t, "http://mlcommons.org/croissant/1.0", croissant["conformsTo"])
+	assert.Equal(t, "CC-BY-4.0", croissant["license"])
+	assert.Equal(t, []interface{}{"https://example.com/feed.xml"}, croissant["isBasedOn"])
+
+	stat, err := os.Stat(dataset.DatasetPath)
+	require.NoError(t, err)
+	assert.Equal(t, stat.Size(), dataset.TotalSize)
+
+	// Only the archive remains; staging is cleaned up
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	stored, err := svc.Get(context.Background(), dataset.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dataset.DatasetPath, stored.DatasetPath)
+}
+
+func readZipJSON(t *testing.T, f *zip.File, v interface{}) {
+	require.NotNil(t, f)
+	rc, err := f.Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	require.NoError(t, json.NewDecoder(rc).Decode(v))
+}
+
+func TestGenerate_AudioFolderFormat(t *testing.T) {
+	exporter := &fakeExporter{manifest: `{"file_name":"advertisement/a.wav","label":"advertisement","duration":12}
+`}
+	svc := NewService(NewRepository(setupTestDB(t)), exporter, t.TempDir(), []byte("secret"), CardOptions{})
+
+	dataset, err := svc.Generate(context.Background(), GenerateParams{Format: models.DatasetFormatAudioFolder})
+	require.NoError(t, err)
+	assert.Equal(t, models.DatasetFormatAudioFolder, exporter.opts.Format)
+	assert.Equal(t, models.DatasetFormatAudioFolder, dataset.Format)
+	assert.Equal(t, 1, dataset.TotalSamples)
+
+	_, err = svc.Generate(context.Background(), GenerateParams{Format: "parquet"})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestGenerate_ToolkitFormats(t *testing.T) {
+	manifest := `{"uuid":"c2","file_path":"advertisement/a.wav","label":"advertisement","duration":1.5,"transcript_text":"buy  now\nplease"}
+{"uuid":"c1","file_path":"advertisement/a.wav","label":"advertisement","duration":2,"hard_negative":true}
+`
+	readZip := func(t *testing.T, archive string) map[string]string {
+		reader, err := zip.OpenReader(archive)
+		require.NoError(t, err)
+		defer reader.Close()
+		files := make(map[string]string)
+		for _, f := range reader.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			files[f.Name] = string(data)
+		}
+		return files
+	}
+
+	exporter := &fakeExporter{manifest: manifest}
+	svc := NewService(NewRepository(setupTestDB(t)), exporter, t.TempDir(), []byte("secret"), CardOptions{})
+
+	dataset, err := svc.Generate(context.Background(), GenerateParams{Format: models.DatasetFormatKaldi})
+	require.NoError(t, err)
+	assert.Equal(t, models.DatasetFormatJSONL, exporter.opts.Format, "the exporter writes the JSONL manifest")
+	assert.Equal(t, models.DatasetFormatKaldi, dataset.Format)
+	assert.Equal(t, 2, dataset.TotalSamples)
+
+	files := readZip(t, dataset.DatasetPath)
+	assert.Contains(t, files, models.DatasetManifestFile)
+	assert.Equal(t, "c1 advertisement/a.wav\nc2 advertisement/a.wav\n", files["kaldi/wav.scp"], "sorted by utterance ID")
+	assert.Equal(t, "c1 c1 0.000 2.000\nc2 c2 0.000 1.500\n", files["kaldi/segments"])
+	assert.Equal(t, "c1 c1\nc2 c2\n", files["kaldi/utt2spk"])
+	assert.Equal(t, "c1 advertisement\nc2 advertisement\n", files["kaldi/utt2label"])
+	assert.Equal(t, "c1 1\nc2 0\n", files["kaldi/utt2hard_negative"])
+	assert.Equal(t, "c2 buy now please\n", files["kaldi/text"], "only utterances with a transcript, on one line")
+
+	dataset, err = svc.Generate(context.Background(), GenerateParams{Format: models.DatasetFormatESPnet})
+	require.NoError(t, err)
+	files = readZip(t, dataset.DatasetPath)
+	assert.NotContains(t, files, "kaldi/wav.scp")
+
+	var espnet struct {
+		Utts map[string]espnetUtterance `json:"utts"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(files[espnetManifest]), &espnet))
+	require.Len(t, espnet.Utts, 2)
+	utt := espnet.Utts["c2"]
+	assert.Equal(t, []espnetInput{{Name: "input1", Feat: "advertisement/a.wav", Filetype: "sound", Shape: []int{24000, 1}}}, utt.Input)
+	assert.Equal(t, []espnetOutput{{Name: "target1", Text: "buy now please", Label: "advertisement"}}, utt.Output)
+	assert.Equal(t, "c2", utt.Utt2Spk)
+	assert.True(t, espnet.Utts["c1"].Output[0].HardNegative)
+}
+
+func TestGenerate_Anonymize(t *testing.T) {
+	dir := t.TempDir()
+	db := setupTestDB(t)
+	podcast := models.Podcast{PodcastIndexID: 42, Title: "Show", FeedURL: "https://example.com/feed.xml"}
+	require.NoError(t, db.Create(&podcast).Error)
+	require.NoError(t, db.Create(&models.Episode{PodcastID: podcast.ID, PodcastIndexID: 7, PodcastIndexFeedID: 42,
+		Title: "Ep", AudioURL: "https://example.com/a.mp3", FeedTitle: "Show", FeedLanguage: "en"}).Error)
+	for _, id := range []string{"c1", "c2"} {
+		require.NoError(t, db.Create(&models.Clip{UUID: id, PodcastIndexEpisodeID: 7, SourceEpisodeURL: "https://example.com/a.mp3", Label: "advertisement"}).Error)
+	}
+
+	exporter := &fakeExporter{
+		manifest: `{"file_path":"advertisement/a.wav","label":"advertisement","duration":10,"source_url":"https://example.com/a.mp3","uuid":"c1","transcript_text":"buy now","license":"Show Network license","fair_use_note":"Quoted from Show for commentary"}
+`,
+		skipped: []clips.SkippedClip{{UUID: "c2", Label: "advertisement", EpisodeID: 7, Reason: clips.ExtractionFailed, Error: "fetching https://example.com/a.mp3"}},
+	}
+	svc := NewService(NewRepository(db), exporter, dir, []byte("secret"), CardOptions{})
+
+	dataset, err := svc.Generate(context.Background(), GenerateParams{Name: "shared", Anonymize: true})
+	require.NoError(t, err)
+	assert.True(t, dataset.Anonymized)
+	assert.Contains(t, dataset.SkippedJSON, "c2", "the stored skip report is kept whole for remediation")
+
+	reader, err := zip.OpenReader(dataset.DatasetPath)
+	require.NoError(t, err)
+	defer reader.Close()
+	files := make(map[string]*zip.File)
+	for _, f := range reader.File {
+		files[f.Name] = f
+		body, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		body.Close()
+		require.NoError(t, err)
+		for _, identifying := range []string{"example.com", "Show", `"c1"`, `"c2"`} {
+			assert.NotContains(t, string(data), identifying, f.Name)
+		}
+	}
+
+	rc, err := files["manifest.jsonl"].Open()
+	require.NoError(t, err)
+	var row map[string]interface{}
+	require.NoError(t, json.NewDecoder(rc).Decode(&row))
+	rc.Close()
+	clipID := row["uuid"].(string)
+	assert.Len(t, clipID, 16)
+	assert.NotContains(t, row, "source_url")
+	assert.NotContains(t, row, "fair_use_note", "fair use notes may name the podcast")
+	assert.NotContains(t, row, "license", "licenses are listed per pseudonymous podcast in the card")
+	assert.Equal(t, "advertisement/clip_"+clipID+".wav", row["file_path"])
+	assert.NotNil(t, files[row["file_path"].(string)], "the audio file is renamed to match")
+	assert.Equal(t, "buy now", row["transcript_text"])
+
+	var info Info
+	readZipJSON(t, files["info.json"], &info)
+	assert.True(t, info.Anonymized)
+	require.Len(t, info.Sources, 1)
+	assert.Equal(t, Source{ID: row["podcast_id"].(string), Language: "en", Clips: 1}, info.Sources[0])
+	require.Len(t, info.Skipped, 1)
+	assert.Empty(t, info.Skipped[0].Error)
+
+	var mapping Mapping
+	data, err := os.ReadFile(dataset.MappingPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &mapping))
+	assert.Equal(t, ClipOrigin{UUID: "c1", EpisodeID: 7, FeedID: 42, SourceURL: "https://example.com/a.mp3"}, mapping.Clips[clipID])
+	assert.Equal(t, int64(7), mapping.Episodes[row["episode_id"].(string)])
+	assert.Equal(t, "https://example.com/feed.xml", mapping.Podcasts[info.Sources[0].ID].FeedURL)
+	assert.Contains(t, mapping.Clips, info.Skipped[0].UUID)
+
+	// Another dataset of the same clips gets other IDs
+	again, err := svc.Generate(context.Background(), GenerateParams{Anonymize: true})
+	require.NoError(t, err)
+	data, err = os.ReadFile(again.MappingPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), clipID)
+}
+
+func TestGenerate_EmptyDataset(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewService(NewRepository(setupTestDB(t)), &fakeExporter{}, dir, []byte("secret"), CardOptions{})
+
+	_, err := svc.Generate(context.Background(), GenerateParams{})
+	assert.ErrorIs(t, err, ErrEmptyDataset)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDryRun_ReportsWithoutWriting(t *testing.T) {
+	dir := t.TempDir()
+	exporter := &fakeExporter{planned: []clips.PlannedClip{
+		{UUID: "c1", Label: "advertisement", Duration: 15, SizeBytes: 480044, Extracted: true},
+		{UUID: "c2", Label: "advertisement", Duration: 8, HardNegative: true},
+		{UUID: "c3", Label: "music", Duration: 30},
+		{UUID: "c4", Label: "music", EpisodeID: 9, Extracted: true, Missing: clips.MissingClipFile},
+	}}
+	svc := NewService(NewRepository(setupTestDB(t)), exporter, dir, []byte("secret"), CardOptions{TargetDuration: 15})
+
+	report, err := svc.DryRun(context.Background(), GenerateParams{
+		IncludeHardNegatives: true,
+		Filters:              clips.ExportFilters{Labels: []string{"advertisement", "music"}},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, exporter.opts.IncludeHardNegatives)
+	assert.Equal(t, []string{"advertisement", "music"}, exporter.opts.Filters.Labels)
+	assert.Equal(t, models.DatasetFormatJSONL, report.Format)
+	assert.Equal(t, 3, report.TotalSamples)
+	assert.Equal(t, 45.0, report.TotalDuration)
+	assert.Equal(t, 2, report.ToExtract)
+	assert.Equal(t, int64(480044*3), report.EstimatedSize)
+	assert.Equal(t, LabelSummary{Samples: 2, HardNegatives: 1, DurationSeconds: 30}, report.Labels["advertisement"])
+	assert.Equal(t, LabelSummary{Samples: 1, DurationSeconds: 15}, report.Labels["music"])
+	assert.Equal(t, []MissingAudio{{UUID: "c4", Label: "music", EpisodeID: 9, Reason: clips.MissingClipFile}}, report.MissingAudio)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	datasets, err := svc.List(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, datasets)
+
+	_, err = svc.DryRun(context.Background(), GenerateParams{Format: "parquet"})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestGet_NotFound(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)), &fakeExporter{}, t.TempDir(), []byte("secret"), CardOptions{})
+
+	_, err := svc.Get(context.Background(), "ds-missing")
+	assert.ErrorIs(t, err, ErrDatasetNotFound)
+}
+
+func TestSignedDownload(t *testing.T) {
+	svc := NewService(nil, nil, t.TempDir(), []byte("secret"), CardOptions{})
+
+	expires, sig := svc.SignDownload("ds-1", time.Hour)
+	assert.NoError(t, svc.VerifyDownload("ds-1", expires.Unix(), sig))
+	assert.ErrorIs(t, svc.VerifyDownload("ds-2", expires.Unix(), sig), ErrInvalidSignature)
+	assert.ErrorIs(t, svc.VerifyDownload("ds-1", expires.Unix()+60, sig), ErrInvalidSignature)
+
+	other := NewService(nil, nil, t.TempDir(), []byte("other"), CardOptions{})
+	assert.ErrorIs(t, other.VerifyDownload("ds-1", expires.Unix(), sig), ErrInvalidSignature)
+
+	past, stale := svc.SignDownload("ds-1", -time.Minute)
+	assert.ErrorIs(t, svc.VerifyDownload("ds-1", past.Unix(), stale), ErrLinkExpired)
+}
+
+type fakeQueue struct {
+	nextID uint
+	unique []int64
+	reruns []models.JobPayload
+}
+
+func (q *fakeQueue) EnqueueUniqueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, uniqueKey string, opts ...jobs.JobOption) (*models.Job, error) {
+	q.nextID++
+	q.unique = append(q.unique, payload[uniqueKey].(int64))
+	return &models.Job{Model: gorm.Model{ID: q.nextID}, Type: jobType}, nil
+}
+
+func (q *fakeQueue) EnqueueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, opts ...jobs.JobOption) (*models.Job, error) {
+	q.nextID++
+	q.reruns = append(q.reruns, payload)
+	return &models.Job{Model: gorm.Model{ID: q.nextID}, Type: jobType, Payload: payload}, nil
+}
+
+func TestGenerate_QueuesCachingForSkippedClips(t *testing.T) {
+	exporter := &fakeExporter{
+		manifest: `{"uuid":"c1","label":"advertisement","duration":10}
+`,
+		skipped: []clips.SkippedClip{
+			{UUID: "c2", Label: "advertisement", EpisodeID: 7, Reason: clips.MissingSource},
+			{UUID: "c3", Label: "advertisement", EpisodeID: 7, Reason: clips.ExtractionFailed, Error: "ffmpeg exited 1"},
+			{UUID: "c4", Label: "music", EpisodeID: 8, Reason: clips.MissingSource},
+			{UUID: "c5", Label: "music", EpisodeID: 9, Extracted: true, Reason: clips.MissingClipFile},
+		},
+	}
+	queue := &fakeQueue{}
+	svc := NewService(NewRepository(setupTestDB(t)), exporter, t.TempDir(), []byte("secret"), CardOptions{}, WithJobQueue(queue))
+
+	// Without the option the skipped clips are only reported
+	dataset, err := svc.Generate(context.Background(), GenerateParams{Name: "ads"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, dataset.SkippedSamples)
+	assert.Contains(t, dataset.SkippedJSON, "ffmpeg exited 1")
+	assert.Nil(t, dataset.RerunJobID)
+	assert.Empty(t, queue.unique)
+
+	dataset, err = svc.Generate(context.Background(), GenerateParams{Name: "ads", CacheMissingAudio: true})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{7, 8}, queue.unique)
+	require.Len(t, queue.reruns, 1)
+	assert.Equal(t, "ads", queue.reruns[0]["name"])
+	assert.NotContains(t, queue.reruns[0], "cache_missing_audio")
+	require.NotNil(t, dataset.RerunJobID)
+	assert.Equal(t, uint(3), *dataset.RerunJobID)
+
+	stored, err := svc.Get(context.Background(), dataset.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dataset.RerunJobID, stored.RerunJobID)
+}
+
+func TestGenerate_EmptyDatasetQueuesRerun(t *testing.T) {
+	exporter := &fakeExporter{skipped: []clips.SkippedClip{
+		{UUID: "c1", Label: "advertisement", EpisodeID: 7, Reason: clips.MissingSource},
+	}}
+	svc := NewService(NewRepository(setupTestDB(t)), exporter, t.TempDir(), []byte("secret"), CardOptions{}, WithJobQueue(&fakeQueue{}))
+
+	_, err := svc.Generate(context.Background(), GenerateParams{CacheMissingAudio: true})
+	assert.ErrorIs(t, err, ErrEmptyDataset)
+	assert.Contains(t, err.Error(), "regeneration queued as job 2")
+}
@@ -0,0 +1,180 @@
+package datasets
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+)
+
+// Files the speech toolkit formats add beside manifest.jsonl
+const (
+	kaldiDir       = "kaldi"
+	espnetManifest = "data.json"
+)
+
+// toolkitUtterance is one clip as the speech toolkits see it. Each clip is its
+// own recording and, with no speaker information, its own speaker; the
+// utterance ID is the clip's (possibly pseudonymous) UUID.
+type toolkitUtterance struct {
+	ID           string
+	Path         string // Relative to the archive root
+	Label        string
+	HardNegative bool
+	Duration     float64
+	Text         string // Transcript text on one line; empty when unknown
+}
+
+// toolkitUtterances reads the final manifest in dir, sorted by utterance ID as
+// Kaldi requires (byte order, as with LC_ALL=C sort)
+func toolkitUtterances(dir string) ([]toolkitUtterance, error) {
+	entries, err := readManifest(filepath.Join(dir, models.DatasetManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	utterances := make([]toolkitUtterance, 0, len(entries))
+	for _, entry := range entries {
+		utterance := toolkitUtterance{
+			ID:           entry.UUID,
+			Path:         entry.FilePath,
+			Label:        entry.Label,
+			HardNegative: entry.HardNegative,
+			Duration:     entry.Duration,
+		}
+		if entry.TranscriptText != nil {
+			utterance.Text = strings.Join(strings.Fields(*entry.TranscriptText), " ")
+		}
+		utterances = append(utterances, utterance)
+	}
+	sort.Slice(utterances, func(i, j int) bool { return utterances[i].ID < utterances[j].ID })
+	return utterances, nil
+}
+
+// writeToolkitFiles adds the files of a speech toolkit format to dir, from
+// the manifest as it will be archived; other formats need none
+func writeToolkitFiles(dir, format string) error {
+	if format != models.DatasetFormatKaldi && format != models.DatasetFormatESPnet {
+		return nil
+	}
+	utterances, err := toolkitUtterances(dir)
+	if err != nil {
+		return err
+	}
+	if format == models.DatasetFormatKaldi {
+		return writeKaldi(filepath.Join(dir, kaldiDir), utterances)
+	}
+	return writeESPnet(filepath.Join(dir, espnetManifest), utterances)
+}
+
+// writeKaldi writes a Kaldi data directory. segments spans each whole clip
+// file; text has only the utterances with a transcript, so label-only datasets
+// validate with utils/validate_data_dir.sh --no-text. The label, and whether
+// the clip is a hard negative, go in utt2label and utt2hard_negative.
+func writeKaldi(dir string, utterances []toolkitUtterance) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating kaldi directory: %w", err)
+	}
+
+	hardNegatives := false
+	for _, u := range utterances {
+		hardNegatives = hardNegatives || u.HardNegative
+	}
+
+	files := []kaldiFile{
+		{"wav.scp", func(u toolkitUtterance) string { return u.ID + " " + u.Path }},
+		{"segments", func(u toolkitUtterance) string { return fmt.Sprintf("%s %s 0.000 %.3f", u.ID, u.ID, u.Duration) }},
+		{"utt2spk", func(u toolkitUtterance) string { return u.ID + " " + u.ID }},
+		{"spk2utt", func(u toolkitUtterance) string { return u.ID + " " + u.ID }},
+		{"utt2dur", func(u toolkitUtterance) string { return fmt.Sprintf("%s %.3f", u.ID, u.Duration) }},
+		{"utt2label", func(u toolkitUtterance) string { return u.ID + " " + u.Label }},
+		{"text", func(u toolkitUtterance) string {
+			if u.Text == "" {
+				return ""
+			}
+			return u.ID + " " + u.Text
+		}},
+	}
+	if hardNegatives {
+		files = append(files, kaldiFile{"utt2hard_negative", func(u toolkitUtterance) string {
+			if u.HardNegative {
+				return u.ID + " 1"
+			}
+			return u.ID + " 0"
+		}})
+	}
+
+	for _, f := range files {
+		if err := writeLines(filepath.Join(dir, f.name), utterances, f.line); err != nil {
+			return fmt.Errorf("writing kaldi %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// kaldiFile is one file of a Kaldi data directory, with one line per utterance
+type kaldiFile struct {
+	name string
+	line func(u toolkitUtterance) string // "" leaves the utterance out
+}
+
+func writeLines(path string, utterances []toolkitUtterance, line func(u toolkitUtterance) string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, u := range utterances {
+		if l := line(u); l != "" {
+			w.WriteString(l)
+			w.WriteByte('\n')
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// espnetInput and espnetOutput are the entries of an ESPnet data.json utterance
+type espnetInput struct {
+	Name     string `json:"name"`
+	Feat     string `json:"feat"`
+	Filetype string `json:"filetype"`
+	Shape    []int  `json:"shape"` // Samples, channels
+}
+
+type espnetOutput struct {
+	Name         string `json:"name"`
+	Text         string `json:"text"`
+	Label        string `json:"label"`
+	HardNegative bool   `json:"hard_negative"`
+}
+
+type espnetUtterance struct {
+	Input   []espnetInput  `json:"input"`
+	Output  []espnetOutput `json:"output"`
+	Utt2Spk string         `json:"utt2spk"`
+}
+
+// writeESPnet writes an ESPnet data.json: one input reading the clip's WAV
+// file as sound, and one output carrying its transcript text and label
+func writeESPnet(path string, utterances []toolkitUtterance) error {
+	utts := make(map[string]espnetUtterance, len(utterances))
+	for _, u := range utterances {
+		samples := int(math.Round(u.Duration * clips.ClipSampleRate))
+		utts[u.ID] = espnetUtterance{
+			Input:   []espnetInput{{Name: "input1", Feat: u.Path, Filetype: "sound", Shape: []int{samples, clips.ClipChannels}}},
+			Output:  []espnetOutput{{Name: "target1", Text: u.Text, Label: u.Label, HardNegative: u.HardNegative}},
+			Utt2Spk: u.ID,
+		}
+	}
+	return writeJSON(path, map[string]interface{}{"utts": utts})
+}
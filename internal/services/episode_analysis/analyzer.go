@@ -2,12 +2,13 @@ package episodeanalysis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os/exec"
 	"regexp"
 	"strconv"
-	"strings"
+
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 )
 
 // VolumeSpike represents a detected volume anomaly
@@ -19,14 +20,16 @@ type VolumeSpike struct {
 
 // VolumeAnalyzer scans audio files for volume spikes
 type VolumeAnalyzer struct {
-	thresholdDB float64 // dB above baseline to consider a spike
-	minDuration float64 // Minimum spike duration in seconds
-	segmentSize float64 // Segment size for analysis in seconds
+	ffmpeg      *ffmpeg.FFmpeg // Runs every process under the configured resource limits
+	thresholdDB float64        // dB above baseline to consider a spike
+	minDuration float64        // Minimum spike duration in seconds
+	segmentSize float64        // Segment size for analysis in seconds
 }
 
 // NewVolumeAnalyzer creates a new analyzer with default settings
-func NewVolumeAnalyzer() *VolumeAnalyzer {
+func NewVolumeAnalyzer(ffmpegInstance *ffmpeg.FFmpeg) *VolumeAnalyzer {
 	return &VolumeAnalyzer{
+		ffmpeg:      ffmpegInstance,
 		thresholdDB: 20.0, // 20dB above baseline - catches exceptional spikes only
 		minDuration: 5.0,  // 5 second minimum
 		segmentSize: 5.0,  // Analyze in 5-second chunks
@@ -102,6 +105,10 @@ func (a *VolumeAnalyzer) analyzeSegments(ctx context.Context, audioPath string,
 
 		// Analyze this specific segment
 		segMean, segMax, err := a.getSegmentVolume(ctx, audioPath, startTime, endTime)
+		if errors.Is(err, ffmpeg.ErrResourceLimit) {
+			// The next segment would be killed the same way
+			return nil, err
+		}
 		if err != nil {
 			log.Printf("[WARN] Failed to analyze segment %.2f-%.2f: %v", startTime, endTime, err)
 			// Use overall stats as fallback
@@ -124,7 +131,7 @@ func (a *VolumeAnalyzer) analyzeSegments(ctx context.Context, audioPath string,
 func (a *VolumeAnalyzer) getSegmentVolume(ctx context.Context, audioPath string, startTime, endTime float64) (float64, float64, error) {
 	duration := endTime - startTime
 
-	cmd := exec.CommandContext(ctx, "ffmpeg",
+	output, err := a.ffmpeg.Run(ctx,
 		"-ss", fmt.Sprintf("%.2f", startTime),
 		"-t", fmt.Sprintf("%.2f", duration),
 		"-i", audioPath,
@@ -132,30 +139,26 @@ func (a *VolumeAnalyzer) getSegmentVolume(ctx context.Context, audioPath string,
 		"-f", "null",
 		"-",
 	)
-
-	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return 0, 0, fmt.Errorf("ffmpeg failed: %w", err)
 	}
 
-	return a.parseVolumeOutput(string(output))
+	return a.parseVolumeOutput(output)
 }
 
 // getVolumeStats gets overall volume statistics for the entire file
 func (a *VolumeAnalyzer) getVolumeStats(ctx context.Context, audioPath string) (float64, float64, error) {
-	cmd := exec.CommandContext(ctx, "ffmpeg",
+	output, err := a.ffmpeg.Run(ctx,
 		"-i", audioPath,
 		"-af", "volumedetect",
 		"-f", "null",
 		"-",
 	)
-
-	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return 0, 0, fmt.Errorf("ffmpeg failed: %w", err)
 	}
 
-	return a.parseVolumeOutput(string(output))
+	return a.parseVolumeOutput(output)
 }
 
 // parseVolumeOutput extracts volume statistics from FFmpeg output
@@ -186,25 +189,7 @@ func (a *VolumeAnalyzer) parseVolumeOutput(output string) (float64, float64, err
 
 // getAudioDuration gets the duration of an audio file
 func (a *VolumeAnalyzer) getAudioDuration(ctx context.Context, audioPath string) (float64, error) {
-	cmd := exec.CommandContext(ctx, "ffprobe",
-		"-v", "error",
-		"-show_entries", "format=duration",
-		"-of", "default=noprint_wrappers=1:nokey=1",
-		audioPath,
-	)
-
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, fmt.Errorf("ffprobe failed: %w", err)
-	}
-
-	durationStr := strings.TrimSpace(string(output))
-	duration, err := strconv.ParseFloat(durationStr, 64)
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse duration: %w", err)
-	}
-
-	return duration, nil
+	return a.ffmpeg.Duration(ctx, audioPath)
 }
 
 // calculateBaseline calculates the baseline volume (median)
@@ -0,0 +1,99 @@
+package episodeanalysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// VolumeSpikeModel is the registry name of the built-in volume spike detector
+const VolumeSpikeModel = "volume-spike"
+
+// ErrBackendUnavailable is returned when a model's backend can't be run by this server
+var ErrBackendUnavailable = errors.New("detector backend not available")
+
+// Detection is a segment of an episode a detector found, with the label it
+// predicts for it
+type Detection struct {
+	Start float64  `json:"start"`           // Seconds from the start of the episode
+	End   float64  `json:"end"`             // Seconds from the start of the episode
+	Label string   `json:"label"`           // One of the model's labels
+	Score *float64 `json:"score,omitempty"` // Confidence in Label, 0 to 1
+}
+
+// Detector finds labeled segments in an episode's processed audio
+type Detector interface {
+	Detect(ctx context.Context, episodeID int64, audioPath string) ([]Detection, error)
+}
+
+// BuiltinModels returns the registry entries of the detectors compiled into
+// the server, the default first
+func BuiltinModels() []models.DetectorModel {
+	return []models.DetectorModel{{
+		Name:    VolumeSpikeModel,
+		Version: "1",
+		Backend: models.DetectorBackendBuiltin,
+		Labels:  []string{VolumeSpikeLabel},
+	}}
+}
+
+// detectorFor returns the detector that runs model
+func (s *serviceImpl) detectorFor(model *models.DetectorModel) (Detector, error) {
+	switch model.Backend {
+	case models.DetectorBackendBuiltin:
+		if model.Name == VolumeSpikeModel {
+			return volumeSpikeDetector{s.analyzer}, nil
+		}
+	case models.DetectorBackendLocal:
+		return commandDetector{path: model.LocalPath}, nil
+	case models.DetectorBackendHTTP:
+		return NewRemoteDetector(model, s.httpClient), nil
+	}
+	return nil, fmt.Errorf("%w: %s uses %s", ErrBackendUnavailable, model.Identity(), model.Backend)
+}
+
+// volumeSpikeDetector reports the VolumeAnalyzer's spikes as detections
+type volumeSpikeDetector struct {
+	analyzer *VolumeAnalyzer
+}
+
+func (d volumeSpikeDetector) Detect(ctx context.Context, _ int64, audioPath string) ([]Detection, error) {
+	spikes, err := d.analyzer.FindSpikes(ctx, audioPath)
+	if err != nil {
+		return nil, err
+	}
+	detections := make([]Detection, 0, len(spikes))
+	for _, spike := range spikes {
+		detections = append(detections, Detection{Start: spike.StartTime, End: spike.EndTime, Label: VolumeSpikeLabel})
+	}
+	return detections, nil
+}
+
+// commandDetector runs a local model as an executable given the audio file's
+// path as its only argument, reading an InferenceResponse from its stdout
+// with times from the start of the episode
+type commandDetector struct {
+	path string
+}
+
+func (d commandDetector) Detect(ctx context.Context, _ int64, audioPath string) ([]Detection, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, d.path, audioPath)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running %s: %w: %s", d.path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var output InferenceResponse
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("parsing output of %s: %w", d.path, err)
+	}
+	return output.Segments, nil
+}
@@ -0,0 +1,245 @@
+package episodeanalysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Defaults for http models whose registry entry leaves them unset
+const (
+	DefaultChunkSeconds   = 30.0
+	DefaultRequestTimeout = 60 * time.Second
+)
+
+const (
+	// FeatureFrameRate is the number of feature frames per second of audio
+	FeatureFrameRate = 100
+
+	maxInferenceResponse = 4 << 20 // Bytes of a model's response to one chunk
+	segmentJoinTolerance = 0.05    // Seconds between segments still joined across chunks
+)
+
+// InferenceRequest is what RemoteDetector POSTs as JSON to a model's endpoint
+// for each chunk of an episode, in order. Exactly one of Audio and Features
+// is set, depending on the model's input.
+type InferenceRequest struct {
+	Model     string             `json:"model"`
+	Version   string             `json:"version"`
+	EpisodeID int64              `json:"episode_id"`
+	Labels    []string           `json:"labels"` // The labels registered for the model
+	Chunk     InferenceChunk     `json:"chunk"`
+	Audio     string             `json:"audio,omitempty"` // Base64 of the chunk as 16-bit PCM WAV
+	Features  *InferenceFeatures `json:"features,omitempty"`
+}
+
+// InferenceChunk locates a chunk in the episode
+type InferenceChunk struct {
+	Index      int     `json:"index"`
+	Offset     float64 `json:"offset"`   // Seconds from the start of the episode
+	Duration   float64 `json:"duration"` // Seconds; the last chunk may be shorter
+	SampleRate int     `json:"sample_rate"`
+	Channels   int     `json:"channels"`
+}
+
+// InferenceFeatures describes a chunk by its loudness over time
+type InferenceFeatures struct {
+	FrameRate int       `json:"frame_rate"` // Frames per second
+	LevelsDB  []float64 `json:"levels_db"`  // RMS level of each frame in dBFS
+}
+
+// InferenceResponse is what a model's endpoint answers with. Segment times
+// are seconds from the start of the chunk; an empty list means nothing found.
+type InferenceResponse struct {
+	Segments []Detection `json:"segments"`
+}
+
+// RemoteDetector runs a model served over HTTP: it splits an episode's
+// processed audio into chunks and POSTs each to the model's endpoint as an
+// InferenceRequest, expecting an InferenceResponse with a 2xx status.
+// Segments of a label that run on across chunk boundaries are joined.
+type RemoteDetector struct {
+	model   *models.DetectorModel
+	client  *http.Client
+	token   string
+	chunk   float64
+	timeout time.Duration
+}
+
+// NewRemoteDetector creates a detector for an http model. A token read from
+// the model's TokenEnv is sent as a bearer token.
+func NewRemoteDetector(model *models.DetectorModel, client *http.Client) *RemoteDetector {
+	d := &RemoteDetector{
+		model:   model,
+		client:  client,
+		chunk:   DefaultChunkSeconds,
+		timeout: DefaultRequestTimeout,
+	}
+	if model.ChunkSeconds > 0 {
+		d.chunk = model.ChunkSeconds
+	}
+	if model.TimeoutSeconds > 0 {
+		d.timeout = time.Duration(model.TimeoutSeconds) * time.Second
+	}
+	if model.TokenEnv != "" {
+		d.token = os.Getenv(model.TokenEnv)
+	}
+	return d
+}
+
+// Detect sends the audio chunk by chunk and collects the segments found
+func (d *RemoteDetector) Detect(ctx context.Context, episodeID int64, audioPath string) ([]Detection, error) {
+	pcm, err := openPCM(audioPath)
+	if err != nil {
+		return nil, err
+	}
+	defer pcm.Close()
+
+	frames := int(math.Round(d.chunk * float64(pcm.sampleRate)))
+	buf := make([]byte, frames*pcm.frameBytes())
+	var detections []Detection
+	for index := 0; ; index++ {
+		n, err := io.ReadFull(pcm.data, buf)
+		if n == 0 && (err == io.EOF || err == io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("reading audio: %w", err)
+		}
+		samples := buf[:n-n%pcm.frameBytes()]
+		if len(samples) == 0 {
+			break
+		}
+
+		request := InferenceRequest{
+			Model:     d.model.Name,
+			Version:   d.model.Version,
+			EpisodeID: episodeID,
+			Labels:    d.model.Labels,
+			Chunk: InferenceChunk{
+				Index:      index,
+				Offset:     float64(index*frames) / float64(pcm.sampleRate),
+				Duration:   float64(len(samples)/pcm.frameBytes()) / float64(pcm.sampleRate),
+				SampleRate: pcm.sampleRate,
+				Channels:   pcm.channels,
+			},
+		}
+		if d.model.Input == models.DetectorInputFeatures {
+			request.Features = &InferenceFeatures{
+				FrameRate: FeatureFrameRate,
+				LevelsDB:  frameLevels(samples, pcm.sampleRate, pcm.channels, FeatureFrameRate),
+			}
+		} else {
+			request.Audio = base64.StdEncoding.EncodeToString(encodeWAV(samples, pcm.sampleRate, pcm.channels))
+		}
+
+		segments, err := d.infer(ctx, &request)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d at %.1fs: %w", index, request.Chunk.Offset, err)
+		}
+		for _, segment := range segments {
+			start := math.Max(segment.Start, 0)
+			end := math.Min(segment.End, request.Chunk.Duration)
+			if end <= start {
+				continue
+			}
+			segment.Start, segment.End = request.Chunk.Offset+start, request.Chunk.Offset+end
+			detections = append(detections, segment)
+		}
+		if len(samples) < len(buf) {
+			break
+		}
+	}
+	return joinDetections(detections), nil
+}
+
+// infer sends one chunk and returns the segments in its response
+func (d *RemoteDetector) infer(ctx context.Context, request *InferenceRequest) ([]Detection, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.model.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxInferenceResponse+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet := strings.TrimSpace(string(data[:min(len(data), 200)]))
+		return nil, fmt.Errorf("endpoint returned HTTP %d: %s", resp.StatusCode, snippet)
+	}
+	if len(data) > maxInferenceResponse {
+		return nil, errors.New("response is larger than 4MB")
+	}
+
+	var response InferenceResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	return response.Segments, nil
+}
+
+// joinDetections merges segments of the same label that overlap or touch,
+// as a segment running over a chunk boundary comes back in two halves. The
+// score of a joined segment is the duration-weighted mean of the scored parts.
+func joinDetections(detections []Detection) []Detection {
+	sort.SliceStable(detections, func(i, j int) bool { return detections[i].Start < detections[j].Start })
+
+	var joined []Detection
+	open := make(map[string]int) // Label -> index in joined of its latest segment
+	for _, d := range detections {
+		i, ok := open[d.Label]
+		if !ok || d.Start > joined[i].End+segmentJoinTolerance {
+			open[d.Label] = len(joined)
+			joined = append(joined, d)
+			continue
+		}
+		last := &joined[i]
+		last.Score = weightedScore(last, &d)
+		last.End = math.Max(last.End, d.End)
+	}
+	return joined
+}
+
+// weightedScore averages the scores of two segments by their durations
+func weightedScore(a, b *Detection) *float64 {
+	switch {
+	case a.Score == nil:
+		return b.Score
+	case b.Score == nil:
+		return a.Score
+	}
+	wa, wb := a.End-a.Start, b.End-b.Start
+	score := (*a.Score*wa + *b.Score*wb) / (wa + wb)
+	return &score
+}
@@ -0,0 +1,123 @@
+package episodeanalysis
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestWAV writes seconds of a constant 16kHz mono signal
+func writeTestWAV(t *testing.T, seconds float64) string {
+	samples := make([]byte, int(seconds*16000)*2)
+	for i := 0; i < len(samples); i += 2 {
+		binary.LittleEndian.PutUint16(samples[i:], uint16(int16(16384))) // Half scale: -6.02 dBFS
+	}
+	path := filepath.Join(t.TempDir(), "processed.wav")
+	require.NoError(t, os.WriteFile(path, encodeWAV(samples, 16000, 1), 0o644))
+	return path
+}
+
+func TestRemoteDetector_ChunksAndJoins(t *testing.T) {
+	var mu sync.Mutex
+	var requests []InferenceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var req InferenceRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+
+		// An ad running from 0.5s in the first chunk into the second, and music
+		// in the last chunk reaching past its end
+		score := 0.9
+		response := InferenceResponse{Segments: []Detection{}}
+		switch req.Chunk.Index {
+		case 0:
+			response.Segments = append(response.Segments, Detection{Start: 0.5, End: 1, Label: "advertisement", Score: &score})
+		case 1:
+			low := 0.6
+			response.Segments = append(response.Segments, Detection{Start: 0, End: 0.5, Label: "advertisement", Score: &low})
+		case 2:
+			response.Segments = append(response.Segments, Detection{Start: 0.1, End: 5, Label: "music"})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_MODEL_TOKEN", "secret")
+	model := &models.DetectorModel{
+		Name: "ads", Version: "3", Backend: models.DetectorBackendHTTP, Endpoint: server.URL,
+		Labels: []string{"advertisement", "music"}, ChunkSeconds: 1, TokenEnv: "TEST_MODEL_TOKEN",
+	}
+	detections, err := NewRemoteDetector(model, server.Client()).Detect(context.Background(), 77, writeTestWAV(t, 2.5))
+	require.NoError(t, err)
+
+	require.Len(t, requests, 3)
+	last := requests[2]
+	assert.Equal(t, int64(77), last.EpisodeID)
+	assert.Equal(t, []string{"advertisement", "music"}, last.Labels)
+	assert.Equal(t, InferenceChunk{Index: 2, Offset: 2, Duration: 0.5, SampleRate: 16000, Channels: 1}, last.Chunk)
+	assert.Nil(t, last.Features)
+	audio, err := base64.StdEncoding.DecodeString(last.Audio)
+	require.NoError(t, err)
+	assert.Len(t, audio, 44+8000*2)
+
+	require.Len(t, detections, 2)
+	assert.Equal(t, "advertisement", detections[0].Label)
+	assert.InDelta(t, 0.5, detections[0].Start, 1e-9)
+	assert.InDelta(t, 1.5, detections[0].End, 1e-9)
+	require.NotNil(t, detections[0].Score)
+	assert.InDelta(t, 0.75, *detections[0].Score, 1e-9)
+	assert.Equal(t, "music", detections[1].Label)
+	assert.InDelta(t, 2.1, detections[1].Start, 1e-9)
+	assert.InDelta(t, 2.5, detections[1].End, 1e-9, "clamped to the end of the chunk")
+}
+
+func TestRemoteDetector_Features(t *testing.T) {
+	var got InferenceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"segments": []}`))
+	}))
+	defer server.Close()
+
+	model := &models.DetectorModel{
+		Name: "loudness", Version: "1", Backend: models.DetectorBackendHTTP, Endpoint: server.URL,
+		Labels: []string{"advertisement"}, Input: models.DetectorInputFeatures,
+	}
+	detections, err := NewRemoteDetector(model, server.Client()).Detect(context.Background(), 1, writeTestWAV(t, 0.25))
+	require.NoError(t, err)
+	assert.Empty(t, detections)
+
+	assert.Empty(t, got.Audio)
+	require.NotNil(t, got.Features)
+	assert.Equal(t, FeatureFrameRate, got.Features.FrameRate)
+	require.Len(t, got.Features.LevelsDB, 25)
+	assert.InDelta(t, -6.02, got.Features.LevelsDB[0], 0.01)
+}
+
+func TestRemoteDetector_ReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	model := &models.DetectorModel{
+		Name: "ads", Version: "1", Backend: models.DetectorBackendHTTP, Endpoint: server.URL, Labels: []string{"advertisement"},
+	}
+	_, err := NewRemoteDetector(model, server.Client()).Detect(context.Background(), 1, writeTestWAV(t, 1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 503: model not loaded")
+}
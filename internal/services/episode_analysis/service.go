@@ -4,43 +4,75 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 
+	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/internal/services/audiocache"
+	"github.com/killallgit/player-api/internal/services/autoapproval"
 	"github.com/killallgit/player-api/internal/services/clips"
 	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/modelregistry"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 )
 
-// Service analyzes episodes for volume anomalies and creates clips
+// VolumeSpikeLabel is the label given to clips created from detected spikes
+const VolumeSpikeLabel = "volume_spike"
+
+// Service runs detector models over episodes and creates clips from what
+// they find
 type Service interface {
-	// AnalyzeAndCreateClips finds volume spikes in an episode and auto-creates clips
+	// AnalyzeAndCreateClips runs model over an episode and auto-creates clips
+	// from its detections; a nil model runs the registry's default.
 	// Returns list of created clip UUIDs
-	AnalyzeAndCreateClips(ctx context.Context, episodeID int64) ([]string, error)
+	AnalyzeAndCreateClips(ctx context.Context, episodeID int64, model *models.DetectorModel) ([]string, error)
 }
 
 type serviceImpl struct {
 	audioCache     audiocache.Service
 	clipService    clips.Service
 	episodeService episodes.EpisodeService
+	registry       modelregistry.Service
+	thresholds     autoapproval.Service // Confidence per label that approves new clips; optional
 	analyzer       *VolumeAnalyzer
+	httpClient     *http.Client // For http models; requests time out per model
 }
 
-// NewService creates a new episode analysis service
+// NewService creates a new episode analysis service. Without a registry it
+// runs the built-in volume spike detector, whose ffmpeg processes run through
+// ffmpegInstance; without thresholds every clip it creates waits for review.
 func NewService(
 	audioCache audiocache.Service,
 	clipService clips.Service,
 	episodeService episodes.EpisodeService,
+	registry modelregistry.Service,
+	thresholds autoapproval.Service,
+	ffmpegInstance *ffmpeg.FFmpeg,
 ) Service {
 	return &serviceImpl{
 		audioCache:     audioCache,
 		clipService:    clipService,
 		episodeService: episodeService,
-		analyzer:       NewVolumeAnalyzer(),
+		registry:       registry,
+		thresholds:     thresholds,
+		analyzer:       NewVolumeAnalyzer(ffmpegInstance),
+		httpClient:     &http.Client{},
 	}
 }
 
 // AnalyzeAndCreateClips is the main entry point for episode analysis
-func (s *serviceImpl) AnalyzeAndCreateClips(ctx context.Context, episodeID int64) ([]string, error) {
-	log.Printf("[INFO] Starting volume spike analysis for episode %d", episodeID)
+func (s *serviceImpl) AnalyzeAndCreateClips(ctx context.Context, episodeID int64, model *models.DetectorModel) ([]string, error) {
+	if model == nil {
+		var err error
+		if model, err = s.defaultModel(ctx); err != nil {
+			return nil, err
+		}
+	}
+	detector, err := s.detectorFor(model)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[INFO] Starting analysis of episode %d with %s", episodeID, model.Identity())
 
 	// 1. Fetch episode details to get audio URL
 	episode, err := s.episodeService.GetEpisodeByPodcastIndexID(ctx, episodeID)
@@ -55,53 +87,98 @@ func (s *serviceImpl) AnalyzeAndCreateClips(ctx context.Context, episodeID int64
 	log.Printf("[INFO] Episode: %s (duration: %v seconds)", episode.Title, episode.Duration)
 
 	// 2. Get or download audio (uses cache if available)
-	audioCache, err := s.audioCache.GetOrDownloadAudio(ctx, episodeID, episode.AudioURL)
+	audioCache, err := s.audioCache.GetProcessedAudio(ctx, episodeID, episode.AudioURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get audio: %w", err)
 	}
 
 	log.Printf("[INFO] Using cached audio: %s (%.2f seconds)", audioCache.ProcessedPath, audioCache.DurationSeconds)
 
-	// 3. Analyze audio for volume spikes
-	spikes, err := s.analyzer.FindSpikes(ctx, audioCache.ProcessedPath)
+	// 3. Run the model over the audio
+	detections, err := detector.Detect(ctx, episodeID, audioCache.ProcessedPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to analyze audio: %w", err)
+		return nil, fmt.Errorf("failed to analyze audio with %s: %w", model.Identity(), err)
 	}
 
-	log.Printf("[INFO] Detected %d volume spikes", len(spikes))
+	log.Printf("[INFO] %s detected %d segments", model.Identity(), len(detections))
 
-	if len(spikes) == 0 {
-		log.Printf("[INFO] No volume spikes found in episode %d", episodeID)
+	if len(detections) == 0 {
+		log.Printf("[INFO] Nothing detected in episode %d", episodeID)
 		return []string{}, nil
 	}
 
-	// 4. Create clips from detected spikes
+	// 4. Create clips from the detections
 	var clipUUIDs []string
 
-	for i, spike := range spikes {
-		log.Printf("[INFO] Creating clip %d/%d: %.2fs-%.2fs (peak: %.2f dB)",
-			i+1, len(spikes), spike.StartTime, spike.EndTime, spike.PeakDB)
+	for i, detection := range detections {
+		if !model.PredictsLabel(detection.Label) {
+			log.Printf("[WARN] Skipping detection %d: %s doesn't predict label %q", i+1, model.Identity(), detection.Label)
+			continue
+		}
+
+		log.Printf("[INFO] Creating clip %d/%d: %.2fs-%.2fs (%s)",
+			i+1, len(detections), detection.Start, detection.End, detection.Label)
 
-		// Create clip with special label for auto-detected spikes
-		// Not approved - user must review and approve before extraction
+		// Not approved - user must review and approve before extraction,
+		// unless the score clears the label's auto-approval threshold below
 		clip, err := s.clipService.CreateClip(ctx, clips.CreateClipParams{
 			PodcastIndexEpisodeID: episodeID,
-			OriginalStartTime:     spike.StartTime,
-			OriginalEndTime:       spike.EndTime,
-			Label:                 "volume_spike", // Special label for auto-detected
-			Approved:              false,          // Needs review before extraction
+			OriginalStartTime:     detection.Start,
+			OriginalEndTime:       detection.End,
+			Label:                 detection.Label,
+			Approved:              false, // Needs review before extraction
+			ModelName:             model.Name,
+			ModelVersion:          model.Version,
+			Confidence:            detection.Score,
 		})
 
 		if err != nil {
-			log.Printf("[WARN] Failed to create clip for spike %d: %v", i+1, err)
+			log.Printf("[WARN] Failed to create clip for detection %d: %v", i+1, err)
 			continue
 		}
 
 		clipUUIDs = append(clipUUIDs, clip.UUID)
-		log.Printf("[INFO] Created clip %s for spike at %.2fs-%.2fs", clip.UUID, spike.StartTime, spike.EndTime)
+		log.Printf("[INFO] Created clip %s for detection at %.2fs-%.2fs", clip.UUID, detection.Start, detection.End)
+
+		s.autoApprove(ctx, clip.UUID, detection)
 	}
 
-	log.Printf("[INFO] Successfully created %d clips from %d detected spikes", len(clipUUIDs), len(spikes))
+	log.Printf("[INFO] Successfully created %d clips from %d detections", len(clipUUIDs), len(detections))
 
 	return clipUUIDs, nil
 }
+
+// autoApprove approves a new clip when its detection scored at or over the
+// threshold for its label. Failures leave the clip waiting for review.
+func (s *serviceImpl) autoApprove(ctx context.Context, clipUUID string, detection Detection) {
+	if s.thresholds == nil || detection.Score == nil {
+		return
+	}
+	threshold, ok, err := s.thresholds.ThresholdFor(ctx, detection.Label)
+	if err != nil {
+		log.Printf("[WARN] Failed to get auto-approval threshold for %q: %v", detection.Label, err)
+		return
+	}
+	if !ok || *detection.Score < threshold {
+		return
+	}
+	if _, err := s.clipService.AutoApproveClip(ctx, clipUUID, threshold); err != nil {
+		log.Printf("[WARN] Clip %s scored %.3f but was not auto-approved: %v", clipUUID, *detection.Score, err)
+		return
+	}
+	log.Printf("[INFO] Auto-approved clip %s: %s scored %.3f (threshold %.3f)", clipUUID, detection.Label, *detection.Score, threshold)
+}
+
+// defaultModel returns the registry's default model, or the built-in volume
+// spike detector when there is no registry
+func (s *serviceImpl) defaultModel(ctx context.Context) (*models.DetectorModel, error) {
+	if s.registry == nil {
+		builtin := BuiltinModels()[0]
+		return &builtin, nil
+	}
+	model, err := s.registry.Resolve(ctx, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve default model: %w", err)
+	}
+	return model, nil
+}
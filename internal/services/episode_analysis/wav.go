@@ -0,0 +1,136 @@
+package episodeanalysis
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// silenceDB is the level reported for frames of digital silence
+const silenceDB = -120.0
+
+// pcmFile reads the samples of a 16-bit PCM WAV file, such as the processed
+// rendition of the audio cache
+type pcmFile struct {
+	file       *os.File
+	data       io.Reader // The data chunk
+	sampleRate int
+	channels   int
+}
+
+// openPCM opens a WAV file and positions it at its samples
+func openPCM(path string) (*pcmFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	pcm := &pcmFile{file: file}
+	if err := pcm.readHeader(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return pcm, nil
+}
+
+func (p *pcmFile) Close() error {
+	return p.file.Close()
+}
+
+// readHeader walks the RIFF chunks up to "data", reading the format on the way
+func (p *pcmFile) readHeader() error {
+	var riff [12]byte
+	if _, err := io.ReadFull(p.file, riff[:]); err != nil {
+		return err
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return errors.New("not a WAV file")
+	}
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(p.file, header[:]); err != nil {
+			return fmt.Errorf("no data chunk: %w", err)
+		}
+		id, size := string(header[0:4]), int64(binary.LittleEndian.Uint32(header[4:8]))
+		switch id {
+		case "fmt ":
+			var format [16]byte
+			if size < 16 {
+				return errors.New("short fmt chunk")
+			}
+			if _, err := io.ReadFull(p.file, format[:]); err != nil {
+				return err
+			}
+			if code := binary.LittleEndian.Uint16(format[0:2]); code != 1 {
+				return fmt.Errorf("format %d is not PCM", code)
+			}
+			if bits := binary.LittleEndian.Uint16(format[14:16]); bits != 16 {
+				return fmt.Errorf("%d-bit samples, want 16", bits)
+			}
+			p.channels = int(binary.LittleEndian.Uint16(format[2:4]))
+			p.sampleRate = int(binary.LittleEndian.Uint32(format[4:8]))
+			size -= 16
+		case "data":
+			if p.sampleRate <= 0 || p.channels <= 0 {
+				return errors.New("data before fmt chunk")
+			}
+			p.data = io.LimitReader(p.file, size)
+			return nil
+		}
+		// Chunks are padded to an even size
+		if _, err := p.file.Seek(size+size%2, io.SeekCurrent); err != nil {
+			return err
+		}
+	}
+}
+
+// frameBytes is the size of one sample frame
+func (p *pcmFile) frameBytes() int {
+	return 2 * p.channels
+}
+
+// encodeWAV wraps 16-bit PCM samples in a canonical WAV header
+func encodeWAV(samples []byte, sampleRate, channels int) []byte {
+	var buf bytes.Buffer
+	buf.Grow(44 + len(samples))
+	le := binary.LittleEndian
+	buf.WriteString("RIFF")
+	binary.Write(&buf, le, uint32(36+len(samples)))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, le, uint32(16))
+	binary.Write(&buf, le, uint16(1)) // PCM
+	binary.Write(&buf, le, uint16(channels))
+	binary.Write(&buf, le, uint32(sampleRate))
+	binary.Write(&buf, le, uint32(sampleRate*channels*2)) // Byte rate
+	binary.Write(&buf, le, uint16(channels*2))            // Block align
+	binary.Write(&buf, le, uint16(16))
+	buf.WriteString("data")
+	binary.Write(&buf, le, uint32(len(samples)))
+	buf.Write(samples)
+	return buf.Bytes()
+}
+
+// frameLevels returns the RMS level in dBFS of each 1/frameRate seconds of
+// 16-bit PCM samples, over all channels; a last partial frame is included
+func frameLevels(samples []byte, sampleRate, channels, frameRate int) []float64 {
+	frameSize := max(sampleRate/frameRate, 1) * channels * 2
+	levels := make([]float64, 0, len(samples)/frameSize+1)
+	for start := 0; start+1 < len(samples); start += frameSize {
+		end := min(start+frameSize, len(samples)) &^ 1
+		var sum float64
+		for i := start; i < end; i += 2 {
+			v := float64(int16(binary.LittleEndian.Uint16(samples[i:]))) / 32768
+			sum += v * v
+		}
+		rms := math.Sqrt(sum / float64((end-start)/2))
+		level := silenceDB
+		if rms > 0 {
+			level = math.Max(20*math.Log10(rms), silenceDB)
+		}
+		levels = append(levels, math.Round(level*100)/100)
+	}
+	return levels
+}
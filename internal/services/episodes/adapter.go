@@ -176,6 +176,36 @@ func (a *PodcastIndexAdapter) convertEpisode(ep podcastindex.Episode) PodcastInd
 		feedDuplicateOf = int64Ptr(int64(ep.FeedDuplicateOf))
 	}
 
+	var persons []Person
+	for _, p := range ep.Persons {
+		persons = append(persons, Person{
+			Name:  p.Name,
+			Role:  p.Role,
+			Group: p.Group,
+			Href:  p.Href,
+			Img:   p.Img,
+		})
+	}
+
+	var alternates []AlternateEnclosure
+	for _, alt := range ep.AlternateEnclosures {
+		sources := make([]AlternateEnclosureSource, 0, len(alt.Sources))
+		for _, source := range alt.Sources {
+			sources = append(sources, AlternateEnclosureSource{URI: source.URI, ContentType: source.ContentType})
+		}
+		alternates = append(alternates, AlternateEnclosure{
+			Type:    alt.Type,
+			Length:  alt.Length,
+			Bitrate: alt.Bitrate,
+			Height:  alt.Height,
+			Lang:    alt.Lang,
+			Title:   alt.Title,
+			Codecs:  alt.Codecs,
+			Default: alt.Default,
+			Sources: sources,
+		})
+	}
+
 	return PodcastIndexEpisode{
 		ID:                  ep.ID,
 		Title:               ep.Title,
@@ -202,5 +232,7 @@ func (a *PodcastIndexAdapter) convertEpisode(ep podcastindex.Episode) PodcastInd
 		FeedDuplicateOf:     feedDuplicateOf,
 		ChaptersURL:         ep.ChaptersURL,
 		TranscriptURL:       ep.TranscriptURL,
+		Persons:             persons,
+		AlternateEnclosures: alternates,
 	}
 }
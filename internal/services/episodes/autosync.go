@@ -0,0 +1,117 @@
+package episodes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for fetching a single episode from Podcast Index on demand
+const (
+	DefaultFetchAttempts     = 3
+	DefaultFetchBackoff      = 250 * time.Millisecond
+	DefaultMissingEpisodeTTL = 10 * time.Minute
+
+	maxMissingEpisodes = 10000 // Bound on remembered missing IDs
+)
+
+// errEpisodeMissing marks a Podcast Index answer that the episode doesn't exist
+var errEpisodeMissing = errors.New("episode does not exist in Podcast Index")
+
+// fetchEpisode fetches an episode from Podcast Index, retrying transient
+// failures with exponential backoff. An episode the API reports missing is not
+// retried; it is remembered for the missing-episode TTL and reported as
+// errEpisodeMissing without calling the API again until then.
+func (s *Service) fetchEpisode(ctx context.Context, podcastIndexID int64) (*PodcastIndexEpisode, error) {
+	if s.missing.has(podcastIndexID) {
+		return nil, errEpisodeMissing
+	}
+
+	backoff := s.fetchBackoff
+	var lastErr error
+	for attempt := 1; attempt <= s.fetchAttempts; attempt++ {
+		episode, err := s.fetcher.GetEpisodeByID(ctx, podcastIndexID)
+		if err == nil {
+			return episode, nil
+		}
+		if isMissingEpisode(err) {
+			s.missing.add(podcastIndexID)
+			return nil, errEpisodeMissing
+		}
+		lastErr = err
+		if attempt == s.fetchAttempts {
+			break
+		}
+
+		log.Printf("[WARN] Fetching episode %d from Podcast Index failed (attempt %d/%d), retrying in %v: %v",
+			podcastIndexID, attempt, s.fetchAttempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return nil, fmt.Errorf("fetching episode %d after %d attempts: %w", podcastIndexID, s.fetchAttempts, lastErr)
+}
+
+// isMissingEpisode reports whether a Podcast Index error means the episode doesn't exist
+func isMissingEpisode(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "not found") || strings.Contains(msg, "404")
+}
+
+// missingEpisodes remembers Podcast Index episode IDs the API reported
+// missing, so repeated requests for them don't reach the API until the TTL
+// passes. The database is checked first, so an episode that appears through a
+// feed sync in the meantime is still found.
+type missingEpisodes struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	expires map[int64]time.Time
+}
+
+func newMissingEpisodes(ttl time.Duration) *missingEpisodes {
+	return &missingEpisodes{ttl: ttl, expires: make(map[int64]time.Time)}
+}
+
+// has reports whether the episode was reported missing within the TTL
+func (m *missingEpisodes) has(id int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expiresAt, ok := m.expires[id]
+	if ok && time.Now().After(expiresAt) {
+		delete(m.expires, id)
+		return false
+	}
+	return ok
+}
+
+// add remembers that the episode is missing. When full, expired entries are
+// dropped first, then arbitrary ones.
+func (m *missingEpisodes) add(id int64) {
+	if m.ttl <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.expires) >= maxMissingEpisodes {
+		now := time.Now()
+		for key, expiresAt := range m.expires {
+			if now.After(expiresAt) {
+				delete(m.expires, key)
+			}
+		}
+		for key := range m.expires {
+			if len(m.expires) < maxMissingEpisodes {
+				break
+			}
+			delete(m.expires, key)
+		}
+	}
+	m.expires[id] = time.Now().Add(m.ttl)
+}
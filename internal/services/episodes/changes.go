@@ -0,0 +1,157 @@
+package episodes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// MaxChangesLimit caps the changes returned by one call
+const MaxChangesLimit = 500
+
+// ChangeCursor marks a position in a feed's change history: the last change
+// a client has seen, with the episode's row ID breaking ties
+type ChangeCursor struct {
+	ChangedAt time.Time
+	ID        uint
+}
+
+// IsZero reports whether the cursor is the start of the history
+func (c ChangeCursor) IsZero() bool {
+	return c.ChangedAt.IsZero() && c.ID == 0
+}
+
+// String encodes the cursor for clients, who should treat it as opaque
+func (c ChangeCursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	raw := strconv.FormatInt(c.ChangedAt.UnixNano(), 10) + ":" + strconv.FormatUint(uint64(c.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseChangeCursor decodes a cursor from String; empty is the zero cursor
+func ParseChangeCursor(s string) (ChangeCursor, error) {
+	if s == "" {
+		return ChangeCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ChangeCursor{}, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return ChangeCursor{}, ErrInvalidCursor
+	}
+	changedAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return ChangeCursor{}, ErrInvalidCursor
+	}
+	rowID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return ChangeCursor{}, ErrInvalidCursor
+	}
+	// Local time, as the timestamps are stored, so they compare alike
+	return ChangeCursor{ChangedAt: time.Unix(0, changedAt), ID: uint(rowID)}, nil
+}
+
+// cursorFor returns the cursor just past an episode's latest change
+func cursorFor(episode *models.Episode) ChangeCursor {
+	if episode.DeletedAt.Valid {
+		return ChangeCursor{ChangedAt: episode.DeletedAt.Time, ID: episode.ID}
+	}
+	return ChangeCursor{ChangedAt: episode.UpdatedAt, ID: episode.ID}
+}
+
+// EpisodeChanges is one page of a feed's episode changes
+type EpisodeChanges struct {
+	Created []models.Episode // Stored since the cursor
+	Updated []models.Episode // Stored before the cursor and changed since
+	Deleted []models.Episode // Deleted since the cursor
+	Next    ChangeCursor     // Where the next call picks up; the cursor given when nothing changed
+	HasMore bool             // More changes are waiting past Next
+}
+
+// GetEpisodeChanges returns the feed's stored episodes created, updated or
+// deleted since cursor. Only stored episodes are considered; nothing is
+// fetched from Podcast Index.
+func (s *Service) GetEpisodeChanges(ctx context.Context, feedID int64, cursor string, limit int) (*EpisodeChanges, error) {
+	after, err := ParseChangeCursor(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, cursor)
+	}
+	if limit <= 0 || limit > MaxChangesLimit {
+		limit = MaxChangesLimit
+	}
+
+	// One extra row tells whether there's more after this page
+	episodes, err := s.repository.GetEpisodeChanges(ctx, feedID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &EpisodeChanges{Next: after}
+	if len(episodes) > limit {
+		episodes = episodes[:limit]
+		changes.HasMore = true
+	}
+	for _, episode := range episodes {
+		switch {
+		case episode.DeletedAt.Valid:
+			changes.Deleted = append(changes.Deleted, episode)
+		case after.IsZero() || episode.CreatedAt.After(after.ChangedAt):
+			changes.Created = append(changes.Created, episode)
+		default:
+			changes.Updated = append(changes.Updated, episode)
+		}
+		changes.Next = cursorFor(&episode)
+	}
+	return changes, nil
+}
+
+// sameFeedContent reports whether a freshly fetched episode matches what's
+// stored in everything the feed supplies. The crawl date is left out: Podcast
+// Index recrawls without the episode changing.
+func sameFeedContent(stored, fetched *models.Episode) bool {
+	duration := stored.Duration
+	if stored.DurationProbed {
+		duration = stored.FeedDuration
+	}
+	return stored.PodcastID == fetched.PodcastID &&
+		stored.PodcastIndexID == fetched.PodcastIndexID &&
+		stored.PodcastIndexFeedID == fetched.PodcastIndexFeedID &&
+		stored.Title == fetched.Title &&
+		stored.Description == fetched.Description &&
+		stored.Link == fetched.Link &&
+		stored.AudioURL == fetched.AudioURL &&
+		stored.EnclosureType == fetched.EnclosureType &&
+		stored.EnclosureLength == fetched.EnclosureLength &&
+		equalPtr(duration, fetched.Duration) &&
+		stored.PublishedAt.Equal(fetched.PublishedAt) &&
+		equalPtr(stored.EpisodeNumber, fetched.EpisodeNumber) &&
+		equalPtr(stored.Season, fetched.Season) &&
+		stored.EpisodeType == fetched.EpisodeType &&
+		stored.Explicit == fetched.Explicit &&
+		stored.Image == fetched.Image &&
+		stored.FeedTitle == fetched.FeedTitle &&
+		stored.FeedImage == fetched.FeedImage &&
+		stored.FeedLanguage == fetched.FeedLanguage &&
+		equalPtr(stored.FeedItunesID, fetched.FeedItunesID) &&
+		stored.ChaptersURL == fetched.ChaptersURL &&
+		stored.TranscriptURL == fetched.TranscriptURL &&
+		slices.Equal(stored.AlternateEnclosures, fetched.AlternateEnclosures)
+}
+
+// equalPtr reports whether two optional values are both unset or equal
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,113 @@
+package episodes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func titles(episodes []models.Episode) []string {
+	result := make([]string, len(episodes))
+	for i, episode := range episodes {
+		result[i] = episode.Title
+	}
+	return result
+}
+
+func TestGetEpisodeChanges(t *testing.T) {
+	repo := NewRepository(setupTestDB(t))
+	svc := NewService(nil, repo, NewCache(time.Minute), nil)
+	ctx := context.Background()
+
+	stored := make([]*models.Episode, 3)
+	for i, title := range []string{"One", "Two", "Three"} {
+		stored[i] = &models.Episode{
+			PodcastID: 1, PodcastIndexID: int64(i + 1), PodcastIndexFeedID: 100,
+			Title: title, GUID: title, AudioURL: "https://example.com/" + title + ".mp3",
+		}
+		require.NoError(t, repo.CreateEpisode(ctx, stored[i]))
+	}
+	require.NoError(t, repo.CreateEpisode(ctx, &models.Episode{
+		PodcastID: 2, PodcastIndexID: 9, PodcastIndexFeedID: 200, Title: "Other feed", GUID: "other", AudioURL: "https://example.com/other.mp3",
+	}))
+
+	// The first sync pages through everything stored
+	changes, err := svc.GetEpisodeChanges(ctx, 100, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"One", "Two"}, titles(changes.Created))
+	assert.True(t, changes.HasMore)
+
+	changes, err = svc.GetEpisodeChanges(ctx, 100, changes.Next.String(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Three"}, titles(changes.Created))
+	assert.False(t, changes.HasMore)
+	cursor := changes.Next.String()
+
+	changes, err = svc.GetEpisodeChanges(ctx, 100, cursor, 2)
+	require.NoError(t, err)
+	assert.Empty(t, changes.Created)
+	assert.Equal(t, cursor, changes.Next.String(), "nothing changed, so the cursor stays put")
+
+	// Later changes come back as updates and deletions
+	stored[0].Title = "One, remastered"
+	require.NoError(t, repo.UpdateEpisode(ctx, stored[0]))
+	require.NoError(t, repo.DeleteEpisode(ctx, stored[1].ID))
+
+	changes, err = svc.GetEpisodeChanges(ctx, 100, cursor, 10)
+	require.NoError(t, err)
+	assert.Empty(t, changes.Created)
+	assert.Equal(t, []string{"One, remastered"}, titles(changes.Updated))
+	require.Len(t, changes.Deleted, 1)
+	assert.Equal(t, int64(2), changes.Deleted[0].PodcastIndexID)
+
+	_, err = svc.GetEpisodeChanges(ctx, 100, "not-a-cursor", 10)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestChangeCursorRoundTrip(t *testing.T) {
+	cursor := ChangeCursor{ChangedAt: time.Unix(1700000000, 123456789), ID: 42}
+	parsed, err := ParseChangeCursor(cursor.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.ChangedAt.Equal(cursor.ChangedAt))
+	assert.Equal(t, cursor.ID, parsed.ID)
+
+	parsed, err = ParseChangeCursor("")
+	require.NoError(t, err)
+	assert.True(t, parsed.IsZero())
+}
+
+func TestSyncEpisodesToDatabase_SkipsUnchanged(t *testing.T) {
+	repo := NewRepository(setupTestDB(t))
+	svc := NewService(nil, repo, NewCache(time.Minute), nil)
+	ctx := context.Background()
+
+	duration := 1800
+	fetched := PodcastIndexEpisode{
+		ID: 7, Title: "Pilot", GUID: "pilot", EnclosureURL: "https://example.com/pilot.mp3",
+		Duration: &duration, DatePublished: 1700000000, DateCrawled: 1700000100,
+	}
+	_, err := svc.SyncEpisodesToDatabase(ctx, []PodcastIndexEpisode{fetched}, 1, 100)
+	require.NoError(t, err)
+	first, err := repo.GetEpisodeByGUID(ctx, "pilot")
+	require.NoError(t, err)
+
+	// A recrawl with nothing new leaves the row alone
+	fetched.DateCrawled = 1700000200
+	_, err = svc.SyncEpisodesToDatabase(ctx, []PodcastIndexEpisode{fetched}, 1, 100)
+	require.NoError(t, err)
+	again, err := repo.GetEpisodeByGUID(ctx, "pilot")
+	require.NoError(t, err)
+	assert.True(t, first.UpdatedAt.Equal(again.UpdatedAt))
+
+	fetched.Title = "Pilot (edited)"
+	_, err = svc.SyncEpisodesToDatabase(ctx, []PodcastIndexEpisode{fetched}, 1, 100)
+	require.NoError(t, err)
+	edited, err := repo.GetEpisodeByGUID(ctx, "pilot")
+	require.NoError(t, err)
+	assert.Equal(t, "Pilot (edited)", edited.Title)
+	assert.True(t, edited.UpdatedAt.After(first.UpdatedAt))
+}
@@ -11,6 +11,7 @@ var (
 	ErrInvalidInput    = errors.New("invalid input")
 	ErrCacheMiss       = errors.New("cache miss")
 	ErrSyncFailed      = errors.New("sync failed")
+	ErrInvalidCursor   = errors.New("invalid sync cursor")
 )
 
 // NotFoundError represents an error when a resource is not found
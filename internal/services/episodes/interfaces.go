@@ -17,10 +17,19 @@ type EpisodeRepository interface {
 	GetEpisodeByID(ctx context.Context, id uint) (*models.Episode, error)
 	GetEpisodeByGUID(ctx context.Context, guid string) (*models.Episode, error)
 	GetEpisodeByPodcastIndexID(ctx context.Context, podcastIndexID int64) (*models.Episode, error)
+	GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) ([]models.Episode, error)
 	GetEpisodesByPodcastID(ctx context.Context, podcastID uint, page, limit int) ([]models.Episode, int64, error)
 	GetEpisodesByPodcastIndexFeedID(ctx context.Context, feedID int64, page, limit int) ([]models.Episode, int64, error)
 	GetRecentEpisodes(ctx context.Context, limit int) ([]models.Episode, error)
 
+	// GetEpisodeStatuses aggregates waveform, transcript and clip state for a page of
+	// episodes in one query. Episodes not in the database are left out of the map.
+	GetEpisodeStatuses(ctx context.Context, podcastIndexIDs []int64) (map[int64]models.EpisodeStatus, error)
+
+	// GetEpisodeChanges returns up to limit of the feed's episodes changed
+	// after the cursor, deleted ones included, oldest change first
+	GetEpisodeChanges(ctx context.Context, feedID int64, after ChangeCursor, limit int) ([]models.Episode, error)
+
 	// Update operations
 	UpdateEpisode(ctx context.Context, episode *models.Episode) error
 
@@ -66,6 +75,29 @@ type EpisodeService interface {
 	GetEpisodesByPodcastID(ctx context.Context, podcastID uint, page, limit int) ([]models.Episode, int64, error)
 	GetEpisodesByPodcastIndexFeedID(ctx context.Context, feedID int64, page, limit int) ([]models.Episode, int64, error)
 	GetRecentEpisodes(ctx context.Context, limit int) ([]models.Episode, error)
+
+	// GetEpisodesByPodcastIndexIDs loads many episodes from the cache and database in
+	// one pass, keyed by Podcast Index ID. Unlike GetEpisodeByPodcastIndexID it does
+	// not fall back to the Podcast Index API, so unknown IDs are missing from the map.
+	GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) (map[int64]*models.Episode, error)
+
+	// GetEpisodeStatuses returns list-view processing state for the given episodes
+	GetEpisodeStatuses(ctx context.Context, podcastIndexIDs []int64) (map[int64]models.EpisodeStatus, error)
+
+	// GetEpisodeChanges returns the feed's stored episodes created, updated or
+	// deleted since cursor, for clients keeping their own copy. An empty cursor
+	// starts from the beginning; an unreadable one is ErrInvalidCursor.
+	GetEpisodeChanges(ctx context.Context, feedID int64, cursor string, limit int) (*EpisodeChanges, error)
+}
+
+// PeopleIngester stores podcast:person credits for synced episodes
+type PeopleIngester interface {
+	IngestEpisodePeople(ctx context.Context, podcastIndexEpisodeID int64, persons []Person) error
+}
+
+// NewEpisodeNotifier is told about episodes created by a sync
+type NewEpisodeNotifier interface {
+	NotifyNewEpisodes(ctx context.Context, podcastID uint, episodes []*models.Episode) error
 }
 
 // EpisodeTransformer defines the interface for transforming between different episode formats
@@ -85,6 +85,28 @@ func (r *Repository) GetEpisodeByPodcastIndexID(ctx context.Context, podcastInde
 	return &episode, nil
 }
 
+// multiGetBatchSize bounds the IDs bound into one IN clause, keeping well under
+// SQLite's host parameter limit
+const multiGetBatchSize = 500
+
+// GetEpisodesByPodcastIndexIDs loads episodes with one IN query per batch of IDs.
+// IDs without a stored episode are skipped.
+func (r *Repository) GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) ([]models.Episode, error) {
+	var episodes []models.Episode
+	for start := 0; start < len(podcastIndexIDs); start += multiGetBatchSize {
+		end := min(start+multiGetBatchSize, len(podcastIndexIDs))
+
+		var batch []models.Episode
+		if err := r.db.WithContext(ctx).
+			Where("podcast_index_id IN ?", podcastIndexIDs[start:end]).
+			Find(&batch).Error; err != nil {
+			return nil, fmt.Errorf("getting episodes by podcast index ids: %w", err)
+		}
+		episodes = append(episodes, batch...)
+	}
+	return episodes, nil
+}
+
 func (r *Repository) GetEpisodesByPodcastID(ctx context.Context, podcastID uint, page, limit int) ([]models.Episode, int64, error) {
 	var episodes []models.Episode
 	var total int64
@@ -131,6 +153,47 @@ func (r *Repository) GetEpisodesByPodcastIndexFeedID(ctx context.Context, feedID
 	return episodes, total, nil
 }
 
+// episodeStatusQuery reads the list-view flags of many episodes at once; the
+// correlated EXISTS checks and the grouped clip counts keep it to one round trip
+// no matter how many episodes are on the page.
+const episodeStatusQuery = `
+SELECT e.podcast_index_id AS podcast_index_episode_id,
+	EXISTS (SELECT 1 FROM waveforms w
+		WHERE w.podcast_index_episode_id = e.podcast_index_id AND w.deleted_at IS NULL) AS has_waveform,
+	EXISTS (SELECT 1 FROM transcriptions t
+		WHERE t.podcast_index_episode_id = e.podcast_index_id AND t.deleted_at IS NULL) AS has_transcript,
+	COALESCE(c.clip_count, 0) AS clip_count,
+	COALESCE(c.approved_clip_count, 0) AS approved_clip_count
+FROM episodes e
+LEFT JOIN (
+	SELECT podcast_index_episode_id,
+		COUNT(*) AS clip_count,
+		SUM(CASE WHEN approved THEN 1 ELSE 0 END) AS approved_clip_count
+	FROM clips
+	WHERE rejected = ? AND podcast_index_episode_id IN ?
+	GROUP BY podcast_index_episode_id
+) c ON c.podcast_index_episode_id = e.podcast_index_id
+WHERE e.podcast_index_id IN ? AND e.deleted_at IS NULL`
+
+func (r *Repository) GetEpisodeStatuses(ctx context.Context, podcastIndexIDs []int64) (map[int64]models.EpisodeStatus, error) {
+	statuses := make(map[int64]models.EpisodeStatus, len(podcastIndexIDs))
+	if len(podcastIndexIDs) == 0 {
+		return statuses, nil
+	}
+
+	var rows []models.EpisodeStatus
+	if err := r.db.WithContext(ctx).
+		Raw(episodeStatusQuery, false, podcastIndexIDs, podcastIndexIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("getting episode statuses: %w", err)
+	}
+
+	for _, row := range rows {
+		statuses[row.PodcastIndexEpisodeID] = row
+	}
+	return statuses, nil
+}
+
 func (r *Repository) GetRecentEpisodes(ctx context.Context, limit int) ([]models.Episode, error) {
 	var episodes []models.Episode
 
@@ -144,6 +207,25 @@ func (r *Repository) GetRecentEpisodes(ctx context.Context, limit int) ([]models
 	return episodes, nil
 }
 
+// GetEpisodeChanges returns up to limit of the feed's episodes changed after
+// the cursor, deleted ones included, ordered by when they last changed. A soft
+// delete doesn't touch updated_at, so a deleted episode changed when it was
+// deleted.
+func (r *Repository) GetEpisodeChanges(ctx context.Context, feedID int64, after ChangeCursor, limit int) ([]models.Episode, error) {
+	const changedAt = "COALESCE(deleted_at, updated_at)"
+
+	query := r.db.WithContext(ctx).Unscoped().Where("podcast_index_feed_id = ?", feedID)
+	if !after.IsZero() {
+		query = query.Where("("+changedAt+" > ? OR ("+changedAt+" = ? AND id > ?))", after.ChangedAt, after.ChangedAt, after.ID)
+	}
+
+	var episodes []models.Episode
+	if err := query.Order(changedAt + " ASC, id ASC").Limit(limit).Find(&episodes).Error; err != nil {
+		return nil, fmt.Errorf("getting episode changes: %w", err)
+	}
+	return episodes, nil
+}
+
 func (r *Repository) DeleteEpisode(ctx context.Context, id uint) error {
 	result := r.db.WithContext(ctx).Delete(&models.Episode{}, id)
 	if result.Error != nil {
@@ -162,6 +244,16 @@ func (r *Repository) UpsertEpisode(ctx context.Context, episode *models.Episode)
 	if err == nil {
 		episode.ID = existing.ID
 		episode.CreatedAt = existing.CreatedAt
+		if existing.DurationProbed {
+			// A measured duration beats whatever the feed reports on re-sync
+			episode.FeedDuration = episode.Duration
+			episode.Duration = existing.Duration
+			episode.DurationProbed = true
+		}
+		if existing.AudioURL == episode.AudioURL {
+			// The source stands in for this enclosure only
+			episode.AudioSourceURL = existing.AudioSourceURL
+		}
 		return r.UpdateEpisode(ctx, episode)
 	}
 
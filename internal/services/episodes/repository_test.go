@@ -220,6 +220,43 @@ func TestRepository_UpsertEpisode(t *testing.T) {
 	assert.Equal(t, "Updated", retrieved.Description)
 }
 
+func TestRepository_UpsertEpisode_KeepsProbedDuration(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	measured, feed := 3725, 3600
+	require.NoError(t, db.Create(&models.Episode{
+		PodcastID:      1,
+		PodcastIndexID: 42,
+		Title:          "Probed",
+		AudioURL:       "https://example.com/probed.mp3",
+		GUID:           "probed-guid",
+		Duration:       &measured,
+		FeedDuration:   &feed,
+		DurationProbed: true,
+	}).Error)
+
+	// A re-sync brings the feed's figure back
+	resynced := 3500
+	require.NoError(t, repo.UpsertEpisode(ctx, &models.Episode{
+		PodcastID:      1,
+		PodcastIndexID: 42,
+		Title:          "Probed",
+		AudioURL:       "https://example.com/probed.mp3",
+		GUID:           "probed-guid",
+		Duration:       &resynced,
+	}))
+
+	var retrieved models.Episode
+	require.NoError(t, db.Where("guid = ?", "probed-guid").First(&retrieved).Error)
+	require.NotNil(t, retrieved.Duration)
+	assert.Equal(t, measured, *retrieved.Duration)
+	require.NotNil(t, retrieved.FeedDuration)
+	assert.Equal(t, resynced, *retrieved.FeedDuration)
+	assert.True(t, retrieved.DurationProbed)
+}
+
 func TestRepository_DeleteEpisode(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewRepository(db)
@@ -249,3 +286,73 @@ func TestRepository_DeleteEpisode(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
+
+func TestRepository_GetEpisodeStatuses(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Waveform{}, &models.Transcription{}, &models.Clip{}))
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(t, repo.CreateEpisode(ctx, &models.Episode{
+			PodcastID:      1,
+			PodcastIndexID: i,
+			Title:          fmt.Sprintf("Episode %d", i),
+			AudioURL:       fmt.Sprintf("https://example.com/episode%d.mp3", i),
+			GUID:           fmt.Sprintf("status-guid-%d", i),
+		}))
+	}
+
+	require.NoError(t, db.Create(&models.Waveform{PodcastIndexEpisodeID: 1, PeaksData: []byte("[]")}).Error)
+	require.NoError(t, db.Create(&models.Transcription{PodcastIndexEpisodeID: 2, Text: "hello"}).Error)
+	for i, clip := range []models.Clip{
+		{PodcastIndexEpisodeID: 1, Approved: true},
+		{PodcastIndexEpisodeID: 1},
+		{PodcastIndexEpisodeID: 1, Rejected: true},
+		{PodcastIndexEpisodeID: 3},
+	} {
+		clip.UUID = fmt.Sprintf("clip-%d", i)
+		clip.SourceEpisodeURL = "https://example.com/episode.mp3"
+		clip.Label = "advertisement"
+		require.NoError(t, db.Create(&clip).Error)
+	}
+
+	statuses, err := repo.GetEpisodeStatuses(ctx, []int64{1, 2, 3, 404})
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+
+	assert.Equal(t, models.EpisodeStatus{PodcastIndexEpisodeID: 1, HasWaveform: true, ClipCount: 2, ApprovedClipCount: 1}, statuses[1])
+	assert.Equal(t, models.EpisodeStatus{PodcastIndexEpisodeID: 2, HasTranscript: true}, statuses[2])
+	assert.Equal(t, models.EpisodeStatus{PodcastIndexEpisodeID: 3, ClipCount: 1}, statuses[3])
+
+	empty, err := repo.GetEpisodeStatuses(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestRepository_GetEpisodesByPodcastIndexIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(t, repo.CreateEpisode(ctx, &models.Episode{
+			PodcastID:      1,
+			PodcastIndexID: i * 10,
+			Title:          fmt.Sprintf("Episode %d", i),
+			AudioURL:       fmt.Sprintf("https://example.com/episode%d.mp3", i),
+			GUID:           fmt.Sprintf("multi-guid-%d", i),
+		}))
+	}
+
+	episodes, err := repo.GetEpisodesByPodcastIndexIDs(ctx, []int64{10, 30, 404})
+	require.NoError(t, err)
+	require.Len(t, episodes, 2)
+
+	ids := []int64{episodes[0].PodcastIndexID, episodes[1].PodcastIndexID}
+	assert.ElementsMatch(t, []int64{10, 30}, ids)
+
+	episodes, err = repo.GetEpisodesByPodcastIndexIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, episodes)
+}
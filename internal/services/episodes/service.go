@@ -2,9 +2,10 @@ package episodes
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,9 +19,14 @@ type Service struct {
 	repository        EpisodeRepository
 	cache             EpisodeCache
 	podcastService    podcasts.PodcastService
+	people            PeopleIngester
+	notifier          NewEpisodeNotifier
 	keyGen            CacheKeyGenerator
 	maxConcurrentSync int
 	syncTimeout       time.Duration
+	fetchAttempts     int
+	fetchBackoff      time.Duration
+	missing           *missingEpisodes
 }
 
 // ServiceOption is a functional option for configuring the service
@@ -44,6 +50,41 @@ func WithSyncTimeout(timeout time.Duration) ServiceOption {
 	}
 }
 
+// WithPeopleIngester stores person credits from synced episodes
+func WithPeopleIngester(people PeopleIngester) ServiceOption {
+	return func(s *Service) {
+		s.people = people
+	}
+}
+
+// WithNewEpisodeNotifier reports episodes created by a sync, e.g. to subscribers
+func WithNewEpisodeNotifier(notifier NewEpisodeNotifier) ServiceOption {
+	return func(s *Service) {
+		s.notifier = notifier
+	}
+}
+
+// WithFetchRetry sets how many times an on-demand Podcast Index episode fetch
+// is attempted, and the wait before the first retry, which doubles after each
+func WithFetchRetry(attempts int, backoff time.Duration) ServiceOption {
+	return func(s *Service) {
+		if attempts > 0 {
+			s.fetchAttempts = attempts
+		}
+		if backoff > 0 {
+			s.fetchBackoff = backoff
+		}
+	}
+}
+
+// WithMissingEpisodeTTL sets how long an episode Podcast Index reported
+// missing is answered from memory; zero or negative disables remembering
+func WithMissingEpisodeTTL(ttl time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.missing = newMissingEpisodes(ttl)
+	}
+}
+
 // NewService creates a new episode service with optional configuration
 func NewService(fetcher EpisodeFetcher, repository EpisodeRepository, cache EpisodeCache, podcastService podcasts.PodcastService, opts ...ServiceOption) *Service {
 	s := &Service{
@@ -54,6 +95,9 @@ func NewService(fetcher EpisodeFetcher, repository EpisodeRepository, cache Epis
 		keyGen:            NewKeyGenerator("episode"),
 		maxConcurrentSync: DefaultMaxConcurrentSyncs,
 		syncTimeout:       DefaultSyncTimeout,
+		fetchAttempts:     DefaultFetchAttempts,
+		fetchBackoff:      DefaultFetchBackoff,
+		missing:           newMissingEpisodes(DefaultMissingEpisodeTTL),
 	}
 
 	// Apply options
@@ -130,6 +174,7 @@ func (s *Service) SyncEpisodesToDatabase(ctx context.Context, episodes []Podcast
 		successCount int
 		failureCount int
 		errors       []error
+		created      []*models.Episode
 		mu           sync.Mutex
 		wg           sync.WaitGroup
 	)
@@ -137,6 +182,15 @@ func (s *Service) SyncEpisodesToDatabase(ctx context.Context, episodes []Podcast
 	// Use a semaphore to limit concurrent operations
 	sem := make(chan struct{}, s.maxConcurrentSync)
 
+	// New episodes are only news once the feed has been synced before; the
+	// first sync stores the back catalogue
+	notifyNew := false
+	if s.notifier != nil {
+		if _, total, err := s.repository.GetEpisodesByPodcastID(ctx, podcastID, 1, 1); err == nil && total > 0 {
+			notifyNew = true
+		}
+	}
+
 	transformer := NewTransformer()
 
 	for _, piEpisode := range episodes {
@@ -163,16 +217,33 @@ func (s *Service) SyncEpisodesToDatabase(ctx context.Context, episodes []Podcast
 			episode.PodcastIndexFeedID = podcastIndexFeedID
 
 			// Check if episode exists
+			isNew := false
 			existing, err := s.repository.GetEpisodeByGUID(ctx, episode.GUID)
 			if err == nil && existing != nil {
 				// Preserve existing data
 				episode.ID = existing.ID
 				episode.CreatedAt = existing.CreatedAt
+				if existing.AudioURL == episode.AudioURL {
+					// The source stands in for this enclosure only
+					episode.AudioSourceURL = existing.AudioSourceURL
+				}
 
-				err = s.repository.UpdateEpisode(ctx, episode)
+				// Unchanged episodes aren't saved, so they don't show up
+				// as changed to clients syncing incrementally
+				if !sameFeedContent(existing, episode) {
+					err = s.repository.UpdateEpisode(ctx, episode)
+				}
 			} else if IsNotFound(err) {
 				// Create new episode
 				err = s.repository.CreateEpisode(ctx, episode)
+				isNew = true
+			}
+
+			// Credits are supplementary; a failure here does not fail the episode sync
+			if err == nil && s.people != nil && len(pie.Persons) > 0 {
+				if peopleErr := s.people.IngestEpisodePeople(ctx, pie.ID, pie.Persons); peopleErr != nil {
+					log.Printf("[WARN] Failed to store people for episode %d: %v", pie.ID, peopleErr)
+				}
 			}
 
 			mu.Lock()
@@ -181,6 +252,9 @@ func (s *Service) SyncEpisodesToDatabase(ctx context.Context, episodes []Podcast
 				errors = append(errors, err)
 			} else {
 				successCount++
+				if isNew {
+					created = append(created, episode)
+				}
 				// Invalidate cache for this podcast
 				s.cache.InvalidatePattern(s.keyGen.PodcastPattern(podcastID))
 			}
@@ -190,6 +264,13 @@ func (s *Service) SyncEpisodesToDatabase(ctx context.Context, episodes []Podcast
 
 	wg.Wait()
 
+	if notifyNew && len(created) > 0 {
+		sort.Slice(created, func(i, j int) bool { return created[i].PublishedAt.After(created[j].PublishedAt) })
+		if err := s.notifier.NotifyNewEpisodes(ctx, podcastID, created); err != nil {
+			log.Printf("[WARN] Failed to notify subscribers of podcast %d about %d new episodes: %v", podcastID, len(created), err)
+		}
+	}
+
 	if failureCount > 0 {
 		return successCount, NewSyncError(successCount, failureCount, errors)
 	}
@@ -262,11 +343,10 @@ func (s *Service) GetEpisodeByPodcastIndexID(ctx context.Context, podcastIndexID
 				return nil, err // Return original not found error
 			}
 
-			// Fetch single episode from Podcast Index API
-			apiEpisode, fetchErr := s.fetcher.GetEpisodeByID(ctx, podcastIndexID)
+			// Fetch single episode from Podcast Index API, retrying transient failures
+			apiEpisode, fetchErr := s.fetchEpisode(ctx, podcastIndexID)
 			if fetchErr != nil {
-				// Check if it's a "not found" error from the API
-				if strings.Contains(fetchErr.Error(), "not found") || strings.Contains(fetchErr.Error(), "404") {
+				if errors.Is(fetchErr, errEpisodeMissing) {
 					log.Printf("[ERROR] Service.GetEpisodeByPodcastIndexID: Episode %d does not exist in Podcast Index API", podcastIndexID)
 					// Return a permanent error indicating the episode doesn't exist
 					return nil, fmt.Errorf("episode %d does not exist in Podcast Index", podcastIndexID)
@@ -385,6 +465,51 @@ func (s *Service) GetEpisodesByPodcastIndexFeedID(ctx context.Context, feedID in
 	return episodes, total, nil
 }
 
+// GetEpisodesByPodcastIndexIDs returns the stored episodes for the given IDs,
+// serving what it can from the cache and loading the rest in batched queries.
+// Loaded episodes are cached for later single lookups.
+func (s *Service) GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) (map[int64]*models.Episode, error) {
+	result := make(map[int64]*models.Episode, len(podcastIndexIDs))
+
+	var misses []int64
+	for _, id := range podcastIndexIDs {
+		if _, seen := result[id]; seen {
+			continue
+		}
+		if episode, found := s.cache.GetEpisode(s.keyGen.EpisodeByPodcastIndexID(id)); found {
+			result[id] = episode
+			continue
+		}
+		result[id] = nil // Placeholder so duplicate IDs are only fetched once
+		misses = append(misses, id)
+	}
+
+	if len(misses) > 0 {
+		episodes, err := s.repository.GetEpisodesByPodcastIndexIDs(ctx, misses)
+		if err != nil {
+			return nil, err
+		}
+		for i := range episodes {
+			episode := &episodes[i]
+			result[episode.PodcastIndexID] = episode
+			s.cache.SetEpisode(s.keyGen.EpisodeByPodcastIndexID(episode.PodcastIndexID), episode)
+		}
+	}
+
+	for id, episode := range result {
+		if episode == nil {
+			delete(result, id)
+		}
+	}
+	return result, nil
+}
+
+// GetEpisodeStatuses returns list-view processing state for the given episodes.
+// Not cached: the flags change as jobs finish.
+func (s *Service) GetEpisodeStatuses(ctx context.Context, podcastIndexIDs []int64) (map[int64]models.EpisodeStatus, error) {
+	return s.repository.GetEpisodeStatuses(ctx, podcastIndexIDs)
+}
+
 // GetRecentEpisodes retrieves recent episodes with caching
 func (s *Service) GetRecentEpisodes(ctx context.Context, limit int) ([]models.Episode, error) {
 	key := s.keyGen.RecentEpisodes(limit)
@@ -2,6 +2,7 @@ package episodes
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -103,6 +104,22 @@ func (m *MockRepository) GetEpisodesByPodcastIndexFeedID(ctx context.Context, fe
 	return args.Get(0).([]models.Episode), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockRepository) GetEpisodesByPodcastIndexIDs(ctx context.Context, podcastIndexIDs []int64) ([]models.Episode, error) {
+	args := m.Called(ctx, podcastIndexIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Episode), args.Error(1)
+}
+
+func (m *MockRepository) GetEpisodeStatuses(ctx context.Context, podcastIndexIDs []int64) (map[int64]models.EpisodeStatus, error) {
+	args := m.Called(ctx, podcastIndexIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]models.EpisodeStatus), args.Error(1)
+}
+
 func (m *MockRepository) GetRecentEpisodes(ctx context.Context, limit int) ([]models.Episode, error) {
 	args := m.Called(ctx, limit)
 	if args.Get(0) == nil {
@@ -111,6 +128,14 @@ func (m *MockRepository) GetRecentEpisodes(ctx context.Context, limit int) ([]mo
 	return args.Get(0).([]models.Episode), args.Error(1)
 }
 
+func (m *MockRepository) GetEpisodeChanges(ctx context.Context, feedID int64, after ChangeCursor, limit int) ([]models.Episode, error) {
+	args := m.Called(ctx, feedID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Episode), args.Error(1)
+}
+
 func (m *MockRepository) UpdateEpisode(ctx context.Context, episode *models.Episode) error {
 	args := m.Called(ctx, episode)
 	return args.Error(0)
@@ -406,3 +431,152 @@ func TestService_GetRecentEpisodes_CacheHit(t *testing.T) {
 	// Verify repository was not called
 	mockRepo.AssertNotCalled(t, "GetRecentEpisodes")
 }
+
+type recordingNotifier struct {
+	podcastID uint
+	episodes  []*models.Episode
+	calls     int
+}
+
+func (n *recordingNotifier) NotifyNewEpisodes(ctx context.Context, podcastID uint, episodes []*models.Episode) error {
+	n.calls++
+	n.podcastID = podcastID
+	n.episodes = episodes
+	return nil
+}
+
+func TestService_SyncEpisodesToDatabase_NotifiesNewEpisodes(t *testing.T) {
+	episodes := []PodcastIndexEpisode{
+		{ID: 1, Title: "Older", GUID: "guid-1", DatePublished: 1700000000},
+		{ID: 2, Title: "Newer", GUID: "guid-2", DatePublished: 1700100000},
+	}
+
+	t.Run("feed synced before", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockCache := new(MockCache)
+		notifier := &recordingNotifier{}
+		service := NewService(nil, mockRepo, mockCache, nil, WithNewEpisodeNotifier(notifier))
+
+		mockRepo.On("GetEpisodesByPodcastID", mock.Anything, uint(5), 1, 1).Return([]models.Episode{{}}, int64(3), nil)
+		mockRepo.On("GetEpisodeByGUID", mock.Anything, mock.Anything).Return(nil, NewNotFoundError("episode", "guid"))
+		mockRepo.On("CreateEpisode", mock.Anything, mock.AnythingOfType("*models.Episode")).Return(nil)
+		mockCache.On("InvalidatePattern", mock.AnythingOfType("string")).Return()
+
+		count, err := service.SyncEpisodesToDatabase(context.Background(), episodes, 5, 500)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		require.Equal(t, 1, notifier.calls)
+		assert.Equal(t, uint(5), notifier.podcastID)
+		require.Len(t, notifier.episodes, 2)
+		assert.Equal(t, "Newer", notifier.episodes[0].Title)
+	})
+
+	t.Run("first sync stores back catalogue silently", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockCache := new(MockCache)
+		notifier := &recordingNotifier{}
+		service := NewService(nil, mockRepo, mockCache, nil, WithNewEpisodeNotifier(notifier))
+
+		mockRepo.On("GetEpisodesByPodcastID", mock.Anything, uint(5), 1, 1).Return([]models.Episode{}, int64(0), nil)
+		mockRepo.On("GetEpisodeByGUID", mock.Anything, mock.Anything).Return(nil, NewNotFoundError("episode", "guid"))
+		mockRepo.On("CreateEpisode", mock.Anything, mock.AnythingOfType("*models.Episode")).Return(nil)
+		mockCache.On("InvalidatePattern", mock.AnythingOfType("string")).Return()
+
+		_, err := service.SyncEpisodesToDatabase(context.Background(), episodes, 5, 500)
+		require.NoError(t, err)
+		assert.Equal(t, 0, notifier.calls)
+	})
+}
+
+func TestService_GetEpisodesByPodcastIndexIDs(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockCache := new(MockCache)
+	service := NewService(nil, mockRepo, mockCache, nil)
+	keys := NewKeyGenerator("episode")
+
+	cached := &models.Episode{PodcastIndexID: 1, Title: "Cached"}
+	mockCache.On("GetEpisode", keys.EpisodeByPodcastIndexID(1)).Return(cached, true)
+	mockCache.On("GetEpisode", keys.EpisodeByPodcastIndexID(2)).Return(nil, false)
+	mockCache.On("GetEpisode", keys.EpisodeByPodcastIndexID(3)).Return(nil, false)
+
+	// Only cache misses reach the repository, once each, in a single call
+	mockRepo.On("GetEpisodesByPodcastIndexIDs", mock.Anything, []int64{2, 3}).
+		Return([]models.Episode{{PodcastIndexID: 2, Title: "Stored"}}, nil).Once()
+	mockCache.On("SetEpisode", keys.EpisodeByPodcastIndexID(2), mock.Anything).Once()
+
+	episodes, err := service.GetEpisodesByPodcastIndexIDs(context.Background(), []int64{1, 2, 3, 2})
+	require.NoError(t, err)
+
+	require.Len(t, episodes, 2)
+	assert.Equal(t, "Cached", episodes[1].Title)
+	assert.Equal(t, "Stored", episodes[2].Title)
+	assert.NotContains(t, episodes, int64(3))
+
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestService_GetEpisodeByPodcastIndexID_RetriesTransientFailures(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockCache := new(MockCache)
+	mockFetcher := new(MockFetcher)
+	service := NewService(mockFetcher, mockRepo, mockCache, nil, WithFetchRetry(3, time.Millisecond))
+
+	mockCache.On("GetEpisode", mock.Anything).Return(nil, false)
+	mockRepo.On("GetEpisodeByPodcastIndexID", mock.Anything, int64(42)).Return(nil, NewNotFoundError("episode", 42))
+	mockFetcher.On("GetEpisodeByID", mock.Anything, int64(42)).Return(nil, errors.New("connection reset")).Twice()
+	// The third attempt succeeds; without a feed ID the episode can't be stored
+	mockFetcher.On("GetEpisodeByID", mock.Anything, int64(42)).Return(&PodcastIndexEpisode{ID: 42}, nil).Once()
+
+	_, err := service.GetEpisodeByPodcastIndexID(context.Background(), 42)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no feed ID")
+	mockFetcher.AssertNumberOfCalls(t, "GetEpisodeByID", 3)
+}
+
+func TestService_GetEpisodeByPodcastIndexID_GivesUpAfterAttempts(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockCache := new(MockCache)
+	mockFetcher := new(MockFetcher)
+	service := NewService(mockFetcher, mockRepo, mockCache, nil, WithFetchRetry(2, time.Millisecond))
+
+	mockCache.On("GetEpisode", mock.Anything).Return(nil, false)
+	mockRepo.On("GetEpisodeByPodcastIndexID", mock.Anything, int64(42)).Return(nil, NewNotFoundError("episode", 42))
+	mockFetcher.On("GetEpisodeByID", mock.Anything, int64(42)).Return(nil, errors.New("503 Service Unavailable"))
+
+	_, err := service.GetEpisodeByPodcastIndexID(context.Background(), 42)
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+	mockFetcher.AssertNumberOfCalls(t, "GetEpisodeByID", 2)
+
+	// Transient failures aren't remembered as missing
+	_, err = service.GetEpisodeByPodcastIndexID(context.Background(), 42)
+	require.Error(t, err)
+	mockFetcher.AssertNumberOfCalls(t, "GetEpisodeByID", 4)
+}
+
+func TestService_GetEpisodeByPodcastIndexID_RemembersMissingEpisodes(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockCache := new(MockCache)
+	mockFetcher := new(MockFetcher)
+	service := NewService(mockFetcher, mockRepo, mockCache, nil, WithFetchRetry(3, time.Millisecond))
+
+	mockCache.On("GetEpisode", mock.Anything).Return(nil, false)
+	mockRepo.On("GetEpisodeByPodcastIndexID", mock.Anything, int64(404)).Return(nil, NewNotFoundError("episode", 404))
+	mockFetcher.On("GetEpisodeByID", mock.Anything, int64(404)).Return(nil, errors.New("episode not found: ID 404"))
+
+	for i := 0; i < 3; i++ {
+		_, err := service.GetEpisodeByPodcastIndexID(context.Background(), 404)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist in Podcast Index")
+	}
+	// Not retried, and later requests are answered without the API
+	mockFetcher.AssertNumberOfCalls(t, "GetEpisodeByID", 1)
+
+	// Without a TTL every request asks the API again
+	service = NewService(mockFetcher, mockRepo, mockCache, nil, WithMissingEpisodeTTL(0))
+	_, err := service.GetEpisodeByPodcastIndexID(context.Background(), 404)
+	require.Error(t, err)
+	mockFetcher.AssertNumberOfCalls(t, "GetEpisodeByID", 2)
+}
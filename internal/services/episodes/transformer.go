@@ -2,6 +2,8 @@ package episodes
 
 import (
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/killallgit/player-api/internal/models"
@@ -44,6 +46,7 @@ func (t *Transformer) ModelToPodcastIndex(episode *models.Episode) PodcastIndexE
 		FeedItunesID:        episode.FeedItunesID,
 		ChaptersURL:         episode.ChaptersURL,
 		TranscriptURL:       episode.TranscriptURL,
+		AlternateEnclosures: alternatesToPodcastIndex(episode.AlternateEnclosures),
 		// Clips are managed separately via the clips service
 	}
 
@@ -84,6 +87,8 @@ func (t *Transformer) PodcastIndexToModel(pie PodcastIndexEpisode, podcastID uin
 		FeedItunesID:    pie.FeedItunesID,
 		ChaptersURL:     pie.ChaptersURL,
 		TranscriptURL:   pie.TranscriptURL,
+
+		AlternateEnclosures: alternatesFromPodcastIndex(pie.AlternateEnclosures),
 	}
 
 	// Convert Unix timestamps to time.Time
@@ -97,6 +102,52 @@ func (t *Transformer) PodcastIndexToModel(pie PodcastIndexEpisode, podcastID uin
 	return episode
 }
 
+// alternatesFromPodcastIndex keeps the alternate enclosures published over
+// HTTP, at their first such source
+func alternatesFromPodcastIndex(alternates []AlternateEnclosure) []models.AlternateEnclosure {
+	var result []models.AlternateEnclosure
+	for _, alt := range alternates {
+		for _, source := range alt.Sources {
+			if !strings.HasPrefix(source.URI, "https://") && !strings.HasPrefix(source.URI, "http://") {
+				continue
+			}
+			result = append(result, models.AlternateEnclosure{
+				URL:     source.URI,
+				Type:    alt.Type,
+				Length:  alt.Length,
+				Bitrate: int(math.Round(alt.Bitrate)),
+				Height:  alt.Height,
+				Lang:    alt.Lang,
+				Title:   alt.Title,
+				Codecs:  alt.Codecs,
+				Default: alt.Default,
+			})
+			break
+		}
+	}
+	return result
+}
+
+// alternatesToPodcastIndex converts stored alternate enclosures back to
+// Podcast Index format, each with its one source
+func alternatesToPodcastIndex(alternates []models.AlternateEnclosure) []AlternateEnclosure {
+	var result []AlternateEnclosure
+	for _, alt := range alternates {
+		result = append(result, AlternateEnclosure{
+			Type:    alt.Type,
+			Length:  alt.Length,
+			Bitrate: float64(alt.Bitrate),
+			Height:  alt.Height,
+			Lang:    alt.Lang,
+			Title:   alt.Title,
+			Codecs:  alt.Codecs,
+			Default: alt.Default,
+			Sources: []AlternateEnclosureSource{{URI: alt.URL, ContentType: alt.Type}},
+		})
+	}
+	return result
+}
+
 // CreateErrorResponse creates a Podcast Index compatible error response
 func (t *Transformer) CreateErrorResponse(errorMessage string) PodcastIndexErrorResponse {
 	return PodcastIndexErrorResponse{
@@ -231,3 +231,38 @@ func TestTransformer_EmptyEpisodesList(t *testing.T) {
 	assert.Equal(t, 0, response.Count)
 	assert.Empty(t, response.Items)
 }
+
+func TestTransformer_AlternateEnclosures(t *testing.T) {
+	transformer := NewTransformer()
+
+	pie := PodcastIndexEpisode{
+		ID:           12345,
+		GUID:         "test-guid",
+		EnclosureURL: "https://example.com/episode.mp3",
+		AlternateEnclosures: []AlternateEnclosure{
+			{
+				Type:    "audio/mpeg",
+				Bitrate: 64000.4,
+				Title:   "Low bandwidth",
+				Sources: []AlternateEnclosureSource{
+					{URI: "ipfs://QmHash"},
+					{URI: "https://example.com/episode-64k.mp3"},
+				},
+			},
+			{Type: "audio/mpeg", Sources: []AlternateEnclosureSource{{URI: "magnet:?xt=urn:btih:abc"}}},
+		},
+	}
+
+	episode := transformer.PodcastIndexToModel(pie, 1)
+	require.Len(t, episode.AlternateEnclosures, 1, "alternates without an HTTP source are dropped")
+	assert.Equal(t, models.AlternateEnclosure{
+		URL:     "https://example.com/episode-64k.mp3",
+		Type:    "audio/mpeg",
+		Bitrate: 64000,
+		Title:   "Low bandwidth",
+	}, episode.AlternateEnclosures[0])
+
+	back := transformer.ModelToPodcastIndex(episode)
+	require.Len(t, back.AlternateEnclosures, 1)
+	assert.Equal(t, "https://example.com/episode-64k.mp3", back.AlternateEnclosures[0].Sources[0].URI)
+}
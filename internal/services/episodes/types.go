@@ -15,40 +15,41 @@ type EpisodeMetadata struct {
 
 // PodcastIndexEpisode represents an episode in the exact format returned by Podcast Index API
 type PodcastIndexEpisode struct {
-	ID                  int64               `json:"id" example:"123456789"`
-	Title               string              `json:"title" example:"Episode 42: The Answer to Everything"`
-	Link                string              `json:"link,omitempty" example:"https://example.com/episode/42"`
-	Description         string              `json:"description" example:"In this episode, we explore the meaning of life, the universe, and everything."`
-	GUID                string              `json:"guid" example:"episode-42-guid-string"`
-	DatePublished       int64               `json:"datePublished" example:"1704063600"`
-	DatePublishedPretty string              `json:"datePublishedPretty,omitempty" example:"2024-01-01 00:00:00"`
-	DateCrawled         int64               `json:"dateCrawled,omitempty" example:"1704067200"`
-	EnclosureURL        string              `json:"enclosureUrl" example:"https://example.com/audio/episode42.mp3"`
-	EnclosureType       string              `json:"enclosureType,omitempty" example:"audio/mpeg"`
-	EnclosureLength     int64               `json:"enclosureLength,omitempty" example:"52428800"`
-	Duration            *int                `json:"duration" example:"3600"`
-	Explicit            int                 `json:"explicit,omitempty" example:"0"`
-	Episode             *int                `json:"episode,omitempty" example:"42"`
-	EpisodeType         string              `json:"episodeType,omitempty" example:"full"`
-	Season              *int                `json:"season,omitempty" example:"2"`
-	Image               string              `json:"image,omitempty" example:"https://example.com/episode42-cover.jpg"`
-	FeedItunesID        *int64              `json:"feedItunesId,omitempty" example:"987654321"`
-	FeedURL             string              `json:"feedUrl,omitempty" example:"https://example.com/rss.xml"`
-	FeedImage           string              `json:"feedImage,omitempty" example:"https://example.com/podcast-cover.jpg"`
-	FeedID              int64               `json:"feedId" example:"123456"`
-	FeedTitle           string              `json:"feedTitle,omitempty" example:"The Tech Show"`
-	PodcastGUID         string              `json:"podcastGuid,omitempty" example:"podcast-guid-string"`
-	FeedLanguage        string              `json:"feedLanguage,omitempty" example:"en"`
-	FeedDead            int                 `json:"feedDead,omitempty" example:"0"`
-	FeedDuplicateOf     *int64              `json:"feedDuplicateOf,omitempty"`
-	ChaptersURL         string              `json:"chaptersUrl,omitempty" example:"https://example.com/chapters/episode42.json"`
-	TranscriptURL       string              `json:"transcriptUrl,omitempty" example:"https://example.com/transcripts/episode42.txt"`
-	Transcripts         []Transcript        `json:"transcripts,omitempty"`
-	Soundbite           *Soundbite          `json:"soundbite,omitempty"`
-	Soundbites          []Soundbite         `json:"soundbites,omitempty"`
-	Persons             []Person            `json:"persons,omitempty"`
-	SocialInteract      []SocialInteraction `json:"socialInteract,omitempty"`
-	Value               *Value              `json:"value,omitempty"`
+	ID                  int64                `json:"id" example:"123456789"`
+	Title               string               `json:"title" example:"Episode 42: The Answer to Everything"`
+	Link                string               `json:"link,omitempty" example:"https://example.com/episode/42"`
+	Description         string               `json:"description" example:"In this episode, we explore the meaning of life, the universe, and everything."`
+	GUID                string               `json:"guid" example:"episode-42-guid-string"`
+	DatePublished       int64                `json:"datePublished" example:"1704063600"`
+	DatePublishedPretty string               `json:"datePublishedPretty,omitempty" example:"2024-01-01 00:00:00"`
+	DateCrawled         int64                `json:"dateCrawled,omitempty" example:"1704067200"`
+	EnclosureURL        string               `json:"enclosureUrl" example:"https://example.com/audio/episode42.mp3"`
+	EnclosureType       string               `json:"enclosureType,omitempty" example:"audio/mpeg"`
+	EnclosureLength     int64                `json:"enclosureLength,omitempty" example:"52428800"`
+	Duration            *int                 `json:"duration" example:"3600"`
+	Explicit            int                  `json:"explicit,omitempty" example:"0"`
+	Episode             *int                 `json:"episode,omitempty" example:"42"`
+	EpisodeType         string               `json:"episodeType,omitempty" example:"full"`
+	Season              *int                 `json:"season,omitempty" example:"2"`
+	Image               string               `json:"image,omitempty" example:"https://example.com/episode42-cover.jpg"`
+	FeedItunesID        *int64               `json:"feedItunesId,omitempty" example:"987654321"`
+	FeedURL             string               `json:"feedUrl,omitempty" example:"https://example.com/rss.xml"`
+	FeedImage           string               `json:"feedImage,omitempty" example:"https://example.com/podcast-cover.jpg"`
+	FeedID              int64                `json:"feedId" example:"123456"`
+	FeedTitle           string               `json:"feedTitle,omitempty" example:"The Tech Show"`
+	PodcastGUID         string               `json:"podcastGuid,omitempty" example:"podcast-guid-string"`
+	FeedLanguage        string               `json:"feedLanguage,omitempty" example:"en"`
+	FeedDead            int                  `json:"feedDead,omitempty" example:"0"`
+	FeedDuplicateOf     *int64               `json:"feedDuplicateOf,omitempty"`
+	ChaptersURL         string               `json:"chaptersUrl,omitempty" example:"https://example.com/chapters/episode42.json"`
+	TranscriptURL       string               `json:"transcriptUrl,omitempty" example:"https://example.com/transcripts/episode42.txt"`
+	Transcripts         []Transcript         `json:"transcripts,omitempty"`
+	Soundbite           *Soundbite           `json:"soundbite,omitempty"`
+	Soundbites          []Soundbite          `json:"soundbites,omitempty"`
+	Persons             []Person             `json:"persons,omitempty"`
+	AlternateEnclosures []AlternateEnclosure `json:"alternateEnclosures,omitempty"`
+	SocialInteract      []SocialInteraction  `json:"socialInteract,omitempty"`
+	Value               *Value               `json:"value,omitempty"`
 	// Clips are managed separately via the clips service
 }
 
@@ -83,6 +84,26 @@ type Soundbite struct {
 	Title     string  `json:"title,omitempty"`
 }
 
+// AlternateEnclosure represents another rendition of the episode's media in
+// Podcast Index format
+type AlternateEnclosure struct {
+	Type    string                     `json:"type"`
+	Length  int64                      `json:"length,omitempty"`
+	Bitrate float64                    `json:"bitrate,omitempty"`
+	Height  int                        `json:"height,omitempty"`
+	Lang    string                     `json:"lang,omitempty"`
+	Title   string                     `json:"title,omitempty"`
+	Codecs  string                     `json:"codecs,omitempty"`
+	Default bool                       `json:"default,omitempty"`
+	Sources []AlternateEnclosureSource `json:"sources"`
+}
+
+// AlternateEnclosureSource represents one URI an alternate enclosure is published at
+type AlternateEnclosureSource struct {
+	URI         string `json:"uri"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
 // Person represents a person associated with an episode
 type Person struct {
 	Name  string `json:"name"`
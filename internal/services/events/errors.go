@@ -0,0 +1,12 @@
+package events
+
+import "errors"
+
+var (
+	// ErrInvalidEvent is returned when an event in a batch has an unknown type,
+	// no episode or a negative position
+	ErrInvalidEvent = errors.New("invalid playback event")
+
+	// ErrBatchTooLarge is returned when a batch holds more events than allowed
+	ErrBatchTooLarge = errors.New("too many events in batch")
+)
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Event is one playback event as reported by a client
+type Event struct {
+	Type         string    `json:"type" example:"play"` // play, pause, seek, complete or skip_ad
+	EpisodeID    int64     `json:"episode_id" example:"16797088990"`
+	PodcastID    int64     `json:"podcast_id,omitempty" example:"6780065"`  // Ignored; the podcast is resolved from the stored episode
+	Position     float64   `json:"position" example:"1834.5"`               // Seconds into the episode; the target of a seek or skip
+	FromPosition float64   `json:"from_position,omitempty" example:"1790"`  // Where a seek or skip started
+	SessionID    string    `json:"session_id,omitempty" example:"4f9c2a1e"` // Groups a listening session so it is sampled as a whole
+	OccurredAt   time.Time `json:"occurred_at,omitempty"`                   // Client time; defaults to when the batch arrives
+}
+
+// IngestResult counts what happened to a batch
+type IngestResult struct {
+	Received   int `json:"received"`
+	Stored     int `json:"stored"`
+	SampledOut int `json:"sampled_out"` // Dropped by events.sample_rate
+	Expired    int `json:"expired"`     // Older than the retention period
+}
+
+// Config controls ingestion
+type Config struct {
+	MaxBatch   int           // Events accepted per batch
+	SampleRate float64       // Fraction of anonymous sessions stored, in (0, 1]
+	Retention  time.Duration // Events older than this are dropped on arrival and pruned later
+}
+
+// Service ingests client playback analytics
+type Service interface {
+	// Ingest validates and stores a batch. userID is empty for anonymous
+	// listeners; signed-in listeners' events are never sampled out since they
+	// feed the user's listening stats.
+	Ingest(ctx context.Context, userID string, batch []Event) (*IngestResult, error)
+
+	// Purge deletes events that occurred before the cutoff
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Repository defines the interface for playback event persistence
+type Repository interface {
+	// Insert appends events in one batch
+	Insert(ctx context.Context, events []models.PlaybackEvent) error
+
+	// FeedIDsForEpisodes resolves the feeds of locally stored episodes
+	FeedIDsForEpisodes(ctx context.Context, episodeIDs []int64) (map[int64]int64, error)
+
+	// DeleteBefore removes events that occurred before the cutoff
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
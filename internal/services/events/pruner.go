@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Pruner periodically deletes playback events older than the retention period
+type Pruner struct {
+	service   Service
+	retention time.Duration
+	interval  time.Duration
+	cancel    context.CancelFunc
+}
+
+// NewPruner creates a new playback event pruner
+func NewPruner(service Service, retention, interval time.Duration) *Pruner {
+	return &Pruner{
+		service:   service,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Start prunes immediately and then every interval until Stop is called
+func (p *Pruner) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+
+	p.Prune(ctx)
+
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.Prune(ctx)
+			case <-ctx.Done():
+				log.Println("[INFO] Playback event pruner stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the pruner
+func (p *Pruner) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// Prune deletes events past the retention period
+func (p *Pruner) Prune(ctx context.Context) {
+	deleted, err := p.service.Purge(ctx, time.Now().UTC().Add(-p.retention))
+	if err != nil {
+		log.Printf("[ERROR] Failed to prune playback events: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[INFO] Pruned %d playback events older than %v", deleted, p.retention)
+	}
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// insertBatchSize keeps multi-row inserts under SQLite's variable limit
+const insertBatchSize = 100
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new playback event repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Insert appends events in one batch
+func (r *repository) Insert(ctx context.Context, events []models.PlaybackEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(events, insertBatchSize).Error
+}
+
+// FeedIDsForEpisodes resolves the feeds of locally stored episodes
+func (r *repository) FeedIDsForEpisodes(ctx context.Context, episodeIDs []int64) (map[int64]int64, error) {
+	var rows []struct {
+		PodcastIndexID     int64
+		PodcastIndexFeedID int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Episode{}).
+		Select("podcast_index_id, podcast_index_feed_id").
+		Where("podcast_index_id IN ?", episodeIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	feeds := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		feeds[row.PodcastIndexID] = row.PodcastIndexFeedID
+	}
+	return feeds, nil
+}
+
+// DeleteBefore removes events that occurred before the cutoff
+func (r *repository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("occurred_at < ?", before).Delete(&models.PlaybackEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// validTypes are the event types clients may report
+var validTypes = map[string]bool{
+	models.PlaybackEventPlay:     true,
+	models.PlaybackEventPause:    true,
+	models.PlaybackEventSeek:     true,
+	models.PlaybackEventComplete: true,
+	models.PlaybackEventSkipAd:   true,
+}
+
+// sessionIDLimit matches the session_id column size
+const sessionIDLimit = 64
+
+// service implements the Service interface
+type service struct {
+	repo   Repository
+	config Config
+	now    func() time.Time
+	random func() float64
+}
+
+// NewService creates a new playback event service. A sample rate outside
+// (0, 1] stores everything.
+func NewService(repo Repository, config Config) Service {
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		config.SampleRate = 1
+	}
+	return &service{repo: repo, config: config, now: time.Now, random: rand.Float64}
+}
+
+// Ingest validates and stores a batch. The whole batch is refused if any
+// event is invalid, so client bugs surface rather than skewing figures.
+func (s *service) Ingest(ctx context.Context, userID string, batch []Event) (*IngestResult, error) {
+	if s.config.MaxBatch > 0 && len(batch) > s.config.MaxBatch {
+		return nil, fmt.Errorf("%w: %d events, at most %d allowed", ErrBatchTooLarge, len(batch), s.config.MaxBatch)
+	}
+	for i := range batch {
+		if err := validate(&batch[i]); err != nil {
+			return nil, fmt.Errorf("%w: event %d: %s", ErrInvalidEvent, i, err)
+		}
+	}
+
+	now := s.now().UTC()
+	result := &IngestResult{Received: len(batch)}
+	weight := 1.0
+	if userID == "" {
+		weight = 1 / s.config.SampleRate
+	}
+
+	rows := make([]models.PlaybackEvent, 0, len(batch))
+	var episodeIDs []int64
+	for _, event := range batch {
+		occurredAt := event.OccurredAt.UTC()
+		if event.OccurredAt.IsZero() || occurredAt.After(now) {
+			occurredAt = now // Clients with fast clocks must not create events from the future
+		}
+		if s.config.Retention > 0 && occurredAt.Before(now.Add(-s.config.Retention)) {
+			result.Expired++
+			continue
+		}
+		if userID == "" && !s.keep(event.SessionID) {
+			result.SampledOut++
+			continue
+		}
+
+		rows = append(rows, models.PlaybackEvent{
+			ReceivedAt:            now,
+			OccurredAt:            occurredAt,
+			UserID:                userID,
+			SessionID:             event.SessionID,
+			PodcastIndexEpisodeID: event.EpisodeID,
+			Type:                  event.Type,
+			Position:              event.Position,
+			FromPosition:          event.FromPosition,
+			Weight:                weight,
+		})
+		episodeIDs = append(episodeIDs, event.EpisodeID)
+	}
+
+	// The feed always comes from the stored episode: a client-supplied one
+	// could credit plays to any podcast's analytics
+	if len(episodeIDs) > 0 {
+		feeds, err := s.repo.FeedIDsForEpisodes(ctx, episodeIDs)
+		if err != nil {
+			return nil, fmt.Errorf("resolving episode feeds: %w", err)
+		}
+		for i := range rows {
+			rows[i].PodcastIndexFeedID = feeds[rows[i].PodcastIndexEpisodeID]
+		}
+	}
+
+	if err := s.repo.Insert(ctx, rows); err != nil {
+		return nil, fmt.Errorf("storing events: %w", err)
+	}
+	result.Stored = len(rows)
+	return result, nil
+}
+
+// keep decides whether an anonymous event survives sampling. Sessions are
+// sampled by a hash of their ID so a session is stored in full or not at
+// all, across batches too; events without a session are sampled singly.
+func (s *service) keep(sessionID string) bool {
+	if s.config.SampleRate >= 1 {
+		return true
+	}
+	if sessionID == "" {
+		return s.random() < s.config.SampleRate
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(sessionID))
+	return float64(hash.Sum64()%10000) < s.config.SampleRate*10000
+}
+
+func validate(event *Event) error {
+	if !validTypes[event.Type] {
+		return fmt.Errorf("unknown type %q", event.Type)
+	}
+	if event.EpisodeID <= 0 {
+		return fmt.Errorf("episode_id is required")
+	}
+	if event.Position < 0 || event.FromPosition < 0 {
+		return fmt.Errorf("positions must not be negative")
+	}
+	if len(event.SessionID) > sessionIDLimit {
+		return fmt.Errorf("session_id longer than %d characters", sessionIDLimit)
+	}
+	return nil
+}
+
+// Purge deletes events that occurred before the cutoff
+func (s *service) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return s.repo.DeleteBefore(ctx, before)
+}
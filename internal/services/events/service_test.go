@@ -0,0 +1,146 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Episode{}, &models.PlaybackEvent{}))
+	return db
+}
+
+func newTestService(db *gorm.DB, config Config, now time.Time) *service {
+	svc := NewService(NewRepository(db), config).(*service)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestIngest(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestService(db, Config{MaxBatch: 10, Retention: 24 * time.Hour}, now)
+	ctx := context.Background()
+
+	require.NoError(t, db.Create(&models.Episode{
+		PodcastID: 1, PodcastIndexID: 7, PodcastIndexFeedID: 500,
+		Title: "Ep", GUID: "guid-7", AudioURL: "https://example.com/7.mp3",
+	}).Error)
+
+	result, err := svc.Ingest(ctx, "user-1", []Event{
+		{Type: models.PlaybackEventPlay, EpisodeID: 7, OccurredAt: now.Add(-time.Minute)},
+		{Type: models.PlaybackEventSkipAd, EpisodeID: 7, PodcastID: 900, FromPosition: 60, Position: 120, OccurredAt: now.Add(time.Hour)},
+		{Type: models.PlaybackEventPlay, EpisodeID: 8, PodcastID: 900},
+		{Type: models.PlaybackEventPause, EpisodeID: 9},
+		{Type: models.PlaybackEventComplete, EpisodeID: 7, OccurredAt: now.Add(-48 * time.Hour)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &IngestResult{Received: 5, Stored: 4, Expired: 1}, result)
+
+	var stored []models.PlaybackEvent
+	require.NoError(t, db.Order("id").Find(&stored).Error)
+	require.Len(t, stored, 4)
+	assert.Equal(t, int64(500), stored[0].PodcastIndexFeedID, "resolved from the stored episode")
+	assert.Equal(t, now.Add(-time.Minute), stored[0].OccurredAt.UTC())
+	assert.Equal(t, int64(500), stored[1].PodcastIndexFeedID, "client-supplied feed can't override the episode's")
+	assert.Equal(t, now, stored[1].OccurredAt.UTC(), "future times are clamped")
+	assert.Zero(t, stored[2].PodcastIndexFeedID, "client-supplied feed ignored")
+	assert.Zero(t, stored[3].PodcastIndexFeedID, "unknown episode")
+	assert.Equal(t, now, stored[3].OccurredAt.UTC(), "missing times default to arrival")
+	for _, event := range stored {
+		assert.Equal(t, "user-1", event.UserID)
+		assert.Equal(t, 1.0, event.Weight)
+	}
+}
+
+func TestIngest_RejectsInvalidBatches(t *testing.T) {
+	db := setupTestDB(t)
+	svc := newTestService(db, Config{MaxBatch: 2}, time.Now())
+	ctx := context.Background()
+
+	_, err := svc.Ingest(ctx, "", []Event{
+		{Type: models.PlaybackEventPlay, EpisodeID: 1},
+		{Type: models.PlaybackEventPlay, EpisodeID: 1},
+		{Type: models.PlaybackEventPlay, EpisodeID: 1},
+	})
+	assert.ErrorIs(t, err, ErrBatchTooLarge)
+
+	for _, event := range []Event{
+		{Type: "rewind", EpisodeID: 1},
+		{Type: models.PlaybackEventPlay},
+		{Type: models.PlaybackEventSeek, EpisodeID: 1, Position: -5},
+	} {
+		_, err := svc.Ingest(ctx, "", []Event{{Type: models.PlaybackEventPlay, EpisodeID: 1}, event})
+		assert.ErrorIs(t, err, ErrInvalidEvent, "%+v", event)
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&models.PlaybackEvent{}).Count(&count).Error)
+	assert.Zero(t, count, "nothing stored from a refused batch")
+}
+
+func TestIngest_SamplesAnonymousSessions(t *testing.T) {
+	db := setupTestDB(t)
+	svc := newTestService(db, Config{SampleRate: 0.25}, time.Now())
+	ctx := context.Background()
+
+	var batch []Event
+	for session := 0; session < 400; session++ {
+		for _, eventType := range []string{models.PlaybackEventPlay, models.PlaybackEventComplete} {
+			batch = append(batch, Event{Type: eventType, EpisodeID: 1, SessionID: fmt.Sprintf("session-%d", session)})
+		}
+	}
+	result, err := svc.Ingest(ctx, "", batch)
+	require.NoError(t, err)
+	assert.Equal(t, len(batch), result.Stored+result.SampledOut)
+	assert.InDelta(t, 200, result.Stored, 60, "about a quarter of sessions kept")
+
+	// Sessions are kept or dropped whole, and each row stands for four events
+	var rows []struct {
+		SessionID string
+		Events    int
+		Weight    float64
+	}
+	require.NoError(t, db.Model(&models.PlaybackEvent{}).
+		Select("session_id, COUNT(*) AS events, MAX(weight) AS weight").
+		Group("session_id").Scan(&rows).Error)
+	for _, row := range rows {
+		assert.Equal(t, 2, row.Events, row.SessionID)
+		assert.Equal(t, 4.0, row.Weight)
+	}
+
+	// Signed-in listeners are never sampled
+	result, err = svc.Ingest(ctx, "user-1", batch[:20])
+	require.NoError(t, err)
+	assert.Equal(t, 20, result.Stored)
+}
+
+func TestPurge(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now().UTC()
+	svc := newTestService(db, Config{}, now)
+	ctx := context.Background()
+
+	_, err := svc.Ingest(ctx, "", []Event{
+		{Type: models.PlaybackEventPlay, EpisodeID: 1, OccurredAt: now.Add(-72 * time.Hour)},
+		{Type: models.PlaybackEventPlay, EpisodeID: 1, OccurredAt: now.Add(-time.Hour)},
+	})
+	require.NoError(t, err)
+
+	deleted, err := svc.Purge(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
@@ -0,0 +1,9 @@
+package externalusage
+
+import "errors"
+
+var (
+	// ErrBudgetExceeded is returned instead of making a call once the day's
+	// budget for the external API is spent
+	ErrBudgetExceeded = errors.New("daily external API budget exceeded")
+)
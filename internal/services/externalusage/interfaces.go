@@ -0,0 +1,64 @@
+package externalusage
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// PodcastIndex is the service name Podcast Index calls are recorded under
+const PodcastIndex = "podcast_index"
+
+// DayUsage is one day's calls to an external API, totalled and per endpoint
+type DayUsage struct {
+	Day          string                    `json:"day"` // UTC date, YYYY-MM-DD
+	Calls        int64                     `json:"calls"`
+	Errors       int64                     `json:"errors"`
+	Rejected     int64                     `json:"rejected"`
+	AvgLatencyMs float64                   `json:"avg_latency_ms"`
+	MaxLatencyMs int64                     `json:"max_latency_ms"`
+	Endpoints    []models.ExternalAPIUsage `json:"endpoints"` // Busiest first
+}
+
+// Status is where an external API stands against today's budget
+type Status struct {
+	Service     string `json:"service"`
+	DailyBudget int64  `json:"daily_budget"` // 0 when unlimited
+	UsedToday   int64  `json:"used_today"`
+	Degraded    bool   `json:"degraded"` // Budget spent: calls are refused and stored data served until the next UTC day
+}
+
+// Tracker meters one external API's calls; a client calls Allow before each
+// request and Record after it
+type Tracker interface {
+	// Allow returns ErrBudgetExceeded when the call shouldn't be made
+	Allow(ctx context.Context, endpoint string) error
+
+	// Record counts a call that was made
+	Record(ctx context.Context, endpoint string, latency time.Duration, callErr error)
+}
+
+// Service accounts for calls to external APIs and enforces daily budgets
+type Service interface {
+	// Tracker returns the tracker for the named external API
+	Tracker(service string) Tracker
+
+	// Daily returns the named API's usage for the last days days, newest first
+	Daily(ctx context.Context, service string, days int) ([]DayUsage, error)
+
+	// Status returns the named API's standing against today's budget
+	Status(ctx context.Context, service string) (*Status, error)
+}
+
+// Repository defines usage persistence
+type Repository interface {
+	// Add adds usage's counts to its day and endpoint's row
+	Add(ctx context.Context, usage *models.ExternalAPIUsage) error
+
+	// ListSince returns the service's rows from since (a UTC date) on
+	ListSince(ctx context.Context, service, since string) ([]models.ExternalAPIUsage, error)
+
+	// CallsOn returns the service's total calls on day
+	CallsOn(ctx context.Context, service, day string) (int64, error)
+}
@@ -0,0 +1,65 @@
+package externalusage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new external usage repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Add adds usage's counts to its day and endpoint's row
+func (r *repository) Add(ctx context.Context, usage *models.ExternalAPIUsage) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "service"}, {Name: "endpoint"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"calls":            gorm.Expr("calls + ?", usage.Calls),
+			"errors":           gorm.Expr("errors + ?", usage.Errors),
+			"rejected":         gorm.Expr("rejected + ?", usage.Rejected),
+			"total_latency_ms": gorm.Expr("total_latency_ms + ?", usage.TotalLatencyMs),
+			"max_latency_ms":   gorm.Expr("CASE WHEN max_latency_ms < ? THEN ? ELSE max_latency_ms END", usage.MaxLatencyMs, usage.MaxLatencyMs),
+			"updated_at":       gorm.Expr("?", usage.UpdatedAt),
+		}),
+	}).Create(usage).Error
+	if err != nil {
+		return fmt.Errorf("recording external API usage: %w", err)
+	}
+	return nil
+}
+
+// ListSince returns the service's rows from since on, newest day first and
+// busiest endpoint first within a day
+func (r *repository) ListSince(ctx context.Context, service, since string) ([]models.ExternalAPIUsage, error) {
+	var rows []models.ExternalAPIUsage
+	err := r.db.WithContext(ctx).
+		Where("service = ? AND day >= ?", service, since).
+		Order("day DESC, calls DESC, endpoint ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("listing external API usage: %w", err)
+	}
+	return rows, nil
+}
+
+// CallsOn returns the service's total calls on day
+func (r *repository) CallsOn(ctx context.Context, service, day string) (int64, error) {
+	var calls int64
+	err := r.db.WithContext(ctx).Model(&models.ExternalAPIUsage{}).
+		Where("service = ? AND day = ?", service, day).
+		Select("COALESCE(SUM(calls), 0)").
+		Scan(&calls).Error
+	if err != nil {
+		return 0, fmt.Errorf("counting external API calls: %w", err)
+	}
+	return calls, nil
+}
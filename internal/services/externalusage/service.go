@@ -0,0 +1,184 @@
+package externalusage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// dayLayout is how days are keyed in the usage table
+const dayLayout = "2006-01-02"
+
+type service struct {
+	repo    Repository
+	budgets map[string]int64
+	now     func() time.Time
+
+	mu    sync.Mutex
+	today map[string]*dayCount // By service
+}
+
+// dayCount is a service's calls so far today, kept in memory so budget checks
+// don't hit the database on every call
+type dayCount struct {
+	day      string
+	calls    int64
+	degraded bool // The spent budget has been logged
+}
+
+// NewService creates a new external usage service. budgets holds daily call
+// budgets by service name; services without one are only counted.
+func NewService(repo Repository, budgets map[string]int64) Service {
+	return &service{
+		repo:    repo,
+		budgets: budgets,
+		now:     time.Now,
+		today:   make(map[string]*dayCount),
+	}
+}
+
+// Tracker returns the tracker for the named external API
+func (s *service) Tracker(service string) Tracker {
+	return &tracker{service: s, name: service}
+}
+
+// counter returns the service's count for today, loading it from the database
+// on the first call of a day so a restart doesn't reset the budget. Callers
+// hold s.mu.
+func (s *service) counter(ctx context.Context, service string) *dayCount {
+	day := s.now().UTC().Format(dayLayout)
+	count := s.today[service]
+	if count != nil && count.day == day {
+		return count
+	}
+
+	count = &dayCount{day: day}
+	calls, err := s.repo.CallsOn(ctx, service, day)
+	if err != nil {
+		log.Printf("[WARN] Loading today's %s usage, counting from zero: %v", service, err)
+	}
+	count.calls = calls
+	s.today[service] = count
+	return count
+}
+
+// allow counts a call against the service's budget, refusing it once the
+// budget is spent
+func (s *service) allow(ctx context.Context, service, endpoint string) error {
+	budget := s.budgets[service]
+
+	s.mu.Lock()
+	count := s.counter(ctx, service)
+	if budget > 0 && count.calls >= budget {
+		first := !count.degraded
+		count.degraded = true
+		s.mu.Unlock()
+
+		if first {
+			log.Printf("[WARN] %s daily budget of %d calls spent; serving stored data until the next UTC day", service, budget)
+		}
+		s.add(ctx, &models.ExternalAPIUsage{Service: service, Endpoint: endpoint, Rejected: 1})
+		return fmt.Errorf("calling %s %s: %w", service, endpoint, ErrBudgetExceeded)
+	}
+	count.calls++
+	s.mu.Unlock()
+	return nil
+}
+
+// record stores a call that was made
+func (s *service) record(ctx context.Context, service, endpoint string, latency time.Duration, callErr error) {
+	usage := &models.ExternalAPIUsage{
+		Service:        service,
+		Endpoint:       endpoint,
+		Calls:          1,
+		TotalLatencyMs: latency.Milliseconds(),
+		MaxLatencyMs:   latency.Milliseconds(),
+	}
+	if callErr != nil {
+		usage.Errors = 1
+	}
+	s.add(ctx, usage)
+}
+
+// add writes usage to today's row. Accounting never fails the call it
+// describes, so errors are only logged.
+func (s *service) add(ctx context.Context, usage *models.ExternalAPIUsage) {
+	now := s.now().UTC()
+	usage.Day = now.Format(dayLayout)
+	usage.UpdatedAt = now
+
+	// The call's own deadline may have passed by now
+	if err := s.repo.Add(context.WithoutCancel(ctx), usage); err != nil {
+		log.Printf("[WARN] %v", err)
+	}
+}
+
+// Daily returns the service's usage for the last days days, newest first.
+// Days without calls are left out.
+func (s *service) Daily(ctx context.Context, service string, days int) ([]DayUsage, error) {
+	if days < 1 {
+		days = 1
+	}
+	since := s.now().UTC().AddDate(0, 0, -(days - 1)).Format(dayLayout)
+
+	rows, err := s.repo.ListSince(ctx, service, since)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := []DayUsage{}
+	var totalLatency int64
+	for _, row := range rows {
+		if len(usage) == 0 || usage[len(usage)-1].Day != row.Day {
+			totalLatency = 0
+			usage = append(usage, DayUsage{Day: row.Day})
+		}
+		day := &usage[len(usage)-1]
+		day.Calls += row.Calls
+		day.Errors += row.Errors
+		day.Rejected += row.Rejected
+		day.MaxLatencyMs = max(day.MaxLatencyMs, row.MaxLatencyMs)
+		day.Endpoints = append(day.Endpoints, row)
+
+		totalLatency += row.TotalLatencyMs
+		if day.Calls > 0 {
+			day.AvgLatencyMs = float64(totalLatency) / float64(day.Calls)
+		}
+	}
+	return usage, nil
+}
+
+// Status returns the service's standing against today's budget
+func (s *service) Status(ctx context.Context, service string) (*Status, error) {
+	budget := s.budgets[service]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := s.counter(ctx, service)
+	return &Status{
+		Service:     service,
+		DailyBudget: budget,
+		UsedToday:   count.calls,
+		Degraded:    budget > 0 && count.calls >= budget,
+	}, nil
+}
+
+// tracker meters one external API's calls
+type tracker struct {
+	service *service
+	name    string
+}
+
+// Allow returns ErrBudgetExceeded when the call shouldn't be made
+func (t *tracker) Allow(ctx context.Context, endpoint string) error {
+	return t.service.allow(ctx, t.name, endpoint)
+}
+
+// Record counts a call that was made
+func (t *tracker) Record(ctx context.Context, endpoint string, latency time.Duration, callErr error) {
+	t.service.record(ctx, t.name, endpoint, latency, callErr)
+}
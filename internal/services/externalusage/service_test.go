@@ -0,0 +1,99 @@
+package externalusage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestService(t *testing.T, budget int64) (*service, Repository) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.ExternalAPIUsage{}))
+
+	repo := NewRepository(db)
+	svc := NewService(repo, map[string]int64{PodcastIndex: budget}).(*service)
+	svc.now = func() time.Time { return time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC) }
+	return svc, repo
+}
+
+func TestDaily_AggregatesByDayAndEndpoint(t *testing.T) {
+	svc, _ := setupTestService(t, 0)
+	ctx := context.Background()
+	tracker := svc.Tracker(PodcastIndex)
+
+	for _, latency := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond} {
+		require.NoError(t, tracker.Allow(ctx, "search/byterm"))
+		tracker.Record(ctx, "search/byterm", latency, nil)
+	}
+	require.NoError(t, tracker.Allow(ctx, "episodes/byfeedid"))
+	tracker.Record(ctx, "episodes/byfeedid", 200*time.Millisecond, errors.New("status 500"))
+
+	svc.now = func() time.Time { return time.Date(2026, 3, 13, 9, 0, 0, 0, time.UTC) }
+	tracker.Record(ctx, "categories/list", 50*time.Millisecond, nil)
+	svc.now = func() time.Time { return time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC) }
+
+	days, err := svc.Daily(ctx, PodcastIndex, 7)
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+
+	today := days[0]
+	assert.Equal(t, "2026-03-14", today.Day)
+	assert.Equal(t, int64(3), today.Calls)
+	assert.Equal(t, int64(1), today.Errors)
+	assert.Equal(t, int64(300), today.MaxLatencyMs)
+	assert.InDelta(t, 200, today.AvgLatencyMs, 0.001)
+	require.Len(t, today.Endpoints, 2)
+	assert.Equal(t, "search/byterm", today.Endpoints[0].Endpoint, "busiest endpoint first")
+	assert.Equal(t, int64(400), today.Endpoints[0].TotalLatencyMs)
+
+	assert.Equal(t, "2026-03-13", days[1].Day)
+
+	days, err = svc.Daily(ctx, PodcastIndex, 1)
+	require.NoError(t, err)
+	assert.Len(t, days, 1, "only today")
+}
+
+func TestAllow_EnforcesDailyBudget(t *testing.T) {
+	svc, repo := setupTestService(t, 2)
+	ctx := context.Background()
+	tracker := svc.Tracker(PodcastIndex)
+
+	require.NoError(t, tracker.Allow(ctx, "search/byterm"))
+	require.NoError(t, tracker.Allow(ctx, "search/byterm"))
+	assert.ErrorIs(t, tracker.Allow(ctx, "search/byterm"), ErrBudgetExceeded)
+
+	status, err := svc.Status(ctx, PodcastIndex)
+	require.NoError(t, err)
+	assert.True(t, status.Degraded)
+	assert.Equal(t, int64(2), status.UsedToday)
+
+	rows, err := repo.ListSince(ctx, PodcastIndex, "2026-03-14")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, int64(1), rows[0].Rejected)
+
+	// A new day starts a new budget
+	svc.now = func() time.Time { return time.Date(2026, 3, 15, 0, 0, 1, 0, time.UTC) }
+	assert.NoError(t, tracker.Allow(ctx, "search/byterm"))
+}
+
+func TestAllow_CountsEarlierCallsToday(t *testing.T) {
+	svc, _ := setupTestService(t, 2)
+	ctx := context.Background()
+	svc.Tracker(PodcastIndex).Record(ctx, "search/byterm", time.Millisecond, nil)
+	svc.Tracker(PodcastIndex).Record(ctx, "search/byterm", time.Millisecond, nil)
+
+	// As after a restart: today's stored calls count against the budget
+	assert.ErrorIs(t, svc.Tracker(PodcastIndex).Allow(ctx, "categories/list"), ErrBudgetExceeded)
+}
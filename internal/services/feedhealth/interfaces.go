@@ -0,0 +1,60 @@
+package feedhealth
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Config sets when failing feeds change status
+type Config struct {
+	DegradedAfter int           // Consecutive failures before a feed is degraded
+	DeadAfter     int           // Consecutive failures before a feed is dead...
+	DeadMinAge    time.Duration // ...provided the streak has lasted at least this long
+}
+
+// UnhealthyFeed is a degraded or dead feed with the podcast's stored details
+type UnhealthyFeed struct {
+	models.FeedHealth
+	Title   string `json:"title,omitempty"`
+	FeedURL string `json:"feed_url,omitempty"`
+}
+
+// Service tracks fetch failures per podcast
+type Service interface {
+	// RecordAudioFetch records the outcome of downloading an episode's audio;
+	// a nil err is a success. Episodes not stored locally are ignored, as are
+	// failures caused by ctx ending.
+	RecordAudioFetch(ctx context.Context, podcastIndexEpisodeID int64, err error)
+
+	// RecordFeedGone marks a feed dead because Podcast Index reports it dead
+	// or no longer returns it
+	RecordFeedGone(ctx context.Context, podcastIndexFeedID int64, reason string)
+
+	// RecordFeedAlive clears a dead status set by RecordFeedGone once Podcast
+	// Index returns the feed as live again
+	RecordFeedAlive(ctx context.Context, podcastIndexFeedID int64)
+
+	// Status returns the feed's status; feeds without failures are healthy
+	Status(ctx context.Context, podcastIndexFeedID int64) (models.FeedHealthStatus, error)
+
+	// ListUnhealthy returns degraded and dead feeds, or only those with status
+	// when it is set, worst first, with the total count
+	ListUnhealthy(ctx context.Context, status models.FeedHealthStatus, limit, offset int) ([]UnhealthyFeed, int64, error)
+}
+
+// Repository defines the interface for feed health persistence
+type Repository interface {
+	// Get returns the feed's health row, or nil if it has none
+	Get(ctx context.Context, podcastIndexFeedID int64) (*models.FeedHealth, error)
+
+	// Update loads the feed's row (a new healthy one if it has none), applies
+	// fn and saves it, in one transaction. fn returning false skips the save.
+	Update(ctx context.Context, podcastIndexFeedID int64, fn func(health *models.FeedHealth) bool) error
+
+	// FeedIDForEpisode resolves a stored episode's feed, 0 if it isn't stored
+	FeedIDForEpisode(ctx context.Context, podcastIndexEpisodeID int64) (int64, error)
+
+	ListUnhealthy(ctx context.Context, statuses []models.FeedHealthStatus, limit, offset int) ([]UnhealthyFeed, int64, error)
+}
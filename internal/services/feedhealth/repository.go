@@ -0,0 +1,83 @@
+package feedhealth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new feed health repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Get returns the feed's health row, or nil if it has none
+func (r *repository) Get(ctx context.Context, podcastIndexFeedID int64) (*models.FeedHealth, error) {
+	var health models.FeedHealth
+	err := r.db.WithContext(ctx).Where("podcast_index_feed_id = ?", podcastIndexFeedID).First(&health).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Update applies fn to the feed's row in a transaction
+func (r *repository) Update(ctx context.Context, podcastIndexFeedID int64, fn func(health *models.FeedHealth) bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		health := models.FeedHealth{PodcastIndexFeedID: podcastIndexFeedID, Status: models.FeedHealthy}
+		err := tx.Where("podcast_index_feed_id = ?", podcastIndexFeedID).First(&health).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if !fn(&health) {
+			return nil
+		}
+		return tx.Save(&health).Error
+	})
+}
+
+// FeedIDForEpisode resolves a stored episode's feed
+func (r *repository) FeedIDForEpisode(ctx context.Context, podcastIndexEpisodeID int64) (int64, error) {
+	var feedIDs []int64
+	err := r.db.WithContext(ctx).Model(&models.Episode{}).
+		Where("podcast_index_id = ?", podcastIndexEpisodeID).
+		Limit(1).
+		Pluck("podcast_index_feed_id", &feedIDs).Error
+	if err != nil || len(feedIDs) == 0 {
+		return 0, err
+	}
+	return feedIDs[0], nil
+}
+
+// ListUnhealthy returns feeds with the given statuses, dead first, then by
+// the length of their failure streak
+func (r *repository) ListUnhealthy(ctx context.Context, statuses []models.FeedHealthStatus, limit, offset int) ([]UnhealthyFeed, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.FeedHealth{}).Where("feed_health.status IN ?", statuses)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var feeds []UnhealthyFeed
+	err := query.
+		Select("feed_health.*, podcasts.title, podcasts.feed_url").
+		Joins("LEFT JOIN podcasts ON podcasts.podcast_index_id = feed_health.podcast_index_feed_id AND podcasts.deleted_at IS NULL").
+		Order("CASE feed_health.status WHEN 'dead' THEN 0 ELSE 1 END").
+		Order("feed_health.consecutive_failures DESC").
+		Order("feed_health.failing_since").
+		Limit(limit).
+		Offset(offset).
+		Scan(&feeds).Error
+	return feeds, total, err
+}
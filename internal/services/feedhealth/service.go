@@ -0,0 +1,178 @@
+package feedhealth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/download"
+)
+
+// maxErrorLength bounds the stored error message
+const maxErrorLength = 500
+
+// service implements the Service interface
+type service struct {
+	repo   Repository
+	config Config
+	now    func() time.Time
+}
+
+// NewService creates a new feed health service
+func NewService(repo Repository, config Config) Service {
+	if config.DegradedAfter <= 0 {
+		config.DegradedAfter = 3
+	}
+	if config.DeadAfter < config.DegradedAfter {
+		config.DeadAfter = config.DegradedAfter
+	}
+	return &service{repo: repo, config: config, now: time.Now}
+}
+
+// RecordAudioFetch records the outcome of an episode audio download
+func (s *service) RecordAudioFetch(ctx context.Context, podcastIndexEpisodeID int64, err error) {
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up; that says nothing about the feed
+		return
+	}
+
+	feedID, lookupErr := s.repo.FeedIDForEpisode(ctx, podcastIndexEpisodeID)
+	if lookupErr != nil {
+		log.Printf("[WARN] Failed to resolve feed of episode %d for health tracking: %v", podcastIndexEpisodeID, lookupErr)
+		return
+	}
+	if feedID == 0 {
+		return
+	}
+
+	if err == nil {
+		s.recordSuccess(ctx, feedID)
+		return
+	}
+
+	kind := models.FeedFailureAudioError
+	statusCode := 0
+	var statusErr *download.StatusError
+	if errors.As(err, &statusErr) {
+		statusCode = statusErr.StatusCode
+		if statusCode == http.StatusNotFound || statusCode == http.StatusGone {
+			kind = models.FeedFailureAudioGone
+		}
+	}
+	s.recordFailure(ctx, feedID, kind, statusCode, err.Error(), false)
+}
+
+// RecordFeedGone marks a feed dead
+func (s *service) RecordFeedGone(ctx context.Context, podcastIndexFeedID int64, reason string) {
+	s.recordFailure(ctx, podcastIndexFeedID, models.FeedFailureFeedGone, 0, reason, true)
+}
+
+// RecordFeedAlive clears a dead status set by RecordFeedGone
+func (s *service) RecordFeedAlive(ctx context.Context, podcastIndexFeedID int64) {
+	err := s.repo.Update(ctx, podcastIndexFeedID, func(health *models.FeedHealth) bool {
+		if health.Status != models.FeedDead || health.LastFailureKind != models.FeedFailureFeedGone {
+			return false
+		}
+		log.Printf("[INFO] Podcast Index lists feed %d as live again", podcastIndexFeedID)
+		health.Status = models.FeedHealthy
+		health.ConsecutiveFailures = 0
+		health.FailingSince = nil
+		return true
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to clear feed_gone status of feed %d: %v", podcastIndexFeedID, err)
+	}
+}
+
+func (s *service) recordSuccess(ctx context.Context, feedID int64) {
+	now := s.now().UTC()
+	err := s.repo.Update(ctx, feedID, func(health *models.FeedHealth) bool {
+		if health.ID == 0 {
+			// Healthy feeds don't get a row until something fails
+			return false
+		}
+		if health.LastFailureKind == models.FeedFailureFeedGone && health.Status == models.FeedDead {
+			// Audio still served from a CDN doesn't bring back a feed Podcast Index dropped
+			health.LastSuccessAt = &now
+			return true
+		}
+		if health.Status != models.FeedHealthy {
+			log.Printf("[INFO] Feed %d is healthy again after %d failures", feedID, health.ConsecutiveFailures)
+		}
+		health.Status = models.FeedHealthy
+		health.ConsecutiveFailures = 0
+		health.FailingSince = nil
+		health.LastSuccessAt = &now
+		return true
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to record fetch success for feed %d: %v", feedID, err)
+	}
+}
+
+func (s *service) recordFailure(ctx context.Context, feedID int64, kind models.FeedFailureKind, statusCode int, message string, gone bool) {
+	now := s.now().UTC()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+
+	err := s.repo.Update(ctx, feedID, func(health *models.FeedHealth) bool {
+		if health.FailingSince == nil {
+			health.FailingSince = &now
+		}
+		health.ConsecutiveFailures++
+		health.TotalFailures++
+		health.LastFailureAt = &now
+		health.LastFailureKind = kind
+		health.LastStatusCode = statusCode
+		health.LastError = message
+
+		status := s.statusFor(health, now, gone)
+		if status != health.Status {
+			log.Printf("[WARN] Feed %d is now %s after %d consecutive failures: %s", feedID, status, health.ConsecutiveFailures, message)
+		}
+		health.Status = status
+		return true
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to record %s failure for feed %d: %v", kind, feedID, err)
+	}
+}
+
+// statusFor applies the thresholds to a feed that just failed
+func (s *service) statusFor(health *models.FeedHealth, now time.Time, gone bool) models.FeedHealthStatus {
+	if gone || health.Status == models.FeedDead {
+		return models.FeedDead
+	}
+	if health.ConsecutiveFailures >= s.config.DeadAfter && now.Sub(*health.FailingSince) >= s.config.DeadMinAge {
+		return models.FeedDead
+	}
+	if health.ConsecutiveFailures >= s.config.DegradedAfter {
+		return models.FeedDegraded
+	}
+	return models.FeedHealthy
+}
+
+// Status returns the feed's status
+func (s *service) Status(ctx context.Context, podcastIndexFeedID int64) (models.FeedHealthStatus, error) {
+	health, err := s.repo.Get(ctx, podcastIndexFeedID)
+	if err != nil {
+		return "", err
+	}
+	if health == nil {
+		return models.FeedHealthy, nil
+	}
+	return health.Status, nil
+}
+
+// ListUnhealthy returns degraded and dead feeds
+func (s *service) ListUnhealthy(ctx context.Context, status models.FeedHealthStatus, limit, offset int) ([]UnhealthyFeed, int64, error) {
+	statuses := []models.FeedHealthStatus{models.FeedDegraded, models.FeedDead}
+	if status != "" {
+		statuses = []models.FeedHealthStatus{status}
+	}
+	return s.repo.ListUnhealthy(ctx, statuses, limit, offset)
+}
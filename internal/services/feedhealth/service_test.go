@@ -0,0 +1,119 @@
+package feedhealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/download"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Episode{}, &models.FeedHealth{}))
+
+	require.NoError(t, db.Create(&models.Podcast{PodcastIndexID: 500, Title: "Gone Show", FeedURL: "https://example.com/gone.xml"}).Error)
+	require.NoError(t, db.Create(&models.Episode{
+		PodcastID: 1, PodcastIndexID: 7, PodcastIndexFeedID: 500,
+		Title: "Ep", GUID: "guid-7", AudioURL: "https://example.com/7.mp3",
+	}).Error)
+	return db
+}
+
+func newTestService(db *gorm.DB, now *time.Time) *service {
+	svc := NewService(NewRepository(db), Config{DegradedAfter: 2, DeadAfter: 3, DeadMinAge: 24 * time.Hour}).(*service)
+	svc.now = func() time.Time { return *now }
+	return svc
+}
+
+func TestAudioFailuresDegradeThenKill(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestService(db, &now)
+	ctx := context.Background()
+	notFound := &download.StatusError{StatusCode: 404, Message: "server returned status 404"}
+
+	svc.RecordAudioFetch(ctx, 7, nil)
+	var count int64
+	require.NoError(t, db.Model(&models.FeedHealth{}).Count(&count).Error)
+	assert.Zero(t, count, "successes alone create no rows")
+
+	svc.RecordAudioFetch(ctx, 7, notFound)
+	status, err := svc.Status(ctx, 500)
+	require.NoError(t, err)
+	assert.Equal(t, models.FeedHealthy, status)
+
+	svc.RecordAudioFetch(ctx, 7, errors.New("connection reset"))
+	status, _ = svc.Status(ctx, 500)
+	assert.Equal(t, models.FeedDegraded, status)
+
+	svc.RecordAudioFetch(ctx, 7, notFound)
+	status, _ = svc.Status(ctx, 500)
+	assert.Equal(t, models.FeedDegraded, status, "not dead until failing for DeadMinAge")
+
+	now = now.Add(25 * time.Hour)
+	svc.RecordAudioFetch(ctx, 7, notFound)
+	status, _ = svc.Status(ctx, 500)
+	assert.Equal(t, models.FeedDead, status)
+
+	feeds, total, err := svc.ListUnhealthy(ctx, "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, feeds, 1)
+	assert.Equal(t, "Gone Show", feeds[0].Title)
+	assert.Equal(t, 4, feeds[0].ConsecutiveFailures)
+	assert.Equal(t, models.FeedFailureAudioGone, feeds[0].LastFailureKind)
+	assert.Equal(t, 404, feeds[0].LastStatusCode)
+
+	svc.RecordAudioFetch(ctx, 7, nil)
+	status, _ = svc.Status(ctx, 500)
+	assert.Equal(t, models.FeedHealthy, status, "a successful download revives the feed")
+}
+
+func TestCancelledFetchesAndUnknownEpisodesIgnored(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestService(db, &now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	svc.RecordAudioFetch(ctx, 7, context.Canceled)
+	svc.RecordAudioFetch(context.Background(), 99, errors.New("boom"))
+
+	var count int64
+	require.NoError(t, db.Model(&models.FeedHealth{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestFeedGone(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestService(db, &now)
+	ctx := context.Background()
+
+	svc.RecordFeedGone(ctx, 500, "marked dead by Podcast Index")
+	status, _ := svc.Status(ctx, 500)
+	assert.Equal(t, models.FeedDead, status, "Podcast Index is trusted immediately")
+
+	svc.RecordAudioFetch(ctx, 7, nil)
+	status, _ = svc.Status(ctx, 500)
+	assert.Equal(t, models.FeedDead, status, "audio still on a CDN doesn't revive a dropped feed")
+
+	feeds, _, err := svc.ListUnhealthy(ctx, models.FeedDegraded, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, feeds)
+
+	svc.RecordFeedAlive(ctx, 500)
+	status, _ = svc.Status(ctx, 500)
+	assert.Equal(t, models.FeedHealthy, status)
+}
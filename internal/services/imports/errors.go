@@ -0,0 +1,18 @@
+package imports
+
+import "errors"
+
+var (
+	// ErrUnsupportedFormat is returned for formats Parse doesn't know
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+
+	// ErrInvalidFile is returned when a file can't be read as its format
+	ErrInvalidFile = errors.New("invalid import file")
+
+	// ErrEmptyImport is returned when a file names no podcasts
+	ErrEmptyImport = errors.New("import file contains no podcasts")
+
+	// ErrTooLarge is returned when a file has more than MaxFeeds podcasts or
+	// MaxPlays episodes
+	ErrTooLarge = errors.New("import file is too large")
+)
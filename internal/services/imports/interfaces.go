@@ -0,0 +1,75 @@
+package imports
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/episodes"
+)
+
+// PodcastFinder resolves a feed URL or iTunes ID to a stored podcast,
+// fetching it from Podcast Index when it isn't stored yet
+type PodcastFinder interface {
+	FindPodcast(ctx context.Context, feedURL string, itunesID int64) (*models.Podcast, error)
+}
+
+// EpisodeFetcher fetches a podcast's episodes from Podcast Index, for plays
+// of episodes that aren't stored yet
+type EpisodeFetcher interface {
+	FetchAndSyncEpisodes(ctx context.Context, podcastIndexID int64, limit int) (*episodes.PodcastIndexResponse, error)
+}
+
+// Subscriber subscribes users to podcasts
+type Subscriber interface {
+	Subscribe(ctx context.Context, userID string, podcastIndexID int64, folder string, tags []string) (*models.Subscription, error)
+}
+
+// ProgressImporter records playback positions brought over from other apps
+type ProgressImporter interface {
+	ImportProgress(ctx context.Context, userID string, podcastIndexEpisodeID int64, position, duration float64, completed bool, at time.Time) (*models.PlaybackProgress, bool, error)
+}
+
+// Result summarizes an import
+type Result struct {
+	Feeds             int         `json:"feeds"`              // Podcasts in the file
+	Subscribed        int         `json:"subscribed"`         // New subscriptions
+	AlreadySubscribed int         `json:"already_subscribed"` // Podcasts the user was already subscribed to
+	Plays             int         `json:"plays"`              // Episodes with progress in the file
+	ProgressImported  int         `json:"progress_imported"`
+	ProgressKept      int         `json:"progress_kept"` // The user's own progress was newer
+	Unmatched         []Unmatched `json:"unmatched,omitempty"`
+	UnmatchedCount    int         `json:"unmatched_count"` // Unmatched may be cut short; this is every entry
+}
+
+// Unmatched is an entry that couldn't be imported
+type Unmatched struct {
+	Kind     string `json:"kind"` // "podcast" or "episode"
+	FeedURL  string `json:"feed_url,omitempty"`
+	ITunesID int64  `json:"itunes_id,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// Service imports subscriptions and listening history from other apps
+type Service interface {
+	// Import subscribes the user to the library's podcasts and records its
+	// playback positions. Entries that can't be matched to Podcast Index are
+	// listed in the result rather than failing the import. progress, when not
+	// nil, is called with the percentage done.
+	Import(ctx context.Context, userID string, library *Library, progress func(percent int)) (*Result, error)
+}
+
+// EpisodeKey is what a stored episode is matched on
+type EpisodeKey struct {
+	PodcastIndexID int64
+	GUID           string
+	AudioURL       string
+	Title          string
+}
+
+// Repository reads the stored episodes plays are matched against
+type Repository interface {
+	// EpisodeKeys returns the matching keys of a feed's stored episodes
+	EpisodeKeys(ctx context.Context, podcastIndexFeedID int64) ([]EpisodeKey, error)
+}
@@ -0,0 +1,461 @@
+package imports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is the kind of file an import comes from
+type Format string
+
+const (
+	// FormatOPML is an OPML subscription list, as exported by Apple Podcasts
+	// (through third-party tools), Pocket Casts and most other apps. Overcast's
+	// "extended" OPML also carries each episode's progress.
+	FormatOPML Format = "opml"
+
+	// FormatCSV is a table of subscriptions or listening history with a header
+	// row; see the column aliases below
+	FormatCSV Format = "csv"
+
+	// FormatJSON is {"subscriptions": [...], "history": [...]} or a bare array
+	// of history entries, with the same field names as the CSV columns
+	FormatJSON Format = "json"
+)
+
+// Limits on what one import may contain, so a job payload stays small enough
+// to store
+const (
+	MaxFeeds = 2000
+	MaxPlays = 20000
+)
+
+// Feed is a podcast to subscribe to
+type Feed struct {
+	FeedURL  string `json:"feed_url,omitempty"`
+	ITunesID int64  `json:"itunes_id,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Folder   string `json:"folder,omitempty"`
+}
+
+// Play is the user's progress in an episode of a podcast
+type Play struct {
+	FeedURL      string    `json:"feed_url,omitempty"`
+	ITunesID     int64     `json:"itunes_id,omitempty"`
+	GUID         string    `json:"guid,omitempty"`
+	EnclosureURL string    `json:"enclosure_url,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	Position     float64   `json:"position,omitempty"` // Seconds
+	Duration     float64   `json:"duration,omitempty"` // Seconds; 0 when unknown
+	Played       bool      `json:"played,omitempty"`
+	At           time.Time `json:"at,omitempty"` // When the app last saw this progress; zero when unknown
+}
+
+// Library is everything read from an import file
+type Library struct {
+	Feeds []Feed `json:"feeds,omitempty"`
+	Plays []Play `json:"plays,omitempty"`
+}
+
+// DetectFormat guesses a file's format from its name, content type and first
+// bytes, in that order of preference. It returns "" when nothing matches.
+func DetectFormat(filename, contentType string, data []byte) Format {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".opml", ".xml":
+		return FormatOPML
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	}
+	switch {
+	case strings.Contains(contentType, "xml") || strings.Contains(contentType, "opml"):
+		return FormatOPML
+	case strings.Contains(contentType, "csv"):
+		return FormatCSV
+	case strings.Contains(contentType, "json"):
+		return FormatJSON
+	}
+	switch trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff"); {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return FormatOPML
+	case bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[")):
+		return FormatJSON
+	case bytes.ContainsRune(trimmed, ','):
+		return FormatCSV
+	}
+	return ""
+}
+
+// Parse reads an import file. Entries that don't name a podcast are skipped;
+// a file with nothing to import returns ErrEmptyImport.
+func Parse(format Format, r io.Reader) (*Library, error) {
+	var (
+		library *Library
+		err     error
+	)
+	switch format {
+	case FormatOPML:
+		library, err = parseOPML(r)
+	case FormatCSV:
+		library, err = parseCSV(r)
+	case FormatJSON:
+		library, err = parseJSON(r)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	if len(library.Feeds) == 0 && len(library.Plays) == 0 {
+		return nil, ErrEmptyImport
+	}
+	if len(library.Feeds) > MaxFeeds || len(library.Plays) > MaxPlays {
+		return nil, fmt.Errorf("%w: %d podcasts and %d episodes (at most %d and %d)",
+			ErrTooLarge, len(library.Feeds), len(library.Plays), MaxFeeds, MaxPlays)
+	}
+	return library, nil
+}
+
+// outline is an OPML outline element. Overcast adds the episode attributes.
+type outline struct {
+	Type         string    `xml:"type,attr"`
+	Text         string    `xml:"text,attr"`
+	Title        string    `xml:"title,attr"`
+	XMLURL       string    `xml:"xmlUrl,attr"`
+	EnclosureURL string    `xml:"enclosureUrl,attr"`
+	Progress     string    `xml:"progress,attr"`
+	Played       string    `xml:"played,attr"`
+	UserUpdated  string    `xml:"userUpdatedDate,attr"`
+	Outlines     []outline `xml:"outline"`
+}
+
+func parseOPML(r io.Reader) (*Library, error) {
+	var doc struct {
+		Body struct {
+			Outlines []outline `xml:"outline"`
+		} `xml:"body"`
+	}
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false // Exports often carry unescaped ampersands
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	library := &Library{}
+	var walk func(outlines []outline, folder string)
+	walk = func(outlines []outline, folder string) {
+		for _, o := range outlines {
+			feedURL := strings.TrimSpace(o.XMLURL)
+			if o.Type == "podcast-episode" {
+				continue
+			}
+			if feedURL == "" {
+				// A folder of feeds. Overcast's export groups everything under
+				// "feeds", which isn't one of the user's folders.
+				name := strings.TrimSpace(firstNonEmpty(o.Text, o.Title))
+				if strings.EqualFold(name, "feeds") || strings.EqualFold(name, "playlists") {
+					name = folder
+				}
+				walk(o.Outlines, name)
+				continue
+			}
+
+			feed := Feed{FeedURL: feedURL, Title: strings.TrimSpace(firstNonEmpty(o.Title, o.Text)), Folder: folder}
+			feed.ITunesID, feed.FeedURL = splitFeedURL(feed.FeedURL)
+			library.Feeds = append(library.Feeds, feed)
+
+			for _, episode := range o.Outlines {
+				if episode.Type != "podcast-episode" {
+					continue
+				}
+				play := Play{
+					FeedURL:      feed.FeedURL,
+					ITunesID:     feed.ITunesID,
+					EnclosureURL: strings.TrimSpace(episode.EnclosureURL),
+					Title:        strings.TrimSpace(firstNonEmpty(episode.Title, episode.Text)),
+					Position:     parseSeconds(episode.Progress),
+					Played:       parseBool(episode.Played),
+					At:           parseTime(episode.UserUpdated),
+				}
+				if play.Position > 0 || play.Played {
+					library.Plays = append(library.Plays, play)
+				}
+			}
+		}
+	}
+	walk(doc.Body.Outlines, "")
+	return library, nil
+}
+
+// Column names accepted for each field, in CSV headers and JSON keys. Names
+// are compared lowercased with spaces and dashes as underscores.
+var (
+	feedURLColumns   = []string{"feed_url", "feed", "rss", "rss_url", "xmlurl", "url", "podcast_url", "podcast_feed_url"}
+	itunesIDColumns  = []string{"itunes_id", "apple_id", "apple_podcasts_id", "collection_id", "itunes_url", "apple_podcasts_url"}
+	feedTitleColumns = []string{"podcast", "podcast_title", "show", "show_title"}
+	folderColumns    = []string{"folder", "category", "group"}
+	guidColumns      = []string{"guid", "episode_guid"}
+	enclosureColumns = []string{"enclosure_url", "episode_url", "audio_url", "media_url"}
+	titleColumns     = []string{"title", "episode_title", "episode"}
+	positionColumns  = []string{"position", "progress", "played_up_to", "position_seconds", "playhead"}
+	durationColumns  = []string{"duration", "duration_seconds", "length"}
+	playedColumns    = []string{"played", "completed", "finished", "is_played"}
+	timeColumns      = []string{"updated_at", "listened_at", "played_at", "last_played", "date", "timestamp"}
+)
+
+// record is one CSV row or JSON object, keyed by normalized column name
+type record map[string]string
+
+func (r record) get(columns []string) string {
+	for _, column := range columns {
+		if value := strings.TrimSpace(r[column]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// add sorts a record into a feed to subscribe to, or a play when it names an
+// episode. Records without a podcast are dropped, since there is no way to
+// find their episodes.
+func (library *Library) add(r record, asPlay bool) {
+	itunesID, feedURL := splitFeedURL(r.get(feedURLColumns))
+	if id := parseITunesID(r.get(itunesIDColumns)); id > 0 {
+		itunesID = id
+	}
+	if feedURL == "" && itunesID == 0 {
+		return
+	}
+
+	guid, enclosure, title := r.get(guidColumns), r.get(enclosureColumns), r.get(titleColumns)
+	if !asPlay && guid == "" && enclosure == "" {
+		library.Feeds = append(library.Feeds, Feed{
+			FeedURL:  feedURL,
+			ITunesID: itunesID,
+			Title:    firstNonEmpty(r.get(feedTitleColumns), title),
+			Folder:   r.get(folderColumns),
+		})
+		return
+	}
+	if guid == "" && enclosure == "" && title == "" {
+		return
+	}
+	library.Plays = append(library.Plays, Play{
+		FeedURL:      feedURL,
+		ITunesID:     itunesID,
+		GUID:         guid,
+		EnclosureURL: enclosure,
+		Title:        title,
+		Position:     parseSeconds(r.get(positionColumns)),
+		Duration:     parseSeconds(r.get(durationColumns)),
+		Played:       parseBool(r.get(playedColumns)),
+		At:           parseTime(r.get(timeColumns)),
+	})
+}
+
+func parseCSV(r io.Reader) (*Library, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	for i := range header {
+		header[i] = normalizeColumn(header[i])
+	}
+
+	library := &Library{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		r := make(record, len(header))
+		for i, value := range row {
+			if i < len(header) {
+				r[header[i]] = value
+			}
+		}
+		library.add(r, false)
+	}
+	return library, nil
+}
+
+func parseJSON(r io.Reader) (*Library, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var document struct {
+		Subscriptions []map[string]any `json:"subscriptions"`
+		History       []map[string]any `json:"history"`
+	}
+	if trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff"); bytes.HasPrefix(trimmed, []byte("[")) {
+		err = json.Unmarshal(trimmed, &document.History)
+	} else {
+		err = json.Unmarshal(trimmed, &document)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	library := &Library{}
+	for _, object := range document.Subscriptions {
+		library.add(jsonRecord(object), false)
+	}
+	for _, object := range document.History {
+		library.add(jsonRecord(object), true)
+	}
+	return library, nil
+}
+
+// jsonRecord flattens a JSON object's scalar values into a record
+func jsonRecord(object map[string]any) record {
+	r := make(record, len(object))
+	for key, value := range object {
+		switch v := value.(type) {
+		case string:
+			r[normalizeColumn(key)] = v
+		case float64:
+			r[normalizeColumn(key)] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			r[normalizeColumn(key)] = strconv.FormatBool(v)
+		}
+	}
+	return r
+}
+
+func normalizeColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// appleIDPattern matches the show ID in an Apple Podcasts URL such as
+// https://podcasts.apple.com/us/podcast/some-show/id1234567890
+var appleIDPattern = regexp.MustCompile(`/id(\d+)`)
+
+// splitFeedURL returns the iTunes ID when the "feed URL" is really an Apple
+// Podcasts page, which has no feed of its own, and the feed URL otherwise
+func splitFeedURL(raw string) (int64, string) {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return 0, raw
+	}
+	if host := strings.ToLower(parsed.Host); host == "podcasts.apple.com" || host == "itunes.apple.com" {
+		return parseITunesID(raw), ""
+	}
+	if parsed.Scheme == "feed" || parsed.Scheme == "pcast" || parsed.Scheme == "itpc" {
+		parsed.Scheme = "https"
+		return 0, parsed.String()
+	}
+	return 0, raw
+}
+
+// parseITunesID reads an iTunes ID written as a number or an Apple Podcasts URL
+func parseITunesID(value string) int64 {
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil && id > 0 {
+		return id
+	}
+	if match := appleIDPattern.FindStringSubmatch(value); match != nil {
+		id, _ := strconv.ParseInt(match[1], 10, 64)
+		return id
+	}
+	return 0
+}
+
+// parseSeconds reads a time as seconds, milliseconds (values with an "ms"
+// suffix) or [hh:]mm:ss
+func parseSeconds(value string) float64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if ms, ok := strings.CutSuffix(value, "ms"); ok {
+		if n, err := strconv.ParseFloat(strings.TrimSpace(ms), 64); err == nil && n > 0 {
+			return n / 1000
+		}
+		return 0
+	}
+	if strings.Contains(value, ":") {
+		var seconds float64
+		for _, part := range strings.Split(value, ":") {
+			n, err := strconv.ParseFloat(part, 64)
+			if err != nil || n < 0 {
+				return 0
+			}
+			seconds = seconds*60 + n
+		}
+		return seconds
+	}
+	if n, err := strconv.ParseFloat(value, 64); err == nil && n > 0 {
+		return n
+	}
+	return 0
+}
+
+func parseBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "y", "played", "completed":
+		return true
+	}
+	return false
+}
+
+// timeLayouts are tried in order for timestamps that aren't Unix seconds
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+func parseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil && n > 0 {
+		if n > 1e12 {
+			return time.UnixMilli(n).UTC()
+		}
+		return time.Unix(n, 0).UTC()
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+package imports
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new import repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// EpisodeKeys returns the matching keys of a feed's stored episodes
+func (r *repository) EpisodeKeys(ctx context.Context, podcastIndexFeedID int64) ([]EpisodeKey, error) {
+	var keys []EpisodeKey
+	err := r.db.WithContext(ctx).Model(&models.Episode{}).
+		Select("podcast_index_id, guid, audio_url, title").
+		Where("podcast_index_feed_id = ?", podcastIndexFeedID).
+		Scan(&keys).Error
+	return keys, err
+}
@@ -0,0 +1,300 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+)
+
+const (
+	// episodeFetchLimit is how many of a podcast's episodes are fetched from
+	// Podcast Index to match plays that aren't stored (the API's maximum)
+	episodeFetchLimit = 1000
+
+	// maxUnmatchedReported bounds the unmatched entries listed in a result
+	maxUnmatchedReported = 100
+)
+
+// service implements the Service interface
+type service struct {
+	repo       Repository
+	podcasts   PodcastFinder
+	episodes   EpisodeFetcher
+	subscriber Subscriber
+	progress   ProgressImporter
+}
+
+// NewService creates a new import service
+func NewService(repo Repository, podcasts PodcastFinder, episodes EpisodeFetcher, subscriber Subscriber, progress ProgressImporter) Service {
+	return &service{
+		repo:       repo,
+		podcasts:   podcasts,
+		episodes:   episodes,
+		subscriber: subscriber,
+		progress:   progress,
+	}
+}
+
+// podcastKey identifies a podcast the way an import file does
+type podcastKey struct {
+	feedURL  string
+	itunesID int64
+}
+
+// importRun is the state of one Import call
+type importRun struct {
+	*service
+	userID   string
+	result   *Result
+	resolved map[podcastKey]*models.Podcast // Nil for keys that didn't resolve
+	report   func(percent int)
+	done     int
+	total    int
+}
+
+// Import subscribes the user to the library's podcasts, then records the
+// plays, grouped by podcast so each podcast's episodes are looked up once
+func (s *service) Import(ctx context.Context, userID string, library *Library, progress func(percent int)) (*Result, error) {
+	run := &importRun{
+		service:  s,
+		userID:   userID,
+		result:   &Result{Feeds: len(library.Feeds), Plays: len(library.Plays)},
+		resolved: make(map[podcastKey]*models.Podcast),
+		report:   progress,
+	}
+
+	byPodcast := make(map[podcastKey][]Play)
+	var order []podcastKey
+	for _, play := range library.Plays {
+		key := podcastKey{play.FeedURL, play.ITunesID}
+		if _, ok := byPodcast[key]; !ok {
+			order = append(order, key)
+		}
+		byPodcast[key] = append(byPodcast[key], play)
+	}
+	run.total = len(library.Feeds) + len(order)
+
+	for _, feed := range library.Feeds {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := run.subscribe(ctx, feed); err != nil {
+			return nil, err
+		}
+		run.step()
+	}
+
+	for _, key := range order {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := run.importPlays(ctx, key, byPodcast[key]); err != nil {
+			return nil, err
+		}
+		run.step()
+	}
+	return run.result, nil
+}
+
+// step reports that one podcast has been handled
+func (r *importRun) step() {
+	r.done++
+	if r.report != nil && r.total > 0 {
+		r.report(r.done * 100 / r.total)
+	}
+}
+
+// resolve finds a podcast once per import. Lookup failures are reported as
+// unmatched by the caller; only a cancelled context is returned as an error.
+func (r *importRun) resolve(ctx context.Context, key podcastKey) (*models.Podcast, string, error) {
+	if podcast, ok := r.resolved[key]; ok {
+		if podcast == nil {
+			return nil, "podcast not found in Podcast Index", nil
+		}
+		return podcast, "", nil
+	}
+	podcast, err := r.podcasts.FindPodcast(ctx, key.feedURL, key.itunesID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		log.Printf("[DEBUG] Import for user %s could not find podcast %q (iTunes %d): %v", r.userID, key.feedURL, key.itunesID, err)
+		r.resolved[key] = nil
+		return nil, "podcast not found in Podcast Index", nil
+	}
+	r.resolved[key] = podcast
+	return podcast, "", nil
+}
+
+func (r *importRun) subscribe(ctx context.Context, feed Feed) error {
+	podcast, reason, err := r.resolve(ctx, podcastKey{feed.FeedURL, feed.ITunesID})
+	if err != nil {
+		return err
+	}
+	if podcast == nil {
+		r.unmatched(Unmatched{Kind: "podcast", FeedURL: feed.FeedURL, ITunesID: feed.ITunesID, Title: feed.Title, Reason: reason})
+		return nil
+	}
+
+	_, err = r.subscriber.Subscribe(ctx, r.userID, podcast.PodcastIndexID, feed.Folder, nil)
+	if errors.Is(err, subscriptions.ErrInvalidSubscription) && feed.Folder != "" {
+		// A folder name this app doesn't allow shouldn't lose the subscription
+		_, err = r.subscriber.Subscribe(ctx, r.userID, podcast.PodcastIndexID, "", nil)
+	}
+	switch {
+	case err == nil:
+		r.result.Subscribed++
+	case errors.Is(err, subscriptions.ErrAlreadySubscribed):
+		r.result.AlreadySubscribed++
+	case ctx.Err() != nil:
+		return ctx.Err()
+	default:
+		log.Printf("[WARN] Import for user %s failed to subscribe to podcast %d: %v", r.userID, podcast.PodcastIndexID, err)
+		r.unmatched(Unmatched{Kind: "podcast", FeedURL: feed.FeedURL, ITunesID: feed.ITunesID, Title: feed.Title, Reason: "failed to subscribe"})
+	}
+	return nil
+}
+
+// importPlays matches one podcast's plays to episodes, first among the stored
+// episodes and then, for any left over, among those Podcast Index lists
+func (r *importRun) importPlays(ctx context.Context, key podcastKey, plays []Play) error {
+	podcast, reason, err := r.resolve(ctx, key)
+	if err != nil {
+		return err
+	}
+	if podcast == nil {
+		for _, play := range plays {
+			r.unmatched(Unmatched{Kind: "episode", FeedURL: play.FeedURL, ITunesID: play.ITunesID, Title: play.Title, Reason: reason})
+		}
+		return nil
+	}
+
+	stored, err := r.repo.EpisodeKeys(ctx, podcast.PodcastIndexID)
+	if err != nil {
+		return fmt.Errorf("loading episodes of podcast %d: %w", podcast.PodcastIndexID, err)
+	}
+	matcher := newEpisodeMatcher(stored)
+
+	var fetched *episodeMatcher
+	for _, play := range plays {
+		episodeID := matcher.match(play)
+		if episodeID == 0 && fetched == nil && r.episodes != nil {
+			fetched = newEpisodeMatcher(nil)
+			response, err := r.episodes.FetchAndSyncEpisodes(ctx, podcast.PodcastIndexID, episodeFetchLimit)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Printf("[WARN] Import for user %s failed to fetch episodes of podcast %d: %v", r.userID, podcast.PodcastIndexID, err)
+			} else {
+				fetched = newEpisodeMatcher(fetchedKeys(response))
+			}
+		}
+		if episodeID == 0 && fetched != nil {
+			episodeID = fetched.match(play)
+		}
+		if episodeID == 0 {
+			r.unmatched(Unmatched{Kind: "episode", FeedURL: play.FeedURL, ITunesID: play.ITunesID, Title: play.Title, Reason: "episode not found in the podcast's feed"})
+			continue
+		}
+
+		_, imported, err := r.progress.ImportProgress(ctx, r.userID, episodeID, play.Position, play.Duration, play.Played, play.At)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			log.Printf("[WARN] Import for user %s failed to record progress for episode %d: %v", r.userID, episodeID, err)
+			r.unmatched(Unmatched{Kind: "episode", FeedURL: play.FeedURL, ITunesID: play.ITunesID, Title: play.Title, Reason: "failed to record progress"})
+		case imported:
+			r.result.ProgressImported++
+		default:
+			r.result.ProgressKept++
+		}
+	}
+	return nil
+}
+
+func (r *importRun) unmatched(entry Unmatched) {
+	r.result.UnmatchedCount++
+	if len(r.result.Unmatched) < maxUnmatchedReported {
+		r.result.Unmatched = append(r.result.Unmatched, entry)
+	}
+}
+
+// fetchedKeys turns a Podcast Index episode listing into matching keys
+func fetchedKeys(response *episodes.PodcastIndexResponse) []EpisodeKey {
+	if response == nil {
+		return nil
+	}
+	keys := make([]EpisodeKey, 0, len(response.Items))
+	for _, item := range response.Items {
+		keys = append(keys, EpisodeKey{PodcastIndexID: item.ID, GUID: item.GUID, AudioURL: item.EnclosureURL, Title: item.Title})
+	}
+	return keys
+}
+
+// episodeMatcher finds an episode by GUID, then audio URL, then title. Apps
+// export whichever of these they kept, and audio URLs often differ only in
+// scheme or tracking parameters.
+type episodeMatcher struct {
+	byGUID  map[string]int64
+	byURL   map[string]int64
+	byTitle map[string]int64
+}
+
+func newEpisodeMatcher(keys []EpisodeKey) *episodeMatcher {
+	m := &episodeMatcher{
+		byGUID:  make(map[string]int64, len(keys)),
+		byURL:   make(map[string]int64, len(keys)),
+		byTitle: make(map[string]int64, len(keys)),
+	}
+	for _, key := range keys {
+		if key.PodcastIndexID == 0 {
+			continue
+		}
+		if key.GUID != "" {
+			m.byGUID[key.GUID] = key.PodcastIndexID
+		}
+		if audio := normalizeAudioURL(key.AudioURL); audio != "" {
+			m.byURL[audio] = key.PodcastIndexID
+		}
+		if title := normalizeTitle(key.Title); title != "" {
+			m.byTitle[title] = key.PodcastIndexID
+		}
+	}
+	return m
+}
+
+// match returns the Podcast Index ID of the play's episode, or 0
+func (m *episodeMatcher) match(play Play) int64 {
+	if id, ok := m.byGUID[play.GUID]; ok && play.GUID != "" {
+		return id
+	}
+	if id, ok := m.byURL[normalizeAudioURL(play.EnclosureURL)]; ok && play.EnclosureURL != "" {
+		return id
+	}
+	if id, ok := m.byTitle[normalizeTitle(play.Title)]; ok && play.Title != "" {
+		return id
+	}
+	return 0
+}
+
+// normalizeAudioURL drops the scheme and query of an audio URL
+func normalizeAudioURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	return strings.ToLower(parsed.Host) + parsed.EscapedPath()
+}
+
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
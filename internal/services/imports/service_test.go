@@ -0,0 +1,224 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/subscriptions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const overcastOPML = `<?xml version="1.0" encoding="utf-8"?>
+<opml version="1.0">
+  <head><title>Overcast Podcast Subscriptions</title></head>
+  <body>
+    <outline text="feeds">
+      <outline type="rss" text="Tech Talk" title="Tech Talk" xmlUrl="https://example.com/tech.xml">
+        <outline type="podcast-episode" title="Episode 2" enclosureUrl="http://cdn.example.com/tech/2.mp3?source=overcast" progress="754" played="0" userUpdatedDate="2024-03-01T10:00:00-05:00"/>
+        <outline type="podcast-episode" title="Episode 1" enclosureUrl="https://cdn.example.com/tech/1.mp3" played="1" userUpdatedDate="2024-02-01T10:00:00-05:00"/>
+        <outline type="podcast-episode" title="Unplayed" enclosureUrl="https://cdn.example.com/tech/0.mp3" played="0"/>
+      </outline>
+      <outline text="News">
+        <outline type="rss" text="Daily News" xmlUrl="feed://example.com/news.xml"/>
+      </outline>
+    </outline>
+  </body>
+</opml>`
+
+func TestParse_OvercastOPML(t *testing.T) {
+	format := DetectFormat("overcast.opml", "", nil)
+	require.Equal(t, FormatOPML, format)
+
+	library, err := Parse(format, strings.NewReader(overcastOPML))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Feed{
+		{FeedURL: "https://example.com/tech.xml", Title: "Tech Talk"},
+		{FeedURL: "https://example.com/news.xml", Title: "Daily News", Folder: "News"},
+	}, library.Feeds)
+
+	require.Len(t, library.Plays, 2, "episodes without progress are skipped")
+	assert.Equal(t, "Episode 2", library.Plays[0].Title)
+	assert.Equal(t, float64(754), library.Plays[0].Position)
+	assert.False(t, library.Plays[0].Played)
+	assert.Equal(t, time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC), library.Plays[0].At)
+	assert.True(t, library.Plays[1].Played)
+}
+
+func TestParse_CSV(t *testing.T) {
+	history := "Podcast URL,Episode GUID,Episode Title,Position,Duration,Completed,Listened At\n" +
+		"https://example.com/tech.xml,guid-1,Episode 1,01:02:03,3900,false,2024-03-01 10:00:00\n" +
+		",guid-2,No podcast,10,,,\n"
+	library, err := Parse(DetectFormat("", "text/csv", nil), strings.NewReader(history))
+	require.NoError(t, err)
+	assert.Empty(t, library.Feeds)
+	require.Len(t, library.Plays, 1, "rows without a podcast are dropped")
+	assert.Equal(t, Play{
+		FeedURL:  "https://example.com/tech.xml",
+		GUID:     "guid-1",
+		Title:    "Episode 1",
+		Position: 3723,
+		Duration: 3900,
+		At:       time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+	}, library.Plays[0])
+
+	subs := "title,url,folder\nTech Talk,https://podcasts.apple.com/us/podcast/tech-talk/id1234567,Work\n"
+	library, err = Parse(FormatCSV, strings.NewReader(subs))
+	require.NoError(t, err)
+	assert.Equal(t, []Feed{{ITunesID: 1234567, Title: "Tech Talk", Folder: "Work"}}, library.Feeds)
+}
+
+func TestParse_JSON(t *testing.T) {
+	document := `{
+		"subscriptions": [{"feed_url": "https://example.com/tech.xml"}],
+		"history": [{"itunes_id": 42, "episode_url": "https://cdn.example.com/1.mp3", "progress": "90000ms", "played": true, "timestamp": 1709287200}]
+	}`
+	require.Equal(t, FormatJSON, DetectFormat("", "", []byte(document)))
+
+	library, err := Parse(FormatJSON, strings.NewReader(document))
+	require.NoError(t, err)
+	assert.Equal(t, []Feed{{FeedURL: "https://example.com/tech.xml"}}, library.Feeds)
+	require.Len(t, library.Plays, 1)
+	assert.Equal(t, int64(42), library.Plays[0].ITunesID)
+	assert.Equal(t, float64(90), library.Plays[0].Position)
+	assert.True(t, library.Plays[0].Played)
+	assert.Equal(t, time.Unix(1709287200, 0).UTC(), library.Plays[0].At)
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse("xlsx", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, err = Parse(FormatJSON, strings.NewReader("{not json"))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+
+	_, err = Parse(FormatCSV, strings.NewReader("title,notes\nSomething,else\n"))
+	assert.ErrorIs(t, err, ErrEmptyImport)
+}
+
+// fakeFinder resolves podcasts stored in the test database by feed URL
+type fakeFinder struct {
+	db *gorm.DB
+}
+
+func (f *fakeFinder) FindPodcast(ctx context.Context, feedURL string, itunesID int64) (*models.Podcast, error) {
+	var podcast models.Podcast
+	if err := f.db.Where("feed_url = ?", feedURL).First(&podcast).Error; err != nil {
+		return nil, errors.New("podcast not found")
+	}
+	return &podcast, nil
+}
+
+// fakeFetcher lists the episodes Podcast Index knows but the database doesn't
+type fakeFetcher struct {
+	items map[int64][]episodes.PodcastIndexEpisode
+	calls int
+}
+
+func (f *fakeFetcher) FetchAndSyncEpisodes(ctx context.Context, podcastIndexID int64, limit int) (*episodes.PodcastIndexResponse, error) {
+	f.calls++
+	return &episodes.PodcastIndexResponse{Items: f.items[podcastIndexID]}, nil
+}
+
+type fakeSubscriber struct {
+	subscribed map[int64]string // Podcast Index ID to folder
+}
+
+func (f *fakeSubscriber) Subscribe(ctx context.Context, userID string, podcastIndexID int64, folder string, tags []string) (*models.Subscription, error) {
+	if _, ok := f.subscribed[podcastIndexID]; ok {
+		return nil, subscriptions.ErrAlreadySubscribed
+	}
+	if folder == "Bad/Folder" {
+		return nil, subscriptions.ErrInvalidSubscription
+	}
+	f.subscribed[podcastIndexID] = folder
+	return &models.Subscription{}, nil
+}
+
+type importedProgress struct {
+	episodeID int64
+	position  float64
+	completed bool
+}
+
+type fakeProgress struct {
+	imported []importedProgress
+	keep     map[int64]bool // Episodes whose existing progress is newer
+}
+
+func (f *fakeProgress) ImportProgress(ctx context.Context, userID string, episodeID int64, position, duration float64, completed bool, at time.Time) (*models.PlaybackProgress, bool, error) {
+	if f.keep[episodeID] {
+		return &models.PlaybackProgress{}, false, nil
+	}
+	f.imported = append(f.imported, importedProgress{episodeID, position, completed})
+	return &models.PlaybackProgress{}, true, nil
+}
+
+func TestImport(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Episode{}))
+
+	tech := models.Podcast{PodcastIndexID: 100, Title: "Tech Talk", FeedURL: "https://example.com/tech.xml"}
+	news := models.Podcast{PodcastIndexID: 200, Title: "Daily News", FeedURL: "https://example.com/news.xml"}
+	require.NoError(t, db.Create(&tech).Error)
+	require.NoError(t, db.Create(&news).Error)
+	require.NoError(t, db.Create(&models.Episode{
+		PodcastID: tech.ID, PodcastIndexID: 1001, PodcastIndexFeedID: 100,
+		Title: "Episode 1", GUID: "guid-1", AudioURL: "https://cdn.example.com/tech/1.mp3",
+	}).Error)
+
+	fetcher := &fakeFetcher{items: map[int64][]episodes.PodcastIndexEpisode{
+		100: {{ID: 1002, GUID: "guid-2", Title: "Episode 2", EnclosureURL: "https://cdn.example.com/tech/2.mp3"}},
+	}}
+	subscriber := &fakeSubscriber{subscribed: map[int64]string{200: ""}}
+	progress := &fakeProgress{keep: map[int64]bool{1001: true}}
+	svc := NewService(NewRepository(db), &fakeFinder{db: db}, fetcher, subscriber, progress)
+
+	library := &Library{
+		Feeds: []Feed{
+			{FeedURL: "https://example.com/tech.xml", Folder: "Bad/Folder"},
+			{FeedURL: "https://example.com/news.xml"},
+			{FeedURL: "https://example.com/gone.xml", Title: "Gone"},
+		},
+		Plays: []Play{
+			{FeedURL: "https://example.com/tech.xml", Title: " episode  1 ", Played: true},
+			{FeedURL: "https://example.com/tech.xml", EnclosureURL: "http://CDN.example.com/tech/2.mp3?source=app", Position: 300},
+			{FeedURL: "https://example.com/tech.xml", GUID: "guid-missing", Title: "Episode 9"},
+			{FeedURL: "https://example.com/gone.xml", Title: "Lost"},
+		},
+	}
+
+	var reported []int
+	result, err := svc.Import(context.Background(), "user-1", library, func(percent int) { reported = append(reported, percent) })
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Feeds)
+	assert.Equal(t, 1, result.Subscribed)
+	assert.Equal(t, 1, result.AlreadySubscribed)
+	assert.Equal(t, "", subscriber.subscribed[100], "an invalid folder falls back to none")
+
+	assert.Equal(t, 4, result.Plays)
+	assert.Equal(t, 1, result.ProgressImported)
+	assert.Equal(t, 1, result.ProgressKept)
+	assert.Equal(t, []importedProgress{{1002, 300, false}}, progress.imported)
+	assert.Equal(t, 1, fetcher.calls, "a podcast's episodes are fetched at most once")
+
+	assert.Equal(t, 3, result.UnmatchedCount)
+	require.Len(t, result.Unmatched, 3)
+	assert.Equal(t, "podcast", result.Unmatched[0].Kind)
+	assert.Equal(t, "Gone", result.Unmatched[0].Title)
+	assert.Equal(t, "Episode 9", result.Unmatched[1].Title)
+	assert.Equal(t, "Lost", result.Unmatched[2].Title)
+
+	assert.Equal(t, []int{20, 40, 60, 80, 100}, reported)
+}
@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"time"
 
 	"github.com/killallgit/player-api/internal/models"
 )
@@ -17,6 +18,7 @@ type Service interface {
 	GetJobStatus(ctx context.Context, jobID uint) (models.JobStatus, error)
 	GetJobForWaveform(ctx context.Context, podcastIndexEpisodeID int64) (*models.Job, error)
 	GetJobForTranscription(ctx context.Context, podcastIndexEpisodeID int64) (*models.Job, error)
+	GetJobForSummary(ctx context.Context, podcastIndexEpisodeID int64) (*models.Job, error)
 
 	// Worker operations (used by worker pool)
 	ClaimNextJob(ctx context.Context, workerID string, jobTypes []models.JobType) (*models.Job, error)
@@ -25,15 +27,35 @@ type Service interface {
 	FailJob(ctx context.Context, jobID uint, err error) error
 	FailJobWithDetails(ctx context.Context, jobID uint, errorType models.JobErrorType, errorCode, errorMsg, errorDetails string) error
 	ReleaseJob(ctx context.Context, jobID uint) error
+	// Heartbeat tells the reaper the job's worker is still alive
+	Heartbeat(ctx context.Context, jobID uint) error
+	// ReapStaleJobs fails processing jobs whose heartbeat is older than
+	// heartbeatTimeout or that have run past their type's timeout, so they are
+	// retried instead of staying in processing forever. It returns the reaped jobs.
+	ReapStaleJobs(ctx context.Context, heartbeatTimeout time.Duration, timeouts Timeouts) ([]*models.Job, error)
+	// FailDependents cascades a job's permanent failure or cancellation to the
+	// jobs waiting on it, returning the jobs it failed
+	FailDependents(ctx context.Context, jobID uint) ([]*models.Job, error)
 
 	// Manual retry operations
 	RetryFailedJob(ctx context.Context, jobID uint) (*models.Job, error)
 
+	// CancelJob stops a job that hasn't finished: a queued job never starts,
+	// and a running one has its context cancelled. The jobs waiting on it fail.
+	// Returns ErrJobFinished for jobs that completed or failed for good.
+	CancelJob(ctx context.Context, jobID uint) (*models.Job, error)
+	// OnCancel registers a hook run with the ID of each job CancelJob cancels,
+	// so workers in this process can stop it without waiting for a heartbeat
+	OnCancel(hook CancelHook)
+
 	// Maintenance
 	CleanupOldJobs(ctx context.Context, retentionDays int) (int64, error)
 	DeletePermanentlyFailedJob(ctx context.Context, jobID uint) error
 }
 
+// CancelHook is called after a job is cancelled, on the canceller's goroutine
+type CancelHook func(jobID uint)
+
 // JobOption is a functional option for configuring jobs
 type JobOption func(*jobConfig)
 
@@ -42,6 +64,7 @@ type jobConfig struct {
 	Priority   int
 	MaxRetries int
 	CreatedBy  string
+	DependsOn  []uint
 }
 
 // WithPriority sets the priority of a job (higher = more priority)
@@ -64,3 +87,55 @@ func WithCreatedBy(createdBy string) JobOption {
 		cfg.CreatedBy = createdBy
 	}
 }
+
+// WithDependsOn makes the job wait until the given jobs have completed. If any
+// of them fails permanently or is cancelled, the job fails with it. Ignored
+// when EnqueueUniqueJob returns an existing job.
+func WithDependsOn(jobIDs ...uint) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.DependsOn = append(cfg.DependsOn, jobIDs...)
+	}
+}
+
+// Timeouts limits how long a job may run, per job type
+type Timeouts struct {
+	Default time.Duration                    // Applies to types without their own limit; 0 for none
+	ByType  map[models.JobType]time.Duration // Per-type overrides; 0 for none
+}
+
+// For returns the execution limit for a job type, 0 meaning unlimited
+func (t Timeouts) For(jobType models.JobType) time.Duration {
+	if timeout, ok := t.ByType[jobType]; ok {
+		return timeout
+	}
+	return t.Default
+}
+
+// QueueLimits caps how many jobs of each type may wait to be claimed, so a
+// burst of requests can't grow the queue faster than workers drain it
+type QueueLimits struct {
+	Default int                    // Applies to types without their own limit; 0 for none
+	ByType  map[models.JobType]int // Per-type overrides; 0 for none
+	// LowPriorityShare is the fraction of a type's limit that jobs below
+	// DefaultPriority may fill, leaving the rest for requests a user is waiting on
+	LowPriorityShare float64
+	RetryAfter       time.Duration // Suggested wait reported with ErrQueueFull
+}
+
+// For returns the queue depth limit for a job type, 0 meaning unlimited
+func (l QueueLimits) For(jobType models.JobType) int {
+	if limit, ok := l.ByType[jobType]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// forPriority returns the limit for a job type and priority. Low priority
+// jobs get LowPriorityShare of it, but always at least one slot.
+func (l QueueLimits) forPriority(jobType models.JobType, priority int) int {
+	limit := l.For(jobType)
+	if limit <= 0 || priority >= DefaultPriority || l.LowPriorityShare <= 0 || l.LowPriorityShare >= 1 {
+		return limit
+	}
+	return max(1, int(float64(limit)*l.LowPriorityShare))
+}
@@ -16,18 +16,34 @@ var (
 	ErrJobNotFound       = errors.New("job not found")
 	ErrNoJobsAvailable   = errors.New("no jobs available")
 	ErrJobAlreadyClaimed = errors.New("job already claimed")
+	// ErrDependencyNotFound is returned when a job is created depending on a job that doesn't exist
+	ErrDependencyNotFound = errors.New("dependency job not found")
+	// ErrJobCancelled is returned when recording progress or an outcome for a
+	// job that was cancelled while it ran
+	ErrJobCancelled = errors.New("job cancelled")
+	// ErrJobFinished is returned when cancelling a job that already completed or failed for good
+	ErrJobFinished = errors.New("job already finished")
 )
 
 // Repository defines the interface for job persistence
 type Repository interface {
 	// Create operations
 	CreateJob(ctx context.Context, job *models.Job) error
+	// CreateUniqueJob inserts job unless a live job has the same DedupKey, in
+	// which case that job is returned and created is false
+	CreateUniqueJob(ctx context.Context, job *models.Job) (existing *models.Job, created bool, err error)
 
 	// Read operations
 	GetJob(ctx context.Context, id uint) (*models.Job, error)
 	GetJobByTypeAndPayload(ctx context.Context, jobType models.JobType, key, value string) (*models.Job, error)
 	GetPendingJobs(ctx context.Context, limit int) ([]*models.Job, error)
 	GetJobsByStatus(ctx context.Context, status models.JobStatus, limit int) ([]*models.Job, error)
+	// GetLiveJobByDedupKey returns the newest job with dedupKey that hasn't
+	// finished, or ErrJobNotFound
+	GetLiveJobByDedupKey(ctx context.Context, dedupKey string) (*models.Job, error)
+	// CountWaitingJobs counts the jobs of a type that are pending or failed
+	// with retries left, i.e. the ones a worker could still claim
+	CountWaitingJobs(ctx context.Context, jobType models.JobType) (int64, error)
 
 	// Update operations
 	ClaimNextJob(ctx context.Context, workerID string, jobTypes []models.JobType) (*models.Job, error)
@@ -37,6 +53,18 @@ type Repository interface {
 	FailJob(ctx context.Context, jobID uint, errorMsg string) error
 	FailJobWithDetails(ctx context.Context, jobID uint, errorType models.JobErrorType, errorCode, errorMsg, errorDetails string) error
 	ReleaseJob(ctx context.Context, jobID uint) error
+	// CancelJob cancels a job that hasn't finished and returns it; cancelling
+	// a cancelled job returns it unchanged
+	CancelJob(ctx context.Context, jobID uint) (*models.Job, error)
+	// Heartbeat records that the worker is still processing the job
+	Heartbeat(ctx context.Context, jobID uint) error
+	// FailStaleJob fails a processing job with a retryable system error, unless
+	// it has finished or moved to another worker since it was found stale.
+	// It reports whether the job was failed.
+	FailStaleJob(ctx context.Context, jobID uint, workerID, errorCode, errorMsg string) (bool, error)
+	// FailDependents permanently fails every job still waiting, directly or
+	// transitively, on jobID and returns them
+	FailDependents(ctx context.Context, jobID uint) ([]*models.Job, error)
 
 	// Delete operations
 	DeleteOldJobs(ctx context.Context, olderThan time.Time) (int64, error)
@@ -55,9 +83,168 @@ func NewRepository(db *gorm.DB) Repository {
 	}
 }
 
-// CreateJob creates a new job
+// CreateJob creates a new job along with its dependency edges
 func (r *repository) CreateJob(ctx context.Context, job *models.Job) error {
-	return r.db.WithContext(ctx).Create(job).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		return addDependencies(tx, job)
+	})
+}
+
+// CreateUniqueJob creates a job guarded by the live-job DedupKey index. The
+// insert and the conflict check are one statement, so concurrent callers can't
+// both create a job the way a lookup followed by an insert could.
+func (r *repository) CreateUniqueJob(ctx context.Context, job *models.Job) (*models.Job, bool, error) {
+	if job.DedupKey == nil {
+		return nil, false, fmt.Errorf("creating unique job: dedup key not set")
+	}
+
+	var created bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		created = true
+		return addDependencies(tx, job)
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("creating unique job: %w", err)
+	}
+	if created {
+		return job, true, nil
+	}
+
+	existing, err := r.GetLiveJobByDedupKey(ctx, *job.DedupKey)
+	if errors.Is(err, ErrJobNotFound) {
+		// The conflicting job finished between the insert and this read
+		return r.CreateUniqueJob(ctx, job)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+// GetLiveJobByDedupKey returns the newest unfinished job with dedupKey
+func (r *repository) GetLiveJobByDedupKey(ctx context.Context, dedupKey string) (*models.Job, error) {
+	var existing models.Job
+	err := r.db.WithContext(ctx).
+		Where("dedup_key = ?", dedupKey).
+		Where("status NOT IN ?", []models.JobStatus{
+			models.JobStatusCompleted,
+			models.JobStatusCancelled,
+			models.JobStatusPermanentlyFailed,
+		}).
+		Order("id DESC").
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting live job for %s: %w", dedupKey, err)
+	}
+	if err := loadDependencies(r.db.WithContext(ctx), &existing); err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// CountWaitingJobs counts claimable jobs of a type, including those still
+// waiting on dependencies
+func (r *repository) CountWaitingJobs(ctx context.Context, jobType models.JobType) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("type = ?", jobType).
+		Where("(status = ? OR (status = ? AND retry_count < max_retries))",
+			models.JobStatusPending, models.JobStatusFailed).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("counting waiting %s jobs: %w", jobType, err)
+	}
+	return count, nil
+}
+
+// addDependencies stores job's DependsOn edges. Dependencies must already
+// exist, which also rules out cycles. A job depending on one that has already
+// failed for good can never run, so it is failed on the spot.
+func addDependencies(tx *gorm.DB, job *models.Job) error {
+	if len(job.DependsOn) == 0 {
+		return nil
+	}
+
+	var parents []models.Job
+	if err := tx.Where("id IN ?", job.DependsOn).Find(&parents).Error; err != nil {
+		return fmt.Errorf("getting dependency jobs: %w", err)
+	}
+	found := make(map[uint]bool, len(parents))
+	for _, parent := range parents {
+		found[parent.ID] = true
+	}
+
+	edges := make([]models.JobDependency, 0, len(job.DependsOn))
+	for _, parentID := range job.DependsOn {
+		if !found[parentID] {
+			return fmt.Errorf("%w: %d", ErrDependencyNotFound, parentID)
+		}
+		if parentID == job.ID {
+			return fmt.Errorf("job %d cannot depend on itself", job.ID)
+		}
+		edges = append(edges, models.JobDependency{JobID: job.ID, DependsOnID: parentID})
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&edges).Error; err != nil {
+		return fmt.Errorf("creating job dependencies: %w", err)
+	}
+
+	for i := range parents {
+		if parent := &parents[i]; parent.Status == models.JobStatusPermanentlyFailed || parent.Status == models.JobStatusCancelled {
+			return failForDependency(tx, job, parent)
+		}
+	}
+	return nil
+}
+
+// failForDependency permanently fails job because parent failed or was cancelled
+func failForDependency(tx *gorm.DB, job *models.Job, parent *models.Job) error {
+	now := time.Now()
+	code := "dependency_failed"
+	if parent.Status == models.JobStatusCancelled {
+		code = "dependency_cancelled"
+	}
+	msg := fmt.Sprintf("dependency job %d (%s) did not complete", parent.ID, parent.Type)
+
+	updates := map[string]interface{}{
+		"status":         models.JobStatusPermanentlyFailed,
+		"error":          msg,
+		"error_type":     string(models.ErrorTypeDependency),
+		"error_code":     code,
+		"error_details":  fmt.Sprintf("dependency status: %s", parent.Status),
+		"last_failed_at": &now,
+		"completed_at":   &now,
+	}
+	if err := tx.Model(&models.Job{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failing job %d for dependency: %w", job.ID, err)
+	}
+
+	job.Status = models.JobStatusPermanentlyFailed
+	job.SetErrorDetails(models.ErrorTypeDependency, code, msg, fmt.Sprintf("dependency status: %s", parent.Status))
+	job.LastFailedAt = &now
+	job.CompletedAt = &now
+	return nil
+}
+
+// loadDependencies fills in job.DependsOn
+func loadDependencies(db *gorm.DB, job *models.Job) error {
+	job.DependsOn = nil
+	if err := db.Model(&models.JobDependency{}).
+		Where("job_id = ?", job.ID).
+		Order("depends_on_id").
+		Pluck("depends_on_id", &job.DependsOn).Error; err != nil {
+		return fmt.Errorf("getting job dependencies: %w", err)
+	}
+	return nil
 }
 
 // GetJob retrieves a job by ID
@@ -70,6 +257,9 @@ func (r *repository) GetJob(ctx context.Context, id uint) (*models.Job, error) {
 		}
 		return nil, fmt.Errorf("getting job: %w", err)
 	}
+	if err := loadDependencies(r.db.WithContext(ctx), &job); err != nil {
+		return nil, err
+	}
 	return &job, nil
 }
 
@@ -154,7 +344,11 @@ func (r *repository) ClaimNextJob(ctx context.Context, workerID string, jobTypes
 			Where("status IN ?", []models.JobStatus{models.JobStatusPending, models.JobStatusFailed}).
 			Where("status != ?", models.JobStatusPermanentlyFailed).
 			Where("(status = ? OR (status = ? AND retry_count < max_retries))",
-				models.JobStatusPending, models.JobStatusFailed)
+				models.JobStatusPending, models.JobStatusFailed).
+			// Jobs wait until everything they depend on has completed
+			Where(`NOT EXISTS (
+				SELECT 1 FROM job_dependencies d JOIN jobs parent ON parent.id = d.depends_on_id
+				WHERE d.job_id = jobs.id AND parent.status <> ?)`, models.JobStatusCompleted)
 
 		// Filter by job types if specified
 		if len(jobTypes) > 0 {
@@ -175,9 +369,10 @@ func (r *repository) ClaimNextJob(ctx context.Context, workerID string, jobTypes
 		// Update job status and worker
 		now := time.Now()
 		updates := map[string]interface{}{
-			"status":     models.JobStatusProcessing,
-			"worker_id":  workerID,
-			"started_at": &now,
+			"status":       models.JobStatusProcessing,
+			"worker_id":    workerID,
+			"started_at":   &now,
+			"heartbeat_at": &now,
 		}
 
 		// Increment retry count if this is a retry
@@ -193,6 +388,7 @@ func (r *repository) ClaimNextJob(ctx context.Context, workerID string, jobTypes
 		job.Status = models.JobStatusProcessing
 		job.WorkerID = workerID
 		job.StartedAt = &now
+		job.HeartbeatAt = &now
 		if job.Status == models.JobStatusFailed {
 			job.RetryCount++
 		}
@@ -216,17 +412,18 @@ func (r *repository) UpdateJobProgress(ctx context.Context, jobID uint, progress
 		progress = 100
 	}
 
+	// Progress is also a sign of life
 	result := r.db.WithContext(ctx).
 		Model(&models.Job{}).
 		Where("id = ? AND status = ?", jobID, models.JobStatusProcessing).
-		Update("progress", progress)
+		Updates(map[string]interface{}{"progress": progress, "heartbeat_at": time.Now()})
 
 	if result.Error != nil {
 		return fmt.Errorf("updating job progress: %w", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return ErrJobNotFound
+		return r.notProcessing(ctx, jobID)
 	}
 
 	return nil
@@ -260,9 +457,10 @@ func (r *repository) CompleteJob(ctx context.Context, jobID uint, result models.
 		"result":       result,
 	}
 
+	// A cancelled job stays cancelled even if its processor ran to the end
 	res := r.db.WithContext(ctx).
 		Model(&models.Job{}).
-		Where("id = ?", jobID).
+		Where("id = ? AND status <> ?", jobID, models.JobStatusCancelled).
 		Updates(updates)
 
 	if res.Error != nil {
@@ -270,7 +468,7 @@ func (r *repository) CompleteJob(ctx context.Context, jobID uint, result models.
 	}
 
 	if res.RowsAffected == 0 {
-		return ErrJobNotFound
+		return r.notProcessing(ctx, jobID)
 	}
 
 	return nil
@@ -293,13 +491,16 @@ func (r *repository) FailJobWithDetails(ctx context.Context, jobID uint, errorTy
 		}
 		return fmt.Errorf("finding job to fail: %w", err)
 	}
+	if job.Status == models.JobStatusCancelled {
+		return ErrJobCancelled
+	}
 
 	// Calculate new retry count
 	newRetryCount := job.RetryCount + 1
 
 	// Determine if job should be permanently failed
 	var status models.JobStatus
-	if newRetryCount >= job.MaxRetries || errorType == models.ErrorTypeNotFound {
+	if newRetryCount >= job.MaxRetries || !errorType.Retryable() {
 		status = models.JobStatusPermanentlyFailed
 	} else {
 		status = models.JobStatusFailed
@@ -323,7 +524,7 @@ func (r *repository) FailJobWithDetails(ctx context.Context, jobID uint, errorTy
 
 	if err := r.db.WithContext(ctx).
 		Model(&models.Job{}).
-		Where("id = ?", jobID).
+		Where("id = ? AND status <> ?", jobID, models.JobStatusCancelled).
 		Updates(updates).Error; err != nil {
 		return fmt.Errorf("failing job: %w", err)
 	}
@@ -334,10 +535,11 @@ func (r *repository) FailJobWithDetails(ctx context.Context, jobID uint, errorTy
 // ReleaseJob releases a job back to pending status (e.g., if worker crashes)
 func (r *repository) ReleaseJob(ctx context.Context, jobID uint) error {
 	updates := map[string]interface{}{
-		"status":     models.JobStatusPending,
-		"worker_id":  "",
-		"started_at": nil,
-		"progress":   0,
+		"status":       models.JobStatusPending,
+		"worker_id":    "",
+		"started_at":   nil,
+		"heartbeat_at": nil,
+		"progress":     0,
 	}
 
 	result := r.db.WithContext(ctx).
@@ -356,6 +558,159 @@ func (r *repository) ReleaseJob(ctx context.Context, jobID uint) error {
 	return nil
 }
 
+// Heartbeat refreshes a processing job's heartbeat
+func (r *repository) Heartbeat(ctx context.Context, jobID uint) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Job{}).
+		Where("id = ? AND status = ?", jobID, models.JobStatusProcessing).
+		Update("heartbeat_at", time.Now())
+
+	if result.Error != nil {
+		return fmt.Errorf("updating job heartbeat: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return r.notProcessing(ctx, jobID)
+	}
+
+	return nil
+}
+
+// notProcessing explains why an update guarded on a job's status matched no
+// row: ErrJobCancelled when it was cancelled, else ErrJobNotFound
+func (r *repository) notProcessing(ctx context.Context, jobID uint) error {
+	var job models.Job
+	err := r.db.WithContext(ctx).Select("status").First(&job, jobID).Error
+	if err == nil && job.Status == models.JobStatusCancelled {
+		return ErrJobCancelled
+	}
+	return ErrJobNotFound
+}
+
+// CancelJob moves a pending, processing or retrying job to cancelled. The
+// worker running it finds out from its next heartbeat, or sooner through the
+// service's cancel hooks.
+func (r *repository) CancelJob(ctx context.Context, jobID uint) (*models.Job, error) {
+	var job models.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&job, jobID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrJobNotFound
+			}
+			return fmt.Errorf("finding job to cancel: %w", err)
+		}
+		if job.Status == models.JobStatusCancelled {
+			return nil
+		}
+		if job.IsTerminal() {
+			return ErrJobFinished
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"status":       models.JobStatusCancelled,
+			"completed_at": &now,
+		}
+		if err := tx.Model(&job).Updates(updates).Error; err != nil {
+			return fmt.Errorf("cancelling job: %w", err)
+		}
+		job.Status = models.JobStatusCancelled
+		job.CompletedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FailStaleJob fails a job the reaper found abandoned. The status and worker
+// guard keeps it from failing a job that completed, or was reaped and claimed
+// again, after it was read.
+func (r *repository) FailStaleJob(ctx context.Context, jobID uint, workerID, errorCode, errorMsg string) (bool, error) {
+	var job models.Job
+	if err := r.db.WithContext(ctx).First(&job, jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, ErrJobNotFound
+		}
+		return false, fmt.Errorf("finding stale job: %w", err)
+	}
+
+	now := time.Now()
+	newRetryCount := job.RetryCount + 1
+	status := models.JobStatusFailed
+	if newRetryCount >= job.MaxRetries {
+		status = models.JobStatusPermanentlyFailed
+	}
+
+	updates := map[string]interface{}{
+		"status":         status,
+		"error":          errorMsg,
+		"error_type":     string(models.ErrorTypeSystem),
+		"error_code":     errorCode,
+		"error_details":  fmt.Sprintf("worker %s, started %v, last heartbeat %v", workerID, job.StartedAt, job.HeartbeatAt),
+		"last_failed_at": &now,
+		"retry_count":    newRetryCount,
+		"worker_id":      "",
+		"heartbeat_at":   nil,
+	}
+	if status == models.JobStatusPermanentlyFailed {
+		updates["completed_at"] = &now
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.Job{}).
+		Where("id = ? AND status = ? AND worker_id = ?", jobID, models.JobStatusProcessing, workerID).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failing stale job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FailDependents walks the dependency graph down from jobID, permanently
+// failing each job that hasn't started yet. Jobs already running or finished
+// are left alone, as are their own dependents.
+func (r *repository) FailDependents(ctx context.Context, jobID uint) ([]*models.Job, error) {
+	var failed []*models.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var root models.Job
+		if err := tx.First(&root, jobID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrJobNotFound
+			}
+			return fmt.Errorf("finding failed job: %w", err)
+		}
+
+		parents := []*models.Job{&root}
+		for len(parents) > 0 {
+			parent := parents[0]
+			parents = parents[1:]
+
+			var children []*models.Job
+			if err := tx.
+				Where("id IN (?)", tx.Model(&models.JobDependency{}).Select("job_id").Where("depends_on_id = ?", parent.ID)).
+				Where("status IN ?", []models.JobStatus{models.JobStatusPending, models.JobStatusFailed}).
+				Find(&children).Error; err != nil {
+				return fmt.Errorf("finding dependents of job %d: %w", parent.ID, err)
+			}
+
+			for _, child := range children {
+				if err := failForDependency(tx, child, parent); err != nil {
+					return err
+				}
+				failed = append(failed, child)
+				parents = append(parents, child)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return failed, nil
+}
+
 // DeleteOldJobs deletes jobs older than the specified time
 func (r *repository) DeleteOldJobs(ctx context.Context, olderThan time.Time) (int64, error) {
 	result := r.db.WithContext(ctx).
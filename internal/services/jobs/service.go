@@ -8,39 +8,66 @@ import (
 	"time"
 
 	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/tracing"
 )
 
 const (
 	DefaultMaxRetries = 3
 	DefaultPriority   = 0
+	// LowPriority is for background work nobody is waiting on, like
+	// pipeline prefetching; it is shed first when a queue fills up
+	LowPriority = -10
 )
 
+// ErrQueueFull is returned when a job type's queue is at its depth limit
+var ErrQueueFull = errors.New("job queue is full")
+
+// QueueFullError reports a rejected enqueue. It matches ErrQueueFull.
+type QueueFullError struct {
+	Type       models.JobType
+	Depth      int64
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("%s queue is full (%d waiting, limit %d)", e.Type, e.Depth, e.Limit)
+}
+
+func (e *QueueFullError) Is(target error) bool {
+	return target == ErrQueueFull
+}
+
 type service struct {
-	repo Repository
+	repo        Repository
+	cancelHooks []CancelHook
+	limits      QueueLimits
 }
 
-func NewService(repo Repository) Service {
-	return &service{
-		repo: repo,
+// ServiceOption is a functional option for configuring the service
+type ServiceOption func(*service)
+
+// WithQueueLimits caps how many jobs of each type may wait to be claimed
+func WithQueueLimits(limits QueueLimits) ServiceOption {
+	return func(s *service) {
+		s.limits = limits
 	}
 }
 
-func (s *service) EnqueueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, opts ...JobOption) (*models.Job, error) {
-	cfg := &jobConfig{
-		Priority:   DefaultPriority,
-		MaxRetries: DefaultMaxRetries,
+func NewService(repo Repository, opts ...ServiceOption) Service {
+	s := &service{
+		repo: repo,
 	}
 	for _, opt := range opts {
-		opt(cfg)
+		opt(s)
 	}
+	return s
+}
 
-	job := &models.Job{
-		Type:       jobType,
-		Status:     models.JobStatusPending,
-		Payload:    payload,
-		Priority:   cfg.Priority,
-		MaxRetries: cfg.MaxRetries,
-		CreatedBy:  cfg.CreatedBy,
+func (s *service) EnqueueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, opts ...JobOption) (*models.Job, error) {
+	job := newJob(ctx, jobType, payload, opts)
+	if err := s.admit(ctx, job); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.CreateJob(ctx, job); err != nil {
@@ -52,22 +79,88 @@ func (s *service) EnqueueJob(ctx context.Context, jobType models.JobType, payloa
 	return job, nil
 }
 
+// EnqueueUniqueJob enqueues a job unless one of the same type with the same
+// payload[uniqueKey] is still pending, processing or awaiting retry, in which
+// case that job is returned. Deduplication is enforced by the repository, so
+// it holds under concurrent requests for the same episode. When the queue is
+// full, a duplicate still gets the live job; only new work is refused.
 func (s *service) EnqueueUniqueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, uniqueKey string, opts ...JobOption) (*models.Job, error) {
 	uniqueValue, ok := payload[uniqueKey]
 	if !ok {
 		return nil, fmt.Errorf("unique key %s not found in payload", uniqueKey)
 	}
 
-	existingJob, err := s.repo.GetJobByTypeAndPayload(ctx, jobType, uniqueKey, fmt.Sprintf("%v", uniqueValue))
-	if err == nil && existingJob != nil {
-		if !existingJob.IsTerminal() {
-			log.Printf("[DEBUG] Job already exists for %s with %s=%v (ID: %d, Status: %s)",
-				jobType, uniqueKey, uniqueValue, existingJob.ID, existingJob.Status)
-			return existingJob, nil
+	job := newJob(ctx, jobType, payload, opts)
+	dedupKey := fmt.Sprintf("%s:%s=%v", jobType, uniqueKey, uniqueValue)
+	job.DedupKey = &dedupKey
+
+	if err := s.admit(ctx, job); err != nil {
+		existing, lookupErr := s.repo.GetLiveJobByDedupKey(ctx, dedupKey)
+		if lookupErr == nil {
+			log.Printf("[DEBUG] Queue full, returning existing %s job ID %d for %s=%v",
+				jobType, existing.ID, uniqueKey, uniqueValue)
+			return existing, nil
 		}
+		if !errors.Is(lookupErr, ErrJobNotFound) {
+			return nil, lookupErr
+		}
+		return nil, err
 	}
 
-	return s.EnqueueJob(ctx, jobType, payload, opts...)
+	job, created, err := s.repo.CreateUniqueJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("creating job: %w", err)
+	}
+	if !created {
+		log.Printf("[DEBUG] Job already exists for %s with %s=%v (ID: %d, Status: %s)",
+			jobType, uniqueKey, uniqueValue, job.ID, job.Status)
+		return job, nil
+	}
+
+	log.Printf("[DEBUG] Enqueued %s job ID %d with priority %d", jobType, job.ID, job.Priority)
+	return job, nil
+}
+
+// admit refuses job with a QueueFullError when its type already has as many
+// waiting jobs as its priority is allowed
+func (s *service) admit(ctx context.Context, job *models.Job) error {
+	limit := s.limits.forPriority(job.Type, job.Priority)
+	if limit <= 0 {
+		return nil
+	}
+
+	depth, err := s.repo.CountWaitingJobs(ctx, job.Type)
+	if err != nil {
+		return err
+	}
+	if depth < int64(limit) {
+		return nil
+	}
+
+	log.Printf("[WARN] Refusing %s job with priority %d: %d waiting, limit %d",
+		job.Type, job.Priority, depth, limit)
+	return &QueueFullError{Type: job.Type, Depth: depth, Limit: limit, RetryAfter: s.limits.RetryAfter}
+}
+
+func newJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, opts []JobOption) *models.Job {
+	cfg := &jobConfig{
+		Priority:   DefaultPriority,
+		MaxRetries: DefaultMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &models.Job{
+		Type:         jobType,
+		Status:       models.JobStatusPending,
+		Payload:      payload,
+		Priority:     cfg.Priority,
+		MaxRetries:   cfg.MaxRetries,
+		CreatedBy:    cfg.CreatedBy,
+		DependsOn:    cfg.DependsOn,
+		TraceContext: tracing.Inject(ctx),
+	}
 }
 
 func (s *service) GetJob(ctx context.Context, jobID uint) (*models.Job, error) {
@@ -111,6 +204,17 @@ func (s *service) GetJobForTranscription(ctx context.Context, podcastIndexEpisod
 	return job, nil
 }
 
+func (s *service) GetJobForSummary(ctx context.Context, podcastIndexEpisodeID int64) (*models.Job, error) {
+	job, err := s.repo.GetJobByTypeAndPayload(ctx, models.JobTypeSummaryGeneration, "episode_id", fmt.Sprintf("%d", podcastIndexEpisodeID))
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("getting job for summary: %w", err)
+	}
+	return job, nil
+}
+
 func (s *service) ClaimNextJob(ctx context.Context, workerID string, jobTypes []models.JobType) (*models.Job, error) {
 	job, err := s.repo.ClaimNextJob(ctx, workerID, jobTypes)
 	if err != nil {
@@ -187,6 +291,124 @@ func (s *service) ReleaseJob(ctx context.Context, jobID uint) error {
 	return nil
 }
 
+func (s *service) CancelJob(ctx context.Context, jobID uint) (*models.Job, error) {
+	wasRunning := false
+	if job, err := s.repo.GetJob(ctx, jobID); err == nil {
+		wasRunning = job.Status == models.JobStatusProcessing
+	}
+
+	job, err := s.repo.CancelJob(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) || errors.Is(err, ErrJobFinished) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("cancelling job: %w", err)
+	}
+
+	if dependents, err := s.repo.FailDependents(ctx, jobID); err != nil {
+		log.Printf("[WARN] Failed to fail dependents of cancelled job %d: %v", jobID, err)
+	} else if len(dependents) > 0 {
+		log.Printf("[DEBUG] Cancelling job %d failed %d dependent jobs", jobID, len(dependents))
+	}
+
+	if wasRunning {
+		for _, hook := range s.cancelHooks {
+			hook(jobID)
+		}
+	}
+
+	log.Printf("[INFO] Job %d (%s) cancelled", jobID, job.Type)
+	return job, nil
+}
+
+// OnCancel registers a hook run for each cancelled job that was running.
+// Hooks are registered during startup, before any job is cancelled.
+func (s *service) OnCancel(hook CancelHook) {
+	s.cancelHooks = append(s.cancelHooks, hook)
+}
+
+func (s *service) Heartbeat(ctx context.Context, jobID uint) error {
+	if err := s.repo.Heartbeat(ctx, jobID); err != nil {
+		if errors.Is(err, ErrJobNotFound) || errors.Is(err, ErrJobCancelled) {
+			return err
+		}
+		return fmt.Errorf("recording job heartbeat: %w", err)
+	}
+	return nil
+}
+
+func (s *service) ReapStaleJobs(ctx context.Context, heartbeatTimeout time.Duration, timeouts Timeouts) ([]*models.Job, error) {
+	processing, err := s.repo.GetJobsByStatus(ctx, models.JobStatusProcessing, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing processing jobs: %w", err)
+	}
+
+	now := time.Now()
+	var reaped []*models.Job
+	for _, job := range processing {
+		code, message := staleReason(job, now, heartbeatTimeout, timeouts)
+		if code == "" {
+			continue
+		}
+
+		workerID := job.WorkerID
+		failed, err := s.repo.FailStaleJob(ctx, job.ID, workerID, code, message)
+		if err != nil {
+			log.Printf("[ERROR] Failed to reap stale job %d: %v", job.ID, err)
+			continue
+		}
+		if !failed {
+			continue // finished or reclaimed in the meantime
+		}
+
+		if updated, err := s.repo.GetJob(ctx, job.ID); err == nil {
+			job = updated
+		}
+		log.Printf("[WARN] Reaped job %d (%s) from worker %s: %s (retry %d/%d)",
+			job.ID, job.Type, workerID, message, job.RetryCount, job.MaxRetries)
+		reaped = append(reaped, job)
+	}
+
+	return reaped, nil
+}
+
+// staleReason returns an error code and message when a processing job has
+// stopped sending heartbeats or run past its timeout. Workers cancel a job at
+// its timeout themselves, so the reaper allows heartbeatTimeout on top and
+// only steps in when the worker didn't.
+func staleReason(job *models.Job, now time.Time, heartbeatTimeout time.Duration, timeouts Timeouts) (string, string) {
+	lastSeen := job.HeartbeatAt
+	if lastSeen == nil {
+		lastSeen = job.StartedAt
+	}
+	if heartbeatTimeout > 0 && lastSeen != nil && now.Sub(*lastSeen) > heartbeatTimeout {
+		return "worker_lost", fmt.Sprintf("no heartbeat from worker for %s", now.Sub(*lastSeen).Round(time.Second))
+	}
+
+	timeout := timeouts.For(job.Type)
+	if timeout > 0 && job.StartedAt != nil && now.Sub(*job.StartedAt) > timeout+heartbeatTimeout {
+		return "job_timeout", fmt.Sprintf("job exceeded its %s timeout", timeout)
+	}
+
+	return "", ""
+}
+
+func (s *service) FailDependents(ctx context.Context, jobID uint) ([]*models.Job, error) {
+	failed, err := s.repo.FailDependents(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failing dependent jobs: %w", err)
+	}
+
+	for _, job := range failed {
+		log.Printf("[ERROR] Job %d failed permanently: %s", job.ID, job.Error)
+	}
+
+	return failed, nil
+}
+
 func (s *service) FailJobWithDetails(ctx context.Context, jobID uint, errorType models.JobErrorType, errorCode, errorMsg, errorDetails string) error {
 	if err := s.repo.FailJobWithDetails(ctx, jobID, errorType, errorCode, errorMsg, errorDetails); err != nil {
 		if errors.Is(err, ErrJobNotFound) {
@@ -0,0 +1,383 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestService(t *testing.T) (Service, *gorm.DB) {
+	// A file database so concurrent callers really use separate connections
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	db, err := gorm.Open(sqlite.Open(path+"?_busy_timeout=5000&_journal_mode=WAL"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Job{}, &models.JobDependency{}))
+	return NewService(NewRepository(db)), db
+}
+
+func TestEnqueueUniqueJob_ConcurrentCallersShareOneJob(t *testing.T) {
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+
+	const callers = 20
+	ids := make([]uint, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			job, err := svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 42}, "episode_id")
+			if assert.NoError(t, err) {
+				ids[i] = job.ID
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var count int64
+	require.NoError(t, db.Model(&models.Job{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	for _, id := range ids {
+		assert.Equal(t, ids[0], id)
+	}
+}
+
+func TestEnqueueUniqueJob_ScopedByTypeAndValue(t *testing.T) {
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	first, err := svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 1}, "episode_id")
+	require.NoError(t, err)
+
+	other, err := svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 2}, "episode_id")
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, other.ID)
+
+	transcription, err := svc.EnqueueUniqueJob(ctx, models.JobTypeTranscriptionGeneration, models.JobPayload{"episode_id": 1}, "episode_id")
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, transcription.ID)
+
+	_, err = svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"url": "x"}, "episode_id")
+	assert.Error(t, err)
+}
+
+func TestEnqueueUniqueJob_ReturnsLiveJobUntilTerminal(t *testing.T) {
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+	payload := models.JobPayload{"episode_id": 7}
+
+	job, err := svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, payload, "episode_id")
+	require.NoError(t, err)
+
+	claimed, err := svc.ClaimNextJob(ctx, "worker-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, job.ID, claimed.ID)
+
+	// Processing, then failed-awaiting-retry: both still the live job
+	again, err := svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, payload, "episode_id")
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, again.ID)
+	assert.Equal(t, models.JobStatusProcessing, again.Status)
+
+	require.NoError(t, svc.FailJobWithDetails(ctx, job.ID, models.ErrorTypeSystem, "", "boom", ""))
+	again, err = svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, payload, "episode_id")
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, again.ID)
+	assert.Equal(t, models.JobStatusFailed, again.Status)
+
+	// Once the job completes, a new request creates a fresh job
+	require.NoError(t, svc.CompleteJob(ctx, job.ID, models.JobResult{}))
+	fresh, err := svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, payload, "episode_id")
+	require.NoError(t, err)
+	assert.NotEqual(t, job.ID, fresh.ID)
+	assert.Equal(t, models.JobStatusPending, fresh.Status)
+}
+
+func TestClaimNextJob_WaitsForDependencies(t *testing.T) {
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	parent, err := svc.EnqueueJob(ctx, models.JobTypeTranscriptionGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+	// The child outranks its parent but still has to wait for it
+	child, err := svc.EnqueueJob(ctx, models.JobTypeSummaryGeneration, models.JobPayload{"episode_id": 1},
+		WithDependsOn(parent.ID), WithPriority(10))
+	require.NoError(t, err)
+
+	loaded, err := svc.GetJob(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{parent.ID}, loaded.DependsOn)
+
+	claimed, err := svc.ClaimNextJob(ctx, "worker-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, parent.ID, claimed.ID)
+
+	_, err = svc.ClaimNextJob(ctx, "worker-2", nil)
+	assert.ErrorIs(t, err, ErrNoJobsAvailable)
+
+	require.NoError(t, svc.CompleteJob(ctx, parent.ID, models.JobResult{}))
+	claimed, err = svc.ClaimNextJob(ctx, "worker-2", nil)
+	require.NoError(t, err)
+	assert.Equal(t, child.ID, claimed.ID)
+}
+
+func TestFailDependents_Cascades(t *testing.T) {
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	root, err := svc.EnqueueJob(ctx, models.JobTypeTranscriptionGeneration, models.JobPayload{"episode_id": 1}, WithMaxRetries(1))
+	require.NoError(t, err)
+	child, err := svc.EnqueueJob(ctx, models.JobTypeSummaryGeneration, models.JobPayload{"episode_id": 1}, WithDependsOn(root.ID))
+	require.NoError(t, err)
+	grandchild, err := svc.EnqueueJob(ctx, models.JobTypeAutoLabel, models.JobPayload{"episode_id": 1}, WithDependsOn(child.ID))
+	require.NoError(t, err)
+	unrelated, err := svc.EnqueueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+
+	_, err = svc.ClaimNextJob(ctx, "worker-1", []models.JobType{models.JobTypeTranscriptionGeneration})
+	require.NoError(t, err)
+	require.NoError(t, svc.FailJobWithDetails(ctx, root.ID, models.ErrorTypeProcessing, "whisper_failed", "boom", ""))
+
+	failed, err := svc.FailDependents(ctx, root.ID)
+	require.NoError(t, err)
+	require.Len(t, failed, 2)
+	assert.Equal(t, child.ID, failed[0].ID)
+	assert.Equal(t, grandchild.ID, failed[1].ID)
+
+	for _, id := range []uint{child.ID, grandchild.ID} {
+		job, err := svc.GetJob(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusPermanentlyFailed, job.Status)
+		assert.Equal(t, string(models.ErrorTypeDependency), job.ErrorType)
+		assert.Equal(t, "dependency_failed", job.ErrorCode)
+	}
+
+	job, err := svc.GetJob(ctx, unrelated.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusPending, job.Status)
+}
+
+func TestEnqueueJob_Dependencies(t *testing.T) {
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.EnqueueJob(ctx, models.JobTypeSummaryGeneration, models.JobPayload{"episode_id": 1}, WithDependsOn(999))
+	assert.ErrorIs(t, err, ErrDependencyNotFound)
+	var count int64
+	require.NoError(t, db.Model(&models.Job{}).Count(&count).Error)
+	assert.Zero(t, count, "job with a missing dependency must not be created")
+
+	// Depending on a job that already failed for good fails straight away
+	parent, err := svc.EnqueueJob(ctx, models.JobTypeTranscriptionGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.Job{}).Where("id = ?", parent.ID).Update("status", models.JobStatusCancelled).Error)
+
+	child, err := svc.EnqueueUniqueJob(ctx, models.JobTypeSummaryGeneration, models.JobPayload{"episode_id": 1}, "episode_id", WithDependsOn(parent.ID))
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusPermanentlyFailed, child.Status)
+	assert.Equal(t, "dependency_cancelled", child.ErrorCode)
+}
+
+func TestFailJobWithDetails_ConfigurationErrorsAreNotRetried(t *testing.T) {
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	job, err := svc.EnqueueJob(ctx, models.JobTypeTranscriptionGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+	require.NoError(t, svc.FailJobWithDetails(ctx, job.ID, models.ErrorTypeConfiguration, "transcription_backend_auth", "rejected credentials", ""))
+
+	job, err = svc.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusPermanentlyFailed, job.Status, "first failure is final")
+	assert.Equal(t, string(models.ErrorTypeConfiguration), job.ErrorType)
+	assert.Equal(t, "transcription_backend_auth", job.ErrorCode)
+}
+
+func TestReapStaleJobs(t *testing.T) {
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+
+	enqueueAndClaim := func(jobType models.JobType, worker string) *models.Job {
+		_, err := svc.EnqueueJob(ctx, jobType, models.JobPayload{})
+		require.NoError(t, err)
+		job, err := svc.ClaimNextJob(ctx, worker, []models.JobType{jobType})
+		require.NoError(t, err)
+		return job
+	}
+	backdate := func(job *models.Job, started, heartbeat time.Duration) {
+		now := time.Now()
+		require.NoError(t, db.Model(&models.Job{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"started_at":   now.Add(-started),
+			"heartbeat_at": now.Add(-heartbeat),
+		}).Error)
+	}
+
+	lost := enqueueAndClaim(models.JobTypeWaveformGeneration, "worker-1")
+	backdate(lost, 20*time.Minute, 10*time.Minute)
+
+	overdue := enqueueAndClaim(models.JobTypeTranscriptionGeneration, "worker-2")
+	backdate(overdue, 3*time.Hour, time.Second)
+
+	healthy := enqueueAndClaim(models.JobTypeDatasetGeneration, "worker-3")
+	backdate(healthy, 3*time.Hour, time.Second)
+	require.NoError(t, svc.Heartbeat(ctx, healthy.ID))
+
+	timeouts := Timeouts{
+		Default: time.Hour,
+		ByType:  map[models.JobType]time.Duration{models.JobTypeDatasetGeneration: 6 * time.Hour},
+	}
+	reaped, err := svc.ReapStaleJobs(ctx, 5*time.Minute, timeouts)
+	require.NoError(t, err)
+	require.Len(t, reaped, 2)
+
+	byID := map[uint]*models.Job{}
+	for _, job := range reaped {
+		byID[job.ID] = job
+	}
+	assert.Equal(t, "worker_lost", byID[lost.ID].ErrorCode)
+	assert.Equal(t, "job_timeout", byID[overdue.ID].ErrorCode)
+	for _, job := range reaped {
+		assert.Equal(t, models.JobStatusFailed, job.Status, "reaped jobs are retried")
+		assert.Empty(t, job.WorkerID)
+		assert.Nil(t, job.HeartbeatAt)
+	}
+
+	current, err := svc.GetJob(ctx, healthy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusProcessing, current.Status)
+
+	// A reaped job can be claimed again
+	again, err := svc.ClaimNextJob(ctx, "worker-4", []models.JobType{models.JobTypeWaveformGeneration})
+	require.NoError(t, err)
+	assert.Equal(t, lost.ID, again.ID)
+}
+
+func TestReapStaleJobs_SkipsJobsThatMoved(t *testing.T) {
+	svc, db := setupTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.EnqueueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{})
+	require.NoError(t, err)
+	job, err := svc.ClaimNextJob(ctx, "worker-1", nil)
+	require.NoError(t, err)
+
+	repo := NewRepository(db)
+	failed, err := repo.FailStaleJob(ctx, job.ID, "worker-2", "worker_lost", "stale")
+	require.NoError(t, err)
+	assert.False(t, failed, "claimed by a different worker")
+
+	require.NoError(t, svc.CompleteJob(ctx, job.ID, nil))
+	failed, err = repo.FailStaleJob(ctx, job.ID, "worker-1", "worker_lost", "stale")
+	require.NoError(t, err)
+	assert.False(t, failed, "already completed")
+}
+
+func TestCancelJob_KeepsCancelledState(t *testing.T) {
+	svc, _ := setupTestService(t)
+	ctx := context.Background()
+
+	queued, err := svc.EnqueueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+	_, err = svc.CancelJob(ctx, queued.ID)
+	require.NoError(t, err)
+	_, err = svc.ClaimNextJob(ctx, "worker-1", []models.JobType{models.JobTypeWaveformGeneration})
+	assert.Error(t, err, "cancelled jobs are never claimed")
+
+	// A processor finishing after the cancel can't overwrite it
+	_, err = svc.EnqueueJob(ctx, models.JobTypeTranscriptionGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+	running, err := svc.ClaimNextJob(ctx, "worker-1", []models.JobType{models.JobTypeTranscriptionGeneration})
+	require.NoError(t, err)
+	var hooked []uint
+	svc.OnCancel(func(jobID uint) { hooked = append(hooked, jobID) })
+	_, err = svc.CancelJob(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{running.ID}, hooked, "hooks hear about running jobs only")
+
+	assert.ErrorIs(t, svc.Heartbeat(ctx, running.ID), ErrJobCancelled)
+	assert.ErrorIs(t, svc.UpdateProgress(ctx, running.ID, 50), ErrJobCancelled)
+	assert.ErrorIs(t, svc.CompleteJob(ctx, running.ID, models.JobResult{}), ErrJobCancelled)
+	assert.ErrorIs(t, svc.FailJob(ctx, running.ID, errors.New("context canceled")), ErrJobCancelled)
+	job, err := svc.GetJob(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusCancelled, job.Status)
+	assert.NotNil(t, job.CompletedAt)
+
+	// Finished jobs can't be cancelled
+	_, err = svc.EnqueueJob(ctx, models.JobTypeSummaryGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+	done, err := svc.ClaimNextJob(ctx, "worker-1", []models.JobType{models.JobTypeSummaryGeneration})
+	require.NoError(t, err)
+	require.NoError(t, svc.CompleteJob(ctx, done.ID, models.JobResult{}))
+	_, err = svc.CancelJob(ctx, done.ID)
+	assert.ErrorIs(t, err, ErrJobFinished)
+	_, err = svc.CancelJob(ctx, 9999)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestEnqueue_QueueLimits(t *testing.T) {
+	_, db := setupTestService(t)
+	svc := NewService(NewRepository(db), WithQueueLimits(QueueLimits{
+		Default:          4,
+		ByType:           map[models.JobType]int{models.JobTypeSummaryGeneration: 0},
+		LowPriorityShare: 0.5,
+		RetryAfter:       time.Minute,
+	}))
+	ctx := context.Background()
+	waveform := func(episodeID int, opts ...JobOption) (*models.Job, error) {
+		return svc.EnqueueUniqueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": episodeID}, "episode_id", opts...)
+	}
+
+	// Low priority work may only fill half the queue
+	for i := 1; i <= 2; i++ {
+		_, err := waveform(i, WithPriority(LowPriority))
+		require.NoError(t, err)
+	}
+	_, err := waveform(3, WithPriority(LowPriority))
+	var full *QueueFullError
+	require.ErrorAs(t, err, &full)
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Equal(t, 2, full.Limit)
+	assert.Equal(t, time.Minute, full.RetryAfter)
+
+	// Requested work still gets the rest
+	for i := 3; i <= 4; i++ {
+		_, err := waveform(i)
+		require.NoError(t, err)
+	}
+	_, err = waveform(5)
+	assert.ErrorIs(t, err, ErrQueueFull)
+	_, err = svc.EnqueueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 5})
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	// A duplicate of queued work is answered with the queued job
+	job, err := waveform(1)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), job.ID)
+
+	// Other types have their own queue, and 0 means unlimited
+	_, err = svc.EnqueueJob(ctx, models.JobTypeTranscriptionGeneration, models.JobPayload{"episode_id": 1})
+	assert.NoError(t, err)
+	for i := 0; i < 6; i++ {
+		_, err = svc.EnqueueJob(ctx, models.JobTypeSummaryGeneration, models.JobPayload{"episode_id": i})
+		require.NoError(t, err)
+	}
+
+	// Claimed jobs no longer count against the limit
+	_, err = svc.ClaimNextJob(ctx, "worker-1", []models.JobType{models.JobTypeWaveformGeneration})
+	require.NoError(t, err)
+	_, err = waveform(5)
+	assert.NoError(t, err)
+}
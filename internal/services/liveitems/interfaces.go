@@ -0,0 +1,57 @@
+package liveitems
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+)
+
+// Fetcher asks Podcast Index for a podcast's episodes; the response carries
+// the podcast's live items alongside them
+type Fetcher interface {
+	GetEpisodesByPodcastID(ctx context.Context, podcastID int64, limit int) (*podcastindex.EpisodesResponse, error)
+}
+
+// Config controls how often live items are fetched
+type Config struct {
+	StaleAfter    time.Duration // Age at which a request fetches a podcast's live items again
+	CheckInterval time.Duration // Time between status checks of items that are live or due to start
+	LeadTime      time.Duration // How long before an item's scheduled start its checks begin
+	StartGrace    time.Duration // How long a pending item is still expected to go live after its scheduled start
+}
+
+// Service keeps podcasts' live items and their statuses current
+type Service interface {
+	// List returns the podcast's live and upcoming items, live first, then
+	// by start time. Items older than StaleAfter are fetched again first.
+	List(ctx context.Context, podcastIndexFeedID int64) ([]models.LiveItem, error)
+
+	// Refresh fetches the podcast's live items from Podcast Index, stores
+	// them and schedules their next status check
+	Refresh(ctx context.Context, podcastIndexFeedID int64) ([]models.LiveItem, error)
+
+	// DueFeeds returns podcasts whose live items are due a status check,
+	// most overdue first
+	DueFeeds(ctx context.Context, limit int) ([]int64, error)
+}
+
+// Repository defines the interface for live item persistence
+type Repository interface {
+	// Replace swaps the podcast's stored live items for items and saves its schedule
+	Replace(ctx context.Context, podcastIndexFeedID int64, items []models.LiveItem, schedule *models.LiveItemSchedule) error
+
+	// ListByFeed returns the podcast's live items by start time
+	ListByFeed(ctx context.Context, podcastIndexFeedID int64) ([]models.LiveItem, error)
+
+	// GetSchedule returns the podcast's schedule, or nil if its live items
+	// have never been fetched
+	GetSchedule(ctx context.Context, podcastIndexFeedID int64) (*models.LiveItemSchedule, error)
+
+	// ListDue returns podcasts whose next check is at or before now, most overdue first
+	ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error)
+
+	// RecordFailure counts a failed check and moves the podcast's next check to next
+	RecordFailure(ctx context.Context, podcastIndexFeedID int64, next time.Time) error
+}
@@ -0,0 +1,83 @@
+package liveitems
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new live item repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Replace swaps the podcast's stored live items for items and saves its schedule
+func (r *repository) Replace(ctx context.Context, podcastIndexFeedID int64, items []models.LiveItem, schedule *models.LiveItemSchedule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("podcast_index_feed_id = ?", podcastIndexFeedID).Delete(&models.LiveItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			if err := tx.Create(&items).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(schedule).Error
+	})
+}
+
+// ListByFeed returns the podcast's live items by start time
+func (r *repository) ListByFeed(ctx context.Context, podcastIndexFeedID int64) ([]models.LiveItem, error) {
+	var items []models.LiveItem
+	err := r.db.WithContext(ctx).
+		Where("podcast_index_feed_id = ?", podcastIndexFeedID).
+		Order("start_time, id").
+		Find(&items).Error
+	return items, err
+}
+
+// GetSchedule returns the podcast's schedule, or nil if its live items have
+// never been fetched
+func (r *repository) GetSchedule(ctx context.Context, podcastIndexFeedID int64) (*models.LiveItemSchedule, error) {
+	var schedule models.LiveItemSchedule
+	err := r.db.WithContext(ctx).Where("podcast_index_feed_id = ?", podcastIndexFeedID).First(&schedule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ListDue returns podcasts whose next check is at or before now, most overdue first
+func (r *repository) ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	var feedIDs []int64
+	query := r.db.WithContext(ctx).Model(&models.LiveItemSchedule{}).
+		Where("next_refresh_at IS NOT NULL AND next_refresh_at <= ?", now).
+		Order("next_refresh_at")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Pluck("podcast_index_feed_id", &feedIDs).Error
+	return feedIDs, err
+}
+
+// RecordFailure counts a failed check and moves the podcast's next check to next
+func (r *repository) RecordFailure(ctx context.Context, podcastIndexFeedID int64, next time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LiveItemSchedule{}).
+		Where("podcast_index_feed_id = ?", podcastIndexFeedID).
+		Updates(map[string]interface{}{
+			"failures":        gorm.Expr("failures + 1"),
+			"next_refresh_at": next,
+		}).Error
+}
@@ -0,0 +1,81 @@
+package liveitems
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// Scheduler periodically enqueues status refresh jobs for podcasts whose live
+// items are live or about to start
+type Scheduler struct {
+	service    Service
+	jobService jobs.Service
+	interval   time.Duration
+	batchSize  int
+	cancel     context.CancelFunc
+}
+
+// NewScheduler creates a new live item scheduler that checks for due
+// podcasts every interval, enqueueing at most batchSize per check
+func NewScheduler(service Service, jobService jobs.Service, interval time.Duration, batchSize int) *Scheduler {
+	return &Scheduler{
+		service:    service,
+		jobService: jobService,
+		interval:   interval,
+		batchSize:  batchSize,
+	}
+}
+
+// Start checks immediately and then every interval until Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	go func() {
+		s.Check(ctx)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Check(ctx)
+			case <-ctx.Done():
+				log.Println("[INFO] Live item scheduler stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Check enqueues a refresh job for every due podcast. A podcast stays due
+// until its job runs, so one still queued is found and not duplicated.
+func (s *Scheduler) Check(ctx context.Context) {
+	due, err := s.service.DueFeeds(ctx, s.batchSize)
+	if err != nil {
+		log.Printf("[ERROR] Failed to list podcasts due a live item refresh: %v", err)
+		return
+	}
+
+	for _, feedID := range due {
+		if _, err := s.jobService.EnqueueUniqueJob(
+			ctx,
+			models.JobTypeLiveItemRefresh,
+			models.JobPayload{"feed_id": feedID},
+			"feed_id",
+		); err != nil {
+			log.Printf("[ERROR] Failed to enqueue live item refresh for podcast %d: %v", feedID, err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package liveitems
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+)
+
+// maxFailureBackoff caps how far repeated failures push a podcast's next check
+const maxFailureBackoff = time.Hour
+
+// service implements the Service interface
+type service struct {
+	repo    Repository
+	fetcher Fetcher
+	config  Config
+	now     func() time.Time
+}
+
+// NewService creates a new live item service
+func NewService(repo Repository, fetcher Fetcher, config Config) Service {
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = 15 * time.Minute
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = 2 * time.Minute
+	}
+	if config.LeadTime < 0 {
+		config.LeadTime = 0
+	}
+	if config.StartGrace <= 0 {
+		config.StartGrace = 2 * time.Hour
+	}
+	return &service{repo: repo, fetcher: fetcher, config: config, now: time.Now}
+}
+
+// List returns the podcast's live and upcoming items. When they are stale
+// and fetching them again fails, the stored items are returned; only a
+// podcast that has never been fetched surfaces the error.
+func (s *service) List(ctx context.Context, podcastIndexFeedID int64) ([]models.LiveItem, error) {
+	schedule, err := s.repo.GetSchedule(ctx, podcastIndexFeedID)
+	if err != nil {
+		return nil, fmt.Errorf("loading live item schedule: %w", err)
+	}
+
+	now := s.now().UTC()
+	if schedule == nil || now.Sub(schedule.RefreshedAt) >= s.config.StaleAfter {
+		items, err := s.Refresh(ctx, podcastIndexFeedID)
+		if err == nil {
+			return s.current(items, now), nil
+		}
+		if schedule == nil {
+			return nil, err
+		}
+		log.Printf("[WARN] Serving stored live items for podcast %d: %v", podcastIndexFeedID, err)
+	}
+
+	items, err := s.repo.ListByFeed(ctx, podcastIndexFeedID)
+	if err != nil {
+		return nil, fmt.Errorf("listing live items: %w", err)
+	}
+	return s.current(items, now), nil
+}
+
+// Refresh fetches the podcast's live items and schedules the next status
+// check around the earliest item that is live or about to start. When the
+// fetch fails, a scheduled check is backed off instead.
+func (s *service) Refresh(ctx context.Context, podcastIndexFeedID int64) ([]models.LiveItem, error) {
+	// Live items come with the episodes whatever max is, so ask for as few episodes as possible
+	response, err := s.fetcher.GetEpisodesByPodcastID(ctx, podcastIndexFeedID, 1)
+	if err != nil {
+		s.backOff(ctx, podcastIndexFeedID)
+		return nil, fmt.Errorf("fetching live items from Podcast Index: %w", err)
+	}
+
+	items := make([]models.LiveItem, 0, len(response.LiveItems))
+	for _, item := range response.LiveItems {
+		items = append(items, fromPodcastIndex(podcastIndexFeedID, item))
+	}
+
+	now := s.now().UTC()
+	schedule := &models.LiveItemSchedule{
+		PodcastIndexFeedID: podcastIndexFeedID,
+		RefreshedAt:        now,
+		NextRefreshAt:      s.nextCheck(items, now),
+	}
+	if err := s.repo.Replace(ctx, podcastIndexFeedID, items, schedule); err != nil {
+		return nil, fmt.Errorf("storing live items: %w", err)
+	}
+	return items, nil
+}
+
+// backOff pushes a scheduled check back after a failure, doubling the delay
+// with each failure in a row up to maxFailureBackoff, so a feed that keeps
+// failing isn't fetched on every scheduler pass
+func (s *service) backOff(ctx context.Context, podcastIndexFeedID int64) {
+	schedule, err := s.repo.GetSchedule(ctx, podcastIndexFeedID)
+	if err != nil || schedule == nil || schedule.NextRefreshAt == nil {
+		return
+	}
+
+	delay := s.config.CheckInterval
+	for i := 0; i < schedule.Failures && delay < maxFailureBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxFailureBackoff)
+
+	if err := s.repo.RecordFailure(ctx, podcastIndexFeedID, s.now().UTC().Add(delay)); err != nil {
+		log.Printf("[WARN] Failed to back off live item checks for podcast %d: %v", podcastIndexFeedID, err)
+	}
+}
+
+// DueFeeds returns podcasts whose live items are due a status check
+func (s *service) DueFeeds(ctx context.Context, limit int) ([]int64, error) {
+	return s.repo.ListDue(ctx, s.now().UTC(), limit)
+}
+
+// nextCheck returns when the items' statuses should next be checked: soon
+// while one is live or its start is near, LeadTime before the next scheduled
+// start otherwise, and never when nothing is live or upcoming
+func (s *service) nextCheck(items []models.LiveItem, now time.Time) *time.Time {
+	var next *time.Time
+	for _, item := range items {
+		var at time.Time
+		switch {
+		case item.IsLive():
+			at = now.Add(s.config.CheckInterval)
+		case s.upcoming(item, now):
+			at = item.StartTime.Add(-s.config.LeadTime)
+			if !at.After(now) {
+				at = now.Add(s.config.CheckInterval)
+			}
+		default:
+			continue
+		}
+		if next == nil || at.Before(*next) {
+			next = &at
+		}
+	}
+	return next
+}
+
+// upcoming reports whether a pending item is still expected to go live. Feeds
+// don't always flip the status of a stream that was cancelled or ran late, so
+// one StartGrace past its start is given up on.
+func (s *service) upcoming(item models.LiveItem, now time.Time) bool {
+	return item.Status == models.LiveItemStatusPending &&
+		!item.StartTime.IsZero() &&
+		now.Before(item.StartTime.Add(s.config.StartGrace))
+}
+
+// current keeps the items that are live or upcoming, live first
+func (s *service) current(items []models.LiveItem, now time.Time) []models.LiveItem {
+	kept := make([]models.LiveItem, 0, len(items))
+	for _, item := range items {
+		if item.IsLive() || s.upcoming(item, now) {
+			kept = append(kept, item)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].IsLive() != kept[j].IsLive() {
+			return kept[i].IsLive()
+		}
+		return kept[i].StartTime.Before(kept[j].StartTime)
+	})
+	return kept
+}
+
+// fromPodcastIndex converts a Podcast Index live item. Statuses other than
+// the three the namespace defines are treated as pending.
+func fromPodcastIndex(podcastIndexFeedID int64, item podcastindex.LiveItem) models.LiveItem {
+	status := strings.ToLower(strings.TrimSpace(item.Status))
+	switch status {
+	case models.LiveItemStatusLive, models.LiveItemStatusEnded:
+	default:
+		status = models.LiveItemStatusPending
+	}
+
+	image := item.Image
+	if image == "" {
+		image = item.FeedImage
+	}
+
+	live := models.LiveItem{
+		PodcastIndexID:     item.ID,
+		PodcastIndexFeedID: podcastIndexFeedID,
+		GUID:               item.GUID,
+		Title:              item.Title,
+		Description:        item.Description,
+		Status:             status,
+		EnclosureURL:       item.EnclosureURL,
+		EnclosureType:      item.EnclosureType,
+		ContentLink:        item.ContentLink,
+		Image:              image,
+	}
+	if item.StartTime > 0 {
+		live.StartTime = time.Unix(item.StartTime, 0).UTC()
+	}
+	if item.EndTime > 0 {
+		end := time.Unix(item.EndTime, 0).UTC()
+		live.EndTime = &end
+	}
+	if live.ContentLink == "" {
+		live.ContentLink = item.Link
+	}
+	return live
+}
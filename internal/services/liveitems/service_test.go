@@ -0,0 +1,166 @@
+package liveitems
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.LiveItem{}, &models.LiveItemSchedule{}))
+	return db
+}
+
+type fakeFetcher struct {
+	items []podcastindex.LiveItem
+	err   error
+	calls int
+}
+
+func (f *fakeFetcher) GetEpisodesByPodcastID(ctx context.Context, podcastID int64, limit int) (*podcastindex.EpisodesResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &podcastindex.EpisodesResponse{LiveItems: f.items}, nil
+}
+
+type testEnv struct {
+	svc     *service
+	fetcher *fakeFetcher
+	now     time.Time
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	env := &testEnv{
+		fetcher: &fakeFetcher{},
+		now:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	config := Config{StaleAfter: 15 * time.Minute, CheckInterval: 2 * time.Minute, LeadTime: 10 * time.Minute, StartGrace: 2 * time.Hour}
+	env.svc = NewService(NewRepository(setupTestDB(t)), env.fetcher, config).(*service)
+	env.svc.now = func() time.Time { return env.now }
+	return env
+}
+
+func TestListSplitsLiveAndUpcoming(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	env.fetcher.items = []podcastindex.LiveItem{
+		{ID: 1, Title: "Tomorrow", Status: "pending", StartTime: env.now.Add(24 * time.Hour).Unix()},
+		{ID: 2, Title: "On air", Status: "LIVE", StartTime: env.now.Add(-30 * time.Minute).Unix(), FeedImage: "https://example.com/feed.jpg"},
+		{ID: 3, Title: "Last week", Status: "ended", StartTime: env.now.Add(-7 * 24 * time.Hour).Unix()},
+		{ID: 4, Title: "Never started", Status: "pending", StartTime: env.now.Add(-3 * time.Hour).Unix()},
+		{ID: 5, Title: "Soon", Status: "", StartTime: env.now.Add(time.Hour).Unix()},
+	}
+
+	items, err := env.svc.List(ctx, 42)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, "On air", items[0].Title, "live items come first")
+	assert.Equal(t, "https://example.com/feed.jpg", items[0].Image)
+	assert.Equal(t, "Soon", items[1].Title)
+	assert.Equal(t, models.LiveItemStatusPending, items[1].Status, "a missing status counts as pending")
+	assert.Equal(t, "Tomorrow", items[2].Title)
+
+	_, err = env.svc.List(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, 1, env.fetcher.calls, "fresh items are served from the database")
+
+	env.now = env.now.Add(15 * time.Minute)
+	env.fetcher.err = errors.New("podcast index down")
+	items, err = env.svc.List(ctx, 42)
+	require.NoError(t, err, "stale items are served when Podcast Index fails")
+	assert.Len(t, items, 3)
+	assert.Equal(t, 2, env.fetcher.calls)
+
+	_, err = env.svc.List(ctx, 7)
+	assert.Error(t, err, "a podcast never fetched has nothing to fall back on")
+}
+
+func TestRefreshSchedulesChecksAroundStartTimes(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	dueAfter := func(d time.Duration) []int64 {
+		saved := env.now
+		env.now = env.now.Add(d)
+		defer func() { env.now = saved }()
+		due, err := env.svc.DueFeeds(ctx, 0)
+		require.NoError(t, err)
+		return due
+	}
+
+	// Nothing live or upcoming: only requests refresh it
+	env.fetcher.items = []podcastindex.LiveItem{{ID: 1, Status: "ended", StartTime: env.now.Add(-time.Hour).Unix()}}
+	_, err := env.svc.Refresh(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, dueAfter(48*time.Hour))
+
+	// Checks begin lead time before the start
+	env.fetcher.items = []podcastindex.LiveItem{{ID: 2, Status: "pending", StartTime: env.now.Add(time.Hour).Unix()}}
+	_, err = env.svc.Refresh(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, dueAfter(49*time.Minute))
+	assert.Equal(t, []int64{2}, dueAfter(50*time.Minute))
+
+	// Once live, or within the lead time, it is checked every interval
+	env.fetcher.items = []podcastindex.LiveItem{{ID: 3, Status: "live", StartTime: env.now.Add(-time.Hour).Unix()}}
+	_, err = env.svc.Refresh(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, dueAfter(2*time.Hour), "most overdue first")
+	assert.NotContains(t, dueAfter(time.Minute), int64(3))
+	assert.Contains(t, dueAfter(2*time.Minute), int64(3))
+
+	env.fetcher.items = []podcastindex.LiveItem{{ID: 3, Status: "ended", StartTime: env.now.Add(-time.Hour).Unix()}}
+	_, err = env.svc.Refresh(ctx, 3)
+	require.NoError(t, err)
+	assert.NotContains(t, dueAfter(time.Hour), int64(3), "ended streams need no more checks")
+}
+
+func TestRefreshBacksOffFailingChecks(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	env.fetcher.items = []podcastindex.LiveItem{{ID: 1, Status: "live", StartTime: env.now.Add(-time.Hour).Unix()}}
+	_, err := env.svc.Refresh(ctx, 1)
+	require.NoError(t, err)
+
+	env.fetcher.err = errors.New("podcast index unavailable")
+	for _, delay := range []time.Duration{2 * time.Minute, 4 * time.Minute, 8 * time.Minute} {
+		env.now = env.now.Add(time.Hour)
+		due, err := env.svc.DueFeeds(ctx, 0)
+		require.NoError(t, err)
+		require.Equal(t, []int64{1}, due)
+
+		_, err = env.svc.Refresh(ctx, 1)
+		require.Error(t, err)
+
+		env.now = env.now.Add(delay - time.Second)
+		due, err = env.svc.DueFeeds(ctx, 0)
+		require.NoError(t, err)
+		assert.Empty(t, due, "not due again for %s", delay)
+	}
+
+	// A successful check resets the backoff
+	env.fetcher.err = nil
+	env.now = env.now.Add(time.Hour)
+	_, err = env.svc.Refresh(ctx, 1)
+	require.NoError(t, err)
+	schedule, err := env.svc.repo.GetSchedule(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, schedule.Failures)
+}
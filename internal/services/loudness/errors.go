@@ -0,0 +1,9 @@
+package loudness
+
+import "errors"
+
+var (
+	// ErrAudioNotCached is returned when an episode has no measurement and
+	// its audio isn't cached to measure
+	ErrAudioNotCached = errors.New("episode audio not cached")
+)
@@ -0,0 +1,35 @@
+package loudness
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
+)
+
+// Service measures and stores the loudness of cached episodes
+type Service interface {
+	// GetLoudness returns an episode's measurement, measuring its cached
+	// audio first when there is none or the audio was downloaded again since
+	GetLoudness(ctx context.Context, podcastIndexEpisodeID int64) (*models.EpisodeLoudness, error)
+}
+
+// Repository defines loudness persistence
+type Repository interface {
+	// GetByEpisodeID returns nil without error when the episode wasn't measured
+	GetByEpisodeID(ctx context.Context, podcastIndexEpisodeID int64) (*models.EpisodeLoudness, error)
+
+	// Save creates or replaces an episode's measurement
+	Save(ctx context.Context, loudness *models.EpisodeLoudness) error
+}
+
+// Meter measures a file's loudness; *ffmpeg.FFmpeg satisfies it
+type Meter interface {
+	MeasureLoudness(ctx context.Context, filePath string) (*ffmpeg.LoudnessStats, error)
+}
+
+// AudioSource finds an episode's cached audio; audiocache.Service satisfies it
+type AudioSource interface {
+	// GetCachedAudio returns nil without error when the episode isn't cached
+	GetCachedAudio(ctx context.Context, podcastIndexEpisodeID int64) (*models.AudioCache, error)
+}
@@ -0,0 +1,47 @@
+package loudness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new loudness repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetByEpisodeID returns an episode's measurement, or nil when there is none
+func (r *repository) GetByEpisodeID(ctx context.Context, podcastIndexEpisodeID int64) (*models.EpisodeLoudness, error) {
+	var loudness models.EpisodeLoudness
+	err := r.db.WithContext(ctx).Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).First(&loudness).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting episode loudness: %w", err)
+	}
+	return &loudness, nil
+}
+
+// Save creates or replaces an episode's measurement
+func (r *repository) Save(ctx context.Context, loudness *models.EpisodeLoudness) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "podcast_index_episode_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"source_sha256", "integrated_lufs", "true_peak_dbtp", "range_lu", "threshold_lufs", "updated_at",
+		}),
+	}).Create(loudness).Error
+	if err != nil {
+		return fmt.Errorf("saving episode loudness: %w", err)
+	}
+	return nil
+}
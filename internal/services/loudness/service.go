@@ -0,0 +1,70 @@
+package loudness
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+type service struct {
+	repo  Repository
+	audio AudioSource
+	meter Meter
+}
+
+// NewService creates a loudness service measuring the original audio of
+// cached episodes with meter
+func NewService(repo Repository, audio AudioSource, meter Meter) Service {
+	return &service{repo: repo, audio: audio, meter: meter}
+}
+
+// GetLoudness returns the stored measurement while it matches the cached
+// audio, else measures the cached audio
+func (s *service) GetLoudness(ctx context.Context, podcastIndexEpisodeID int64) (*models.EpisodeLoudness, error) {
+	stored, err := s.repo.GetByEpisodeID(ctx, podcastIndexEpisodeID)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := s.audio.GetCachedAudio(ctx, podcastIndexEpisodeID)
+	if err != nil {
+		return nil, fmt.Errorf("getting cached audio: %w", err)
+	}
+
+	// A measurement outlives the cache entry it came from
+	if stored != nil && (cache == nil || stored.SourceSHA256 == cache.OriginalSHA256) {
+		return stored, nil
+	}
+	if cache == nil || cache.OriginalPath == "" {
+		return nil, ErrAudioNotCached
+	}
+
+	stats, err := s.meter.MeasureLoudness(ctx, cache.OriginalPath)
+	if err != nil {
+		return nil, fmt.Errorf("measuring loudness: %w", err)
+	}
+	loudness := &models.EpisodeLoudness{
+		PodcastIndexEpisodeID: podcastIndexEpisodeID,
+		SourceSHA256:          cache.OriginalSHA256,
+		IntegratedLUFS:        stats.IntegratedLUFS,
+		TruePeakDBTP:          stats.TruePeakDBTP,
+		RangeLU:               stats.RangeLU,
+		ThresholdLUFS:         stats.ThresholdLUFS,
+	}
+	if err := s.repo.Save(ctx, loudness); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] Episode %d loudness: %.1f LUFS, true peak %.1f dBTP, LRA %.1f LU",
+		podcastIndexEpisodeID, loudness.IntegratedLUFS, loudness.TruePeakDBTP, loudness.RangeLU)
+	return loudness, nil
+}
+
+// Gain returns the dB to apply to an episode to bring it to targetLUFS,
+// reduced where needed to keep its true peak at or under maxTruePeakDBTP
+func Gain(loudness *models.EpisodeLoudness, targetLUFS, maxTruePeakDBTP float64) float64 {
+	gain := math.Min(targetLUFS-loudness.IntegratedLUFS, maxTruePeakDBTP-loudness.TruePeakDBTP)
+	return math.Round(gain*100) / 100
+}
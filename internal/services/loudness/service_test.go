@@ -0,0 +1,90 @@
+package loudness
+
+import (
+	"context"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type fakeAudio struct {
+	cache *models.AudioCache
+}
+
+func (f *fakeAudio) GetCachedAudio(ctx context.Context, podcastIndexEpisodeID int64) (*models.AudioCache, error) {
+	return f.cache, nil
+}
+
+type fakeMeter struct {
+	calls []string
+	stats ffmpeg.LoudnessStats
+}
+
+func (f *fakeMeter) MeasureLoudness(ctx context.Context, filePath string) (*ffmpeg.LoudnessStats, error) {
+	f.calls = append(f.calls, filePath)
+	stats := f.stats
+	return &stats, nil
+}
+
+func setupTestService(t *testing.T) (Service, *fakeAudio, *fakeMeter) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.EpisodeLoudness{}))
+
+	audio := &fakeAudio{}
+	meter := &fakeMeter{stats: ffmpeg.LoudnessStats{IntegratedLUFS: -18.4, TruePeakDBTP: -0.3, RangeLU: 6.7, ThresholdLUFS: -28.6}}
+	return NewService(NewRepository(db), audio, meter), audio, meter
+}
+
+func TestGetLoudness_MeasuresOnce(t *testing.T) {
+	svc, audio, meter := setupTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.GetLoudness(ctx, 42)
+	assert.ErrorIs(t, err, ErrAudioNotCached)
+
+	audio.cache = &models.AudioCache{PodcastIndexEpisodeID: 42, OriginalPath: "/cache/42.mp3", OriginalSHA256: "aaa"}
+	loudness, err := svc.GetLoudness(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, -18.4, loudness.IntegratedLUFS)
+	assert.Equal(t, 6.7, loudness.RangeLU)
+
+	_, err = svc.GetLoudness(ctx, 42)
+	require.NoError(t, err)
+	assert.Len(t, meter.calls, 1, "stored measurement is reused")
+
+	// Kept after the audio leaves the cache
+	audio.cache = nil
+	loudness, err = svc.GetLoudness(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, -18.4, loudness.IntegratedLUFS)
+
+	// Measured again when the audio is downloaded anew
+	audio.cache = &models.AudioCache{PodcastIndexEpisodeID: 42, OriginalPath: "/cache/42-new.mp3", OriginalSHA256: "bbb"}
+	meter.stats.IntegratedLUFS = -16
+	loudness, err = svc.GetLoudness(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, -16.0, loudness.IntegratedLUFS)
+	assert.Equal(t, []string{"/cache/42.mp3", "/cache/42-new.mp3"}, meter.calls)
+}
+
+func TestGain(t *testing.T) {
+	loudness := &models.EpisodeLoudness{IntegratedLUFS: -20, TruePeakDBTP: -6}
+	assert.Equal(t, 4.0, Gain(loudness, -16, -1))
+
+	// Limited by the true peak
+	loudness.TruePeakDBTP = -2.5
+	assert.Equal(t, 1.5, Gain(loudness, -16, -1))
+
+	// Loud episodes are turned down
+	loudness = &models.EpisodeLoudness{IntegratedLUFS: -11.2, TruePeakDBTP: 0.4}
+	assert.Equal(t, -4.8, Gain(loudness, -16, -1))
+}
@@ -0,0 +1,18 @@
+package modelregistry
+
+import "errors"
+
+var (
+	// ErrModelNotFound is returned when no registered model matches
+	ErrModelNotFound = errors.New("detector model not found")
+
+	// ErrInvalidModel is returned for a registration with missing or
+	// inconsistent fields
+	ErrInvalidModel = errors.New("invalid detector model")
+
+	// ErrModelExists is returned when the name and version are already registered
+	ErrModelExists = errors.New("detector model version already registered")
+
+	// ErrDefaultModel is returned when deleting the model analysis runs by default
+	ErrDefaultModel = errors.New("detector model is the default")
+)
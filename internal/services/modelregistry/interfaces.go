@@ -0,0 +1,46 @@
+package modelregistry
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Service manages the registry of detector models episode analysis can run
+type Service interface {
+	// List returns every registered model, by name and newest version first
+	List(ctx context.Context) ([]models.DetectorModel, error)
+
+	// Get returns a model by ID
+	Get(ctx context.Context, id uint) (*models.DetectorModel, error)
+
+	// Resolve picks the model to run: the default when name is empty, else
+	// the given version of name, or its newest when version is empty
+	Resolve(ctx context.Context, name, version string) (*models.DetectorModel, error)
+
+	// Register validates and adds a model on behalf of userID
+	Register(ctx context.Context, model *models.DetectorModel, userID string) (*models.DetectorModel, error)
+
+	// SetDefault makes a model the one analysis runs when none is selected
+	SetDefault(ctx context.Context, id uint) (*models.DetectorModel, error)
+
+	// Delete removes a model; the default can't be removed
+	Delete(ctx context.Context, id uint) error
+
+	// EnsureBuiltins registers the built-in models that are missing, making
+	// the first of them the default when there is none
+	EnsureBuiltins(ctx context.Context) error
+}
+
+// Repository defines detector model persistence
+type Repository interface {
+	List(ctx context.Context) ([]models.DetectorModel, error)
+	Get(ctx context.Context, id uint) (*models.DetectorModel, error)
+	GetDefault(ctx context.Context) (*models.DetectorModel, error)
+	// GetVersion returns the given version of name, or its newest when version is empty
+	GetVersion(ctx context.Context, name, version string) (*models.DetectorModel, error)
+	Create(ctx context.Context, model *models.DetectorModel) error
+	// SetDefault moves the default flag to the model with id
+	SetDefault(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,101 @@
+package modelregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new detector model repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// List returns all models ordered by name, newest registration first
+func (r *repository) List(ctx context.Context) ([]models.DetectorModel, error) {
+	var list []models.DetectorModel
+	if err := r.db.WithContext(ctx).Order("name").Order("id DESC").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("listing detector models: %w", err)
+	}
+	return list, nil
+}
+
+// Get returns a model by ID
+func (r *repository) Get(ctx context.Context, id uint) (*models.DetectorModel, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// GetDefault returns the default model
+func (r *repository) GetDefault(ctx context.Context) (*models.DetectorModel, error) {
+	return r.first(r.db.WithContext(ctx).Where("is_default = ?", true))
+}
+
+// GetVersion returns a version of a model, the newest registered when version is empty
+func (r *repository) GetVersion(ctx context.Context, name, version string) (*models.DetectorModel, error) {
+	query := r.db.WithContext(ctx).Where("name = ?", name)
+	if version != "" {
+		query = query.Where("version = ?", version)
+	}
+	return r.first(query.Order("id DESC"))
+}
+
+func (r *repository) first(query *gorm.DB) (*models.DetectorModel, error) {
+	var model models.DetectorModel
+	err := query.First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrModelNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting detector model: %w", err)
+	}
+	return &model, nil
+}
+
+// Create stores a new model
+func (r *repository) Create(ctx context.Context, model *models.DetectorModel) error {
+	err := r.db.WithContext(ctx).Create(model).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrModelExists
+	}
+	if err != nil {
+		return fmt.Errorf("creating detector model: %w", err)
+	}
+	return nil
+}
+
+// SetDefault clears the default flag everywhere but on id, in one transaction
+func (r *repository) SetDefault(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.DetectorModel{}).Where("id = ?", id).Update("is_default", true)
+		if res.Error != nil {
+			return fmt.Errorf("setting default detector model: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrModelNotFound
+		}
+		err := tx.Model(&models.DetectorModel{}).Where("id <> ? AND is_default = ?", id, true).Update("is_default", false).Error
+		if err != nil {
+			return fmt.Errorf("clearing default detector model: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete removes a model by ID
+func (r *repository) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.DetectorModel{}, id)
+	if res.Error != nil {
+		return fmt.Errorf("deleting detector model: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrModelNotFound
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package modelregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Field limits, matching the column sizes of models.DetectorModel
+const (
+	maxNameLength    = 100
+	maxVersionLength = 50
+	maxPathLength    = 500
+	maxLabelLength   = 100 // The clips.label column
+
+	maxChunkSeconds   = 600 // Ten minutes of 16kHz audio is about 19MB per request
+	maxTimeoutSeconds = 600
+)
+
+// tokenEnvPattern is what an environment variable name may look like
+var tokenEnvPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type service struct {
+	repo     Repository
+	builtins map[string]models.DetectorModel
+	order    []string // Builtin names in the order given, the first becoming the default
+}
+
+// NewService creates a detector model registry. builtins are the detectors
+// compiled into the server; only their names may be registered with the
+// builtin backend.
+func NewService(repo Repository, builtins ...models.DetectorModel) Service {
+	s := &service{repo: repo, builtins: make(map[string]models.DetectorModel, len(builtins))}
+	for _, b := range builtins {
+		s.builtins[b.Name] = b
+		s.order = append(s.order, b.Name)
+	}
+	return s
+}
+
+// List returns every registered model
+func (s *service) List(ctx context.Context) ([]models.DetectorModel, error) {
+	return s.repo.List(ctx)
+}
+
+// Get returns a model by ID
+func (s *service) Get(ctx context.Context, id uint) (*models.DetectorModel, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Resolve picks the default model, or a version of a named one
+func (s *service) Resolve(ctx context.Context, name, version string) (*models.DetectorModel, error) {
+	name, version = strings.TrimSpace(name), strings.TrimSpace(version)
+	if name == "" {
+		if version != "" {
+			return nil, fmt.Errorf("%w: a version needs a model name", ErrInvalidModel)
+		}
+		return s.repo.GetDefault(ctx)
+	}
+	return s.repo.GetVersion(ctx, name, version)
+}
+
+// Register validates and stores a new model version
+func (s *service) Register(ctx context.Context, model *models.DetectorModel, userID string) (*models.DetectorModel, error) {
+	if err := s.validate(model); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetVersion(ctx, model.Name, model.Version); err == nil {
+		return nil, ErrModelExists
+	} else if !errors.Is(err, ErrModelNotFound) {
+		return nil, err
+	}
+
+	makeDefault := model.IsDefault
+	model.ID, model.IsDefault, model.CreatedBy = 0, false, userID
+	if err := s.repo.Create(ctx, model); err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] Model registry: %s registered %s (%s) predicting %v",
+		userID, model.Identity(), model.Backend, model.Labels)
+
+	if makeDefault {
+		return s.SetDefault(ctx, model.ID)
+	}
+	return model, nil
+}
+
+// validate normalizes a registration and checks it against its backend
+func (s *service) validate(model *models.DetectorModel) error {
+	model.Name = strings.TrimSpace(model.Name)
+	model.Version = strings.TrimSpace(model.Version)
+	model.Endpoint = strings.TrimSpace(model.Endpoint)
+	model.LocalPath = strings.TrimSpace(model.LocalPath)
+	model.Input = strings.TrimSpace(model.Input)
+	model.TokenEnv = strings.TrimSpace(model.TokenEnv)
+
+	switch {
+	case model.Name == "" || model.Version == "":
+		return fmt.Errorf("%w: name and version are required", ErrInvalidModel)
+	case len(model.Name) > maxNameLength:
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidModel, maxNameLength)
+	case len(model.Version) > maxVersionLength:
+		return fmt.Errorf("%w: version is longer than %d characters", ErrInvalidModel, maxVersionLength)
+	case strings.Contains(model.Name, "@"):
+		return fmt.Errorf("%w: name can't contain '@'", ErrInvalidModel)
+	case len(model.Endpoint) > maxPathLength || len(model.LocalPath) > maxPathLength:
+		return fmt.Errorf("%w: endpoint and local_path are limited to %d characters", ErrInvalidModel, maxPathLength)
+	}
+
+	if model.Backend != models.DetectorBackendHTTP &&
+		(model.Input != "" || model.ChunkSeconds != 0 || model.TimeoutSeconds != 0 || model.TokenEnv != "") {
+		return fmt.Errorf("%w: only http models take input, chunk_seconds, timeout_seconds or token_env", ErrInvalidModel)
+	}
+
+	switch model.Backend {
+	case models.DetectorBackendBuiltin:
+		if _, ok := s.builtins[model.Name]; !ok {
+			return fmt.Errorf("%w: no built-in detector is named %q", ErrInvalidModel, model.Name)
+		}
+		if model.Endpoint != "" || model.LocalPath != "" {
+			return fmt.Errorf("%w: built-in models take no endpoint or local_path", ErrInvalidModel)
+		}
+	case models.DetectorBackendLocal:
+		if model.LocalPath == "" || !filepath.IsAbs(model.LocalPath) || model.Endpoint != "" {
+			return fmt.Errorf("%w: local models need an absolute local_path and no endpoint", ErrInvalidModel)
+		}
+	case models.DetectorBackendHTTP:
+		u, err := url.Parse(model.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || model.LocalPath != "" {
+			return fmt.Errorf("%w: http models need an http(s) endpoint and no local_path", ErrInvalidModel)
+		}
+		if err := validateHTTPSettings(model); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: backend must be %s, %s or %s", ErrInvalidModel,
+			models.DetectorBackendBuiltin, models.DetectorBackendLocal, models.DetectorBackendHTTP)
+	}
+
+	labels := make([]string, 0, len(model.Labels))
+	seen := make(map[string]bool, len(model.Labels))
+	for _, label := range model.Labels {
+		label = strings.TrimSpace(label)
+		if label == "" || len(label) > maxLabelLength {
+			return fmt.Errorf("%w: labels must be 1 to %d characters", ErrInvalidModel, maxLabelLength)
+		}
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) == 0 {
+		return fmt.Errorf("%w: a model must predict at least one label", ErrInvalidModel)
+	}
+	model.Labels = labels
+	return nil
+}
+
+// validateHTTPSettings checks the request settings of an http model
+func validateHTTPSettings(model *models.DetectorModel) error {
+	switch {
+	case model.Input != "" && model.Input != models.DetectorInputAudio && model.Input != models.DetectorInputFeatures:
+		return fmt.Errorf("%w: input must be %s or %s", ErrInvalidModel, models.DetectorInputAudio, models.DetectorInputFeatures)
+	case model.ChunkSeconds < 0 || model.ChunkSeconds > maxChunkSeconds:
+		return fmt.Errorf("%w: chunk_seconds must be at most %d", ErrInvalidModel, maxChunkSeconds)
+	case model.ChunkSeconds != 0 && model.ChunkSeconds < 1:
+		return fmt.Errorf("%w: chunk_seconds must be at least 1", ErrInvalidModel)
+	case model.TimeoutSeconds < 0 || model.TimeoutSeconds > maxTimeoutSeconds:
+		return fmt.Errorf("%w: timeout_seconds must be at most %d", ErrInvalidModel, maxTimeoutSeconds)
+	case model.TokenEnv != "" && !tokenEnvPattern.MatchString(model.TokenEnv):
+		return fmt.Errorf("%w: token_env must be an environment variable name", ErrInvalidModel)
+	}
+	return nil
+}
+
+// SetDefault makes a model the default
+func (s *service) SetDefault(ctx context.Context, id uint) (*models.DetectorModel, error) {
+	if err := s.repo.SetDefault(ctx, id); err != nil {
+		return nil, err
+	}
+	model, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] Model registry: %s is now the default", model.Identity())
+	return model, nil
+}
+
+// Delete removes a model other than the default
+func (s *service) Delete(ctx context.Context, id uint) error {
+	model, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if model.IsDefault {
+		return ErrDefaultModel
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	log.Printf("[INFO] Model registry: removed %s", model.Identity())
+	return nil
+}
+
+// EnsureBuiltins registers missing built-in models and makes sure there is a default
+func (s *service) EnsureBuiltins(ctx context.Context) error {
+	for _, name := range s.order {
+		builtin := s.builtins[name]
+		if _, err := s.repo.GetVersion(ctx, builtin.Name, builtin.Version); err == nil {
+			continue
+		} else if !errors.Is(err, ErrModelNotFound) {
+			return err
+		}
+		builtin.Backend = models.DetectorBackendBuiltin
+		builtin.IsDefault = false
+		if err := s.repo.Create(ctx, &builtin); err != nil {
+			return err
+		}
+		log.Printf("[INFO] Model registry: registered built-in %s", builtin.Identity())
+	}
+
+	if _, err := s.repo.GetDefault(ctx); !errors.Is(err, ErrModelNotFound) {
+		return err
+	}
+	if len(s.order) == 0 {
+		return nil
+	}
+	first := s.builtins[s.order[0]]
+	model, err := s.repo.GetVersion(ctx, first.Name, first.Version)
+	if err != nil {
+		return err
+	}
+	_, err = s.SetDefault(ctx, model.ID)
+	return err
+}
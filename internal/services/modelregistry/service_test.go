@@ -0,0 +1,127 @@
+package modelregistry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var testBuiltin = models.DetectorModel{Name: "volume-spike", Version: "1", Labels: []string{"volume_spike"}}
+
+func setupTestService(t *testing.T) Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.DetectorModel{}))
+	svc := NewService(NewRepository(db), testBuiltin)
+	require.NoError(t, svc.EnsureBuiltins(context.Background()))
+	return svc
+}
+
+func TestEnsureBuiltins_RegistersDefault(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	// Running again neither duplicates the entry nor moves the default
+	require.NoError(t, svc.EnsureBuiltins(ctx))
+	list, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, models.DetectorBackendBuiltin, list[0].Backend)
+	assert.True(t, list[0].IsDefault)
+
+	model, err := svc.Resolve(ctx, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "volume-spike@1", model.Identity())
+	assert.Equal(t, []string{"volume_spike"}, model.Labels)
+}
+
+func TestRegister_ResolvesVersions(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	for _, version := range []string{"1", "2"} {
+		_, err := svc.Register(ctx, &models.DetectorModel{
+			Name:      "ad-detector",
+			Version:   version,
+			Backend:   models.DetectorBackendLocal,
+			LocalPath: "/opt/models/ad-detector",
+			Labels:    []string{" advertisement ", "music", "advertisement"},
+		}, "admin-1")
+		require.NoError(t, err)
+	}
+
+	newest, err := svc.Resolve(ctx, "ad-detector", "")
+	require.NoError(t, err)
+	assert.Equal(t, "2", newest.Version)
+	assert.Equal(t, []string{"advertisement", "music"}, newest.Labels)
+	assert.Equal(t, "admin-1", newest.CreatedBy)
+
+	first, err := svc.Resolve(ctx, "ad-detector", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "1", first.Version)
+
+	_, err = svc.Resolve(ctx, "ad-detector", "3")
+	assert.ErrorIs(t, err, ErrModelNotFound)
+
+	_, err = svc.Register(ctx, &models.DetectorModel{
+		Name: "ad-detector", Version: "2", Backend: models.DetectorBackendLocal,
+		LocalPath: "/opt/models/ad-detector", Labels: []string{"advertisement"},
+	}, "admin-1")
+	assert.ErrorIs(t, err, ErrModelExists)
+}
+
+func TestRegister_Rejects(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	tests := map[string]models.DetectorModel{
+		"no version":       {Name: "ads", Backend: models.DetectorBackendLocal, LocalPath: "/opt/ads", Labels: []string{"ad"}},
+		"no labels":        {Name: "ads", Version: "1", Backend: models.DetectorBackendLocal, LocalPath: "/opt/ads"},
+		"unknown backend":  {Name: "ads", Version: "1", Backend: "grpc", Labels: []string{"ad"}},
+		"unknown builtin":  {Name: "ads", Version: "1", Backend: models.DetectorBackendBuiltin, Labels: []string{"ad"}},
+		"relative path":    {Name: "ads", Version: "1", Backend: models.DetectorBackendLocal, LocalPath: "ads", Labels: []string{"ad"}},
+		"endpoint scheme":  {Name: "ads", Version: "1", Backend: models.DetectorBackendHTTP, Endpoint: "ftp://host/ads", Labels: []string{"ad"}},
+		"path and address": {Name: "ads", Version: "1", Backend: models.DetectorBackendHTTP, Endpoint: "https://host/ads", LocalPath: "/opt/ads", Labels: []string{"ad"}},
+		"unknown input":    {Name: "ads", Version: "1", Backend: models.DetectorBackendHTTP, Endpoint: "https://host/ads", Input: "spectrogram", Labels: []string{"ad"}},
+		"long chunks":      {Name: "ads", Version: "1", Backend: models.DetectorBackendHTTP, Endpoint: "https://host/ads", ChunkSeconds: 3600, Labels: []string{"ad"}},
+		"token name":       {Name: "ads", Version: "1", Backend: models.DetectorBackendHTTP, Endpoint: "https://host/ads", TokenEnv: "not a name", Labels: []string{"ad"}},
+		"local settings":   {Name: "ads", Version: "1", Backend: models.DetectorBackendLocal, LocalPath: "/opt/ads", Input: models.DetectorInputAudio, Labels: []string{"ad"}},
+	}
+	for name, model := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Register(ctx, &model, "admin-1")
+			assert.ErrorIs(t, err, ErrInvalidModel)
+		})
+	}
+}
+
+func TestSetDefault_MovesFlag(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	builtin, err := svc.Resolve(ctx, "", "")
+	require.NoError(t, err)
+
+	remote, err := svc.Register(ctx, &models.DetectorModel{
+		Name: "ads", Version: "1", Backend: models.DetectorBackendHTTP,
+		Endpoint: "https://inference.example.com/ads", Labels: []string{"advertisement"}, IsDefault: true,
+	}, "admin-1")
+	require.NoError(t, err)
+	assert.True(t, remote.IsDefault)
+
+	resolved, err := svc.Resolve(ctx, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, remote.ID, resolved.ID)
+
+	assert.ErrorIs(t, svc.Delete(ctx, remote.ID), ErrDefaultModel)
+	require.NoError(t, svc.Delete(ctx, builtin.ID))
+	assert.ErrorIs(t, svc.Delete(ctx, builtin.ID), ErrModelNotFound)
+}
@@ -0,0 +1,8 @@
+package notifications
+
+import "errors"
+
+var (
+	// ErrInvalidNotification is returned when a notification is missing its owner, type or title
+	ErrInvalidNotification = errors.New("invalid notification")
+)
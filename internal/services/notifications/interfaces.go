@@ -0,0 +1,65 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// ListOptions selects a page of a user's feed, newest first
+type ListOptions struct {
+	UnreadOnly bool
+	Limit      int
+	BeforeID   uint // Cursor: only notifications older than this ID
+}
+
+// Service defines the interface for the per-user notification feed
+type Service interface {
+	// Notify stores a notification
+	Notify(ctx context.Context, notification *models.Notification) error
+
+	// NotifyNewEpisodes tells every subscriber of a podcast about newly synced episodes
+	NotifyNewEpisodes(ctx context.Context, podcastID uint, episodes []*models.Episode) error
+
+	// NotifyJobCompleted tells the user who created a job that it finished.
+	// Jobs without a creator are ignored.
+	NotifyJobCompleted(ctx context.Context, job *models.Job) error
+
+	// List returns a page of the user's notifications
+	List(ctx context.Context, userID string, opts ListOptions) ([]models.Notification, error)
+
+	// UnreadCount returns how many of the user's notifications are unread
+	UnreadCount(ctx context.Context, userID string) (int64, error)
+
+	// MarkRead marks the given notifications read, or all of them when ids is
+	// empty, and returns how many changed
+	MarkRead(ctx context.Context, userID string, ids []uint) (int64, error)
+
+	// Purge deletes notifications created before the cutoff
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Repository defines the interface for notification persistence
+type Repository interface {
+	// Create stores notifications in one batch
+	Create(ctx context.Context, notifications []models.Notification) error
+
+	// List returns a page of the user's notifications, newest first
+	List(ctx context.Context, userID string, opts ListOptions) ([]models.Notification, error)
+
+	// CountUnread counts the user's unread notifications
+	CountUnread(ctx context.Context, userID string) (int64, error)
+
+	// MarkRead sets read_at on the user's unread notifications, limited to ids when given
+	MarkRead(ctx context.Context, userID string, ids []uint, at time.Time) (int64, error)
+
+	// DeleteBefore removes notifications created before the cutoff
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// SubscriberIDs returns the users subscribed to a podcast
+	SubscriberIDs(ctx context.Context, podcastID uint) ([]string, error)
+
+	// PodcastTitle returns a podcast's title
+	PodcastTitle(ctx context.Context, podcastID uint) (string, error)
+}
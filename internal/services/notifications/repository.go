@@ -0,0 +1,92 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// insertBatchSize bounds rows per INSERT when fanning out to many subscribers
+const insertBatchSize = 500
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new notifications repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create stores notifications in one batch
+func (r *repository) Create(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(notifications, insertBatchSize).Error
+}
+
+// List returns a page of the user's notifications, newest first
+func (r *repository) List(ctx context.Context, userID string, opts ListOptions) ([]models.Notification, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if opts.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if opts.BeforeID > 0 {
+		query = query.Where("id < ?", opts.BeforeID)
+	}
+
+	var notifications []models.Notification
+	err := query.Order("id DESC").Limit(opts.Limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnread counts the user's unread notifications
+func (r *repository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead sets read_at on the user's unread notifications, limited to ids when given
+func (r *repository) MarkRead(ctx context.Context, userID string, ids []uint, at time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	result := query.Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteBefore removes notifications created before the cutoff
+func (r *repository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+// SubscriberIDs returns the users subscribed to a podcast
+func (r *repository) SubscriberIDs(ctx context.Context, podcastID uint) ([]string, error) {
+	var userIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&models.Subscription{}).
+		Where("podcast_id = ?", podcastID).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// PodcastTitle returns a podcast's title
+func (r *repository) PodcastTitle(ctx context.Context, podcastID uint) (string, error) {
+	var podcast models.Podcast
+	err := r.db.WithContext(ctx).Select("title").First(&podcast, podcastID).Error
+	return podcast.Title, err
+}
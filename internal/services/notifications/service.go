@@ -0,0 +1,162 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/datatypes"
+)
+
+// Page size bounds for List
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// maxEpisodeIDs caps the episode IDs stored on a single new-episodes notification
+const maxEpisodeIDs = 20
+
+// service implements the Service interface
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new notifications service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// Notify stores a notification
+func (s *service) Notify(ctx context.Context, notification *models.Notification) error {
+	if notification.UserID == "" || notification.Type == "" || notification.Title == "" {
+		return ErrInvalidNotification
+	}
+	notifications := []models.Notification{*notification}
+	if err := s.repo.Create(ctx, notifications); err != nil {
+		return fmt.Errorf("storing notification: %w", err)
+	}
+	notification.ID = notifications[0].ID
+	return nil
+}
+
+// NotifyNewEpisodes tells every subscriber of a podcast about newly synced episodes.
+// One notification per subscriber summarizes the whole batch.
+func (s *service) NotifyNewEpisodes(ctx context.Context, podcastID uint, episodes []*models.Episode) error {
+	if len(episodes) == 0 {
+		return nil
+	}
+
+	subscribers, err := s.repo.SubscriberIDs(ctx, podcastID)
+	if err != nil {
+		return fmt.Errorf("listing subscribers: %w", err)
+	}
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	podcastTitle, err := s.repo.PodcastTitle(ctx, podcastID)
+	if err != nil {
+		return fmt.Errorf("loading podcast: %w", err)
+	}
+
+	title := fmt.Sprintf("New episode of %s", podcastTitle)
+	if len(episodes) > 1 {
+		title = fmt.Sprintf("%d new episodes of %s", len(episodes), podcastTitle)
+	}
+
+	episodeIDs := make([]int64, 0, min(len(episodes), maxEpisodeIDs))
+	for _, episode := range episodes[:min(len(episodes), maxEpisodeIDs)] {
+		episodeIDs = append(episodeIDs, episode.PodcastIndexID)
+	}
+	data := datatypes.JSONMap{
+		"podcast_id":  podcastID,
+		"episode_ids": episodeIDs,
+		"count":       len(episodes),
+	}
+
+	notifications := make([]models.Notification, 0, len(subscribers))
+	for _, userID := range subscribers {
+		notifications = append(notifications, models.Notification{
+			UserID: userID,
+			Type:   models.NotificationNewEpisodes,
+			Title:  title,
+			Body:   episodes[0].Title,
+			Data:   data,
+		})
+	}
+
+	if err := s.repo.Create(ctx, notifications); err != nil {
+		return fmt.Errorf("storing new episode notifications: %w", err)
+	}
+	return nil
+}
+
+// NotifyJobCompleted tells the user who created a job that it finished
+func (s *service) NotifyJobCompleted(ctx context.Context, job *models.Job) error {
+	if job == nil || job.CreatedBy == "" {
+		return nil
+	}
+
+	notification := &models.Notification{
+		UserID: job.CreatedBy,
+		Type:   models.NotificationJobCompleted,
+		Title:  fmt.Sprintf("Your %s job has finished", strings.ReplaceAll(string(job.Type), "_", " ")),
+		Data: datatypes.JSONMap{
+			"job_id":   job.ID,
+			"job_type": job.Type,
+		},
+	}
+	if job.Type == models.JobTypeUserExport {
+		notification.Type = models.NotificationExportReady
+		notification.Title = "Your data export is ready"
+		notification.Body = "Open the export to get a download link."
+	}
+
+	return s.Notify(ctx, notification)
+}
+
+// List returns a page of the user's notifications
+func (s *service) List(ctx context.Context, userID string, opts ListOptions) ([]models.Notification, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultListLimit
+	}
+	if opts.Limit > MaxListLimit {
+		opts.Limit = MaxListLimit
+	}
+
+	notifications, err := s.repo.List(ctx, userID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// UnreadCount returns how many of the user's notifications are unread
+func (s *service) UnreadCount(ctx context.Context, userID string) (int64, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("counting unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks the given notifications read, or all of them when ids is empty
+func (s *service) MarkRead(ctx context.Context, userID string, ids []uint) (int64, error) {
+	updated, err := s.repo.MarkRead(ctx, userID, ids, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("marking notifications read: %w", err)
+	}
+	return updated, nil
+}
+
+// Purge deletes notifications created before the cutoff
+func (s *service) Purge(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := s.repo.DeleteBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("purging notifications: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,150 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Subscription{}, &models.Notification{}))
+	return db
+}
+
+func seedPodcast(t *testing.T, db *gorm.DB, subscribers ...string) uint {
+	podcast := models.Podcast{PodcastIndexID: 100, Title: "Test Show", FeedURL: "https://example.com/feed"}
+	require.NoError(t, db.Create(&podcast).Error)
+	for _, userID := range subscribers {
+		require.NoError(t, db.Create(&models.Subscription{UserID: userID, PodcastID: podcast.ID}).Error)
+	}
+	return podcast.ID
+}
+
+func TestNotifyNewEpisodes_FansOutToSubscribers(t *testing.T) {
+	db := setupTestDB(t)
+	podcastID := seedPodcast(t, db, "user-1", "user-2")
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	episodes := []*models.Episode{
+		{PodcastIndexID: 2, Title: "Latest"},
+		{PodcastIndexID: 1, Title: "Earlier"},
+	}
+	require.NoError(t, svc.NotifyNewEpisodes(ctx, podcastID, episodes))
+
+	for _, userID := range []string{"user-1", "user-2"} {
+		items, err := svc.List(ctx, userID, ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, models.NotificationNewEpisodes, items[0].Type)
+		assert.Equal(t, "2 new episodes of Test Show", items[0].Title)
+		assert.Equal(t, "Latest", items[0].Body)
+		assert.Equal(t, json.Number("2"), items[0].Data["count"])
+	}
+
+	items, err := svc.List(ctx, "user-3", ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestNotifyJobCompleted(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	// System jobs have no creator and are ignored
+	require.NoError(t, svc.NotifyJobCompleted(ctx, &models.Job{Type: models.JobTypeWaveformGeneration}))
+
+	export := &models.Job{Type: models.JobTypeUserExport, CreatedBy: "user-1"}
+	export.ID = 7
+	require.NoError(t, svc.NotifyJobCompleted(ctx, export))
+
+	items, err := svc.List(ctx, "user-1", ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, models.NotificationExportReady, items[0].Type)
+	assert.Equal(t, json.Number("7"), items[0].Data["job_id"])
+
+	var total int64
+	require.NoError(t, db.Model(&models.Notification{}).Count(&total).Error)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestListAndMarkRead(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	var ids []uint
+	for i := 0; i < 5; i++ {
+		n := &models.Notification{UserID: "user-1", Type: models.NotificationJobCompleted, Title: "done"}
+		require.NoError(t, svc.Notify(ctx, n))
+		ids = append(ids, n.ID)
+	}
+	other := &models.Notification{UserID: "user-2", Type: models.NotificationJobCompleted, Title: "done"}
+	require.NoError(t, svc.Notify(ctx, other))
+
+	// Newest first, paged by ID cursor
+	page, err := svc.List(ctx, "user-1", ListOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, ids[4], page[0].ID)
+	next, err := svc.List(ctx, "user-1", ListOptions{Limit: 2, BeforeID: page[1].ID})
+	require.NoError(t, err)
+	require.Len(t, next, 2)
+	assert.Equal(t, ids[2], next[0].ID)
+
+	// Another user's notification is not touched
+	updated, err := svc.MarkRead(ctx, "user-1", []uint{ids[0], other.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	unread, err := svc.UnreadCount(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), unread)
+
+	unreadOnly, err := svc.List(ctx, "user-1", ListOptions{UnreadOnly: true})
+	require.NoError(t, err)
+	assert.Len(t, unreadOnly, 4)
+
+	updated, err = svc.MarkRead(ctx, "user-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), updated)
+
+	unread, err = svc.UnreadCount(ctx, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), unread)
+
+	assert.ErrorIs(t, svc.Notify(ctx, &models.Notification{UserID: "user-1"}), ErrInvalidNotification)
+}
+
+func TestPurge(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	old := models.Notification{UserID: "user-1", Type: models.NotificationJobCompleted, Title: "old", CreatedAt: time.Now().Add(-48 * time.Hour)}
+	require.NoError(t, db.Create(&old).Error)
+	require.NoError(t, svc.Notify(ctx, &models.Notification{UserID: "user-1", Type: models.NotificationJobCompleted, Title: "new"}))
+
+	deleted, err := svc.Purge(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	items, err := svc.List(ctx, "user-1", ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "new", items[0].Title)
+}
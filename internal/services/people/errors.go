@@ -0,0 +1,8 @@
+package people
+
+import "errors"
+
+var (
+	// ErrPersonNotFound is returned when a person does not exist
+	ErrPersonNotFound = errors.New("person not found")
+)
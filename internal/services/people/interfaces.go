@@ -0,0 +1,38 @@
+package people
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/episodes"
+)
+
+// Service defines the interface for podcast:person credits
+type Service interface {
+	// IngestEpisodePeople replaces the credits for an episode with the given persons
+	IngestEpisodePeople(ctx context.Context, podcastIndexEpisodeID int64, persons []episodes.Person) error
+
+	// GetEpisodePeople returns the people credited on an episode
+	GetEpisodePeople(ctx context.Context, podcastIndexEpisodeID int64) ([]models.EpisodePerson, error)
+
+	// GetPerson retrieves a person by ID
+	GetPerson(ctx context.Context, id uint) (*models.Person, error)
+
+	// ListPersonEpisodes returns locally stored episodes a person is credited on, newest first
+	ListPersonEpisodes(ctx context.Context, personID uint, limit, offset int) ([]models.Episode, int64, error)
+}
+
+// Repository defines the interface for people persistence
+type Repository interface {
+	// ReplaceEpisodeCredits upserts the credited people and replaces the episode's links
+	ReplaceEpisodeCredits(ctx context.Context, podcastIndexEpisodeID int64, credits []models.EpisodePerson) error
+
+	// ListEpisodeCredits returns an episode's credits with people preloaded
+	ListEpisodeCredits(ctx context.Context, podcastIndexEpisodeID int64) ([]models.EpisodePerson, error)
+
+	// GetByID retrieves a person by ID
+	GetByID(ctx context.Context, id uint) (*models.Person, error)
+
+	// ListEpisodes returns episodes linked to a person
+	ListEpisodes(ctx context.Context, personID uint, limit, offset int) ([]models.Episode, int64, error)
+}
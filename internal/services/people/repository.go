@@ -0,0 +1,106 @@
+package people
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new people repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ReplaceEpisodeCredits upserts the credited people and replaces the episode's links
+func (r *repository) ReplaceEpisodeCredits(ctx context.Context, podcastIndexEpisodeID int64, credits []models.EpisodePerson) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		links := make([]models.EpisodePerson, 0, len(credits))
+		for _, credit := range credits {
+			person := credit.Person
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "img", "updated_at"}),
+			}).Create(&person).Error; err != nil {
+				return fmt.Errorf("upserting person %q: %w", person.Name, err)
+			}
+
+			// The upsert does not return the ID of an existing row
+			if err := tx.Where("key = ?", person.Key).First(&person).Error; err != nil {
+				return fmt.Errorf("loading person %q: %w", person.Name, err)
+			}
+
+			links = append(links, models.EpisodePerson{
+				PodcastIndexEpisodeID: podcastIndexEpisodeID,
+				PersonID:              person.ID,
+				Role:                  credit.Role,
+				Group:                 credit.Group,
+			})
+		}
+
+		if err := tx.Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).Delete(&models.EpisodePerson{}).Error; err != nil {
+			return fmt.Errorf("clearing episode credits: %w", err)
+		}
+
+		if len(links) == 0 {
+			return nil
+		}
+
+		return tx.Omit("Person").Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+}
+
+// ListEpisodeCredits returns an episode's credits with people preloaded
+func (r *repository) ListEpisodeCredits(ctx context.Context, podcastIndexEpisodeID int64) ([]models.EpisodePerson, error) {
+	var credits []models.EpisodePerson
+	err := r.db.WithContext(ctx).
+		Preload("Person").
+		Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).
+		Order("\"group\" ASC, role ASC").
+		Find(&credits).Error
+	if err != nil {
+		return nil, fmt.Errorf("listing episode credits: %w", err)
+	}
+	return credits, nil
+}
+
+// GetByID retrieves a person by ID
+func (r *repository) GetByID(ctx context.Context, id uint) (*models.Person, error) {
+	var person models.Person
+	if err := r.db.WithContext(ctx).First(&person, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPersonNotFound
+		}
+		return nil, fmt.Errorf("getting person: %w", err)
+	}
+	return &person, nil
+}
+
+// ListEpisodes returns episodes linked to a person with their podcasts preloaded
+func (r *repository) ListEpisodes(ctx context.Context, personID uint, limit, offset int) ([]models.Episode, int64, error) {
+	subQuery := r.db.Model(&models.EpisodePerson{}).
+		Select("DISTINCT podcast_index_episode_id").
+		Where("person_id = ?", personID)
+
+	query := r.db.WithContext(ctx).Model(&models.Episode{}).Where("podcast_index_id IN (?)", subQuery)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting person episodes: %w", err)
+	}
+
+	var episodes []models.Episode
+	if err := query.Preload("Podcast").Order("published_at DESC").Limit(limit).Offset(offset).Find(&episodes).Error; err != nil {
+		return nil, 0, fmt.Errorf("listing person episodes: %w", err)
+	}
+
+	return episodes, total, nil
+}
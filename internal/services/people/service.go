@@ -0,0 +1,70 @@
+package people
+
+import (
+	"context"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/episodes"
+)
+
+// defaultRole is used when a podcast:person tag omits the role attribute,
+// matching the podcast namespace default
+const defaultRole = "host"
+
+// service implements the Service interface
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new people service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// IngestEpisodePeople replaces the credits for an episode with the given persons
+func (s *service) IngestEpisodePeople(ctx context.Context, podcastIndexEpisodeID int64, persons []episodes.Person) error {
+	credits := make([]models.EpisodePerson, 0, len(persons))
+	for _, p := range persons {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			continue
+		}
+
+		role := strings.ToLower(strings.TrimSpace(p.Role))
+		if role == "" {
+			role = defaultRole
+		}
+
+		credits = append(credits, models.EpisodePerson{
+			Role:  role,
+			Group: strings.ToLower(strings.TrimSpace(p.Group)),
+			Person: models.Person{
+				Key:  models.PersonKey(name, p.Href),
+				Name: name,
+				Href: strings.TrimSpace(p.Href),
+				Img:  strings.TrimSpace(p.Img),
+			},
+		})
+	}
+
+	return s.repo.ReplaceEpisodeCredits(ctx, podcastIndexEpisodeID, credits)
+}
+
+// GetEpisodePeople returns the people credited on an episode
+func (s *service) GetEpisodePeople(ctx context.Context, podcastIndexEpisodeID int64) ([]models.EpisodePerson, error) {
+	return s.repo.ListEpisodeCredits(ctx, podcastIndexEpisodeID)
+}
+
+// GetPerson retrieves a person by ID
+func (s *service) GetPerson(ctx context.Context, id uint) (*models.Person, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListPersonEpisodes returns locally stored episodes a person is credited on, newest first
+func (s *service) ListPersonEpisodes(ctx context.Context, personID uint, limit, offset int) ([]models.Episode, int64, error) {
+	if _, err := s.repo.GetByID(ctx, personID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListEpisodes(ctx, personID, limit, offset)
+}
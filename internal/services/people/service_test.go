@@ -0,0 +1,114 @@
+package people
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Episode{}, &models.Person{}, &models.EpisodePerson{}))
+	return db
+}
+
+func createEpisode(t *testing.T, db *gorm.DB, podcastIndexID int64, published time.Time) {
+	require.NoError(t, db.Create(&models.Episode{
+		PodcastID:      1,
+		PodcastIndexID: podcastIndexID,
+		Title:          "Episode",
+		GUID:           fmt.Sprintf("guid-%d", podcastIndexID),
+		AudioURL:       "https://example.com/audio.mp3",
+		PublishedAt:    published,
+	}).Error)
+}
+
+func TestIngestEpisodePeople_DeduplicatesAcrossEpisodes(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	host := episodes.Person{Name: "Jane Host", Href: "https://example.com/jane"}
+	require.NoError(t, svc.IngestEpisodePeople(ctx, 1, []episodes.Person{
+		host,
+		{Name: "Guest One", Role: "Guest", Group: "Cast"},
+		{Name: "   "},
+	}))
+	require.NoError(t, svc.IngestEpisodePeople(ctx, 2, []episodes.Person{
+		{Name: "Jane Host", Href: "https://example.com/Jane", Img: "https://example.com/jane.jpg"},
+	}))
+
+	credits, err := svc.GetEpisodePeople(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, credits, 2)
+
+	var hostCredit models.EpisodePerson
+	for _, credit := range credits {
+		if credit.Role == "host" {
+			hostCredit = credit
+		}
+	}
+	assert.Equal(t, "Jane Host", hostCredit.Person.Name)
+
+	second, err := svc.GetEpisodePeople(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, hostCredit.PersonID, second[0].PersonID)
+	assert.Equal(t, "https://example.com/jane.jpg", second[0].Person.Img)
+}
+
+func TestIngestEpisodePeople_ReplacesCredits(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	require.NoError(t, svc.IngestEpisodePeople(ctx, 1, []episodes.Person{{Name: "Old Guest", Role: "guest"}}))
+	require.NoError(t, svc.IngestEpisodePeople(ctx, 1, []episodes.Person{{Name: "New Guest", Role: "guest"}}))
+
+	credits, err := svc.GetEpisodePeople(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, credits, 1)
+	assert.Equal(t, "New Guest", credits[0].Person.Name)
+}
+
+func TestListPersonEpisodes(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	now := time.Now()
+	createEpisode(t, db, 10, now.Add(-48*time.Hour))
+	createEpisode(t, db, 11, now)
+
+	guest := episodes.Person{Name: "Recurring Guest", Role: "guest"}
+	require.NoError(t, svc.IngestEpisodePeople(ctx, 10, []episodes.Person{guest}))
+	require.NoError(t, svc.IngestEpisodePeople(ctx, 11, []episodes.Person{guest}))
+	// Credits for episodes not stored locally are kept but not listed
+	require.NoError(t, svc.IngestEpisodePeople(ctx, 12, []episodes.Person{guest}))
+
+	credits, err := svc.GetEpisodePeople(ctx, 10)
+	require.NoError(t, err)
+	personID := credits[0].PersonID
+
+	list, total, err := svc.ListPersonEpisodes(ctx, personID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(11), list[0].PodcastIndexID)
+
+	_, _, err = svc.ListPersonEpisodes(ctx, 999, 10, 0)
+	assert.True(t, errors.Is(err, ErrPersonNotFound))
+}
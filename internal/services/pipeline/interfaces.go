@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// Step is one job the pipeline can queue for newly cached audio
+type Step string
+
+const (
+	StepWaveform      Step = "waveform"
+	StepTranscription Step = "transcription"
+	StepAnalysis      Step = "analysis"
+)
+
+// stepOrder is the order steps are queued in, whatever order a profile lists
+// them: cheap, user-visible work first, then the steps that build on it
+var stepOrder = []Step{StepWaveform, StepTranscription, StepAnalysis}
+
+// Config selects the steps each podcast's episodes get once their audio is cached
+type Config struct {
+	Profiles        map[string][]Step // Named step lists
+	DefaultProfile  string            // Profile for podcasts without an entry in PodcastProfiles
+	PodcastProfiles map[int64]string  // Podcast Index feed ID -> profile name
+}
+
+// JobEnqueuer queues deduplicated jobs; jobs.Service satisfies it
+type JobEnqueuer interface {
+	EnqueueUniqueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, uniqueKey string, opts ...jobs.JobOption) (*models.Job, error)
+}
+
+// EpisodeLookup resolves an episode to its podcast
+type EpisodeLookup interface {
+	GetEpisodeByPodcastIndexID(ctx context.Context, podcastIndexID int64) (*models.Episode, error)
+}
+
+// WaveformChecker reports whether an episode already has a waveform
+type WaveformChecker interface {
+	WaveformExists(ctx context.Context, podcastIndexEpisodeID int64) (bool, error)
+}
+
+// TranscriptionChecker loads an episode's transcription, if any
+type TranscriptionChecker interface {
+	GetTranscription(ctx context.Context, podcastIndexEpisodeID int64) (*models.Transcription, error)
+}
+
+// ClipLister lists clips, used to tell whether an episode was analyzed
+type ClipLister interface {
+	ListClips(ctx context.Context, filters clips.ListClipsFilters) ([]*models.Clip, error)
+}
+
+// Checkers tell the pipeline which results already exist. A nil checker
+// disables its step, as there is then no service to process the job either.
+type Checkers struct {
+	Waveforms      WaveformChecker
+	Transcriptions TranscriptionChecker
+	Clips          ClipLister
+}
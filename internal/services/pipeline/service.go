@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	episodeanalysis "github.com/killallgit/player-api/internal/services/episode_analysis"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// Pipeline queues the follow-up jobs for episodes whose audio was just cached,
+// so waveform, transcription and analysis reuse the one download
+type Pipeline struct {
+	jobs     JobEnqueuer
+	episodes EpisodeLookup
+	checkers Checkers
+	cfg      Config
+}
+
+// New creates a post-cache pipeline
+func New(jobs JobEnqueuer, episodes EpisodeLookup, checkers Checkers, cfg Config) *Pipeline {
+	return &Pipeline{
+		jobs:     jobs,
+		episodes: episodes,
+		checkers: checkers,
+		cfg:      cfg,
+	}
+}
+
+// AudioCached queues the steps of the episode's podcast profile that have not
+// run yet. It is an audiocache.CachedHook; failures are logged, never returned,
+// since the audio itself was cached fine.
+func (p *Pipeline) AudioCached(ctx context.Context, cache *models.AudioCache) {
+	episodeID := cache.PodcastIndexEpisodeID
+	steps := p.StepsFor(ctx, episodeID)
+	if len(steps) == 0 {
+		return
+	}
+
+	for _, step := range steps {
+		jobType, done, err := p.check(ctx, step, episodeID)
+		if err != nil {
+			log.Printf("[WARN] Pipeline: checking %s for episode %d: %v", step, episodeID, err)
+			continue
+		}
+		if done {
+			continue
+		}
+
+		// Nobody is waiting on these yet, so they yield to requested work
+		job, err := p.jobs.EnqueueUniqueJob(ctx, jobType, models.JobPayload{"episode_id": episodeID}, "episode_id",
+			jobs.WithPriority(jobs.LowPriority))
+		if err != nil {
+			log.Printf("[WARN] Pipeline: failed to enqueue %s for episode %d: %v", step, episodeID, err)
+			continue
+		}
+		log.Printf("[DEBUG] Pipeline: %s job %d (%s) for episode %d", step, job.ID, job.Status, episodeID)
+	}
+}
+
+// StepsFor returns the steps configured for an episode's podcast in the order
+// they are queued
+func (p *Pipeline) StepsFor(ctx context.Context, episodeID int64) []Step {
+	profile := p.cfg.DefaultProfile
+	if len(p.cfg.PodcastProfiles) > 0 && p.episodes != nil {
+		episode, err := p.episodes.GetEpisodeByPodcastIndexID(ctx, episodeID)
+		if err != nil {
+			log.Printf("[WARN] Pipeline: looking up episode %d: %v", episodeID, err)
+		} else if name, ok := p.cfg.PodcastProfiles[episode.PodcastIndexFeedID]; ok {
+			profile = name
+		}
+	}
+
+	wanted := make(map[Step]bool)
+	for _, step := range p.cfg.Profiles[profile] {
+		wanted[step] = true
+	}
+
+	var steps []Step
+	for _, step := range stepOrder {
+		if wanted[step] {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// check maps a step to its job type and reports whether its result already exists
+func (p *Pipeline) check(ctx context.Context, step Step, episodeID int64) (models.JobType, bool, error) {
+	switch step {
+	case StepWaveform:
+		if p.checkers.Waveforms == nil {
+			return "", true, nil
+		}
+		exists, err := p.checkers.Waveforms.WaveformExists(ctx, episodeID)
+		return models.JobTypeWaveformGeneration, exists, err
+
+	case StepTranscription:
+		if p.checkers.Transcriptions == nil {
+			return "", true, nil
+		}
+		// The service reports a missing transcription as an error
+		transcription, err := p.checkers.Transcriptions.GetTranscription(ctx, episodeID)
+		return models.JobTypeTranscriptionGeneration, err == nil && transcription != nil, nil
+
+	case StepAnalysis:
+		if p.checkers.Clips == nil {
+			return "", true, nil
+		}
+		spikes, err := p.checkers.Clips.ListClips(ctx, clips.ListClipsFilters{
+			EpisodeID: &episodeID,
+			Label:     episodeanalysis.VolumeSpikeLabel,
+			Limit:     1,
+		})
+		return models.JobTypeEpisodeAnalysis, len(spikes) > 0, err
+	}
+	return "", true, nil
+}
@@ -0,0 +1,132 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobs struct {
+	queued []models.JobType
+}
+
+func (f *fakeJobs) EnqueueUniqueJob(ctx context.Context, jobType models.JobType, payload models.JobPayload, uniqueKey string, opts ...jobs.JobOption) (*models.Job, error) {
+	f.queued = append(f.queued, jobType)
+	return &models.Job{Type: jobType, Status: models.JobStatusPending, Payload: payload}, nil
+}
+
+type fakeEpisodes map[int64]int64 // episode ID -> feed ID
+
+func (f fakeEpisodes) GetEpisodeByPodcastIndexID(ctx context.Context, id int64) (*models.Episode, error) {
+	feedID, ok := f[id]
+	if !ok {
+		return nil, errors.New("episode not found")
+	}
+	return &models.Episode{PodcastIndexID: id, PodcastIndexFeedID: feedID}, nil
+}
+
+type fakeWaveforms map[int64]bool
+
+func (f fakeWaveforms) WaveformExists(ctx context.Context, id int64) (bool, error) {
+	return f[id], nil
+}
+
+type fakeTranscriptions map[int64]bool
+
+func (f fakeTranscriptions) GetTranscription(ctx context.Context, id int64) (*models.Transcription, error) {
+	if !f[id] {
+		return nil, errors.New("transcription not found")
+	}
+	return &models.Transcription{PodcastIndexEpisodeID: id}, nil
+}
+
+type fakeClips map[int64]bool // episodes with spike clips
+
+func (f fakeClips) ListClips(ctx context.Context, filters clips.ListClipsFilters) ([]*models.Clip, error) {
+	if filters.EpisodeID != nil && f[*filters.EpisodeID] {
+		return []*models.Clip{{Label: filters.Label}}, nil
+	}
+	return nil, nil
+}
+
+var testConfig = Config{
+	Profiles: map[string][]Step{
+		"waveform": {StepWaveform},
+		"full":     {StepAnalysis, StepTranscription, StepWaveform},
+	},
+	DefaultProfile:  "waveform",
+	PodcastProfiles: map[int64]string{900: "full"},
+}
+
+func cached(episodeID int64) *models.AudioCache {
+	return &models.AudioCache{PodcastIndexEpisodeID: episodeID}
+}
+
+func TestAudioCached_QueuesProfileInDependencyOrder(t *testing.T) {
+	queue := &fakeJobs{}
+	p := New(queue, fakeEpisodes{1: 900}, Checkers{
+		Waveforms:      fakeWaveforms{},
+		Transcriptions: fakeTranscriptions{},
+		Clips:          fakeClips{},
+	}, testConfig)
+
+	p.AudioCached(context.Background(), cached(1))
+
+	assert.Equal(t, []models.JobType{
+		models.JobTypeWaveformGeneration,
+		models.JobTypeTranscriptionGeneration,
+		models.JobTypeEpisodeAnalysis,
+	}, queue.queued)
+}
+
+func TestAudioCached_DefaultProfile(t *testing.T) {
+	queue := &fakeJobs{}
+	p := New(queue, fakeEpisodes{2: 100}, Checkers{
+		Waveforms:      fakeWaveforms{},
+		Transcriptions: fakeTranscriptions{},
+		Clips:          fakeClips{},
+	}, testConfig)
+
+	p.AudioCached(context.Background(), cached(2))
+	assert.Equal(t, []models.JobType{models.JobTypeWaveformGeneration}, queue.queued)
+
+	// An episode that can't be looked up falls back to the default too
+	queue.queued = nil
+	p.AudioCached(context.Background(), cached(3))
+	assert.Equal(t, []models.JobType{models.JobTypeWaveformGeneration}, queue.queued)
+}
+
+func TestAudioCached_SkipsExistingResults(t *testing.T) {
+	queue := &fakeJobs{}
+	p := New(queue, fakeEpisodes{1: 900}, Checkers{
+		Waveforms:      fakeWaveforms{1: true},
+		Transcriptions: fakeTranscriptions{1: true},
+		Clips:          fakeClips{},
+	}, testConfig)
+
+	p.AudioCached(context.Background(), cached(1))
+	assert.Equal(t, []models.JobType{models.JobTypeEpisodeAnalysis}, queue.queued)
+}
+
+func TestAudioCached_SkipsStepsWithoutService(t *testing.T) {
+	queue := &fakeJobs{}
+	p := New(queue, fakeEpisodes{1: 900}, Checkers{Waveforms: fakeWaveforms{}}, testConfig)
+
+	p.AudioCached(context.Background(), cached(1))
+	assert.Equal(t, []models.JobType{models.JobTypeWaveformGeneration}, queue.queued)
+}
+
+func TestAudioCached_UnknownProfileQueuesNothing(t *testing.T) {
+	queue := &fakeJobs{}
+	cfg := testConfig
+	cfg.DefaultProfile = "none"
+	p := New(queue, nil, Checkers{Waveforms: fakeWaveforms{}}, cfg)
+
+	p.AudioCached(context.Background(), cached(1))
+	assert.Empty(t, queue.queued)
+}
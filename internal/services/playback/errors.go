@@ -0,0 +1,11 @@
+package playback
+
+import "errors"
+
+var (
+	// ErrProgressNotFound is returned when the user has no progress for an episode
+	ErrProgressNotFound = errors.New("playback progress not found")
+
+	// ErrInvalidPosition is returned for negative positions or durations
+	ErrInvalidPosition = errors.New("position and duration must be non-negative")
+)
@@ -0,0 +1,81 @@
+package playback
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Service defines the interface for per-user playback progress
+type Service interface {
+	// UpdateProgress records the user's position in an episode, marking it completed
+	// once the position passes the completion threshold or completed is true
+	UpdateProgress(ctx context.Context, userID string, podcastIndexEpisodeID int64, position, duration float64, completed bool) (*models.PlaybackProgress, error)
+
+	// ImportProgress records a position brought over from another app, as of
+	// the time the app last saw it (zero for unknown). Progress the user has
+	// reported here since then is kept. Imported completions with a known time
+	// count towards history and stats on the day they happened; listening
+	// time is not imported.
+	ImportProgress(ctx context.Context, userID string, podcastIndexEpisodeID int64, position, duration float64, completed bool, at time.Time) (*models.PlaybackProgress, bool, error)
+
+	// GetProgress retrieves the user's progress for an episode
+	GetProgress(ctx context.Context, userID string, podcastIndexEpisodeID int64) (*models.PlaybackProgress, error)
+
+	// PlayedEpisodeIDs returns the set of episodes the user has completed
+	PlayedEpisodeIDs(ctx context.Context, userID string) (map[int64]struct{}, error)
+
+	// GetStats returns listening totals, top podcasts and streaks for the user
+	GetStats(ctx context.Context, userID string) (*Stats, error)
+}
+
+// Stats summarizes a user's listening
+type Stats struct {
+	SecondsListened   float64        `json:"seconds_listened"`
+	HoursListened     float64        `json:"hours_listened"`
+	EpisodesCompleted int            `json:"episodes_completed"`
+	TopPodcasts       []PodcastTotal `json:"top_podcasts"`
+	CurrentStreakDays int            `json:"current_streak_days"` // Consecutive days with listening, ending today or yesterday
+	LongestStreakDays int            `json:"longest_streak_days"`
+	LastListenedDay   string         `json:"last_listened_day,omitempty"` // YYYY-MM-DD (UTC)
+	AdsSkipped        int            `json:"ads_skipped"`                 // From skip_ad playback events within the event retention period
+	AdSecondsSkipped  float64        `json:"ad_seconds_skipped"`
+}
+
+// PodcastTotal counts completed episodes for one podcast
+type PodcastTotal struct {
+	PodcastIndexFeedID int64  `json:"podcast_index_feed_id"`
+	Title              string `json:"title,omitempty"`
+	EpisodesCompleted  int    `json:"episodes_completed"`
+}
+
+// Repository defines the interface for playback progress persistence
+type Repository interface {
+	// Get retrieves progress for a user and episode
+	Get(ctx context.Context, userID string, podcastIndexEpisodeID int64) (*models.PlaybackProgress, error)
+
+	// Save creates or updates progress for a user and episode
+	Save(ctx context.Context, progress *models.PlaybackProgress) error
+
+	// ListCompletedEpisodeIDs returns IDs of episodes the user has completed
+	ListCompletedEpisodeIDs(ctx context.Context, userID string) ([]int64, error)
+
+	// AddListening adds listened seconds and completions to the user's daily aggregate
+	AddListening(ctx context.Context, userID string, day time.Time, seconds float64, completed int) error
+
+	// AddHistory records a completed play
+	AddHistory(ctx context.Context, entry *models.ListeningHistory) error
+
+	// ListDaily returns the user's daily aggregates ordered by day
+	ListDaily(ctx context.Context, userID string) ([]models.DailyListening, error)
+
+	// TopPodcasts returns the podcasts with the most completed plays
+	TopPodcasts(ctx context.Context, userID string, limit int) ([]PodcastTotal, error)
+
+	// FeedIDForEpisode resolves the feed of a locally stored episode, or 0 if unknown
+	FeedIDForEpisode(ctx context.Context, podcastIndexEpisodeID int64) (int64, error)
+
+	// SumAdSkips counts the user's skip_ad playback events and the seconds they skipped
+	SumAdSkips(ctx context.Context, userID string) (int, float64, error)
+}
@@ -0,0 +1,144 @@
+package playback
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new playback progress repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Get retrieves progress for a user and episode
+func (r *repository) Get(ctx context.Context, userID string, podcastIndexEpisodeID int64) (*models.PlaybackProgress, error) {
+	var progress models.PlaybackProgress
+
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND podcast_index_episode_id = ?", userID, podcastIndexEpisodeID).
+		First(&progress)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrProgressNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &progress, nil
+}
+
+// Save creates or updates progress for a user and episode
+func (r *repository) Save(ctx context.Context, progress *models.PlaybackProgress) error {
+	if progress == nil {
+		return errors.New("progress cannot be nil")
+	}
+
+	if progress.ID != 0 {
+		return r.db.WithContext(ctx).Save(progress).Error
+	}
+
+	// Concurrent first reports from two devices resolve to a single row
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "podcast_index_episode_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position", "duration", "completed", "completed_at", "reported_at", "updated_at"}),
+	}).Create(progress).Error
+}
+
+// ListCompletedEpisodeIDs returns IDs of episodes the user has completed
+func (r *repository) ListCompletedEpisodeIDs(ctx context.Context, userID string) ([]int64, error) {
+	var ids []int64
+
+	err := r.db.WithContext(ctx).Model(&models.PlaybackProgress{}).
+		Where("user_id = ? AND completed = ?", userID, true).
+		Pluck("podcast_index_episode_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// AddListening adds listened seconds and completions to the user's daily aggregate
+func (r *repository) AddListening(ctx context.Context, userID string, day time.Time, seconds float64, completed int) error {
+	row := models.DailyListening{
+		UserID:            userID,
+		Day:               day.UTC().Format(dayLayout),
+		SecondsListened:   seconds,
+		EpisodesCompleted: completed,
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"seconds_listened":   gorm.Expr("seconds_listened + ?", seconds),
+			"episodes_completed": gorm.Expr("episodes_completed + ?", completed),
+		}),
+	}).Create(&row).Error
+}
+
+// AddHistory records a completed play
+func (r *repository) AddHistory(ctx context.Context, entry *models.ListeningHistory) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// ListDaily returns the user's daily aggregates ordered by day
+func (r *repository) ListDaily(ctx context.Context, userID string) ([]models.DailyListening, error) {
+	var days []models.DailyListening
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("day ASC").Find(&days).Error; err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// TopPodcasts returns the podcasts with the most completed plays
+func (r *repository) TopPodcasts(ctx context.Context, userID string, limit int) ([]PodcastTotal, error) {
+	var totals []PodcastTotal
+	err := r.db.WithContext(ctx).Table("listening_history AS h").
+		Select("h.podcast_index_feed_id, p.title, COUNT(*) AS episodes_completed").
+		Joins("LEFT JOIN podcasts p ON p.podcast_index_id = h.podcast_index_feed_id AND p.deleted_at IS NULL").
+		Where("h.user_id = ? AND h.podcast_index_feed_id <> 0", userID).
+		Group("h.podcast_index_feed_id, p.title").
+		Order("episodes_completed DESC").
+		Limit(limit).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// FeedIDForEpisode resolves the feed of a locally stored episode, or 0 if unknown
+func (r *repository) FeedIDForEpisode(ctx context.Context, podcastIndexEpisodeID int64) (int64, error) {
+	var feedIDs []int64
+	err := r.db.WithContext(ctx).Model(&models.Episode{}).
+		Where("podcast_index_id = ?", podcastIndexEpisodeID).
+		Limit(1).
+		Pluck("podcast_index_feed_id", &feedIDs).Error
+	if err != nil || len(feedIDs) == 0 {
+		return 0, err
+	}
+	return feedIDs[0], nil
+}
+
+// SumAdSkips counts the user's skip_ad playback events and the seconds they skipped
+func (r *repository) SumAdSkips(ctx context.Context, userID string) (int, float64, error) {
+	var total struct {
+		Skips   int
+		Seconds float64
+	}
+	err := r.db.WithContext(ctx).Model(&models.PlaybackEvent{}).
+		Select("COUNT(*) AS skips, COALESCE(SUM(CASE WHEN position > from_position THEN position - from_position ELSE 0 END), 0) AS seconds").
+		Where("user_id = ? AND type = ?", userID, models.PlaybackEventSkipAd).
+		Scan(&total).Error
+	return total.Skips, total.Seconds, err
+}
@@ -0,0 +1,255 @@
+package playback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// CompletionThreshold is the fraction of an episode that counts as played through
+const CompletionThreshold = 0.95
+
+const (
+	// maxListeningRate bounds how much position may advance per second of wall time
+	// for the advance to count as listening (the highest allowed playback speed)
+	maxListeningRate = 3.0
+
+	// listeningGrace allows for client clock jitter between progress reports
+	listeningGrace = 5 * time.Second
+
+	// topPodcastsLimit is the number of podcasts returned in stats
+	topPodcastsLimit = 5
+
+	dayLayout = "2006-01-02"
+)
+
+// service implements the Service interface
+type service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a new playback progress service
+func NewService(repo Repository) Service {
+	return &service{repo: repo, now: time.Now}
+}
+
+// UpdateProgress records the user's position in an episode. Once an episode is
+// completed it stays completed, so scrubbing back does not un-play it.
+func (s *service) UpdateProgress(ctx context.Context, userID string, podcastIndexEpisodeID int64, position, duration float64, completed bool) (*models.PlaybackProgress, error) {
+	if position < 0 || duration < 0 {
+		return nil, ErrInvalidPosition
+	}
+
+	progress, err := s.repo.Get(ctx, userID, podcastIndexEpisodeID)
+	if err != nil && !errors.Is(err, ErrProgressNotFound) {
+		return nil, fmt.Errorf("loading progress: %w", err)
+	}
+	now := s.now()
+
+	// Seconds listened since the last report; seeks and stale reports count as zero
+	var listened float64
+	if progress == nil {
+		progress = &models.PlaybackProgress{
+			UserID:                userID,
+			PodcastIndexEpisodeID: podcastIndexEpisodeID,
+		}
+	} else if advance := position - progress.Position; advance > 0 {
+		elapsed := now.Sub(progress.ReportedAt) + listeningGrace
+		if advance <= elapsed.Seconds()*maxListeningRate {
+			listened = advance
+		}
+	}
+
+	progress.Position = position
+	progress.ReportedAt = now
+	if duration > 0 {
+		progress.Duration = duration
+	}
+
+	reachedEnd := progress.Duration > 0 && position >= progress.Duration*CompletionThreshold
+	justCompleted := (completed || reachedEnd) && !progress.Completed
+	if justCompleted {
+		progress.Completed = true
+		progress.CompletedAt = &now
+	}
+
+	if err := s.repo.Save(ctx, progress); err != nil {
+		return nil, fmt.Errorf("saving progress: %w", err)
+	}
+
+	s.recordListening(ctx, progress, listened, justCompleted, now)
+
+	return progress, nil
+}
+
+// recordListening updates history and daily aggregates. Failures are logged
+// rather than returned since the progress itself has already been saved.
+func (s *service) recordListening(ctx context.Context, progress *models.PlaybackProgress, listened float64, justCompleted bool, now time.Time) {
+	if listened <= 0 && !justCompleted {
+		return
+	}
+
+	completedCount := 0
+	if justCompleted {
+		completedCount = 1
+
+		feedID, err := s.repo.FeedIDForEpisode(ctx, progress.PodcastIndexEpisodeID)
+		if err != nil {
+			log.Printf("[WARN] Failed to resolve feed for episode %d: %v", progress.PodcastIndexEpisodeID, err)
+		}
+
+		if err := s.repo.AddHistory(ctx, &models.ListeningHistory{
+			UserID:                progress.UserID,
+			PodcastIndexEpisodeID: progress.PodcastIndexEpisodeID,
+			PodcastIndexFeedID:    feedID,
+			Duration:              progress.Duration,
+			CompletedAt:           now,
+		}); err != nil {
+			log.Printf("[WARN] Failed to record history for user %s episode %d: %v", progress.UserID, progress.PodcastIndexEpisodeID, err)
+		}
+	}
+
+	if err := s.repo.AddListening(ctx, progress.UserID, now, listened, completedCount); err != nil {
+		log.Printf("[WARN] Failed to update daily listening for user %s: %v", progress.UserID, err)
+	}
+}
+
+// ImportProgress records a position from another app unless the user's own
+// progress is newer. Returns whether anything was recorded.
+func (s *service) ImportProgress(ctx context.Context, userID string, podcastIndexEpisodeID int64, position, duration float64, completed bool, at time.Time) (*models.PlaybackProgress, bool, error) {
+	if position < 0 || duration < 0 {
+		return nil, false, ErrInvalidPosition
+	}
+
+	progress, err := s.repo.Get(ctx, userID, podcastIndexEpisodeID)
+	if err != nil && !errors.Is(err, ErrProgressNotFound) {
+		return nil, false, fmt.Errorf("loading progress: %w", err)
+	}
+	dated := !at.IsZero()
+	if !dated || at.After(s.now()) {
+		at = s.now()
+	}
+	if progress == nil {
+		progress = &models.PlaybackProgress{
+			UserID:                userID,
+			PodcastIndexEpisodeID: podcastIndexEpisodeID,
+		}
+	} else if !dated || !progress.ReportedAt.Before(at) || (progress.Completed && !completed) {
+		return progress, false, nil // Undated imports only fill in episodes without progress
+	}
+
+	progress.Position = position
+	progress.ReportedAt = at
+	if duration > 0 {
+		progress.Duration = duration
+	}
+	reachedEnd := progress.Duration > 0 && position >= progress.Duration*CompletionThreshold
+	justCompleted := (completed || reachedEnd) && !progress.Completed
+	if justCompleted {
+		progress.Completed = true
+		progress.CompletedAt = &at
+	}
+
+	if err := s.repo.Save(ctx, progress); err != nil {
+		return nil, false, fmt.Errorf("saving progress: %w", err)
+	}
+
+	if justCompleted && dated {
+		s.recordListening(ctx, progress, 0, true, at)
+	}
+	return progress, true, nil
+}
+
+// GetProgress retrieves the user's progress for an episode
+func (s *service) GetProgress(ctx context.Context, userID string, podcastIndexEpisodeID int64) (*models.PlaybackProgress, error) {
+	return s.repo.Get(ctx, userID, podcastIndexEpisodeID)
+}
+
+// PlayedEpisodeIDs returns the set of episodes the user has completed
+func (s *service) PlayedEpisodeIDs(ctx context.Context, userID string) (map[int64]struct{}, error) {
+	ids, err := s.repo.ListCompletedEpisodeIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing completed episodes: %w", err)
+	}
+
+	played := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		played[id] = struct{}{}
+	}
+	return played, nil
+}
+
+// GetStats returns listening totals, top podcasts and streaks for the user
+func (s *service) GetStats(ctx context.Context, userID string) (*Stats, error) {
+	days, err := s.repo.ListDaily(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("loading daily listening: %w", err)
+	}
+
+	top, err := s.repo.TopPodcasts(ctx, userID, topPodcastsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("loading top podcasts: %w", err)
+	}
+	if top == nil {
+		top = []PodcastTotal{}
+	}
+
+	skips, skippedSeconds, err := s.repo.SumAdSkips(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("loading ad skips: %w", err)
+	}
+
+	stats := &Stats{TopPodcasts: top, AdsSkipped: skips, AdSecondsSkipped: skippedSeconds}
+
+	var active []string
+	for _, day := range days {
+		stats.SecondsListened += day.SecondsListened
+		stats.EpisodesCompleted += day.EpisodesCompleted
+		if day.SecondsListened > 0 || day.EpisodesCompleted > 0 {
+			active = append(active, day.Day)
+		}
+	}
+	stats.HoursListened = math.Round(stats.SecondsListened/36) / 100
+
+	if len(active) > 0 {
+		stats.LastListenedDay = active[len(active)-1]
+		stats.CurrentStreakDays, stats.LongestStreakDays = streaks(active, s.now().UTC())
+	}
+
+	return stats, nil
+}
+
+// streaks computes the current and longest runs of consecutive active days.
+// activeDays must be sorted ascending in YYYY-MM-DD form. The current streak
+// survives until the end of the day after the last active day.
+func streaks(activeDays []string, today time.Time) (current, longest int) {
+	var prev time.Time
+	run := 0
+	for _, value := range activeDays {
+		day, err := time.Parse(dayLayout, value)
+		if err != nil {
+			continue
+		}
+		if run > 0 && day.Sub(prev) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		prev = day
+	}
+
+	todayDate, _ := time.Parse(dayLayout, today.Format(dayLayout))
+	if gap := todayDate.Sub(prev); gap <= 24*time.Hour {
+		current = run
+	}
+	return current, longest
+}
@@ -0,0 +1,187 @@
+package playback
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.Podcast{},
+		&models.Episode{},
+		&models.PlaybackProgress{},
+		&models.ListeningHistory{},
+		&models.DailyListening{},
+		&models.PlaybackEvent{},
+	))
+	return db
+}
+
+func setupTestService(t *testing.T) Service {
+	return NewService(NewRepository(setupTestDB(t)))
+}
+
+// fakeClock lets tests control the time between progress reports
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func (f *fakeClock) Advance(d time.Duration) { f.now = f.now.Add(d) }
+
+func TestUpdateProgress_CompletesAtThreshold(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	progress, err := svc.UpdateProgress(ctx, "user-1", 100, 60, 1000, false)
+	require.NoError(t, err)
+	assert.False(t, progress.Completed)
+
+	progress, err = svc.UpdateProgress(ctx, "user-1", 100, 960, 0, false)
+	require.NoError(t, err)
+	assert.True(t, progress.Completed)
+	assert.NotNil(t, progress.CompletedAt)
+	assert.Equal(t, float64(1000), progress.Duration)
+
+	// Scrubbing back keeps the episode completed
+	progress, err = svc.UpdateProgress(ctx, "user-1", 100, 10, 0, false)
+	require.NoError(t, err)
+	assert.True(t, progress.Completed)
+	assert.Equal(t, float64(10), progress.Position)
+}
+
+func TestPlayedEpisodeIDs_ScopedToUser(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.UpdateProgress(ctx, "user-1", 1, 0, 0, true)
+	require.NoError(t, err)
+	_, err = svc.UpdateProgress(ctx, "user-1", 2, 30, 1000, false)
+	require.NoError(t, err)
+	_, err = svc.UpdateProgress(ctx, "user-2", 3, 0, 0, true)
+	require.NoError(t, err)
+
+	played, err := svc.PlayedEpisodeIDs(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Len(t, played, 1)
+	assert.Contains(t, played, int64(1))
+
+	_, err = svc.GetProgress(ctx, "user-2", 1)
+	assert.True(t, errors.Is(err, ErrProgressNotFound))
+}
+
+func TestUpdateProgress_RejectsNegativePosition(t *testing.T) {
+	svc := setupTestService(t)
+
+	_, err := svc.UpdateProgress(context.Background(), "user-1", 1, -1, 0, false)
+	assert.True(t, errors.Is(err, ErrInvalidPosition))
+}
+
+func TestGetStats_CountsPlausibleListeningOnly(t *testing.T) {
+	db := setupTestDB(t)
+	clock := &fakeClock{now: time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)}
+	svc := &service{repo: NewRepository(db), now: clock.Now}
+	ctx := context.Background()
+
+	require.NoError(t, db.Create(&models.Podcast{PodcastIndexID: 500, Title: "Daily Show", FeedURL: "https://example.com/feed.xml"}).Error)
+	require.NoError(t, db.Create(&models.Episode{
+		PodcastID: 1, PodcastIndexID: 1, PodcastIndexFeedID: 500,
+		Title: "Ep", GUID: "guid-1", AudioURL: "https://example.com/1.mp3",
+	}).Error)
+
+	_, err := svc.UpdateProgress(ctx, "user-1", 1, 0, 1800, false)
+	require.NoError(t, err)
+
+	clock.Advance(10 * time.Minute)
+	_, err = svc.UpdateProgress(ctx, "user-1", 1, 600, 0, false)
+	require.NoError(t, err)
+
+	// Jumping far ahead in a few seconds is a seek, not listening, but still completes
+	clock.Advance(2 * time.Second)
+	_, err = svc.UpdateProgress(ctx, "user-1", 1, 1790, 0, false)
+	require.NoError(t, err)
+
+	stats, err := svc.GetStats(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, float64(600), stats.SecondsListened)
+	assert.Equal(t, 1, stats.EpisodesCompleted)
+	require.Len(t, stats.TopPodcasts, 1)
+	assert.Equal(t, int64(500), stats.TopPodcasts[0].PodcastIndexFeedID)
+	assert.Equal(t, "Daily Show", stats.TopPodcasts[0].Title)
+	assert.Equal(t, 1, stats.CurrentStreakDays)
+	assert.Zero(t, stats.AdsSkipped)
+
+	// Ad skips come from the user's playback events
+	require.NoError(t, db.Create(&[]models.PlaybackEvent{
+		{UserID: "user-1", PodcastIndexEpisodeID: 1, Type: models.PlaybackEventSkipAd, FromPosition: 300, Position: 360, Weight: 1},
+		{UserID: "user-1", PodcastIndexEpisodeID: 1, Type: models.PlaybackEventSeek, FromPosition: 400, Position: 900, Weight: 1},
+		{UserID: "user-2", PodcastIndexEpisodeID: 1, Type: models.PlaybackEventSkipAd, FromPosition: 0, Position: 30, Weight: 1},
+	}).Error)
+	stats, err = svc.GetStats(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.AdsSkipped)
+	assert.Equal(t, 60.0, stats.AdSecondsSkipped)
+}
+
+func TestStreaks(t *testing.T) {
+	today := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	current, longest := streaks([]string{"2024-03-01", "2024-03-02", "2024-03-03", "2024-03-08", "2024-03-09"}, today)
+	assert.Equal(t, 2, current)
+	assert.Equal(t, 3, longest)
+
+	current, longest = streaks([]string{"2024-03-01", "2024-03-02"}, today)
+	assert.Equal(t, 0, current)
+	assert.Equal(t, 2, longest)
+}
+
+func TestImportProgress_KeepsNewerProgress(t *testing.T) {
+	db := setupTestDB(t)
+	clock := &fakeClock{now: time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)}
+	svc := &service{repo: NewRepository(db), now: clock.Now}
+	ctx := context.Background()
+
+	// Reported here yesterday; an import from two days ago doesn't replace it
+	_, err := svc.UpdateProgress(ctx, "user-1", 1, 300, 1800, false)
+	require.NoError(t, err)
+	_, imported, err := svc.ImportProgress(ctx, "user-1", 1, 900, 1800, false, clock.now.Add(-48*time.Hour))
+	require.NoError(t, err)
+	assert.False(t, imported)
+
+	// A later import does, and a completion is dated when it happened
+	playedAt := clock.now.Add(time.Hour)
+	clock.Advance(24 * time.Hour)
+	progress, imported, err := svc.ImportProgress(ctx, "user-1", 1, 1800, 1800, true, playedAt)
+	require.NoError(t, err)
+	assert.True(t, imported)
+	assert.True(t, progress.Completed)
+	assert.Equal(t, playedAt, progress.CompletedAt.UTC())
+
+	stats, err := svc.GetStats(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.EpisodesCompleted)
+	assert.Equal(t, "2024-03-10", stats.LastListenedDay)
+
+	// Without a time, only episodes with no progress are filled in
+	_, imported, err = svc.ImportProgress(ctx, "user-1", 1, 10, 0, false, time.Time{})
+	require.NoError(t, err)
+	assert.False(t, imported)
+	progress, imported, err = svc.ImportProgress(ctx, "user-1", 2, 120, 0, false, time.Time{})
+	require.NoError(t, err)
+	assert.True(t, imported)
+	assert.Equal(t, float64(120), progress.Position)
+}
@@ -3,14 +3,20 @@ package podcastindex
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"golang.org/x/sync/singleflight"
 )
 
+// ErrPodcastNotFound is returned when Podcast Index doesn't know a feed ID
+var ErrPodcastNotFound = errors.New("podcast not found")
+
 // Client handles communication with the Podcast Index API
 type Client struct {
 	httpClient *http.Client
@@ -18,6 +24,8 @@ type Client struct {
 	apiKey     string
 	apiSecret  string
 	userAgent  string
+	usage      UsageTracker
+	inflight   singleflight.Group // Identical calls in flight, by path and query
 }
 
 // Config holds configuration for the Podcast Index client
@@ -27,12 +35,21 @@ type Config struct {
 	BaseURL   string
 	UserAgent string
 	Timeout   time.Duration
+	Usage     UsageTracker // Optional; meters calls and may refuse them
 }
 
 // NewClient creates a new Podcast Index API client
 func NewClient(cfg Config) *Client {
 	httpClient := &http.Client{
 		Timeout: cfg.Timeout,
+		// Client spans for each API call. Trace headers stay in-house: the
+		// empty propagator keeps them off requests to Podcast Index.
+		Transport: otelhttp.NewTransport(http.DefaultTransport,
+			otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return "podcastindex " + strings.TrimPrefix(r.URL.Path, "/api/1.0")
+			}),
+		),
 	}
 
 	if cfg.BaseURL == "" {
@@ -49,63 +66,19 @@ func NewClient(cfg Config) *Client {
 		apiKey:     cfg.APIKey,
 		apiSecret:  cfg.APISecret,
 		userAgent:  cfg.UserAgent,
+		usage:      cfg.Usage,
 	}
 }
 
-// makeAPIRequest is a helper method to reduce code duplication for API requests
-func (c *Client) makeAPIRequest(ctx context.Context, endpoint string, result interface{}) error {
-	fullURL := fmt.Sprintf("%s/%s", c.baseURL, endpoint)
-
-	// Create a clean context that inherits deadlines but not values/metadata
-	// This prevents auth middleware headers from propagating to external API calls
-	cleanCtx := context.Background()
-	if deadline, ok := ctx.Deadline(); ok {
-		var cancel context.CancelFunc
-		cleanCtx, cancel = context.WithDeadline(cleanCtx, deadline)
-		defer cancel()
-	}
-
-	req, err := http.NewRequestWithContext(cleanCtx, "GET", fullURL, nil)
-	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
-	}
-
-	signRequest(req, c.apiKey, c.apiSecret, c.userAgent)
-
-	// Debug log the headers being sent (only Authorization for security)
-	if authHeader := req.Header.Get("Authorization"); authHeader != "" {
-		// Only log first 10 chars of auth header for security
-		if len(authHeader) > 10 {
-			log.Printf("[DEBUG] Sending Authorization header to Podcast Index: %.10s...", authHeader)
-		}
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[ERROR] Podcast Index API returned status %d for %s", resp.StatusCode, fullURL)
-		return fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-		return fmt.Errorf("decoding response: %w", err)
+// clampLimit applies an endpoint's default and maximum page size
+func clampLimit(limit, fallback, max int) int64 {
+	if limit <= 0 {
+		return int64(fallback)
 	}
-
-	// Check for API error status if the result has Status field
-	if statusObj, ok := result.(interface{ GetStatus() string }); ok {
-		if statusObj.GetStatus() != "true" {
-			if descObj, ok := result.(interface{ GetDescription() string }); ok {
-				return fmt.Errorf("API error: %s", descObj.GetDescription())
-			}
-			return fmt.Errorf("API returned error status")
-		}
+	if limit > max {
+		return int64(max)
 	}
-
-	return nil
+	return int64(limit)
 }
 
 // Search searches for podcasts by term
@@ -114,280 +87,70 @@ func (c *Client) Search(ctx context.Context, query string, limit int, fullText b
 		return nil, fmt.Errorf("search query cannot be empty")
 	}
 
-	// Default and max limit
-	if limit <= 0 {
-		limit = 25
-	}
-	if limit > 100 {
-		limit = 100
-	}
-
-	// Build URL with query parameters
-	params := url.Values{}
-	params.Set("q", query)
-	params.Set("max", fmt.Sprintf("%d", limit))
-	if fullText {
-		params.Set("fulltext", "true")
-	}
-	if val != "" {
-		params.Set("val", val)
-	}
-	if apOnly {
-		params.Set("aponly", "true")
-	}
-	if clean {
-		params.Set("clean", "true")
-	}
-
-	endpoint := fmt.Sprintf("%s/search/byterm?%s", c.baseURL, params.Encode())
-
-	// Create a clean context that inherits deadlines but not values/metadata
-	// This prevents auth middleware headers from propagating to external API calls
-	cleanCtx := context.Background()
-	if deadline, ok := ctx.Deadline(); ok {
-		var cancel context.CancelFunc
-		cleanCtx, cancel = context.WithDeadline(cleanCtx, deadline)
-		defer cancel()
-	}
-
-	// Create request with clean context
-	req, err := http.NewRequestWithContext(cleanCtx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	// Sign the request
-	signRequest(req, c.apiKey, c.apiSecret, c.userAgent)
-
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		// Log headers for debugging
-		fmt.Printf("Request headers: %v\n", req.Header)
-		fmt.Printf("Response status: %d\n", resp.StatusCode)
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
-
-	// Decode response
 	var searchResp SearchResponse
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&searchResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	if len(searchResp.Feeds) > 0 {
-		fmt.Printf("DEBUG: Found %d podcasts\n", len(searchResp.Feeds))
-	}
-
-	// Check API status
-	if searchResp.Status != "true" {
-		return nil, fmt.Errorf("API returned error status: %s", searchResp.Description)
+	err := c.get(ctx, "search/byterm", &searchResp,
+		withParam("q", query),
+		withInt("max", clampLimit(limit, 25, 100)),
+		withFlag("fulltext", fullText),
+		withParam("val", val),
+		withFlag("aponly", apOnly),
+		withFlag("clean", clean),
+	)
+	if err != nil {
+		return nil, err
 	}
-
 	return &searchResp, nil
 }
 
 // GetTrending fetches trending podcasts from Podcast Index with optional filters
-func (c *Client) GetTrending(ctx context.Context, max, since int, categories []string, lang string, fullText bool) (*SearchResponse, error) {
-	// Default and max limit
-	if max <= 0 {
-		max = 10
-	}
-	if max > 100 {
-		max = 100
-	}
-
-	// Build URL with query parameters
-	params := url.Values{}
-	params.Set("max", fmt.Sprintf("%d", max))
-
-	if since > 0 {
-		params.Set("since", fmt.Sprintf("%d", since))
-	}
-
-	if len(categories) > 0 {
-		// Join categories with comma for the API
-		params.Set("cat", strings.Join(categories, ","))
-	}
-
-	if lang != "" {
-		params.Set("lang", lang)
-	} else {
-		params.Set("lang", "en") // Default to English
-	}
-
-	if fullText {
-		params.Set("fulltext", "true")
-	}
-
-	endpoint := fmt.Sprintf("%s/podcasts/trending?%s", c.baseURL, params.Encode())
-
-	// Create a clean context that inherits deadlines but not values/metadata
-	// This prevents auth middleware headers from propagating to external API calls
-	cleanCtx := context.Background()
-	if deadline, ok := ctx.Deadline(); ok {
-		var cancel context.CancelFunc
-		cleanCtx, cancel = context.WithDeadline(cleanCtx, deadline)
-		defer cancel()
-	}
-
-	// Create request with clean context
-	req, err := http.NewRequestWithContext(cleanCtx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	// Sign the request
-	signRequest(req, c.apiKey, c.apiSecret, c.userAgent)
-
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+func (c *Client) GetTrending(ctx context.Context, max, since int, categories, notCategories []string, lang string, fullText bool) (*SearchResponse, error) {
+	if lang == "" {
+		lang = "en"
 	}
 
-	// Decode response
 	var trendingResp SearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&trendingResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	// Check API status
-	if trendingResp.Status != "true" {
-		return nil, fmt.Errorf("API returned error status: %s", trendingResp.Description)
+	err := c.get(ctx, "podcasts/trending", &trendingResp,
+		withInt("max", clampLimit(max, 10, 100)),
+		withInt("since", int64(since)),
+		withList("cat", categories),
+		withList("notcat", notCategories),
+		withParam("lang", lang),
+		withFlag("fulltext", fullText),
+	)
+	if err != nil {
+		return nil, err
 	}
-
 	return &trendingResp, nil
 }
 
 // GetCategories retrieves all supported podcast categories
-func (c *Client) GetCategories() (*CategoriesResponse, error) {
-	// Build URL
-	endpoint := fmt.Sprintf("%s/categories/list", c.baseURL)
-
-	// Create request
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	// Sign the request
-	signRequest(req, c.apiKey, c.apiSecret, c.userAgent)
-
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
-
-	// Decode response
+func (c *Client) GetCategories(ctx context.Context) (*CategoriesResponse, error) {
 	var categoriesResp CategoriesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&categoriesResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	// Check API status
-	if categoriesResp.Status != "true" {
-		return nil, fmt.Errorf("API returned error status: %s", categoriesResp.Description)
+	if err := c.get(ctx, "categories/list", &categoriesResp); err != nil {
+		return nil, err
 	}
-
 	return &categoriesResp, nil
 }
 
 // GetEpisodesByPodcastID fetches episodes for a specific podcast
 func (c *Client) GetEpisodesByPodcastID(ctx context.Context, podcastID int64, limit int) (*EpisodesResponse, error) {
-	// Build URL with query parameters
-	params := url.Values{}
-	params.Set("id", fmt.Sprintf("%d", podcastID)) // API expects "id" not "feedId"
-	if limit > 0 {
-		params.Set("max", fmt.Sprintf("%d", limit))
-	}
-
-	endpoint := fmt.Sprintf("episodes/byfeedid?%s", params.Encode())
-
-	// Debug log the endpoint
-	log.Printf("[DEBUG] Calling Podcast Index API: %s/%s", c.baseURL, endpoint)
-
 	var episodesResp EpisodesResponse
-	if err := c.makeAPIRequest(ctx, endpoint, &episodesResp); err != nil {
+	err := c.get(ctx, "episodes/byfeedid", &episodesResp,
+		withInt("id", podcastID), // The API expects "id", not "feedId"
+		withInt("max", int64(limit)),
+	)
+	if err != nil {
 		return nil, err
 	}
-
-	// Check API status
-	if episodesResp.Status != "true" {
-		return nil, fmt.Errorf("API error: %s", episodesResp.Description)
-	}
-
 	return &episodesResp, nil
 }
 
 // GetEpisodeByGUID fetches a single episode by GUID
 func (c *Client) GetEpisodeByGUID(ctx context.Context, guid string) (*EpisodeByGUIDResponse, error) {
-	// Build URL with query parameters
-	params := url.Values{}
-	params.Set("guid", guid)
-
-	endpoint := fmt.Sprintf("%s/episodes/byguid?%s", c.baseURL, params.Encode())
-
-	// Create a clean context that inherits deadlines but not values/metadata
-	// This prevents auth middleware headers from propagating to external API calls
-	cleanCtx := context.Background()
-	if deadline, ok := ctx.Deadline(); ok {
-		var cancel context.CancelFunc
-		cleanCtx, cancel = context.WithDeadline(cleanCtx, deadline)
-		defer cancel()
-	}
-
-	// Create request with clean context
-	req, err := http.NewRequestWithContext(cleanCtx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	// Sign the request
-	signRequest(req, c.apiKey, c.apiSecret, c.userAgent)
-
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
-
-	// Decode response
 	var episodeResp EpisodeByGUIDResponse
-	if err := json.NewDecoder(resp.Body).Decode(&episodeResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	// Check API status
-	if episodeResp.Status != "true" {
-		return nil, fmt.Errorf("API error: %s", episodeResp.Description)
+	if err := c.get(ctx, "episodes/byguid", &episodeResp, withParam("guid", guid)); err != nil {
+		return nil, err
 	}
-
 	return &episodeResp, nil
 }
 
@@ -397,167 +160,78 @@ func (c *Client) GetEpisodesByFeedURL(ctx context.Context, feedURL string, limit
 		return nil, fmt.Errorf("feed URL cannot be empty")
 	}
 
-	params := url.Values{}
-	params.Set("url", feedURL)
-	if limit > 0 {
-		params.Set("max", fmt.Sprintf("%d", limit))
-	}
-
-	endpoint := fmt.Sprintf("episodes/byfeedurl?%s", params.Encode())
-
 	var episodesResp EpisodesResponse
-	if err := c.makeAPIRequest(ctx, endpoint, &episodesResp); err != nil {
+	err := c.get(ctx, "episodes/byfeedurl", &episodesResp,
+		withParam("url", feedURL),
+		withInt("max", int64(limit)),
+	)
+	if err != nil {
 		return nil, err
 	}
-
-	if episodesResp.Status != "true" {
-		return nil, fmt.Errorf("API error: %s", episodesResp.Description)
-	}
-
 	return &episodesResp, nil
 }
 
 // GetEpisodesByiTunesID fetches episodes for a podcast by iTunes ID
 func (c *Client) GetEpisodesByiTunesID(ctx context.Context, itunesID int64, limit int) (*EpisodesResponse, error) {
-	params := url.Values{}
-	params.Set("id", fmt.Sprintf("%d", itunesID))
-	if limit > 0 {
-		params.Set("max", fmt.Sprintf("%d", limit))
-	}
-
-	endpoint := fmt.Sprintf("episodes/byitunesid?%s", params.Encode())
-
 	var episodesResp EpisodesResponse
-	if err := c.makeAPIRequest(ctx, endpoint, &episodesResp); err != nil {
+	err := c.get(ctx, "episodes/byitunesid", &episodesResp,
+		withInt("id", itunesID),
+		withInt("max", int64(limit)),
+	)
+	if err != nil {
 		return nil, err
 	}
-
-	if episodesResp.Status != "true" {
-		return nil, fmt.Errorf("API error: %s", episodesResp.Description)
-	}
-
 	return &episodesResp, nil
 }
 
 // GetRecentEpisodes fetches the most recent episodes globally
 func (c *Client) GetRecentEpisodes(ctx context.Context, limit int) (*EpisodesResponse, error) {
-	if limit <= 0 {
-		limit = 25
-	}
-	if limit > 100 {
-		limit = 100
-	}
-
-	params := url.Values{}
-	params.Set("max", fmt.Sprintf("%d", limit))
-
-	endpoint := fmt.Sprintf("recent/episodes?%s", params.Encode())
-
 	var episodesResp EpisodesResponse
-	if err := c.makeAPIRequest(ctx, endpoint, &episodesResp); err != nil {
+	if err := c.get(ctx, "recent/episodes", &episodesResp, withInt("max", clampLimit(limit, 25, 100))); err != nil {
 		return nil, err
 	}
-
-	if episodesResp.Status != "true" {
-		return nil, fmt.Errorf("API error: %s", episodesResp.Description)
-	}
-
 	return &episodesResp, nil
 }
 
 // GetRandomEpisodes fetches random podcast episodes from the Podcast Index API
-func (c *Client) GetRandomEpisodes(ctx context.Context, max int, lang string, notCategories []string) (*EpisodesResponse, error) {
-	if max <= 0 {
-		max = 10
-	}
-	if max > 100 {
-		max = 100
-	}
-
+func (c *Client) GetRandomEpisodes(ctx context.Context, max int, lang string, categories, notCategories []string) (*EpisodesResponse, error) {
 	if lang == "" {
 		lang = "en"
 	}
 
-	params := url.Values{}
-	params.Set("max", fmt.Sprintf("%d", max))
-	params.Set("lang", lang)
-
-	if len(notCategories) > 0 {
-		params.Set("notcat", strings.Join(notCategories, ","))
-	}
-
-	endpoint := fmt.Sprintf("%s/episodes/random?%s", c.baseURL, params.Encode())
-
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	signRequest(req, c.apiKey, c.apiSecret, c.userAgent)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
-
-	// The random endpoint returns "episodes" instead of "items" like other endpoints
-	// We need to handle this inconsistency
+	// The random endpoint lists "episodes" where the others list "items"
 	var randomResp struct {
 		Status      string    `json:"status"`
-		Episodes    []Episode `json:"episodes"` // Note: different field name
+		Episodes    []Episode `json:"episodes"`
 		Count       int       `json:"count"`
 		Max         string    `json:"max"`
 		Description string    `json:"description"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&randomResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	if randomResp.Status != "true" {
-		return nil, fmt.Errorf("API error: %s", randomResp.Description)
+	err := c.get(ctx, "episodes/random", &randomResp,
+		withInt("max", clampLimit(max, 10, 100)),
+		withParam("lang", lang),
+		withList("cat", categories),
+		withList("notcat", notCategories),
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	// Map to our standard response structure
-	episodesResp := &EpisodesResponse{
+	return &EpisodesResponse{
 		Status:      randomResp.Status,
-		Items:       randomResp.Episodes, // Map episodes to items
+		Items:       randomResp.Episodes,
 		Count:       randomResp.Count,
 		Max:         randomResp.Max,
 		Description: randomResp.Description,
-	}
-
-	return episodesResp, nil
+	}, nil
 }
 
 // GetRecentFeeds fetches the most recently updated feeds
 func (c *Client) GetRecentFeeds(ctx context.Context, limit int) (*RecentFeedsResponse, error) {
-	if limit <= 0 {
-		limit = 25
-	}
-	if limit > 100 {
-		limit = 100
-	}
-
-	params := url.Values{}
-	params.Set("max", fmt.Sprintf("%d", limit))
-
-	endpoint := fmt.Sprintf("recent/feeds?%s", params.Encode())
-
 	var feedsResp RecentFeedsResponse
-	if err := c.makeAPIRequest(ctx, endpoint, &feedsResp); err != nil {
+	if err := c.get(ctx, "recent/feeds", &feedsResp, withInt("max", clampLimit(limit, 25, 100))); err != nil {
 		return nil, err
 	}
-
-	if feedsResp.Status != "true" {
-		return nil, fmt.Errorf("API error: %s", feedsResp.Description)
-	}
-
 	return &feedsResp, nil
 }
 
@@ -567,50 +241,24 @@ func (c *Client) GetEpisodeByID(ctx context.Context, episodeID int64) (*Episode,
 		return nil, fmt.Errorf("invalid episode ID: %d", episodeID)
 	}
 
-	params := url.Values{}
-	params.Set("id", fmt.Sprintf("%d", episodeID))
-
-	endpoint := fmt.Sprintf("episodes/byid?%s", params.Encode())
-
-	// Create a struct matching the API response structure for a single episode
 	var episodeResp struct {
 		Status      string  `json:"status"`
 		ID          int64   `json:"id"`
 		Episode     Episode `json:"episode"`
 		Description string  `json:"description"`
 	}
-
-	if err := c.makeAPIRequest(ctx, endpoint, &episodeResp); err != nil {
-		// Check if it's a 404 error
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
+	if err := c.get(ctx, "episodes/byid", &episodeResp, withInt("id", episodeID)); err != nil {
+		if isNotFound(err) {
 			return nil, fmt.Errorf("episode not found: ID %d", episodeID)
 		}
 		return nil, fmt.Errorf("fetching episode by ID: %w", err)
 	}
 
-	if episodeResp.Status != "true" {
-		// Handle case where episode doesn't exist
-		if strings.Contains(strings.ToLower(episodeResp.Description), "not found") ||
-			strings.Contains(strings.ToLower(episodeResp.Description), "no episode") {
-			return nil, fmt.Errorf("episode not found: ID %d", episodeID)
-		}
-		return nil, fmt.Errorf("API error: %s", episodeResp.Description)
-	}
-
-	// Some responses might have the episode data at the top level or nested
-	if episodeResp.Episode.ID != 0 {
-		return &episodeResp.Episode, nil
+	// Unknown episodes come back as success with an empty episode
+	if episodeResp.Episode.ID == 0 {
+		return nil, fmt.Errorf("episode not found: ID %d", episodeID)
 	}
-
-	// If the data is at the top level, construct the episode
-	if episodeResp.ID != 0 {
-		episode := Episode{ID: episodeResp.ID}
-		// The API might return the full episode data at the top level
-		// For now, return what we have
-		return &episode, nil
-	}
-
-	return nil, fmt.Errorf("unexpected response structure for episode ID %d", episodeID)
+	return &episodeResp.Episode, nil
 }
 
 // GetPodcastByID fetches a single podcast by its Podcast Index ID
@@ -618,26 +266,44 @@ func (c *Client) GetPodcastByID(ctx context.Context, podcastID int64) (*PodcastB
 	if podcastID <= 0 {
 		return nil, fmt.Errorf("invalid podcast ID: %d", podcastID)
 	}
+	return c.lookupPodcast(ctx, "podcasts/byfeedid", fmt.Sprintf("ID %d", podcastID), withInt("id", podcastID))
+}
 
-	params := url.Values{}
-	params.Set("id", fmt.Sprintf("%d", podcastID))
-
-	endpoint := fmt.Sprintf("podcasts/byfeedid?%s", params.Encode())
+// GetPodcastByFeedURL fetches a single podcast by its feed URL
+func (c *Client) GetPodcastByFeedURL(ctx context.Context, feedURL string) (*PodcastByIDResponse, error) {
+	if feedURL == "" {
+		return nil, fmt.Errorf("feed URL cannot be empty")
+	}
+	return c.lookupPodcast(ctx, "podcasts/byfeedurl", "feed URL "+feedURL, withParam("url", feedURL))
+}
 
-	var podcastResp PodcastByIDResponse
-	if err := c.makeAPIRequest(ctx, endpoint, &podcastResp); err != nil {
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
-			return nil, fmt.Errorf("podcast not found: ID %d", podcastID)
-		}
-		return nil, fmt.Errorf("fetching podcast by ID: %w", err)
+// GetPodcastByITunesID fetches a single podcast by its Apple Podcasts (iTunes) ID
+func (c *Client) GetPodcastByITunesID(ctx context.Context, itunesID int64) (*PodcastByIDResponse, error) {
+	if itunesID <= 0 {
+		return nil, fmt.Errorf("invalid iTunes ID: %d", itunesID)
 	}
+	return c.lookupPodcast(ctx, "podcasts/byitunesid", fmt.Sprintf("iTunes ID %d", itunesID), withInt("id", itunesID))
+}
 
-	if podcastResp.Status != "true" {
-		if strings.Contains(strings.ToLower(podcastResp.Description), "not found") {
-			return nil, fmt.Errorf("podcast not found: ID %d", podcastID)
+// lookupPodcast fetches a podcasts/by* endpoint. Unknown feeds come back as
+// success with an empty "feed" array rather than an object, so the feed is
+// decoded separately and reported as ErrPodcastNotFound when missing.
+func (c *Client) lookupPodcast(ctx context.Context, path, lookup string, opts ...requestOption) (*PodcastByIDResponse, error) {
+	var raw struct {
+		Status      string          `json:"status"`
+		Feed        json.RawMessage `json:"feed"`
+		Description string          `json:"description"`
+	}
+	if err := c.get(ctx, path, &raw, opts...); err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrPodcastNotFound, lookup)
 		}
-		return nil, fmt.Errorf("API error: %s", podcastResp.Description)
+		return nil, fmt.Errorf("fetching podcast by %s: %w", lookup, err)
 	}
 
+	podcastResp := PodcastByIDResponse{Status: raw.Status, Description: raw.Description}
+	if err := json.Unmarshal(raw.Feed, &podcastResp.Feed); err != nil || podcastResp.Feed.ID == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPodcastNotFound, lookup)
+	}
 	return &podcastResp, nil
 }
@@ -2,6 +2,7 @@ package podcastindex
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -139,3 +140,34 @@ func TestSearchEmptyQuery(t *testing.T) {
 		t.Error("Expected error for empty query, got nil")
 	}
 }
+
+func TestGetPodcastByFeedURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1.0/podcasts/byfeedurl" {
+			t.Errorf("Expected path /api/1.0/podcasts/byfeedurl, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("url") == "https://example.com/feed.xml" {
+			_, _ = w.Write([]byte(`{"status": "true", "feed": {"id": 920666, "title": "Test Podcast"}, "description": "Found matching feed"}`))
+			return
+		}
+		// Unknown feeds are a success with an empty array
+		_, _ = w.Write([]byte(`{"status": "true", "feed": [], "description": "No feeds match this url."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{APIKey: "test-key", APISecret: "test-secret", BaseURL: server.URL + "/api/1.0"})
+
+	resp, err := client.GetPodcastByFeedURL(context.Background(), "https://example.com/feed.xml")
+	if err != nil {
+		t.Fatalf("GetPodcastByFeedURL failed: %v", err)
+	}
+	if resp.Feed.ID != 920666 {
+		t.Errorf("Expected feed ID 920666, got %d", resp.Feed.ID)
+	}
+
+	_, err = client.GetPodcastByFeedURL(context.Background(), "https://example.com/unknown.xml")
+	if !errors.Is(err, ErrPodcastNotFound) {
+		t.Errorf("Expected ErrPodcastNotFound, got %v", err)
+	}
+}
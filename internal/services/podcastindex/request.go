@@ -0,0 +1,226 @@
+package podcastindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxResponseBytes bounds a response body; a full page of episodes is well
+// under this
+const maxResponseBytes = 32 << 20
+
+// APIError is a failed Podcast Index call: a non-200 status, or a 200 whose
+// body reports "status": "false"
+type APIError struct {
+	Path        string
+	StatusCode  int    // HTTP status
+	Description string // Podcast Index's description of the failure, if any
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != http.StatusOK {
+		return fmt.Sprintf("API returned status %d for %s", e.StatusCode, e.Path)
+	}
+	if e.Description == "" {
+		return fmt.Sprintf("API returned error status for %s", e.Path)
+	}
+	return fmt.Sprintf("API error for %s: %s", e.Path, e.Description)
+}
+
+// NotFound reports whether Podcast Index said the thing asked for doesn't exist
+func (e *APIError) NotFound() bool {
+	description := strings.ToLower(e.Description)
+	return e.StatusCode == http.StatusNotFound ||
+		strings.Contains(description, "not found") || strings.Contains(description, "no episode")
+}
+
+// isNotFound reports whether err is an APIError for something that doesn't exist
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.NotFound()
+}
+
+// UsageTracker meters calls to the API. Allow is asked before each call and
+// can refuse it, for instance once a daily budget is spent; Record is told
+// about every call that was made.
+type UsageTracker interface {
+	Allow(ctx context.Context, endpoint string) error
+	Record(ctx context.Context, endpoint string, latency time.Duration, callErr error)
+}
+
+// apiRequest is one call to the API, built up by requestOptions
+type apiRequest struct {
+	path   string
+	params url.Values
+}
+
+// requestOption sets part of a request
+type requestOption func(*apiRequest)
+
+// withParam sets a query parameter; empty values are left out
+func withParam(key, value string) requestOption {
+	return func(r *apiRequest) {
+		if value != "" {
+			r.params.Set(key, value)
+		}
+	}
+}
+
+// withInt sets an integer query parameter; values <= 0 are left out
+func withInt(key string, value int64) requestOption {
+	return func(r *apiRequest) {
+		if value > 0 {
+			r.params.Set(key, strconv.FormatInt(value, 10))
+		}
+	}
+}
+
+// withFlag sets a boolean query parameter when on
+func withFlag(key string, on bool) requestOption {
+	return func(r *apiRequest) {
+		if on {
+			r.params.Set(key, "true")
+		}
+	}
+}
+
+// withList sets a comma-separated query parameter; empty lists are left out
+func withList(key string, values []string) requestOption {
+	return withParam(key, strings.Join(values, ","))
+}
+
+// get calls the endpoint at path and decodes the response into result, which
+// may be any struct matching the endpoint's shape. Every call goes through here
+// so they all get the same context handling, signing and error checks.
+//
+// Identical calls already in flight share one upstream request, so a burst of
+// users searching for the same thing costs one call; each caller decodes the
+// shared body into its own result. The shared request belongs to no one caller:
+// it is bounded only by the client timeout, and each caller stops waiting at
+// its own deadline.
+func (c *Client) get(ctx context.Context, path string, result interface{}, opts ...requestOption) error {
+	r := &apiRequest{path: path, params: url.Values{}}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	reqCtx, cancel := cleanContext(ctx)
+	defer cancel()
+	if err := reqCtx.Err(); err != nil {
+		return fmt.Errorf("calling %s: %w", path, err)
+	}
+
+	flight := c.inflight.DoChan(path+"?"+r.params.Encode(), func() (interface{}, error) {
+		callCtx, cancel := c.sharedContext(reqCtx)
+		defer cancel()
+		return c.call(callCtx, r)
+	})
+	select {
+	case res := <-flight:
+		if res.Err != nil {
+			return res.Err
+		}
+		if err := json.Unmarshal(res.Val.([]byte), result); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+		return nil
+	case <-reqCtx.Done():
+		return fmt.Errorf("calling %s: %w", path, reqCtx.Err())
+	}
+}
+
+// call makes one upstream request, metered by the usage tracker if there is one
+func (c *Client) call(ctx context.Context, r *apiRequest) ([]byte, error) {
+	if c.usage == nil {
+		return c.do(ctx, r)
+	}
+
+	if err := c.usage.Allow(ctx, r.path); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	body, err := c.do(ctx, r)
+	c.usage.Record(ctx, r.path, time.Since(start), err)
+	return body, err
+}
+
+// do makes the request and returns the body of a successful response
+func (c *Client) do(ctx context.Context, r *apiRequest) ([]byte, error) {
+	path := r.path
+	fullURL := c.baseURL + "/" + path
+	if len(r.params) > 0 {
+		fullURL += "?" + r.params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	signRequest(req, c.apiKey, c.apiSecret, c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	// Failures usually still carry the JSON envelope with a description
+	var envelope struct {
+		Status      json.RawMessage `json:"status"`
+		Description string          `json:"description"`
+	}
+	envelopeErr := json.Unmarshal(body, &envelope)
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("[ERROR] Podcast Index API returned status %d for %s", resp.StatusCode, path)
+		return nil, &APIError{Path: path, StatusCode: resp.StatusCode, Description: envelope.Description}
+	}
+	if envelopeErr != nil {
+		return nil, fmt.Errorf("decoding response: %w", envelopeErr)
+	}
+	// The status is the string "true" on most endpoints and a boolean on a few
+	if status := string(bytes.Trim(envelope.Status, `"`)); status != "true" {
+		return nil, &APIError{Path: path, StatusCode: resp.StatusCode, Description: envelope.Description}
+	}
+	return body, nil
+}
+
+// cleanContext returns a context for an outgoing call that keeps the caller's
+// deadline and trace but none of its values, so nothing request-scoped (such
+// as the caller's auth) can leak into calls to Podcast Index. Cancellation is
+// deliberately not carried over: a result fetched for a client that went away
+// is still stored for the next one.
+func cleanContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	clean := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(clean, deadline)
+	}
+	return clean, func() {}
+}
+
+// sharedContext returns the context for a request shared by every identical
+// caller: the first caller's trace, but none of its deadline, bounded instead
+// by the client timeout
+func (c *Client) sharedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	shared := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	if c.httpClient.Timeout > 0 {
+		return context.WithTimeout(shared, c.httpClient.Timeout)
+	}
+	return shared, func() {}
+}
@@ -0,0 +1,408 @@
+package podcastindex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ctxKey string
+
+// recordingServer answers every call with body and records the last request
+func recordingServer(t *testing.T, status int, body string) (*Client, *http.Request) {
+	t.Helper()
+	last := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = *r.Clone(context.Background())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(Config{APIKey: "test-key", APISecret: "test-secret", BaseURL: server.URL + "/api/1.0", UserAgent: "TestAgent/1.0"}), last
+}
+
+func TestEndpointRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		call   func(ctx context.Context, c *Client) error
+		path   string
+		params url.Values
+	}{
+		{
+			name: "search",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.Search(ctx, "go", 500, true, "lightning", true, true)
+				return err
+			},
+			path:   "search/byterm",
+			params: url.Values{"q": {"go"}, "max": {"100"}, "fulltext": {"true"}, "val": {"lightning"}, "aponly": {"true"}, "clean": {"true"}},
+		},
+		{
+			name: "trending",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetTrending(ctx, 0, 3600, []string{"News", "Tech"}, []string{"Sports"}, "", false)
+				return err
+			},
+			path:   "podcasts/trending",
+			params: url.Values{"max": {"10"}, "since": {"3600"}, "cat": {"News,Tech"}, "notcat": {"Sports"}, "lang": {"en"}},
+		},
+		{
+			name: "categories",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetCategories(ctx)
+				return err
+			},
+			path:   "categories/list",
+			params: url.Values{},
+		},
+		{
+			name: "episodes by feed ID",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetEpisodesByPodcastID(ctx, 42, 20)
+				return err
+			},
+			path:   "episodes/byfeedid",
+			params: url.Values{"id": {"42"}, "max": {"20"}},
+		},
+		{
+			name: "episode by GUID",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetEpisodeByGUID(ctx, "abc-123")
+				return err
+			},
+			path:   "episodes/byguid",
+			params: url.Values{"guid": {"abc-123"}},
+		},
+		{
+			name: "episodes by feed URL",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetEpisodesByFeedURL(ctx, "https://example.com/feed.xml", 0)
+				return err
+			},
+			path:   "episodes/byfeedurl",
+			params: url.Values{"url": {"https://example.com/feed.xml"}},
+		},
+		{
+			name: "episodes by iTunes ID",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetEpisodesByiTunesID(ctx, 7, 5)
+				return err
+			},
+			path:   "episodes/byitunesid",
+			params: url.Values{"id": {"7"}, "max": {"5"}},
+		},
+		{
+			name: "recent episodes",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetRecentEpisodes(ctx, 0)
+				return err
+			},
+			path:   "recent/episodes",
+			params: url.Values{"max": {"25"}},
+		},
+		{
+			name: "random episodes",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetRandomEpisodes(ctx, 3, "de", nil, []string{"True Crime"})
+				return err
+			},
+			path:   "episodes/random",
+			params: url.Values{"max": {"3"}, "lang": {"de"}, "notcat": {"True Crime"}},
+		},
+		{
+			name: "recent feeds",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetRecentFeeds(ctx, 1000)
+				return err
+			},
+			path:   "recent/feeds",
+			params: url.Values{"max": {"100"}},
+		},
+		{
+			name: "episode by ID",
+			body: `{"status": "true", "id": 9, "episode": {"id": 9}}`,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetEpisodeByID(ctx, 9)
+				return err
+			},
+			path:   "episodes/byid",
+			params: url.Values{"id": {"9"}},
+		},
+		{
+			name: "podcast by ID",
+			body: `{"status": "true", "feed": {"id": 11}}`,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetPodcastByID(ctx, 11)
+				return err
+			},
+			path:   "podcasts/byfeedid",
+			params: url.Values{"id": {"11"}},
+		},
+		{
+			name: "podcast by iTunes ID",
+			body: `{"status": "true", "feed": {"id": 11}}`,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetPodcastByITunesID(ctx, 12)
+				return err
+			},
+			path:   "podcasts/byitunesid",
+			params: url.Values{"id": {"12"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.body
+			if body == "" {
+				body = `{"status": "true"}`
+			}
+			client, last := recordingServer(t, http.StatusOK, body)
+
+			if err := tt.call(context.Background(), client); err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+			if last.URL.Path != "/api/1.0/"+tt.path {
+				t.Errorf("Expected path /api/1.0/%s, got %s", tt.path, last.URL.Path)
+			}
+			if got := last.URL.Query(); got.Encode() != tt.params.Encode() {
+				t.Errorf("Expected params %s, got %s", tt.params.Encode(), got.Encode())
+			}
+			for _, header := range []string{"X-Auth-Key", "X-Auth-Date", "Authorization"} {
+				if last.Header.Get(header) == "" {
+					t.Errorf("Missing %s header", header)
+				}
+			}
+			if last.Header.Get("User-Agent") != "TestAgent/1.0" {
+				t.Errorf("Expected User-Agent TestAgent/1.0, got %s", last.Header.Get("User-Agent"))
+			}
+		})
+	}
+}
+
+func TestGetRandomEpisodesMapsEpisodes(t *testing.T) {
+	client, _ := recordingServer(t, http.StatusOK, `{"status": "true", "episodes": [{"id": 1}, {"id": 2}], "count": 2}`)
+
+	resp, err := client.GetRandomEpisodes(context.Background(), 2, "", nil, nil)
+	if err != nil {
+		t.Fatalf("GetRandomEpisodes failed: %v", err)
+	}
+	if len(resp.Items) != 2 || resp.Items[1].ID != 2 {
+		t.Errorf("Expected the random episodes as items, got %+v", resp.Items)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		notFound bool
+	}{
+		{"server error", http.StatusInternalServerError, `oops`, false},
+		{"not found status", http.StatusNotFound, `{"status": "false", "description": "Feed id not found"}`, true},
+		{"false status", http.StatusOK, `{"status": "false", "description": "Invalid parameters"}`, false},
+		{"boolean false status", http.StatusOK, `{"status": false, "description": "No episodes found"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := recordingServer(t, tt.status, tt.body)
+
+			_, err := client.GetRecentEpisodes(context.Background(), 10)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected an APIError, got %v", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Path != "recent/episodes" {
+				t.Errorf("Unexpected error fields: %+v", apiErr)
+			}
+			if apiErr.NotFound() != tt.notFound {
+				t.Errorf("Expected NotFound() %v for %q", tt.notFound, tt.body)
+			}
+		})
+	}
+}
+
+func TestNotFoundMapping(t *testing.T) {
+	client, _ := recordingServer(t, http.StatusNotFound, `{"status": "false", "description": "Feed not found"}`)
+
+	if _, err := client.GetPodcastByID(context.Background(), 5); !errors.Is(err, ErrPodcastNotFound) {
+		t.Errorf("Expected ErrPodcastNotFound, got %v", err)
+	}
+	if _, err := client.GetEpisodeByID(context.Background(), 5); err == nil || err.Error() != "episode not found: ID 5" {
+		t.Errorf("Expected episode not found error, got %v", err)
+	}
+}
+
+func TestCleanContext(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	parent, cancelParent := context.WithDeadline(context.WithValue(context.Background(), ctxKey("user"), "user-1"), deadline)
+
+	ctx, cancel := cleanContext(parent)
+	defer cancel()
+
+	if ctx.Value(ctxKey("user")) != nil {
+		t.Error("Expected request values not to be carried over")
+	}
+	if got, ok := ctx.Deadline(); !ok || !got.Equal(deadline) {
+		t.Errorf("Expected deadline %v, got %v (%v)", deadline, got, ok)
+	}
+
+	cancelParent()
+	if ctx.Err() != nil {
+		t.Error("Expected the caller's cancellation not to be carried over")
+	}
+}
+
+func TestExpiredDeadlineSkipsCall(t *testing.T) {
+	client, last := recordingServer(t, http.StatusOK, `{"status": "true"}`)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	if _, err := client.GetCategories(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if last.URL != nil {
+		t.Error("Expected no request once the deadline has passed")
+	}
+}
+
+// fakeUsage refuses calls once allowed reaches zero and records the rest
+type fakeUsage struct {
+	allowed  int
+	recorded []string
+	failed   int
+}
+
+var errRefused = errors.New("refused")
+
+func (f *fakeUsage) Allow(ctx context.Context, endpoint string) error {
+	if f.allowed == 0 {
+		return errRefused
+	}
+	f.allowed--
+	return nil
+}
+
+func (f *fakeUsage) Record(ctx context.Context, endpoint string, latency time.Duration, callErr error) {
+	f.recorded = append(f.recorded, endpoint)
+	if callErr != nil {
+		f.failed++
+	}
+}
+
+func TestUsageTracker(t *testing.T) {
+	client, last := recordingServer(t, http.StatusOK, `{"status": "false", "description": "nope"}`)
+	usage := &fakeUsage{allowed: 1}
+	client.usage = usage
+
+	if _, err := client.GetCategories(context.Background()); err == nil {
+		t.Fatal("Expected the error status to fail the call")
+	}
+	if len(usage.recorded) != 1 || usage.recorded[0] != "categories/list" || usage.failed != 1 {
+		t.Errorf("Expected one failed categories/list call recorded, got %v with %d failed", usage.recorded, usage.failed)
+	}
+
+	*last = http.Request{}
+	if _, err := client.GetCategories(context.Background()); !errors.Is(err, errRefused) {
+		t.Errorf("Expected the tracker's refusal, got %v", err)
+	}
+	if last.URL != nil {
+		t.Error("Expected no request once the tracker refuses")
+	}
+	if len(usage.recorded) != 1 {
+		t.Errorf("Expected refused calls not to be recorded as made, got %v", usage.recorded)
+	}
+}
+
+func TestIdenticalCallsShareOneRequest(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"status": "true", "count": 1, "feeds": [{"id": 7, "title": "Tech Talk"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(Config{APIKey: "test-key", APISecret: "test-secret", BaseURL: server.URL})
+
+	var wg sync.WaitGroup
+	results := make([]*SearchResponse, 5)
+	errs := make([]error, 5)
+	for i := range results {
+		query := "technology"
+		if i == len(results)-1 {
+			query = "history"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = client.Search(context.Background(), query, 10, false, "", false, false)
+		}()
+	}
+	// Let every call reach the client before the first response comes back
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Expected one upstream request per distinct query, got %d", got)
+	}
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("Search %d: %v", i, errs[i])
+		}
+		if len(results[i].Feeds) != 1 || results[i].Feeds[0].ID != 7 {
+			t.Errorf("Search %d: expected the shared result, got %+v", i, results[i])
+		}
+	}
+	// Each caller decodes into its own result
+	results[0].Feeds[0].Title = "changed"
+	if results[1].Feeds[0].Title != "Tech Talk" {
+		t.Error("Expected callers not to share decoded results")
+	}
+}
+
+func TestSharedCallOutlivesFirstCallersDeadline(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"status": "true", "count": 1, "feeds": [{"id": 7, "title": "Tech Talk"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(Config{APIKey: "test-key", APISecret: "test-secret", BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	// The first caller gives up long before the response arrives
+	hurried, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	hurriedErr := make(chan error, 1)
+	go func() {
+		_, err := client.Search(hurried, "technology", 10, false, "", false, false)
+		hurriedErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	result, err := client.Search(context.Background(), "technology", 10, false, "", false, false)
+	if err != nil {
+		t.Fatalf("Expected the patient caller to get the shared result, got %v", err)
+	}
+	if len(result.Feeds) != 1 || result.Feeds[0].ID != 7 {
+		t.Errorf("Expected the shared result, got %+v", result)
+	}
+	if err := <-hurriedErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the first caller to stop at its own deadline, got %v", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected one upstream request, got %d", got)
+	}
+}
@@ -36,6 +36,7 @@ type Podcast struct {
 	Language         string            `json:"language"`
 	Categories       map[string]string `json:"categories"`
 	Locked           int               `json:"locked"`
+	Dead             int               `json:"dead"` // 1 once Podcast Index has given up on the feed
 	ImageURLHash     int               `json:"imageUrlHash"`
 	EpisodeCount     int               `json:"episodeCount"`
 	ITunesID         int               `json:"itunesId"`
@@ -44,40 +45,95 @@ type Podcast struct {
 
 // Episode represents an episode from the Podcast Index API
 type Episode struct {
-	ID                  int64  `json:"id"`
-	Title               string `json:"title"`
-	Link                string `json:"link"`
-	Description         string `json:"description"`
-	GUID                string `json:"guid"`
-	DatePublished       int64  `json:"datePublished"`
-	DatePublishedPretty string `json:"datePublishedPretty"`
-	DateCrawled         int64  `json:"dateCrawled"`
-	EnclosureURL        string `json:"enclosureUrl"`
-	EnclosureType       string `json:"enclosureType"`
-	EnclosureLength     int    `json:"enclosureLength"`
-	Duration            int    `json:"duration"`
-	Explicit            int    `json:"explicit"`
-	Episode             int    `json:"episode"`
-	EpisodeType         string `json:"episodeType"`
-	Season              int    `json:"season"`
-	Image               string `json:"image"`
-	FeedItunesId        int    `json:"feedItunesId"`
-	FeedImage           string `json:"feedImage"`
-	FeedId              int    `json:"feedId"`
-	FeedLanguage        string `json:"feedLanguage"`
-	FeedDead            int    `json:"feedDead"`
-	FeedDuplicateOf     int    `json:"feedDuplicateOf"`
-	ChaptersURL         string `json:"chaptersUrl"`
-	TranscriptURL       string `json:"transcriptUrl"`
+	ID                  int64    `json:"id"`
+	Title               string   `json:"title"`
+	Link                string   `json:"link"`
+	Description         string   `json:"description"`
+	GUID                string   `json:"guid"`
+	DatePublished       int64    `json:"datePublished"`
+	DatePublishedPretty string   `json:"datePublishedPretty"`
+	DateCrawled         int64    `json:"dateCrawled"`
+	EnclosureURL        string   `json:"enclosureUrl"`
+	EnclosureType       string   `json:"enclosureType"`
+	EnclosureLength     int      `json:"enclosureLength"`
+	Duration            int      `json:"duration"`
+	Explicit            int      `json:"explicit"`
+	Episode             int      `json:"episode"`
+	EpisodeType         string   `json:"episodeType"`
+	Season              int      `json:"season"`
+	Image               string   `json:"image"`
+	FeedItunesId        int      `json:"feedItunesId"`
+	FeedImage           string   `json:"feedImage"`
+	FeedId              int      `json:"feedId"`
+	FeedLanguage        string   `json:"feedLanguage"`
+	FeedDead            int      `json:"feedDead"`
+	FeedDuplicateOf     int      `json:"feedDuplicateOf"`
+	ChaptersURL         string   `json:"chaptersUrl"`
+	TranscriptURL       string   `json:"transcriptUrl"`
+	Persons             []Person `json:"persons,omitempty"`
+
+	AlternateEnclosures []AlternateEnclosure `json:"alternateEnclosures,omitempty"`
+}
+
+// Person represents a podcast:person credit on an episode
+type Person struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Group string `json:"group"`
+	Href  string `json:"href"`
+	Img   string `json:"img"`
+}
+
+// AlternateEnclosure is a podcast:alternateEnclosure: another rendition of the
+// episode's media, such as a lower bitrate or a different codec
+type AlternateEnclosure struct {
+	Type    string                     `json:"type"`
+	Length  int64                      `json:"length"`
+	Bitrate float64                    `json:"bitrate"` // Bits per second
+	Height  int                        `json:"height"`  // Video only
+	Lang    string                     `json:"lang"`
+	Title   string                     `json:"title"`
+	Codecs  string                     `json:"codecs"`
+	Default bool                       `json:"default"`
+	Sources []AlternateEnclosureSource `json:"sources"`
+}
+
+// AlternateEnclosureSource is one place an alternate enclosure is published
+type AlternateEnclosureSource struct {
+	URI         string `json:"uri"`
+	ContentType string `json:"contentType"`
 }
 
 // EpisodesResponse represents the response from episodes API
 type EpisodesResponse struct {
-	Status      string    `json:"status"`
-	Items       []Episode `json:"items"`
-	Count       int       `json:"count"`
-	Max         string    `json:"max"`
-	Description string    `json:"description"`
+	Status      string     `json:"status"`
+	Items       []Episode  `json:"items"`
+	LiveItems   []LiveItem `json:"liveItems,omitempty"` // Only returned by episodes/byfeedid
+	Count       int        `json:"count"`
+	Max         string     `json:"max"`
+	Description string     `json:"description"`
+}
+
+// LiveItem is a podcast:liveItem: a live stream the feed has scheduled, is
+// broadcasting or has finished
+type LiveItem struct {
+	ID              int64  `json:"id"`
+	Title           string `json:"title"`
+	Link            string `json:"link"`
+	Description     string `json:"description"`
+	GUID            string `json:"guid"`
+	EnclosureURL    string `json:"enclosureUrl"`
+	EnclosureType   string `json:"enclosureType"`
+	EnclosureLength int64  `json:"enclosureLength"`
+	StartTime       int64  `json:"startTime"` // Unix seconds
+	EndTime         int64  `json:"endTime"`   // Unix seconds; 0 when open-ended
+	Status          string `json:"status"`    // pending, live or ended
+	ContentLink     string `json:"contentLink"`
+	Image           string `json:"image"`
+	FeedImage       string `json:"feedImage"`
+	FeedID          int64  `json:"feedId"`
+	FeedTitle       string `json:"feedTitle"`
 }
 
 // EpisodeByGUIDResponse represents the response from episode by GUID API
@@ -28,6 +28,12 @@ type PodcastRepository interface {
 	IncrementFetchCount(ctx context.Context, podcastID uint) error
 }
 
+// FeedHealthRecorder is told when Podcast Index reports a feed gone or live
+type FeedHealthRecorder interface {
+	RecordFeedGone(ctx context.Context, podcastIndexFeedID int64, reason string)
+	RecordFeedAlive(ctx context.Context, podcastIndexFeedID int64)
+}
+
 // PodcastService defines the business logic interface for podcast operations
 type PodcastService interface {
 	// DB-first lookup (main method)
@@ -36,6 +42,10 @@ type PodcastService interface {
 	// Fetch from API and store
 	FetchAndStorePodcast(ctx context.Context, piID int64) (*models.Podcast, error)
 
+	// FindPodcast looks a podcast up by feed URL or iTunes ID, in the DB and
+	// then Podcast Index, for feeds known by something other than their ID
+	FindPodcast(ctx context.Context, feedURL string, itunesID int64) (*models.Podcast, error)
+
 	// Background refresh
 	RefreshPodcast(ctx context.Context, piID int64) (*models.Podcast, error)
 	ShouldRefresh(podcast *models.Podcast) bool
@@ -2,12 +2,16 @@ package podcasts
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"time"
 
 	"github.com/killallgit/player-api/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -26,6 +30,7 @@ func (r *Repository) CreatePodcast(ctx context.Context, podcast *models.Podcast)
 		}
 		return fmt.Errorf("creating podcast: %w", err)
 	}
+	r.linkCategories(ctx, podcast)
 	return nil
 }
 
@@ -38,6 +43,7 @@ func (r *Repository) UpdatePodcast(ctx context.Context, podcast *models.Podcast)
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("podcast not found")
 	}
+	r.linkCategories(ctx, podcast)
 	return nil
 }
 
@@ -176,3 +182,47 @@ func (r *Repository) IncrementFetchCount(ctx context.Context, podcastID uint) er
 		Where("id = ?", podcastID).
 		Update("fetch_count", gorm.Expr("fetch_count + 1")).Error
 }
+
+// linkCategories replaces the podcast's category associations from its Categories JSON.
+// Unknown categories are created by ID and name; the parent tree is filled in when the
+// full category list is synced. Failures are logged so a podcast store never fails on them.
+func (r *Repository) linkCategories(ctx context.Context, podcast *models.Podcast) {
+	if podcast.ID == 0 || len(podcast.Categories) == 0 {
+		return
+	}
+
+	var categoryMap map[string]string
+	if err := json.Unmarshal(podcast.Categories, &categoryMap); err != nil {
+		log.Printf("[WARN] Failed to parse categories for podcast %d: %v", podcast.PodcastIndexID, err)
+		return
+	}
+
+	categories := make([]models.Category, 0, len(categoryMap))
+	links := make([]models.PodcastCategory, 0, len(categoryMap))
+	for idStr, name := range categoryMap {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil || id == 0 {
+			continue
+		}
+		categories = append(categories, models.Category{ID: uint(id), Name: name})
+		links = append(links, models.PodcastCategory{PodcastID: podcast.ID, CategoryID: uint(id)})
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(categories) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&categories).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("podcast_id = ?", podcast.ID).Delete(&models.PodcastCategory{}).Error; err != nil {
+			return err
+		}
+		if len(links) > 0 {
+			return tx.Create(&links).Error
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to link categories for podcast %d: %v", podcast.PodcastIndexID, err)
+	}
+}
@@ -3,6 +3,7 @@ package podcasts
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -16,14 +17,29 @@ type Service struct {
 	repository   PodcastRepository
 	piClient     *podcastindex.Client
 	refreshAfter time.Duration // How old before auto-refresh
+	health       FeedHealthRecorder
 }
 
-func NewService(repository PodcastRepository, piClient *podcastindex.Client) PodcastService {
-	return &Service{
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithFeedHealth reports feeds Podcast Index marks dead or drops
+func WithFeedHealth(recorder FeedHealthRecorder) Option {
+	return func(s *Service) {
+		s.health = recorder
+	}
+}
+
+func NewService(repository PodcastRepository, piClient *podcastindex.Client, opts ...Option) PodcastService {
+	s := &Service{
 		repository:   repository,
 		piClient:     piClient,
 		refreshAfter: 24 * time.Hour, // Refresh if older than 24 hours
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GetPodcastByPodcastIndexID - DB-first lookup with automatic API fallback
@@ -74,9 +90,53 @@ func (s *Service) FetchAndStorePodcast(ctx context.Context, piID int64) (*models
 	if response.Status != "true" || response.Feed.ID == 0 {
 		return nil, fmt.Errorf("podcast not found in Podcast Index: status=%s", response.Status)
 	}
+	return s.storeFeed(ctx, &response.Feed)
+}
+
+// FindPodcast looks a podcast up by feed URL and then iTunes ID, in the
+// database first and then Podcast Index, storing what Podcast Index finds.
+// Either key may be empty. Returns an error wrapping
+// podcastindex.ErrPodcastNotFound when no lookup finds it.
+func (s *Service) FindPodcast(ctx context.Context, feedURL string, itunesID int64) (*models.Podcast, error) {
+	if feedURL != "" {
+		if podcast, err := s.repository.GetPodcastByFeedURL(ctx, feedURL); err == nil {
+			return podcast, nil
+		}
+	}
+	if itunesID > 0 {
+		if podcast, err := s.repository.GetPodcastByITunesID(ctx, itunesID); err == nil {
+			return podcast, nil
+		}
+	}
+	if s.piClient == nil {
+		return nil, fmt.Errorf("podcast index client not available")
+	}
+
+	var response *podcastindex.PodcastByIDResponse
+	err := fmt.Errorf("%w: no feed URL or iTunes ID", podcastindex.ErrPodcastNotFound)
+	if feedURL != "" {
+		response, err = s.piClient.GetPodcastByFeedURL(ctx, feedURL)
+	}
+	if response == nil && itunesID > 0 && (feedURL == "" || errors.Is(err, podcastindex.ErrPodcastNotFound)) {
+		response, err = s.piClient.GetPodcastByITunesID(ctx, itunesID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up podcast in Podcast Index: %w", err)
+	}
+
+	// The feed may be stored already under the URL Podcast Index has for it
+	if podcast, err := s.repository.GetPodcastByPodcastIndexID(ctx, int64(response.Feed.ID)); err == nil {
+		return podcast, nil
+	}
+	return s.storeFeed(ctx, &response.Feed)
+}
+
+// storeFeed stores a podcast fetched from Podcast Index
+func (s *Service) storeFeed(ctx context.Context, feed *podcastindex.Podcast) (*models.Podcast, error) {
+	s.recordFeedState(ctx, feed)
 
 	// Transform API response to our model
-	podcast, err := s.transformFromPodcastIndex(&response.Feed)
+	podcast, err := s.transformFromPodcastIndex(feed)
 	if err != nil {
 		return nil, fmt.Errorf("transforming podcast data: %w", err)
 	}
@@ -91,7 +151,7 @@ func (s *Service) FetchAndStorePodcast(ctx context.Context, piID int64) (*models
 		return nil, fmt.Errorf("storing podcast in database: %w", err)
 	}
 
-	log.Printf("[INFO] Stored podcast %d: %s", piID, podcast.Title)
+	log.Printf("[INFO] Stored podcast %d: %s", feed.ID, podcast.Title)
 
 	return podcast, nil
 }
@@ -110,8 +170,12 @@ func (s *Service) RefreshPodcast(ctx context.Context, piID int64) (*models.Podca
 	// Fetch fresh data from API
 	response, err := s.piClient.GetPodcastByID(ctx, piID)
 	if err != nil {
+		if errors.Is(err, podcastindex.ErrPodcastNotFound) && s.health != nil {
+			s.health.RecordFeedGone(ctx, piID, "feed no longer listed by Podcast Index")
+		}
 		return nil, fmt.Errorf("fetching from Podcast Index API: %w", err)
 	}
+	s.recordFeedState(ctx, &response.Feed)
 
 	// Transform to our model
 	podcast, err := s.transformFromPodcastIndex(&response.Feed)
@@ -163,6 +227,18 @@ func (s *Service) UpdatePodcastMetrics(ctx context.Context, podcastID uint, epis
 	return s.repository.UpdatePodcast(ctx, podcast)
 }
 
+// recordFeedState passes Podcast Index's dead flag on to feed health tracking
+func (s *Service) recordFeedState(ctx context.Context, piFeed *podcastindex.Podcast) {
+	if s.health == nil || piFeed.ID == 0 {
+		return
+	}
+	if piFeed.Dead == 1 {
+		s.health.RecordFeedGone(ctx, int64(piFeed.ID), "marked dead by Podcast Index")
+		return
+	}
+	s.health.RecordFeedAlive(ctx, int64(piFeed.ID))
+}
+
 // transformFromPodcastIndex transforms Podcast Index API response to our model
 func (s *Service) transformFromPodcastIndex(piFeed *podcastindex.Podcast) (*models.Podcast, error) {
 	// Convert categories map to JSON
@@ -215,6 +291,7 @@ func (s *Service) transformFromPodcastIndex(piFeed *podcastindex.Podcast) (*mode
 		LastGoodHTTPCode: piFeed.LastGoodHTTPCode,
 		ImageURLHash:     int64(piFeed.ImageURLHash),
 		Locked:           piFeed.Locked,
+		Dead:             piFeed.Dead,
 	}
 
 	return podcast, nil
@@ -343,3 +343,25 @@ func TestService_GetByID(t *testing.T) {
 	assert.Equal(t, expectedPodcast, podcast)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestService_FindPodcast_Stored(t *testing.T) {
+	mockRepo := new(MockPodcastRepository)
+	service := NewService(mockRepo, nil)
+
+	stored := &models.Podcast{PodcastIndexID: 12345, FeedURL: "https://example.com/feed.xml"}
+	itunesID := int64(987)
+	byITunes := &models.Podcast{PodcastIndexID: 678, ITunesID: &itunesID}
+
+	mockRepo.On("GetPodcastByFeedURL", mock.Anything, "https://example.com/feed.xml").Return(stored, nil)
+	mockRepo.On("GetPodcastByFeedURL", mock.Anything, "https://example.com/moved.xml").Return(nil, assert.AnError)
+	mockRepo.On("GetPodcastByITunesID", mock.Anything, int64(987)).Return(byITunes, nil)
+
+	podcast, err := service.FindPodcast(context.Background(), "https://example.com/feed.xml", 0)
+	require.NoError(t, err)
+	assert.Equal(t, stored, podcast)
+
+	// Falls back to the iTunes ID when the feed URL isn't stored
+	podcast, err = service.FindPodcast(context.Background(), "https://example.com/moved.xml", 987)
+	require.NoError(t, err)
+	assert.Equal(t, byITunes, podcast)
+}
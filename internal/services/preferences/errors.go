@@ -0,0 +1,11 @@
+package preferences
+
+import "errors"
+
+var (
+	// ErrPreferencesNotFound is returned when a user has no stored preferences
+	ErrPreferencesNotFound = errors.New("preferences not found")
+
+	// ErrInvalidPreference is returned when a known key fails validation
+	ErrInvalidPreference = errors.New("invalid preference")
+)
@@ -0,0 +1,38 @@
+package preferences
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Update is a partial preferences change; nil fields are left unchanged.
+// Extras are merged key by key, and a key set to nil is removed. Languages and
+// ExcludedCategories replace the stored lists; point at an empty slice to clear one.
+type Update struct {
+	PlaybackSpeed      *float64
+	SkipForwardSeconds *int
+	SkipBackSeconds    *int
+	Theme              *string
+	Languages          *[]string
+	ExcludedCategories *[]string
+	Extras             map[string]interface{}
+}
+
+// Service defines the interface for per-user preferences
+type Service interface {
+	// Get returns the user's preferences, or defaults if none are stored
+	Get(ctx context.Context, userID string) (*models.UserPreferences, error)
+
+	// Update validates and applies a partial change, returning the merged preferences
+	Update(ctx context.Context, userID string, update Update) (*models.UserPreferences, error)
+}
+
+// Repository defines the interface for preferences persistence
+type Repository interface {
+	// GetByUserID retrieves stored preferences for a user
+	GetByUserID(ctx context.Context, userID string) (*models.UserPreferences, error)
+
+	// Save creates or replaces preferences for a user
+	Save(ctx context.Context, prefs *models.UserPreferences) error
+}
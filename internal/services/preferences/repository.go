@@ -0,0 +1,54 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new preferences repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetByUserID retrieves stored preferences for a user
+func (r *repository) GetByUserID(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	var prefs models.UserPreferences
+
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&prefs)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrPreferencesNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &prefs, nil
+}
+
+// Save creates or replaces preferences for a user
+func (r *repository) Save(ctx context.Context, prefs *models.UserPreferences) error {
+	if prefs == nil {
+		return errors.New("preferences cannot be nil")
+	}
+
+	if prefs.ID != 0 {
+		return r.db.WithContext(ctx).Save(prefs).Error
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"playback_speed", "skip_forward_seconds", "skip_back_seconds", "theme",
+			"languages", "excluded_categories", "extras_data", "updated_at",
+		}),
+	}).Create(prefs).Error
+}
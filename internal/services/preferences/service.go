@@ -0,0 +1,201 @@
+package preferences
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Defaults applied to users without stored preferences
+const (
+	DefaultPlaybackSpeed      = 1.0
+	DefaultSkipForwardSeconds = 30
+	DefaultSkipBackSeconds    = 15
+	DefaultTheme              = "system"
+)
+
+// Validation bounds for known keys
+const (
+	MinPlaybackSpeed = 0.5
+	MaxPlaybackSpeed = 3.0
+	MinSkipSeconds   = 1
+	MaxSkipSeconds   = 300
+
+	// MaxExtrasBytes caps the encoded size of free-form settings
+	MaxExtrasBytes = 16 * 1024
+
+	MaxLanguages          = 10
+	MaxExcludedCategories = 25
+	MaxCategoryLength     = 64
+)
+
+// languageCode matches ISO 639 codes with an optional region, as Podcast Index uses them ("en", "pt-br")
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+var validThemes = map[string]bool{
+	"system": true,
+	"light":  true,
+	"dark":   true,
+}
+
+// service implements the Service interface
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new preferences service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// Get returns the user's preferences, or defaults if none are stored
+func (s *service) Get(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	prefs, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrPreferencesNotFound) {
+			return defaults(userID), nil
+		}
+		return nil, fmt.Errorf("loading preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// Update validates and applies a partial change, returning the merged preferences
+func (s *service) Update(ctx context.Context, userID string, update Update) (*models.UserPreferences, error) {
+	if err := validate(update); err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.PlaybackSpeed != nil {
+		prefs.PlaybackSpeed = *update.PlaybackSpeed
+	}
+	if update.SkipForwardSeconds != nil {
+		prefs.SkipForwardSeconds = *update.SkipForwardSeconds
+	}
+	if update.SkipBackSeconds != nil {
+		prefs.SkipBackSeconds = *update.SkipBackSeconds
+	}
+	if update.Theme != nil {
+		prefs.Theme = *update.Theme
+	}
+	if update.Languages != nil {
+		prefs.Languages = strings.Join(normalizeList(*update.Languages, true), ",")
+	}
+	if update.ExcludedCategories != nil {
+		prefs.ExcludedCategories = strings.Join(normalizeList(*update.ExcludedCategories, false), ",")
+	}
+
+	if len(update.Extras) > 0 {
+		extras, err := prefs.Extras()
+		if err != nil {
+			return nil, fmt.Errorf("decoding stored extras: %w", err)
+		}
+		for key, value := range update.Extras {
+			if value == nil {
+				delete(extras, key)
+				continue
+			}
+			extras[key] = value
+		}
+		if err := prefs.SetExtras(extras); err != nil {
+			return nil, fmt.Errorf("encoding extras: %w", err)
+		}
+		if len(prefs.ExtrasData) > MaxExtrasBytes {
+			return nil, fmt.Errorf("%w: extras exceed %d bytes", ErrInvalidPreference, MaxExtrasBytes)
+		}
+	}
+
+	if err := s.repo.Save(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("saving preferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+// validate checks known keys against their allowed ranges
+func validate(update Update) error {
+	if v := update.PlaybackSpeed; v != nil && (*v < MinPlaybackSpeed || *v > MaxPlaybackSpeed) {
+		return fmt.Errorf("%w: playback_speed must be between %.1f and %.1f", ErrInvalidPreference, MinPlaybackSpeed, MaxPlaybackSpeed)
+	}
+	if v := update.SkipForwardSeconds; v != nil && (*v < MinSkipSeconds || *v > MaxSkipSeconds) {
+		return fmt.Errorf("%w: skip_forward_seconds must be between %d and %d", ErrInvalidPreference, MinSkipSeconds, MaxSkipSeconds)
+	}
+	if v := update.SkipBackSeconds; v != nil && (*v < MinSkipSeconds || *v > MaxSkipSeconds) {
+		return fmt.Errorf("%w: skip_back_seconds must be between %d and %d", ErrInvalidPreference, MinSkipSeconds, MaxSkipSeconds)
+	}
+	if v := update.Theme; v != nil && !validThemes[*v] {
+		return fmt.Errorf("%w: theme must be one of system, light, dark", ErrInvalidPreference)
+	}
+	if v := update.Languages; v != nil {
+		languages := normalizeList(*v, true)
+		if len(languages) > MaxLanguages {
+			return fmt.Errorf("%w: at most %d languages", ErrInvalidPreference, MaxLanguages)
+		}
+		for _, lang := range languages {
+			if !languageCode.MatchString(lang) {
+				return fmt.Errorf("%w: %q is not a language code", ErrInvalidPreference, lang)
+			}
+		}
+	}
+	if v := update.ExcludedCategories; v != nil {
+		categories := normalizeList(*v, false)
+		if len(categories) > MaxExcludedCategories {
+			return fmt.Errorf("%w: at most %d excluded categories", ErrInvalidPreference, MaxExcludedCategories)
+		}
+		for _, category := range categories {
+			if len(category) > MaxCategoryLength || strings.Contains(category, ",") {
+				return fmt.Errorf("%w: invalid category %q", ErrInvalidPreference, category)
+			}
+		}
+	}
+	if len(update.Extras) > 0 {
+		data, err := json.Marshal(update.Extras)
+		if err != nil {
+			return fmt.Errorf("%w: extras must be JSON-encodable", ErrInvalidPreference)
+		}
+		if len(data) > MaxExtrasBytes {
+			return fmt.Errorf("%w: extras exceed %d bytes", ErrInvalidPreference, MaxExtrasBytes)
+		}
+	}
+	return nil
+}
+
+// normalizeList trims entries and drops blanks and duplicates, keeping the
+// caller's order. Language codes are also lowercased.
+func normalizeList(values []string, lower bool) []string {
+	seen := make(map[string]bool, len(values))
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if lower {
+			value = strings.ToLower(value)
+		}
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, value)
+	}
+	return normalized
+}
+
+func defaults(userID string) *models.UserPreferences {
+	return &models.UserPreferences{
+		UserID:             userID,
+		PlaybackSpeed:      DefaultPlaybackSpeed,
+		SkipForwardSeconds: DefaultSkipForwardSeconds,
+		SkipBackSeconds:    DefaultSkipBackSeconds,
+		Theme:              DefaultTheme,
+	}
+}
@@ -0,0 +1,119 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestService(t *testing.T) Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.UserPreferences{}))
+	return NewService(NewRepository(db))
+}
+
+func TestGet_ReturnsDefaults(t *testing.T) {
+	svc := setupTestService(t)
+
+	prefs, err := svc.Get(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPlaybackSpeed, prefs.PlaybackSpeed)
+	assert.Equal(t, DefaultSkipForwardSeconds, prefs.SkipForwardSeconds)
+	assert.Equal(t, DefaultTheme, prefs.Theme)
+}
+
+func TestUpdate_MergesPartialChanges(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	speed := 1.5
+	_, err := svc.Update(ctx, "user-1", Update{
+		PlaybackSpeed: &speed,
+		Extras:        map[string]interface{}{"autoplay": true, "sleep_timer": 30},
+	})
+	require.NoError(t, err)
+
+	theme := "dark"
+	prefs, err := svc.Update(ctx, "user-1", Update{
+		Theme:  &theme,
+		Extras: map[string]interface{}{"sleep_timer": nil},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.5, prefs.PlaybackSpeed)
+	assert.Equal(t, "dark", prefs.Theme)
+
+	stored, err := svc.Get(ctx, "user-1")
+	require.NoError(t, err)
+	extras, err := stored.Extras()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"autoplay": true}, extras)
+
+	// Other users are unaffected
+	other, err := svc.Get(ctx, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPlaybackSpeed, other.PlaybackSpeed)
+}
+
+func TestUpdate_Validation(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	tooFast := 4.0
+	zeroSkip := 0
+	badTheme := "neon"
+	huge := strings.Repeat("x", MaxExtrasBytes)
+
+	for name, update := range map[string]Update{
+		"speed":  {PlaybackSpeed: &tooFast},
+		"skip":   {SkipBackSeconds: &zeroSkip},
+		"theme":  {Theme: &badTheme},
+		"extras": {Extras: map[string]interface{}{"blob": huge}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Update(ctx, "user-1", update)
+			assert.True(t, errors.Is(err, ErrInvalidPreference))
+		})
+	}
+}
+
+func TestUpdate_DiscoveryFilters(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	languages := []string{" EN ", "pt-BR", "en", ""}
+	categories := []string{"News", "news", "True Crime"}
+	prefs, err := svc.Update(ctx, "user-1", Update{Languages: &languages, ExcludedCategories: &categories})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "pt-br"}, prefs.LanguageList())
+	assert.Equal(t, []string{"News", "True Crime"}, prefs.ExcludedCategoryList())
+
+	// Other updates leave the lists alone; an empty list clears one
+	theme := "light"
+	none := []string{}
+	prefs, err = svc.Update(ctx, "user-1", Update{Theme: &theme, ExcludedCategories: &none})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "pt-br"}, prefs.LanguageList())
+	assert.Empty(t, prefs.ExcludedCategoryList())
+
+	for name, update := range map[string]Update{
+		"language code": {Languages: &[]string{"english"}},
+		"comma":         {ExcludedCategories: &[]string{"News,Politics"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Update(ctx, "user-1", update)
+			assert.ErrorIs(t, err, ErrInvalidPreference)
+		})
+	}
+}
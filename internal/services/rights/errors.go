@@ -0,0 +1,11 @@
+package rights
+
+import "errors"
+
+var (
+	// ErrRightsNotFound is returned when a podcast has no recorded rights
+	ErrRightsNotFound = errors.New("podcast rights not found")
+
+	// ErrInvalidRights is returned for a missing feed ID or an overlong field
+	ErrInvalidRights = errors.New("invalid podcast rights")
+)
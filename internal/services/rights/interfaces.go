@@ -0,0 +1,32 @@
+package rights
+
+import (
+	"context"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Service manages the license and allowed uses recorded for each podcast.
+// Clip exports read the rights directly from the podcast_rights table.
+type Service interface {
+	// List returns the rights of every podcast that has them, by feed ID
+	List(ctx context.Context) ([]models.PodcastRights, error)
+
+	// Get returns the rights of a podcast
+	Get(ctx context.Context, feedID int64) (*models.PodcastRights, error)
+
+	// Set creates or replaces the rights of rights.PodcastIndexFeedID on behalf of userID
+	Set(ctx context.Context, rights *models.PodcastRights, userID string) (*models.PodcastRights, error)
+
+	// Delete removes a podcast's rights, lifting its restrictions
+	Delete(ctx context.Context, feedID int64) error
+}
+
+// Repository defines podcast rights persistence
+type Repository interface {
+	List(ctx context.Context) ([]models.PodcastRights, error)
+	Get(ctx context.Context, feedID int64) (*models.PodcastRights, error)
+	// Upsert stores rights, replacing any existing row for the feed
+	Upsert(ctx context.Context, rights *models.PodcastRights) error
+	Delete(ctx context.Context, feedID int64) error
+}
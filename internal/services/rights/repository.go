@@ -0,0 +1,69 @@
+package rights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new podcast rights repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// List returns all rights ordered by feed ID
+func (r *repository) List(ctx context.Context) ([]models.PodcastRights, error) {
+	var rights []models.PodcastRights
+	if err := r.db.WithContext(ctx).Order("podcast_index_feed_id").Find(&rights).Error; err != nil {
+		return nil, fmt.Errorf("listing podcast rights: %w", err)
+	}
+	return rights, nil
+}
+
+// Get returns the rights of a feed
+func (r *repository) Get(ctx context.Context, feedID int64) (*models.PodcastRights, error) {
+	var rights models.PodcastRights
+	err := r.db.WithContext(ctx).Where("podcast_index_feed_id = ?", feedID).First(&rights).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRightsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting podcast rights: %w", err)
+	}
+	return &rights, nil
+}
+
+// Upsert stores rights, replacing every field of an existing row for the feed
+func (r *repository) Upsert(ctx context.Context, rights *models.PodcastRights) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "podcast_index_feed_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at", "license", "fair_use_note",
+			"allow_training", "allow_derivatives", "allow_redistribution", "updated_by",
+		}),
+	}).Create(rights).Error
+	if err != nil {
+		return fmt.Errorf("saving podcast rights: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the rights of a feed
+func (r *repository) Delete(ctx context.Context, feedID int64) error {
+	res := r.db.WithContext(ctx).Where("podcast_index_feed_id = ?", feedID).Delete(&models.PodcastRights{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting podcast rights: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrRightsNotFound
+	}
+	return nil
+}
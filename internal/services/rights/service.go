@@ -0,0 +1,69 @@
+package rights
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Field limits, matching the column sizes of models.PodcastRights
+const (
+	maxLicenseLength     = 255
+	maxFairUseNoteLength = 1000
+)
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a podcast rights service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// List returns the rights of every podcast that has them
+func (s *service) List(ctx context.Context) ([]models.PodcastRights, error) {
+	return s.repo.List(ctx)
+}
+
+// Get returns the rights of a podcast
+func (s *service) Get(ctx context.Context, feedID int64) (*models.PodcastRights, error) {
+	return s.repo.Get(ctx, feedID)
+}
+
+// Set validates and stores a podcast's rights, replacing any it had
+func (s *service) Set(ctx context.Context, rights *models.PodcastRights, userID string) (*models.PodcastRights, error) {
+	if rights.PodcastIndexFeedID <= 0 {
+		return nil, fmt.Errorf("%w: feed ID must be positive", ErrInvalidRights)
+	}
+	rights.License = strings.TrimSpace(rights.License)
+	rights.FairUseNote = strings.TrimSpace(rights.FairUseNote)
+	if len(rights.License) > maxLicenseLength {
+		return nil, fmt.Errorf("%w: license is longer than %d characters", ErrInvalidRights, maxLicenseLength)
+	}
+	if len(rights.FairUseNote) > maxFairUseNoteLength {
+		return nil, fmt.Errorf("%w: fair use note is longer than %d characters", ErrInvalidRights, maxFairUseNoteLength)
+	}
+	rights.UpdatedBy = userID
+
+	if err := s.repo.Upsert(ctx, rights); err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] Rights: %s set podcast %d to license %q, allowed uses %v",
+		userID, rights.PodcastIndexFeedID, rights.License, rights.AllowedUses())
+
+	// Reload so the response carries the row's ID and creation time on update
+	return s.repo.Get(ctx, rights.PodcastIndexFeedID)
+}
+
+// Delete removes a podcast's rights
+func (s *service) Delete(ctx context.Context, feedID int64) error {
+	if err := s.repo.Delete(ctx, feedID); err != nil {
+		return err
+	}
+	log.Printf("[INFO] Rights: removed the rights of podcast %d", feedID)
+	return nil
+}
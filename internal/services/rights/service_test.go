@@ -0,0 +1,76 @@
+package rights
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestService(t *testing.T) Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.PodcastRights{}))
+	return NewService(NewRepository(db))
+}
+
+func TestSet_ReplacesRights(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.Get(ctx, 42)
+	assert.ErrorIs(t, err, ErrRightsNotFound)
+
+	created, err := svc.Set(ctx, &models.PodcastRights{
+		PodcastIndexFeedID: 42,
+		License:            " CC-BY-4.0 ",
+		AllowTraining:      true,
+		AllowDerivatives:   true,
+	}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, "CC-BY-4.0", created.License)
+	assert.Equal(t, []string{models.RightsUseTraining, models.RightsUseDerivatives}, created.AllowedUses())
+	assert.Empty(t, created.ExportRestriction())
+
+	// Allowed uses can be taken back; a false isn't replaced by a column default
+	updated, err := svc.Set(ctx, &models.PodcastRights{
+		PodcastIndexFeedID: 42,
+		License:            "CC-BY-ND-4.0",
+		AllowTraining:      true,
+	}, "admin-2")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, updated.ID)
+	assert.Equal(t, "CC-BY-ND-4.0", updated.License)
+	assert.False(t, updated.AllowDerivatives)
+	assert.Equal(t, "admin-2", updated.UpdatedBy)
+	assert.Equal(t, "podcast is marked no-derivatives", updated.ExportRestriction())
+
+	all, err := svc.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	require.NoError(t, svc.Delete(ctx, 42))
+	assert.ErrorIs(t, svc.Delete(ctx, 42), ErrRightsNotFound)
+}
+
+func TestSet_Validates(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.Set(ctx, &models.PodcastRights{}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidRights)
+
+	_, err = svc.Set(ctx, &models.PodcastRights{PodcastIndexFeedID: 1, License: strings.Repeat("x", 256)}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidRights)
+
+	_, err = svc.Set(ctx, &models.PodcastRights{PodcastIndexFeedID: 1, FairUseNote: strings.Repeat("x", 1001)}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidRights)
+}
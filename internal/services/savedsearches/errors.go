@@ -0,0 +1,14 @@
+package savedsearches
+
+import "errors"
+
+var (
+	// ErrSavedSearchNotFound is returned when a saved search doesn't exist or belongs to another user
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+
+	// ErrInvalidSavedSearch is returned when a saved search has no query or out-of-range settings
+	ErrInvalidSavedSearch = errors.New("invalid saved search")
+
+	// ErrTooManySavedSearches is returned when the user already has the maximum number of saved searches
+	ErrTooManySavedSearches = errors.New("too many saved searches")
+)
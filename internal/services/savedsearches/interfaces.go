@@ -0,0 +1,95 @@
+package savedsearches
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+	"github.com/killallgit/player-api/internal/services/webhooks"
+)
+
+// Searcher runs a saved search's query against Podcast Index
+type Searcher interface {
+	Search(ctx context.Context, query string, limit int, fullText bool, val string, apOnly bool, clean bool) (*podcastindex.SearchResponse, error)
+}
+
+// FeedFilter reports whether a podcast returned for search may be recorded.
+// It applies the search's language and category filters and the blocklist,
+// which Podcast Index search can't apply itself.
+type FeedFilter func(search *models.SavedSearch, feed podcastindex.Podcast) bool
+
+// Notifier posts in-app notifications
+type Notifier interface {
+	Notify(ctx context.Context, notification *models.Notification) error
+}
+
+// WebhookSender delivers signed events to user-supplied URLs
+type WebhookSender interface {
+	ValidateURL(raw string) error
+	Send(ctx context.Context, url string, event webhooks.Event, data map[string]interface{}) error
+}
+
+// Config bounds saved searches
+type Config struct {
+	MaxPerUser      int           // Saved searches a user may keep; 0 for no limit
+	DefaultInterval time.Duration // Time between runs when the user doesn't choose
+	MinInterval     time.Duration // Shortest interval a user may choose
+	DefaultLimit    int           // Podcast Index results fetched per run when the user doesn't choose
+	MaxLimit        int
+	BatchSize       int // Due searches run per scheduler tick
+}
+
+// Service manages saved searches and runs them on schedule
+type Service interface {
+	// Create validates and stores a saved search; its first run is due immediately
+	Create(ctx context.Context, search *models.SavedSearch) error
+
+	// List returns the user's saved searches, oldest first
+	List(ctx context.Context, userID string) ([]models.SavedSearch, error)
+
+	// Get returns one of the user's saved searches
+	Get(ctx context.Context, userID string, id uint) (*models.SavedSearch, error)
+
+	// Delete removes one of the user's saved searches and its results
+	Delete(ctx context.Context, userID string, id uint) error
+
+	// Results returns podcasts the search found after since, newest first
+	Results(ctx context.Context, userID string, id uint, since time.Time, limit int) ([]models.SavedSearchResult, error)
+
+	// Run executes a saved search now, records podcasts it hasn't returned
+	// before and returns how many there were
+	Run(ctx context.Context, search *models.SavedSearch) (int, error)
+
+	// RunDue runs the saved searches whose next run has come and returns how many ran
+	RunDue(ctx context.Context) (int, error)
+}
+
+// Repository defines the interface for saved search persistence
+type Repository interface {
+	Create(ctx context.Context, search *models.SavedSearch) error
+
+	// CountByUser counts the user's saved searches
+	CountByUser(ctx context.Context, userID string) (int64, error)
+
+	// ListByUser returns the user's saved searches, oldest first
+	ListByUser(ctx context.Context, userID string) ([]models.SavedSearch, error)
+
+	// Get returns one of the user's saved searches, or ErrSavedSearchNotFound
+	Get(ctx context.Context, userID string, id uint) (*models.SavedSearch, error)
+
+	// Delete removes one of the user's saved searches with its results
+	Delete(ctx context.Context, userID string, id uint) error
+
+	// ListDue returns saved searches whose next run is at or before now, most overdue first
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.SavedSearch, error)
+
+	// SaveRun stores the outcome fields of a run (last and next run, last error)
+	SaveRun(ctx context.Context, search *models.SavedSearch) error
+
+	// AddResults records podcasts the search hasn't returned before and returns them
+	AddResults(ctx context.Context, searchID uint, results []models.SavedSearchResult) ([]models.SavedSearchResult, error)
+
+	// ListResults returns results found after since, newest first
+	ListResults(ctx context.Context, searchID uint, since time.Time, limit int) ([]models.SavedSearchResult, error)
+}
@@ -0,0 +1,137 @@
+package savedsearches
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new saved search repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create stores a saved search
+func (r *repository) Create(ctx context.Context, search *models.SavedSearch) error {
+	return r.db.WithContext(ctx).Create(search).Error
+}
+
+// CountByUser counts the user's saved searches
+func (r *repository) CountByUser(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.SavedSearch{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// ListByUser returns the user's saved searches, oldest first
+func (r *repository) ListByUser(ctx context.Context, userID string) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&searches).Error
+	return searches, err
+}
+
+// Get returns one of the user's saved searches
+func (r *repository) Get(ctx context.Context, userID string, id uint) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&search).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSavedSearchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+// Delete removes one of the user's saved searches with its results
+func (r *repository) Delete(ctx context.Context, userID string, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.SavedSearch{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSavedSearchNotFound
+		}
+		return tx.Where("saved_search_id = ?", id).Delete(&models.SavedSearchResult{}).Error
+	})
+}
+
+// ListDue returns saved searches whose next run is at or before now, most overdue first
+func (r *repository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	query := r.db.WithContext(ctx).Where("next_run_at <= ?", now).Order("next_run_at")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&searches).Error
+	return searches, err
+}
+
+// SaveRun stores the outcome fields of a run
+func (r *repository) SaveRun(ctx context.Context, search *models.SavedSearch) error {
+	return r.db.WithContext(ctx).Model(&models.SavedSearch{}).Where("id = ?", search.ID).Updates(map[string]interface{}{
+		"last_run_at": search.LastRunAt,
+		"next_run_at": search.NextRunAt,
+		"last_error":  search.LastError,
+	}).Error
+}
+
+// AddResults records podcasts the search hasn't returned before and returns them
+func (r *repository) AddResults(ctx context.Context, searchID uint, results []models.SavedSearchResult) ([]models.SavedSearchResult, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+	feedIDs := make([]int64, len(results))
+	for i := range results {
+		feedIDs[i] = results[i].PodcastIndexFeedID
+	}
+
+	var added []models.SavedSearchResult
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var known []int64
+		if err := tx.Model(&models.SavedSearchResult{}).
+			Where("saved_search_id = ? AND podcast_index_feed_id IN ?", searchID, feedIDs).
+			Pluck("podcast_index_feed_id", &known).Error; err != nil {
+			return err
+		}
+		seen := make(map[int64]bool, len(known))
+		for _, id := range known {
+			seen[id] = true
+		}
+		for _, result := range results {
+			if seen[result.PodcastIndexFeedID] {
+				continue
+			}
+			seen[result.PodcastIndexFeedID] = true
+			result.SavedSearchID = searchID
+			added = append(added, result)
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		// A concurrent run may have recorded some of these in the meantime
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&added).Error
+	})
+	return added, err
+}
+
+// ListResults returns results found after since, newest first
+func (r *repository) ListResults(ctx context.Context, searchID uint, since time.Time, limit int) ([]models.SavedSearchResult, error) {
+	var results []models.SavedSearchResult
+	query := r.db.WithContext(ctx).Where("saved_search_id = ? AND found_at > ?", searchID, since).Order("found_at DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&results).Error
+	return results, err
+}
@@ -0,0 +1,64 @@
+package savedsearches
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Scheduler periodically runs saved searches that are due
+type Scheduler struct {
+	service  Service
+	interval time.Duration
+	cancel   context.CancelFunc
+}
+
+// NewScheduler creates a new saved search scheduler that checks for due
+// searches every interval
+func NewScheduler(service Service, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Start checks immediately and then every interval until Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	go func() {
+		s.Check(ctx)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Check(ctx)
+			case <-ctx.Done():
+				log.Println("[INFO] Saved search scheduler stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Check runs the saved searches that are due
+func (s *Scheduler) Check(ctx context.Context) {
+	ran, err := s.service.RunDue(ctx)
+	if err != nil {
+		log.Printf("[ERROR] Failed to run saved searches: %v", err)
+		return
+	}
+	if ran > 0 {
+		log.Printf("[INFO] Ran %d saved searches", ran)
+	}
+}
@@ -0,0 +1,276 @@
+package savedsearches
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/webhooks"
+)
+
+// notifiedTitles is how many new podcast titles a notification names
+const notifiedTitles = 3
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	searcher Searcher
+	filter   FeedFilter
+	notifier Notifier
+	webhooks WebhookSender
+	config   Config
+	now      func() time.Time
+}
+
+// Option configures optional collaborators of the service
+type Option func(*service)
+
+// WithFilter drops results the filter rejects
+func WithFilter(filter FeedFilter) Option {
+	return func(s *service) { s.filter = filter }
+}
+
+// WithNotifier posts a notification when a run of a search with Notify set finds podcasts
+func WithNotifier(notifier Notifier) Option {
+	return func(s *service) { s.notifier = notifier }
+}
+
+// WithWebhooks lets saved searches push new results to a webhook URL
+func WithWebhooks(sender WebhookSender) Option {
+	return func(s *service) { s.webhooks = sender }
+}
+
+// NewService creates a new saved search service
+func NewService(repo Repository, searcher Searcher, config Config, opts ...Option) Service {
+	if config.DefaultInterval <= 0 {
+		config.DefaultInterval = 24 * time.Hour
+	}
+	if config.MinInterval <= 0 || config.MinInterval > config.DefaultInterval {
+		config.MinInterval = min(time.Hour, config.DefaultInterval)
+	}
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = 100
+	}
+	if config.DefaultLimit <= 0 || config.DefaultLimit > config.MaxLimit {
+		config.DefaultLimit = min(20, config.MaxLimit)
+	}
+	s := &service{repo: repo, searcher: searcher, config: config, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create validates and stores a saved search, applying defaults for the
+// interval and limit. Its first run is due immediately and records the
+// current results as the baseline later runs are compared with.
+func (s *service) Create(ctx context.Context, search *models.SavedSearch) error {
+	search.Query = strings.TrimSpace(search.Query)
+	search.Name = strings.TrimSpace(search.Name)
+	if search.UserID == "" || search.Query == "" {
+		return fmt.Errorf("%w: query is required", ErrInvalidSavedSearch)
+	}
+	if len(search.Query) > 255 || len(search.Name) > 100 {
+		return fmt.Errorf("%w: query or name too long", ErrInvalidSavedSearch)
+	}
+	if search.Name == "" {
+		search.Name = search.Query
+	}
+
+	if search.Limit == 0 {
+		search.Limit = s.config.DefaultLimit
+	}
+	if search.Limit < 1 || search.Limit > s.config.MaxLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSavedSearch, s.config.MaxLimit)
+	}
+
+	if search.IntervalMinutes == 0 {
+		search.IntervalMinutes = int(s.config.DefaultInterval / time.Minute)
+	}
+	if time.Duration(search.IntervalMinutes)*time.Minute < s.config.MinInterval {
+		return fmt.Errorf("%w: interval must be at least %d minutes", ErrInvalidSavedSearch, int(s.config.MinInterval/time.Minute))
+	}
+
+	if search.WebhookURL != "" {
+		if s.webhooks == nil {
+			return fmt.Errorf("%w: webhooks are not available", ErrInvalidSavedSearch)
+		}
+		if err := s.webhooks.ValidateURL(search.WebhookURL); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSavedSearch, err)
+		}
+	}
+
+	if s.config.MaxPerUser > 0 {
+		count, err := s.repo.CountByUser(ctx, search.UserID)
+		if err != nil {
+			return fmt.Errorf("counting saved searches: %w", err)
+		}
+		if count >= int64(s.config.MaxPerUser) {
+			return fmt.Errorf("%w: at most %d allowed", ErrTooManySavedSearches, s.config.MaxPerUser)
+		}
+	}
+
+	search.ID = 0
+	search.LastRunAt = nil
+	search.LastError = ""
+	search.NextRunAt = s.now().UTC()
+	return s.repo.Create(ctx, search)
+}
+
+// List returns the user's saved searches, oldest first
+func (s *service) List(ctx context.Context, userID string) ([]models.SavedSearch, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Get returns one of the user's saved searches
+func (s *service) Get(ctx context.Context, userID string, id uint) (*models.SavedSearch, error) {
+	return s.repo.Get(ctx, userID, id)
+}
+
+// Delete removes one of the user's saved searches and its results
+func (s *service) Delete(ctx context.Context, userID string, id uint) error {
+	return s.repo.Delete(ctx, userID, id)
+}
+
+// Results returns podcasts the search found after since, newest first
+func (s *service) Results(ctx context.Context, userID string, id uint, since time.Time, limit int) ([]models.SavedSearchResult, error) {
+	if _, err := s.repo.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListResults(ctx, id, since, limit)
+}
+
+// Run executes a saved search now. Whatever the outcome, the next run is
+// scheduled one interval later, so a failing search doesn't hammer Podcast Index.
+func (s *service) Run(ctx context.Context, search *models.SavedSearch) (int, error) {
+	now := s.now().UTC()
+	initial := search.LastRunAt == nil
+	search.NextRunAt = now.Add(time.Duration(search.IntervalMinutes) * time.Minute)
+
+	added, runErr := s.search(ctx, search, now, initial)
+	search.LastError = ""
+	// A failed first run leaves LastRunAt unset so the next one still
+	// records the baseline instead of announcing everything as new
+	if runErr == nil || !initial {
+		search.LastRunAt = &now
+	}
+	if runErr != nil {
+		search.LastError = runErr.Error()
+		if len(search.LastError) > 500 {
+			search.LastError = search.LastError[:500]
+		}
+	}
+	if err := s.repo.SaveRun(ctx, search); err != nil {
+		return 0, fmt.Errorf("saving run of saved search %d: %w", search.ID, err)
+	}
+	if runErr != nil {
+		return 0, runErr
+	}
+
+	if !initial && len(added) > 0 {
+		s.announce(ctx, search, added)
+	}
+	return len(added), nil
+}
+
+func (s *service) search(ctx context.Context, search *models.SavedSearch, now time.Time, initial bool) ([]models.SavedSearchResult, error) {
+	response, err := s.searcher.Search(ctx, search.Query, search.Limit, search.FullText, search.Val, search.ApOnly, search.Clean)
+	if err != nil {
+		return nil, fmt.Errorf("searching Podcast Index: %w", err)
+	}
+
+	results := make([]models.SavedSearchResult, 0, len(response.Feeds))
+	for _, feed := range response.Feeds {
+		if s.filter != nil && !s.filter(search, feed) {
+			continue
+		}
+		image := feed.Artwork
+		if image == "" {
+			image = feed.Image
+		}
+		results = append(results, models.SavedSearchResult{
+			PodcastIndexFeedID: int64(feed.ID),
+			FoundAt:            now,
+			Initial:            initial,
+			Title:              feed.Title,
+			Author:             feed.Author,
+			Image:              image,
+			URL:                feed.URL,
+		})
+	}
+
+	added, err := s.repo.AddResults(ctx, search.ID, results)
+	if err != nil {
+		return nil, fmt.Errorf("recording results: %w", err)
+	}
+	return added, nil
+}
+
+// announce tells the user about new results. Failures are logged since the
+// results are already recorded and can be fetched.
+func (s *service) announce(ctx context.Context, search *models.SavedSearch, added []models.SavedSearchResult) {
+	if search.Notify && s.notifier != nil {
+		titles := make([]string, 0, notifiedTitles)
+		for _, result := range added[:min(len(added), notifiedTitles)] {
+			titles = append(titles, result.Title)
+		}
+		body := strings.Join(titles, ", ")
+		if len(added) > notifiedTitles {
+			body += fmt.Sprintf(" and %d more", len(added)-notifiedTitles)
+		}
+		err := s.notifier.Notify(ctx, &models.Notification{
+			UserID: search.UserID,
+			Type:   models.NotificationSavedSearch,
+			Title:  fmt.Sprintf("%d new podcasts for %q", len(added), search.Name),
+			Body:   body,
+			Data:   map[string]interface{}{"saved_search_id": search.ID, "count": len(added)},
+		})
+		if err != nil {
+			log.Printf("[WARN] Failed to notify user %s about saved search %d: %v", search.UserID, search.ID, err)
+		}
+	}
+
+	if search.WebhookURL != "" && s.webhooks != nil {
+		feedIDs := make([]int64, len(added))
+		for i, result := range added {
+			feedIDs[i] = result.PodcastIndexFeedID
+		}
+		err := s.webhooks.Send(ctx, search.WebhookURL, webhooks.EventSavedSearchResults, map[string]interface{}{
+			"saved_search_id": search.ID,
+			"query":           search.Query,
+			"feed_ids":        feedIDs,
+			"found_at":        added[0].FoundAt,
+		})
+		if err != nil {
+			log.Printf("[WARN] Failed to send saved search %d webhook: %v", search.ID, err)
+		}
+	}
+}
+
+// RunDue runs the saved searches whose next run has come, one at a time
+func (s *service) RunDue(ctx context.Context) (int, error) {
+	due, err := s.repo.ListDue(ctx, s.now().UTC(), s.config.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("listing due saved searches: %w", err)
+	}
+
+	ran := 0
+	for i := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		added, err := s.Run(ctx, &due[i])
+		ran++
+		if err != nil {
+			log.Printf("[WARN] Saved search %d failed: %v", due[i].ID, err)
+			continue
+		}
+		if added > 0 {
+			log.Printf("[DEBUG] Saved search %d found %d new podcasts", due[i].ID, added)
+		}
+	}
+	return ran, nil
+}
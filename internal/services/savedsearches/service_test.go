@@ -0,0 +1,184 @@
+package savedsearches
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/podcastindex"
+	"github.com/killallgit/player-api/internal/services/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.SavedSearch{}, &models.SavedSearchResult{}))
+	return db
+}
+
+type fakeSearcher struct {
+	feeds []podcastindex.Podcast
+	err   error
+	calls int
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, query string, limit int, fullText bool, val string, apOnly bool, clean bool) (*podcastindex.SearchResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &podcastindex.SearchResponse{Feeds: f.feeds}, nil
+}
+
+type fakeNotifier struct {
+	notifications []*models.Notification
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, notification *models.Notification) error {
+	f.notifications = append(f.notifications, notification)
+	return nil
+}
+
+type fakeWebhooks struct {
+	sent []map[string]interface{}
+}
+
+func (f *fakeWebhooks) ValidateURL(raw string) error {
+	if raw != "https://hooks.example.com/in" {
+		return errors.New("bad url")
+	}
+	return nil
+}
+
+func (f *fakeWebhooks) Send(ctx context.Context, url string, event webhooks.Event, data map[string]interface{}) error {
+	f.sent = append(f.sent, data)
+	return nil
+}
+
+type testEnv struct {
+	svc      *service
+	searcher *fakeSearcher
+	notifier *fakeNotifier
+	webhooks *fakeWebhooks
+	now      time.Time
+}
+
+func newTestEnv(t *testing.T, config Config, opts ...Option) *testEnv {
+	env := &testEnv{
+		searcher: &fakeSearcher{},
+		notifier: &fakeNotifier{},
+		webhooks: &fakeWebhooks{},
+		now:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	opts = append([]Option{WithNotifier(env.notifier), WithWebhooks(env.webhooks)}, opts...)
+	env.svc = NewService(NewRepository(setupTestDB(t)), env.searcher, config, opts...).(*service)
+	env.svc.now = func() time.Time { return env.now }
+	return env
+}
+
+func TestCreateValidates(t *testing.T) {
+	env := newTestEnv(t, Config{MaxPerUser: 2, DefaultInterval: 24 * time.Hour, MinInterval: time.Hour})
+	ctx := context.Background()
+
+	search := &models.SavedSearch{UserID: "user-1", Query: "  rust  "}
+	require.NoError(t, env.svc.Create(ctx, search))
+	assert.Equal(t, "rust", search.Name)
+	assert.Equal(t, 20, search.Limit)
+	assert.Equal(t, 24*60, search.IntervalMinutes)
+	assert.Equal(t, env.now, search.NextRunAt, "first run is due immediately")
+
+	err := env.svc.Create(ctx, &models.SavedSearch{UserID: "user-1", Query: "go", IntervalMinutes: 30})
+	assert.ErrorIs(t, err, ErrInvalidSavedSearch)
+	err = env.svc.Create(ctx, &models.SavedSearch{UserID: "user-1", Query: "go", Limit: 500})
+	assert.ErrorIs(t, err, ErrInvalidSavedSearch)
+	err = env.svc.Create(ctx, &models.SavedSearch{UserID: "user-1", Query: "go", WebhookURL: "http://localhost/"})
+	assert.ErrorIs(t, err, ErrInvalidSavedSearch)
+	err = env.svc.Create(ctx, &models.SavedSearch{UserID: "user-1", Query: " "})
+	assert.ErrorIs(t, err, ErrInvalidSavedSearch)
+
+	require.NoError(t, env.svc.Create(ctx, &models.SavedSearch{UserID: "user-1", Query: "go"}))
+	err = env.svc.Create(ctx, &models.SavedSearch{UserID: "user-1", Query: "zig"})
+	assert.ErrorIs(t, err, ErrTooManySavedSearches)
+	require.NoError(t, env.svc.Create(ctx, &models.SavedSearch{UserID: "user-2", Query: "zig"}))
+
+	_, err = env.svc.Get(ctx, "user-2", search.ID)
+	assert.ErrorIs(t, err, ErrSavedSearchNotFound, "other users' searches are hidden")
+}
+
+func TestRunRecordsOnlyNewPodcasts(t *testing.T) {
+	filter := func(search *models.SavedSearch, feed podcastindex.Podcast) bool {
+		return feed.Language == "en"
+	}
+	env := newTestEnv(t, Config{}, WithFilter(filter))
+	ctx := context.Background()
+
+	search := &models.SavedSearch{UserID: "user-1", Query: "rust", Notify: true, WebhookURL: "https://hooks.example.com/in"}
+	require.NoError(t, env.svc.Create(ctx, search))
+
+	env.searcher.feeds = []podcastindex.Podcast{
+		{ID: 1, Title: "Rustacean Station", Language: "en"},
+		{ID: 2, Title: "Rost", Language: "de"},
+	}
+	ran, err := env.svc.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+	assert.Empty(t, env.notifier.notifications, "the first run is a baseline")
+	assert.Empty(t, env.webhooks.sent)
+
+	ran, err = env.svc.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, ran, "not due again until the interval passes")
+
+	env.now = env.now.Add(25 * time.Hour)
+	env.searcher.feeds = append(env.searcher.feeds, podcastindex.Podcast{ID: 3, Title: "New Rustacean", Language: "en"})
+	ran, err = env.svc.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+
+	require.Len(t, env.notifier.notifications, 1)
+	assert.Equal(t, models.NotificationSavedSearch, env.notifier.notifications[0].Type)
+	assert.Equal(t, "New Rustacean", env.notifier.notifications[0].Body)
+	require.Len(t, env.webhooks.sent, 1)
+	assert.Equal(t, []int64{3}, env.webhooks.sent[0]["feed_ids"])
+
+	results, err := env.svc.Results(ctx, "user-1", search.ID, time.Time{}, 50)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, int64(3), results[0].PodcastIndexFeedID)
+	assert.False(t, results[0].Initial)
+	assert.True(t, results[1].Initial)
+
+	results, err = env.svc.Results(ctx, "user-1", search.ID, env.now.Add(-time.Hour), 50)
+	require.NoError(t, err)
+	assert.Len(t, results, 1, "since excludes the baseline")
+}
+
+func TestRunFailureReschedules(t *testing.T) {
+	env := newTestEnv(t, Config{})
+	ctx := context.Background()
+
+	search := &models.SavedSearch{UserID: "user-1", Query: "rust"}
+	require.NoError(t, env.svc.Create(ctx, search))
+	env.searcher.err = errors.New("podcast index down")
+
+	_, err := env.svc.Run(ctx, search)
+	require.Error(t, err)
+
+	stored, err := env.svc.Get(ctx, "user-1", search.ID)
+	require.NoError(t, err)
+	assert.Contains(t, stored.LastError, "podcast index down")
+	assert.Equal(t, env.now.Add(24*time.Hour), stored.NextRunAt.UTC())
+
+	require.NoError(t, env.svc.Delete(ctx, "user-1", search.ID))
+	_, err = env.svc.Get(ctx, "user-1", search.ID)
+	assert.ErrorIs(t, err, ErrSavedSearchNotFound)
+}
@@ -0,0 +1,18 @@
+package streamcache
+
+import "errors"
+
+var (
+	// ErrRangeNotSupported is returned when the origin ignores Range requests
+	ErrRangeNotSupported = errors.New("origin does not support range requests")
+
+	// ErrInvalidRange is returned for ranges outside the audio
+	ErrInvalidRange = errors.New("invalid byte range")
+
+	// ErrOriginStatus is returned when the origin answers with an unexpected status
+	ErrOriginStatus = errors.New("unexpected origin status")
+
+	// ErrSourceChanged is returned when the origin serves a different file than
+	// the one the cached chunks came from
+	ErrSourceChanged = errors.New("enclosure changed while streaming")
+)
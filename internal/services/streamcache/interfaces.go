@@ -0,0 +1,37 @@
+package streamcache
+
+import (
+	"context"
+	"io"
+)
+
+// Service serves byte ranges of remote audio from chunks cached on disk
+type Service interface {
+	// Stat returns the size and content type of an episode's audio, fetching the
+	// first chunk from the origin if nothing usable is cached yet. Each variant, such as
+	// an alternate enclosure, is cached apart; the empty variant is the enclosure.
+	Stat(ctx context.Context, podcastIndexEpisodeID int64, variant, sourceURL string) (*Source, error)
+
+	// WriteRange writes bytes start through end (inclusive) of the audio to w,
+	// fetching missing chunks from the origin. Concurrent requests for the same
+	// chunk share a single origin fetch. ErrSourceChanged means the enclosure
+	// changed midway; the entry has been discarded and the next request starts over.
+	WriteRange(ctx context.Context, w io.Writer, podcastIndexEpisodeID int64, variant, sourceURL string, start, end int64) error
+
+	// CachedPrefix returns how many bytes from the start of the audio are on
+	// disk without a gap, and the enclosure's metadata. Nothing is fetched; source
+	// is nil when the episode has never been streamed.
+	CachedPrefix(podcastIndexEpisodeID int64) (cached int64, source *Source, err error)
+}
+
+// Source describes cached audio
+type Source struct {
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+	ChunkSize   int64  `json:"chunk_size"` // Entries cached under a different size are discarded
+
+	// The enclosure the chunks came from; a different URL, or an ETag or size
+	// the origin no longer reports, discards the entry
+	URL  string `json:"url"`
+	ETag string `json:"etag,omitempty"`
+}
@@ -0,0 +1,482 @@
+package streamcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/killallgit/player-api/pkg/download"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultChunkSize is the size of cached segments; range requests are aligned to it
+const DefaultChunkSize = 1 << 20
+
+// service implements the Service interface
+type service struct {
+	directory    string
+	chunkSize    int64
+	client       *http.Client
+	fetchTimeout time.Duration
+	policies     *download.Policies
+	group        singleflight.Group
+	maxSize      int64 // Chunk bytes kept before least recently used entries are evicted; 0 = unbounded
+
+	// mu guards metadata checks, entry invalidation and size accounting
+	mu   sync.Mutex
+	size int64 // Chunk bytes on disk; -1 until the directory has been scanned
+}
+
+// Option configures the segment cache
+type Option func(*service)
+
+// WithMaxSize bounds the bytes of audio kept on disk. Past it, whole entries are
+// evicted least recently streamed first. Non-positive leaves it unbounded.
+func WithMaxSize(bytes int64) Option {
+	return func(s *service) {
+		if bytes > 0 {
+			s.maxSize = bytes
+		}
+	}
+}
+
+// NewService creates a new segment cache storing chunks under directory. A
+// non-positive chunkSize uses DefaultChunkSize. Origin requests follow the
+// per-host download policies; nil uses the built-in defaults.
+func NewService(directory string, chunkSize int64, fetchTimeout time.Duration, policies *download.Policies, opts ...Option) Service {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if policies == nil {
+		policies = download.NewPolicies(download.DefaultBasePolicy(), download.DefaultHostPolicies())
+	}
+	s := &service{
+		directory:    directory,
+		chunkSize:    chunkSize,
+		client:       &http.Client{},
+		fetchTimeout: fetchTimeout,
+		policies:     policies,
+		size:         -1,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Stat returns the size and content type of an episode's audio. An entry
+// cached under another chunk size or for another enclosure URL is discarded
+// and fetched again.
+func (s *service) Stat(ctx context.Context, podcastIndexEpisodeID int64, variant, sourceURL string) (*Source, error) {
+	entry := entryName(podcastIndexEpisodeID, variant)
+	s.touch(entry)
+
+	source, err := s.readMeta(entry)
+	if err == nil && source.URL == sourceURL {
+		return source, nil
+	}
+	if err == nil || !os.IsNotExist(err) {
+		log.Printf("[INFO] Discarding stale stream cache entry %s", entry)
+		s.invalidate(entry)
+	}
+
+	// The first chunk's response carries the total size
+	if err := s.ensureChunk(ctx, entry, sourceURL, 0); err != nil {
+		return nil, err
+	}
+	return s.readMeta(entry)
+}
+
+// WriteRange writes bytes start through end (inclusive) of the audio to w
+func (s *service) WriteRange(ctx context.Context, w io.Writer, podcastIndexEpisodeID int64, variant, sourceURL string, start, end int64) error {
+	entry := entryName(podcastIndexEpisodeID, variant)
+	source, err := s.Stat(ctx, podcastIndexEpisodeID, variant, sourceURL)
+	if err != nil {
+		return err
+	}
+	if start < 0 || start > end || end >= source.Size {
+		return ErrInvalidRange
+	}
+
+	for index := start / s.chunkSize; index <= end/s.chunkSize; index++ {
+		if err := s.ensureChunk(ctx, entry, sourceURL, index); err != nil {
+			return err
+		}
+
+		chunkStart := index * s.chunkSize
+		from := max(start, chunkStart) - chunkStart
+		to := min(end, chunkStart+s.chunkSize-1) - chunkStart
+
+		if err := s.copyChunk(w, entry, index, from, to-from+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CachedPrefix returns the length of the gap-free run of chunks from the start
+func (s *service) CachedPrefix(podcastIndexEpisodeID int64) (int64, *Source, error) {
+	entry := entryName(podcastIndexEpisodeID, "")
+	source, err := s.readMeta(entry)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil, nil
+		}
+		return 0, nil, err
+	}
+
+	var cached int64
+	for index := int64(0); cached < source.Size; index++ {
+		if _, err := os.Stat(s.chunkPath(entry, index)); err != nil {
+			break
+		}
+		cached = min(cached+s.chunkSize, source.Size)
+	}
+	return cached, source, nil
+}
+
+// copyChunk writes length bytes of a cached chunk starting at offset
+func (s *service) copyChunk(w io.Writer, entry string, index, offset, length int64) error {
+	file, err := os.Open(s.chunkPath(entry, index))
+	if err != nil {
+		return fmt.Errorf("opening chunk %d: %w", index, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(w, io.NewSectionReader(file, offset, length)); err != nil {
+		return fmt.Errorf("writing chunk %d: %w", index, err)
+	}
+	return nil
+}
+
+// ensureChunk fetches a chunk from the origin unless it is already on disk.
+// Concurrent callers for the same chunk wait on one fetch.
+func (s *service) ensureChunk(ctx context.Context, entry, sourceURL string, index int64) error {
+	path := s.chunkPath(entry, index)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s:%d", entry, index)
+	result := s.group.DoChan(key, func() (interface{}, error) {
+		// Detached from the caller so one client disconnecting does not fail the
+		// fetch for everyone else waiting on this chunk
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.fetchTimeout)
+		defer cancel()
+		return nil, s.fetchChunk(fetchCtx, entry, sourceURL, index)
+	})
+
+	select {
+	case res := <-result:
+		return res.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetchChunk downloads one aligned range from the origin and stores it
+func (s *service) fetchChunk(ctx context.Context, entry, sourceURL string, index int64) error {
+	policy := s.policies.For(sourceURL)
+	release, err := s.policies.Acquire(ctx, policy)
+	if err != nil {
+		return fmt.Errorf("waiting for origin slot: %w", err)
+	}
+	defer release()
+
+	resp, err := s.requestChunk(ctx, policy, sourceURL, index)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		return ErrRangeNotSupported
+	default:
+		return fmt.Errorf("%w: %d", ErrOriginStatus, resp.StatusCode)
+	}
+
+	total, err := parseContentRangeTotal(resp.Header.Get("Content-Range"))
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkSource(entry, &Source{
+		Size:        total,
+		ContentType: resp.Header.Get("Content-Type"),
+		ChunkSize:   s.chunkSize,
+		URL:         sourceURL,
+		ETag:        resp.Header.Get("ETag"),
+	}); err != nil {
+		return err
+	}
+
+	path := s.chunkPath(entry, index)
+	if err := writeAtomic(path, resp.Body); err != nil {
+		return fmt.Errorf("storing chunk %d: %w", index, err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		s.stored(entry, info.Size())
+	}
+
+	log.Printf("[DEBUG] Cached stream chunk %d for %s", index, entry)
+	return nil
+}
+
+// checkSource compares what the origin just answered with the entry's
+// metadata, writing the metadata for a new entry. When the enclosure has
+// changed since the entry was started, chunks of the two versions would be
+// spliced together, so the entry is discarded and ErrSourceChanged returned.
+func (s *service) checkSource(entry string, fetched *Source) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.readMeta(entry)
+	if err == nil {
+		if stored.URL == fetched.URL && stored.Size == fetched.Size && stored.ETag == fetched.ETag {
+			return nil
+		}
+		log.Printf("[INFO] Enclosure of stream cache entry %s changed, discarding it", entry)
+		s.removeEntry(entry)
+		return ErrSourceChanged
+	}
+
+	if err := os.MkdirAll(filepath.Join(s.directory, entry), 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	if err := s.writeMeta(entry, fetched); err != nil {
+		log.Printf("[WARN] Failed to store stream metadata for %s: %v", entry, err)
+	}
+	return nil
+}
+
+// invalidate discards an entry's chunks and metadata
+func (s *service) invalidate(entry string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeEntry(entry)
+}
+
+// removeEntry deletes an entry directory. Callers hold s.mu.
+func (s *service) removeEntry(entry string) {
+	if err := os.RemoveAll(filepath.Join(s.directory, entry)); err != nil {
+		log.Printf("[WARN] Failed to remove stream cache entry %s: %v", entry, err)
+	}
+	s.size = -1 // Rescanned on the next store
+}
+
+// touch marks an entry as just streamed; eviction removes the entries
+// touched longest ago first
+func (s *service) touch(entry string) {
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(s.directory, entry), now, now)
+}
+
+// stored accounts for a chunk written to entry and evicts other entries
+// while the cache is over its size bound
+func (s *service) stored(entry string, bytes int64) {
+	if s.maxSize <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size < 0 {
+		s.size = s.scanEntries(nil)
+	} else {
+		s.size += bytes
+	}
+	if s.size > s.maxSize {
+		s.evict(entry)
+	}
+}
+
+// cacheEntry is an entry directory found by scanEntries
+type cacheEntry struct {
+	name       string
+	size       int64
+	lastAccess time.Time
+}
+
+// scanEntries returns the bytes of cached chunks, listing entries into list
+// when it is not nil
+func (s *service) scanEntries(list *[]cacheEntry) int64 {
+	dirs, err := os.ReadDir(s.directory)
+	if err != nil {
+		return 0
+	}
+
+	var total int64
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		info, err := dir.Info()
+		if err != nil {
+			continue
+		}
+		entry := cacheEntry{name: dir.Name(), lastAccess: info.ModTime()}
+		files, _ := os.ReadDir(filepath.Join(s.directory, dir.Name()))
+		for _, file := range files {
+			if !strings.HasSuffix(file.Name(), ".chunk") {
+				continue
+			}
+			if fileInfo, err := file.Info(); err == nil {
+				entry.size += fileInfo.Size()
+			}
+		}
+		total += entry.size
+		if list != nil {
+			*list = append(*list, entry)
+		}
+	}
+	return total
+}
+
+// evict removes the least recently streamed entries, sparing keep, until the
+// cache fits its bound. Callers hold s.mu.
+func (s *service) evict(keep string) {
+	var entries []cacheEntry
+	s.size = s.scanEntries(&entries)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastAccess.Before(entries[j].lastAccess)
+	})
+
+	for _, entry := range entries {
+		if s.size <= s.maxSize {
+			break
+		}
+		if entry.name == keep {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.directory, entry.name)); err != nil {
+			log.Printf("[WARN] Failed to evict stream cache entry %s: %v", entry.name, err)
+			continue
+		}
+		s.size -= entry.size
+		log.Printf("[DEBUG] Evicted stream cache entry %s (%d bytes)", entry.name, entry.size)
+	}
+}
+
+// requestChunk issues the range request, retrying statuses the host policy allows
+func (s *service) requestChunk(ctx context.Context, policy download.HostPolicy, sourceURL string, index int64) (*http.Response, error) {
+	start := index * s.chunkSize
+	end := start + s.chunkSize - 1
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating origin request: %w", err)
+		}
+		policy.Apply(req)
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetching chunk %d: %w", index, err)
+		}
+		if attempt >= policy.Retries() || !policy.ShouldRetry(resp.StatusCode) {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		select {
+		case <-time.After(time.Duration(attempt+1) * policy.RetryBackoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// entryName names the cache directory of an episode's rendition: the episode
+// ID for its enclosure, with the variant appended for other renditions
+func entryName(podcastIndexEpisodeID int64, variant string) string {
+	name := strconv.FormatInt(podcastIndexEpisodeID, 10)
+	if variant != "" {
+		name += "-" + variant
+	}
+	return name
+}
+
+func (s *service) chunkPath(entry string, index int64) string {
+	return filepath.Join(s.directory, entry, fmt.Sprintf("%d.chunk", index))
+}
+
+func (s *service) metaPath(entry string) string {
+	return filepath.Join(s.directory, entry, "meta.json")
+}
+
+// errStaleEntry is returned by readMeta for entries written with another chunk size
+var errStaleEntry = errors.New("chunk size changed")
+
+// readMeta loads stored metadata, rejecting entries written with another chunk size
+func (s *service) readMeta(entry string) (*Source, error) {
+	data, err := os.ReadFile(s.metaPath(entry))
+	if err != nil {
+		return nil, err
+	}
+	var source Source
+	if err := json.Unmarshal(data, &source); err != nil {
+		return nil, err
+	}
+	if source.ChunkSize != s.chunkSize {
+		return nil, errStaleEntry
+	}
+	return &source, nil
+}
+
+func (s *service) writeMeta(entry string, source *Source) error {
+	data, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(s.metaPath(entry), strings.NewReader(string(data)))
+}
+
+// writeAtomic writes to a temporary file and renames it into place so readers
+// never see a partial chunk
+func writeAtomic(path string, r io.Reader) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".partial-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// parseContentRangeTotal extracts the complete length from "bytes 0-1023/4096"
+func parseContentRangeTotal(header string) (int64, error) {
+	slash := strings.LastIndex(header, "/")
+	if !strings.HasPrefix(header, "bytes ") || slash < 0 {
+		return 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	total, err := strconv.ParseInt(header[slash+1:], 10, 64)
+	if err != nil || total <= 0 {
+		return 0, fmt.Errorf("origin did not report a total length in Content-Range %q", header)
+	}
+	return total, nil
+}
@@ -0,0 +1,209 @@
+package streamcache
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrigin(t *testing.T, content []byte, ranges bool) (*httptest.Server, *int32) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "audio/mpeg")
+		if !ranges {
+			w.Write(content)
+			return
+		}
+		http.ServeContent(w, r, "audio.mp3", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func testContent(size int) []byte {
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	return content
+}
+
+func TestWriteRange_SpansChunksAndCaches(t *testing.T) {
+	content := testContent(100)
+	origin, hits := newOrigin(t, content, true)
+	svc := NewService(t.TempDir(), 16, 5*time.Second, nil)
+	ctx := context.Background()
+
+	source, err := svc.Stat(ctx, 1, "", origin.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), source.Size)
+	assert.Equal(t, "audio/mpeg", source.ContentType)
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.WriteRange(ctx, &buf, 1, "", origin.URL, 10, 40))
+	assert.Equal(t, content[10:41], buf.Bytes())
+
+	fetched := atomic.LoadInt32(hits)
+	buf.Reset()
+	require.NoError(t, svc.WriteRange(ctx, &buf, 1, "", origin.URL, 12, 35))
+	assert.Equal(t, content[12:36], buf.Bytes())
+	assert.Equal(t, fetched, atomic.LoadInt32(hits), "cached chunks should not hit the origin")
+
+	// The final, short chunk
+	buf.Reset()
+	require.NoError(t, svc.WriteRange(ctx, &buf, 1, "", origin.URL, 90, 99))
+	assert.Equal(t, content[90:], buf.Bytes())
+
+	assert.ErrorIs(t, svc.WriteRange(ctx, &buf, 1, "", origin.URL, 50, 100), ErrInvalidRange)
+}
+
+func TestWriteRange_CoalescesConcurrentFetches(t *testing.T) {
+	content := testContent(64)
+	origin, hits := newOrigin(t, content, true)
+	svc := NewService(t.TempDir(), 64, 5*time.Second, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			assert.NoError(t, svc.WriteRange(context.Background(), &buf, 2, "", origin.URL, 0, 63))
+			assert.Equal(t, content, buf.Bytes())
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(hits), int32(2))
+}
+
+func TestStat_OriginWithoutRangeSupport(t *testing.T) {
+	origin, _ := newOrigin(t, testContent(32), false)
+	svc := NewService(t.TempDir(), 16, 5*time.Second, nil)
+
+	_, err := svc.Stat(context.Background(), 3, "", origin.URL)
+	assert.ErrorIs(t, err, ErrRangeNotSupported)
+}
+
+func TestCachedPrefix_StopsAtFirstGap(t *testing.T) {
+	content := testContent(100)
+	origin, _ := newOrigin(t, content, true)
+	svc := NewService(t.TempDir(), 16, 5*time.Second, nil)
+	ctx := context.Background()
+
+	cached, source, err := svc.CachedPrefix(1)
+	require.NoError(t, err)
+	assert.Zero(t, cached)
+	assert.Nil(t, source)
+
+	// Chunks 0, 1 and 3 are cached; chunk 2 is missing
+	require.NoError(t, svc.WriteRange(ctx, &bytes.Buffer{}, 1, "", origin.URL, 0, 31))
+	require.NoError(t, svc.WriteRange(ctx, &bytes.Buffer{}, 1, "", origin.URL, 48, 50))
+	cached, source, err = svc.CachedPrefix(1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(32), cached)
+	assert.Equal(t, int64(100), source.Size)
+
+	require.NoError(t, svc.WriteRange(ctx, &bytes.Buffer{}, 1, "", origin.URL, 32, 99))
+	cached, _, err = svc.CachedPrefix(1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), cached)
+}
+
+func TestStat_DiscardsEntryAfterChunkSizeChange(t *testing.T) {
+	content := testContent(100)
+	origin, _ := newOrigin(t, content, true)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	_, err := NewService(dir, 16, 5*time.Second, nil).Stat(ctx, 1, "", origin.URL)
+	require.NoError(t, err)
+
+	svc := NewService(dir, 32, 5*time.Second, nil)
+	source, err := svc.Stat(ctx, 1, "", origin.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(32), source.ChunkSize)
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.WriteRange(ctx, &buf, 1, "", origin.URL, 0, 63))
+	assert.Equal(t, content[:64], buf.Bytes())
+}
+
+func TestStat_DiscardsEntryForNewEnclosureURL(t *testing.T) {
+	oldOrigin, _ := newOrigin(t, testContent(100), true)
+	newContent := bytes.Repeat([]byte{7}, 80)
+	newOrigin, _ := newOrigin(t, newContent, true)
+	svc := NewService(t.TempDir(), 16, 5*time.Second, nil)
+	ctx := context.Background()
+
+	require.NoError(t, svc.WriteRange(ctx, &bytes.Buffer{}, 1, "", oldOrigin.URL, 0, 99))
+
+	source, err := svc.Stat(ctx, 1, "", newOrigin.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(80), source.Size)
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.WriteRange(ctx, &buf, 1, "", newOrigin.URL, 0, 79))
+	assert.Equal(t, newContent, buf.Bytes())
+}
+
+func TestWriteRange_DiscardsEntryWhenETagChanges(t *testing.T) {
+	var mu sync.Mutex
+	content, etag := testContent(64), `"v1"`
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		body, tag := content, etag
+		mu.Unlock()
+		w.Header().Set("ETag", tag)
+		http.ServeContent(w, r, "audio.mp3", time.Time{}, bytes.NewReader(body))
+	}))
+	t.Cleanup(origin.Close)
+
+	svc := NewService(t.TempDir(), 16, 5*time.Second, nil)
+	ctx := context.Background()
+	require.NoError(t, svc.WriteRange(ctx, &bytes.Buffer{}, 1, "", origin.URL, 0, 15))
+
+	mu.Lock()
+	content, etag = bytes.Repeat([]byte{9}, 64), `"v2"`
+	mu.Unlock()
+
+	err := svc.WriteRange(ctx, &bytes.Buffer{}, 1, "", origin.URL, 16, 31)
+	assert.ErrorIs(t, err, ErrSourceChanged)
+
+	// The next request starts over on the new version only
+	var buf bytes.Buffer
+	require.NoError(t, svc.WriteRange(ctx, &buf, 1, "", origin.URL, 0, 63))
+	assert.Equal(t, content, buf.Bytes())
+}
+
+func TestWriteRange_EvictsLeastRecentlyStreamed(t *testing.T) {
+	origin, _ := newOrigin(t, testContent(64), true)
+	svc := NewService(t.TempDir(), 16, 5*time.Second, nil, WithMaxSize(140))
+	ctx := context.Background()
+
+	for _, id := range []int64{1, 2} {
+		require.NoError(t, svc.WriteRange(ctx, &bytes.Buffer{}, id, "", origin.URL, 0, 63))
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Streaming episode 1 again makes episode 2 the least recently used
+	_, err := svc.Stat(ctx, 1, "", origin.URL)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, svc.WriteRange(ctx, &bytes.Buffer{}, 3, "", origin.URL, 0, 15))
+
+	for id, want := range map[int64]int64{1: 64, 2: 0, 3: 16} {
+		cached, _, err := svc.CachedPrefix(id)
+		require.NoError(t, err)
+		assert.Equal(t, want, cached, "episode %d", id)
+	}
+}
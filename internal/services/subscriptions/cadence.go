@@ -0,0 +1,94 @@
+package subscriptions
+
+import (
+	"slices"
+	"time"
+)
+
+// Cadence estimation limits
+const (
+	cadenceMinIntervals = 3   // Intervals needed before a show's cadence is trusted
+	cadenceMaxIntervals = 20  // Most recent intervals considered, so format changes take over
+	cadenceRegularShare = 0.6 // Share of intervals that must be near the median
+	cadenceDormantAfter = 3   // Intervals without a release after which a show counts as dormant
+	cadenceMinInterval  = time.Hour
+)
+
+// Cadence is how often a podcast releases episodes, estimated from when its
+// stored episodes were published
+type Cadence struct {
+	Interval    time.Duration // Median time between releases
+	LastRelease time.Time
+}
+
+// EstimateCadence estimates a release cadence from publication times. It
+// reports false when there are too few releases, or the gaps between them are
+// too irregular for a prediction to be useful.
+func EstimateCadence(published []time.Time) (Cadence, bool) {
+	times := slices.Clone(published)
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+
+	var intervals []time.Duration
+	for i := 1; i < len(times); i++ {
+		// Episodes dropped together count as a single release
+		if gap := times[i].Sub(times[i-1]); gap > 0 {
+			intervals = append(intervals, gap)
+		}
+	}
+	if len(intervals) > cadenceMaxIntervals {
+		intervals = intervals[len(intervals)-cadenceMaxIntervals:]
+	}
+	if len(intervals) < cadenceMinIntervals {
+		return Cadence{}, false
+	}
+
+	sorted := slices.Clone(intervals)
+	slices.Sort(sorted)
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+	if median < cadenceMinInterval {
+		return Cadence{}, false
+	}
+
+	regular := 0
+	for _, interval := range intervals {
+		if interval >= median/2 && interval <= median*3/2 {
+			regular++
+		}
+	}
+	if float64(regular) < cadenceRegularShare*float64(len(intervals)) {
+		return Cadence{}, false
+	}
+
+	return Cadence{Interval: median, LastRelease: times[len(times)-1]}, true
+}
+
+// Predict returns the expected release times in [from, to) that are not
+// before now. A show that has gone several intervals without a release is
+// treated as dormant and predicts nothing.
+func (c Cadence) Predict(from, to, now time.Time) []time.Time {
+	if c.Interval <= 0 || now.Sub(c.LastRelease) > cadenceDormantAfter*c.Interval {
+		return nil
+	}
+	if from.Before(now) {
+		from = now
+	}
+
+	// Skip whole intervals up to the window instead of stepping through them
+	next := c.LastRelease.Add(c.Interval)
+	if next.Before(from) {
+		skipped := from.Sub(next) / c.Interval
+		next = next.Add(skipped * c.Interval)
+		if next.Before(from) {
+			next = next.Add(c.Interval)
+		}
+	}
+
+	var expected []time.Time
+	for ; next.Before(to); next = next.Add(c.Interval) {
+		expected = append(expected, next)
+	}
+	return expected
+}
@@ -0,0 +1,74 @@
+package subscriptions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func weekly(start time.Time, n int) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = start.AddDate(0, 0, 7*i)
+	}
+	return times
+}
+
+func TestEstimateCadence(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	t.Run("weekly show", func(t *testing.T) {
+		times := weekly(start, 8)
+		// A release a day late doesn't move the median
+		times[4] = times[4].Add(24 * time.Hour)
+		cadence, ok := EstimateCadence(times)
+		require.True(t, ok)
+		assert.Equal(t, week, cadence.Interval)
+		assert.Equal(t, times[7], cadence.LastRelease)
+	})
+
+	t.Run("too few releases", func(t *testing.T) {
+		_, ok := EstimateCadence(weekly(start, 3))
+		assert.False(t, ok)
+	})
+
+	t.Run("same-day drops count once", func(t *testing.T) {
+		times := append(weekly(start, 4), start, start.AddDate(0, 0, 7))
+		cadence, ok := EstimateCadence(times)
+		require.True(t, ok)
+		assert.Equal(t, week, cadence.Interval)
+	})
+
+	t.Run("irregular show", func(t *testing.T) {
+		days := []int{0, 1, 9, 10, 40, 41, 90}
+		times := make([]time.Time, len(days))
+		for i, d := range days {
+			times[i] = start.AddDate(0, 0, d)
+		}
+		_, ok := EstimateCadence(times)
+		assert.False(t, ok)
+	})
+}
+
+func TestCadencePredict(t *testing.T) {
+	last := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC) // A Monday
+	cadence := Cadence{Interval: 7 * 24 * time.Hour, LastRelease: last}
+	now := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 23, 9, 0, 0, 0, time.UTC)
+	// Nothing before now, and to is exclusive
+	assert.Equal(t, []time.Time{last.AddDate(0, 0, 7), last.AddDate(0, 0, 14)}, cadence.Predict(from, to, now))
+
+	// Ranges far ahead skip to the first release in them
+	from = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	to = from.AddDate(0, 0, 7)
+	assert.Equal(t, []time.Time{time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}, cadence.Predict(from, to, now))
+
+	// Several missed releases mean the show is dormant
+	later := last.AddDate(0, 0, 30)
+	assert.Empty(t, cadence.Predict(later, later.AddDate(0, 0, 30), later))
+}
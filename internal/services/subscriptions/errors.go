@@ -0,0 +1,20 @@
+package subscriptions
+
+import "errors"
+
+var (
+	// ErrSubscriptionNotFound is returned when the user isn't subscribed to the podcast
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+
+	// ErrAlreadySubscribed is returned when subscribing to a podcast twice
+	ErrAlreadySubscribed = errors.New("already subscribed")
+
+	// ErrInvalidSubscription is returned for folder or tag names that are too long, or too many tags
+	ErrInvalidSubscription = errors.New("invalid subscription")
+
+	// ErrInvalidCalendarRange is returned for a calendar range that is empty or too long
+	ErrInvalidCalendarRange = errors.New("invalid calendar range")
+
+	// ErrPodcastNotFound is returned when the podcast to subscribe to can't be found
+	ErrPodcastNotFound = errors.New("podcast not found")
+)
@@ -0,0 +1,177 @@
+package subscriptions
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// PodcastResolver finds a podcast by Podcast Index feed ID, fetching and
+// storing it when it isn't known locally yet
+type PodcastResolver interface {
+	GetPodcastByPodcastIndexID(ctx context.Context, piID int64) (*models.Podcast, error)
+}
+
+// Filter narrows a user's subscriptions. Empty fields match everything.
+type Filter struct {
+	Folder string // Exact folder name
+	Tag    string // Tag the subscription carries
+}
+
+// Changes updates a subscription's organization; nil fields are left alone
+type Changes struct {
+	Folder *string
+	Tags   *[]string
+}
+
+// FolderCount is a folder and how many subscriptions are in it
+type FolderCount struct {
+	Folder string `json:"folder"`
+	Count  int64  `json:"count"`
+}
+
+// EpisodeQuery pages through the episodes of a user's subscriptions
+type EpisodeQuery struct {
+	Filter
+	PublishedSince  time.Time // Zero for no lower bound
+	PublishedBefore time.Time // Zero for no upper bound
+	UnreadOnly      bool      // Leave out episodes the user marked read
+	Limit           int
+	Offset          int
+}
+
+// InboxQuery pages through the user's inbox
+type InboxQuery struct {
+	Filter
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// InboxItem is an inbox episode with its read state
+type InboxItem struct {
+	Episode models.Episode
+	ReadAt  *time.Time // Nil while unread
+}
+
+// Inbox is a page of the user's inbox
+type Inbox struct {
+	Items  []InboxItem
+	Total  int64 // Episodes matching the query
+	Unread int64 // Unread episodes in the whole inbox, regardless of filter
+}
+
+// CalendarEntry is a release on a user's calendar: an episode that came out,
+// or one expected from the show's cadence
+type CalendarEntry struct {
+	At      time.Time
+	Podcast *models.Podcast
+	Episode *models.Episode // Nil for expected releases
+}
+
+// ShowCadence is a subscribed podcast's estimated release cadence
+type ShowCadence struct {
+	Podcast *models.Podcast
+	Cadence
+}
+
+// Calendar is the releases of a user's subscriptions in a time range
+type Calendar struct {
+	Entries  []CalendarEntry // By time
+	Cadences []ShowCadence   // Subscribed podcasts regular enough to predict
+}
+
+// Service manages users' podcast subscriptions
+type Service interface {
+	// Subscribe subscribes the user to a podcast by Podcast Index feed ID
+	Subscribe(ctx context.Context, userID string, podcastIndexID int64, folder string, tags []string) (*models.Subscription, error)
+
+	// List returns the user's subscriptions matching filter, oldest first,
+	// with their podcasts loaded
+	List(ctx context.Context, userID string, filter Filter) ([]models.Subscription, error)
+
+	// Get returns the user's subscription to a podcast, with the podcast loaded
+	Get(ctx context.Context, userID string, podcastIndexID int64) (*models.Subscription, error)
+
+	// Update changes a subscription's folder or tags
+	Update(ctx context.Context, userID string, podcastIndexID int64, changes Changes) (*models.Subscription, error)
+
+	// Unsubscribe removes the user's subscription to a podcast
+	Unsubscribe(ctx context.Context, userID string, podcastIndexID int64) error
+
+	// Folders lists the user's folders by name with their subscription counts.
+	// Subscriptions without a folder are counted under "".
+	Folders(ctx context.Context, userID string) ([]FolderCount, error)
+
+	// Episodes returns stored episodes of the subscriptions matching the
+	// query's filter, newest first, and how many there are in total
+	Episodes(ctx context.Context, userID string, query EpisodeQuery) ([]models.Episode, int64, error)
+
+	// Inbox returns recent episodes of the user's subscriptions, newest first,
+	// with their read state. Episodes published longer ago than the inbox's
+	// maximum age are left out.
+	Inbox(ctx context.Context, userID string, query InboxQuery) (*Inbox, error)
+
+	// MarkRead marks inbox episodes read by Podcast Index episode ID, or the
+	// whole inbox when episodeIDs is empty. Episodes that aren't from the
+	// user's subscriptions or are already read are ignored. Returns how many
+	// were marked.
+	MarkRead(ctx context.Context, userID string, episodeIDs []int64) (int64, error)
+
+	// MarkUnread clears the read state of episodes and returns how many changed
+	MarkUnread(ctx context.Context, userID string, episodeIDs []int64) (int64, error)
+
+	// UnreadCount returns how many episodes in the user's inbox are unread
+	UnreadCount(ctx context.Context, userID string) (int64, error)
+
+	// Calendar returns releases of the subscriptions matching filter in
+	// [from, to): stored episodes published then, and future releases
+	// expected from each show's cadence. Returns ErrInvalidCalendarRange when
+	// the range is empty or longer than MaxCalendarRange.
+	Calendar(ctx context.Context, userID string, filter Filter, from, to time.Time) (*Calendar, error)
+}
+
+// Repository defines the interface for subscription persistence
+type Repository interface {
+	// Create returns ErrAlreadySubscribed when the user is already subscribed
+	Create(ctx context.Context, subscription *models.Subscription) error
+
+	// List returns the user's subscriptions matching filter, oldest first,
+	// with their podcasts loaded
+	List(ctx context.Context, userID string, filter Filter) ([]models.Subscription, error)
+
+	// Get returns the user's subscription to a podcast by Podcast Index feed
+	// ID, with the podcast loaded, or ErrSubscriptionNotFound
+	Get(ctx context.Context, userID string, podcastIndexID int64) (*models.Subscription, error)
+
+	// Save stores a subscription's folder and tags
+	Save(ctx context.Context, subscription *models.Subscription) error
+
+	// Delete removes the user's subscription to a podcast by Podcast Index
+	// feed ID, or returns ErrSubscriptionNotFound
+	Delete(ctx context.Context, userID string, podcastIndexID int64) error
+
+	// Folders counts the user's subscriptions per folder
+	Folders(ctx context.Context, userID string) ([]FolderCount, error)
+
+	// Episodes returns a page of episodes of matching subscriptions, newest first, and the total
+	Episodes(ctx context.Context, userID string, query EpisodeQuery) ([]models.Episode, int64, error)
+
+	// CountEpisodes counts episodes of matching subscriptions, ignoring the page
+	CountEpisodes(ctx context.Context, userID string, query EpisodeQuery) (int64, error)
+
+	// ReadTimes returns when the user read each of the episodes that is read
+	ReadTimes(ctx context.Context, userID string, episodeIDs []int64) (map[int64]time.Time, error)
+
+	// MarkRead marks unread episodes of matching subscriptions read at the
+	// given time, limited to episodeIDs when not empty, and returns how many
+	MarkRead(ctx context.Context, userID string, query EpisodeQuery, episodeIDs []int64, at time.Time) (int64, error)
+
+	// MarkUnread removes the user's read markers for episodes
+	MarkUnread(ctx context.Context, userID string, episodeIDs []int64) (int64, error)
+
+	// PublishTimes returns when each podcast's stored episodes since a time
+	// were published, by local podcast ID
+	PublishTimes(ctx context.Context, podcastIDs []uint, since time.Time) (map[uint][]time.Time, error)
+}
@@ -0,0 +1,211 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapes LIKE wildcards in a tag
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new subscription repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create stores a subscription. A concurrent subscribe to the same podcast
+// loses on the unique index and gets ErrAlreadySubscribed.
+func (r *repository) Create(ctx context.Context, subscription *models.Subscription) error {
+	err := r.db.WithContext(ctx).Create(subscription).Error
+	if err != nil && (errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "UNIQUE constraint failed")) {
+		return ErrAlreadySubscribed
+	}
+	return err
+}
+
+// List returns the user's subscriptions matching filter, oldest first
+func (r *repository) List(ctx context.Context, userID string, filter Filter) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := r.filtered(ctx, userID, filter).Preload("Podcast").Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Get returns the user's subscription to a podcast
+func (r *repository) Get(ctx context.Context, userID string, podcastIndexID int64) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := r.db.WithContext(ctx).Preload("Podcast").
+		Where("user_id = ? AND podcast_id IN (?)", userID, r.podcastIDs(podcastIndexID)).
+		First(&subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Save stores a subscription's folder and tags
+func (r *repository) Save(ctx context.Context, subscription *models.Subscription) error {
+	return r.db.WithContext(ctx).Model(subscription).
+		Updates(map[string]interface{}{"folder": subscription.Folder, "tags": subscription.Tags}).Error
+}
+
+// Delete removes the user's subscription to a podcast
+func (r *repository) Delete(ctx context.Context, userID string, podcastIndexID int64) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND podcast_id IN (?)", userID, r.podcastIDs(podcastIndexID)).
+		Delete(&models.Subscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// Folders counts the user's subscriptions per folder
+func (r *repository) Folders(ctx context.Context, userID string) ([]FolderCount, error) {
+	var folders []FolderCount
+	err := r.db.WithContext(ctx).Model(&models.Subscription{}).
+		Select("folder, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("folder").
+		Order("folder").
+		Scan(&folders).Error
+	return folders, err
+}
+
+// Episodes returns a page of episodes of matching subscriptions, newest first
+func (r *repository) Episodes(ctx context.Context, userID string, query EpisodeQuery) ([]models.Episode, int64, error) {
+	total, err := r.CountEpisodes(ctx, userID, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var page []models.Episode
+	err = r.episodes(ctx, userID, query).Order("published_at DESC").Order("id DESC").
+		Limit(query.Limit).Offset(query.Offset).
+		Find(&page).Error
+	return page, total, err
+}
+
+// CountEpisodes counts episodes of matching subscriptions
+func (r *repository) CountEpisodes(ctx context.Context, userID string, query EpisodeQuery) (int64, error) {
+	var total int64
+	err := r.episodes(ctx, userID, query).Count(&total).Error
+	return total, err
+}
+
+// ReadTimes returns when the user read each of the episodes that is read
+func (r *repository) ReadTimes(ctx context.Context, userID string, episodeIDs []int64) (map[int64]time.Time, error) {
+	times := make(map[int64]time.Time, len(episodeIDs))
+	if len(episodeIDs) == 0 {
+		return times, nil
+	}
+	var reads []models.EpisodeRead
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND podcast_index_episode_id IN ?", userID, episodeIDs).
+		Find(&reads).Error
+	for _, read := range reads {
+		times[read.PodcastIndexEpisodeID] = read.ReadAt
+	}
+	return times, err
+}
+
+// MarkRead marks unread episodes of matching subscriptions read
+func (r *repository) MarkRead(ctx context.Context, userID string, query EpisodeQuery, episodeIDs []int64, at time.Time) (int64, error) {
+	query.UnreadOnly = true
+	episodes := r.episodes(ctx, userID, query)
+	if len(episodeIDs) > 0 {
+		episodes = episodes.Where("podcast_index_id IN ?", episodeIDs)
+	}
+	var ids []int64
+	if err := episodes.Pluck("podcast_index_id", &ids).Error; err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	reads := make([]models.EpisodeRead, len(ids))
+	for i, id := range ids {
+		reads[i] = models.EpisodeRead{UserID: userID, PodcastIndexEpisodeID: id, ReadAt: at}
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(reads, 500)
+	return result.RowsAffected, result.Error
+}
+
+// MarkUnread removes the user's read markers for episodes
+func (r *repository) MarkUnread(ctx context.Context, userID string, episodeIDs []int64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND podcast_index_episode_id IN ?", userID, episodeIDs).
+		Delete(&models.EpisodeRead{})
+	return result.RowsAffected, result.Error
+}
+
+// PublishTimes returns when each podcast's stored episodes since a time were published
+func (r *repository) PublishTimes(ctx context.Context, podcastIDs []uint, since time.Time) (map[uint][]time.Time, error) {
+	times := make(map[uint][]time.Time, len(podcastIDs))
+	if len(podcastIDs) == 0 {
+		return times, nil
+	}
+	var rows []struct {
+		PodcastID   uint
+		PublishedAt time.Time
+	}
+	err := r.db.WithContext(ctx).Model(&models.Episode{}).
+		Select("podcast_id, published_at").
+		Where("podcast_id IN ? AND published_at >= ?", podcastIDs, since).
+		Order("published_at").
+		Scan(&rows).Error
+	for _, row := range rows {
+		times[row.PodcastID] = append(times[row.PodcastID], row.PublishedAt)
+	}
+	return times, err
+}
+
+// episodes selects episodes of the subscriptions matching query
+func (r *repository) episodes(ctx context.Context, userID string, query EpisodeQuery) *gorm.DB {
+	podcasts := r.filtered(ctx, userID, query.Filter).Select("podcast_id")
+	episodes := r.db.WithContext(ctx).Model(&models.Episode{}).Where("podcast_id IN (?)", podcasts)
+	if !query.PublishedSince.IsZero() {
+		episodes = episodes.Where("published_at >= ?", query.PublishedSince)
+	}
+	if !query.PublishedBefore.IsZero() {
+		episodes = episodes.Where("published_at < ?", query.PublishedBefore)
+	}
+	if query.UnreadOnly {
+		read := r.db.Model(&models.EpisodeRead{}).Select("podcast_index_episode_id").Where("user_id = ?", userID)
+		episodes = episodes.Where("podcast_index_id NOT IN (?)", read)
+	}
+	return episodes
+}
+
+// filtered selects the user's subscriptions matching filter
+func (r *repository) filtered(ctx context.Context, userID string, filter Filter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Subscription{}).Where("user_id = ?", userID)
+	if filter.Folder != "" {
+		query = query.Where("folder = ?", filter.Folder)
+	}
+	if filter.Tag != "" {
+		// Tags are stored comma-separated, so match the tag between commas
+		tag := likeEscaper.Replace(filter.Tag)
+		query = query.Where(`',' || tags || ',' LIKE ? ESCAPE '\'`, "%,"+tag+",%")
+	}
+	return query
+}
+
+// podcastIDs selects the local ID of the podcast with a Podcast Index feed ID
+func (r *repository) podcastIDs(podcastIndexID int64) *gorm.DB {
+	return r.db.Model(&models.Podcast{}).Select("id").Where("podcast_index_id = ?", podcastIndexID)
+}
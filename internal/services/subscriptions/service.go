@@ -0,0 +1,308 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Limits on how a subscription can be organized
+const (
+	MaxNameLength = 50 // Longest folder or tag name
+	MaxTags       = 10 // Tags per subscription
+)
+
+// DefaultInboxMaxAge is how long episodes stay in the inbox after publication
+const DefaultInboxMaxAge = 30 * 24 * time.Hour
+
+// Calendar limits
+const (
+	MaxCalendarRange    = 92 * 24 * time.Hour      // Longest range a calendar covers
+	maxCalendarEpisodes = 2000                     // Released episodes listed per calendar
+	cadenceHistory      = 2 * 365 * 24 * time.Hour // Publication history cadences are estimated from
+)
+
+// service implements the Service interface
+type service struct {
+	repo        Repository
+	podcasts    PodcastResolver
+	inboxMaxAge time.Duration
+	now         func() time.Time
+}
+
+// Option configures the subscription service
+type Option func(*service)
+
+// WithInboxMaxAge sets how long episodes stay in the inbox after publication
+func WithInboxMaxAge(maxAge time.Duration) Option {
+	return func(s *service) {
+		if maxAge > 0 {
+			s.inboxMaxAge = maxAge
+		}
+	}
+}
+
+// NewService creates a new subscription service
+func NewService(repo Repository, podcasts PodcastResolver, opts ...Option) Service {
+	s := &service{repo: repo, podcasts: podcasts, inboxMaxAge: DefaultInboxMaxAge, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Subscribe subscribes the user to a podcast, fetching it from Podcast Index
+// first if it isn't stored yet
+func (s *service) Subscribe(ctx context.Context, userID string, podcastIndexID int64, folder string, tags []string) (*models.Subscription, error) {
+	folder, err := normalizeFolder(folder)
+	if err != nil {
+		return nil, err
+	}
+	tagList, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Get(ctx, userID, podcastIndexID); err == nil {
+		return nil, ErrAlreadySubscribed
+	} else if !errors.Is(err, ErrSubscriptionNotFound) {
+		return nil, err
+	}
+
+	podcast, err := s.podcasts.GetPodcastByPodcastIndexID(ctx, podcastIndexID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %d: %v", ErrPodcastNotFound, podcastIndexID, err)
+	}
+
+	subscription := &models.Subscription{
+		UserID:    userID,
+		PodcastID: podcast.ID,
+		Folder:    folder,
+		Tags:      tagList,
+	}
+	if err := s.repo.Create(ctx, subscription); err != nil {
+		if errors.Is(err, ErrAlreadySubscribed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("creating subscription: %w", err)
+	}
+	subscription.Podcast = *podcast
+	return subscription, nil
+}
+
+// List returns the user's subscriptions matching filter, oldest first
+func (s *service) List(ctx context.Context, userID string, filter Filter) ([]models.Subscription, error) {
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+	return s.repo.List(ctx, userID, filter)
+}
+
+// Get returns the user's subscription to a podcast
+func (s *service) Get(ctx context.Context, userID string, podcastIndexID int64) (*models.Subscription, error) {
+	return s.repo.Get(ctx, userID, podcastIndexID)
+}
+
+// Update changes a subscription's folder or tags. An empty folder takes the
+// subscription out of its folder; an empty tag list clears its tags.
+func (s *service) Update(ctx context.Context, userID string, podcastIndexID int64, changes Changes) (*models.Subscription, error) {
+	subscription, err := s.repo.Get(ctx, userID, podcastIndexID)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.Folder != nil {
+		if subscription.Folder, err = normalizeFolder(*changes.Folder); err != nil {
+			return nil, err
+		}
+	}
+	if changes.Tags != nil {
+		if subscription.Tags, err = normalizeTags(*changes.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Save(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("updating subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// Unsubscribe removes the user's subscription to a podcast
+func (s *service) Unsubscribe(ctx context.Context, userID string, podcastIndexID int64) error {
+	return s.repo.Delete(ctx, userID, podcastIndexID)
+}
+
+// Folders lists the user's folders with their subscription counts
+func (s *service) Folders(ctx context.Context, userID string) ([]FolderCount, error) {
+	return s.repo.Folders(ctx, userID)
+}
+
+// Episodes returns stored episodes of the matching subscriptions, newest first
+func (s *service) Episodes(ctx context.Context, userID string, query EpisodeQuery) ([]models.Episode, int64, error) {
+	query.Tag = strings.ToLower(strings.TrimSpace(query.Tag))
+	return s.repo.Episodes(ctx, userID, query)
+}
+
+// Inbox returns a page of recent episodes of the user's subscriptions with
+// their read state, and the inbox's unread count
+func (s *service) Inbox(ctx context.Context, userID string, query InboxQuery) (*Inbox, error) {
+	since := s.inboxSince()
+	episodes, total, err := s.repo.Episodes(ctx, userID, EpisodeQuery{
+		Filter:         Filter{Folder: query.Folder, Tag: strings.ToLower(strings.TrimSpace(query.Tag))},
+		PublishedSince: since,
+		UnreadOnly:     query.UnreadOnly,
+		Limit:          query.Limit,
+		Offset:         query.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading inbox episodes: %w", err)
+	}
+
+	ids := make([]int64, len(episodes))
+	for i := range episodes {
+		ids[i] = episodes[i].PodcastIndexID
+	}
+	readTimes, err := s.repo.ReadTimes(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading read state: %w", err)
+	}
+
+	inbox := &Inbox{Items: make([]InboxItem, len(episodes)), Total: total}
+	for i := range episodes {
+		inbox.Items[i].Episode = episodes[i]
+		if readAt, ok := readTimes[episodes[i].PodcastIndexID]; ok {
+			inbox.Items[i].ReadAt = &readAt
+		}
+	}
+
+	if query.UnreadOnly && query.Filter == (Filter{}) {
+		inbox.Unread = total
+	} else if inbox.Unread, err = s.UnreadCount(ctx, userID); err != nil {
+		return nil, err
+	}
+	return inbox, nil
+}
+
+// MarkRead marks inbox episodes read, or the whole inbox when episodeIDs is empty
+func (s *service) MarkRead(ctx context.Context, userID string, episodeIDs []int64) (int64, error) {
+	query := EpisodeQuery{}
+	if len(episodeIDs) == 0 {
+		// Only the whole inbox; older episodes of the subscriptions stay unread
+		query.PublishedSince = s.inboxSince()
+	}
+	return s.repo.MarkRead(ctx, userID, query, episodeIDs, s.now())
+}
+
+// MarkUnread clears the read state of episodes
+func (s *service) MarkUnread(ctx context.Context, userID string, episodeIDs []int64) (int64, error) {
+	if len(episodeIDs) == 0 {
+		return 0, nil
+	}
+	return s.repo.MarkUnread(ctx, userID, episodeIDs)
+}
+
+// UnreadCount returns how many episodes in the user's inbox are unread
+func (s *service) UnreadCount(ctx context.Context, userID string) (int64, error) {
+	count, err := s.repo.CountEpisodes(ctx, userID, EpisodeQuery{PublishedSince: s.inboxSince(), UnreadOnly: true})
+	if err != nil {
+		return 0, fmt.Errorf("counting unread episodes: %w", err)
+	}
+	return count, nil
+}
+
+// Calendar returns released and expected episodes of the matching subscriptions in [from, to)
+func (s *service) Calendar(ctx context.Context, userID string, filter Filter, from, to time.Time) (*Calendar, error) {
+	if !to.After(from) || to.Sub(from) > MaxCalendarRange {
+		return nil, ErrInvalidCalendarRange
+	}
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+
+	subs, err := s.repo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("loading subscriptions: %w", err)
+	}
+	podcasts := make(map[uint]*models.Podcast, len(subs))
+	podcastIDs := make([]uint, 0, len(subs))
+	for i := range subs {
+		podcasts[subs[i].PodcastID] = &subs[i].Podcast
+		podcastIDs = append(podcastIDs, subs[i].PodcastID)
+	}
+
+	released, _, err := s.repo.Episodes(ctx, userID, EpisodeQuery{
+		Filter:          filter,
+		PublishedSince:  from,
+		PublishedBefore: to,
+		Limit:           maxCalendarEpisodes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading released episodes: %w", err)
+	}
+	calendar := &Calendar{Entries: make([]CalendarEntry, 0, len(released))}
+	for i := range released {
+		calendar.Entries = append(calendar.Entries, CalendarEntry{
+			At:      released[i].PublishedAt,
+			Podcast: podcasts[released[i].PodcastID],
+			Episode: &released[i],
+		})
+	}
+
+	now := s.now()
+	history, err := s.repo.PublishTimes(ctx, podcastIDs, now.Add(-cadenceHistory))
+	if err != nil {
+		return nil, fmt.Errorf("loading publication history: %w", err)
+	}
+	for _, podcastID := range podcastIDs {
+		cadence, ok := EstimateCadence(history[podcastID])
+		if !ok {
+			continue
+		}
+		calendar.Cadences = append(calendar.Cadences, ShowCadence{Podcast: podcasts[podcastID], Cadence: cadence})
+		for _, at := range cadence.Predict(from, to, now) {
+			calendar.Entries = append(calendar.Entries, CalendarEntry{At: at, Podcast: podcasts[podcastID]})
+		}
+	}
+
+	slices.SortStableFunc(calendar.Entries, func(a, b CalendarEntry) int {
+		return a.At.Compare(b.At)
+	})
+	return calendar, nil
+}
+
+// inboxSince returns the publication time episodes must be newer than to be in the inbox
+func (s *service) inboxSince() time.Time {
+	return s.now().Add(-s.inboxMaxAge)
+}
+
+// normalizeFolder trims a folder name and checks its length
+func normalizeFolder(folder string) (string, error) {
+	folder = strings.TrimSpace(folder)
+	if len(folder) > MaxNameLength {
+		return "", fmt.Errorf("%w: folder name longer than %d characters", ErrInvalidSubscription, MaxNameLength)
+	}
+	return folder, nil
+}
+
+// normalizeTags lowercases, trims and de-duplicates tags into the stored
+// comma-separated form, dropping empty ones
+func normalizeTags(tags []string) (string, error) {
+	seen := make(map[string]bool, len(tags))
+	kept := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > MaxNameLength || strings.Contains(tag, ",") {
+			return "", fmt.Errorf("%w: tag %q must be at most %d characters without commas", ErrInvalidSubscription, tag, MaxNameLength)
+		}
+		seen[tag] = true
+		kept = append(kept, tag)
+	}
+	if len(kept) > MaxTags {
+		return "", fmt.Errorf("%w: at most %d tags allowed", ErrInvalidSubscription, MaxTags)
+	}
+	return strings.Join(kept, ","), nil
+}
@@ -0,0 +1,376 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Episode{}, &models.Subscription{}, &models.EpisodeRead{}))
+	return db
+}
+
+// fakeResolver resolves feed IDs to podcasts already stored in the test database
+type fakeResolver struct {
+	db *gorm.DB
+}
+
+func (f *fakeResolver) GetPodcastByPodcastIndexID(ctx context.Context, piID int64) (*models.Podcast, error) {
+	var podcast models.Podcast
+	if err := f.db.Where("podcast_index_id = ?", piID).First(&podcast).Error; err != nil {
+		return nil, errors.New("not in podcast index")
+	}
+	return &podcast, nil
+}
+
+func setupService(t *testing.T) (Service, *gorm.DB) {
+	db := setupTestDB(t)
+	for _, id := range []int64{100, 200, 300} {
+		require.NoError(t, db.Create(&models.Podcast{PodcastIndexID: id, Title: "Show", FeedURL: fmt.Sprintf("https://example.com/%d.xml", id)}).Error)
+	}
+	return NewService(NewRepository(db), &fakeResolver{db: db}), db
+}
+
+func TestSubscribe(t *testing.T) {
+	svc, db := setupService(t)
+	ctx := context.Background()
+
+	sub, err := svc.Subscribe(ctx, "user-1", 100, " news ", []string{"Daily", "daily", " politics "})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), sub.Podcast.PodcastIndexID)
+	assert.Equal(t, "news", sub.Folder)
+	assert.Equal(t, []string{"daily", "politics"}, sub.TagList())
+
+	_, err = svc.Subscribe(ctx, "user-1", 100, "", nil)
+	assert.ErrorIs(t, err, ErrAlreadySubscribed)
+
+	// A subscribe racing past the existence check hits the unique index
+	var podcast models.Podcast
+	require.NoError(t, db.Where("podcast_index_id = ?", 100).First(&podcast).Error)
+	err = NewRepository(db).Create(ctx, &models.Subscription{UserID: "user-1", PodcastID: podcast.ID})
+	assert.ErrorIs(t, err, ErrAlreadySubscribed)
+
+	// Another user can subscribe to the same podcast
+	_, err = svc.Subscribe(ctx, "user-2", 100, "", nil)
+	assert.NoError(t, err)
+
+	_, err = svc.Subscribe(ctx, "user-1", 999, "", nil)
+	assert.ErrorIs(t, err, ErrPodcastNotFound)
+
+	_, err = svc.Subscribe(ctx, "user-1", 200, "", []string{"a,b"})
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+
+	tooMany := make([]string, MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a' + i))
+	}
+	_, err = svc.Subscribe(ctx, "user-1", 200, "", tooMany)
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+}
+
+func TestListFilters(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+
+	_, err := svc.Subscribe(ctx, "user-1", 100, "news", []string{"daily"})
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user-1", 200, "comedy", []string{"weekly", "daily_show"})
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user-1", 300, "", nil)
+	require.NoError(t, err)
+
+	all, err := svc.List(ctx, "user-1", Filter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	news, err := svc.List(ctx, "user-1", Filter{Folder: "news"})
+	require.NoError(t, err)
+	require.Len(t, news, 1)
+	assert.Equal(t, int64(100), news[0].Podcast.PodcastIndexID)
+
+	// Tags match whole names, case-insensitively, with LIKE wildcards escaped
+	daily, err := svc.List(ctx, "user-1", Filter{Tag: "DAILY"})
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, int64(100), daily[0].Podcast.PodcastIndexID)
+
+	wildcard, err := svc.List(ctx, "user-1", Filter{Tag: "daily%"})
+	require.NoError(t, err)
+	assert.Empty(t, wildcard)
+
+	other, err := svc.List(ctx, "user-2", Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestUpdateAndFolders(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+
+	_, err := svc.Subscribe(ctx, "user-1", 100, "news", []string{"daily"})
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user-1", 200, "news", nil)
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user-1", 300, "", nil)
+	require.NoError(t, err)
+
+	folder := "comedy"
+	sub, err := svc.Update(ctx, "user-1", 200, Changes{Folder: &folder})
+	require.NoError(t, err)
+	assert.Equal(t, "comedy", sub.Folder)
+
+	// Clearing tags leaves the folder alone
+	tags := []string{}
+	sub, err = svc.Update(ctx, "user-1", 100, Changes{Tags: &tags})
+	require.NoError(t, err)
+	assert.Equal(t, "news", sub.Folder)
+	assert.Empty(t, sub.TagList())
+
+	stored, err := svc.Get(ctx, "user-1", 100)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Tags)
+
+	_, err = svc.Update(ctx, "user-1", 999, Changes{Folder: &folder})
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+
+	folders, err := svc.Folders(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, []FolderCount{{Folder: "", Count: 1}, {Folder: "comedy", Count: 1}, {Folder: "news", Count: 1}}, folders)
+}
+
+func TestEpisodes(t *testing.T) {
+	svc, db := setupService(t)
+	ctx := context.Background()
+
+	_, err := svc.Subscribe(ctx, "user-1", 100, "news", nil)
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user-1", 200, "comedy", nil)
+	require.NoError(t, err)
+
+	var podcasts []models.Podcast
+	require.NoError(t, db.Order("podcast_index_id").Find(&podcasts).Error)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, podcast := range podcasts {
+		for j := 0; j < 2; j++ {
+			id := int64(1000 + i*10 + j)
+			require.NoError(t, db.Create(&models.Episode{
+				PodcastID:      podcast.ID,
+				PodcastIndexID: id,
+				GUID:           fmt.Sprint(id),
+				Title:          "Episode",
+				AudioURL:       "https://example.com/a.mp3",
+				PublishedAt:    base.Add(time.Duration(i*2+j) * time.Hour),
+			}).Error)
+		}
+	}
+
+	// Episodes of podcast 300 aren't in the feed since it isn't subscribed
+	episodes, total, err := svc.Episodes(ctx, "user-1", EpisodeQuery{Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	require.Len(t, episodes, 3)
+	assert.Equal(t, int64(1011), episodes[0].PodcastIndexID)
+	assert.Equal(t, int64(1010), episodes[1].PodcastIndexID)
+	assert.Equal(t, int64(1001), episodes[2].PodcastIndexID)
+
+	episodes, total, err = svc.Episodes(ctx, "user-1", EpisodeQuery{Filter: Filter{Folder: "news"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, episodes, 2)
+	assert.Equal(t, int64(1001), episodes[0].PodcastIndexID)
+
+	episodes, _, err = svc.Episodes(ctx, "user-1", EpisodeQuery{Limit: 10, Offset: 3})
+	require.NoError(t, err)
+	require.Len(t, episodes, 1)
+	assert.Equal(t, int64(1000), episodes[0].PodcastIndexID)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	svc, _ := setupService(t)
+	ctx := context.Background()
+
+	_, err := svc.Subscribe(ctx, "user-1", 100, "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Unsubscribe(ctx, "user-1", 100))
+	_, err = svc.Get(ctx, "user-1", 100)
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+	assert.ErrorIs(t, svc.Unsubscribe(ctx, "user-1", 100), ErrSubscriptionNotFound)
+
+	// Resubscribing after unsubscribing works
+	_, err = svc.Subscribe(ctx, "user-1", 100, "", nil)
+	assert.NoError(t, err)
+}
+
+func TestInbox(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var podcasts []*models.Podcast
+	for _, id := range []int64{100, 200, 300} {
+		podcast := &models.Podcast{PodcastIndexID: id, Title: "Show", FeedURL: fmt.Sprintf("https://example.com/%d.xml", id)}
+		require.NoError(t, db.Create(podcast).Error)
+		podcasts = append(podcasts, podcast)
+	}
+	addEpisode := func(podcast *models.Podcast, id int64, age time.Duration) {
+		require.NoError(t, db.Create(&models.Episode{
+			PodcastID:      podcast.ID,
+			PodcastIndexID: id,
+			GUID:           fmt.Sprint(id),
+			Title:          "Episode",
+			AudioURL:       "https://example.com/a.mp3",
+			PublishedAt:    now.Add(-age),
+		}).Error)
+	}
+	addEpisode(podcasts[0], 1, time.Hour)
+	addEpisode(podcasts[1], 2, 2*time.Hour)
+	addEpisode(podcasts[0], 3, 3*time.Hour)
+	addEpisode(podcasts[0], 4, 10*24*time.Hour) // Too old for the inbox
+	addEpisode(podcasts[2], 5, time.Minute)     // Not subscribed
+
+	svc := NewService(NewRepository(db), &fakeResolver{db: db}, WithInboxMaxAge(7*24*time.Hour))
+	svc.(*service).now = func() time.Time { return now }
+
+	_, err := svc.Subscribe(ctx, "user-1", 100, "news", nil)
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user-1", 200, "comedy", nil)
+	require.NoError(t, err)
+
+	inbox, err := svc.Inbox(ctx, "user-1", InboxQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), inbox.Total)
+	assert.Equal(t, int64(3), inbox.Unread)
+	require.Len(t, inbox.Items, 3)
+	assert.Equal(t, int64(1), inbox.Items[0].Episode.PodcastIndexID)
+	assert.Equal(t, int64(2), inbox.Items[1].Episode.PodcastIndexID)
+	assert.Equal(t, int64(3), inbox.Items[2].Episode.PodcastIndexID)
+	assert.Nil(t, inbox.Items[0].ReadAt)
+
+	// Episodes outside the user's subscriptions aren't marked
+	updated, err := svc.MarkRead(ctx, "user-1", []int64{2, 5})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	inbox, err = svc.Inbox(ctx, "user-1", InboxQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), inbox.Unread)
+	require.NotNil(t, inbox.Items[1].ReadAt)
+	assert.True(t, inbox.Items[1].ReadAt.Equal(now))
+
+	unread, err := svc.Inbox(ctx, "user-1", InboxQuery{UnreadOnly: true, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), unread.Total)
+	require.Len(t, unread.Items, 1)
+	assert.Equal(t, int64(1), unread.Items[0].Episode.PodcastIndexID)
+
+	// Folder filtering narrows the page but not the unread count
+	comedy, err := svc.Inbox(ctx, "user-1", InboxQuery{Filter: Filter{Folder: "comedy"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), comedy.Total)
+	assert.Equal(t, int64(2), comedy.Unread)
+
+	// Marking everything read only covers the inbox window
+	updated, err = svc.MarkRead(ctx, "user-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	count, err := svc.UnreadCount(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	var old int64
+	require.NoError(t, db.Model(&models.EpisodeRead{}).Where("podcast_index_episode_id = ?", 4).Count(&old).Error)
+	assert.Zero(t, old)
+
+	updated, err = svc.MarkUnread(ctx, "user-1", []int64{1, 4})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+	count, err = svc.UnreadCount(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Read state is per user
+	other, err := svc.Inbox(ctx, "user-2", InboxQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, other.Total)
+}
+
+func TestCalendar(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	var podcasts []*models.Podcast
+	for _, id := range []int64{100, 200} {
+		podcast := &models.Podcast{PodcastIndexID: id, Title: fmt.Sprint("Show ", id), FeedURL: fmt.Sprintf("https://example.com/%d.xml", id)}
+		require.NoError(t, db.Create(podcast).Error)
+		podcasts = append(podcasts, podcast)
+	}
+	// Show 100 releases every Monday at 09:00; show 200 has released twice
+	monday := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	id := int64(1)
+	for week := 0; week < 9; week++ {
+		require.NoError(t, db.Create(&models.Episode{
+			PodcastID: podcasts[0].ID, PodcastIndexID: id, GUID: fmt.Sprint(id),
+			Title: "Weekly", AudioURL: "https://example.com/a.mp3", PublishedAt: monday.AddDate(0, 0, 7*week),
+		}).Error)
+		id++
+	}
+	for _, day := range []int{3, 60} {
+		require.NoError(t, db.Create(&models.Episode{
+			PodcastID: podcasts[1].ID, PodcastIndexID: id, GUID: fmt.Sprint(id),
+			Title: "Sporadic", AudioURL: "https://example.com/a.mp3", PublishedAt: monday.AddDate(0, 0, day),
+		}).Error)
+		id++
+	}
+
+	svc := NewService(NewRepository(db), &fakeResolver{db: db})
+	svc.(*service).now = func() time.Time { return now }
+	_, err := svc.Subscribe(ctx, "user-1", 100, "", nil)
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user-1", 200, "misc", nil)
+	require.NoError(t, err)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)
+	calendar, err := svc.Calendar(ctx, "user-1", Filter{}, from, to)
+	require.NoError(t, err)
+
+	require.Len(t, calendar.Cadences, 1)
+	assert.Equal(t, int64(100), calendar.Cadences[0].Podcast.PodcastIndexID)
+	assert.Equal(t, 7*24*time.Hour, calendar.Cadences[0].Interval)
+
+	// Released: Mon Mar 2 (show 100) and Mar 6 (show 200); expected: Mon Mar 9 and Mar 16
+	require.Len(t, calendar.Entries, 4)
+	assert.Equal(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), calendar.Entries[0].At)
+	assert.NotNil(t, calendar.Entries[0].Episode)
+	assert.Equal(t, int64(200), calendar.Entries[1].Podcast.PodcastIndexID)
+	assert.NotNil(t, calendar.Entries[1].Episode)
+	assert.Equal(t, time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), calendar.Entries[2].At)
+	assert.Nil(t, calendar.Entries[2].Episode)
+	assert.Equal(t, int64(100), calendar.Entries[2].Podcast.PodcastIndexID)
+	assert.Equal(t, time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC), calendar.Entries[3].At)
+
+	misc, err := svc.Calendar(ctx, "user-1", Filter{Folder: "misc"}, from, to)
+	require.NoError(t, err)
+	require.Len(t, misc.Entries, 1)
+	assert.Empty(t, misc.Cadences)
+
+	_, err = svc.Calendar(ctx, "user-1", Filter{}, to, from)
+	assert.ErrorIs(t, err, ErrInvalidCalendarRange)
+	_, err = svc.Calendar(ctx, "user-1", Filter{}, from, from.Add(MaxCalendarRange+time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidCalendarRange)
+}
@@ -0,0 +1,176 @@
+package summary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+const systemPrompt = `You summarize podcast episode transcripts.
+Respond with a JSON object only, using this shape:
+{"summary": "<3-5 sentence summary>", "topics": [{"title": "<short topic title>", "description": "<one sentence>"}]}
+List topics in the order they are discussed. Use at most 10 topics.`
+
+// OpenAIConfig holds configuration for an OpenAI-compatible chat completions endpoint
+type OpenAIConfig struct {
+	APIURL          string
+	APIKey          string
+	Model           string
+	MaxOutputTokens int
+	Timeout         time.Duration
+}
+
+// OpenAIClient calls an OpenAI-compatible /chat/completions endpoint
+type OpenAIClient struct {
+	httpClient      *http.Client
+	apiURL          string
+	apiKey          string
+	model           string
+	maxOutputTokens int
+}
+
+// NewOpenAIClient creates a new OpenAI-compatible summarization client
+func NewOpenAIClient(cfg OpenAIConfig) *OpenAIClient {
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.openai.com/v1"
+	}
+	if cfg.Model == "" {
+		cfg.Model = "gpt-4o-mini"
+	}
+	if cfg.MaxOutputTokens <= 0 {
+		cfg.MaxOutputTokens = 800
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Minute
+	}
+
+	return &OpenAIClient{
+		httpClient:      &http.Client{Timeout: cfg.Timeout},
+		apiURL:          strings.TrimRight(cfg.APIURL, "/"),
+		apiKey:          cfg.APIKey,
+		model:           cfg.Model,
+		maxOutputTokens: cfg.MaxOutputTokens,
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+type summaryPayload struct {
+	Summary string                `json:"summary"`
+	Topics  []models.SummaryTopic `json:"topics"`
+}
+
+// Model returns the model name used for requests
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+// Summarize sends transcript text to the model and returns the parsed result
+func (c *OpenAIClient) Summarize(ctx context.Context, transcriptText string) (*Result, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: transcriptText},
+		},
+		MaxTokens:   c.maxOutputTokens,
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("summary API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var chatResp chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	// Usage is billed whether or not the answer is usable
+	result := &Result{
+		PromptTokens:     chatResp.Usage.PromptTokens,
+		CompletionTokens: chatResp.Usage.CompletionTokens,
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return result, ErrEmptyResponse
+	}
+
+	content := strings.TrimSpace(chatResp.Choices[0].Message.Content)
+	if content == "" {
+		return result, ErrEmptyResponse
+	}
+
+	var payload summaryPayload
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &payload); err != nil || payload.Summary == "" {
+		// Model ignored the JSON instruction; keep the prose as the summary
+		result.Summary = content
+		result.Topics = []models.SummaryTopic{}
+		return result, nil
+	}
+
+	result.Summary = payload.Summary
+	result.Topics = payload.Topics
+	if result.Topics == nil {
+		result.Topics = []models.SummaryTopic{}
+	}
+
+	return result, nil
+}
+
+// stripCodeFence removes a surrounding ``` block that some models wrap JSON in
+func stripCodeFence(content string) string {
+	if !strings.HasPrefix(content, "```") {
+		return content
+	}
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	return strings.TrimSpace(content)
+}
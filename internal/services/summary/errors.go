@@ -0,0 +1,17 @@
+package summary
+
+import "errors"
+
+var (
+	// ErrSummaryNotFound is returned when no summary exists for an episode
+	ErrSummaryNotFound = errors.New("summary not found")
+
+	// ErrEmptyTranscript is returned when there is no transcription text to summarize
+	ErrEmptyTranscript = errors.New("transcription text is empty")
+
+	// ErrTokenBudgetExceeded is returned when the daily token budget has been spent
+	ErrTokenBudgetExceeded = errors.New("summary token budget exceeded")
+
+	// ErrEmptyResponse is returned when the model returns no usable content
+	ErrEmptyResponse = errors.New("summary model returned an empty response")
+)
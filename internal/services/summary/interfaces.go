@@ -0,0 +1,58 @@
+package summary
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Service defines the interface for episode summary operations
+type Service interface {
+	// GetSummary retrieves a stored summary by Podcast Index episode ID
+	GetSummary(ctx context.Context, podcastIndexEpisodeID int64) (*models.EpisodeSummary, error)
+
+	// GenerateSummary summarizes a transcription and stores the result
+	GenerateSummary(ctx context.Context, podcastIndexEpisodeID int64, transcriptText string) (*models.EpisodeSummary, error)
+
+	// DeleteSummary removes a stored summary
+	DeleteSummary(ctx context.Context, podcastIndexEpisodeID int64) error
+}
+
+// Repository defines the interface for summary data persistence
+type Repository interface {
+	// GetByEpisodeID retrieves a summary by Podcast Index episode ID
+	GetByEpisodeID(ctx context.Context, podcastIndexEpisodeID int64) (*models.EpisodeSummary, error)
+
+	// Save creates or replaces the summary for an episode
+	Save(ctx context.Context, summary *models.EpisodeSummary) error
+
+	// Delete removes a summary by Podcast Index episode ID
+	Delete(ctx context.Context, podcastIndexEpisodeID int64) error
+
+	// RecordUsage appends the token usage of one model call
+	RecordUsage(ctx context.Context, usage *models.SummaryUsage) error
+
+	// TokensUsedSince returns the total prompt and completion tokens spent by
+	// model calls since the given time
+	TokensUsedSince(ctx context.Context, since time.Time) (int64, error)
+}
+
+// Client defines the interface for the LLM backend that produces summaries
+type Client interface {
+	// Summarize sends transcript text to the model and returns the parsed
+	// result. When the API answered but gave nothing usable, the error comes
+	// with a Result carrying the reported token usage.
+	Summarize(ctx context.Context, transcriptText string) (*Result, error)
+
+	// Model returns the model name used for requests
+	Model() string
+}
+
+// Result holds the output of a summarization request
+type Result struct {
+	Summary          string
+	Topics           []models.SummaryTopic
+	PromptTokens     int
+	CompletionTokens int
+}
@@ -0,0 +1,90 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new summary repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetByEpisodeID retrieves a summary by Podcast Index episode ID
+func (r *repository) GetByEpisodeID(ctx context.Context, podcastIndexEpisodeID int64) (*models.EpisodeSummary, error) {
+	var summary models.EpisodeSummary
+
+	result := r.db.WithContext(ctx).Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).First(&summary)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrSummaryNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &summary, nil
+}
+
+// Save creates or replaces the summary for an episode
+func (r *repository) Save(ctx context.Context, summary *models.EpisodeSummary) error {
+	if summary == nil {
+		return errors.New("summary cannot be nil")
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "podcast_index_episode_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"summary", "topics_data", "model", "prompt_tokens", "completion_tokens",
+			"truncated", "updated_at", "deleted_at",
+		}),
+	}).Create(summary).Error
+}
+
+// Delete removes a summary by Podcast Index episode ID
+func (r *repository) Delete(ctx context.Context, podcastIndexEpisodeID int64) error {
+	result := r.db.WithContext(ctx).Unscoped().Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).Delete(&models.EpisodeSummary{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSummaryNotFound
+	}
+
+	return nil
+}
+
+// RecordUsage appends the token usage of one model call
+func (r *repository) RecordUsage(ctx context.Context, usage *models.SummaryUsage) error {
+	if usage == nil {
+		return errors.New("usage cannot be nil")
+	}
+	return r.db.WithContext(ctx).Create(usage).Error
+}
+
+// TokensUsedSince returns the total prompt and completion tokens spent by
+// model calls since the given time
+func (r *repository) TokensUsedSince(ctx context.Context, since time.Time) (int64, error) {
+	var total int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.SummaryUsage{}).
+		Where("created_at >= ?", since).
+		Select("COALESCE(SUM(prompt_tokens + completion_tokens), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
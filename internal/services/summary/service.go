@@ -0,0 +1,160 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+const (
+	// DefaultMaxInputTokens caps how much transcription text is sent to the model
+	DefaultMaxInputTokens = 12000
+
+	// charsPerToken is a rough estimate used to budget input without a tokenizer
+	charsPerToken = 4
+)
+
+// service implements the Service interface
+type service struct {
+	repo             Repository
+	client           Client
+	maxInputTokens   int
+	dailyTokenBudget int64
+}
+
+// ServiceOption is a functional option for configuring the service
+type ServiceOption func(*service)
+
+// WithMaxInputTokens sets the maximum number of estimated tokens sent to the model
+func WithMaxInputTokens(max int) ServiceOption {
+	return func(s *service) {
+		if max > 0 {
+			s.maxInputTokens = max
+		}
+	}
+}
+
+// WithDailyTokenBudget sets the total tokens that may be spent in a rolling 24 hour window (0 = unlimited)
+func WithDailyTokenBudget(budget int64) ServiceOption {
+	return func(s *service) {
+		if budget >= 0 {
+			s.dailyTokenBudget = budget
+		}
+	}
+}
+
+// NewService creates a new summary service
+func NewService(repo Repository, client Client, opts ...ServiceOption) Service {
+	s := &service{
+		repo:           repo,
+		client:         client,
+		maxInputTokens: DefaultMaxInputTokens,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// GetSummary retrieves a stored summary by Podcast Index episode ID
+func (s *service) GetSummary(ctx context.Context, podcastIndexEpisodeID int64) (*models.EpisodeSummary, error) {
+	return s.repo.GetByEpisodeID(ctx, podcastIndexEpisodeID)
+}
+
+// GenerateSummary summarizes a transcription and stores the result
+func (s *service) GenerateSummary(ctx context.Context, podcastIndexEpisodeID int64, transcriptText string) (*models.EpisodeSummary, error) {
+	transcriptText = strings.TrimSpace(transcriptText)
+	if transcriptText == "" {
+		return nil, ErrEmptyTranscript
+	}
+
+	if s.dailyTokenBudget > 0 {
+		used, err := s.repo.TokensUsedSince(ctx, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return nil, fmt.Errorf("checking token budget: %w", err)
+		}
+		if used >= s.dailyTokenBudget {
+			log.Printf("[WARN] Summary token budget exhausted (%d/%d), skipping episode %d", used, s.dailyTokenBudget, podcastIndexEpisodeID)
+			return nil, ErrTokenBudgetExceeded
+		}
+	}
+
+	input, truncated := truncateToTokens(transcriptText, s.maxInputTokens)
+	if truncated {
+		log.Printf("[DEBUG] Transcription for episode %d truncated from %d to %d characters to fit input budget",
+			podcastIndexEpisodeID, len(transcriptText), len(input))
+	}
+
+	result, err := s.client.Summarize(ctx, input)
+	s.recordUsage(ctx, podcastIndexEpisodeID, input, result, err)
+	if err != nil {
+		return nil, fmt.Errorf("summarizing transcription: %w", err)
+	}
+
+	summary := &models.EpisodeSummary{
+		PodcastIndexEpisodeID: podcastIndexEpisodeID,
+		Summary:               result.Summary,
+		Model:                 s.client.Model(),
+		PromptTokens:          result.PromptTokens,
+		CompletionTokens:      result.CompletionTokens,
+		Truncated:             truncated,
+	}
+	if err := summary.SetTopics(result.Topics); err != nil {
+		return nil, fmt.Errorf("encoding topics: %w", err)
+	}
+
+	if err := s.repo.Save(ctx, summary); err != nil {
+		return nil, fmt.Errorf("saving summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// DeleteSummary removes a stored summary
+func (s *service) DeleteSummary(ctx context.Context, podcastIndexEpisodeID int64) error {
+	return s.repo.Delete(ctx, podcastIndexEpisodeID)
+}
+
+// recordUsage counts a model call against the token budget. Calls the API
+// reported no usage for (timeouts, error statuses) are charged the estimated
+// size of the prompt, since the provider may have billed it anyway.
+func (s *service) recordUsage(ctx context.Context, podcastIndexEpisodeID int64, input string, result *Result, callErr error) {
+	usage := &models.SummaryUsage{
+		PodcastIndexEpisodeID: podcastIndexEpisodeID,
+		Model:                 s.client.Model(),
+		Failed:                callErr != nil,
+	}
+	if result != nil && result.PromptTokens+result.CompletionTokens > 0 {
+		usage.PromptTokens = result.PromptTokens
+		usage.CompletionTokens = result.CompletionTokens
+	} else {
+		usage.PromptTokens = len(input) / charsPerToken
+		usage.Estimated = true
+	}
+
+	// Recorded even when the caller gave up, because the call was still made
+	if err := s.repo.RecordUsage(context.WithoutCancel(ctx), usage); err != nil {
+		log.Printf("[WARN] Failed to record summary token usage for episode %d: %v", podcastIndexEpisodeID, err)
+	}
+}
+
+// truncateToTokens cuts text to roughly maxTokens tokens, preferring a word boundary
+func truncateToTokens(text string, maxTokens int) (string, bool) {
+	maxChars := maxTokens * charsPerToken
+	if maxTokens <= 0 || len(text) <= maxChars {
+		return text, false
+	}
+
+	cut := text[:maxChars]
+	if idx := strings.LastIndexAny(cut, " \n\t"); idx > maxChars/2 {
+		cut = cut[:idx]
+	}
+
+	return strings.ToValidUTF8(cut, ""), true
+}
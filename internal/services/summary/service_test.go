@@ -0,0 +1,166 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.EpisodeSummary{}, &models.SummaryUsage{}))
+	return db
+}
+
+func newTestServer(t *testing.T, content string, lastUserMessage *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if lastUserMessage != nil {
+			*lastUserMessage = req.Messages[len(req.Messages)-1].Content
+		}
+
+		resp := map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": content}},
+			},
+			"usage": map[string]int{"prompt_tokens": 100, "completion_tokens": 50},
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestGenerateSummary_StoresSummaryAndTopics(t *testing.T) {
+	server := newTestServer(t, "```json\n{\"summary\":\"An episode about Go.\",\"topics\":[{\"title\":\"Intro\"},{\"title\":\"Generics\"}]}\n```", nil)
+	defer server.Close()
+
+	client := NewOpenAIClient(OpenAIConfig{APIURL: server.URL, APIKey: "test-key", Model: "test-model"})
+	svc := NewService(NewRepository(setupTestDB(t)), client)
+
+	created, err := svc.GenerateSummary(context.Background(), 42, "hello world")
+	require.NoError(t, err)
+	assert.Equal(t, "An episode about Go.", created.Summary)
+	assert.Equal(t, "test-model", created.Model)
+	assert.False(t, created.Truncated)
+
+	stored, err := svc.GetSummary(context.Background(), 42)
+	require.NoError(t, err)
+	topics, err := stored.Topics()
+	require.NoError(t, err)
+	require.Len(t, topics, 2)
+	assert.Equal(t, "Generics", topics[1].Title)
+	assert.Equal(t, 150, stored.PromptTokens+stored.CompletionTokens)
+
+	// Regenerating replaces the existing row
+	_, err = svc.GenerateSummary(context.Background(), 42, "hello again")
+	require.NoError(t, err)
+}
+
+func TestGenerateSummary_PlainTextFallback(t *testing.T) {
+	server := newTestServer(t, "Just a prose summary.", nil)
+	defer server.Close()
+
+	client := NewOpenAIClient(OpenAIConfig{APIURL: server.URL, APIKey: "test-key"})
+	svc := NewService(NewRepository(setupTestDB(t)), client)
+
+	created, err := svc.GenerateSummary(context.Background(), 1, "text")
+	require.NoError(t, err)
+	assert.Equal(t, "Just a prose summary.", created.Summary)
+
+	topics, err := created.Topics()
+	require.NoError(t, err)
+	assert.Empty(t, topics)
+}
+
+func TestGenerateSummary_TruncatesInput(t *testing.T) {
+	var sent string
+	server := newTestServer(t, `{"summary":"short"}`, &sent)
+	defer server.Close()
+
+	client := NewOpenAIClient(OpenAIConfig{APIURL: server.URL, APIKey: "test-key"})
+	svc := NewService(NewRepository(setupTestDB(t)), client, WithMaxInputTokens(10))
+
+	created, err := svc.GenerateSummary(context.Background(), 7, strings.Repeat("word ", 100))
+	require.NoError(t, err)
+	assert.True(t, created.Truncated)
+	assert.LessOrEqual(t, len(sent), 10*charsPerToken)
+}
+
+func TestGenerateSummary_TokenBudget(t *testing.T) {
+	server := newTestServer(t, `{"summary":"ok"}`, nil)
+	defer server.Close()
+
+	client := NewOpenAIClient(OpenAIConfig{APIURL: server.URL, APIKey: "test-key"})
+	svc := NewService(NewRepository(setupTestDB(t)), client, WithDailyTokenBudget(100))
+
+	_, err := svc.GenerateSummary(context.Background(), 1, "first")
+	require.NoError(t, err)
+
+	_, err = svc.GenerateSummary(context.Background(), 2, "second")
+	assert.True(t, errors.Is(err, ErrTokenBudgetExceeded))
+}
+
+func TestGenerateSummary_TokenBudgetCountsRegenerationsAndFailures(t *testing.T) {
+	server := newTestServer(t, `{"summary":"ok"}`, nil)
+	defer server.Close()
+
+	db := setupTestDB(t)
+	client := NewOpenAIClient(OpenAIConfig{APIURL: server.URL, APIKey: "test-key"})
+	svc := NewService(NewRepository(db), client, WithDailyTokenBudget(250))
+
+	// Each call spends 150 tokens; regenerating must not overwrite the first spend
+	_, err := svc.GenerateSummary(context.Background(), 1, "first")
+	require.NoError(t, err)
+	_, err = svc.GenerateSummary(context.Background(), 1, "again")
+	require.NoError(t, err)
+
+	_, err = svc.GenerateSummary(context.Background(), 1, "third")
+	assert.True(t, errors.Is(err, ErrTokenBudgetExceeded))
+
+	// A call the API rejects is charged its estimated prompt size
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	repo := NewRepository(setupTestDB(t))
+	svc = NewService(repo, NewOpenAIClient(OpenAIConfig{APIURL: failing.URL}))
+	_, err = svc.GenerateSummary(context.Background(), 2, strings.Repeat("word ", 100))
+	require.Error(t, err)
+
+	used, err := repo.TokensUsedSince(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(strings.TrimSpace(strings.Repeat("word ", 100)))/charsPerToken), used)
+}
+
+func TestGenerateSummary_EmptyTranscript(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)), NewOpenAIClient(OpenAIConfig{}))
+
+	_, err := svc.GenerateSummary(context.Background(), 1, "   ")
+	assert.True(t, errors.Is(err, ErrEmptyTranscript))
+}
+
+func TestGetSummary_NotFound(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)), NewOpenAIClient(OpenAIConfig{}))
+
+	_, err := svc.GetSummary(context.Background(), 99)
+	assert.True(t, errors.Is(err, ErrSummaryNotFound))
+}
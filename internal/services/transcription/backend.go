@@ -0,0 +1,399 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/spf13/viper"
+)
+
+// Backend names accepted by transcription.backend
+const (
+	BackendLocal         = "local"          // whisper.cpp on this machine
+	BackendOpenAI        = "openai"         // OpenAI or any OpenAI-compatible /audio/transcriptions API
+	BackendFasterWhisper = "faster_whisper" // whisper-asr-webservice style /asr server, e.g. on a GPU host
+)
+
+// ErrAudioTooLarge is returned when the audio exceeds what a remote backend accepts
+var ErrAudioTooLarge = errors.New("audio too large for transcription backend")
+
+// uploadBitrate is the opus bitrate of audio compressed to fit an upload
+// limit; plenty for speech, and a 25 MB limit then holds over two hours
+const uploadBitrate = "24k"
+
+// Encoder runs ffmpeg, for compressing audio before it is uploaded;
+// *ffmpeg.FFmpeg satisfies it
+type Encoder interface {
+	Run(ctx context.Context, args ...string) (string, error)
+}
+
+// Backend turns an audio file into a transcript
+type Backend interface {
+	// Name is one of the Backend* constants
+	Name() string
+	Transcribe(ctx context.Context, audioPath string, opts BackendOptions) (*BackendResult, error)
+}
+
+// BackendOptions are per-transcription settings
+type BackendOptions struct {
+	Model    string // Registry model name; only the local backend uses it
+	Language string
+}
+
+// BackendResult is a backend's transcript
+type BackendResult struct {
+	Text     string
+	Language string
+	Duration float64                    // Seconds of audio; 0 when the backend doesn't report it
+	Segments []models.TranscriptSegment // Empty when the backend returns untimed text
+	Model    string                     // Model that produced the text, recorded on the Transcription
+}
+
+// BackendError is an unsuccessful response from a remote backend
+type BackendError struct {
+	Backend    string
+	StatusCode int
+	Body       string
+}
+
+func (e *BackendError) Error() string {
+	return fmt.Sprintf("%s transcription failed with status %d: %s", e.Backend, e.StatusCode, e.Body)
+}
+
+// NewBackendFromConfig creates the backend named by transcription.backend.
+// The local backend resolves models through the registry, or uses only the
+// configured model_path when registry is nil. The OpenAI backend compresses
+// audio over its upload limit with encoder, when there is one.
+func NewBackendFromConfig(registry *ModelRegistry, encoder Encoder) (Backend, error) {
+	switch name := viper.GetString("transcription.backend"); name {
+	case "", BackendLocal:
+		whisper := WhisperFromConfig()
+		if registry == nil {
+			registry = SingleModelRegistry(whisper.ModelPath)
+		}
+		return &LocalBackend{Whisper: whisper, Models: registry}, nil
+	case BackendOpenAI:
+		backend := &OpenAIBackend{
+			BaseURL:        viper.GetString("transcription.openai.base_url"),
+			APIKey:         viper.GetString("transcription.openai.api_key"),
+			Model:          viper.GetString("transcription.openai.model"),
+			MaxUploadBytes: viper.GetInt64("transcription.openai.max_upload_bytes"),
+			Encoder:        encoder,
+			Client:         &http.Client{Timeout: viper.GetDuration("transcription.openai.timeout")},
+		}
+		if backend.BaseURL == "" || backend.Model == "" {
+			return nil, fmt.Errorf("transcription.openai.base_url and model are required for the %s backend", name)
+		}
+		return backend, nil
+	case BackendFasterWhisper:
+		backend := &FasterWhisperBackend{
+			URL:    viper.GetString("transcription.faster_whisper.url"),
+			Model:  viper.GetString("transcription.faster_whisper.model"),
+			Client: &http.Client{Timeout: viper.GetDuration("transcription.faster_whisper.timeout")},
+		}
+		if backend.URL == "" {
+			return nil, fmt.Errorf("transcription.faster_whisper.url is required for the %s backend", name)
+		}
+		return backend, nil
+	default:
+		return nil, fmt.Errorf("unknown transcription backend %q", name)
+	}
+}
+
+// LocalBackend runs whisper.cpp with a model from the registry
+type LocalBackend struct {
+	Whisper Whisper
+	Models  *ModelRegistry
+}
+
+// Name implements Backend
+func (b *LocalBackend) Name() string {
+	return BackendLocal
+}
+
+// Transcribe implements Backend. Missing models are installed first, so
+// registry errors (ErrUnknownModel, ErrModelNotInstalled, ErrModelDownload)
+// come back unchanged.
+func (b *LocalBackend) Transcribe(ctx context.Context, audioPath string, opts BackendOptions) (*BackendResult, error) {
+	// Checked first so a missing binary doesn't trigger a model download
+	if _, err := exec.LookPath(b.Whisper.Path); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrWhisperNotFound, b.Whisper.Path, err)
+	}
+
+	model := opts.Model
+	if model == "" {
+		model = b.Models.Default()
+	}
+	modelPath, err := b.Models.Path(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	whisper := b.Whisper
+	if opts.Language != "" {
+		whisper.Language = opts.Language
+	}
+	text, err := whisper.Transcribe(ctx, audioPath, modelPath)
+	if err != nil {
+		return nil, err
+	}
+	return &BackendResult{Text: text, Language: whisper.Language, Model: model}, nil
+}
+
+// OpenAIBackend uploads audio to an OpenAI-compatible transcription API. Most
+// self-hosted faster-whisper servers also speak this API.
+type OpenAIBackend struct {
+	BaseURL        string // e.g. https://api.openai.com/v1
+	APIKey         string
+	Model          string  // e.g. whisper-1
+	MaxUploadBytes int64   // Files above this are compressed, or refused if they still don't fit; 0 for no limit
+	Encoder        Encoder // Compresses oversized files to 16kHz mono opus; without it they are refused
+	Client         *http.Client
+}
+
+// Name implements Backend
+func (b *OpenAIBackend) Name() string {
+	return BackendOpenAI
+}
+
+// Transcribe implements Backend
+func (b *OpenAIBackend) Transcribe(ctx context.Context, audioPath string, opts BackendOptions) (*BackendResult, error) {
+	audioPath, cleanup, err := compressForUpload(ctx, b.Encoder, audioPath, b.MaxUploadBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	if err := checkUploadSize(audioPath, b.MaxUploadBytes); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{
+		"model":           b.Model,
+		"response_format": "verbose_json", // includes duration and segments
+	}
+	if opts.Language != "" {
+		fields["language"] = opts.Language
+	}
+	headers := http.Header{}
+	if b.APIKey != "" {
+		headers.Set("Authorization", "Bearer "+b.APIKey)
+	}
+
+	endpoint := strings.TrimSuffix(b.BaseURL, "/") + "/audio/transcriptions"
+	result, err := uploadAudio(ctx, b.Client, BackendOpenAI, endpoint, "file", audioPath, fields, headers)
+	if err != nil {
+		return nil, err
+	}
+	result.Model = b.Model
+	if opts.Language != "" {
+		result.Language = opts.Language // verbose_json names the language ("english") rather than coding it
+	}
+	return result, nil
+}
+
+// FasterWhisperBackend uploads audio to a whisper-asr-webservice compatible
+// server running the faster-whisper engine
+type FasterWhisperBackend struct {
+	URL    string // Server root, e.g. http://gpu-host:9000
+	Model  string // Label recorded on transcriptions; the server picks the actual model
+	Client *http.Client
+}
+
+// Name implements Backend
+func (b *FasterWhisperBackend) Name() string {
+	return BackendFasterWhisper
+}
+
+// Transcribe implements Backend
+func (b *FasterWhisperBackend) Transcribe(ctx context.Context, audioPath string, opts BackendOptions) (*BackendResult, error) {
+	query := url.Values{}
+	query.Set("task", "transcribe")
+	query.Set("output", "json")
+	query.Set("encode", "true") // let the server decode mp3 and friends with ffmpeg
+	if opts.Language != "" {
+		query.Set("language", opts.Language)
+	}
+
+	endpoint := strings.TrimSuffix(b.URL, "/") + "/asr?" + query.Encode()
+	result, err := uploadAudio(ctx, b.Client, BackendFasterWhisper, endpoint, "audio_file", audioPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	result.Model = b.Model
+	if result.Model == "" {
+		result.Model = BackendFasterWhisper
+	}
+	if result.Language == "" {
+		result.Language = opts.Language
+	}
+	return result, nil
+}
+
+// remoteTranscript is the JSON both remote APIs return
+type remoteTranscript struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language"`
+	Duration float64 `json:"duration"`
+	Segments []struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+}
+
+// uploadAudio streams the file as a multipart form and decodes the transcript
+func uploadAudio(ctx context.Context, client *http.Client, backend, endpoint, fileField, audioPath string, fields map[string]string, headers http.Header) (*BackendResult, error) {
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening audio: %w", err)
+	}
+	defer file.Close()
+
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeForm(form, fileField, filepath.Base(audioPath), file, fields))
+	}()
+	defer body.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s request: %w", backend, err)
+	}
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s transcription request: %w", backend, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return nil, fmt.Errorf("%w: %s rejected %s", ErrAudioTooLarge, backend, filepath.Base(audioPath))
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &BackendError{Backend: backend, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(message))}
+	}
+
+	var decoded remoteTranscript
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding %s response: %w", backend, err)
+	}
+
+	result := &BackendResult{
+		Text:     strings.TrimSpace(decoded.Text),
+		Language: decoded.Language,
+		Duration: decoded.Duration,
+		Segments: make([]models.TranscriptSegment, 0, len(decoded.Segments)),
+	}
+	for _, segment := range decoded.Segments {
+		result.Segments = append(result.Segments, models.TranscriptSegment{
+			Start: segment.Start,
+			End:   segment.End,
+			Text:  strings.TrimSpace(segment.Text),
+		})
+	}
+	if result.Duration == 0 && len(result.Segments) > 0 {
+		result.Duration = result.Segments[len(result.Segments)-1].End
+	}
+	log.Printf("[DEBUG] %s transcribed %s in %s", backend, filepath.Base(audioPath), time.Since(start).Round(time.Second))
+	return result, nil
+}
+
+func writeForm(form *multipart.Writer, fileField, fileName string, file io.Reader, fields map[string]string) error {
+	for key, value := range fields {
+		if err := form.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+	part, err := form.CreateFormFile(fileField, fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	return form.Close()
+}
+
+// compressForUpload re-encodes audio larger than limit as 16kHz mono opus in a
+// temp file, which cleanup removes. The processed rendition is uncompressed
+// PCM, so anything but a short episode needs this to fit an upload limit.
+// Audio already within the limit, or with no encoder to shrink it, is
+// returned as is.
+func compressForUpload(ctx context.Context, encoder Encoder, audioPath string, limit int64) (string, func(), error) {
+	noop := func() {}
+	if encoder == nil || limit <= 0 {
+		return audioPath, noop, nil
+	}
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return "", noop, fmt.Errorf("checking audio size: %w", err)
+	}
+	if info.Size() <= limit {
+		return audioPath, noop, nil
+	}
+
+	temp, err := os.CreateTemp("", "transcription_upload_*.ogg")
+	if err != nil {
+		return "", noop, fmt.Errorf("creating upload file: %w", err)
+	}
+	temp.Close()
+	cleanup := func() {
+		if err := os.Remove(temp.Name()); err != nil && !os.IsNotExist(err) {
+			log.Printf("[WARN] Failed to remove transcription upload %s: %v", temp.Name(), err)
+		}
+	}
+
+	output, err := encoder.Run(ctx,
+		"-i", audioPath,
+		"-vn",
+		"-ar", "16000",
+		"-ac", "1",
+		"-c:a", "libopus",
+		"-b:a", uploadBitrate,
+		"-y",
+		temp.Name(),
+	)
+	if err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("compressing audio for upload: %w\nOutput: %s", err, output)
+	}
+	return temp.Name(), cleanup, nil
+}
+
+func checkUploadSize(audioPath string, limit int64) error {
+	if limit <= 0 {
+		return nil
+	}
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return fmt.Errorf("checking audio size: %w", err)
+	}
+	if info.Size() > limit {
+		return fmt.Errorf("%w: %.1f MB exceeds the %.1f MB limit", ErrAudioTooLarge,
+			float64(info.Size())/(1024*1024), float64(limit)/(1024*1024))
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAudio(t *testing.T, size int) string {
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+	return path
+}
+
+func TestOpenAIBackend_Transcribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/audio/transcriptions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "whisper-1", r.FormValue("model"))
+		assert.Equal(t, "verbose_json", r.FormValue("response_format"))
+		assert.Equal(t, "en", r.FormValue("language"))
+
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, "episode.mp3", header.Filename)
+		assert.Len(t, data, 64)
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"text":     " Hello there. General Kenobi. ",
+			"language": "english",
+			"duration": 4.5,
+			"segments": []map[string]interface{}{
+				{"start": 0.0, "end": 2.0, "text": " Hello there."},
+				{"start": 2.0, "end": 4.5, "text": " General Kenobi."},
+			},
+		})
+	}))
+	defer server.Close()
+
+	backend := &OpenAIBackend{BaseURL: server.URL + "/v1/", APIKey: "secret", Model: "whisper-1"}
+	result, err := backend.Transcribe(context.Background(), writeAudio(t, 64), BackendOptions{Model: "base.en", Language: "en"})
+	require.NoError(t, err)
+
+	assert.Equal(t, &BackendResult{
+		Text:     "Hello there. General Kenobi.",
+		Language: "en",
+		Duration: 4.5,
+		Segments: []models.TranscriptSegment{
+			{Start: 0, End: 2, Text: "Hello there."},
+			{Start: 2, End: 4.5, Text: "General Kenobi."},
+		},
+		Model: "whisper-1",
+	}, result)
+}
+
+func TestOpenAIBackend_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid api key"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	backend := &OpenAIBackend{BaseURL: server.URL, Model: "whisper-1", MaxUploadBytes: 100}
+
+	_, err := backend.Transcribe(context.Background(), writeAudio(t, 101), BackendOptions{})
+	assert.ErrorIs(t, err, ErrAudioTooLarge, "checked before uploading")
+
+	_, err = backend.Transcribe(context.Background(), writeAudio(t, 100), BackendOptions{})
+	var backendErr *BackendError
+	require.ErrorAs(t, err, &backendErr)
+	assert.Equal(t, http.StatusUnauthorized, backendErr.StatusCode)
+	assert.Contains(t, backendErr.Body, "invalid api key")
+}
+
+// fakeEncoder stands in for ffmpeg, writing size bytes to the output path
+type fakeEncoder struct {
+	size int
+	args []string
+}
+
+func (e *fakeEncoder) Run(ctx context.Context, args ...string) (string, error) {
+	e.args = args
+	return "", os.WriteFile(args[len(args)-1], make([]byte, e.size), 0o644)
+}
+
+func TestOpenAIBackend_CompressesLongProcessedAudio(t *testing.T) {
+	var uploaded string
+	var uploadedSize int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		uploaded, uploadedSize = header.Filename, len(data)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"text": "A long episode."})
+	}))
+	defer server.Close()
+
+	// An hour of 16kHz mono PCM is ~115 MB; scaled down against a scaled down limit
+	processed := filepath.Join(t.TempDir(), "processed.wav")
+	require.NoError(t, os.WriteFile(processed, make([]byte, 4096), 0o644))
+	encoder := &fakeEncoder{size: 512}
+	backend := &OpenAIBackend{BaseURL: server.URL, Model: "whisper-1", MaxUploadBytes: 1024, Encoder: encoder}
+
+	result, err := backend.Transcribe(context.Background(), processed, BackendOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "A long episode.", result.Text)
+	assert.Contains(t, encoder.args, "libopus")
+	assert.Equal(t, ".ogg", filepath.Ext(uploaded))
+	assert.Equal(t, 512, uploadedSize)
+	_, err = os.Stat(encoder.args[len(encoder.args)-1])
+	assert.True(t, os.IsNotExist(err), "the compressed upload is removed")
+
+	// Audio that still doesn't fit after compressing is refused
+	encoder.size = 2048
+	_, err = backend.Transcribe(context.Background(), processed, BackendOptions{})
+	assert.ErrorIs(t, err, ErrAudioTooLarge)
+}
+
+func TestFasterWhisperBackend_Transcribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/asr", r.URL.Path)
+		assert.Equal(t, "json", r.URL.Query().Get("output"))
+		assert.Equal(t, "de", r.URL.Query().Get("language"))
+		_, _, err := r.FormFile("audio_file")
+		require.NoError(t, err)
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"text":     "Guten Tag.",
+			"segments": []map[string]interface{}{{"start": 0.5, "end": 1.5, "text": "Guten Tag."}},
+		})
+	}))
+	defer server.Close()
+
+	backend := &FasterWhisperBackend{URL: server.URL, Model: "large-v3"}
+	result, err := backend.Transcribe(context.Background(), writeAudio(t, 8), BackendOptions{Language: "de"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Guten Tag.", result.Text)
+	assert.Equal(t, "de", result.Language)
+	assert.Equal(t, "large-v3", result.Model)
+	assert.Equal(t, 1.5, result.Duration, "taken from the last segment when not reported")
+	assert.Len(t, result.Segments, 1)
+}
+
+func TestFasterWhisperBackend_TooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	}))
+	defer server.Close()
+
+	backend := &FasterWhisperBackend{URL: server.URL}
+	_, err := backend.Transcribe(context.Background(), writeAudio(t, 8), BackendOptions{})
+	assert.ErrorIs(t, err, ErrAudioTooLarge)
+}
+
+func TestLocalBackend_Transcribe(t *testing.T) {
+	missing := &LocalBackend{
+		Whisper: Whisper{Path: "/nonexistent/whisper-cli"},
+		Models:  NewModelRegistry(ModelConfig{Dir: t.TempDir(), Default: "base.en", AutoDownload: true, BaseURL: "http://127.0.0.1:1"}),
+	}
+	_, err := missing.Transcribe(context.Background(), "audio.wav", BackendOptions{})
+	assert.ErrorIs(t, err, ErrWhisperNotFound, "binary checked before any model download")
+
+	// true stands in for a whisper binary that prints nothing
+	backend := &LocalBackend{
+		Whisper: Whisper{Path: "true", Language: "en"},
+		Models:  NewModelRegistry(ModelConfig{Dir: t.TempDir(), Default: "base.en"}),
+	}
+
+	_, err = backend.Transcribe(context.Background(), "audio.wav", BackendOptions{Model: "huge"})
+	assert.ErrorIs(t, err, ErrUnknownModel)
+
+	_, err = backend.Transcribe(context.Background(), "audio.wav", BackendOptions{})
+	assert.ErrorIs(t, err, ErrModelNotInstalled)
+
+	require.NoError(t, os.WriteFile(backend.Models.LocalPath("small"), []byte("model"), 0o644))
+	result, err := backend.Transcribe(context.Background(), "audio.wav", BackendOptions{Model: "small", Language: "fr"})
+	require.NoError(t, err)
+	assert.Equal(t, &BackendResult{Language: "fr", Model: "small"}, result)
+}
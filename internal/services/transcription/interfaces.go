@@ -19,6 +19,18 @@ type TranscriptionService interface {
 
 	// ExistsTranscription checks if a transcription exists for an episode
 	ExistsTranscription(ctx context.Context, podcastIndexEpisodeID int64) (bool, error)
+
+	// BuildSearchIndex fills the search index from stored transcripts if that hasn't completed yet
+	BuildSearchIndex(ctx context.Context) error
+
+	// SearchTranscripts finds episodes whose transcripts contain every word of query
+	SearchTranscripts(ctx context.Context, query string, opts SearchOptions) ([]TranscriptMatch, error)
+
+	// SimilarEpisodes finds other episodes of the same podcast whose transcripts overlap the episode's
+	SimilarEpisodes(ctx context.Context, podcastIndexEpisodeID int64, opts SimilarOptions) (*SimilarResult, error)
+
+	// Outline returns the episode's transcript split into paragraphs and topical sections
+	Outline(ctx context.Context, podcastIndexEpisodeID int64) (*models.TranscriptOutline, error)
 }
 
 // Repository defines the interface for transcription data persistence
@@ -32,9 +44,33 @@ type Repository interface {
 	// Update updates an existing transcription
 	Update(ctx context.Context, transcription *models.Transcription) error
 
+	// SaveOutline stores an episode's outline, leaving the rest of the transcription as is
+	SaveOutline(ctx context.Context, podcastIndexEpisodeID int64, data []byte) error
+
 	// Delete removes a transcription
 	Delete(ctx context.Context, podcastIndexEpisodeID int64) error
 
 	// Exists checks if a transcription exists for an episode
 	Exists(ctx context.Context, podcastIndexEpisodeID int64) (bool, error)
+
+	// BuildSearchIndex creates the search index and backfills it, recording completion once done
+	BuildSearchIndex(ctx context.Context) error
+
+	// IndexTranscript replaces the episode's entries in the full-text search index
+	IndexTranscript(ctx context.Context, transcription *models.Transcription) error
+
+	// RemoveFromIndex drops the episode's entries from the search index
+	RemoveFromIndex(ctx context.Context, podcastIndexEpisodeID int64) error
+
+	// SearchEpisodes returns episodes with segments matching an FTS query, most matches first
+	SearchEpisodes(ctx context.Context, match string, limit, offset int) ([]EpisodeHits, error)
+
+	// MatchingSegments returns the matching segments of the given episodes
+	MatchingSegments(ctx context.Context, match string, episodeIDs []int64) ([]SegmentHit, error)
+
+	// EpisodesByID loads stored episodes with their podcasts, keyed by Podcast Index ID
+	EpisodesByID(ctx context.Context, episodeIDs []int64) (map[int64]*models.Episode, error)
+
+	// PodcastTranscriptions returns the transcriptions of a podcast's other episodes, newest episodes first
+	PodcastTranscriptions(ctx context.Context, podcastIndexFeedID, excludeEpisodeID int64, limit int) ([]models.Transcription, error)
 }
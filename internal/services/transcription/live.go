@@ -0,0 +1,193 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/streamcache"
+)
+
+var (
+	// ErrLiveBusy is returned when every live transcription slot is in use
+	ErrLiveBusy = errors.New("live transcription at capacity")
+
+	// ErrLiveIdle is returned when no new audio was cached for the idle timeout,
+	// usually because the listener paused or left
+	ErrLiveIdle = errors.New("no new audio cached")
+)
+
+// StreamedAudio is the part of the stream cache live transcription reads from
+type StreamedAudio interface {
+	CachedPrefix(podcastIndexEpisodeID int64) (int64, *streamcache.Source, error)
+	WriteRange(ctx context.Context, w io.Writer, podcastIndexEpisodeID int64, variant, sourceURL string, start, end int64) error
+}
+
+// SpeechDecoder decodes part of an audio stream to a 16kHz WAV for whisper
+type SpeechDecoder interface {
+	ExtractSpeech(ctx context.Context, r io.Reader, offset, duration float64, outputPath string) (float64, error)
+}
+
+// SegmentTranscriber turns a 16kHz WAV file into timed segments
+type SegmentTranscriber interface {
+	TranscribeSegments(ctx context.Context, wavPath string) ([]models.TranscriptSegment, error)
+}
+
+// LiveConfig tunes live transcription
+type LiveConfig struct {
+	ChunkDuration time.Duration // Audio transcribed per whisper run
+	PollInterval  time.Duration // Wait between checks for newly cached audio
+	IdleTimeout   time.Duration // Give up after this long without new audio
+	MaxSessions   int           // Concurrent live transcriptions; whisper is CPU bound
+	TempDir       string
+}
+
+// DefaultLiveConfig returns settings that keep captions about one chunk
+// behind the cached audio
+func DefaultLiveConfig() LiveConfig {
+	return LiveConfig{
+		ChunkDuration: 30 * time.Second,
+		PollInterval:  2 * time.Second,
+		IdleTimeout:   2 * time.Minute,
+		MaxSessions:   2,
+		TempDir:       os.TempDir(),
+	}
+}
+
+// Live transcribes the portion of an episode already in the stream cache,
+// chunk by chunk, so captions can be shown during the first playback
+type Live struct {
+	audio       StreamedAudio
+	decoder     SpeechDecoder
+	transcriber SegmentTranscriber
+	config      LiveConfig
+	sessions    chan struct{}
+}
+
+// NewLive creates a live transcriber
+func NewLive(audio StreamedAudio, decoder SpeechDecoder, transcriber SegmentTranscriber, config LiveConfig) *Live {
+	defaults := DefaultLiveConfig()
+	if config.ChunkDuration <= 0 {
+		config.ChunkDuration = defaults.ChunkDuration
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaults.IdleTimeout
+	}
+	if config.MaxSessions <= 0 {
+		config.MaxSessions = defaults.MaxSessions
+	}
+	if config.TempDir == "" {
+		config.TempDir = defaults.TempDir
+	}
+	return &Live{
+		audio:       audio,
+		decoder:     decoder,
+		transcriber: transcriber,
+		config:      config,
+		sessions:    make(chan struct{}, config.MaxSessions),
+	}
+}
+
+// Stream transcribes the episode from the given second onward, calling emit
+// with each chunk's segments in order. It follows the stream cache as the
+// listener plays: once the cached audio is used up it waits for more, and it
+// returns nil after the last chunk of the audio, ErrLiveIdle if the cache
+// stops growing, or the context's error when the caller goes away.
+func (l *Live) Stream(ctx context.Context, episodeID int64, sourceURL string, from float64, emit func([]models.TranscriptSegment) error) error {
+	select {
+	case l.sessions <- struct{}{}:
+		defer func() { <-l.sessions }()
+	default:
+		return ErrLiveBusy
+	}
+
+	dir, err := os.MkdirTemp(l.config.TempDir, "live-transcript-*")
+	if err != nil {
+		return fmt.Errorf("creating work directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	wavPath := filepath.Join(dir, "chunk.wav")
+
+	position := from
+	chunk := l.config.ChunkDuration.Seconds()
+	lastProgress := time.Now()
+	var attempted int64 // cached length that last yielded too little audio
+	for {
+		cached, source, err := l.audio.CachedPrefix(episodeID)
+		if err != nil {
+			return fmt.Errorf("checking cached audio: %w", err)
+		}
+		complete := source != nil && cached >= source.Size
+
+		var decoded float64
+		if cached > 0 && cached != attempted {
+			decoded, err = l.decode(ctx, episodeID, sourceURL, cached, position, chunk, wavPath)
+			if err != nil {
+				return err
+			}
+			attempted = cached
+		}
+
+		// A short chunk is only transcribed once nothing more can arrive;
+		// otherwise wait so whisper gets a full chunk of context
+		if decoded >= chunk || (complete && decoded > 0) {
+			segments, err := l.transcriber.TranscribeSegments(ctx, wavPath)
+			if err != nil {
+				return fmt.Errorf("transcribing from %.1fs: %w", position, err)
+			}
+			for i := range segments {
+				segments[i].Start += position
+				segments[i].End += position
+			}
+			if err := emit(segments); err != nil {
+				return err
+			}
+			position += decoded
+			lastProgress = time.Now()
+			attempted = 0
+			continue
+		}
+
+		if complete {
+			return nil
+		}
+		if time.Since(lastProgress) > l.config.IdleTimeout {
+			return ErrLiveIdle
+		}
+
+		select {
+		case <-time.After(l.config.PollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// decode pipes the cached bytes through the decoder and returns the seconds
+// of speech written from position onward
+func (l *Live) decode(ctx context.Context, episodeID int64, sourceURL string, cached int64, position, chunk float64, wavPath string) (float64, error) {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(l.audio.WriteRange(ctx, writer, episodeID, "", sourceURL, 0, cached-1))
+	}()
+	defer reader.Close()
+
+	decoded, err := l.decoder.ExtractSpeech(ctx, reader, position, chunk, wavPath)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, fmt.Errorf("decoding cached audio at %.1fs: %w", position, err)
+	}
+	log.Printf("[DEBUG] Decoded %.1fs of cached audio at %.1fs for live transcript of episode %d", decoded, position, episodeID)
+	return decoded, nil
+}
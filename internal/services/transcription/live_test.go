@@ -0,0 +1,127 @@
+package transcription
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/streamcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bytesPerSecond maps fake cached bytes to seconds of audio
+const bytesPerSecond = 100
+
+// fakeStreamedAudio is a stream cache whose prefix grows by step bytes on each check
+type fakeStreamedAudio struct {
+	mu     sync.Mutex
+	cached int64
+	step   int64
+	size   int64
+}
+
+func (f *fakeStreamedAudio) CachedPrefix(id int64) (int64, *streamcache.Source, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cached := f.cached
+	f.cached = min(f.cached+f.step, f.size)
+	return cached, &streamcache.Source{Size: f.size}, nil
+}
+
+func (f *fakeStreamedAudio) WriteRange(ctx context.Context, w io.Writer, id int64, variant, url string, start, end int64) error {
+	_, err := w.Write(make([]byte, end-start+1))
+	return err
+}
+
+// fakeDecoder reports as many seconds as the piped bytes cover past offset
+type fakeDecoder struct {
+	offsets []float64
+}
+
+func (f *fakeDecoder) ExtractSpeech(ctx context.Context, r io.Reader, offset, duration float64, outputPath string) (float64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	available := float64(len(data))/bytesPerSecond - offset
+	if available <= 0 {
+		return 0, nil
+	}
+	f.offsets = append(f.offsets, offset)
+	return min(available, duration), nil
+}
+
+type fakeSegmentTranscriber struct{}
+
+func (fakeSegmentTranscriber) TranscribeSegments(ctx context.Context, wavPath string) ([]models.TranscriptSegment, error) {
+	return []models.TranscriptSegment{{Start: 1, End: 2, Text: "words"}}, nil
+}
+
+func testLiveConfig(t *testing.T) LiveConfig {
+	return LiveConfig{
+		ChunkDuration: 10 * time.Second,
+		PollInterval:  time.Millisecond,
+		IdleTimeout:   time.Second,
+		MaxSessions:   1,
+		TempDir:       t.TempDir(),
+	}
+}
+
+func TestLiveStream_FollowsCachedAudio(t *testing.T) {
+	// 25 seconds of audio cached 5 seconds at a time
+	audio := &fakeStreamedAudio{step: 5 * bytesPerSecond, size: 25 * bytesPerSecond}
+	decoder := &fakeDecoder{}
+	live := NewLive(audio, decoder, fakeSegmentTranscriber{}, testLiveConfig(t))
+
+	var starts []float64
+	err := live.Stream(context.Background(), 1, "https://example.com/a.mp3", 0, func(segments []models.TranscriptSegment) error {
+		for _, segment := range segments {
+			starts = append(starts, segment.Start)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Two full 10s chunks, then the 5s tail once the audio is complete;
+	// segment times are shifted to the chunk's position in the episode
+	assert.Equal(t, []float64{1, 11, 21}, starts)
+}
+
+func TestLiveStream_StartsFromPosition(t *testing.T) {
+	audio := &fakeStreamedAudio{cached: 30 * bytesPerSecond, size: 30 * bytesPerSecond}
+	live := NewLive(audio, &fakeDecoder{}, fakeSegmentTranscriber{}, testLiveConfig(t))
+
+	var starts []float64
+	err := live.Stream(context.Background(), 1, "", 15, func(segments []models.TranscriptSegment) error {
+		starts = append(starts, segments[0].Start)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{16, 26}, starts)
+}
+
+func TestLiveStream_IdleAndBusy(t *testing.T) {
+	// Only 5 seconds ever cached out of 60
+	audio := &fakeStreamedAudio{cached: 5 * bytesPerSecond, size: 60 * bytesPerSecond}
+	config := testLiveConfig(t)
+	config.IdleTimeout = 20 * time.Millisecond
+	live := NewLive(audio, &fakeDecoder{}, fakeSegmentTranscriber{}, config)
+
+	noop := func([]models.TranscriptSegment) error { return nil }
+
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		close(started)
+		done <- live.Stream(context.Background(), 1, "", 0, noop)
+	}()
+	<-started
+	require.Eventually(t, func() bool { return len(live.sessions) == 1 }, time.Second, time.Millisecond)
+	assert.ErrorIs(t, live.Stream(context.Background(), 2, "", 0, noop), ErrLiveBusy)
+
+	assert.ErrorIs(t, <-done, ErrLiveIdle)
+}
@@ -0,0 +1,243 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	// ErrUnknownModel is returned for a model name that is neither a published
+	// whisper.cpp model nor installed locally
+	ErrUnknownModel = errors.New("unknown whisper model")
+
+	// ErrModelNotInstalled is returned when a model is missing and automatic
+	// download is off
+	ErrModelNotInstalled = errors.New("whisper model not installed")
+
+	// ErrModelDownload wraps failures to fetch a missing model
+	ErrModelDownload = errors.New("whisper model download failed")
+)
+
+// KnownModels are the published whisper.cpp GGML models that can be
+// downloaded by name. Larger models are slower but more accurate; the .en
+// variants are English-only and better at it.
+var KnownModels = []string{
+	"tiny", "tiny.en",
+	"base", "base.en",
+	"small", "small.en",
+	"medium", "medium.en",
+}
+
+// DefaultModelBaseURL hosts the published GGML models
+const DefaultModelBaseURL = "https://huggingface.co/ggerganov/whisper.cpp/resolve/main"
+
+// ModelConfig configures the model registry
+type ModelConfig struct {
+	Dir           string           // Directory holding ggml-<name>.bin files
+	Default       string           // Model used when neither the request nor the podcast picks one
+	PodcastModels map[int64]string // Podcast Index feed ID -> model name
+	AutoDownload  bool             // Fetch missing known models on first use
+	BaseURL       string           // Download location; DefaultModelBaseURL when empty
+}
+
+// ModelInfo describes one model in the registry
+type ModelInfo struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+	Default   bool   `json:"default"`
+	SizeBytes int64  `json:"size_bytes,omitempty"` // Size on disk when installed
+}
+
+// ModelRegistry resolves whisper model names to installed GGML files,
+// downloading missing ones when allowed
+type ModelRegistry struct {
+	config ModelConfig
+	client *http.Client
+	group  singleflight.Group
+}
+
+// NewModelRegistry creates a registry over config.Dir
+func NewModelRegistry(config ModelConfig) *ModelRegistry {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultModelBaseURL
+	}
+	return &ModelRegistry{config: config, client: &http.Client{}}
+}
+
+// SingleModelRegistry serves the model file at path as the default, from its
+// directory, without downloading
+func SingleModelRegistry(path string) *ModelRegistry {
+	return NewModelRegistry(ModelConfig{Dir: filepath.Dir(path), Default: ModelNameFromPath(path)})
+}
+
+// ModelFileName is the file a model is stored under
+func ModelFileName(name string) string {
+	return "ggml-" + name + ".bin"
+}
+
+// ModelNameFromPath returns the model name of a ggml-<name>.bin path
+func ModelNameFromPath(path string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "ggml-"), ".bin")
+}
+
+// Default returns the default model name
+func (r *ModelRegistry) Default() string {
+	return r.config.Default
+}
+
+// AutoDownload reports whether missing known models are fetched on first use
+func (r *ModelRegistry) AutoDownload() bool {
+	return r.config.AutoDownload
+}
+
+// Select picks the model for a transcription: the requested model, else the
+// podcast's configured model, else the default
+func (r *ModelRegistry) Select(requested string, feedID int64) string {
+	if requested != "" {
+		return requested
+	}
+	if name, ok := r.config.PodcastModels[feedID]; ok && name != "" {
+		return name
+	}
+	return r.config.Default
+}
+
+// Validate reports ErrUnknownModel for names that can't be installed
+func (r *ModelRegistry) Validate(name string) error {
+	if isKnownModel(name) || r.installed(name) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownModel, name)
+}
+
+// LocalPath returns where the model is or would be stored
+func (r *ModelRegistry) LocalPath(name string) string {
+	return filepath.Join(r.config.Dir, ModelFileName(name))
+}
+
+// Path returns the installed model file, downloading a missing known model
+// when auto-download is on. Concurrent callers share one download.
+func (r *ModelRegistry) Path(ctx context.Context, name string) (string, error) {
+	if err := r.Validate(name); err != nil {
+		return "", err
+	}
+	path := r.LocalPath(name)
+	if r.installed(name) {
+		return path, nil
+	}
+	if !r.config.AutoDownload {
+		return "", fmt.Errorf("%w: %s (expected at %s)", ErrModelNotInstalled, name, path)
+	}
+
+	result := r.group.DoChan(name, func() (interface{}, error) {
+		// Detached so one caller giving up doesn't abort a download others wait on
+		return nil, r.download(context.WithoutCancel(ctx), name)
+	})
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			return "", fmt.Errorf("%w: %w", ErrModelDownload, res.Err)
+		}
+		return path, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// List returns the known models and any other models installed in the
+// directory, sorted by name
+func (r *ModelRegistry) List() []ModelInfo {
+	names := make(map[string]bool, len(KnownModels))
+	for _, name := range KnownModels {
+		names[name] = true
+	}
+	if matches, err := filepath.Glob(filepath.Join(r.config.Dir, "ggml-*.bin")); err == nil {
+		for _, match := range matches {
+			names[ModelNameFromPath(match)] = true
+		}
+	}
+	names[r.config.Default] = true
+
+	models := make([]ModelInfo, 0, len(names))
+	for name := range names {
+		info := ModelInfo{Name: name, Default: name == r.config.Default}
+		if stat, err := os.Stat(r.LocalPath(name)); err == nil {
+			info.Installed = true
+			info.SizeBytes = stat.Size()
+		}
+		models = append(models, info)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return models
+}
+
+func (r *ModelRegistry) installed(name string) bool {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return false
+	}
+	_, err := os.Stat(r.LocalPath(name))
+	return err == nil
+}
+
+func isKnownModel(name string) bool {
+	for _, known := range KnownModels {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// download fetches a known model into the directory via a temporary file
+func (r *ModelRegistry) download(ctx context.Context, name string) error {
+	if !isKnownModel(name) {
+		return fmt.Errorf("%w: %s", ErrModelNotInstalled, name)
+	}
+	url := strings.TrimSuffix(r.config.BaseURL, "/") + "/" + ModelFileName(name)
+	log.Printf("[INFO] Downloading whisper model %s from %s", name, url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating model request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("downloading model %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading model %s: unexpected status %d", name, resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(r.config.Dir, 0o755); err != nil {
+		return fmt.Errorf("creating model directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(r.config.Dir, ".download-*")
+	if err != nil {
+		return fmt.Errorf("creating model file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing model %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), r.LocalPath(name)); err != nil {
+		return fmt.Errorf("installing model %s: %w", name, err)
+	}
+	log.Printf("[INFO] Installed whisper model %s (%.1f MB)", name, float64(written)/(1024*1024))
+	return nil
+}
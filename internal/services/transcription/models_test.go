@@ -0,0 +1,111 @@
+package transcription
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelRegistry_Select(t *testing.T) {
+	registry := NewModelRegistry(ModelConfig{
+		Default:       "base.en",
+		PodcastModels: map[int64]string{42: "small"},
+	})
+
+	assert.Equal(t, "tiny", registry.Select("tiny", 42), "request wins over podcast")
+	assert.Equal(t, "small", registry.Select("", 42))
+	assert.Equal(t, "base.en", registry.Select("", 7))
+}
+
+func TestModelRegistry_Validate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ggml-custom.bin"), []byte("model"), 0o644))
+	registry := NewModelRegistry(ModelConfig{Dir: dir})
+
+	assert.NoError(t, registry.Validate("medium.en"))
+	assert.NoError(t, registry.Validate("custom"), "installed models are valid even if unpublished")
+	assert.ErrorIs(t, registry.Validate("huge"), ErrUnknownModel)
+	assert.ErrorIs(t, registry.Validate("../custom"), ErrUnknownModel)
+	assert.ErrorIs(t, registry.Validate(""), ErrUnknownModel)
+}
+
+func TestModelRegistry_List(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ggml-base.en.bin"), []byte("model"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ggml-custom.bin"), []byte("model"), 0o644))
+	registry := NewModelRegistry(ModelConfig{Dir: dir, Default: "base.en"})
+
+	models := registry.List()
+	require.Len(t, models, len(KnownModels)+1)
+
+	byName := make(map[string]ModelInfo)
+	for _, model := range models {
+		byName[model.Name] = model
+	}
+	assert.Equal(t, ModelInfo{Name: "base.en", Installed: true, Default: true, SizeBytes: 5}, byName["base.en"])
+	assert.True(t, byName["custom"].Installed)
+	assert.False(t, byName["small"].Installed)
+	assert.Equal(t, "base", models[0].Name, "sorted by name")
+}
+
+func TestModelRegistry_PathDownloadsMissingModel(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/ggml-tiny.en.bin" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("weights"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	registry := NewModelRegistry(ModelConfig{Dir: dir, AutoDownload: true, BaseURL: server.URL})
+
+	path, err := registry.Path(context.Background(), "tiny.en")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "ggml-tiny.en.bin"), path)
+
+	content, err := os.ReadFile(filepath.Join(dir, "ggml-tiny.en.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, "weights", string(content))
+
+	// Installed now, so no further download
+	_, err = registry.Path(context.Background(), "tiny.en")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestModelRegistry_PathWithoutAutoDownload(t *testing.T) {
+	registry := NewModelRegistry(ModelConfig{Dir: t.TempDir()})
+
+	_, err := registry.Path(context.Background(), "small")
+	assert.ErrorIs(t, err, ErrModelNotInstalled)
+
+	_, err = registry.Path(context.Background(), "huge")
+	assert.ErrorIs(t, err, ErrUnknownModel)
+}
+
+func TestModelRegistry_PathDownloadFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	dir := t.TempDir()
+	registry := NewModelRegistry(ModelConfig{Dir: dir, AutoDownload: true, BaseURL: server.URL})
+
+	_, err := registry.Path(context.Background(), "tiny")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrModelDownload)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "partial downloads are removed")
+}
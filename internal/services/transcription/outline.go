@@ -0,0 +1,383 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// OutlineVersion identifies the segmentation below. Stored outlines built by
+// an older version are rebuilt when next requested.
+const OutlineVersion = 1
+
+const (
+	// paragraphPause is the silence between timed segments that starts a new paragraph
+	paragraphPause = 2.0
+
+	// targetParagraphWords ends a paragraph at the next sentence end once it is this long
+	targetParagraphWords = 80
+
+	// maxParagraphWords ends a paragraph mid-sentence once it is this long
+	maxParagraphWords = 200
+
+	// tilingBlock is how many paragraphs on either side of a gap are compared
+	// to score how much the topic shifts there
+	tilingBlock = 3
+
+	// minSectionParagraphs keeps sections from being too short to navigate by
+	minSectionParagraphs = 3
+
+	// sectionKeywords is how many keywords describe a section
+	sectionKeywords = 3
+)
+
+var (
+	sentenceEnd    = regexp.MustCompile(`([.?!]["')\]]*)\s+`)
+	paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+)
+
+// stopWords are left out of topic comparisons and keywords: function words and
+// the fillers of unscripted speech
+var stopWords = func() map[string]bool {
+	words := strings.Fields(`a about above actually after again all also am an and any are aren't around as at
+		back be because been before being below between both but by can can't could couldn't did didn't do does
+		doesn't doing don't down during each even every few for from further get gets getting go goes going gonna
+		got gotta had hadn't has hasn't have haven't having he he'd he'll he's her here here's hers herself him
+		himself his how how's i i'd i'll i'm i've if in into is isn't it it's its itself just kind know let let's
+		like little lot made make many maybe me mean might more most much must my myself need no nor not now of off
+		oh ok okay on once one only or other ought our ours ourselves out over own pretty probably quite really
+		right said same say saying says see she she'd she'll she's should shouldn't so some something sort still
+		such sure take talk talking than that that's the their theirs them themselves then there there's these
+		they they'd they'll they're they've things think this those though through thing to today too um uh
+		under until up us very want was wasn't way we we'd we'll we're we've well went were weren't what what's
+		when when's where where's which while who who's whom why why's will with won't would wouldn't yeah yes you
+		you'd you'll you're you've your yours yourself yourselves`)
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}()
+
+// Outline returns the episode's transcript outline, building and storing it
+// if it is missing or was built by an older segmentation
+func (s *Service) Outline(ctx context.Context, podcastIndexEpisodeID int64) (*models.TranscriptOutline, error) {
+	transcription, err := s.repo.GetByEpisodeID(ctx, podcastIndexEpisodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transcription: %w", err)
+	}
+	if transcription == nil {
+		return nil, ErrNoTranscript
+	}
+
+	if outline, err := transcription.Outline(); err == nil && outline != nil && outline.Version == OutlineVersion {
+		return outline, nil
+	}
+
+	outline, err := BuildOutline(transcription)
+	if err != nil {
+		return nil, err
+	}
+	if len(outline.Sections) == 0 {
+		return nil, ErrNoTranscript
+	}
+	if err := transcription.SetOutline(outline); err == nil {
+		if err := s.repo.SaveOutline(ctx, podcastIndexEpisodeID, transcription.OutlineData); err != nil {
+			log.Printf("[WARN] Failed to store transcript outline for episode %d: %v", podcastIndexEpisodeID, err)
+		}
+	}
+	return outline, nil
+}
+
+// setOutline builds and sets the outline of a transcription about to be
+// saved. The outline can always be rebuilt, so a failure only logs.
+func setOutline(transcription *models.Transcription) {
+	outline, err := BuildOutline(transcription)
+	if err == nil {
+		err = transcription.SetOutline(outline)
+	}
+	if err != nil {
+		log.Printf("[WARN] Failed to build transcript outline for episode %d: %v", transcription.PodcastIndexEpisodeID, err)
+		transcription.OutlineData = nil
+	}
+}
+
+// SaveOutline stores an episode's outline without touching the rest of the transcription
+func (r *repository) SaveOutline(ctx context.Context, podcastIndexEpisodeID int64, data []byte) error {
+	return r.db.WithContext(ctx).Model(&models.Transcription{}).
+		Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).
+		UpdateColumn("outline_data", data).Error
+}
+
+// BuildOutline splits a transcript into paragraphs, then groups the paragraphs
+// into topical sections with TextTiling: a section boundary goes where the
+// vocabulary of the paragraphs before a gap differs most from the paragraphs
+// after it. Timed segments are used when present, the plain text otherwise.
+func BuildOutline(transcription *models.Transcription) (*models.TranscriptOutline, error) {
+	segments, err := transcription.Segments()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode segments: %w", err)
+	}
+
+	outline := &models.TranscriptOutline{Version: OutlineVersion, Sections: []models.OutlineSection{}}
+	var paragraphs []models.OutlineParagraph
+	if len(segments) > 0 {
+		outline.Timed = true
+		paragraphs = timedParagraphs(segments)
+	} else {
+		paragraphs = textParagraphs(transcription.Text)
+	}
+	if len(paragraphs) == 0 {
+		return outline, nil
+	}
+
+	words := make([][]string, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		words[i] = contentWords(paragraph.Text)
+	}
+
+	bounds := sectionBoundaries(words)
+	sectionWords := make([][]string, 0, len(bounds)+1)
+	start := 0
+	for _, end := range append(bounds, len(paragraphs)) {
+		var all []string
+		for _, w := range words[start:end] {
+			all = append(all, w...)
+		}
+		sectionWords = append(sectionWords, all)
+		outline.Sections = append(outline.Sections, models.OutlineSection{
+			Start:      paragraphs[start].Start,
+			End:        paragraphs[end-1].End,
+			Paragraphs: paragraphs[start:end],
+		})
+		start = end
+	}
+
+	for i, keywords := range keywords(sectionWords) {
+		outline.Sections[i].Keywords = keywords
+		outline.Sections[i].Title = sectionTitle(keywords, i)
+	}
+	return outline, nil
+}
+
+// timedParagraphs groups segments into paragraphs, breaking at long pauses and
+// at sentence ends once a paragraph is long enough
+func timedParagraphs(segments []models.TranscriptSegment) []models.OutlineParagraph {
+	var paragraphs []models.OutlineParagraph
+	var texts []string
+	var current models.OutlineParagraph
+	words, lastEnd := 0, 0.0
+	flush := func() {
+		if len(texts) > 0 {
+			current.Text = strings.Join(texts, " ")
+			paragraphs = append(paragraphs, current)
+		}
+		texts, words = nil, 0
+	}
+
+	for _, segment := range segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		if len(texts) > 0 {
+			if segment.Start-lastEnd >= paragraphPause || endsSentence(texts[len(texts)-1]) && words >= targetParagraphWords || words >= maxParagraphWords {
+				flush()
+			}
+		}
+		if len(texts) == 0 {
+			current = models.OutlineParagraph{Start: segment.Start}
+		}
+		texts = append(texts, text)
+		current.End, lastEnd = segment.End, segment.End
+		words += len(strings.Fields(text))
+	}
+	flush()
+	return paragraphs
+}
+
+// textParagraphs keeps the paragraphs of an untimed transcript, splitting long
+// or unbroken ones by sentence the way timedParagraphs does
+func textParagraphs(text string) []models.OutlineParagraph {
+	var paragraphs []models.OutlineParagraph
+	for _, block := range paragraphBreak.Split(text, -1) {
+		var sentences []models.TranscriptSegment
+		for _, sentence := range strings.Split(sentenceEnd.ReplaceAllString(strings.TrimSpace(block), "$1\n"), "\n") {
+			sentences = append(sentences, models.TranscriptSegment{Text: sentence})
+		}
+		paragraphs = append(paragraphs, timedParagraphs(sentences)...)
+	}
+	return paragraphs
+}
+
+func endsSentence(text string) bool {
+	return strings.ContainsAny(text[len(text)-1:], `.?!"')]`)
+}
+
+// contentWords returns the lowercased words of text that can carry a topic
+func contentWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+	words := fields[:0]
+	for _, word := range fields {
+		word = strings.Trim(word, "'")
+		if len([]rune(word)) >= 3 && !stopWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// sectionBoundaries returns the paragraph indexes that start a new section.
+// Each gap between paragraphs is scored by how much less alike the blocks of
+// paragraphs on either side are than the most alike blocks nearby (its depth);
+// gaps deeper than the mean depth less half a standard deviation become
+// boundaries, deepest first, as long as sections keep minSectionParagraphs.
+func sectionBoundaries(words [][]string) []int {
+	n := len(words)
+	if n < 2*minSectionParagraphs {
+		return nil
+	}
+
+	// similarity[g] compares the blocks around the gap before paragraph g+1
+	similarity := make([]float64, n-1)
+	for g := range similarity {
+		before := termCounts(words[max(0, g+1-tilingBlock) : g+1])
+		after := termCounts(words[g+1 : min(n, g+1+tilingBlock)])
+		similarity[g] = cosine(before, after)
+	}
+
+	depth := make([]float64, len(similarity))
+	for g, sim := range similarity {
+		left, right := sim, sim
+		for i := g - 1; i >= 0 && similarity[i] >= left; i-- {
+			left = similarity[i]
+		}
+		for i := g + 1; i < len(similarity) && similarity[i] >= right; i++ {
+			right = similarity[i]
+		}
+		depth[g] = (left - sim) + (right - sim)
+	}
+
+	mean, variance := 0.0, 0.0
+	for _, d := range depth {
+		mean += d
+	}
+	mean /= float64(len(depth))
+	for _, d := range depth {
+		variance += (d - mean) * (d - mean)
+	}
+	cutoff := mean - math.Sqrt(variance/float64(len(depth)))/2
+
+	gaps := make([]int, len(depth))
+	for g := range gaps {
+		gaps[g] = g
+	}
+	sort.SliceStable(gaps, func(i, j int) bool { return depth[gaps[i]] > depth[gaps[j]] })
+
+	var bounds []int
+	for _, g := range gaps {
+		if depth[g] <= cutoff || depth[g] == 0 {
+			break
+		}
+		start := g + 1
+		if start < minSectionParagraphs || n-start < minSectionParagraphs {
+			continue
+		}
+		fits := true
+		for _, b := range bounds {
+			if start-b < minSectionParagraphs && b-start < minSectionParagraphs { // Closer than that either way
+				fits = false
+				break
+			}
+		}
+		if fits {
+			bounds = append(bounds, start)
+		}
+	}
+	sort.Ints(bounds)
+	return bounds
+}
+
+func termCounts(paragraphs [][]string) map[string]float64 {
+	counts := make(map[string]float64)
+	for _, words := range paragraphs {
+		for _, word := range words {
+			counts[word]++
+		}
+	}
+	return counts
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for word, count := range a {
+		dot += count * b[word]
+		normA += count * count
+	}
+	for _, count := range b {
+		normB += count * count
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// keywords returns each section's most particular words: frequent in the
+// section and rare in the others
+func keywords(sections [][]string) [][]string {
+	counts := make([]map[string]float64, len(sections))
+	inSections := make(map[string]int)
+	for i, words := range sections {
+		counts[i] = termCounts([][]string{words})
+		for word := range counts[i] {
+			inSections[word]++
+		}
+	}
+
+	result := make([][]string, len(sections))
+	for i := range sections {
+		type scored struct {
+			word  string
+			score float64
+		}
+		var ranked []scored
+		for word, count := range counts[i] {
+			if count < 2 && len(sections[i]) > 50 {
+				continue // A word said once says little about a long section
+			}
+			idf := 1 + math.Log(float64(len(sections))/float64(inSections[word]))
+			ranked = append(ranked, scored{word, count * idf})
+		}
+		sort.Slice(ranked, func(a, b int) bool {
+			if ranked[a].score != ranked[b].score {
+				return ranked[a].score > ranked[b].score
+			}
+			return ranked[a].word < ranked[b].word
+		})
+		result[i] = []string{}
+		for _, r := range ranked[:min(sectionKeywords, len(ranked))] {
+			result[i] = append(result[i], r.word)
+		}
+	}
+	return result
+}
+
+// sectionTitle names a section after its keywords, or its position without any
+func sectionTitle(keywords []string, index int) string {
+	if len(keywords) == 0 {
+		return fmt.Sprintf("Part %d", index+1)
+	}
+	title := []rune(strings.Join(keywords, ", "))
+	title[0] = unicode.ToUpper(title[0])
+	return string(title)
+}
@@ -0,0 +1,116 @@
+package transcription
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	cookingTalk = "The sourdough starter needs flour and water every morning. Bread dough rises slowly overnight in the kitchen."
+	spaceTalk   = "The rocket launch carried a telescope into orbit. Astronomers pointed the telescope at distant galaxies and planets."
+)
+
+// pausedSegments returns one segment per text with a three second pause after each
+func pausedSegments(texts ...string) []models.TranscriptSegment {
+	segments := make([]models.TranscriptSegment, len(texts))
+	for i, text := range texts {
+		start := float64(i * 13)
+		segments[i] = models.TranscriptSegment{Start: start, End: start + 10, Text: text}
+	}
+	return segments
+}
+
+func TestBuildOutline_Timed(t *testing.T) {
+	texts := []string{cookingTalk, cookingTalk, cookingTalk, cookingTalk, spaceTalk, spaceTalk, spaceTalk, spaceTalk}
+	transcription := &models.Transcription{PodcastIndexEpisodeID: 1}
+	require.NoError(t, transcription.SetSegments(pausedSegments(texts...)))
+
+	outline, err := BuildOutline(transcription)
+	require.NoError(t, err)
+	assert.True(t, outline.Timed)
+	assert.Equal(t, OutlineVersion, outline.Version)
+	require.Len(t, outline.Sections, 2)
+
+	cooking, space := outline.Sections[0], outline.Sections[1]
+	assert.Len(t, cooking.Paragraphs, 4, "each pause starts a paragraph")
+	assert.Equal(t, 0.0, cooking.Start)
+	assert.Equal(t, 49.0, cooking.End)
+	assert.Equal(t, 52.0, space.Start)
+	assert.Equal(t, []string{"bread", "dough", "flour"}, cooking.Keywords, "equally telling words go alphabetically")
+	assert.Equal(t, "telescope", space.Keywords[0], "the most repeated word leads")
+	assert.True(t, strings.HasPrefix(space.Title, "Telescope, "), space.Title)
+}
+
+func TestBuildOutline_Paragraphs(t *testing.T) {
+	// Contiguous segments run into one paragraph until it is long and a sentence ends
+	long := strings.Repeat("word ", 50) + "end."
+	transcription := &models.Transcription{}
+	require.NoError(t, transcription.SetSegments([]models.TranscriptSegment{
+		{Start: 0, End: 10, Text: long},
+		{Start: 10, End: 20, Text: "and more"},
+		{Start: 20, End: 30, Text: long},
+		{Start: 30, End: 40, Text: "next paragraph"},
+		{Start: 40, End: 41, Text: "  "},
+	}))
+
+	outline, err := BuildOutline(transcription)
+	require.NoError(t, err)
+	require.Len(t, outline.Sections, 1, "too few paragraphs to split into sections")
+	paragraphs := outline.Sections[0].Paragraphs
+	require.Len(t, paragraphs, 2)
+	assert.Equal(t, 30.0, paragraphs[0].End)
+	assert.Equal(t, "next paragraph", paragraphs[1].Text)
+	assert.Equal(t, 40.0, paragraphs[1].End, "blank segments are dropped")
+}
+
+func TestBuildOutline_Untimed(t *testing.T) {
+	text := strings.Join([]string{cookingTalk, cookingTalk, cookingTalk, spaceTalk, spaceTalk, spaceTalk}, "\n\n")
+	outline, err := BuildOutline(&models.Transcription{Text: text})
+	require.NoError(t, err)
+	assert.False(t, outline.Timed)
+	require.Len(t, outline.Sections, 2)
+	assert.Len(t, outline.Sections[0].Paragraphs, 3, "blank lines end paragraphs")
+	assert.Equal(t, cookingTalk, outline.Sections[0].Paragraphs[0].Text)
+	assert.Zero(t, outline.Sections[1].Start)
+
+	outline, err = BuildOutline(&models.Transcription{Text: "Um, yeah. So, like, you know?"})
+	require.NoError(t, err)
+	require.Len(t, outline.Sections, 1)
+	assert.Equal(t, "Part 1", outline.Sections[0].Title, "fillers make no keywords")
+
+	outline, err = BuildOutline(&models.Transcription{Text: "  \n\n "})
+	require.NoError(t, err)
+	assert.Empty(t, outline.Sections)
+}
+
+func TestOutline(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	_, err := svc.Outline(ctx, 1)
+	assert.ErrorIs(t, err, ErrNoTranscript)
+
+	transcription := &models.Transcription{PodcastIndexEpisodeID: 1}
+	require.NoError(t, transcription.SetSegments(pausedSegments(cookingTalk, spaceTalk)))
+	require.NoError(t, svc.SaveTranscription(ctx, transcription))
+
+	var stored models.Transcription
+	require.NoError(t, db.Where("podcast_index_episode_id = ?", 1).First(&stored).Error)
+	assert.NotEmpty(t, stored.OutlineData, "the outline is built on save")
+
+	// Transcripts stored before outlines existed get one on first request
+	require.NoError(t, db.Model(&stored).UpdateColumn("outline_data", nil).Error)
+	outline, err := svc.Outline(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, outline.Sections, 1)
+	assert.Len(t, outline.Sections[0].Paragraphs, 2)
+
+	require.NoError(t, db.Where("podcast_index_episode_id = ?", 1).First(&stored).Error)
+	assert.NotEmpty(t, stored.OutlineData)
+}
@@ -3,6 +3,8 @@ package transcription
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 
 	"github.com/killallgit/player-api/internal/models"
 	"gorm.io/gorm"
@@ -11,6 +13,9 @@ import (
 // repository implements the Repository interface using GORM
 type repository struct {
 	db *gorm.DB
+
+	indexMu    sync.Mutex  // held while the search index is built
+	indexReady atomic.Bool // set once the search index is complete
 }
 
 // NewRepository creates a new transcription repository
@@ -0,0 +1,380 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrEmptyQuery is returned when a transcript search query has no searchable words
+var ErrEmptyQuery = errors.New("search query has no searchable words")
+
+// ErrSearchIndexBuilding is returned by searches while the search index is
+// being filled from the stored transcripts
+var ErrSearchIndexBuilding = errors.New("transcript search index is being built")
+
+// searchTable is the FTS4 index of transcript segments. Only the text is
+// indexed; the episode and timing columns ride along for the results.
+const searchTable = "transcript_search"
+
+// searchBackfillTable gets a row once every stored transcript has been indexed
+const searchBackfillTable = "transcript_search_backfill"
+
+const (
+	// SnippetOpen and SnippetClose surround the matched words in a segment snippet
+	SnippetOpen  = "<mark>"
+	SnippetClose = "</mark>"
+
+	// DefaultSegmentsPerEpisode is how many matching segments are returned per episode
+	DefaultSegmentsPerEpisode = 3
+
+	backfillBatchSize = 100
+)
+
+// SearchOptions pages transcript search results
+type SearchOptions struct {
+	Limit    int // Episodes to return
+	Offset   int // Episodes to skip
+	Segments int // Matching segments to return per episode; DefaultSegmentsPerEpisode when 0
+}
+
+// TranscriptMatch is one episode whose transcript matches a search
+type TranscriptMatch struct {
+	EpisodeID int64           // Podcast Index episode ID
+	Episode   *models.Episode // Stored episode with its podcast; nil if the episode isn't stored
+	Matches   int             // Number of matching segments
+	Segments  []SegmentMatch  // The best matching segments, most hits first
+}
+
+// SegmentMatch is one matching transcript segment
+type SegmentMatch struct {
+	Start   *float64 // Seconds into the episode; nil for untimed transcripts
+	End     *float64
+	Text    string
+	Snippet string // Excerpt with matches wrapped in SnippetOpen/SnippetClose
+	Hits    int    // Occurrences of the query words in the segment
+}
+
+// searchEntry is one row of the search index
+type searchEntry struct {
+	Text      string
+	EpisodeID int64
+	StartTime *float64
+	EndTime   *float64
+}
+
+// EpisodeHits is an episode and its number of matching segments
+type EpisodeHits struct {
+	EpisodeID int64
+	Matches   int
+}
+
+// SegmentHit is a matching index row with its snippet and match offsets
+type SegmentHit struct {
+	EpisodeID int64
+	StartTime *float64
+	EndTime   *float64
+	Text      string
+	Snippet   string
+	Offsets   string
+}
+
+// matchQuery turns free text into an FTS query that requires every word.
+// Each word is quoted so FTS operators in user input are treated as text.
+func matchQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		if word != "" {
+			terms = append(terms, `"`+word+`"`)
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// searchEntries splits a transcript into index rows: its timed segments, or
+// its sentences when the transcript has no timing
+func searchEntries(t *models.Transcription) ([]searchEntry, error) {
+	segments, err := t.Segments()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode segments: %w", err)
+	}
+
+	var entries []searchEntry
+	if len(segments) > 0 {
+		for _, segment := range segments {
+			if text := strings.TrimSpace(segment.Text); text != "" {
+				start, end := segment.Start, segment.End
+				entries = append(entries, searchEntry{Text: text, EpisodeID: t.PodcastIndexEpisodeID, StartTime: &start, EndTime: &end})
+			}
+		}
+		return entries, nil
+	}
+
+	for _, sentence := range sentences(t.Text) {
+		entries = append(entries, searchEntry{Text: sentence, EpisodeID: t.PodcastIndexEpisodeID})
+	}
+	return entries, nil
+}
+
+// sentences splits text at sentence-ending punctuation and line breaks
+func sentences(text string) []string {
+	var out []string
+	start := 0
+	runes := []rune(text)
+	for i, r := range runes {
+		end := r == '\n' || ((r == '.' || r == '?' || r == '!') && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])))
+		if end {
+			if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+				out = append(out, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// BuildSearchIndex creates the search index and fills it from the transcripts
+// already stored. Completion is recorded only once the backfill has finished,
+// so a build that fails or is interrupted starts over on the next call.
+func (r *repository) BuildSearchIndex(ctx context.Context) error {
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
+	return r.buildSearchIndex(ctx)
+}
+
+// ensureSearchIndex makes sure the search index is complete before it is
+// queried, retrying a build that failed. The build is detached from ctx so an
+// abandoned request can't leave it half done.
+func (r *repository) ensureSearchIndex(ctx context.Context) error {
+	if r.indexReady.Load() {
+		return nil
+	}
+	if !r.indexMu.TryLock() {
+		return ErrSearchIndexBuilding
+	}
+	defer r.indexMu.Unlock()
+	return r.buildSearchIndex(context.WithoutCancel(ctx))
+}
+
+// buildSearchIndex does the work of BuildSearchIndex; callers hold indexMu
+func (r *repository) buildSearchIndex(ctx context.Context) error {
+	if r.indexReady.Load() {
+		return nil
+	}
+	if err := r.createSearchIndex(ctx); err != nil {
+		return err
+	}
+
+	var backfilled int64
+	if err := r.db.WithContext(ctx).Table(searchBackfillTable).Count(&backfilled).Error; err != nil {
+		return fmt.Errorf("failed to check search index backfill: %w", err)
+	}
+	if backfilled == 0 {
+		var batch []models.Transcription
+		err := r.db.WithContext(ctx).FindInBatches(&batch, backfillBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				if err := r.indexTranscript(ctx, &batch[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill search index: %w", err)
+		}
+		if err := r.db.WithContext(ctx).Exec("INSERT INTO "+searchBackfillTable+" (completed_at) VALUES (?)", time.Now().UTC()).Error; err != nil {
+			return fmt.Errorf("failed to record search index backfill: %w", err)
+		}
+	}
+
+	r.indexReady.Store(true)
+	return nil
+}
+
+// createSearchIndex creates the search index and its backfill marker if they
+// don't exist yet. Transcripts can be indexed as soon as the table exists;
+// the backfill replaces rows per episode, so the two don't conflict.
+func (r *repository) createSearchIndex(ctx context.Context) error {
+	create := `CREATE VIRTUAL TABLE IF NOT EXISTS ` + searchTable + ` USING fts4(
+		text, episode_id, start_time, end_time,
+		notindexed=episode_id, notindexed=start_time, notindexed=end_time,
+		tokenize=unicode61)`
+	if err := r.db.WithContext(ctx).Exec(create).Error; err != nil {
+		return fmt.Errorf("failed to create search index: %w", err)
+	}
+	marker := `CREATE TABLE IF NOT EXISTS ` + searchBackfillTable + ` (completed_at DATETIME NOT NULL)`
+	if err := r.db.WithContext(ctx).Exec(marker).Error; err != nil {
+		return fmt.Errorf("failed to create search index backfill marker: %w", err)
+	}
+	return nil
+}
+
+// IndexTranscript replaces the episode's rows in the search index
+func (r *repository) IndexTranscript(ctx context.Context, transcription *models.Transcription) error {
+	if err := r.createSearchIndex(ctx); err != nil {
+		return err
+	}
+	return r.indexTranscript(ctx, transcription)
+}
+
+func (r *repository) indexTranscript(ctx context.Context, transcription *models.Transcription) error {
+	entries, err := searchEntries(transcription)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM "+searchTable+" WHERE episode_id = ?", transcription.PodcastIndexEpisodeID).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Table(searchTable).CreateInBatches(entries, backfillBatchSize).Error
+	})
+}
+
+// RemoveFromIndex drops the episode's rows from the search index
+func (r *repository) RemoveFromIndex(ctx context.Context, podcastIndexEpisodeID int64) error {
+	if err := r.createSearchIndex(ctx); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Exec("DELETE FROM "+searchTable+" WHERE episode_id = ?", podcastIndexEpisodeID).Error
+}
+
+// SearchEpisodes returns episodes with segments matching the FTS query, most
+// matching segments first
+func (r *repository) SearchEpisodes(ctx context.Context, match string, limit, offset int) ([]EpisodeHits, error) {
+	if err := r.ensureSearchIndex(ctx); err != nil {
+		return nil, err
+	}
+	var hits []EpisodeHits
+	err := r.db.WithContext(ctx).Table(searchTable).
+		Select("episode_id, COUNT(*) AS matches").
+		Where(searchTable+" MATCH ?", match).
+		Group("episode_id").
+		Order("matches DESC, episode_id").
+		Limit(limit).Offset(offset).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// MatchingSegments returns the segments of the given episodes that match the
+// FTS query, with snippets and match offsets
+func (r *repository) MatchingSegments(ctx context.Context, match string, episodeIDs []int64) ([]SegmentHit, error) {
+	if err := r.ensureSearchIndex(ctx); err != nil {
+		return nil, err
+	}
+	var hits []SegmentHit
+	err := r.db.WithContext(ctx).Table(searchTable).
+		Select("episode_id, start_time, end_time, text, snippet("+searchTable+", ?, ?, '…', 0, 16) AS snippet, offsets("+searchTable+") AS offsets",
+			SnippetOpen, SnippetClose).
+		Where(searchTable+" MATCH ? AND episode_id IN ?", match, episodeIDs).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// EpisodesByID loads stored episodes with their podcasts, keyed by Podcast
+// Index episode ID
+func (r *repository) EpisodesByID(ctx context.Context, episodeIDs []int64) (map[int64]*models.Episode, error) {
+	var episodes []*models.Episode
+	if err := r.db.WithContext(ctx).Preload("Podcast").
+		Where("podcast_index_id IN ?", episodeIDs).
+		Find(&episodes).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*models.Episode, len(episodes))
+	for _, episode := range episodes {
+		byID[episode.PodcastIndexID] = episode
+	}
+	return byID, nil
+}
+
+// BuildSearchIndex fills the transcript search index from the stored
+// transcripts if that hasn't completed yet. Run it at startup so the first
+// search doesn't wait for the backfill.
+func (s *Service) BuildSearchIndex(ctx context.Context) error {
+	return s.repo.BuildSearchIndex(ctx)
+}
+
+// SearchTranscripts finds episodes whose stored transcripts contain every word
+// of query, ranked by how many segments match
+func (s *Service) SearchTranscripts(ctx context.Context, query string, opts SearchOptions) ([]TranscriptMatch, error) {
+	match := matchQuery(query)
+	if match == "" {
+		return nil, ErrEmptyQuery
+	}
+	if opts.Segments <= 0 {
+		opts.Segments = DefaultSegmentsPerEpisode
+	}
+
+	episodes, err := s.repo.SearchEpisodes(ctx, match, opts.Limit, opts.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transcripts: %w", err)
+	}
+	if len(episodes) == 0 {
+		return []TranscriptMatch{}, nil
+	}
+
+	ids := make([]int64, len(episodes))
+	for i, episode := range episodes {
+		ids[i] = episode.EpisodeID
+	}
+	hits, err := s.repo.MatchingSegments(ctx, match, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load matching segments: %w", err)
+	}
+	stored, err := s.repo.EpisodesByID(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episodes: %w", err)
+	}
+
+	segments := make(map[int64][]SegmentMatch, len(episodes))
+	for _, hit := range hits {
+		segments[hit.EpisodeID] = append(segments[hit.EpisodeID], SegmentMatch{
+			Start:   hit.StartTime,
+			End:     hit.EndTime,
+			Text:    hit.Text,
+			Snippet: hit.Snippet,
+			Hits:    len(strings.Fields(hit.Offsets)) / 4, // offsets() reports four integers per match
+		})
+	}
+
+	results := make([]TranscriptMatch, len(episodes))
+	for i, episode := range episodes {
+		best := segments[episode.EpisodeID]
+		sort.SliceStable(best, func(a, b int) bool {
+			if best[a].Hits != best[b].Hits {
+				return best[a].Hits > best[b].Hits
+			}
+			return startOf(best[a]) < startOf(best[b])
+		})
+		results[i] = TranscriptMatch{
+			EpisodeID: episode.EpisodeID,
+			Episode:   stored[episode.EpisodeID],
+			Matches:   episode.Matches,
+			Segments:  best[:min(len(best), opts.Segments)],
+		}
+	}
+	return results, nil
+}
+
+func startOf(segment SegmentMatch) float64 {
+	if segment.Start == nil {
+		return 0
+	}
+	return *segment.Start
+}
@@ -0,0 +1,146 @@
+package transcription
+
+import (
+	"context"
+	"testing"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&models.Podcast{}, &models.Episode{}, &models.Transcription{}))
+	return db
+}
+
+func timedTranscript(t *testing.T, episodeID int64, texts ...string) *models.Transcription {
+	segments := make([]models.TranscriptSegment, len(texts))
+	for i, text := range texts {
+		segments[i] = models.TranscriptSegment{Start: float64(i * 10), End: float64(i*10 + 10), Text: text}
+	}
+	transcription := &models.Transcription{PodcastIndexEpisodeID: episodeID}
+	require.NoError(t, transcription.SetSegments(segments))
+	return transcription
+}
+
+func TestSearchTranscripts_RanksByMatchingSegments(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	require.NoError(t, svc.SaveTranscription(ctx, timedTranscript(t, 1,
+		"Welcome to the show.",
+		"Today we talk about solar power.",
+		"Solar panels are cheap now, solar is everywhere.",
+	)))
+	require.NoError(t, svc.SaveTranscription(ctx, timedTranscript(t, 2,
+		"A short mention of solar energy.",
+		"Then something else entirely.",
+	)))
+	require.NoError(t, svc.SaveTranscription(ctx, timedTranscript(t, 3, "Nothing relevant here.")))
+
+	results, err := svc.SearchTranscripts(ctx, "Solar", SearchOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, int64(1), results[0].EpisodeID)
+	assert.Equal(t, 2, results[0].Matches)
+	require.Len(t, results[0].Segments, 2)
+	// The segment mentioning solar twice ranks first
+	assert.Equal(t, 2, results[0].Segments[0].Hits)
+	assert.Equal(t, 20.0, *results[0].Segments[0].Start)
+	assert.Contains(t, results[0].Segments[0].Snippet, SnippetOpen+"Solar"+SnippetClose)
+
+	assert.Equal(t, int64(2), results[1].EpisodeID)
+	assert.Equal(t, 1, results[1].Matches)
+
+	// Every word must appear in the segment
+	results, err = svc.SearchTranscripts(ctx, "solar panels", SearchOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 1, results[0].Matches)
+}
+
+func TestSearchTranscripts_ReindexesAndRemoves(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	require.NoError(t, svc.SaveTranscription(ctx, &models.Transcription{
+		PodcastIndexEpisodeID: 5,
+		Text:                  "An untimed transcript about gardening. Nothing else!",
+	}))
+	results, err := svc.SearchTranscripts(ctx, "gardening", SearchOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Segments[0].Start)
+	assert.Equal(t, "An untimed transcript about gardening.", results[0].Segments[0].Text)
+
+	// Saving again replaces the indexed text
+	require.NoError(t, svc.SaveTranscription(ctx, timedTranscript(t, 5, "Now it is about cooking.")))
+	results, err = svc.SearchTranscripts(ctx, "gardening", SearchOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	require.NoError(t, svc.DeleteTranscription(ctx, 5))
+	results, err = svc.SearchTranscripts(ctx, "cooking", SearchOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSearchTranscripts_BackfillsExistingTranscripts(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.Create(timedTranscript(t, 7, "Stored before the index existed.")).Error)
+
+	svc := NewService(NewRepository(db))
+	results, err := svc.SearchTranscripts(context.Background(), "index", SearchOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(7), results[0].EpisodeID)
+}
+
+func TestSearchTranscripts_RetriesFailedBuild(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.Create(timedTranscript(t, 7, "Stored before the index existed.")).Error)
+	repo := NewRepository(db).(*repository)
+	svc := NewService(repo)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, svc.BuildSearchIndex(cancelled))
+
+	// Searches wait for a build that is under way rather than querying a partial index
+	repo.indexMu.Lock()
+	_, err := svc.SearchTranscripts(context.Background(), "index", SearchOptions{Limit: 10})
+	assert.ErrorIs(t, err, ErrSearchIndexBuilding)
+	repo.indexMu.Unlock()
+
+	// The failed build is retried, and completion recorded once the backfill is done
+	results, err := svc.SearchTranscripts(context.Background(), "index", SearchOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	var backfilled int64
+	require.NoError(t, db.Table(searchBackfillTable).Count(&backfilled).Error)
+	assert.Equal(t, int64(1), backfilled)
+}
+
+func TestSearchTranscripts_QuotesOperators(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+
+	_, err := svc.SearchTranscripts(context.Background(), `"*" - ()`, SearchOptions{Limit: 10})
+	assert.ErrorIs(t, err, ErrEmptyQuery)
+
+	assert.Equal(t, `"rock" "OR" "roll"`, matchQuery(`rock OR roll*`))
+	assert.Equal(t, `"don't" "stop"`, matchQuery(`"don't" stop`))
+}
@@ -3,6 +3,7 @@ package transcription
 import (
 	"context"
 	"errors"
+	"log"
 
 	"github.com/killallgit/player-api/internal/models"
 	"gorm.io/gorm"
@@ -35,22 +36,48 @@ func (s *Service) SaveTranscription(ctx context.Context, transcription *models.T
 		return err
 	}
 
+	setOutline(transcription)
+
 	if existing != nil {
 		// Update existing transcription
 		existing.Text = transcription.Text
 		existing.Language = transcription.Language
 		existing.Model = transcription.Model
 		existing.Duration = transcription.Duration
-		return s.repo.Update(ctx, existing)
+		existing.SegmentsData = transcription.SegmentsData
+		existing.OutlineData = transcription.OutlineData
+		if err := s.repo.Update(ctx, existing); err != nil {
+			return err
+		}
+		s.index(ctx, existing)
+		return nil
 	}
 
 	// Create new transcription
-	return s.repo.Create(ctx, transcription)
+	if err := s.repo.Create(ctx, transcription); err != nil {
+		return err
+	}
+	s.index(ctx, transcription)
+	return nil
+}
+
+// index refreshes the search index for a saved transcription. The transcript
+// itself is already stored, so a failure here only costs search recall.
+func (s *Service) index(ctx context.Context, transcription *models.Transcription) {
+	if err := s.repo.IndexTranscript(ctx, transcription); err != nil {
+		log.Printf("[WARN] Failed to index transcript for episode %d: %v", transcription.PodcastIndexEpisodeID, err)
+	}
 }
 
 // DeleteTranscription removes a transcription by podcast index episode ID
 func (s *Service) DeleteTranscription(ctx context.Context, podcastIndexEpisodeID int64) error {
-	return s.repo.Delete(ctx, podcastIndexEpisodeID)
+	if err := s.repo.Delete(ctx, podcastIndexEpisodeID); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveFromIndex(ctx, podcastIndexEpisodeID); err != nil {
+		log.Printf("[WARN] Failed to remove transcript for episode %d from search index: %v", podcastIndexEpisodeID, err)
+	}
+	return nil
 }
 
 // ExistsTranscription checks if a transcription exists for an episode
@@ -0,0 +1,230 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+var (
+	// ErrNoTranscript is returned when the episode to compare has no stored transcript
+	ErrNoTranscript = errors.New("episode has no transcript")
+
+	// ErrEpisodeNotStored is returned when the episode to compare isn't in the database,
+	// so its podcast is unknown
+	ErrEpisodeNotStored = errors.New("episode not stored")
+)
+
+const (
+	// shingleWords is the length of the word sequences compared between transcripts.
+	// Five words is long enough that shared phrasing ("thanks for listening") rarely
+	// matches on its own, and short enough to survive small transcription differences.
+	shingleWords = 5
+
+	// DefaultSimilarCandidates is how many of the podcast's other transcribed episodes are compared
+	DefaultSimilarCandidates = 200
+
+	// DefaultSimilarMinScore drops episodes that share less than this of either transcript
+	DefaultSimilarMinScore = 0.2
+)
+
+// Similarity kinds, from the overlap of two transcripts' shingles
+const (
+	SimilarRerun       = "rerun"       // Nearly the same transcript
+	SimilarCompilation = "compilation" // The compared episode contains most of the other one
+	SimilarExcerpt     = "excerpt"     // Most of the compared episode appears in the other one
+	SimilarOverlap     = "overlap"     // Shares a substantial segment, such as a replayed interview
+)
+
+// SimilarOptions limits an episode comparison
+type SimilarOptions struct {
+	Limit      int     // Results to return
+	MinScore   float64 // Minimum of the larger containment; DefaultSimilarMinScore when 0
+	Candidates int     // Other episodes to compare; DefaultSimilarCandidates when 0
+}
+
+// SimilarEpisode is an episode whose transcript overlaps the compared one
+type SimilarEpisode struct {
+	EpisodeID   int64           // Podcast Index episode ID
+	Episode     *models.Episode // Stored episode
+	Kind        string          // One of the Similar* kinds
+	Jaccard     float64         // Shared shingles over all shingles of both episodes
+	ContainedIn float64         // Share of the compared episode found in this one
+	Contains    float64         // Share of this episode found in the compared one
+}
+
+// SimilarResult lists the likely duplicates of an episode
+type SimilarResult struct {
+	Compared int // Transcribed episodes compared against
+	Similar  []SimilarEpisode
+}
+
+// PodcastTranscriptions returns the transcriptions of a podcast's other
+// episodes, newest episodes first
+func (r *repository) PodcastTranscriptions(ctx context.Context, podcastIndexFeedID, excludeEpisodeID int64, limit int) ([]models.Transcription, error) {
+	var transcriptions []models.Transcription
+	query := r.db.WithContext(ctx).
+		Joins("JOIN episodes ON episodes.podcast_index_id = transcriptions.podcast_index_episode_id AND episodes.deleted_at IS NULL").
+		Where("episodes.podcast_index_feed_id = ? AND transcriptions.podcast_index_episode_id <> ?", podcastIndexFeedID, excludeEpisodeID).
+		Order("episodes.published_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&transcriptions).Error
+	return transcriptions, err
+}
+
+// SimilarEpisodes compares the episode's transcript with the transcripts of
+// other episodes of the same podcast, using hashed word shingles, and returns
+// the ones sharing at least MinScore of either transcript, closest first
+func (s *Service) SimilarEpisodes(ctx context.Context, podcastIndexEpisodeID int64, opts SimilarOptions) (*SimilarResult, error) {
+	if opts.MinScore <= 0 {
+		opts.MinScore = DefaultSimilarMinScore
+	}
+	if opts.Candidates <= 0 {
+		opts.Candidates = DefaultSimilarCandidates
+	}
+
+	stored, err := s.repo.EpisodesByID(ctx, []int64{podcastIndexEpisodeID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episode: %w", err)
+	}
+	episode, ok := stored[podcastIndexEpisodeID]
+	if !ok {
+		return nil, ErrEpisodeNotStored
+	}
+
+	target, err := s.repo.GetByEpisodeID(ctx, podcastIndexEpisodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transcription: %w", err)
+	}
+	if target == nil {
+		return nil, ErrNoTranscript
+	}
+	targetShingles := shingles(transcriptText(target))
+	if len(targetShingles) == 0 {
+		return nil, ErrNoTranscript
+	}
+
+	candidates, err := s.repo.PodcastTranscriptions(ctx, episode.PodcastIndexFeedID, podcastIndexEpisodeID, opts.Candidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load podcast transcriptions: %w", err)
+	}
+
+	result := &SimilarResult{Compared: len(candidates), Similar: []SimilarEpisode{}}
+	for i := range candidates {
+		other := shingles(transcriptText(&candidates[i]))
+		if len(other) == 0 {
+			continue
+		}
+		shared := 0
+		for hash := range targetShingles {
+			if _, ok := other[hash]; ok {
+				shared++
+			}
+		}
+		if shared == 0 {
+			continue
+		}
+
+		similar := SimilarEpisode{
+			EpisodeID:   candidates[i].PodcastIndexEpisodeID,
+			Jaccard:     float64(shared) / float64(len(targetShingles)+len(other)-shared),
+			ContainedIn: float64(shared) / float64(len(targetShingles)),
+			Contains:    float64(shared) / float64(len(other)),
+		}
+		if max(similar.ContainedIn, similar.Contains) < opts.MinScore {
+			continue
+		}
+		similar.Kind = similarKind(similar)
+		result.Similar = append(result.Similar, similar)
+	}
+
+	sort.SliceStable(result.Similar, func(i, j int) bool {
+		a, b := result.Similar[i], result.Similar[j]
+		if a.Jaccard != b.Jaccard {
+			return a.Jaccard > b.Jaccard
+		}
+		return max(a.ContainedIn, a.Contains) > max(b.ContainedIn, b.Contains)
+	})
+	if opts.Limit > 0 && len(result.Similar) > opts.Limit {
+		result.Similar = result.Similar[:opts.Limit]
+	}
+
+	if len(result.Similar) > 0 {
+		ids := make([]int64, len(result.Similar))
+		for i, similar := range result.Similar {
+			ids[i] = similar.EpisodeID
+		}
+		episodes, err := s.repo.EpisodesByID(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load episodes: %w", err)
+		}
+		for i := range result.Similar {
+			result.Similar[i].Episode = episodes[result.Similar[i].EpisodeID]
+		}
+	}
+	return result, nil
+}
+
+func similarKind(similar SimilarEpisode) string {
+	switch {
+	case similar.Jaccard >= 0.8:
+		return SimilarRerun
+	case similar.Contains >= 0.6:
+		return SimilarCompilation
+	case similar.ContainedIn >= 0.6:
+		return SimilarExcerpt
+	default:
+		return SimilarOverlap
+	}
+}
+
+// transcriptText returns the full text, falling back to the joined segments
+func transcriptText(transcription *models.Transcription) string {
+	if strings.TrimSpace(transcription.Text) != "" {
+		return transcription.Text
+	}
+	segments, err := transcription.Segments()
+	if err != nil {
+		return ""
+	}
+	texts := make([]string, len(segments))
+	for i, segment := range segments {
+		texts[i] = segment.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// shingles hashes every run of shingleWords consecutive normalized words.
+// Transcripts shorter than that yield a single shingle of all their words.
+func shingles(text string) map[uint64]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+	if len(words) == 0 {
+		return nil
+	}
+
+	set := make(map[uint64]struct{}, len(words))
+	hash := fnv.New64a()
+	add := func(run []string) {
+		hash.Reset()
+		_, _ = hash.Write([]byte(strings.Join(run, " ")))
+		set[hash.Sum64()] = struct{}{}
+	}
+	if len(words) < shingleWords {
+		add(words)
+		return set
+	}
+	for i := 0; i+shingleWords <= len(words); i++ {
+		add(words[i : i+shingleWords])
+	}
+	return set
+}
@@ -0,0 +1,100 @@
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// words returns n distinct words starting at from, standing in for a stretch of speech
+func words(from, n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("w%d", from+i)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestShingles(t *testing.T) {
+	assert.Empty(t, shingles("  ...  "))
+	assert.Len(t, shingles("too short"), 1)
+	assert.Len(t, shingles(words(0, 10)), 6)
+	assert.Equal(t, shingles("Hello, there! General Kenobi. You're"), shingles("hello there general kenobi you're"),
+		"case and punctuation are ignored")
+}
+
+func TestSimilarEpisodes(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	require.NoError(t, db.Create(&models.Podcast{PodcastIndexID: 100, Title: "Show", FeedURL: "https://example.com/feed"}).Error)
+	published := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, feedID := range map[int64]int64{1: 100, 2: 100, 3: 100, 4: 100, 5: 100, 6: 200, 7: 100} {
+		require.NoError(t, db.Create(&models.Episode{
+			PodcastID: 1, PodcastIndexID: i, PodcastIndexFeedID: feedID, Title: fmt.Sprintf("Episode %d", i),
+			GUID: fmt.Sprintf("guid-%d", i), AudioURL: "https://example.com/a.mp3", PublishedAt: published.AddDate(0, 0, int(i)),
+		}).Error)
+	}
+
+	transcripts := map[int64]string{
+		1: words(0, 200),                           // The episode compared
+		2: words(0, 200),                           // Re-run
+		3: words(0, 60),                            // Excerpt of episode 1, which compiles it
+		4: words(150, 50) + " " + words(1000, 450), // Shares the last 50 words
+		5: words(5000, 200),                        // Unrelated
+		6: words(0, 200),                           // Same words, other podcast
+	}
+	for id, text := range transcripts {
+		require.NoError(t, db.Create(&models.Transcription{PodcastIndexEpisodeID: id, Text: text}).Error)
+	}
+	// Timed transcript without full text is compared through its segments
+	timed := timedTranscript(t, 7, words(100, 50), words(150, 50))
+	require.NoError(t, db.Create(timed).Error)
+
+	result, err := svc.SimilarEpisodes(ctx, 1, SimilarOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Compared, "other podcasts are not compared")
+
+	kinds := map[int64]string{}
+	for _, similar := range result.Similar {
+		kinds[similar.EpisodeID] = similar.Kind
+		require.NotNil(t, similar.Episode)
+	}
+	assert.Equal(t, map[int64]string{2: SimilarRerun, 3: SimilarCompilation, 7: SimilarCompilation, 4: SimilarOverlap}, kinds)
+	assert.Equal(t, int64(2), result.Similar[0].EpisodeID, "closest first")
+	assert.Equal(t, 1.0, result.Similar[0].Jaccard)
+
+	for _, similar := range result.Similar {
+		if similar.EpisodeID == 4 {
+			assert.InDelta(t, 46.0/196, similar.ContainedIn, 0.001)
+			assert.InDelta(t, 46.0/496, similar.Contains, 0.001)
+		}
+	}
+
+	limited, err := svc.SimilarEpisodes(ctx, 1, SimilarOptions{Limit: 1, MinScore: 0.5})
+	require.NoError(t, err)
+	require.Len(t, limited.Similar, 1)
+
+	// From the excerpt's side, episode 1 holds all of it
+	excerpt, err := svc.SimilarEpisodes(ctx, 3, SimilarOptions{MinScore: 0.9})
+	require.NoError(t, err)
+	require.Len(t, excerpt.Similar, 2)
+	assert.Equal(t, SimilarExcerpt, excerpt.Similar[0].Kind)
+
+	_, err = svc.SimilarEpisodes(ctx, 99, SimilarOptions{})
+	assert.ErrorIs(t, err, ErrEpisodeNotStored)
+
+	require.NoError(t, db.Create(&models.Episode{
+		PodcastID: 1, PodcastIndexID: 8, PodcastIndexFeedID: 100, Title: "Untranscribed",
+		GUID: "guid-8", AudioURL: "https://example.com/a.mp3",
+	}).Error)
+	_, err = svc.SimilarEpisodes(ctx, 8, SimilarOptions{})
+	assert.ErrorIs(t, err, ErrNoTranscript)
+}
@@ -0,0 +1,133 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/pkg/transcript"
+	"github.com/spf13/viper"
+)
+
+// ErrWhisperNotFound is returned when the whisper.cpp binary isn't installed
+var ErrWhisperNotFound = errors.New("whisper binary not found")
+
+// Whisper runs the whisper.cpp command line tool
+type Whisper struct {
+	Path      string         // whisper-cli binary
+	ModelPath string         // ggml model file, used when Models is nil
+	Models    *ModelRegistry // Resolves, and if needed installs, the default model on each run
+	Language  string
+	Threads   int
+}
+
+// WhisperFromConfig reads the transcription.* settings. An unset binary falls
+// back to whisper-cli (Homebrew) when it's on PATH, else the whisper.cpp build
+// in the container image.
+func WhisperFromConfig() Whisper {
+	w := Whisper{
+		Path:      viper.GetString("transcription.whisper_path"),
+		ModelPath: viper.GetString("transcription.model_path"),
+		Language:  viper.GetString("transcription.language"),
+	}
+	if w.ModelPath == "" {
+		w.ModelPath = "./models/ggml-base.en.bin"
+	}
+	if w.Path == "" {
+		if _, err := exec.LookPath("whisper-cli"); err == nil {
+			w.Path = "whisper-cli"
+		} else {
+			w.Path = "/app/bin/main"
+		}
+	}
+	if w.Language == "" {
+		w.Language = "en"
+	}
+	return w
+}
+
+// Transcribe transcribes an audio file to plain text with the given model
+func (w Whisper) Transcribe(ctx context.Context, audioPath, modelPath string) (string, error) {
+	if _, err := exec.LookPath(w.Path); err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrWhisperNotFound, w.Path, err)
+	}
+
+	threads := w.Threads
+	if threads <= 0 {
+		threads = 4
+	}
+	cmd := exec.CommandContext(ctx, w.Path,
+		"-m", modelPath,
+		"-f", audioPath,
+		"-l", w.Language,
+		"-t", strconv.Itoa(threads),
+		"-otxt", // output as text
+		"-nt",   // no timestamps
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("whisper failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// TranscribeSegments transcribes a 16kHz mono WAV file into timed segments.
+// whisper writes a VTT file beside the input, which is parsed and removed.
+func (w Whisper) TranscribeSegments(ctx context.Context, wavPath string) ([]models.TranscriptSegment, error) {
+	if _, err := exec.LookPath(w.Path); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrWhisperNotFound, w.Path, err)
+	}
+
+	modelPath := w.ModelPath
+	if w.Models != nil {
+		path, err := w.Models.Path(ctx, w.Models.Default())
+		if err != nil {
+			return nil, err
+		}
+		modelPath = path
+	}
+
+	threads := w.Threads
+	if threads <= 0 {
+		threads = 4
+	}
+	outputBase := strings.TrimSuffix(wavPath, ".wav")
+	cmd := exec.CommandContext(ctx, w.Path,
+		"-m", modelPath,
+		"-f", wavPath,
+		"-l", w.Language,
+		"-t", strconv.Itoa(threads),
+		"-ovtt",
+		"-of", outputBase,
+		"-np", // no progress or system info on stderr
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("whisper failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	vttPath := outputBase + ".vtt"
+	defer os.Remove(vttPath)
+	content, err := os.ReadFile(vttPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading whisper output: %w", err)
+	}
+
+	parsed, err := transcript.NewParser().Parse(string(content), transcript.FormatVTT)
+	if err != nil {
+		return nil, fmt.Errorf("parsing whisper output: %w", err)
+	}
+	segments := make([]models.TranscriptSegment, 0, len(parsed.Segments))
+	for _, seg := range parsed.Segments {
+		segments = append(segments, models.TranscriptSegment{
+			Start: seg.Start.Seconds(),
+			End:   seg.End.Seconds(),
+			Text:  seg.Text,
+		})
+	}
+	return segments, nil
+}
@@ -0,0 +1,174 @@
+package userdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// ScheduleDeletion schedules deletion of the user's data after the grace period
+func (s *service) ScheduleDeletion(ctx context.Context, userID string, grace time.Duration) (*models.AccountDeletion, error) {
+	deletion, err := s.repo.GetDeletion(ctx, userID)
+	if err != nil && !errors.Is(err, ErrDeletionNotFound) {
+		return nil, fmt.Errorf("loading deletion request: %w", err)
+	}
+
+	if deletion != nil && (deletion.Status == models.AccountDeletionScheduled || deletion.Status == models.AccountDeletionQueued) {
+		return deletion, nil
+	}
+
+	// Reuse the row from a cancelled or completed request
+	if deletion == nil {
+		deletion = &models.AccountDeletion{UserID: userID}
+	}
+	now := time.Now().UTC()
+	deletion.Status = models.AccountDeletionScheduled
+	deletion.RequestedAt = now
+	deletion.ScheduledFor = now.Add(grace)
+	deletion.JobID = nil
+	deletion.CompletedAt = nil
+	deletion.CancelledAt = nil
+
+	if err := s.repo.SaveDeletion(ctx, deletion); err != nil {
+		return nil, fmt.Errorf("saving deletion request: %w", err)
+	}
+
+	log.Printf("[INFO] Account deletion scheduled for user %s at %s", userID, deletion.ScheduledFor.Format(time.RFC3339))
+	return deletion, nil
+}
+
+// GetDeletion returns the user's deletion request
+func (s *service) GetDeletion(ctx context.Context, userID string) (*models.AccountDeletion, error) {
+	return s.repo.GetDeletion(ctx, userID)
+}
+
+// CancelDeletion cancels a deletion that is still within its grace period
+func (s *service) CancelDeletion(ctx context.Context, userID string) (*models.AccountDeletion, error) {
+	deletion, err := s.repo.GetDeletion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch deletion.Status {
+	case models.AccountDeletionScheduled:
+	case models.AccountDeletionQueued:
+		return nil, ErrDeletionInProgress
+	default:
+		return nil, ErrDeletionNotFound
+	}
+
+	now := time.Now().UTC()
+	deletion.Status = models.AccountDeletionCancelled
+	deletion.CancelledAt = &now
+	if err := s.repo.SaveDeletion(ctx, deletion); err != nil {
+		return nil, fmt.Errorf("saving deletion request: %w", err)
+	}
+
+	log.Printf("[INFO] Account deletion cancelled for user %s", userID)
+	return deletion, nil
+}
+
+// DueDeletions returns scheduled deletions whose grace period has ended
+func (s *service) DueDeletions(ctx context.Context, now time.Time) ([]models.AccountDeletion, error) {
+	return s.repo.ListDueDeletions(ctx, now)
+}
+
+// MarkDeletionQueued records the job that will carry out a deletion
+// The update only applies while the request is still scheduled, so a cancel
+// that lands between listing and queueing is not overwritten.
+func (s *service) MarkDeletionQueued(ctx context.Context, userID string, jobID uint) error {
+	queued, err := s.repo.MarkDeletionQueued(ctx, userID, jobID)
+	if err != nil {
+		return err
+	}
+	if !queued {
+		return ErrDeletionCancelled
+	}
+	return nil
+}
+
+// DeleteAccountData removes the user's data. Rows in user-scoped tables are deleted,
+// clips are anonymized or deleted per the clip policy, export archives are removed,
+// and the user is cleared from any remaining jobs.
+func (s *service) DeleteAccountData(ctx context.Context, userID string) (*DeletionSummary, error) {
+	deletion, err := s.repo.GetDeletion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if deletion.Status == models.AccountDeletionCancelled {
+		return nil, ErrDeletionCancelled
+	}
+
+	summary := &DeletionSummary{}
+
+	if err := s.repo.DeleteUserRows(ctx, userID, summary); err != nil {
+		return nil, fmt.Errorf("deleting user rows: %w", err)
+	}
+
+	if err := s.removeClips(ctx, userID, summary); err != nil {
+		return nil, err
+	}
+
+	exportIDs, err := s.repo.DeleteExportJobs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("deleting export jobs: %w", err)
+	}
+	for _, jobID := range exportIDs {
+		if err := os.Remove(s.ArchivePath(jobID)); err != nil && !os.IsNotExist(err) {
+			log.Printf("[WARN] Failed to remove export archive for job %d: %v", jobID, err)
+		}
+	}
+	summary.Exports = int64(len(exportIDs))
+
+	if err := s.repo.AnonymizeJobs(ctx, userID); err != nil {
+		return nil, fmt.Errorf("anonymizing jobs: %w", err)
+	}
+	if err := s.repo.AnonymizeAnnotationAudits(ctx, userID); err != nil {
+		return nil, fmt.Errorf("anonymizing annotation history: %w", err)
+	}
+
+	now := time.Now().UTC()
+	deletion.Status = models.AccountDeletionCompleted
+	deletion.CompletedAt = &now
+	if err := s.repo.SaveDeletion(ctx, deletion); err != nil {
+		return nil, fmt.Errorf("saving deletion request: %w", err)
+	}
+
+	return summary, nil
+}
+
+// removeClips applies the clip policy to the user's clips
+func (s *service) removeClips(ctx context.Context, userID string, summary *DeletionSummary) error {
+	if s.clipPolicy != ClipPolicyDelete || s.clipRemover == nil {
+		count, err := s.repo.AnonymizeClips(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("anonymizing clips: %w", err)
+		}
+		summary.ClipsAnonymized = count
+		return nil
+	}
+
+	clips, err := s.repo.ListClips(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("loading clips: %w", err)
+	}
+	for _, clip := range clips {
+		if err := s.clipRemover.DeleteClip(ctx, clip.UUID); err != nil {
+			return fmt.Errorf("deleting clip %s: %w", clip.UUID, err)
+		}
+		summary.ClipsDeleted++
+	}
+
+	// Clips created while the deletion ran are anonymized rather than missed
+	count, err := s.repo.AnonymizeClips(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("anonymizing clips: %w", err)
+	}
+	summary.ClipsAnonymized = count
+	return nil
+}
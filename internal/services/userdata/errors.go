@@ -0,0 +1,20 @@
+package userdata
+
+import "errors"
+
+var (
+	// ErrInvalidSignature is returned when a download link's signature does not match
+	ErrInvalidSignature = errors.New("invalid download signature")
+
+	// ErrLinkExpired is returned when a download link is past its expiry
+	ErrLinkExpired = errors.New("download link expired")
+
+	// ErrDeletionNotFound is returned when the user has no pending deletion request
+	ErrDeletionNotFound = errors.New("account deletion not found")
+
+	// ErrDeletionInProgress is returned when cancelling a deletion whose job has been queued
+	ErrDeletionInProgress = errors.New("account deletion already in progress")
+
+	// ErrDeletionCancelled is returned when a deletion job runs after the request was cancelled
+	ErrDeletionCancelled = errors.New("account deletion was cancelled")
+)
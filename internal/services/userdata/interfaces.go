@@ -0,0 +1,138 @@
+package userdata
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Service defines the interface for assembling a user's personal data
+type Service interface {
+	// Collect gathers everything stored for the user
+	Collect(ctx context.Context, userID string) (*Export, error)
+
+	// WriteArchive writes the user's data as a zip of JSON files for the given
+	// export job and returns the archive size in bytes
+	WriteArchive(ctx context.Context, userID string, jobID uint) (int64, error)
+
+	// ArchivePath returns where the archive for an export job is stored
+	ArchivePath(jobID uint) string
+
+	// SignDownload returns an expiry and signature authorizing download of an export
+	SignDownload(jobID uint, ttl time.Duration) (time.Time, string)
+
+	// VerifyDownload checks a signature produced by SignDownload
+	VerifyDownload(jobID uint, expires int64, signature string) error
+
+	// ScheduleDeletion schedules deletion of the user's data after the grace period.
+	// An existing pending request is returned unchanged.
+	ScheduleDeletion(ctx context.Context, userID string, grace time.Duration) (*models.AccountDeletion, error)
+
+	// GetDeletion returns the user's deletion request
+	GetDeletion(ctx context.Context, userID string) (*models.AccountDeletion, error)
+
+	// CancelDeletion cancels a deletion that is still within its grace period
+	CancelDeletion(ctx context.Context, userID string) (*models.AccountDeletion, error)
+
+	// DueDeletions returns scheduled deletions whose grace period has ended
+	DueDeletions(ctx context.Context, now time.Time) ([]models.AccountDeletion, error)
+
+	// MarkDeletionQueued records the job that will carry out a deletion.
+	// Returns ErrDeletionCancelled when the request was cancelled in the meantime.
+	MarkDeletionQueued(ctx context.Context, userID string, jobID uint) error
+
+	// DeleteAccountData removes the user's data, applying the configured clip policy
+	DeleteAccountData(ctx context.Context, userID string) (*DeletionSummary, error)
+}
+
+// ClipPolicy controls what happens to clips a deleted user created
+type ClipPolicy string
+
+const (
+	// ClipPolicyAnonymize keeps the clips but clears their owner
+	ClipPolicyAnonymize ClipPolicy = "anonymize"
+	// ClipPolicyDelete removes the clips and their audio files
+	ClipPolicyDelete ClipPolicy = "delete"
+)
+
+// ClipRemover deletes a clip and its stored audio
+type ClipRemover interface {
+	DeleteClip(ctx context.Context, uuid string) error
+}
+
+// DeletionSummary counts what an account deletion removed or anonymized
+type DeletionSummary struct {
+	Subscriptions    int64 `json:"subscriptions"`
+	PlaybackProgress int64 `json:"playback_progress"`
+	ListeningHistory int64 `json:"listening_history"`
+	DailyListening   int64 `json:"daily_listening"`
+	Preferences      int64 `json:"preferences"`
+	Notifications    int64 `json:"notifications"`
+	PlaybackEvents   int64 `json:"playback_events"`
+	SavedSearches    int64 `json:"saved_searches"`
+	EpisodeReads     int64 `json:"episode_reads"`
+	AutoplayRules    int64 `json:"autoplay_rules"`
+	ClipsAnonymized  int64 `json:"clips_anonymized"`
+	ClipsDeleted     int64 `json:"clips_deleted"`
+	Exports          int64 `json:"exports"`
+}
+
+// Export is the full set of user-scoped data
+type Export struct {
+	UserID           string                    `json:"user_id"`
+	ExportedAt       time.Time                 `json:"exported_at"`
+	Subscriptions    []SubscriptionRecord      `json:"subscriptions"`
+	PlaybackProgress []models.PlaybackProgress `json:"playback_progress"` // Per-episode resume positions
+	ListeningHistory []models.ListeningHistory `json:"listening_history"`
+	DailyListening   []models.DailyListening   `json:"daily_listening"`
+	Preferences      *models.UserPreferences   `json:"preferences,omitempty"`
+	Annotations      []models.Clip             `json:"annotations"` // Clips the user created
+	SavedSearches    []models.SavedSearch      `json:"saved_searches"`
+	EpisodeReads     []models.EpisodeRead      `json:"episode_reads"` // Inbox episodes marked read
+}
+
+// SubscriptionRecord is a subscription flattened with the podcast's identifiers
+type SubscriptionRecord struct {
+	PodcastIndexID int64     `json:"podcast_index_id"`
+	Title          string    `json:"title"`
+	FeedURL        string    `json:"feed_url"`
+	SubscribedAt   time.Time `json:"subscribed_at"`
+}
+
+// Repository defines the interface for reading user-scoped data
+type Repository interface {
+	ListSubscriptions(ctx context.Context, userID string) ([]SubscriptionRecord, error)
+	ListPlaybackProgress(ctx context.Context, userID string) ([]models.PlaybackProgress, error)
+	ListListeningHistory(ctx context.Context, userID string) ([]models.ListeningHistory, error)
+	ListDailyListening(ctx context.Context, userID string) ([]models.DailyListening, error)
+
+	// GetPreferences returns nil without error when the user has none stored
+	GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error)
+
+	ListClips(ctx context.Context, userID string) ([]models.Clip, error)
+	ListSavedSearches(ctx context.Context, userID string) ([]models.SavedSearch, error)
+	ListEpisodeReads(ctx context.Context, userID string) ([]models.EpisodeRead, error)
+
+	GetDeletion(ctx context.Context, userID string) (*models.AccountDeletion, error)
+	SaveDeletion(ctx context.Context, deletion *models.AccountDeletion) error
+
+	// MarkDeletionQueued reports false when the deletion was no longer scheduled
+	MarkDeletionQueued(ctx context.Context, userID string, jobID uint) (bool, error)
+	ListDueDeletions(ctx context.Context, now time.Time) ([]models.AccountDeletion, error)
+
+	// DeleteUserRows removes subscriptions, playback, listening and preference rows in one transaction
+	DeleteUserRows(ctx context.Context, userID string, summary *DeletionSummary) error
+
+	// AnonymizeClips clears the owner of the user's clips
+	AnonymizeClips(ctx context.Context, userID string) (int64, error)
+
+	// DeleteExportJobs removes the user's finished export jobs and returns their IDs
+	DeleteExportJobs(ctx context.Context, userID string) ([]uint, error)
+
+	// AnonymizeJobs clears the creator of the user's remaining jobs
+	AnonymizeJobs(ctx context.Context, userID string) error
+
+	// AnonymizeAnnotationAudits clears the user from the annotation audit trail
+	AnonymizeAnnotationAudits(ctx context.Context, userID string) error
+}
@@ -0,0 +1,197 @@
+package userdata
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new user data repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ListSubscriptions returns the user's subscriptions joined with podcast identifiers
+func (r *repository) ListSubscriptions(ctx context.Context, userID string) ([]SubscriptionRecord, error) {
+	var records []SubscriptionRecord
+	err := r.db.WithContext(ctx).
+		Table("subscriptions").
+		Select("podcasts.podcast_index_id, podcasts.title, podcasts.feed_url, subscriptions.created_at AS subscribed_at").
+		Joins("JOIN podcasts ON podcasts.id = subscriptions.podcast_id").
+		Where("subscriptions.user_id = ? AND subscriptions.deleted_at IS NULL", userID).
+		Order("subscriptions.created_at").
+		Scan(&records).Error
+	return records, err
+}
+
+// ListPlaybackProgress returns the user's resume positions
+func (r *repository) ListPlaybackProgress(ctx context.Context, userID string) ([]models.PlaybackProgress, error) {
+	var progress []models.PlaybackProgress
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("updated_at").Find(&progress).Error
+	return progress, err
+}
+
+// ListListeningHistory returns the user's completed plays
+func (r *repository) ListListeningHistory(ctx context.Context, userID string) ([]models.ListeningHistory, error) {
+	var history []models.ListeningHistory
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("completed_at").Find(&history).Error
+	return history, err
+}
+
+// ListDailyListening returns the user's daily listening aggregates
+func (r *repository) ListDailyListening(ctx context.Context, userID string) ([]models.DailyListening, error) {
+	var daily []models.DailyListening
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("day").Find(&daily).Error
+	return daily, err
+}
+
+// GetPreferences returns the user's stored preferences, or nil if there are none
+func (r *repository) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	var prefs models.UserPreferences
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&prefs).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// ListClips returns the clips the user created
+func (r *repository) ListClips(ctx context.Context, userID string) ([]models.Clip, error) {
+	var clips []models.Clip
+	err := r.db.WithContext(ctx).Where("created_by = ?", userID).Order("created_at").Find(&clips).Error
+	return clips, err
+}
+
+// GetDeletion returns the user's deletion request
+func (r *repository) GetDeletion(ctx context.Context, userID string) (*models.AccountDeletion, error) {
+	var deletion models.AccountDeletion
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&deletion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeletionNotFound
+		}
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+// SaveDeletion creates or updates a deletion request
+func (r *repository) SaveDeletion(ctx context.Context, deletion *models.AccountDeletion) error {
+	if deletion == nil {
+		return errors.New("deletion cannot be nil")
+	}
+	return r.db.WithContext(ctx).Save(deletion).Error
+}
+
+// MarkDeletionQueued moves a still-scheduled deletion to queued in a single
+// conditional update. It reports false when the request was no longer scheduled.
+func (r *repository) MarkDeletionQueued(ctx context.Context, userID string, jobID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.AccountDeletion{}).
+		Where("user_id = ? AND status = ?", userID, models.AccountDeletionScheduled).
+		Updates(map[string]interface{}{"status": models.AccountDeletionQueued, "job_id": jobID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListDueDeletions returns scheduled deletions whose grace period ended before now
+func (r *repository) ListDueDeletions(ctx context.Context, now time.Time) ([]models.AccountDeletion, error) {
+	var deletions []models.AccountDeletion
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_for <= ?", models.AccountDeletionScheduled, now).
+		Order("scheduled_for").
+		Find(&deletions).Error
+	return deletions, err
+}
+
+// ListSavedSearches returns the searches the user saved
+func (r *repository) ListSavedSearches(ctx context.Context, userID string) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&searches).Error
+	return searches, err
+}
+
+// ListEpisodeReads returns the inbox episodes the user marked read
+func (r *repository) ListEpisodeReads(ctx context.Context, userID string) ([]models.EpisodeRead, error) {
+	var reads []models.EpisodeRead
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("read_at").Find(&reads).Error
+	return reads, err
+}
+
+// DeleteUserRows removes the user's rows from every user-scoped table
+func (r *repository) DeleteUserRows(ctx context.Context, userID string, summary *DeletionSummary) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Saved search results carry no user ID; remove them before their searches
+		searches := tx.Model(&models.SavedSearch{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("saved_search_id IN (?)", searches).Delete(&models.SavedSearchResult{}).Error; err != nil {
+			return err
+		}
+
+		tables := []struct {
+			model interface{}
+			count *int64
+		}{
+			{&models.Subscription{}, &summary.Subscriptions},
+			{&models.PlaybackProgress{}, &summary.PlaybackProgress},
+			{&models.ListeningHistory{}, &summary.ListeningHistory},
+			{&models.DailyListening{}, &summary.DailyListening},
+			{&models.UserPreferences{}, &summary.Preferences},
+			{&models.Notification{}, &summary.Notifications},
+			{&models.PlaybackEvent{}, &summary.PlaybackEvents},
+			{&models.SavedSearch{}, &summary.SavedSearches},
+			{&models.EpisodeRead{}, &summary.EpisodeReads},
+			{&models.AutoplayRules{}, &summary.AutoplayRules},
+		}
+		for _, table := range tables {
+			// Unscoped so soft-deleted subscriptions are purged too
+			result := tx.Unscoped().Where("user_id = ?", userID).Delete(table.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			*table.count = result.RowsAffected
+		}
+		return nil
+	})
+}
+
+// AnonymizeClips clears the owner of the user's clips
+func (r *repository) AnonymizeClips(ctx context.Context, userID string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Clip{}).Where("created_by = ?", userID).Update("created_by", "")
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExportJobs removes the user's finished export jobs and returns their IDs
+func (r *repository) DeleteExportJobs(ctx context.Context, userID string) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("type = ? AND created_by = ? AND status <> ?", models.JobTypeUserExport, userID, models.JobStatusProcessing).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	if err := r.db.WithContext(ctx).Unscoped().Delete(&models.Job{}, ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// AnonymizeJobs clears the creator of the user's remaining jobs
+func (r *repository) AnonymizeJobs(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Model(&models.Job{}).Where("created_by = ?", userID).Update("created_by", "").Error
+}
+
+// AnonymizeAnnotationAudits clears the user from annotation audit rows; the
+// changes themselves stay so training labels remain traceable
+func (r *repository) AnonymizeAnnotationAudits(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Model(&models.AnnotationAudit{}).Where("user_id = ?", userID).Update("user_id", "").Error
+}
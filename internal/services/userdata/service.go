@@ -0,0 +1,205 @@
+package userdata
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// service implements the Service interface
+type service struct {
+	repo        Repository
+	directory   string
+	secret      []byte
+	clipPolicy  ClipPolicy
+	clipRemover ClipRemover
+}
+
+// Option configures optional service behaviour
+type Option func(*service)
+
+// WithClipPolicy sets how a deleted user's clips are handled (default: anonymize)
+func WithClipPolicy(policy ClipPolicy) Option {
+	return func(s *service) {
+		s.clipPolicy = policy
+	}
+}
+
+// WithClipRemover sets the remover used when the clip policy is delete.
+// Without one, the delete policy falls back to anonymizing.
+func WithClipRemover(remover ClipRemover) Option {
+	return func(s *service) {
+		s.clipRemover = remover
+	}
+}
+
+// NewService creates a new user data service. Archives are written under directory
+// and download links are signed with secret.
+func NewService(repo Repository, directory string, secret []byte, opts ...Option) Service {
+	s := &service{
+		repo:       repo,
+		directory:  directory,
+		secret:     secret,
+		clipPolicy: ClipPolicyAnonymize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Collect gathers everything stored for the user
+func (s *service) Collect(ctx context.Context, userID string) (*Export, error) {
+	export := &Export{
+		UserID:     userID,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	var err error
+	if export.Subscriptions, err = s.repo.ListSubscriptions(ctx, userID); err != nil {
+		return nil, fmt.Errorf("loading subscriptions: %w", err)
+	}
+	if export.PlaybackProgress, err = s.repo.ListPlaybackProgress(ctx, userID); err != nil {
+		return nil, fmt.Errorf("loading playback progress: %w", err)
+	}
+	if export.ListeningHistory, err = s.repo.ListListeningHistory(ctx, userID); err != nil {
+		return nil, fmt.Errorf("loading listening history: %w", err)
+	}
+	if export.DailyListening, err = s.repo.ListDailyListening(ctx, userID); err != nil {
+		return nil, fmt.Errorf("loading daily listening: %w", err)
+	}
+	if export.Preferences, err = s.repo.GetPreferences(ctx, userID); err != nil {
+		return nil, fmt.Errorf("loading preferences: %w", err)
+	}
+	if export.Annotations, err = s.repo.ListClips(ctx, userID); err != nil {
+		return nil, fmt.Errorf("loading annotations: %w", err)
+	}
+	if export.SavedSearches, err = s.repo.ListSavedSearches(ctx, userID); err != nil {
+		return nil, fmt.Errorf("loading saved searches: %w", err)
+	}
+	if export.EpisodeReads, err = s.repo.ListEpisodeReads(ctx, userID); err != nil {
+		return nil, fmt.Errorf("loading inbox read state: %w", err)
+	}
+
+	return export, nil
+}
+
+// WriteArchive writes one JSON file per data set into a zip archive. The archive
+// is written to a temporary name and renamed so readers never see a partial file.
+func (s *service) WriteArchive(ctx context.Context, userID string, jobID uint) (int64, error) {
+	export, err := s.Collect(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(s.directory, 0o750); err != nil {
+		return 0, fmt.Errorf("creating export directory: %w", err)
+	}
+
+	path := s.ArchivePath(jobID)
+	tmpPath := path + ".partial"
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return 0, fmt.Errorf("creating archive: %w", err)
+	}
+
+	writeErr := writeZip(file, export)
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmpPath)
+		if writeErr != nil {
+			return 0, writeErr
+		}
+		return 0, fmt.Errorf("closing archive: %w", closeErr)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("finalizing archive: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading archive size: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// ArchivePath returns where the archive for an export job is stored
+func (s *service) ArchivePath(jobID uint) string {
+	return filepath.Join(s.directory, fmt.Sprintf("export_%d.zip", jobID))
+}
+
+// SignDownload returns an expiry and signature authorizing download of an export
+func (s *service) SignDownload(jobID uint, ttl time.Duration) (time.Time, string) {
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+	return expires, s.sign(jobID, expires.Unix())
+}
+
+// VerifyDownload checks a signature produced by SignDownload
+func (s *service) VerifyDownload(jobID uint, expires int64, signature string) error {
+	expected := s.sign(jobID, expires)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expires {
+		return ErrLinkExpired
+	}
+	return nil
+}
+
+func (s *service) sign(jobID uint, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strconv.FormatUint(uint64(jobID), 10) + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// writeZip encodes each section of the export as its own JSON file
+func writeZip(file *os.File, export *Export) error {
+	zw := zip.NewWriter(file)
+
+	entries := []struct {
+		name  string
+		value interface{}
+	}{
+		{"manifest.json", map[string]interface{}{
+			"user_id":     export.UserID,
+			"exported_at": export.ExportedAt,
+		}},
+		{"subscriptions.json", export.Subscriptions},
+		{"playback_progress.json", export.PlaybackProgress},
+		{"listening_history.json", export.ListeningHistory},
+		{"daily_listening.json", export.DailyListening},
+		{"preferences.json", export.Preferences},
+		{"annotations.json", export.Annotations},
+		{"saved_searches.json", export.SavedSearches},
+		{"episode_reads.json", export.EpisodeReads},
+	}
+
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			return fmt.Errorf("adding %s: %w", entry.name, err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(entry.value); err != nil {
+			return fmt.Errorf("encoding %s: %w", entry.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package userdata
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.Podcast{}, &models.Subscription{}, &models.Clip{},
+		&models.PlaybackProgress{}, &models.ListeningHistory{}, &models.DailyListening{}, &models.PlaybackEvent{},
+		&models.UserPreferences{}, &models.AccountDeletion{}, &models.Job{},
+		&models.Notification{}, &models.AnnotationAudit{},
+		&models.SavedSearch{}, &models.SavedSearchResult{}, &models.EpisodeRead{},
+		&models.AutoplayRules{},
+	))
+	return db
+}
+
+func seedUser(t *testing.T, db *gorm.DB, userID string) {
+	podcast := models.Podcast{PodcastIndexID: 42, Title: "Show", FeedURL: "https://example.com/feed.xml"}
+	require.NoError(t, db.Create(&podcast).Error)
+	require.NoError(t, db.Create(&models.Subscription{UserID: userID, PodcastID: podcast.ID}).Error)
+	require.NoError(t, db.Create(&models.PlaybackProgress{UserID: userID, PodcastIndexEpisodeID: 7, Position: 120}).Error)
+	require.NoError(t, db.Create(&models.UserPreferences{UserID: userID, PlaybackSpeed: 1.5, Theme: "dark"}).Error)
+	require.NoError(t, db.Create(&models.Clip{UUID: "clip-mine", PodcastIndexEpisodeID: 7, SourceEpisodeURL: "https://example.com/a.mp3", CreatedBy: userID}).Error)
+	require.NoError(t, db.Create(&models.Clip{UUID: "clip-other", PodcastIndexEpisodeID: 7, SourceEpisodeURL: "https://example.com/a.mp3", CreatedBy: "someone-else"}).Error)
+}
+
+func TestCollect_OnlyIncludesUserData(t *testing.T) {
+	db := setupTestDB(t)
+	seedUser(t, db, "user-1")
+	svc := NewService(NewRepository(db), t.TempDir(), []byte("secret"))
+
+	export, err := svc.Collect(context.Background(), "user-1")
+	require.NoError(t, err)
+
+	require.Len(t, export.Subscriptions, 1)
+	assert.Equal(t, int64(42), export.Subscriptions[0].PodcastIndexID)
+	require.Len(t, export.PlaybackProgress, 1)
+	assert.Equal(t, 120.0, export.PlaybackProgress[0].Position)
+	require.NotNil(t, export.Preferences)
+	assert.Equal(t, "dark", export.Preferences.Theme)
+	require.Len(t, export.Annotations, 1)
+	assert.Equal(t, "clip-mine", export.Annotations[0].UUID)
+
+	empty, err := svc.Collect(context.Background(), "nobody")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Subscriptions)
+	assert.Nil(t, empty.Preferences)
+}
+
+func TestWriteArchive_ContainsJSONFiles(t *testing.T) {
+	db := setupTestDB(t)
+	seedUser(t, db, "user-1")
+	svc := NewService(NewRepository(db), t.TempDir(), []byte("secret"))
+
+	size, err := svc.WriteArchive(context.Background(), "user-1", 5)
+	require.NoError(t, err)
+	assert.Positive(t, size)
+
+	reader, err := zip.OpenReader(svc.ArchivePath(5))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range reader.File {
+		files[f.Name] = f
+	}
+	for _, name := range []string{"manifest.json", "subscriptions.json", "playback_progress.json", "preferences.json", "annotations.json"} {
+		assert.Contains(t, files, name)
+	}
+
+	rc, err := files["annotations.json"].Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	var clips []models.Clip
+	require.NoError(t, json.NewDecoder(rc).Decode(&clips))
+	assert.Len(t, clips, 1)
+}
+
+func TestDownloadSignature(t *testing.T) {
+	svc := NewService(nil, t.TempDir(), []byte("secret"))
+
+	expires, signature := svc.SignDownload(9, time.Hour)
+	assert.NoError(t, svc.VerifyDownload(9, expires.Unix(), signature))
+
+	assert.ErrorIs(t, svc.VerifyDownload(10, expires.Unix(), signature), ErrInvalidSignature)
+	assert.ErrorIs(t, svc.VerifyDownload(9, expires.Unix()+1, signature), ErrInvalidSignature)
+
+	other := NewService(nil, t.TempDir(), []byte("different"))
+	assert.ErrorIs(t, other.VerifyDownload(9, expires.Unix(), signature), ErrInvalidSignature)
+
+	past, expired := svc.SignDownload(9, -time.Minute)
+	assert.ErrorIs(t, svc.VerifyDownload(9, past.Unix(), expired), ErrLinkExpired)
+}
+
+type recordingRemover struct {
+	db      *gorm.DB
+	removed []string
+}
+
+func (r *recordingRemover) DeleteClip(ctx context.Context, uuid string) error {
+	r.removed = append(r.removed, uuid)
+	return r.db.Where("uuid = ?", uuid).Delete(&models.Clip{}).Error
+}
+
+func TestScheduleAndCancelDeletion(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db), t.TempDir(), []byte("secret"))
+	ctx := context.Background()
+
+	_, err := svc.CancelDeletion(ctx, "user-1")
+	assert.ErrorIs(t, err, ErrDeletionNotFound)
+
+	first, err := svc.ScheduleDeletion(ctx, "user-1", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, models.AccountDeletionScheduled, first.Status)
+
+	// Repeating the request keeps the original schedule
+	again, err := svc.ScheduleDeletion(ctx, "user-1", 2*time.Hour)
+	require.NoError(t, err)
+	assert.True(t, first.ScheduledFor.Equal(again.ScheduledFor))
+
+	due, err := svc.DueDeletions(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = svc.DueDeletions(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	cancelled, err := svc.CancelDeletion(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.AccountDeletionCancelled, cancelled.Status)
+
+	_, err = svc.DeleteAccountData(ctx, "user-1")
+	assert.ErrorIs(t, err, ErrDeletionCancelled)
+
+	// A sweep that listed the request before the cancel must not queue it
+	assert.ErrorIs(t, svc.MarkDeletionQueued(ctx, "user-1", 2), ErrDeletionCancelled)
+	stored, err := svc.GetDeletion(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.AccountDeletionCancelled, stored.Status)
+	assert.Nil(t, stored.JobID)
+
+	// A queued deletion can no longer be cancelled
+	_, err = svc.ScheduleDeletion(ctx, "user-1", 0)
+	require.NoError(t, err)
+	require.NoError(t, svc.MarkDeletionQueued(ctx, "user-1", 3))
+	_, err = svc.CancelDeletion(ctx, "user-1")
+	assert.ErrorIs(t, err, ErrDeletionInProgress)
+}
+
+func TestDeleteAccountData_AnonymizesClips(t *testing.T) {
+	db := setupTestDB(t)
+	seedUser(t, db, "user-1")
+	svc := NewService(NewRepository(db), t.TempDir(), []byte("secret"))
+	ctx := context.Background()
+
+	_, err := svc.WriteArchive(ctx, "user-1", 1)
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.Job{Model: gorm.Model{ID: 1}, Type: models.JobTypeUserExport, Status: models.JobStatusCompleted, CreatedBy: "user-1"}).Error)
+	require.NoError(t, db.Create(&models.Notification{UserID: "user-1", Type: models.NotificationExportReady, Title: "Your data export is ready"}).Error)
+	require.NoError(t, db.Create(&models.AnnotationAudit{ClipUUID: "clip-mine", PodcastIndexEpisodeID: 1, UserID: "user-1", Action: models.AnnotationActionUpdate}).Error)
+
+	_, err = svc.ScheduleDeletion(ctx, "user-1", 0)
+	require.NoError(t, err)
+
+	summary, err := svc.DeleteAccountData(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), summary.Subscriptions)
+	assert.Equal(t, int64(1), summary.PlaybackProgress)
+	assert.Equal(t, int64(1), summary.Preferences)
+	assert.Equal(t, int64(1), summary.Notifications)
+	assert.Equal(t, int64(1), summary.ClipsAnonymized)
+	assert.Equal(t, int64(1), summary.Exports)
+
+	export, err := svc.Collect(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, export.Subscriptions)
+	assert.Empty(t, export.Annotations)
+
+	var clipCount int64
+	require.NoError(t, db.Model(&models.Clip{}).Count(&clipCount).Error)
+	assert.Equal(t, int64(2), clipCount)
+	assert.NoFileExists(t, svc.ArchivePath(1))
+
+	var audit models.AnnotationAudit
+	require.NoError(t, db.First(&audit).Error)
+	assert.Empty(t, audit.UserID, "audit rows are kept but no longer name the user")
+
+	deletion, err := svc.GetDeletion(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.AccountDeletionCompleted, deletion.Status)
+}
+
+func TestDeleteAccountData_DeletePolicy(t *testing.T) {
+	db := setupTestDB(t)
+	seedUser(t, db, "user-1")
+	remover := &recordingRemover{db: db}
+	svc := NewService(NewRepository(db), t.TempDir(), []byte("secret"),
+		WithClipPolicy(ClipPolicyDelete), WithClipRemover(remover))
+	ctx := context.Background()
+
+	_, err := svc.ScheduleDeletion(ctx, "user-1", 0)
+	require.NoError(t, err)
+
+	summary, err := svc.DeleteAccountData(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), summary.ClipsDeleted)
+	assert.Equal(t, []string{"clip-mine"}, remover.removed)
+
+	var remaining []models.Clip
+	require.NoError(t, db.Find(&remaining).Error)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "clip-other", remaining[0].UUID)
+}
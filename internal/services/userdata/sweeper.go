@@ -0,0 +1,89 @@
+package userdata
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// Sweeper periodically enqueues deletion jobs for accounts whose grace period has ended
+type Sweeper struct {
+	service    Service
+	jobService jobs.Service
+	interval   time.Duration
+	cancel     context.CancelFunc
+}
+
+// NewSweeper creates a new account deletion sweeper
+func NewSweeper(service Service, jobService jobs.Service, interval time.Duration) *Sweeper {
+	return &Sweeper{
+		service:    service,
+		jobService: jobService,
+		interval:   interval,
+	}
+}
+
+// Start runs a sweep immediately and then every interval until Stop is called
+func (s *Sweeper) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	s.Sweep(ctx)
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Sweep(ctx)
+			case <-ctx.Done():
+				log.Println("[INFO] Account deletion sweeper stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the sweeper
+func (s *Sweeper) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Sweep enqueues a deletion job for every due request
+func (s *Sweeper) Sweep(ctx context.Context) {
+	due, err := s.service.DueDeletions(ctx, time.Now().UTC())
+	if err != nil {
+		log.Printf("[ERROR] Failed to list due account deletions: %v", err)
+		return
+	}
+
+	for _, deletion := range due {
+		job, err := s.jobService.EnqueueUniqueJob(
+			ctx,
+			models.JobTypeAccountDeletion,
+			models.JobPayload{"user_id": deletion.UserID},
+			"user_id",
+		)
+		if err != nil {
+			log.Printf("[ERROR] Failed to enqueue account deletion for user %s: %v", deletion.UserID, err)
+			continue
+		}
+		if err := s.service.MarkDeletionQueued(ctx, deletion.UserID, job.ID); err != nil {
+			if errors.Is(err, ErrDeletionCancelled) {
+				// Cancelled after it was listed; drop the job we just enqueued
+				if _, err := s.jobService.CancelJob(ctx, job.ID); err != nil {
+					log.Printf("[WARN] Failed to cancel deletion job %d for user %s: %v", job.ID, deletion.UserID, err)
+				}
+				continue
+			}
+			log.Printf("[ERROR] Failed to mark account deletion queued for user %s: %v", deletion.UserID, err)
+		}
+	}
+}
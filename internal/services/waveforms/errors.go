@@ -11,4 +11,7 @@ var (
 
 	// ErrInvalidPeaksData is returned when peaks data is invalid
 	ErrInvalidPeaksData = errors.New("invalid peaks data")
+
+	// ErrCheckpointNotFound is returned when no partial waveform is recorded
+	ErrCheckpointNotFound = errors.New("waveform checkpoint not found")
 )
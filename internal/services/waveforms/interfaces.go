@@ -19,6 +19,12 @@ type WaveformService interface {
 
 	// WaveformExists checks if waveform data exists for an episode
 	WaveformExists(ctx context.Context, podcastIndexEpisodeID int64) (bool, error)
+
+	// SaveCheckpoint records the partial peaks of a waveform still being generated
+	SaveCheckpoint(ctx context.Context, checkpoint *models.WaveformCheckpoint) error
+
+	// GetCheckpoint retrieves the latest partial peaks for an episode
+	GetCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) (*models.WaveformCheckpoint, error)
 }
 
 // WaveformRepository defines the interface for waveform data access
@@ -37,4 +43,13 @@ type WaveformRepository interface {
 
 	// Exists checks if a waveform exists for an episode
 	Exists(ctx context.Context, podcastIndexEpisodeID int64) (bool, error)
+
+	// SaveCheckpoint creates or replaces the checkpoint for an episode
+	SaveCheckpoint(ctx context.Context, checkpoint *models.WaveformCheckpoint) error
+
+	// GetCheckpoint retrieves the checkpoint for an episode
+	GetCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) (*models.WaveformCheckpoint, error)
+
+	// DeleteCheckpoint removes the checkpoint for an episode, if any
+	DeleteCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) error
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/killallgit/player-api/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // repository implements WaveformRepository
@@ -76,3 +77,35 @@ func (r *repository) Exists(ctx context.Context, podcastIndexEpisodeID int64) (b
 
 	return count > 0, nil
 }
+
+// SaveCheckpoint creates or replaces the checkpoint for an episode
+func (r *repository) SaveCheckpoint(ctx context.Context, checkpoint *models.WaveformCheckpoint) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "podcast_index_episode_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"peaks_data", "duration", "progress", "updated_at"}),
+	}).Create(checkpoint).Error
+}
+
+// GetCheckpoint retrieves the checkpoint for an episode
+func (r *repository) GetCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) (*models.WaveformCheckpoint, error) {
+	var checkpoint models.WaveformCheckpoint
+	err := r.db.WithContext(ctx).
+		Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).
+		First(&checkpoint).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCheckpointNotFound
+		}
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// DeleteCheckpoint removes the checkpoint for an episode, if any
+func (r *repository) DeleteCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) error {
+	return r.db.WithContext(ctx).
+		Where("podcast_index_episode_id = ?", podcastIndexEpisodeID).
+		Delete(&models.WaveformCheckpoint{}).Error
+}
@@ -21,7 +21,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Run migrations
-	err = db.AutoMigrate(&models.Episode{}, &models.Waveform{})
+	err = db.AutoMigrate(&models.Episode{}, &models.Waveform{}, &models.WaveformCheckpoint{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -409,3 +409,41 @@ func TestRepository_UniqueConstraint(t *testing.T) {
 		t.Errorf("Retrieved waveform Duration = %v, want %v (should be first waveform)", retrieved.Duration, 300.0)
 	}
 }
+
+func TestRepository_SaveCheckpointUpserts(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	first := &models.WaveformCheckpoint{PodcastIndexEpisodeID: 7, Progress: 10, Duration: 60}
+	if err := first.SetPeaks([]float32{0.5}); err != nil {
+		t.Fatalf("Failed to set peaks: %v", err)
+	}
+	if err := repo.SaveCheckpoint(ctx, first); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+
+	second := &models.WaveformCheckpoint{PodcastIndexEpisodeID: 7, Progress: 40, Duration: 60}
+	if err := second.SetPeaks([]float32{0.5, 0.9, 1.0}); err != nil {
+		t.Fatalf("Failed to set peaks: %v", err)
+	}
+	if err := repo.SaveCheckpoint(ctx, second); err != nil {
+		t.Fatalf("Failed to replace checkpoint: %v", err)
+	}
+
+	got, err := repo.GetCheckpoint(ctx, 7)
+	if err != nil {
+		t.Fatalf("Failed to get checkpoint: %v", err)
+	}
+	peaks, _ := got.Peaks()
+	if got.Progress != 40 || len(peaks) != 3 {
+		t.Errorf("Expected replaced checkpoint, got progress=%d peaks=%v", got.Progress, peaks)
+	}
+
+	if err := repo.DeleteCheckpoint(ctx, 7); err != nil {
+		t.Fatalf("Failed to delete checkpoint: %v", err)
+	}
+	if _, err := repo.GetCheckpoint(ctx, 7); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("Expected ErrCheckpointNotFound, got %v", err)
+	}
+}
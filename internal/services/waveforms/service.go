@@ -58,7 +58,11 @@ func (s *service) SaveWaveform(ctx context.Context, waveform *models.Waveform) e
 
 	if exists {
 		log.Printf("[DEBUG] Updating existing waveform for Podcast Index Episode ID: %d", waveform.PodcastIndexEpisodeID)
-		return s.repo.Update(ctx, waveform)
+		if err := s.repo.Update(ctx, waveform); err != nil {
+			return err
+		}
+		s.clearCheckpoint(ctx, waveform.PodcastIndexEpisodeID)
+		return nil
 	}
 
 	log.Printf("[DEBUG] Creating new waveform for Podcast Index Episode ID: %d", waveform.PodcastIndexEpisodeID)
@@ -72,6 +76,8 @@ func (s *service) SaveWaveform(ctx context.Context, waveform *models.Waveform) e
 		}
 		return err
 	}
+
+	s.clearCheckpoint(ctx, waveform.PodcastIndexEpisodeID)
 	return nil
 }
 
@@ -95,3 +101,30 @@ func (s *service) WaveformExists(ctx context.Context, podcastIndexEpisodeID int6
 	log.Printf("[DEBUG] Checking if waveform exists for Podcast Index Episode ID %d: %t", podcastIndexEpisodeID, exists)
 	return exists, err
 }
+
+// SaveCheckpoint records the partial peaks of a waveform still being generated
+func (s *service) SaveCheckpoint(ctx context.Context, checkpoint *models.WaveformCheckpoint) error {
+	if checkpoint.PodcastIndexEpisodeID == 0 {
+		return ErrInvalidEpisodeID
+	}
+	if len(checkpoint.PeaksData) == 0 {
+		return ErrInvalidPeaksData
+	}
+	return s.repo.SaveCheckpoint(ctx, checkpoint)
+}
+
+// GetCheckpoint retrieves the latest partial peaks for an episode
+func (s *service) GetCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) (*models.WaveformCheckpoint, error) {
+	if podcastIndexEpisodeID == 0 {
+		return nil, ErrInvalidEpisodeID
+	}
+	return s.repo.GetCheckpoint(ctx, podcastIndexEpisodeID)
+}
+
+// clearCheckpoint drops partial peaks once the full waveform is stored. Failure
+// only leaves a stale row that is never read while the waveform exists.
+func (s *service) clearCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) {
+	if err := s.repo.DeleteCheckpoint(ctx, podcastIndexEpisodeID); err != nil {
+		log.Printf("[WARN] Failed to delete waveform checkpoint for episode %d: %v", podcastIndexEpisodeID, err)
+	}
+}
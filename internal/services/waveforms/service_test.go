@@ -10,14 +10,16 @@ import (
 
 // mockWaveformRepository is a mock implementation of WaveformRepository for testing
 type mockWaveformRepository struct {
-	waveforms map[int64]*models.Waveform
-	shouldErr bool
+	waveforms   map[int64]*models.Waveform
+	checkpoints map[int64]*models.WaveformCheckpoint
+	shouldErr   bool
 }
 
 func newMockWaveformRepository() *mockWaveformRepository {
 	return &mockWaveformRepository{
-		waveforms: make(map[int64]*models.Waveform),
-		shouldErr: false,
+		waveforms:   make(map[int64]*models.Waveform),
+		checkpoints: make(map[int64]*models.WaveformCheckpoint),
+		shouldErr:   false,
 	}
 }
 
@@ -75,6 +77,36 @@ func (m *mockWaveformRepository) Exists(ctx context.Context, podcastIndexEpisode
 	return exists, nil
 }
 
+func (m *mockWaveformRepository) SaveCheckpoint(ctx context.Context, checkpoint *models.WaveformCheckpoint) error {
+	if m.shouldErr {
+		return errors.New("mock database error")
+	}
+
+	m.checkpoints[checkpoint.PodcastIndexEpisodeID] = checkpoint
+	return nil
+}
+
+func (m *mockWaveformRepository) GetCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) (*models.WaveformCheckpoint, error) {
+	if m.shouldErr {
+		return nil, errors.New("mock database error")
+	}
+
+	checkpoint, exists := m.checkpoints[podcastIndexEpisodeID]
+	if !exists {
+		return nil, ErrCheckpointNotFound
+	}
+	return checkpoint, nil
+}
+
+func (m *mockWaveformRepository) DeleteCheckpoint(ctx context.Context, podcastIndexEpisodeID int64) error {
+	if m.shouldErr {
+		return errors.New("mock database error")
+	}
+
+	delete(m.checkpoints, podcastIndexEpisodeID)
+	return nil
+}
+
 func TestNewService(t *testing.T) {
 	repo := newMockWaveformRepository()
 	service := NewService(repo)
@@ -429,3 +461,46 @@ func TestService_WaveformExists(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Checkpoints(t *testing.T) {
+	repo := newMockWaveformRepository()
+	service := NewService(repo)
+	ctx := context.Background()
+
+	if _, err := service.GetCheckpoint(ctx, 42); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Fatalf("Expected ErrCheckpointNotFound, got %v", err)
+	}
+
+	checkpoint := &models.WaveformCheckpoint{PodcastIndexEpisodeID: 42, Duration: 120, Progress: 30}
+	if err := checkpoint.SetPeaks([]float32{0.2, 1.0, 0.5}); err != nil {
+		t.Fatalf("Failed to set peaks: %v", err)
+	}
+	if err := service.SaveCheckpoint(ctx, checkpoint); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+
+	got, err := service.GetCheckpoint(ctx, 42)
+	if err != nil {
+		t.Fatalf("Failed to get checkpoint: %v", err)
+	}
+	peaks, err := got.Peaks()
+	if err != nil || len(peaks) != 3 || got.Progress != 30 {
+		t.Errorf("Unexpected checkpoint: progress=%d peaks=%v err=%v", got.Progress, peaks, err)
+	}
+
+	// Saving the full waveform drops the checkpoint
+	waveform := &models.Waveform{PodcastIndexEpisodeID: 42, Duration: 120}
+	if err := waveform.SetPeaks([]float32{0.1, 0.2}); err != nil {
+		t.Fatalf("Failed to set peaks: %v", err)
+	}
+	if err := service.SaveWaveform(ctx, waveform); err != nil {
+		t.Fatalf("Failed to save waveform: %v", err)
+	}
+	if _, err := service.GetCheckpoint(ctx, 42); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("Expected checkpoint to be cleared, got %v", err)
+	}
+
+	if err := service.SaveCheckpoint(ctx, &models.WaveformCheckpoint{PodcastIndexEpisodeID: 42}); !errors.Is(err, ErrInvalidPeaksData) {
+		t.Errorf("Expected ErrInvalidPeaksData for empty peaks, got %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+package webhooks
+
+import "errors"
+
+var (
+	// ErrInvalidCallbackURL is returned for callback URLs that are malformed,
+	// not http(s), or point at a private or loopback address
+	ErrInvalidCallbackURL = errors.New("invalid callback URL")
+)
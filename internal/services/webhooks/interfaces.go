@@ -0,0 +1,93 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Event names the outcome a callback reports
+type Event string
+
+const (
+	EventJobCompleted Event = "job.completed"
+	EventJobFailed    Event = "job.failed"
+
+	// EventSavedSearchResults reports podcasts a saved search found since its last run
+	EventSavedSearchResults Event = "saved_search.results"
+)
+
+// Signature headers sent with every callback. The signature is the hex
+// HMAC-SHA256 of "<timestamp>.<body>" under the configured secret.
+const (
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+)
+
+// Payload is the JSON body POSTed to a callback URL
+type Payload struct {
+	Event Event                  `json:"event"`
+	Job   *JobSummary            `json:"job,omitempty"`  // Set for job events
+	Data  map[string]interface{} `json:"data,omitempty"` // Identifiers of what the job produced, e.g. episode_id or dataset_id, or the event's details
+}
+
+// JobSummary is the part of a job a callback receiver sees
+type JobSummary struct {
+	ID          uint       `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	ErrorType   string     `json:"error_type,omitempty"`
+	ErrorCode   string     `json:"error_code,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Config controls delivery
+type Config struct {
+	Secret       []byte        // HMAC key for the signature header
+	Timeout      time.Duration // Per-attempt request timeout
+	MaxAttempts  int           // Deliveries tried before giving up
+	RetryBackoff time.Duration // Wait before the second attempt, doubling after each failure
+	AllowPrivate bool          // Permit loopback/private targets (local development only)
+}
+
+// Service registers callback URLs on jobs and calls them when the jobs finish
+type Service interface {
+	// ValidateURL checks that a callback URL is acceptable before a job is queued
+	ValidateURL(raw string) error
+
+	// Register attaches a callback URL to a job
+	Register(ctx context.Context, jobID uint, url string) error
+
+	// NotifyJobCompleted delivers job.completed to the job's callbacks
+	NotifyJobCompleted(ctx context.Context, job *models.Job) error
+
+	// NotifyJobFailed delivers job.failed to the job's callbacks. Called only
+	// once a job has failed for good, not on failures that will be retried.
+	NotifyJobFailed(ctx context.Context, job *models.Job) error
+
+	// CatchUp delivers the outcome of a job that has already finished to
+	// callbacks not notified yet. Call it with the job's state read after
+	// Register: a job can finish before its callback is registered, and then
+	// the worker's notification has already gone out without it.
+	CatchUp(ctx context.Context, job *models.Job) error
+
+	// Send delivers an event that isn't tied to a job to url, signed and
+	// retried like job callbacks. Attempts are logged rather than stored.
+	Send(ctx context.Context, url string, event Event, data map[string]interface{}) error
+}
+
+// Repository defines the interface for callback persistence
+type Repository interface {
+	Create(ctx context.Context, callback *models.JobCallback) error
+
+	// ListUndelivered returns a job's callbacks that have not been delivered
+	ListUndelivered(ctx context.Context, jobID uint) ([]models.JobCallback, error)
+
+	// Claim marks a callback notified, reporting false when it already was
+	Claim(ctx context.Context, id uint) (bool, error)
+
+	// RecordAttempt counts a delivery attempt, marking the callback delivered on success
+	RecordAttempt(ctx context.Context, id uint, delivered bool, lastError string) error
+}
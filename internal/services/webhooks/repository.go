@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// repository implements the Repository interface using GORM
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new webhooks repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, callback *models.JobCallback) error {
+	return r.db.WithContext(ctx).Create(callback).Error
+}
+
+func (r *repository) ListUndelivered(ctx context.Context, jobID uint) ([]models.JobCallback, error) {
+	var callbacks []models.JobCallback
+	err := r.db.WithContext(ctx).
+		Where("job_id = ? AND delivered_at IS NULL", jobID).
+		Order("id").
+		Find(&callbacks).Error
+	return callbacks, err
+}
+
+func (r *repository) Claim(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.JobCallback{}).
+		Where("id = ? AND notified_at IS NULL", id).
+		Update("notified_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *repository) RecordAttempt(ctx context.Context, id uint, delivered bool, lastError string) error {
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+	}
+	if delivered {
+		updates["delivered_at"] = time.Now()
+	}
+	return r.db.WithContext(ctx).Model(&models.JobCallback{}).Where("id = ?", id).Updates(updates).Error
+}
@@ -0,0 +1,287 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+)
+
+// Delivery defaults used when Config leaves them unset
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxAttempts  = 3
+	DefaultRetryBackoff = 2 * time.Second
+)
+
+// subjectKeys are the job payload and result fields copied into Payload.Data
+var subjectKeys = []string{"episode_id", "clip_uuid", "dataset_id", "total_samples"}
+
+// service implements the Service interface
+type service struct {
+	repo   Repository
+	cfg    Config
+	client *http.Client
+}
+
+// NewService creates a new webhooks service
+func NewService(repo Repository, cfg Config) Service {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivate {
+		// Checked at connect time as well as in ValidateURL, so a hostname
+		// that later resolves to an internal address is still refused
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip != nil && isPrivate(ip) {
+				return fmt.Errorf("%w: %s is not a public address", ErrInvalidCallbackURL, host)
+			}
+			return nil
+		}
+	}
+
+	return &service{
+		repo: repo,
+		cfg:  cfg,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			// Receivers answer directly; a redirect could point anywhere
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// ValidateURL accepts absolute http(s) URLs whose host is not a private,
+// loopback or link-local address, unless AllowPrivate is set
+func (s *service) ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: must be an absolute http or https URL", ErrInvalidCallbackURL)
+	}
+	if len(raw) > 2048 {
+		return fmt.Errorf("%w: longer than 2048 characters", ErrInvalidCallbackURL)
+	}
+	if s.cfg.AllowPrivate {
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s is not a public host", ErrInvalidCallbackURL, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivate(ip) {
+		return fmt.Errorf("%w: %s is not a public address", ErrInvalidCallbackURL, host)
+	}
+	return nil
+}
+
+// Register attaches a callback URL to a job
+func (s *service) Register(ctx context.Context, jobID uint, callbackURL string) error {
+	if err := s.ValidateURL(callbackURL); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, &models.JobCallback{JobID: jobID, URL: callbackURL})
+}
+
+// NotifyJobCompleted delivers job.completed to the job's callbacks
+func (s *service) NotifyJobCompleted(ctx context.Context, job *models.Job) error {
+	return s.notify(ctx, EventJobCompleted, job)
+}
+
+// NotifyJobFailed delivers job.failed to the job's callbacks
+func (s *service) NotifyJobFailed(ctx context.Context, job *models.Job) error {
+	return s.notify(ctx, EventJobFailed, job)
+}
+
+// CatchUp delivers a finished job's outcome to callbacks not notified yet
+func (s *service) CatchUp(ctx context.Context, job *models.Job) error {
+	if !job.IsTerminal() {
+		return nil
+	}
+	if job.Status == models.JobStatusCompleted {
+		return s.notify(ctx, EventJobCompleted, job)
+	}
+	return s.notify(ctx, EventJobFailed, job)
+}
+
+// notify looks up the job's callbacks and delivers to them in the background,
+// so a slow receiver never holds up the worker that finished the job. Each
+// callback is claimed first, so it is sent once even when the worker and
+// CatchUp both get to it.
+func (s *service) notify(ctx context.Context, event Event, job *models.Job) error {
+	callbacks, err := s.repo.ListUndelivered(ctx, job.ID)
+	if err != nil {
+		return fmt.Errorf("listing callbacks for job %d: %w", job.ID, err)
+	}
+	if len(callbacks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(newPayload(event, job))
+	if err != nil {
+		return fmt.Errorf("encoding callback payload: %w", err)
+	}
+
+	for _, callback := range callbacks {
+		claimed, err := s.repo.Claim(ctx, callback.ID)
+		if err != nil {
+			return fmt.Errorf("claiming callback %d: %w", callback.ID, err)
+		}
+		if !claimed {
+			continue
+		}
+		go s.deliver(callback.URL, event, body, fmt.Sprintf("callback %d for job %d", callback.ID, callback.JobID),
+			func(delivered bool, lastError string) {
+				if err := s.repo.RecordAttempt(context.Background(), callback.ID, delivered, lastError); err != nil {
+					log.Printf("[WARN] Failed to record callback %d attempt: %v", callback.ID, err)
+				}
+			})
+	}
+	return nil
+}
+
+// Send delivers an event that isn't tied to a job to url in the background
+func (s *service) Send(_ context.Context, targetURL string, event Event, data map[string]interface{}) error {
+	if err := s.ValidateURL(targetURL); err != nil {
+		return err
+	}
+	body, err := json.Marshal(Payload{Event: event, Data: data})
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+	go s.deliver(targetURL, event, body, fmt.Sprintf("%s webhook", event), nil)
+	return nil
+}
+
+// deliver POSTs body to one URL, retrying with exponential backoff. record,
+// if set, is told the outcome of every attempt; label names the delivery in logs.
+func (s *service) deliver(targetURL string, event Event, body []byte, label string, record func(delivered bool, lastError string)) {
+	backoff := s.cfg.RetryBackoff
+	for attempt := 1; attempt <= s.cfg.MaxAttempts; attempt++ {
+		err := s.post(targetURL, event, body)
+
+		if record != nil {
+			lastError := ""
+			if err != nil {
+				lastError = err.Error()
+				if len(lastError) > 500 {
+					lastError = lastError[:500]
+				}
+			}
+			record(err == nil, lastError)
+		}
+
+		if err == nil {
+			log.Printf("[DEBUG] Delivered %s to %s", event, label)
+			return
+		}
+		log.Printf("[WARN] %s failed (attempt %d/%d): %v", label, attempt, s.cfg.MaxAttempts, err)
+
+		if attempt < s.cfg.MaxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+func (s *service) post(callbackURL string, event Event, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(event))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(s.cfg.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("receiver answered %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>", the value of the
+// signature header without its "sha256=" prefix. Receivers recompute it to
+// verify a callback.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newPayload(event Event, job *models.Job) Payload {
+	payload := Payload{
+		Event: event,
+		Job: &JobSummary{
+			ID:          job.ID,
+			Type:        string(job.Type),
+			Status:      string(job.Status),
+			CompletedAt: job.CompletedAt,
+		},
+	}
+	if event == EventJobFailed {
+		payload.Job.ErrorType = job.ErrorType
+		payload.Job.ErrorCode = job.ErrorCode
+	}
+
+	for _, key := range subjectKeys {
+		if value, ok := job.Payload[key]; ok {
+			setData(&payload, key, value)
+		} else if value, ok := job.Result[key]; ok {
+			setData(&payload, key, value)
+		}
+	}
+	return payload
+}
+
+func setData(payload *Payload, key string, value interface{}) {
+	if payload.Data == nil {
+		payload.Data = make(map[string]interface{})
+	}
+	payload.Data[key] = value
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast()
+}
@@ -0,0 +1,241 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var testSecret = []byte("test-secret")
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	// Deliveries record attempts from their own goroutines; keep them on the
+	// one connection that holds the in-memory database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&models.JobCallback{}))
+	return db
+}
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+func newTestService(t *testing.T, db *gorm.DB) Service {
+	return NewService(NewRepository(db), Config{
+		Secret:       testSecret,
+		Timeout:      2 * time.Second,
+		MaxAttempts:  3,
+		RetryBackoff: 10 * time.Millisecond,
+		AllowPrivate: true,
+	})
+}
+
+func waitForDelivery(t *testing.T, db *gorm.DB, jobID uint) models.JobCallback {
+	t.Helper()
+	var callback models.JobCallback
+	require.Eventually(t, func() bool {
+		return db.Where("job_id = ? AND delivered_at IS NOT NULL", jobID).First(&callback).Error == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	return callback
+}
+
+func TestNotifyJobCompleted_SignsPayload(t *testing.T) {
+	deliveries := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- received{header: r.Header.Clone(), body: body}
+	}))
+	defer server.Close()
+
+	db := setupTestDB(t)
+	svc := newTestService(t, db)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Register(ctx, 7, server.URL+"/hook"))
+
+	job := &models.Job{
+		Model:   gorm.Model{ID: 7},
+		Type:    models.JobTypeTranscriptionGeneration,
+		Status:  models.JobStatusCompleted,
+		Payload: models.JobPayload{"episode_id": 123},
+	}
+	require.NoError(t, svc.NotifyJobCompleted(ctx, job))
+
+	var got received
+	select {
+	case got = <-deliveries:
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+
+	assert.Equal(t, string(EventJobCompleted), got.header.Get(HeaderEvent))
+	timestamp := got.header.Get(HeaderTimestamp)
+	require.NotEmpty(t, timestamp)
+	assert.Equal(t, "sha256="+Sign(testSecret, timestamp, got.body), got.header.Get(HeaderSignature))
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(got.body, &payload))
+	assert.Equal(t, EventJobCompleted, payload.Event)
+	assert.Equal(t, uint(7), payload.Job.ID)
+	assert.Equal(t, "completed", payload.Job.Status)
+	assert.EqualValues(t, 123, payload.Data["episode_id"])
+
+	callback := waitForDelivery(t, db, 7)
+	assert.Equal(t, 1, callback.Attempts)
+	assert.Empty(t, callback.LastError)
+}
+
+func TestNotifyJobFailed_RetriesUntilAccepted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	db := setupTestDB(t)
+	svc := newTestService(t, db)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Register(ctx, 9, server.URL))
+	job := &models.Job{
+		Model:     gorm.Model{ID: 9},
+		Type:      models.JobTypeDatasetGeneration,
+		Status:    models.JobStatusPermanentlyFailed,
+		ErrorType: "not_found",
+		ErrorCode: "dataset_empty",
+	}
+	require.NoError(t, svc.NotifyJobFailed(ctx, job))
+
+	callback := waitForDelivery(t, db, 9)
+	assert.Equal(t, 3, callback.Attempts)
+	assert.EqualValues(t, 3, calls.Load())
+
+	// Delivered callbacks are not sent again
+	require.NoError(t, svc.NotifyJobFailed(ctx, job))
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestNotify_NoCallbacks(t *testing.T) {
+	db := setupTestDB(t)
+	svc := newTestService(t, db)
+
+	require.NoError(t, svc.NotifyJobCompleted(context.Background(), &models.Job{Model: gorm.Model{ID: 1}}))
+}
+
+func TestValidateURL(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)), Config{Secret: testSecret})
+
+	valid := []string{
+		"https://pipeline.example.com/hooks/jobs",
+		"http://93.184.216.34:8080/callback",
+	}
+	for _, raw := range valid {
+		assert.NoError(t, svc.ValidateURL(raw), raw)
+	}
+
+	invalid := []string{
+		"",
+		"ftp://example.com/hook",
+		"/relative/path",
+		"https://localhost/hook",
+		"http://api.localhost/hook",
+		"http://127.0.0.1:9000/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"https://example.com/" + strings.Repeat("a", 2048),
+	}
+	for _, raw := range invalid {
+		err := svc.ValidateURL(raw)
+		assert.True(t, errors.Is(err, ErrInvalidCallbackURL), raw)
+	}
+}
+
+func TestRegister_RejectsInvalidURL(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db), Config{Secret: testSecret})
+
+	err := svc.Register(context.Background(), 1, "http://127.0.0.1/hook")
+	assert.ErrorIs(t, err, ErrInvalidCallbackURL)
+
+	var count int64
+	require.NoError(t, db.Model(&models.JobCallback{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestNewPayload_SubjectFromResult(t *testing.T) {
+	job := &models.Job{
+		Model:  gorm.Model{ID: 3},
+		Type:   models.JobTypeDatasetGeneration,
+		Status: models.JobStatusCompleted,
+		Result: models.JobResult{"dataset_id": "ds-1", "total_samples": 12, "archive_path": "/data/ds-1.zip"},
+	}
+
+	payload := newPayload(EventJobCompleted, job)
+	assert.Equal(t, "ds-1", payload.Data["dataset_id"])
+	assert.Equal(t, 12, payload.Data["total_samples"])
+	assert.NotContains(t, payload.Data, "archive_path")
+	assert.Empty(t, payload.Job.ErrorCode)
+}
+
+func TestCatchUp_DeliversToCallbackRegisteredAfterCompletion(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	db := setupTestDB(t)
+	svc := newTestService(t, db)
+	ctx := context.Background()
+
+	job := &models.Job{
+		Model:  gorm.Model{ID: 11},
+		Type:   models.JobTypeTranscriptionGeneration,
+		Status: models.JobStatusProcessing,
+	}
+
+	// Registered while the job runs: nothing to catch up on yet
+	require.NoError(t, svc.Register(ctx, 11, server.URL))
+	require.NoError(t, svc.CatchUp(ctx, job))
+
+	// The job finishes; the worker's notification and a racing CatchUp
+	// deliver the callback once between them
+	job.Status = models.JobStatusCompleted
+	require.NoError(t, svc.NotifyJobCompleted(ctx, job))
+	require.NoError(t, svc.CatchUp(ctx, job))
+	waitForDelivery(t, db, 11)
+
+	// Registered after the worker already notified
+	require.NoError(t, svc.Register(ctx, 11, server.URL+"/late"))
+	require.NoError(t, svc.CatchUp(ctx, job))
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 2, calls.Load(), "each callback is sent once")
+}
@@ -0,0 +1,94 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/userdata"
+)
+
+// AccountDeletionProcessor removes a user's data once their grace period has ended
+type AccountDeletionProcessor struct {
+	jobService      jobs.Service
+	userDataService userdata.Service
+}
+
+// NewAccountDeletionProcessor creates a new account deletion processor
+func NewAccountDeletionProcessor(jobService jobs.Service, userDataService userdata.Service) *AccountDeletionProcessor {
+	return &AccountDeletionProcessor{
+		jobService:      jobService,
+		userDataService: userDataService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *AccountDeletionProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeAccountDeletion
+}
+
+// ProcessJob deletes the data of the user named in the payload
+func (p *AccountDeletionProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	userID, _ := job.Payload["user_id"].(string)
+	if userID == "" {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			"user_id not found in payload",
+			fmt.Errorf("user_id not found in payload"),
+		)
+	}
+
+	log.Printf("[DEBUG] Processing account deletion job %d", job.ID)
+
+	summary, err := p.userDataService.DeleteAccountData(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, userdata.ErrDeletionCancelled):
+			if err := p.jobService.CompleteJob(ctx, job.ID, models.JobResult{"skipped": "cancelled"}); err != nil {
+				return fmt.Errorf("failed to complete job: %w", err)
+			}
+			return nil
+		case errors.Is(err, userdata.ErrDeletionNotFound):
+			return models.NewNotFoundError(
+				"deletion_not_found",
+				"No deletion request for user",
+				err.Error(),
+				err,
+			)
+		default:
+			return models.NewSystemError(
+				"deletion_failed",
+				"Failed to delete account data",
+				err.Error(),
+				err,
+			)
+		}
+	}
+
+	jobResult := models.JobResult{
+		"subscriptions":     summary.Subscriptions,
+		"playback_progress": summary.PlaybackProgress,
+		"listening_history": summary.ListeningHistory,
+		"daily_listening":   summary.DailyListening,
+		"preferences":       summary.Preferences,
+		"notifications":     summary.Notifications,
+		"clips_anonymized":  summary.ClipsAnonymized,
+		"clips_deleted":     summary.ClipsDeleted,
+		"exports":           summary.Exports,
+	}
+
+	if err := p.jobService.CompleteJob(ctx, job.ID, jobResult); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[INFO] Account deletion job %d completed", job.ID)
+	return nil
+}
@@ -0,0 +1,139 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/audiocache"
+	"github.com/killallgit/player-api/internal/services/episodes"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
+)
+
+// AudioCacheProcessor downloads an episode's audio into the cache. Dataset
+// generation queues it for episodes whose clips it had to skip.
+type AudioCacheProcessor struct {
+	jobService        jobs.Service
+	episodeService    episodes.EpisodeService
+	audioCacheService audiocache.Service
+}
+
+// NewAudioCacheProcessor creates a new audio cache processor
+func NewAudioCacheProcessor(
+	jobService jobs.Service,
+	episodeService episodes.EpisodeService,
+	audioCacheService audiocache.Service,
+) *AudioCacheProcessor {
+	return &AudioCacheProcessor{
+		jobService:        jobService,
+		episodeService:    episodeService,
+		audioCacheService: audioCacheService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *AudioCacheProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeAudioCache
+}
+
+// ProcessJob caches the episode's audio, or finds it already cached
+func (p *AudioCacheProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	log.Printf("[DEBUG] Processing audio cache job %d", job.ID)
+
+	episodeID, err := p.parseEpisodeID(job.Payload)
+	if err != nil {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			fmt.Sprintf("Failed to parse episode ID: %v", err),
+			err,
+		)
+	}
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 10); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	episode, err := p.episodeService.GetEpisodeByPodcastIndexID(ctx, episodeID)
+	if err != nil {
+		return models.NewNotFoundError(
+			"episode_not_found",
+			fmt.Sprintf("Episode %d not found", episodeID),
+			err.Error(),
+			err,
+		)
+	}
+	if episode.AudioURL == "" {
+		return models.NewNotFoundError(
+			"no_audio_url",
+			fmt.Sprintf("Episode %d has no audio URL", episodeID),
+			"The episode's feed does not list an enclosure to cache",
+			fmt.Errorf("episode %d has no audio URL", episodeID),
+		)
+	}
+
+	// Also brings renditions processed in an older format up to date
+	cache, err := p.audioCacheService.GetProcessedAudio(ctx, episodeID, episode.AudioURL)
+	if errors.Is(err, ffmpeg.ErrResourceLimit) {
+		return models.NewResourceLimitError(
+			"ffmpeg_resource_limit",
+			"Processing episode audio exceeded resource limits",
+			err.Error(),
+			err,
+		)
+	}
+	if err != nil {
+		return models.NewDownloadError(
+			"audio_cache_failed",
+			"Failed to cache episode audio",
+			err.Error(),
+			err,
+		)
+	}
+
+	result := models.JobResult{
+		"episode_id": episodeID,
+		"size_bytes": cache.OriginalSize,
+	}
+	if err := p.jobService.CompleteJob(ctx, job.ID, result); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] Audio cached for episode %d", episodeID)
+	return nil
+}
+
+// parseEpisodeID extracts the episode ID from the job payload
+func (p *AudioCacheProcessor) parseEpisodeID(payload models.JobPayload) (int64, error) {
+	episodeIDValue, exists := payload["episode_id"]
+	if !exists {
+		return 0, fmt.Errorf("episode_id not found in payload")
+	}
+
+	switch v := episodeIDValue.(type) {
+	case float64:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return int64(v), nil
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid episode_id string: %s", v)
+		}
+		return id, nil
+	default:
+		return 0, fmt.Errorf("invalid episode_id type: %T", v)
+	}
+}
@@ -2,6 +2,7 @@ package workers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/internal/services/clips"
 	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
 	"gorm.io/gorm"
 )
 
@@ -114,17 +116,12 @@ func (p *ClipExtractionProcessor) ProcessJob(ctx context.Context, job *models.Jo
 	})
 
 	if err != nil {
-		errMsg := err.Error()
 		log.Printf("[ERROR] Clip extraction failed for %s: %v", clipUUID, err)
 
-		p.db.Model(&clip).Updates(map[string]interface{}{
-			"status":        "failed",
-			"error_message": errMsg,
-			"updated_at":    time.Now(),
-		})
-
 		// Return classified error for proper retry handling
-		return p.classifyExtractionError(err, clipUUID)
+		jobErr := p.classifyExtractionError(err, clipUUID)
+		p.markFailed(&clip, err.Error(), jobErr)
+		return jobErr
 	}
 
 	defer func() {
@@ -139,18 +136,14 @@ func (p *ClipExtractionProcessor) ProcessJob(ctx context.Context, job *models.Jo
 
 	file, err := os.Open(result.FilePath)
 	if err != nil {
-		errMsg := fmt.Sprintf("failed to open extracted file: %v", err)
-		p.db.Model(&clip).Updates(map[string]interface{}{
-			"status":        "failed",
-			"error_message": errMsg,
-			"updated_at":    time.Now(),
-		})
-		return models.NewSystemError(
+		jobErr := models.NewSystemError(
 			"file_open_error",
 			"Failed to open extracted audio file",
 			err.Error(),
 			err,
 		)
+		p.markFailed(&clip, fmt.Sprintf("failed to open extracted file: %v", err), jobErr)
+		return jobErr
 	}
 	defer file.Close()
 
@@ -164,30 +157,40 @@ func (p *ClipExtractionProcessor) ProcessJob(ctx context.Context, job *models.Jo
 		)
 	}
 	if err := p.storage.SaveClip(ctx, clip.Label, *clip.ClipFilename, file); err != nil {
-		errMsg := fmt.Sprintf("failed to save clip: %v", err)
-		p.db.Model(&clip).Updates(map[string]interface{}{
-			"status":        "failed",
-			"error_message": errMsg,
-			"updated_at":    time.Now(),
-		})
-		return models.NewSystemError(
+		jobErr := models.NewSystemError(
 			"storage_error",
 			"Failed to save clip to storage",
 			err.Error(),
 			err,
 		)
+		p.markFailed(&clip, fmt.Sprintf("failed to save clip: %v", err), jobErr)
+		return jobErr
 	}
 
 	if err := p.jobService.UpdateProgress(ctx, job.ID, 85); err != nil {
 		log.Printf("[WARN] Failed to update job progress: %v", err)
 	}
 
+	// Storage may have kept an earlier extraction of the same audio
+	digest, size, err := clips.StoredDigest(ctx, p.storage, clip.Label, *clip.ClipFilename)
+	if err != nil {
+		return models.NewSystemError(
+			"storage_error",
+			"Failed to read back stored clip",
+			err.Error(),
+			err,
+		)
+	}
+
 	if err := p.db.Model(&clip).Updates(map[string]interface{}{
 		"status":          "ready",
 		"clip_duration":   result.Duration,
-		"clip_size_bytes": result.SizeBytes,
+		"clip_size_bytes": size,
+		"clip_sha256":     digest,
 		"extracted":       true,
 		"error_message":   nil,
+		"error_type":      nil,
+		"error_code":      nil,
 		"updated_at":      time.Now(),
 	}).Error; err != nil {
 		log.Printf("[ERROR] Failed to update clip record: %v", err)
@@ -211,7 +214,7 @@ func (p *ClipExtractionProcessor) ProcessJob(ctx context.Context, job *models.Jo
 		"clip_uuid":      clipUUID,
 		"label":          clip.Label,
 		"duration":       result.Duration,
-		"size_bytes":     result.SizeBytes,
+		"size_bytes":     size,
 		"sample_rate":    result.SampleRate,
 		"channels":       result.Channels,
 		"source_url":     clip.SourceEpisodeURL,
@@ -256,9 +259,31 @@ func (p *ClipExtractionProcessor) parseClipUUID(payload models.JobPayload) (stri
 	return clipUUID, nil
 }
 
-func (p *ClipExtractionProcessor) classifyExtractionError(err error, clipUUID string) error {
+// markFailed records a failed extraction on the clip along with how it was
+// classified, so clip responses can tell users why
+func (p *ClipExtractionProcessor) markFailed(clip *models.Clip, errMsg string, jobErr *models.StructuredJobError) {
+	p.db.Model(clip).Updates(map[string]interface{}{
+		"status":        "failed",
+		"error_message": errMsg,
+		"error_type":    string(jobErr.Type),
+		"error_code":    jobErr.Code,
+		"updated_at":    time.Now(),
+	})
+}
+
+func (p *ClipExtractionProcessor) classifyExtractionError(err error, clipUUID string) *models.StructuredJobError {
 	errMsg := err.Error()
 
+	// Sandbox limits: the same clip would be killed again, so don't retry
+	if errors.Is(err, ffmpeg.ErrResourceLimit) {
+		return models.NewResourceLimitError(
+			"ffmpeg_resource_limit",
+			fmt.Sprintf("Extracting clip %s exceeded resource limits", clipUUID),
+			errMsg,
+			err,
+		)
+	}
+
 	if containsAny(errMsg, []string{"download", "http", "403", "404", "timeout", "connection"}) {
 		return models.NewDownloadError(
 			"download_failed",
@@ -0,0 +1,93 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// ClipReextractionProcessor re-extracts clips queued by POST /clips/reextract
+type ClipReextractionProcessor struct {
+	jobService  jobs.Service
+	clipService clips.Service
+}
+
+// NewClipReextractionProcessor creates a new clip re-extraction processor
+func NewClipReextractionProcessor(jobService jobs.Service, clipService clips.Service) *ClipReextractionProcessor {
+	return &ClipReextractionProcessor{
+		jobService:  jobService,
+		clipService: clipService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *ClipReextractionProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeClipReextraction
+}
+
+// ProcessJob re-extracts the clips selected by the payload, which holds
+// clips.ExportFilters. Progress follows the clips done; clips that fail don't
+// fail the job, they are counted in its result.
+func (p *ClipReextractionProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	var filters clips.ExportFilters
+	raw, err := json.Marshal(job.Payload)
+	if err == nil {
+		err = json.Unmarshal(raw, &filters)
+	}
+	if err != nil {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			fmt.Sprintf("Failed to parse clip filters: %v", err),
+			err,
+		)
+	}
+
+	log.Printf("[DEBUG] Processing clip re-extraction job %d", job.ID)
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 5); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	lastProgress := 5
+	result, err := p.clipService.ReextractClips(ctx, filters, func(done, total int) {
+		// 5-95%, written only when the percentage moves
+		progress := 5 + 90*done/total
+		if progress == lastProgress {
+			return
+		}
+		lastProgress = progress
+		if err := p.jobService.UpdateProgress(ctx, job.ID, progress); err != nil {
+			log.Printf("[WARN] Failed to update job progress: %v", err)
+		}
+	})
+	if err != nil {
+		return models.NewSystemError(
+			"reextraction_failed",
+			"Failed to re-extract clips",
+			err.Error(),
+			err,
+		)
+	}
+
+	jobResult := models.JobResult{
+		"matched":     result.Matched,
+		"reextracted": result.Reextracted,
+		"failed":      result.Failed,
+	}
+	if err := p.jobService.CompleteJob(ctx, job.ID, jobResult); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] Clip re-extraction job %d completed (%d/%d clips)", job.ID, result.Reextracted, result.Matched)
+	return nil
+}
@@ -0,0 +1,79 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/clips"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// ClipVerificationProcessor verifies stored clip files queued by POST /admin/clips/verify
+type ClipVerificationProcessor struct {
+	jobService  jobs.Service
+	clipService clips.Service
+}
+
+// NewClipVerificationProcessor creates a new clip verification processor
+func NewClipVerificationProcessor(jobService jobs.Service, clipService clips.Service) *ClipVerificationProcessor {
+	return &ClipVerificationProcessor{
+		jobService:  jobService,
+		clipService: clipService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *ClipVerificationProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeClipVerification
+}
+
+// ProcessJob checks every ready clip's file, applying the payload's repair
+// mode to broken clips. The counts and issues go in the job result.
+func (p *ClipVerificationProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	repair, _ := job.GetPayloadString("repair")
+	if !clips.IsValidRepairMode(repair) {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			fmt.Sprintf("Unknown repair mode %q", repair),
+			nil,
+		)
+	}
+
+	log.Printf("[DEBUG] Processing clip verification job %d", job.ID)
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 5); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	result, err := p.clipService.VerifyClips(ctx, clips.VerifyOptions{Repair: repair})
+	if err != nil {
+		return models.NewSystemError(
+			"verification_failed",
+			"Failed to verify clips",
+			err.Error(),
+			err,
+		)
+	}
+
+	jobResult := models.JobResult{
+		"checked":       result.Checked,
+		"ok":            result.OK,
+		"hash_recorded": result.HashRecorded,
+		"broken":        result.Broken,
+		"repaired":      result.Repaired,
+		"issues":        result.Issues,
+	}
+	if err := p.jobService.CompleteJob(ctx, job.ID, jobResult); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] Clip verification job %d completed (%d broken of %d)", job.ID, result.Broken, result.Checked)
+	return nil
+}
@@ -0,0 +1,89 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/datasets"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// DatasetGenerationProcessor builds dataset archives queued by POST /datasets
+type DatasetGenerationProcessor struct {
+	jobService     jobs.Service
+	datasetService datasets.Service
+}
+
+// NewDatasetGenerationProcessor creates a new dataset generation processor
+func NewDatasetGenerationProcessor(jobService jobs.Service, datasetService datasets.Service) *DatasetGenerationProcessor {
+	return &DatasetGenerationProcessor{
+		jobService:     jobService,
+		datasetService: datasetService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *DatasetGenerationProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeDatasetGeneration
+}
+
+// ProcessJob generates the dataset described by the payload, which holds
+// datasets.GenerateParams
+func (p *DatasetGenerationProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	var params datasets.GenerateParams
+	raw, err := json.Marshal(job.Payload)
+	if err == nil {
+		err = json.Unmarshal(raw, &params)
+	}
+	if err != nil {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			fmt.Sprintf("Failed to parse dataset parameters: %v", err),
+			err,
+		)
+	}
+
+	log.Printf("[DEBUG] Processing dataset generation job %d", job.ID)
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 10); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	dataset, err := p.datasetService.Generate(ctx, params)
+	if errors.Is(err, datasets.ErrEmptyDataset) || errors.Is(err, datasets.ErrUnsupportedFormat) {
+		// Retrying won't add clips or change the format
+		return models.NewNotFoundError("dataset_empty", "No clips to export", err.Error(), err)
+	}
+	if err != nil {
+		return models.NewSystemError(
+			"dataset_failed",
+			"Failed to generate dataset",
+			err.Error(),
+			err,
+		)
+	}
+
+	result := models.JobResult{
+		"dataset_id":      dataset.ID,
+		"total_samples":   dataset.TotalSamples,
+		"skipped_samples": dataset.SkippedSamples,
+	}
+	if dataset.RerunJobID != nil {
+		result["rerun_job_id"] = *dataset.RerunJobID
+	}
+	if err := p.jobService.CompleteJob(ctx, job.ID, result); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] Dataset generation job %d completed (%s)", job.ID, dataset.ID)
+	return nil
+}
@@ -0,0 +1,159 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/killallgit/player-api/internal/models"
+	episodeanalysis "github.com/killallgit/player-api/internal/services/episode_analysis"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/modelregistry"
+	"github.com/killallgit/player-api/pkg/ffmpeg"
+)
+
+// EpisodeAnalysisProcessor runs episode analysis queued by GET
+// /episodes/:id/clips and by the post-cache pipeline. A "model_id" in the
+// payload selects the registry model; without one the default runs.
+type EpisodeAnalysisProcessor struct {
+	jobService      jobs.Service
+	analysisService episodeanalysis.Service
+	registry        modelregistry.Service
+}
+
+// NewEpisodeAnalysisProcessor creates a new episode analysis processor
+func NewEpisodeAnalysisProcessor(jobService jobs.Service, analysisService episodeanalysis.Service, registry modelregistry.Service) *EpisodeAnalysisProcessor {
+	return &EpisodeAnalysisProcessor{
+		jobService:      jobService,
+		analysisService: analysisService,
+		registry:        registry,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *EpisodeAnalysisProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeEpisodeAnalysis
+}
+
+// ProcessJob analyzes the episode and records how many clips it created
+func (p *EpisodeAnalysisProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	log.Printf("[DEBUG] Processing episode analysis job %d", job.ID)
+
+	episodeID, err := p.parseEpisodeID(job.Payload)
+	if err != nil {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			fmt.Sprintf("Failed to parse episode ID: %v", err),
+			err,
+		)
+	}
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 10); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	model, err := p.selectedModel(ctx, job.Payload)
+	if err != nil {
+		return models.NewSystemError(
+			"invalid_model",
+			"Selected model is not available",
+			err.Error(),
+			err,
+		)
+	}
+
+	clipUUIDs, err := p.analysisService.AnalyzeAndCreateClips(ctx, episodeID, model)
+	if errors.Is(err, ffmpeg.ErrResourceLimit) {
+		return models.NewResourceLimitError(
+			"ffmpeg_resource_limit",
+			"Analyzing episode audio exceeded resource limits",
+			err.Error(),
+			err,
+		)
+	}
+	if err != nil {
+		return models.NewProcessingError(
+			"analysis_failed",
+			"Failed to analyze episode",
+			err.Error(),
+			err,
+		)
+	}
+
+	result := models.JobResult{
+		"episode_id":    episodeID,
+		"clips_created": len(clipUUIDs),
+	}
+	if model != nil {
+		result["model"] = model.Identity()
+	}
+	if err := p.jobService.CompleteJob(ctx, job.ID, result); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] Episode analysis completed for episode %d (%d clips)", episodeID, len(clipUUIDs))
+	return nil
+}
+
+// selectedModel looks up the model_id of the payload, returning nil for the
+// default when there is none
+func (p *EpisodeAnalysisProcessor) selectedModel(ctx context.Context, payload models.JobPayload) (*models.DetectorModel, error) {
+	value, exists := payload["model_id"]
+	if !exists {
+		return nil, nil
+	}
+	var id uint
+	switch v := value.(type) {
+	case float64:
+		if v > 0 {
+			id = uint(v)
+		}
+	case int:
+		if v > 0 {
+			id = uint(v)
+		}
+	case uint:
+		id = v
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("invalid model_id: %v", value)
+	}
+	if p.registry == nil {
+		return nil, fmt.Errorf("model registry not available")
+	}
+	return p.registry.Get(ctx, id)
+}
+
+// parseEpisodeID extracts the episode ID from the job payload
+func (p *EpisodeAnalysisProcessor) parseEpisodeID(payload models.JobPayload) (int64, error) {
+	episodeIDValue, exists := payload["episode_id"]
+	if !exists {
+		return 0, fmt.Errorf("episode_id not found in payload")
+	}
+
+	switch v := episodeIDValue.(type) {
+	case float64:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return int64(v), nil
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid episode_id string: %s", v)
+		}
+		return id, nil
+	default:
+		return 0, fmt.Errorf("invalid episode_id type: %T", v)
+	}
+}
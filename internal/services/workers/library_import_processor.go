@@ -0,0 +1,108 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/imports"
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// LibraryImportProcessor imports subscriptions and playback progress parsed
+// from another app's export
+type LibraryImportProcessor struct {
+	jobService    jobs.Service
+	importService imports.Service
+}
+
+// NewLibraryImportProcessor creates a new library import processor
+func NewLibraryImportProcessor(jobService jobs.Service, importService imports.Service) *LibraryImportProcessor {
+	return &LibraryImportProcessor{
+		jobService:    jobService,
+		importService: importService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *LibraryImportProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeLibraryImport
+}
+
+// ProcessJob imports the library in the payload for the user named there
+func (p *LibraryImportProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	userID, _ := job.Payload["user_id"].(string)
+	library, err := decodeLibrary(job.Payload["library"])
+	if userID == "" || err != nil {
+		if err == nil {
+			err = fmt.Errorf("user_id not found in payload")
+		}
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			err.Error(),
+			err,
+		)
+	}
+
+	log.Printf("[DEBUG] Processing library import job %d (%d podcasts, %d episodes)", job.ID, len(library.Feeds), len(library.Plays))
+
+	lastReported := 0
+	result, err := p.importService.Import(ctx, userID, library, func(percent int) {
+		// Imports of a few thousand podcasts would otherwise write progress thousands of times
+		if percent < 100 && percent-lastReported < 5 {
+			return
+		}
+		lastReported = percent
+		if err := p.jobService.UpdateProgress(ctx, job.ID, percent); err != nil {
+			log.Printf("[WARN] Failed to update job progress: %v", err)
+		}
+	})
+	if err != nil {
+		return models.NewSystemError(
+			"import_failed",
+			"Failed to import library",
+			err.Error(),
+			err,
+		)
+	}
+
+	var jobResult models.JobResult
+	encoded, err := json.Marshal(result)
+	if err == nil {
+		err = json.Unmarshal(encoded, &jobResult)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode import result: %w", err)
+	}
+	if err := p.jobService.CompleteJob(ctx, job.ID, jobResult); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] Library import job %d completed: %d subscribed, %d progress imported, %d unmatched",
+		job.ID, result.Subscribed, result.ProgressImported, result.UnmatchedCount)
+	return nil
+}
+
+// decodeLibrary reads the library back out of a job payload, where it was
+// stored as JSON
+func decodeLibrary(value interface{}) (*imports.Library, error) {
+	if value == nil {
+		return nil, fmt.Errorf("library not found in payload")
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid library in payload: %w", err)
+	}
+	var library imports.Library
+	if err := json.Unmarshal(encoded, &library); err != nil {
+		return nil, fmt.Errorf("invalid library in payload: %w", err)
+	}
+	return &library, nil
+}
@@ -0,0 +1,101 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/liveitems"
+)
+
+// LiveItemRefreshProcessor fetches a podcast's live items again so their
+// statuses follow streams starting and ending. The live item scheduler
+// queues it around scheduled start times.
+type LiveItemRefreshProcessor struct {
+	jobService      jobs.Service
+	liveItemService liveitems.Service
+}
+
+// NewLiveItemRefreshProcessor creates a new live item refresh processor
+func NewLiveItemRefreshProcessor(jobService jobs.Service, liveItemService liveitems.Service) *LiveItemRefreshProcessor {
+	return &LiveItemRefreshProcessor{
+		jobService:      jobService,
+		liveItemService: liveItemService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *LiveItemRefreshProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeLiveItemRefresh
+}
+
+// ProcessJob refreshes the live items of the podcast in the payload
+func (p *LiveItemRefreshProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	feedID, err := p.parseFeedID(job.Payload)
+	if err != nil {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			fmt.Sprintf("Failed to parse feed ID: %v", err),
+			err,
+		)
+	}
+
+	items, err := p.liveItemService.Refresh(ctx, feedID)
+	if err != nil {
+		return models.NewSystemError(
+			"refresh_failed",
+			"Failed to refresh live items",
+			err.Error(),
+			err,
+		)
+	}
+
+	live := 0
+	for _, item := range items {
+		if item.IsLive() {
+			live++
+		}
+	}
+	if err := p.jobService.CompleteJob(ctx, job.ID, models.JobResult{
+		"feed_id":    feedID,
+		"live_items": len(items),
+		"live":       live,
+	}); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] Live item refresh job %d completed: podcast %d has %d live items, %d live", job.ID, feedID, len(items), live)
+	return nil
+}
+
+func (p *LiveItemRefreshProcessor) parseFeedID(payload models.JobPayload) (int64, error) {
+	feedIDValue, exists := payload["feed_id"]
+	if !exists {
+		return 0, fmt.Errorf("feed_id not found in payload")
+	}
+
+	switch v := feedIDValue.(type) {
+	case float64:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid feed_id string: %s", v)
+		}
+		return id, nil
+	default:
+		return 0, fmt.Errorf("invalid feed_id type: %T", v)
+	}
+}
@@ -0,0 +1,79 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/killallgit/player-api/internal/services/jobs"
+)
+
+// ReaperConfig tunes the stale job reaper
+type ReaperConfig struct {
+	Interval         time.Duration // How often to look for stale jobs
+	HeartbeatTimeout time.Duration // A processing job without a heartbeat for this long is abandoned
+	Timeouts         jobs.Timeouts // Jobs running past their type's limit (plus HeartbeatTimeout) are reaped too
+}
+
+// Reaper fails jobs stuck in processing after their worker crashed or hung,
+// so they are retried like any other failure
+type Reaper struct {
+	jobService jobs.Service
+	config     ReaperConfig
+	notifiers  []JobNotifier
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// NewReaper creates a reaper
+func NewReaper(jobService jobs.Service, config ReaperConfig) *Reaper {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	return &Reaper{jobService: jobService, config: config}
+}
+
+// Start reaps immediately, which recovers jobs orphaned by the previous run of
+// the server, and then every interval until Stop is called
+func (r *Reaper) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+		r.Reap(ctx)
+
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.Reap(ctx)
+			case <-ctx.Done():
+				log.Println("[INFO] Job reaper stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the reaper and waits for a pass in progress
+func (r *Reaper) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+		<-r.done
+	}
+}
+
+// Reap fails the stale jobs once
+func (r *Reaper) Reap(ctx context.Context) {
+	reaped, err := r.jobService.ReapStaleJobs(ctx, r.config.HeartbeatTimeout, r.config.Timeouts)
+	if err != nil {
+		log.Printf("[ERROR] Failed to reap stale jobs: %v", err)
+		return
+	}
+	for _, job := range reaped {
+		notifyFailed(ctx, r.jobService, r.notifiers, job, "Job reaper")
+	}
+}
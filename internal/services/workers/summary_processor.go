@@ -0,0 +1,159 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/summary"
+	"github.com/killallgit/player-api/internal/services/transcription"
+)
+
+// SummaryProcessor processes summary generation jobs
+type SummaryProcessor struct {
+	jobService           jobs.Service
+	summaryService       summary.Service
+	transcriptionService transcription.TranscriptionService
+}
+
+// NewSummaryProcessor creates a new summary processor
+func NewSummaryProcessor(
+	jobService jobs.Service,
+	summaryService summary.Service,
+	transcriptionService transcription.TranscriptionService,
+) *SummaryProcessor {
+	return &SummaryProcessor{
+		jobService:           jobService,
+		summaryService:       summaryService,
+		transcriptionService: transcriptionService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *SummaryProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeSummaryGeneration
+}
+
+// ProcessJob processes a summary generation job
+func (p *SummaryProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	log.Printf("[DEBUG] Processing summary generation job %d", job.ID)
+
+	episodeID, err := p.parseEpisodeID(job.Payload)
+	if err != nil {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			fmt.Sprintf("Failed to parse episode ID: %v", err),
+			err,
+		)
+	}
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 10); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	transcriptionModel, err := p.transcriptionService.GetTranscription(ctx, episodeID)
+	if err != nil {
+		return models.NewSystemError(
+			"database_error",
+			"Failed to load transcription",
+			err.Error(),
+			err,
+		)
+	}
+	if transcriptionModel == nil {
+		return models.NewNotFoundError(
+			"transcription_not_found",
+			fmt.Sprintf("No transcription for episode %d", episodeID),
+			"A completed transcription is required before summarizing",
+			fmt.Errorf("transcription not found"),
+		)
+	}
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 30); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	result, err := p.summaryService.GenerateSummary(ctx, episodeID, transcriptionModel.Text)
+	if err != nil {
+		switch {
+		case errors.Is(err, summary.ErrTokenBudgetExceeded):
+			return models.NewProcessingError(
+				"token_budget_exceeded",
+				"Summary token budget exhausted",
+				"The daily summary token budget has been spent; try again later",
+				err,
+			)
+		case errors.Is(err, summary.ErrEmptyTranscript):
+			return models.NewProcessingError(
+				"empty_transcript",
+				"Transcription has no text to summarize",
+				err.Error(),
+				err,
+			)
+		default:
+			return models.NewSystemError(
+				"summary_failed",
+				"Failed to generate summary",
+				err.Error(),
+				err,
+			)
+		}
+	}
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 100); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	jobResult := models.JobResult{
+		"episode_id":        episodeID,
+		"model":             result.Model,
+		"prompt_tokens":     result.PromptTokens,
+		"completion_tokens": result.CompletionTokens,
+		"truncated":         result.Truncated,
+	}
+
+	if err := p.jobService.CompleteJob(ctx, job.ID, jobResult); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] Summary completed for episode %d (%d prompt tokens, %d completion tokens)",
+		episodeID, result.PromptTokens, result.CompletionTokens)
+
+	return nil
+}
+
+// parseEpisodeID extracts the episode ID from the job payload
+func (p *SummaryProcessor) parseEpisodeID(payload models.JobPayload) (int64, error) {
+	episodeIDValue, exists := payload["episode_id"]
+	if !exists {
+		return 0, fmt.Errorf("episode_id not found in payload")
+	}
+
+	switch v := episodeIDValue.(type) {
+	case float64:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return int64(v), nil
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid episode_id string: %s", v)
+		}
+		return id, nil
+	default:
+		return 0, fmt.Errorf("invalid episode_id type: %T", v)
+	}
+}
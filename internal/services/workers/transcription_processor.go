@@ -2,15 +2,16 @@ package workers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os/exec"
+	"net/http"
 	"path/filepath"
 	"strconv"
-	"strings"
 
 	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/internal/services/audiocache"
+	"github.com/killallgit/player-api/internal/services/contentsafety"
 	"github.com/killallgit/player-api/internal/services/episodes"
 	"github.com/killallgit/player-api/internal/services/jobs"
 	"github.com/killallgit/player-api/internal/services/transcription"
@@ -25,11 +26,12 @@ type TranscriptionProcessor struct {
 	transcriptionService transcription.TranscriptionService
 	episodeService       episodes.EpisodeService
 	audioCacheService    audiocache.Service
+	contentSafety        contentsafety.Service
 	downloader           *download.Downloader
 	transcriptFetcher    *transcript.Fetcher
 	transcriptParser     *transcript.Parser
-	modelPath            string
-	whisperPath          string
+	models               *transcription.ModelRegistry
+	backend              transcription.Backend
 	language             string
 	preferExisting       bool
 }
@@ -40,6 +42,10 @@ func NewTranscriptionProcessor(
 	transcriptionService transcription.TranscriptionService,
 	episodeService episodes.EpisodeService,
 	audioCacheService audiocache.Service,
+	contentSafety contentsafety.Service,
+	policies *download.Policies,
+	whisperModels *transcription.ModelRegistry,
+	backend transcription.Backend,
 ) *TranscriptionProcessor {
 	// Create downloader with default options
 	downloadOpts := download.DefaultOptions()
@@ -47,26 +53,16 @@ func NewTranscriptionProcessor(
 	if downloadOpts.TempDir == "" {
 		downloadOpts.TempDir = "./tmp"
 	}
+	downloadOpts.Policies = policies
 
 	// Get whisper configuration
-	modelPath := viper.GetString("transcription.model_path")
-	if modelPath == "" {
-		modelPath = "./models/ggml-base.en.bin"
+	whisper := transcription.WhisperFromConfig()
+	if whisperModels == nil {
+		// Without a shared registry only the configured model is used
+		whisperModels = transcription.SingleModelRegistry(whisper.ModelPath)
 	}
-
-	whisperPath := viper.GetString("transcription.whisper_path")
-	if whisperPath == "" {
-		// Default to whisper-cli (homebrew) or main binary (whisper.cpp build)
-		if _, err := exec.LookPath("whisper-cli"); err == nil {
-			whisperPath = "whisper-cli"
-		} else {
-			whisperPath = "/app/bin/main"
-		}
-	}
-
-	language := viper.GetString("transcription.language")
-	if language == "" {
-		language = "en"
+	if backend == nil {
+		backend = &transcription.LocalBackend{Whisper: whisper, Models: whisperModels}
 	}
 
 	// Get preference for using existing transcripts
@@ -87,12 +83,13 @@ func NewTranscriptionProcessor(
 		transcriptionService: transcriptionService,
 		episodeService:       episodeService,
 		audioCacheService:    audioCacheService,
+		contentSafety:        contentSafety,
 		downloader:           download.NewDownloader(downloadOpts),
 		transcriptFetcher:    transcript.NewFetcher(fetchOpts),
 		transcriptParser:     transcript.NewParser(),
-		modelPath:            modelPath,
-		whisperPath:          whisperPath,
-		language:             language,
+		models:               whisperModels,
+		backend:              backend,
+		language:             whisper.Language,
 		preferExisting:       preferExisting,
 	}
 }
@@ -169,11 +166,16 @@ func (p *TranscriptionProcessor) ProcessJob(ctx context.Context, job *models.Job
 					SourceURL:             episode.TranscriptURL,
 					Format:                string(parsedTranscript.Format),
 				}
+				if err := transcriptionModel.SetSegments(toTranscriptSegments(parsedTranscript.Segments)); err != nil {
+					log.Printf("[WARN] Failed to encode transcript segments for episode %d: %v", episodeID, err)
+				}
 
 				// Save transcription to database
 				if err := p.transcriptionService.SaveTranscription(ctx, transcriptionModel); err != nil {
 					log.Printf("[ERROR] Failed to save fetched transcript: %v", err)
 				} else {
+					p.analyzeSegments(ctx, int64(episodeID), parsedTranscript.Segments)
+
 					// Update progress: Complete
 					if err := p.jobService.UpdateProgress(ctx, job.ID, 100); err != nil {
 						log.Printf("Failed to update job progress: %v", err)
@@ -226,8 +228,8 @@ func (p *TranscriptionProcessor) ProcessJob(ctx context.Context, job *models.Job
 	if p.audioCacheService != nil {
 		log.Printf("[DEBUG] Checking audio cache for transcription of episode %d (database ID: %d)", episodeID, episode.ID)
 
-		// Get or download audio through cache (prefer processed audio for ML) - use Podcast Index ID
-		audioCache, err := p.audioCacheService.GetOrDownloadAudio(ctx, int64(episodeID), episode.AudioURL)
+		// Get or download audio through cache; the processed rendition is already 16kHz mono - use Podcast Index ID
+		audioCache, err := p.audioCacheService.GetProcessedAudio(ctx, int64(episodeID), episode.AudioURL)
 		if err != nil {
 			log.Printf("[WARN] Audio cache failed for transcription of Podcast Index episode %d, falling back to direct download: %v", episodeID, err)
 		} else if audioCache != nil && audioCache.ProcessedPath != "" {
@@ -270,12 +272,32 @@ func (p *TranscriptionProcessor) ProcessJob(ctx context.Context, job *models.Job
 		log.Printf("Failed to update job progress: %v", err)
 	}
 
-	log.Printf("[DEBUG] Transcribing audio from file: %s", audioFilePath)
+	// Pick the model: requested with the job, else the podcast's, else the default
+	requestedModel, _ := job.Payload["model"].(string)
+	modelName := p.models.Select(requestedModel, episode.PodcastIndexFeedID)
+
+	log.Printf("[DEBUG] Transcribing audio from file: %s with %s backend (model %s)", audioFilePath, p.backend.Name(), modelName)
 
 	// Generate transcription
-	transcriptionText, duration, err := p.transcribeAudio(ctx, audioFilePath)
+	backendResult, err := p.backend.Transcribe(ctx, audioFilePath, transcription.BackendOptions{
+		Model:    modelName,
+		Language: p.language,
+	})
+	if errors.Is(err, transcription.ErrWhisperNotFound) {
+		log.Printf("[WARNING] %v, using placeholder transcription", err)
+		backendResult, err = p.generatePlaceholderTranscription(audioFilePath, modelName)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to transcribe audio: %w", err)
+		return p.classifyBackendError(modelName, err)
+	}
+	transcriptionText := backendResult.Text
+	duration := backendResult.Duration
+	if duration == 0 {
+		duration = 300.0 // whisper.cpp's text output carries no timing; placeholder 5 minutes
+	}
+	language := backendResult.Language
+	if language == "" {
+		language = p.language
 	}
 
 	// Update progress: Transcription complete, saving to database
@@ -287,19 +309,26 @@ func (p *TranscriptionProcessor) ProcessJob(ctx context.Context, job *models.Job
 	transcriptionModel := &models.Transcription{
 		PodcastIndexEpisodeID: int64(episodeID),
 		Text:                  transcriptionText,
-		Language:              p.language,
-		Model:                 filepath.Base(p.modelPath),
+		Language:              language,
+		Model:                 backendResult.Model,
 		Duration:              duration,
 		Source:                "generated",
 		SourceURL:             "",        // No source URL for generated transcripts
 		Format:                "whisper", // Whisper output format
 	}
+	if len(backendResult.Segments) > 0 {
+		if err := transcriptionModel.SetSegments(backendResult.Segments); err != nil {
+			log.Printf("[WARN] Failed to encode transcript segments for episode %d: %v", episodeID, err)
+		}
+	}
 
 	// Save transcription to database
 	if err := p.transcriptionService.SaveTranscription(ctx, transcriptionModel); err != nil {
 		return fmt.Errorf("failed to save transcription: %w", err)
 	}
 
+	p.analyzeText(ctx, int64(episodeID), transcriptionText, duration)
+
 	// Update progress: Complete
 	if err := p.jobService.UpdateProgress(ctx, job.ID, 100); err != nil {
 		log.Printf("Failed to update job progress: %v", err)
@@ -310,8 +339,9 @@ func (p *TranscriptionProcessor) ProcessJob(ctx context.Context, job *models.Job
 		"episode_id":  episodeID,
 		"source":      "generated",
 		"duration":    duration,
-		"language":    p.language,
-		"model":       filepath.Base(p.modelPath),
+		"language":    language,
+		"model":       backendResult.Model,
+		"backend":     p.backend.Name(),
 		"text_length": len(transcriptionText),
 		"file_size":   audioFileSize,
 		"cached":      p.audioCacheService != nil && audioFilePath != "",
@@ -329,46 +359,74 @@ func (p *TranscriptionProcessor) ProcessJob(ctx context.Context, job *models.Job
 	return nil
 }
 
-// transcribeAudio transcribes audio using whisper
-func (p *TranscriptionProcessor) transcribeAudio(ctx context.Context, audioPath string) (string, float64, error) {
-	// Check if whisper binary exists
-	if _, err := exec.LookPath(p.whisperPath); err != nil {
-		// For now, return placeholder transcription
-		log.Printf("[WARNING] Whisper binary not found at %s, using placeholder transcription", p.whisperPath)
-		return p.generatePlaceholderTranscription(audioPath)
-	}
-
-	// Run whisper-cli command (Homebrew whisper-cpp installation)
-	cmd := exec.CommandContext(ctx, p.whisperPath,
-		"-m", p.modelPath, // model path
-		"-f", audioPath, // input file
-		"-l", p.language, // language
-		"-t", "4", // threads
-		"-otxt", // output as text
-		"-nt",   // no timestamps
-	)
+// toTranscriptSegments converts parsed segments to the stored representation
+func toTranscriptSegments(segments []transcript.Segment) []models.TranscriptSegment {
+	stored := make([]models.TranscriptSegment, len(segments))
+	for i, seg := range segments {
+		stored[i] = models.TranscriptSegment{
+			Start: seg.Start.Seconds(),
+			End:   seg.End.Seconds(),
+			Text:  seg.Text,
+		}
+	}
+	return stored
+}
 
-	output, err := cmd.Output()
-	if err != nil {
-		log.Printf("[ERROR] Whisper command failed: %v", err)
-		// Fall back to placeholder
-		return p.generatePlaceholderTranscription(audioPath)
+// analyzeSegments runs explicit-language detection on timed transcript segments.
+// Failures are logged only; a missing analysis never fails the transcription job.
+func (p *TranscriptionProcessor) analyzeSegments(ctx context.Context, episodeID int64, segments []transcript.Segment) {
+	if p.contentSafety == nil {
+		return
+	}
+
+	timed := make([]contentsafety.Segment, len(segments))
+	for i, seg := range segments {
+		timed[i] = contentsafety.Segment{
+			Start: seg.Start.Seconds(),
+			End:   seg.End.Seconds(),
+			Text:  seg.Text,
+		}
 	}
 
-	// Parse output to extract transcription
-	transcriptionText := string(output)
+	if _, err := p.contentSafety.AnalyzeSegments(ctx, episodeID, timed); err != nil {
+		log.Printf("[WARN] Content analysis failed for episode %d: %v", episodeID, err)
+	}
+}
 
-	// Clean up the transcription text
-	transcriptionText = strings.TrimSpace(transcriptionText)
+// analyzeText runs explicit-language detection on untimed transcript text
+func (p *TranscriptionProcessor) analyzeText(ctx context.Context, episodeID int64, text string, duration float64) {
+	if p.contentSafety == nil {
+		return
+	}
 
-	// For now, estimate duration based on file size (will be replaced with actual duration)
-	duration := 300.0 // Placeholder 5 minutes
+	if _, err := p.contentSafety.AnalyzeText(ctx, episodeID, text, duration); err != nil {
+		log.Printf("[WARN] Content analysis failed for episode %d: %v", episodeID, err)
+	}
+}
 
-	return transcriptionText, duration, nil
+// classifyBackendError fails the job permanently when retrying can't help,
+// such as an unknown model or audio a remote backend won't accept, and
+// retries otherwise
+func (p *TranscriptionProcessor) classifyBackendError(modelName string, err error) error {
+	var backendErr *transcription.BackendError
+	switch {
+	case errors.Is(err, transcription.ErrUnknownModel):
+		return models.NewNotFoundError("whisper_model_unknown", fmt.Sprintf("unknown whisper model %q", modelName), err.Error(), err)
+	case errors.Is(err, transcription.ErrModelNotInstalled):
+		return models.NewNotFoundError("whisper_model_missing", fmt.Sprintf("whisper model %q is not installed", modelName), err.Error(), err)
+	case errors.Is(err, transcription.ErrModelDownload):
+		return models.NewDownloadError("whisper_model_download", fmt.Sprintf("failed to install whisper model %q", modelName), err.Error(), err)
+	case errors.Is(err, transcription.ErrAudioTooLarge):
+		return models.NewResourceLimitError("transcription_audio_too_large", "audio too large for the transcription backend", err.Error(), err)
+	case errors.As(err, &backendErr) && (backendErr.StatusCode == http.StatusUnauthorized || backendErr.StatusCode == http.StatusForbidden):
+		return models.NewConfigurationError("transcription_backend_auth", fmt.Sprintf("%s transcription backend rejected the credentials", backendErr.Backend), err.Error(), err)
+	default:
+		return models.NewProcessingError("transcription_failed", "failed to transcribe audio", err.Error(), err)
+	}
 }
 
 // generatePlaceholderTranscription generates a placeholder transcription for testing
-func (p *TranscriptionProcessor) generatePlaceholderTranscription(audioPath string) (string, float64, error) {
+func (p *TranscriptionProcessor) generatePlaceholderTranscription(audioPath, modelName string) (*transcription.BackendResult, error) {
 	// This is temporary until whisper is properly integrated
 	placeholderText := fmt.Sprintf(
 		"[Transcription placeholder for audio file: %s]\n\n"+
@@ -381,10 +439,12 @@ func (p *TranscriptionProcessor) generatePlaceholderTranscription(audioPath stri
 		filepath.Base(audioPath),
 	)
 
-	// Placeholder duration
-	duration := 300.0 // 5 minutes
-
-	return placeholderText, duration, nil
+	return &transcription.BackendResult{
+		Text:     placeholderText,
+		Language: p.language,
+		Duration: 300.0, // Placeholder 5 minutes
+		Model:    modelName,
+	}, nil
 }
 
 // parseEpisodeID extracts the episode ID from the job payload
@@ -0,0 +1,70 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/internal/services/userdata"
+)
+
+// UserExportProcessor builds personal data export archives
+type UserExportProcessor struct {
+	jobService      jobs.Service
+	userDataService userdata.Service
+}
+
+// NewUserExportProcessor creates a new user export processor
+func NewUserExportProcessor(jobService jobs.Service, userDataService userdata.Service) *UserExportProcessor {
+	return &UserExportProcessor{
+		jobService:      jobService,
+		userDataService: userDataService,
+	}
+}
+
+// CanProcess returns true if this processor can handle the job type
+func (p *UserExportProcessor) CanProcess(jobType models.JobType) bool {
+	return jobType == models.JobTypeUserExport
+}
+
+// ProcessJob writes the export archive for the user named in the payload
+func (p *UserExportProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	if !p.CanProcess(job.Type) {
+		return fmt.Errorf("unsupported job type: %s", job.Type)
+	}
+
+	userID, _ := job.Payload["user_id"].(string)
+	if userID == "" {
+		return models.NewSystemError(
+			"invalid_payload",
+			"Invalid job payload",
+			"user_id not found in payload",
+			fmt.Errorf("user_id not found in payload"),
+		)
+	}
+
+	log.Printf("[DEBUG] Processing user export job %d", job.ID)
+
+	if err := p.jobService.UpdateProgress(ctx, job.ID, 10); err != nil {
+		log.Printf("[WARN] Failed to update job progress: %v", err)
+	}
+
+	size, err := p.userDataService.WriteArchive(ctx, userID, job.ID)
+	if err != nil {
+		return models.NewSystemError(
+			"export_failed",
+			"Failed to build data export",
+			err.Error(),
+			err,
+		)
+	}
+
+	if err := p.jobService.CompleteJob(ctx, job.ID, models.JobResult{"size_bytes": size}); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("[DEBUG] User export job %d completed (%d bytes)", job.ID, size)
+	return nil
+}
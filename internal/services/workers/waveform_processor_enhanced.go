@@ -2,6 +2,7 @@ package workers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -35,10 +36,12 @@ func NewEnhancedWaveformProcessor(
 	audioCacheService audiocache.Service,
 	ffmpegInstance *ffmpeg.FFmpeg,
 	options ffmpeg.ProcessingOptions,
+	policies *download.Policies,
 ) *EnhancedWaveformProcessor {
 	// Create downloader with default options
 	downloadOpts := download.DefaultOptions()
 	downloadOpts.TempDir = options.TempDir
+	downloadOpts.Policies = policies
 
 	// Add progress callback that updates job progress
 	var currentJobID uint
@@ -88,7 +91,7 @@ func (p *EnhancedWaveformProcessor) ProcessJob(ctx context.Context, job *models.
 	}
 
 	// Get episode details using Podcast Index ID
-	episode, err := p.episodeService.GetEpisodeByPodcastIndexID(ctx, int64(podcastIndexID))
+	episode, err := p.episodeService.GetEpisodeByPodcastIndexID(ctx, podcastIndexID)
 	if err != nil {
 		// Check if this is a permanent "not found in Podcast Index" error
 		if strings.Contains(err.Error(), "does not exist in Podcast Index") {
@@ -111,7 +114,7 @@ func (p *EnhancedWaveformProcessor) ProcessJob(ctx context.Context, job *models.
 	}
 
 	// Check if waveform already exists for this episode
-	existingWaveform, err := p.waveformService.GetWaveform(ctx, int64(podcastIndexID))
+	existingWaveform, err := p.waveformService.GetWaveform(ctx, podcastIndexID)
 	if err == nil && existingWaveform != nil {
 		log.Printf("[DEBUG] Waveform already exists for Podcast Index Episode %d, skipping generation", podcastIndexID)
 
@@ -164,7 +167,7 @@ func (p *EnhancedWaveformProcessor) ProcessJob(ctx context.Context, job *models.
 		log.Printf("[DEBUG] Checking audio cache for episode %d (database ID: %d)", podcastIndexID, episode.ID)
 
 		// Get or download audio through cache - use Podcast Index ID, not database ID
-		audioCache, err := p.audioCacheService.GetOrDownloadAudio(ctx, int64(podcastIndexID), episode.AudioURL)
+		audioCache, err := p.audioCacheService.GetOrDownloadAudio(ctx, podcastIndexID, episode.AudioURL)
 		if err != nil {
 			log.Printf("[WARN] Audio cache failed for Podcast Index episode %d, falling back to direct download: %v", podcastIndexID, err)
 		} else if audioCache != nil && audioCache.OriginalPath != "" {
@@ -179,7 +182,7 @@ func (p *EnhancedWaveformProcessor) ProcessJob(ctx context.Context, job *models.
 		log.Printf("[DEBUG] Downloading audio for episode %d (database ID: %d) from URL: %s", podcastIndexID, episode.ID, episode.AudioURL)
 
 		// Download audio to temp file with retry logic (use Podcast Index ID for logging)
-		downloadResult, err := p.downloader.DownloadWithRetry(ctx, episode.AudioURL, podcastIndexID)
+		downloadResult, err := p.downloader.DownloadWithRetry(ctx, episode.AudioURL, uint(podcastIndexID))
 		if err != nil {
 			return p.classifyDownloadError(err, episode.AudioURL)
 		}
@@ -205,8 +208,10 @@ func (p *EnhancedWaveformProcessor) ProcessJob(ctx context.Context, job *models.
 
 	log.Printf("[DEBUG] Processing waveform from file: %s", audioFilePath)
 
-	// Generate waveform from audio file
-	waveformData, err := p.ffmpeg.GenerateWaveform(ctx, audioFilePath, p.options)
+	// Generate waveform from audio file, checkpointing partial peaks for clients polling the job
+	options := p.options
+	options.OnProgress = p.checkpointFunc(ctx, job.ID, podcastIndexID)
+	waveformData, err := p.ffmpeg.GenerateWaveform(ctx, audioFilePath, options)
 	if err != nil {
 		// Log the detailed error for debugging
 		log.Printf("[ERROR] FFmpeg waveform generation failed for episode %d: %v", podcastIndexID, err)
@@ -227,11 +232,17 @@ func (p *EnhancedWaveformProcessor) ProcessJob(ctx context.Context, job *models.
 
 	// Create waveform model - Use Podcast Index Episode ID for API consistency
 	waveformModel := &models.Waveform{
-		PodcastIndexEpisodeID: int64(podcastIndexID), // Use Podcast Index Episode ID, not database ID
+		PodcastIndexEpisodeID: podcastIndexID, // Use Podcast Index Episode ID, not database ID
 		Duration:              waveformData.Duration,
 		Resolution:            waveformData.Resolution,
 		SampleRate:            waveformData.SampleRate,
 	}
+	if stats := waveformData.Stats; stats != nil {
+		waveformModel.P95Peak = &stats.P95Peak
+		waveformModel.MedianPeak = &stats.MedianPeak
+		waveformModel.DynamicRangeDB = &stats.DynamicRangeDB
+		waveformModel.LoudnessDB = &stats.LoudnessDB
+	}
 
 	// Set peaks data
 	if err := waveformModel.SetPeaks(waveformData.Peaks); err != nil {
@@ -271,33 +282,28 @@ func (p *EnhancedWaveformProcessor) ProcessJob(ctx context.Context, job *models.
 	return nil
 }
 
-// parseEpisodeID extracts the episode ID from the job payload
-func (p *EnhancedWaveformProcessor) parseEpisodeID(payload models.JobPayload) (uint, error) {
-	// JobPayload is already a map[string]interface{}, so use it directly
-	data := map[string]any(payload)
-
-	// Extract episode_id
-	episodeIDValue, exists := data["episode_id"]
+// parseEpisodeID extracts the Podcast Index episode ID from the job payload
+func (p *EnhancedWaveformProcessor) parseEpisodeID(payload models.JobPayload) (int64, error) {
+	episodeIDValue, exists := payload["episode_id"]
 	if !exists {
 		return 0, fmt.Errorf("episode_id not found in payload")
 	}
 
-	// Handle different number types
 	switch v := episodeIDValue.(type) {
 	case float64:
-		return uint(v), nil
+		return int64(v), nil
 	case int:
-		return uint(v), nil
+		return int64(v), nil
 	case int64:
-		return uint(v), nil
-	case uint:
 		return v, nil
+	case uint:
+		return int64(v), nil
 	case string:
-		// Try to parse as string number
-		if id, err := strconv.ParseUint(v, 10, 32); err == nil {
-			return uint(id), nil
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid episode_id string: %s", v)
 		}
-		return 0, fmt.Errorf("invalid episode_id string: %s", v)
+		return id, nil
 	default:
 		return 0, fmt.Errorf("invalid episode_id type: %T", v)
 	}
@@ -352,6 +358,28 @@ func (p *EnhancedWaveformProcessor) classifyDownloadError(err error, audioURL st
 		err)
 }
 
+// checkpointFunc returns a progress callback that stores partial peaks and maps
+// decoding progress onto the 50-85% span of the job
+func (p *EnhancedWaveformProcessor) checkpointFunc(ctx context.Context, jobID uint, podcastIndexID int64) ffmpeg.ProgressFunc {
+	return func(partial *ffmpeg.WaveformData, fraction float64) {
+		checkpoint := &models.WaveformCheckpoint{
+			PodcastIndexEpisodeID: podcastIndexID,
+			Duration:              partial.Duration,
+			Progress:              int(fraction * 100),
+		}
+		if err := checkpoint.SetPeaks(partial.Peaks); err != nil {
+			log.Printf("[WARN] Failed to encode waveform checkpoint for episode %d: %v", podcastIndexID, err)
+			return
+		}
+		if err := p.waveformService.SaveCheckpoint(ctx, checkpoint); err != nil {
+			log.Printf("[WARN] Failed to save waveform checkpoint for episode %d: %v", podcastIndexID, err)
+		}
+		if err := p.jobService.UpdateProgress(ctx, jobID, 50+int(fraction*35)); err != nil {
+			log.Printf("Failed to update job progress: %v", err)
+		}
+	}
+}
+
 // classifyProcessingError classifies FFmpeg/processing errors into structured categories
 func (p *EnhancedWaveformProcessor) classifyProcessingError(err error, audioFilePath string) *models.StructuredJobError {
 	errMsg := err.Error()
@@ -373,6 +401,14 @@ func (p *EnhancedWaveformProcessor) classifyProcessingError(err error, audioFile
 		errorDetails = fmt.Sprintf("File: %s\nError: %s", audioFilePath, errMsg)
 	}
 
+	// Sandbox limits: the same input would be killed again, so don't retry
+	if errors.Is(err, ffmpeg.ErrResourceLimit) {
+		return models.NewResourceLimitError("ffmpeg_resource_limit",
+			"Audio processing exceeded resource limits",
+			errorDetails,
+			err)
+	}
+
 	// Duration limit errors
 	if strings.Contains(errLower, "exceeds maximum duration") || strings.Contains(errLower, "exceeds limit") {
 		return models.NewProcessingError("duration_exceeded",
@@ -33,7 +33,7 @@ func TestEnhancedWaveformProcessor_ParseEpisodeID(t *testing.T) {
 	tests := []struct {
 		name     string
 		payload  models.JobPayload
-		expected uint
+		expected int64
 		hasError bool
 	}{
 		{
@@ -48,6 +48,12 @@ func TestEnhancedWaveformProcessor_ParseEpisodeID(t *testing.T) {
 			expected: 456,
 			hasError: false,
 		},
+		{
+			name:     "Podcast Index ID beyond 32 bits as string",
+			payload:  models.JobPayload{"episode_id": "41951637359"},
+			expected: 41951637359,
+			hasError: false,
+		},
 		{
 			name:     "missing episode_id",
 			payload:  models.JobPayload{"other_field": "value"},
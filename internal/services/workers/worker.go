@@ -2,6 +2,7 @@ package workers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -9,6 +10,9 @@ import (
 
 	"github.com/killallgit/player-api/internal/models"
 	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/killallgit/player-api/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type JobProcessor interface {
@@ -16,13 +20,32 @@ type JobProcessor interface {
 	CanProcess(jobType models.JobType) bool
 }
 
+// JobNotifier is told when a job finishes successfully
+type JobNotifier interface {
+	NotifyJobCompleted(ctx context.Context, job *models.Job) error
+}
+
+// JobFailureNotifier is a JobNotifier that also wants to hear about jobs that
+// failed for good. Failures that will be retried are not reported.
+type JobFailureNotifier interface {
+	JobNotifier
+	NotifyJobFailed(ctx context.Context, job *models.Job) error
+}
+
 type Worker struct {
-	id           string
-	jobService   jobs.Service
-	processors   []JobProcessor
-	stopChan     chan struct{}
-	wg           sync.WaitGroup
-	pollInterval time.Duration
+	id                string
+	jobService        jobs.Service
+	notifiers         []JobNotifier
+	processors        []JobProcessor
+	stopChan          chan struct{}
+	wg                sync.WaitGroup
+	pollInterval      time.Duration
+	timeouts          jobs.Timeouts
+	heartbeatInterval time.Duration // 0 disables heartbeats
+
+	mu         sync.Mutex
+	runningJob uint                    // Job being processed; 0 when idle
+	cancelJob  context.CancelCauseFunc // Stops runningJob
 }
 
 func NewWorker(id string, jobService jobs.Service, pollInterval time.Duration) *Worker {
@@ -82,6 +105,16 @@ func (w *Worker) processNextJob(ctx context.Context) error {
 		models.JobTypePodcastSync,
 		models.JobTypeClipExtraction,
 		models.JobTypeAutoLabel,
+		models.JobTypeSummaryGeneration,
+		models.JobTypeUserExport,
+		models.JobTypeAccountDeletion,
+		models.JobTypeDatasetGeneration,
+		models.JobTypeEpisodeAnalysis,
+		models.JobTypeAudioCache,
+		models.JobTypeClipReextraction,
+		models.JobTypeLibraryImport,
+		models.JobTypeLiveItemRefresh,
+		models.JobTypeClipVerification,
 	}
 
 	for _, jobType := range allJobTypes {
@@ -120,56 +153,285 @@ func (w *Worker) processNextJob(ctx context.Context) error {
 		return fmt.Errorf("no processor found for job type %s", job.Type)
 	}
 
-	err = processor.ProcessJob(ctx, job)
+	err = w.runJob(ctx, processor, job)
+	if errors.Is(err, jobs.ErrJobCancelled) {
+		log.Printf("Worker %s: job %d was cancelled", w.id, job.ID)
+		return nil
+	}
 	if err != nil {
 		if structuredErr, ok := err.(*models.StructuredJobError); ok {
 			failErr := w.jobService.FailJobWithDetails(ctx, job.ID, structuredErr.Type, structuredErr.Code, structuredErr.Message, structuredErr.Details)
+			if errors.Is(failErr, jobs.ErrJobCancelled) {
+				log.Printf("Worker %s: job %d was cancelled", w.id, job.ID)
+				return nil
+			}
 			if failErr != nil {
 				log.Printf("Worker %s: failed to mark job %d as failed: %v", w.id, job.ID, failErr)
 			}
 		} else {
 			failErr := w.jobService.FailJob(ctx, job.ID, err)
+			if errors.Is(failErr, jobs.ErrJobCancelled) {
+				log.Printf("Worker %s: job %d was cancelled", w.id, job.ID)
+				return nil
+			}
 			if failErr != nil {
 				log.Printf("Worker %s: failed to mark job %d as failed: %v", w.id, job.ID, failErr)
 			}
 		}
+		w.notifyFailed(ctx, job.ID)
 		return fmt.Errorf("job processing failed: %w", err)
 	}
 
 	log.Printf("Worker %s completed job %d", w.id, job.ID)
+
+	// Processors store the result when completing the job; hand notifiers the
+	// finished row rather than the claimed one
+	if finished, err := w.jobService.GetJob(ctx, job.ID); err == nil {
+		job = finished
+	}
+	if job.Status == models.JobStatusCancelled {
+		return nil
+	}
+
+	// Notifiers decide for themselves which jobs they care about
+	for _, notifier := range w.notifiers {
+		if err := notifier.NotifyJobCompleted(ctx, job); err != nil {
+			log.Printf("Worker %s: failed to notify completion of job %d: %v", w.id, job.ID, err)
+		}
+	}
 	return nil
 }
 
+// runJob runs the processor under the job type's timeout, sending heartbeats
+// until it returns. It returns ErrJobCancelled when the job was cancelled
+// while it ran, whatever the processor made of its cancelled context.
+func (w *Worker) runJob(ctx context.Context, processor JobProcessor, job *models.Job) (err error) {
+	// The job continues the trace of the request that queued it
+	ctx, span := tracing.Tracer().Start(tracing.Extract(ctx, job.TraceContext), "job "+string(job.Type),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.Int64("job.id", int64(job.ID)),
+			attribute.String("job.type", string(job.Type)),
+			attribute.Int("job.retry_count", job.RetryCount),
+			attribute.String("job.worker_id", w.id),
+		))
+	defer func() { tracing.End(span, err) }()
+
+	jobCtx, cancelJob := context.WithCancelCause(ctx)
+	defer cancelJob(nil)
+	w.setRunning(job.ID, cancelJob)
+	defer w.setRunning(0, nil)
+
+	timeout := w.timeouts.For(job.Type)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		jobCtx, cancel = context.WithTimeout(jobCtx, timeout)
+		defer cancel()
+	}
+
+	if w.heartbeatInterval > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		go w.heartbeat(jobCtx, job.ID, done, cancelJob)
+	}
+
+	err = processor.ProcessJob(jobCtx, job)
+	if errors.Is(context.Cause(jobCtx), jobs.ErrJobCancelled) {
+		return fmt.Errorf("%w while processing", jobs.ErrJobCancelled)
+	}
+	if err != nil && timeout > 0 && errors.Is(jobCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return models.NewSystemError("job_timeout", fmt.Sprintf("job exceeded its %s timeout", timeout), err.Error(), err)
+	}
+	return err
+}
+
+// heartbeat records that the job is alive every heartbeatInterval. A job
+// cancelled from another process is found this way, and stopped with cancel.
+func (w *Worker) heartbeat(ctx context.Context, jobID uint, done <-chan struct{}, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(w.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := w.jobService.Heartbeat(ctx, jobID)
+			if errors.Is(err, jobs.ErrJobCancelled) {
+				cancel(jobs.ErrJobCancelled)
+				return
+			}
+			if err != nil && !errors.Is(err, jobs.ErrJobNotFound) {
+				log.Printf("Worker %s: failed to record heartbeat for job %d: %v", w.id, jobID, err)
+			}
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// setRunning records the job the worker is processing and how to stop it
+func (w *Worker) setRunning(jobID uint, cancel context.CancelCauseFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.runningJob, w.cancelJob = jobID, cancel
+}
+
+// cancelRunning stops jobID if this worker is processing it
+func (w *Worker) cancelRunning(jobID uint) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.runningJob != jobID || w.cancelJob == nil {
+		return false
+	}
+	w.cancelJob(jobs.ErrJobCancelled)
+	return true
+}
+
+// notifyFailed handles a failed job once it will not be retried
+func (w *Worker) notifyFailed(ctx context.Context, jobID uint) {
+	job, err := w.jobService.GetJob(ctx, jobID)
+	if err != nil {
+		return
+	}
+	notifyFailed(ctx, w.jobService, w.notifiers, job, "Worker "+w.id)
+}
+
+// notifyFailed handles a job that will not be retried: the jobs waiting on it
+// fail too, and failure notifiers hear about all of them
+func notifyFailed(ctx context.Context, jobService jobs.Service, notifiers []JobNotifier, job *models.Job, source string) {
+	if !job.IsTerminal() {
+		return
+	}
+
+	failed := []*models.Job{job}
+	dependents, err := jobService.FailDependents(ctx, job.ID)
+	if err != nil {
+		log.Printf("%s: failed to fail dependents of job %d: %v", source, job.ID, err)
+	}
+	failed = append(failed, dependents...)
+
+	for _, notifier := range notifiers {
+		failureNotifier, ok := notifier.(JobFailureNotifier)
+		if !ok {
+			continue
+		}
+		for _, job := range failed {
+			if err := failureNotifier.NotifyJobFailed(ctx, job); err != nil {
+				log.Printf("%s: failed to notify failure of job %d: %v", source, job.ID, err)
+			}
+		}
+	}
+}
+
 type WorkerPool struct {
 	workers    []*Worker
 	jobService jobs.Service
+	reaper     *Reaper
 	mu         sync.RWMutex
 	started    bool
+	ctx        context.Context // Workers added by Resize run under the pool's context
+
+	// What each worker is set up with, for workers added by Resize
+	pollInterval      time.Duration
+	processors        []JobProcessor
+	notifiers         []JobNotifier
+	timeouts          jobs.Timeouts
+	heartbeatInterval time.Duration
+	lastWorkerID      int            // Worker IDs aren't reused, so the reaper can't mistake one for another
+	retiring          sync.WaitGroup // Workers removed by Resize finishing their job
 }
 
 func NewWorkerPool(jobService jobs.Service, workerCount int, pollInterval time.Duration) *WorkerPool {
 	pool := &WorkerPool{
-		jobService: jobService,
-		workers:    make([]*Worker, workerCount),
+		jobService:   jobService,
+		workers:      make([]*Worker, workerCount),
+		pollInterval: pollInterval,
 	}
 
 	for i := 0; i < workerCount; i++ {
-		workerID := fmt.Sprintf("worker-%d", i+1)
-		pool.workers[i] = NewWorker(workerID, jobService, pollInterval)
+		pool.workers[i] = pool.newWorker()
 	}
 
+	// Jobs cancelled through this process stop at once; others at their
+	// worker's next heartbeat
+	jobService.OnCancel(pool.cancelRunning)
+
 	return pool
 }
 
+// newWorker creates a worker set up like the pool's others
+func (p *WorkerPool) newWorker() *Worker {
+	p.lastWorkerID++
+	worker := NewWorker(fmt.Sprintf("worker-%d", p.lastWorkerID), p.jobService, p.pollInterval)
+	worker.processors = append(worker.processors, p.processors...)
+	worker.notifiers = append(worker.notifiers, p.notifiers...)
+	worker.timeouts = p.timeouts
+	worker.heartbeatInterval = p.heartbeatInterval
+	return worker
+}
+
+// cancelRunning stops a cancelled job if one of the pool's workers is running it
+func (p *WorkerPool) cancelRunning(jobID uint) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, worker := range p.workers {
+		if worker.cancelRunning(jobID) {
+			log.Printf("Stopping cancelled job %d on worker %s", jobID, worker.id)
+			return
+		}
+	}
+}
+
 func (p *WorkerPool) RegisterProcessor(processor JobProcessor) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	p.processors = append(p.processors, processor)
 	for _, worker := range p.workers {
 		worker.RegisterProcessor(processor)
 	}
 }
 
+// AddNotifier reports successful jobs to notifier, after any notifiers added
+// before it. Call before Start.
+func (p *WorkerPool) AddNotifier(notifier JobNotifier) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.notifiers = append(p.notifiers, notifier)
+	for _, worker := range p.workers {
+		worker.notifiers = append(worker.notifiers, notifier)
+	}
+	if p.reaper != nil {
+		p.reaper.notifiers = append(p.reaper.notifiers, notifier)
+	}
+}
+
+// SetTimeouts limits how long each job type may run and makes workers send a
+// heartbeat every heartbeatInterval while processing. Call before Start.
+func (p *WorkerPool) SetTimeouts(timeouts jobs.Timeouts, heartbeatInterval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.timeouts, p.heartbeatInterval = timeouts, heartbeatInterval
+	for _, worker := range p.workers {
+		worker.timeouts = timeouts
+		worker.heartbeatInterval = heartbeatInterval
+	}
+}
+
+// EnableReaper runs a reaper with the pool, failing jobs left in processing by
+// a crashed or hung worker. Notifiers added before or after hear about jobs it
+// fails for good. Call before Start.
+func (p *WorkerPool) EnableReaper(config ReaperConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reaper = NewReaper(p.jobService, config)
+	p.reaper.notifiers = append(p.reaper.notifiers, p.notifiers...)
+}
+
 func (p *WorkerPool) Start(ctx context.Context) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -183,11 +445,54 @@ func (p *WorkerPool) Start(ctx context.Context) error {
 	for _, worker := range p.workers {
 		worker.Start(ctx)
 	}
+	if p.reaper != nil {
+		p.reaper.Start(ctx)
+	}
 
+	p.ctx = ctx
 	p.started = true
 	return nil
 }
 
+// Resize changes the number of workers. Added workers start at once when the
+// pool is running; removed ones stop after finishing the job they are on.
+func (p *WorkerPool) Resize(workerCount int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	workerCount = max(workerCount, 0)
+	if workerCount == len(p.workers) {
+		return
+	}
+	log.Printf("Resizing worker pool from %d to %d workers", len(p.workers), workerCount)
+
+	for len(p.workers) < workerCount {
+		worker := p.newWorker()
+		p.workers = append(p.workers, worker)
+		if p.started {
+			worker.Start(p.ctx)
+		}
+	}
+	for len(p.workers) > workerCount {
+		worker := p.workers[len(p.workers)-1]
+		p.workers = p.workers[:len(p.workers)-1]
+		if p.started {
+			p.retiring.Add(1)
+			go func() {
+				defer p.retiring.Done()
+				worker.Stop()
+			}()
+		}
+	}
+}
+
+// Size returns the number of workers
+func (p *WorkerPool) Size() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.workers)
+}
+
 func (p *WorkerPool) Stop() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -198,9 +503,13 @@ func (p *WorkerPool) Stop() {
 
 	log.Printf("Stopping worker pool")
 
+	if p.reaper != nil {
+		p.reaper.Stop()
+	}
 	for _, worker := range p.workers {
 		worker.Stop()
 	}
+	p.retiring.Wait()
 
 	p.started = false
 }
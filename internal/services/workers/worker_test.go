@@ -0,0 +1,155 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/killallgit/player-api/internal/models"
+	"github.com/killallgit/player-api/internal/services/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// blockingProcessor waits for its context to end
+type blockingProcessor struct{}
+
+func (blockingProcessor) ProcessJob(ctx context.Context, job *models.Job) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingProcessor) CanProcess(models.JobType) bool { return true }
+
+func TestWorker_RunJobTimeout(t *testing.T) {
+	worker := NewWorker("worker-1", nil, time.Second)
+	worker.timeouts = jobs.Timeouts{
+		Default: time.Hour,
+		ByType:  map[models.JobType]time.Duration{models.JobTypeWaveformGeneration: 10 * time.Millisecond},
+	}
+
+	err := worker.runJob(context.Background(), blockingProcessor{}, &models.Job{Type: models.JobTypeWaveformGeneration})
+	var structured *models.StructuredJobError
+	require.True(t, errors.As(err, &structured))
+	assert.Equal(t, models.ErrorTypeSystem, structured.Type)
+	assert.Equal(t, "job_timeout", structured.Code)
+
+	// Shutdown is not reported as a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = worker.runJob(ctx, blockingProcessor{}, &models.Job{Type: models.JobTypeWaveformGeneration})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func setupJobService(t *testing.T) (jobs.Service, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Job{}, &models.JobDependency{}))
+	return jobs.NewService(jobs.NewRepository(db)), db
+}
+
+// runCancelled processes the next job on worker, cancelling it with cancel
+// once it is running, and returns what processNextJob returned
+func runCancelled(t *testing.T, svc jobs.Service, worker *Worker, jobID uint, cancel func()) error {
+	result := make(chan error, 1)
+	go func() { result <- worker.processNextJob(context.Background()) }()
+
+	require.Eventually(t, func() bool {
+		job, err := svc.GetJob(context.Background(), jobID)
+		return err == nil && job.Status == models.JobStatusProcessing
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("processor was not stopped")
+		return nil
+	}
+}
+
+func TestWorkerPool_CancelStopsRunningJob(t *testing.T) {
+	svc, _ := setupJobService(t)
+	ctx := context.Background()
+	pool := NewWorkerPool(svc, 1, time.Hour)
+	pool.RegisterProcessor(blockingProcessor{})
+
+	job, err := svc.EnqueueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+	child, err := svc.EnqueueJob(ctx, models.JobTypeTranscriptionGeneration, models.JobPayload{"episode_id": 1}, jobs.WithDependsOn(job.ID))
+	require.NoError(t, err)
+
+	err = runCancelled(t, svc, pool.workers[0], job.ID, func() {
+		_, err := svc.CancelJob(ctx, job.ID)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	cancelled, err := svc.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusCancelled, cancelled.Status, "not failed by the stopped processor")
+	assert.Empty(t, cancelled.Error)
+
+	dependent, err := svc.GetJob(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusPermanentlyFailed, dependent.Status)
+
+	_, err = svc.CancelJob(ctx, job.ID)
+	require.NoError(t, err, "cancelling again is a no-op")
+	_, err = svc.CancelJob(ctx, child.ID)
+	assert.ErrorIs(t, err, jobs.ErrJobFinished)
+}
+
+func TestWorker_HeartbeatFindsCancellation(t *testing.T) {
+	svc, db := setupJobService(t)
+	ctx := context.Background()
+	worker := NewWorker("worker-1", svc, time.Hour)
+	worker.RegisterProcessor(blockingProcessor{})
+	worker.heartbeatInterval = 10 * time.Millisecond
+
+	job, err := svc.EnqueueJob(ctx, models.JobTypeWaveformGeneration, models.JobPayload{"episode_id": 1})
+	require.NoError(t, err)
+
+	// Cancelled by another process, whose hooks this worker doesn't hear
+	other := jobs.NewService(jobs.NewRepository(db))
+	err = runCancelled(t, svc, worker, job.ID, func() {
+		_, err := other.CancelJob(ctx, job.ID)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	cancelled, err := svc.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusCancelled, cancelled.Status)
+}
+
+func TestWorkerPool_Resize(t *testing.T) {
+	svc, _ := setupJobService(t)
+	pool := NewWorkerPool(svc, 1, time.Hour)
+	pool.RegisterProcessor(blockingProcessor{})
+	pool.SetTimeouts(jobs.Timeouts{Default: time.Minute}, time.Second)
+
+	pool.Resize(3)
+	require.Equal(t, 3, pool.Size())
+	for _, worker := range pool.workers {
+		assert.Len(t, worker.processors, 1, worker.id)
+		assert.Equal(t, time.Minute, worker.timeouts.Default, worker.id)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, pool.Start(ctx))
+
+	pool.Resize(1)
+	assert.Equal(t, 1, pool.Size())
+	pool.Resize(2)
+	require.Equal(t, 2, pool.Size())
+	assert.Equal(t, "worker-4", pool.workers[1].id, "IDs of removed workers aren't reused")
+
+	pool.Stop()
+}
@@ -0,0 +1,173 @@
+package chapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FetchOptions configures chapter fetching behavior
+type FetchOptions struct {
+	Timeout   time.Duration
+	UserAgent string
+	MaxSize   int64         // Maximum chapters file size in bytes
+	CacheTTL  time.Duration // How long a fetched file, or a failure to fetch it, is remembered
+}
+
+// DefaultFetchOptions returns default fetch options
+func DefaultFetchOptions() FetchOptions {
+	return FetchOptions{
+		Timeout:   10 * time.Second,
+		UserAgent: "PodcastPlayerAPI/1.0",
+		MaxSize:   2 * 1024 * 1024, // 2MB; chapter files are small even with many entries
+		CacheTTL:  time.Hour,
+	}
+}
+
+// Chapter is one entry of a Podcasting 2.0 JSON chapters file
+type Chapter struct {
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime,omitempty"` // Zero when the file leaves it to the next chapter's start
+	Title     string  `json:"title"`
+	Img       string  `json:"img,omitempty"`
+	URL       string  `json:"url,omitempty"`
+	TOC       *bool   `json:"toc,omitempty"` // False for chapters players show but leave out of the table of contents
+}
+
+// File is a Podcasting 2.0 JSON chapters file
+type File struct {
+	Version  string    `json:"version"`
+	Chapters []Chapter `json:"chapters"`
+}
+
+// Fetcher downloads and parses chapter files, remembering each for CacheTTL
+type Fetcher struct {
+	client  *http.Client
+	options FetchOptions
+
+	mu    sync.Mutex
+	cache map[string]cachedFile
+	now   func() time.Time
+}
+
+type cachedFile struct {
+	chapters  []Chapter
+	err       error
+	expiresAt time.Time
+}
+
+// NewFetcher creates a new chapters fetcher
+func NewFetcher(options FetchOptions) *Fetcher {
+	return &Fetcher{
+		client:  &http.Client{Timeout: options.Timeout},
+		options: options,
+		cache:   make(map[string]cachedFile),
+		now:     time.Now,
+	}
+}
+
+// Fetch returns the chapters at url sorted by start time
+func (f *Fetcher) Fetch(ctx context.Context, url string) ([]Chapter, error) {
+	if url == "" {
+		return nil, fmt.Errorf("empty chapters URL")
+	}
+
+	f.mu.Lock()
+	cached, ok := f.cache[url]
+	f.mu.Unlock()
+	if ok && f.now().Before(cached.expiresAt) {
+		return cached.chapters, cached.err
+	}
+
+	chapters, err := f.fetch(ctx, url)
+	// A caller giving up says nothing about the file, so it isn't remembered
+	if ctx.Err() == nil && f.options.CacheTTL > 0 {
+		f.mu.Lock()
+		f.removeExpired()
+		f.cache[url] = cachedFile{chapters: chapters, err: err, expiresAt: f.now().Add(f.options.CacheTTL)}
+		f.mu.Unlock()
+	}
+	return chapters, err
+}
+
+func (f *Fetcher) fetch(ctx context.Context, url string) ([]Chapter, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", f.options.UserAgent)
+	req.Header.Set("Accept", "application/json+chapters,application/json,*/*")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chapters: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > f.options.MaxSize {
+		return nil, fmt.Errorf("chapters too large: %d bytes (max: %d)", resp.ContentLength, f.options.MaxSize)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.options.MaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chapters: %w", err)
+	}
+	return Parse(body)
+}
+
+// removeExpired drops expired entries so feeds that are never asked for
+// again don't pile up. Callers hold f.mu.
+func (f *Fetcher) removeExpired() {
+	now := f.now()
+	for url, cached := range f.cache {
+		if !now.Before(cached.expiresAt) {
+			delete(f.cache, url)
+		}
+	}
+}
+
+// Parse reads a JSON chapters file, returning its chapters sorted by start time
+func Parse(data []byte) ([]Chapter, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid chapters file: %w", err)
+	}
+	chapters := make([]Chapter, 0, len(file.Chapters))
+	for _, chapter := range file.Chapters {
+		if chapter.StartTime >= 0 {
+			chapters = append(chapters, chapter)
+		}
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].StartTime < chapters[j].StartTime
+	})
+	return chapters, nil
+}
+
+// At returns the chapter playing at position seconds, or nil before the
+// first chapter and in gaps after a chapter's end time. A chapter without an
+// end time runs until the next one starts, which is filled in.
+func At(chapters []Chapter, position float64) *Chapter {
+	index := sort.Search(len(chapters), func(i int) bool {
+		return chapters[i].StartTime > position
+	}) - 1
+	if index < 0 {
+		return nil
+	}
+	chapter := chapters[index]
+	if chapter.EndTime > 0 && position >= chapter.EndTime {
+		return nil
+	}
+	if chapter.EndTime <= 0 && index+1 < len(chapters) {
+		chapter.EndTime = chapters[index+1].StartTime
+	}
+	return &chapter
+}
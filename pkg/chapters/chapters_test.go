@@ -0,0 +1,109 @@
+package chapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testFile = `{
+	"version": "1.2.0",
+	"chapters": [
+		{"startTime": 300, "title": "Interview", "img": "https://example.com/guest.jpg"},
+		{"startTime": 0, "title": "Intro"},
+		{"startTime": 1800, "endTime": 1860, "title": "Sponsor", "toc": false},
+		{"startTime": 1900, "title": "Wrap-up", "url": "https://example.com/notes"}
+	]
+}`
+
+func TestAt(t *testing.T) {
+	chapters, err := Parse([]byte(testFile))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		position  float64
+		wantTitle string
+		wantEnd   float64
+	}{
+		{position: 0, wantTitle: "Intro", wantEnd: 300},
+		{position: 299.9, wantTitle: "Intro", wantEnd: 300},
+		{position: 300, wantTitle: "Interview", wantEnd: 1800},
+		{position: 1830, wantTitle: "Sponsor", wantEnd: 1860},
+		{position: 1870},
+		{position: 5000, wantTitle: "Wrap-up"},
+	}
+
+	for _, tt := range tests {
+		chapter := At(chapters, tt.position)
+		if tt.wantTitle == "" {
+			if chapter != nil {
+				t.Errorf("At(%v) = %q, want no chapter", tt.position, chapter.Title)
+			}
+			continue
+		}
+		if chapter == nil {
+			t.Errorf("At(%v) = nil, want %q", tt.position, tt.wantTitle)
+			continue
+		}
+		if chapter.Title != tt.wantTitle || chapter.EndTime != tt.wantEnd {
+			t.Errorf("At(%v) = %q ending %v, want %q ending %v", tt.position, chapter.Title, chapter.EndTime, tt.wantTitle, tt.wantEnd)
+		}
+	}
+
+	if chapter := At(nil, 10); chapter != nil {
+		t.Errorf("Expected no chapter without chapters, got %q", chapter.Title)
+	}
+}
+
+func TestFetchCaches(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/missing.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json+chapters")
+		_, _ = w.Write([]byte(testFile))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fetcher := NewFetcher(DefaultFetchOptions())
+	fetcher.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		chapters, err := fetcher.Fetch(ctx, server.URL+"/chapters.json")
+		if err != nil {
+			t.Fatalf("Fetch failed: %v", err)
+		}
+		if len(chapters) != 4 || chapters[0].Title != "Intro" {
+			t.Fatalf("Expected 4 chapters starting with Intro, got %+v", chapters)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the second fetch to be cached, got %d requests", requests)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := fetcher.Fetch(ctx, server.URL+"/missing.json"); err == nil {
+			t.Fatal("Expected an error for a missing file")
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected failures to be cached too, got %d requests", requests)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := fetcher.Fetch(ctx, server.URL+"/chapters.json"); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected an expired file to be fetched again, got %d requests", requests)
+	}
+}
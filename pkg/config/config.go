@@ -12,7 +12,7 @@ import (
 // Init initializes the configuration system using standard Viper practices
 func Init() error {
 	// Set defaults
-	setDefaults()
+	setDefaults(viper.GetViper())
 
 	// Set config name and paths
 	viper.SetConfigName("config")
@@ -78,6 +78,16 @@ func GetDuration(key string) time.Duration {
 	return viper.GetDuration(key)
 }
 
+// GetFloat64 returns a float config value
+func GetFloat64(key string) float64 {
+	return viper.GetFloat64(key)
+}
+
+// GetStringMapString returns a string map config value
+func GetStringMapString(key string) map[string]string {
+	return viper.GetStringMapString(key)
+}
+
 // validateConfig validates the configuration
 func validateConfig() error {
 	// Check if we're in test mode to suppress warnings
@@ -167,74 +177,258 @@ func (c *Config) Validate() error {
 	return nil
 }
 
-func setDefaults() {
-	viper.SetDefault("server.host", "0.0.0.0")
-	viper.SetDefault("server.port", 9000)
-	viper.SetDefault("server.shutdown_timeout", "10s")
-	viper.SetDefault("server.read_timeout", "30s")
-	viper.SetDefault("server.write_timeout", "30s")
-
-	viper.SetDefault("database.path", "./data/podcast.db")
-	viper.SetDefault("database.verbose", false)
-
-	viper.SetDefault("podcast_index.api_url", "https://api.podcastindex.org/api/1.0")
-	viper.SetDefault("podcast_index.timeout", "30s")
-	viper.SetDefault("podcast_index.user_agent", "PodcastPlayerAPI/1.0")
-
-	viper.SetDefault("episodes.max_concurrent_sync", 5)
-	viper.SetDefault("episodes.sync_timeout", "30s")
-
-	viper.SetDefault("security.cors_enabled", true)
-	viper.SetDefault("security.cors_origins", "*")
-	viper.SetDefault("security.cors_methods", "GET,POST,PUT,DELETE,OPTIONS")
-	viper.SetDefault("security.cors_headers", "Content-Type,Authorization")
-	viper.SetDefault("security.rate_limit_enabled", true)
-	viper.SetDefault("security.rate_limit_rps", 10)
-	viper.SetDefault("security.rate_limit_burst", 20)
-
-	viper.SetDefault("dev.auth_enabled", false)
-	viper.SetDefault("dev.auth_token", "")
-
-	viper.SetDefault("processing.workers", 2)
-	viper.SetDefault("processing.max_queue_size", 100)
-	viper.SetDefault("processing.timeout", "5m")
-
-	viper.SetDefault("transcription.enabled", false)
-	viper.SetDefault("transcription.prefer_existing", true)
-	viper.SetDefault("transcription.fetch_timeout", "30s")
-	viper.SetDefault("transcription.allowed_transcript_formats", "vtt,srt,txt,json")
-
-	viper.SetDefault("cache.enabled", true)
-	viper.SetDefault("cache.type", "memory")
-	viper.SetDefault("cache.max_size_mb", 100)
-	viper.SetDefault("cache.default_ttl", "30m")
-	viper.SetDefault("cache.cleanup_interval", "1m")
-	viper.SetDefault("cache.ttl_search", 15)
-	viper.SetDefault("cache.ttl_trending", 60)
-	viper.SetDefault("cache.ttl_podcast", 120)
-	viper.SetDefault("cache.ttl_episode", 60)
-	viper.SetDefault("cache.ttl_reviews", 120)
-	viper.SetDefault("cache.ttl_categories", 240)
-	viper.SetDefault("cache.ttl_waveform", 1440)
-
-	viper.SetDefault("clips.storage_path", "./clips")
-	viper.SetDefault("clips.target_duration", 0.0)
-
-	viper.SetDefault("ffmpeg.path", "ffmpeg")
-	viper.SetDefault("ffmpeg.ffprobe_path", "ffprobe")
-	viper.SetDefault("ffmpeg.timeout", "300s")
-
-	viper.SetDefault("temp_dir", "./tmp")
-
-	viper.SetDefault("transcription.model_path", "./models/ggml-base.en.bin")
-	viper.SetDefault("transcription.whisper_path", "whisper-cpp")
-	viper.SetDefault("transcription.language", "en")
-
-	viper.SetDefault("audio_cache.directory", "./audio-cache")
-
-	viper.SetDefault("cleanup.interval", "5m")
-	viper.SetDefault("cleanup.max_age", "1h")
-
-	viper.SetDefault("logging.level", "info")
-	viper.SetDefault("logging.format", "text")
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("server.host", "0.0.0.0")
+	v.SetDefault("server.port", 9000)
+	v.SetDefault("server.shutdown_timeout", "10s")
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "killallplayer-api")
+	v.SetDefault("tracing.endpoint", "localhost:4318")
+	v.SetDefault("tracing.url_path", "/v1/traces")
+	v.SetDefault("tracing.insecure", true)
+	v.SetDefault("tracing.headers", map[string]string{})
+	v.SetDefault("tracing.sample_ratio", 1.0)
+	v.SetDefault("server.read_header_timeout", "10s")
+	v.SetDefault("server.read_timeout", "30s")
+	v.SetDefault("server.write_timeout", "60s")
+	v.SetDefault("server.idle_timeout", "120s")
+	v.SetDefault("server.stream_write_timeout", "60s")
+	v.SetDefault("server.http2", true)
+
+	v.SetDefault("database.path", "./data/podcast.db")
+	v.SetDefault("database.verbose", false)
+
+	v.SetDefault("podcast_index.api_url", "https://api.podcastindex.org/api/1.0")
+	v.SetDefault("podcast_index.timeout", "30s")
+	v.SetDefault("podcast_index.user_agent", "PodcastPlayerAPI/1.0")
+	v.SetDefault("podcast_index.daily_budget", 0)
+
+	v.SetDefault("episodes.max_concurrent_sync", 5)
+	v.SetDefault("episodes.sync_timeout", "30s")
+	v.SetDefault("episodes.fetch_attempts", 3)
+	v.SetDefault("episodes.fetch_backoff", "250ms")
+	v.SetDefault("episodes.missing_ttl", "10m")
+
+	v.SetDefault("security.cors_enabled", true)
+	v.SetDefault("security.cors_origins", "*")
+	v.SetDefault("security.cors_methods", "GET,HEAD,POST,PUT,DELETE,OPTIONS")
+	v.SetDefault("security.cors_headers", "Content-Type,Authorization,Range,If-Range")
+	v.SetDefault("security.cors_expose_headers", "Content-Length,Content-Range,Accept-Ranges,ETag")
+	v.SetDefault("security.cors_allow_credentials", false)
+	v.SetDefault("security.cors_max_age", "24h")
+	v.SetDefault("request_limits.max_body_kb", 1024)
+	v.SetDefault("request_limits.multipart_memory_mb", 8)
+	v.SetDefault("request_limits.routes", []map[string]interface{}{
+		// Exports of large libraries with listening history run to several MB
+		{"prefix": "/api/v1/me/import", "max_body_kb": 10240, "multipart": true},
+		// Predictions uploaded for evaluation carry one entry per dataset clip
+		{"prefix": "/api/v1/datasets", "max_body_kb": 16384},
+	})
+
+	v.SetDefault("security.rate_limit_enabled", true)
+	v.SetDefault("security.rate_limit_rps", 10)
+	v.SetDefault("security.rate_limit_burst", 20)
+	v.SetDefault("security.search_rate_limit_rps", 5)
+	v.SetDefault("security.search_rate_limit_burst", 10)
+	v.SetDefault("security.rate_limit_tiers", []map[string]interface{}{
+		{"name": "admin", "permission": "podcasts:admin", "multiplier": 10},
+	})
+
+	v.SetDefault("dev.auth_enabled", false)
+	v.SetDefault("dev.auth_token", "")
+
+	v.SetDefault("processing.workers", 2)
+	v.SetDefault("processing.max_queue_size", 100)
+	v.SetDefault("processing.timeout", "5m")
+	v.SetDefault("jobs.timeout", "1h")
+	v.SetDefault("jobs.timeouts", map[string]string{
+		"transcription_generation": "4h",
+		"dataset_generation":       "6h",
+		"user_export":              "2h",
+		"library_import":           "2h",
+	})
+	v.SetDefault("jobs.heartbeat_interval", "30s")
+	v.SetDefault("jobs.heartbeat_timeout", "5m")
+	v.SetDefault("jobs.reaper_interval", "1m")
+	v.SetDefault("jobs.queue_limit", 1000)
+	v.SetDefault("jobs.queue_limits", map[string]int{})
+	v.SetDefault("jobs.low_priority_share", 0.5)
+	v.SetDefault("jobs.queue_retry_after", "30s")
+
+	v.SetDefault("transcription.enabled", false)
+	v.SetDefault("transcription.prefer_existing", true)
+	v.SetDefault("transcription.fetch_timeout", "30s")
+	v.SetDefault("transcription.allowed_transcript_formats", "vtt,srt,txt,json")
+
+	v.SetDefault("content_safety.enabled", true)
+	v.SetDefault("content_safety.padding_seconds", 0.75)
+	v.SetDefault("content_safety.extra_terms", []string{})
+
+	v.SetDefault("summary.enabled", false)
+	v.SetDefault("summary.api_url", "https://api.openai.com/v1")
+	v.SetDefault("summary.api_key", "")
+	v.SetDefault("summary.model", "gpt-4o-mini")
+	v.SetDefault("summary.timeout", "2m")
+	v.SetDefault("summary.max_input_tokens", 12000)
+	v.SetDefault("summary.max_output_tokens", 800)
+	v.SetDefault("summary.daily_token_budget", 0)
+
+	v.SetDefault("cache.enabled", true)
+	v.SetDefault("cache.type", "memory")
+	v.SetDefault("cache.max_size_mb", 100)
+	v.SetDefault("cache.default_ttl", "30m")
+	v.SetDefault("cache.cleanup_interval", "1m")
+	v.SetDefault("cache.ttl_search", 15)
+	v.SetDefault("cache.ttl_trending", 60)
+	v.SetDefault("cache.ttl_recent", 5)
+	v.SetDefault("cache.ttl_random", 2)
+	v.SetDefault("cache.ttl_podcast", 120)
+	v.SetDefault("cache.ttl_episode", 60)
+	v.SetDefault("cache.ttl_reviews", 120)
+	v.SetDefault("cache.ttl_categories", 240)
+	v.SetDefault("cache.ttl_waveform", 1440)
+
+	v.SetDefault("clips.storage_path", "./clips")
+	v.SetDefault("clips.deduplicate", true)
+	v.SetDefault("clips.target_duration", 0.0)
+	v.SetDefault("clips.skip_labels", []string{"advertisement"})
+	v.SetDefault("clips.skip_min_confidence", 0.0)
+	v.SetDefault("clips.skip_merge_gap", 1.0)
+	v.SetDefault("clips.label_quotas", map[string]int{})
+	v.SetDefault("clips.auto_approve_thresholds", map[string]float64{})
+	v.SetDefault("clips.integrity_check_interval", "24h")
+	v.SetDefault("clips.integrity_repair", "")
+
+	v.SetDefault("ffmpeg.path", "ffmpeg")
+	v.SetDefault("ffmpeg.ffprobe_path", "ffprobe")
+	v.SetDefault("ffmpeg.timeout", "300s")
+	v.SetDefault("ffmpeg.cpu_time_limit", "10m")
+	v.SetDefault("ffmpeg.nice", 10)
+	v.SetDefault("ffmpeg.max_output_mb", 512)
+	v.SetDefault("ffmpeg.max_concurrent", 4)
+	v.SetDefault("ffmpeg.preview_timeout", "60s")
+
+	v.SetDefault("temp_dir", "./tmp")
+
+	v.SetDefault("datasets.directory", "./datasets")
+	v.SetDefault("datasets.signing_secret", "")
+	v.SetDefault("datasets.url_ttl", "24h")
+	v.SetDefault("datasets.max_url_ttl", "168h")
+	v.SetDefault("datasets.license", "")
+
+	v.SetDefault("pipeline.default_profile", "waveform")
+	v.SetDefault("pipeline.profiles", map[string][]string{
+		"none":     {},
+		"waveform": {"waveform"},
+		"standard": {"waveform", "transcription"},
+		"full":     {"waveform", "transcription", "analysis"},
+	})
+	v.SetDefault("pipeline.podcast_profiles", map[string]string{})
+
+	v.SetDefault("webhooks.signing_secret", "")
+	v.SetDefault("webhooks.timeout", "10s")
+	v.SetDefault("webhooks.max_attempts", 3)
+	v.SetDefault("webhooks.retry_backoff", "2s")
+	v.SetDefault("webhooks.allow_private", false)
+
+	v.SetDefault("export.directory", "./exports")
+	v.SetDefault("export.signing_secret", "")
+	v.SetDefault("export.url_ttl", "15m")
+
+	v.SetDefault("account_deletion.grace_period", "720h")
+	v.SetDefault("account_deletion.clip_policy", "anonymize")
+	v.SetDefault("account_deletion.sweep_interval", "1h")
+
+	v.SetDefault("notifications.retention", "720h")
+	v.SetDefault("notifications.prune_interval", "6h")
+
+	v.SetDefault("events.enabled", true)
+	v.SetDefault("events.max_batch", 500)
+	v.SetDefault("events.sample_rate", 1.0)
+	v.SetDefault("events.retention", "2160h")
+	v.SetDefault("events.prune_interval", "6h")
+
+	v.SetDefault("feed_health.enabled", true)
+	v.SetDefault("feed_health.degraded_after", 3)
+	v.SetDefault("feed_health.dead_after", 10)
+	v.SetDefault("feed_health.dead_min_age", "168h")
+
+	v.SetDefault("artwork.palette_enabled", true)
+	v.SetDefault("artwork.palette_size", 5)
+	v.SetDefault("artwork.fetch_timeout", "10s")
+	v.SetDefault("artwork.retry_after", "24h")
+
+	v.SetDefault("chapters.fetch_timeout", "10s")
+	v.SetDefault("chapters.cache_ttl", "1h")
+
+	v.SetDefault("now_playing.artwork_sizes", []int{96, 256, 512})
+	v.SetDefault("now_playing.artwork_url", "")
+
+	v.SetDefault("share.base_url", "")
+	v.SetDefault("share.redirect_url", "")
+
+	v.SetDefault("saved_searches.enabled", true)
+	v.SetDefault("saved_searches.max_per_user", 25)
+	v.SetDefault("saved_searches.default_interval", "24h")
+	v.SetDefault("saved_searches.min_interval", "1h")
+	v.SetDefault("saved_searches.default_limit", 20)
+	v.SetDefault("saved_searches.max_limit", 100)
+	v.SetDefault("saved_searches.check_interval", "5m")
+	v.SetDefault("saved_searches.batch_size", 20)
+
+	v.SetDefault("live_items.enabled", true)
+	v.SetDefault("live_items.stale_after", "15m")
+	v.SetDefault("live_items.check_interval", "2m")
+	v.SetDefault("live_items.lead_time", "10m")
+	v.SetDefault("live_items.start_grace", "2h")
+	v.SetDefault("live_items.schedule_interval", "1m")
+	v.SetDefault("live_items.batch_size", 50)
+
+	v.SetDefault("inbox.max_age", "720h")
+
+	v.SetDefault("transcription.model_path", "./models/ggml-base.en.bin")
+	v.SetDefault("transcription.whisper_path", "whisper-cpp")
+	v.SetDefault("transcription.language", "en")
+	v.SetDefault("transcription.models_dir", "")
+	v.SetDefault("transcription.default_model", "")
+	v.SetDefault("transcription.podcast_models", map[string]string{})
+	v.SetDefault("transcription.auto_download_models", true)
+	v.SetDefault("transcription.model_base_url", "https://huggingface.co/ggerganov/whisper.cpp/resolve/main")
+	v.SetDefault("transcription.backend", "local")
+	v.SetDefault("transcription.openai.base_url", "https://api.openai.com/v1")
+	v.SetDefault("transcription.openai.api_key", "")
+	v.SetDefault("transcription.openai.model", "whisper-1")
+	v.SetDefault("transcription.openai.max_upload_bytes", 25*1024*1024)
+	v.SetDefault("transcription.openai.timeout", "30m")
+	v.SetDefault("transcription.faster_whisper.url", "")
+	v.SetDefault("transcription.faster_whisper.model", "faster-whisper")
+	v.SetDefault("transcription.faster_whisper.timeout", "30m")
+	v.SetDefault("transcription.live.chunk_duration", "30s")
+	v.SetDefault("transcription.live.poll_interval", "2s")
+	v.SetDefault("transcription.live.idle_timeout", "2m")
+	v.SetDefault("transcription.live.max_sessions", 2)
+
+	v.SetDefault("audio_cache.directory", "./audio-cache")
+
+	v.SetDefault("loudness.target_lufs", -16.0)
+	v.SetDefault("loudness.max_true_peak", -1.0)
+
+	v.SetDefault("download.user_agent", "")
+	v.SetDefault("download.referer", "")
+	v.SetDefault("download.max_retries", 2)
+	v.SetDefault("download.retry_backoff", "2s")
+	v.SetDefault("download.retry_statuses", []int{403, 429, 503})
+
+	v.SetDefault("stream_cache.directory", "./stream-cache")
+	v.SetDefault("stream_cache.chunk_size", 1048576)
+	v.SetDefault("stream_cache.fetch_timeout", "30s")
+	v.SetDefault("stream_cache.max_size_mb", 2048)
+
+	v.SetDefault("cleanup.interval", "5m")
+	v.SetDefault("cleanup.max_age", "1h")
+
+	v.SetDefault("config.watch", true)
+
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.format", "text")
 }
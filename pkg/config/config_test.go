@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -41,6 +42,35 @@ server:
 				}
 			},
 		},
+		{
+			name: "server timeouts from config file",
+			setup: func() {
+				viper.Reset()
+				content := `
+server:
+  read_timeout: 15s
+  write_timeout: 30s
+  idle_timeout: 2m
+`
+				_ = os.WriteFile("./config.yaml", []byte(content), 0644)
+			},
+			cleanup: func() {
+				_ = os.Remove("./config.yaml")
+				viper.Reset()
+			},
+			wantErr: false,
+			check: func(t *testing.T) {
+				if got := GetDuration("server.read_timeout"); got != 15*time.Second {
+					t.Errorf("Expected server.read_timeout to be 15s, got %v", got)
+				}
+				if got := GetDuration("server.write_timeout"); got != 30*time.Second {
+					t.Errorf("Expected server.write_timeout to be 30s, got %v", got)
+				}
+				if got := GetDuration("server.idle_timeout"); got != 2*time.Minute {
+					t.Errorf("Expected server.idle_timeout to be 2m, got %v", got)
+				}
+			},
+		},
 		{
 			name: "missing config file with defaults",
 			setup: func() {
@@ -57,6 +87,12 @@ server:
 				if GetInt("server.port") != 9000 {
 					t.Errorf("Expected default server.port to be 9000, got %d", GetInt("server.port"))
 				}
+				if got := GetDuration("server.write_timeout"); got != 60*time.Second {
+					t.Errorf("Expected default server.write_timeout to be 60s, got %v", got)
+				}
+				if got := GetDuration("server.stream_write_timeout"); got != 60*time.Second {
+					t.Errorf("Expected default server.stream_write_timeout to be 60s, got %v", got)
+				}
 			},
 		},
 	}
@@ -130,3 +166,31 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestChangedKeys(t *testing.T) {
+	before := map[string]any{
+		"logging.level":             "info",
+		"server.port":               9000,
+		"security.rate_limit_tiers": []any{map[string]any{"name": "admin", "multiplier": 10}},
+	}
+	after := map[string]any{
+		"logging.level":             "debug",
+		"security.rate_limit_tiers": []any{map[string]any{"name": "admin", "multiplier": 10}},
+		"processing.workers":        4,
+	}
+
+	got := changedKeys(before, after)
+	want := []string{"logging.level", "processing.workers", "server.port"}
+	if len(got) != len(want) {
+		t.Fatalf("changedKeys() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("changedKeys() = %v, want %v", got, want)
+		}
+	}
+
+	if !IsReloadable("processing.workers") || IsReloadable("server.port") {
+		t.Error("processing.workers should be reloadable and server.port not")
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// reloadableKeys are the settings applied while the server runs. Changing
+// anything else in the config file takes a restart.
+var reloadableKeys = map[string]bool{
+	"security.rate_limit_rps":          true,
+	"security.rate_limit_burst":        true,
+	"security.search_rate_limit_rps":   true,
+	"security.search_rate_limit_burst": true,
+	"security.rate_limit_tiers":        true,
+	"cache.default_ttl":                true,
+	"cache.ttl_search":                 true,
+	"cache.ttl_trending":               true,
+	"cache.ttl_recent":                 true,
+	"cache.ttl_random":                 true,
+	"cache.ttl_podcast":                true,
+	"cache.ttl_episode":                true,
+	"cache.ttl_categories":             true,
+	"processing.workers":               true,
+	"logging.level":                    true,
+}
+
+// IsReloadable reports whether a change to key is applied without a restart
+func IsReloadable(key string) bool {
+	return reloadableKeys[key]
+}
+
+// Watch re-reads the config file whenever it is saved and calls apply with
+// the reloadable keys whose values changed and the settings they were read
+// from. Each change is logged; changes to other keys are logged as waiting for
+// a restart. Watch does nothing when no config file was read or config.watch
+// is off.
+//
+// The file is re-read into its own viper instance, so the global settings that
+// requests read are never written while the server runs. apply must read the
+// new values from settings, and is called on the watcher's goroutine.
+func Watch(apply func(changed []string, settings *viper.Viper)) {
+	if viper.ConfigFileUsed() == "" || !viper.GetBool("config.watch") {
+		return
+	}
+
+	settings := newFileSettings()
+	if err := settings.ReadInConfig(); err != nil {
+		log.Printf("[WARN] Not watching %s for config changes: %v", viper.ConfigFileUsed(), err)
+		return
+	}
+
+	var mu sync.Mutex
+	previous := snapshot(settings)
+	settings.OnConfigChange(func(fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		current := snapshot(settings)
+		var changed []string
+		for _, key := range changedKeys(previous, current) {
+			if !IsReloadable(key) {
+				log.Printf("[WARN] Config %s changed; restart to apply it", key)
+				continue
+			}
+			log.Printf("[INFO] Config %s changed: %v -> %v", key, previous[key], current[key])
+			changed = append(changed, key)
+		}
+		previous = current
+
+		if len(changed) > 0 {
+			apply(changed, settings)
+		}
+	})
+	settings.WatchConfig()
+	log.Printf("[INFO] Watching %s for config changes", viper.ConfigFileUsed())
+}
+
+// newFileSettings returns a viper instance with the same defaults, file and
+// environment overrides as the global one
+func newFileSettings() *viper.Viper {
+	v := viper.New()
+	setDefaults(v)
+	v.SetConfigFile(viper.ConfigFileUsed())
+	v.SetEnvPrefix("KILLALL")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	return v
+}
+
+// snapshot returns every setting's current value
+func snapshot(v *viper.Viper) map[string]any {
+	values := make(map[string]any)
+	for _, key := range v.AllKeys() {
+		values[key] = v.Get(key)
+	}
+	return values
+}
+
+// changedKeys returns the keys added, removed or changed between two
+// snapshots, sorted
+func changedKeys(before, after map[string]any) []string {
+	var keys []string
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
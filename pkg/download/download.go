@@ -2,6 +2,7 @@ package download
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -18,8 +19,9 @@ type DownloadOptions struct {
 	MaxSize       int64         // Maximum file size in bytes (0 = no limit)
 	Timeout       time.Duration // Download timeout
 	ProgressFunc  ProgressFunc  // Optional progress callback
-	UserAgent     string        // User agent string
-	ValidateAudio bool          // Validate content-type is audio
+	UserAgent     string        // User agent string, used as the base policy when Policies is nil
+	ValidateAudio bool          // Validate content-type is audio or video; the audio track of a video is used
+	Policies      *Policies     // Per-host headers, concurrency and retries (nil = built-in defaults)
 }
 
 // ProgressFunc is called during download to report progress
@@ -31,7 +33,7 @@ func DefaultOptions() DownloadOptions {
 		TempDir:       "/tmp",
 		MaxSize:       500 * 1024 * 1024, // 500MB default max
 		Timeout:       5 * time.Minute,
-		UserAgent:     "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/119.0 Mobile/15E148 Safari/605.1.15",
+		UserAgent:     BrowserUserAgent,
 		ValidateAudio: true,
 	}
 }
@@ -45,15 +47,36 @@ type DownloadResult struct {
 	LastModified  time.Time // Last-Modified header if present
 }
 
+// StatusError is returned when the origin answers with a non-success status
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
 // Downloader handles downloading audio files to temporary storage
 type Downloader struct {
-	client  *http.Client
-	options DownloadOptions
+	client   *http.Client
+	options  DownloadOptions
+	policies *Policies
 }
 
 // NewDownloader creates a new downloader with the given options
 func NewDownloader(options DownloadOptions) *Downloader {
+	policies := options.Policies
+	if policies == nil {
+		base := DefaultBasePolicy()
+		if options.UserAgent != "" {
+			base.UserAgent = options.UserAgent
+		}
+		policies = NewPolicies(base, DefaultHostPolicies())
+	}
+
 	return &Downloader{
+		policies: policies,
 		client: &http.Client{
 			Timeout: options.Timeout,
 			Transport: &http.Transport{
@@ -73,6 +96,13 @@ func (d *Downloader) DownloadToTemp(ctx context.Context, url string, episodeID u
 
 	log.Printf("[DEBUG] Using enclosureUrl directly: %s", url)
 
+	policy := d.policies.For(url)
+	release, err := d.policies.Acquire(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for download slot: %w", err)
+	}
+	defer release()
+
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -80,9 +110,8 @@ func (d *Downloader) DownloadToTemp(ctx context.Context, url string, episodeID u
 	}
 
 	// Set headers
-	req.Header.Set("User-Agent", d.options.UserAgent)
 	req.Header.Set("Accept", "audio/*,*/*")
-	req.Header.Set("Referer", "https://podcastplayer.app/")
+	policy.Apply(req)
 
 	// Execute request
 	resp, err := d.client.Do(req)
@@ -98,16 +127,16 @@ func (d *Downloader) DownloadToTemp(ctx context.Context, url string, episodeID u
 			log.Printf("[ERROR] 403 Forbidden from %s - Headers: %v", url, resp.Header)
 			if strings.Contains(url, "buzzsprout") {
 				log.Printf("[WARN] Buzzsprout detected - known to have strict hotlink protection")
-				return nil, fmt.Errorf("audio download blocked by CDN (403 Forbidden): This podcast uses direct CDN URLs with hotlink protection. The audio may be accessible via web browsers but not server-side downloads")
+				return nil, &StatusError{StatusCode: resp.StatusCode, Message: "audio download blocked by CDN (403 Forbidden): This podcast uses direct CDN URLs with hotlink protection. The audio may be accessible via web browsers but not server-side downloads"}
 			}
-			return nil, fmt.Errorf("audio download blocked by CDN (403 Forbidden): The audio URL is protected and cannot be downloaded by the server. This may be due to IP blocking or hotlink protection")
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: "audio download blocked by CDN (403 Forbidden): The audio URL is protected and cannot be downloaded by the server. This may be due to IP blocking or hotlink protection"}
 		}
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("server returned status %d", resp.StatusCode)}
 	}
 
 	// Validate content type if required
 	contentType := resp.Header.Get("Content-Type")
-	if d.options.ValidateAudio && !isAudioContentType(contentType) {
+	if d.options.ValidateAudio && !isMediaContentType(contentType) {
 		return nil, fmt.Errorf("invalid content type: %s", contentType)
 	}
 
@@ -163,7 +192,7 @@ func (d *Downloader) createTempFile(episodeID uint, url string) (*os.File, error
 		if idx := strings.Index(lastPart, "?"); idx > 0 {
 			lastPart = lastPart[:idx]
 		}
-		if isValidAudioExtension(lastPart) {
+		if isValidMediaExtension(lastPart) {
 			ext = "." + lastPart
 		}
 	}
@@ -238,17 +267,18 @@ func CleanupOldTempFiles(tempDir string, maxAge time.Duration) error {
 	return nil
 }
 
-// isAudioContentType checks if content type is audio
-func isAudioContentType(contentType string) bool {
+// isMediaContentType checks if content type is audio or video
+func isMediaContentType(contentType string) bool {
 	contentType = strings.ToLower(contentType)
 	return strings.HasPrefix(contentType, "audio/") ||
+		strings.HasPrefix(contentType, "video/") ||
 		contentType == "application/octet-stream" // Some servers use this for audio
 }
 
-// isValidAudioExtension checks if extension is valid for audio files
-func isValidAudioExtension(ext string) bool {
+// isValidMediaExtension checks if extension is valid for audio or video files
+func isValidMediaExtension(ext string) bool {
 	ext = strings.ToLower(ext)
-	validExts := []string{"mp3", "m4a", "aac", "ogg", "wav", "flac", "opus", "webm"}
+	validExts := []string{"mp3", "m4a", "aac", "ogg", "wav", "flac", "opus", "webm", "mp4", "m4v", "mov"}
 	for _, valid := range validExts {
 		if ext == valid {
 			return true
@@ -276,17 +306,22 @@ func (pr *progressReader) Read(p []byte) (int, error) {
 	return n, err
 }
 
-// DownloadWithRetry attempts to download with retry logic for 403 errors
+// DownloadWithRetry downloads, retrying responses whose status the host's policy
+// marks as retryable (by default 403, 429 and 503)
 func (d *Downloader) DownloadWithRetry(ctx context.Context, url string, episodeID uint) (*DownloadResult, error) {
-	var lastErr error
-	maxRetries := 3
+	policy := d.policies.For(url)
+	attempts := 1 + policy.Retries()
 
-	for attempt := 0; attempt < maxRetries; attempt++ {
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
 		if attempt > 0 {
-			// Wait before retry with exponential backoff
-			waitTime := time.Duration(attempt) * 2 * time.Second
+			waitTime := time.Duration(attempt) * policy.RetryBackoff
 			log.Printf("[DEBUG] Retry attempt %d after %v for episode %d", attempt, waitTime, episodeID)
-			time.Sleep(waitTime)
+			select {
+			case <-time.After(waitTime):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
 		}
 
 		result, err := d.DownloadToTemp(ctx, url, episodeID)
@@ -296,11 +331,11 @@ func (d *Downloader) DownloadWithRetry(ctx context.Context, url string, episodeI
 
 		lastErr = err
 
-		// Only retry on 403 errors
-		if !strings.Contains(err.Error(), "status 403") {
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || !policy.ShouldRetry(statusErr.StatusCode) {
 			return nil, err
 		}
 	}
 
-	return nil, fmt.Errorf("download failed after %d attempts: %w", maxRetries, lastErr)
+	return nil, fmt.Errorf("download failed after %d attempts: %w", attempts, lastErr)
 }
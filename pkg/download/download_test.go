@@ -257,13 +257,18 @@ func TestDownloadWithRetry_Success(t *testing.T) {
 
 func TestDownloadWithRetry_403Failure(t *testing.T) {
 	// Create test server that always returns 403
+	var attempts int
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
 		w.WriteHeader(http.StatusForbidden)
 		_, _ = w.Write([]byte("Forbidden"))
 	}))
 	defer server.Close()
 
 	options := DefaultOptions()
+	base := DefaultBasePolicy()
+	base.RetryBackoff = time.Millisecond
+	options.Policies = NewPolicies(base)
 	downloader := NewDownloader(options)
 
 	ctx := context.Background()
@@ -273,11 +278,13 @@ func TestDownloadWithRetry_403Failure(t *testing.T) {
 		t.Fatal("Expected error after retries, got nil")
 	}
 
-	// The retry logic only retries 403 errors, and for 403 errors it returns
-	// the descriptive error message, not the "failed after X attempts" message
+	// The descriptive 403 message is preserved through the retry wrapper
 	if !strings.Contains(err.Error(), "403 Forbidden") {
 		t.Errorf("Expected 403 error message, got: %v", err.Error())
 	}
+	if attempts != 1+base.Retries() {
+		t.Errorf("Expected %d attempts, got %d", 1+base.Retries(), attempts)
+	}
 }
 
 func TestDownloadWithRetry_NonRetryableError(t *testing.T) {
@@ -304,7 +311,7 @@ func TestDownloadWithRetry_NonRetryableError(t *testing.T) {
 	}
 }
 
-func TestIsAudioContentType(t *testing.T) {
+func TestIsMediaContentType(t *testing.T) {
 	testCases := []struct {
 		contentType string
 		expected    bool
@@ -314,6 +321,7 @@ func TestIsAudioContentType(t *testing.T) {
 		{"audio/wav", true},
 		{"AUDIO/MPEG", true},               // Case insensitive
 		{"application/octet-stream", true}, // Special case for some servers
+		{"video/mp4", true},                // Audio track is used
 		{"text/html", false},
 		{"image/jpeg", false},
 		{"application/json", false},
@@ -321,14 +329,14 @@ func TestIsAudioContentType(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		result := isAudioContentType(tc.contentType)
+		result := isMediaContentType(tc.contentType)
 		if result != tc.expected {
-			t.Errorf("isAudioContentType(%q) = %v, expected %v", tc.contentType, result, tc.expected)
+			t.Errorf("isMediaContentType(%q) = %v, expected %v", tc.contentType, result, tc.expected)
 		}
 	}
 }
 
-func TestIsValidAudioExtension(t *testing.T) {
+func TestIsValidMediaExtension(t *testing.T) {
 	testCases := []struct {
 		ext      string
 		expected bool
@@ -340,15 +348,16 @@ func TestIsValidAudioExtension(t *testing.T) {
 		{"flac", true},
 		{"ogg", true},
 		{"opus", true},
+		{"mp4", true},
 		{"txt", false},
 		{"html", false},
 		{"", false},
 	}
 
 	for _, tc := range testCases {
-		result := isValidAudioExtension(tc.ext)
+		result := isValidMediaExtension(tc.ext)
 		if result != tc.expected {
-			t.Errorf("isValidAudioExtension(%q) = %v, expected %v", tc.ext, result, tc.expected)
+			t.Errorf("isValidMediaExtension(%q) = %v, expected %v", tc.ext, result, tc.expected)
 		}
 	}
 }
@@ -0,0 +1,123 @@
+package download
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MirrorRule points a host's enclosures at a mirror serving the same paths
+type MirrorRule struct {
+	Host   string `mapstructure:"host"`   // Matches the host and its subdomains
+	Mirror string `mapstructure:"mirror"` // Host, optionally with port, that replaces it
+}
+
+// redirectors are analytics prefixes that wrap the real enclosure URL, e.g.
+// https://dts.podtrac.com/redirect.mp3/traffic.libsyn.com/show/ep.mp3, mapped
+// to the number of path segments before the wrapped URL. A redirector that
+// blocks us says nothing about the origin behind it.
+var redirectors = map[string]int{
+	"dts.podtrac.com":      1, // redirect.mp3/
+	"www.podtrac.com":      2, // pts/redirect.mp3/
+	"chtbl.com":            2, // track/<id>/
+	"chrt.fm":              2, // track/<id>/
+	"pdst.fm":              1, // e/
+	"op3.dev":              1, // e/ or e,pg=<guid>/
+	"pfx.vpixl.com":        1, // <id>/
+	"pscrb.fm":             2, // rss/p/
+	"verifi.podscribe.com": 2, // rss/p/
+	"arttrk.com":           2, // p/<id>/
+}
+
+// IsForbidden reports whether a download failed because the host refused it
+func IsForbidden(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden
+}
+
+// FallbackURLs returns other URLs that may serve the same audio as rawURL,
+// most likely first: the https form of an http URL, the URL with analytics
+// redirect prefixes removed, and each of those on a configured mirror. rawURL
+// itself is not included.
+func FallbackURLs(rawURL string, mirrors []MirrorRule) []string {
+	var candidates []string
+	seen := map[string]bool{rawURL: true}
+	add := func(candidate string) {
+		if candidate != "" && !seen[candidate] {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	bases := []string{rawURL}
+	for inner := unwrapRedirect(rawURL); inner != ""; inner = unwrapRedirect(inner) {
+		bases = append(bases, inner)
+	}
+	for _, base := range bases {
+		add(base)
+		add(upgradeScheme(base))
+	}
+	for _, base := range bases {
+		for _, rule := range mirrors {
+			if mirrored := applyMirror(base, rule); mirrored != "" {
+				add(mirrored)
+				add(upgradeScheme(mirrored))
+			}
+		}
+	}
+	return candidates
+}
+
+// upgradeScheme returns the https form of an http URL, or "" for any other URL
+func upgradeScheme(rawURL string) string {
+	if rest, ok := strings.CutPrefix(rawURL, "http://"); ok {
+		return "https://" + rest
+	}
+	return ""
+}
+
+// unwrapRedirect returns the URL an analytics redirect wraps, or "" when
+// rawURL is not one. Wrapped URLs usually leave out the scheme; they get the
+// outer URL's.
+func unwrapRedirect(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	skip, ok := redirectors[strings.ToLower(parsed.Hostname())]
+	if !ok {
+		return ""
+	}
+	segments := strings.SplitN(strings.TrimPrefix(parsed.EscapedPath(), "/"), "/", skip+1)
+	if len(segments) <= skip || segments[skip] == "" {
+		return ""
+	}
+	inner := segments[skip]
+	if parsed.RawQuery != "" {
+		inner += "?" + parsed.RawQuery
+	}
+
+	for _, scheme := range []string{"https:/", "http:/"} {
+		if rest, ok := strings.CutPrefix(inner, scheme); ok {
+			return scheme + "/" + strings.TrimPrefix(rest, "/")
+		}
+	}
+	return parsed.Scheme + "://" + inner
+}
+
+// applyMirror moves rawURL to the rule's mirror host, or returns "" when the
+// rule doesn't match
+func applyMirror(rawURL string, rule MirrorRule) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || rule.Mirror == "" {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	ruleHost := strings.ToLower(strings.TrimPrefix(rule.Host, "."))
+	if ruleHost == "" || (host != ruleHost && !strings.HasSuffix(host, "."+ruleHost)) {
+		return ""
+	}
+	parsed.Host = rule.Mirror
+	return parsed.String()
+}
@@ -0,0 +1,63 @@
+package download
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestFallbackURLs(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		mirrors []MirrorRule
+		want    []string
+	}{
+		{
+			name: "http is upgraded",
+			url:  "http://cdn.example.com/ep.mp3",
+			want: []string{"https://cdn.example.com/ep.mp3"},
+		},
+		{
+			name: "https has nothing to try",
+			url:  "https://cdn.example.com/ep.mp3",
+		},
+		{
+			name: "analytics redirects are unwrapped",
+			url:  "https://dts.podtrac.com/redirect.mp3/chtbl.com/track/ABC12/traffic.libsyn.com/show/ep.mp3?dest-id=1",
+			want: []string{
+				"https://chtbl.com/track/ABC12/traffic.libsyn.com/show/ep.mp3?dest-id=1",
+				"https://traffic.libsyn.com/show/ep.mp3?dest-id=1",
+			},
+		},
+		{
+			name: "wrapped URLs keep their own scheme",
+			url:  "https://op3.dev/e/http://media.example.com/ep.mp3",
+			want: []string{"http://media.example.com/ep.mp3", "https://media.example.com/ep.mp3"},
+		},
+		{
+			name:    "mirrors match subdomains",
+			url:     "https://audio.blocked.example/show/ep.mp3",
+			mirrors: []MirrorRule{{Host: "blocked.example", Mirror: "mirror.example:8443"}, {Host: "other.example", Mirror: "unused.example"}},
+			want:    []string{"https://mirror.example:8443/show/ep.mp3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FallbackURLs(tt.url, tt.mirrors)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FallbackURLs(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsForbidden(t *testing.T) {
+	if !IsForbidden(fmt.Errorf("wrapped: %w", &StatusError{StatusCode: 403})) {
+		t.Error("Expected a wrapped 403 to be forbidden")
+	}
+	if IsForbidden(&StatusError{StatusCode: 404}) || IsForbidden(fmt.Errorf("timeout")) {
+		t.Error("Expected only 403 to be forbidden")
+	}
+}
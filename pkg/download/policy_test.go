@@ -0,0 +1,103 @@
+package download
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPolicies_ForMatchesMostSpecificHost(t *testing.T) {
+	retries := 5
+	policies := NewPolicies(DefaultBasePolicy(), DefaultHostPolicies(), []HostPolicy{
+		{Host: "example.com", UserAgent: "example-agent"},
+		{Host: "cdn.example.com", Headers: map[string]string{"X-Token": "abc"}, MaxRetries: &retries},
+	})
+
+	policy := policies.For("https://www.buzzsprout.com/123/episode.mp3")
+	if policy.Referer != "https://www.buzzsprout.com/" || policy.MaxConcurrency != 2 {
+		t.Errorf("Expected buzzsprout policy, got %+v", policy)
+	}
+
+	policy = policies.For("https://media.cdn.example.com/a.mp3")
+	if policy.Host != "cdn.example.com" {
+		t.Errorf("Expected most specific host, got %q", policy.Host)
+	}
+	if policy.Headers["X-Token"] != "abc" || policy.Retries() != 5 {
+		t.Errorf("Expected host headers and retries, got %+v", policy)
+	}
+	// Fields not set on the rule fall back to the base policy, not the parent host
+	if policy.UserAgent != BrowserUserAgent {
+		t.Errorf("Expected base user agent, got %q", policy.UserAgent)
+	}
+
+	policy = policies.For("https://notexample.com/a.mp3")
+	if policy.Host != "" {
+		t.Errorf("Expected no host match for lookalike domain, got %q", policy.Host)
+	}
+}
+
+func TestPolicies_ConfiguredRuleOverridesBuiltIn(t *testing.T) {
+	policies := NewPolicies(DefaultBasePolicy(), DefaultHostPolicies(), []HostPolicy{
+		{Host: "buzzsprout.com", UserAgent: "custom"},
+	})
+
+	policy := policies.For("https://buzzsprout.com/a.mp3")
+	if policy.UserAgent != "custom" {
+		t.Errorf("Expected configured user agent, got %q", policy.UserAgent)
+	}
+	if policy.MaxConcurrency != 0 {
+		t.Errorf("Expected configured rule to replace built-in entirely, got concurrency %d", policy.MaxConcurrency)
+	}
+}
+
+func TestPolicies_AcquireLimitsConcurrency(t *testing.T) {
+	policies := NewPolicies(DefaultBasePolicy(), []HostPolicy{{Host: "example.com", MaxConcurrency: 1}})
+	policy := policies.For("https://example.com/a.mp3")
+
+	release, err := policies.Acquire(context.Background(), policy)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := policies.Acquire(ctx, policy); err == nil {
+		t.Fatal("Expected second acquire to block until the context expired")
+	}
+
+	release()
+	next, err := policies.Acquire(context.Background(), policy)
+	if err != nil {
+		t.Fatalf("Acquire after release failed: %v", err)
+	}
+	next()
+}
+
+func TestDownloadToTemp_AppliesHostPolicyHeaders(t *testing.T) {
+	var userAgent, referer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		referer = r.Header.Get("Referer")
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer server.Close()
+
+	options := DefaultOptions()
+	options.TempDir = t.TempDir()
+	options.Policies = NewPolicies(DefaultBasePolicy(), []HostPolicy{
+		{Host: "127.0.0.1", UserAgent: "policy-agent", Referer: "https://referer.example/"},
+	})
+
+	result, err := NewDownloader(options).DownloadToTemp(context.Background(), server.URL, 1)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	defer CleanupTempFile(result.FilePath)
+
+	if userAgent != "policy-agent" || referer != "https://referer.example/" {
+		t.Errorf("Expected policy headers, got UA %q referer %q", userAgent, referer)
+	}
+}
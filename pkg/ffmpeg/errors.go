@@ -10,10 +10,12 @@ var (
 	ErrFFmpegNotFound        = errors.New("ffmpeg binary not found")
 	ErrFFprobeNotFound       = errors.New("ffprobe binary not found")
 	ErrInvalidAudioFile      = errors.New("invalid or unsupported audio file")
+	ErrNoAudioStream         = errors.New("file has no audio stream")
 	ErrAudioTooLong          = errors.New("audio file exceeds maximum duration")
 	ErrProcessingTimeout     = errors.New("audio processing timeout")
 	ErrInsufficientDiskSpace = errors.New("insufficient disk space for processing")
 	ErrTempFileCreation      = errors.New("failed to create temporary file")
+	ErrResourceLimit         = errors.New("process exceeded resource limit")
 )
 
 // ProcessingError represents an error during audio processing
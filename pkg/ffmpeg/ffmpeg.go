@@ -3,14 +3,13 @@ package ffmpeg
 import (
 	"bytes"
 	"context"
-	"encoding/binary"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -20,15 +19,21 @@ type FFmpeg struct {
 	ffmpegPath  string
 	ffprobePath string
 	timeout     time.Duration
+	limits      ResourceLimits
+	slots       chan struct{} // nil when concurrency is unlimited
 }
 
 // New creates a new FFmpeg instance
-func New(ffmpegPath, ffprobePath string, timeout time.Duration) *FFmpeg {
-	return &FFmpeg{
+func New(ffmpegPath, ffprobePath string, timeout time.Duration, opts ...Option) *FFmpeg {
+	f := &FFmpeg{
 		ffmpegPath:  ffmpegPath,
 		ffprobePath: ffprobePath,
 		timeout:     timeout,
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 // ValidateBinaries checks if ffmpeg and ffprobe are available
@@ -88,7 +93,7 @@ func (f *FFmpeg) GenerateWaveform(ctx context.Context, input string, options Pro
 	}
 
 	// Generate waveform peaks using FFmpeg
-	peaks, err := f.extractWaveformPeaks(ctx, inputFile, options.WaveformResolution)
+	peaks, stats, err := f.extractWaveformPeaks(ctx, inputFile, options.WaveformResolution, metadata, options.OnProgress)
 	if err != nil {
 		return nil, err
 	}
@@ -98,112 +103,78 @@ func (f *FFmpeg) GenerateWaveform(ctx context.Context, input string, options Pro
 		Duration:   metadata.Duration,
 		Resolution: len(peaks),
 		SampleRate: metadata.SampleRate,
+		Stats:      stats,
 	}, nil
 }
 
-// extractWaveformPeaks extracts waveform peak data using FFmpeg's showwavespic filter
-func (f *FFmpeg) extractWaveformPeaks(ctx context.Context, inputFile string, resolution int) ([]float32, error) {
-	// Create a temporary output file for the raw audio data
-	tempDir := filepath.Dir(inputFile)
-	rawFile, err := os.CreateTemp(tempDir, "waveform_*.raw")
-	if err != nil {
-		return nil, NewProcessingError("temp_file_creation", inputFile, err, "")
+// pcmSampleRate is the rate PCM is decoded at for full-resolution waveforms
+const pcmSampleRate = 44100
+
+// partialInterval is the minimum progress between OnProgress callbacks
+const partialInterval = 0.05
+
+// extractWaveformPeaks decodes the file to PCM on ffmpeg's stdout and computes
+// peaks as samples arrive. The metadata duration sizes the peak windows so the
+// result has about resolution peaks; when it is unknown, fixed windows are
+// downsampled and no progress is reported. Stats are computed over the final
+// peaks, with loudness taken from every decoded sample.
+func (f *FFmpeg) extractWaveformPeaks(ctx context.Context, inputFile string, resolution int, metadata *AudioMetadata, onProgress ProgressFunc) ([]float32, *WaveformStats, error) {
+	if resolution <= 0 {
+		resolution = DefaultProcessingOptions().WaveformResolution
+	}
+
+	expectedSamples := int64(metadata.Duration * pcmSampleRate)
+	windowSize := previewWindowSamples
+	if expectedSamples > 0 {
+		windowSize = int(expectedSamples / int64(resolution))
+		if windowSize < 1 {
+			windowSize = 1
+		}
+	}
+
+	windows := &windowPeaks{size: windowSize}
+	if onProgress != nil && expectedSamples > 0 {
+		var reported float64
+		windows.onWindow = func() {
+			fraction := float64(windows.samples) / float64(expectedSamples)
+			if fraction >= 1 || fraction-reported < partialInterval {
+				return
+			}
+			reported = fraction
+			partial := make([]float32, len(windows.peaks))
+			copy(partial, windows.peaks)
+			normalizePeaks(partial, windows.max)
+			onProgress(&WaveformData{
+				Peaks:      partial,
+				Duration:   metadata.Duration,
+				Resolution: len(partial),
+				SampleRate: metadata.SampleRate,
+			}, fraction)
+		}
 	}
-	rawPath := rawFile.Name()
-	rawFile.Close()
-	defer os.Remove(rawPath)
 
-	// Convert to raw PCM data for analysis
 	args := []string{
+		"-v", "error",
 		"-i", inputFile,
+		"-map", "0:a:0", // First audio stream; video episodes carry a video stream too
 		"-f", "f32le", // 32-bit float little-endian
 		"-ac", "1", // Convert to mono
-		"-ar", "44100", // Resample to 44.1kHz
-		"-y", // Overwrite output
-		rawPath,
+		"-ar", strconv.Itoa(pcmSampleRate),
+		"pipe:1",
 	}
 
 	cmd := exec.CommandContext(ctx, f.ffmpegPath, args...)
+	cmd.Stdout = windows
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
-		return nil, NewProcessingError("pcm_conversion", inputFile, err, stderr.String())
-	}
-
-	// Read and analyze the raw PCM data
-	return f.analyzePCMData(rawPath, resolution)
-}
-
-// analyzePCMData reads raw PCM data and generates peak values
-func (f *FFmpeg) analyzePCMData(rawPath string, resolution int) ([]float32, error) {
-	file, err := os.Open(rawPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	// Get file size to calculate total samples
-	stat, err := file.Stat()
-	if err != nil {
-		return nil, err
-	}
-
-	totalBytes := stat.Size()
-	totalSamples := totalBytes / 4 // 4 bytes per float32 sample
-	samplesPerPeak := totalSamples / int64(resolution)
-
-	if samplesPerPeak < 1 {
-		samplesPerPeak = 1
-	}
-
-	peaks := make([]float32, 0, resolution)
-	buffer := make([]byte, 4*samplesPerPeak) // Buffer for samples
-	var globalMaxPeak float32
-
-	// First pass: find the global maximum to normalize
-	tempPeaks := make([]float32, 0, resolution)
-	for i := 0; i < resolution; i++ {
-		// Read chunk of samples
-		n, err := file.Read(buffer)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		// Convert bytes to float32 samples and find peak
-		var maxPeak float32
-		for j := 0; j < n; j += 4 {
-			if j+4 <= len(buffer) {
-				// Convert little-endian bytes to float32
-				sample := bytesToFloat32(buffer[j : j+4])
-				if abs(sample) > abs(maxPeak) {
-					maxPeak = sample
-				}
-			}
-		}
-
-		peakValue := abs(maxPeak)
-		tempPeaks = append(tempPeaks, peakValue)
-		if peakValue > globalMaxPeak {
-			globalMaxPeak = peakValue
-		}
+	if err := f.run(ctx, cmd); err != nil {
+		return nil, nil, NewProcessingError("pcm_conversion", inputFile, err, stderr.String())
 	}
+	windows.flush()
 
-	// Second pass: normalize peaks to [0,1] range
-	if globalMaxPeak > 0 {
-		for _, peak := range tempPeaks {
-			normalizedPeak := peak / globalMaxPeak
-			peaks = append(peaks, normalizedPeak)
-		}
-	} else {
-		// All silence - just copy the zeros
-		peaks = tempPeaks
-	}
-
-	return peaks, nil
+	peaks := DownsamplePeaks(windows.peaks, resolution)
+	return peaks, windows.stats(peaks), nil
 }
 
 // downloadToTemp downloads a URL to a temporary file
@@ -263,17 +234,6 @@ func (f *FFmpeg) downloadToTemp(ctx context.Context, url, tempDir string) (strin
 
 // Helper functions
 
-// bytesToFloat32 converts 4 bytes to a float32 in little-endian format
-func bytesToFloat32(b []byte) float32 {
-	var f float32
-	buf := bytes.NewReader(b)
-	if err := binary.Read(buf, binary.LittleEndian, &f); err != nil {
-		// If we can't read the bytes, return 0 (silence)
-		return 0
-	}
-	return f
-}
-
 // abs returns the absolute value of a float32
 func abs(x float32) float32 {
 	if x < 0 {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -215,6 +216,20 @@ func TestValidateAudioFile(t *testing.T) {
 	}
 }
 
+func TestIsSupportedFormat(t *testing.T) {
+	for format, want := range map[string]bool{
+		"mp3":                     true,
+		"mov,mp4,m4a,3gp,3g2,mj2": true,
+		"matroska,webm":           true,
+		"avi":                     false,
+		"":                        false,
+	} {
+		if got := isSupportedFormat(format); got != want {
+			t.Errorf("isSupportedFormat(%q) = %v, want %v", format, got, want)
+		}
+	}
+}
+
 // Test waveform generation with 30-second test clip
 func TestGenerateWaveformWith30sClip(t *testing.T) {
 	ffmpeg := New("ffmpeg", "ffprobe", 30*time.Second)
@@ -302,3 +317,40 @@ func TestGetMetadataFileNotFound(t *testing.T) {
 		t.Errorf("Expected ProcessingError, got %T", err)
 	}
 }
+
+func TestExtractWaveformPeaksReportsProgress(t *testing.T) {
+	// Stand-in for ffmpeg that writes two seconds of silent f32le PCM to stdout
+	script := filepath.Join(t.TempDir(), "fake-ffmpeg")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nhead -c 352800 /dev/zero\n"), 0o755); err != nil {
+		t.Fatalf("Failed to write fake ffmpeg: %v", err)
+	}
+
+	f := New(script, "ffprobe", 30*time.Second)
+	metadata := &AudioMetadata{Duration: 2, SampleRate: 44100}
+
+	var calls int
+	var lastFraction float64
+	peaks, stats, err := f.extractWaveformPeaks(context.Background(), "input.mp3", 100, metadata, func(partial *WaveformData, fraction float64) {
+		calls++
+		if fraction <= lastFraction {
+			t.Errorf("Expected increasing progress, got %v after %v", fraction, lastFraction)
+		}
+		if partial.Duration != metadata.Duration {
+			t.Errorf("Expected partial duration %v, got %v", metadata.Duration, partial.Duration)
+		}
+		lastFraction = fraction
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(peaks) != 100 {
+		t.Errorf("Expected 100 peaks, got %d", len(peaks))
+	}
+	if stats == nil {
+		t.Error("Expected stats alongside the peaks")
+	}
+	if calls < 10 {
+		t.Errorf("Expected progress roughly every 5%%, got %d callbacks", calls)
+	}
+}
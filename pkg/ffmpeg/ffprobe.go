@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os/exec"
 	"strconv"
+	"strings"
 )
 
 // ffprobeOutput represents the JSON structure returned by ffprobe
@@ -40,10 +41,10 @@ func (f *FFmpeg) GetMetadata(ctx context.Context, filePath string) (*AudioMetada
 
 	cmd := exec.CommandContext(ctx, f.ffprobePath, args...)
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	cmd.Stdout = f.outputBuffer(&stdout)
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := f.run(ctx, cmd); err != nil {
 		return nil, NewProcessingError("metadata_extraction", filePath, err, stderr.String())
 	}
 
@@ -55,6 +56,32 @@ func (f *FFmpeg) GetMetadata(ctx context.Context, filePath string) (*AudioMetada
 	return f.parseMetadata(&output, filePath)
 }
 
+// Duration reads the container duration of a file in seconds, which is cheaper
+// than GetMetadata when nothing else is needed
+func (f *FFmpeg) Duration(ctx context.Context, filePath string) (float64, error) {
+	args := []string{
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		filePath,
+	}
+
+	cmd := exec.CommandContext(ctx, f.ffprobePath, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = f.outputBuffer(&stdout)
+	cmd.Stderr = &stderr
+
+	if err := f.run(ctx, cmd); err != nil {
+		return 0, NewProcessingError("duration_probe", filePath, err, stderr.String())
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(stdout.String()), 64)
+	if err != nil {
+		return 0, NewProcessingError("duration_parsing", filePath, err, "")
+	}
+	return duration, nil
+}
+
 // parseMetadata converts ffprobe output to AudioMetadata
 func (f *FFmpeg) parseMetadata(output *ffprobeOutput, filePath string) (*AudioMetadata, error) {
 	metadata := &AudioMetadata{}
@@ -125,7 +152,22 @@ func (f *FFmpeg) parseMetadata(output *ffprobeOutput, filePath string) (*AudioMe
 	return metadata, nil
 }
 
-// ValidateAudioFile checks if a file is a valid audio file that can be processed
+// supportedFormats are the containers ffmpeg is trusted to decode. Video
+// containers are included; only their first audio stream is read.
+var supportedFormats = map[string]bool{
+	"mp3":      true,
+	"mp4":      true, // ffprobe reports mp4 and m4a as "mov,mp4,m4a,3gp,3g2,mj2"
+	"m4a":      true,
+	"aac":      true,
+	"wav":      true,
+	"flac":     true,
+	"ogg":      true,
+	"matroska": true, // Also webm
+	"webm":     true,
+}
+
+// ValidateAudioFile checks if a file is a valid audio file that can be
+// processed, or a video file with an audio track
 func (f *FFmpeg) ValidateAudioFile(ctx context.Context, filePath string) error {
 	metadata, err := f.GetMetadata(ctx, filePath)
 	if err != nil {
@@ -137,19 +179,24 @@ func (f *FFmpeg) ValidateAudioFile(ctx context.Context, filePath string) error {
 		return ErrInvalidAudioFile
 	}
 
-	// Check supported formats
-	supportedFormats := map[string]bool{
-		"mp3":  true,
-		"m4a":  true,
-		"aac":  true,
-		"wav":  true,
-		"flac": true,
-		"ogg":  true,
+	if !isSupportedFormat(metadata.Format) {
+		return fmt.Errorf("unsupported audio format: %s", metadata.Format)
 	}
 
-	if !supportedFormats[metadata.Format] {
-		return fmt.Errorf("unsupported audio format: %s", metadata.Format)
+	if metadata.Codec == "" {
+		return ErrNoAudioStream
 	}
 
 	return nil
 }
+
+// isSupportedFormat reports whether an ffprobe format name is supported.
+// ffprobe names a format by every alias it answers to, e.g. "matroska,webm".
+func isSupportedFormat(format string) bool {
+	for _, name := range strings.Split(format, ",") {
+		if supportedFormats[name] {
+			return true
+		}
+	}
+	return false
+}
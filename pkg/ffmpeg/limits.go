@@ -0,0 +1,121 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/killallgit/player-api/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ResourceLimits bounds what a single ffmpeg or ffprobe process may consume.
+// Zero values disable the corresponding limit.
+type ResourceLimits struct {
+	CPUTime        time.Duration // CPU time before the process is killed
+	Nice           int           // Scheduling niceness applied to each process
+	MaxOutputBytes int64         // Largest file or stdout a process may write
+	MaxConcurrent  int           // Processes allowed to run at once per FFmpeg instance
+}
+
+// Option configures an FFmpeg instance
+type Option func(*FFmpeg)
+
+// WithLimits applies resource limits to every process the instance spawns
+func WithLimits(limits ResourceLimits) Option {
+	return func(f *FFmpeg) {
+		f.limits = limits
+		if limits.MaxConcurrent > 0 {
+			f.slots = make(chan struct{}, limits.MaxConcurrent)
+		}
+	}
+}
+
+// Limits returns the resource limits the instance was configured with
+func (f *FFmpeg) Limits() ResourceLimits {
+	return f.limits
+}
+
+// Run runs ffmpeg with args under the instance's limits, for callers that
+// build their own command line. It returns ffmpeg's stderr, where filters such
+// as volumedetect print their results and failures are explained.
+func (f *FFmpeg) Run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, f.ffmpegPath, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = f.outputBuffer(&stderr)
+
+	err := f.run(ctx, cmd)
+	return stderr.String(), err
+}
+
+// run starts cmd once a process slot is free, applies the configured limits
+// and waits for it. Exceeding a limit is reported as ErrResourceLimit.
+func (f *FFmpeg) run(ctx context.Context, cmd *exec.Cmd) (err error) {
+	_, span := tracing.Start(ctx, "exec "+filepath.Base(cmd.Path),
+		attribute.String("process.executable.name", filepath.Base(cmd.Path)))
+	defer func() { tracing.End(span, err) }()
+
+	if f.slots != nil {
+		waitStart := time.Now()
+		select {
+		case f.slots <- struct{}{}:
+			defer func() { <-f.slots }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		// Time queued behind other processes is often what makes a request slow
+		span.SetAttributes(attribute.Int64("ffmpeg.slot_wait_ms", time.Since(waitStart).Milliseconds()))
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Limits are applied right after start. ffmpeg and ffprobe don't fork, and
+	// the window before the limits take effect is far shorter than any limit
+	// worth configuring
+	if err := applyLimits(cmd.Process.Pid, f.limits); err != nil {
+		log.Printf("[WARN] Failed to apply resource limits to pid %d: %v", cmd.Process.Pid, err)
+	}
+
+	err = cmd.Wait()
+	if cmd.ProcessState != nil {
+		span.SetAttributes(attribute.Int("process.exit.code", cmd.ProcessState.ExitCode()))
+	}
+	if err == nil {
+		return nil
+	}
+	// The exit status hides the copy error once the writer refuses output
+	if out, ok := cmd.Stdout.(*limitedBuffer); ok && out.err != nil {
+		return out.err
+	}
+	if reason := limitExceeded(cmd.ProcessState, f.limits); reason != "" {
+		return fmt.Errorf("%w: %s", ErrResourceLimit, reason)
+	}
+	return err
+}
+
+// outputBuffer returns a writer for captured stdout that refuses to grow past
+// the configured output limit
+func (f *FFmpeg) outputBuffer(buf *bytes.Buffer) *limitedBuffer {
+	return &limitedBuffer{buf: buf, limit: f.limits.MaxOutputBytes}
+}
+
+// limitedBuffer is a bytes.Buffer wrapper that fails once limit bytes are written
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int64
+	err   error
+}
+
+func (w *limitedBuffer) Write(p []byte) (int, error) {
+	if w.limit > 0 && int64(w.buf.Len()+len(p)) > w.limit {
+		w.err = fmt.Errorf("%w: output exceeds %d bytes", ErrResourceLimit, w.limit)
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
@@ -0,0 +1,67 @@
+//go:build linux
+
+package ffmpeg
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyLimits sets niceness, CPU time and file size limits on a running process
+func applyLimits(pid int, limits ResourceLimits) error {
+	var errs []error
+
+	if limits.Nice != 0 {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, pid, limits.Nice); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if limits.CPUTime > 0 {
+		// The soft limit delivers SIGXCPU; the hard limit one second later is a SIGKILL backstop
+		seconds := uint64(limits.CPUTime.Seconds())
+		if seconds == 0 {
+			seconds = 1
+		}
+		rlim := &unix.Rlimit{Cur: seconds, Max: seconds + 1}
+		if err := unix.Prlimit(pid, unix.RLIMIT_CPU, rlim, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if limits.MaxOutputBytes > 0 {
+		size := uint64(limits.MaxOutputBytes)
+		rlim := &unix.Rlimit{Cur: size, Max: size}
+		if err := unix.Prlimit(pid, unix.RLIMIT_FSIZE, rlim, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// limitExceeded reports which limit, if any, terminated the process
+func limitExceeded(state *os.ProcessState, limits ResourceLimits) string {
+	if state == nil {
+		return ""
+	}
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+
+	switch status.Signal() {
+	case syscall.SIGXCPU:
+		return "cpu time limit exceeded"
+	case syscall.SIGXFSZ:
+		return "output size limit exceeded"
+	case syscall.SIGKILL:
+		if limits.CPUTime > 0 && state.UserTime()+state.SystemTime() >= limits.CPUTime {
+			return "cpu time limit exceeded"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+//go:build linux
+
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunCPUTimeLimit(t *testing.T) {
+	f := New("ffmpeg", "ffprobe", 30*time.Second, WithLimits(ResourceLimits{CPUTime: time.Second}))
+
+	err := f.run(context.Background(), exec.Command("sh", "-c", "while :; do :; done"))
+	if !errors.Is(err, ErrResourceLimit) {
+		t.Fatalf("Expected ErrResourceLimit, got %v", err)
+	}
+}
+
+func TestRunOutputFileLimit(t *testing.T) {
+	f := New("ffmpeg", "ffprobe", 30*time.Second, WithLimits(ResourceLimits{MaxOutputBytes: 4096}))
+
+	// Limits apply after start, so write from the limited shell itself rather than a child forked earlier
+	out := filepath.Join(t.TempDir(), "out.raw")
+	err := f.run(context.Background(), exec.Command("sh", "-c", "sleep 0.2; exec head -c 65536 /dev/zero > "+out))
+	if !errors.Is(err, ErrResourceLimit) {
+		t.Fatalf("Expected ErrResourceLimit, got %v", err)
+	}
+}
+
+func TestRunStdoutLimit(t *testing.T) {
+	f := New("ffmpeg", "ffprobe", 30*time.Second, WithLimits(ResourceLimits{MaxOutputBytes: 16}))
+
+	var stdout bytes.Buffer
+	cmd := exec.Command("sh", "-c", "head -c 65536 /dev/zero")
+	cmd.Stdout = f.outputBuffer(&stdout)
+	err := f.run(context.Background(), cmd)
+	if !errors.Is(err, ErrResourceLimit) {
+		t.Fatalf("Expected ErrResourceLimit, got %v", err)
+	}
+}
+
+func TestRunWithinLimits(t *testing.T) {
+	f := New("ffmpeg", "ffprobe", 30*time.Second, WithLimits(ResourceLimits{
+		CPUTime:        5 * time.Second,
+		Nice:           5,
+		MaxOutputBytes: 1024,
+	}))
+
+	var stdout bytes.Buffer
+	cmd := exec.Command("sh", "-c", "echo ok")
+	cmd.Stdout = f.outputBuffer(&stdout)
+	if err := f.run(context.Background(), cmd); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stdout.String() != "ok\n" {
+		t.Errorf("Expected stdout 'ok', got %q", stdout.String())
+	}
+}
+
+func TestRunConcurrencyCap(t *testing.T) {
+	f := New("ffmpeg", "ffprobe", 30*time.Second, WithLimits(ResourceLimits{MaxConcurrent: 2}))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f.run(context.Background(), exec.Command("sleep", "0.3")); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Four 300ms processes two at a time need at least two rounds
+	if elapsed := time.Since(start); elapsed < 600*time.Millisecond {
+		t.Errorf("Expected processes to be serialized into two rounds, finished in %v", elapsed)
+	}
+}
+
+func TestRunWaitingForSlotHonorsContext(t *testing.T) {
+	f := New("ffmpeg", "ffprobe", 30*time.Second, WithLimits(ResourceLimits{MaxConcurrent: 1}))
+	f.slots <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := f.run(ctx, exec.Command("true"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context deadline error, got %v", err)
+	}
+}
+
+func TestRunAppliesLimitsToCallerCommands(t *testing.T) {
+	// sh stands in for ffmpeg so the test doesn't need the binary
+	f := New("sh", "sh", 30*time.Second, WithLimits(ResourceLimits{CPUTime: time.Second}))
+
+	stderr, err := f.Run(context.Background(), "-c", "echo mean_volume: -20.0 dB >&2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stderr != "mean_volume: -20.0 dB\n" {
+		t.Errorf("Expected stderr to be returned, got %q", stderr)
+	}
+
+	if _, err := f.Run(context.Background(), "-c", "while :; do :; done"); !errors.Is(err, ErrResourceLimit) {
+		t.Fatalf("Expected ErrResourceLimit, got %v", err)
+	}
+}
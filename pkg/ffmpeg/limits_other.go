@@ -0,0 +1,15 @@
+//go:build !linux
+
+package ffmpeg
+
+import "os"
+
+// applyLimits is a no-op where per-process rlimits cannot be set; only the
+// concurrency cap and stdout limit are enforced
+func applyLimits(pid int, limits ResourceLimits) error {
+	return nil
+}
+
+func limitExceeded(state *os.ProcessState, limits ResourceLimits) string {
+	return ""
+}
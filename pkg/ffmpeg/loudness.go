@@ -0,0 +1,104 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// loudnessFloor is what levels of digital silence, which loudnorm reports as
+// -inf, are raised to: the EBU R128 absolute gate
+const loudnessFloor = -70.0
+
+// LoudnessStats is an EBU R128 measurement of a whole file
+type LoudnessStats struct {
+	IntegratedLUFS float64 `json:"integrated_lufs"` // Integrated loudness
+	TruePeakDBTP   float64 `json:"true_peak_dbtp"`  // Maximum true peak
+	RangeLU        float64 `json:"range_lu"`        // Loudness range (LRA)
+	ThresholdLUFS  float64 `json:"threshold_lufs"`  // Relative gate the integrated loudness was measured over
+}
+
+// loudnormOutput is the summary the loudnorm filter prints with
+// print_format=json; its numbers are strings
+type loudnormOutput struct {
+	InputI      string `json:"input_i"`
+	InputTP     string `json:"input_tp"`
+	InputLRA    string `json:"input_lra"`
+	InputThresh string `json:"input_thresh"`
+}
+
+// MeasureLoudness runs loudnorm's analysis pass over a file, decoding it
+// without writing any output
+func (f *FFmpeg) MeasureLoudness(ctx context.Context, filePath string) (*LoudnessStats, error) {
+	args := []string{
+		"-hide_banner",
+		"-nostats",
+		"-i", filePath,
+		"-vn",
+		"-af", "loudnorm=print_format=json",
+		"-f", "null",
+		"-",
+	}
+
+	cmd := exec.CommandContext(ctx, f.ffmpegPath, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = f.outputBuffer(&stderr)
+
+	if err := f.run(ctx, cmd); err != nil {
+		return nil, NewProcessingError("loudness_measurement", filePath, err, lastLines(stderr.String(), 5))
+	}
+
+	stats, err := parseLoudnorm(stderr.String())
+	if err != nil {
+		return nil, NewProcessingError("loudness_parsing", filePath, err, "")
+	}
+	return stats, nil
+}
+
+// parseLoudnorm reads the JSON summary loudnorm prints last on stderr
+func parseLoudnorm(stderr string) (*LoudnessStats, error) {
+	start := strings.LastIndex(stderr, "{")
+	end := strings.LastIndex(stderr, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("no loudnorm summary in output")
+	}
+
+	var output loudnormOutput
+	if err := json.Unmarshal([]byte(stderr[start:end+1]), &output); err != nil {
+		return nil, err
+	}
+
+	var stats LoudnessStats
+	for _, field := range []struct {
+		value string
+		dst   *float64
+		floor float64
+	}{
+		{output.InputI, &stats.IntegratedLUFS, loudnessFloor},
+		{output.InputTP, &stats.TruePeakDBTP, loudnessFloor},
+		{output.InputLRA, &stats.RangeLU, 0},
+		{output.InputThresh, &stats.ThresholdLUFS, loudnessFloor},
+	} {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field.value), 64)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(v) || v < field.floor {
+			v = field.floor
+		}
+		*field.dst = v
+	}
+	return &stats, nil
+}
+
+// lastLines returns the last n lines of s, which for ffmpeg's stderr hold the
+// error rather than the banner
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.Join(lines[max(len(lines)-n, 0):], "\n")
+}
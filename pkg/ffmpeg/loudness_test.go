@@ -0,0 +1,45 @@
+package ffmpeg
+
+import "testing"
+
+const loudnormStderr = `Input #0, mp3, from 'episode.mp3':
+  Duration: 00:42:10.03, start: 0.025057, bitrate: 128 kb/s
+[Parsed_loudnorm_0 @ 0x600000b2c000] 
+{
+	"input_i" : "-18.42",
+	"input_tp" : "-0.31",
+	"input_lra" : "6.70",
+	"input_thresh" : "-28.61",
+	"output_i" : "-24.02",
+	"output_tp" : "-2.00",
+	"output_lra" : "5.90",
+	"output_thresh" : "-34.16",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.02"
+}
+`
+
+func TestParseLoudnorm(t *testing.T) {
+	stats, err := parseLoudnorm(loudnormStderr)
+	if err != nil {
+		t.Fatalf("parseLoudnorm: %v", err)
+	}
+	want := LoudnessStats{IntegratedLUFS: -18.42, TruePeakDBTP: -0.31, RangeLU: 6.7, ThresholdLUFS: -28.61}
+	if *stats != want {
+		t.Errorf("Expected %+v, got %+v", want, *stats)
+	}
+}
+
+func TestParseLoudnormSilence(t *testing.T) {
+	stats, err := parseLoudnorm(`{"input_i": "-inf", "input_tp": "-inf", "input_lra": "0.00", "input_thresh": "-inf"}`)
+	if err != nil {
+		t.Fatalf("parseLoudnorm: %v", err)
+	}
+	if stats.IntegratedLUFS != loudnessFloor || stats.TruePeakDBTP != loudnessFloor {
+		t.Errorf("Expected silence raised to %v, got %+v", loudnessFloor, *stats)
+	}
+
+	if _, err := parseLoudnorm("Invalid data found when processing input"); err == nil {
+		t.Error("Expected an error without a summary")
+	}
+}
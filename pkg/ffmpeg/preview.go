@@ -0,0 +1,170 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os/exec"
+)
+
+const (
+	// previewSampleRate is low enough to decode quickly while still showing speech and music envelopes
+	previewSampleRate = 8000
+
+	// previewWindowSamples groups decoded samples into 100ms windows, bounding memory
+	// to ~72k floats for a two hour episode regardless of the requested resolution
+	previewWindowSamples = previewSampleRate / 10
+
+	// DefaultPreviewResolution is the number of peaks in a preview waveform
+	DefaultPreviewResolution = 200
+)
+
+// GeneratePreviewWaveform decodes audio from r as it arrives and returns a coarse
+// waveform. Nothing is written to disk, so a streamed HTTP body can be passed
+// directly and peaks are computed while the download is still in progress.
+func (f *FFmpeg) GeneratePreviewWaveform(ctx context.Context, r io.Reader, resolution int) (*WaveformData, error) {
+	if resolution <= 0 {
+		resolution = DefaultPreviewResolution
+	}
+
+	args := []string{
+		"-v", "error",
+		"-i", "pipe:0",
+		"-map", "0:a:0",
+		"-f", "f32le",
+		"-ac", "1",
+		"-ar", fmt.Sprint(previewSampleRate),
+		"pipe:1",
+	}
+
+	cmd := exec.CommandContext(ctx, f.ffmpegPath, args...)
+	cmd.Stdin = r
+	windows := &windowPeaks{size: previewWindowSamples}
+	cmd.Stdout = windows
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := f.run(ctx, cmd); err != nil {
+		return nil, NewProcessingError("preview_decode", "pipe:0", err, stderr.String())
+	}
+	if windows.samples == 0 {
+		return nil, NewProcessingError("preview_decode", "pipe:0", ErrInvalidAudioFile, stderr.String())
+	}
+	windows.flush()
+
+	peaks := DownsamplePeaks(windows.peaks, resolution)
+	return &WaveformData{
+		Peaks:      peaks,
+		Duration:   float64(windows.samples) / previewSampleRate,
+		Resolution: len(peaks),
+		SampleRate: previewSampleRate,
+		Stats:      windows.stats(peaks),
+	}, nil
+}
+
+// DownsamplePeaks reduces peaks to at most resolution values by taking the
+// maximum of each bucket, then normalizes the result to [0,1]
+func DownsamplePeaks(peaks []float32, resolution int) []float32 {
+	if resolution <= 0 || len(peaks) == 0 {
+		return []float32{}
+	}
+	if resolution > len(peaks) {
+		resolution = len(peaks)
+	}
+
+	out := make([]float32, resolution)
+	var globalMax float32
+	for i := range out {
+		start := i * len(peaks) / resolution
+		end := (i + 1) * len(peaks) / resolution
+		for _, p := range peaks[start:end] {
+			if p > out[i] {
+				out[i] = p
+			}
+		}
+		if out[i] > globalMax {
+			globalMax = out[i]
+		}
+	}
+
+	normalizePeaks(out, globalMax)
+	return out
+}
+
+// normalizePeaks scales peaks in place so max maps to 1
+func normalizePeaks(peaks []float32, max float32) {
+	if max <= 0 {
+		return
+	}
+	for i := range peaks {
+		peaks[i] /= max
+	}
+}
+
+// windowPeaks is an io.Writer that consumes f32le PCM and keeps the absolute
+// peak of each window of size samples, along with the sum of squares of all
+// samples for a loudness estimate. onWindow, if set, is called after each
+// completed window.
+type windowPeaks struct {
+	size       int
+	onWindow   func()
+	peaks      []float32
+	max        float32
+	current    float32
+	count      int
+	samples    int64
+	sumSquares float64
+	partial    []byte // trailing bytes of a sample split across writes
+}
+
+func (w *windowPeaks) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(w.partial) > 0 {
+		need := 4 - len(w.partial)
+		if len(p) < need {
+			w.partial = append(w.partial, p...)
+			return n, nil
+		}
+		w.add(append(w.partial, p[:need]...))
+		w.partial = w.partial[:0]
+		p = p[need:]
+	}
+	for len(p) >= 4 {
+		w.add(p[:4])
+		p = p[4:]
+	}
+	w.partial = append(w.partial, p...)
+	return n, nil
+}
+
+func (w *windowPeaks) add(b []byte) {
+	sample := abs(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	w.sumSquares += float64(sample) * float64(sample)
+	if sample > w.current {
+		w.current = sample
+	}
+	w.count++
+	w.samples++
+	if w.count >= w.size {
+		w.flush()
+		if w.onWindow != nil {
+			w.onWindow()
+		}
+	}
+}
+
+// flush closes the current window, if it has any samples
+func (w *windowPeaks) flush() {
+	if w.count == 0 {
+		return
+	}
+	w.peaks = append(w.peaks, w.current)
+	if w.current > w.max {
+		w.max = w.current
+	}
+	w.current = 0
+	w.count = 0
+}
@@ -0,0 +1,73 @@
+package ffmpeg
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func encodeSamples(samples ...float32) []byte {
+	buf := make([]byte, 4*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(s))
+	}
+	return buf
+}
+
+func TestWindowPeaksSplitWrites(t *testing.T) {
+	samples := make([]float32, previewWindowSamples*2+10)
+	samples[5] = -0.8                     // first window, negative peak
+	samples[previewWindowSamples+3] = 0.4 // second window
+	samples[len(samples)-1] = 0.2         // partial trailing window
+
+	data := encodeSamples(samples...)
+	w := &windowPeaks{size: previewWindowSamples}
+	// Write in odd-sized pieces so samples straddle write boundaries
+	for len(data) > 0 {
+		n := 7
+		if n > len(data) {
+			n = len(data)
+		}
+		if _, err := w.Write(data[:n]); err != nil {
+			t.Fatalf("Unexpected write error: %v", err)
+		}
+		data = data[n:]
+	}
+	w.flush()
+
+	if w.samples != int64(len(samples)) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), w.samples)
+	}
+	expected := []float32{0.8, 0.4, 0.2}
+	if len(w.peaks) != len(expected) {
+		t.Fatalf("Expected %d windows, got %d", len(expected), len(w.peaks))
+	}
+	for i, peak := range expected {
+		if math.Abs(float64(w.peaks[i]-peak)) > 1e-6 {
+			t.Errorf("Window %d: expected peak %v, got %v", i, peak, w.peaks[i])
+		}
+	}
+}
+
+func TestDownsamplePeaks(t *testing.T) {
+	peaks := []float32{0.1, 0.5, 0.2, 0.25, 0.05, 0.1}
+
+	got := DownsamplePeaks(peaks, 3)
+	expected := []float32{1, 0.5, 0.2}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d peaks, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if math.Abs(float64(got[i]-expected[i])) > 1e-6 {
+			t.Errorf("Peak %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+
+	// Asking for more points than available returns one per input
+	if got := DownsamplePeaks(peaks, 100); len(got) != len(peaks) {
+		t.Errorf("Expected %d peaks, got %d", len(peaks), len(got))
+	}
+	if got := DownsamplePeaks(nil, 10); len(got) != 0 {
+		t.Errorf("Expected no peaks for empty input, got %d", len(got))
+	}
+}
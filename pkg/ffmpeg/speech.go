@@ -0,0 +1,53 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+const (
+	// SpeechSampleRate is the rate whisper expects its input at
+	SpeechSampleRate = 16000
+
+	// wavHeaderSize is the size of the canonical header ffmpeg writes for PCM WAV
+	wavHeaderSize = 44
+)
+
+// ExtractSpeech decodes duration seconds of audio from r, starting offset
+// seconds in, to a 16kHz mono 16-bit WAV at outputPath. r may hold only the
+// beginning of a file; decoding stops where the data does. It returns the
+// seconds of audio written, which is less than duration when r runs out.
+func (f *FFmpeg) ExtractSpeech(ctx context.Context, r io.Reader, offset, duration float64, outputPath string) (float64, error) {
+	args := []string{
+		"-v", "error",
+		"-i", "pipe:0",
+		"-ss", strconv.FormatFloat(offset, 'f', 3, 64),
+		"-t", strconv.FormatFloat(duration, 'f', 3, 64),
+		"-vn",
+		"-ac", "1",
+		"-ar", strconv.Itoa(SpeechSampleRate),
+		"-c:a", "pcm_s16le",
+		"-y", outputPath,
+	}
+
+	cmd := exec.CommandContext(ctx, f.ffmpegPath, args...)
+	cmd.Stdin = r
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := f.run(ctx, cmd); err != nil {
+		return 0, NewProcessingError("speech_decode", "pipe:0", err, stderr.String())
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return 0, fmt.Errorf("reading decoded speech: %w", err)
+	}
+	samples := max(info.Size()-wavHeaderSize, 0) / 2
+	return float64(samples) / SpeechSampleRate, nil
+}
@@ -0,0 +1,61 @@
+package ffmpeg
+
+import (
+	"math"
+	"sort"
+)
+
+// silenceFloorDB is the level reported for digital silence, and the floor the
+// quiet end of the dynamic range is clamped to so near-silent gaps don't
+// produce unbounded ranges
+const silenceFloorDB = -60.0
+
+// PeakStats summarizes normalized peaks so clients can scale a rendering
+// without scanning every point. LoudnessDB is left at zero; it needs the
+// decoded samples rather than peaks and is filled in by the decoder.
+func PeakStats(peaks []float32) WaveformStats {
+	if len(peaks) == 0 {
+		return WaveformStats{}
+	}
+
+	sorted := make([]float32, len(peaks))
+	copy(sorted, peaks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	p95 := percentile(sorted, 0.95)
+	p10 := percentile(sorted, 0.10)
+	return WaveformStats{
+		P95Peak:        p95,
+		MedianPeak:     percentile(sorted, 0.50),
+		DynamicRangeDB: round1(levelDB(float64(p95)) - levelDB(float64(p10))),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted, p in [0,1]
+func percentile(sorted []float32, p float64) float32 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// levelDB converts a linear amplitude to decibels, clamped to silenceFloorDB
+func levelDB(amplitude float64) float64 {
+	if amplitude <= 0 {
+		return silenceFloorDB
+	}
+	return max(20*math.Log10(amplitude), silenceFloorDB)
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// stats returns PeakStats for peaks with the RMS level of every sample the
+// writer has seen as the loudness estimate
+func (w *windowPeaks) stats(peaks []float32) *WaveformStats {
+	stats := PeakStats(peaks)
+	stats.LoudnessDB = silenceFloorDB
+	if w.samples > 0 {
+		stats.LoudnessDB = round1(levelDB(math.Sqrt(w.sumSquares / float64(w.samples))))
+	}
+	return &stats
+}
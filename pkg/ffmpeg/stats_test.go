@@ -0,0 +1,74 @@
+package ffmpeg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPeakStats(t *testing.T) {
+	peaks := make([]float32, 100)
+	for i := range peaks {
+		peaks[i] = float32(i+1) / 100 // 0.01 .. 1.00
+	}
+
+	stats := PeakStats(peaks)
+	if stats.P95Peak != 0.95 {
+		t.Errorf("Expected p95 0.95, got %v", stats.P95Peak)
+	}
+	if stats.MedianPeak != 0.5 {
+		t.Errorf("Expected median 0.5, got %v", stats.MedianPeak)
+	}
+	// 20*log10(0.95/0.10)
+	if stats.DynamicRangeDB != 19.6 {
+		t.Errorf("Expected dynamic range 19.6 dB, got %v", stats.DynamicRangeDB)
+	}
+	if peaks[0] != 0.01 {
+		t.Error("PeakStats must not reorder the caller's peaks")
+	}
+}
+
+func TestPeakStatsClampsSilence(t *testing.T) {
+	peaks := make([]float32, 20)
+	peaks[19] = 1 // one loud peak in near silence
+
+	stats := PeakStats(peaks)
+	if stats.DynamicRangeDB != 0 {
+		t.Errorf("Expected no range when p95 and p10 are both silent, got %v", stats.DynamicRangeDB)
+	}
+
+	peaks = []float32{0, 0, 1, 1, 1, 1, 1, 1, 1, 1}
+	if got := PeakStats(peaks).DynamicRangeDB; got != -silenceFloorDB {
+		t.Errorf("Expected range clamped to %v dB, got %v", -silenceFloorDB, got)
+	}
+
+	if stats := PeakStats(nil); stats != (WaveformStats{}) {
+		t.Errorf("Expected zero stats for no peaks, got %+v", stats)
+	}
+}
+
+func TestWindowPeaksLoudness(t *testing.T) {
+	// A full-scale square wave has an RMS of 1, or 0 dBFS; halving it is -6 dB
+	samples := make([]float32, previewWindowSamples)
+	for i := range samples {
+		samples[i] = 0.5
+		if i%2 == 1 {
+			samples[i] = -0.5
+		}
+	}
+
+	w := &windowPeaks{size: previewWindowSamples}
+	if _, err := w.Write(encodeSamples(samples...)); err != nil {
+		t.Fatalf("Unexpected write error: %v", err)
+	}
+	w.flush()
+
+	stats := w.stats(DownsamplePeaks(w.peaks, 1))
+	want := math.Round(200*math.Log10(0.5)) / 10
+	if stats.LoudnessDB != want {
+		t.Errorf("Expected loudness %v dBFS, got %v", want, stats.LoudnessDB)
+	}
+
+	if got := (&windowPeaks{}).stats(nil).LoudnessDB; got != silenceFloorDB {
+		t.Errorf("Expected silence floor for no samples, got %v", got)
+	}
+}
@@ -19,17 +19,33 @@ type AudioMetadata struct {
 
 // WaveformData represents audio waveform peak data
 type WaveformData struct {
-	Peaks      []float32 `json:"peaks"`       // Peak values (0.0 - 1.0)
-	Duration   float64   `json:"duration"`    // Duration in seconds
-	Resolution int       `json:"resolution"`  // Number of peaks
-	SampleRate int       `json:"sample_rate"` // Original sample rate
+	Peaks      []float32      `json:"peaks"`           // Peak values (0.0 - 1.0)
+	Duration   float64        `json:"duration"`        // Duration in seconds
+	Resolution int            `json:"resolution"`      // Number of peaks
+	SampleRate int            `json:"sample_rate"`     // Original sample rate
+	Stats      *WaveformStats `json:"stats,omitempty"` // Summary of the peaks and decoded level
 }
 
+// WaveformStats summarizes a waveform for rendering. Peak values are on the
+// same normalized [0,1] scale as Peaks.
+type WaveformStats struct {
+	P95Peak        float32 `json:"p95_peak"`         // 95th percentile peak; a robust ceiling to scale to
+	MedianPeak     float32 `json:"median_peak"`      // Typical peak height
+	DynamicRangeDB float64 `json:"dynamic_range_db"` // Spread between loud (p95) and quiet (p10) peaks
+	LoudnessDB     float64 `json:"loudness_db"`      // RMS level of the decoded audio in dBFS
+}
+
+// ProgressFunc receives the waveform computed so far, with peaks normalized to
+// [0,1] and the full expected duration, and the fraction of the audio decoded.
+// It is called from the decoding goroutine.
+type ProgressFunc func(partial *WaveformData, fraction float64)
+
 // ProcessingOptions defines options for audio processing
 type ProcessingOptions struct {
 	WaveformResolution int           `json:"waveform_resolution"` // Number of peaks to generate
 	MaxDuration        time.Duration `json:"max_duration"`        // Maximum duration to process
 	TempDir            string        `json:"temp_dir"`            // Directory for temporary files
+	OnProgress         ProgressFunc  `json:"-"`                   // Optional partial peak callback
 }
 
 // DefaultProcessingOptions returns sensible defaults for audio processing
@@ -0,0 +1,112 @@
+// Package logging filters the standard logger by level. Log lines carry
+// their level as a tag, e.g. log.Printf("[WARN] ..."); untagged lines count
+// as info.
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is a log severity, lowest first
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// ParseLevel parses a level name, as in the logging.level setting
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Writer passes on the log lines at or above its level
+type Writer struct {
+	out   io.Writer
+	level atomic.Int32
+}
+
+// NewWriter creates a Writer writing to out
+func NewWriter(out io.Writer, level Level) *Writer {
+	w := &Writer{out: out}
+	w.level.Store(int32(level))
+	return w
+}
+
+// SetLevel changes the level; safe while logging
+func (w *Writer) SetLevel(level Level) {
+	w.level.Store(int32(level))
+}
+
+// Level returns the current level
+func (w *Writer) Level() Level {
+	return Level(w.level.Load())
+}
+
+// Write implements io.Writer. The standard logger writes one line per call.
+func (w *Writer) Write(p []byte) (int, error) {
+	if lineLevel(p) < w.Level() {
+		return len(p), nil
+	}
+	return w.out.Write(p)
+}
+
+// lineLevel reads the level tag from a log line. The tag is the first
+// bracketed word; the logger's date and time prefix has no brackets.
+func lineLevel(line []byte) Level {
+	start := bytes.IndexByte(line, '[')
+	if start < 0 {
+		return LevelInfo
+	}
+	end := bytes.IndexByte(line[start:], ']')
+	if end < 0 {
+		return LevelInfo
+	}
+	switch tag := string(line[start+1 : start+end]); {
+	case tag == "DEBUG" || strings.HasSuffix(tag, "-debug"):
+		return LevelDebug
+	case tag == "WARN" || tag == "WARNING":
+		return LevelWarn
+	case tag == "ERROR":
+		return LevelError
+	}
+	return LevelInfo
+}
+
+// std filters the standard logger once Install has run
+var std atomic.Pointer[Writer]
+
+// Install filters the standard logger's output by level
+func Install(level Level) {
+	w := NewWriter(log.Writer(), level)
+	log.SetOutput(w)
+	std.Store(w)
+}
+
+// SetLevel changes the standard logger's level. It reports whether the level
+// changed; before Install there is nothing to change.
+func SetLevel(level Level) bool {
+	w := std.Load()
+	if w == nil || w.Level() == level {
+		return false
+	}
+	w.SetLevel(level)
+	return true
+}
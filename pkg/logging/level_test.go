@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestWriterFiltersByLevel(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(&out, LevelInfo)
+	logger := log.New(w, "", log.LstdFlags)
+
+	logger.Printf("[DEBUG] hidden")
+	logger.Printf("[GIN-debug] hidden too")
+	logger.Printf("[INFO] shown")
+	logger.Printf("untagged counts as info")
+	logger.Printf("[WARN] warning")
+	if got := bytes.Count(out.Bytes(), []byte("\n")); got != 3 {
+		t.Fatalf("wrote %d lines at info, want 3:\n%s", got, out.String())
+	}
+	if bytes.Contains(out.Bytes(), []byte("hidden")) {
+		t.Errorf("debug line written at info:\n%s", out.String())
+	}
+
+	out.Reset()
+	w.SetLevel(LevelError)
+	logger.Printf("[WARN] dropped")
+	logger.Printf("[ERROR] kept")
+	if got := out.String(); !bytes.Contains([]byte(got), []byte("kept")) || bytes.Contains([]byte(got), []byte("dropped")) {
+		t.Errorf("at error level wrote:\n%s", got)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "warning": LevelWarn, "error": LevelError, "": LevelInfo} {
+		got, err := ParseLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(verbose) succeeded")
+	}
+}
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordSpans installs a provider that keeps finished spans in memory
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	_, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	return recorder
+}
+
+func TestInjectExtract(t *testing.T) {
+	recordSpans(t)
+
+	assert.Empty(t, Inject(context.Background()), "nothing to carry outside a trace")
+
+	ctx, span := Start(context.Background(), "request")
+	injected := Inject(ctx)
+	span.End()
+	require.NotEmpty(t, injected)
+
+	// A job picked up later continues the same trace
+	restored := Extract(context.Background(), injected)
+	assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(restored).TraceID())
+
+	assert.Equal(t, context.Background(), Extract(context.Background(), "not json"))
+}
+
+func TestEndRecordsError(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := Start(context.Background(), "work")
+	End(span, errors.New("boom"))
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	assert.Equal(t, "boom", ended[0].Status().Description)
+}
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestGormPlugin(t *testing.T) {
+	recorder := recordSpans(t)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(GormPlugin{}))
+	require.NoError(t, db.AutoMigrate(&widget{}))
+
+	// Outside a trace nothing is recorded
+	require.NoError(t, db.Create(&widget{Name: "untraced"}).Error)
+	assert.Empty(t, recorder.Ended())
+
+	ctx, parent := Start(context.Background(), "request")
+	require.NoError(t, db.WithContext(ctx).Create(&widget{Name: "traced"}).Error)
+	var found widget
+	require.NoError(t, db.WithContext(ctx).Where("name = ?", "traced").First(&found).Error)
+	err = db.WithContext(ctx).Where("name = ?", "missing").First(&widget{}).Error
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	parent.End()
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		if span.Name() == "request" {
+			continue
+		}
+		names = append(names, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.NotEqual(t, codes.Error, span.Status().Code, "not found is not an error")
+	}
+	assert.Equal(t, []string{"db.create", "db.select", "db.select"}, names)
+}
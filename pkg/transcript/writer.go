@@ -0,0 +1,66 @@
+package transcript
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ToSRT renders the transcript's timed segments as SubRip subtitles
+func (t *Transcript) ToSRT() string {
+	var builder strings.Builder
+	cue := 0
+	for _, segment := range t.Segments {
+		text := cueText(segment.Text)
+		if text == "" {
+			continue
+		}
+		cue++
+		fmt.Fprintf(&builder, "%d\n%s --> %s\n%s\n\n", cue,
+			formatTimestamp(segment.Start, ','), formatTimestamp(segment.End, ','), text)
+	}
+	return builder.String()
+}
+
+// vttEscaper escapes the characters WebVTT reserves in cue text. Escaping >
+// also keeps an arrow in the payload from being read as a cue timing line.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// ToVTT renders the transcript's timed segments as WebVTT subtitles
+func (t *Transcript) ToVTT() string {
+	var builder strings.Builder
+	builder.WriteString("WEBVTT\n\n")
+	for _, segment := range t.Segments {
+		text := cueText(segment.Text)
+		if text == "" {
+			continue
+		}
+		text = vttEscaper.Replace(text)
+		fmt.Fprintf(&builder, "%s --> %s\n%s\n\n",
+			formatTimestamp(segment.Start, '.'), formatTimestamp(segment.End, '.'), text)
+	}
+	return builder.String()
+}
+
+// cueText trims a segment's text and drops blank lines, which would end the
+// cue early in both formats
+func cueText(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// formatTimestamp formats d as HH:MM:SS followed by sep and milliseconds,
+// the SRT form with a comma and the VTT form with a dot
+func formatTimestamp(d time.Duration, sep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
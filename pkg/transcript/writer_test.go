@@ -0,0 +1,51 @@
+package transcript
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToSRTAndVTT(t *testing.T) {
+	transcript := &Transcript{Segments: []Segment{
+		{Start: 0, End: 2500 * time.Millisecond, Text: " Welcome back. "},
+		{Start: 2500 * time.Millisecond, End: 3 * time.Second, Text: "   "},
+		{Start: time.Hour + 2*time.Minute + 3*time.Second + 45*time.Millisecond, End: time.Hour + 2*time.Minute + 5*time.Second, Text: "Line one\n\nline two --> three"},
+	}}
+
+	wantSRT := "1\n00:00:00,000 --> 00:00:02,500\nWelcome back.\n\n" +
+		"2\n01:02:03,045 --> 01:02:05,000\nLine one\nline two --> three\n\n"
+	if got := transcript.ToSRT(); got != wantSRT {
+		t.Errorf("ToSRT() =\n%q\nwant\n%q", got, wantSRT)
+	}
+
+	wantVTT := "WEBVTT\n\n" +
+		"00:00:00.000 --> 00:00:02.500\nWelcome back.\n\n" +
+		"01:02:03.045 --> 01:02:05.000\nLine one\nline two --&gt; three\n\n"
+	if got := transcript.ToVTT(); got != wantVTT {
+		t.Errorf("ToVTT() =\n%q\nwant\n%q", got, wantVTT)
+	}
+
+	// What's written parses back to the same cues
+	parsed, err := NewParser().Parse(wantSRT, FormatSRT)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(parsed.Segments) != 2 || parsed.Segments[1].Start != transcript.Segments[2].Start {
+		t.Errorf("Expected the SRT to parse back to 2 cues, got %+v", parsed.Segments)
+	}
+}
+
+func TestToVTT_EscapesCueText(t *testing.T) {
+	transcript := &Transcript{Segments: []Segment{
+		{Start: 0, End: time.Second, Text: "Q&A: <b>not a tag</b> & 3 > 2"},
+	}}
+
+	want := "WEBVTT\n\n00:00:00.000 --> 00:00:01.000\nQ&amp;A: &lt;b&gt;not a tag&lt;/b&gt; &amp; 3 &gt; 2\n\n"
+	if got := transcript.ToVTT(); got != want {
+		t.Errorf("ToVTT() =\n%q\nwant\n%q", got, want)
+	}
+	// SRT has no escapes; its text stays as spoken
+	if got := transcript.ToSRT(); got != "1\n00:00:00,000 --> 00:00:01,000\nQ&A: <b>not a tag</b> & 3 > 2\n\n" {
+		t.Errorf("ToSRT() = %q", got)
+	}
+}